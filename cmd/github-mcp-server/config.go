@@ -0,0 +1,30 @@
+package main
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect the server configuration",
+}
+
+var printEffectiveConfigCmd = &cobra.Command{
+	Use:   "print-effective",
+	Short: "Print the effective configuration",
+	Long:  `Print the configuration the server would run with, after merging the config file, environment variables and flags, with the source of each setting. The token is redacted.`,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+		return cfg.WriteEffective(os.Stdout)
+	},
+}
+
+func init() {
+	configCmd.AddCommand(printEffectiveConfigCmd)
+	rootCmd.AddCommand(configCmd)
+}
@@ -5,13 +5,13 @@ import (
 	"fmt"
 	"os"
 	"strings"
-	"time"
 
 	"github.com/github/github-mcp-server/internal/ghmcp"
+	"github.com/github/github-mcp-server/pkg/config"
 	"github.com/github/github-mcp-server/pkg/github"
+	"github.com/github/github-mcp-server/pkg/ratelimit"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
-	"github.com/spf13/viper"
 )
 
 // These variables are set by the build process using ldflags.
@@ -32,46 +32,68 @@ var (
 		Short: "Start stdio server",
 		Long:  `Start a server that communicates via standard input/output streams using JSON-RPC messages.`,
 		RunE: func(_ *cobra.Command, _ []string) error {
-			token := viper.GetString("personal_access_token")
-			if token == "" {
-				return errors.New("GITHUB_PERSONAL_ACCESS_TOKEN not set")
+			cfg, err := loadConfig()
+			if err != nil {
+				return err
 			}
-
-			// If you're wondering why we're not using viper.GetStringSlice("toolsets"),
-			// it's because viper doesn't handle comma-separated values correctly for env
-			// vars when using GetStringSlice.
-			// https://github.com/spf13/viper/issues/380
-			var enabledToolsets []string
-			if err := viper.UnmarshalKey("toolsets", &enabledToolsets); err != nil {
-				return fmt.Errorf("failed to unmarshal toolsets: %w", err)
+			if cfg.Token == "" && !cfg.App.Enabled() && !cfg.OAuth.Enabled() {
+				return errors.New("GITHUB_PERSONAL_ACCESS_TOKEN not set, nor a GitHub App with GITHUB_APP_ID, nor an OAuth app with GITHUB_OAUTH_CLIENT_ID")
 			}
-
-			// Parse tools (similar to toolsets)
-			var enabledTools []string
-			if err := viper.UnmarshalKey("tools", &enabledTools); err != nil {
-				return fmt.Errorf("failed to unmarshal tools: %w", err)
+			app, err := cfg.App.GitHubApp()
+			if err != nil {
+				return err
 			}
-
-			// If neither toolset config nor tools config is passed we enable the default toolset
-			if len(enabledToolsets) == 0 && len(enabledTools) == 0 {
-				enabledToolsets = []string{github.ToolsetMetadataDefault.ID}
+			accounts, err := githubAccounts(cfg)
+			if err != nil {
+				return err
 			}
 
-			ttl := viper.GetDuration("repo-access-cache-ttl")
+			ttl := cfg.Cache.RepoAccessTTL
+			reloadable := reloadableConfig(cfg)
 			stdioServerConfig := ghmcp.StdioServerConfig{
-				Version:              version,
-				Host:                 viper.GetString("host"),
-				Token:                token,
-				EnabledToolsets:      enabledToolsets,
-				EnabledTools:         enabledTools,
-				DynamicToolsets:      viper.GetBool("dynamic_toolsets"),
-				ReadOnly:             viper.GetBool("read-only"),
-				ExportTranslations:   viper.GetBool("export-translations"),
-				EnableCommandLogging: viper.GetBool("enable-command-logging"),
-				LogFilePath:          viper.GetString("log-file"),
-				ContentWindowSize:    viper.GetInt("content-window-size"),
-				LockdownMode:         viper.GetBool("lockdown-mode"),
-				RepoAccessCacheTTL:   &ttl,
+				Version: version,
+				Host:    cfg.Host,
+				EnterpriseURLs: ghmcp.EnterpriseURLs{
+					API:     cfg.Enterprise.APIURL,
+					Upload:  cfg.Enterprise.UploadURL,
+					GraphQL: cfg.Enterprise.GraphQLURL,
+				},
+				EnterpriseVersion:     cfg.Enterprise.Version,
+				Token:                 cfg.Token,
+				App:                   app,
+				OAuth:                 cfg.OAuth.DeviceFlow(),
+				Accounts:              accounts,
+				EnabledToolsets:       reloadable.EnabledToolsets,
+				EnabledTools:          reloadable.EnabledTools,
+				DynamicToolsets:       cfg.Toolsets.Dynamic,
+				ReadOnly:              reloadable.ReadOnly,
+				ExportTranslations:    cfg.Translations.Export,
+				EnableCommandLogging:  cfg.Logging.CommandLogging,
+				LogFilePath:           cfg.Logging.File,
+				LogLevel:              cfg.Logging.Level,
+				ContentWindowSize:     reloadable.ContentWindowSize,
+				LockdownMode:          reloadable.LockdownMode,
+				RepoAccessCacheTTL:    &ttl,
+				CommitSigning:         cfg.CommitSigning.Signing(),
+				PushLimits:            pushLimits(cfg),
+				PushHostLimits:        pushHostLimits(cfg),
+				DefaultBranch:         cfg.Repositories.DefaultBranch,
+				MetricsAddress:        cfg.Metrics.Address,
+				ResponseCacheMaxBytes: cfg.Cache.ResponseMaxBytes,
+				MaxConcurrentRequests: cfg.Limits.MaxConcurrentRequests,
+				RateLimitStore:        rateLimitStore(cfg),
+				ConfigFile:            configFilePath(),
+				Reload: func() (ghmcp.ReloadedConfig, error) {
+					next, err := loadConfig()
+					if err != nil {
+						return ghmcp.ReloadedConfig{}, err
+					}
+					return ghmcp.ReloadedConfig{
+						ReloadableConfig: reloadableConfig(next),
+						LogLevel:         next.Logging.Level,
+						RestartRequired:  cfg.RestartRequired(next),
+					}, nil
+				},
 			}
 			return ghmcp.RunStdioServer(stdioServerConfig)
 		},
@@ -79,47 +101,143 @@ var (
 )
 
 func init() {
-	cobra.OnInitialize(initConfig)
 	rootCmd.SetGlobalNormalizationFunc(wordSepNormalizeFunc)
 
 	rootCmd.SetVersionTemplate("{{.Short}}\n{{.Version}}\n")
 
-	// Add global flags that will be shared by all commands
+	// Add global flags that will be shared by all commands. Each flag overrides the
+	// matching setting of the config file and environment, see pkg/config.
+	rootCmd.PersistentFlags().String("config", "", "Path to a YAML or TOML config file (env: "+config.EnvConfigFile+")")
 	rootCmd.PersistentFlags().StringSlice("toolsets", nil, github.GenerateToolsetsHelp())
 	rootCmd.PersistentFlags().StringSlice("tools", nil, "Comma-separated list of specific tools to enable")
 	rootCmd.PersistentFlags().Bool("dynamic-toolsets", false, "Enable dynamic toolsets")
 	rootCmd.PersistentFlags().Bool("read-only", false, "Restrict the server to read-only operations")
 	rootCmd.PersistentFlags().String("log-file", "", "Path to log file")
+	rootCmd.PersistentFlags().String("log-level", "", "Log level: debug, info, warn or error (defaults to debug with --log-file, info otherwise)")
+	rootCmd.PersistentFlags().String("metrics-address", "", "Serve Prometheus metrics on /metrics at this host:port (e.g. 127.0.0.1:9090)")
 	rootCmd.PersistentFlags().Bool("enable-command-logging", false, "When enabled, the server will log all command requests and responses to the log file")
 	rootCmd.PersistentFlags().Bool("export-translations", false, "Save translations to a JSON file")
 	rootCmd.PersistentFlags().String("gh-host", "", "Specify the GitHub hostname (for GitHub Enterprise etc.)")
-	rootCmd.PersistentFlags().Int("content-window-size", 5000, "Specify the content window size")
+	rootCmd.PersistentFlags().String("enterprise-api-url", "", "REST API URL of the GitHub Enterprise Server, when not where --gh-host implies")
+	rootCmd.PersistentFlags().String("enterprise-upload-url", "", "Upload URL of the GitHub Enterprise Server, when not where --gh-host implies")
+	rootCmd.PersistentFlags().String("enterprise-graphql-url", "", "GraphQL API URL of the GitHub Enterprise Server, when not where --gh-host implies")
+	rootCmd.PersistentFlags().String("enterprise-version", "", "Version of the GitHub Enterprise Server (e.g. 3.14), detected from its meta endpoint when unset")
+	rootCmd.PersistentFlags().Int64("app-id", 0, "Authenticate as the GitHub App with this ID instead of with a personal access token")
+	rootCmd.PersistentFlags().String("app-private-key-file", "", "Path to the PEM private key of the GitHub App")
+	rootCmd.PersistentFlags().String("app-installation-owner", "", "Account whose GitHub App installation authenticates requests that name no owner, such as searches")
+	rootCmd.PersistentFlags().String("oauth-client-id", "", "Sign in with the device flow of the OAuth app with this client ID, keeping the token in the OS keyring")
+	rootCmd.PersistentFlags().StringSlice("oauth-scopes", nil, "Comma-separated list of scopes to request when signing in with the OAuth app")
+	rootCmd.PersistentFlags().Int("content-window-size", config.DefaultContentWindowSize, "Specify the content window size")
 	rootCmd.PersistentFlags().Bool("lockdown-mode", false, "Enable lockdown mode")
-	rootCmd.PersistentFlags().Duration("repo-access-cache-ttl", 5*time.Minute, "Override the repo access cache TTL (e.g. 1m, 0s to disable)")
-
-	// Bind flag to viper
-	_ = viper.BindPFlag("toolsets", rootCmd.PersistentFlags().Lookup("toolsets"))
-	_ = viper.BindPFlag("tools", rootCmd.PersistentFlags().Lookup("tools"))
-	_ = viper.BindPFlag("dynamic_toolsets", rootCmd.PersistentFlags().Lookup("dynamic-toolsets"))
-	_ = viper.BindPFlag("read-only", rootCmd.PersistentFlags().Lookup("read-only"))
-	_ = viper.BindPFlag("log-file", rootCmd.PersistentFlags().Lookup("log-file"))
-	_ = viper.BindPFlag("enable-command-logging", rootCmd.PersistentFlags().Lookup("enable-command-logging"))
-	_ = viper.BindPFlag("export-translations", rootCmd.PersistentFlags().Lookup("export-translations"))
-	_ = viper.BindPFlag("host", rootCmd.PersistentFlags().Lookup("gh-host"))
-	_ = viper.BindPFlag("content-window-size", rootCmd.PersistentFlags().Lookup("content-window-size"))
-	_ = viper.BindPFlag("lockdown-mode", rootCmd.PersistentFlags().Lookup("lockdown-mode"))
-	_ = viper.BindPFlag("repo-access-cache-ttl", rootCmd.PersistentFlags().Lookup("repo-access-cache-ttl"))
+	rootCmd.PersistentFlags().String("rate-limit-state-file", "", "Save the rate limiter state to this file and restore it on startup, so restarts do not reset the pacing")
+	rootCmd.PersistentFlags().Int("max-concurrent-requests", config.DefaultMaxConcurrentRequests, "Maximum number of GitHub API requests in flight (0 for no limit)")
+	rootCmd.PersistentFlags().Duration("repo-access-cache-ttl", config.DefaultRepoAccessCacheTTL, "Override the repo access cache TTL (e.g. 1m, 0s to disable)")
+	rootCmd.PersistentFlags().Int64("response-cache-max-bytes", config.DefaultResponseCacheMaxBytes, "Size of the GitHub API response cache used for conditional requests (0 to disable)")
+	rootCmd.PersistentFlags().String("commit-signing-format", "", "Sign commits created by bulk tools: gpg or ssh (empty disables signing)")
+	rootCmd.PersistentFlags().String("commit-signing-key", "", "Signing key: gpg key ID, or path to the SSH key for ssh signing")
+	rootCmd.PersistentFlags().String("commit-signing-program", "", "Override the signing program (defaults to gpg or ssh-keygen)")
+	rootCmd.PersistentFlags().String("commit-signing-name", "", "Committer name for signed commits")
+	rootCmd.PersistentFlags().String("commit-signing-email", "", "Committer email for signed commits; must belong to the key owner for commits to show as verified")
 
 	// Add subcommands
 	rootCmd.AddCommand(stdioCmd)
 }
 
-func initConfig() {
-	// Initialize Viper configuration
-	viper.SetEnvPrefix("github")
-	viper.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
-	viper.AutomaticEnv()
+// loadConfig loads the configuration from the file named by --config or GITHUB_CONFIG,
+// the environment and the command line flags.
+func loadConfig() (*config.Config, error) {
+	return config.Load(configFilePath(), rootCmd.PersistentFlags())
+}
+
+// configFilePath returns the config file named by --config or GITHUB_CONFIG, if any.
+func configFilePath() string {
+	path, _ := rootCmd.PersistentFlags().GetString("config")
+	if path == "" {
+		path = os.Getenv(config.EnvConfigFile)
+	}
+	return path
+}
+
+// rateLimitStore returns the store of the rate limiter state configured by cfg, nil if none.
+func rateLimitStore(cfg *config.Config) ratelimit.Store {
+	if cfg.RateLimits.StateFile == "" {
+		return nil
+	}
+	return ratelimit.FileStore{Path: cfg.RateLimits.StateFile}
+}
+
+// reloadableConfig returns the settings of cfg that the server can apply while running.
+func reloadableConfig(cfg *config.Config) ghmcp.ReloadableConfig {
+	enabledToolsets := cfg.Toolsets.Enabled
+	enabledTools := cfg.Toolsets.Tools
+
+	// If neither toolset config nor tools config is passed we enable the default toolset
+	if len(enabledToolsets) == 0 && len(enabledTools) == 0 {
+		enabledToolsets = []string{github.ToolsetMetadataDefault.ID}
+	}
+
+	return ghmcp.ReloadableConfig{
+		EnabledToolsets:   enabledToolsets,
+		EnabledTools:      enabledTools,
+		ReadOnly:          cfg.Policies.ReadOnly,
+		LockdownMode:      cfg.Policies.LockdownMode,
+		ContentWindowSize: cfg.Limits.ContentWindowSize,
+	}
+}
+
+// pushLimits returns the configured push limits along with where each was set.
+func pushLimits(cfg *config.Config) github.PushLimits {
+	push := cfg.Limits.Push
+	return github.PushLimits{
+		MaxFilesPerPush:       push.MaxFiles,
+		MaxFileSizeBytes:      push.MaxFileSizeBytes,
+		MaxTotalPushSizeBytes: push.MaxTotalSizeBytes,
+		DefaultChunkSize:      push.DefaultChunkSize,
+		MaxChunkSize:          push.MaxChunkSize,
+		Sources: map[string]string{
+			github.PushLimitMaxFilesPerPush:       string(cfg.Source("limits.push.max_files")),
+			github.PushLimitMaxFileSizeBytes:      string(cfg.Source("limits.push.max_file_size_bytes")),
+			github.PushLimitMaxTotalPushSizeBytes: string(cfg.Source("limits.push.max_total_size_bytes")),
+			github.PushLimitDefaultChunkSize:      string(cfg.Source("limits.push.default_chunk_size")),
+			github.PushLimitMaxChunkSize:          string(cfg.Source("limits.push.max_chunk_size")),
+		},
+	}
+}
+
+// pushHostLimits returns the push limits configured per host, keyed by hostname.
+// githubAccounts returns the configured accounts with the tokens of their environment variables.
+func githubAccounts(cfg *config.Config) ([]ghmcp.Account, error) {
+	accounts := make([]ghmcp.Account, 0, len(cfg.Accounts))
+	for _, account := range cfg.Accounts {
+		token := os.Getenv(account.TokenEnv)
+		if token == "" {
+			return nil, fmt.Errorf("%s not set, the token of account %s", account.TokenEnv, account.Name)
+		}
+		accounts = append(accounts, ghmcp.Account{Name: account.Name, Host: account.Host, Token: token})
+	}
+	return accounts, nil
+}
 
+func pushHostLimits(cfg *config.Config) map[string]github.PushLimits {
+	source := fmt.Sprintf("%s (limits.push_hosts)", cfg.Source("limits.push_hosts"))
+	hosts := make(map[string]github.PushLimits, len(cfg.Limits.PushHosts))
+	for _, host := range cfg.Limits.PushHosts {
+		hosts[host.Host] = github.PushLimits{
+			MaxFilesPerPush:       host.MaxFiles,
+			MaxFileSizeBytes:      host.MaxFileSizeBytes,
+			MaxTotalPushSizeBytes: host.MaxTotalSizeBytes,
+			DefaultChunkSize:      host.DefaultChunkSize,
+			MaxChunkSize:          host.MaxChunkSize,
+			Sources: map[string]string{
+				github.PushLimitMaxFilesPerPush:       source,
+				github.PushLimitMaxFileSizeBytes:      source,
+				github.PushLimitMaxTotalPushSizeBytes: source,
+				github.PushLimitDefaultChunkSize:      source,
+				github.PushLimitMaxChunkSize:          source,
+			},
+		}
+	}
+	return hosts
 }
 
 func main() {
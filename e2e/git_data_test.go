@@ -0,0 +1,351 @@
+//go:build e2e
+
+package e2e_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	getOrgOnce sync.Once
+	org        string
+)
+
+// getE2EOrg returns the organization in which the Git data tests create their disposable
+// repositories, skipping the test when none is configured.
+func getE2EOrg(t *testing.T) string {
+	getOrgOnce.Do(func() {
+		org = os.Getenv("GITHUB_MCP_SERVER_E2E_ORG")
+	})
+	if org == "" {
+		t.Skip("GITHUB_MCP_SERVER_E2E_ORG environment variable is not set")
+	}
+	return org
+}
+
+// createSandboxRepository creates a private repository with a README in the e2e organization
+// and deletes it when the test finishes. It returns the owner, name and default branch.
+func createSandboxRepository(t *testing.T, mcpClient *mcp.ClientSession) (string, string, string) {
+	owner := getE2EOrg(t)
+	repoName := fmt.Sprintf("github-mcp-server-e2e-%s-%d", t.Name(), time.Now().UnixMilli())
+
+	t.Logf("Creating repository %s/%s...", owner, repoName)
+	callTool(t, mcpClient, "create_repository", map[string]any{
+		"name":         repoName,
+		"organization": owner,
+		"private":      true,
+		"autoInit":     true,
+	})
+
+	// Cleanup the repository after the test
+	t.Cleanup(func() {
+		// MCP Server doesn't support deletions, but we can use the GitHub Client
+		ghClient := getRESTClient(t)
+		t.Logf("Deleting repository %s/%s...", owner, repoName)
+		_, err := ghClient.Repositories.Delete(context.Background(), owner, repoName)
+		require.NoError(t, err, "expected to delete repository successfully")
+	})
+
+	ghClient := getRESTClient(t)
+	repository, _, err := ghClient.Repositories.Get(context.Background(), owner, repoName)
+	require.NoError(t, err, "expected to get repository successfully")
+
+	return owner, repoName, repository.GetDefaultBranch()
+}
+
+// callTool calls a tool that is expected to succeed and unmarshals its text result into out, if given.
+func callTool(t *testing.T, mcpClient *mcp.ClientSession, name string, args map[string]any, out ...any) {
+	resp, err := mcpClient.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      name,
+		Arguments: args,
+	})
+	require.NoError(t, err, "expected to call '%s' tool successfully", name)
+	require.False(t, resp.IsError, fmt.Sprintf("expected result not to be an error: %+v", resp))
+	require.NotEmpty(t, resp.Content, "expected content to have at least one item")
+
+	textContent, ok := resp.Content[0].(*mcp.TextContent)
+	require.True(t, ok, "expected content to be of type TextContent")
+	for _, o := range out {
+		require.NoError(t, json.Unmarshal([]byte(textContent.Text), o), "expected to unmarshal text content successfully")
+	}
+}
+
+// branchFiles lists the paths of the files on a branch.
+func branchFiles(t *testing.T, owner, repo, branch string) []string {
+	ghClient := getRESTClient(t)
+	tree, _, err := ghClient.Git.GetTree(context.Background(), owner, repo, branch, true)
+	require.NoError(t, err, "expected to get tree successfully")
+
+	var paths []string
+	for _, entry := range tree.Entries {
+		if entry.GetType() == "blob" {
+			paths = append(paths, entry.GetPath())
+		}
+	}
+	slices.Sort(paths)
+	return paths
+}
+
+// branchHead returns the SHA a branch points to.
+func branchHead(t *testing.T, owner, repo, branch string) string {
+	ghClient := getRESTClient(t)
+	ref, _, err := ghClient.Git.GetRef(context.Background(), owner, repo, "refs/heads/"+branch)
+	require.NoError(t, err, "expected to get ref successfully")
+	return ref.GetObject().GetSHA()
+}
+
+func testFiles(t *testing.T, paths ...string) []any {
+	var files []any
+	for _, path := range paths {
+		files = append(files, map[string]any{
+			"path":    path,
+			"content": fmt.Sprintf("%s created by e2e test %s\n", path, t.Name()),
+		})
+	}
+	return files
+}
+
+func TestPushFilesChunked(t *testing.T) {
+	t.Parallel()
+
+	mcpClient := setupMCPClient(t, withToolsets([]string{"repos", "bulk_operations"}))
+	owner, repoName, defaultBranch := createSandboxRepository(t, mcpClient)
+	initialHead := branchHead(t, owner, repoName, defaultBranch)
+
+	// Push five files in chunks of two, which takes three commits
+
+	t.Logf("Pushing files in chunks to %s/%s...", owner, repoName)
+	var pushResult struct {
+		TotalFiles       int    `json:"total_files"`
+		TotalChunks      int    `json:"total_chunks"`
+		SuccessfulChunks int    `json:"successful_chunks"`
+		FinalCommitSHA   string `json:"final_commit_sha"`
+		FullySuccessful  bool   `json:"fully_successful"`
+	}
+	callTool(t, mcpClient, "push_files_chunked", map[string]any{
+		"owner":      owner,
+		"repo":       repoName,
+		"branch":     defaultBranch,
+		"files":      testFiles(t, "docs/a.md", "docs/b.md", "src/main.go", "src/util/util.go", "notes.txt"),
+		"message":    "Add test files",
+		"chunk_size": 2,
+	}, &pushResult)
+
+	require.True(t, pushResult.FullySuccessful, "expected push to be fully successful")
+	require.Equal(t, 5, pushResult.TotalFiles, "expected all files to be pushed")
+	require.Equal(t, 3, pushResult.TotalChunks, "expected three chunks")
+	require.Equal(t, 3, pushResult.SuccessfulChunks, "expected every chunk to be pushed")
+
+	// The branch points to the last chunk's commit, three commits after the initial one
+
+	require.Equal(t, pushResult.FinalCommitSHA, branchHead(t, owner, repoName, defaultBranch), "expected branch to point to the final commit")
+
+	ghClient := getRESTClient(t)
+	comparison, _, err := ghClient.Repositories.CompareCommits(context.Background(), owner, repoName, initialHead, pushResult.FinalCommitSHA, nil)
+	require.NoError(t, err, "expected to compare commits successfully")
+	require.Equal(t, 3, comparison.GetAheadBy(), "expected one commit per chunk")
+	require.Equal(t, 0, comparison.GetBehindBy(), "expected chunks to be committed on top of the branch")
+
+	require.Equal(t,
+		[]string{"README.md", "docs/a.md", "docs/b.md", "notes.txt", "src/main.go", "src/util/util.go"},
+		branchFiles(t, owner, repoName, defaultBranch),
+		"expected pushed files to be on the branch",
+	)
+
+	// Pushing the same files again changes nothing
+
+	t.Logf("Pushing the same files again to %s/%s...", owner, repoName)
+	var repeatResult struct {
+		FullySuccessful bool `json:"fully_successful"`
+		Chunks          []struct {
+			NoChanges bool `json:"no_changes"`
+		} `json:"chunks"`
+	}
+	callTool(t, mcpClient, "push_files_chunked", map[string]any{
+		"owner":         owner,
+		"repo":          repoName,
+		"branch":        defaultBranch,
+		"files":         testFiles(t, "docs/a.md", "docs/b.md"),
+		"message":       "Add test files again",
+		"result_detail": "full",
+	}, &repeatResult)
+
+	require.True(t, repeatResult.FullySuccessful, "expected push to be fully successful")
+	require.Len(t, repeatResult.Chunks, 1, "expected one chunk")
+	require.True(t, repeatResult.Chunks[0].NoChanges, "expected no commit for unchanged files")
+	require.Equal(t, pushResult.FinalCommitSHA, branchHead(t, owner, repoName, defaultBranch), "expected branch not to move")
+}
+
+func TestBulkDeleteFiles(t *testing.T) {
+	t.Parallel()
+
+	mcpClient := setupMCPClient(t, withToolsets([]string{"repos", "bulk_operations"}))
+	owner, repoName, defaultBranch := createSandboxRepository(t, mcpClient)
+
+	t.Logf("Pushing files to %s/%s...", owner, repoName)
+	callTool(t, mcpClient, "push_files_chunked", map[string]any{
+		"owner":   owner,
+		"repo":    repoName,
+		"branch":  defaultBranch,
+		"files":   testFiles(t, "keep.txt", "old/a.txt", "old/b.txt", "old/nested/c.txt"),
+		"message": "Add test files",
+	})
+
+	// Delete the old directory's files, skipping a path that does not exist
+
+	t.Logf("Deleting files in %s/%s...", owner, repoName)
+	var deleteResult struct {
+		CommitSHA    string   `json:"commit_sha"`
+		DeletedFiles []string `json:"deleted_files"`
+		FilesDeleted int      `json:"files_deleted"`
+		SkippedPaths []string `json:"skipped_paths"`
+	}
+	callTool(t, mcpClient, "bulk_delete_files", map[string]any{
+		"owner":               owner,
+		"repo":                repoName,
+		"branch":              defaultBranch,
+		"paths":               []any{"old/a.txt", "old/b.txt", "old/nested/c.txt", "old/missing.txt"},
+		"message":             "Delete old files",
+		"missing_path_policy": "skip",
+	}, &deleteResult)
+
+	require.Equal(t, 3, deleteResult.FilesDeleted, "expected three files to be deleted")
+	require.Equal(t, []string{"old/missing.txt"}, deleteResult.SkippedPaths, "expected the missing path to be skipped")
+	require.Equal(t, deleteResult.CommitSHA, branchHead(t, owner, repoName, defaultBranch), "expected branch to point to the deletion commit")
+	require.Equal(t, []string{"README.md", "keep.txt"}, branchFiles(t, owner, repoName, defaultBranch), "expected the old directory to be gone")
+
+	// The deletion is a single commit removing all three files
+
+	ghClient := getRESTClient(t)
+	commit, _, err := ghClient.Repositories.GetCommit(context.Background(), owner, repoName, deleteResult.CommitSHA, nil)
+	require.NoError(t, err, "expected to get commit successfully")
+	require.Len(t, commit.Files, 3, "expected three file changes")
+	for _, file := range commit.Files {
+		require.Equal(t, "removed", file.GetStatus(), "expected %s to be removed", file.GetFilename())
+	}
+
+	// Deleting paths that no longer exist makes no commit
+
+	var repeatResult struct {
+		NoChanges bool `json:"no_changes"`
+	}
+	callTool(t, mcpClient, "bulk_delete_files", map[string]any{
+		"owner":               owner,
+		"repo":                repoName,
+		"branch":              defaultBranch,
+		"paths":               []any{"old/a.txt"},
+		"message":             "Delete old files again",
+		"missing_path_policy": "skip",
+	}, &repeatResult)
+
+	require.True(t, repeatResult.NoChanges, "expected no commit when nothing is deleted")
+	require.Equal(t, deleteResult.CommitSHA, branchHead(t, owner, repoName, defaultBranch), "expected branch not to move")
+}
+
+func TestBranchRefs(t *testing.T) {
+	t.Parallel()
+
+	mcpClient := setupMCPClient(t, withToolsets([]string{"repos", "bulk_operations"}))
+	owner, repoName, defaultBranch := createSandboxRepository(t, mcpClient)
+
+	// Create a branch from the default branch
+
+	t.Logf("Creating branch in %s/%s...", owner, repoName)
+	callTool(t, mcpClient, "create_branch", map[string]any{
+		"owner":       owner,
+		"repo":        repoName,
+		"branch":      "e2e-refs",
+		"from_branch": defaultBranch,
+	})
+	require.Equal(t, branchHead(t, owner, repoName, defaultBranch), branchHead(t, owner, repoName, "e2e-refs"), "expected branch to start at the default branch")
+
+	// Snapshot the branch, then move it on with a push
+
+	t.Logf("Snapshotting branch in %s/%s...", owner, repoName)
+	var snapshot struct {
+		Label string `json:"label"`
+		SHA   string `json:"sha"`
+		Tag   string `json:"tag"`
+	}
+	callTool(t, mcpClient, "snapshot_branch", map[string]any{
+		"owner":      owner,
+		"repo":       repoName,
+		"branch":     "e2e-refs",
+		"label":      "before-push",
+		"create_tag": true,
+	}, &snapshot)
+	require.Equal(t, branchHead(t, owner, repoName, "e2e-refs"), snapshot.SHA, "expected snapshot to record the branch head")
+
+	ghClient := getRESTClient(t)
+	tagRef, _, err := ghClient.Git.GetRef(context.Background(), owner, repoName, "refs/tags/"+snapshot.Tag)
+	require.NoError(t, err, "expected snapshot tag to exist")
+	require.Equal(t, snapshot.SHA, tagRef.GetObject().GetSHA(), "expected snapshot tag to point to the snapshot")
+
+	var pushResult struct {
+		FinalCommitSHA string `json:"final_commit_sha"`
+	}
+	callTool(t, mcpClient, "push_files_chunked", map[string]any{
+		"owner":      owner,
+		"repo":       repoName,
+		"branch":     "e2e-refs",
+		"files":      testFiles(t, "a.txt", "b.txt"),
+		"message":    "Add test files",
+		"chunk_size": 1,
+	}, &pushResult)
+	require.Equal(t, pushResult.FinalCommitSHA, branchHead(t, owner, repoName, "e2e-refs"), "expected branch to move to the pushed commit")
+
+	// A restore that expects another head is refused
+
+	resp, err := mcpClient.CallTool(context.Background(), &mcp.CallToolParams{
+		Name: "restore_branch_snapshot",
+		Arguments: map[string]any{
+			"owner":             owner,
+			"repo":              repoName,
+			"branch":            "e2e-refs",
+			"label":             "before-push",
+			"expected_head_sha": snapshot.SHA,
+		},
+	})
+	require.NoError(t, err, "expected to call 'restore_branch_snapshot' tool successfully")
+	require.True(t, resp.IsError, "expected restore with a stale head to be refused")
+	require.Equal(t, pushResult.FinalCommitSHA, branchHead(t, owner, repoName, "e2e-refs"), "expected branch not to move")
+
+	// Restoring the snapshot force-moves the branch back
+
+	t.Logf("Restoring branch snapshot in %s/%s...", owner, repoName)
+	var restoreResult struct {
+		PreviousSHA      string `json:"previous_sha"`
+		DiscardedCommits int    `json:"discarded_commits"`
+		Restored         bool   `json:"restored"`
+	}
+	callTool(t, mcpClient, "restore_branch_snapshot", map[string]any{
+		"owner":             owner,
+		"repo":              repoName,
+		"branch":            "e2e-refs",
+		"label":             "before-push",
+		"expected_head_sha": pushResult.FinalCommitSHA,
+	}, &restoreResult)
+
+	require.True(t, restoreResult.Restored, "expected branch to be restored")
+	require.Equal(t, pushResult.FinalCommitSHA, restoreResult.PreviousSHA, "expected previous head to be reported")
+	require.Equal(t, 2, restoreResult.DiscardedCommits, "expected both chunk commits to be discarded")
+	require.Equal(t, snapshot.SHA, branchHead(t, owner, repoName, "e2e-refs"), "expected branch to point to the snapshot again")
+	require.False(t, slices.ContainsFunc(branchFiles(t, owner, repoName, "e2e-refs"), func(path string) bool {
+		return strings.HasSuffix(path, ".txt")
+	}), "expected pushed files to be gone")
+
+	// The default branch was never touched
+
+	require.Equal(t, snapshot.SHA, branchHead(t, owner, repoName, defaultBranch), "expected default branch not to move")
+}
@@ -0,0 +1,170 @@
+package ghmcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/github/github-mcp-server/pkg/accounts"
+	"github.com/github/github-mcp-server/pkg/github"
+	"github.com/github/github-mcp-server/pkg/trace"
+	"github.com/github/github-mcp-server/pkg/utils"
+	gogithub "github.com/google/go-github/v79/github"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/shurcooL/githubv4"
+)
+
+// accountArgument is the argument of tool calls naming the account they act as
+const accountArgument = "account"
+
+// Account is a named GitHub account tool calls can act as instead of the main one of the server.
+type Account struct {
+	// Name identifies the account in the account argument of tool calls
+	Name string
+	// Host is the GitHub host of the account (e.g. https://github.example.com), GitHub.com
+	// when empty
+	Host string
+	// Token authenticates the requests of the account
+	Token string
+}
+
+// githubClients are the clients making the GitHub requests of an account.
+type githubClients struct {
+	rest    *gogithub.Client
+	gqlHTTP *http.Client
+	gql     *githubv4.Client
+	rawURL  *url.URL
+	// host is the GitHub host the clients target
+	host apiHost
+}
+
+// newAccountClients returns the clients of account, whose requests go through transport with
+// the token of the account.
+func newAccountClients(account Account, transport http.RoundTripper, version string) (*githubClients, error) {
+	host, err := parseAPIHost(account.Host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse API host of account %s: %w", account.Name, err)
+	}
+
+	rest := gogithub.NewClient(&http.Client{Transport: trace.NewTransport(transport)}).WithAuthToken(account.Token)
+	rest.UserAgent = fmt.Sprintf("github-mcp-server/%s", version)
+	rest.BaseURL = host.baseRESTURL
+	rest.UploadURL = host.uploadURL
+
+	gqlHTTP := &http.Client{
+		Transport: &bearerAuthTransport{
+			transport: trace.NewTransport(transport),
+			token:     account.Token,
+		},
+	}
+	return &githubClients{
+		rest:    rest,
+		gqlHTTP: gqlHTTP,
+		gql:     githubv4.NewEnterpriseClient(host.graphqlURL.String(), gqlHTTP),
+		rawURL:  host.rawURL,
+		host:    host,
+	}, nil
+}
+
+// accountsOf returns the accounts as reported to tools, without their tokens.
+func accountsOf(configured []Account) []accounts.Account {
+	result := make([]accounts.Account, 0, len(configured))
+	for _, account := range configured {
+		result = append(result, accounts.Account{Name: account.Name, Host: account.Host})
+	}
+	return result
+}
+
+// addAccountToContext makes the GitHub requests of each tool call act as the account named by
+// its account argument, which is removed before the tool sees it, or else as the account the
+// session selected. Listed tools advertise the argument.
+func addAccountToContext(selector *accounts.Selector) func(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			ctx = accounts.ContextWithSelector(ctx, selector)
+			session, _ := req.GetSession().(*mcp.ServerSession)
+			name := selector.Selected(session)
+
+			switch req := req.(type) {
+			case *mcp.CallToolRequest:
+				if req.Params == nil || req.Params.Name == github.SelectAccountToolName {
+					break
+				}
+				argument, err := takeAccountArgument(req.Params)
+				if err == nil && argument != "" {
+					err = selector.Check(argument)
+					name = argument
+				}
+				if err != nil {
+					return utils.NewToolResultError(err.Error()), nil
+				}
+			case *mcp.ListToolsRequest:
+				result, err := next(accounts.ContextWithAccount(ctx, name), method, req)
+				if list, ok := result.(*mcp.ListToolsResult); ok && list != nil {
+					result = withAccountParameter(list, selector)
+				}
+				return result, err
+			}
+			return next(accounts.ContextWithAccount(ctx, name), method, req)
+		}
+	}
+}
+
+// takeAccountArgument removes the account argument from params and returns it. Arguments that
+// are not an object are left for the tool to reject.
+func takeAccountArgument(params *mcp.CallToolParamsRaw) (string, error) {
+	var args map[string]json.RawMessage
+	if len(params.Arguments) == 0 || json.Unmarshal(params.Arguments, &args) != nil {
+		return "", nil
+	}
+	value, ok := args[accountArgument]
+	if !ok {
+		return "", nil
+	}
+	var name string
+	if err := json.Unmarshal(value, &name); err != nil {
+		return "", errors.New("parameter account is not of type string")
+	}
+	delete(args, accountArgument)
+	arguments, err := json.Marshal(args)
+	if err != nil {
+		return "", fmt.Errorf("failed to remove the account argument: %w", err)
+	}
+	params.Arguments = arguments
+	return name, nil
+}
+
+// withAccountParameter returns a copy of list whose tools take the account argument, leaving
+// the tools of the server unchanged.
+func withAccountParameter(list *mcp.ListToolsResult, selector *accounts.Selector) *mcp.ListToolsResult {
+	var names []any
+	for _, account := range selector.Accounts() {
+		names = append(names, account.Name)
+	}
+	result := *list
+	result.Tools = make([]*mcp.Tool, 0, len(list.Tools))
+	for _, tool := range list.Tools {
+		schema, ok := tool.InputSchema.(*jsonschema.Schema)
+		if !ok || tool.Name == github.SelectAccountToolName {
+			result.Tools = append(result.Tools, tool)
+			continue
+		}
+		schema = schema.CloneSchemas()
+		if schema.Properties == nil {
+			schema.Properties = make(map[string]*jsonschema.Schema)
+		}
+		schema.Properties[accountArgument] = &jsonschema.Schema{
+			Type:        "string",
+			Description: "Account to act as, instead of the one selected for the session with select_account",
+			Enum:        names,
+		}
+		copied := *tool
+		copied.InputSchema = schema
+		result.Tools = append(result.Tools, &copied)
+	}
+	return &result
+}
@@ -0,0 +1,101 @@
+package ghmcp
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/github/github-mcp-server/pkg/accounts"
+	"github.com/github/github-mcp-server/pkg/github"
+	"github.com/github/github-mcp-server/pkg/toolsets"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_addAccountToContext(t *testing.T) {
+	ctx := context.Background()
+	server := mcp.NewServer(&mcp.Implementation{Name: "server"}, nil)
+	// whoami reports the account it acts as and the arguments it receives
+	whoami := &mcp.Tool{Name: "whoami", InputSchema: &jsonschema.Schema{Type: "object", Properties: map[string]*jsonschema.Schema{"owner": {Type: "string"}}}}
+	mcp.AddTool(server, whoami, func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		return nil, map[string]any{"account": accounts.AccountFromContext(ctx), "args": args}, nil
+	})
+	toolsets.NewServerTool(github.SelectAccount(translations.NullTranslationHelper)).RegisterFunc(server)
+	server.AddReceivingMiddleware(addAccountToContext(accounts.NewSelector("", accounts.Account{Name: "ghes", Host: "https://github.example.com"})))
+
+	connect := func() *mcp.ClientSession {
+		serverTransport, clientTransport := mcp.NewInMemoryTransports()
+		serverSession, err := server.Connect(ctx, serverTransport, nil)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = serverSession.Close() })
+		session, err := mcp.NewClient(&mcp.Implementation{Name: "client"}, nil).Connect(ctx, clientTransport, nil)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = session.Close() })
+		return session
+	}
+	call := func(session *mcp.ClientSession, name string, args map[string]any) *mcp.CallToolResult {
+		t.Helper()
+		result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: name, Arguments: args})
+		require.NoError(t, err)
+		return result
+	}
+	whoamiOf := func(result *mcp.CallToolResult) map[string]any {
+		t.Helper()
+		require.False(t, result.IsError, result.Content)
+		var reported map[string]any
+		encoded, err := json.Marshal(result.StructuredContent)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(encoded, &reported))
+		return reported
+	}
+	session := connect()
+
+	// Listed tools take the account argument, except select_account, which has its own
+	tools, err := session.ListTools(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, tools.Tools, 2)
+	for _, tool := range tools.Tools {
+		properties := tool.InputSchema.(map[string]any)["properties"].(map[string]any)
+		if tool.Name == whoami.Name {
+			assert.Equal(t, []any{"default", "ghes"}, properties["account"].(map[string]any)["enum"])
+			assert.Contains(t, properties, "owner")
+		} else {
+			assert.NotContains(t, properties["account"], "enum")
+		}
+	}
+	assert.NotContains(t, whoami.InputSchema.(*jsonschema.Schema).Properties, "account")
+
+	assert.Equal(t, map[string]any{"account": "default", "args": map[string]any{"owner": "acme"}}, whoamiOf(call(session, "whoami", map[string]any{"owner": "acme"})))
+	assert.Equal(t, map[string]any{"account": "ghes", "args": map[string]any{"owner": "acme"}}, whoamiOf(call(session, "whoami", map[string]any{"owner": "acme", "account": "ghes"})))
+	result := call(session, "whoami", map[string]any{"account": "work"})
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, `unknown account "work"`)
+
+	// A session selecting an account acts as it unless a call names another
+	require.False(t, call(session, github.SelectAccountToolName, map[string]any{"account": "ghes"}).IsError)
+	assert.Equal(t, "ghes", whoamiOf(call(session, "whoami", nil))["account"])
+	assert.Equal(t, "default", whoamiOf(call(session, "whoami", map[string]any{"account": "default"}))["account"])
+	assert.Equal(t, "default", whoamiOf(call(connect(), "whoami", nil))["account"])
+}
+
+func Test_newMCPServer_Accounts(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	_, _, err := newMCPServer(MCPServerConfig{
+		Translator: translations.NullTranslationHelper,
+		Logger:     logger,
+		Accounts:   []Account{{Name: "work", Token: "ghp_work"}, {Name: "tenant", Host: "https://tenant.ghe.com", Token: "ghp_tenant"}},
+	})
+	assert.NoError(t, err)
+
+	_, _, err = newMCPServer(MCPServerConfig{
+		Translator: translations.NullTranslationHelper,
+		Logger:     logger,
+		Accounts:   []Account{{Name: "work", Token: "a"}, {Name: "work", Token: "b"}},
+	})
+	assert.EqualError(t, err, "account work is configured more than once")
+}
@@ -0,0 +1,165 @@
+package ghmcp
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/github/github-mcp-server/pkg/github"
+	"github.com/github/github-mcp-server/pkg/utils"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// enterpriseVersionTimeout bounds the detection of the version of a GitHub Enterprise Server
+const enterpriseVersionTimeout = 10 * time.Second
+
+// EnterpriseURLs override the URLs of the APIs of a GitHub Enterprise Server, for instances
+// serving them elsewhere than where their host implies. Empty URLs keep the derived ones.
+type EnterpriseURLs struct {
+	// API is the REST API URL (e.g. https://github.example.com/api/v3/)
+	API string
+	// Upload is the URL release assets are uploaded to (e.g. https://github.example.com/api/uploads/)
+	Upload string
+	// GraphQL is the GraphQL API URL (e.g. https://github.example.com/api/graphql)
+	GraphQL string
+}
+
+// withURLs returns h with the URLs that urls override.
+func (h apiHost) withURLs(urls EnterpriseURLs) (apiHost, error) {
+	overrides := []struct {
+		name   string
+		value  string
+		target **url.URL
+		// directory URLs end with a slash, as go-github requires
+		directory bool
+	}{
+		{"API", urls.API, &h.baseRESTURL, true},
+		{"upload", urls.Upload, &h.uploadURL, true},
+		{"GraphQL", urls.GraphQL, &h.graphqlURL, false},
+	}
+	for _, o := range overrides {
+		if o.value == "" {
+			continue
+		}
+		u, err := url.Parse(o.value)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return apiHost{}, fmt.Errorf("invalid %s URL: %q", o.name, o.value)
+		}
+		if o.directory && !strings.HasSuffix(u.Path, "/") {
+			u.Path += "/"
+		}
+		*o.target = u
+	}
+	return h, nil
+}
+
+// enterpriseTools are the tools relying on APIs that a GitHub Enterprise Server lacks, per host.
+// The version of a host is the configured one, or else the one its meta endpoint reports to the
+// first account on it that needs it, whose authentication the request carries.
+type enterpriseTools struct {
+	logger *slog.Logger
+
+	mu    sync.Mutex
+	hosts map[string]*enterpriseHost
+}
+
+// enterpriseHost holds the tools that the GitHub Enterprise Server at a host lacks, once known
+type enterpriseHost struct {
+	once  sync.Once
+	tools map[string]bool
+}
+
+// newEnterpriseTools returns the enterpriseTools of the servers, version being that of the one
+// at host, detected when empty.
+func newEnterpriseTools(logger *slog.Logger, host apiHost, version string) *enterpriseTools {
+	e := &enterpriseTools{logger: logger, hosts: make(map[string]*enterpriseHost)}
+	if host.enterpriseServer && version != "" {
+		h := e.host(host)
+		h.once.Do(func() { e.record(h, host, version) })
+	}
+	return e
+}
+
+// host returns the enterpriseHost of host, whose tools are unknown until its once has run
+func (e *enterpriseTools) host(host apiHost) *enterpriseHost {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	key := host.baseRESTURL.String()
+	h, ok := e.hosts[key]
+	if !ok {
+		h = &enterpriseHost{}
+		e.hosts[key] = h
+	}
+	return h
+}
+
+// record sets the tools that h, the GitHub Enterprise Server at host, lacks for its version
+func (e *enterpriseTools) record(h *enterpriseHost, host apiHost, version string) {
+	tools := github.EnterpriseUnavailableTools(version)
+	h.tools = make(map[string]bool, len(tools))
+	for _, tool := range tools {
+		h.tools[tool] = true
+	}
+	e.logger.Info("targeting GitHub Enterprise Server", "host", host.baseRESTURL.Host, "version", version, "unavailableTools", tools)
+}
+
+// unavailable returns the tools that the host of c lacks, detecting its version with the
+// client of c the first time.
+func (e *enterpriseTools) unavailable(ctx context.Context, c *githubClients) map[string]bool {
+	if !c.host.enterpriseServer {
+		return nil
+	}
+	h := e.host(c.host)
+	h.once.Do(func() {
+		// The detection is shared by the later requests, so it does not end with this one
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), enterpriseVersionTimeout)
+		defer cancel()
+		version, err := github.EnterpriseVersion(ctx, c.rest)
+		if err != nil {
+			e.logger.Warn("failed to detect the GitHub Enterprise Server version, set it to hide the tools it lacks", "host", c.host.baseRESTURL.Host, "error", err)
+		}
+		e.record(h, c.host, version)
+	})
+	return h.tools
+}
+
+// hideEnterpriseUnavailableTools leaves out of tool lists, and fails the calls of, the tools
+// relying on APIs that the GitHub Enterprise Server of the account acting lacks.
+func hideEnterpriseUnavailableTools(tools *enterpriseTools, clientsOf func(context.Context) (*githubClients, error)) func(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			if method != "tools/list" && method != "tools/call" {
+				return next(ctx, method, req)
+			}
+			// Unknown accounts are left for the tool call to report
+			c, err := clientsOf(ctx)
+			if err != nil {
+				return next(ctx, method, req)
+			}
+			unavailable := tools.unavailable(ctx, c)
+			if len(unavailable) == 0 {
+				return next(ctx, method, req)
+			}
+
+			if callReq, ok := req.(*mcp.CallToolRequest); ok && callReq.Params != nil && unavailable[callReq.Params.Name] {
+				return utils.NewToolResultError(fmt.Sprintf("%s is not available on the GitHub Enterprise Server at %s, which lacks the API it relies on", callReq.Params.Name, c.host.baseRESTURL.Host)), nil
+			}
+			result, err := next(ctx, method, req)
+			if list, ok := result.(*mcp.ListToolsResult); ok && list != nil {
+				filtered := *list
+				filtered.Tools = make([]*mcp.Tool, 0, len(list.Tools))
+				for _, tool := range list.Tools {
+					if !unavailable[tool.Name] {
+						filtered.Tools = append(filtered.Tools, tool)
+					}
+				}
+				result = &filtered
+			}
+			return result, err
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package ghmcp
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/github/github-mcp-server/pkg/github"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_newMCPServer_Enterprise(t *testing.T) {
+	// ghesServer answers the meta requests made with token with version
+	ghesServer := func(metaPath, token, version string) (*httptest.Server, *atomic.Int32) {
+		var requests atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != metaPath || r.Header.Get("Authorization") != "Bearer "+token {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			requests.Add(1)
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"installed_version": "` + version + `"}`))
+		}))
+		t.Cleanup(server.Close)
+		return server, &requests
+	}
+	ghes, metaRequests := ghesServer("/custom/v3/meta", "ghp_token", "3.16.2")
+	legacy, legacyRequests := ghesServer("/api/v3/meta", "ghp_legacy", "3.10.0")
+
+	ctx := context.Background()
+	connect := func(version string) *mcp.ClientSession {
+		t.Helper()
+		server, _, err := newMCPServer(MCPServerConfig{
+			Host:              ghes.URL,
+			Token:             "ghp_token",
+			EnterpriseURLs:    EnterpriseURLs{API: ghes.URL + "/custom/v3"},
+			EnterpriseVersion: version,
+			Accounts:          []Account{{Name: "legacy", Host: legacy.URL, Token: "ghp_legacy"}},
+			EnabledToolsets:   []string{"all"},
+			Translator:        translations.NullTranslationHelper,
+			Logger:            slog.New(slog.NewTextHandler(io.Discard, nil)),
+		})
+		require.NoError(t, err)
+		serverTransport, clientTransport := mcp.NewInMemoryTransports()
+		serverSession, err := server.Connect(ctx, serverTransport, nil)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = serverSession.Close() })
+		session, err := mcp.NewClient(&mcp.Implementation{Name: "client"}, nil).Connect(ctx, clientTransport, nil)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = session.Close() })
+		return session
+	}
+	listed := func(session *mcp.ClientSession) map[string]bool {
+		t.Helper()
+		tools, err := session.ListTools(ctx, nil)
+		require.NoError(t, err)
+		names := make(map[string]bool, len(tools.Tools))
+		for _, tool := range tools.Tools {
+			names[tool.Name] = true
+		}
+		return names
+	}
+	callError := func(session *mcp.ClientSession, name string, args map[string]any) string {
+		t.Helper()
+		result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: name, Arguments: args})
+		require.NoError(t, err)
+		require.True(t, result.IsError)
+		return result.Content[0].(*mcp.TextContent).Text
+	}
+
+	// The version is detected once the tools are needed, with the token of the account
+	session := connect("")
+	assert.Equal(t, int32(0), metaRequests.Load())
+	tools := listed(session)
+	assert.Equal(t, int32(1), metaRequests.Load())
+	assert.True(t, tools["get_me"])
+	assert.True(t, tools["list_repository_rulesets"])
+	assert.False(t, tools["list_issue_types"])
+	assert.False(t, tools["list_projects"])
+	assert.False(t, tools["assign_copilot_to_issue"])
+	assert.Equal(t, "list_issue_types is not available on the GitHub Enterprise Server at "+ghes.Listener.Addr().String()+", which lacks the API it relies on",
+		callError(session, "list_issue_types", map[string]any{"owner": "acme"}))
+
+	// Each host is detected once, and the tools its version lacks are hidden from its accounts
+	assert.Contains(t, callError(session, "list_repository_rulesets", map[string]any{"owner": "acme", "repo": "widgets", "account": "legacy"}),
+		"list_repository_rulesets is not available on the GitHub Enterprise Server at "+legacy.Listener.Addr().String())
+	assert.True(t, listed(session)["list_repository_rulesets"])
+	result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: github.SelectAccountToolName, Arguments: map[string]any{"account": "legacy"}})
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	assert.False(t, listed(session)["list_repository_rulesets"])
+	assert.Equal(t, int32(1), metaRequests.Load())
+	assert.Equal(t, int32(1), legacyRequests.Load())
+
+	// A configured version is not detected
+	tools = listed(connect("3.19"))
+	assert.Equal(t, int32(1), metaRequests.Load())
+	assert.True(t, tools["list_issue_types"])
+	assert.False(t, tools["get_copilot_metrics"])
+}
+
+func Test_apiHost_withURLs(t *testing.T) {
+	host, err := parseAPIHost("")
+	require.NoError(t, err)
+
+	host, err = host.withURLs(EnterpriseURLs{API: "https://ghes.example.com:8443/api/v3", GraphQL: "https://ghes.example.com:8443/api/graphql"})
+	require.NoError(t, err)
+	assert.Equal(t, "https://ghes.example.com:8443/api/v3/", host.baseRESTURL.String())
+	assert.Equal(t, "https://ghes.example.com:8443/api/graphql", host.graphqlURL.String())
+	assert.Equal(t, "https://uploads.github.com", host.uploadURL.String())
+
+	_, err = host.withURLs(EnterpriseURLs{Upload: "ghes.example.com/api/uploads"})
+	assert.EqualError(t, err, `invalid upload URL: "ghes.example.com/api/uploads"`)
+}
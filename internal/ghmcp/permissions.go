@@ -0,0 +1,53 @@
+package ghmcp
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+
+	"github.com/github/github-mcp-server/pkg/github"
+	"github.com/github/github-mcp-server/pkg/utils"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// checkToolPermissions fails the tool calls needing a permission that the token lacks before
+// they reach GitHub, so that they report the permission rather than a 404, and gives tools
+// access to checker. Calls go ahead when the grants of the token cannot be read.
+func checkToolPermissions(checker *github.PermissionChecker, registry *toolRegistry, logger *slog.Logger) func(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			ctx = github.ContextWithPermissionChecker(ctx, checker)
+			callReq, ok := req.(*mcp.CallToolRequest)
+			if method != "tools/call" || !ok || callReq.Params == nil {
+				return next(ctx, method, req)
+			}
+			name := callReq.Params.Name
+			required := registry.requiredPermissions(name)
+			if len(required) == 0 {
+				return next(ctx, method, req)
+			}
+
+			grants, err := checker.Grants(ctx, ownerArgument(callReq.Params))
+			if err != nil {
+				logger.Debug("failed to check the permissions of the token", "tool", name, "error", err)
+				return next(ctx, method, req)
+			}
+			if missing := grants.Missing(required); len(missing) > 0 {
+				return utils.NewToolResultError(github.LacksPermissionsError(name, missing)), nil
+			}
+			return next(ctx, method, req)
+		}
+	}
+}
+
+// ownerArgument returns the owner argument of a tool call, which decides the GitHub App
+// installation its requests use.
+func ownerArgument(params *mcp.CallToolParamsRaw) string {
+	var args struct {
+		Owner string `json:"owner"`
+	}
+	if len(params.Arguments) == 0 || json.Unmarshal(params.Arguments, &args) != nil {
+		return ""
+	}
+	return args.Owner
+}
@@ -0,0 +1,71 @@
+package ghmcp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/github/github-mcp-server/pkg/github"
+	gogithub "github.com/google/go-github/v79/github"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_checkToolPermissions(t *testing.T) {
+	ghServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/user", r.URL.Path)
+		w.Header().Set("X-OAuth-Scopes", "read:org")
+		_, _ = w.Write([]byte(`{"login": "octocat"}`))
+	}))
+	defer ghServer.Close()
+	client := gogithub.NewClient(nil)
+	client.BaseURL, _ = url.Parse(ghServer.URL + "/")
+	getClient := func(context.Context) (*gogithub.Client, error) { return client, nil }
+
+	registry := &toolRegistry{server: mcp.NewServer(&mcp.Implementation{Name: "test"}, nil), newGroup: testToolsetGroup}
+	_, err := registry.apply(ReloadableConfig{EnabledToolsets: []string{"issues"}, ContentWindowSize: 10})
+	require.NoError(t, err)
+
+	called := 0
+	handler := func(ctx context.Context, _ string, _ mcp.Request) (mcp.Result, error) {
+		_, ok := github.PermissionCheckerFromContext(ctx)
+		assert.True(t, ok)
+		called++
+		return &mcp.CallToolResult{}, nil
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	middleware := checkToolPermissions(github.NewPermissionChecker(getClient), registry, logger)(handler)
+	call := func(name string) *mcp.CallToolResult {
+		t.Helper()
+		result, err := middleware(context.Background(), "tools/call", &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{Name: name, Arguments: []byte(`{"owner": "acme"}`)}})
+		require.NoError(t, err)
+		return result.(*mcp.CallToolResult)
+	}
+
+	// Classic tokens need no scope to read public repositories
+	assert.False(t, call("get_issue").IsError)
+	result := call("create_issue")
+	require.True(t, result.IsError)
+	assert.Equal(t, "token lacks issues:write, which create_issue requires. Call verify_access to see the permissions of the token", result.Content[0].(*mcp.TextContent).Text)
+	assert.Equal(t, 1, called)
+
+	// Calls go ahead when the grants of the token cannot be read
+	failing := checkToolPermissions(github.NewPermissionChecker(func(context.Context) (*gogithub.Client, error) {
+		return nil, errors.New("no client")
+	}), registry, logger)(handler)
+	_, err = failing(context.Background(), "tools/call", &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{Name: "create_issue"}})
+	require.NoError(t, err)
+	assert.Equal(t, 2, called)
+}
+
+func Test_ownerArgument(t *testing.T) {
+	assert.Equal(t, "acme", ownerArgument(&mcp.CallToolParamsRaw{Arguments: []byte(`{"owner": "acme", "repo": "widgets"}`)}))
+	assert.Empty(t, ownerArgument(&mcp.CallToolParamsRaw{Arguments: []byte(`{"owner": 42}`)}))
+	assert.Empty(t, ownerArgument(&mcp.CallToolParamsRaw{}))
+}
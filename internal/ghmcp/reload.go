@@ -0,0 +1,323 @@
+package ghmcp
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/github/github-mcp-server/pkg/github"
+	"github.com/github/github-mcp-server/pkg/toolsets"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// reloadDebounce groups the bursts of events that editors produce when saving a file.
+const reloadDebounce = 250 * time.Millisecond
+
+// ReloadableConfig holds the settings that can change while the server runs.
+type ReloadableConfig struct {
+	// EnabledToolsets is a list of toolsets to enable
+	EnabledToolsets []string
+
+	// EnabledTools is a list of specific tools to enable (additive to toolsets)
+	EnabledTools []string
+
+	// ReadOnly indicates if we should only offer read-only tools
+	ReadOnly bool
+
+	// LockdownMode indicates if we should enable lockdown mode
+	LockdownMode bool
+
+	// Content window size
+	ContentWindowSize int
+}
+
+// ReloadedConfig is the configuration read again by StdioServerConfig.Reload.
+type ReloadedConfig struct {
+	ReloadableConfig
+
+	// LogLevel is debug, info, warn or error; empty keeps the startup default
+	LogLevel string
+
+	// RestartRequired names the changed settings that only take effect after a restart
+	RestartRequired []string
+}
+
+// toolRegistry tracks the tools, resource templates and prompts registered on a
+// server, so that a reload registers only what became enabled and removes what
+// no longer is. go-sdk notifies clients with list_changed on each change.
+type toolRegistry struct {
+	mu     sync.Mutex
+	server *mcp.Server
+	// newGroup builds the toolset group for a configuration, with enabled toolsets
+	newGroup func(ReloadableConfig) (*toolsets.ToolsetGroup, error)
+
+	group     *toolsets.ToolsetGroup
+	current   ReloadableConfig
+	tools     map[string]bool
+	templates map[string]bool
+	prompts   map[string]bool
+}
+
+// toolChanges lists the tools added and removed by a reload.
+type toolChanges struct {
+	Added   []string
+	Removed []string
+}
+
+// apply registers the features selected by cfg and removes the rest. Nothing is
+// changed on the server if cfg cannot be applied.
+func (r *toolRegistry) apply(cfg ReloadableConfig) (toolChanges, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	group, err := r.newGroup(cfg)
+	if err != nil {
+		return toolChanges{}, err
+	}
+	sel, err := group.Select(cfg.EnabledTools, cfg.ReadOnly)
+	if err != nil {
+		return toolChanges{}, fmt.Errorf("failed to register tools: %w", err)
+	}
+
+	// Handlers capture lockdown mode and the content window size, so a change
+	// to either replaces every registered handler
+	replaceAll := r.group == nil ||
+		cfg.LockdownMode != r.current.LockdownMode ||
+		cfg.ContentWindowSize != r.current.ContentWindowSize
+
+	var changes toolChanges
+	tools := make(map[string]bool, len(sel.Tools))
+	for _, tool := range sel.Tools {
+		tools[tool.Tool.Name] = true
+		if !r.tools[tool.Tool.Name] {
+			changes.Added = append(changes.Added, tool.Tool.Name)
+		} else if !replaceAll {
+			continue
+		}
+		tool.RegisterFunc(r.server)
+	}
+	templates := make(map[string]bool, len(sel.ResourceTemplates))
+	for _, template := range sel.ResourceTemplates {
+		templates[template.Template.URITemplate] = true
+		if replaceAll || !r.templates[template.Template.URITemplate] {
+			r.server.AddResourceTemplate(&template.Template, template.Handler)
+		}
+	}
+	prompts := make(map[string]bool, len(sel.Prompts))
+	for _, prompt := range sel.Prompts {
+		prompts[prompt.Prompt.Name] = true
+		if replaceAll || !r.prompts[prompt.Prompt.Name] {
+			r.server.AddPrompt(&prompt.Prompt, prompt.Handler)
+		}
+	}
+
+	// Tools removed from the group, such as those the host lacks the APIs of, are not found
+	var skippedTools []string
+	for _, name := range cfg.EnabledTools {
+		if _, _, err := group.FindToolByName(name); err == nil && !tools[name] {
+			skippedTools = append(skippedTools, name)
+		}
+	}
+	if len(skippedTools) > 0 {
+		fmt.Fprintf(os.Stderr, "Write tools skipped due to read-only mode: %s\n", strings.Join(skippedTools, ", "))
+	}
+
+	changes.Removed = missingKeys(r.tools, tools)
+	if len(changes.Removed) > 0 {
+		r.server.RemoveTools(changes.Removed...)
+	}
+	if removed := missingKeys(r.templates, templates); len(removed) > 0 {
+		r.server.RemoveResourceTemplates(removed...)
+	}
+	if removed := missingKeys(r.prompts, prompts); len(removed) > 0 {
+		r.server.RemovePrompts(removed...)
+	}
+
+	sort.Strings(changes.Added)
+	r.group, r.current = group, cfg
+	r.tools, r.templates, r.prompts = tools, templates, prompts
+	return changes, nil
+}
+
+func (r *toolRegistry) currentConfig() ReloadableConfig {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.current
+}
+
+// isReadOnly reports whether the tool called name only reads data.
+func (r *toolRegistry) isReadOnly(name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.group == nil {
+		return false
+	}
+	tool, _, err := r.group.FindToolByName(name)
+	return err == nil && tool.Tool.Annotations != nil && tool.Tool.Annotations.ReadOnlyHint
+}
+
+// requiredPermissions returns the permissions the tool called name needs.
+func (r *toolRegistry) requiredPermissions(name string) []github.Permission {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.group == nil {
+		return nil
+	}
+	tool, toolset, err := r.group.FindToolByName(name)
+	if err != nil {
+		return nil
+	}
+	return github.ToolPermissions(toolset, tool.Tool)
+}
+
+// missingKeys returns the keys of previous that are not in next, sorted.
+func missingKeys(previous, next map[string]bool) []string {
+	var missing []string
+	for key := range previous {
+		if !next[key] {
+			missing = append(missing, key)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// reloader applies a reloaded configuration to a running stdio server.
+type reloader struct {
+	load     func() (ReloadedConfig, error)
+	registry *toolRegistry
+	logLevel *slog.LevelVar
+	// defaultLevel is used when the reloaded configuration sets no level
+	defaultLevel slog.Level
+	// dynamicToolsets disables tool reloads, since clients enable toolsets themselves
+	dynamicToolsets bool
+	logger          *slog.Logger
+}
+
+// reload loads the configuration and applies it. The current configuration is
+// kept if the new one is invalid.
+func (r *reloader) reload(trigger string) {
+	r.logger.Info("reloading configuration", "trigger", trigger)
+
+	cfg, err := r.load()
+	if err != nil {
+		r.logger.Error("failed to reload configuration, keeping the current one", "error", err)
+		return
+	}
+	level, err := parseLogLevel(cfg.LogLevel, r.defaultLevel)
+	if err != nil {
+		r.logger.Error("failed to reload configuration, keeping the current one", "error", err)
+		return
+	}
+
+	cfg.EnabledToolsets = resolveToolsets(cfg.EnabledToolsets, r.dynamicToolsets)
+	cfg.EnabledTools = github.CleanTools(cfg.EnabledTools)
+
+	if len(cfg.RestartRequired) > 0 {
+		r.logger.Warn("changed settings take effect after a restart", "settings", cfg.RestartRequired)
+	}
+
+	if r.dynamicToolsets {
+		if !reloadableEqual(r.registry.currentConfig(), cfg.ReloadableConfig) {
+			r.logger.Warn("tool settings are not reloaded when dynamic toolsets are enabled")
+		}
+	} else {
+		changes, err := r.registry.apply(cfg.ReloadableConfig)
+		if err != nil {
+			r.logger.Error("failed to reload configuration, keeping the current one", "error", err)
+			return
+		}
+		if len(changes.Added) > 0 || len(changes.Removed) > 0 {
+			r.logger.Info("tools changed", "added", changes.Added, "removed", changes.Removed)
+		}
+	}
+
+	r.logLevel.Set(level)
+	r.logger.Info("configuration reloaded", "readOnly", cfg.ReadOnly, "lockdownEnabled", cfg.LockdownMode, "logLevel", level)
+}
+
+func reloadableEqual(a, b ReloadableConfig) bool {
+	return slices.Equal(a.EnabledToolsets, b.EnabledToolsets) &&
+		slices.Equal(a.EnabledTools, b.EnabledTools) &&
+		a.ReadOnly == b.ReadOnly &&
+		a.LockdownMode == b.LockdownMode &&
+		a.ContentWindowSize == b.ContentWindowSize
+}
+
+// parseLogLevel parses debug, info, warn or error, returning defaultLevel when level is empty.
+func parseLogLevel(level string, defaultLevel slog.Level) (slog.Level, error) {
+	if level == "" {
+		return defaultLevel, nil
+	}
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(level)); err != nil {
+		return 0, fmt.Errorf("invalid log level %q: %w", level, err)
+	}
+	return l, nil
+}
+
+// watchReload calls reload on SIGHUP and, if configFile is not empty, when the
+// file changes, until ctx is done.
+func watchReload(ctx context.Context, configFile string, logger *slog.Logger, reload func(trigger string)) error {
+	var watcher *fsnotify.Watcher
+	var events <-chan fsnotify.Event
+	var watchErrs <-chan error
+	var target string
+	if configFile != "" {
+		var err error
+		target, err = filepath.Abs(configFile)
+		if err != nil {
+			return fmt.Errorf("failed to resolve config file path: %w", err)
+		}
+		watcher, err = fsnotify.NewWatcher()
+		if err != nil {
+			return fmt.Errorf("failed to create file watcher: %w", err)
+		}
+		// Watch the directory, since editors often save by replacing the file
+		if err := watcher.Add(filepath.Dir(target)); err != nil {
+			_ = watcher.Close()
+			return fmt.Errorf("failed to watch %s: %w", filepath.Dir(target), err)
+		}
+		events, watchErrs = watcher.Events, watcher.Errors
+	}
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	go func() {
+		defer signal.Stop(hup)
+		if watcher != nil {
+			defer func() { _ = watcher.Close() }()
+		}
+
+		var debounce <-chan time.Time
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hup:
+				reload("SIGHUP")
+			case event := <-events:
+				if filepath.Clean(event.Name) == target && event.Has(fsnotify.Write|fsnotify.Create) {
+					debounce = time.After(reloadDebounce)
+				}
+			case <-debounce:
+				debounce = nil
+				reload("config file changed")
+			case err := <-watchErrs:
+				logger.Warn("config file watch failed", "error", err)
+			}
+		}
+	}()
+	return nil
+}
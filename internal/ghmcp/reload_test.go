@@ -0,0 +1,114 @@
+package ghmcp
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/github/github-mcp-server/pkg/toolsets"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testToolsetGroup(rc ReloadableConfig) (*toolsets.ToolsetGroup, error) {
+	handler := func(_ context.Context, _ *mcp.CallToolRequest, _ map[string]any) (*mcp.CallToolResult, any, error) {
+		return &mcp.CallToolResult{}, nil, nil
+	}
+	tool := func(name string, readOnly bool) toolsets.ServerTool {
+		return toolsets.NewServerTool(mcp.Tool{
+			Name:        name,
+			Annotations: &mcp.ToolAnnotations{ReadOnlyHint: readOnly},
+			InputSchema: map[string]any{"type": "object"},
+		}, mcp.ToolHandlerFor[map[string]any, any](handler))
+	}
+
+	tsg := toolsets.NewToolsetGroup(rc.ReadOnly)
+	issues := toolsets.NewToolset("issues", "Issues")
+	issues.AddReadTools(tool("get_issue", true)).AddWriteTools(tool("create_issue", false))
+	tsg.AddToolset(issues)
+	repos := toolsets.NewToolset("repos", "Repositories")
+	repos.AddReadTools(tool("get_file", true)).AddWriteTools(tool("push_files", false))
+	tsg.AddToolset(repos)
+
+	if err := tsg.EnableToolsets(rc.EnabledToolsets, &toolsets.EnableToolsetsOptions{ErrorOnUnknown: true}); err != nil {
+		return nil, err
+	}
+	return tsg, nil
+}
+
+func Test_toolRegistryApply(t *testing.T) {
+	ctx := context.Background()
+	server := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+	registry := &toolRegistry{server: server, newGroup: testToolsetGroup}
+
+	_, err := registry.apply(ReloadableConfig{EnabledToolsets: []string{"issues"}, ContentWindowSize: 10})
+	require.NoError(t, err)
+
+	listChanged := make(chan struct{}, 10)
+	client := mcp.NewClient(&mcp.Implementation{Name: "client"}, &mcp.ClientOptions{
+		ToolListChangedHandler: func(context.Context, *mcp.ToolListChangedRequest) {
+			listChanged <- struct{}{}
+		},
+	})
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	defer func() { _ = serverSession.Close() }()
+	session, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	defer func() { _ = session.Close() }()
+
+	toolNames := func() []string {
+		result, err := session.ListTools(ctx, nil)
+		require.NoError(t, err)
+		var names []string
+		for _, tool := range result.Tools {
+			names = append(names, tool.Name)
+		}
+		sort.Strings(names)
+		return names
+	}
+	assert.Equal(t, []string{"create_issue", "get_issue"}, toolNames())
+
+	// Switching toolsets and policies adds and removes tools, and clients are notified
+	changes, err := registry.apply(ReloadableConfig{EnabledToolsets: []string{"repos"}, EnabledTools: []string{"get_issue"}, ReadOnly: true, ContentWindowSize: 10})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"get_file"}, changes.Added)
+	assert.Equal(t, []string{"create_issue"}, changes.Removed)
+	assert.Equal(t, []string{"get_file", "get_issue"}, toolNames())
+	<-listChanged
+
+	// An invalid configuration leaves the registered tools alone
+	_, err = registry.apply(ReloadableConfig{EnabledToolsets: []string{"missing"}, ContentWindowSize: 10})
+	require.Error(t, err)
+	_, err = registry.apply(ReloadableConfig{EnabledToolsets: []string{"repos"}, EnabledTools: []string{"missing_tool"}, ContentWindowSize: 10})
+	require.Error(t, err)
+	assert.Equal(t, []string{"get_file", "get_issue"}, toolNames())
+	assert.Equal(t, []string{"repos"}, registry.currentConfig().EnabledToolsets)
+}
+
+func Test_parseLogLevel(t *testing.T) {
+	level, err := parseLogLevel("", -4)
+	require.NoError(t, err)
+	assert.Equal(t, "DEBUG", level.String())
+
+	level, err = parseLogLevel("warn", 0)
+	require.NoError(t, err)
+	assert.Equal(t, "WARN", level.String())
+
+	_, err = parseLogLevel("verbose", 0)
+	require.Error(t, err)
+}
+
+func Test_toolRegistryIsReadOnly(t *testing.T) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+	registry := &toolRegistry{server: server, newGroup: testToolsetGroup}
+	assert.False(t, registry.isReadOnly("get_issue"))
+
+	_, err := registry.apply(ReloadableConfig{EnabledToolsets: []string{"issues"}, ContentWindowSize: 10})
+	require.NoError(t, err)
+	assert.True(t, registry.isReadOnly("get_issue"))
+	assert.False(t, registry.isReadOnly("create_issue"))
+	assert.False(t, registry.isReadOnly("unknown_tool"))
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
@@ -13,12 +14,23 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/github/github-mcp-server/pkg/accounts"
 	"github.com/github/github-mcp-server/pkg/errors"
+	"github.com/github/github-mcp-server/pkg/ghapp"
 	"github.com/github/github-mcp-server/pkg/github"
+	"github.com/github/github-mcp-server/pkg/httpcache"
+	"github.com/github/github-mcp-server/pkg/inflight"
 	"github.com/github/github-mcp-server/pkg/lockdown"
 	mcplog "github.com/github/github-mcp-server/pkg/log"
+	"github.com/github/github-mcp-server/pkg/metrics"
+	"github.com/github/github-mcp-server/pkg/oauth"
+	"github.com/github/github-mcp-server/pkg/ratelimit"
 	"github.com/github/github-mcp-server/pkg/raw"
+	"github.com/github/github-mcp-server/pkg/signing"
+	"github.com/github/github-mcp-server/pkg/toolsets"
+	"github.com/github/github-mcp-server/pkg/trace"
 	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/github/github-mcp-server/pkg/uploads"
 	gogithub "github.com/google/go-github/v79/github"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/shurcooL/githubv4"
@@ -34,6 +46,25 @@ type MCPServerConfig struct {
 	// GitHub Token to authenticate with the GitHub API
 	Token string
 
+	// EnterpriseURLs override the API URLs derived from Host
+	EnterpriseURLs EnterpriseURLs
+
+	// EnterpriseVersion is the version of the GitHub Enterprise Server at Host, which decides
+	// the tools available. It is detected with the meta endpoint when empty.
+	EnterpriseVersion string
+
+	// App authenticates the server as a GitHub App instead of with Token, with a token of the
+	// installation on the owner of each request
+	App *ghapp.Config
+
+	// OAuth signs the server in with the OAuth device flow instead of with Token, the first time
+	// a request needs a token that the keyring does not hold
+	OAuth *oauth.Config
+
+	// Accounts are further accounts, each with its own token and host, that tool calls can act
+	// as by naming them in their account argument or by selecting them for their session
+	Accounts []Account
+
 	// EnabledToolsets is a list of toolsets to enable
 	// See: https://github.com/github/github-mcp-server?tab=readme-ov-file#tool-configuration
 	EnabledToolsets []string
@@ -62,16 +93,105 @@ type MCPServerConfig struct {
 	Logger *slog.Logger
 	// RepoAccessTTL overrides the default TTL for repository access cache entries.
 	RepoAccessTTL *time.Duration
+
+	// CommitSigning configures signing of commits created by the bulk tools
+	CommitSigning signing.Config
+
+	// PushLimits bounds the pushes made by the file tools. Zero fields use the defaults.
+	PushLimits github.PushLimits
+
+	// PushHostLimits overrides PushLimits per GitHub hostname. Zero fields use PushLimits.
+	PushHostLimits map[string]github.PushLimits
+
+	// DefaultBranch is the initial branch of initialize_repository, main when empty
+	DefaultBranch string
+
+	// Uploads lets push_files_chunked reference content uploaded ahead of the call. Servers
+	// served over HTTP mount Uploads.Handler() to accept uploads; nil disables upload IDs.
+	Uploads *uploads.Store
+
+	// Metrics records the tool calls and GitHub API requests of the server, for
+	// get_server_metrics and the metrics listener. The server creates its own when nil.
+	Metrics *metrics.Metrics
+
+	// ResponseCacheMaxBytes bounds the GitHub API responses cached for conditional requests,
+	// whose 304 Not Modified answers do not count against the rate limit. Zero disables it.
+	ResponseCacheMaxBytes int64
+
+	// MaxConcurrentRequests bounds the GitHub API requests in flight. Zero leaves them unbounded.
+	MaxConcurrentRequests int
 }
 
 func NewMCPServer(cfg MCPServerConfig) (*mcp.Server, error) {
+	ghServer, _, err := newMCPServer(cfg)
+	return ghServer, err
+}
+
+// newMCPServer creates the server along with the registry of its tools, which
+// is used to apply configuration reloads.
+func newMCPServer(cfg MCPServerConfig) (*mcp.Server, *toolRegistry, error) {
 	apiHost, err := parseAPIHost(cfg.Host)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse API host: %w", err)
+		return nil, nil, fmt.Errorf("failed to parse API host: %w", err)
+	}
+	apiHost, err = apiHost.withURLs(cfg.EnterpriseURLs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to configure GitHub Enterprise Server URLs: %w", err)
+	}
+
+	serverMetrics := cfg.Metrics
+	if serverMetrics == nil {
+		serverMetrics = metrics.New()
+	}
+	if serverMetrics.RateLimiters == nil {
+		serverMetrics.RateLimiters = github.RateLimiterStats
 	}
 
 	// Construct our REST client
-	restClient := gogithub.NewClient(nil).WithAuthToken(cfg.Token)
+	// Every request carries the trace ID of the tool call that makes it, see addTraceToContext,
+	// and waits for the request limiter of its token, and for that of the tool making it if any,
+	// which every response retunes to the real quota of the token. Cached responses are revalidated beneath
+	// the rate limiters, so that they still see the headers of the 304, and requests are counted
+	// once sent.
+	var apiTransport http.RoundTripper = metrics.NewTransport(nil, serverMetrics)
+	if cfg.ResponseCacheMaxBytes > 0 {
+		responseCache := httpcache.NewTransport(apiTransport, cfg.ResponseCacheMaxBytes)
+		if serverMetrics.ResponseCache == nil {
+			serverMetrics.ResponseCache = responseCache.Stats
+		}
+		apiTransport = responseCache
+	}
+	// Requests take a slot once through the rate limiters, so that those waiting their turn do
+	// not hold one
+	if cfg.MaxConcurrentRequests > 0 {
+		apiTransport = inflight.NewLimiter(apiTransport, cfg.MaxConcurrentRequests)
+	}
+	// Identical GET requests in flight at the same time are sent once, before they wait on the
+	// rate limiters.
+	// The requests of further accounts, which carry their own token, start there.
+	tokenTransport := inflight.NewCoalescer(ratelimit.NewRegistryTransport(apiTransport, github.RequestLimiters()))
+	var rateLimitTransport http.RoundTripper = tokenTransport
+	// A GitHub App authenticates each request with the token of the installation on its owner,
+	// and is rate limited as that installation. The requests of the app itself, which look up
+	// installations and mint their tokens, skip the rate limiters and are not coalesced.
+	var app *ghapp.App
+	if cfg.App != nil {
+		app, err = ghapp.New(*cfg.App, apiHost.baseRESTURL, apiTransport)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to configure GitHub App authentication: %w", err)
+		}
+		rateLimitTransport = ghapp.NewTransport(app, rateLimitTransport)
+	}
+	// A server signing in with the device flow gets its token on the first request that needs
+	// one, so that the user can be prompted by the client making it
+	if cfg.OAuth != nil {
+		rateLimitTransport = oauth.NewTransport(oauth.NewAuthenticator(*cfg.OAuth, apiHost.webURL, cfg.Logger), rateLimitTransport)
+	}
+	authenticated := app != nil || cfg.OAuth != nil
+	restClient := gogithub.NewClient(&http.Client{Transport: trace.NewTransport(rateLimitTransport)})
+	if !authenticated {
+		restClient = restClient.WithAuthToken(cfg.Token)
+	}
 	restClient.UserAgent = fmt.Sprintf("github-mcp-server/%s", cfg.Version)
 	restClient.BaseURL = apiHost.baseRESTURL
 	restClient.UploadURL = apiHost.uploadURL
@@ -81,11 +201,37 @@ func NewMCPServer(cfg MCPServerConfig) (*mcp.Server, error) {
 	// did the necessary API host parsing so that github.com will return the correct URL anyway.
 	gqlHTTPClient := &http.Client{
 		Transport: &bearerAuthTransport{
-			transport: http.DefaultTransport,
+			transport: trace.NewTransport(rateLimitTransport),
 			token:     cfg.Token,
 		},
 	} // We're going to wrap the Transport later in beforeInit
+	if authenticated {
+		gqlHTTPClient.Transport = trace.NewTransport(rateLimitTransport)
+	}
 	gqlClient := githubv4.NewEnterpriseClient(apiHost.graphqlURL.String(), gqlHTTPClient)
+
+	// The clients of each account, the requests of a tool call using those of the account it
+	// acts as, see addAccountToContext
+	clients := map[string]*githubClients{
+		accounts.Default: {rest: restClient, gqlHTTP: gqlHTTPClient, gql: gqlClient, rawURL: apiHost.rawURL, host: apiHost},
+	}
+	for _, account := range cfg.Accounts {
+		if _, ok := clients[account.Name]; ok {
+			return nil, nil, fmt.Errorf("account %s is configured more than once", account.Name)
+		}
+		clients[account.Name], err = newAccountClients(account, tokenTransport, cfg.Version)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	clientsOf := func(ctx context.Context) (*githubClients, error) {
+		name := accounts.AccountFromContext(ctx)
+		c, ok := clients[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown account %q", name)
+		}
+		return c, nil
+	}
 	repoAccessOpts := []lockdown.RepoAccessOption{}
 	if cfg.RepoAccessTTL != nil {
 		repoAccessOpts = append(repoAccessOpts, lockdown.WithTTL(*cfg.RepoAccessTTL))
@@ -93,51 +239,48 @@ func NewMCPServer(cfg MCPServerConfig) (*mcp.Server, error) {
 
 	repoAccessLogger := cfg.Logger.With("component", "lockdown")
 	repoAccessOpts = append(repoAccessOpts, lockdown.WithLogger(repoAccessLogger))
-	var repoAccessCache *lockdown.RepoAccessCache
-	if cfg.LockdownMode {
-		repoAccessCache = lockdown.GetInstance(gqlClient, repoAccessOpts...)
-	}
-
-	enabledToolsets := cfg.EnabledToolsets
-
-	// If dynamic toolsets are enabled, remove "all" from the enabled toolsets
-	if cfg.DynamicToolsets {
-		enabledToolsets = github.RemoveToolset(enabledToolsets, github.ToolsetMetadataAll.ID)
+	var signer *signing.Signer
+	if cfg.CommitSigning.Enabled() {
+		signer, err = signing.New(cfg.CommitSigning)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to configure commit signing: %w", err)
+		}
 	}
 
-	// Clean up the passed toolsets
-	enabledToolsets, invalidToolsets := github.CleanToolsets(enabledToolsets)
-
-	// If "all" is present, override all other toolsets
-	if github.ContainsToolset(enabledToolsets, github.ToolsetMetadataAll.ID) {
-		enabledToolsets = []string{github.ToolsetMetadataAll.ID}
-	}
-	// If "default" is present, expand to real toolset IDs
-	if github.ContainsToolset(enabledToolsets, github.ToolsetMetadataDefault.ID) {
-		enabledToolsets = github.AddDefaultToolset(enabledToolsets)
+	// Tool schemas describe the push limits and default branch, so they are set before any tool is created
+	if clamped := github.SetPushLimits(cfg.PushLimits); len(clamped) > 0 {
+		cfg.Logger.Warn("push limits clamped to the range GitHub accepts", "limits", clamped)
 	}
+	github.SetLimitsProvider(github.NewHostLimitsProvider(cfg.PushHostLimits))
+	github.SetDefaultBranch(cfg.DefaultBranch)
 
-	if len(invalidToolsets) > 0 {
-		fmt.Fprintf(os.Stderr, "Invalid toolsets ignored: %s\n", strings.Join(invalidToolsets, ", "))
-	}
+	enabledToolsets := resolveToolsets(cfg.EnabledToolsets, cfg.DynamicToolsets)
 
 	// Generate instructions based on enabled toolsets
 	instructions := github.GenerateInstructions(enabledToolsets)
 
-	getClient := func(_ context.Context) (*gogithub.Client, error) {
-		return restClient, nil // closing over client
+	getClient := func(ctx context.Context) (*gogithub.Client, error) {
+		c, err := clientsOf(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return c.rest, nil
 	}
 
-	getGQLClient := func(_ context.Context) (*githubv4.Client, error) {
-		return gqlClient, nil // closing over client
+	getGQLClient := func(ctx context.Context) (*githubv4.Client, error) {
+		c, err := clientsOf(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return c.gql, nil
 	}
 
 	getRawClient := func(ctx context.Context) (*raw.Client, error) {
-		client, err := getClient(ctx)
+		c, err := clientsOf(ctx)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get GitHub client: %w", err)
 		}
-		return raw.NewClient(client, apiHost.rawURL), nil // closing over client
+		return raw.NewClient(c.rest, c.rawURL), nil
 	}
 
 	ghServer := github.NewServer(cfg.Version, &mcp.ServerOptions{
@@ -146,53 +289,116 @@ func NewMCPServer(cfg MCPServerConfig) (*mcp.Server, error) {
 		CompletionHandler: github.CompletionsHandler(getClient),
 	})
 
+	registry := &toolRegistry{
+		server: ghServer,
+		newGroup: func(rc ReloadableConfig) (*toolsets.ToolsetGroup, error) {
+			var repoAccessCache *lockdown.RepoAccessCache
+			if rc.LockdownMode {
+				repoAccessCache = lockdown.GetInstance(gqlClient, repoAccessOpts...)
+			}
+
+			// Create default toolsets
+			tsg := github.DefaultToolsetGroup(
+				rc.ReadOnly,
+				getClient,
+				getGQLClient,
+				getRawClient,
+				cfg.Translator,
+				rc.ContentWindowSize,
+				github.FeatureFlags{LockdownMode: rc.LockdownMode},
+				repoAccessCache,
+			)
+
+			// Enable toolsets if configured
+			// This always happens if toolsets are specified, regardless of whether tools are also specified
+			if len(rc.EnabledToolsets) > 0 {
+				if err := tsg.EnableToolsets(rc.EnabledToolsets, nil); err != nil {
+					return nil, fmt.Errorf("failed to enable toolsets: %w", err)
+				}
+			}
+			return tsg, nil
+		},
+	}
+
 	// Add middlewares
 	ghServer.AddReceivingMiddleware(addGitHubAPIErrorToContext)
-	ghServer.AddReceivingMiddleware(addUserAgentsMiddleware(cfg, restClient, gqlHTTPClient))
-
-	// Create default toolsets
-	tsg := github.DefaultToolsetGroup(
-		cfg.ReadOnly,
-		getClient,
-		getGQLClient,
-		getRawClient,
-		cfg.Translator,
-		cfg.ContentWindowSize,
-		github.FeatureFlags{LockdownMode: cfg.LockdownMode},
-		repoAccessCache,
-	)
-
-	// Enable and register toolsets if configured
-	// This always happens if toolsets are specified, regardless of whether tools are also specified
-	if len(enabledToolsets) > 0 {
-		err = tsg.EnableToolsets(enabledToolsets, nil)
-		if err != nil {
-			return nil, fmt.Errorf("failed to enable toolsets: %w", err)
+	ghServer.AddReceivingMiddleware(addTraceToContext(cfg.Logger))
+	ghServer.AddReceivingMiddleware(addUserAgentsMiddleware(cfg, clients))
+	// Added before the middlewares setting up the app and account, so that it sees them
+	ghServer.AddReceivingMiddleware(checkToolPermissions(github.NewPermissionChecker(getClient), registry, cfg.Logger))
+	// Tools relying on APIs that the GitHub Enterprise Server of the account acting lacks are
+	// hidden, which needs the account, and the app or sign-in authenticating its requests
+	for _, c := range clients {
+		if c.host.enterpriseServer {
+			ghServer.AddReceivingMiddleware(hideEnterpriseUnavailableTools(newEnterpriseTools(cfg.Logger, apiHost, cfg.EnterpriseVersion), clientsOf))
+			break
 		}
-
-		// Register all mcp functionality with the server
-		tsg.RegisterAll(ghServer)
+	}
+	if signer != nil {
+		ghServer.AddReceivingMiddleware(addCommitSignerToContext(signer))
+	}
+	if cfg.Uploads != nil {
+		ghServer.AddReceivingMiddleware(addUploadsToContext(cfg.Uploads))
+	}
+	if app != nil {
+		ghServer.AddReceivingMiddleware(addAppToContext(app))
+	}
+	if cfg.OAuth != nil {
+		ghServer.AddReceivingMiddleware(addOAuthPromptToContext)
+	}
+	if len(cfg.Accounts) > 0 {
+		selector := accounts.NewSelector(cfg.Host, accountsOf(cfg.Accounts)...)
+		ghServer.AddReceivingMiddleware(addAccountToContext(selector))
 	}
 
-	// Register specific tools if configured
-	if len(cfg.EnabledTools) > 0 {
-		// Clean and validate tool names
-		enabledTools := github.CleanTools(cfg.EnabledTools)
-
-		// Register the specified tools (additive to any toolsets already enabled)
-		err = tsg.RegisterSpecificTools(ghServer, enabledTools, cfg.ReadOnly)
-		if err != nil {
-			return nil, fmt.Errorf("failed to register tools: %w", err)
-		}
+	// Register the enabled toolsets and any specific tools, which are additive to the toolsets
+	if _, err := registry.apply(ReloadableConfig{
+		EnabledToolsets:   enabledToolsets,
+		EnabledTools:      github.CleanTools(cfg.EnabledTools),
+		ReadOnly:          cfg.ReadOnly,
+		LockdownMode:      cfg.LockdownMode,
+		ContentWindowSize: cfg.ContentWindowSize,
+	}); err != nil {
+		return nil, nil, err
 	}
 
+	ghServer.AddReceivingMiddleware(addPriorityToContext(registry))
+	// Added last so that tool call latencies include the other middlewares
+	ghServer.AddReceivingMiddleware(addMetricsToContext(serverMetrics))
+
 	// Register dynamic toolsets if configured (additive to toolsets and tools)
 	if cfg.DynamicToolsets {
-		dynamic := github.InitDynamicToolset(ghServer, tsg, cfg.Translator)
+		dynamic := github.InitDynamicToolset(ghServer, registry.group, cfg.Translator)
 		dynamic.RegisterTools(ghServer)
 	}
 
-	return ghServer, nil
+	return ghServer, registry, nil
+}
+
+// resolveToolsets cleans the configured toolset names, printing any invalid ones,
+// and expands "all" and "default".
+func resolveToolsets(enabledToolsets []string, dynamicToolsets bool) []string {
+	// If dynamic toolsets are enabled, remove "all" from the enabled toolsets
+	if dynamicToolsets {
+		enabledToolsets = github.RemoveToolset(enabledToolsets, github.ToolsetMetadataAll.ID)
+	}
+
+	// Clean up the passed toolsets
+	enabledToolsets, invalidToolsets := github.CleanToolsets(enabledToolsets)
+
+	// If "all" is present, override all other toolsets
+	if github.ContainsToolset(enabledToolsets, github.ToolsetMetadataAll.ID) {
+		enabledToolsets = []string{github.ToolsetMetadataAll.ID}
+	}
+	// If "default" is present, expand to real toolset IDs
+	if github.ContainsToolset(enabledToolsets, github.ToolsetMetadataDefault.ID) {
+		enabledToolsets = github.AddDefaultToolset(enabledToolsets)
+	}
+
+	if len(invalidToolsets) > 0 {
+		fmt.Fprintf(os.Stderr, "Invalid toolsets ignored: %s\n", strings.Join(invalidToolsets, ", "))
+	}
+	return enabledToolsets
 }
 
 type StdioServerConfig struct {
@@ -205,6 +411,21 @@ type StdioServerConfig struct {
 	// GitHub Token to authenticate with the GitHub API
 	Token string
 
+	// EnterpriseURLs override the API URLs derived from Host
+	EnterpriseURLs EnterpriseURLs
+
+	// EnterpriseVersion is the version of the GitHub Enterprise Server at Host, detected when empty
+	EnterpriseVersion string
+
+	// App authenticates the server as a GitHub App instead of with Token
+	App *ghapp.Config
+
+	// OAuth signs the server in with the OAuth device flow instead of with Token
+	OAuth *oauth.Config
+
+	// Accounts are further accounts tool calls can act as
+	Accounts []Account
+
 	// EnabledToolsets is a list of toolsets to enable
 	// See: https://github.com/github/github-mcp-server?tab=readme-ov-file#tool-configuration
 	EnabledToolsets []string
@@ -238,6 +459,44 @@ type StdioServerConfig struct {
 
 	// RepoAccessCacheTTL overrides the default TTL for repository access cache entries.
 	RepoAccessCacheTTL *time.Duration
+
+	// CommitSigning configures signing of commits created by the bulk tools
+	CommitSigning signing.Config
+
+	// PushLimits bounds the pushes made by the file tools
+	PushLimits github.PushLimits
+
+	// PushHostLimits overrides PushLimits per GitHub hostname
+	PushHostLimits map[string]github.PushLimits
+
+	// DefaultBranch is the initial branch of initialize_repository
+	DefaultBranch string
+
+	// LogLevel is debug, info, warn or error. When empty, it is debug when
+	// logging to a file and info otherwise.
+	LogLevel string
+
+	// Reload reads the configuration again on SIGHUP or when ConfigFile changes.
+	// The toolsets, policies, content window size and log level are applied
+	// without a restart. Reloading is disabled when nil.
+	Reload func() (ReloadedConfig, error)
+
+	// ConfigFile is the config file to watch for changes, if any
+	ConfigFile string
+
+	// MetricsAddress is the host:port on which Prometheus metrics are served on /metrics.
+	// Metrics are not served when empty, but get_server_metrics still reports them.
+	MetricsAddress string
+
+	// ResponseCacheMaxBytes bounds the GitHub API response cache, disabled when zero
+	ResponseCacheMaxBytes int64
+
+	// MaxConcurrentRequests bounds the GitHub API requests in flight, unbounded when zero
+	MaxConcurrentRequests int
+
+	// RateLimitStore keeps the state of the rate limiters across restarts: it is restored at
+	// startup and saved while the server runs. The state is not kept when nil.
+	RateLimitStore ratelimit.Store
 }
 
 // RunStdioServer is not concurrent safe.
@@ -248,40 +507,96 @@ func RunStdioServer(cfg StdioServerConfig) error {
 
 	t, dumpTranslations := translations.TranslationHelper()
 
-	var slogHandler slog.Handler
 	var logOutput io.Writer
+	defaultLevel := slog.LevelInfo
 	if cfg.LogFilePath != "" {
 		file, err := os.OpenFile(cfg.LogFilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
 		if err != nil {
 			return fmt.Errorf("failed to open log file: %w", err)
 		}
 		logOutput = file
-		slogHandler = slog.NewTextHandler(logOutput, &slog.HandlerOptions{Level: slog.LevelDebug})
+		defaultLevel = slog.LevelDebug
 	} else {
 		logOutput = os.Stderr
-		slogHandler = slog.NewTextHandler(logOutput, &slog.HandlerOptions{Level: slog.LevelInfo})
 	}
-	logger := slog.New(slogHandler)
+	level, err := parseLogLevel(cfg.LogLevel, defaultLevel)
+	if err != nil {
+		return err
+	}
+	// The level is a LevelVar so that configuration reloads can change it
+	logLevel := new(slog.LevelVar)
+	logLevel.Set(level)
+	logger := slog.New(slog.NewTextHandler(logOutput, &slog.HandlerOptions{Level: logLevel}))
 	logger.Info("starting server", "version", cfg.Version, "host", cfg.Host, "dynamicToolsets", cfg.DynamicToolsets, "readOnly", cfg.ReadOnly, "lockdownEnabled", cfg.LockdownMode)
+	if cfg.OAuth != nil {
+		logger.Info("signing in with the OAuth device flow", "clientID", cfg.OAuth.ClientID)
+	}
+	for _, account := range cfg.Accounts {
+		logger.Info("configured account", "account", account.Name, "host", account.Host)
+	}
+	if cfg.App != nil {
+		logger.Info("authenticating as a GitHub App", "appID", cfg.App.AppID, "installationOwner", cfg.App.DefaultOwner)
+	}
 
-	ghServer, err := NewMCPServer(MCPServerConfig{
-		Version:           cfg.Version,
-		Host:              cfg.Host,
-		Token:             cfg.Token,
-		EnabledToolsets:   cfg.EnabledToolsets,
-		EnabledTools:      cfg.EnabledTools,
-		DynamicToolsets:   cfg.DynamicToolsets,
-		ReadOnly:          cfg.ReadOnly,
-		Translator:        t,
-		ContentWindowSize: cfg.ContentWindowSize,
-		LockdownMode:      cfg.LockdownMode,
-		Logger:            logger,
-		RepoAccessTTL:     cfg.RepoAccessCacheTTL,
+	serverMetrics := metrics.New()
+	if cfg.MetricsAddress != "" {
+		listener, err := net.Listen("tcp", cfg.MetricsAddress)
+		if err != nil {
+			return fmt.Errorf("failed to listen for metrics: %w", err)
+		}
+		metricsServer := serveMetrics(listener, serverMetrics, logger)
+		defer func() { _ = metricsServer.Close() }()
+	}
+
+	ghServer, registry, err := newMCPServer(MCPServerConfig{
+		Version:               cfg.Version,
+		Host:                  cfg.Host,
+		Token:                 cfg.Token,
+		EnterpriseURLs:        cfg.EnterpriseURLs,
+		EnterpriseVersion:     cfg.EnterpriseVersion,
+		App:                   cfg.App,
+		OAuth:                 cfg.OAuth,
+		Accounts:              cfg.Accounts,
+		EnabledToolsets:       cfg.EnabledToolsets,
+		EnabledTools:          cfg.EnabledTools,
+		DynamicToolsets:       cfg.DynamicToolsets,
+		ReadOnly:              cfg.ReadOnly,
+		Translator:            t,
+		ContentWindowSize:     cfg.ContentWindowSize,
+		LockdownMode:          cfg.LockdownMode,
+		Logger:                logger,
+		RepoAccessTTL:         cfg.RepoAccessCacheTTL,
+		CommitSigning:         cfg.CommitSigning,
+		PushLimits:            cfg.PushLimits,
+		PushHostLimits:        cfg.PushHostLimits,
+		DefaultBranch:         cfg.DefaultBranch,
+		Metrics:               serverMetrics,
+		ResponseCacheMaxBytes: cfg.ResponseCacheMaxBytes,
+		MaxConcurrentRequests: cfg.MaxConcurrentRequests,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to create MCP server: %w", err)
 	}
 
+	if cfg.RateLimitStore != nil {
+		stop := persistRateLimiters(cfg.RateLimitStore, logger)
+		defer stop()
+	}
+
+	if cfg.Reload != nil {
+		r := &reloader{
+			load:            cfg.Reload,
+			registry:        registry,
+			logLevel:        logLevel,
+			defaultLevel:    defaultLevel,
+			dynamicToolsets: cfg.DynamicToolsets,
+			logger:          logger,
+		}
+		if err := watchReload(ctx, cfg.ConfigFile, logger, r.reload); err != nil {
+			return fmt.Errorf("failed to watch configuration: %w", err)
+		}
+	}
+
 	if cfg.ExportTranslations {
 		// Once server is initialized, all translations are loaded
 		dumpTranslations()
@@ -323,11 +638,61 @@ func RunStdioServer(cfg StdioServerConfig) error {
 	return nil
 }
 
+// rateLimitSaveInterval is how often persistRateLimiters saves the rate limiters
+const rateLimitSaveInterval = 30 * time.Second
+
+// persistRateLimiters restores the rate limiters of the server from store, then saves them
+// every rateLimitSaveInterval until the returned function is called, which saves them a last
+// time. A state that cannot be restored is logged, and the server starts with full limiters.
+func persistRateLimiters(store ratelimit.Store, logger *slog.Logger) func() {
+	states, err := store.Load()
+	if err != nil {
+		logger.Warn("failed to restore rate limiters", "error", err)
+	} else {
+		github.RestoreRateLimiterStates(states)
+		logger.Info("restored rate limiters", "limiters", len(states))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ratelimit.Persist(ctx, store, rateLimitSaveInterval, github.RateLimiterStates, func(err error) {
+			logger.Warn("failed to save rate limiters", "error", err)
+		})
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// serveMetrics serves m on /metrics of listener in the Prometheus text format, until the
+// returned server is closed.
+func serveMetrics(listener net.Listener, m *metrics.Metrics, logger *slog.Logger) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m.Handler())
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	logger.Info("serving metrics", "address", listener.Addr().String())
+	go func() {
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			logger.Error("metrics server failed", "error", err)
+		}
+	}()
+	return server
+}
+
 type apiHost struct {
 	baseRESTURL *url.URL
 	graphqlURL  *url.URL
 	uploadURL   *url.URL
 	rawURL      *url.URL
+	// webURL serves the web pages, among which the OAuth endpoints
+	webURL *url.URL
+
+	// enterpriseServer is set for GitHub Enterprise Server hosts, whose version decides the
+	// tools available
+	enterpriseServer bool
 }
 
 func newDotcomHost() (apiHost, error) {
@@ -351,11 +716,17 @@ func newDotcomHost() (apiHost, error) {
 		return apiHost{}, fmt.Errorf("failed to parse dotcom Raw URL: %w", err)
 	}
 
+	webURL, err := url.Parse("https://github.com/")
+	if err != nil {
+		return apiHost{}, fmt.Errorf("failed to parse dotcom Web URL: %w", err)
+	}
+
 	return apiHost{
 		baseRESTURL: baseRestURL,
 		graphqlURL:  gqlURL,
 		uploadURL:   uploadURL,
 		rawURL:      rawURL,
+		webURL:      webURL,
 	}, nil
 }
 
@@ -390,11 +761,17 @@ func newGHECHost(hostname string) (apiHost, error) {
 		return apiHost{}, fmt.Errorf("failed to parse GHEC Raw URL: %w", err)
 	}
 
+	webURL, err := url.Parse(fmt.Sprintf("https://%s/", u.Hostname()))
+	if err != nil {
+		return apiHost{}, fmt.Errorf("failed to parse GHEC Web URL: %w", err)
+	}
+
 	return apiHost{
 		baseRESTURL: restURL,
 		graphqlURL:  gqlURL,
 		uploadURL:   uploadURL,
 		rawURL:      rawURL,
+		webURL:      webURL,
 	}, nil
 }
 
@@ -404,27 +781,27 @@ func newGHESHost(hostname string) (apiHost, error) {
 		return apiHost{}, fmt.Errorf("failed to parse GHES URL: %w", err)
 	}
 
-	restURL, err := url.Parse(fmt.Sprintf("%s://%s/api/v3/", u.Scheme, u.Hostname()))
+	restURL, err := url.Parse(fmt.Sprintf("%s://%s/api/v3/", u.Scheme, u.Host))
 	if err != nil {
 		return apiHost{}, fmt.Errorf("failed to parse GHES REST URL: %w", err)
 	}
 
-	gqlURL, err := url.Parse(fmt.Sprintf("%s://%s/api/graphql", u.Scheme, u.Hostname()))
+	gqlURL, err := url.Parse(fmt.Sprintf("%s://%s/api/graphql", u.Scheme, u.Host))
 	if err != nil {
 		return apiHost{}, fmt.Errorf("failed to parse GHES GraphQL URL: %w", err)
 	}
 
 	// Check if subdomain isolation is enabled
 	// See https://docs.github.com/en/enterprise-server@3.17/admin/configuring-settings/hardening-security-for-your-enterprise/enabling-subdomain-isolation#about-subdomain-isolation
-	hasSubdomainIsolation := checkSubdomainIsolation(u.Scheme, u.Hostname())
+	hasSubdomainIsolation := checkSubdomainIsolation(u.Scheme, u.Host)
 
 	var uploadURL *url.URL
 	if hasSubdomainIsolation {
 		// With subdomain isolation: https://uploads.hostname/
-		uploadURL, err = url.Parse(fmt.Sprintf("%s://uploads.%s/", u.Scheme, u.Hostname()))
+		uploadURL, err = url.Parse(fmt.Sprintf("%s://uploads.%s/", u.Scheme, u.Host))
 	} else {
 		// Without subdomain isolation: https://hostname/api/uploads/
-		uploadURL, err = url.Parse(fmt.Sprintf("%s://%s/api/uploads/", u.Scheme, u.Hostname()))
+		uploadURL, err = url.Parse(fmt.Sprintf("%s://%s/api/uploads/", u.Scheme, u.Host))
 	}
 	if err != nil {
 		return apiHost{}, fmt.Errorf("failed to parse GHES Upload URL: %w", err)
@@ -433,20 +810,28 @@ func newGHESHost(hostname string) (apiHost, error) {
 	var rawURL *url.URL
 	if hasSubdomainIsolation {
 		// With subdomain isolation: https://raw.hostname/
-		rawURL, err = url.Parse(fmt.Sprintf("%s://raw.%s/", u.Scheme, u.Hostname()))
+		rawURL, err = url.Parse(fmt.Sprintf("%s://raw.%s/", u.Scheme, u.Host))
 	} else {
 		// Without subdomain isolation: https://hostname/raw/
-		rawURL, err = url.Parse(fmt.Sprintf("%s://%s/raw/", u.Scheme, u.Hostname()))
+		rawURL, err = url.Parse(fmt.Sprintf("%s://%s/raw/", u.Scheme, u.Host))
 	}
 	if err != nil {
 		return apiHost{}, fmt.Errorf("failed to parse GHES Raw URL: %w", err)
 	}
 
+	webURL, err := url.Parse(fmt.Sprintf("%s://%s/", u.Scheme, u.Host))
+	if err != nil {
+		return apiHost{}, fmt.Errorf("failed to parse GHES Web URL: %w", err)
+	}
+
 	return apiHost{
 		baseRESTURL: restURL,
 		graphqlURL:  gqlURL,
 		uploadURL:   uploadURL,
 		rawURL:      rawURL,
+		webURL:      webURL,
+
+		enterpriseServer: true,
 	}, nil
 }
 
@@ -473,7 +858,7 @@ func checkSubdomainIsolation(scheme, hostname string) bool {
 	return resp.StatusCode == http.StatusOK
 }
 
-// Note that this does not handle ports yet, so development environments are out.
+// GitHub Enterprise Server hosts keep their port, unlike GitHub.com and ghe.com hosts.
 func parseAPIHost(s string) (apiHost, error) {
 	if s == "" {
 		return newDotcomHost()
@@ -530,7 +915,141 @@ func addGitHubAPIErrorToContext(next mcp.MethodHandler) mcp.MethodHandler {
 	}
 }
 
-func addUserAgentsMiddleware(cfg MCPServerConfig, restClient *gogithub.Client, gqlHTTPClient *http.Client) func(next mcp.MethodHandler) mcp.MethodHandler {
+// addTraceToContext gives each tool call a trace, sent to GitHub with every request the call
+// makes. The client can choose the trace ID with the trace_id _meta field. The result's _meta
+// reports the trace ID and the GitHub request IDs of failed requests, which are also logged.
+func addTraceToContext(logger *slog.Logger) func(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			callReq, ok := req.(*mcp.CallToolRequest)
+			if method != "tools/call" || !ok || callReq.Params == nil {
+				return next(ctx, method, req)
+			}
+
+			traceID, _ := callReq.Params.Meta["trace_id"].(string)
+			tr := trace.New(traceID)
+			result, err := next(trace.ContextWithTrace(ctx, tr), method, req)
+
+			failed := tr.FailedRequests()
+			if len(failed) > 0 {
+				logger.Info("GitHub API requests failed", "tool", callReq.Params.Name, "trace_id", tr.ID, "requests", failed)
+			}
+			if toolResult, ok := result.(*mcp.CallToolResult); ok && toolResult != nil {
+				if toolResult.Meta == nil {
+					toolResult.Meta = mcp.Meta{}
+				}
+				toolResult.Meta["trace_id"] = tr.ID
+				if len(failed) > 0 {
+					toolResult.Meta["github_requests"] = failed
+				}
+			}
+			return result, err
+		}
+	}
+}
+
+// addPriorityToContext lets the GitHub requests of read-only tool calls, which someone is
+// waiting on, wait on the rate limiters ahead of other calls and of background bulk work.
+func addPriorityToContext(registry *toolRegistry) func(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			callReq, ok := req.(*mcp.CallToolRequest)
+			if method != "tools/call" || !ok || callReq.Params == nil || !registry.isReadOnly(callReq.Params.Name) {
+				return next(ctx, method, req)
+			}
+			return next(ratelimit.WithPriority(ctx, ratelimit.PriorityHigh), method, req)
+		}
+	}
+}
+
+// addMetricsToContext records every tool call in m, with its latency and, if it fails, its
+// error code, and gives tools access to m.
+func addMetricsToContext(m *metrics.Metrics) func(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			ctx = metrics.ContextWithMetrics(ctx, m)
+			callReq, ok := req.(*mcp.CallToolRequest)
+			if method != "tools/call" || !ok || callReq.Params == nil {
+				return next(ctx, method, req)
+			}
+
+			// The GitHub errors of the call are collected in the context set up here, which
+			// addGitHubAPIErrorToContext resets rather than replaces
+			ctx = errors.ContextWithGitHubErrors(ctx)
+			start := time.Now()
+			result, err := next(ctx, method, req)
+			m.RecordToolCall(callReq.Params.Name, time.Since(start), toolErrorCode(ctx, result, err))
+			return result, err
+		}
+	}
+}
+
+// toolErrorCode classifies a tool call for metrics: empty if it succeeded, or else the code of
+// a validation error, the HTTP status of the last failed GitHub API request, graphql_error
+// for GraphQL errors, internal_error when the handler failed, or tool_error.
+func toolErrorCode(ctx context.Context, result mcp.Result, err error) string {
+	if err != nil {
+		return "internal_error"
+	}
+	toolResult, ok := result.(*mcp.CallToolResult)
+	if !ok || toolResult == nil || !toolResult.IsError {
+		return ""
+	}
+	if structured, ok := toolResult.StructuredContent.(map[string]any); ok {
+		if code, ok := structured["code"].(string); ok && code != "" {
+			return code
+		}
+	}
+	if apiErrors, _ := errors.GetGitHubAPIErrors(ctx); len(apiErrors) > 0 {
+		if resp := apiErrors[len(apiErrors)-1].Response; resp != nil && resp.Response != nil {
+			return fmt.Sprintf("http_%d", resp.StatusCode)
+		}
+		return "github_api_error"
+	}
+	if graphQLErrors, _ := errors.GetGitHubGraphQLErrors(ctx); len(graphQLErrors) > 0 {
+		return "graphql_error"
+	}
+	return "tool_error"
+}
+
+func addCommitSignerToContext(signer *signing.Signer) func(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (result mcp.Result, err error) {
+			return next(signing.ContextWithSigner(ctx, signer), method, req)
+		}
+	}
+}
+
+func addUploadsToContext(store *uploads.Store) func(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (result mcp.Result, err error) {
+			return next(uploads.ContextWithStore(ctx, store), method, req)
+		}
+	}
+}
+
+func addAppToContext(app *ghapp.App) func(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (result mcp.Result, err error) {
+			return next(ghapp.ContextWithApp(ctx, app), method, req)
+		}
+	}
+}
+
+// addOAuthPromptToContext prompts the user to sign in through the client making the request,
+// if it supports elicitation.
+func addOAuthPromptToContext(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (result mcp.Result, err error) {
+		if session, ok := req.GetSession().(*mcp.ServerSession); ok {
+			if prompt := oauth.ElicitationPrompt(session, oauth.WriterPrompt(os.Stderr)); prompt != nil {
+				ctx = oauth.ContextWithPrompt(ctx, prompt)
+			}
+		}
+		return next(ctx, method, req)
+	}
+}
+
+func addUserAgentsMiddleware(cfg MCPServerConfig, clients map[string]*githubClients) func(next mcp.MethodHandler) mcp.MethodHandler {
 	return func(next mcp.MethodHandler) mcp.MethodHandler {
 		return func(ctx context.Context, method string, request mcp.Request) (result mcp.Result, err error) {
 			if method != "initialize" {
@@ -550,11 +1069,13 @@ func addUserAgentsMiddleware(cfg MCPServerConfig, restClient *gogithub.Client, g
 				message.Params.ClientInfo.Version,
 			)
 
-			restClient.UserAgent = userAgent
+			for _, c := range clients {
+				c.rest.UserAgent = userAgent
 
-			gqlHTTPClient.Transport = &userAgentTransport{
-				transport: gqlHTTPClient.Transport,
-				agent:     userAgent,
+				c.gqlHTTP.Transport = &userAgentTransport{
+					transport: c.gqlHTTP.Transport,
+					agent:     userAgent,
+				}
 			}
 
 			return next(ctx, method, request)
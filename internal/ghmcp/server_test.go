@@ -0,0 +1,204 @@
+package ghmcp
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/github/github-mcp-server/pkg/errors"
+	"github.com/github/github-mcp-server/pkg/ghapp"
+	"github.com/github/github-mcp-server/pkg/github"
+	"github.com/github/github-mcp-server/pkg/metrics"
+	"github.com/github/github-mcp-server/pkg/ratelimit"
+	"github.com/github/github-mcp-server/pkg/trace"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/github/github-mcp-server/pkg/utils"
+	gogithub "github.com/google/go-github/v79/github"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_addTraceToContext(t *testing.T) {
+	var receivedTraceID string
+	github := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedTraceID = r.Header.Get(trace.HeaderTraceID)
+		w.Header().Set(trace.HeaderGitHubRequestID, "C0DE:1")
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer github.Close()
+	httpClient := &http.Client{Transport: trace.NewTransport(nil)}
+
+	// The handler makes one failing request, as a tool calling the GitHub API would
+	handler := func(ctx context.Context, _ string, _ mcp.Request) (mcp.Result, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, github.URL+"/repos/owner/repo", nil)
+		require.NoError(t, err)
+		resp, err := httpClient.Do(req)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		return &mcp.CallToolResult{IsError: true}, nil
+	}
+	middleware := addTraceToContext(slog.New(slog.NewTextHandler(io.Discard, nil)))(handler)
+
+	req := &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{
+		Name: "get_repository",
+		Meta: mcp.Meta{"trace_id": "client-trace"},
+	}}
+	result, err := middleware(context.Background(), "tools/call", req)
+	require.NoError(t, err)
+
+	assert.Equal(t, "client-trace", receivedTraceID)
+	toolResult, ok := result.(*mcp.CallToolResult)
+	require.True(t, ok)
+	assert.Equal(t, "client-trace", toolResult.Meta["trace_id"])
+
+	// The metadata reaches clients as JSON
+	encoded, err := json.Marshal(toolResult.Meta["github_requests"])
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"method":"GET","path":"/repos/owner/repo","status":502,"github_request_id":"C0DE:1"}]`, string(encoded))
+}
+
+func Test_addMetricsToContext(t *testing.T) {
+	m := metrics.New()
+	handler := func(ctx context.Context, _ string, req mcp.Request) (mcp.Result, error) {
+		_, ok := metrics.FromContext(ctx)
+		assert.True(t, ok)
+		if req.(*mcp.CallToolRequest).Params.Name == "get_issue" {
+			_, _ = errors.NewGitHubAPIErrorToCtx(ctx, "failed to get issue", &gogithub.Response{Response: &http.Response{StatusCode: http.StatusNotFound}}, fmt.Errorf("not found"))
+			return &mcp.CallToolResult{IsError: true}, nil
+		}
+		return &mcp.CallToolResult{}, nil
+	}
+	// addGitHubAPIErrorToContext runs inside, as on the server
+	middleware := addMetricsToContext(m)(addGitHubAPIErrorToContext(handler))
+
+	for _, name := range []string{"get_me", "get_issue"} {
+		_, err := middleware(context.Background(), "tools/call", &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{Name: name}})
+		require.NoError(t, err)
+	}
+
+	tools := m.Snapshot().Tools
+	require.Len(t, tools, 2)
+	assert.Equal(t, "get_issue", tools[0].Name)
+	assert.Equal(t, map[string]int64{"http_404": 1}, tools[0].ErrorCodes)
+	assert.Equal(t, "get_me", tools[1].Name)
+	assert.Zero(t, tools[1].Errors)
+}
+
+func Test_toolErrorCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		ctx      func() context.Context
+		result   mcp.Result
+		err      error
+		expected string
+	}{
+		{name: "success", result: &mcp.CallToolResult{}, expected: ""},
+		{name: "handler error", err: fmt.Errorf("boom"), expected: "internal_error"},
+		{name: "validation error", result: utils.NewToolResultValidationError("MISSING_FILE_PATH", "file at index 0 must have a non-empty path", "", nil), expected: "MISSING_FILE_PATH"},
+		{
+			name: "GraphQL error",
+			ctx: func() context.Context {
+				ctx := errors.ContextWithGitHubErrors(context.Background())
+				_ = errors.NewGitHubGraphQLErrorResponse(ctx, "failed", fmt.Errorf("boom"))
+				return ctx
+			},
+			result:   &mcp.CallToolResult{IsError: true},
+			expected: "graphql_error",
+		},
+		{name: "other error", result: &mcp.CallToolResult{IsError: true}, expected: "tool_error"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tc.ctx != nil {
+				ctx = tc.ctx()
+			}
+			assert.Equal(t, tc.expected, toolErrorCode(ctx, tc.result, tc.err))
+		})
+	}
+}
+
+func Test_serveMetrics(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	m := metrics.New()
+	m.RecordToolCall("get_me", time.Millisecond, "")
+	server := serveMetrics(listener, m, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	defer func() { _ = server.Close() }()
+
+	resp, err := http.Get("http://" + listener.Addr().String() + "/metrics")
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, string(body), `github_mcp_tool_calls_total{tool="get_me"} 1`)
+}
+
+func Test_persistRateLimiters(t *testing.T) {
+	store := ratelimit.FileStore{Path: filepath.Join(t.TempDir(), "rate-limits.json")}
+	// A state that cannot be read is replaced rather than stopping the server
+	require.NoError(t, os.WriteFile(store.Path, []byte("not json"), 0600))
+	var logs strings.Builder
+	stop := persistRateLimiters(store, slog.New(slog.NewTextHandler(&logs, nil)))
+	github.RequestLimiters().Get("token:persisted")
+	stop()
+
+	assert.Contains(t, logs.String(), "failed to restore rate limiters")
+	states, err := store.Load()
+	require.NoError(t, err)
+	assert.Contains(t, states, "requests/token:persisted")
+}
+
+func Test_newMCPServer_App(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	privateKey := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	_, _, err = newMCPServer(MCPServerConfig{
+		Translator: translations.NullTranslationHelper,
+		Logger:     logger,
+		App:        &ghapp.Config{AppID: 42, PrivateKey: privateKey, DefaultOwner: "acme"},
+	})
+	assert.NoError(t, err)
+
+	_, _, err = newMCPServer(MCPServerConfig{
+		Translator: translations.NullTranslationHelper,
+		Logger:     logger,
+		App:        &ghapp.Config{AppID: 42, PrivateKey: []byte("not a key")},
+	})
+	assert.ErrorContains(t, err, "failed to configure GitHub App authentication")
+}
+
+func Test_parseAPIHost_WebURL(t *testing.T) {
+	tests := []struct {
+		host     string
+		expected string
+	}{
+		{"", "https://github.com/"},
+		{"https://tenant.ghe.com", "https://tenant.ghe.com/"},
+		// GitHub Enterprise Server hosts keep their port
+		{"http://127.0.0.1:8080", "http://127.0.0.1:8080/"},
+	}
+	for _, tc := range tests {
+		host, err := parseAPIHost(tc.host)
+		require.NoError(t, err, tc.host)
+		assert.Equal(t, tc.expected, host.webURL.String(), tc.host)
+	}
+}
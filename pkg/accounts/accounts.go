@@ -0,0 +1,134 @@
+// Package accounts lets the server act as one of several GitHub accounts, each with credentials
+// and a host of its own, chosen by each tool call or, for the calls that choose none, by the
+// session making them.
+package accounts
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Default is the name of the account of the main credentials of the server.
+const Default = "default"
+
+// Account is an account tool calls can act as.
+type Account struct {
+	Name string `json:"name"`
+	// Host is the GitHub host of the account, such as https://github.example.com, or empty
+	// for GitHub.com.
+	Host string `json:"host,omitempty"`
+}
+
+// Selector holds the accounts of the server and the account each session acts as by default.
+type Selector struct {
+	accounts []Account
+
+	mu       sync.Mutex
+	sessions map[*mcp.ServerSession]string
+}
+
+// NewSelector returns a Selector of the Default account, on defaultHost, and accounts.
+func NewSelector(defaultHost string, accounts ...Account) *Selector {
+	return &Selector{
+		accounts: append([]Account{{Name: Default, Host: defaultHost}}, accounts...),
+		sessions: make(map[*mcp.ServerSession]string),
+	}
+}
+
+// Accounts returns the accounts, the Default account first.
+func (s *Selector) Accounts() []Account {
+	return slices.Clone(s.accounts)
+}
+
+// Check returns an error naming the accounts if there is none called name.
+func (s *Selector) Check(name string) error {
+	names := make([]string, 0, len(s.accounts))
+	for _, account := range s.accounts {
+		if account.Name == name {
+			return nil
+		}
+		names = append(names, account.Name)
+	}
+	return fmt.Errorf("unknown account %q, expected one of: %s", name, strings.Join(names, ", "))
+}
+
+// Select makes name the account of the tool calls of session that name none.
+func (s *Selector) Select(session *mcp.ServerSession, name string) error {
+	if err := s.Check(name); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.sessions[session]; !ok && session != nil {
+		// Forget the session once it ends
+		go func() {
+			_ = session.Wait()
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			delete(s.sessions, session)
+		}()
+	}
+	s.sessions[session] = name
+	return nil
+}
+
+// Selected returns the account of the tool calls of session that name none, Default unless the
+// session selected another.
+func (s *Selector) Selected(session *mcp.ServerSession) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if name, ok := s.sessions[session]; ok {
+		return name
+	}
+	return Default
+}
+
+type selectorKey struct{}
+
+// ContextWithSelector returns a context carrying the selector of the server.
+func ContextWithSelector(ctx context.Context, s *Selector) context.Context {
+	return context.WithValue(ctx, selectorKey{}, s)
+}
+
+// FromContext returns the selector carried by ctx, if the server has several accounts.
+func FromContext(ctx context.Context) (*Selector, bool) {
+	s, ok := ctx.Value(selectorKey{}).(*Selector)
+	return s, ok && s != nil
+}
+
+type accountKey struct{}
+
+// ContextWithAccount returns a context whose GitHub requests act as the account called name.
+func ContextWithAccount(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, accountKey{}, name)
+}
+
+// AccountFromContext returns the name of the account the requests of ctx act as, Default
+// unless set with ContextWithAccount.
+func AccountFromContext(ctx context.Context) string {
+	if name, ok := ctx.Value(accountKey{}).(string); ok && name != "" {
+		return name
+	}
+	return Default
+}
+
+// Key identifies the account the requests of ctx act as by its name and host, for keying state
+// that tool calls keep for later calls, so that no account reaches another's.
+func Key(ctx context.Context) string {
+	name := AccountFromContext(ctx)
+	host := ""
+	if s, ok := FromContext(ctx); ok {
+		for _, account := range s.accounts {
+			if account.Name == name {
+				host = account.Host
+				break
+			}
+		}
+	}
+	return name + "@" + host
+}
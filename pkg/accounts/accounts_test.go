@@ -0,0 +1,71 @@
+package accounts
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func connect(t *testing.T) *mcp.ServerSession {
+	t.Helper()
+	ctx := context.Background()
+	server := mcp.NewServer(&mcp.Implementation{Name: "server"}, nil)
+	client := mcp.NewClient(&mcp.Implementation{Name: "client"}, nil)
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = serverSession.Close() })
+	session, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = session.Close() })
+	return serverSession
+}
+
+func TestSelector(t *testing.T) {
+	s := NewSelector("", Account{Name: "ghes", Host: "https://github.example.com"})
+	assert.Equal(t, []Account{{Name: Default}, {Name: "ghes", Host: "https://github.example.com"}}, s.Accounts())
+	assert.NoError(t, s.Check("ghes"))
+	assert.EqualError(t, s.Check("work"), `unknown account "work", expected one of: default, ghes`)
+
+	first, second := connect(t), connect(t)
+	assert.Equal(t, Default, s.Selected(first))
+	require.NoError(t, s.Select(first, "ghes"))
+	assert.Equal(t, "ghes", s.Selected(first))
+	assert.Equal(t, Default, s.Selected(second))
+	assert.Error(t, s.Select(second, "work"))
+	assert.Equal(t, Default, s.Selected(second))
+
+	// Sessions are forgotten once they end
+	require.NoError(t, first.Close())
+	assert.Eventually(t, func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return len(s.sessions) == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestContext(t *testing.T) {
+	ctx := context.Background()
+	_, ok := FromContext(ctx)
+	assert.False(t, ok)
+	assert.Equal(t, Default, AccountFromContext(ctx))
+
+	s := NewSelector("")
+	got, ok := FromContext(ContextWithSelector(ctx, s))
+	assert.True(t, ok)
+	assert.Same(t, s, got)
+	assert.Equal(t, "work", AccountFromContext(ContextWithAccount(ctx, "work")))
+}
+
+func TestKey(t *testing.T) {
+	ctx := context.Background()
+	assert.Equal(t, "default@", Key(ctx))
+
+	ctx = ContextWithSelector(ctx, NewSelector("https://github.example.com", Account{Name: "work"}))
+	assert.Equal(t, "default@https://github.example.com", Key(ctx))
+	assert.Equal(t, "work@", Key(ContextWithAccount(ctx, "work")))
+}
@@ -0,0 +1,581 @@
+// Package config loads the server configuration from a YAML or TOML file,
+// environment variables and command line flags, in increasing order of
+// precedence, and validates the result.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/github/github-mcp-server/pkg/ghapp"
+	"github.com/github/github-mcp-server/pkg/httpcache"
+	"github.com/github/github-mcp-server/pkg/keyring"
+	"github.com/github/github-mcp-server/pkg/oauth"
+	"github.com/github/github-mcp-server/pkg/signing"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+const (
+	// DefaultContentWindowSize is the default size of the content window used for job logs.
+	DefaultContentWindowSize = 5000
+	// DefaultMaxConcurrentRequests is the default number of GitHub API requests in flight.
+	DefaultMaxConcurrentRequests = 20
+	// DefaultRepoAccessCacheTTL is the default TTL of repository access cache entries.
+	DefaultRepoAccessCacheTTL = 5 * time.Minute
+	// DefaultResponseCacheMaxBytes is the default size of the GitHub API response cache.
+	DefaultResponseCacheMaxBytes = httpcache.DefaultMaxBytes
+
+	// EnvConfigFile names the config file when the --config flag is not used.
+	EnvConfigFile = "GITHUB_CONFIG"
+)
+
+// Config is the complete server configuration.
+type Config struct {
+	// Host is the GitHub hostname, for GitHub Enterprise Server or ghe.com.
+	Host string `mapstructure:"host" yaml:"host"`
+	// Enterprise overrides the API URLs derived from Host and the version of GitHub Enterprise
+	// Server detected.
+	Enterprise EnterpriseConfig `mapstructure:"enterprise" yaml:"enterprise"`
+	// Token is the personal access token used to call the GitHub API.
+	Token string `mapstructure:"token" yaml:"token"`
+	// App authenticates the server as a GitHub App instead of with Token.
+	App AppConfig `mapstructure:"app" yaml:"app"`
+	// OAuth signs the server in with the OAuth device flow instead of with Token.
+	OAuth OAuthConfig `mapstructure:"oauth" yaml:"oauth"`
+	// Accounts are further accounts, each with its own token and host, that tool calls can
+	// act as instead of the one above.
+	Accounts []AccountConfig `mapstructure:"accounts" yaml:"accounts"`
+
+	Toolsets      ToolsetsConfig      `mapstructure:"toolsets" yaml:"toolsets"`
+	Policies      PoliciesConfig      `mapstructure:"policies" yaml:"policies"`
+	Limits        LimitsConfig        `mapstructure:"limits" yaml:"limits"`
+	Repositories  RepositoriesConfig  `mapstructure:"repositories" yaml:"repositories"`
+	Cache         CacheConfig         `mapstructure:"cache" yaml:"cache"`
+	RateLimits    RateLimitsConfig    `mapstructure:"rate_limits" yaml:"rate_limits"`
+	Logging       LoggingConfig       `mapstructure:"logging" yaml:"logging"`
+	Metrics       MetricsConfig       `mapstructure:"metrics" yaml:"metrics"`
+	CommitSigning CommitSigningConfig `mapstructure:"commit_signing" yaml:"commit_signing"`
+	Translations  TranslationsConfig  `mapstructure:"translations" yaml:"translations"`
+
+	// sources records where each setting came from, keyed like settings
+	sources map[string]Source
+}
+
+// EnterpriseConfig describes a GitHub Enterprise Server whose APIs are not where its host
+// implies, or whose version the server cannot detect.
+type EnterpriseConfig struct {
+	APIURL     string `mapstructure:"api_url" yaml:"api_url"`
+	UploadURL  string `mapstructure:"upload_url" yaml:"upload_url"`
+	GraphQLURL string `mapstructure:"graphql_url" yaml:"graphql_url"`
+	// Version is the version of GitHub Enterprise Server, such as 3.14, which decides the
+	// tools available. It is detected when empty.
+	Version string `mapstructure:"version" yaml:"version"`
+}
+
+// enterpriseVersion matches GitHub Enterprise Server versions
+var enterpriseVersion = regexp.MustCompile(`^[0-9]+(\.[0-9]+)*$`)
+
+// AppConfig authenticates the server as a GitHub App, with installation tokens minted and
+// renewed by the server.
+type AppConfig struct {
+	// ID is the app ID. The server authenticates as the app when it is set.
+	ID int64 `mapstructure:"id" yaml:"id"`
+	// PrivateKeyFile is the path to the PEM private key of the app. PrivateKey holds the key
+	// itself instead, for environments that pass secrets as variables.
+	PrivateKeyFile string `mapstructure:"private_key_file" yaml:"private_key_file"`
+	PrivateKey     string `mapstructure:"private_key" yaml:"private_key"`
+	// InstallationOwner is the account whose installation authenticates the requests that name
+	// no owner, such as searches and GraphQL queries. When empty, the app must have a single
+	// installation.
+	InstallationOwner string `mapstructure:"installation_owner" yaml:"installation_owner"`
+}
+
+// Enabled reports whether the server authenticates as a GitHub App.
+func (c AppConfig) Enabled() bool {
+	return c.ID != 0
+}
+
+// GitHubApp returns the configuration in the form used by the ghapp package, reading the
+// private key file, or nil if the server does not authenticate as an app.
+func (c AppConfig) GitHubApp() (*ghapp.Config, error) {
+	if !c.Enabled() {
+		return nil, nil
+	}
+	key := []byte(c.PrivateKey)
+	if c.PrivateKeyFile != "" {
+		var err error
+		key, err = os.ReadFile(c.PrivateKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read app.private_key_file: %w", err)
+		}
+	}
+	return &ghapp.Config{AppID: c.ID, PrivateKey: key, DefaultOwner: c.InstallationOwner}, nil
+}
+
+// OAuthConfig signs the server in with the OAuth device flow of an OAuth app: the user enters a
+// code on GitHub, and the token obtained is stored in the keyring of the operating system.
+type OAuthConfig struct {
+	// ClientID is the client ID of the OAuth app. The server signs in when it is set.
+	ClientID string `mapstructure:"client_id" yaml:"client_id"`
+	// Scopes are the scopes requested, those the tools need when empty.
+	Scopes []string `mapstructure:"scopes" yaml:"scopes"`
+}
+
+// Enabled reports whether the server signs in with the device flow.
+func (c OAuthConfig) Enabled() bool {
+	return c.ClientID != ""
+}
+
+// DeviceFlow returns the configuration in the form used by the oauth package, storing tokens in
+// the keyring of the operating system, or nil if the server does not sign in.
+func (c OAuthConfig) DeviceFlow() *oauth.Config {
+	if !c.Enabled() {
+		return nil
+	}
+	return &oauth.Config{ClientID: c.ClientID, Scopes: c.Scopes, Keyring: keyring.System()}
+}
+
+// AccountConfig names a GitHub account tool calls can act as. Its token is read from an
+// environment variable, so that the config file holds no token.
+type AccountConfig struct {
+	// Name identifies the account in the account argument of tool calls
+	Name string `mapstructure:"name" yaml:"name"`
+	// Host is the GitHub host of the account, such as https://github.example.com, GitHub.com
+	// when empty
+	Host string `mapstructure:"host" yaml:"host"`
+	// TokenEnv names the environment variable holding the token of the account
+	TokenEnv string `mapstructure:"token_env" yaml:"token_env"`
+}
+
+// accountName matches the names of accounts
+var accountName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// ToolsetsConfig selects the tools exposed by the server.
+type ToolsetsConfig struct {
+	Enabled []string `mapstructure:"enabled" yaml:"enabled"`
+	Tools   []string `mapstructure:"tools" yaml:"tools"`
+	Dynamic bool     `mapstructure:"dynamic" yaml:"dynamic"`
+}
+
+// PoliciesConfig restricts what the server is allowed to do.
+type PoliciesConfig struct {
+	ReadOnly     bool `mapstructure:"read_only" yaml:"read_only"`
+	LockdownMode bool `mapstructure:"lockdown_mode" yaml:"lockdown_mode"`
+}
+
+// LimitsConfig bounds the size of tool output, the GitHub API requests in flight and pushes.
+type LimitsConfig struct {
+	ContentWindowSize int `mapstructure:"content_window_size" yaml:"content_window_size"`
+	// MaxConcurrentRequests bounds the GitHub API requests in flight. Zero leaves them unbounded.
+	MaxConcurrentRequests int              `mapstructure:"max_concurrent_requests" yaml:"max_concurrent_requests"`
+	Push                  PushLimitsConfig `mapstructure:"push" yaml:"push"`
+	// PushHosts overrides Push for individual GitHub hosts, such as GitHub
+	// Enterprise Server instances with their own push limits.
+	PushHosts []HostPushLimitsConfig `mapstructure:"push_hosts" yaml:"push_hosts"`
+}
+
+// PushLimitsConfig bounds the pushes made by the file tools. Zero keeps the
+// built-in default, and the server clamps values to what GitHub accepts.
+type PushLimitsConfig struct {
+	MaxFiles          int   `mapstructure:"max_files" yaml:"max_files"`
+	MaxFileSizeBytes  int64 `mapstructure:"max_file_size_bytes" yaml:"max_file_size_bytes"`
+	MaxTotalSizeBytes int64 `mapstructure:"max_total_size_bytes" yaml:"max_total_size_bytes"`
+	DefaultChunkSize  int   `mapstructure:"default_chunk_size" yaml:"default_chunk_size"`
+	MaxChunkSize      int   `mapstructure:"max_chunk_size" yaml:"max_chunk_size"`
+}
+
+// HostPushLimitsConfig sets the push limits of one host. Zero keeps the value of limits.push.
+type HostPushLimitsConfig struct {
+	// Host is the hostname, such as github.example.com or github.com.
+	Host             string `mapstructure:"host" yaml:"host"`
+	PushLimitsConfig `mapstructure:",squash" yaml:",inline"`
+}
+
+// RepositoriesConfig sets defaults for the repositories created or initialized by the tools.
+type RepositoriesConfig struct {
+	// DefaultBranch is the initial branch of initialize_repository, main when empty.
+	DefaultBranch string `mapstructure:"default_branch" yaml:"default_branch"`
+}
+
+// CacheConfig configures in-memory caches.
+type CacheConfig struct {
+	RepoAccessTTL time.Duration `mapstructure:"repo_access_ttl" yaml:"repo_access_ttl"`
+	// ResponseMaxBytes bounds the GitHub API responses kept for conditional requests. Zero
+	// disables the response cache.
+	ResponseMaxBytes int64 `mapstructure:"response_max_bytes" yaml:"response_max_bytes"`
+}
+
+// RateLimitsConfig configures the client-side rate limiters.
+type RateLimitsConfig struct {
+	// StateFile keeps the state of the rate limiters across restarts. It is not kept when empty.
+	StateFile string `mapstructure:"state_file" yaml:"state_file"`
+}
+
+// LoggingConfig configures the server log.
+type LoggingConfig struct {
+	// File is the log file path. The log goes to stderr when empty.
+	File           string `mapstructure:"file" yaml:"file"`
+	CommandLogging bool   `mapstructure:"command_logging" yaml:"command_logging"`
+	// Level is debug, info, warn or error. When empty, it is debug when logging
+	// to a file and info otherwise.
+	Level string `mapstructure:"level" yaml:"level"`
+}
+
+// MetricsConfig configures the Prometheus metrics listener.
+type MetricsConfig struct {
+	// Address is the host:port on which /metrics is served, such as 127.0.0.1:9090. Metrics
+	// are not served when empty.
+	Address string `mapstructure:"address" yaml:"address"`
+}
+
+// CommitSigningConfig mirrors signing.Config.
+type CommitSigningConfig struct {
+	Format  string `mapstructure:"format" yaml:"format"`
+	Key     string `mapstructure:"key" yaml:"key"`
+	Program string `mapstructure:"program" yaml:"program"`
+	Name    string `mapstructure:"name" yaml:"name"`
+	Email   string `mapstructure:"email" yaml:"email"`
+}
+
+// Signing returns the configuration in the form used by the signing package.
+func (c CommitSigningConfig) Signing() signing.Config {
+	return signing.Config{
+		Format:  signing.Format(c.Format),
+		Key:     c.Key,
+		Program: c.Program,
+		Name:    c.Name,
+		Email:   c.Email,
+	}
+}
+
+// TranslationsConfig configures tool description overrides.
+type TranslationsConfig struct {
+	Export bool `mapstructure:"export" yaml:"export"`
+}
+
+// Source identifies where the value of a setting came from.
+type Source string
+
+const (
+	SourceDefault Source = "default"
+	SourceFile    Source = "file"
+	SourceEnv     Source = "env"
+	SourceFlag    Source = "flag"
+)
+
+// setting ties a config file key to the flag and environment variable that
+// override it. The flag and variable names predate the config file and are
+// kept for compatibility.
+type setting struct {
+	key  string
+	flag string
+	env  string
+}
+
+var settings = []setting{
+	{key: "host", flag: "gh-host", env: "GITHUB_HOST"},
+	{key: "enterprise.api_url", flag: "enterprise-api-url", env: "GITHUB_ENTERPRISE_API_URL"},
+	{key: "enterprise.upload_url", flag: "enterprise-upload-url", env: "GITHUB_ENTERPRISE_UPLOAD_URL"},
+	{key: "enterprise.graphql_url", flag: "enterprise-graphql-url", env: "GITHUB_ENTERPRISE_GRAPHQL_URL"},
+	{key: "enterprise.version", flag: "enterprise-version", env: "GITHUB_ENTERPRISE_VERSION"},
+	{key: "token", env: "GITHUB_PERSONAL_ACCESS_TOKEN"},
+	{key: "app.id", flag: "app-id", env: "GITHUB_APP_ID"},
+	{key: "app.private_key_file", flag: "app-private-key-file", env: "GITHUB_APP_PRIVATE_KEY_FILE"},
+	{key: "app.private_key", env: "GITHUB_APP_PRIVATE_KEY"},
+	{key: "app.installation_owner", flag: "app-installation-owner", env: "GITHUB_APP_INSTALLATION_OWNER"},
+	{key: "oauth.client_id", flag: "oauth-client-id", env: "GITHUB_OAUTH_CLIENT_ID"},
+	{key: "oauth.scopes", flag: "oauth-scopes", env: "GITHUB_OAUTH_SCOPES"},
+	{key: "toolsets.enabled", flag: "toolsets", env: "GITHUB_TOOLSETS"},
+	{key: "toolsets.tools", flag: "tools", env: "GITHUB_TOOLS"},
+	{key: "toolsets.dynamic", flag: "dynamic-toolsets", env: "GITHUB_DYNAMIC_TOOLSETS"},
+	{key: "policies.read_only", flag: "read-only", env: "GITHUB_READ_ONLY"},
+	{key: "policies.lockdown_mode", flag: "lockdown-mode", env: "GITHUB_LOCKDOWN_MODE"},
+	{key: "limits.content_window_size", flag: "content-window-size", env: "GITHUB_CONTENT_WINDOW_SIZE"},
+	{key: "limits.max_concurrent_requests", flag: "max-concurrent-requests", env: "GITHUB_MAX_CONCURRENT_REQUESTS"},
+	{key: "limits.push.max_files", env: "GITHUB_PUSH_MAX_FILES"},
+	{key: "limits.push.max_file_size_bytes", env: "GITHUB_PUSH_MAX_FILE_SIZE_BYTES"},
+	{key: "limits.push.max_total_size_bytes", env: "GITHUB_PUSH_MAX_TOTAL_SIZE_BYTES"},
+	{key: "limits.push.default_chunk_size", env: "GITHUB_PUSH_DEFAULT_CHUNK_SIZE"},
+	{key: "limits.push.max_chunk_size", env: "GITHUB_PUSH_MAX_CHUNK_SIZE"},
+	{key: "repositories.default_branch", env: "GITHUB_DEFAULT_BRANCH"},
+	{key: "cache.repo_access_ttl", flag: "repo-access-cache-ttl", env: "GITHUB_REPO_ACCESS_CACHE_TTL"},
+	{key: "cache.response_max_bytes", flag: "response-cache-max-bytes", env: "GITHUB_RESPONSE_CACHE_MAX_BYTES"},
+	{key: "rate_limits.state_file", flag: "rate-limit-state-file", env: "GITHUB_RATE_LIMIT_STATE_FILE"},
+	{key: "logging.file", flag: "log-file", env: "GITHUB_LOG_FILE"},
+	{key: "logging.command_logging", flag: "enable-command-logging", env: "GITHUB_ENABLE_COMMAND_LOGGING"},
+	{key: "logging.level", flag: "log-level", env: "GITHUB_LOG_LEVEL"},
+	{key: "metrics.address", flag: "metrics-address", env: "GITHUB_METRICS_ADDRESS"},
+	{key: "commit_signing.format", flag: "commit-signing-format", env: "GITHUB_COMMIT_SIGNING_FORMAT"},
+	{key: "commit_signing.key", flag: "commit-signing-key", env: "GITHUB_COMMIT_SIGNING_KEY"},
+	{key: "commit_signing.program", flag: "commit-signing-program", env: "GITHUB_COMMIT_SIGNING_PROGRAM"},
+	{key: "commit_signing.name", flag: "commit-signing-name", env: "GITHUB_COMMIT_SIGNING_NAME"},
+	{key: "commit_signing.email", flag: "commit-signing-email", env: "GITHUB_COMMIT_SIGNING_EMAIL"},
+	{key: "translations.export", flag: "export-translations", env: "GITHUB_EXPORT_TRANSLATIONS"},
+}
+
+// Default returns the configuration used when nothing is set.
+func Default() Config {
+	return Config{
+		Limits: LimitsConfig{ContentWindowSize: DefaultContentWindowSize, MaxConcurrentRequests: DefaultMaxConcurrentRequests},
+		Cache:  CacheConfig{RepoAccessTTL: DefaultRepoAccessCacheTTL, ResponseMaxBytes: DefaultResponseCacheMaxBytes},
+	}
+}
+
+// Load builds the configuration from the file at path, if path is not empty,
+// the environment and any changed flags in flags, which may be nil. The file
+// format is chosen by its extension: .yaml, .yml or .toml.
+func Load(path string, flags *pflag.FlagSet) (*Config, error) {
+	v := viper.New()
+
+	defaults := Default()
+	v.SetDefault("limits.content_window_size", defaults.Limits.ContentWindowSize)
+	v.SetDefault("limits.max_concurrent_requests", defaults.Limits.MaxConcurrentRequests)
+	v.SetDefault("cache.repo_access_ttl", defaults.Cache.RepoAccessTTL)
+	v.SetDefault("cache.response_max_bytes", defaults.Cache.ResponseMaxBytes)
+
+	if path != "" {
+		switch ext := strings.ToLower(filepath.Ext(path)); ext {
+		case ".yaml", ".yml", ".toml":
+		default:
+			return nil, fmt.Errorf("unsupported config file type %q: use .yaml, .yml or .toml", ext)
+		}
+		v.SetConfigFile(path)
+		if err := v.ReadInConfig(); err != nil {
+			return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
+		}
+	}
+
+	for _, s := range settings {
+		if err := v.BindEnv(s.key, s.env); err != nil {
+			return nil, fmt.Errorf("failed to bind %s: %w", s.env, err)
+		}
+		if s.flag == "" || flags == nil {
+			continue
+		}
+		if f := flags.Lookup(s.flag); f != nil {
+			if err := v.BindPFlag(s.key, f); err != nil {
+				return nil, fmt.Errorf("failed to bind --%s: %w", s.flag, err)
+			}
+		}
+	}
+
+	var cfg Config
+	if err := v.UnmarshalExact(&cfg); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	cfg.sources = make(map[string]Source, len(settings)+1)
+	for _, s := range settings {
+		cfg.sources[s.key] = sourceOf(v, s, flags)
+	}
+	// Per-host push limits and accounts can only be set in the config file
+	if v.InConfig("limits.push_hosts") {
+		cfg.sources["limits.push_hosts"] = SourceFile
+	}
+	if v.InConfig("accounts") {
+		cfg.sources["accounts"] = SourceFile
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+func sourceOf(v *viper.Viper, s setting, flags *pflag.FlagSet) Source {
+	if s.flag != "" && flags != nil {
+		if f := flags.Lookup(s.flag); f != nil && f.Changed {
+			return SourceFlag
+		}
+	}
+	if _, ok := os.LookupEnv(s.env); ok {
+		return SourceEnv
+	}
+	if v.InConfig(s.key) {
+		return SourceFile
+	}
+	return SourceDefault
+}
+
+// Source reports where the setting with the given config file key came from.
+func (c *Config) Source(key string) Source {
+	if s, ok := c.sources[key]; ok {
+		return s
+	}
+	return SourceDefault
+}
+
+// RestartRequired returns the keys of the settings that differ between c and
+// next and that are only read at startup: the server connection and credentials, the dynamic
+// toolsets mode, request concurrency, push limits, the default branch, caches, the rate
+// limiter state file, log output, the metrics listener, commit signing and translations.
+// The other settings can be reloaded while the server runs.
+func (c *Config) RestartRequired(next *Config) []string {
+	var keys []string
+	changed := func(key string, differs bool) {
+		if differs {
+			keys = append(keys, key)
+		}
+	}
+	changed("host", c.Host != next.Host)
+	changed("token", c.Token != next.Token)
+	changed("app", c.App != next.App)
+	changed("enterprise", c.Enterprise != next.Enterprise)
+	changed("oauth", c.OAuth.ClientID != next.OAuth.ClientID || !slices.Equal(c.OAuth.Scopes, next.OAuth.Scopes))
+	changed("accounts", !slices.Equal(c.Accounts, next.Accounts))
+	changed("toolsets.dynamic", c.Toolsets.Dynamic != next.Toolsets.Dynamic)
+	changed("limits.max_concurrent_requests", c.Limits.MaxConcurrentRequests != next.Limits.MaxConcurrentRequests)
+	changed("limits.push", c.Limits.Push != next.Limits.Push)
+	changed("limits.push_hosts", !slices.Equal(c.Limits.PushHosts, next.Limits.PushHosts))
+	changed("repositories.default_branch", c.Repositories.DefaultBranch != next.Repositories.DefaultBranch)
+	changed("cache.repo_access_ttl", c.Cache.RepoAccessTTL != next.Cache.RepoAccessTTL)
+	changed("cache.response_max_bytes", c.Cache.ResponseMaxBytes != next.Cache.ResponseMaxBytes)
+	changed("rate_limits.state_file", c.RateLimits.StateFile != next.RateLimits.StateFile)
+	changed("logging.file", c.Logging.File != next.Logging.File)
+	changed("logging.command_logging", c.Logging.CommandLogging != next.Logging.CommandLogging)
+	changed("metrics.address", c.Metrics.Address != next.Metrics.Address)
+	changed("commit_signing", c.CommitSigning != next.CommitSigning)
+	changed("translations.export", c.Translations.Export != next.Translations.Export)
+	return keys
+}
+
+// Validate checks that the configuration is usable. It does not require a
+// token, so that the configuration can be inspected without one.
+func (c *Config) Validate() error {
+	var errs []error
+
+	if c.App.ID < 0 {
+		errs = append(errs, fmt.Errorf("app.id must be positive, got %d", c.App.ID))
+	}
+	if c.App.Enabled() {
+		if (c.App.PrivateKeyFile == "") == (c.App.PrivateKey == "") {
+			errs = append(errs, errors.New("exactly one of app.private_key_file and app.private_key is required when app.id is set"))
+		}
+		if c.Token != "" {
+			errs = append(errs, errors.New("token and app.id are mutually exclusive: authenticate with a token or as a GitHub App"))
+		}
+	} else if c.App.PrivateKeyFile != "" || c.App.PrivateKey != "" || c.App.InstallationOwner != "" {
+		errs = append(errs, errors.New("app.id is required when other app settings are set"))
+	}
+	if c.OAuth.Enabled() && (c.Token != "" || c.App.Enabled()) {
+		errs = append(errs, errors.New("oauth.client_id cannot be combined with token or app.id: use a single way to authenticate"))
+	}
+	if !c.OAuth.Enabled() && len(c.OAuth.Scopes) > 0 {
+		errs = append(errs, errors.New("oauth.client_id is required when oauth.scopes is set"))
+	}
+	enterprise := c.Enterprise
+	for _, u := range []struct{ key, value string }{
+		{"enterprise.api_url", enterprise.APIURL},
+		{"enterprise.upload_url", enterprise.UploadURL},
+		{"enterprise.graphql_url", enterprise.GraphQLURL},
+	} {
+		if u.value == "" {
+			continue
+		}
+		if c.Host == "" {
+			errs = append(errs, fmt.Errorf("%s requires host, the GitHub Enterprise Server it overrides the URL of", u.key))
+		}
+		if parsed, err := url.Parse(u.value); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			errs = append(errs, fmt.Errorf("%s must be a URL with a scheme (http or https), got %q", u.key, u.value))
+		}
+	}
+	if enterprise.Version != "" && !enterpriseVersion.MatchString(enterprise.Version) {
+		errs = append(errs, fmt.Errorf("enterprise.version must be a version such as 3.14, got %q", enterprise.Version))
+	}
+	seenAccounts := make(map[string]bool, len(c.Accounts))
+	for i, account := range c.Accounts {
+		switch {
+		case !accountName.MatchString(account.Name):
+			errs = append(errs, fmt.Errorf("accounts[%d].name must be made of lowercase letters, digits, - and _, got %q", i, account.Name))
+		case account.Name == "default":
+			errs = append(errs, fmt.Errorf("accounts[%d].name must not be default, the name of the account of token", i))
+		case seenAccounts[account.Name]:
+			errs = append(errs, fmt.Errorf("accounts lists account %s more than once", account.Name))
+		}
+		seenAccounts[account.Name] = true
+		if account.Host != "" && !strings.HasPrefix(account.Host, "https://") && !strings.HasPrefix(account.Host, "http://") {
+			errs = append(errs, fmt.Errorf("accounts[%d].host must be a URL with a scheme (http or https), got %q", i, account.Host))
+		}
+		if account.TokenEnv == "" {
+			errs = append(errs, fmt.Errorf("accounts[%d].token_env must be set", i))
+		}
+	}
+	if c.Limits.ContentWindowSize <= 0 {
+		errs = append(errs, fmt.Errorf("limits.content_window_size must be positive, got %d", c.Limits.ContentWindowSize))
+	}
+	if c.Limits.MaxConcurrentRequests < 0 {
+		errs = append(errs, fmt.Errorf("limits.max_concurrent_requests must not be negative, got %d", c.Limits.MaxConcurrentRequests))
+	}
+	push := c.Limits.Push
+	if push.MaxFiles < 0 || push.MaxFileSizeBytes < 0 || push.MaxTotalSizeBytes < 0 || push.DefaultChunkSize < 0 || push.MaxChunkSize < 0 {
+		errs = append(errs, errors.New("limits.push values must not be negative"))
+	}
+	seenHosts := make(map[string]bool, len(c.Limits.PushHosts))
+	for i, host := range c.Limits.PushHosts {
+		name := strings.ToLower(strings.TrimSpace(host.Host))
+		switch {
+		case name == "":
+			errs = append(errs, fmt.Errorf("limits.push_hosts[%d].host must be set", i))
+		case strings.Contains(name, "/"):
+			errs = append(errs, fmt.Errorf("limits.push_hosts[%d].host must be a hostname, not a URL: %q", i, host.Host))
+		case seenHosts[name]:
+			errs = append(errs, fmt.Errorf("limits.push_hosts lists host %s more than once", name))
+		}
+		seenHosts[name] = true
+		if host.MaxFiles < 0 || host.MaxFileSizeBytes < 0 || host.MaxTotalSizeBytes < 0 || host.DefaultChunkSize < 0 || host.MaxChunkSize < 0 {
+			errs = append(errs, fmt.Errorf("limits.push_hosts[%d] values must not be negative", i))
+		}
+	}
+	if branch := c.Repositories.DefaultBranch; branch != "" && (strings.ContainsAny(branch, " ~^:?*[\\") || strings.Contains(branch, "..") || strings.HasPrefix(branch, "/") || strings.HasSuffix(branch, "/")) {
+		errs = append(errs, fmt.Errorf("repositories.default_branch is not a valid branch name: %q", branch))
+	}
+	if c.Cache.RepoAccessTTL < 0 {
+		errs = append(errs, fmt.Errorf("cache.repo_access_ttl must not be negative, got %s", c.Cache.RepoAccessTTL))
+	}
+	if c.Cache.ResponseMaxBytes < 0 {
+		errs = append(errs, fmt.Errorf("cache.response_max_bytes must not be negative, got %d", c.Cache.ResponseMaxBytes))
+	}
+	if slices.ContainsFunc(c.OAuth.Scopes, func(scope string) bool { return strings.TrimSpace(scope) == "" }) {
+		errs = append(errs, errors.New("oauth.scopes must not contain empty scopes"))
+	}
+	for _, name := range append(append([]string{}, c.Toolsets.Enabled...), c.Toolsets.Tools...) {
+		if strings.TrimSpace(name) == "" {
+			errs = append(errs, errors.New("toolsets.enabled and toolsets.tools must not contain empty names"))
+			break
+		}
+	}
+
+	switch strings.ToLower(c.Logging.Level) {
+	case "", "debug", "info", "warn", "error":
+	default:
+		errs = append(errs, fmt.Errorf("logging.level must be debug, info, warn or error, got %q", c.Logging.Level))
+	}
+
+	if c.Metrics.Address != "" {
+		if _, _, err := net.SplitHostPort(c.Metrics.Address); err != nil {
+			errs = append(errs, fmt.Errorf("metrics.address must be host:port, got %q", c.Metrics.Address))
+		}
+	}
+
+	signingCfg := c.CommitSigning.Signing()
+	switch signingCfg.Format {
+	case "":
+	case signing.FormatGPG, signing.FormatSSH:
+		if signingCfg.Key == "" || signingCfg.Name == "" || signingCfg.Email == "" {
+			errs = append(errs, errors.New("commit_signing.key, commit_signing.name and commit_signing.email are required when commit_signing.format is set"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("commit_signing.format must be gpg or ssh, got %q", signingCfg.Format))
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"fmt"
+	"io"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// redacted replaces secrets in printed configuration
+const redacted = "<redacted>"
+
+// WriteEffective writes the configuration as YAML, in the config file format,
+// with a comment after each setting naming where its value came from. The
+// token and the app private key are redacted.
+func (c *Config) WriteEffective(w io.Writer) error {
+	printed := *c
+	if printed.Token != "" {
+		printed.Token = redacted
+	}
+	if printed.App.PrivateKey != "" {
+		printed.App.PrivateKey = redacted
+	}
+
+	var doc yaml.Node
+	if err := doc.Encode(printed); err != nil {
+		return fmt.Errorf("failed to encode configuration: %w", err)
+	}
+	c.annotateSources(&doc, "")
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return fmt.Errorf("failed to write configuration: %w", err)
+	}
+	return enc.Close()
+}
+
+// annotateSources walks the mapping nodes of n and comments each setting with its source.
+func (c *Config) annotateSources(n *yaml.Node, prefix string) {
+	if n.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		key, value := n.Content[i], n.Content[i+1]
+		path := key.Value
+		if prefix != "" {
+			path = prefix + "." + key.Value
+		}
+		if value.Kind == yaml.MappingNode {
+			c.annotateSources(value, path)
+			continue
+		}
+		if value.Kind == yaml.SequenceNode && len(value.Content) == 0 {
+			// Empty lists are printed inline, after the value
+			value.LineComment = c.describeSource(path)
+			continue
+		}
+		key.LineComment = c.describeSource(path)
+	}
+}
+
+func (c *Config) describeSource(key string) string {
+	source := c.Source(key)
+	for _, s := range settings {
+		if s.key != key {
+			continue
+		}
+		switch source {
+		case SourceEnv:
+			return fmt.Sprintf("env %s", s.env)
+		case SourceFlag:
+			return fmt.Sprintf("flag --%s", s.flag)
+		}
+	}
+	return string(source)
+}
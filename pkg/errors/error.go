@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/github/github-mcp-server/pkg/trace"
 	"github.com/github/github-mcp-server/pkg/utils"
 	"github.com/google/go-github/v79/github"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -13,17 +14,28 @@ type GitHubAPIError struct {
 	Message  string           `json:"message"`
 	Response *github.Response `json:"-"`
 	Err      error            `json:"-"`
+	// RequestID is GitHub's X-GitHub-Request-Id for the failed request, if known
+	RequestID string `json:"request_id,omitempty"`
 }
 
 // NewGitHubAPIError creates a new GitHubAPIError with the provided message, response, and error.
 func newGitHubAPIError(message string, resp *github.Response, err error) *GitHubAPIError {
 	return &GitHubAPIError{
-		Message:  message,
-		Response: resp,
-		Err:      err,
+		Message:   message,
+		Response:  resp,
+		Err:       err,
+		RequestID: requestID(resp),
 	}
 }
 
+// requestID returns the GitHub request ID of resp, or "" if there is none.
+func requestID(resp *github.Response) string {
+	if resp == nil || resp.Response == nil {
+		return ""
+	}
+	return resp.Header.Get(trace.HeaderGitHubRequestID)
+}
+
 func (e *GitHubAPIError) Error() string {
 	return fmt.Errorf("%s: %w", e.Message, e.Err).Error()
 }
@@ -113,6 +125,10 @@ func NewGitHubAPIErrorResponse(ctx context.Context, message string, resp *github
 	if ctx != nil {
 		_, _ = addGitHubAPIErrorToContext(ctx, apiErr) // Explicitly ignore error for graceful handling
 	}
+	if apiErr.RequestID != "" {
+		// Quote the request ID so that support can find the call in GitHub's logs
+		message = fmt.Sprintf("%s (GitHub request ID %s)", message, apiErr.RequestID)
+	}
 	return utils.NewToolResultErrorFromErr(message, err)
 }
 
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/google/go-github/v79/github"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -292,6 +293,24 @@ func TestGitHubErrorTypes(t *testing.T) {
 		assert.Equal(t, "test message: not found", err.Error())
 	})
 
+	t.Run("GitHubAPIError records the GitHub request ID", func(t *testing.T) {
+		resp := &github.Response{Response: &http.Response{
+			StatusCode: 502,
+			Header:     http.Header{"X-Github-Request-Id": []string{"C0DE:1234:5678"}},
+		}}
+		ctx := ContextWithGitHubErrors(context.Background())
+
+		result := NewGitHubAPIErrorResponse(ctx, "failed to create tree", resp, fmt.Errorf("bad gateway"))
+
+		apiErrors, err := GetGitHubAPIErrors(ctx)
+		require.NoError(t, err)
+		require.Len(t, apiErrors, 1)
+		assert.Equal(t, "C0DE:1234:5678", apiErrors[0].RequestID)
+		textContent, ok := result.Content[0].(*mcp.TextContent)
+		require.True(t, ok)
+		assert.Equal(t, "failed to create tree (GitHub request ID C0DE:1234:5678): bad gateway", textContent.Text)
+	})
+
 	t.Run("GitHubGraphQLError implements error interface", func(t *testing.T) {
 		originalErr := fmt.Errorf("query failed")
 
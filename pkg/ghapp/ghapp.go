@@ -0,0 +1,487 @@
+// Package ghapp authenticates the server as a GitHub App: it signs app JWTs with the private
+// key of the app, finds the installation of the app on the owner each request targets, and
+// mints installation tokens for it, renewed before they expire.
+package ghapp
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/github/github-mcp-server/pkg/ratelimit"
+	"github.com/google/go-github/v79/github"
+)
+
+const (
+	// jwtLifetime is how long app JWTs are valid. GitHub accepts at most 10 minutes.
+	jwtLifetime = 9 * time.Minute
+	// jwtClockSkew backdates the JWTs issued, in case the clock of GitHub is behind ours
+	jwtClockSkew = time.Minute
+	// refreshMargin is how long before it expires an installation token is renewed, so that
+	// requests never carry a token about to expire
+	refreshMargin = 5 * time.Minute
+	// notInstalledTTL is how long an owner the app is not installed on uses the default
+	// installation before the installations of the app are looked up again
+	notInstalledTTL = 10 * time.Minute
+)
+
+// Config identifies a GitHub App and the installation used by default.
+type Config struct {
+	// AppID is the ID of the app
+	AppID int64
+	// PrivateKey is a private key of the app, PEM encoded in PKCS #1 or PKCS #8
+	PrivateKey []byte
+	// DefaultOwner is the account whose installation authenticates requests that name no owner,
+	// such as searches and GraphQL queries. When empty, the app must have a single installation.
+	DefaultOwner string
+}
+
+// App mints installation tokens for a GitHub App.
+type App struct {
+	id           int64
+	key          *rsa.PrivateKey
+	defaultOwner string
+	client       *github.Client
+
+	// lookupMu serializes the lookups of installations, so that concurrent requests to the
+	// same owner look it up once
+	lookupMu sync.Mutex
+
+	mu            sync.Mutex
+	installations map[int64]*installation
+	// owners maps lowercased owners to their installation
+	owners map[string]*installation
+	// notInstalled records when owners the app is not installed on were looked up
+	notInstalled map[string]time.Time
+	defaultInst  *installation
+}
+
+// installation is an installation of the app and its current token
+type installation struct {
+	id                  int64
+	account             string
+	repositorySelection string
+	permissions         map[string]string
+
+	// mu serializes the renewals of the token
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+// New returns the App described by cfg, calling the GitHub API at baseURL through base, or
+// http.DefaultTransport if base is nil.
+func New(cfg Config, baseURL *url.URL, base http.RoundTripper) (*App, error) {
+	if cfg.AppID <= 0 {
+		return nil, errors.New("GitHub App ID must be positive")
+	}
+	key, err := parsePrivateKey(cfg.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	app := &App{
+		id:            cfg.AppID,
+		key:           key,
+		defaultOwner:  cfg.DefaultOwner,
+		installations: make(map[int64]*installation),
+		owners:        make(map[string]*installation),
+		notInstalled:  make(map[string]time.Time),
+	}
+	app.client = github.NewClient(&http.Client{Transport: &jwtTransport{app: app, base: base}})
+	if baseURL != nil {
+		app.client.BaseURL = baseURL
+	}
+	return app, nil
+}
+
+func parsePrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("GitHub App private key is not PEM encoded")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse GitHub App private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("GitHub App private key must be an RSA key, got %T", parsed)
+	}
+	return key, nil
+}
+
+// ID returns the ID of the app.
+func (a *App) ID() int64 {
+	return a.id
+}
+
+// JWT returns a JSON Web Token authenticating as the app, signed with RS256.
+func (a *App) JWT() (string, error) {
+	now := time.Now()
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]any{
+		"iat": now.Add(-jwtClockSkew).Unix(),
+		"exp": now.Add(jwtLifetime).Unix(),
+		"iss": strconv.FormatInt(a.id, 10),
+	})
+	if err != nil {
+		return "", err
+	}
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, a.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign GitHub App JWT: %w", err)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// Token returns a token of the installation of the app on owner, minting one if the last is
+// about to expire, along with the ID of the installation. Owners the app is not installed on,
+// and an empty owner, get the token of the default installation, which can still read their
+// public repositories.
+func (a *App) Token(ctx context.Context, owner string) (string, int64, error) {
+	inst, err := a.installationFor(ctx, owner)
+	if err != nil {
+		return "", 0, err
+	}
+	token, err := a.token(ctx, inst)
+	if err != nil {
+		return "", 0, err
+	}
+	return token, inst.id, nil
+}
+
+func (a *App) token(ctx context.Context, inst *installation) (string, error) {
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+	if inst.token != "" && time.Until(inst.expiresAt) > refreshMargin {
+		return inst.token, nil
+	}
+
+	token, _, err := a.client.Apps.CreateInstallationToken(ctx, inst.id, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create a token for installation %d of GitHub App %d: %w", inst.id, a.id, err)
+	}
+	inst.token = token.GetToken()
+	inst.expiresAt = token.GetExpiresAt().Time
+	a.mu.Lock()
+	if token.Permissions != nil {
+		inst.permissions = permissionsMap(token.Permissions)
+	}
+	a.mu.Unlock()
+	return inst.token, nil
+}
+
+// installationFor returns the installation authenticating the requests to owner.
+func (a *App) installationFor(ctx context.Context, owner string) (*installation, error) {
+	if owner == "" {
+		return a.defaultInstallation(ctx)
+	}
+	inst, installed, err := a.ownerInstallation(ctx, strings.ToLower(owner))
+	if err != nil {
+		return nil, err
+	}
+	if !installed {
+		return a.defaultInstallation(ctx)
+	}
+	return inst, nil
+}
+
+// ownerInstallation returns the installation of the app on owner, looking it up unless it is
+// known, and reports whether the app is installed on owner.
+func (a *App) ownerInstallation(ctx context.Context, owner string) (*installation, bool, error) {
+	cached := func() (*installation, bool, bool) {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		if inst, ok := a.owners[owner]; ok {
+			return inst, true, true
+		}
+		if checked, ok := a.notInstalled[owner]; ok && time.Since(checked) < notInstalledTTL {
+			return nil, false, true
+		}
+		return nil, false, false
+	}
+	if inst, installed, known := cached(); known {
+		return inst, installed, nil
+	}
+
+	a.lookupMu.Lock()
+	defer a.lookupMu.Unlock()
+	// Another request may have looked the owner up meanwhile
+	if inst, installed, known := cached(); known {
+		return inst, installed, nil
+	}
+	inst, err := a.lookup(ctx, owner)
+	if err != nil {
+		return nil, false, err
+	}
+	if inst == nil {
+		a.mu.Lock()
+		a.notInstalled[owner] = time.Now()
+		a.mu.Unlock()
+		return nil, false, nil
+	}
+	return inst, true, nil
+}
+
+// lookup finds the installation of the app on owner, an organization or a user, and records
+// it. It returns nil if the app is not installed on owner.
+func (a *App) lookup(ctx context.Context, owner string) (*installation, error) {
+	found, resp, err := a.client.Apps.FindOrganizationInstallation(ctx, owner)
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		found, resp, err = a.client.Apps.FindUserInstallation(ctx, owner)
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil, nil
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the installation of GitHub App %d on %s: %w", a.id, owner, err)
+	}
+	return a.record(found), nil
+}
+
+// record adds the installation found to those of the app, keeping the token of an installation
+// already known.
+func (a *App) record(found *github.Installation) *installation {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	inst, ok := a.installations[found.GetID()]
+	if !ok {
+		inst = &installation{id: found.GetID()}
+		a.installations[inst.id] = inst
+	}
+	inst.account = found.GetAccount().GetLogin()
+	inst.repositorySelection = found.GetRepositorySelection()
+	if inst.permissions == nil {
+		inst.permissions = permissionsMap(found.Permissions)
+	}
+	a.owners[strings.ToLower(inst.account)] = inst
+	delete(a.notInstalled, strings.ToLower(inst.account))
+	return inst
+}
+
+// defaultInstallation returns the installation on the default owner, or the only installation
+// of the app when there is no default owner.
+func (a *App) defaultInstallation(ctx context.Context) (*installation, error) {
+	a.mu.Lock()
+	inst := a.defaultInst
+	a.mu.Unlock()
+	if inst != nil {
+		return inst, nil
+	}
+
+	if a.defaultOwner != "" {
+		found, installed, err := a.ownerInstallation(ctx, strings.ToLower(a.defaultOwner))
+		if err != nil {
+			return nil, err
+		}
+		if !installed {
+			return nil, fmt.Errorf("GitHub App %d is not installed on %s", a.id, a.defaultOwner)
+		}
+		inst = found
+	} else {
+		installations, _, err := a.client.Apps.ListInstallations(ctx, &github.ListOptions{PerPage: 2})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the installations of GitHub App %d: %w", a.id, err)
+		}
+		switch len(installations) {
+		case 0:
+			return nil, fmt.Errorf("GitHub App %d is not installed on any account", a.id)
+		case 1:
+			inst = a.record(installations[0])
+		default:
+			return nil, fmt.Errorf("GitHub App %d has several installations: set the installation owner used for requests that name no owner", a.id)
+		}
+	}
+
+	a.mu.Lock()
+	a.defaultInst = inst
+	a.mu.Unlock()
+	return inst, nil
+}
+
+// InstallationStatus describes an installation the app authenticates as.
+type InstallationStatus struct {
+	ID                  int64             `json:"id"`
+	Account             string            `json:"account"`
+	RepositorySelection string            `json:"repository_selection,omitempty"`
+	Permissions         map[string]string `json:"permissions,omitempty"`
+	// TokenExpiresAt is when the current token expires, nil until one is minted
+	TokenExpiresAt *time.Time `json:"token_expires_at,omitempty"`
+	// Default reports whether the installation authenticates the requests that name no owner
+	Default bool `json:"default"`
+}
+
+// Status describes the app and the installations it authenticated as so far.
+type Status struct {
+	AppID         int64                `json:"app_id"`
+	DefaultOwner  string               `json:"default_owner,omitempty"`
+	Installations []InstallationStatus `json:"installations"`
+}
+
+// Status returns the installations the app authenticated as so far, sorted by account.
+func (a *App) Status() Status {
+	a.mu.Lock()
+	installations := make([]*installation, 0, len(a.installations))
+	for _, inst := range a.installations {
+		installations = append(installations, inst)
+	}
+	a.mu.Unlock()
+
+	status := Status{AppID: a.id, DefaultOwner: a.defaultOwner, Installations: make([]InstallationStatus, 0, len(installations))}
+	for _, inst := range installations {
+		status.Installations = append(status.Installations, a.status(inst))
+	}
+	slices.SortFunc(status.Installations, func(x, y InstallationStatus) int {
+		return strings.Compare(strings.ToLower(x.Account), strings.ToLower(y.Account))
+	})
+	return status
+}
+
+// Installation returns the status of the installation authenticating the requests to owner,
+// minting its token if needed.
+func (a *App) Installation(ctx context.Context, owner string) (InstallationStatus, error) {
+	inst, err := a.installationFor(ctx, owner)
+	if err != nil {
+		return InstallationStatus{}, err
+	}
+	if _, err := a.token(ctx, inst); err != nil {
+		return InstallationStatus{}, err
+	}
+	return a.status(inst), nil
+}
+
+func (a *App) status(inst *installation) InstallationStatus {
+	inst.mu.Lock()
+	expiresAt := inst.expiresAt
+	inst.mu.Unlock()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	s := InstallationStatus{
+		ID:                  inst.id,
+		Account:             inst.account,
+		RepositorySelection: inst.repositorySelection,
+		Permissions:         inst.permissions,
+		Default:             inst == a.defaultInst,
+	}
+	if !expiresAt.IsZero() {
+		s.TokenExpiresAt = &expiresAt
+	}
+	return s
+}
+
+// permissionsMap returns the permissions granted, keyed by permission name.
+func permissionsMap(permissions *github.InstallationPermissions) map[string]string {
+	if permissions == nil {
+		return nil
+	}
+	data, err := json.Marshal(permissions)
+	if err != nil {
+		return nil
+	}
+	var m map[string]string
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil
+	}
+	return m
+}
+
+type appKey struct{}
+
+// ContextWithApp returns a context carrying app, for the get_app_installations tool.
+func ContextWithApp(ctx context.Context, app *App) context.Context {
+	return context.WithValue(ctx, appKey{}, app)
+}
+
+// FromContext returns the App carried by ctx, if the server authenticates as a GitHub App.
+func FromContext(ctx context.Context) (*App, bool) {
+	app, ok := ctx.Value(appKey{}).(*App)
+	return app, ok && app != nil
+}
+
+// jwtTransport authenticates the requests of the app itself with a JWT
+type jwtTransport struct {
+	app  *App
+	base http.RoundTripper
+}
+
+func (t *jwtTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.app.JWT()
+	if err != nil {
+		return nil, err
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+token)
+	return t.base.RoundTrip(req)
+}
+
+// Transport authenticates each request with a token of the installation on the owner it
+// targets, and rate limits it as that installation, see ratelimit.WithInstallationID.
+type Transport struct {
+	App  *App
+	Base http.RoundTripper
+}
+
+// NewTransport wraps base, or http.DefaultTransport if base is nil, to authenticate requests
+// as installations of app.
+func NewTransport(app *App, base http.RoundTripper) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Transport{App: app, Base: base}
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, id, err := t.App.Token(req.Context(), RequestOwner(req))
+	if err != nil {
+		return nil, err
+	}
+	req = req.Clone(ratelimit.WithInstallationID(req.Context(), id))
+	req.Header.Set("Authorization", "Bearer "+token)
+	return t.Base.RoundTrip(req)
+}
+
+// RequestOwner returns the owner of the repository, organization or user req targets, on the
+// REST, uploads or raw content hosts, or "" for requests that name no owner.
+func RequestOwner(req *http.Request) string {
+	path := strings.TrimPrefix(req.URL.Path, "/api/v3")
+	path = strings.TrimPrefix(path, "/api/uploads")
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	switch {
+	case len(segments) < 2:
+		return ""
+	case strings.HasPrefix(req.URL.Hostname(), "raw."):
+		return segments[0]
+	}
+	switch segments[0] {
+	case "repos", "orgs", "users", "raw":
+		return segments[1]
+	}
+	return ""
+}
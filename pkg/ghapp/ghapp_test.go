@@ -0,0 +1,329 @@
+package ghapp
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/github/github-mcp-server/pkg/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testKeyOnce sync.Once
+	testKey     *rsa.PrivateKey
+)
+
+func privateKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	testKeyOnce.Do(func() {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		testKey = key
+	})
+	return testKey
+}
+
+func pkcs1PEM(key *rsa.PrivateKey) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+}
+
+// verifyJWT checks the signature of token with key and returns its claims.
+func verifyJWT(t *testing.T, key *rsa.PublicKey, token string) map[string]any {
+	t.Helper()
+	parts := strings.Split(token, ".")
+	require.Len(t, parts, 3)
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	require.NoError(t, err)
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	require.NoError(t, rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature))
+	data, err := base64.RawURLEncoding.DecodeString(parts[1])
+	require.NoError(t, err)
+	var claims map[string]any
+	require.NoError(t, json.Unmarshal(data, &claims))
+	return claims
+}
+
+// fakeGitHub serves the app endpoints for installations on the organization acme and the user
+// octocat, minting tokens valid for *lifetime.
+type fakeGitHub struct {
+	t             *testing.T
+	installations []map[string]any
+	lifetime      atomic.Int64
+	mints         atomic.Int32
+	lookups       atomic.Int32
+}
+
+func newFakeGitHub(t *testing.T, installations ...map[string]any) (*fakeGitHub, *httptest.Server) {
+	t.Helper()
+	f := &fakeGitHub{t: t, installations: installations}
+	f.lifetime.Store(int64(time.Hour))
+	server := httptest.NewServer(f)
+	t.Cleanup(server.Close)
+	return f, server
+}
+
+var (
+	acme    = map[string]any{"id": 1, "account": map[string]any{"login": "acme"}, "repository_selection": "selected", "permissions": map[string]any{"contents": "read"}}
+	octocat = map[string]any{"id": 2, "account": map[string]any{"login": "octocat"}, "repository_selection": "all", "permissions": map[string]any{"issues": "write"}}
+)
+
+func (f *fakeGitHub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Every app endpoint is called with a JWT of app 42
+	claims := verifyJWT(f.t, &privateKey(f.t).PublicKey, strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+	assert.Equal(f.t, "42", claims["iss"])
+
+	reply := func(v any) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(v)
+	}
+	switch {
+	case r.URL.Path == "/app/installations":
+		reply(f.installations)
+	case strings.HasSuffix(r.URL.Path, "/installation"):
+		f.lookups.Add(1)
+		for _, inst := range f.installations {
+			login := inst["account"].(map[string]any)["login"]
+			if r.URL.Path == fmt.Sprintf("/orgs/%s/installation", login) && login == "acme" ||
+				r.URL.Path == fmt.Sprintf("/users/%s/installation", login) && login == "octocat" {
+				reply(inst)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+	case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/access_tokens"):
+		n := f.mints.Add(1)
+		id := strings.Split(r.URL.Path, "/")[3]
+		w.WriteHeader(http.StatusCreated)
+		reply(map[string]any{
+			"token":       fmt.Sprintf("token-%s-%d", id, n),
+			"expires_at":  time.Now().Add(time.Duration(f.lifetime.Load())).UTC().Format(time.RFC3339),
+			"permissions": map[string]any{"contents": "write", "metadata": "read"},
+		})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newApp(t *testing.T, server *httptest.Server, defaultOwner string) *App {
+	t.Helper()
+	baseURL, err := url.Parse(server.URL + "/")
+	require.NoError(t, err)
+	app, err := New(Config{AppID: 42, PrivateKey: pkcs1PEM(privateKey(t)), DefaultOwner: defaultOwner}, baseURL, nil)
+	require.NoError(t, err)
+	return app
+}
+
+func TestApp_JWT(t *testing.T) {
+	key := privateKey(t)
+	app, err := New(Config{AppID: 42, PrivateKey: pkcs1PEM(key)}, nil, nil)
+	require.NoError(t, err)
+
+	token, err := app.JWT()
+	require.NoError(t, err)
+	claims := verifyJWT(t, &key.PublicKey, token)
+	assert.Equal(t, "42", claims["iss"])
+	now := float64(time.Now().Unix())
+	assert.InDelta(t, now-60, claims["iat"], 5)
+	assert.InDelta(t, now+9*60, claims["exp"], 5)
+}
+
+func TestNew_PrivateKeys(t *testing.T) {
+	key := privateKey(t)
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	_, err = New(Config{AppID: 42, PrivateKey: pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8})}, nil, nil)
+	assert.NoError(t, err)
+
+	_, err = New(Config{AppID: 42, PrivateKey: []byte("not a key")}, nil, nil)
+	assert.ErrorContains(t, err, "not PEM encoded")
+	_, err = New(Config{AppID: 42, PrivateKey: pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("garbage")})}, nil, nil)
+	assert.ErrorContains(t, err, "failed to parse GitHub App private key")
+	_, err = New(Config{PrivateKey: pkcs1PEM(key)}, nil, nil)
+	assert.ErrorContains(t, err, "App ID must be positive")
+}
+
+func TestTransport(t *testing.T) {
+	fake, github := newFakeGitHub(t, acme, octocat)
+	app := newApp(t, github, "acme")
+
+	type seen struct{ authorization, rateLimitKey string }
+	var requests []seen
+	var mu sync.Mutex
+	api := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {}))
+	defer api.Close()
+	base := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		requests = append(requests, seen{req.Header.Get("Authorization"), ratelimit.RequestKey(req)})
+		mu.Unlock()
+		return http.DefaultTransport.RoundTrip(req)
+	})
+	client := &http.Client{Transport: NewTransport(app, base)}
+
+	for _, path := range []string{"/repos/acme/r/issues", "/repos/ACME/other", "/users/octocat/repos", "/repos/someone/public", "/search/code"} {
+		resp, err := client.Get(api.URL + path)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+	}
+
+	assert.Equal(t, []seen{
+		{"Bearer token-1-1", "installation:1"},
+		{"Bearer token-1-1", "installation:1"},
+		{"Bearer token-2-2", "installation:2"},
+		// Owners the app is not installed on, and requests that name none, use the default
+		{"Bearer token-1-1", "installation:1"},
+		{"Bearer token-1-1", "installation:1"},
+	}, requests)
+	// acme, octocat as an organization then as a user, and someone as both, each looked up once
+	assert.Equal(t, int32(5), fake.lookups.Load())
+}
+
+func TestApp_RenewsTokens(t *testing.T) {
+	fake, github := newFakeGitHub(t, acme)
+	app := newApp(t, github, "")
+
+	// Tokens about to expire are renewed
+	fake.lifetime.Store(int64(3 * time.Minute))
+	first, id, err := app.Token(context.Background(), "acme")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), id)
+	second, _, err := app.Token(context.Background(), "acme")
+	require.NoError(t, err)
+	assert.NotEqual(t, first, second)
+
+	fake.lifetime.Store(int64(time.Hour))
+	third, _, err := app.Token(context.Background(), "acme")
+	require.NoError(t, err)
+	fourth, _, err := app.Token(context.Background(), "acme")
+	require.NoError(t, err)
+	assert.Equal(t, third, fourth)
+	assert.Equal(t, int32(3), fake.mints.Load())
+}
+
+func TestApp_ConcurrentRequestsMintOnce(t *testing.T) {
+	fake, github := newFakeGitHub(t, acme)
+	app := newApp(t, github, "")
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, err := app.Token(context.Background(), "acme")
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), fake.mints.Load())
+	assert.Equal(t, int32(1), fake.lookups.Load())
+}
+
+func TestApp_DefaultInstallation(t *testing.T) {
+	_, github := newFakeGitHub(t, acme, octocat)
+
+	// An app with several installations needs a default owner for requests that name none
+	_, _, err := newApp(t, github, "").Token(context.Background(), "")
+	assert.ErrorContains(t, err, "GitHub App 42 has several installations")
+
+	_, _, err = newApp(t, github, "someone").Token(context.Background(), "")
+	assert.ErrorContains(t, err, "GitHub App 42 is not installed on someone")
+
+	_, id, err := newApp(t, github, "octocat").Token(context.Background(), "")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), id)
+
+	// The only installation of an app is its default
+	_, single := newFakeGitHub(t, octocat)
+	_, id, err = newApp(t, single, "").Token(context.Background(), "")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), id)
+
+	_, none := newFakeGitHub(t)
+	_, _, err = newApp(t, none, "").Token(context.Background(), "")
+	assert.ErrorContains(t, err, "GitHub App 42 is not installed on any account")
+}
+
+func TestApp_Status(t *testing.T) {
+	_, github := newFakeGitHub(t, acme, octocat)
+	app := newApp(t, github, "octocat")
+
+	assert.Equal(t, Status{AppID: 42, DefaultOwner: "octocat", Installations: []InstallationStatus{}}, app.Status())
+
+	// Looking an installation up mints its token, whose permissions replace those of the
+	// installation
+	status, err := app.Installation(context.Background(), "acme")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), status.ID)
+	assert.Equal(t, "acme", status.Account)
+	assert.Equal(t, "selected", status.RepositorySelection)
+	assert.Equal(t, map[string]string{"contents": "write", "metadata": "read"}, status.Permissions)
+	require.NotNil(t, status.TokenExpiresAt)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), *status.TokenExpiresAt, time.Minute)
+	assert.False(t, status.Default)
+
+	_, _, err = app.Token(context.Background(), "")
+	require.NoError(t, err)
+	installations := app.Status().Installations
+	require.Len(t, installations, 2)
+	assert.Equal(t, "acme", installations[0].Account)
+	assert.Equal(t, "octocat", installations[1].Account)
+	assert.True(t, installations[1].Default)
+}
+
+func TestApp_ContextRoundTrip(t *testing.T) {
+	_, ok := FromContext(context.Background())
+	assert.False(t, ok)
+
+	app := &App{id: 42}
+	got, ok := FromContext(ContextWithApp(context.Background(), app))
+	assert.True(t, ok)
+	assert.Same(t, app, got)
+}
+
+func TestRequestOwner(t *testing.T) {
+	tests := []struct {
+		url      string
+		expected string
+	}{
+		{"https://api.github.com/repos/acme/r/issues", "acme"},
+		{"https://api.github.com/orgs/acme/teams", "acme"},
+		{"https://api.github.com/users/octocat", "octocat"},
+		{"https://uploads.github.com/repos/acme/r/releases/1/assets", "acme"},
+		{"https://raw.githubusercontent.com/acme/r/main/README.md", "acme"},
+		{"https://github.example.com/api/v3/repos/acme/r", "acme"},
+		{"https://github.example.com/api/uploads/repos/acme/r/releases/1/assets", "acme"},
+		{"https://github.example.com/raw/acme/r/main/README.md", "acme"},
+		{"https://api.github.com/search/code?q=acme", ""},
+		{"https://api.github.com/user", ""},
+		{"https://api.github.com/graphql", ""},
+		{"https://github.example.com/api/graphql", ""},
+	}
+	for _, tc := range tests {
+		req, err := http.NewRequest(http.MethodGet, tc.url, nil)
+		require.NoError(t, err)
+		assert.Equal(t, tc.expected, RequestOwner(req), tc.url)
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
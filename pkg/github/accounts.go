@@ -0,0 +1,65 @@
+package github
+
+import (
+	"context"
+
+	"github.com/github/github-mcp-server/pkg/accounts"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/github/github-mcp-server/pkg/utils"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// SelectAccountToolName is the name of the tool selecting the account of a session, whose
+// account parameter names the account to select rather than the one the call acts as.
+const SelectAccountToolName = "select_account"
+
+// AccountsResult reports the accounts of the server and the one the session acts as.
+type AccountsResult struct {
+	Accounts []accounts.Account `json:"accounts"`
+	Selected string             `json:"selected"`
+}
+
+// SelectAccount creates a tool to list the GitHub accounts the server can act as and to choose
+// the one the tool calls of the session act as by default.
+func SelectAccount(t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        SelectAccountToolName,
+		Description: t("TOOL_SELECT_ACCOUNT_DESCRIPTION", "List the GitHub accounts this server can act as, each with its host, and select the account that the tool calls of this session act as when they do not pass an account parameter. Without an account, reports the accounts and the one selected."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_SELECT_ACCOUNT_USER_TITLE", "Select GitHub account"),
+			ReadOnlyHint: true,
+		},
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"account": {
+					Type:        "string",
+					Description: "Name of the account to select for the rest of the session",
+				},
+			},
+		},
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, req *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		name, err := OptionalParam[string](args, "account")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		selector, ok := accounts.FromContext(ctx)
+		if !ok {
+			return utils.NewToolResultError("this server acts as a single GitHub account, configure accounts to choose between several"), nil, nil
+		}
+		if name != "" {
+			if err := selector.Select(req.Session, name); err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+		}
+		return MarshalledTextResult(AccountsResult{
+			Accounts: selector.Accounts(),
+			Selected: selector.Selected(req.Session),
+		}), nil, nil
+	})
+
+	return tool, handler
+}
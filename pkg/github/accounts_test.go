@@ -0,0 +1,48 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/accounts"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SelectAccount(t *testing.T) {
+	tool, handler := SelectAccount(translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.True(t, tool.Annotations.ReadOnlyHint)
+
+	request := createMCPRequest(map[string]any{})
+	result, _, err := handler(context.Background(), &request, map[string]any{})
+	require.NoError(t, err)
+	assert.Contains(t, getErrorResult(t, result).Text, "this server acts as a single GitHub account")
+
+	ctx := accounts.ContextWithSelector(context.Background(), accounts.NewSelector("", accounts.Account{Name: "ghes", Host: "https://github.example.com"}))
+	call := func(args map[string]any) AccountsResult {
+		t.Helper()
+		request := createMCPRequest(args)
+		result, _, err := handler(ctx, &request, args)
+		require.NoError(t, err)
+		require.False(t, result.IsError, getTextResult(t, result).Text)
+		var got AccountsResult
+		require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &got))
+		return got
+	}
+
+	assert.Equal(t, AccountsResult{
+		Accounts: []accounts.Account{{Name: "default"}, {Name: "ghes", Host: "https://github.example.com"}},
+		Selected: "default",
+	}, call(map[string]any{}))
+	assert.Equal(t, "ghes", call(map[string]any{"account": "ghes"}).Selected)
+	assert.Equal(t, "ghes", call(map[string]any{}).Selected)
+
+	request = createMCPRequest(map[string]any{"account": "work"})
+	result, _, err = handler(ctx, &request, map[string]any{"account": "work"})
+	require.NoError(t, err)
+	assert.Equal(t, `unknown account "work", expected one of: default, ghes`, getErrorResult(t, result).Text)
+}
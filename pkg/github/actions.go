@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/github/github-mcp-server/internal/profiler"
 	buffer "github.com/github/github-mcp-server/pkg/buffer"
@@ -21,6 +22,9 @@ import (
 const (
 	DescriptionRepositoryOwner = "Repository owner"
 	DescriptionRepositoryName  = "Repository name"
+
+	// defaultJobLogMaxBytes caps the log content returned per job when max_bytes is not given
+	defaultJobLogMaxBytes = 64 * 1024
 )
 
 // ListWorkflows creates a tool to list workflows in a repository
@@ -617,6 +621,11 @@ func GetJobLogs(getClient GetClientFn, t translations.TranslationHelperFunc, con
 						Description: "Number of lines to return from the end of the log",
 						Default:     json.RawMessage(`500`),
 					},
+					"max_bytes": {
+						Type:        "number",
+						Description: "Maximum size in bytes of the log content returned for each job. Longer logs keep their last bytes, starting at a line boundary",
+						Default:     json.RawMessage(`65536`),
+					},
 				},
 				Required: []string{"owner", "repo"},
 			},
@@ -656,6 +665,16 @@ func GetJobLogs(getClient GetClientFn, t translations.TranslationHelperFunc, con
 			if tailLines == 0 {
 				tailLines = 500
 			}
+			maxBytes, err := OptionalIntParam(args, "max_bytes")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			if maxBytes < 0 {
+				return utils.NewToolResultError("max_bytes must be positive"), nil, nil
+			}
+			if maxBytes == 0 {
+				maxBytes = defaultJobLogMaxBytes
+			}
 
 			client, err := getClient(ctx)
 			if err != nil {
@@ -672,10 +691,10 @@ func GetJobLogs(getClient GetClientFn, t translations.TranslationHelperFunc, con
 
 			if failedOnly && runID > 0 {
 				// Handle failed-only mode: get logs for all failed jobs in the workflow run
-				return handleFailedJobLogs(ctx, client, owner, repo, int64(runID), returnContent, tailLines, contentWindowSize)
+				return handleFailedJobLogs(ctx, client, owner, repo, int64(runID), returnContent, tailLines, maxBytes, contentWindowSize)
 			} else if jobID > 0 {
 				// Handle single job mode
-				return handleSingleJobLogs(ctx, client, owner, repo, int64(jobID), returnContent, tailLines, contentWindowSize)
+				return handleSingleJobLogs(ctx, client, owner, repo, int64(jobID), returnContent, tailLines, maxBytes, contentWindowSize)
 			}
 
 			return utils.NewToolResultError("Either job_id must be provided for single job logs, or run_id with failed_only=true for failed job logs"), nil, nil
@@ -683,7 +702,7 @@ func GetJobLogs(getClient GetClientFn, t translations.TranslationHelperFunc, con
 }
 
 // handleFailedJobLogs gets logs for all failed jobs in a workflow run
-func handleFailedJobLogs(ctx context.Context, client *github.Client, owner, repo string, runID int64, returnContent bool, tailLines int, contentWindowSize int) (*mcp.CallToolResult, any, error) {
+func handleFailedJobLogs(ctx context.Context, client *github.Client, owner, repo string, runID int64, returnContent bool, tailLines int, maxBytes int, contentWindowSize int) (*mcp.CallToolResult, any, error) {
 	// First, get all jobs for the workflow run
 	jobs, resp, err := client.Actions.ListWorkflowJobs(ctx, owner, repo, runID, &github.ListWorkflowJobsOptions{
 		Filter: "latest",
@@ -715,7 +734,7 @@ func handleFailedJobLogs(ctx context.Context, client *github.Client, owner, repo
 	// Collect logs for all failed jobs
 	var logResults []map[string]any
 	for _, job := range failedJobs {
-		jobResult, resp, err := getJobLogData(ctx, client, owner, repo, job.GetID(), job.GetName(), returnContent, tailLines, contentWindowSize)
+		jobResult, resp, err := getJobLogData(ctx, client, owner, repo, job.GetID(), job.GetName(), returnContent, tailLines, maxBytes, contentWindowSize)
 		if err != nil {
 			// Continue with other jobs even if one fails
 			jobResult = map[string]any{
@@ -748,8 +767,8 @@ func handleFailedJobLogs(ctx context.Context, client *github.Client, owner, repo
 }
 
 // handleSingleJobLogs gets logs for a single job
-func handleSingleJobLogs(ctx context.Context, client *github.Client, owner, repo string, jobID int64, returnContent bool, tailLines int, contentWindowSize int) (*mcp.CallToolResult, any, error) {
-	jobResult, resp, err := getJobLogData(ctx, client, owner, repo, jobID, "", returnContent, tailLines, contentWindowSize)
+func handleSingleJobLogs(ctx context.Context, client *github.Client, owner, repo string, jobID int64, returnContent bool, tailLines int, maxBytes int, contentWindowSize int) (*mcp.CallToolResult, any, error) {
+	jobResult, resp, err := getJobLogData(ctx, client, owner, repo, jobID, "", returnContent, tailLines, maxBytes, contentWindowSize)
 	if err != nil {
 		return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to get job logs", resp, err), nil, nil
 	}
@@ -763,7 +782,7 @@ func handleSingleJobLogs(ctx context.Context, client *github.Client, owner, repo
 }
 
 // getJobLogData retrieves log data for a single job, either as URL or content
-func getJobLogData(ctx context.Context, client *github.Client, owner, repo string, jobID int64, jobName string, returnContent bool, tailLines int, contentWindowSize int) (map[string]any, *github.Response, error) {
+func getJobLogData(ctx context.Context, client *github.Client, owner, repo string, jobID int64, jobName string, returnContent bool, tailLines int, maxBytes int, contentWindowSize int) (map[string]any, *github.Response, error) {
 	// Get the download URL for the job logs
 	url, resp, err := client.Actions.GetWorkflowJobLogs(ctx, owner, repo, jobID, 1)
 	if err != nil {
@@ -788,9 +807,14 @@ func getJobLogData(ctx context.Context, client *github.Client, owner, repo strin
 			}
 			return nil, ghRes, fmt.Errorf("failed to download log content for job %d: %w", jobID, err)
 		}
+		content, truncated := tailBytes(content, maxBytes)
 		result["logs_content"] = content
 		result["message"] = "Job logs content retrieved successfully"
 		result["original_length"] = originalLength
+		if truncated {
+			result["truncated"] = true
+			result["note"] = fmt.Sprintf("Only the last %d bytes of the requested lines are included. Raise max_bytes or lower tail_lines to see more.", maxBytes)
+		}
 	} else {
 		// Return just the URL
 		result["logs_url"] = url.String()
@@ -801,6 +825,23 @@ func getJobLogData(ctx context.Context, client *github.Client, owner, repo strin
 	return result, resp, nil
 }
 
+// tailBytes returns the end of content that fits in maxBytes, starting at a line boundary
+// where one is available, and whether anything was dropped.
+func tailBytes(content string, maxBytes int) (string, bool) {
+	if len(content) <= maxBytes {
+		return content, false
+	}
+	tail := content[len(content)-maxBytes:]
+	if i := strings.IndexByte(tail, '\n'); i >= 0 && i < len(tail)-1 {
+		return tail[i+1:], true
+	}
+	// A single line longer than maxBytes; cut it at a rune boundary instead
+	for len(tail) > 0 && !utf8.RuneStart(tail[0]) {
+		tail = tail[1:]
+	}
+	return tail, true
+}
+
 func downloadLogContent(ctx context.Context, logURL string, tailLines int, maxLines int) (string, int, *http.Response, error) {
 	prof := profiler.New(nil, profiler.IsProfilingEnabled())
 	finish := prof.Start(ctx, "log_buffer_processing")
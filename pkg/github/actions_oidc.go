@@ -0,0 +1,189 @@
+package github
+
+import (
+	"context"
+	"fmt"
+
+	ghErrors "github.com/github/github-mcp-server/pkg/errors"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/github/github-mcp-server/pkg/utils"
+	"github.com/google/go-github/v79/github"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// OIDCCustomClaims reports the OIDC subject claim template of a repository or organization.
+type OIDCCustomClaims struct {
+	// Scope is "repository" or "organization"
+	Scope string `json:"scope"`
+	Owner string `json:"owner"`
+	Repo  string `json:"repo,omitempty"`
+	// UseDefault is set when a repository uses its organization's template or GitHub's default
+	UseDefault       bool     `json:"use_default"`
+	IncludeClaimKeys []string `json:"include_claim_keys"`
+}
+
+// oidcScopeProperties are the parameters selecting a repository or an organization
+func oidcScopeProperties() map[string]*jsonschema.Schema {
+	return map[string]*jsonschema.Schema{
+		"owner": {
+			Type:        "string",
+			Description: "Organization name, or the owner of repo",
+		},
+		"repo": {
+			Type:        "string",
+			Description: "Repository name. Omit to use the organization's template",
+		},
+	}
+}
+
+// newOIDCCustomClaims returns the claims of template at the scope selected by owner and repo.
+func newOIDCCustomClaims(owner, repo string, template *github.OIDCSubjectClaimCustomTemplate) OIDCCustomClaims {
+	claims := OIDCCustomClaims{
+		Scope:            "organization",
+		Owner:            owner,
+		Repo:             repo,
+		UseDefault:       template.GetUseDefault(),
+		IncludeClaimKeys: template.IncludeClaimKeys,
+	}
+	if repo != "" {
+		claims.Scope = "repository"
+	}
+	if claims.IncludeClaimKeys == nil {
+		claims.IncludeClaimKeys = []string{}
+	}
+	return claims
+}
+
+// GetActionsOIDCCustomClaims creates a tool to get the OIDC subject claim template of a repository or organization.
+func GetActionsOIDCCustomClaims(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	return mcp.Tool{
+			Name:        "get_actions_oidc_custom_claims",
+			Description: t("TOOL_GET_ACTIONS_OIDC_CUSTOM_CLAIMS_DESCRIPTION", "Get the claim keys making up the subject (sub) of the OIDC tokens issued to GitHub Actions workflows of a repository or organization, which cloud trust policies match against"),
+			Annotations: &mcp.ToolAnnotations{
+				Title:        t("TOOL_GET_ACTIONS_OIDC_CUSTOM_CLAIMS_USER_TITLE", "Get Actions OIDC subject claims"),
+				ReadOnlyHint: true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type:       "object",
+				Properties: oidcScopeProperties(),
+				Required:   []string{"owner"},
+			},
+		},
+		func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+			owner, err := RequiredParam[string](args, "owner")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			repo, err := OptionalParam[string](args, "repo")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+
+			var template *github.OIDCSubjectClaimCustomTemplate
+			var resp *github.Response
+			if repo != "" {
+				template, resp, err = client.Actions.GetRepoOIDCSubjectClaimCustomTemplate(ctx, owner, repo)
+			} else {
+				template, resp, err = client.Actions.GetOrgOIDCSubjectClaimCustomTemplate(ctx, owner)
+			}
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to get OIDC subject claim template", resp, err), nil, nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			return MarshalledTextResult(newOIDCCustomClaims(owner, repo, template)), nil, nil
+		}
+}
+
+// SetActionsOIDCCustomClaims creates a tool to set the OIDC subject claim template of a repository or organization.
+func SetActionsOIDCCustomClaims(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	properties := oidcScopeProperties()
+	properties["include_claim_keys"] = &jsonschema.Schema{
+		Type:        "array",
+		Description: "Claim keys to build the subject from, in order, e.g. [\"repo\", \"context\", \"job_workflow_ref\"]. Required for organizations, and for repositories unless use_default is set",
+		Items: &jsonschema.Schema{
+			Type: "string",
+		},
+	}
+	properties["use_default"] = &jsonschema.Schema{
+		Type:        "boolean",
+		Description: "Repositories only: use the organization's template, or GitHub's default subject when the organization has none, instead of include_claim_keys",
+	}
+
+	return mcp.Tool{
+			Name:        "set_actions_oidc_custom_claims",
+			Description: t("TOOL_SET_ACTIONS_OIDC_CUSTOM_CLAIMS_DESCRIPTION", "Set the claim keys making up the subject (sub) of the OIDC tokens issued to GitHub Actions workflows of a repository or organization. Cloud trust policies matching the old subject stop accepting the tokens, so update them together"),
+			Annotations: &mcp.ToolAnnotations{
+				Title:        t("TOOL_SET_ACTIONS_OIDC_CUSTOM_CLAIMS_USER_TITLE", "Set Actions OIDC subject claims"),
+				ReadOnlyHint: false,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type:       "object",
+				Properties: properties,
+				Required:   []string{"owner"},
+			},
+		},
+		func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+			owner, err := RequiredParam[string](args, "owner")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			repo, err := OptionalParam[string](args, "repo")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			claimKeys, err := OptionalStringArrayParam(args, "include_claim_keys")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			useDefault, err := OptionalParam[bool](args, "use_default")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+
+			seen := make(map[string]bool, len(claimKeys))
+			for i, key := range claimKeys {
+				if key == "" {
+					return utils.NewToolResultError(fmt.Sprintf("include_claim_keys[%d] must be a non-empty string", i)), nil, nil
+				}
+				if seen[key] {
+					return utils.NewToolResultError(fmt.Sprintf("claim key %s is included more than once", key)), nil, nil
+				}
+				seen[key] = true
+			}
+			switch {
+			case repo == "" && useDefault:
+				return utils.NewToolResultError("use_default only applies to repositories; organization templates always list include_claim_keys"), nil, nil
+			case useDefault && len(claimKeys) > 0:
+				return utils.NewToolResultError("include_claim_keys cannot be combined with use_default"), nil, nil
+			case !useDefault && len(claimKeys) == 0:
+				return utils.NewToolResultError("include_claim_keys must list at least one claim key"), nil, nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+
+			template := &github.OIDCSubjectClaimCustomTemplate{IncludeClaimKeys: claimKeys}
+			var resp *github.Response
+			if repo != "" {
+				template.UseDefault = github.Ptr(useDefault)
+				resp, err = client.Actions.SetRepoOIDCSubjectClaimCustomTemplate(ctx, owner, repo, template)
+			} else {
+				resp, err = client.Actions.SetOrgOIDCSubjectClaimCustomTemplate(ctx, owner, template)
+			}
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to set OIDC subject claim template", resp, err), nil, nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			return MarshalledTextResult(newOIDCCustomClaims(owner, repo, template)), nil, nil
+		}
+}
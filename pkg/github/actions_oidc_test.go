@@ -0,0 +1,215 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v79/github"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_GetActionsOIDCCustomClaims(t *testing.T) {
+	mockClient := github.NewClient(nil)
+	tool, _ := GetActionsOIDCCustomClaims(stubGetClientFn(mockClient), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	schema, ok := tool.InputSchema.(*jsonschema.Schema)
+	require.True(t, ok, "InputSchema should be *jsonschema.Schema")
+	assert.Equal(t, "get_actions_oidc_custom_claims", tool.Name)
+	assert.True(t, tool.Annotations.ReadOnlyHint)
+	assert.Contains(t, schema.Properties, "repo")
+	assert.ElementsMatch(t, schema.Required, []string{"owner"})
+
+	tests := []struct {
+		name           string
+		mockedClient   *http.Client
+		requestArgs    map[string]any
+		expectError    bool
+		expectedErrMsg string
+		expectedClaims OIDCCustomClaims
+	}{
+		{
+			name: "repository template",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatch(
+					mock.GetReposActionsOidcCustomizationSubByOwnerByRepo,
+					&github.OIDCSubjectClaimCustomTemplate{UseDefault: github.Ptr(false), IncludeClaimKeys: []string{"repo", "context"}},
+				),
+			),
+			requestArgs: map[string]any{"owner": "corp", "repo": "deployer"},
+			expectedClaims: OIDCCustomClaims{
+				Scope:            "repository",
+				Owner:            "corp",
+				Repo:             "deployer",
+				IncludeClaimKeys: []string{"repo", "context"},
+			},
+		},
+		{
+			name: "organization template",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatch(
+					mock.GetOrgsActionsOidcCustomizationSubByOrg,
+					&github.OIDCSubjectClaimCustomTemplate{IncludeClaimKeys: []string{"repository_owner_id"}},
+				),
+			),
+			requestArgs: map[string]any{"owner": "corp"},
+			expectedClaims: OIDCCustomClaims{
+				Scope:            "organization",
+				Owner:            "corp",
+				IncludeClaimKeys: []string{"repository_owner_id"},
+			},
+		},
+		{
+			name: "API error",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.GetOrgsActionsOidcCustomizationSubByOrg,
+					mockResponse(t, http.StatusNotFound, map[string]string{"message": "Not Found"}),
+				),
+			),
+			requestArgs:    map[string]any{"owner": "corp"},
+			expectError:    true,
+			expectedErrMsg: "failed to get OIDC subject claim template",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, handler := GetActionsOIDCCustomClaims(stubGetClientFn(github.NewClient(tc.mockedClient)), translations.NullTranslationHelper)
+			request := createMCPRequest(tc.requestArgs)
+			result, _, err := handler(context.Background(), &request, tc.requestArgs)
+			require.NoError(t, err)
+
+			if tc.expectError {
+				assert.Contains(t, getErrorResult(t, result).Text, tc.expectedErrMsg)
+				return
+			}
+
+			var claims OIDCCustomClaims
+			require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &claims))
+			assert.Equal(t, tc.expectedClaims, claims)
+		})
+	}
+}
+
+func Test_SetActionsOIDCCustomClaims(t *testing.T) {
+	mockClient := github.NewClient(nil)
+	tool, _ := SetActionsOIDCCustomClaims(stubGetClientFn(mockClient), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	schema, ok := tool.InputSchema.(*jsonschema.Schema)
+	require.True(t, ok, "InputSchema should be *jsonschema.Schema")
+	assert.Equal(t, "set_actions_oidc_custom_claims", tool.Name)
+	assert.False(t, tool.Annotations.ReadOnlyHint)
+	assert.Contains(t, schema.Properties, "include_claim_keys")
+	assert.Contains(t, schema.Properties, "use_default")
+	assert.ElementsMatch(t, schema.Required, []string{"owner"})
+
+	tests := []struct {
+		name           string
+		mockedClient   *http.Client
+		requestArgs    map[string]any
+		expectError    bool
+		expectedErrMsg string
+		expectedClaims OIDCCustomClaims
+	}{
+		{
+			name: "sets repository claim keys",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.PutReposActionsOidcCustomizationSubByOwnerByRepo,
+					expectRequestBody(t, map[string]any{
+						"use_default":        false,
+						"include_claim_keys": []any{"repo", "context", "job_workflow_ref"},
+					}).andThen(mockResponse(t, http.StatusCreated, nil)),
+				),
+			),
+			requestArgs: map[string]any{"owner": "corp", "repo": "deployer", "include_claim_keys": []any{"repo", "context", "job_workflow_ref"}},
+			expectedClaims: OIDCCustomClaims{
+				Scope:            "repository",
+				Owner:            "corp",
+				Repo:             "deployer",
+				IncludeClaimKeys: []string{"repo", "context", "job_workflow_ref"},
+			},
+		},
+		{
+			name: "resets a repository to the default",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.PutReposActionsOidcCustomizationSubByOwnerByRepo,
+					expectRequestBody(t, map[string]any{"use_default": true}).andThen(mockResponse(t, http.StatusCreated, nil)),
+				),
+			),
+			requestArgs: map[string]any{"owner": "corp", "repo": "deployer", "use_default": true},
+			expectedClaims: OIDCCustomClaims{
+				Scope:            "repository",
+				Owner:            "corp",
+				Repo:             "deployer",
+				UseDefault:       true,
+				IncludeClaimKeys: []string{},
+			},
+		},
+		{
+			name: "sets organization claim keys",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.PutOrgsActionsOidcCustomizationSubByOrg,
+					expectRequestBody(t, map[string]any{"include_claim_keys": []any{"repository_owner_id", "repository_id"}}).andThen(mockResponse(t, http.StatusCreated, nil)),
+				),
+			),
+			requestArgs: map[string]any{"owner": "corp", "include_claim_keys": []any{"repository_owner_id", "repository_id"}},
+			expectedClaims: OIDCCustomClaims{
+				Scope:            "organization",
+				Owner:            "corp",
+				IncludeClaimKeys: []string{"repository_owner_id", "repository_id"},
+			},
+		},
+		{
+			name:           "organizations cannot use the default",
+			mockedClient:   mock.NewMockedHTTPClient(),
+			requestArgs:    map[string]any{"owner": "corp", "use_default": true},
+			expectError:    true,
+			expectedErrMsg: "use_default only applies to repositories",
+		},
+		{
+			name:           "claim keys are required",
+			mockedClient:   mock.NewMockedHTTPClient(),
+			requestArgs:    map[string]any{"owner": "corp", "repo": "deployer"},
+			expectError:    true,
+			expectedErrMsg: "must list at least one claim key",
+		},
+		{
+			name:           "duplicate claim keys",
+			mockedClient:   mock.NewMockedHTTPClient(),
+			requestArgs:    map[string]any{"owner": "corp", "include_claim_keys": []any{"repo", "repo"}},
+			expectError:    true,
+			expectedErrMsg: "claim key repo is included more than once",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, handler := SetActionsOIDCCustomClaims(stubGetClientFn(github.NewClient(tc.mockedClient)), translations.NullTranslationHelper)
+			request := createMCPRequest(tc.requestArgs)
+			result, _, err := handler(context.Background(), &request, tc.requestArgs)
+			require.NoError(t, err)
+
+			if tc.expectError {
+				assert.Contains(t, getErrorResult(t, result).Text, tc.expectedErrMsg)
+				return
+			}
+
+			require.False(t, result.IsError, getTextResult(t, result).Text)
+			var claims OIDCCustomClaims
+			require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &claims))
+			assert.Equal(t, tc.expectedClaims, claims)
+		})
+	}
+}
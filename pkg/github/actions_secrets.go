@@ -0,0 +1,160 @@
+package github
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+
+	ghErrors "github.com/github/github-mcp-server/pkg/errors"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/github/github-mcp-server/pkg/utils"
+	"github.com/google/go-github/v79/github"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"golang.org/x/crypto/nacl/box"
+)
+
+// encryptSecret encrypts value with a libsodium sealed box for the base64-encoded Curve25519 public key
+// of a repository, environment or organization, as the Actions secrets API requires.
+func encryptSecret(publicKey, value string) (string, error) {
+	decoded, err := base64.StdEncoding.DecodeString(publicKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode public key: %w", err)
+	}
+	if len(decoded) != 32 {
+		return "", fmt.Errorf("public key must be 32 bytes, got %d", len(decoded))
+	}
+	var key [32]byte
+	copy(key[:], decoded)
+
+	sealed, err := box.SealAnonymous(nil, []byte(value), &key, rand.Reader)
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt secret: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// SetActionsSecret creates a tool to create or update an Actions secret.
+func SetActionsSecret(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	properties := orgVisibilityProperties(actionsScopeProperties())
+	properties["name"] = &jsonschema.Schema{
+		Type:        "string",
+		Description: "Secret name",
+	}
+	properties["value"] = &jsonschema.Schema{
+		Type:        "string",
+		Description: "Secret value. It is encrypted before it is sent to GitHub and cannot be read back",
+	}
+
+	tool := mcp.Tool{
+		Name:        "set_actions_secret",
+		Description: t("TOOL_SET_ACTIONS_SECRET_DESCRIPTION", "Create or update a GitHub Actions secret of a repository, a deployment environment or an organization. The value is encrypted with the public key of the repository, environment or organization."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_SET_ACTIONS_SECRET_USER_TITLE", "Set Actions secret"),
+			ReadOnlyHint: false,
+		},
+		InputSchema: &jsonschema.Schema{
+			Type:       "object",
+			Properties: properties,
+			Required:   []string{"owner", "name", "value"},
+		},
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		scope, err := requiredActionsScope(args)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		name, err := RequiredParam[string](args, "name")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		value, err := RequiredParam[string](args, "value")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		visibility, repositoryIDs, err := optionalOrgVisibility(args, scope)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		// Organization secrets always need a visibility; keep the current one of an existing secret
+		if scope.isOrg() && visibility == "" {
+			visibility = "all"
+			existing, resp, err := client.Actions.GetOrgSecret(ctx, scope.owner, name)
+			switch {
+			case err == nil:
+				visibility = existing.Visibility
+				_ = resp.Body.Close()
+			case resp == nil || resp.StatusCode != http.StatusNotFound:
+				return ghErrors.NewGitHubAPIErrorResponse(ctx, fmt.Sprintf("failed to get secret %s of %s", name, scope), resp, err), nil, nil
+			}
+		}
+
+		// The environment endpoints address the repository by ID
+		var repoID int
+		if scope.environment != "" {
+			repository, resp, err := client.Repositories.Get(ctx, scope.owner, scope.repo)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to get repository", resp, err), nil, nil
+			}
+			_ = resp.Body.Close()
+			repoID = int(repository.GetID())
+		}
+
+		var publicKey *github.PublicKey
+		var resp *github.Response
+		switch {
+		case scope.environment != "":
+			publicKey, resp, err = client.Actions.GetEnvPublicKey(ctx, repoID, scope.environment)
+		case scope.repo != "":
+			publicKey, resp, err = client.Actions.GetRepoPublicKey(ctx, scope.owner, scope.repo)
+		default:
+			publicKey, resp, err = client.Actions.GetOrgPublicKey(ctx, scope.owner)
+		}
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, fmt.Sprintf("failed to get public key of %s", scope), resp, err), nil, nil
+		}
+		_ = resp.Body.Close()
+
+		encrypted, err := encryptSecret(publicKey.GetKey(), value)
+		if err != nil {
+			return nil, nil, err
+		}
+		secret := &github.EncryptedSecret{
+			Name:                  name,
+			KeyID:                 publicKey.GetKeyID(),
+			EncryptedValue:        encrypted,
+			Visibility:            visibility,
+			SelectedRepositoryIDs: repositoryIDs,
+		}
+
+		switch {
+		case scope.environment != "":
+			resp, err = client.Actions.CreateOrUpdateEnvSecret(ctx, repoID, scope.environment, secret)
+		case scope.repo != "":
+			resp, err = client.Actions.CreateOrUpdateRepoSecret(ctx, scope.owner, scope.repo, secret)
+		default:
+			resp, err = client.Actions.CreateOrUpdateOrgSecret(ctx, scope.owner, secret)
+		}
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, fmt.Sprintf("failed to set secret %s of %s", name, scope), resp, err), nil, nil
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		action := "updated"
+		if resp.StatusCode == http.StatusCreated {
+			action = "created"
+		}
+		return utils.NewToolResultText(fmt.Sprintf("Secret %s %s in %s", name, action, scope)), nil, nil
+	})
+
+	return tool, handler
+}
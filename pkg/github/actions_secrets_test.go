@@ -0,0 +1,159 @@
+package github
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v79/github"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/nacl/box"
+)
+
+func Test_encryptSecret(t *testing.T) {
+	publicKey, privateKey, err := box.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	encrypted, err := encryptSecret(base64.StdEncoding.EncodeToString(publicKey[:]), "hunter2")
+	require.NoError(t, err)
+
+	sealed, err := base64.StdEncoding.DecodeString(encrypted)
+	require.NoError(t, err)
+	decrypted, ok := box.OpenAnonymous(nil, sealed, publicKey, privateKey)
+	require.True(t, ok)
+	assert.Equal(t, "hunter2", string(decrypted))
+
+	_, err = encryptSecret("not a key", "hunter2")
+	assert.ErrorContains(t, err, "failed to decode public key")
+	_, err = encryptSecret(base64.StdEncoding.EncodeToString([]byte("short")), "hunter2")
+	assert.ErrorContains(t, err, "public key must be 32 bytes")
+}
+
+func Test_SetActionsSecret(t *testing.T) {
+	tool, _ := SetActionsSecret(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	publicKey, privateKey, err := box.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	key := &github.PublicKey{KeyID: github.Ptr("key-1"), Key: github.Ptr(base64.StdEncoding.EncodeToString(publicKey[:]))}
+
+	// expectSecret checks that the secret is encrypted with key and has the given visibility
+	expectSecret := func(t *testing.T, visibility string, status int) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			var body map[string]any
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, "key-1", body["key_id"])
+			if visibility == "" {
+				assert.NotContains(t, body, "visibility")
+			} else {
+				assert.Equal(t, visibility, body["visibility"])
+			}
+
+			sealed, err := base64.StdEncoding.DecodeString(body["encrypted_value"].(string))
+			require.NoError(t, err)
+			decrypted, ok := box.OpenAnonymous(nil, sealed, publicKey, privateKey)
+			require.True(t, ok)
+			assert.Equal(t, "hunter2", string(decrypted))
+			w.WriteHeader(status)
+		}
+	}
+
+	tests := []struct {
+		name         string
+		args         map[string]any
+		mockedClient func(t *testing.T) *http.Client
+		expectError  string
+		expectText   string
+	}{
+		{
+			name: "create repository secret",
+			args: map[string]any{"owner": "owner", "repo": "repo", "name": "TOKEN", "value": "hunter2"},
+			mockedClient: func(t *testing.T) *http.Client {
+				return mock.NewMockedHTTPClient(
+					mock.WithRequestMatchHandler(mock.GetReposActionsSecretsPublicKeyByOwnerByRepo, mockResponse(t, http.StatusOK, key)),
+					mock.WithRequestMatchHandler(
+						mock.PutReposActionsSecretsByOwnerByRepoBySecretName,
+						expectPath(t, "/repos/owner/repo/actions/secrets/TOKEN").andThen(expectSecret(t, "", http.StatusCreated)),
+					),
+				)
+			},
+			expectText: "Secret TOKEN created in repository owner/repo",
+		},
+		{
+			name: "update environment secret",
+			args: map[string]any{"owner": "owner", "repo": "repo", "environment": "production", "name": "TOKEN", "value": "hunter2"},
+			mockedClient: func(t *testing.T) *http.Client {
+				return mock.NewMockedHTTPClient(
+					mock.WithRequestMatchHandler(mock.GetReposByOwnerByRepo, mockResponse(t, http.StatusOK, &github.Repository{ID: github.Ptr(int64(42))})),
+					mock.WithRequestMatchHandler(
+						mock.EndpointPattern{Pattern: "/repositories/{repository_id}/environments/{environment_name}/secrets/public-key", Method: "GET"},
+						expectPath(t, "/repositories/42/environments/production/secrets/public-key").andThen(mockResponse(t, http.StatusOK, key)),
+					),
+					mock.WithRequestMatchHandler(
+						mock.EndpointPattern{Pattern: "/repositories/{repository_id}/environments/{environment_name}/secrets/{secret_name}", Method: "PUT"},
+						expectPath(t, "/repositories/42/environments/production/secrets/TOKEN").andThen(expectSecret(t, "", http.StatusNoContent)),
+					),
+				)
+			},
+			expectText: "Secret TOKEN updated in environment production of owner/repo",
+		},
+		{
+			name: "update organization secret keeps its visibility",
+			args: map[string]any{"owner": "octo-org", "name": "TOKEN", "value": "hunter2"},
+			mockedClient: func(t *testing.T) *http.Client {
+				return mock.NewMockedHTTPClient(
+					mock.WithRequestMatchHandler(mock.GetOrgsActionsSecretsPublicKeyByOrg, mockResponse(t, http.StatusOK, key)),
+					mock.WithRequestMatchHandler(mock.GetOrgsActionsSecretsByOrgBySecretName, mockResponse(t, http.StatusOK, &github.Secret{Name: "TOKEN", Visibility: "private"})),
+					mock.WithRequestMatchHandler(mock.PutOrgsActionsSecretsByOrgBySecretName, expectSecret(t, "private", http.StatusNoContent)),
+				)
+			},
+			expectText: "Secret TOKEN updated in organization octo-org",
+		},
+		{
+			name: "create organization secret visible to all repositories",
+			args: map[string]any{"owner": "octo-org", "name": "TOKEN", "value": "hunter2"},
+			mockedClient: func(t *testing.T) *http.Client {
+				return mock.NewMockedHTTPClient(
+					mock.WithRequestMatchHandler(mock.GetOrgsActionsSecretsPublicKeyByOrg, mockResponse(t, http.StatusOK, key)),
+					mock.WithRequestMatchHandler(mock.GetOrgsActionsSecretsByOrgBySecretName, mockResponse(t, http.StatusNotFound, map[string]string{"message": "Not Found"})),
+					mock.WithRequestMatchHandler(mock.PutOrgsActionsSecretsByOrgBySecretName, expectSecret(t, "all", http.StatusCreated)),
+				)
+			},
+			expectText: "Secret TOKEN created in organization octo-org",
+		},
+		{
+			name:        "selected repositories of repository secret",
+			args:        map[string]any{"owner": "owner", "repo": "repo", "name": "TOKEN", "value": "hunter2", "selected_repository_ids": []any{"1"}},
+			expectError: "visibility and selected_repository_ids only apply to organizations",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var client *http.Client
+			if tc.mockedClient != nil {
+				client = tc.mockedClient(t)
+			}
+			_, handler := SetActionsSecret(stubGetClientFn(github.NewClient(client)), translations.NullTranslationHelper)
+			request := createMCPRequest(tc.args)
+			result, _, err := handler(context.Background(), &request, tc.args)
+			require.NoError(t, err)
+
+			if tc.expectError != "" {
+				require.True(t, result.IsError)
+				assert.Contains(t, getErrorResult(t, result).Text, tc.expectError)
+				return
+			}
+			require.False(t, result.IsError, getTextResult(t, result).Text)
+			assert.Equal(t, tc.expectText, getTextResult(t, result).Text)
+			assert.NotContains(t, getTextResult(t, result).Text, "hunter2")
+		})
+	}
+}
@@ -1253,6 +1253,75 @@ func Test_GetJobLogs_WithContentReturnAndLargeTailLines(t *testing.T) {
 	assert.NotContains(t, response, "logs_url")
 }
 
+func Test_GetJobLogs_WithContentReturnAndMaxBytes(t *testing.T) {
+	logContent := "Line 1\nLine 2\nLine 3\nLine 4"
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(logContent))
+	}))
+	defer testServer.Close()
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.GetReposActionsJobsLogsByOwnerByRepoByJobId,
+			http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Location", testServer.URL)
+				w.WriteHeader(http.StatusFound)
+			}),
+		),
+	)
+
+	client := github.NewClient(mockedClient)
+	_, handler := GetJobLogs(stubGetClientFn(client), translations.NullTranslationHelper, 5000)
+
+	args := map[string]any{
+		"owner":          "owner",
+		"repo":           "repo",
+		"job_id":         float64(123),
+		"return_content": true,
+		"max_bytes":      float64(16),
+	}
+	request := createMCPRequest(args)
+
+	result, _, err := handler(context.Background(), &request, args)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+
+	textContent := getTextResult(t, result)
+	var response map[string]any
+	err = json.Unmarshal([]byte(textContent.Text), &response)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Line 3\nLine 4", response["logs_content"])
+	assert.Equal(t, true, response["truncated"])
+	assert.Equal(t, float64(4), response["original_length"])
+	assert.Contains(t, response["note"], "last 16 bytes")
+}
+
+func Test_tailBytes(t *testing.T) {
+	tests := []struct {
+		name          string
+		content       string
+		maxBytes      int
+		expected      string
+		wantTruncated bool
+	}{
+		{name: "fits", content: "a\nb", maxBytes: 3, expected: "a\nb"},
+		{name: "starts at a line boundary", content: "first\nsecond\nthird", maxBytes: 10, expected: "third", wantTruncated: true},
+		{name: "single long line", content: "0123456789", maxBytes: 4, expected: "6789", wantTruncated: true},
+		{name: "does not split runes", content: "héllo", maxBytes: 4, expected: "llo", wantTruncated: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, truncated := tailBytes(tc.content, tc.maxBytes)
+			assert.Equal(t, tc.expected, got)
+			assert.Equal(t, tc.wantTruncated, truncated)
+		})
+	}
+}
+
 func Test_MemoryUsage_SlidingWindow_vs_NoWindow(t *testing.T) {
 	if testing.Short() {
 		t.Skip("Skipping memory profiling test in short mode")
@@ -0,0 +1,348 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	ghErrors "github.com/github/github-mcp-server/pkg/errors"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/github/github-mcp-server/pkg/utils"
+	"github.com/google/go-github/v79/github"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// actionsScope selects the repository, deployment environment or organization that Actions variables
+// and secrets belong to.
+type actionsScope struct {
+	owner       string
+	repo        string
+	environment string
+}
+
+func (s actionsScope) isOrg() bool {
+	return s.repo == ""
+}
+
+func (s actionsScope) String() string {
+	switch {
+	case s.environment != "":
+		return fmt.Sprintf("environment %s of %s/%s", s.environment, s.owner, s.repo)
+	case s.repo != "":
+		return fmt.Sprintf("repository %s/%s", s.owner, s.repo)
+	default:
+		return fmt.Sprintf("organization %s", s.owner)
+	}
+}
+
+// actionsScopeProperties are the parameters selecting a repository, a deployment environment or an organization
+func actionsScopeProperties() map[string]*jsonschema.Schema {
+	return map[string]*jsonschema.Schema{
+		"owner": {
+			Type:        "string",
+			Description: "Organization name, or the owner of repo",
+		},
+		"repo": {
+			Type:        "string",
+			Description: "Repository name. Omit to use the organization's",
+		},
+		"environment": {
+			Type:        "string",
+			Description: "Deployment environment of repo. Omit to use the repository's",
+		},
+	}
+}
+
+// orgVisibilityProperties adds the parameters choosing the repositories that can use an organization
+// variable or secret to properties.
+func orgVisibilityProperties(properties map[string]*jsonschema.Schema) map[string]*jsonschema.Schema {
+	properties["visibility"] = &jsonschema.Schema{
+		Type:        "string",
+		Description: "Organizations only: the repositories that can use it. Defaults to 'all' when creating",
+		Enum:        []any{"all", "private", "selected"},
+	}
+	properties["selected_repository_ids"] = &jsonschema.Schema{
+		Type:        "array",
+		Description: "Organizations only: IDs of the repositories that can use it when visibility is 'selected' (e.g. [\"1296269\"])",
+		Items: &jsonschema.Schema{
+			Type: "string",
+		},
+	}
+	return properties
+}
+
+func requiredActionsScope(args map[string]any) (actionsScope, error) {
+	var scope actionsScope
+	var err error
+	if scope.owner, err = RequiredParam[string](args, "owner"); err != nil {
+		return scope, err
+	}
+	if scope.repo, err = OptionalParam[string](args, "repo"); err != nil {
+		return scope, err
+	}
+	if scope.environment, err = OptionalParam[string](args, "environment"); err != nil {
+		return scope, err
+	}
+	if scope.environment != "" && scope.repo == "" {
+		return scope, fmt.Errorf("repo is required with environment")
+	}
+	return scope, nil
+}
+
+// optionalOrgVisibility reads the parameters of orgVisibilityProperties, which only apply to organizations.
+func optionalOrgVisibility(args map[string]any, scope actionsScope) (string, []int64, error) {
+	visibility, err := OptionalParam[string](args, "visibility")
+	if err != nil {
+		return "", nil, err
+	}
+	repositoryIDs, err := OptionalBigIntArrayParam(args, "selected_repository_ids")
+	if err != nil {
+		return "", nil, err
+	}
+	if !scope.isOrg() && (visibility != "" || len(repositoryIDs) > 0) {
+		return "", nil, fmt.Errorf("visibility and selected_repository_ids only apply to organizations")
+	}
+	if len(repositoryIDs) > 0 && visibility != "selected" {
+		return "", nil, fmt.Errorf("selected_repository_ids requires visibility 'selected'")
+	}
+	if visibility == "selected" && len(repositoryIDs) == 0 {
+		return "", nil, fmt.Errorf("visibility 'selected' requires selected_repository_ids")
+	}
+	return visibility, repositoryIDs, nil
+}
+
+// MinimalActionsVariable is the output type for Actions variables.
+type MinimalActionsVariable struct {
+	Name       string `json:"name"`
+	Value      string `json:"value"`
+	Visibility string `json:"visibility,omitempty"`
+	UpdatedAt  string `json:"updated_at,omitempty"`
+}
+
+// ListActionsVariables creates a tool to list the Actions variables of a repository, environment or organization.
+func ListActionsVariables(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "list_actions_variables",
+		Description: t("TOOL_LIST_ACTIONS_VARIABLES_DESCRIPTION", "List the GitHub Actions configuration variables of a repository, a deployment environment or an organization, with their values."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_LIST_ACTIONS_VARIABLES_USER_TITLE", "List Actions variables"),
+			ReadOnlyHint: true,
+		},
+		InputSchema: WithPagination(&jsonschema.Schema{
+			Type:       "object",
+			Properties: actionsScopeProperties(),
+			Required:   []string{"owner"},
+		}),
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		scope, err := requiredActionsScope(args)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		pagination, err := OptionalPaginationParams(args)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		opts := &github.ListOptions{Page: pagination.Page, PerPage: pagination.PerPage}
+		var variables *github.ActionsVariables
+		var resp *github.Response
+		switch {
+		case scope.environment != "":
+			variables, resp, err = client.Actions.ListEnvVariables(ctx, scope.owner, scope.repo, scope.environment, opts)
+		case scope.repo != "":
+			variables, resp, err = client.Actions.ListRepoVariables(ctx, scope.owner, scope.repo, opts)
+		default:
+			variables, resp, err = client.Actions.ListOrgVariables(ctx, scope.owner, opts)
+		}
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, fmt.Sprintf("failed to list variables of %s", scope), resp, err), nil, nil
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		result := make([]MinimalActionsVariable, 0, len(variables.Variables))
+		for _, variable := range variables.Variables {
+			m := MinimalActionsVariable{
+				Name:       variable.Name,
+				Value:      variable.Value,
+				Visibility: variable.GetVisibility(),
+			}
+			if variable.UpdatedAt != nil {
+				m.UpdatedAt = variable.UpdatedAt.Format(time.RFC3339)
+			}
+			result = append(result, m)
+		}
+
+		return MarshalledTextResult(map[string]any{
+			"total_count": variables.TotalCount,
+			"variables":   result,
+		}), nil, nil
+	})
+
+	return tool, handler
+}
+
+// SetActionsVariable creates a tool to create or update an Actions variable.
+func SetActionsVariable(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	properties := orgVisibilityProperties(actionsScopeProperties())
+	properties["name"] = &jsonschema.Schema{
+		Type:        "string",
+		Description: "Variable name",
+	}
+	properties["value"] = &jsonschema.Schema{
+		Type:        "string",
+		Description: "Variable value. Variables are not encrypted; use set_actions_secret for sensitive values",
+	}
+
+	tool := mcp.Tool{
+		Name:        "set_actions_variable",
+		Description: t("TOOL_SET_ACTIONS_VARIABLE_DESCRIPTION", "Create or update a GitHub Actions configuration variable of a repository, a deployment environment or an organization."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_SET_ACTIONS_VARIABLE_USER_TITLE", "Set Actions variable"),
+			ReadOnlyHint: false,
+		},
+		InputSchema: &jsonschema.Schema{
+			Type:       "object",
+			Properties: properties,
+			Required:   []string{"owner", "name", "value"},
+		},
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		scope, err := requiredActionsScope(args)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		name, err := RequiredParam[string](args, "name")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		// An empty value is valid, so only require the parameter to be present
+		if _, ok := args["value"]; !ok {
+			return utils.NewToolResultError("missing required parameter: value"), nil, nil
+		}
+		value, err := OptionalParam[string](args, "value")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		visibility, repositoryIDs, err := optionalOrgVisibility(args, scope)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		variable := &github.ActionsVariable{Name: name, Value: value}
+		if visibility != "" {
+			variable.Visibility = github.Ptr(visibility)
+		}
+		if len(repositoryIDs) > 0 {
+			variable.SelectedRepositoryIDs = (*github.SelectedRepoIDs)(&repositoryIDs)
+		}
+
+		// Update the variable, and create it when it does not exist yet
+		var resp *github.Response
+		switch {
+		case scope.environment != "":
+			resp, err = client.Actions.UpdateEnvVariable(ctx, scope.owner, scope.repo, scope.environment, variable)
+		case scope.repo != "":
+			resp, err = client.Actions.UpdateRepoVariable(ctx, scope.owner, scope.repo, variable)
+		default:
+			resp, err = client.Actions.UpdateOrgVariable(ctx, scope.owner, variable)
+		}
+		action := "updated"
+		var ghErr *github.ErrorResponse
+		if errors.As(err, &ghErr) && ghErr.Response.StatusCode == http.StatusNotFound {
+			action = "created"
+			switch {
+			case scope.environment != "":
+				resp, err = client.Actions.CreateEnvVariable(ctx, scope.owner, scope.repo, scope.environment, variable)
+			case scope.repo != "":
+				resp, err = client.Actions.CreateRepoVariable(ctx, scope.owner, scope.repo, variable)
+			default:
+				if variable.Visibility == nil {
+					variable.Visibility = github.Ptr("all")
+				}
+				resp, err = client.Actions.CreateOrgVariable(ctx, scope.owner, variable)
+			}
+		}
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, fmt.Sprintf("failed to set variable %s of %s", name, scope), resp, err), nil, nil
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		return utils.NewToolResultText(fmt.Sprintf("Variable %s %s in %s", name, action, scope)), nil, nil
+	})
+
+	return tool, handler
+}
+
+// DeleteActionsVariable creates a tool to delete an Actions variable.
+func DeleteActionsVariable(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	properties := actionsScopeProperties()
+	properties["name"] = &jsonschema.Schema{
+		Type:        "string",
+		Description: "Variable name",
+	}
+
+	tool := mcp.Tool{
+		Name:        "delete_actions_variable",
+		Description: t("TOOL_DELETE_ACTIONS_VARIABLE_DESCRIPTION", "Delete a GitHub Actions configuration variable of a repository, a deployment environment or an organization."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:           t("TOOL_DELETE_ACTIONS_VARIABLE_USER_TITLE", "Delete Actions variable"),
+			ReadOnlyHint:    false,
+			DestructiveHint: jsonschema.Ptr(true),
+		},
+		InputSchema: &jsonschema.Schema{
+			Type:       "object",
+			Properties: properties,
+			Required:   []string{"owner", "name"},
+		},
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		scope, err := requiredActionsScope(args)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		name, err := RequiredParam[string](args, "name")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		var resp *github.Response
+		switch {
+		case scope.environment != "":
+			resp, err = client.Actions.DeleteEnvVariable(ctx, scope.owner, scope.repo, scope.environment, name)
+		case scope.repo != "":
+			resp, err = client.Actions.DeleteRepoVariable(ctx, scope.owner, scope.repo, name)
+		default:
+			resp, err = client.Actions.DeleteOrgVariable(ctx, scope.owner, name)
+		}
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, fmt.Sprintf("failed to delete variable %s of %s", name, scope), resp, err), nil, nil
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		return utils.NewToolResultText(fmt.Sprintf("Variable %s deleted from %s", name, scope)), nil, nil
+	})
+
+	return tool, handler
+}
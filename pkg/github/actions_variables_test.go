@@ -0,0 +1,217 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v79/github"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ListActionsVariables(t *testing.T) {
+	tool, _ := ListActionsVariables(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.True(t, tool.Annotations.ReadOnlyHint)
+
+	tests := []struct {
+		name         string
+		args         map[string]any
+		mockedClient *http.Client
+		expectError  string
+	}{
+		{
+			name: "repository",
+			args: map[string]any{"owner": "owner", "repo": "repo"},
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.GetReposActionsVariablesByOwnerByRepo,
+					mockResponse(t, http.StatusOK, &github.ActionsVariables{TotalCount: 1, Variables: []*github.ActionsVariable{{Name: "REGION", Value: "eu-west-1"}}}),
+				),
+			),
+		},
+		{
+			name: "environment",
+			args: map[string]any{"owner": "owner", "repo": "repo", "environment": "production"},
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.GetReposEnvironmentsVariablesByOwnerByRepoByEnvironmentName,
+					expectPath(t, "/repos/owner/repo/environments/production/variables").andThen(
+						mockResponse(t, http.StatusOK, &github.ActionsVariables{TotalCount: 1, Variables: []*github.ActionsVariable{{Name: "REGION", Value: "eu-west-1"}}}),
+					),
+				),
+			),
+		},
+		{
+			name: "organization",
+			args: map[string]any{"owner": "octo-org"},
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.GetOrgsActionsVariablesByOrg,
+					mockResponse(t, http.StatusOK, &github.ActionsVariables{TotalCount: 1, Variables: []*github.ActionsVariable{{Name: "REGION", Value: "eu-west-1", Visibility: github.Ptr("all")}}}),
+				),
+			),
+		},
+		{
+			name:        "environment without repo",
+			args:        map[string]any{"owner": "owner", "environment": "production"},
+			expectError: "repo is required with environment",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, handler := ListActionsVariables(stubGetClientFn(github.NewClient(tc.mockedClient)), translations.NullTranslationHelper)
+			request := createMCPRequest(tc.args)
+			result, _, err := handler(context.Background(), &request, tc.args)
+			require.NoError(t, err)
+
+			if tc.expectError != "" {
+				require.True(t, result.IsError)
+				assert.Contains(t, getErrorResult(t, result).Text, tc.expectError)
+				return
+			}
+			require.False(t, result.IsError, getTextResult(t, result).Text)
+
+			var response struct {
+				TotalCount int                      `json:"total_count"`
+				Variables  []MinimalActionsVariable `json:"variables"`
+			}
+			require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &response))
+			assert.Equal(t, 1, response.TotalCount)
+			require.Len(t, response.Variables, 1)
+			assert.Equal(t, "REGION", response.Variables[0].Name)
+			assert.Equal(t, "eu-west-1", response.Variables[0].Value)
+		})
+	}
+}
+
+func Test_SetActionsVariable(t *testing.T) {
+	tool, _ := SetActionsVariable(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	notFound := mockResponse(t, http.StatusNotFound, map[string]string{"message": "Not Found"})
+
+	tests := []struct {
+		name         string
+		args         map[string]any
+		mockedClient *http.Client
+		expectError  string
+		expectText   string
+	}{
+		{
+			name: "update repository variable",
+			args: map[string]any{"owner": "owner", "repo": "repo", "name": "REGION", "value": "eu-west-1"},
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.PatchReposActionsVariablesByOwnerByRepoByName,
+					expectRequestBody(t, map[string]any{"name": "REGION", "value": "eu-west-1"}).andThen(
+						http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusNoContent) }),
+					),
+				),
+			),
+			expectText: "Variable REGION updated in repository owner/repo",
+		},
+		{
+			name: "create environment variable",
+			args: map[string]any{"owner": "owner", "repo": "repo", "environment": "production", "name": "REGION", "value": ""},
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(mock.PatchReposEnvironmentsVariablesByOwnerByRepoByEnvironmentNameByName, notFound),
+				mock.WithRequestMatchHandler(
+					mock.PostReposEnvironmentsVariablesByOwnerByRepoByEnvironmentName,
+					expectRequestBody(t, map[string]any{"name": "REGION", "value": ""}).andThen(
+						mockResponse(t, http.StatusCreated, nil),
+					),
+				),
+			),
+			expectText: "Variable REGION created in environment production of owner/repo",
+		},
+		{
+			name: "create organization variable visible to all repositories",
+			args: map[string]any{"owner": "octo-org", "name": "REGION", "value": "eu-west-1"},
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(mock.PatchOrgsActionsVariablesByOrgByName, notFound),
+				mock.WithRequestMatchHandler(
+					mock.PostOrgsActionsVariablesByOrg,
+					expectRequestBody(t, map[string]any{"name": "REGION", "value": "eu-west-1", "visibility": "all"}).andThen(
+						mockResponse(t, http.StatusCreated, nil),
+					),
+				),
+			),
+			expectText: "Variable REGION created in organization octo-org",
+		},
+		{
+			name: "update organization variable for selected repositories",
+			args: map[string]any{"owner": "octo-org", "name": "REGION", "value": "eu-west-1", "visibility": "selected", "selected_repository_ids": []any{"1", "2"}},
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.PatchOrgsActionsVariablesByOrgByName,
+					expectRequestBody(t, map[string]any{"name": "REGION", "value": "eu-west-1", "visibility": "selected", "selected_repository_ids": []any{float64(1), float64(2)}}).andThen(
+						http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusNoContent) }),
+					),
+				),
+			),
+			expectText: "Variable REGION updated in organization octo-org",
+		},
+		{
+			name:        "visibility of repository variable",
+			args:        map[string]any{"owner": "owner", "repo": "repo", "name": "REGION", "value": "eu-west-1", "visibility": "all"},
+			expectError: "visibility and selected_repository_ids only apply to organizations",
+		},
+		{
+			name:        "selected visibility without repositories",
+			args:        map[string]any{"owner": "octo-org", "name": "REGION", "value": "eu-west-1", "visibility": "selected"},
+			expectError: "visibility 'selected' requires selected_repository_ids",
+		},
+		{
+			name:        "missing value",
+			args:        map[string]any{"owner": "owner", "repo": "repo", "name": "REGION"},
+			expectError: "missing required parameter: value",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, handler := SetActionsVariable(stubGetClientFn(github.NewClient(tc.mockedClient)), translations.NullTranslationHelper)
+			request := createMCPRequest(tc.args)
+			result, _, err := handler(context.Background(), &request, tc.args)
+			require.NoError(t, err)
+
+			if tc.expectError != "" {
+				require.True(t, result.IsError)
+				assert.Contains(t, getErrorResult(t, result).Text, tc.expectError)
+				return
+			}
+			require.False(t, result.IsError, getTextResult(t, result).Text)
+			assert.Equal(t, tc.expectText, getTextResult(t, result).Text)
+		})
+	}
+}
+
+func Test_DeleteActionsVariable(t *testing.T) {
+	tool, _ := DeleteActionsVariable(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.True(t, *tool.Annotations.DestructiveHint)
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.DeleteReposActionsVariablesByOwnerByRepoByName,
+			expectPath(t, "/repos/owner/repo/actions/variables/REGION").andThen(
+				http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusNoContent) }),
+			),
+		),
+	)
+	_, handler := DeleteActionsVariable(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+	args := map[string]any{"owner": "owner", "repo": "repo", "name": "REGION"}
+	request := createMCPRequest(args)
+	result, _, err := handler(context.Background(), &request, args)
+	require.NoError(t, err)
+	require.False(t, result.IsError, getTextResult(t, result).Text)
+	assert.Equal(t, "Variable REGION deleted from repository owner/repo", getTextResult(t, result).Text)
+}
@@ -0,0 +1,54 @@
+package github
+
+import (
+	"context"
+
+	"github.com/github/github-mcp-server/pkg/ghapp"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/github/github-mcp-server/pkg/utils"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// GetAppInstallations creates a tool to report the GitHub App installations this server
+// authenticates as, with the permissions of their tokens.
+func GetAppInstallations(t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "get_app_installations",
+		Description: t("TOOL_GET_APP_INSTALLATIONS_DESCRIPTION", "Get the GitHub App installations this server authenticates as when it runs as a GitHub App: the account of each installation, its repository selection, the permissions of its token and when the token expires. Requests to an owner use the installation on that owner, or the default installation if the app is not installed there. Pass an owner to check which installation, and so which permissions, its requests would use."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_GET_APP_INSTALLATIONS_USER_TITLE", "Get GitHub App installations"),
+			ReadOnlyHint: true,
+		},
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"owner": {
+					Type:        "string",
+					Description: "Repository owner, organization or user whose installation to report. Reports every installation used so far when omitted.",
+				},
+			},
+		},
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		owner, err := OptionalParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		app, ok := ghapp.FromContext(ctx)
+		if !ok {
+			return utils.NewToolResultError("this server authenticates with a token, not as a GitHub App"), nil, nil
+		}
+		if owner == "" {
+			return MarshalledTextResult(app.Status()), nil, nil
+		}
+		installation, err := app.Installation(ctx, owner)
+		if err != nil {
+			return utils.NewToolResultErrorFromErr("failed to get the GitHub App installation", err), nil, nil
+		}
+		return MarshalledTextResult(installation), nil, nil
+	})
+
+	return tool, handler
+}
@@ -0,0 +1,88 @@
+package github
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/ghapp"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_GetAppInstallations(t *testing.T) {
+	tool, handler := GetAppInstallations(translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.True(t, tool.Annotations.ReadOnlyHint)
+
+	request := createMCPRequest(map[string]any{})
+	result, _, err := handler(context.Background(), &request, map[string]any{})
+	require.NoError(t, err)
+	assert.Equal(t, "this server authenticates with a token, not as a GitHub App", getErrorResult(t, result).Text)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/orgs/acme/installation":
+			_, _ = w.Write([]byte(`{"id": 7, "account": {"login": "acme"}, "repository_selection": "all"}`))
+		case "/app/installations/7/access_tokens":
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"token":       "ghs_token",
+				"expires_at":  time.Now().Add(time.Hour).UTC().Format(time.RFC3339),
+				"permissions": map[string]string{"contents": "write"},
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	baseURL, err := url.Parse(server.URL + "/")
+	require.NoError(t, err)
+	app, err := ghapp.New(ghapp.Config{
+		AppID:        42,
+		PrivateKey:   pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}),
+		DefaultOwner: "acme",
+	}, baseURL, nil)
+	require.NoError(t, err)
+	ctx := ghapp.ContextWithApp(context.Background(), app)
+
+	request = createMCPRequest(map[string]any{"owner": "acme"})
+	result, _, err = handler(ctx, &request, map[string]any{"owner": "acme"})
+	require.NoError(t, err)
+	require.False(t, result.IsError, getTextResult(t, result).Text)
+	var installation ghapp.InstallationStatus
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &installation))
+	assert.Equal(t, int64(7), installation.ID)
+	assert.Equal(t, "acme", installation.Account)
+	assert.Equal(t, map[string]string{"contents": "write"}, installation.Permissions)
+	assert.NotNil(t, installation.TokenExpiresAt)
+
+	request = createMCPRequest(map[string]any{})
+	result, _, err = handler(ctx, &request, map[string]any{})
+	require.NoError(t, err)
+	var status ghapp.Status
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &status))
+	assert.Equal(t, int64(42), status.AppID)
+	require.Len(t, status.Installations, 1)
+	assert.Equal(t, "acme", status.Installations[0].Account)
+
+	// Owners the app cannot look up are reported as errors
+	server.Close()
+	request = createMCPRequest(map[string]any{"owner": "other"})
+	result, _, err = handler(ctx, &request, map[string]any{"owner": "other"})
+	require.NoError(t, err)
+	assert.Contains(t, getErrorResult(t, result).Text, "failed to get the GitHub App installation")
+}
@@ -0,0 +1,277 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	ghErrors "github.com/github/github-mcp-server/pkg/errors"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/github/github-mcp-server/pkg/utils"
+	"github.com/google/go-github/v79/github"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	// maxBackportTargets bounds the release branches of one backport_pull_request call
+	maxBackportTargets = 10
+	// maxBackportCommits bounds the commits cherry-picked from one pull request
+	maxBackportCommits = 250
+)
+
+// Backport states reported in BackportResult.State
+const (
+	BackportStateCreated  = "created"
+	BackportStateConflict = "conflict"
+	BackportStateFailed   = "failed"
+)
+
+// BackportResult reports the backport of a pull request to one target branch.
+type BackportResult struct {
+	Target string `json:"target"`
+	Branch string `json:"branch"`
+	State  string `json:"state"`
+	Number int    `json:"number,omitempty"`
+	URL    string `json:"url,omitempty"`
+	// ConflictCommit is the commit that did not apply cleanly to the target
+	ConflictCommit string `json:"conflict_commit,omitempty"`
+	Error          string `json:"error,omitempty"`
+}
+
+// BackportPullRequestResult reports the backports of a pull request.
+type BackportPullRequestResult struct {
+	PullNumber int              `json:"pull_number"`
+	Commits    []string         `json:"commits"`
+	Backports  []BackportResult `json:"backports"`
+}
+
+// BackportPullRequest creates a tool to cherry-pick a merged pull request onto release branches.
+func BackportPullRequest(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "backport_pull_request",
+		Description: t("TOOL_BACKPORT_PULL_REQUEST_DESCRIPTION", "Backport a merged pull request to one or more target branches, such as release branches. For each target, the pull request's commits are cherry-picked onto a new backport branch and a pull request linking to the original is opened. Merge commits of the pull request are skipped. A target the commits do not apply to cleanly is reported as a conflict, and the others are still backported."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_BACKPORT_PULL_REQUEST_USER_TITLE", "Backport pull request"),
+			ReadOnlyHint: false,
+		},
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"owner": {
+					Type:        "string",
+					Description: "Repository owner",
+				},
+				"repo": {
+					Type:        "string",
+					Description: "Repository name",
+				},
+				"pullNumber": {
+					Type:        "number",
+					Description: "Number of the merged pull request to backport",
+				},
+				"target_branches": {
+					Type:        "array",
+					Description: fmt.Sprintf("Branches to backport to (max: %d)", maxBackportTargets),
+					Items: &jsonschema.Schema{
+						Type: "string",
+					},
+				},
+				"draft": {
+					Type:        "boolean",
+					Description: "Open the backport pull requests as drafts",
+				},
+			},
+			Required: []string{"owner", "repo", "pullNumber", "target_branches"},
+		},
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		repo, err := RequiredParam[string](args, "repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		pullNumber, err := RequiredInt(args, "pullNumber")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		targets, err := OptionalStringArrayParam(args, "target_branches")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		if len(targets) == 0 {
+			return utils.NewToolResultError("target_branches must name at least one branch"), nil, nil
+		}
+		if len(targets) > maxBackportTargets {
+			return utils.NewToolResultError(fmt.Sprintf("too many target branches: %d exceeds maximum of %d per call", len(targets), maxBackportTargets)), nil, nil
+		}
+		seen := make(map[string]bool, len(targets))
+		for _, target := range targets {
+			if strings.TrimSpace(target) == "" {
+				return utils.NewToolResultError("target_branches must not contain empty names"), nil, nil
+			}
+			if seen[target] {
+				return utils.NewToolResultError(fmt.Sprintf("branch %s is listed more than once", target)), nil, nil
+			}
+			seen[target] = true
+		}
+		draft, err := OptionalParam[bool](args, "draft")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		pr, resp, err := client.PullRequests.Get(ctx, owner, repo, pullNumber)
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx,
+				"failed to get pull request",
+				resp,
+				err,
+			), nil, nil
+		}
+		_ = resp.Body.Close()
+		if !pr.GetMerged() {
+			return utils.NewToolResultError(fmt.Sprintf("pull request #%d is not merged; only merged pull requests can be backported", pullNumber)), nil, nil
+		}
+		for _, target := range targets {
+			if target == pr.GetBase().GetRef() {
+				return utils.NewToolResultError(fmt.Sprintf("pull request #%d was merged into %s, which cannot be a target", pullNumber, target)), nil, nil
+			}
+		}
+
+		commits, err := backportCommits(ctx, client, owner, repo, pullNumber)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		if len(commits) == 0 {
+			return utils.NewToolResultError(fmt.Sprintf("pull request #%d has no commits to backport", pullNumber)), nil, nil
+		}
+
+		result := BackportPullRequestResult{PullNumber: pullNumber, Commits: commits}
+		for _, target := range targets {
+			result.Backports = append(result.Backports, backportToBranch(ctx, client, owner, repo, pr, commits, target, draft))
+		}
+
+		return MarshalledTextResult(result), nil, nil
+	})
+
+	return tool, handler
+}
+
+// backportCommits lists the commits of a pull request to cherry-pick, oldest first. Merge
+// commits only bring in changes of other branches, so they are skipped.
+func backportCommits(ctx context.Context, client *github.Client, owner, repo string, pullNumber int) ([]string, error) {
+	var commits []string
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		page, resp, err := client.PullRequests.ListCommits(ctx, owner, repo, pullNumber, opts)
+		if err != nil {
+			_, _ = ghErrors.NewGitHubAPIErrorToCtx(ctx, "failed to list pull request commits", resp, err)
+			return nil, fmt.Errorf("failed to list pull request commits: %w", err)
+		}
+		_ = resp.Body.Close()
+		for _, commit := range page {
+			if len(commit.Parents) > 1 {
+				continue
+			}
+			commits = append(commits, commit.GetSHA())
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	if len(commits) > maxBackportCommits {
+		return nil, fmt.Errorf("pull request has %d commits, more than the %d that can be backported", len(commits), maxBackportCommits)
+	}
+	return commits, nil
+}
+
+// backportToBranch cherry-picks commits onto a new branch created from target and opens a
+// pull request into target. The backport branch is deleted again if the commits do not apply.
+func backportToBranch(ctx context.Context, client *github.Client, owner, repo string, pr *github.PullRequest, commits []string, target string, draft bool) BackportResult {
+	result := BackportResult{
+		Target: target,
+		Branch: fmt.Sprintf("backport-%d-to-%s", pr.GetNumber(), target),
+		State:  BackportStateFailed,
+	}
+
+	ref, resp, err := client.Git.GetRef(ctx, owner, repo, "refs/heads/"+target)
+	if err != nil {
+		_, _ = ghErrors.NewGitHubAPIErrorToCtx(ctx, "failed to get target branch", resp, err)
+		result.Error = fmt.Sprintf("failed to get target branch: %v", err)
+		return result
+	}
+	_ = resp.Body.Close()
+	_, resp, err = client.Git.CreateRef(ctx, owner, repo, github.CreateRef{
+		Ref: "refs/heads/" + result.Branch,
+		SHA: ref.GetObject().GetSHA(),
+	})
+	if err != nil {
+		_, _ = ghErrors.NewGitHubAPIErrorToCtx(ctx, "failed to create backport branch", resp, err)
+		result.Error = fmt.Sprintf("failed to create backport branch %s: %v. Delete it if it is left over from an earlier backport", result.Branch, err)
+		return result
+	}
+	_ = resp.Body.Close()
+
+	if _, err := cherryPickCommits(ctx, client, owner, repo, result.Branch, commits); err != nil {
+		var conflict *cherryPickConflictError
+		if errors.As(err, &conflict) {
+			result.State = BackportStateConflict
+			result.ConflictCommit = conflict.Commit
+		}
+		result.Error = err.Error()
+		resp, deleteErr := client.Git.DeleteRef(ctx, owner, repo, "refs/heads/"+result.Branch)
+		if deleteErr != nil {
+			result.Error += fmt.Sprintf("; the backport branch %s could not be deleted: %v", result.Branch, deleteErr)
+		} else {
+			_ = resp.Body.Close()
+		}
+		return result
+	}
+
+	created, resp, err := client.PullRequests.Create(ctx, owner, repo, &github.NewPullRequest{
+		Title: github.Ptr(fmt.Sprintf("[%s] %s", target, pr.GetTitle())),
+		Head:  github.Ptr(result.Branch),
+		Base:  github.Ptr(target),
+		Body:  github.Ptr(backportBody(pr, commits, target)),
+		Draft: github.Ptr(draft),
+	})
+	if err != nil {
+		_, _ = ghErrors.NewGitHubAPIErrorToCtx(ctx, "failed to create pull request", resp, err)
+		result.Error = fmt.Sprintf("failed to create pull request: %v. The commits are on branch %s; open the pull request with the create_pull_request tool", err, result.Branch)
+		return result
+	}
+	_ = resp.Body.Close()
+
+	result.State = BackportStateCreated
+	result.Number = created.GetNumber()
+	result.URL = created.GetHTMLURL()
+	return result
+}
+
+// backportBody links a backport pull request to the original and lists the picked commits.
+func backportBody(pr *github.PullRequest, commits []string, target string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Backport of #%d to `%s`.\n\n", pr.GetNumber(), target)
+	if url := pr.GetHTMLURL(); url != "" {
+		fmt.Fprintf(&b, "Original pull request: %s\n", url)
+	}
+	if sha := pr.GetMergeCommitSHA(); sha != "" {
+		fmt.Fprintf(&b, "Merge commit: %s\n", sha)
+	}
+	b.WriteString("\nCherry-picked commits:\n")
+	for _, sha := range commits {
+		fmt.Fprintf(&b, "- %s\n", sha)
+	}
+	return b.String()
+}
@@ -0,0 +1,177 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v79/github"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_BackportPullRequest(t *testing.T) {
+	mockClient := github.NewClient(nil)
+	tool, _ := BackportPullRequest(stubGetClientFn(mockClient), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	schema, ok := tool.InputSchema.(*jsonschema.Schema)
+	require.True(t, ok, "InputSchema should be *jsonschema.Schema")
+
+	assert.Equal(t, "backport_pull_request", tool.Name)
+	assert.Contains(t, schema.Properties, "target_branches")
+	assert.ElementsMatch(t, schema.Required, []string{"owner", "repo", "pullNumber", "target_branches"})
+
+	mergedPR := &github.PullRequest{
+		Number:         github.Ptr(42),
+		Title:          github.Ptr("Fix crash on startup"),
+		Merged:         github.Ptr(true),
+		MergeCommitSHA: github.Ptr("merge-sha"),
+		HTMLURL:        github.Ptr("https://github.com/owner/repo/pull/42"),
+		Base:           &github.PullRequestBranch{Ref: github.Ptr("main")},
+	}
+
+	t.Run("backports to each target and reports conflicts", func(t *testing.T) {
+		var commitMessages []string
+		var pullRequest map[string]any
+		var deletedRefs []string
+		mockedClient := mock.NewMockedHTTPClient(
+			mock.WithRequestMatch(mock.GetReposPullsByOwnerByRepoByPullNumber, mergedPR),
+			mock.WithRequestMatch(mock.GetReposPullsCommitsByOwnerByRepoByPullNumber, []*github.RepositoryCommit{
+				{SHA: github.Ptr("fix-sha"), Parents: []*github.Commit{{SHA: github.Ptr("parent-sha")}}},
+				{SHA: github.Ptr("sync-sha"), Parents: []*github.Commit{{SHA: github.Ptr("fix-sha")}, {SHA: github.Ptr("main-sha")}}},
+			}),
+			mock.WithRequestMatchHandler(
+				mock.GetReposGitRefByOwnerByRepoByRef,
+				mockResponse(t, http.StatusOK, &github.Reference{Object: &github.GitObject{SHA: github.Ptr("release-sha")}}),
+			),
+			mock.WithRequestMatchHandler(
+				mock.PostReposGitRefsByOwnerByRepo,
+				mockResponse(t, http.StatusCreated, &github.Reference{}),
+			),
+			mock.WithRequestMatchHandler(
+				mock.GetReposGitCommitsByOwnerByRepoByCommitSha,
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if strings.HasSuffix(r.URL.Path, "/fix-sha") {
+						mockResponse(t, http.StatusOK, &github.Commit{
+							SHA:     github.Ptr("fix-sha"),
+							Message: github.Ptr("Fix crash on startup"),
+							Parents: []*github.Commit{{SHA: github.Ptr("parent-sha")}},
+						})(w, r)
+						return
+					}
+					mockResponse(t, http.StatusOK, &github.Commit{
+						SHA:  github.Ptr("release-sha"),
+						Tree: &github.Tree{SHA: github.Ptr("release-tree-sha")},
+					})(w, r)
+				}),
+			),
+			mock.WithRequestMatchHandler(
+				mock.PostReposGitCommitsByOwnerByRepo,
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					var commit map[string]any
+					assert.NoError(t, json.NewDecoder(r.Body).Decode(&commit))
+					commitMessages = append(commitMessages, commit["message"].(string))
+					mockResponse(t, http.StatusCreated, &github.Commit{SHA: github.Ptr("picked-sha")})(w, r)
+				}),
+			),
+			mock.WithRequestMatchHandler(
+				mock.PatchReposGitRefsByOwnerByRepoByRef,
+				mockResponse(t, http.StatusOK, &github.Reference{}),
+			),
+			mock.WithRequestMatchHandler(
+				mock.PostReposMergesByOwnerByRepo,
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					var merge map[string]any
+					assert.NoError(t, json.NewDecoder(r.Body).Decode(&merge))
+					assert.Equal(t, "fix-sha", merge["head"])
+					if strings.HasSuffix(merge["base"].(string), "release-2") {
+						w.WriteHeader(http.StatusConflict)
+						_, _ = w.Write([]byte(`{"message": "Merge conflict"}`))
+						return
+					}
+					mockResponse(t, http.StatusCreated, &github.RepositoryCommit{
+						Commit: &github.Commit{Tree: &github.Tree{SHA: github.Ptr("merged-tree-sha")}},
+					})(w, r)
+				}),
+			),
+			mock.WithRequestMatchHandler(
+				mock.PostReposPullsByOwnerByRepo,
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					assert.NoError(t, json.NewDecoder(r.Body).Decode(&pullRequest))
+					mockResponse(t, http.StatusCreated, &github.PullRequest{
+						Number:  github.Ptr(43),
+						HTMLURL: github.Ptr("https://github.com/owner/repo/pull/43"),
+					})(w, r)
+				}),
+			),
+			mock.WithRequestMatchHandler(
+				mock.DeleteReposGitRefsByOwnerByRepoByRef,
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					deletedRefs = append(deletedRefs, r.URL.Path)
+					w.WriteHeader(http.StatusNoContent)
+				}),
+			),
+		)
+
+		_, handler := BackportPullRequest(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+		args := map[string]interface{}{
+			"owner":           "owner",
+			"repo":            "repo",
+			"pullNumber":      float64(42),
+			"target_branches": []interface{}{"release-1", "release-2"},
+		}
+		request := createMCPRequest(args)
+		result, _, err := handler(context.Background(), &request, args)
+		require.NoError(t, err)
+		require.False(t, result.IsError, getTextResult(t, result).Text)
+
+		var backport BackportPullRequestResult
+		require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &backport))
+		assert.Equal(t, []string{"fix-sha"}, backport.Commits)
+		require.Len(t, backport.Backports, 2)
+
+		assert.Equal(t, BackportResult{
+			Target: "release-1",
+			Branch: "backport-42-to-release-1",
+			State:  BackportStateCreated,
+			Number: 43,
+			URL:    "https://github.com/owner/repo/pull/43",
+		}, backport.Backports[0])
+		assert.Equal(t, "[release-1] Fix crash on startup", pullRequest["title"])
+		assert.Equal(t, "release-1", pullRequest["base"])
+		assert.Contains(t, pullRequest["body"], "Backport of #42")
+		assert.Contains(t, pullRequest["body"], "- fix-sha")
+		assert.Contains(t, commitMessages, "Fix crash on startup\n\n(cherry picked from commit fix-sha)")
+
+		assert.Equal(t, BackportStateConflict, backport.Backports[1].State)
+		assert.Equal(t, "fix-sha", backport.Backports[1].ConflictCommit)
+		assert.Equal(t, []string{"/repos/owner/repo/git/refs/heads/backport-42-to-release-2"}, deletedRefs)
+	})
+
+	t.Run("pull request not merged", func(t *testing.T) {
+		openPR := *mergedPR
+		openPR.Merged = github.Ptr(false)
+		mockedClient := mock.NewMockedHTTPClient(
+			mock.WithRequestMatch(mock.GetReposPullsByOwnerByRepoByPullNumber, &openPR),
+		)
+
+		_, handler := BackportPullRequest(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+		args := map[string]interface{}{
+			"owner":           "owner",
+			"repo":            "repo",
+			"pullNumber":      float64(42),
+			"target_branches": []interface{}{"release-1"},
+		}
+		request := createMCPRequest(args)
+		result, _, err := handler(context.Background(), &request, args)
+		require.NoError(t, err)
+		assert.Contains(t, getErrorResult(t, result).Text, "is not merged")
+	})
+}
@@ -0,0 +1,835 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	ghErrors "github.com/github/github-mcp-server/pkg/errors"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/github/github-mcp-server/pkg/utils"
+	"github.com/google/go-github/v79/github"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// BranchProtection is the output type for the branch protection of a branch.
+type BranchProtection struct {
+	Branch                         string                `json:"branch"`
+	Protected                      bool                  `json:"protected"`
+	RequiredStatusChecks           *RequiredStatusChecks `json:"required_status_checks,omitempty"`
+	RequiredPullRequestReviews     *RequiredReviews      `json:"required_pull_request_reviews,omitempty"`
+	EnforceAdmins                  bool                  `json:"enforce_admins"`
+	RequiredSignatures             bool                  `json:"required_signatures"`
+	RequiredLinearHistory          bool                  `json:"required_linear_history"`
+	RequiredConversationResolution bool                  `json:"required_conversation_resolution"`
+	AllowForcePushes               bool                  `json:"allow_force_pushes"`
+	AllowDeletions                 bool                  `json:"allow_deletions"`
+	LockBranch                     bool                  `json:"lock_branch"`
+	PushRestrictions               *BranchActors         `json:"push_restrictions,omitempty"`
+	Rules                          []map[string]any      `json:"rules,omitempty"`
+	RulesError                     string                `json:"rules_error,omitempty"`
+}
+
+// RequiredStatusChecks lists the checks that must pass before merging into a protected branch.
+type RequiredStatusChecks struct {
+	// Strict requires branches to be up to date with the protected branch before merging
+	Strict bool     `json:"strict"`
+	Checks []string `json:"checks"`
+}
+
+// RequiredReviews are the review requirements of a protected branch.
+type RequiredReviews struct {
+	RequiredApprovingReviewCount int  `json:"required_approving_review_count"`
+	RequireCodeOwnerReviews      bool `json:"require_code_owner_reviews"`
+	DismissStaleReviews          bool `json:"dismiss_stale_reviews"`
+	RequireLastPushApproval      bool `json:"require_last_push_approval"`
+}
+
+// BranchActors are the users, teams and apps allowed to do something on a protected branch.
+type BranchActors struct {
+	Users []string `json:"users"`
+	Teams []string `json:"teams"`
+	Apps  []string `json:"apps"`
+}
+
+func convertToBranchProtection(branch string, protection *github.Protection) BranchProtection {
+	result := BranchProtection{
+		Branch:                         branch,
+		Protected:                      true,
+		EnforceAdmins:                  protection.EnforceAdmins != nil && protection.EnforceAdmins.Enabled,
+		RequiredSignatures:             protection.GetRequiredSignatures().GetEnabled(),
+		RequiredLinearHistory:          protection.RequireLinearHistory != nil && protection.RequireLinearHistory.Enabled,
+		RequiredConversationResolution: protection.RequiredConversationResolution != nil && protection.RequiredConversationResolution.Enabled,
+		AllowForcePushes:               protection.AllowForcePushes != nil && protection.AllowForcePushes.Enabled,
+		AllowDeletions:                 protection.AllowDeletions != nil && protection.AllowDeletions.Enabled,
+		LockBranch:                     protection.GetLockBranch().GetEnabled(),
+	}
+	if checks := protection.RequiredStatusChecks; checks != nil {
+		result.RequiredStatusChecks = &RequiredStatusChecks{Strict: checks.Strict, Checks: statusCheckContexts(checks)}
+	}
+	if reviews := protection.RequiredPullRequestReviews; reviews != nil {
+		result.RequiredPullRequestReviews = &RequiredReviews{
+			RequiredApprovingReviewCount: reviews.RequiredApprovingReviewCount,
+			RequireCodeOwnerReviews:      reviews.RequireCodeOwnerReviews,
+			DismissStaleReviews:          reviews.DismissStaleReviews,
+			RequireLastPushApproval:      reviews.RequireLastPushApproval,
+		}
+	}
+	if restrictions := protection.Restrictions; restrictions != nil {
+		actors := branchActors(restrictions.Users, restrictions.Teams, restrictions.Apps)
+		result.PushRestrictions = &actors
+	}
+	return result
+}
+
+// statusCheckContexts returns the names of the required checks.
+func statusCheckContexts(checks *github.RequiredStatusChecks) []string {
+	contexts := []string{}
+	if checks.Checks != nil {
+		for _, check := range *checks.Checks {
+			contexts = append(contexts, check.Context)
+		}
+	} else if checks.Contexts != nil {
+		contexts = append(contexts, *checks.Contexts...)
+	}
+	return contexts
+}
+
+func branchActors(users []*github.User, teams []*github.Team, apps []*github.App) BranchActors {
+	actors := BranchActors{Users: []string{}, Teams: []string{}, Apps: []string{}}
+	for _, user := range users {
+		actors.Users = append(actors.Users, user.GetLogin())
+	}
+	for _, team := range teams {
+		actors.Teams = append(actors.Teams, team.GetSlug())
+	}
+	for _, app := range apps {
+		actors.Apps = append(actors.Apps, app.GetSlug())
+	}
+	return actors
+}
+
+// protectionRequest returns the request that keeps protection as it is, as the update endpoint
+// replaces the whole protection.
+func protectionRequest(protection *github.Protection) *github.ProtectionRequest {
+	current := convertToBranchProtection("", protection)
+	request := &github.ProtectionRequest{
+		EnforceAdmins:                  current.EnforceAdmins,
+		RequireLinearHistory:           github.Ptr(current.RequiredLinearHistory),
+		AllowForcePushes:               github.Ptr(current.AllowForcePushes),
+		AllowDeletions:                 github.Ptr(current.AllowDeletions),
+		RequiredConversationResolution: github.Ptr(current.RequiredConversationResolution),
+		LockBranch:                     github.Ptr(current.LockBranch),
+	}
+	if protection.BlockCreations != nil {
+		request.BlockCreations = protection.BlockCreations.Enabled
+	}
+	if protection.AllowForkSyncing != nil {
+		request.AllowForkSyncing = protection.AllowForkSyncing.Enabled
+	}
+	if checks := protection.RequiredStatusChecks; checks != nil {
+		request.RequiredStatusChecks = &github.RequiredStatusChecks{Strict: checks.Strict}
+		if checks.Checks != nil {
+			request.RequiredStatusChecks.Checks = checks.Checks
+		} else {
+			request.RequiredStatusChecks.Checks = &[]*github.RequiredStatusCheck{}
+			for _, context := range statusCheckContexts(checks) {
+				*request.RequiredStatusChecks.Checks = append(*request.RequiredStatusChecks.Checks, &github.RequiredStatusCheck{Context: context})
+			}
+		}
+	}
+	if reviews := protection.RequiredPullRequestReviews; reviews != nil {
+		request.RequiredPullRequestReviews = &github.PullRequestReviewsEnforcementRequest{
+			DismissStaleReviews:          reviews.DismissStaleReviews,
+			RequireCodeOwnerReviews:      reviews.RequireCodeOwnerReviews,
+			RequiredApprovingReviewCount: reviews.RequiredApprovingReviewCount,
+			RequireLastPushApproval:      github.Ptr(reviews.RequireLastPushApproval),
+		}
+		if dismissal := reviews.DismissalRestrictions; dismissal != nil {
+			actors := branchActors(dismissal.Users, dismissal.Teams, dismissal.Apps)
+			request.RequiredPullRequestReviews.DismissalRestrictionsRequest = &github.DismissalRestrictionsRequest{
+				Users: &actors.Users,
+				Teams: &actors.Teams,
+				Apps:  &actors.Apps,
+			}
+		}
+		if bypass := reviews.BypassPullRequestAllowances; bypass != nil {
+			actors := branchActors(bypass.Users, bypass.Teams, bypass.Apps)
+			request.RequiredPullRequestReviews.BypassPullRequestAllowancesRequest = &github.BypassPullRequestAllowancesRequest{
+				Users: actors.Users,
+				Teams: actors.Teams,
+				Apps:  actors.Apps,
+			}
+		}
+	}
+	if restrictions := protection.Restrictions; restrictions != nil {
+		actors := branchActors(restrictions.Users, restrictions.Teams, restrictions.Apps)
+		request.Restrictions = &github.BranchRestrictionsRequest{Users: actors.Users, Teams: actors.Teams, Apps: actors.Apps}
+	}
+	return request
+}
+
+// getBranchRules returns the ruleset rules that apply to branch, as the API returns them.
+func getBranchRules(ctx context.Context, client *github.Client, owner, repo, branch string) ([]map[string]any, error) {
+	req, err := client.NewRequest(http.MethodGet, fmt.Sprintf("repos/%s/%s/rules/branches/%s?per_page=100", owner, repo, url.PathEscape(branch)), nil)
+	if err != nil {
+		return nil, err
+	}
+	var rules []map[string]any
+	resp, err := client.Do(ctx, req, &rules)
+	if err != nil {
+		return nil, err
+	}
+	_ = resp.Body.Close()
+	return rules, nil
+}
+
+// GetBranchProtection creates a tool to get the protection and ruleset rules of a branch.
+func GetBranchProtection(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "get_branch_protection",
+		Description: t("TOOL_GET_BRANCH_PROTECTION_DESCRIPTION", "Get the rules that apply to a branch: its branch protection (required checks and reviews, signed commits, push restrictions) and the rules of the repository and organization rulesets that target it. Use this to find out why a push or merge was rejected. Reading branch protection requires admin access to the repository."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_GET_BRANCH_PROTECTION_USER_TITLE", "Get branch protection"),
+			ReadOnlyHint: true,
+		},
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"owner": {
+					Type:        "string",
+					Description: "Repository owner",
+				},
+				"repo": {
+					Type:        "string",
+					Description: "Repository name",
+				},
+				"branch": {
+					Type:        "string",
+					Description: "Branch name",
+				},
+			},
+			Required: []string{"owner", "repo", "branch"},
+		},
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		repo, err := RequiredParam[string](args, "repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		branch, err := RequiredParam[string](args, "branch")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		result := BranchProtection{Branch: branch}
+		protection, resp, err := client.Repositories.GetBranchProtection(ctx, owner, repo, branch)
+		switch {
+		case errors.Is(err, github.ErrBranchNotProtected):
+		case err != nil:
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to get branch protection", resp, err), nil, nil
+		default:
+			_ = resp.Body.Close()
+			result = convertToBranchProtection(branch, protection)
+		}
+
+		// Rulesets are not available on every plan and server version, so the protection is
+		// still returned without them
+		rules, err := getBranchRules(ctx, client, owner, repo, branch)
+		if err != nil {
+			result.RulesError = fmt.Sprintf("failed to get ruleset rules: %v", err)
+		}
+		result.Rules = rules
+
+		return MarshalledTextResult(result), nil, nil
+	})
+
+	return tool, handler
+}
+
+// UpdateBranchProtection creates a tool to change the protection of a branch.
+func UpdateBranchProtection(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	actorsSchema := func(description string) *jsonschema.Schema {
+		return &jsonschema.Schema{
+			Type:        "object",
+			Description: description,
+			Properties: map[string]*jsonschema.Schema{
+				"users": {Type: "array", Description: "User logins", Items: &jsonschema.Schema{Type: "string"}},
+				"teams": {Type: "array", Description: "Team slugs", Items: &jsonschema.Schema{Type: "string"}},
+				"apps":  {Type: "array", Description: "GitHub App slugs", Items: &jsonschema.Schema{Type: "string"}},
+			},
+		}
+	}
+
+	tool := mcp.Tool{
+		Name:        "update_branch_protection",
+		Description: t("TOOL_UPDATE_BRANCH_PROTECTION_DESCRIPTION", "Protect a branch or change its protection. Only the settings given are changed; the others keep their current value. Requires admin access to the repository."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_UPDATE_BRANCH_PROTECTION_USER_TITLE", "Update branch protection"),
+			ReadOnlyHint: false,
+		},
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"owner": {
+					Type:        "string",
+					Description: "Repository owner",
+				},
+				"repo": {
+					Type:        "string",
+					Description: "Repository name",
+				},
+				"branch": {
+					Type:        "string",
+					Description: "Branch name",
+				},
+				"required_status_checks": {
+					Type:        "array",
+					Description: "Names of the checks that must pass before merging, replacing the current ones. An empty list removes the requirement",
+					Items:       &jsonschema.Schema{Type: "string"},
+				},
+				"strict": {
+					Type:        "boolean",
+					Description: "Whether branches must be up to date with this branch before merging. Applies when status checks are required",
+				},
+				"require_pull_request_reviews": {
+					Type:        "boolean",
+					Description: "Whether changes must be made through pull requests. Set to false to remove the review requirements",
+				},
+				"required_approving_review_count": {
+					Type:        "number",
+					Description: "Number of approvals a pull request needs (0-6). Implies require_pull_request_reviews",
+					Minimum:     jsonschema.Ptr(0.0),
+					Maximum:     jsonschema.Ptr(6.0),
+				},
+				"require_code_owner_reviews": {
+					Type:        "boolean",
+					Description: "Whether code owners must approve changes to the files they own. Implies require_pull_request_reviews",
+				},
+				"dismiss_stale_reviews": {
+					Type:        "boolean",
+					Description: "Whether approvals are dismissed when new commits are pushed. Implies require_pull_request_reviews",
+				},
+				"require_last_push_approval": {
+					Type:        "boolean",
+					Description: "Whether the last push must be approved by someone other than its author. Implies require_pull_request_reviews",
+				},
+				"enforce_admins": {
+					Type:        "boolean",
+					Description: "Whether the protection also applies to administrators",
+				},
+				"required_signatures": {
+					Type:        "boolean",
+					Description: "Whether commits pushed to the branch must be signed",
+				},
+				"required_linear_history": {
+					Type:        "boolean",
+					Description: "Whether merge commits are rejected",
+				},
+				"required_conversation_resolution": {
+					Type:        "boolean",
+					Description: "Whether review conversations must be resolved before merging",
+				},
+				"allow_force_pushes": {
+					Type:        "boolean",
+					Description: "Whether anyone with push access can force push",
+				},
+				"allow_deletions": {
+					Type:        "boolean",
+					Description: "Whether anyone with push access can delete the branch",
+				},
+				"lock_branch": {
+					Type:        "boolean",
+					Description: "Whether the branch is read-only",
+				},
+				"push_restrictions": actorsSchema("Only these users, teams and apps can push to the branch, replacing the current restrictions. Only available for organization repositories"),
+				"restrict_pushes": {
+					Type:        "boolean",
+					Description: "Set to false to let everyone with push access push again, removing push_restrictions",
+				},
+			},
+			Required: []string{"owner", "repo", "branch"},
+		},
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		repo, err := RequiredParam[string](args, "repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		branch, err := RequiredParam[string](args, "branch")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		current, resp, err := client.Repositories.GetBranchProtection(ctx, owner, repo, branch)
+		switch {
+		case errors.Is(err, github.ErrBranchNotProtected):
+			current = &github.Protection{}
+		case err != nil:
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to get branch protection", resp, err), nil, nil
+		default:
+			_ = resp.Body.Close()
+		}
+
+		request := protectionRequest(current)
+		if err := applyProtectionArgs(request, args); err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		updated, resp, err := client.Repositories.UpdateBranchProtection(ctx, owner, repo, branch, request)
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to update branch protection", resp, err), nil, nil
+		}
+		_ = resp.Body.Close()
+
+		if _, ok := args["required_signatures"]; ok {
+			required, err := OptionalParam[bool](args, "required_signatures")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			if required {
+				_, resp, err = client.Repositories.RequireSignaturesOnProtectedBranch(ctx, owner, repo, branch)
+			} else {
+				resp, err = client.Repositories.OptionalSignaturesOnProtectedBranch(ctx, owner, repo, branch)
+			}
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx, "branch protection was updated, but changing required signatures failed", resp, err), nil, nil
+			}
+			_ = resp.Body.Close()
+			updated.RequiredSignatures = &github.SignaturesProtectedBranch{Enabled: github.Ptr(required)}
+		}
+
+		return MarshalledTextResult(convertToBranchProtection(branch, updated)), nil, nil
+	})
+
+	return tool, handler
+}
+
+// applyProtectionArgs changes request as the update_branch_protection arguments ask.
+func applyProtectionArgs(request *github.ProtectionRequest, args map[string]any) error {
+	if _, ok := args["required_status_checks"]; ok {
+		contexts, err := OptionalStringArrayParam(args, "required_status_checks")
+		if err != nil {
+			return err
+		}
+		if len(contexts) == 0 {
+			request.RequiredStatusChecks = nil
+		} else {
+			strict := request.RequiredStatusChecks != nil && request.RequiredStatusChecks.Strict
+			checks := make([]*github.RequiredStatusCheck, 0, len(contexts))
+			for _, context := range contexts {
+				checks = append(checks, &github.RequiredStatusCheck{Context: context})
+			}
+			request.RequiredStatusChecks = &github.RequiredStatusChecks{Strict: strict, Checks: &checks}
+		}
+	}
+	if _, ok := args["strict"]; ok {
+		strict, err := OptionalParam[bool](args, "strict")
+		if err != nil {
+			return err
+		}
+		if request.RequiredStatusChecks == nil {
+			if strict {
+				return fmt.Errorf("strict applies to required status checks: set required_status_checks too")
+			}
+		} else {
+			request.RequiredStatusChecks.Strict = strict
+		}
+	}
+
+	requireReviews, err := OptionalBoolParamWithDefault(args, "require_pull_request_reviews", true)
+	if err != nil {
+		return err
+	}
+	reviewSettings := []string{"required_approving_review_count", "require_code_owner_reviews", "dismiss_stale_reviews", "require_last_push_approval"}
+	if !requireReviews {
+		for _, name := range reviewSettings {
+			if _, ok := args[name]; ok {
+				return fmt.Errorf("%s cannot be set when require_pull_request_reviews is false", name)
+			}
+		}
+		request.RequiredPullRequestReviews = nil
+	} else {
+		_, explicit := args["require_pull_request_reviews"]
+		for _, name := range reviewSettings {
+			if _, ok := args[name]; ok {
+				explicit = true
+			}
+		}
+		if explicit && request.RequiredPullRequestReviews == nil {
+			request.RequiredPullRequestReviews = &github.PullRequestReviewsEnforcementRequest{RequiredApprovingReviewCount: 1}
+		}
+		if reviews := request.RequiredPullRequestReviews; reviews != nil {
+			if _, ok := args["required_approving_review_count"]; ok {
+				count, err := OptionalIntParam(args, "required_approving_review_count")
+				if err != nil {
+					return err
+				}
+				if count < 0 || count > 6 {
+					return fmt.Errorf("required_approving_review_count must be between 0 and 6")
+				}
+				reviews.RequiredApprovingReviewCount = count
+			}
+			for name, field := range map[string]*bool{
+				"require_code_owner_reviews": &reviews.RequireCodeOwnerReviews,
+				"dismiss_stale_reviews":      &reviews.DismissStaleReviews,
+			} {
+				if _, ok := args[name]; ok {
+					if *field, err = OptionalParam[bool](args, name); err != nil {
+						return err
+					}
+				}
+			}
+			if _, ok := args["require_last_push_approval"]; ok {
+				value, err := OptionalParam[bool](args, "require_last_push_approval")
+				if err != nil {
+					return err
+				}
+				reviews.RequireLastPushApproval = github.Ptr(value)
+			}
+		}
+	}
+
+	if _, ok := args["enforce_admins"]; ok {
+		if request.EnforceAdmins, err = OptionalParam[bool](args, "enforce_admins"); err != nil {
+			return err
+		}
+	}
+	for name, field := range map[string]**bool{
+		"required_linear_history":          &request.RequireLinearHistory,
+		"required_conversation_resolution": &request.RequiredConversationResolution,
+		"allow_force_pushes":               &request.AllowForcePushes,
+		"allow_deletions":                  &request.AllowDeletions,
+		"lock_branch":                      &request.LockBranch,
+	} {
+		if _, ok := args[name]; !ok {
+			continue
+		}
+		value, err := OptionalParam[bool](args, name)
+		if err != nil {
+			return err
+		}
+		*field = github.Ptr(value)
+	}
+
+	restrictPushes, err := OptionalBoolParamWithDefault(args, "restrict_pushes", true)
+	if err != nil {
+		return err
+	}
+	rawRestrictions, setRestrictions := args["push_restrictions"]
+	switch {
+	case !restrictPushes && setRestrictions:
+		return fmt.Errorf("push_restrictions cannot be set when restrict_pushes is false")
+	case !restrictPushes:
+		request.Restrictions = nil
+	case setRestrictions:
+		restrictions, ok := rawRestrictions.(map[string]any)
+		if !ok {
+			return fmt.Errorf("push_restrictions must be an object with users, teams and apps")
+		}
+		request.Restrictions = &github.BranchRestrictionsRequest{Users: []string{}, Teams: []string{}, Apps: []string{}}
+		for name, field := range map[string]*[]string{
+			"users": &request.Restrictions.Users,
+			"teams": &request.Restrictions.Teams,
+			"apps":  &request.Restrictions.Apps,
+		} {
+			values, err := OptionalStringArrayParam(restrictions, name)
+			if err != nil {
+				return fmt.Errorf("push_restrictions: %w", err)
+			}
+			if values != nil {
+				*field = values
+			}
+		}
+	}
+	return nil
+}
+
+// RulesetSummary is the output type for rulesets in lists.
+type RulesetSummary struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Target      string `json:"target,omitempty"`
+	Enforcement string `json:"enforcement"`
+	SourceType  string `json:"source_type,omitempty"`
+	Source      string `json:"source"`
+}
+
+// ListRepositoryRulesets creates a tool to list the rulesets of a repository.
+func ListRepositoryRulesets(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "list_repository_rulesets",
+		Description: t("TOOL_LIST_REPOSITORY_RULESETS_DESCRIPTION", "List the rulesets of a GitHub repository, including by default those inherited from its organization. Use get_repository_ruleset for the rules of a ruleset."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_LIST_REPOSITORY_RULESETS_USER_TITLE", "List repository rulesets"),
+			ReadOnlyHint: true,
+		},
+		InputSchema: WithPagination(&jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"owner": {
+					Type:        "string",
+					Description: "Repository owner",
+				},
+				"repo": {
+					Type:        "string",
+					Description: "Repository name",
+				},
+				"includes_parents": {
+					Type:        "boolean",
+					Description: "Whether to include the rulesets of the organization that apply to the repository (default: true)",
+					Default:     json.RawMessage("true"),
+				},
+			},
+			Required: []string{"owner", "repo"},
+		}),
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		repo, err := RequiredParam[string](args, "repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		includesParents, err := OptionalBoolParamWithDefault(args, "includes_parents", true)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		pagination, err := OptionalPaginationParams(args)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		rulesets, resp, err := client.Repositories.GetAllRulesets(ctx, owner, repo, &github.RepositoryListRulesetsOptions{
+			IncludesParents: github.Ptr(includesParents),
+			ListOptions:     github.ListOptions{Page: pagination.Page, PerPage: pagination.PerPage},
+		})
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to list rulesets", resp, err), nil, nil
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		summaries := make([]RulesetSummary, 0, len(rulesets))
+		for _, ruleset := range rulesets {
+			summary := RulesetSummary{
+				ID:          ruleset.GetID(),
+				Name:        ruleset.Name,
+				Enforcement: string(ruleset.Enforcement),
+				Source:      ruleset.Source,
+			}
+			if ruleset.Target != nil {
+				summary.Target = string(*ruleset.Target)
+			}
+			if ruleset.SourceType != nil {
+				summary.SourceType = string(*ruleset.SourceType)
+			}
+			summaries = append(summaries, summary)
+		}
+
+		return MarshalledTextResult(summaries), nil, nil
+	})
+
+	return tool, handler
+}
+
+// GetRepositoryRuleset creates a tool to get a ruleset with its rules.
+func GetRepositoryRuleset(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "get_repository_ruleset",
+		Description: t("TOOL_GET_REPOSITORY_RULESET_DESCRIPTION", "Get a ruleset of a GitHub repository with its conditions, rules and bypass actors"),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_GET_REPOSITORY_RULESET_USER_TITLE", "Get repository ruleset"),
+			ReadOnlyHint: true,
+		},
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"owner": {
+					Type:        "string",
+					Description: "Repository owner",
+				},
+				"repo": {
+					Type:        "string",
+					Description: "Repository name",
+				},
+				"ruleset_id": {
+					Type:        "number",
+					Description: "ID of the ruleset, as returned by list_repository_rulesets",
+				},
+			},
+			Required: []string{"owner", "repo", "ruleset_id"},
+		},
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		repo, err := RequiredParam[string](args, "repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		rulesetID, err := RequiredInt(args, "ruleset_id")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		// Organization rulesets are returned too, as list_repository_rulesets includes them
+		ruleset, resp, err := client.Repositories.GetRuleset(ctx, owner, repo, int64(rulesetID), true)
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to get ruleset", resp, err), nil, nil
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		return MarshalledTextResult(ruleset), nil, nil
+	})
+
+	return tool, handler
+}
+
+// SetRepositoryRuleset creates a tool to create or replace a repository ruleset.
+func SetRepositoryRuleset(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "set_repository_ruleset",
+		Description: t("TOOL_SET_REPOSITORY_RULESET_DESCRIPTION", "Create a ruleset in a GitHub repository, or replace an existing one when ruleset_id is given. The ruleset uses the format of the GitHub REST API, as returned by get_repository_ruleset. Requires admin access to the repository."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_SET_REPOSITORY_RULESET_USER_TITLE", "Create or update repository ruleset"),
+			ReadOnlyHint: false,
+		},
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"owner": {
+					Type:        "string",
+					Description: "Repository owner",
+				},
+				"repo": {
+					Type:        "string",
+					Description: "Repository name",
+				},
+				"ruleset_id": {
+					Type:        "number",
+					Description: "ID of the ruleset to replace. Omit to create a ruleset",
+				},
+				"ruleset": {
+					Type: "object",
+					Description: `The ruleset, e.g. {"name": "main", "target": "branch", "enforcement": "active", ` +
+						`"conditions": {"ref_name": {"include": ["~DEFAULT_BRANCH"], "exclude": []}}, ` +
+						`"rules": [{"type": "required_signatures"}, {"type": "pull_request", "parameters": {"required_approving_review_count": 1, ...}}]}`,
+					Properties: map[string]*jsonschema.Schema{
+						"name": {
+							Type:        "string",
+							Description: "Name of the ruleset",
+						},
+						"enforcement": {
+							Type:        "string",
+							Description: "Whether the ruleset is enforced. 'evaluate' only reports what it would block, on GitHub Enterprise",
+							Enum:        []any{"active", "evaluate", "disabled"},
+						},
+					},
+					Required: []string{"name", "enforcement"},
+				},
+			},
+			Required: []string{"owner", "repo", "ruleset"},
+		},
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		repo, err := RequiredParam[string](args, "repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		rulesetID, err := OptionalIntParam(args, "ruleset_id")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		rawRuleset, ok := args["ruleset"].(map[string]any)
+		if !ok {
+			return utils.NewToolResultError("ruleset must be an object"), nil, nil
+		}
+
+		encoded, err := json.Marshal(rawRuleset)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to marshal ruleset: %w", err)
+		}
+		var ruleset github.RepositoryRuleset
+		if err := json.Unmarshal(encoded, &ruleset); err != nil {
+			return utils.NewToolResultError(fmt.Sprintf("invalid ruleset: %v", err)), nil, nil
+		}
+		if ruleset.Name == "" || ruleset.Enforcement == "" {
+			return utils.NewToolResultError("ruleset needs a name and an enforcement"), nil, nil
+		}
+		// Read-only fields of a ruleset returned by get_repository_ruleset are not sent back
+		ruleset.ID = nil
+		ruleset.Links = nil
+		ruleset.NodeID = nil
+		ruleset.CurrentUserCanBypass = nil
+		ruleset.CreatedAt = nil
+		ruleset.UpdatedAt = nil
+		ruleset.SourceType = nil
+		ruleset.Source = ""
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		var saved *github.RepositoryRuleset
+		var resp *github.Response
+		if rulesetID > 0 {
+			saved, resp, err = client.Repositories.UpdateRuleset(ctx, owner, repo, int64(rulesetID), ruleset)
+		} else {
+			saved, resp, err = client.Repositories.CreateRuleset(ctx, owner, repo, ruleset)
+		}
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to save ruleset", resp, err), nil, nil
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		return MarshalledTextResult(saved), nil, nil
+	})
+
+	return tool, handler
+}
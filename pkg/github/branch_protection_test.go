@@ -0,0 +1,365 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v79/github"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var mockProtection = &github.Protection{
+	RequiredStatusChecks: &github.RequiredStatusChecks{
+		Strict: true,
+		Checks: &[]*github.RequiredStatusCheck{{Context: "build"}, {Context: "lint"}},
+	},
+	RequiredPullRequestReviews: &github.PullRequestReviewsEnforcement{
+		RequiredApprovingReviewCount: 1,
+		DismissStaleReviews:          true,
+		DismissalRestrictions: &github.DismissalRestrictions{
+			Users: []*github.User{{Login: github.Ptr("lead")}},
+		},
+	},
+	EnforceAdmins:      &github.AdminEnforcement{Enabled: true},
+	RequiredSignatures: &github.SignaturesProtectedBranch{Enabled: github.Ptr(true)},
+	AllowForcePushes:   &github.AllowForcePushes{Enabled: false},
+	Restrictions: &github.BranchRestrictions{
+		Teams: []*github.Team{{Slug: github.Ptr("release")}},
+	},
+}
+
+func Test_GetBranchProtection(t *testing.T) {
+	tool, _ := GetBranchProtection(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	rules := []map[string]any{{"type": "pull_request", "ruleset_source": "octo-org", "ruleset_id": float64(3)}}
+
+	t.Run("protected branch", func(t *testing.T) {
+		mockedClient := mock.NewMockedHTTPClient(
+			mock.WithRequestMatch(mock.GetReposBranchesProtectionByOwnerByRepoByBranch, mockProtection),
+			mock.WithRequestMatchHandler(
+				mock.GetReposRulesBranchesByOwnerByRepoByBranch,
+				expectPath(t, "/repos/owner/repo/rules/branches/main").andThen(mockResponse(t, http.StatusOK, rules)),
+			),
+		)
+		_, handler := GetBranchProtection(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+		args := map[string]any{"owner": "owner", "repo": "repo", "branch": "main"}
+		request := createMCPRequest(args)
+		result, _, err := handler(context.Background(), &request, args)
+		require.NoError(t, err)
+		require.False(t, result.IsError, getTextResult(t, result).Text)
+
+		var protection BranchProtection
+		require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &protection))
+		assert.True(t, protection.Protected)
+		assert.Equal(t, &RequiredStatusChecks{Strict: true, Checks: []string{"build", "lint"}}, protection.RequiredStatusChecks)
+		assert.Equal(t, 1, protection.RequiredPullRequestReviews.RequiredApprovingReviewCount)
+		assert.True(t, protection.EnforceAdmins)
+		assert.True(t, protection.RequiredSignatures)
+		assert.Equal(t, []string{"release"}, protection.PushRestrictions.Teams)
+		assert.Equal(t, rules, protection.Rules)
+	})
+
+	t.Run("unprotected branch without rulesets", func(t *testing.T) {
+		mockedClient := mock.NewMockedHTTPClient(
+			mock.WithRequestMatchHandler(
+				mock.GetReposBranchesProtectionByOwnerByRepoByBranch,
+				mockResponse(t, http.StatusNotFound, map[string]string{"message": "Branch not protected"}),
+			),
+			mock.WithRequestMatchHandler(
+				mock.GetReposRulesBranchesByOwnerByRepoByBranch,
+				mockResponse(t, http.StatusNotFound, map[string]string{"message": "Not Found"}),
+			),
+		)
+		_, handler := GetBranchProtection(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+		args := map[string]any{"owner": "owner", "repo": "repo", "branch": "dev"}
+		request := createMCPRequest(args)
+		result, _, err := handler(context.Background(), &request, args)
+		require.NoError(t, err)
+		require.False(t, result.IsError, getTextResult(t, result).Text)
+
+		var protection BranchProtection
+		require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &protection))
+		assert.False(t, protection.Protected)
+		assert.Equal(t, "dev", protection.Branch)
+		assert.Contains(t, protection.RulesError, "404")
+	})
+
+	t.Run("forbidden", func(t *testing.T) {
+		mockedClient := mock.NewMockedHTTPClient(
+			mock.WithRequestMatchHandler(
+				mock.GetReposBranchesProtectionByOwnerByRepoByBranch,
+				mockResponse(t, http.StatusForbidden, map[string]string{"message": "Resource not accessible by integration"}),
+			),
+		)
+		_, handler := GetBranchProtection(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+		args := map[string]any{"owner": "owner", "repo": "repo", "branch": "main"}
+		request := createMCPRequest(args)
+		result, _, err := handler(context.Background(), &request, args)
+		require.NoError(t, err)
+		require.True(t, result.IsError)
+		assert.Contains(t, getErrorResult(t, result).Text, "failed to get branch protection")
+	})
+}
+
+func Test_UpdateBranchProtection(t *testing.T) {
+	tool, _ := UpdateBranchProtection(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	t.Run("keeps the settings that are not given", func(t *testing.T) {
+		var sent map[string]any
+		signatures := false
+		mockedClient := mock.NewMockedHTTPClient(
+			mock.WithRequestMatch(mock.GetReposBranchesProtectionByOwnerByRepoByBranch, mockProtection),
+			mock.WithRequestMatchHandler(
+				mock.PutReposBranchesProtectionByOwnerByRepoByBranch,
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					require.NoError(t, json.NewDecoder(r.Body).Decode(&sent))
+					w.WriteHeader(http.StatusOK)
+					_ = json.NewEncoder(w).Encode(mockProtection)
+				}),
+			),
+			mock.WithRequestMatchHandler(
+				mock.DeleteReposBranchesProtectionRequiredSignaturesByOwnerByRepoByBranch,
+				http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+					signatures = true
+					w.WriteHeader(http.StatusNoContent)
+				}),
+			),
+		)
+		_, handler := UpdateBranchProtection(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+		args := map[string]any{
+			"owner":                           "owner",
+			"repo":                            "repo",
+			"branch":                          "main",
+			"required_approving_review_count": float64(2),
+			"allow_force_pushes":              true,
+			"required_signatures":             false,
+		}
+		request := createMCPRequest(args)
+		result, _, err := handler(context.Background(), &request, args)
+		require.NoError(t, err)
+		require.False(t, result.IsError, getTextResult(t, result).Text)
+		assert.True(t, signatures)
+
+		assert.Equal(t, map[string]any{"strict": true, "checks": []any{
+			map[string]any{"context": "build"},
+			map[string]any{"context": "lint"},
+		}}, sent["required_status_checks"])
+		reviews := sent["required_pull_request_reviews"].(map[string]any)
+		assert.Equal(t, float64(2), reviews["required_approving_review_count"])
+		assert.Equal(t, true, reviews["dismiss_stale_reviews"])
+		assert.Equal(t, []any{"lead"}, reviews["dismissal_restrictions"].(map[string]any)["users"])
+		assert.Equal(t, true, sent["enforce_admins"])
+		assert.Equal(t, true, sent["allow_force_pushes"])
+		assert.Equal(t, []any{"release"}, sent["restrictions"].(map[string]any)["teams"])
+
+		var protection BranchProtection
+		require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &protection))
+		assert.False(t, protection.RequiredSignatures)
+	})
+
+	t.Run("protects an unprotected branch", func(t *testing.T) {
+		mockedClient := mock.NewMockedHTTPClient(
+			mock.WithRequestMatchHandler(
+				mock.GetReposBranchesProtectionByOwnerByRepoByBranch,
+				mockResponse(t, http.StatusNotFound, map[string]string{"message": "Branch not protected"}),
+			),
+			mock.WithRequestMatchHandler(
+				mock.PutReposBranchesProtectionByOwnerByRepoByBranch,
+				expectRequestBody(t, map[string]any{
+					"required_status_checks":           map[string]any{"strict": true, "checks": []any{map[string]any{"context": "ci"}}},
+					"required_pull_request_reviews":    map[string]any{"dismiss_stale_reviews": false, "require_code_owner_reviews": true, "required_approving_review_count": float64(1)},
+					"enforce_admins":                   false,
+					"restrictions":                     nil,
+					"required_linear_history":          false,
+					"required_conversation_resolution": false,
+					"allow_force_pushes":               false,
+					"allow_deletions":                  false,
+					"lock_branch":                      false,
+				}).andThen(mockResponse(t, http.StatusOK, &github.Protection{})),
+			),
+		)
+		_, handler := UpdateBranchProtection(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+		args := map[string]any{
+			"owner":                      "owner",
+			"repo":                       "repo",
+			"branch":                     "main",
+			"required_status_checks":     []any{"ci"},
+			"strict":                     true,
+			"require_code_owner_reviews": true,
+		}
+		request := createMCPRequest(args)
+		result, _, err := handler(context.Background(), &request, args)
+		require.NoError(t, err)
+		require.False(t, result.IsError, getTextResult(t, result).Text)
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		_, handler := UpdateBranchProtection(stubGetClientFn(github.NewClient(mock.NewMockedHTTPClient(
+			mock.WithRequestMatchHandler(
+				mock.GetReposBranchesProtectionByOwnerByRepoByBranch,
+				mockResponse(t, http.StatusOK, &github.Protection{}),
+			),
+		))), translations.NullTranslationHelper)
+
+		for _, tc := range []struct {
+			args    map[string]any
+			message string
+		}{
+			{map[string]any{"require_pull_request_reviews": false, "dismiss_stale_reviews": true}, "dismiss_stale_reviews cannot be set when require_pull_request_reviews is false"},
+			{map[string]any{"strict": true}, "strict applies to required status checks"},
+			{map[string]any{"restrict_pushes": false, "push_restrictions": map[string]any{}}, "push_restrictions cannot be set when restrict_pushes is false"},
+		} {
+			full := map[string]any{"owner": "owner", "repo": "repo", "branch": "main"}
+			for k, v := range tc.args {
+				full[k] = v
+			}
+			request := createMCPRequest(full)
+			result, _, err := handler(context.Background(), &request, full)
+			require.NoError(t, err)
+			require.True(t, result.IsError)
+			assert.Contains(t, getErrorResult(t, result).Text, tc.message)
+		}
+	})
+}
+
+func Test_ListRepositoryRulesets(t *testing.T) {
+	tool, _ := ListRepositoryRulesets(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.GetReposRulesetsByOwnerByRepo,
+			expectQueryParams(t, map[string]string{"includes_parents": "false", "page": "1", "per_page": "30"}).andThen(
+				mockResponse(t, http.StatusOK, []*github.RepositoryRuleset{{
+					ID:          github.Ptr(int64(3)),
+					Name:        "main",
+					Target:      github.Ptr(github.RulesetTargetBranch),
+					Enforcement: github.RulesetEnforcementActive,
+					SourceType:  github.Ptr(github.RulesetSourceTypeRepository),
+					Source:      "owner/repo",
+				}}),
+			),
+		),
+	)
+	_, handler := ListRepositoryRulesets(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+	args := map[string]any{"owner": "owner", "repo": "repo", "includes_parents": false}
+	request := createMCPRequest(args)
+	result, _, err := handler(context.Background(), &request, args)
+	require.NoError(t, err)
+	require.False(t, result.IsError, getTextResult(t, result).Text)
+
+	var rulesets []RulesetSummary
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &rulesets))
+	assert.Equal(t, []RulesetSummary{{ID: 3, Name: "main", Target: "branch", Enforcement: "active", SourceType: "Repository", Source: "owner/repo"}}, rulesets)
+}
+
+func Test_GetRepositoryRuleset(t *testing.T) {
+	tool, _ := GetRepositoryRuleset(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.GetReposRulesetsByOwnerByRepoByRulesetId,
+			expectPath(t, "/repos/owner/repo/rulesets/3").andThen(
+				mockResponse(t, http.StatusOK, &github.RepositoryRuleset{
+					ID:          github.Ptr(int64(3)),
+					Name:        "main",
+					Enforcement: github.RulesetEnforcementActive,
+					Rules:       &github.RepositoryRulesetRules{RequiredSignatures: &github.EmptyRuleParameters{}},
+				}),
+			),
+		),
+	)
+	_, handler := GetRepositoryRuleset(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+	args := map[string]any{"owner": "owner", "repo": "repo", "ruleset_id": float64(3)}
+	request := createMCPRequest(args)
+	result, _, err := handler(context.Background(), &request, args)
+	require.NoError(t, err)
+	require.False(t, result.IsError, getTextResult(t, result).Text)
+	assert.Contains(t, getTextResult(t, result).Text, `"type":"required_signatures"`)
+}
+
+func Test_SetRepositoryRuleset(t *testing.T) {
+	tool, _ := SetRepositoryRuleset(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	ruleset := map[string]any{
+		"id":          float64(3),
+		"name":        "main",
+		"target":      "branch",
+		"enforcement": "active",
+		"source":      "owner/repo",
+		"conditions":  map[string]any{"ref_name": map[string]any{"include": []any{"~DEFAULT_BRANCH"}, "exclude": []any{}}},
+		"rules":       []any{map[string]any{"type": "required_signatures"}},
+	}
+	// The fields set by GitHub are dropped; go-github always sends source
+	expected := map[string]any{
+		"name":        "main",
+		"target":      "branch",
+		"enforcement": "active",
+		"source":      "",
+		"conditions":  map[string]any{"ref_name": map[string]any{"include": []any{"~DEFAULT_BRANCH"}, "exclude": []any{}}},
+		"rules":       []any{map[string]any{"type": "required_signatures"}},
+	}
+	saved := &github.RepositoryRuleset{ID: github.Ptr(int64(3)), Name: "main", Enforcement: github.RulesetEnforcementActive}
+
+	t.Run("create", func(t *testing.T) {
+		mockedClient := mock.NewMockedHTTPClient(
+			mock.WithRequestMatchHandler(
+				mock.PostReposRulesetsByOwnerByRepo,
+				expectRequestBody(t, expected).andThen(mockResponse(t, http.StatusCreated, saved)),
+			),
+		)
+		_, handler := SetRepositoryRuleset(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+		args := map[string]any{"owner": "owner", "repo": "repo", "ruleset": ruleset}
+		request := createMCPRequest(args)
+		result, _, err := handler(context.Background(), &request, args)
+		require.NoError(t, err)
+		require.False(t, result.IsError, getTextResult(t, result).Text)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		mockedClient := mock.NewMockedHTTPClient(
+			mock.WithRequestMatchHandler(
+				mock.PutReposRulesetsByOwnerByRepoByRulesetId,
+				expectPath(t, "/repos/owner/repo/rulesets/3").andThen(
+					expectRequestBody(t, expected).andThen(mockResponse(t, http.StatusOK, saved)),
+				),
+			),
+		)
+		_, handler := SetRepositoryRuleset(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+		args := map[string]any{"owner": "owner", "repo": "repo", "ruleset_id": float64(3), "ruleset": ruleset}
+		request := createMCPRequest(args)
+		result, _, err := handler(context.Background(), &request, args)
+		require.NoError(t, err)
+		require.False(t, result.IsError, getTextResult(t, result).Text)
+	})
+
+	t.Run("missing enforcement", func(t *testing.T) {
+		_, handler := SetRepositoryRuleset(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+
+		args := map[string]any{"owner": "owner", "repo": "repo", "ruleset": map[string]any{"name": "main"}}
+		request := createMCPRequest(args)
+		result, _, err := handler(context.Background(), &request, args)
+		require.NoError(t, err)
+		require.True(t, result.IsError)
+		assert.Contains(t, getErrorResult(t, result).Text, "ruleset needs a name and an enforcement")
+	})
+}
@@ -0,0 +1,393 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/github/github-mcp-server/pkg/accounts"
+	ghErrors "github.com/github/github-mcp-server/pkg/errors"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/github/github-mcp-server/pkg/utils"
+	"github.com/google/go-github/v79/github"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/muesli/cache2go"
+	"github.com/shurcooL/githubv4"
+)
+
+const (
+	// branchSnapshotTTL is how long a snapshot is remembered by the server. Snapshots saved as
+	// tags can still be restored afterwards.
+	branchSnapshotTTL       = 24 * time.Hour
+	branchSnapshotCacheName = "branch-snapshots"
+	// branchSnapshotTagPrefix prefixes the lightweight tags created for snapshots
+	branchSnapshotTagPrefix = "snapshot/"
+)
+
+// branchSnapshots holds recent snapshots keyed by account, repository, branch and label.
+var branchSnapshots = cache2go.Cache(branchSnapshotCacheName)
+
+// BranchSnapshot is the recorded head of a branch that can be restored later.
+type BranchSnapshot struct {
+	Label     string    `json:"label"`
+	Owner     string    `json:"owner"`
+	Repo      string    `json:"repo"`
+	Branch    string    `json:"branch"`
+	SHA       string    `json:"sha"`
+	Tag       string    `json:"tag,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RestoreBranchSnapshotResult reports how a branch was moved back to a snapshot.
+type RestoreBranchSnapshotResult struct {
+	Snapshot    BranchSnapshot `json:"snapshot"`
+	PreviousSHA string         `json:"previous_sha"`
+	// DiscardedCommits is the number of commits on the branch after the snapshot
+	DiscardedCommits int    `json:"discarded_commits"`
+	Restored         bool   `json:"restored"`
+	Message          string `json:"message"`
+}
+
+// branchSnapshotKey returns the key of a snapshot taken by the account of ctx, so that no
+// account restores snapshots it did not take.
+func branchSnapshotKey(ctx context.Context, owner, repo, branch, label string) string {
+	return fmt.Sprintf("%s\x00%s/%s:%s@%s", accounts.Key(ctx), owner, repo, branch, label)
+}
+
+// branchSnapshotTag returns the name of the lightweight tag that saves a snapshot.
+func branchSnapshotTag(branch, label string) string {
+	return branchSnapshotTagPrefix + branch + "/" + label
+}
+
+// SnapshotBranch creates a tool to record the current head of a branch as an undo point.
+func SnapshotBranch(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "snapshot_branch",
+		Description: t("TOOL_SNAPSHOT_BRANCH_DESCRIPTION", "Record the current head commit of a branch under a label, as an undo point before risky changes such as multi-chunk pushes. Use restore_branch_snapshot to move the branch back. Snapshots are kept by the server for 24 hours; set create_tag to also save the snapshot as a lightweight tag that outlives the server."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_SNAPSHOT_BRANCH_USER_TITLE", "Snapshot branch"),
+			ReadOnlyHint: false,
+		},
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"owner": {
+					Type:        "string",
+					Description: "Repository owner",
+				},
+				"repo": {
+					Type:        "string",
+					Description: "Repository name",
+				},
+				"branch": {
+					Type:        "string",
+					Description: "Branch to snapshot",
+				},
+				"label": {
+					Type:        "string",
+					Description: "Name of the snapshot, unique per branch. Defaults to the current UTC time, e.g. 20060102-150405",
+				},
+				"create_tag": {
+					Type:        "boolean",
+					Description: "Also create the lightweight tag snapshot/<branch>/<label> pointing to the recorded commit",
+					Default:     json.RawMessage("false"),
+				},
+			},
+			Required: []string{"owner", "repo", "branch"},
+		},
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		repo, err := RequiredParam[string](args, "repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		branch, err := RequiredParam[string](args, "branch")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		label, err := OptionalParam[string](args, "label")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		createTag, err := OptionalParam[bool](args, "create_tag")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		now := time.Now().UTC()
+		if label == "" {
+			label = now.Format("20060102-150405")
+		}
+		if strings.ContainsAny(label, " ~^:?*[\\") {
+			return utils.NewToolResultError(fmt.Sprintf("label %q must not contain spaces or any of ~^:?*[\\", label)), nil, nil
+		}
+		key := branchSnapshotKey(ctx, owner, repo, branch, label)
+		if branchSnapshots.Exists(key) {
+			return utils.NewToolResultError(fmt.Sprintf("a snapshot labelled %q already exists for branch %s; choose another label", label, branch)), nil, nil
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		ref, resp, err := client.Git.GetRef(ctx, owner, repo, "refs/heads/"+branch)
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx,
+				"failed to get branch reference",
+				resp,
+				err,
+			), nil, nil
+		}
+		_ = resp.Body.Close()
+
+		snapshot := BranchSnapshot{
+			Label:     label,
+			Owner:     owner,
+			Repo:      repo,
+			Branch:    branch,
+			SHA:       ref.GetObject().GetSHA(),
+			CreatedAt: now,
+		}
+
+		if createTag {
+			tag := branchSnapshotTag(branch, label)
+			_, resp, err := client.Git.CreateRef(ctx, owner, repo, github.CreateRef{
+				Ref: "refs/tags/" + tag,
+				SHA: snapshot.SHA,
+			})
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					fmt.Sprintf("failed to create tag %s", tag),
+					resp,
+					err,
+				), nil, nil
+			}
+			_ = resp.Body.Close()
+			snapshot.Tag = tag
+		}
+
+		branchSnapshots.Add(key, branchSnapshotTTL, snapshot)
+
+		return MarshalledTextResult(snapshot), nil, nil
+	})
+
+	return tool, handler
+}
+
+// loadBranchSnapshot returns the snapshot recorded by this server, or the one saved as a tag.
+func loadBranchSnapshot(ctx context.Context, client *github.Client, owner, repo, branch, label string) (BranchSnapshot, *github.Response, error) {
+	if item, err := branchSnapshots.Value(branchSnapshotKey(ctx, owner, repo, branch, label)); err == nil {
+		if snapshot, ok := item.Data().(BranchSnapshot); ok {
+			return snapshot, nil, nil
+		}
+	}
+
+	tag := branchSnapshotTag(branch, label)
+	ref, resp, err := client.Git.GetRef(ctx, owner, repo, "refs/tags/"+tag)
+	if err != nil {
+		return BranchSnapshot{}, resp, err
+	}
+	_ = resp.Body.Close()
+	return BranchSnapshot{
+		Label:  label,
+		Owner:  owner,
+		Repo:   repo,
+		Branch: branch,
+		SHA:    ref.GetObject().GetSHA(),
+		Tag:    tag,
+	}, resp, nil
+}
+
+// RestoreBranchSnapshot creates a tool to move a branch back to a snapshot taken with snapshot_branch.
+func RestoreBranchSnapshot(getClient GetClientFn, getGQLClient GetGQLClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "restore_branch_snapshot",
+		Description: t("TOOL_RESTORE_BRANCH_SNAPSHOT_DESCRIPTION", "Force-update a branch back to a commit recorded with snapshot_branch, discarding the commits made after it. The restore is refused if the branch no longer contains the snapshot commit, unless allow_diverged is set, if the branch is not at expected_head_sha, or if it moves during the restore."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:           t("TOOL_RESTORE_BRANCH_SNAPSHOT_USER_TITLE", "Restore branch snapshot"),
+			ReadOnlyHint:    false,
+			DestructiveHint: jsonschema.Ptr(true),
+		},
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"owner": {
+					Type:        "string",
+					Description: "Repository owner",
+				},
+				"repo": {
+					Type:        "string",
+					Description: "Repository name",
+				},
+				"branch": {
+					Type:        "string",
+					Description: "Branch to restore",
+				},
+				"label": {
+					Type:        "string",
+					Description: "Label of the snapshot returned by snapshot_branch",
+				},
+				"expected_head_sha": {
+					Type:        "string",
+					Description: "SHA the branch is expected to point to. The restore is refused if the branch has moved",
+				},
+				"allow_diverged": {
+					Type:        "boolean",
+					Description: "Restore even if the snapshot commit is not an ancestor of the branch head, e.g. after the branch was force-pushed (default: false)",
+					Default:     json.RawMessage("false"),
+				},
+			},
+			Required: []string{"owner", "repo", "branch", "label"},
+		},
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		repo, err := RequiredParam[string](args, "repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		branch, err := RequiredParam[string](args, "branch")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		label, err := RequiredParam[string](args, "label")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		expectedHeadSHA, err := OptionalParam[string](args, "expected_head_sha")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		allowDiverged, err := OptionalParam[bool](args, "allow_diverged")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		snapshot, resp, err := loadBranchSnapshot(ctx, client, owner, repo, branch, label)
+		if err != nil {
+			if resp != nil && resp.StatusCode == http.StatusNotFound {
+				return utils.NewToolResultError(fmt.Sprintf("no snapshot labelled %q for branch %s; it may have expired without a tag", label, branch)), nil, nil
+			}
+			return ghErrors.NewGitHubAPIErrorResponse(ctx,
+				"failed to get snapshot tag",
+				resp,
+				err,
+			), nil, nil
+		}
+
+		ref, resp, err := client.Git.GetRef(ctx, owner, repo, "refs/heads/"+branch)
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx,
+				"failed to get branch reference",
+				resp,
+				err,
+			), nil, nil
+		}
+		_ = resp.Body.Close()
+
+		result := RestoreBranchSnapshotResult{
+			Snapshot:    snapshot,
+			PreviousSHA: ref.GetObject().GetSHA(),
+		}
+		if expectedHeadSHA != "" && result.PreviousSHA != expectedHeadSHA {
+			return utils.NewToolResultError(fmt.Sprintf(
+				"branch %s is at %s but expected %s; inspect the branch before restoring",
+				branch, result.PreviousSHA, expectedHeadSHA,
+			)), nil, nil
+		}
+		if result.PreviousSHA == snapshot.SHA {
+			result.Message = fmt.Sprintf("branch %s is already at snapshot %q", branch, label)
+			return MarshalledTextResult(result), nil, nil
+		}
+
+		comparison, resp, err := client.Repositories.CompareCommits(ctx, owner, repo, snapshot.SHA, result.PreviousSHA, &github.ListOptions{PerPage: 1})
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx,
+				"failed to compare branch with snapshot",
+				resp,
+				err,
+			), nil, nil
+		}
+		_ = resp.Body.Close()
+
+		result.DiscardedCommits = comparison.GetAheadBy()
+		if comparison.GetStatus() != "ahead" && !allowDiverged {
+			return utils.NewToolResultError(fmt.Sprintf(
+				"branch %s no longer contains commit %s of snapshot %q (comparison status %q); set allow_diverged to restore anyway",
+				branch, snapshot.SHA, label, comparison.GetStatus(),
+			)), nil, nil
+		}
+
+		if err := compareAndSwapBranch(ctx, getGQLClient, owner, repo, branch, result.PreviousSHA, snapshot.SHA); err != nil {
+			return ghErrors.NewGitHubGraphQLErrorResponse(ctx,
+				fmt.Sprintf("failed to restore branch %s, which is refused if it moved from %s since it was checked", branch, result.PreviousSHA),
+				err,
+			), nil, nil
+		}
+
+		result.Restored = true
+		result.Message = fmt.Sprintf("branch %s restored to snapshot %q, discarding %d commits", branch, label, result.DiscardedCommits)
+		return MarshalledTextResult(result), nil, nil
+	})
+
+	return tool, handler
+}
+
+// compareAndSwapBranch force-updates branch to sha only if it still points to before. Unlike a
+// REST force update, the check and the update are one atomic step, so that a commit pushed in
+// between is never discarded.
+func compareAndSwapBranch(ctx context.Context, getGQLClient GetGQLClientFn, owner, repo, branch, before, sha string) error {
+	client, err := getGQLClient(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get GitHub GQL client: %w", err)
+	}
+
+	var q struct {
+		Repository struct {
+			ID githubv4.ID
+		} `graphql:"repository(owner: $owner, name: $repo)"`
+	}
+	vars := map[string]interface{}{
+		"owner": githubv4.String(owner),
+		"repo":  githubv4.String(repo),
+	}
+	if err := client.Query(ctx, &q, vars); err != nil {
+		return err
+	}
+
+	var mutation struct {
+		UpdateRefs struct {
+			ClientMutationID githubv4.String
+		} `graphql:"updateRefs(input: $input)"`
+	}
+	beforeOID := githubv4.GitObjectID(before)
+	input := githubv4.UpdateRefsInput{
+		RepositoryID: q.Repository.ID,
+		RefUpdates: []githubv4.RefUpdate{{
+			Name:      githubv4.GitRefname("refs/heads/" + branch),
+			AfterOid:  githubv4.GitObjectID(sha),
+			BeforeOid: &beforeOID,
+			Force:     githubv4.NewBoolean(true),
+		}},
+	}
+	return client.Mutate(ctx, &mutation, input, nil)
+}
@@ -0,0 +1,244 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/github/github-mcp-server/internal/githubv4mock"
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/accounts"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v79/github"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SnapshotBranch(t *testing.T) {
+	mockClient := github.NewClient(nil)
+	tool, _ := SnapshotBranch(stubGetClientFn(mockClient), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	schema, ok := tool.InputSchema.(*jsonschema.Schema)
+	require.True(t, ok, "InputSchema should be *jsonschema.Schema")
+	assert.Contains(t, schema.Properties, "label")
+	assert.Contains(t, schema.Properties, "create_tag")
+	assert.ElementsMatch(t, schema.Required, []string{"owner", "repo", "branch"})
+
+	t.Cleanup(branchSnapshots.Flush)
+
+	client := github.NewClient(mock.NewMockedHTTPClient(
+		mock.WithRequestMatch(
+			mock.GetReposGitRefByOwnerByRepoByRef,
+			&github.Reference{
+				Ref:    github.Ptr("refs/heads/main"),
+				Object: &github.GitObject{SHA: github.Ptr("head-sha")},
+			},
+		),
+		mock.WithRequestMatchHandler(
+			mock.PostReposGitRefsByOwnerByRepo,
+			expectRequestBody(t, map[string]any{
+				"ref": "refs/tags/snapshot/main/before-push",
+				"sha": "head-sha",
+			}).andThen(
+				mockResponse(t, http.StatusCreated, &github.Reference{Ref: github.Ptr("refs/tags/snapshot/main/before-push")}),
+			),
+		),
+	))
+	_, handler := SnapshotBranch(stubGetClientFn(client), translations.NullTranslationHelper)
+
+	args := map[string]interface{}{
+		"owner":      "owner",
+		"repo":       "repo",
+		"branch":     "main",
+		"label":      "before-push",
+		"create_tag": true,
+	}
+	request := createMCPRequest(args)
+	result, _, err := handler(context.Background(), &request, args)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+
+	var snapshot BranchSnapshot
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &snapshot))
+	assert.Equal(t, "head-sha", snapshot.SHA)
+	assert.Equal(t, "snapshot/main/before-push", snapshot.Tag)
+
+	// Labels are unique per branch
+	result, _, err = handler(context.Background(), &request, args)
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	assert.Contains(t, getErrorResult(t, result).Text, "already exists")
+}
+
+func Test_RestoreBranchSnapshot(t *testing.T) {
+	mockClient := github.NewClient(nil)
+	tool, _ := RestoreBranchSnapshot(stubGetClientFn(mockClient), nil, translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	schema, ok := tool.InputSchema.(*jsonschema.Schema)
+	require.True(t, ok, "InputSchema should be *jsonschema.Schema")
+	assert.ElementsMatch(t, schema.Required, []string{"owner", "repo", "branch", "label"})
+
+	t.Cleanup(branchSnapshots.Flush)
+	branchSnapshots.Add(branchSnapshotKey(context.Background(), "owner", "repo", "main", "recorded"), branchSnapshotTTL, BranchSnapshot{
+		Label: "recorded", Owner: "owner", Repo: "repo", Branch: "main", SHA: "snapshot-sha",
+	})
+
+	// refs answers the branch ref with the current head and the tag ref with the snapshot commit
+	refs := mock.WithRequestMatchHandler(
+		mock.GetReposGitRefByOwnerByRepoByRef,
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/repos/owner/repo/git/ref/heads/main":
+				mockResponse(t, http.StatusOK, &github.Reference{Object: &github.GitObject{SHA: github.Ptr("head-sha")}})(w, r)
+			case "/repos/owner/repo/git/ref/tags/snapshot/main/tagged":
+				mockResponse(t, http.StatusOK, &github.Reference{Object: &github.GitObject{SHA: github.Ptr("snapshot-sha")}})(w, r)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"message": "Not Found"}`))
+			}
+		}),
+	)
+	compare := func(status string, aheadBy int) mock.MockBackendOption {
+		return mock.WithRequestMatchHandler(
+			mock.GetReposCompareByOwnerByRepoByBasehead,
+			expectPath(t, "/repos/owner/repo/compare/snapshot-sha...head-sha").andThen(
+				mockResponse(t, http.StatusOK, &github.CommitsComparison{Status: github.Ptr(status), AheadBy: github.Ptr(aheadBy)}),
+			),
+		)
+	}
+	// The branch is moved back with a compare-and-swap from the head the tool saw
+	var mutation struct {
+		UpdateRefs struct {
+			ClientMutationID githubv4.String
+		} `graphql:"updateRefs(input: $input)"`
+	}
+	repoQuery := githubv4mock.NewQueryMatcher(
+		"query($owner:String!$repo:String!){repository(owner: $owner, name: $repo){id}}",
+		map[string]any{"owner": "owner", "repo": "repo"},
+		githubv4mock.DataResponse(map[string]any{"repository": map[string]any{"id": "R_1"}}),
+	)
+	updateRefs := func(response githubv4mock.GQLResponse) githubv4mock.Matcher {
+		return githubv4mock.NewMutationMatcher(mutation, githubv4.UpdateRefsInput{
+			RepositoryID: "R_1",
+			RefUpdates: []githubv4.RefUpdate{{
+				Name:      "refs/heads/main",
+				AfterOid:  "snapshot-sha",
+				BeforeOid: githubv4mock.Ptr(githubv4.GitObjectID("head-sha")),
+				Force:     githubv4.NewBoolean(true),
+			}},
+		}, nil, response)
+	}
+	swapped := []githubv4mock.Matcher{repoQuery, updateRefs(githubv4mock.DataResponse(map[string]any{"updateRefs": map[string]any{"clientMutationId": ""}}))}
+
+	tests := []struct {
+		name           string
+		options        []mock.MockBackendOption
+		gql            []githubv4mock.Matcher
+		args           map[string]interface{}
+		account        string
+		expectError    string
+		expectRestored bool
+		expectDiscard  int
+	}{
+		{
+			name:           "restores a recorded snapshot",
+			options:        []mock.MockBackendOption{refs, compare("ahead", 3)},
+			gql:            swapped,
+			args:           map[string]interface{}{"label": "recorded"},
+			expectRestored: true,
+			expectDiscard:  3,
+		},
+		{
+			name:           "restores a snapshot saved as a tag",
+			options:        []mock.MockBackendOption{refs, compare("ahead", 1)},
+			gql:            swapped,
+			args:           map[string]interface{}{"label": "tagged"},
+			expectRestored: true,
+			expectDiscard:  1,
+		},
+		{
+			name:        "refuses a diverged branch",
+			options:     []mock.MockBackendOption{refs, compare("diverged", 2)},
+			args:        map[string]interface{}{"label": "recorded"},
+			expectError: "set allow_diverged",
+		},
+		{
+			name:           "restores a diverged branch when allowed",
+			options:        []mock.MockBackendOption{refs, compare("diverged", 2)},
+			gql:            swapped,
+			args:           map[string]interface{}{"label": "recorded", "allow_diverged": true},
+			expectRestored: true,
+			expectDiscard:  2,
+		},
+		{
+			name:        "refuses when the branch moved",
+			options:     []mock.MockBackendOption{refs},
+			args:        map[string]interface{}{"label": "recorded", "expected_head_sha": "other-sha"},
+			expectError: "expected other-sha",
+		},
+		{
+			name:        "refuses when the branch moves during the restore",
+			options:     []mock.MockBackendOption{refs, compare("ahead", 3)},
+			gql:         []githubv4mock.Matcher{repoQuery, updateRefs(githubv4mock.ErrorResponse("Expected branch to point to \"head-sha\" but it did not"))},
+			args:        map[string]interface{}{"label": "recorded"},
+			expectError: "moved from head-sha",
+		},
+		{
+			name:        "unknown snapshot",
+			options:     []mock.MockBackendOption{refs},
+			args:        map[string]interface{}{"label": "missing"},
+			expectError: `no snapshot labelled "missing"`,
+		},
+		{
+			name:        "snapshot recorded by another account",
+			options:     []mock.MockBackendOption{refs},
+			args:        map[string]interface{}{"label": "recorded"},
+			account:     "work",
+			expectError: `no snapshot labelled "recorded"`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client := github.NewClient(mock.NewMockedHTTPClient(tc.options...))
+			gqlClient := githubv4.NewClient(githubv4mock.NewMockedHTTPClient(tc.gql...))
+			_, handler := RestoreBranchSnapshot(stubGetClientFn(client), stubGetGQLClientFn(gqlClient), translations.NullTranslationHelper)
+
+			args := map[string]interface{}{
+				"owner":  "owner",
+				"repo":   "repo",
+				"branch": "main",
+			}
+			for k, v := range tc.args {
+				args[k] = v
+			}
+			ctx := context.Background()
+			if tc.account != "" {
+				ctx = accounts.ContextWithAccount(ctx, tc.account)
+			}
+			request := createMCPRequest(args)
+			result, _, err := handler(ctx, &request, args)
+			require.NoError(t, err)
+
+			if tc.expectError != "" {
+				require.True(t, result.IsError)
+				assert.Contains(t, getErrorResult(t, result).Text, tc.expectError)
+				return
+			}
+			require.False(t, result.IsError)
+
+			var restore RestoreBranchSnapshotResult
+			require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &restore))
+			assert.Equal(t, tc.expectRestored, restore.Restored)
+			assert.Equal(t, tc.expectDiscard, restore.DiscardedCommits)
+			assert.Equal(t, "head-sha", restore.PreviousSHA)
+			assert.Equal(t, "snapshot-sha", restore.Snapshot.SHA)
+		})
+	}
+}
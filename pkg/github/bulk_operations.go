@@ -1,11 +1,24 @@
 package github
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	ghErrors "github.com/github/github-mcp-server/pkg/errors"
+	"github.com/github/github-mcp-server/pkg/ratelimit"
+	"github.com/github/github-mcp-server/pkg/sanitize"
+	"github.com/github/github-mcp-server/pkg/signing"
 	"github.com/github/github-mcp-server/pkg/translations"
 	"github.com/github/github-mcp-server/pkg/utils"
 	"github.com/google/go-github/v79/github"
@@ -21,17 +34,50 @@ type ChunkResult struct {
 	Success      bool     `json:"success"`
 	Error        string   `json:"error,omitempty"`
 	Files        []string `json:"files"`
+	// State is one of pushed, failed or pending (not attempted yet)
+	State string `json:"state"`
+	// Retries is the number of API calls repeated after transient failures while pushing the chunk
+	Retries int `json:"retries"`
+	// NoChanges is set when the chunk's files were already on the branch, so no commit was made
+	NoChanges bool `json:"no_changes,omitempty"`
+	// ErrorCode is the code of a failure the client can act on, such as EMPTY_REPOSITORY
+	ErrorCode string `json:"error_code,omitempty"`
 }
 
 // PushFilesChunkedResult represents the overall result of a chunked push operation
 type PushFilesChunkedResult struct {
-	TotalFiles       int           `json:"total_files"`
-	TotalChunks      int           `json:"total_chunks"`
-	SuccessfulChunks int           `json:"successful_chunks"`
-	FailedChunks     int           `json:"failed_chunks"`
-	FinalCommitSHA   string        `json:"final_commit_sha,omitempty"`
-	Chunks           []ChunkResult `json:"chunks"`
-	FullySuccessful  bool          `json:"fully_successful"`
+	// OperationID identifies the push for resume_push_chunked while it is incomplete
+	OperationID      string            `json:"operation_id"`
+	TotalFiles       int               `json:"total_files"`
+	TotalChunks      int               `json:"total_chunks"`
+	SuccessfulChunks int               `json:"successful_chunks"`
+	FailedChunks     int               `json:"failed_chunks"`
+	FinalCommitSHA   string            `json:"final_commit_sha,omitempty"`
+	Chunks           []ChunkResult     `json:"chunks,omitempty"`
+	FullySuccessful  bool              `json:"fully_successful"`
+	RenamedPaths     map[string]string `json:"renamed_paths,omitempty"`
+	// RenamedPathCount replaces RenamedPaths when result_detail is not full
+	RenamedPathCount int  `json:"renamed_path_count,omitempty"`
+	Resumable        bool `json:"resumable"`
+	// NextChunkIndex is the 1-based index of the first chunk still to be pushed
+	NextChunkIndex int `json:"next_chunk_index,omitempty"`
+	// RetryBudget reports the retries consumed across all chunks of this call
+	RetryBudget *ratelimit.BudgetUsage `json:"retry_budget,omitempty"`
+	// PullRequest reports the pull request opened when create_pull_request is set
+	PullRequest *ChunkedPullRequest `json:"pull_request,omitempty"`
+	// NoChanges is set when every file was already on the branch, so no commit was made
+	NoChanges bool `json:"no_changes,omitempty"`
+	// PushedPaths and UnpushedPaths replace Chunks when result_detail is paths_only
+	PushedPaths   []string `json:"pushed_paths,omitempty"`
+	UnpushedPaths []string `json:"unpushed_paths,omitempty"`
+	// Warnings are the advisory findings of validating the files
+	Warnings []ValidationWarning `json:"warnings,omitempty"`
+	// IgnoredPaths lists the files left out by respect_gitignore and ignore_patterns
+	IgnoredPaths []string `json:"ignored_paths,omitempty"`
+	// PacingWaitMs is the time this call spent waiting between chunks to pace the push
+	PacingWaitMs int64 `json:"pacing_wait_ms,omitempty"`
+	// LFSObjects lists the files stored in Git LFS and committed as pointer files
+	LFSObjects []LFSObject `json:"lfs_objects,omitempty"`
 }
 
 // Deprecated: use FileEntry from validation.go instead
@@ -41,14 +87,15 @@ type fileEntry = FileEntry
 // PushFilesChunked creates a tool to push multiple files in chunks, creating multiple commits.
 // This is designed for large file operations that exceed the limits of push_files.
 func PushFilesChunked(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	limits := CurrentPushLimits()
 	tool := mcp.Tool{
 		Name:        "push_files_chunked",
-		Description: t("TOOL_PUSH_FILES_CHUNKED_DESCRIPTION", "Push multiple files to a GitHub repository in chunks, creating multiple commits. Use this for large batches of files (>100 files) that exceed push_files limits."),
+		Description: t("TOOL_PUSH_FILES_CHUNKED_DESCRIPTION", "Push multiple files to a GitHub repository in chunks, creating multiple commits. Use this for large batches of files (>100 files) that exceed push_files limits. If the push stops part way, the result includes an operation_id that can be passed to resume_push_chunked. Set create_pull_request to push to a new branch and open a pull request against branch instead, e.g. when branch is protected."),
 		Annotations: &mcp.ToolAnnotations{
 			Title:        t("TOOL_PUSH_FILES_CHUNKED_USER_TITLE", "Push files in chunks"),
 			ReadOnlyHint: false,
 		},
-		InputSchema: &jsonschema.Schema{
+		InputSchema: withCommitIdentity(&jsonschema.Schema{
 			Type: "object",
 			Properties: map[string]*jsonschema.Schema{
 				"owner": {
@@ -61,46 +108,65 @@ func PushFilesChunked(getClient GetClientFn, t translations.TranslationHelperFun
 				},
 				"branch": {
 					Type:        "string",
-					Description: "Branch to push to",
+					Description: "Branch to push to. Either branch or ref is required",
 				},
+				"ref": refSchema(),
 				"files": {
 					Type:        "array",
-					Description: "Array of file objects to push, each object with path (string) and content (string)",
-					Items: &jsonschema.Schema{
-						Type: "object",
-						Properties: map[string]*jsonschema.Schema{
-							"path": {
-								Type:        "string",
-								Description: "path to the file",
-							},
-							"content": {
-								Type:        "string",
-								Description: "file content",
-							},
-						},
-						Required: []string{"path", "content"},
-					},
+					Description: "Array of file objects to push, each object with path (string) and either content (string) or upload_id (string)",
+					Items:       lfsFileObjectSchema(),
 				},
 				"message": {
 					Type:        "string",
-					Description: "Base commit message (chunk number will be appended)",
+					Description: "Base commit message (chunk number will be appended unless message_template is set)",
 				},
+				"message_template": messageTemplateSchema(),
 				"chunk_size": {
 					Type:        "integer",
-					Description: fmt.Sprintf("Number of files per chunk (default: %d, max: %d)", DefaultChunkSize, MaxChunkSize),
-					Default:     json.RawMessage(fmt.Sprintf("%d", DefaultChunkSize)),
+					Description: fmt.Sprintf("Number of files per chunk (default: %d, max: %d)", limits.DefaultChunkSize, limits.MaxChunkSize),
+					Default:     json.RawMessage(fmt.Sprintf("%d", limits.DefaultChunkSize)),
 				},
 				"continue_on_error": {
 					Type:        "boolean",
 					Description: "Continue processing remaining chunks if one fails (default: false)",
 					Default:     json.RawMessage("false"),
 				},
+				"sanitize_paths": {
+					Type:        "string",
+					Description: "Path sanitization policy applied before pushing. 'none' keeps paths as given, 'normalize' folds unicode confusables, replaces whitespace with dashes and strips trailing dots, 'strict' additionally replaces any character outside [A-Za-z0-9._-] (including emoji) with '_'. Renamed paths are reported in the result",
+					Enum:        []any{"none", "normalize", "strict"},
+					Default:     json.RawMessage(`"none"`),
+				},
+				"max_retries":            maxRetriesSchema(),
+				"allow_empty":            allowEmptySchema(),
+				"allow_secrets":          allowSecretsSchema(),
+				"normalize_line_endings": normalizeLineEndingsSchema(),
+				"lfs_threshold_bytes":    lfsThresholdSchema(),
+				"respect_gitignore":      respectGitignoreSchema(),
+				"ignore_patterns":        ignorePatternsSchema(),
+				"result_detail":          resultDetailSchema(),
+				"group_by":               groupBySchema(),
+				"pacing":                 pacingSchema(),
+				"chunk_delay_ms":         chunkDelaySchema(),
+				"create_pull_request": {
+					Type:        "boolean",
+					Description: "Push the chunks to a new branch created from branch, then open a pull request into branch listing every chunk (default: false)",
+					Default:     json.RawMessage("false"),
+				},
+				"head_branch": {
+					Type:        "string",
+					Description: "Name of the new branch when create_pull_request is set. Must not exist yet (default: a generated chunked-push/ name)",
+				},
+				"pull_request_title": {
+					Type:        "string",
+					Description: "Title of the pull request when create_pull_request is set (default: message)",
+				},
 			},
-			Required: []string{"owner", "repo", "branch", "files", "message"},
-		},
+			Required: []string{"owner", "repo", "files", "message"},
+		}),
 	}
 
-	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, req *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
 		owner, err := RequiredParam[string](args, "owner")
 		if err != nil {
 			return utils.NewToolResultError(err.Error()), nil, nil
@@ -109,7 +175,7 @@ func PushFilesChunked(getClient GetClientFn, t translations.TranslationHelperFun
 		if err != nil {
 			return utils.NewToolResultError(err.Error()), nil, nil
 		}
-		branch, err := RequiredParam[string](args, "branch")
+		targetRef, err := targetRefFromArgs(args)
 		if err != nil {
 			return utils.NewToolResultError(err.Error()), nil, nil
 		}
@@ -118,184 +184,686 @@ func PushFilesChunked(getClient GetClientFn, t translations.TranslationHelperFun
 			return utils.NewToolResultError(err.Error()), nil, nil
 		}
 
-		chunkSize, err := OptionalIntParamWithDefault(args, "chunk_size", DefaultChunkSize)
+		continueOnError, err := OptionalParam[bool](args, "continue_on_error")
 		if err != nil {
 			return utils.NewToolResultError(err.Error()), nil, nil
 		}
-		if chunkSize > MaxChunkSize {
-			chunkSize = MaxChunkSize
+
+		identity, err := commitIdentityFromArgs(ctx, args)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
 		}
-		if chunkSize < 1 {
-			chunkSize = 1
+		retry, err := chunkRetryConfigFromArgs(args)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
 		}
-
-		continueOnError, err := OptionalParam[bool](args, "continue_on_error")
+		allowEmpty, err := OptionalParam[bool](args, "allow_empty")
 		if err != nil {
 			return utils.NewToolResultError(err.Error()), nil, nil
 		}
-
-		filesObj, ok := args["files"].([]interface{})
-		if !ok {
-			return utils.NewToolResultError("files parameter must be an array of objects with path and content"), nil, nil
+		detail, err := resultDetailFromArgs(req, args)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
 		}
-
-		if len(filesObj) == 0 {
-			return utils.NewToolResultError("files array cannot be empty"), nil, nil
+		pacing, err := chunkPacingFromArgs(args, chunkPacingProfiles[PacingAggressive])
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
 		}
-
-		// Validate all files using shared validation logic
-		validationResult, files, err := ValidateFiles(filesObj)
+		messageTemplate, err := OptionalParam[string](args, "message_template")
 		if err != nil {
 			return utils.NewToolResultError(err.Error()), nil, nil
 		}
-
-		// Check for oversized files
-		for _, path := range validationResult.OversizedFiles {
-			if result, err := ValidateFileSize(path, validationResult.LargestFileSize); result != nil || err != nil {
-				return result, nil, nil
+		if messageTemplate != "" {
+			if err := validateMessageTemplate(messageTemplate); err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
 			}
 		}
+		createPR, err := OptionalParam[bool](args, "create_pull_request")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		headBranch, err := OptionalParam[string](args, "head_branch")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		prTitle, err := OptionalParam[string](args, "pull_request_title")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		if !createPR && (headBranch != "" || prTitle != "") {
+			return utils.NewToolResultError("head_branch and pull_request_title require create_pull_request"), nil, nil
+		}
+		branch, isBranch := strings.CutPrefix(targetRef, "refs/heads/")
+		if createPR && !isBranch {
+			return utils.NewToolResultError("create_pull_request requires a branch; pull requests cannot target tags"), nil, nil
+		}
+		if createPR && headBranch == branch {
+			return utils.NewToolResultError("head_branch must differ from branch, which is the base of the pull request"), nil, nil
+		}
 
 		client, err := getClient(ctx)
 		if err != nil {
 			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
 		}
 
-		// Create size-aware chunks using safety margin
-		maxChunkBytes := GetMaxChunkSize()
-		var chunks [][]FileEntry
+		prepared, err := chunkedPushFilesFromArgs(ctx, client, owner, repo, targetRef, args)
+		if err != nil {
+			return toolErrorResult(err), nil, nil
+		}
+
+		op := newChunkedPushOperation(ctx, owner, repo, targetRef, message, prepared.Chunks)
+		op.RenamedPaths = prepared.RenamedPaths
+		op.Identity = identity
+		op.Retry = retry
+		op.AllowEmpty = allowEmpty
+		op.MessageTemplate = messageTemplate
+		op.Pacing = pacing
+		// Files stored in Git LFS are uploaded before any chunk, which commits their pointers
+		if err := op.uploadLFSFiles(ctx, client); err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		if createPR {
+			if headBranch == "" {
+				headBranch = "chunked-push/" + op.ID[:12]
+			}
+			if prTitle == "" {
+				prTitle = message
+			}
+			// Branch off the target so that it is never pushed to directly
+			ref, resp, err := client.Git.GetRef(ctx, owner, repo, targetRef)
+			if isEmptyRepository(resp, err) {
+				return validationErrorResult(emptyRepositoryError(owner, repo, err)), nil, nil
+			}
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to get branch reference", resp, err), nil, nil
+			}
+			_ = resp.Body.Close()
+			_, resp, err = client.Git.CreateRef(ctx, owner, repo, github.CreateRef{
+				Ref: "refs/heads/" + headBranch,
+				SHA: ref.GetObject().GetSHA(),
+			})
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx, fmt.Sprintf("failed to create branch %s", headBranch), resp, err), nil, nil
+			}
+			_ = resp.Body.Close()
 
-		var currentChunk []fileEntry
-		var currentChunkSize int64
-		var currentChunkFileCount int
+			op.Ref = "refs/heads/" + headBranch
+			op.PullRequest = &ChunkedPullRequest{Base: branch, Head: headBranch, Title: prTitle}
+		}
+		op.notify = chunkNotifier(req, "push_files_chunked")
+		op.run(ctx, client, continueOnError)
+		op.openPullRequest(ctx, client)
+		result := op.result().withDetail(detail)
+		result.Warnings = prepared.Warnings
+		result.IgnoredPaths = prepared.IgnoredPaths
 
-		for _, file := range files {
-			fileSize := int64(len(file.Content))
+		r, err := json.Marshal(result)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to marshal response: %w", err)
+		}
 
-			// Check if adding this file would exceed limits
-			wouldExceedSize := currentChunkSize+fileSize > maxChunkBytes
-			wouldExceedCount := currentChunkFileCount >= chunkSize
+		return utils.NewToolResultText(string(r)), nil, nil
+	})
 
-			// Start a new chunk if we'd exceed either limit (and current chunk is not empty)
-			if len(currentChunk) > 0 && (wouldExceedSize || wouldExceedCount) {
-				chunks = append(chunks, currentChunk)
-				currentChunk = []fileEntry{}
-				currentChunkSize = 0
-				currentChunkFileCount = 0
-			}
+	return withIdempotencyKey(tool, handler)
+}
+
+// chunkedPushFiles holds the files of a chunked push, planned into chunks.
+type chunkedPushFiles struct {
+	Chunks       [][]FileEntry
+	Warnings     []ValidationWarning
+	IgnoredPaths []string
+	RenamedPaths map[string]string
+}
+
+// chunkedPushFilesFromArgs prepares the files argument of a chunked push to ref: ignored files
+// are left out, the rest validated, checked against the limits of the host and sanitized, then
+// planned into chunks. resume_push_chunked prepares resent files the same
+// way, so that they produce the chunks of the original call.
+func chunkedPushFilesFromArgs(ctx context.Context, client *github.Client, owner, repo, ref string, args map[string]any) (*chunkedPushFiles, error) {
+	chunkSize, err := OptionalIntParamWithDefault(args, "chunk_size", CurrentPushLimits().DefaultChunkSize)
+	if err != nil {
+		return nil, err
+	}
+	groupBy, err := groupByFromArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	sanitizePolicy, err := OptionalParam[string](args, "sanitize_paths")
+	if err != nil {
+		return nil, err
+	}
+	pathPolicy, err := sanitize.ParsePathPolicy(sanitizePolicy)
+	if err != nil {
+		return nil, err
+	}
+	validationOpts, err := fileValidationOptionsFromArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	validationOpts, err = lfsValidationOptionsFromArgs(args, validationOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	filesObj, ok := args["files"].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("files parameter must be an array of objects with path and content")
+	}
+	if len(filesObj) == 0 {
+		return nil, fmt.Errorf("files array cannot be empty")
+	}
+	filesObj, err = resolveUploadedFiles(ctx, filesObj)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ignored files are left out before validation, so that they count against no limit
+	ignore, err := ignoreRulesFromArgs(ctx, client, owner, repo, ref, args)
+	if err != nil {
+		return nil, err
+	}
+	filesObj, ignoredPaths := filterIgnoredFiles(filesObj, ignore)
+	if len(filesObj) == 0 {
+		return nil, fmt.Errorf("all %d files are ignored, so there is nothing to push", len(ignoredPaths))
+	}
+
+	validationResult, files, err := ValidateFilesWithOptions(filesObj, validationOpts)
+	if err != nil {
+		return nil, err
+	}
+	// Check the largest file against the limits of the host
+	if _, err := ValidateFileSize(pushLimitsFor(ctx, client), validationResult.LargestFile, validationResult.LargestFileSize); err != nil {
+		return nil, err
+	}
+	renamedPaths, err := SanitizeFilePaths(files, pathPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	return &chunkedPushFiles{
+		Chunks:       planGroupedChunks(files, chunkSize, groupBy),
+		Warnings:     validationResult.Warnings,
+		IgnoredPaths: ignoredPaths,
+		RenamedPaths: renamedPaths,
+	}, nil
+}
+
+// signedCommitOptions returns the options to sign commit with the server's configured signer,
+// or nil when signing is not configured. The signer's identity becomes the committer, and the
+// author unless one is already set, since GitHub verifies the signature against the committer.
+func signedCommitOptions(ctx context.Context, commit *github.Commit) *github.CreateCommitOptions {
+	signer, ok := signing.SignerFromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	commit.Committer = signer.Identity(time.Now())
+	if commit.Author == nil {
+		commit.Author = commit.Committer
+	} else if commit.Author.Date == nil {
+		// The author date is part of the signed payload, so it cannot be left to GitHub
+		commit.Author.Date = commit.Committer.Date
+	}
+	return &github.CreateCommitOptions{Signer: signer}
+}
+
+// commitIdentity overrides the author and committer of commits created by the bulk tools.
+// Nil fields leave GitHub's default, the authenticated user, in place.
+type commitIdentity struct {
+	Author    *github.CommitAuthor
+	Committer *github.CommitAuthor
+}
+
+// withCommitIdentity adds the author_name, author_email and committer parameters to schema.
+func withCommitIdentity(schema *jsonschema.Schema) *jsonschema.Schema {
+	schema.Properties["author_name"] = &jsonschema.Schema{
+		Type:        "string",
+		Description: "Name of the commit author, e.g. a bot or the person the change is made for. Requires author_email. Defaults to the authenticated user",
+	}
+	schema.Properties["author_email"] = &jsonschema.Schema{
+		Type:        "string",
+		Description: "Email of the commit author. Requires author_name",
+	}
+	schema.Properties["committer"] = &jsonschema.Schema{
+		Type:        "object",
+		Description: "Committer identity. Defaults to the author. Cannot be set when the server signs commits, since the signing identity is the committer",
+		Properties: map[string]*jsonschema.Schema{
+			"name": {
+				Type:        "string",
+				Description: "Committer name",
+			},
+			"email": {
+				Type:        "string",
+				Description: "Committer email",
+			},
+		},
+		Required: []string{"name", "email"},
+	}
+	return schema
+}
+
+// commitIdentityFromArgs reads the parameters added by withCommitIdentity.
+func commitIdentityFromArgs(ctx context.Context, args map[string]any) (commitIdentity, error) {
+	var identity commitIdentity
+
+	authorName, err := OptionalParam[string](args, "author_name")
+	if err != nil {
+		return identity, err
+	}
+	authorEmail, err := OptionalParam[string](args, "author_email")
+	if err != nil {
+		return identity, err
+	}
+	if (authorName == "") != (authorEmail == "") {
+		return identity, fmt.Errorf("author_name and author_email must be provided together")
+	}
+	if authorName != "" {
+		identity.Author = &github.CommitAuthor{Name: github.Ptr(authorName), Email: github.Ptr(authorEmail)}
+	}
+
+	committer, ok := args["committer"]
+	if !ok || committer == nil {
+		return identity, nil
+	}
+	committerObj, ok := committer.(map[string]any)
+	if !ok {
+		return identity, fmt.Errorf("committer must be an object with name and email")
+	}
+	name, _ := committerObj["name"].(string)
+	email, _ := committerObj["email"].(string)
+	if name == "" || email == "" {
+		return identity, fmt.Errorf("committer must have a non-empty name and email")
+	}
+	if _, signed := signing.SignerFromContext(ctx); signed {
+		return identity, fmt.Errorf("committer cannot be set because this server signs commits; the signing identity is always the committer")
+	}
+	identity.Committer = &github.CommitAuthor{Name: github.Ptr(name), Email: github.Ptr(email)}
+
+	return identity, nil
+}
+
+// apply sets the identity on commit. Copies are used so that a signer filling in
+// dates does not modify the identity shared by later commits.
+func (c commitIdentity) apply(commit *github.Commit) {
+	if c.Author != nil {
+		author := *c.Author
+		commit.Author = &author
+	}
+	if c.Committer != nil {
+		committer := *c.Committer
+		commit.Committer = &committer
+	}
+}
+
+// Groupings of push_files_chunked for the files of a chunk
+const (
+	GroupByNone      = "none"
+	GroupByDirectory = "directory"
+	GroupByExtension = "extension"
+)
+
+// groupBySchema describes the group_by parameter of the chunked tools.
+func groupBySchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:        "string",
+		Description: "How files are grouped into chunks. 'none' fills chunks in the order of files, 'directory' keeps each chunk to files of one directory and 'extension' to files of one extension, so that each commit holds related files. Grouped chunks can be smaller than chunk_size (default: none)",
+		Enum:        []any{GroupByNone, GroupByDirectory, GroupByExtension},
+		Default:     json.RawMessage(`"` + GroupByNone + `"`),
+	}
+}
+
+// groupByFromArgs returns the group_by parameter, defaulting to none.
+func groupByFromArgs(args map[string]any) (string, error) {
+	groupBy, err := OptionalParam[string](args, "group_by")
+	if err != nil {
+		return "", err
+	}
+	switch groupBy {
+	case "":
+		return GroupByNone, nil
+	case GroupByNone, GroupByDirectory, GroupByExtension:
+		return groupBy, nil
+	}
+	return "", fmt.Errorf("group_by must be %s, %s or %s", GroupByNone, GroupByDirectory, GroupByExtension)
+}
+
+// planGroupedChunks splits files into chunks like planChunks, except that with a groupBy other
+// than none each chunk only holds files of one directory or extension. Groups are chunked in
+// the order their first file appears, so the split is as deterministic as planChunks.
+func planGroupedChunks(files []FileEntry, chunkSize int, groupBy string) [][]FileEntry {
+	var key func(file FileEntry) string
+	switch groupBy {
+	case GroupByDirectory:
+		key = func(file FileEntry) string { return path.Dir(file.Path) }
+	case GroupByExtension:
+		key = func(file FileEntry) string { return strings.ToLower(path.Ext(file.Path)) }
+	default:
+		return planChunks(files, chunkSize)
+	}
 
-			currentChunk = append(currentChunk, file)
-			currentChunkSize += fileSize
-			currentChunkFileCount++
+	var order []string
+	groups := make(map[string][]FileEntry)
+	for _, file := range files {
+		k := key(file)
+		if _, ok := groups[k]; !ok {
+			order = append(order, k)
 		}
+		groups[k] = append(groups[k], file)
+	}
+
+	var chunks [][]FileEntry
+	for _, k := range order {
+		chunks = append(chunks, planChunks(groups[k], chunkSize)...)
+	}
+	return chunks
+}
+
+// planChunks splits files into chunks bounded by both chunkSize files and the maximum chunk size in bytes.
+// The split is deterministic so that a resumed push reproduces the same chunks.
+func planChunks(files []FileEntry, chunkSize int) [][]FileEntry {
+	limits := CurrentPushLimits()
+	if chunkSize > limits.MaxChunkSize {
+		chunkSize = limits.MaxChunkSize
+	}
+	if chunkSize < 1 {
+		chunkSize = 1
+	}
 
-		// Add the last chunk if it has files
-		if len(currentChunk) > 0 {
+	// Create size-aware chunks using safety margin
+	maxChunkBytes := limits.maxChunkBytes()
+	var chunks [][]FileEntry
+
+	var currentChunk []fileEntry
+	var currentChunkSize int64
+
+	for _, file := range files {
+		fileSize := int64(len(file.Content))
+
+		// Check if adding this file would exceed limits
+		wouldExceedSize := currentChunkSize+fileSize > maxChunkBytes
+		wouldExceedCount := len(currentChunk) >= chunkSize
+
+		// Start a new chunk if we'd exceed either limit (and current chunk is not empty)
+		if len(currentChunk) > 0 && (wouldExceedSize || wouldExceedCount) {
 			chunks = append(chunks, currentChunk)
+			currentChunk = []fileEntry{}
+			currentChunkSize = 0
 		}
 
-		result := PushFilesChunkedResult{
-			TotalFiles:  len(files),
-			TotalChunks: len(chunks),
-			Chunks:      make([]ChunkResult, 0, len(chunks)),
-		}
+		currentChunk = append(currentChunk, file)
+		currentChunkSize += fileSize
+	}
 
-		// Process each chunk
-		for chunkIdx, chunkFiles := range chunks {
-			chunkResult := ChunkResult{
-				ChunkIndex:   chunkIdx + 1,
-				FilesInChunk: len(chunkFiles),
-				Files:        make([]string, 0, len(chunkFiles)),
-			}
+	// Add the last chunk if it has files
+	if len(currentChunk) > 0 {
+		chunks = append(chunks, currentChunk)
+	}
 
-			for _, f := range chunkFiles {
-				chunkResult.Files = append(chunkResult.Files, f.Path)
-			}
+	return chunks
+}
 
-			// Generate commit message for this chunk
-			chunkMessage := message
-			if result.TotalChunks > 1 {
-				chunkMessage = fmt.Sprintf("%s [chunk %d/%d]", message, chunkIdx+1, result.TotalChunks)
-			}
+// chunkRetryConfig is the retry behavior of the API calls that push a chunk. The
+// max_retries parameter of the chunked tools overrides MaxRetries.
+var chunkRetryConfig = ratelimit.DefaultRetryConfig()
 
-			// Push this chunk
-			commitSHA, pushErr := pushChunk(ctx, client, owner, repo, branch, chunkFiles, chunkMessage)
-			if pushErr != nil {
-				chunkResult.Success = false
-				chunkResult.Error = pushErr.Error()
-				result.FailedChunks++
+// newRetryBudget returns the budget that the retries of one tool call draw from. Tests replace it.
+var newRetryBudget = ratelimit.DefaultRetryBudget
 
-				if !continueOnError {
-					result.Chunks = append(result.Chunks, chunkResult)
-					result.FullySuccessful = false
+// withRetryBudget returns ctx carrying a new retry budget, so that all the API calls of one
+// tool call share it instead of each retrying up to its own limit. A budget already carried
+// by ctx is kept.
+func withRetryBudget(ctx context.Context) (context.Context, *ratelimit.RetryBudget) {
+	if budget, ok := ratelimit.RetryBudgetFromContext(ctx); ok {
+		return ctx, budget
+	}
+	budget := newRetryBudget()
+	return ratelimit.WithRetryBudget(ctx, budget), budget
+}
 
-					r, _ := json.Marshal(result)
-					return utils.NewToolResultText(string(r)), nil, nil
-				}
-			} else {
-				chunkResult.Success = true
-				chunkResult.CommitSHA = commitSHA
-				result.SuccessfulChunks++
-				result.FinalCommitSHA = commitSHA
-			}
+// maxRetriesSchema describes the max_retries parameter of the chunked tools.
+func maxRetriesSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:        "integer",
+		Description: fmt.Sprintf("Times each API call of a chunk is retried after a server error or secondary rate limit (default: %d, max: %d)", chunkRetryConfig.MaxRetries, MaxChunkRetries),
+		Minimum:     jsonschema.Ptr(0.0),
+		Maximum:     jsonschema.Ptr(float64(MaxChunkRetries)),
+		Default:     json.RawMessage(fmt.Sprintf("%d", chunkRetryConfig.MaxRetries)),
+	}
+}
 
-			result.Chunks = append(result.Chunks, chunkResult)
-		}
+// allowEmptySchema describes the allow_empty parameter of the bulk write tools.
+func allowEmptySchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:        "boolean",
+		Description: "Create a commit even if it changes no files. By default, nothing is committed when the files are already on the branch, and the result reports no_changes (default: false)",
+		Default:     json.RawMessage("false"),
+	}
+}
 
-		result.FullySuccessful = result.FailedChunks == 0
+// chunkRetryConfigFromArgs returns chunkRetryConfig with MaxRetries taken from max_retries, if set.
+func chunkRetryConfigFromArgs(args map[string]any) (ratelimit.RetryConfig, error) {
+	retry := chunkRetryConfig
+	maxRetries, err := OptionalIntParamWithDefault(args, "max_retries", retry.MaxRetries)
+	if err != nil {
+		return retry, err
+	}
+	if maxRetries < 0 || maxRetries > MaxChunkRetries {
+		return retry, fmt.Errorf("max_retries must be between 0 and %d", MaxChunkRetries)
+	}
+	retry.MaxRetries = maxRetries
+	return retry, nil
+}
 
-		r, err := json.Marshal(result)
-		if err != nil {
-			return nil, nil, fmt.Errorf("failed to marshal response: %w", err)
+// retryTransient calls call, retrying the errors cfg.Retryable accepts, and records in result the number of
+// retries made, the longest Retry-After asked for and the rate limit of the last response.
+// Every call made by pushChunk happens before or is the ref update, and repeating one leaves
+// at most an unreferenced tree or commit.
+func retryTransient(ctx context.Context, cfg ratelimit.RetryConfig, result *chunkCommit, call func() (*github.Response, error)) error {
+	attempts := 0
+	return ratelimit.RetryWithBackoff(ctx, cfg, func() error {
+		if attempts > 0 {
+			result.Retries++
 		}
-
-		return utils.NewToolResultText(string(r)), nil, nil
+		attempts++
+		resp, err := call()
+		if resp != nil {
+			if resp.Rate.Limit > 0 {
+				result.Rate = resp.Rate
+			}
+			_ = resp.Body.Close()
+		}
+		if delay := retryAfterDelay(resp, err); delay > 0 {
+			result.RetryAfter = max(result.RetryAfter, delay)
+			return ratelimit.RetryAfter(err, delay)
+		}
+		return err
 	})
+}
 
-	return tool, handler
+// retryAfterDelay returns the delay a failed call was asked to wait before retrying, from a
+// secondary rate limit error or the Retry-After header, or zero if none was given.
+func retryAfterDelay(resp *github.Response, err error) time.Duration {
+	if err == nil {
+		return 0
+	}
+	var abuseErr *github.AbuseRateLimitError
+	if errors.As(err, &abuseErr) && abuseErr.RetryAfter != nil {
+		return *abuseErr.RetryAfter
+	}
+	if resp == nil || resp.Response == nil {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	return 0
+}
+
+// refSchema describes the ref parameter of the push tools, an alternative to branch.
+func refSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:        "string",
+		Description: "Fully-qualified ref to push to instead of branch: refs/heads/<branch>, or refs/tags/<tag> to move a lightweight tag to the new commit",
+	}
+}
+
+// targetRefFromArgs returns the fully-qualified ref named by the branch or ref parameter, of
+// which exactly one must be set.
+func targetRefFromArgs(args map[string]any) (string, error) {
+	branch, err := OptionalParam[string](args, "branch")
+	if err != nil {
+		return "", err
+	}
+	ref, err := OptionalParam[string](args, "ref")
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case branch != "" && ref != "":
+		return "", fmt.Errorf("set either branch or ref, not both")
+	case branch != "":
+		ref = "refs/heads/" + branch
+	case ref == "":
+		return "", fmt.Errorf("missing required parameter: branch (or ref)")
+	}
+	if err := validateRefName(ref); err != nil {
+		return "", err
+	}
+	return ref, nil
+}
+
+// validateRefName checks that ref is a branch or tag ref that git accepts, following the rules
+// of git check-ref-format.
+func validateRefName(ref string) error {
+	name, ok := strings.CutPrefix(ref, "refs/heads/")
+	if !ok {
+		name, ok = strings.CutPrefix(ref, "refs/tags/")
+	}
+	if !ok || name == "" {
+		return fmt.Errorf("invalid ref %q: expected refs/heads/<branch> or refs/tags/<tag>", ref)
+	}
+	invalid := strings.ContainsAny(name, " ~^:?*[\\") || strings.Contains(name, "..") || strings.Contains(name, "@{") ||
+		strings.HasSuffix(name, "/") || strings.HasSuffix(name, ".")
+	for _, component := range strings.Split(name, "/") {
+		if component == "" || strings.HasPrefix(component, ".") || strings.HasSuffix(component, ".lock") {
+			invalid = true
+		}
+	}
+	for _, r := range name {
+		if r < 0x20 || r == 0x7f {
+			invalid = true
+		}
+	}
+	if invalid {
+		return fmt.Errorf("invalid ref %q: %q is not a valid git ref name", ref, name)
+	}
+	return nil
+}
+
+// chunkCommit is the outcome of pushing one chunk.
+type chunkCommit struct {
+	// SHA is the new commit, or the branch head if NoChanges is set
+	SHA string
+	// NoChanges is set when the chunk left the tree unchanged and no commit was made
+	NoChanges bool
+	// Retries is the number of API calls repeated after transient failures
+	Retries int
+	// RetryAfter is the longest delay a failed API call was asked to wait before retrying
+	RetryAfter time.Duration
+	// Rate is the rate limit reported by the last API response
+	Rate github.Rate
 }
 
-// pushChunk pushes a single chunk of files to the repository
-func pushChunk(ctx context.Context, client *github.Client, owner, repo, branch string, files []FileEntry, message string) (string, error) {
+// pushChunk pushes a single chunk of files to refName, a branch or lightweight tag given as a
+// fully-qualified ref. Transient failures of each API call are retried according to retry.
+// Unless allowEmpty is set, no commit is made when the files are already on the ref.
+func pushChunk(ctx context.Context, client *github.Client, owner, repo, refName string, files []FileEntry, message string, identity commitIdentity, retry ratelimit.RetryConfig, allowEmpty bool) (chunkCommit, error) {
 	// Validate chunk size before attempting to push
 	if err := ValidateChunkSize(files); err != nil {
-		return "", err
+		return chunkCommit{}, err
 	}
 
+	var result chunkCommit
+	var resp *github.Response
+
 	// Get the reference for the branch
-	ref, resp, err := client.Git.GetRef(ctx, owner, repo, "refs/heads/"+branch)
+	var ref *github.Reference
+	err := retryTransient(ctx, retry, &result, func() (*github.Response, error) {
+		var err error
+		ref, resp, err = client.Git.GetRef(ctx, owner, repo, refName)
+		return resp, err
+	})
 	if err != nil {
-		_, apiErr := ghErrors.NewGitHubAPIErrorToCtx(ctx, "failed to get branch reference", resp, err)
-		return "", apiErr
+		_, _ = ghErrors.NewGitHubAPIErrorToCtx(ctx, "failed to get branch reference", resp, err)
+		if isEmptyRepository(resp, err) {
+			return result, emptyRepositoryError(owner, repo, err)
+		}
+		return result, fmt.Errorf("failed to get branch reference: %w", err)
+	}
+	// An annotated tag points to a tag object, which a commit cannot be added to
+	if objectType := ref.GetObject().GetType(); objectType != "" && objectType != "commit" {
+		return result, fmt.Errorf("%s points to a %s, not a commit; only branches and lightweight tags can be pushed to", refName, objectType)
 	}
-	defer func() { _ = resp.Body.Close() }()
 
 	// Get the commit object that the branch points to
-	baseCommit, resp, err := client.Git.GetCommit(ctx, owner, repo, *ref.Object.SHA)
+	var baseCommit *github.Commit
+	err = retryTransient(ctx, retry, &result, func() (*github.Response, error) {
+		var err error
+		baseCommit, resp, err = client.Git.GetCommit(ctx, owner, repo, *ref.Object.SHA)
+		return resp, err
+	})
 	if err != nil {
-		_, apiErr := ghErrors.NewGitHubAPIErrorToCtx(ctx, "failed to get base commit", resp, err)
-		return "", apiErr
+		_, _ = ghErrors.NewGitHubAPIErrorToCtx(ctx, "failed to get base commit", resp, err)
+		return result, fmt.Errorf("failed to get base commit: %w", err)
 	}
-	defer func() { _ = resp.Body.Close() }()
 
-	// Create tree entries for all files in this chunk
+	// Create tree entries for all files in this chunk. Tree content must be text, so binary
+	// files are uploaded as base64-encoded blobs first.
 	var entries []*github.TreeEntry
 	for _, file := range files {
-		entries = append(entries, &github.TreeEntry{
-			Path:    github.Ptr(file.Path),
-			Mode:    github.Ptr("100644"),
-			Type:    github.Ptr("blob"),
-			Content: github.Ptr(file.Content),
-		})
+		entry := &github.TreeEntry{
+			Path: github.Ptr(file.Path),
+			Mode: github.Ptr("100644"),
+			Type: github.Ptr("blob"),
+		}
+		if file.Binary {
+			var blob *github.Blob
+			err = retryTransient(ctx, retry, &result, func() (*github.Response, error) {
+				var err error
+				blob, resp, err = client.Git.CreateBlob(ctx, owner, repo, github.Blob{
+					Content:  github.Ptr(encodeBlobContent(file.Content)),
+					Encoding: github.Ptr("base64"),
+				})
+				return resp, err
+			})
+			if err != nil {
+				_, _ = ghErrors.NewGitHubAPIErrorToCtx(ctx, "failed to create blob", resp, err)
+				return result, fmt.Errorf("failed to create blob for %s: %w", file.Path, err)
+			}
+			entry.SHA = blob.SHA
+		} else {
+			entry.Content = github.Ptr(file.Content)
+		}
+		entries = append(entries, entry)
 	}
 
 	// Create a new tree
-	newTree, resp, err := client.Git.CreateTree(ctx, owner, repo, *baseCommit.Tree.SHA, entries)
+	var newTree *github.Tree
+	err = retryTransient(ctx, retry, &result, func() (*github.Response, error) {
+		var err error
+		newTree, resp, err = client.Git.CreateTree(ctx, owner, repo, *baseCommit.Tree.SHA, entries)
+		return resp, err
+	})
 	if err != nil {
-		_, apiErr := ghErrors.NewGitHubAPIErrorToCtx(ctx, "failed to create tree", resp, err)
-		return "", apiErr
+		_, _ = ghErrors.NewGitHubAPIErrorToCtx(ctx, "failed to create tree", resp, err)
+		return result, fmt.Errorf("failed to create tree: %w", err)
+	}
+	if !allowEmpty && newTree.GetSHA() == baseCommit.GetTree().GetSHA() {
+		result.SHA = baseCommit.GetSHA()
+		result.NoChanges = true
+		return result, nil
 	}
-	defer func() { _ = resp.Body.Close() }()
 
 	// Create a new commit
 	commit := github.Commit{
@@ -303,32 +871,43 @@ func pushChunk(ctx context.Context, client *github.Client, owner, repo, branch s
 		Tree:    newTree,
 		Parents: []*github.Commit{{SHA: baseCommit.SHA}},
 	}
-	newCommit, resp, err := client.Git.CreateCommit(ctx, owner, repo, commit, nil)
+	identity.apply(&commit)
+	opts := signedCommitOptions(ctx, &commit)
+	var newCommit *github.Commit
+	err = retryTransient(ctx, retry, &result, func() (*github.Response, error) {
+		var err error
+		newCommit, resp, err = client.Git.CreateCommit(ctx, owner, repo, commit, opts)
+		return resp, err
+	})
 	if err != nil {
-		_, apiErr := ghErrors.NewGitHubAPIErrorToCtx(ctx, "failed to create commit", resp, err)
-		return "", apiErr
+		_, _ = ghErrors.NewGitHubAPIErrorToCtx(ctx, "failed to create commit", resp, err)
+		return result, fmt.Errorf("failed to create commit: %w", err)
 	}
-	defer func() { _ = resp.Body.Close() }()
 
-	// Update the reference to point to the new commit
-	_, resp, err = client.Git.UpdateRef(ctx, owner, repo, *ref.Ref, github.UpdateRef{
-		SHA:   *newCommit.SHA,
-		Force: github.Ptr(false),
+	// Update the reference to point to the new commit. Repeating the update after a lost
+	// response is harmless, since the ref then already points to the commit.
+	err = retryTransient(ctx, retry, &result, func() (*github.Response, error) {
+		var err error
+		_, resp, err = client.Git.UpdateRef(ctx, owner, repo, *ref.Ref, github.UpdateRef{
+			SHA:   *newCommit.SHA,
+			Force: github.Ptr(false),
+		})
+		return resp, err
 	})
 	if err != nil {
-		_, apiErr := ghErrors.NewGitHubAPIErrorToCtx(ctx, "failed to update reference", resp, err)
-		return "", apiErr
+		_, _ = ghErrors.NewGitHubAPIErrorToCtx(ctx, "failed to update reference", resp, err)
+		return result, fmt.Errorf("failed to update reference: %w", err)
 	}
-	defer func() { _ = resp.Body.Close() }()
 
-	return *newCommit.SHA, nil
+	result.SHA = newCommit.GetSHA()
+	return result, nil
 }
 
 // GetPushLimits creates a tool to get the current push operation limits
-func GetPushLimits(t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+func GetPushLimits(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
 	tool := mcp.Tool{
 		Name:        "get_push_limits",
-		Description: t("TOOL_GET_PUSH_LIMITS_DESCRIPTION", "Get the current limits for file push operations"),
+		Description: t("TOOL_GET_PUSH_LIMITS_DESCRIPTION", "Get the current limits for file push operations on the GitHub host the server targets"),
 		Annotations: &mcp.ToolAnnotations{
 			Title:        t("TOOL_GET_PUSH_LIMITS_USER_TITLE", "Get push limits"),
 			ReadOnlyHint: true,
@@ -340,22 +919,45 @@ func GetPushLimits(t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHand
 	}
 
 	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
-		limits := map[string]interface{}{
-			"max_files_per_push":        MaxFilesPerPush,
-			"max_file_size_bytes":       MaxFileSizeBytes,
-			"max_file_size_mb":          MaxFileSizeBytes / (1024 * 1024),
-			"max_total_push_size_bytes": MaxTotalPushSizeBytes,
-			"max_total_push_size_mb":    MaxTotalPushSizeBytes / (1024 * 1024),
-			"default_chunk_size":        DefaultChunkSize,
-			"max_chunk_size":            MaxChunkSize,
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		limits := pushLimitsFor(ctx, client)
+		result := map[string]interface{}{
+			"max_files_per_push":        limits.MaxFilesPerPush,
+			"max_file_size_bytes":       limits.MaxFileSizeBytes,
+			"max_file_size_mb":          limits.MaxFileSizeBytes / (1024 * 1024),
+			"max_total_push_size_bytes": limits.MaxTotalPushSizeBytes,
+			"max_total_push_size_mb":    limits.MaxTotalPushSizeBytes / (1024 * 1024),
+			"default_chunk_size":        limits.DefaultChunkSize,
+			"max_chunk_size":            limits.MaxChunkSize,
+			"sources": map[string]string{
+				PushLimitMaxFilesPerPush:       limits.Source(PushLimitMaxFilesPerPush),
+				PushLimitMaxFileSizeBytes:      limits.Source(PushLimitMaxFileSizeBytes),
+				PushLimitMaxTotalPushSizeBytes: limits.Source(PushLimitMaxTotalPushSizeBytes),
+				PushLimitDefaultChunkSize:      limits.Source(PushLimitDefaultChunkSize),
+				PushLimitMaxChunkSize:          limits.Source(PushLimitMaxChunkSize),
+			},
 			"recommendations": map[string]string{
-				"small_batch":  "Use push_files for <= 100 files",
-				"large_batch":  "Use push_files_chunked for > 100 files",
-				"single_file":  "Use create_or_update_file for single files",
+				"small_batch": fmt.Sprintf("Use push_files for <= %d files", limits.MaxFilesPerPush),
+				"large_batch": fmt.Sprintf("Use push_files_chunked for > %d files", limits.MaxFilesPerPush),
+				"single_file": "Use create_or_update_file for single files",
 			},
 		}
 
-		r, err := json.Marshal(limits)
+		if limits.Host != "" {
+			result["host"] = limits.Host
+		}
+		if limits.HostType != "" {
+			result["host_type"] = limits.HostType
+		}
+		if limits.EnterpriseVersion != "" {
+			result["enterprise_version"] = limits.EnterpriseVersion
+		}
+
+		r, err := json.Marshal(result)
 		if err != nil {
 			return nil, nil, fmt.Errorf("failed to marshal response: %w", err)
 		}
@@ -366,6 +968,13 @@ func GetPushLimits(t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHand
 	return tool, handler
 }
 
+// Policies of bulk_delete_files for paths that are not files on the branch
+const (
+	MissingPathPolicyError  = "error"
+	MissingPathPolicySkip   = "skip"
+	MissingPathPolicyIgnore = "ignore"
+)
+
 // BulkDeleteFiles creates a tool to delete multiple files in a single commit
 func BulkDeleteFiles(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
 	tool := mcp.Tool{
@@ -375,7 +984,7 @@ func BulkDeleteFiles(getClient GetClientFn, t translations.TranslationHelperFunc
 			Title:        t("TOOL_BULK_DELETE_FILES_USER_TITLE", "Bulk delete files"),
 			ReadOnlyHint: false,
 		},
-		InputSchema: &jsonschema.Schema{
+		InputSchema: withCommitIdentity(&jsonschema.Schema{
 			Type: "object",
 			Properties: map[string]*jsonschema.Schema{
 				"owner": {
@@ -401,9 +1010,16 @@ func BulkDeleteFiles(getClient GetClientFn, t translations.TranslationHelperFunc
 					Type:        "string",
 					Description: "Commit message",
 				},
+				"missing_path_policy": {
+					Type:        "string",
+					Description: "What to do with paths that are not files on the branch: 'error' fails without committing, 'skip' deletes the other paths and reports the missing ones, 'ignore' does not check the paths",
+					Enum:        []any{MissingPathPolicyError, MissingPathPolicySkip, MissingPathPolicyIgnore},
+					Default:     json.RawMessage(`"` + MissingPathPolicyError + `"`),
+				},
+				"allow_empty": allowEmptySchema(),
 			},
 			Required: []string{"owner", "repo", "branch", "paths", "message"},
-		},
+		}),
 	}
 
 	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
@@ -433,10 +1049,10 @@ func BulkDeleteFiles(getClient GetClientFn, t translations.TranslationHelperFunc
 			return utils.NewToolResultError("paths array cannot be empty"), nil, nil
 		}
 
-		if len(pathsObj) > MaxFilesPerPush {
+		if maxFiles := CurrentPushLimits().MaxFilesPerPush; len(pathsObj) > maxFiles {
 			return utils.NewToolResultError(fmt.Sprintf(
 				"too many files to delete: %d exceeds maximum of %d per operation",
-				len(pathsObj), MaxFilesPerPush,
+				len(pathsObj), maxFiles,
 			)), nil, nil
 		}
 
@@ -449,6 +1065,26 @@ func BulkDeleteFiles(getClient GetClientFn, t translations.TranslationHelperFunc
 			paths = append(paths, path)
 		}
 
+		identity, err := commitIdentityFromArgs(ctx, args)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		allowEmpty, err := OptionalParam[bool](args, "allow_empty")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		missingPathPolicy, err := OptionalParam[string](args, "missing_path_policy")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		switch missingPathPolicy {
+		case "":
+			missingPathPolicy = MissingPathPolicyError
+		case MissingPathPolicyError, MissingPathPolicySkip, MissingPathPolicyIgnore:
+		default:
+			return utils.NewToolResultError(fmt.Sprintf("missing_path_policy must be %s, %s or %s", MissingPathPolicyError, MissingPathPolicySkip, MissingPathPolicyIgnore)), nil, nil
+		}
+
 		client, err := getClient(ctx)
 		if err != nil {
 			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
@@ -456,6 +1092,9 @@ func BulkDeleteFiles(getClient GetClientFn, t translations.TranslationHelperFunc
 
 		// Get the reference for the branch
 		ref, resp, err := client.Git.GetRef(ctx, owner, repo, "refs/heads/"+branch)
+		if isEmptyRepository(resp, err) {
+			return validationErrorResult(emptyRepositoryError(owner, repo, err)), nil, nil
+		}
 		if err != nil {
 			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to get branch reference", resp, err), nil, nil
 		}
@@ -468,6 +1107,50 @@ func BulkDeleteFiles(getClient GetClientFn, t translations.TranslationHelperFunc
 		}
 		defer func() { _ = resp.Body.Close() }()
 
+		// Check the paths against the files of the branch, since deleting a path that does not
+		// exist is either silently ignored or rejected with an unclear error by the trees API
+		var skipped []string
+		if missingPathPolicy != MissingPathPolicyIgnore {
+			baseTree, resp, err := client.Git.GetTree(ctx, owner, repo, baseCommit.GetTree().GetSHA(), true)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to get tree", resp, err), nil, nil
+			}
+			_ = resp.Body.Close()
+			if baseTree.GetTruncated() {
+				return utils.NewToolResultError(fmt.Sprintf(
+					"tree of %s/%s@%s is too large to check the paths; set missing_path_policy to ignore to delete without checking",
+					owner, repo, branch,
+				)), nil, nil
+			}
+
+			var missing []string
+			paths, missing = splitMissingFiles(baseTree.Entries, paths)
+			if len(missing) > 0 && missingPathPolicy == MissingPathPolicyError {
+				return validationErrorResult(&ValidationError{
+					Code:       "MISSING_PATHS",
+					Message:    fmt.Sprintf("%d of the paths are not files on branch %s: %s", len(missing), branch, strings.Join(missing, ", ")),
+					Suggestion: "Check the paths, or set missing_path_policy to skip to delete only the files that exist",
+					Details: map[string]interface{}{
+						"missing_paths": missing,
+					},
+				}), nil, nil
+			}
+			skipped = missing
+		}
+		if len(paths) == 0 {
+			r, err := json.Marshal(map[string]interface{}{
+				"no_changes":    true,
+				"deleted_files": []string{},
+				"files_deleted": 0,
+				"skipped_paths": skipped,
+				"ref":           ref.GetRef(),
+			})
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to marshal response: %w", err)
+			}
+			return utils.NewToolResultText(string(r)), nil, nil
+		}
+
 		// Create tree entries for deletion (SHA nil = delete)
 		var entries []*github.TreeEntry
 		for _, path := range paths {
@@ -486,13 +1169,32 @@ func BulkDeleteFiles(getClient GetClientFn, t translations.TranslationHelperFunc
 		}
 		defer func() { _ = resp.Body.Close() }()
 
+		// None of the paths existed, so deleting them changed nothing
+		if !allowEmpty && newTree.GetSHA() == baseCommit.GetTree().GetSHA() {
+			result := map[string]interface{}{
+				"no_changes":    true,
+				"deleted_files": []string{},
+				"files_deleted": 0,
+				"ref":           ref.GetRef(),
+			}
+			if len(skipped) > 0 {
+				result["skipped_paths"] = skipped
+			}
+			r, err := json.Marshal(result)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to marshal response: %w", err)
+			}
+			return utils.NewToolResultText(string(r)), nil, nil
+		}
+
 		// Create commit
 		commit := github.Commit{
 			Message: github.Ptr(message),
 			Tree:    newTree,
 			Parents: []*github.Commit{{SHA: baseCommit.SHA}},
 		}
-		newCommit, resp, err := client.Git.CreateCommit(ctx, owner, repo, commit, nil)
+		identity.apply(&commit)
+		newCommit, resp, err := client.Git.CreateCommit(ctx, owner, repo, commit, signedCommitOptions(ctx, &commit))
 		if err != nil {
 			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to create commit", resp, err), nil, nil
 		}
@@ -514,6 +1216,317 @@ func BulkDeleteFiles(getClient GetClientFn, t translations.TranslationHelperFunc
 			"files_deleted": len(paths),
 			"ref":           *updatedRef.Ref,
 		}
+		if len(skipped) > 0 {
+			result["skipped_paths"] = skipped
+		}
+
+		r, err := json.Marshal(result)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to marshal response: %w", err)
+		}
+
+		return utils.NewToolResultText(string(r)), nil, nil
+	})
+
+	return withIdempotencyKey(tool, handler)
+}
+
+// CopiedFile describes a single file copied by bulk_copy_files
+type CopiedFile struct {
+	SourcePath string `json:"source_path"`
+	TargetPath string `json:"target_path"`
+	SHA        string `json:"sha"`
+}
+
+// BulkCopyFilesResult represents the result of a bulk copy operation
+type BulkCopyFilesResult struct {
+	CommitSHA   string       `json:"commit_sha"`
+	Ref         string       `json:"ref"`
+	SourceRef   string       `json:"source_ref"`
+	FilesCopied int          `json:"files_copied"`
+	ReusedBlobs bool         `json:"reused_blobs"`
+	Files       []CopiedFile `json:"files"`
+	// NoChanges is set when the files were already on the target branch, so no commit was made
+	NoChanges bool `json:"no_changes,omitempty"`
+}
+
+// BulkCopyFiles creates a tool to copy files and directories from one repository ref to a branch,
+// possibly in another repository, in a single commit.
+func BulkCopyFiles(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "bulk_copy_files",
+		Description: t("TOOL_BULK_COPY_FILES_DESCRIPTION", "Copy files and directories from a source repository ref to a target branch in a single commit. The target can be a branch of the same repository or of another repository, which makes this useful for propagating template files across repositories. Files copied from another repository are uploaded again, so they are held to the push size limits."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_BULK_COPY_FILES_USER_TITLE", "Bulk copy files"),
+			ReadOnlyHint: false,
+		},
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"source_owner": {
+					Type:        "string",
+					Description: "Source repository owner",
+				},
+				"source_repo": {
+					Type:        "string",
+					Description: "Source repository name",
+				},
+				"source_ref": {
+					Type:        "string",
+					Description: "Source branch, tag or commit SHA. Defaults to the source repository's default branch",
+				},
+				"paths": {
+					Type:        "array",
+					Description: "Files or directories to copy from the source. Directories are copied recursively",
+					Items: &jsonschema.Schema{
+						Type: "string",
+					},
+				},
+				"owner": {
+					Type:        "string",
+					Description: "Target repository owner",
+				},
+				"repo": {
+					Type:        "string",
+					Description: "Target repository name",
+				},
+				"branch": {
+					Type:        "string",
+					Description: "Target branch to commit the copied files to",
+				},
+				"target_prefix": {
+					Type:        "string",
+					Description: "Directory in the target repository to copy the paths into. Defaults to the same paths as in the source",
+				},
+				"message": {
+					Type:        "string",
+					Description: "Commit message",
+				},
+				"allow_empty": allowEmptySchema(),
+			},
+			Required: []string{"source_owner", "source_repo", "paths", "owner", "repo", "branch", "message"},
+		},
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		sourceOwner, err := RequiredParam[string](args, "source_owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		sourceRepo, err := RequiredParam[string](args, "source_repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		sourceRef, err := OptionalParam[string](args, "source_ref")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		repo, err := RequiredParam[string](args, "repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		branch, err := RequiredParam[string](args, "branch")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		targetPrefix, err := OptionalParam[string](args, "target_prefix")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		message, err := RequiredParam[string](args, "message")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		allowEmpty, err := OptionalParam[bool](args, "allow_empty")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		pathsObj, ok := args["paths"].([]interface{})
+		if !ok {
+			return utils.NewToolResultError("paths parameter must be an array of strings"), nil, nil
+		}
+		if len(pathsObj) == 0 {
+			return utils.NewToolResultError("paths array cannot be empty"), nil, nil
+		}
+
+		var paths []string
+		for i, p := range pathsObj {
+			path, ok := p.(string)
+			path = strings.Trim(path, "/")
+			if !ok || path == "" {
+				return utils.NewToolResultError(fmt.Sprintf("path at index %d must be a non-empty string", i)), nil, nil
+			}
+			paths = append(paths, path)
+		}
+		targetPrefix = strings.Trim(targetPrefix, "/")
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		if sourceRef == "" {
+			sourceRepository, resp, err := client.Repositories.Get(ctx, sourceOwner, sourceRepo)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to get source repository", resp, err), nil, nil
+			}
+			_ = resp.Body.Close()
+			sourceRef = sourceRepository.GetDefaultBranch()
+		}
+
+		// The trees API accepts branch and tag names as well as SHAs
+		sourceTree, resp, err := client.Git.GetTree(ctx, sourceOwner, sourceRepo, sourceRef, true)
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to get source tree", resp, err), nil, nil
+		}
+		_ = resp.Body.Close()
+
+		if sourceTree.GetTruncated() {
+			return utils.NewToolResultError(fmt.Sprintf(
+				"source tree of %s/%s@%s is too large to list recursively; copy smaller directories or use push_files_chunked",
+				sourceOwner, sourceRepo, sourceRef,
+			)), nil, nil
+		}
+
+		sources, missing := selectTreeEntries(sourceTree.Entries, paths)
+		if len(missing) > 0 {
+			return utils.NewToolResultError(fmt.Sprintf(
+				"paths not found in %s/%s@%s: %s",
+				sourceOwner, sourceRepo, sourceRef, strings.Join(missing, ", "),
+			)), nil, nil
+		}
+		if maxFiles := CurrentPushLimits().MaxFilesPerPush; len(sources) > maxFiles {
+			return utils.NewToolResultError(fmt.Sprintf(
+				"too many files to copy: %d exceeds maximum of %d per operation",
+				len(sources), maxFiles,
+			)), nil, nil
+		}
+
+		// Blobs can only be referenced by SHA within the repository that holds them
+		sameRepo := strings.EqualFold(sourceOwner, owner) && strings.EqualFold(sourceRepo, repo)
+
+		// Content copied from another repository is uploaded again, so it is held to the limits
+		// of inline content before any of it is downloaded
+		if !sameRepo {
+			limits := pushLimitsFor(ctx, client)
+			var totalSize int64
+			for _, source := range sources {
+				if result, err := ValidateFileSize(limits, source.GetPath(), int64(source.GetSize())); result != nil || err != nil {
+					return result, nil, nil
+				}
+				totalSize += int64(source.GetSize())
+			}
+			if result, err := ValidateTotalSize(limits, totalSize); result != nil || err != nil {
+				return result, nil, nil
+			}
+		}
+
+		result := BulkCopyFilesResult{
+			SourceRef:   sourceRef,
+			ReusedBlobs: sameRepo,
+			Files:       make([]CopiedFile, 0, len(sources)),
+		}
+
+		var entries []*github.TreeEntry
+		for _, source := range sources {
+			targetPath := source.GetPath()
+			if targetPrefix != "" {
+				targetPath = targetPrefix + "/" + targetPath
+			}
+
+			blobSHA := source.GetSHA()
+			if !sameRepo {
+				content, resp, err := client.Git.GetBlobRaw(ctx, sourceOwner, sourceRepo, blobSHA)
+				if err != nil {
+					return ghErrors.NewGitHubAPIErrorResponse(ctx, fmt.Sprintf("failed to read source file %s", source.GetPath()), resp, err), nil, nil
+				}
+				_ = resp.Body.Close()
+
+				blob, resp, err := client.Git.CreateBlob(ctx, owner, repo, github.Blob{
+					Content:  github.Ptr(base64.StdEncoding.EncodeToString(content)),
+					Encoding: github.Ptr("base64"),
+				})
+				if err != nil {
+					return ghErrors.NewGitHubAPIErrorResponse(ctx, fmt.Sprintf("failed to create blob for %s", targetPath), resp, err), nil, nil
+				}
+				_ = resp.Body.Close()
+				blobSHA = blob.GetSHA()
+			}
+
+			entries = append(entries, &github.TreeEntry{
+				Path: github.Ptr(targetPath),
+				Mode: github.Ptr(source.GetMode()),
+				Type: github.Ptr("blob"),
+				SHA:  github.Ptr(blobSHA),
+			})
+			result.Files = append(result.Files, CopiedFile{
+				SourcePath: source.GetPath(),
+				TargetPath: targetPath,
+				SHA:        blobSHA,
+			})
+		}
+
+		// Get the reference for the target branch
+		ref, resp, err := client.Git.GetRef(ctx, owner, repo, "refs/heads/"+branch)
+		if isEmptyRepository(resp, err) {
+			return validationErrorResult(emptyRepositoryError(owner, repo, err)), nil, nil
+		}
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to get branch reference", resp, err), nil, nil
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		// Get the commit object
+		baseCommit, resp, err := client.Git.GetCommit(ctx, owner, repo, *ref.Object.SHA)
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to get base commit", resp, err), nil, nil
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		// Create new tree
+		newTree, resp, err := client.Git.CreateTree(ctx, owner, repo, *baseCommit.Tree.SHA, entries)
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to create tree", resp, err), nil, nil
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		if !allowEmpty && newTree.GetSHA() == baseCommit.GetTree().GetSHA() {
+			result.NoChanges = true
+			result.Ref = ref.GetRef()
+			result.FilesCopied = len(result.Files)
+			return MarshalledTextResult(result), nil, nil
+		}
+
+		// Create commit
+		commit := github.Commit{
+			Message: github.Ptr(message),
+			Tree:    newTree,
+			Parents: []*github.Commit{{SHA: baseCommit.SHA}},
+		}
+		newCommit, resp, err := client.Git.CreateCommit(ctx, owner, repo, commit, signedCommitOptions(ctx, &commit))
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to create commit", resp, err), nil, nil
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		// Update reference
+		updatedRef, resp, err := client.Git.UpdateRef(ctx, owner, repo, *ref.Ref, github.UpdateRef{
+			SHA:   *newCommit.SHA,
+			Force: github.Ptr(false),
+		})
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to update reference", resp, err), nil, nil
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		result.CommitSHA = newCommit.GetSHA()
+		result.Ref = updatedRef.GetRef()
+		result.FilesCopied = len(result.Files)
 
 		r, err := json.Marshal(result)
 		if err != nil {
@@ -523,5 +1536,300 @@ func BulkDeleteFiles(getClient GetClientFn, t translations.TranslationHelperFunc
 		return utils.NewToolResultText(string(r)), nil, nil
 	})
 
+	return withIdempotencyKey(tool, handler)
+}
+
+// splitMissingFiles splits paths into those that are files of a recursive tree and those that
+// are not, such as missing paths and directories.
+func splitMissingFiles(tree []*github.TreeEntry, paths []string) (existing, missing []string) {
+	files := make(map[string]bool, len(tree))
+	for _, entry := range tree {
+		if entry.GetType() == "blob" {
+			files[entry.GetPath()] = true
+		}
+	}
+	for _, path := range paths {
+		if files[path] {
+			existing = append(existing, path)
+		} else {
+			missing = append(missing, path)
+		}
+	}
+	return existing, missing
+}
+
+// selectTreeEntries returns the blob entries of a recursive tree that match paths, either exactly
+// or as descendants of a directory path, along with any paths that matched nothing. Submodules
+// are skipped since they cannot be copied as content.
+func selectTreeEntries(tree []*github.TreeEntry, paths []string) ([]*github.TreeEntry, []string) {
+	var selected []*github.TreeEntry
+	var missing []string
+	seen := make(map[string]bool)
+
+	for _, path := range paths {
+		matched := false
+		for _, entry := range tree {
+			entryPath := entry.GetPath()
+			if entryPath != path && !strings.HasPrefix(entryPath, path+"/") {
+				continue
+			}
+			matched = true
+			if entry.GetType() != "blob" || seen[entryPath] {
+				continue
+			}
+			seen[entryPath] = true
+			selected = append(selected, entry)
+		}
+		if !matched {
+			missing = append(missing, path)
+		}
+	}
+
+	return selected, missing
+}
+
+// BulkFile is a single file returned by get_files_bulk
+type BulkFile struct {
+	Path string `json:"path"`
+	// SHA is the blob SHA of the file. get_repository_archive leaves it out for truncated files,
+	// which it does not read whole
+	SHA string `json:"sha,omitempty"`
+	// Size is the full size of the file in bytes, even when the content is truncated
+	Size int `json:"size"`
+	// Encoding is utf-8 for text content, or base64 for binary content
+	Encoding  string `json:"encoding"`
+	Content   string `json:"content"`
+	Truncated bool   `json:"truncated,omitempty"`
+}
+
+// GetFilesBulkResult represents the result of a bulk read
+type GetFilesBulkResult struct {
+	Ref           string     `json:"ref"`
+	TotalFiles    int        `json:"total_files"`
+	FilesReturned int        `json:"files_returned"`
+	BytesReturned int        `json:"bytes_returned"`
+	Files         []BulkFile `json:"files"`
+	// RemainingPaths lists the files left out once the total size budget was spent.
+	// Pass them as paths in another call to continue.
+	RemainingPaths []string `json:"remaining_paths,omitempty"`
+}
+
+// GetFilesBulk creates a tool to read many files, or whole directories, at a ref in one call.
+func GetFilesBulk(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "get_files_bulk",
+		Description: t("TOOL_GET_FILES_BULK_DESCRIPTION", "Get the contents of many files from a GitHub repository in one call. Paths can be files or directories, whose files are all included; omit paths to read the whole repository. Files larger than max_file_size are truncated, and once max_total_size is reached the remaining files are listed in remaining_paths so they can be fetched with another call."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_GET_FILES_BULK_USER_TITLE", "Get files in bulk"),
+			ReadOnlyHint: true,
+		},
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"owner": {
+					Type:        "string",
+					Description: "Repository owner",
+				},
+				"repo": {
+					Type:        "string",
+					Description: "Repository name",
+				},
+				"ref": {
+					Type:        "string",
+					Description: "Branch, tag or commit SHA to read from. Defaults to the default branch",
+				},
+				"paths": {
+					Type:        "array",
+					Description: "File or directory paths to read. Omit to read every file in the repository",
+					Items: &jsonschema.Schema{
+						Type: "string",
+					},
+				},
+				"max_file_size": {
+					Type:        "integer",
+					Description: fmt.Sprintf("Maximum bytes of content returned per file; larger files are truncated (default: %d)", DefaultBulkReadFileSizeBytes),
+					Default:     json.RawMessage(fmt.Sprintf("%d", DefaultBulkReadFileSizeBytes)),
+					Minimum:     jsonschema.Ptr(1.0),
+				},
+				"max_total_size": {
+					Type:        "integer",
+					Description: fmt.Sprintf("Maximum bytes of content returned in total (default: %d, max: %d)", DefaultBulkReadTotalSizeBytes, MaxBulkReadTotalSizeBytes),
+					Default:     json.RawMessage(fmt.Sprintf("%d", DefaultBulkReadTotalSizeBytes)),
+					Minimum:     jsonschema.Ptr(1.0),
+					Maximum:     jsonschema.Ptr(float64(MaxBulkReadTotalSizeBytes)),
+				},
+			},
+			Required: []string{"owner", "repo"},
+		},
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		repo, err := RequiredParam[string](args, "repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		ref, err := OptionalParam[string](args, "ref")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		paths, err := OptionalStringArrayParam(args, "paths")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		maxFileSize, err := OptionalIntParamWithDefault(args, "max_file_size", DefaultBulkReadFileSizeBytes)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		maxTotalSize, err := OptionalIntParamWithDefault(args, "max_total_size", DefaultBulkReadTotalSizeBytes)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		if maxFileSize < 1 || maxTotalSize < 1 {
+			return utils.NewToolResultError("max_file_size and max_total_size must be positive"), nil, nil
+		}
+		maxTotalSize = min(maxTotalSize, MaxBulkReadTotalSizeBytes)
+		// A single file never needs more than the whole budget
+		maxFileSize = min(maxFileSize, maxTotalSize)
+
+		for i, path := range paths {
+			paths[i] = strings.Trim(path, "/")
+			if paths[i] == "" {
+				return utils.NewToolResultError(fmt.Sprintf("path at index %d must be a non-empty string", i)), nil, nil
+			}
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		if ref == "" {
+			repository, resp, err := client.Repositories.Get(ctx, owner, repo)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to get repository", resp, err), nil, nil
+			}
+			_ = resp.Body.Close()
+			ref = repository.GetDefaultBranch()
+		}
+
+		tree, resp, err := client.Git.GetTree(ctx, owner, repo, ref, true)
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to get tree", resp, err), nil, nil
+		}
+		_ = resp.Body.Close()
+
+		if tree.GetTruncated() {
+			return utils.NewToolResultError(fmt.Sprintf(
+				"tree of %s/%s@%s is too large to list recursively; request smaller directories",
+				owner, repo, ref,
+			)), nil, nil
+		}
+
+		var entries []*github.TreeEntry
+		if len(paths) == 0 {
+			for _, entry := range tree.Entries {
+				if entry.GetType() == "blob" {
+					entries = append(entries, entry)
+				}
+			}
+		} else {
+			var missing []string
+			entries, missing = selectTreeEntries(tree.Entries, paths)
+			if len(missing) > 0 {
+				return utils.NewToolResultError(fmt.Sprintf(
+					"paths not found in %s/%s@%s: %s",
+					owner, repo, ref, strings.Join(missing, ", "),
+				)), nil, nil
+			}
+		}
+
+		result := GetFilesBulkResult{
+			Ref:        ref,
+			TotalFiles: len(entries),
+			Files:      make([]BulkFile, 0, len(entries)),
+		}
+
+		// Blobs are fetched one at a time so that nothing past the budget is downloaded
+		for i, entry := range entries {
+			returnedSize := min(entry.GetSize(), maxFileSize)
+			if result.BytesReturned+returnedSize > maxTotalSize {
+				for _, remaining := range entries[i:] {
+					result.RemainingPaths = append(result.RemainingPaths, remaining.GetPath())
+				}
+				break
+			}
+
+			content, resp, err := getBlobPrefix(ctx, client, owner, repo, entry.GetSHA(), maxFileSize+1)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx, fmt.Sprintf("failed to read file %s", entry.GetPath()), resp, err), nil, nil
+			}
+
+			file := bulkFileContent(content, entry.GetSize(), maxFileSize)
+			file.Path = entry.GetPath()
+			file.SHA = entry.GetSHA()
+
+			result.Files = append(result.Files, file)
+			result.BytesReturned += min(len(content), maxFileSize)
+		}
+		result.FilesReturned = len(result.Files)
+
+		return MarshalledTextResult(result), nil, nil
+	})
+
 	return tool, handler
 }
+
+// getBlobPrefix returns at most limit bytes from the start of the content of the blob sha. The
+// rest of a larger blob is not downloaded: the request asks for the range, and the response of
+// a server ignoring it is not read past limit.
+func getBlobPrefix(ctx context.Context, client *github.Client, owner, repo, sha string, limit int) ([]byte, *github.Response, error) {
+	req, err := client.NewRequest(http.MethodGet, fmt.Sprintf("repos/%s/%s/git/blobs/%s", owner, repo, sha), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github.v3.raw")
+	req.Header.Set("Range", fmt.Sprintf("bytes=0-%d", limit-1))
+
+	resp, err := client.BareDo(ctx, req)
+	if err != nil {
+		return nil, resp, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	content, err := io.ReadAll(io.LimitReader(resp.Body, int64(limit)))
+	if err != nil {
+		return nil, resp, fmt.Errorf("failed to read blob %s: %w", sha, err)
+	}
+	return content, resp, nil
+}
+
+// bulkFileContent encodes content for get_files_bulk, truncated to at most maxSize bytes.
+// content may be a prefix of the file, whose full size is the larger of size and its length.
+// Text is returned as is, cut at a character boundary; anything else is base64 encoded.
+func bulkFileContent(content []byte, size, maxSize int) BulkFile {
+	file := BulkFile{Size: max(size, len(content))}
+	if len(content) > maxSize || file.Size > maxSize {
+		content = content[:min(len(content), maxSize)]
+		file.Truncated = true
+	}
+
+	text := content
+	if file.Truncated {
+		// Drop a multi-byte character split by the truncation before checking for text
+		for i := 0; i < utf8.UTFMax && len(text) > 0 && !utf8.Valid(text); i++ {
+			text = text[:len(text)-1]
+		}
+	}
+	if utf8.Valid(text) && !bytes.ContainsRune(text, 0) {
+		file.Encoding = ContentEncodingUTF8
+		file.Content = string(text)
+		return file
+	}
+
+	file.Encoding = "base64"
+	file.Content = base64.StdEncoding.EncodeToString(content)
+	return file
+}
@@ -0,0 +1,353 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	ghErrors "github.com/github/github-mcp-server/pkg/errors"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/github/github-mcp-server/pkg/utils"
+	"github.com/google/go-github/v79/github"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// maxTemplateFiles bounds the files read from a template directory by one bulk_apply_template call
+const maxTemplateFiles = 1000
+
+// templatePlaceholder matches a {{var}} placeholder, with optional spaces inside the braces
+var templatePlaceholder = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_.-]*)\s*\}\}`)
+
+// RenderedTemplateFile maps a template file to the path it is rendered to.
+type RenderedTemplateFile struct {
+	TemplatePath string `json:"template_path"`
+	TargetPath   string `json:"target_path"`
+}
+
+// BulkApplyTemplateResult reports the files rendered from a template and how they were pushed.
+type BulkApplyTemplateResult struct {
+	TemplateRef string                 `json:"template_ref"`
+	Rendered    []RenderedTemplateFile `json:"rendered"`
+	// SkippedFiles lists binary template files, which are neither rendered nor pushed
+	SkippedFiles []string `json:"skipped_files,omitempty"`
+	// UndefinedVariables lists placeholders without a value, which were left as they are
+	UndefinedVariables []string `json:"undefined_variables,omitempty"`
+	// DryRun is set when the files were rendered but not pushed
+	DryRun bool `json:"dry_run,omitempty"`
+	*PushFilesChunkedResult
+}
+
+// renderTemplate replaces the {{var}} placeholders of text with variables, leaving placeholders
+// without a value in place so that templates can carry syntax such as GitHub Actions expressions.
+// The names of the placeholders left in place are added to undefined.
+func renderTemplate(text string, variables map[string]string, undefined map[string]bool) string {
+	return templatePlaceholder.ReplaceAllStringFunc(text, func(placeholder string) string {
+		name := templatePlaceholder.FindStringSubmatch(placeholder)[1]
+		if value, ok := variables[name]; ok {
+			return value
+		}
+		undefined[name] = true
+		return placeholder
+	})
+}
+
+// BulkApplyTemplate creates a tool to render a template directory into a branch.
+func BulkApplyTemplate(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	limits := CurrentPushLimits()
+	tool := mcp.Tool{
+		Name:        "bulk_apply_template",
+		Description: t("TOOL_BULK_APPLY_TEMPLATE_DESCRIPTION", "Scaffold files from a template directory of a repository into a target branch. {{var}} placeholders in file paths and contents are replaced with the given variables, and the rendered files are pushed in chunks like push_files_chunked, so an incomplete push can be continued with resume_push_chunked. Placeholders without a variable are left as they are and reported. Binary files are skipped."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_BULK_APPLY_TEMPLATE_USER_TITLE", "Apply template"),
+			ReadOnlyHint: false,
+		},
+		InputSchema: withCommitIdentity(&jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"template_owner": {
+					Type:        "string",
+					Description: "Owner of the repository holding the template",
+				},
+				"template_repo": {
+					Type:        "string",
+					Description: "Name of the repository holding the template",
+				},
+				"template_ref": {
+					Type:        "string",
+					Description: "Branch, tag or commit SHA of the template. Defaults to the template repository's default branch",
+				},
+				"template_path": {
+					Type:        "string",
+					Description: "Template directory. Its files are rendered relative to it. Defaults to the repository root",
+				},
+				"variables": {
+					Type:        "object",
+					Description: "Values of the {{var}} placeholders, keyed by variable name, e.g. {\"service_name\": \"billing\"}",
+					AdditionalProperties: &jsonschema.Schema{
+						Type: "string",
+					},
+				},
+				"owner": {
+					Type:        "string",
+					Description: "Target repository owner",
+				},
+				"repo": {
+					Type:        "string",
+					Description: "Target repository name",
+				},
+				"branch": {
+					Type:        "string",
+					Description: "Target branch to push the rendered files to",
+				},
+				"target_path": {
+					Type:        "string",
+					Description: "Directory of the target repository to render the template into. Defaults to the repository root",
+				},
+				"message": {
+					Type:        "string",
+					Description: "Base commit message (chunk number will be appended unless message_template is set)",
+				},
+				"message_template": messageTemplateSchema(),
+				"chunk_size": {
+					Type:        "integer",
+					Description: fmt.Sprintf("Number of files per chunk (default: %d, max: %d)", limits.DefaultChunkSize, limits.MaxChunkSize),
+					Default:     json.RawMessage(fmt.Sprintf("%d", limits.DefaultChunkSize)),
+				},
+				"continue_on_error": {
+					Type:        "boolean",
+					Description: "Continue processing remaining chunks if one fails (default: false)",
+					Default:     json.RawMessage("false"),
+				},
+				"dry_run": {
+					Type:        "boolean",
+					Description: "Render the template and report the target paths without pushing",
+				},
+				"max_retries":   maxRetriesSchema(),
+				"allow_empty":   allowEmptySchema(),
+				"allow_secrets": allowSecretsSchema(),
+				"result_detail": resultDetailSchema(),
+			},
+			Required: []string{"template_owner", "template_repo", "owner", "repo", "branch", "message"},
+		}),
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, req *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		templateOwner, err := RequiredParam[string](args, "template_owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		templateRepo, err := RequiredParam[string](args, "template_repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		templateRef, err := OptionalParam[string](args, "template_ref")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		templatePath, err := OptionalParam[string](args, "template_path")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		repo, err := RequiredParam[string](args, "repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		branch, err := RequiredParam[string](args, "branch")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		targetPath, err := OptionalParam[string](args, "target_path")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		message, err := RequiredParam[string](args, "message")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		messageTemplate, err := OptionalParam[string](args, "message_template")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		if messageTemplate != "" {
+			if err := validateMessageTemplate(messageTemplate); err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+		}
+		chunkSize, err := OptionalIntParamWithDefault(args, "chunk_size", CurrentPushLimits().DefaultChunkSize)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		if chunkSize < 1 {
+			return utils.NewToolResultError("chunk_size must be at least 1"), nil, nil
+		}
+		continueOnError, err := OptionalParam[bool](args, "continue_on_error")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		dryRun, err := OptionalParam[bool](args, "dry_run")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		allowEmpty, err := OptionalParam[bool](args, "allow_empty")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		allowSecrets, err := OptionalParam[bool](args, "allow_secrets")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		retry, err := chunkRetryConfigFromArgs(args)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		detail, err := resultDetailFromArgs(req, args)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		identity, err := commitIdentityFromArgs(ctx, args)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		variables := make(map[string]string)
+		if raw, ok := args["variables"]; ok && raw != nil {
+			variablesObj, ok := raw.(map[string]any)
+			if !ok {
+				return utils.NewToolResultError("variables must be an object mapping variable names to values"), nil, nil
+			}
+			for name, value := range variablesObj {
+				text, ok := value.(string)
+				if !ok {
+					return utils.NewToolResultError(fmt.Sprintf("variable %s must be a string", name)), nil, nil
+				}
+				variables[name] = text
+			}
+		}
+		templatePath = strings.Trim(templatePath, "/")
+		targetPath = strings.Trim(targetPath, "/")
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		if templateRef == "" {
+			templateRepository, resp, err := client.Repositories.Get(ctx, templateOwner, templateRepo)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to get template repository", resp, err), nil, nil
+			}
+			_ = resp.Body.Close()
+			templateRef = templateRepository.GetDefaultBranch()
+		}
+
+		tree, resp, err := client.Git.GetTree(ctx, templateOwner, templateRepo, templateRef, true)
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to get template tree", resp, err), nil, nil
+		}
+		_ = resp.Body.Close()
+		if tree.GetTruncated() {
+			return utils.NewToolResultError(fmt.Sprintf(
+				"tree of %s/%s@%s is too large to list recursively; move the template to a smaller repository",
+				templateOwner, templateRepo, templateRef,
+			)), nil, nil
+		}
+
+		entries := tree.Entries
+		if templatePath != "" {
+			var missing []string
+			entries, missing = selectTreeEntries(tree.Entries, []string{templatePath})
+			if len(missing) > 0 {
+				return utils.NewToolResultError(fmt.Sprintf("template path %s not found in %s/%s@%s", templatePath, templateOwner, templateRepo, templateRef)), nil, nil
+			}
+		}
+		var sources []*github.TreeEntry
+		for _, entry := range entries {
+			// Symlinks and submodules have no content to render
+			if entry.GetType() == "blob" && entry.GetMode() != "120000" {
+				sources = append(sources, entry)
+			}
+		}
+		if len(sources) == 0 {
+			return utils.NewToolResultError(fmt.Sprintf("template path %s has no files", templatePath)), nil, nil
+		}
+		if len(sources) > maxTemplateFiles {
+			return utils.NewToolResultError(fmt.Sprintf("template has %d files, more than the maximum of %d per call", len(sources), maxTemplateFiles)), nil, nil
+		}
+
+		result := BulkApplyTemplateResult{
+			TemplateRef: templateRef,
+			Rendered:    make([]RenderedTemplateFile, 0, len(sources)),
+			DryRun:      dryRun,
+		}
+		undefined := make(map[string]bool)
+		filesObj := make([]interface{}, 0, len(sources))
+		for _, source := range sources {
+			content, resp, err := client.Git.GetBlobRaw(ctx, templateOwner, templateRepo, source.GetSHA())
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx, fmt.Sprintf("failed to read template file %s", source.GetPath()), resp, err), nil, nil
+			}
+			_ = resp.Body.Close()
+			if !utf8.Valid(content) || bytes.ContainsRune(content, 0) {
+				result.SkippedFiles = append(result.SkippedFiles, source.GetPath())
+				continue
+			}
+
+			relative := source.GetPath()
+			if templatePath != "" && relative != templatePath {
+				relative = strings.TrimPrefix(relative, templatePath+"/")
+			}
+			rendered := renderTemplate(relative, variables, undefined)
+			if targetPath != "" {
+				rendered = targetPath + "/" + rendered
+			}
+			result.Rendered = append(result.Rendered, RenderedTemplateFile{TemplatePath: source.GetPath(), TargetPath: rendered})
+			filesObj = append(filesObj, map[string]interface{}{
+				"path":    rendered,
+				"content": renderTemplate(string(content), variables, undefined),
+			})
+		}
+		for name := range undefined {
+			result.UndefinedVariables = append(result.UndefinedVariables, name)
+		}
+		sort.Strings(result.UndefinedVariables)
+		if len(filesObj) == 0 {
+			return utils.NewToolResultError("template has only binary files, which bulk_apply_template does not render; use bulk_copy_files"), nil, nil
+		}
+
+		// Rendered paths are validated like the paths of push_files_chunked
+		_, files, err := ValidateFilesWithOptions(filesObj, FileValidationOptions{AllowSecrets: allowSecrets})
+		if err != nil {
+			return prefixedToolErrorResult("rendered template is invalid", err), nil, nil
+		}
+		if dryRun {
+			return MarshalledTextResult(result), nil, nil
+		}
+
+		op := newChunkedPushOperation(ctx, owner, repo, "refs/heads/"+branch, message, planChunks(files, chunkSize))
+		op.MessageTemplate = messageTemplate
+		op.Identity = identity
+		op.Retry = retry
+		op.AllowEmpty = allowEmpty
+		op.notify = chunkNotifier(req, "bulk_apply_template")
+		op.run(ctx, client, continueOnError)
+		pushed := op.result().withDetail(detail)
+		result.PushFilesChunkedResult = &pushed
+		if detail != ResultDetailFull {
+			result.Rendered = nil
+		}
+
+		return MarshalledTextResult(result), nil, nil
+	})
+
+	return withIdempotencyKey(tool, handler)
+}
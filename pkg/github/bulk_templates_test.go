@@ -0,0 +1,182 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v79/github"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_renderTemplate(t *testing.T) {
+	undefined := make(map[string]bool)
+	rendered := renderTemplate("name: {{ service }}-{{env}}\ntoken: ${{ secrets.TOKEN }}\n", map[string]string{"service": "billing", "env": "prod"}, undefined)
+	assert.Equal(t, "name: billing-prod\ntoken: ${{ secrets.TOKEN }}\n", rendered)
+	assert.Equal(t, map[string]bool{"secrets.TOKEN": true}, undefined)
+}
+
+func Test_BulkApplyTemplate(t *testing.T) {
+	mockClient := github.NewClient(nil)
+	tool, _ := BulkApplyTemplate(stubGetClientFn(mockClient), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	schema, ok := tool.InputSchema.(*jsonschema.Schema)
+	require.True(t, ok, "InputSchema should be *jsonschema.Schema")
+
+	assert.Equal(t, "bulk_apply_template", tool.Name)
+	assert.Contains(t, schema.Properties, "variables")
+	assert.Contains(t, schema.Properties, "template_path")
+	assert.ElementsMatch(t, schema.Required, []string{"template_owner", "template_repo", "owner", "repo", "branch", "message"})
+
+	blobs := map[string]string{
+		"readme-sha": "# {{service}}\n",
+		"ci-sha":     "name: {{ service }} CI\nenv:\n  TOKEN: ${{ secrets.TOKEN }}\n",
+		"logo-sha":   "\x89PNG\x00\x01",
+	}
+	templateOptions := func() []mock.MockBackendOption {
+		return []mock.MockBackendOption{
+			mock.WithRequestMatch(
+				mock.GetReposByOwnerByRepo,
+				&github.Repository{DefaultBranch: github.Ptr("main")},
+			),
+			mock.WithRequestMatchHandler(
+				mock.GetReposGitTreesByOwnerByRepoByTreeSha,
+				expectPath(t, "/repos/corp/templates/git/trees/main").andThen(
+					mockResponse(t, http.StatusOK, &github.Tree{Entries: []*github.TreeEntry{
+						{Path: github.Ptr("service"), Type: github.Ptr("tree"), Mode: github.Ptr("040000"), SHA: github.Ptr("dir-sha")},
+						{Path: github.Ptr("service/README.md"), Type: github.Ptr("blob"), Mode: github.Ptr("100644"), SHA: github.Ptr("readme-sha")},
+						{Path: github.Ptr("service/.github/workflows/{{service}}.yml"), Type: github.Ptr("blob"), Mode: github.Ptr("100644"), SHA: github.Ptr("ci-sha")},
+						{Path: github.Ptr("service/logo.png"), Type: github.Ptr("blob"), Mode: github.Ptr("100644"), SHA: github.Ptr("logo-sha")},
+						{Path: github.Ptr("other/README.md"), Type: github.Ptr("blob"), Mode: github.Ptr("100644"), SHA: github.Ptr("other-sha")},
+					}}),
+				),
+			),
+			mock.WithRequestMatchHandler(
+				mock.GetReposGitBlobsByOwnerByRepoByFileSha,
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					sha := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+					content, ok := blobs[sha]
+					if !assert.True(t, ok, "unexpected blob %s", sha) {
+						w.WriteHeader(http.StatusNotFound)
+						return
+					}
+					_, _ = w.Write([]byte(content))
+				}),
+			),
+		}
+	}
+
+	tests := []struct {
+		name           string
+		mockedClient   *http.Client
+		args           map[string]interface{}
+		expectError    bool
+		expectedErrMsg string
+		validate       func(t *testing.T, result BulkApplyTemplateResult)
+	}{
+		{
+			name: "renders paths and contents and pushes them",
+			mockedClient: mock.NewMockedHTTPClient(append(templateOptions(),
+				mockGitDataAPI(t)[0],
+				mockGitDataAPI(t)[1],
+				mock.WithRequestMatchHandler(
+					mock.PostReposGitTreesByOwnerByRepo,
+					http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						var body struct {
+							Tree []map[string]any `json:"tree"`
+						}
+						assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+						contents := make(map[string]any)
+						for _, entry := range body.Tree {
+							contents[entry["path"].(string)] = entry["content"]
+						}
+						assert.Equal(t, map[string]any{
+							"services/billing/README.md":                     "# billing\n",
+							"services/billing/.github/workflows/billing.yml": "name: billing CI\nenv:\n  TOKEN: ${{ secrets.TOKEN }}\n",
+						}, contents)
+						mockResponse(t, http.StatusCreated, &github.Tree{SHA: github.Ptr("new-tree-sha")})(w, r)
+					}),
+				),
+				mockGitDataAPI(t)[3],
+				mockGitDataAPI(t)[4],
+			)...),
+			args: map[string]interface{}{"target_path": "services/billing"},
+			validate: func(t *testing.T, result BulkApplyTemplateResult) {
+				assert.Equal(t, "main", result.TemplateRef)
+				assert.Equal(t, []string{"service/logo.png"}, result.SkippedFiles)
+				assert.Equal(t, []string{"secrets.TOKEN"}, result.UndefinedVariables)
+				require.NotNil(t, result.PushFilesChunkedResult)
+				assert.Equal(t, 1, result.SuccessfulChunks)
+				assert.Equal(t, 2, result.TotalFiles)
+			},
+		},
+		{
+			name:         "dry run renders without pushing",
+			mockedClient: mock.NewMockedHTTPClient(templateOptions()...),
+			args:         map[string]interface{}{"dry_run": true},
+			validate: func(t *testing.T, result BulkApplyTemplateResult) {
+				assert.True(t, result.DryRun)
+				assert.Nil(t, result.PushFilesChunkedResult)
+				assert.Equal(t, []RenderedTemplateFile{
+					{TemplatePath: "service/README.md", TargetPath: "README.md"},
+					{TemplatePath: "service/.github/workflows/{{service}}.yml", TargetPath: ".github/workflows/billing.yml"},
+				}, result.Rendered)
+			},
+		},
+		{
+			name:           "missing template path",
+			mockedClient:   mock.NewMockedHTTPClient(templateOptions()...),
+			args:           map[string]interface{}{"template_path": "missing"},
+			expectError:    true,
+			expectedErrMsg: "template path missing not found",
+		},
+		{
+			name:           "variables must be strings",
+			mockedClient:   mock.NewMockedHTTPClient(),
+			args:           map[string]interface{}{"variables": map[string]interface{}{"replicas": float64(3)}},
+			expectError:    true,
+			expectedErrMsg: "variable replicas must be a string",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, handler := BulkApplyTemplate(stubGetClientFn(github.NewClient(tc.mockedClient)), translations.NullTranslationHelper)
+
+			args := map[string]interface{}{
+				"template_owner": "corp",
+				"template_repo":  "templates",
+				"template_path":  "service",
+				"variables":      map[string]interface{}{"service": "billing"},
+				"owner":          "owner",
+				"repo":           "repo",
+				"branch":         "main",
+				"message":        "Scaffold billing service",
+			}
+			for k, v := range tc.args {
+				args[k] = v
+			}
+			request := createMCPRequest(args)
+			result, _, err := handler(context.Background(), &request, args)
+			require.NoError(t, err)
+
+			if tc.expectError {
+				assert.Contains(t, getErrorResult(t, result).Text, tc.expectedErrMsg)
+				return
+			}
+
+			require.False(t, result.IsError, getTextResult(t, result).Text)
+			var applied BulkApplyTemplateResult
+			require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &applied))
+			tc.validate(t, applied)
+		})
+	}
+}
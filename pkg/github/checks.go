@@ -0,0 +1,591 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	ghErrors "github.com/github/github-mcp-server/pkg/errors"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/github/github-mcp-server/pkg/utils"
+	"github.com/google/go-github/v79/github"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// maxCheckRunAnnotations is the number of annotations the API accepts per request.
+// Further annotations are sent with check run updates, which append to the existing ones.
+const maxCheckRunAnnotations = 50
+
+// MinimalCheckRun is the output type for check runs.
+type MinimalCheckRun struct {
+	ID               int64  `json:"id"`
+	Name             string `json:"name"`
+	HeadSHA          string `json:"head_sha"`
+	Status           string `json:"status"`
+	Conclusion       string `json:"conclusion,omitempty"`
+	HTMLURL          string `json:"html_url,omitempty"`
+	DetailsURL       string `json:"details_url,omitempty"`
+	AnnotationsCount int    `json:"annotations_count"`
+}
+
+// MinimalCommitStatus is the output type for commit statuses.
+type MinimalCommitStatus struct {
+	ID          int64  `json:"id"`
+	SHA         string `json:"sha"`
+	State       string `json:"state"`
+	Context     string `json:"context"`
+	Description string `json:"description,omitempty"`
+	TargetURL   string `json:"target_url,omitempty"`
+}
+
+func convertToMinimalCheckRun(run *github.CheckRun) MinimalCheckRun {
+	return MinimalCheckRun{
+		ID:               run.GetID(),
+		Name:             run.GetName(),
+		HeadSHA:          run.GetHeadSHA(),
+		Status:           run.GetStatus(),
+		Conclusion:       run.GetConclusion(),
+		HTMLURL:          run.GetHTMLURL(),
+		DetailsURL:       run.GetDetailsURL(),
+		AnnotationsCount: run.GetOutput().GetAnnotationsCount(),
+	}
+}
+
+// checkRunInputProperties returns the input properties shared by create_check_run and update_check_run.
+func checkRunInputProperties() map[string]*jsonschema.Schema {
+	return map[string]*jsonschema.Schema{
+		"owner": {
+			Type:        "string",
+			Description: "Repository owner",
+		},
+		"repo": {
+			Type:        "string",
+			Description: "Repository name",
+		},
+		"status": {
+			Type:        "string",
+			Description: "Status of the check run",
+			Enum:        []any{"queued", "in_progress", "completed"},
+		},
+		"conclusion": {
+			Type:        "string",
+			Description: "Conclusion of the check run. Setting it completes the check run",
+			Enum:        []any{"success", "failure", "neutral", "cancelled", "skipped", "timed_out", "action_required"},
+		},
+		"details_url": {
+			Type:        "string",
+			Description: "URL of the full details of the check on the CI system",
+		},
+		"external_id": {
+			Type:        "string",
+			Description: "Reference of the run on the CI system",
+		},
+		"title": {
+			Type:        "string",
+			Description: "Title of the check run output. Defaults to the check run name",
+		},
+		"summary": {
+			Type:        "string",
+			Description: "Summary of the check run output in Markdown. Required with title, text or annotations",
+		},
+		"text": {
+			Type:        "string",
+			Description: "Details of the check run output in Markdown",
+		},
+		"annotations": {
+			Type:        "array",
+			Description: "Annotations on lines of files of the commit. Any number can be given; they are sent 50 at a time",
+			Items: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"path": {
+						Type:        "string",
+						Description: "Path of the file, relative to the repository root",
+					},
+					"start_line": {
+						Type:        "number",
+						Description: "First line of the annotation",
+					},
+					"end_line": {
+						Type:        "number",
+						Description: "Last line of the annotation. Defaults to start_line",
+					},
+					"start_column": {
+						Type:        "number",
+						Description: "First column, only when start_line and end_line are the same",
+					},
+					"end_column": {
+						Type:        "number",
+						Description: "Last column, only when start_line and end_line are the same",
+					},
+					"annotation_level": {
+						Type:        "string",
+						Description: "Level of the annotation",
+						Enum:        []any{"notice", "warning", "failure"},
+					},
+					"message": {
+						Type:        "string",
+						Description: "Short description of the problem",
+					},
+					"title": {
+						Type:        "string",
+						Description: "Title of the annotation",
+					},
+					"raw_details": {
+						Type:        "string",
+						Description: "Details of the problem",
+					},
+				},
+				Required: []string{"path", "start_line", "annotation_level", "message"},
+			},
+		},
+	}
+}
+
+// checkRunAnnotations parses the annotations parameter.
+func checkRunAnnotations(args map[string]any) ([]*github.CheckRunAnnotation, error) {
+	raw, ok := args["annotations"]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	items, ok := raw.([]any)
+	if !ok {
+		return nil, fmt.Errorf("annotations must be an array of objects")
+	}
+
+	annotations := make([]*github.CheckRunAnnotation, 0, len(items))
+	for i, item := range items {
+		fields, ok := item.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("annotations[%d] must be an object", i)
+		}
+		path, err := RequiredParam[string](fields, "path")
+		if err != nil {
+			return nil, fmt.Errorf("annotations[%d]: %w", i, err)
+		}
+		startLine, err := RequiredInt(fields, "start_line")
+		if err != nil {
+			return nil, fmt.Errorf("annotations[%d]: %w", i, err)
+		}
+		level, err := RequiredParam[string](fields, "annotation_level")
+		if err != nil {
+			return nil, fmt.Errorf("annotations[%d]: %w", i, err)
+		}
+		message, err := RequiredParam[string](fields, "message")
+		if err != nil {
+			return nil, fmt.Errorf("annotations[%d]: %w", i, err)
+		}
+		endLine, err := OptionalIntParamWithDefault(fields, "end_line", startLine)
+		if err != nil {
+			return nil, fmt.Errorf("annotations[%d]: %w", i, err)
+		}
+
+		annotation := &github.CheckRunAnnotation{
+			Path:            github.Ptr(path),
+			StartLine:       github.Ptr(startLine),
+			EndLine:         github.Ptr(endLine),
+			AnnotationLevel: github.Ptr(level),
+			Message:         github.Ptr(message),
+		}
+		for param, field := range map[string]**int{"start_column": &annotation.StartColumn, "end_column": &annotation.EndColumn} {
+			value, err := OptionalIntParam(fields, param)
+			if err != nil {
+				return nil, fmt.Errorf("annotations[%d]: %w", i, err)
+			}
+			if value != 0 {
+				*field = github.Ptr(value)
+			}
+		}
+		for param, field := range map[string]**string{"title": &annotation.Title, "raw_details": &annotation.RawDetails} {
+			value, err := OptionalParam[string](fields, param)
+			if err != nil {
+				return nil, fmt.Errorf("annotations[%d]: %w", i, err)
+			}
+			if value != "" {
+				*field = github.Ptr(value)
+			}
+		}
+		if startLine != endLine && (annotation.StartColumn != nil || annotation.EndColumn != nil) {
+			return nil, fmt.Errorf("annotations[%d]: columns can only be set when start_line and end_line are the same", i)
+		}
+		annotations = append(annotations, annotation)
+	}
+	return annotations, nil
+}
+
+// checkRunRequest holds the check run parameters shared by create_check_run and update_check_run.
+type checkRunRequest struct {
+	status      *string
+	conclusion  *string
+	completedAt *github.Timestamp
+	detailsURL  *string
+	externalID  *string
+	output      *github.CheckRunOutput
+	annotations []*github.CheckRunAnnotation
+}
+
+// parseCheckRunRequest reads the shared check run parameters. The output title defaults to name.
+func parseCheckRunRequest(args map[string]any, name string) (*checkRunRequest, error) {
+	request := &checkRunRequest{}
+	for param, field := range map[string]**string{
+		"status":      &request.status,
+		"conclusion":  &request.conclusion,
+		"details_url": &request.detailsURL,
+		"external_id": &request.externalID,
+	} {
+		value, err := OptionalParam[string](args, param)
+		if err != nil {
+			return nil, err
+		}
+		if value != "" {
+			*field = github.Ptr(value)
+		}
+	}
+	if request.conclusion != nil {
+		if request.status != nil && *request.status != "completed" {
+			return nil, fmt.Errorf("status must be completed when a conclusion is set")
+		}
+		request.status = github.Ptr("completed")
+		request.completedAt = &github.Timestamp{Time: time.Now().UTC().Truncate(time.Second)}
+	} else if request.status != nil && *request.status == "completed" {
+		return nil, fmt.Errorf("a completed check run requires a conclusion")
+	}
+
+	title, err := OptionalParam[string](args, "title")
+	if err != nil {
+		return nil, err
+	}
+	summary, err := OptionalParam[string](args, "summary")
+	if err != nil {
+		return nil, err
+	}
+	text, err := OptionalParam[string](args, "text")
+	if err != nil {
+		return nil, err
+	}
+	request.annotations, err = checkRunAnnotations(args)
+	if err != nil {
+		return nil, err
+	}
+
+	if summary == "" {
+		if title != "" || text != "" || len(request.annotations) > 0 {
+			return nil, fmt.Errorf("summary is required to set the check run output or annotations")
+		}
+		return request, nil
+	}
+	if title == "" {
+		title = name
+	}
+	request.output = &github.CheckRunOutput{Title: github.Ptr(title), Summary: github.Ptr(summary)}
+	if text != "" {
+		request.output.Text = github.Ptr(text)
+	}
+	return request, nil
+}
+
+// nextAnnotations returns the output to send with the next batch of annotations and removes the batch from request.
+func (r *checkRunRequest) nextAnnotations() *github.CheckRunOutput {
+	if r.output == nil {
+		return nil
+	}
+	output := *r.output
+	batch := min(len(r.annotations), maxCheckRunAnnotations)
+	output.Annotations = r.annotations[:batch]
+	r.annotations = r.annotations[batch:]
+	return &output
+}
+
+// addRemainingAnnotations sends the annotations that did not fit in the first request.
+func addRemainingAnnotations(ctx context.Context, client *github.Client, owner, repo string, run *github.CheckRun, request *checkRunRequest) (*github.CheckRun, *github.Response, error) {
+	var resp *github.Response
+	for len(request.annotations) > 0 {
+		var err error
+		run, resp, err = client.Checks.UpdateCheckRun(ctx, owner, repo, run.GetID(), github.UpdateCheckRunOptions{
+			Name:   run.GetName(),
+			Output: request.nextAnnotations(),
+		})
+		if err != nil {
+			return nil, resp, err
+		}
+		_ = resp.Body.Close()
+	}
+	return run, resp, nil
+}
+
+// CreateCheckRun creates a tool to create a check run on a commit.
+func CreateCheckRun(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	properties := checkRunInputProperties()
+	properties["name"] = &jsonschema.Schema{
+		Type:        "string",
+		Description: "Name of the check, e.g. 'lint'",
+	}
+	properties["head_sha"] = &jsonschema.Schema{
+		Type:        "string",
+		Description: "SHA of the commit to report on",
+	}
+
+	tool := mcp.Tool{
+		Name:        "create_check_run",
+		Description: t("TOOL_CREATE_CHECK_RUN_DESCRIPTION", "Create a check run on a commit to report CI results, with an output summary and line annotations. Setting a conclusion completes the check run. Check runs can only be created with a GitHub App token; use create_commit_status otherwise."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_CREATE_CHECK_RUN_USER_TITLE", "Create check run"),
+			ReadOnlyHint: false,
+		},
+		InputSchema: &jsonschema.Schema{
+			Type:       "object",
+			Properties: properties,
+			Required:   []string{"owner", "repo", "name", "head_sha"},
+		},
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		repo, err := RequiredParam[string](args, "repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		name, err := RequiredParam[string](args, "name")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		headSHA, err := RequiredParam[string](args, "head_sha")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		request, err := parseCheckRunRequest(args, name)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		opts := github.CreateCheckRunOptions{
+			Name:        name,
+			HeadSHA:     headSHA,
+			DetailsURL:  request.detailsURL,
+			ExternalID:  request.externalID,
+			Status:      request.status,
+			Conclusion:  request.conclusion,
+			CompletedAt: request.completedAt,
+			Output:      request.nextAnnotations(),
+		}
+		if request.status != nil && *request.status != "queued" {
+			opts.StartedAt = &github.Timestamp{Time: time.Now().UTC().Truncate(time.Second)}
+		}
+
+		run, resp, err := client.Checks.CreateCheckRun(ctx, owner, repo, opts)
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to create check run", resp, err), nil, nil
+		}
+		_ = resp.Body.Close()
+
+		run, resp, err = addRemainingAnnotations(ctx, client, owner, repo, run, request)
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to add check run annotations", resp, err), nil, nil
+		}
+
+		return MarshalledTextResult(convertToMinimalCheckRun(run)), nil, nil
+	})
+
+	return tool, handler
+}
+
+// UpdateCheckRun creates a tool to update the status, conclusion and output of a check run.
+func UpdateCheckRun(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	properties := checkRunInputProperties()
+	properties["check_run_id"] = &jsonschema.Schema{
+		Type:        "number",
+		Description: "ID of the check run",
+	}
+	properties["name"] = &jsonschema.Schema{
+		Type:        "string",
+		Description: "New name of the check",
+	}
+
+	tool := mcp.Tool{
+		Name:        "update_check_run",
+		Description: t("TOOL_UPDATE_CHECK_RUN_DESCRIPTION", "Update a check run, e.g. to complete it with a conclusion. A new output replaces the previous title, summary and text, while annotations are added to the existing ones."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_UPDATE_CHECK_RUN_USER_TITLE", "Update check run"),
+			ReadOnlyHint: false,
+		},
+		InputSchema: &jsonschema.Schema{
+			Type:       "object",
+			Properties: properties,
+			Required:   []string{"owner", "repo", "check_run_id"},
+		},
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		repo, err := RequiredParam[string](args, "repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		checkRunID, err := RequiredBigInt(args, "check_run_id")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		name, err := OptionalParam[string](args, "name")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		// The update request always sends a name, so keep the current one unless renaming
+		if name == "" {
+			run, resp, err := client.Checks.GetCheckRun(ctx, owner, repo, checkRunID)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to get check run", resp, err), nil, nil
+			}
+			_ = resp.Body.Close()
+			name = run.GetName()
+		}
+
+		request, err := parseCheckRunRequest(args, name)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		run, resp, err := client.Checks.UpdateCheckRun(ctx, owner, repo, checkRunID, github.UpdateCheckRunOptions{
+			Name:        name,
+			DetailsURL:  request.detailsURL,
+			ExternalID:  request.externalID,
+			Status:      request.status,
+			Conclusion:  request.conclusion,
+			CompletedAt: request.completedAt,
+			Output:      request.nextAnnotations(),
+		})
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to update check run", resp, err), nil, nil
+		}
+		_ = resp.Body.Close()
+
+		run, resp, err = addRemainingAnnotations(ctx, client, owner, repo, run, request)
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to add check run annotations", resp, err), nil, nil
+		}
+
+		return MarshalledTextResult(convertToMinimalCheckRun(run)), nil, nil
+	})
+
+	return tool, handler
+}
+
+// CreateCommitStatus creates a tool to set a commit status.
+func CreateCommitStatus(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "create_commit_status",
+		Description: t("TOOL_CREATE_COMMIT_STATUS_DESCRIPTION", "Set a commit status to report CI results on a commit. A new status with the same context replaces the previous one."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_CREATE_COMMIT_STATUS_USER_TITLE", "Create commit status"),
+			ReadOnlyHint: false,
+		},
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"owner": {
+					Type:        "string",
+					Description: "Repository owner",
+				},
+				"repo": {
+					Type:        "string",
+					Description: "Repository name",
+				},
+				"sha": {
+					Type:        "string",
+					Description: "SHA of the commit",
+				},
+				"state": {
+					Type:        "string",
+					Description: "State of the status",
+					Enum:        []any{"pending", "success", "failure", "error"},
+				},
+				"context": {
+					Type:        "string",
+					Description: "Label that tells this status apart from the statuses of other systems, e.g. 'ci/lint'. Defaults to 'default'",
+				},
+				"description": {
+					Type:        "string",
+					Description: "Short description of the status",
+				},
+				"target_url": {
+					Type:        "string",
+					Description: "URL of the full details of the status",
+				},
+			},
+			Required: []string{"owner", "repo", "sha", "state"},
+		},
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		repo, err := RequiredParam[string](args, "repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		sha, err := RequiredParam[string](args, "sha")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		state, err := RequiredParam[string](args, "state")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		status := github.RepoStatus{State: github.Ptr(state)}
+		for param, field := range map[string]**string{
+			"context":     &status.Context,
+			"description": &status.Description,
+			"target_url":  &status.TargetURL,
+		} {
+			value, err := OptionalParam[string](args, param)
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			if value != "" {
+				*field = github.Ptr(value)
+			}
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		created, resp, err := client.Repositories.CreateStatus(ctx, owner, repo, sha, status)
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to create commit status", resp, err), nil, nil
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		return MarshalledTextResult(MinimalCommitStatus{
+			ID:          created.GetID(),
+			SHA:         sha,
+			State:       created.GetState(),
+			Context:     created.GetContext(),
+			Description: created.GetDescription(),
+			TargetURL:   created.GetTargetURL(),
+		}), nil, nil
+	})
+
+	return tool, handler
+}
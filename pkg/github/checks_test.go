@@ -0,0 +1,212 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v79/github"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CreateCheckRun(t *testing.T) {
+	tool, _ := CreateCheckRun(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	t.Run("completed with annotations in batches", func(t *testing.T) {
+		annotations := make([]any, 0, 60)
+		for i := 1; i <= 60; i++ {
+			annotations = append(annotations, map[string]any{
+				"path":             "main.go",
+				"start_line":       float64(i),
+				"annotation_level": "warning",
+				"message":          fmt.Sprintf("problem %d", i),
+			})
+		}
+
+		var created, updated map[string]any
+		mockedClient := mock.NewMockedHTTPClient(
+			mock.WithRequestMatchHandler(
+				mock.PostReposCheckRunsByOwnerByRepo,
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					require.NoError(t, json.NewDecoder(r.Body).Decode(&created))
+					mockResponse(t, http.StatusCreated, &github.CheckRun{
+						ID:     github.Ptr(int64(5)),
+						Name:   github.Ptr("lint"),
+						Status: github.Ptr("completed"),
+					})(w, r)
+				}),
+			),
+			mock.WithRequestMatchHandler(
+				mock.PatchReposCheckRunsByOwnerByRepoByCheckRunId,
+				expectPath(t, "/repos/owner/repo/check-runs/5").andThen(
+					http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						require.NoError(t, json.NewDecoder(r.Body).Decode(&updated))
+						mockResponse(t, http.StatusOK, &github.CheckRun{
+							ID:         github.Ptr(int64(5)),
+							Name:       github.Ptr("lint"),
+							HeadSHA:    github.Ptr("abc123"),
+							Status:     github.Ptr("completed"),
+							Conclusion: github.Ptr("failure"),
+							Output:     &github.CheckRunOutput{AnnotationsCount: github.Ptr(60)},
+						})(w, r)
+					}),
+				),
+			),
+		)
+		_, handler := CreateCheckRun(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+		args := map[string]any{
+			"owner":       "owner",
+			"repo":        "repo",
+			"name":        "lint",
+			"head_sha":    "abc123",
+			"conclusion":  "failure",
+			"summary":     "60 problems",
+			"annotations": annotations,
+		}
+		request := createMCPRequest(args)
+		result, _, err := handler(context.Background(), &request, args)
+		require.NoError(t, err)
+		require.False(t, result.IsError, getTextResult(t, result).Text)
+
+		assert.Equal(t, "completed", created["status"])
+		assert.Equal(t, "failure", created["conclusion"])
+		assert.NotEmpty(t, created["completed_at"])
+		output := created["output"].(map[string]any)
+		assert.Equal(t, "lint", output["title"])
+		assert.Equal(t, "60 problems", output["summary"])
+		assert.Len(t, output["annotations"], 50)
+		first := output["annotations"].([]any)[0].(map[string]any)
+		assert.Equal(t, float64(1), first["end_line"])
+
+		assert.Equal(t, "lint", updated["name"])
+		assert.Len(t, updated["output"].(map[string]any)["annotations"], 10)
+
+		var run MinimalCheckRun
+		require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &run))
+		assert.Equal(t, MinimalCheckRun{ID: 5, Name: "lint", HeadSHA: "abc123", Status: "completed", Conclusion: "failure", AnnotationsCount: 60}, run)
+	})
+
+	tests := []struct {
+		name        string
+		args        map[string]any
+		expectError string
+	}{
+		{
+			name:        "annotations without summary",
+			args:        map[string]any{"annotations": []any{map[string]any{"path": "a.go", "start_line": float64(1), "annotation_level": "notice", "message": "m"}}},
+			expectError: "summary is required",
+		},
+		{
+			name:        "completed without conclusion",
+			args:        map[string]any{"status": "completed"},
+			expectError: "a completed check run requires a conclusion",
+		},
+		{
+			name:        "conclusion with other status",
+			args:        map[string]any{"status": "in_progress", "conclusion": "success"},
+			expectError: "status must be completed when a conclusion is set",
+		},
+		{
+			name:        "annotation without message",
+			args:        map[string]any{"summary": "s", "annotations": []any{map[string]any{"path": "a.go", "start_line": float64(1), "annotation_level": "notice"}}},
+			expectError: "annotations[0]: missing required parameter: message",
+		},
+		{
+			name:        "columns across lines",
+			args:        map[string]any{"summary": "s", "annotations": []any{map[string]any{"path": "a.go", "start_line": float64(1), "end_line": float64(2), "start_column": float64(3), "annotation_level": "notice", "message": "m"}}},
+			expectError: "columns can only be set when start_line and end_line are the same",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, handler := CreateCheckRun(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+			args := map[string]any{"owner": "owner", "repo": "repo", "name": "lint", "head_sha": "abc123"}
+			for k, v := range tc.args {
+				args[k] = v
+			}
+			request := createMCPRequest(args)
+			result, _, err := handler(context.Background(), &request, args)
+			require.NoError(t, err)
+			require.True(t, result.IsError)
+			assert.Contains(t, getErrorResult(t, result).Text, tc.expectError)
+		})
+	}
+}
+
+func Test_UpdateCheckRun(t *testing.T) {
+	tool, _ := UpdateCheckRun(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.GetReposCheckRunsByOwnerByRepoByCheckRunId,
+			mockResponse(t, http.StatusOK, &github.CheckRun{ID: github.Ptr(int64(5)), Name: github.Ptr("tests")}),
+		),
+		mock.WithRequestMatchHandler(
+			mock.PatchReposCheckRunsByOwnerByRepoByCheckRunId,
+			expectRequestBody(t, map[string]any{
+				"name":   "tests",
+				"status": "in_progress",
+				"output": map[string]any{"title": "Running", "summary": "3 of 10 suites done"},
+			}).andThen(
+				mockResponse(t, http.StatusOK, &github.CheckRun{ID: github.Ptr(int64(5)), Name: github.Ptr("tests"), Status: github.Ptr("in_progress")}),
+			),
+		),
+	)
+	_, handler := UpdateCheckRun(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+	args := map[string]any{"owner": "owner", "repo": "repo", "check_run_id": float64(5), "status": "in_progress", "title": "Running", "summary": "3 of 10 suites done"}
+	request := createMCPRequest(args)
+	result, _, err := handler(context.Background(), &request, args)
+	require.NoError(t, err)
+	require.False(t, result.IsError, getTextResult(t, result).Text)
+
+	var run MinimalCheckRun
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &run))
+	assert.Equal(t, "in_progress", run.Status)
+}
+
+func Test_CreateCommitStatus(t *testing.T) {
+	tool, _ := CreateCommitStatus(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.PostReposStatusesByOwnerByRepoBySha,
+			expectPath(t, "/repos/owner/repo/statuses/abc123").andThen(
+				expectRequestBody(t, map[string]any{
+					"state":       "success",
+					"context":     "ci/lint",
+					"description": "No problems",
+				}).andThen(
+					mockResponse(t, http.StatusCreated, &github.RepoStatus{
+						ID:          github.Ptr(int64(9)),
+						State:       github.Ptr("success"),
+						Context:     github.Ptr("ci/lint"),
+						Description: github.Ptr("No problems"),
+					}),
+				),
+			),
+		),
+	)
+	_, handler := CreateCommitStatus(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+	args := map[string]any{"owner": "owner", "repo": "repo", "sha": "abc123", "state": "success", "context": "ci/lint", "description": "No problems"}
+	request := createMCPRequest(args)
+	result, _, err := handler(context.Background(), &request, args)
+	require.NoError(t, err)
+	require.False(t, result.IsError, getTextResult(t, result).Text)
+
+	var status MinimalCommitStatus
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &status))
+	assert.Equal(t, MinimalCommitStatus{ID: 9, SHA: "abc123", State: "success", Context: "ci/lint", Description: "No problems"}, status)
+}
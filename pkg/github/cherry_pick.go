@@ -0,0 +1,337 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	ghErrors "github.com/github/github-mcp-server/pkg/errors"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/github/github-mcp-server/pkg/utils"
+	"github.com/google/go-github/v79/github"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// cherryPickConflictError reports a commit that does not apply cleanly to the target branch.
+type cherryPickConflictError struct {
+	Commit string
+}
+
+func (e *cherryPickConflictError) Error() string {
+	return fmt.Sprintf("commit %s does not apply cleanly and must be cherry-picked manually", e.Commit)
+}
+
+// cherryPickCommits applies commits in order on top of branch, like git cherry-pick -x, and
+// returns the SHA of the last commit created. The REST API has no cherry-pick endpoint, so each
+// commit is merged into a temporary sibling of the branch head that has the commit's parent as
+// its parent. The merged tree is the head's tree with the commit's changes applied, and is
+// committed onto the head. The branch is moved along the way, so it should be a branch made for
+// the cherry-pick; a *cherryPickConflictError is returned when a commit does not apply cleanly.
+// Commits whose changes are already on the branch are skipped.
+func cherryPickCommits(ctx context.Context, client *github.Client, owner, repo, branch string, commits []string) (string, error) {
+	refName := "refs/heads/" + branch
+	ref, resp, err := client.Git.GetRef(ctx, owner, repo, refName)
+	if err != nil {
+		_, _ = ghErrors.NewGitHubAPIErrorToCtx(ctx, "failed to get branch reference", resp, err)
+		return "", fmt.Errorf("failed to get branch reference: %w", err)
+	}
+	_ = resp.Body.Close()
+	head, resp, err := client.Git.GetCommit(ctx, owner, repo, ref.GetObject().GetSHA())
+	if err != nil {
+		_, _ = ghErrors.NewGitHubAPIErrorToCtx(ctx, "failed to get branch head", resp, err)
+		return "", fmt.Errorf("failed to get branch head: %w", err)
+	}
+	_ = resp.Body.Close()
+
+	for _, sha := range commits {
+		head, err = cherryPickCommit(ctx, client, owner, repo, refName, head, sha)
+		if err != nil {
+			// Leave the branch at the last commit that was picked rather than at a sibling
+			_, resp, resetErr := client.Git.UpdateRef(ctx, owner, repo, refName, github.UpdateRef{SHA: head.GetSHA(), Force: github.Ptr(true)})
+			if resetErr == nil {
+				_ = resp.Body.Close()
+			}
+			return "", err
+		}
+	}
+	return head.GetSHA(), nil
+}
+
+// cherryPickCommit applies the commit sha on top of head, moving refName, and returns the new head.
+// If head already has the changes of the commit, no commit is created and head is returned. On
+// failure, the returned head is the one passed in.
+func cherryPickCommit(ctx context.Context, client *github.Client, owner, repo, refName string, head *github.Commit, sha string) (*github.Commit, error) {
+	picked, resp, err := client.Git.GetCommit(ctx, owner, repo, sha)
+	if err != nil {
+		_, _ = ghErrors.NewGitHubAPIErrorToCtx(ctx, "failed to get commit", resp, err)
+		return head, fmt.Errorf("failed to get commit %s: %w", sha, err)
+	}
+	_ = resp.Body.Close()
+	if len(picked.Parents) != 1 {
+		return head, fmt.Errorf("commit %s has %d parents; only commits with a single parent can be cherry-picked", sha, len(picked.Parents))
+	}
+
+	sibling, resp, err := client.Git.CreateCommit(ctx, owner, repo, github.Commit{
+		Message: github.Ptr("Temporary commit for cherry-picking " + sha),
+		Tree:    &github.Tree{SHA: head.GetTree().SHA},
+		Parents: []*github.Commit{{SHA: picked.Parents[0].SHA}},
+	}, nil)
+	if err != nil {
+		_, _ = ghErrors.NewGitHubAPIErrorToCtx(ctx, "failed to create commit", resp, err)
+		return head, fmt.Errorf("failed to create temporary commit: %w", err)
+	}
+	_ = resp.Body.Close()
+	_, resp, err = client.Git.UpdateRef(ctx, owner, repo, refName, github.UpdateRef{SHA: sibling.GetSHA(), Force: github.Ptr(true)})
+	if err != nil {
+		_, _ = ghErrors.NewGitHubAPIErrorToCtx(ctx, "failed to update reference", resp, err)
+		return head, fmt.Errorf("failed to update reference: %w", err)
+	}
+	_ = resp.Body.Close()
+
+	merge, resp, err := client.Repositories.Merge(ctx, owner, repo, &github.RepositoryMergeRequest{
+		Base:          github.Ptr(refName),
+		Head:          github.Ptr(sha),
+		CommitMessage: github.Ptr("Temporary merge for cherry-picking " + sha),
+	})
+	if resp != nil && resp.StatusCode == http.StatusConflict {
+		return head, &cherryPickConflictError{Commit: sha}
+	}
+	if err != nil {
+		_, _ = ghErrors.NewGitHubAPIErrorToCtx(ctx, "failed to merge commit", resp, err)
+		return head, fmt.Errorf("failed to merge commit %s: %w", sha, err)
+	}
+	_ = resp.Body.Close()
+
+	// The changes of the commit are already on head, so there is nothing to commit
+	if resp.StatusCode == http.StatusNoContent || merge.GetCommit().GetTree().GetSHA() == head.GetTree().GetSHA() {
+		_, resp, err = client.Git.UpdateRef(ctx, owner, repo, refName, github.UpdateRef{SHA: head.GetSHA(), Force: github.Ptr(true)})
+		if err != nil {
+			_, _ = ghErrors.NewGitHubAPIErrorToCtx(ctx, "failed to update reference", resp, err)
+			return head, fmt.Errorf("failed to update reference: %w", err)
+		}
+		_ = resp.Body.Close()
+		return head, nil
+	}
+
+	commit := github.Commit{
+		Message: github.Ptr(fmt.Sprintf("%s\n\n(cherry picked from commit %s)", picked.GetMessage(), sha)),
+		Tree:    &github.Tree{SHA: merge.GetCommit().GetTree().SHA},
+		Parents: []*github.Commit{{SHA: head.SHA}},
+		Author:  picked.Author,
+	}
+	created, resp, err := client.Git.CreateCommit(ctx, owner, repo, commit, signedCommitOptions(ctx, &commit))
+	if err != nil {
+		_, _ = ghErrors.NewGitHubAPIErrorToCtx(ctx, "failed to create commit", resp, err)
+		return head, fmt.Errorf("failed to create commit: %w", err)
+	}
+	_ = resp.Body.Close()
+	_, resp, err = client.Git.UpdateRef(ctx, owner, repo, refName, github.UpdateRef{SHA: created.GetSHA(), Force: github.Ptr(true)})
+	if err != nil {
+		_, _ = ghErrors.NewGitHubAPIErrorToCtx(ctx, "failed to update reference", resp, err)
+		return head, fmt.Errorf("failed to update reference: %w", err)
+	}
+	_ = resp.Body.Close()
+
+	// The created commit carries the merged tree, which the next commit is applied to
+	if created.Tree == nil {
+		created.Tree = commit.Tree
+	}
+	return created, nil
+}
+
+// maxCherryPickCommits bounds the commits of one cherry_pick_commits call
+const maxCherryPickCommits = 50
+
+// Cherry-pick states reported in CherryPickCommitResult.State
+const (
+	CherryPickStatePicked   = "picked"
+	CherryPickStateEmpty    = "empty"
+	CherryPickStateConflict = "conflict"
+	CherryPickStateFailed   = "failed"
+	CherryPickStateSkipped  = "skipped"
+)
+
+// CherryPickCommitResult reports the cherry-pick of one commit.
+type CherryPickCommitResult struct {
+	SHA   string `json:"sha"`
+	State string `json:"state"`
+	// NewSHA is the commit created on the target, unset in a dry run
+	NewSHA string `json:"new_sha,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// CherryPickCommitsResult reports the commits cherry-picked onto a branch.
+type CherryPickCommitsResult struct {
+	Branch  string `json:"branch"`
+	BaseSHA string `json:"base_sha"`
+	// HeadSHA is the branch head after the call, unchanged when nothing was picked
+	HeadSHA   string                   `json:"head_sha"`
+	Commits   []CherryPickCommitResult `json:"commits"`
+	Picked    int                      `json:"picked"`
+	Conflicts int                      `json:"conflicts"`
+	DryRun    bool                     `json:"dry_run,omitempty"`
+}
+
+// CherryPickCommits creates a tool to cherry-pick commits onto a branch with the merge-based
+// cherryPickCommit, which backport_pull_request uses too.
+func CherryPickCommits(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "cherry_pick_commits",
+		Description: t("TOOL_CHERRY_PICK_COMMITS_DESCRIPTION", "Cherry-pick commits onto a branch, in order, like git cherry-pick -x. GitHub merges the changes of each commit into the branch's tree, and the result is committed with the original author and message. A commit conflicts when GitHub cannot merge its changes cleanly. Commits whose changes are already on the branch are reported as empty. The commits are picked on a temporary branch, and the branch is moved once, after all commits are picked."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_CHERRY_PICK_COMMITS_USER_TITLE", "Cherry-pick commits"),
+			ReadOnlyHint: false,
+		},
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"owner": {
+					Type:        "string",
+					Description: "Repository owner",
+				},
+				"repo": {
+					Type:        "string",
+					Description: "Repository name",
+				},
+				"branch": {
+					Type:        "string",
+					Description: "Branch to cherry-pick the commits onto",
+				},
+				"commits": {
+					Type:        "array",
+					Description: fmt.Sprintf("SHAs of the commits to cherry-pick, oldest first (max: %d). Merge commits cannot be picked", maxCherryPickCommits),
+					Items: &jsonschema.Schema{
+						Type: "string",
+					},
+				},
+				"continue_on_conflict": {
+					Type:        "boolean",
+					Description: "Skip commits that conflict and pick the remaining ones. By default, the first conflict stops the cherry-pick and only the commits before it are picked",
+				},
+				"dry_run": {
+					Type:        "boolean",
+					Description: "Report which commits would be picked or conflict without moving the branch",
+				},
+			},
+			Required: []string{"owner", "repo", "branch", "commits"},
+		},
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		repo, err := RequiredParam[string](args, "repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		branch, err := RequiredParam[string](args, "branch")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		commits, err := OptionalStringArrayParam(args, "commits")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		if len(commits) == 0 {
+			return utils.NewToolResultError("commits must list at least one commit SHA"), nil, nil
+		}
+		if len(commits) > maxCherryPickCommits {
+			return utils.NewToolResultError(fmt.Sprintf("too many commits: %d exceeds maximum of %d per call", len(commits), maxCherryPickCommits)), nil, nil
+		}
+		continueOnConflict, err := OptionalParam[bool](args, "continue_on_conflict")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		dryRun, err := OptionalParam[bool](args, "dry_run")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		refName := "refs/heads/" + branch
+		ref, resp, err := client.Git.GetRef(ctx, owner, repo, refName)
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to get branch reference", resp, err), nil, nil
+		}
+		_ = resp.Body.Close()
+		head, resp, err := client.Git.GetCommit(ctx, owner, repo, ref.GetObject().GetSHA())
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to get branch head", resp, err), nil, nil
+		}
+		_ = resp.Body.Close()
+
+		// cherryPickCommit points the branch it picks onto at temporary commits along the way, so the
+		// commits are picked on a temporary branch and the branch itself is moved once they all are
+		result := CherryPickCommitsResult{Branch: branch, BaseSHA: head.GetSHA(), DryRun: dryRun}
+		tempRef := fmt.Sprintf("refs/heads/cherry-pick-%s-%s", branch, result.BaseSHA[:min(len(result.BaseSHA), 7)])
+		_, resp, err = client.Git.CreateRef(ctx, owner, repo, github.CreateRef{Ref: tempRef, SHA: result.BaseSHA})
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, fmt.Sprintf("failed to create temporary branch %s; delete it if it is left over from an earlier cherry-pick", strings.TrimPrefix(tempRef, "refs/heads/")), resp, err), nil, nil
+		}
+		_ = resp.Body.Close()
+		defer func() {
+			if resp, err := client.Git.DeleteRef(ctx, owner, repo, tempRef); err == nil {
+				_ = resp.Body.Close()
+			}
+		}()
+
+		stopped := false
+		for _, sha := range commits {
+			picked := CherryPickCommitResult{SHA: sha, State: CherryPickStateSkipped}
+			if stopped {
+				result.Commits = append(result.Commits, picked)
+				continue
+			}
+
+			created, err := cherryPickCommit(ctx, client, owner, repo, tempRef, head, sha)
+			var conflict *cherryPickConflictError
+			switch {
+			case errors.As(err, &conflict):
+				picked.State = CherryPickStateConflict
+				result.Conflicts++
+				stopped = !continueOnConflict
+			case err != nil:
+				picked.State = CherryPickStateFailed
+				picked.Error = err.Error()
+				stopped = true
+			case created.GetSHA() == head.GetSHA():
+				picked.State = CherryPickStateEmpty
+			default:
+				picked.State = CherryPickStatePicked
+				result.Picked++
+				head = created
+				if !dryRun {
+					picked.NewSHA = created.GetSHA()
+				}
+			}
+			result.Commits = append(result.Commits, picked)
+		}
+
+		result.HeadSHA = head.GetSHA()
+		if dryRun {
+			result.HeadSHA = result.BaseSHA
+		} else if result.HeadSHA != result.BaseSHA {
+			_, resp, err := client.Git.UpdateRef(ctx, owner, repo, refName, github.UpdateRef{
+				SHA:   result.HeadSHA,
+				Force: github.Ptr(false),
+			})
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx, fmt.Sprintf("failed to update %s; it may have moved during the cherry-pick", branch), resp, err), nil, nil
+			}
+			_ = resp.Body.Close()
+		}
+
+		return MarshalledTextResult(result), nil, nil
+	})
+
+	return tool, handler
+}
@@ -0,0 +1,186 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v79/github"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CherryPickCommits(t *testing.T) {
+	tool, _ := CherryPickCommits(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.False(t, tool.Annotations.ReadOnlyHint)
+
+	// c2 conflicts with the branch, and e1 makes changes that c1 already made
+	commits := map[string]*github.Commit{
+		"head": {SHA: github.Ptr("head"), Tree: &github.Tree{SHA: github.Ptr("head-tree")}},
+		"c1":   {SHA: github.Ptr("c1"), Parents: []*github.Commit{{SHA: github.Ptr("p1")}}, Message: github.Ptr("Fix a")},
+		"c2":   {SHA: github.Ptr("c2"), Parents: []*github.Commit{{SHA: github.Ptr("c1")}}, Message: github.Ptr("Fix b")},
+		"c3":   {SHA: github.Ptr("c3"), Parents: []*github.Commit{{SHA: github.Ptr("c2")}}, Message: github.Ptr("Drop README")},
+		"e1":   {SHA: github.Ptr("e1"), Parents: []*github.Commit{{SHA: github.Ptr("p1")}}, Message: github.Ptr("Fix a again")},
+		"m1":   {SHA: github.Ptr("m1"), Parents: []*github.Commit{{SHA: github.Ptr("c1")}, {SHA: github.Ptr("c2")}}},
+	}
+	mergedTrees := map[string]string{"c1": "c1-tree", "c3": "c3-tree", "e1": "c1-tree"}
+
+	// mockedClient serves the commits above, merges them with the trees above, and records the
+	// commits it is asked to create and the refs it creates, updates and deletes
+	type created struct {
+		commits []map[string]any
+		refs    []string
+	}
+	mockedClient := func(t *testing.T, c *created) *http.Client {
+		return mock.NewMockedHTTPClient(
+			mock.WithRequestMatch(mock.GetReposGitRefByOwnerByRepoByRef, &github.Reference{Ref: github.Ptr("refs/heads/release"), Object: &github.GitObject{SHA: github.Ptr("head")}}),
+			mock.WithRequestMatchHandler(
+				mock.GetReposGitCommitsByOwnerByRepoByCommitSha,
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					sha := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+					mockResponse(t, http.StatusOK, commits[sha])(w, r)
+				}),
+			),
+			mock.WithRequestMatchHandler(
+				mock.PostReposGitRefsByOwnerByRepo,
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					var body map[string]any
+					require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+					c.refs = append(c.refs, fmt.Sprintf("create %s at %s", body["ref"], body["sha"]))
+					mockResponse(t, http.StatusCreated, &github.Reference{})(w, r)
+				}),
+			),
+			mock.WithRequestMatchHandler(
+				mock.PostReposGitCommitsByOwnerByRepo,
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					var body map[string]any
+					require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+					c.commits = append(c.commits, body)
+					mockResponse(t, http.StatusCreated, &github.Commit{SHA: github.Ptr(fmt.Sprintf("new-commit-%d", len(c.commits)))})(w, r)
+				}),
+			),
+			mock.WithRequestMatchHandler(
+				mock.PostReposMergesByOwnerByRepo,
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					var body map[string]any
+					require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+					assert.Equal(t, "refs/heads/cherry-pick-release-head", body["base"])
+					tree, ok := mergedTrees[body["head"].(string)]
+					if !ok {
+						w.WriteHeader(http.StatusConflict)
+						_, _ = w.Write([]byte(`{"message": "Merge conflict"}`))
+						return
+					}
+					mockResponse(t, http.StatusCreated, &github.RepositoryCommit{Commit: &github.Commit{Tree: &github.Tree{SHA: github.Ptr(tree)}}})(w, r)
+				}),
+			),
+			mock.WithRequestMatchHandler(
+				mock.PatchReposGitRefsByOwnerByRepoByRef,
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					var body map[string]any
+					require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+					if strings.HasSuffix(r.URL.Path, "/heads/release") {
+						c.refs = append(c.refs, fmt.Sprintf("update refs/heads/release to %s, force %v", body["sha"], body["force"]))
+					}
+					mockResponse(t, http.StatusOK, &github.Reference{})(w, r)
+				}),
+			),
+			mock.WithRequestMatchHandler(
+				mock.DeleteReposGitRefsByOwnerByRepoByRef,
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					c.refs = append(c.refs, "delete "+strings.TrimPrefix(r.URL.Path, "/repos/owner/repo/git/"))
+					w.WriteHeader(http.StatusNoContent)
+				}),
+			),
+		)
+	}
+
+	run := func(t *testing.T, c *created, args map[string]any) CherryPickCommitsResult {
+		_, handler := CherryPickCommits(stubGetClientFn(github.NewClient(mockedClient(t, c))), translations.NullTranslationHelper)
+		args["owner"], args["repo"], args["branch"] = "owner", "repo", "release"
+		request := createMCPRequest(args)
+		result, _, err := handler(context.Background(), &request, args)
+		require.NoError(t, err)
+		require.False(t, result.IsError, getTextResult(t, result).Text)
+		var response CherryPickCommitsResult
+		require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &response))
+		return response
+	}
+	// picks returns the messages and parents of the commits created for picks rather than merges
+	picks := func(c *created) [][]any {
+		var result [][]any
+		for _, commit := range c.commits {
+			if message := commit["message"].(string); !strings.HasPrefix(message, "Temporary") {
+				result = append(result, []any{message, commit["parents"]})
+			}
+		}
+		return result
+	}
+
+	t.Run("stops at the first conflict", func(t *testing.T) {
+		var c created
+		response := run(t, &c, map[string]any{"commits": []any{"c1", "c2", "c3"}})
+
+		assert.Equal(t, []CherryPickCommitResult{
+			{SHA: "c1", State: CherryPickStatePicked, NewSHA: "new-commit-2"},
+			{SHA: "c2", State: CherryPickStateConflict},
+			{SHA: "c3", State: CherryPickStateSkipped},
+		}, response.Commits)
+		assert.Equal(t, 1, response.Picked)
+		assert.Equal(t, 1, response.Conflicts)
+		assert.Equal(t, "head", response.BaseSHA)
+		assert.Equal(t, "new-commit-2", response.HeadSHA)
+		assert.Equal(t, [][]any{{"Fix a\n\n(cherry picked from commit c1)", []any{"head"}}}, picks(&c))
+		assert.Equal(t, []string{
+			"create refs/heads/cherry-pick-release-head at head",
+			"update refs/heads/release to new-commit-2, force false",
+			"delete refs/heads/cherry-pick-release-head",
+		}, c.refs)
+	})
+
+	t.Run("continues past conflicts", func(t *testing.T) {
+		var c created
+		response := run(t, &c, map[string]any{"commits": []any{"c1", "c2", "c3"}, "continue_on_conflict": true})
+
+		assert.Equal(t, CherryPickStateConflict, response.Commits[1].State)
+		assert.Equal(t, CherryPickCommitResult{SHA: "c3", State: CherryPickStatePicked, NewSHA: "new-commit-5"}, response.Commits[2])
+		assert.Equal(t, 2, response.Picked)
+		assert.Equal(t, "new-commit-5", response.HeadSHA)
+		assert.Equal(t, []any{"Drop README\n\n(cherry picked from commit c3)", []any{"new-commit-2"}}, picks(&c)[1])
+		assert.Contains(t, c.refs, "update refs/heads/release to new-commit-5, force false")
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		var c created
+		response := run(t, &c, map[string]any{"commits": []any{"c1", "e1"}, "dry_run": true})
+
+		assert.True(t, response.DryRun)
+		assert.Equal(t, []CherryPickCommitResult{
+			{SHA: "c1", State: CherryPickStatePicked},
+			{SHA: "e1", State: CherryPickStateEmpty},
+		}, response.Commits)
+		assert.Equal(t, "head", response.HeadSHA)
+		assert.Len(t, picks(&c), 1)
+		assert.Equal(t, []string{
+			"create refs/heads/cherry-pick-release-head at head",
+			"delete refs/heads/cherry-pick-release-head",
+		}, c.refs)
+	})
+
+	t.Run("merge commit", func(t *testing.T) {
+		var c created
+		response := run(t, &c, map[string]any{"commits": []any{"m1", "c1"}})
+
+		assert.Equal(t, CherryPickStateFailed, response.Commits[0].State)
+		assert.Contains(t, response.Commits[0].Error, "commit m1 has 2 parents")
+		assert.Equal(t, CherryPickStateSkipped, response.Commits[1].State)
+		assert.Len(t, c.refs, 2)
+	})
+}
@@ -0,0 +1,115 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/github/github-mcp-server/pkg/ratelimit"
+	"github.com/google/jsonschema-go/jsonschema"
+)
+
+// Pacing profiles of the chunked push tools
+const (
+	PacingAggressive = "aggressive"
+	PacingBalanced   = "balanced"
+	PacingGentle     = "gentle"
+)
+
+const (
+	// maxChunkDelay caps the pause between two chunks, so that a push is slowed but never stalled
+	maxChunkDelay = time.Minute
+	// maxChunkDelayMs is the largest chunk_delay_ms accepted
+	maxChunkDelayMs = 60000
+	// chunkAPICalls is the number of API calls a chunk of text files takes: ref, base commit,
+	// tree, commit and ref update
+	chunkAPICalls = 5
+)
+
+// chunkPushLimiters pace the requests of every shared paced push made by this server, per token
+// or installation, so that concurrent pushes share one request rate instead of each using the
+// full quota of the token.
+var chunkPushLimiters = ratelimit.NewRegistry(nil, ratelimit.DefaultIdleTimeout)
+
+// chunkPacing is how long a chunked push waits between chunks.
+type chunkPacing struct {
+	// Delay is the minimum pause between chunks
+	Delay time.Duration
+	// Shared also paces the requests of the push with chunkPushLimiters
+	Shared bool
+	// Reserve is the share of the core rate limit below which the remaining chunks are spread
+	// over the time left until the limit resets. Zero never spreads them.
+	Reserve float64
+}
+
+// chunkPacingProfiles are the pacings selected by the pacing parameter
+var chunkPacingProfiles = map[string]chunkPacing{
+	PacingAggressive: {},
+	PacingBalanced:   {Delay: 250 * time.Millisecond, Shared: true, Reserve: 0.2},
+	PacingGentle:     {Delay: 2 * time.Second, Shared: true, Reserve: 0.5},
+}
+
+// pacingSchema describes the pacing parameter of the chunked push tools.
+func pacingSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type: "string",
+		Description: "How quickly chunks are pushed, to leave rate limit quota for other users of the token. " +
+			"'aggressive' pushes chunks back to back, 'balanced' pauses briefly between chunks and shares a request rate with other paced pushes of this server, " +
+			"'gentle' pauses longer and slows down earlier as the rate limit runs low. Every profile waits as long as GitHub asks with Retry-After (default: aggressive)",
+		Enum:    []any{PacingAggressive, PacingBalanced, PacingGentle},
+		Default: json.RawMessage(`"` + PacingAggressive + `"`),
+	}
+}
+
+// chunkDelaySchema describes the chunk_delay_ms parameter of the chunked push tools.
+func chunkDelaySchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:        "integer",
+		Description: fmt.Sprintf("Pause between chunks in milliseconds, replacing the pause of the pacing profile (max: %d)", maxChunkDelayMs),
+		Minimum:     jsonschema.Ptr(0.0),
+		Maximum:     jsonschema.Ptr(float64(maxChunkDelayMs)),
+	}
+}
+
+// chunkPacingFromArgs returns the pacing selected by the pacing and chunk_delay_ms parameters,
+// or fallback when neither is set.
+func chunkPacingFromArgs(args map[string]any, fallback chunkPacing) (chunkPacing, error) {
+	profile, err := OptionalParam[string](args, "pacing")
+	if err != nil {
+		return fallback, err
+	}
+	pacing := fallback
+	if profile != "" {
+		var ok bool
+		pacing, ok = chunkPacingProfiles[profile]
+		if !ok {
+			return fallback, fmt.Errorf("pacing must be %s, %s or %s", PacingAggressive, PacingBalanced, PacingGentle)
+		}
+	}
+
+	if _, ok := args["chunk_delay_ms"]; ok {
+		delayMs, err := OptionalIntParam(args, "chunk_delay_ms")
+		if err != nil {
+			return fallback, err
+		}
+		if delayMs < 0 || delayMs > maxChunkDelayMs {
+			return fallback, fmt.Errorf("chunk_delay_ms must be between 0 and %d", maxChunkDelayMs)
+		}
+		pacing.Delay = time.Duration(delayMs) * time.Millisecond
+	}
+	return pacing, nil
+}
+
+// delayAfter returns how long to wait after a chunk pushed as commit before pushing the next.
+func (p chunkPacing) delayAfter(commit chunkCommit, now time.Time) time.Duration {
+	delay := max(p.Delay, commit.RetryAfter)
+
+	// Below the reserve, spread the calls the remaining quota allows over the time until it resets
+	rate := commit.Rate
+	if p.Reserve > 0 && rate.Limit > 0 && float64(rate.Remaining) < p.Reserve*float64(rate.Limit) {
+		if untilReset := rate.Reset.Sub(now); untilReset > 0 {
+			delay = max(delay, untilReset*chunkAPICalls/time.Duration(max(rate.Remaining, 1)))
+		}
+	}
+	return min(delay, maxChunkDelay)
+}
@@ -0,0 +1,132 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v79/github"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_chunkPacingFromArgs(t *testing.T) {
+	aggressive := chunkPacingProfiles[PacingAggressive]
+
+	pacing, err := chunkPacingFromArgs(map[string]any{}, aggressive)
+	require.NoError(t, err)
+	assert.Equal(t, aggressive, pacing)
+
+	pacing, err = chunkPacingFromArgs(map[string]any{"pacing": PacingGentle}, aggressive)
+	require.NoError(t, err)
+	assert.Equal(t, chunkPacingProfiles[PacingGentle], pacing)
+
+	pacing, err = chunkPacingFromArgs(map[string]any{"pacing": PacingBalanced, "chunk_delay_ms": float64(1500)}, aggressive)
+	require.NoError(t, err)
+	assert.Equal(t, 1500*time.Millisecond, pacing.Delay)
+	assert.True(t, pacing.Shared)
+
+	// A resumed operation keeps its pacing unless it is overridden
+	pacing, err = chunkPacingFromArgs(map[string]any{}, chunkPacingProfiles[PacingGentle])
+	require.NoError(t, err)
+	assert.Equal(t, chunkPacingProfiles[PacingGentle], pacing)
+
+	_, err = chunkPacingFromArgs(map[string]any{"pacing": "fast"}, aggressive)
+	assert.EqualError(t, err, "pacing must be aggressive, balanced or gentle")
+	_, err = chunkPacingFromArgs(map[string]any{"chunk_delay_ms": float64(-1)}, aggressive)
+	assert.EqualError(t, err, "chunk_delay_ms must be between 0 and 60000")
+}
+
+func Test_chunkPacing_delayAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	plentiful := github.Rate{Limit: 5000, Remaining: 4000, Reset: github.Timestamp{Time: now.Add(time.Hour)}}
+	scarce := github.Rate{Limit: 5000, Remaining: 500, Reset: github.Timestamp{Time: now.Add(10 * time.Minute)}}
+
+	tests := []struct {
+		name     string
+		pacing   chunkPacing
+		commit   chunkCommit
+		expected time.Duration
+	}{
+		{
+			name:     "aggressive does not wait",
+			pacing:   chunkPacing{},
+			commit:   chunkCommit{Rate: scarce},
+			expected: 0,
+		},
+		{
+			name:     "Retry-After is honored by every profile",
+			pacing:   chunkPacing{},
+			commit:   chunkCommit{RetryAfter: 3 * time.Second},
+			expected: 3 * time.Second,
+		},
+		{
+			name:     "fixed delay",
+			pacing:   chunkPacing{Delay: 2 * time.Second, Reserve: 0.5},
+			commit:   chunkCommit{Rate: plentiful},
+			expected: 2 * time.Second,
+		},
+		{
+			name:     "scarce quota is spread until the reset",
+			pacing:   chunkPacing{Delay: 2 * time.Second, Reserve: 0.2},
+			commit:   chunkCommit{Rate: scarce},
+			expected: 10 * time.Minute * chunkAPICalls / 500,
+		},
+		{
+			name:     "delays are capped",
+			pacing:   chunkPacing{Reserve: 0.2},
+			commit:   chunkCommit{Rate: github.Rate{Limit: 5000, Remaining: 1, Reset: github.Timestamp{Time: now.Add(time.Hour)}}},
+			expected: maxChunkDelay,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.pacing.delayAfter(tc.commit, now))
+		})
+	}
+}
+
+func Test_retryAfterDelay(t *testing.T) {
+	retryAfter := 90 * time.Second
+	assert.Equal(t, retryAfter, retryAfterDelay(nil, &github.AbuseRateLimitError{RetryAfter: &retryAfter}))
+
+	resp := &github.Response{Response: &http.Response{StatusCode: http.StatusServiceUnavailable, Header: http.Header{"Retry-After": []string{"7"}}}}
+	assert.Equal(t, 7*time.Second, retryAfterDelay(resp, assert.AnError))
+	assert.Zero(t, retryAfterDelay(resp, nil))
+	assert.Zero(t, retryAfterDelay(&github.Response{Response: &http.Response{Header: http.Header{}}}, assert.AnError))
+}
+
+func Test_PushFilesChunked_Pacing(t *testing.T) {
+	client := github.NewClient(mock.NewMockedHTTPClient(mockGitDataAPI(t)...))
+	_, handler := PushFilesChunked(stubGetClientFn(client), translations.NullTranslationHelper)
+
+	args := map[string]any{
+		"owner":  "owner",
+		"repo":   "repo",
+		"branch": "main",
+		"files": []any{
+			map[string]any{"path": "a.txt", "content": "a"},
+			map[string]any{"path": "b.txt", "content": "b"},
+			map[string]any{"path": "c.txt", "content": "c"},
+		},
+		"message":        "Add files",
+		"chunk_size":     float64(1),
+		"chunk_delay_ms": float64(20),
+	}
+	request := createMCPRequest(args)
+	start := time.Now()
+	result, _, err := handler(context.Background(), &request, args)
+	require.NoError(t, err)
+	require.False(t, result.IsError, getTextResult(t, result).Text)
+
+	var response PushFilesChunkedResult
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &response))
+	assert.True(t, response.FullySuccessful)
+	assert.Equal(t, int64(40), response.PacingWaitMs)
+	assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+}
@@ -0,0 +1,801 @@
+package github
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/github/github-mcp-server/pkg/accounts"
+	ghErrors "github.com/github/github-mcp-server/pkg/errors"
+	"github.com/github/github-mcp-server/pkg/ratelimit"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/github/github-mcp-server/pkg/utils"
+	"github.com/google/go-github/v79/github"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/muesli/cache2go"
+)
+
+// Chunk states reported in ChunkResult.State
+const (
+	ChunkStatePushed  = "pushed"
+	ChunkStateFailed  = "failed"
+	ChunkStatePending = "pending"
+)
+
+const (
+	// chunkedPushOperationTTL is how long an incomplete chunked push can be resumed by operation ID
+	chunkedPushOperationTTL = 1 * time.Hour
+	chunkedPushCacheName    = "chunked-push-operations"
+)
+
+// chunkedPushOperations holds incomplete chunked pushes so that they can be resumed by operation ID,
+// keyed by chunkedPushOperationKey. Entries expire after chunkedPushOperationTTL of inactivity.
+var chunkedPushOperations = cache2go.Cache(chunkedPushCacheName)
+
+// chunkedPushOperation tracks the planned chunks of a push_files_chunked call and which of them
+// have already been committed.
+type chunkedPushOperation struct {
+	ID string
+	// Account identifies the account that started the operation, the only one that can resume it
+	Account string
+	Owner   string
+	Repo    string
+	// Ref is the fully-qualified branch or tag ref the chunks are pushed to
+	Ref     string
+	Message string
+	// MessageTemplate renders the commit message of each chunk; empty appends "[chunk N/M]"
+	MessageTemplate string
+	Chunks          [][]FileEntry
+	RenamedPaths    map[string]string
+	// Identity is the author and committer of every chunk commit
+	Identity commitIdentity
+	// Retry configures the retries of each API call made for a chunk
+	Retry ratelimit.RetryConfig
+	// AllowEmpty commits chunks that change no files instead of skipping them
+	AllowEmpty bool
+	// Pacing is how long to wait between chunks
+	Pacing chunkPacing
+	// PullRequest is set when the chunks go to a new branch, to be proposed once all are pushed
+	PullRequest *ChunkedPullRequest
+	// LFSObjects are the files stored in Git LFS, which the chunks hold the pointers of
+	LFSObjects []LFSObject
+
+	// Per-chunk state, indexed like Chunks
+	Results []ChunkResult
+	// HeadSHA is the branch head after the most recently pushed chunk
+	HeadSHA string
+
+	// budget bounds the retries of all API calls made by the current run
+	budget *ratelimit.RetryBudget
+	// notify streams each chunk result of the current run to the client
+	notify chunkNotifyFunc
+	// paced is the time the current run spent waiting between chunks
+	paced time.Duration
+
+	// mu guards running, which is set while a call resumes the operation
+	mu      sync.Mutex
+	running bool
+}
+
+func newChunkedPushOperation(ctx context.Context, owner, repo, ref, message string, chunks [][]FileEntry) *chunkedPushOperation {
+	op := &chunkedPushOperation{
+		ID:      newOperationID(),
+		Account: accounts.Key(ctx),
+		Owner:   owner,
+		Repo:    repo,
+		Ref:     ref,
+		Message: message,
+		Chunks:  chunks,
+		Results: make([]ChunkResult, len(chunks)),
+		Retry:   chunkRetryConfig,
+	}
+	for i, chunk := range chunks {
+		op.Results[i] = ChunkResult{
+			ChunkIndex:   i + 1,
+			FilesInChunk: len(chunk),
+			Files:        make([]string, 0, len(chunk)),
+			State:        ChunkStatePending,
+		}
+		for _, f := range chunk {
+			op.Results[i].Files = append(op.Results[i].Files, f.Path)
+		}
+	}
+	return op
+}
+
+// newOperationID returns a random identifier for a chunked push operation
+func newOperationID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("op-%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+// claim reserves the operation for the calling resume, which then owns it until it calls
+// release. It returns false if another call is resuming it.
+func (op *chunkedPushOperation) claim() bool {
+	op.mu.Lock()
+	defer op.mu.Unlock()
+	if op.running {
+		return false
+	}
+	op.running = true
+	return true
+}
+
+// release ends the reservation made by claim.
+func (op *chunkedPushOperation) release() {
+	op.mu.Lock()
+	defer op.mu.Unlock()
+	op.running = false
+}
+
+// complete reports whether every chunk of the operation has been pushed
+func (op *chunkedPushOperation) complete() bool {
+	for _, r := range op.Results {
+		if r.State != ChunkStatePushed {
+			return false
+		}
+	}
+	return true
+}
+
+// nextChunkIndex returns the 1-based index of the first chunk that has not been pushed, or 0 if none remain
+func (op *chunkedPushOperation) nextChunkIndex() int {
+	for _, r := range op.Results {
+		if r.State != ChunkStatePushed {
+			return r.ChunkIndex
+		}
+	}
+	return 0
+}
+
+// run pushes every chunk that has not been pushed yet, in order. Unless continueOnError is set,
+// it stops at the first failure and leaves the remaining chunks pending. All chunks share one
+// retry budget, and the run stops as soon as it is exhausted. Between chunks, it waits as long
+// as the pacing asks for, and stops with the remaining chunks pending if ctx is done meanwhile.
+// Its requests wait at low priority, behind interactive calls.
+func (op *chunkedPushOperation) run(ctx context.Context, client *github.Client, continueOnError bool) {
+	ctx, op.budget = withRetryBudget(ctx)
+	ctx = ratelimit.WithPriority(ctx, ratelimit.PriorityLow)
+	if op.Pacing.Shared {
+		ctx = ratelimit.WithLimiters(ctx, chunkPushLimiters)
+	}
+	op.paced = 0
+	if op.notify == nil {
+		op.notify = func(context.Context, ChunkResult, int) {}
+	}
+
+	total := len(op.Chunks)
+	var previous *chunkCommit
+	for i, chunkFiles := range op.Chunks {
+		if op.Results[i].State == ChunkStatePushed {
+			continue
+		}
+
+		if previous != nil {
+			if delay := op.Pacing.delayAfter(*previous, time.Now()); delay > 0 {
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(delay):
+				}
+				op.paced += delay
+			}
+		}
+
+		chunkMessage := renderChunkMessage(op.MessageTemplate, op.Message, i+1, total, chunkFiles)
+
+		commit, err := pushChunk(ctx, client, op.Owner, op.Repo, op.Ref, chunkFiles, chunkMessage, op.Identity, op.Retry, op.AllowEmpty)
+		op.Results[i].Retries = commit.Retries
+		previous = &commit
+		if err != nil {
+			op.Results[i].State = ChunkStateFailed
+			op.Results[i].Success = false
+			op.Results[i].Error = err.Error()
+			var validationErr *ValidationError
+			if errors.As(err, &validationErr) {
+				op.Results[i].ErrorCode = validationErr.Code
+			}
+			// The remaining chunks would fail the same way on an empty repository
+			op.notify(ctx, op.Results[i], total)
+			if !continueOnError || errors.Is(err, ratelimit.ErrRetryBudgetExhausted) || errors.Is(err, ErrEmptyRepository) {
+				return
+			}
+			continue
+		}
+
+		op.Results[i].State = ChunkStatePushed
+		op.Results[i].Success = true
+		op.Results[i].Error = ""
+		op.Results[i].ErrorCode = ""
+		op.Results[i].NoChanges = commit.NoChanges
+		if !commit.NoChanges {
+			op.Results[i].CommitSHA = commit.SHA
+		}
+		op.HeadSHA = commit.SHA
+		op.notify(ctx, op.Results[i], total)
+	}
+}
+
+// messagePlaceholder matches the placeholders of a message_template
+var messagePlaceholder = regexp.MustCompile(`\{[a-z_]*\}`)
+
+// messageTemplatePlaceholders are the placeholders renderChunkMessage fills in
+var messageTemplatePlaceholders = map[string]bool{
+	"{message}":    true,
+	"{index}":      true,
+	"{total}":      true,
+	"{files}":      true,
+	"{first_path}": true,
+	"{last_path}":  true,
+}
+
+// messageTemplateSchema describes the message_template parameter of the chunked push tools
+func messageTemplateSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:        "string",
+		Description: "Template of each chunk's commit message, e.g. \"chore(assets): {message} ({index}/{total})\". Placeholders: {message}, {index}, {total}, {files} (files in the chunk), {first_path}, {last_path}. By default \" [chunk {index}/{total}]\" is appended to message when there is more than one chunk",
+	}
+}
+
+// validateMessageTemplate rejects templates with placeholders renderChunkMessage does not know.
+func validateMessageTemplate(template string) error {
+	for _, placeholder := range messagePlaceholder.FindAllString(template, -1) {
+		if !messageTemplatePlaceholders[placeholder] {
+			return fmt.Errorf("message_template has unknown placeholder %s", placeholder)
+		}
+	}
+	if strings.TrimSpace(messagePlaceholder.ReplaceAllString(template, "x")) == "" {
+		return fmt.Errorf("message_template must not be empty")
+	}
+	return nil
+}
+
+// renderChunkMessage returns the commit message of the index-th of total chunks. Without a
+// template, the chunk number is appended to message when there is more than one chunk.
+func renderChunkMessage(template, message string, index, total int, files []FileEntry) string {
+	if template == "" {
+		if total > 1 {
+			return fmt.Sprintf("%s [chunk %d/%d]", message, index, total)
+		}
+		return message
+	}
+	var firstPath, lastPath string
+	if len(files) > 0 {
+		firstPath = files[0].Path
+		lastPath = files[len(files)-1].Path
+	}
+	return strings.NewReplacer(
+		"{message}", message,
+		"{index}", strconv.Itoa(index),
+		"{total}", strconv.Itoa(total),
+		"{files}", strconv.Itoa(len(files)),
+		"{first_path}", firstPath,
+		"{last_path}", lastPath,
+	).Replace(template)
+}
+
+// noChanges reports whether the operation is complete without having committed anything
+func (op *chunkedPushOperation) noChanges() bool {
+	for _, r := range op.Results {
+		if r.State != ChunkStatePushed || !r.NoChanges {
+			return false
+		}
+	}
+	return len(op.Results) > 0
+}
+
+// Result detail levels of the chunked push tools
+const (
+	ResultDetailFull      = "full"
+	ResultDetailSummary   = "summary"
+	ResultDetailPathsOnly = "paths_only"
+)
+
+// resultDetailSchema describes the result_detail parameter of the chunked push tools
+func resultDetailSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:        "string",
+		Description: "How much of the result to return. 'full' lists every chunk with its files, 'summary' returns the totals and only the failed chunks, 'paths_only' replaces the chunks with the lists of pushed and unpushed paths. Every chunk result is also streamed as a progress notification, or a log message when the call has no progress token. Defaults to summary when the call has a progress token, whose notifications already list the chunks, and to full otherwise. Use summary for thousands of files, whose full result can exceed client message limits",
+		Enum:        []any{ResultDetailFull, ResultDetailSummary, ResultDetailPathsOnly},
+	}
+}
+
+// resultDetailFromArgs reads the result_detail parameter of req, which defaults to summary
+// when req has a progress token, since its progress notifications carry every chunk result.
+func resultDetailFromArgs(req *mcp.CallToolRequest, args map[string]any) (string, error) {
+	detail, err := OptionalParam[string](args, "result_detail")
+	if err != nil {
+		return "", err
+	}
+	switch detail {
+	case "":
+		if req != nil && req.Params != nil && req.Params.GetProgressToken() != nil {
+			return ResultDetailSummary, nil
+		}
+		return ResultDetailFull, nil
+	case ResultDetailFull, ResultDetailSummary, ResultDetailPathsOnly:
+		return detail, nil
+	default:
+		return "", fmt.Errorf("result_detail must be one of full, summary or paths_only")
+	}
+}
+
+// withDetail trims the result to the given result_detail level.
+func (r PushFilesChunkedResult) withDetail(detail string) PushFilesChunkedResult {
+	if detail == ResultDetailFull {
+		return r
+	}
+	r.RenamedPathCount = len(r.RenamedPaths)
+	r.RenamedPaths = nil
+
+	chunks := r.Chunks
+	r.Chunks = nil
+	for _, chunk := range chunks {
+		switch {
+		case detail == ResultDetailPathsOnly && chunk.State == ChunkStatePushed:
+			r.PushedPaths = append(r.PushedPaths, chunk.Files...)
+		case detail == ResultDetailPathsOnly:
+			r.UnpushedPaths = append(r.UnpushedPaths, chunk.Files...)
+		case chunk.State == ChunkStateFailed:
+			r.Chunks = append(r.Chunks, chunk)
+		}
+	}
+	return r
+}
+
+// chunkNotifyFunc reports the result of one of total chunks as soon as it is known.
+type chunkNotifyFunc func(ctx context.Context, result ChunkResult, total int)
+
+// chunkNotifier streams chunk results to the client of req. When the call has a progress
+// token, each result is sent as a progress notification with the result as JSON message;
+// otherwise it is sent as a log message, which clients receive once they set a log level.
+// Failures to notify are ignored, since the final result reports every chunk as well.
+func chunkNotifier(req *mcp.CallToolRequest, logger string) chunkNotifyFunc {
+	if req == nil || req.Session == nil {
+		return func(context.Context, ChunkResult, int) {}
+	}
+	var token any
+	if req.Params != nil {
+		token = req.Params.GetProgressToken()
+	}
+	return func(ctx context.Context, result ChunkResult, total int) {
+		if token == nil {
+			_ = req.Session.Log(ctx, &mcp.LoggingMessageParams{
+				Level:  "info",
+				Logger: logger,
+				Data:   result,
+			})
+			return
+		}
+		message, err := json.Marshal(result)
+		if err != nil {
+			return
+		}
+		_ = req.Session.NotifyProgress(ctx, &mcp.ProgressNotificationParams{
+			ProgressToken: token,
+			Progress:      float64(result.ChunkIndex),
+			Total:         float64(total),
+			Message:       string(message),
+		})
+	}
+}
+
+// ChunkedPullRequest is the pull request that proposes the chunks of a push_files_chunked call.
+type ChunkedPullRequest struct {
+	Base   string `json:"base"`
+	Head   string `json:"head"`
+	Title  string `json:"title"`
+	Number int    `json:"number,omitempty"`
+	URL    string `json:"url,omitempty"`
+	// Error is set if the pull request could not be opened after all chunks were pushed
+	Error string `json:"error,omitempty"`
+}
+
+// openPullRequest opens the operation's pull request once every chunk has been pushed.
+// A failure is recorded on the pull request rather than returned, since the pushed
+// chunks are still reported to the caller.
+func (op *chunkedPushOperation) openPullRequest(ctx context.Context, client *github.Client) {
+	pr := op.PullRequest
+	if pr == nil || pr.Number != 0 || !op.complete() {
+		return
+	}
+	if op.noChanges() {
+		pr.Error = fmt.Sprintf("no pull request was opened since the files are already on %s", pr.Base)
+		return
+	}
+
+	created, resp, err := client.PullRequests.Create(ctx, op.Owner, op.Repo, &github.NewPullRequest{
+		Title: github.Ptr(pr.Title),
+		Head:  github.Ptr(pr.Head),
+		Base:  github.Ptr(pr.Base),
+		Body:  github.Ptr(op.pullRequestBody()),
+	})
+	if err != nil {
+		_, _ = ghErrors.NewGitHubAPIErrorToCtx(ctx, "failed to create pull request", resp, err)
+		pr.Error = fmt.Sprintf("failed to create pull request: %v. The chunks are on branch %s; open the pull request with the create_pull_request tool", err, pr.Head)
+		return
+	}
+	_ = resp.Body.Close()
+
+	pr.Number = created.GetNumber()
+	pr.URL = created.GetHTMLURL()
+	pr.Error = ""
+}
+
+// pullRequestBody lists every chunk commit and its file count.
+func (op *chunkedPushOperation) pullRequestBody() string {
+	total := 0
+	for _, chunk := range op.Chunks {
+		total += len(chunk)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Pushes %d files in %d commits with push_files_chunked.\n\n", total, len(op.Chunks))
+	b.WriteString("| Chunk | Files | Commit |\n|---|---|---|\n")
+	for i, r := range op.Results {
+		commit := r.CommitSHA
+		if r.NoChanges {
+			commit = "no changes"
+		}
+		fmt.Fprintf(&b, "| %d/%d | %d | %s |\n", r.ChunkIndex, len(op.Chunks), len(op.Chunks[i]), commit)
+	}
+	if len(op.RenamedPaths) > 0 {
+		fmt.Fprintf(&b, "\n%d paths were renamed by path sanitization.\n", len(op.RenamedPaths))
+	}
+	return b.String()
+}
+
+// result summarizes the operation and records it for resumption if it is incomplete
+func (op *chunkedPushOperation) result() PushFilesChunkedResult {
+	result := PushFilesChunkedResult{
+		OperationID:    op.ID,
+		TotalChunks:    len(op.Chunks),
+		Chunks:         make([]ChunkResult, len(op.Results)),
+		FinalCommitSHA: op.HeadSHA,
+		RenamedPaths:   op.RenamedPaths,
+		LFSObjects:     op.LFSObjects,
+	}
+	if op.PullRequest != nil {
+		pr := *op.PullRequest
+		result.PullRequest = &pr
+	}
+	copy(result.Chunks, op.Results)
+	if op.budget != nil {
+		usage := op.budget.Usage()
+		result.RetryBudget = &usage
+	}
+	result.PacingWaitMs = op.paced.Milliseconds()
+
+	for i, r := range op.Results {
+		result.TotalFiles += len(op.Chunks[i])
+		switch r.State {
+		case ChunkStatePushed:
+			result.SuccessfulChunks++
+		case ChunkStateFailed:
+			result.FailedChunks++
+		}
+	}
+
+	result.FullySuccessful = op.complete()
+	result.NoChanges = op.noChanges()
+	if !result.FullySuccessful {
+		result.Resumable = true
+		result.NextChunkIndex = op.nextChunkIndex()
+		chunkedPushOperations.Add(chunkedPushOperationKey(op.Account, op.ID), chunkedPushOperationTTL, op)
+	} else {
+		_, _ = chunkedPushOperations.Delete(chunkedPushOperationKey(op.Account, op.ID))
+	}
+
+	return result
+}
+
+// chunkedPushOperationKey returns the key of the operation called id started by account, so
+// that no account resumes the operations of another.
+func chunkedPushOperationKey(account, id string) string {
+	return account + "\x00" + id
+}
+
+// loadChunkedPushOperation returns a previously recorded incomplete operation of the account of ctx
+func loadChunkedPushOperation(ctx context.Context, id string) (*chunkedPushOperation, bool) {
+	item, err := chunkedPushOperations.Value(chunkedPushOperationKey(accounts.Key(ctx), id))
+	if err != nil {
+		return nil, false
+	}
+	op, ok := item.Data().(*chunkedPushOperation)
+	return op, ok
+}
+
+// ResumePushChunked creates a tool to continue a chunked push that did not complete.
+func ResumePushChunked(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "resume_push_chunked",
+		Description: t("TOOL_RESUME_PUSH_CHUNKED_DESCRIPTION", "Resume a push_files_chunked operation that did not complete, continuing from the first chunk that was not pushed. If the operation was started with create_pull_request, the pull request is opened once the last chunk is pushed. Pass the operation_id returned by push_files_chunked, or resend the original files and chunk_size together with start_index and expected_head_sha if the operation is no longer known to the server."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_RESUME_PUSH_CHUNKED_USER_TITLE", "Resume chunked push"),
+			ReadOnlyHint: false,
+		},
+		InputSchema: withCommitIdentity(&jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"operation_id": {
+					Type:        "string",
+					Description: "Operation ID returned by push_files_chunked. When provided, all other parameters except continue_on_error, max_retries, pacing and chunk_delay_ms are ignored",
+				},
+				"owner": {
+					Type:        "string",
+					Description: "Repository owner (explicit mode)",
+				},
+				"repo": {
+					Type:        "string",
+					Description: "Repository name (explicit mode)",
+				},
+				"branch": {
+					Type:        "string",
+					Description: "Branch to push to (explicit mode)",
+				},
+				"ref": {
+					Type:        "string",
+					Description: "Fully-qualified ref to push to instead of branch, as passed to the original call (explicit mode)",
+				},
+				"files": {
+					Type:        "array",
+					Description: "The same files array passed to the original push_files_chunked call (explicit mode)",
+					Items:       lfsFileObjectSchema(),
+				},
+				"message": {
+					Type:        "string",
+					Description: "The same base commit message as the original call (explicit mode)",
+				},
+				"message_template": {
+					Type:        "string",
+					Description: "The same message_template as the original call, if any (explicit mode)",
+				},
+				"chunk_size": {
+					Type:        "integer",
+					Description: "The same chunk_size as the original call (explicit mode)",
+					Default:     json.RawMessage(fmt.Sprintf("%d", CurrentPushLimits().DefaultChunkSize)),
+				},
+				"group_by": {
+					Type:        "string",
+					Description: "The same group_by as the original call, if any (explicit mode)",
+					Enum:        []any{GroupByNone, GroupByDirectory, GroupByExtension},
+				},
+				"normalize_line_endings": {
+					Type:        "string",
+					Description: "The same normalize_line_endings as the original call, if any (explicit mode)",
+					Enum:        []any{LineEndingsPreserve, LineEndingsLF, LineEndingsCRLF},
+				},
+				"lfs_threshold_bytes": {
+					Type:        "integer",
+					Description: "The same lfs_threshold_bytes as the original call, if any (explicit mode)",
+					Minimum:     jsonschema.Ptr(0.0),
+				},
+				"sanitize_paths": {
+					Type:        "string",
+					Description: "The same sanitize_paths as the original call, if any (explicit mode)",
+					Enum:        []any{"none", "normalize", "strict"},
+				},
+				"respect_gitignore": {
+					Type:        "boolean",
+					Description: "The same respect_gitignore as the original call, if any (explicit mode)",
+				},
+				"ignore_patterns": {
+					Type:        "array",
+					Description: "The same ignore_patterns as the original call, if any (explicit mode)",
+					Items:       &jsonschema.Schema{Type: "string"},
+				},
+				"allow_secrets": {
+					Type:        "boolean",
+					Description: "The same allow_secrets as the original call. The resent files are scanned for credentials unless it is set (explicit mode)",
+				},
+				"allow_empty": {
+					Type:        "boolean",
+					Description: "The same allow_empty as the original call, if any (explicit mode)",
+				},
+				"start_index": {
+					Type:        "integer",
+					Description: "1-based index of the first chunk to push (explicit mode)",
+					Minimum:     jsonschema.Ptr(1.0),
+				},
+				"expected_head_sha": {
+					Type:        "string",
+					Description: "SHA the branch is expected to point to before resuming. The resume is refused if the branch has moved",
+				},
+				"continue_on_error": {
+					Type:        "boolean",
+					Description: "Continue processing remaining chunks if one fails (default: false)",
+					Default:     json.RawMessage("false"),
+				},
+				"max_retries":    maxRetriesSchema(),
+				"result_detail":  resultDetailSchema(),
+				"pacing":         pacingSchema(),
+				"chunk_delay_ms": chunkDelaySchema(),
+			},
+		}),
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, req *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		operationID, err := OptionalParam[string](args, "operation_id")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		expectedHeadSHA, err := OptionalParam[string](args, "expected_head_sha")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		continueOnError, err := OptionalParam[bool](args, "continue_on_error")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		retry, err := chunkRetryConfigFromArgs(args)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		detail, err := resultDetailFromArgs(req, args)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		var op *chunkedPushOperation
+		if operationID != "" {
+			var ok bool
+			op, ok = loadChunkedPushOperation(ctx, operationID)
+			if !ok {
+				return utils.NewToolResultError(fmt.Sprintf("unknown or expired operation_id %q; resend the files with start_index and expected_head_sha to resume explicitly", operationID)), nil, nil
+			}
+			// The operation is shared by every call naming it, so only one can run it at a time
+			if !op.claim() {
+				return utils.NewToolResultError(fmt.Sprintf("operation %s is being resumed by another call; wait for it to finish, then resume again if it is still incomplete", operationID)), nil, nil
+			}
+			defer op.release()
+			if expectedHeadSHA == "" {
+				expectedHeadSHA = op.HeadSHA
+			}
+		} else {
+			op, err = chunkedPushOperationFromArgs(ctx, client, args)
+			if err != nil {
+				return toolErrorResult(err), nil, nil
+			}
+			op.Identity, err = commitIdentityFromArgs(ctx, args)
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+		}
+
+		pacing, err := chunkPacingFromArgs(args, op.Pacing)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		// Resent files stored in Git LFS are uploaded again, which is skipped for content LFS has
+		if operationID == "" {
+			if err := op.uploadLFSFiles(ctx, client); err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+		}
+
+		// Refuse to resume on top of a branch that moved since the operation was interrupted
+		if expectedHeadSHA != "" {
+			ref, resp, err := client.Git.GetRef(ctx, op.Owner, op.Repo, op.Ref)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to get branch reference",
+					resp,
+					err,
+				), nil, nil
+			}
+			_ = resp.Body.Close()
+
+			if headSHA := ref.GetObject().GetSHA(); headSHA != expectedHeadSHA {
+				return utils.NewToolResultError(fmt.Sprintf(
+					"%s is at %s but expected %s; it has moved since the operation was interrupted. Inspect the branch before resuming",
+					op.Ref, headSHA, expectedHeadSHA,
+				)), nil, nil
+			}
+		}
+
+		op.Retry = retry
+		op.Pacing = pacing
+		op.notify = chunkNotifier(req, "resume_push_chunked")
+		op.run(ctx, client, continueOnError)
+		op.openPullRequest(ctx, client)
+
+		r, err := json.Marshal(op.result().withDetail(detail))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to marshal response: %w", err)
+		}
+
+		return utils.NewToolResultText(string(r)), nil, nil
+	})
+
+	return tool, handler
+}
+
+// uploadLFSFiles uploads the files with LFS set in the chunks still to be pushed to Git LFS,
+// replacing their content with pointers, and records them in LFSObjects.
+func (op *chunkedPushOperation) uploadLFSFiles(ctx context.Context, client *github.Client) error {
+	for i, chunk := range op.Chunks {
+		if op.Results[i].State == ChunkStatePushed {
+			continue
+		}
+		objects, err := uploadLFSFiles(ctx, client, op.Owner, op.Repo, chunk)
+		if err != nil {
+			return err
+		}
+		op.LFSObjects = append(op.LFSObjects, objects...)
+	}
+	return nil
+}
+
+// chunkedPushOperationFromArgs rebuilds an operation from resent files, prepared like those of
+// push_files_chunked, marking every chunk before start_index as already pushed.
+func chunkedPushOperationFromArgs(ctx context.Context, client *github.Client, args map[string]any) (*chunkedPushOperation, error) {
+	owner, err := RequiredParam[string](args, "owner")
+	if err != nil {
+		return nil, fmt.Errorf("%w (or provide operation_id)", err)
+	}
+	repo, err := RequiredParam[string](args, "repo")
+	if err != nil {
+		return nil, err
+	}
+	ref, err := targetRefFromArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	message, err := RequiredParam[string](args, "message")
+	if err != nil {
+		return nil, err
+	}
+	messageTemplate, err := OptionalParam[string](args, "message_template")
+	if err != nil {
+		return nil, err
+	}
+	if messageTemplate != "" {
+		if err := validateMessageTemplate(messageTemplate); err != nil {
+			return nil, err
+		}
+	}
+	startIndex, err := RequiredInt(args, "start_index")
+	if err != nil {
+		return nil, err
+	}
+	allowEmpty, err := OptionalParam[bool](args, "allow_empty")
+	if err != nil {
+		return nil, err
+	}
+
+	prepared, err := chunkedPushFilesFromArgs(ctx, client, owner, repo, ref, args)
+	if err != nil {
+		return nil, err
+	}
+	if startIndex > len(prepared.Chunks) {
+		return nil, fmt.Errorf("start_index %d is out of range: the files produce %d chunks", startIndex, len(prepared.Chunks))
+	}
+
+	op := newChunkedPushOperation(ctx, owner, repo, ref, message, prepared.Chunks)
+	op.RenamedPaths = prepared.RenamedPaths
+	op.MessageTemplate = messageTemplate
+	op.AllowEmpty = allowEmpty
+	for i := 0; i < startIndex-1; i++ {
+		op.Results[i].State = ChunkStatePushed
+		op.Results[i].Success = true
+	}
+	return op, nil
+}
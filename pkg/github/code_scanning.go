@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 
 	ghErrors "github.com/github/github-mcp-server/pkg/errors"
 	"github.com/github/github-mcp-server/pkg/translations"
@@ -15,10 +16,84 @@ import (
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
+// AlertCodeSnippet is the code a code scanning alert points at, with context lines around it.
+// Lines of the alert are marked with '>' in Code.
+type AlertCodeSnippet struct {
+	Path      string `json:"path"`
+	Ref       string `json:"ref"`
+	StartLine int    `json:"start_line"`
+	EndLine   int    `json:"end_line"`
+	Code      string `json:"code"`
+}
+
+// CodeScanningAlertWithSnippet is the output type of get_code_scanning_alert.
+type CodeScanningAlertWithSnippet struct {
+	*github.Alert
+	Snippet      *AlertCodeSnippet `json:"snippet,omitempty"`
+	SnippetError string            `json:"snippet_error,omitempty"`
+}
+
+// formatAlertSnippet returns lines from to to of content, numbered and with the alert lines
+// start to end marked.
+func formatAlertSnippet(content string, from, to, start, end int) string {
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	to = min(to, len(lines))
+	width := len(fmt.Sprint(to))
+	var b strings.Builder
+	for n := from; n <= to; n++ {
+		marker := " "
+		if n >= start && n <= end {
+			marker = ">"
+		}
+		fmt.Fprintf(&b, "%s%*d | %s\n", marker, width, n, lines[n-1])
+	}
+	return b.String()
+}
+
+// getAlertSnippet fetches the code at the location of the most recent instance of alert, at the
+// commit it was found in, with contextLines lines around it.
+func getAlertSnippet(ctx context.Context, client *github.Client, owner, repo string, alert *github.Alert, contextLines int) (*AlertCodeSnippet, error) {
+	instance := alert.GetMostRecentInstance()
+	location := instance.GetLocation()
+	if location.GetPath() == "" || location.GetStartLine() == 0 {
+		return nil, nil
+	}
+	ref := instance.GetCommitSHA()
+	if ref == "" {
+		ref = instance.GetRef()
+	}
+
+	file, _, resp, err := client.Repositories.GetContents(ctx, owner, repo, location.GetPath(), &github.RepositoryContentGetOptions{Ref: ref})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s at %s: %w", location.GetPath(), ref, err)
+	}
+	_ = resp.Body.Close()
+	if file == nil {
+		return nil, fmt.Errorf("%s is not a file", location.GetPath())
+	}
+	content, err := file.GetContent()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", location.GetPath(), err)
+	}
+
+	start := location.GetStartLine()
+	end := max(location.GetEndLine(), start)
+	from := max(start-contextLines, 1)
+	to := end + contextLines
+	code := formatAlertSnippet(content, from, to, start, end)
+	return &AlertCodeSnippet{
+		Path:      location.GetPath(),
+		Ref:       ref,
+		StartLine: from,
+		EndLine:   from + strings.Count(code, "\n") - 1,
+		Code:      code,
+	}, nil
+}
+
 func GetCodeScanningAlert(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
 	return mcp.Tool{
 			Name:        "get_code_scanning_alert",
-			Description: t("TOOL_GET_CODE_SCANNING_ALERT_DESCRIPTION", "Get details of a specific code scanning alert in a GitHub repository."),
+			Description: t("TOOL_GET_CODE_SCANNING_ALERT_DESCRIPTION", "Get details of a specific code scanning alert in a GitHub repository, with the code it points at."),
 			Annotations: &mcp.ToolAnnotations{
 				Title:        t("TOOL_GET_CODE_SCANNING_ALERT_USER_TITLE", "Get code scanning alert"),
 				ReadOnlyHint: true,
@@ -38,6 +113,17 @@ func GetCodeScanningAlert(getClient GetClientFn, t translations.TranslationHelpe
 						Type:        "number",
 						Description: "The number of the alert.",
 					},
+					"includeSnippet": {
+						Type:        "boolean",
+						Description: "Include the code at the location of the alert, from the commit it was found in. Defaults to true.",
+						Default:     json.RawMessage(`true`),
+					},
+					"contextLines": {
+						Type:        "number",
+						Description: "Number of lines of context around the code of the alert. Defaults to 3.",
+						Minimum:     jsonschema.Ptr(0.0),
+						Maximum:     jsonschema.Ptr(50.0),
+					},
 				},
 				Required: []string{"owner", "repo", "alertNumber"},
 			},
@@ -55,6 +141,18 @@ func GetCodeScanningAlert(getClient GetClientFn, t translations.TranslationHelpe
 			if err != nil {
 				return utils.NewToolResultError(err.Error()), nil, nil
 			}
+			includeSnippet, err := OptionalBoolParamWithDefault(args, "includeSnippet", true)
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			// Read directly, as 0 context lines is a valid choice
+			contextLines := 3
+			if _, ok := args["contextLines"]; ok {
+				contextLines, err = OptionalIntParam(args, "contextLines")
+				if err != nil {
+					return utils.NewToolResultError(err.Error()), nil, nil
+				}
+			}
 
 			client, err := getClient(ctx)
 			if err != nil {
@@ -79,7 +177,16 @@ func GetCodeScanningAlert(getClient GetClientFn, t translations.TranslationHelpe
 				return utils.NewToolResultError(fmt.Sprintf("failed to get alert: %s", string(body))), nil, nil
 			}
 
-			r, err := json.Marshal(alert)
+			result := CodeScanningAlertWithSnippet{Alert: alert}
+			if includeSnippet {
+				// The alert is still useful without its code, so a failure is only reported
+				result.Snippet, err = getAlertSnippet(ctx, client, owner, repo, alert, contextLines)
+				if err != nil {
+					result.SnippetError = err.Error()
+				}
+			}
+
+			r, err := json.Marshal(result)
 			if err != nil {
 				return utils.NewToolResultErrorFromErr("failed to marshal alert", err), nil, nil
 			}
@@ -186,3 +293,107 @@ func ListCodeScanningAlerts(getClient GetClientFn, t translations.TranslationHel
 			return utils.NewToolResultText(string(r)), nil, nil
 		}
 }
+
+// DismissCodeScanningAlert creates a tool to dismiss a code scanning alert, or reopen a dismissed one.
+func DismissCodeScanningAlert(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	return mcp.Tool{
+			Name:        "dismiss_code_scanning_alert",
+			Description: t("TOOL_DISMISS_CODE_SCANNING_ALERT_DESCRIPTION", "Dismiss a code scanning alert with a reason, or reopen a dismissed alert."),
+			Annotations: &mcp.ToolAnnotations{
+				Title:        t("TOOL_DISMISS_CODE_SCANNING_ALERT_USER_TITLE", "Dismiss code scanning alert"),
+				ReadOnlyHint: false,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"owner": {
+						Type:        "string",
+						Description: "The owner of the repository.",
+					},
+					"repo": {
+						Type:        "string",
+						Description: "The name of the repository.",
+					},
+					"alertNumber": {
+						Type:        "number",
+						Description: "The number of the alert.",
+					},
+					"reason": {
+						Type:        "string",
+						Description: "Why the alert is dismissed. Required unless reopen is true.",
+						Enum:        []any{"false positive", "won't fix", "used in tests"},
+					},
+					"comment": {
+						Type:        "string",
+						Description: "Comment explaining the dismissal.",
+					},
+					"reopen": {
+						Type:        "boolean",
+						Description: "Reopen the alert instead of dismissing it.",
+					},
+				},
+				Required: []string{"owner", "repo", "alertNumber"},
+			},
+		},
+		func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+			owner, err := RequiredParam[string](args, "owner")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			repo, err := RequiredParam[string](args, "repo")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			alertNumber, err := RequiredInt(args, "alertNumber")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			reason, err := OptionalParam[string](args, "reason")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			comment, err := OptionalParam[string](args, "comment")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			reopen, err := OptionalParam[bool](args, "reopen")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+
+			state := &github.CodeScanningAlertState{State: "open"}
+			switch {
+			case reopen && (reason != "" || comment != ""):
+				return utils.NewToolResultError("reason and comment cannot be set when reopening an alert"), nil, nil
+			case !reopen && reason == "":
+				return utils.NewToolResultError("missing required parameter: reason"), nil, nil
+			case !reopen:
+				state = &github.CodeScanningAlertState{State: "dismissed", DismissedReason: github.Ptr(reason)}
+				if comment != "" {
+					state.DismissedComment = github.Ptr(comment)
+				}
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return utils.NewToolResultErrorFromErr("failed to get GitHub client", err), nil, nil
+			}
+
+			alert, resp, err := client.CodeScanning.UpdateAlert(ctx, owner, repo, int64(alertNumber), state)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to update alert",
+					resp,
+					err,
+				), nil, nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			r, err := json.Marshal(alert)
+			if err != nil {
+				return utils.NewToolResultErrorFromErr("failed to marshal alert", err), nil, nil
+			}
+
+			return utils.NewToolResultText(string(r)), nil, nil
+		}
+}
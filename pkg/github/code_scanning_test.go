@@ -3,6 +3,7 @@ package github
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"testing"
 
@@ -256,3 +257,137 @@ func Test_ListCodeScanningAlerts(t *testing.T) {
 		})
 	}
 }
+
+func Test_GetCodeScanningAlert_Snippet(t *testing.T) {
+	alert := &github.Alert{
+		Number: github.Ptr(42),
+		MostRecentInstance: &github.MostRecentInstance{
+			Ref:       github.Ptr("refs/heads/main"),
+			CommitSHA: github.Ptr("abc123"),
+			Location:  &github.Location{Path: github.Ptr("app/db.go"), StartLine: github.Ptr(4), EndLine: github.Ptr(5)},
+		},
+	}
+	content := "package app\n\nfunc query(db *sql.DB, name string) {\n\tq := \"SELECT * FROM users WHERE name = '\" + name + \"'\"\n\tdb.Query(q)\n}\n"
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatch(mock.GetReposCodeScanningAlertsByOwnerByRepoByAlertNumber, alert),
+		mock.WithRequestMatchHandler(
+			mock.GetReposContentsByOwnerByRepoByPath,
+			expectQueryParams(t, map[string]string{"ref": "abc123"}).andThen(
+				mockResponse(t, http.StatusOK, &github.RepositoryContent{
+					Type:     github.Ptr("file"),
+					Path:     github.Ptr("app/db.go"),
+					Encoding: github.Ptr(""),
+					Content:  github.Ptr(content),
+				}),
+			),
+		),
+	)
+	_, handler := GetCodeScanningAlert(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+	args := map[string]any{"owner": "owner", "repo": "repo", "alertNumber": float64(42), "contextLines": float64(1)}
+	request := createMCPRequest(args)
+	result, _, err := handler(context.Background(), &request, args)
+	require.NoError(t, err)
+	require.False(t, result.IsError, getTextResult(t, result).Text)
+
+	var returned CodeScanningAlertWithSnippet
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &returned))
+	assert.Equal(t, 42, returned.GetNumber())
+	require.NotNil(t, returned.Snippet)
+	assert.Equal(t, AlertCodeSnippet{
+		Path:      "app/db.go",
+		Ref:       "abc123",
+		StartLine: 3,
+		EndLine:   6,
+		Code:      " 3 | func query(db *sql.DB, name string) {\n>4 | \tq := \"SELECT * FROM users WHERE name = '\" + name + \"'\"\n>5 | \tdb.Query(q)\n 6 | }\n",
+	}, *returned.Snippet)
+
+	t.Run("snippet failure keeps the alert", func(t *testing.T) {
+		mockedClient := mock.NewMockedHTTPClient(
+			mock.WithRequestMatch(mock.GetReposCodeScanningAlertsByOwnerByRepoByAlertNumber, alert),
+			mock.WithRequestMatchHandler(
+				mock.GetReposContentsByOwnerByRepoByPath,
+				mockResponse(t, http.StatusNotFound, `{"message": "Not Found"}`),
+			),
+		)
+		_, handler := GetCodeScanningAlert(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+		args := map[string]any{"owner": "owner", "repo": "repo", "alertNumber": float64(42)}
+		request := createMCPRequest(args)
+		result, _, err := handler(context.Background(), &request, args)
+		require.NoError(t, err)
+		require.False(t, result.IsError)
+
+		var returned CodeScanningAlertWithSnippet
+		require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &returned))
+		assert.Nil(t, returned.Snippet)
+		assert.Contains(t, returned.SnippetError, "failed to get app/db.go at abc123")
+	})
+}
+
+func Test_DismissCodeScanningAlert(t *testing.T) {
+	tool, _ := DismissCodeScanningAlert(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	tests := []struct {
+		name           string
+		requestArgs    map[string]any
+		expectedBody   map[string]any
+		expectedErrMsg string
+	}{
+		{
+			name:         "dismiss with comment",
+			requestArgs:  map[string]any{"reason": "used in tests", "comment": "Fixture data"},
+			expectedBody: map[string]any{"state": "dismissed", "dismissed_reason": "used in tests", "dismissed_comment": "Fixture data"},
+		},
+		{
+			name:         "reopen",
+			requestArgs:  map[string]any{"reopen": true},
+			expectedBody: map[string]any{"state": "open"},
+		},
+		{
+			name:           "dismiss without reason",
+			requestArgs:    map[string]any{},
+			expectedErrMsg: "missing required parameter: reason",
+		},
+		{
+			name:           "reopen with reason",
+			requestArgs:    map[string]any{"reopen": true, "reason": "won't fix"},
+			expectedErrMsg: "reason and comment cannot be set when reopening an alert",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockedClient := mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.PatchReposCodeScanningAlertsByOwnerByRepoByAlertNumber,
+					expectRequestBody(t, tc.expectedBody).andThen(
+						mockResponse(t, http.StatusOK, &github.Alert{Number: github.Ptr(42), State: github.Ptr(fmt.Sprint(tc.expectedBody["state"]))}),
+					),
+				),
+			)
+			_, handler := DismissCodeScanningAlert(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+			args := map[string]any{"owner": "owner", "repo": "repo", "alertNumber": float64(42)}
+			for k, v := range tc.requestArgs {
+				args[k] = v
+			}
+			request := createMCPRequest(args)
+			result, _, err := handler(context.Background(), &request, args)
+			require.NoError(t, err)
+
+			if tc.expectedErrMsg != "" {
+				require.True(t, result.IsError)
+				assert.Contains(t, getErrorResult(t, result).Text, tc.expectedErrMsg)
+				return
+			}
+			require.False(t, result.IsError, getTextResult(t, result).Text)
+
+			var returned github.Alert
+			require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &returned))
+			assert.Equal(t, tc.expectedBody["state"], returned.GetState())
+		})
+	}
+}
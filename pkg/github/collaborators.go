@@ -0,0 +1,329 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	ghErrors "github.com/github/github-mcp-server/pkg/errors"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/github/github-mcp-server/pkg/utils"
+	"github.com/google/go-github/v79/github"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// AddedCollaborator is the output type of add_collaborator.
+type AddedCollaborator struct {
+	Repository string `json:"repository"`
+	Username   string `json:"username"`
+	Permission string `json:"permission"`
+	// Invited is set when the user was sent an invitation, which they must accept to get access.
+	Invited      bool  `json:"invited"`
+	InvitationID int64 `json:"invitation_id,omitempty"`
+}
+
+// MinimalRepositoryInvitation is the output type for repository invitations.
+type MinimalRepositoryInvitation struct {
+	ID          int64  `json:"id"`
+	Invitee     string `json:"invitee"`
+	Inviter     string `json:"inviter,omitempty"`
+	Permissions string `json:"permissions"`
+	Expired     bool   `json:"expired"`
+	CreatedAt   string `json:"created_at,omitempty"`
+	HTMLURL     string `json:"html_url,omitempty"`
+}
+
+// collaboratorErrorResult returns the error of a collaborator API call. Managing collaborators
+// requires admin access to the repository, and a 403 is reported as an ADMIN_REQUIRED
+// validation error so that clients can tell it from other failures.
+func collaboratorErrorResult(ctx context.Context, message, owner, repo string, resp *github.Response, err error) *mcp.CallToolResult {
+	if resp == nil || resp.StatusCode != http.StatusForbidden {
+		return ghErrors.NewGitHubAPIErrorResponse(ctx, message, resp, err)
+	}
+	_, _ = ghErrors.NewGitHubAPIErrorToCtx(ctx, message, resp, err)
+	return validationErrorResult(&ValidationError{
+		Code:       "ADMIN_REQUIRED",
+		Message:    fmt.Sprintf("%s: the token does not have admin access to %s/%s", message, owner, repo),
+		Suggestion: "Use a token of a repository administrator, with the repo scope or the Administration repository permission",
+		Details: map[string]interface{}{
+			"repository": owner + "/" + repo,
+			"status":     resp.StatusCode,
+		},
+		Cause: err,
+	})
+}
+
+// AddCollaborator creates a tool to add a collaborator to a repository.
+func AddCollaborator(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "add_collaborator",
+		Description: t("TOOL_ADD_COLLABORATOR_DESCRIPTION", "Add a collaborator to a repository, or change the permission of an existing collaborator. A new collaborator is sent an invitation and has access once they accept it. Requires admin access to the repository."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_ADD_COLLABORATOR_USER_TITLE", "Add collaborator"),
+			ReadOnlyHint: false,
+		},
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"owner": {
+					Type:        "string",
+					Description: "Repository owner",
+				},
+				"repo": {
+					Type:        "string",
+					Description: "Repository name",
+				},
+				"username": {
+					Type:        "string",
+					Description: "Login of the user",
+				},
+				"permission": {
+					Type:        "string",
+					Description: "Permission to grant. Defaults to push. Only push applies to repositories owned by a user",
+					Enum:        []any{"pull", "triage", "push", "maintain", "admin"},
+				},
+			},
+			Required: []string{"owner", "repo", "username"},
+		},
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		repo, err := RequiredParam[string](args, "repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		username, err := RequiredParam[string](args, "username")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		permission, err := OptionalParam[string](args, "permission")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		if permission == "" {
+			permission = "push"
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		invitation, resp, err := client.Repositories.AddCollaborator(ctx, owner, repo, username, &github.RepositoryAddCollaboratorOptions{Permission: permission})
+		if err != nil {
+			return collaboratorErrorResult(ctx, "failed to add collaborator", owner, repo, resp, err), nil, nil
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		// 201 means an invitation was sent, 204 that an existing collaborator's permission changed
+		return MarshalledTextResult(AddedCollaborator{
+			Repository:   owner + "/" + repo,
+			Username:     username,
+			Permission:   permission,
+			Invited:      resp.StatusCode == http.StatusCreated,
+			InvitationID: invitation.GetID(),
+		}), nil, nil
+	})
+
+	return tool, handler
+}
+
+// RemoveCollaborator creates a tool to remove a collaborator from a repository.
+func RemoveCollaborator(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "remove_collaborator",
+		Description: t("TOOL_REMOVE_COLLABORATOR_DESCRIPTION", "Remove a collaborator from a repository. Their forks of a private repository are deleted. Requires admin access to the repository; use cancel_invitation for invitations that were not accepted."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:           t("TOOL_REMOVE_COLLABORATOR_USER_TITLE", "Remove collaborator"),
+			ReadOnlyHint:    false,
+			DestructiveHint: jsonschema.Ptr(true),
+		},
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"owner": {
+					Type:        "string",
+					Description: "Repository owner",
+				},
+				"repo": {
+					Type:        "string",
+					Description: "Repository name",
+				},
+				"username": {
+					Type:        "string",
+					Description: "Login of the collaborator",
+				},
+			},
+			Required: []string{"owner", "repo", "username"},
+		},
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		repo, err := RequiredParam[string](args, "repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		username, err := RequiredParam[string](args, "username")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		resp, err := client.Repositories.RemoveCollaborator(ctx, owner, repo, username)
+		if err != nil {
+			return collaboratorErrorResult(ctx, "failed to remove collaborator", owner, repo, resp, err), nil, nil
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		return utils.NewToolResultText(fmt.Sprintf("Removed collaborator %s from %s/%s", username, owner, repo)), nil, nil
+	})
+
+	return tool, handler
+}
+
+// ListInvitations creates a tool to list the pending invitations of a repository.
+func ListInvitations(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "list_invitations",
+		Description: t("TOOL_LIST_INVITATIONS_DESCRIPTION", "List the invitations to collaborate on a repository that were not accepted yet. Requires admin access to the repository."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_LIST_INVITATIONS_USER_TITLE", "List repository invitations"),
+			ReadOnlyHint: true,
+		},
+		InputSchema: WithPagination(&jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"owner": {
+					Type:        "string",
+					Description: "Repository owner",
+				},
+				"repo": {
+					Type:        "string",
+					Description: "Repository name",
+				},
+			},
+			Required: []string{"owner", "repo"},
+		}),
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		repo, err := RequiredParam[string](args, "repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		pagination, err := OptionalPaginationParams(args)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		invitations, resp, err := client.Repositories.ListInvitations(ctx, owner, repo, &github.ListOptions{Page: pagination.Page, PerPage: pagination.PerPage})
+		if err != nil {
+			return collaboratorErrorResult(ctx, "failed to list invitations", owner, repo, resp, err), nil, nil
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		result := make([]MinimalRepositoryInvitation, 0, len(invitations))
+		for _, invitation := range invitations {
+			m := MinimalRepositoryInvitation{
+				ID:          invitation.GetID(),
+				Invitee:     invitation.GetInvitee().GetLogin(),
+				Inviter:     invitation.GetInviter().GetLogin(),
+				Permissions: invitation.GetPermissions(),
+				Expired:     invitation.GetExpired(),
+				HTMLURL:     invitation.GetHTMLURL(),
+			}
+			if invitation.CreatedAt != nil {
+				m.CreatedAt = invitation.CreatedAt.Format(time.RFC3339)
+			}
+			result = append(result, m)
+		}
+
+		return MarshalledTextResult(result), nil, nil
+	})
+
+	return tool, handler
+}
+
+// CancelInvitation creates a tool to cancel an invitation to collaborate on a repository.
+func CancelInvitation(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "cancel_invitation",
+		Description: t("TOOL_CANCEL_INVITATION_DESCRIPTION", "Cancel an invitation to collaborate on a repository. Get invitation IDs with list_invitations. Requires admin access to the repository."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:           t("TOOL_CANCEL_INVITATION_USER_TITLE", "Cancel repository invitation"),
+			ReadOnlyHint:    false,
+			DestructiveHint: jsonschema.Ptr(true),
+		},
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"owner": {
+					Type:        "string",
+					Description: "Repository owner",
+				},
+				"repo": {
+					Type:        "string",
+					Description: "Repository name",
+				},
+				"invitation_id": {
+					Type:        "number",
+					Description: "ID of the invitation",
+				},
+			},
+			Required: []string{"owner", "repo", "invitation_id"},
+		},
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		repo, err := RequiredParam[string](args, "repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		invitationID, err := RequiredBigInt(args, "invitation_id")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		resp, err := client.Repositories.DeleteInvitation(ctx, owner, repo, invitationID)
+		if err != nil {
+			return collaboratorErrorResult(ctx, "failed to cancel invitation", owner, repo, resp, err), nil, nil
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		return utils.NewToolResultText(fmt.Sprintf("Cancelled invitation %d to %s/%s", invitationID, owner, repo)), nil, nil
+	})
+
+	return tool, handler
+}
@@ -0,0 +1,207 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v79/github"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func adminRequiredResponse(t *testing.T) http.HandlerFunc {
+	return mockResponse(t, http.StatusForbidden, map[string]any{"message": "Must have admin rights to Repository."})
+}
+
+func Test_AddCollaborator(t *testing.T) {
+	tool, _ := AddCollaborator(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	tests := []struct {
+		name           string
+		args           map[string]any
+		mockedClient   *http.Client
+		expectedResult AddedCollaborator
+		expectedCode   string
+	}{
+		{
+			name: "invites new collaborator",
+			args: map[string]any{"permission": "maintain"},
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.PutReposCollaboratorsByOwnerByRepoByUsername,
+					expectPath(t, "/repos/owner/repo/collaborators/hubot").andThen(
+						expectRequestBody(t, map[string]any{"permission": "maintain"}).andThen(
+							mockResponse(t, http.StatusCreated, &github.CollaboratorInvitation{ID: github.Ptr(int64(42))}),
+						),
+					),
+				),
+			),
+			expectedResult: AddedCollaborator{Repository: "owner/repo", Username: "hubot", Permission: "maintain", Invited: true, InvitationID: 42},
+		},
+		{
+			name: "updates existing collaborator with default permission",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.PutReposCollaboratorsByOwnerByRepoByUsername,
+					expectRequestBody(t, map[string]any{"permission": "push"}).andThen(
+						http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusNoContent) }),
+					),
+				),
+			),
+			expectedResult: AddedCollaborator{Repository: "owner/repo", Username: "hubot", Permission: "push"},
+		},
+		{
+			name: "token without admin rights",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(mock.PutReposCollaboratorsByOwnerByRepoByUsername, adminRequiredResponse(t)),
+			),
+			expectedCode: "ADMIN_REQUIRED",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, handler := AddCollaborator(stubGetClientFn(github.NewClient(tc.mockedClient)), translations.NullTranslationHelper)
+			args := map[string]any{"owner": "owner", "repo": "repo", "username": "hubot"}
+			for k, v := range tc.args {
+				args[k] = v
+			}
+			request := createMCPRequest(args)
+			result, _, err := handler(context.Background(), &request, args)
+			require.NoError(t, err)
+
+			if tc.expectedCode != "" {
+				require.True(t, result.IsError)
+				structured, ok := result.StructuredContent.(map[string]any)
+				require.True(t, ok)
+				assert.Equal(t, tc.expectedCode, structured["code"])
+				assert.Contains(t, structured["message"], "the token does not have admin access to owner/repo")
+				return
+			}
+			require.False(t, result.IsError, getTextResult(t, result).Text)
+
+			var added AddedCollaborator
+			require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &added))
+			assert.Equal(t, tc.expectedResult, added)
+		})
+	}
+}
+
+func Test_RemoveCollaborator(t *testing.T) {
+	tool, _ := RemoveCollaborator(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.True(t, *tool.Annotations.DestructiveHint)
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.DeleteReposCollaboratorsByOwnerByRepoByUsername,
+			expectPath(t, "/repos/owner/repo/collaborators/hubot").andThen(
+				http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusNoContent) }),
+			),
+		),
+	)
+	_, handler := RemoveCollaborator(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+	args := map[string]any{"owner": "owner", "repo": "repo", "username": "hubot"}
+	request := createMCPRequest(args)
+	result, _, err := handler(context.Background(), &request, args)
+	require.NoError(t, err)
+	require.False(t, result.IsError, getTextResult(t, result).Text)
+	assert.Equal(t, "Removed collaborator hubot from owner/repo", getTextResult(t, result).Text)
+}
+
+func Test_ListInvitations(t *testing.T) {
+	tool, _ := ListInvitations(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.True(t, tool.Annotations.ReadOnlyHint)
+
+	t.Run("lists invitations", func(t *testing.T) {
+		mockedClient := mock.NewMockedHTTPClient(
+			mock.WithRequestMatchHandler(
+				mock.GetReposInvitationsByOwnerByRepo,
+				mockResponse(t, http.StatusOK, []*github.RepositoryInvitation{{
+					ID:          github.Ptr(int64(42)),
+					Invitee:     &github.User{Login: github.Ptr("hubot")},
+					Inviter:     &github.User{Login: github.Ptr("octocat")},
+					Permissions: github.Ptr("write"),
+					Expired:     github.Ptr(true),
+				}}),
+			),
+		)
+		_, handler := ListInvitations(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+		args := map[string]any{"owner": "owner", "repo": "repo"}
+		request := createMCPRequest(args)
+		result, _, err := handler(context.Background(), &request, args)
+		require.NoError(t, err)
+		require.False(t, result.IsError, getTextResult(t, result).Text)
+
+		var invitations []MinimalRepositoryInvitation
+		require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &invitations))
+		assert.Equal(t, []MinimalRepositoryInvitation{{ID: 42, Invitee: "hubot", Inviter: "octocat", Permissions: "write", Expired: true}}, invitations)
+	})
+
+	t.Run("not found is not an admin error", func(t *testing.T) {
+		mockedClient := mock.NewMockedHTTPClient(
+			mock.WithRequestMatchHandler(
+				mock.GetReposInvitationsByOwnerByRepo,
+				mockResponse(t, http.StatusNotFound, map[string]any{"message": "Not Found"}),
+			),
+		)
+		_, handler := ListInvitations(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+		args := map[string]any{"owner": "owner", "repo": "repo"}
+		request := createMCPRequest(args)
+		result, _, err := handler(context.Background(), &request, args)
+		require.NoError(t, err)
+		require.True(t, result.IsError)
+		assert.Nil(t, result.StructuredContent)
+		assert.Contains(t, getErrorResult(t, result).Text, "failed to list invitations")
+	})
+}
+
+func Test_CancelInvitation(t *testing.T) {
+	tool, _ := CancelInvitation(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	t.Run("cancels invitation", func(t *testing.T) {
+		mockedClient := mock.NewMockedHTTPClient(
+			mock.WithRequestMatchHandler(
+				mock.DeleteReposInvitationsByOwnerByRepoByInvitationId,
+				expectPath(t, "/repos/owner/repo/invitations/42").andThen(
+					http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusNoContent) }),
+				),
+			),
+		)
+		_, handler := CancelInvitation(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+		args := map[string]any{"owner": "owner", "repo": "repo", "invitation_id": float64(42)}
+		request := createMCPRequest(args)
+		result, _, err := handler(context.Background(), &request, args)
+		require.NoError(t, err)
+		require.False(t, result.IsError, getTextResult(t, result).Text)
+		assert.Equal(t, "Cancelled invitation 42 to owner/repo", getTextResult(t, result).Text)
+	})
+
+	t.Run("token without admin rights", func(t *testing.T) {
+		mockedClient := mock.NewMockedHTTPClient(
+			mock.WithRequestMatchHandler(mock.DeleteReposInvitationsByOwnerByRepoByInvitationId, adminRequiredResponse(t)),
+		)
+		_, handler := CancelInvitation(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+		args := map[string]any{"owner": "owner", "repo": "repo", "invitation_id": float64(42)}
+		request := createMCPRequest(args)
+		result, _, err := handler(context.Background(), &request, args)
+		require.NoError(t, err)
+		require.True(t, result.IsError)
+		structured, ok := result.StructuredContent.(map[string]any)
+		require.True(t, ok)
+		assert.Equal(t, "ADMIN_REQUIRED", structured["code"])
+	})
+}
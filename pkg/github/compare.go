@@ -0,0 +1,446 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	ghErrors "github.com/github/github-mcp-server/pkg/errors"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/github/github-mcp-server/pkg/utils"
+	"github.com/google/go-github/v79/github"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ForkComparisonResult is the output of compare_across_forks.
+type ForkComparisonResult struct {
+	Upstream     string              `json:"upstream"`
+	UpstreamRef  string              `json:"upstream_ref"`
+	Fork         string              `json:"fork"`
+	ForkRef      string              `json:"fork_ref"`
+	Status       string              `json:"status"`
+	AheadBy      int                 `json:"ahead_by"`
+	BehindBy     int                 `json:"behind_by"`
+	MergeBaseSHA string              `json:"merge_base_sha,omitempty"`
+	NeedsSync    bool                `json:"needs_sync"`
+	Commits      []MinimalCommit     `json:"commits"`
+	Files        []MinimalCommitFile `json:"files"`
+	HTMLURL      string              `json:"html_url,omitempty"`
+}
+
+// CompareAcrossForks creates a tool to compare a fork branch against its upstream repository.
+func CompareAcrossForks(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "compare_across_forks",
+		Description: t("TOOL_COMPARE_ACROSS_FORKS_DESCRIPTION", "Compare a branch in a fork against a branch in its upstream repository (three-dot comparison), reporting how many commits the fork is ahead or behind and which files changed. Use this to decide whether a fork needs to be synced or rebased before pushing."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_COMPARE_ACROSS_FORKS_USER_TITLE", "Compare fork with upstream"),
+			ReadOnlyHint: true,
+		},
+		InputSchema: WithPagination(&jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"owner": {
+					Type:        "string",
+					Description: "Fork repository owner",
+				},
+				"repo": {
+					Type:        "string",
+					Description: "Fork repository name",
+				},
+				"branch": {
+					Type:        "string",
+					Description: "Branch in the fork to compare",
+				},
+				"upstream_owner": {
+					Type:        "string",
+					Description: "Upstream repository owner. Defaults to the owner of the fork's parent repository",
+				},
+				"upstream_repo": {
+					Type:        "string",
+					Description: "Upstream repository name. Defaults to the name of the fork's parent repository",
+				},
+				"upstream_branch": {
+					Type:        "string",
+					Description: "Upstream branch to compare against. Defaults to the upstream repository's default branch",
+				},
+			},
+			Required: []string{"owner", "repo", "branch"},
+		}),
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		repo, err := RequiredParam[string](args, "repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		branch, err := RequiredParam[string](args, "branch")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		upstreamOwner, err := OptionalParam[string](args, "upstream_owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		upstreamRepo, err := OptionalParam[string](args, "upstream_repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		upstreamBranch, err := OptionalParam[string](args, "upstream_branch")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		pagination, err := OptionalPaginationParams(args)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		// Resolve the upstream repository from the fork's parent if it wasn't given explicitly
+		if upstreamOwner == "" || upstreamRepo == "" {
+			forkRepo, resp, err := client.Repositories.Get(ctx, owner, repo)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to get fork repository",
+					resp,
+					err,
+				), nil, nil
+			}
+			_ = resp.Body.Close()
+
+			parent := forkRepo.GetParent()
+			if parent == nil {
+				return utils.NewToolResultError(fmt.Sprintf("%s/%s is not a fork; provide upstream_owner and upstream_repo explicitly", owner, repo)), nil, nil
+			}
+			if upstreamOwner == "" {
+				upstreamOwner = parent.GetOwner().GetLogin()
+			}
+			if upstreamRepo == "" {
+				upstreamRepo = parent.GetName()
+			}
+			if upstreamBranch == "" && upstreamOwner == parent.GetOwner().GetLogin() && upstreamRepo == parent.GetName() {
+				upstreamBranch = parent.GetDefaultBranch()
+			}
+		}
+
+		if upstreamBranch == "" {
+			upstream, resp, err := client.Repositories.Get(ctx, upstreamOwner, upstreamRepo)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to get upstream repository",
+					resp,
+					err,
+				), nil, nil
+			}
+			_ = resp.Body.Close()
+			upstreamBranch = upstream.GetDefaultBranch()
+		}
+
+		// Cross-repository comparisons are expressed as OWNER:REPO:REF on the head side
+		head := fmt.Sprintf("%s:%s:%s", owner, repo, branch)
+		comparison, resp, err := client.Repositories.CompareCommits(ctx, upstreamOwner, upstreamRepo, upstreamBranch, head, &github.ListOptions{
+			Page:    pagination.Page,
+			PerPage: pagination.PerPage,
+		})
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx,
+				"failed to compare fork with upstream",
+				resp,
+				err,
+			), nil, nil
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		result := ForkComparisonResult{
+			Upstream:     fmt.Sprintf("%s/%s", upstreamOwner, upstreamRepo),
+			UpstreamRef:  upstreamBranch,
+			Fork:         fmt.Sprintf("%s/%s", owner, repo),
+			ForkRef:      branch,
+			Status:       comparison.GetStatus(),
+			AheadBy:      comparison.GetAheadBy(),
+			BehindBy:     comparison.GetBehindBy(),
+			MergeBaseSHA: comparison.GetMergeBaseCommit().GetSHA(),
+			NeedsSync:    comparison.GetBehindBy() > 0,
+			Commits:      make([]MinimalCommit, 0, len(comparison.Commits)),
+			Files:        make([]MinimalCommitFile, 0, len(comparison.Files)),
+			HTMLURL:      comparison.GetHTMLURL(),
+		}
+		for _, commit := range comparison.Commits {
+			result.Commits = append(result.Commits, convertToMinimalCommit(commit, false))
+		}
+		for _, file := range comparison.Files {
+			result.Files = append(result.Files, MinimalCommitFile{
+				Filename:  file.GetFilename(),
+				Status:    file.GetStatus(),
+				Additions: file.GetAdditions(),
+				Deletions: file.GetDeletions(),
+				Changes:   file.GetChanges(),
+			})
+		}
+
+		r, err := json.Marshal(result)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to marshal response: %w", err)
+		}
+
+		return utils.NewToolResultText(string(r)), nil, nil
+	})
+
+	return tool, handler
+}
+
+const (
+	defaultCompareMaxFiles      = 100
+	defaultCompareMaxPatchLines = 200
+	// compareFilesLimit is the number of files GitHub returns for a comparison at most
+	compareFilesLimit = 300
+)
+
+// RefComparison is the output of compare_refs.
+type RefComparison struct {
+	Base           string         `json:"base"`
+	Head           string         `json:"head"`
+	Status         string         `json:"status"`
+	AheadBy        int            `json:"ahead_by"`
+	BehindBy       int            `json:"behind_by"`
+	MergeBaseSHA   string         `json:"merge_base_sha,omitempty"`
+	TotalCommits   int            `json:"total_commits"`
+	ChangedFiles   int            `json:"changed_files"`
+	MatchedFiles   int            `json:"matched_files"`
+	Files          []ComparedFile `json:"files"`
+	FilesTruncated bool           `json:"files_truncated,omitempty"`
+	Note           string         `json:"note,omitempty"`
+	HTMLURL        string         `json:"html_url,omitempty"`
+}
+
+// ComparedFile is a file changed between two refs, with its patch split into hunks.
+type ComparedFile struct {
+	Filename         string     `json:"filename"`
+	PreviousFilename string     `json:"previous_filename,omitempty"`
+	Status           string     `json:"status"`
+	Additions        int        `json:"additions"`
+	Deletions        int        `json:"deletions"`
+	Hunks            []DiffHunk `json:"hunks,omitempty"`
+	// PatchTruncated is set when hunks were cut to max_patch_lines
+	PatchTruncated bool `json:"patch_truncated,omitempty"`
+	// PatchUnavailable is set when GitHub returned no patch, for binary or very large files
+	PatchUnavailable bool `json:"patch_unavailable,omitempty"`
+}
+
+// DiffHunk is one hunk of a unified diff.
+type DiffHunk struct {
+	Header   string   `json:"header"`
+	OldStart int      `json:"old_start"`
+	OldLines int      `json:"old_lines"`
+	NewStart int      `json:"new_start"`
+	NewLines int      `json:"new_lines"`
+	Lines    []string `json:"lines"`
+}
+
+var hunkHeaderPattern = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// parseHunks splits a unified diff patch into hunks, keeping at most maxLines lines in total.
+// It reports whether lines were dropped.
+func parseHunks(patch string, maxLines int) ([]DiffHunk, bool) {
+	var hunks []DiffHunk
+	kept := 0
+	for _, line := range strings.Split(strings.TrimSuffix(patch, "\n"), "\n") {
+		if kept == maxLines {
+			return hunks, true
+		}
+		if m := hunkHeaderPattern.FindStringSubmatch(line); m != nil {
+			hunk := DiffHunk{Header: line, Lines: []string{}}
+			hunk.OldStart, _ = strconv.Atoi(m[1])
+			hunk.NewStart, _ = strconv.Atoi(m[3])
+			// A missing count means a single line
+			hunk.OldLines, hunk.NewLines = 1, 1
+			if m[2] != "" {
+				hunk.OldLines, _ = strconv.Atoi(m[2])
+			}
+			if m[4] != "" {
+				hunk.NewLines, _ = strconv.Atoi(m[4])
+			}
+			hunks = append(hunks, hunk)
+			continue
+		}
+		if len(hunks) == 0 {
+			continue
+		}
+		hunks[len(hunks)-1].Lines = append(hunks[len(hunks)-1].Lines, line)
+		kept++
+	}
+	return hunks, false
+}
+
+// CompareRefs creates a tool to compare two refs of a repository file by file.
+func CompareRefs(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "compare_refs",
+		Description: t("TOOL_COMPARE_REFS_DESCRIPTION", "Compare two refs (branches, tags or commit SHAs) of a GitHub repository: how many commits head is ahead of and behind base, and the files changed since their merge base with their diff hunks. Use paths to look at part of the tree, e.g. to check what a push changed."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_COMPARE_REFS_USER_TITLE", "Compare refs"),
+			ReadOnlyHint: true,
+		},
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"owner": {
+					Type:        "string",
+					Description: "Repository owner",
+				},
+				"repo": {
+					Type:        "string",
+					Description: "Repository name",
+				},
+				"base": {
+					Type:        "string",
+					Description: "Base ref: branch, tag or commit SHA",
+				},
+				"head": {
+					Type:        "string",
+					Description: "Head ref: branch, tag or commit SHA. Use OWNER:BRANCH for a branch of a fork in the same network",
+				},
+				"paths": {
+					Type:        "array",
+					Description: "Only report files matching these patterns, in .gitignore syntax, e.g. 'src/' or '*.go'",
+					Items:       &jsonschema.Schema{Type: "string"},
+				},
+				"include_patch": {
+					Type:        "boolean",
+					Description: "Include the diff hunks of each file (default: true)",
+					Default:     json.RawMessage("true"),
+				},
+				"max_files": {
+					Type:        "number",
+					Description: fmt.Sprintf("Maximum number of files to return (default: %d)", defaultCompareMaxFiles),
+					Minimum:     jsonschema.Ptr(1.0),
+				},
+				"max_patch_lines": {
+					Type:        "number",
+					Description: fmt.Sprintf("Maximum number of diff lines to return per file (default: %d)", defaultCompareMaxPatchLines),
+					Minimum:     jsonschema.Ptr(1.0),
+				},
+			},
+			Required: []string{"owner", "repo", "base", "head"},
+		},
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		repo, err := RequiredParam[string](args, "repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		base, err := RequiredParam[string](args, "base")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		head, err := RequiredParam[string](args, "head")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		paths, err := OptionalStringArrayParam(args, "paths")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		// The patterns use .gitignore syntax, so the ignore matcher selects the files to report
+		pathRules, err := parseIgnorePatterns(paths...)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		includePatch, err := OptionalBoolParamWithDefault(args, "include_patch", true)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		maxFiles, err := OptionalIntParamWithDefault(args, "max_files", defaultCompareMaxFiles)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		maxPatchLines, err := OptionalIntParamWithDefault(args, "max_patch_lines", defaultCompareMaxPatchLines)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		if maxFiles < 1 || maxPatchLines < 1 {
+			return utils.NewToolResultError("max_files and max_patch_lines must be at least 1"), nil, nil
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		// Files are listed in full with the first page of commits, which are not returned here
+		comparison, resp, err := client.Repositories.CompareCommits(ctx, owner, repo, base, head, &github.ListOptions{PerPage: 1})
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx,
+				"failed to compare refs",
+				resp,
+				err,
+			), nil, nil
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		result := RefComparison{
+			Base:         base,
+			Head:         head,
+			Status:       comparison.GetStatus(),
+			AheadBy:      comparison.GetAheadBy(),
+			BehindBy:     comparison.GetBehindBy(),
+			MergeBaseSHA: comparison.GetMergeBaseCommit().GetSHA(),
+			TotalCommits: comparison.GetTotalCommits(),
+			ChangedFiles: len(comparison.Files),
+			Files:        []ComparedFile{},
+			HTMLURL:      comparison.GetHTMLURL(),
+		}
+		for _, file := range comparison.Files {
+			if len(paths) > 0 && !pathRules.ignores(file.GetFilename()) &&
+				(file.GetPreviousFilename() == "" || !pathRules.ignores(file.GetPreviousFilename())) {
+				continue
+			}
+			result.MatchedFiles++
+			if len(result.Files) == maxFiles {
+				result.FilesTruncated = true
+				continue
+			}
+
+			compared := ComparedFile{
+				Filename:         file.GetFilename(),
+				PreviousFilename: file.GetPreviousFilename(),
+				Status:           file.GetStatus(),
+				Additions:        file.GetAdditions(),
+				Deletions:        file.GetDeletions(),
+			}
+			if includePatch {
+				if file.GetPatch() == "" {
+					compared.PatchUnavailable = file.GetChanges() > 0
+				} else {
+					compared.Hunks, compared.PatchTruncated = parseHunks(file.GetPatch(), maxPatchLines)
+				}
+			}
+			result.Files = append(result.Files, compared)
+		}
+
+		if len(comparison.Files) >= compareFilesLimit {
+			result.Note = fmt.Sprintf("GitHub lists at most %d files in a comparison, so some changed files may be missing; compare narrower ranges of commits to see them", compareFilesLimit)
+		}
+
+		return MarshalledTextResult(result), nil, nil
+	})
+
+	return tool, handler
+}
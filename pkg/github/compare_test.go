@@ -0,0 +1,275 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v79/github"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CompareAcrossForks(t *testing.T) {
+	// Verify tool definition once
+	mockClient := github.NewClient(nil)
+	tool, _ := CompareAcrossForks(stubGetClientFn(mockClient), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	assert.Equal(t, "compare_across_forks", tool.Name)
+	assert.NotEmpty(t, tool.Description)
+	schema, ok := tool.InputSchema.(*jsonschema.Schema)
+	require.True(t, ok)
+	assert.Contains(t, schema.Properties, "upstream_owner")
+	assert.Contains(t, schema.Properties, "upstream_repo")
+	assert.Contains(t, schema.Properties, "upstream_branch")
+	assert.ElementsMatch(t, schema.Required, []string{"owner", "repo", "branch"})
+
+	mockFork := &github.Repository{
+		Name:  github.Ptr("repo"),
+		Owner: &github.User{Login: github.Ptr("fork-owner")},
+		Fork:  github.Ptr(true),
+		Parent: &github.Repository{
+			Name:          github.Ptr("repo"),
+			Owner:         &github.User{Login: github.Ptr("upstream-owner")},
+			DefaultBranch: github.Ptr("main"),
+		},
+	}
+	mockComparison := &github.CommitsComparison{
+		Status:          github.Ptr("diverged"),
+		AheadBy:         github.Ptr(1),
+		BehindBy:        github.Ptr(3),
+		MergeBaseCommit: &github.RepositoryCommit{SHA: github.Ptr("base-sha")},
+		Commits: []*github.RepositoryCommit{
+			{SHA: github.Ptr("abc123"), Commit: &github.Commit{Message: github.Ptr("fork change")}},
+		},
+		Files: []*github.CommitFile{
+			{Filename: github.Ptr("README.md"), Status: github.Ptr("modified"), Additions: github.Ptr(2)},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		mockedClient   *http.Client
+		requestArgs    map[string]interface{}
+		expectError    bool
+		expectedErrMsg string
+		expectedPath   string
+	}{
+		{
+			name: "resolves upstream from fork parent",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatch(
+					mock.GetReposByOwnerByRepo,
+					mockFork,
+				),
+				mock.WithRequestMatchHandler(
+					mock.GetReposCompareByOwnerByRepoByBasehead,
+					expectPath(t, "/repos/upstream-owner/repo/compare/main...fork-owner:repo:feature").andThen(
+						mockResponse(t, http.StatusOK, mockComparison),
+					),
+				),
+			),
+			requestArgs: map[string]interface{}{
+				"owner":  "fork-owner",
+				"repo":   "repo",
+				"branch": "feature",
+			},
+		},
+		{
+			name: "explicit upstream and branch skip repository lookup",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.GetReposCompareByOwnerByRepoByBasehead,
+					expectPath(t, "/repos/upstream-owner/repo/compare/develop...fork-owner:repo:feature").andThen(
+						mockResponse(t, http.StatusOK, mockComparison),
+					),
+				),
+			),
+			requestArgs: map[string]interface{}{
+				"owner":           "fork-owner",
+				"repo":            "repo",
+				"branch":          "feature",
+				"upstream_owner":  "upstream-owner",
+				"upstream_repo":   "repo",
+				"upstream_branch": "develop",
+			},
+		},
+		{
+			name: "repository is not a fork",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatch(
+					mock.GetReposByOwnerByRepo,
+					&github.Repository{Name: github.Ptr("repo")},
+				),
+			),
+			requestArgs: map[string]interface{}{
+				"owner":  "owner",
+				"repo":   "repo",
+				"branch": "feature",
+			},
+			expectError:    true,
+			expectedErrMsg: "is not a fork",
+		},
+		{
+			name: "comparison fails",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.GetReposCompareByOwnerByRepoByBasehead,
+					mockResponse(t, http.StatusNotFound, `{"message": "Not Found"}`),
+				),
+			),
+			requestArgs: map[string]interface{}{
+				"owner":           "fork-owner",
+				"repo":            "repo",
+				"branch":          "missing",
+				"upstream_owner":  "upstream-owner",
+				"upstream_repo":   "repo",
+				"upstream_branch": "main",
+			},
+			expectError:    true,
+			expectedErrMsg: "failed to compare fork with upstream",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client := github.NewClient(tc.mockedClient)
+			_, handler := CompareAcrossForks(stubGetClientFn(client), translations.NullTranslationHelper)
+
+			request := createMCPRequest(tc.requestArgs)
+			result, _, err := handler(context.Background(), &request, tc.requestArgs)
+			require.NoError(t, err)
+
+			if tc.expectError {
+				require.True(t, result.IsError)
+				errorContent := getErrorResult(t, result)
+				assert.Contains(t, errorContent.Text, tc.expectedErrMsg)
+				return
+			}
+
+			require.False(t, result.IsError)
+			textContent := getTextResult(t, result)
+
+			var comparison ForkComparisonResult
+			require.NoError(t, json.Unmarshal([]byte(textContent.Text), &comparison))
+			assert.Equal(t, "upstream-owner/repo", comparison.Upstream)
+			assert.Equal(t, "fork-owner/repo", comparison.Fork)
+			assert.Equal(t, 1, comparison.AheadBy)
+			assert.Equal(t, 3, comparison.BehindBy)
+			assert.True(t, comparison.NeedsSync)
+			assert.Equal(t, "base-sha", comparison.MergeBaseSHA)
+			require.Len(t, comparison.Commits, 1)
+			assert.Equal(t, "abc123", comparison.Commits[0].SHA)
+			require.Len(t, comparison.Files, 1)
+			assert.Equal(t, "README.md", comparison.Files[0].Filename)
+		})
+	}
+}
+
+func Test_CompareRefs(t *testing.T) {
+	tool, _ := CompareRefs(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	comparison := &github.CommitsComparison{
+		Status:          github.Ptr("diverged"),
+		AheadBy:         github.Ptr(2),
+		BehindBy:        github.Ptr(1),
+		TotalCommits:    github.Ptr(2),
+		MergeBaseCommit: &github.RepositoryCommit{SHA: github.Ptr("base-sha")},
+		Files: []*github.CommitFile{
+			{
+				Filename:  github.Ptr("src/main.go"),
+				Status:    github.Ptr("modified"),
+				Additions: github.Ptr(2),
+				Deletions: github.Ptr(1),
+				Changes:   github.Ptr(3),
+				Patch:     github.Ptr("@@ -1,3 +1,3 @@\n package main\n-var a = 1\n+var a = 2\n@@ -10 +10,2 @@ func main() {\n+\tprintln(a)\n+\treturn"),
+			},
+			{
+				Filename:         github.Ptr("src/new.go"),
+				PreviousFilename: github.Ptr("old.go"),
+				Status:           github.Ptr("renamed"),
+			},
+			{
+				Filename:  github.Ptr("logo.png"),
+				Status:    github.Ptr("added"),
+				Additions: github.Ptr(0),
+				Changes:   github.Ptr(1),
+			},
+			{
+				Filename:  github.Ptr("README.md"),
+				Status:    github.Ptr("modified"),
+				Additions: github.Ptr(1),
+				Changes:   github.Ptr(1),
+				Patch:     github.Ptr("@@ -1 +1 @@\n-a\n+b"),
+			},
+		},
+	}
+	newHandler := func(t *testing.T) mcp.ToolHandlerFor[map[string]any, any] {
+		mockedClient := mock.NewMockedHTTPClient(
+			mock.WithRequestMatchHandler(
+				mock.GetReposCompareByOwnerByRepoByBasehead,
+				expectPath(t, "/repos/owner/repo/compare/main...feature").andThen(
+					mockResponse(t, http.StatusOK, comparison),
+				),
+			),
+		)
+		_, handler := CompareRefs(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+		return handler
+	}
+	call := func(t *testing.T, args map[string]any) RefComparison {
+		full := map[string]any{"owner": "owner", "repo": "repo", "base": "main", "head": "feature"}
+		for k, v := range args {
+			full[k] = v
+		}
+		request := createMCPRequest(full)
+		result, _, err := newHandler(t)(context.Background(), &request, full)
+		require.NoError(t, err)
+		require.False(t, result.IsError, getTextResult(t, result).Text)
+		var compared RefComparison
+		require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &compared))
+		return compared
+	}
+
+	t.Run("all files with hunks", func(t *testing.T) {
+		compared := call(t, nil)
+		assert.Equal(t, "diverged", compared.Status)
+		assert.Equal(t, 2, compared.AheadBy)
+		assert.Equal(t, 1, compared.BehindBy)
+		assert.Equal(t, "base-sha", compared.MergeBaseSHA)
+		assert.Equal(t, 4, compared.ChangedFiles)
+		require.Len(t, compared.Files, 4)
+
+		assert.Equal(t, []DiffHunk{
+			{Header: "@@ -1,3 +1,3 @@", OldStart: 1, OldLines: 3, NewStart: 1, NewLines: 3, Lines: []string{" package main", "-var a = 1", "+var a = 2"}},
+			{Header: "@@ -10 +10,2 @@ func main() {", OldStart: 10, OldLines: 1, NewStart: 10, NewLines: 2, Lines: []string{"+\tprintln(a)", "+\treturn"}},
+		}, compared.Files[0].Hunks)
+		assert.Equal(t, "old.go", compared.Files[1].PreviousFilename)
+		assert.False(t, compared.Files[1].PatchUnavailable)
+		assert.True(t, compared.Files[2].PatchUnavailable)
+	})
+
+	t.Run("paths and truncation", func(t *testing.T) {
+		compared := call(t, map[string]any{"paths": []any{"src/"}, "max_files": float64(1), "max_patch_lines": float64(2)})
+		assert.Equal(t, 2, compared.MatchedFiles)
+		assert.True(t, compared.FilesTruncated)
+		require.Len(t, compared.Files, 1)
+		assert.True(t, compared.Files[0].PatchTruncated)
+		require.Len(t, compared.Files[0].Hunks, 1)
+		assert.Equal(t, []string{" package main", "-var a = 1"}, compared.Files[0].Hunks[0].Lines)
+
+		// Renamed files match on either name
+		compared = call(t, map[string]any{"paths": []any{"old.go", "*.md"}, "include_patch": false})
+		require.Len(t, compared.Files, 2)
+		assert.Equal(t, "src/new.go", compared.Files[0].Filename)
+		assert.Equal(t, "README.md", compared.Files[1].Filename)
+		assert.Empty(t, compared.Files[1].Hunks)
+	})
+}
@@ -0,0 +1,533 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	ghErrors "github.com/github/github-mcp-server/pkg/errors"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/github/github-mcp-server/pkg/utils"
+	"github.com/google/go-github/v79/github"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// maxCopilotMetricsDays is the longest period GitHub keeps Copilot metrics for
+const maxCopilotMetricsDays = 100
+
+// CopilotCompletionTotals sums the code completion activity of a period.
+type CopilotCompletionTotals struct {
+	Suggestions    int `json:"suggestions"`
+	Acceptances    int `json:"acceptances"`
+	LinesSuggested int `json:"lines_suggested"`
+	LinesAccepted  int `json:"lines_accepted"`
+	// AcceptanceRate is the share of suggestions accepted, between 0 and 1
+	AcceptanceRate float64 `json:"acceptance_rate"`
+}
+
+func (c *CopilotCompletionTotals) add(language *github.CopilotIDECodeCompletionsModelLanguage) {
+	c.Suggestions += language.TotalCodeSuggestions
+	c.Acceptances += language.TotalCodeAcceptances
+	c.LinesSuggested += language.TotalCodeLinesSuggested
+	c.LinesAccepted += language.TotalCodeLinesAccepted
+}
+
+func (c *CopilotCompletionTotals) finish() {
+	if c.Suggestions > 0 {
+		c.AcceptanceRate = float64(c.Acceptances) / float64(c.Suggestions)
+	}
+}
+
+// CopilotChatTotals sums the chat activity of a period.
+type CopilotChatTotals struct {
+	IDEChats             int `json:"ide_chats"`
+	IDEChatInsertions    int `json:"ide_chat_insertions"`
+	IDEChatCopies        int `json:"ide_chat_copies"`
+	DotcomChats          int `json:"dotcom_chats"`
+	PullRequestSummaries int `json:"pull_request_summaries"`
+}
+
+// CopilotBreakdown is the completion activity of one language or editor.
+type CopilotBreakdown struct {
+	Name string `json:"name"`
+	// PeakEngagedUsers is the largest number of users engaged on one day of the period
+	PeakEngagedUsers int `json:"peak_engaged_users"`
+	CopilotCompletionTotals
+}
+
+// CopilotDailyMetrics is the activity of one day.
+type CopilotDailyMetrics struct {
+	Date         string `json:"date"`
+	ActiveUsers  int    `json:"active_users"`
+	EngagedUsers int    `json:"engaged_users"`
+	Suggestions  int    `json:"suggestions"`
+	Acceptances  int    `json:"acceptances"`
+	Chats        int    `json:"chats"`
+}
+
+// CopilotMetricsReport summarizes the Copilot metrics of an organization or team over a period.
+type CopilotMetricsReport struct {
+	Org   string `json:"org"`
+	Team  string `json:"team,omitempty"`
+	Since string `json:"since,omitempty"`
+	Until string `json:"until,omitempty"`
+	Days  int    `json:"days"`
+	// PeakActiveUsers is the largest number of users active on one day of the period
+	PeakActiveUsers  int                     `json:"peak_active_users"`
+	PeakEngagedUsers int                     `json:"peak_engaged_users"`
+	Completions      CopilotCompletionTotals `json:"completions"`
+	Chat             CopilotChatTotals       `json:"chat"`
+	Languages        []CopilotBreakdown      `json:"languages"`
+	Editors          []CopilotBreakdown      `json:"editors"`
+	Daily            []CopilotDailyMetrics   `json:"daily,omitempty"`
+}
+
+// summarizeCopilotMetrics adds up daily metrics into a report, with completions broken down by
+// language and editor, sorted by the number of suggestions.
+func summarizeCopilotMetrics(days []*github.CopilotMetrics, includeDaily bool) CopilotMetricsReport {
+	report := CopilotMetricsReport{Days: len(days)}
+	languages := make(map[string]*CopilotBreakdown)
+	editors := make(map[string]*CopilotBreakdown)
+	breakdown := func(index map[string]*CopilotBreakdown, name string) *CopilotBreakdown {
+		if index[name] == nil {
+			index[name] = &CopilotBreakdown{Name: name}
+		}
+		return index[name]
+	}
+
+	for _, day := range days {
+		daily := CopilotDailyMetrics{Date: day.Date}
+		if day.TotalActiveUsers != nil {
+			daily.ActiveUsers = *day.TotalActiveUsers
+		}
+		if day.TotalEngagedUsers != nil {
+			daily.EngagedUsers = *day.TotalEngagedUsers
+		}
+		report.PeakActiveUsers = max(report.PeakActiveUsers, daily.ActiveUsers)
+		report.PeakEngagedUsers = max(report.PeakEngagedUsers, daily.EngagedUsers)
+
+		if completions := day.CopilotIDECodeCompletions; completions != nil {
+			for _, language := range completions.Languages {
+				entry := breakdown(languages, language.Name)
+				entry.PeakEngagedUsers = max(entry.PeakEngagedUsers, language.TotalEngagedUsers)
+			}
+			for _, editor := range completions.Editors {
+				editorEntry := breakdown(editors, editor.Name)
+				editorEntry.PeakEngagedUsers = max(editorEntry.PeakEngagedUsers, editor.TotalEngagedUsers)
+				for _, model := range editor.Models {
+					for _, language := range model.Languages {
+						report.Completions.add(language)
+						editorEntry.add(language)
+						breakdown(languages, language.Name).add(language)
+						daily.Suggestions += language.TotalCodeSuggestions
+						daily.Acceptances += language.TotalCodeAcceptances
+					}
+				}
+			}
+		}
+		if chat := day.CopilotIDEChat; chat != nil {
+			for _, editor := range chat.Editors {
+				for _, model := range editor.Models {
+					report.Chat.IDEChats += model.TotalChats
+					report.Chat.IDEChatInsertions += model.TotalChatInsertionEvents
+					report.Chat.IDEChatCopies += model.TotalChatCopyEvents
+					daily.Chats += model.TotalChats
+				}
+			}
+		}
+		if chat := day.CopilotDotcomChat; chat != nil {
+			for _, model := range chat.Models {
+				report.Chat.DotcomChats += model.TotalChats
+				daily.Chats += model.TotalChats
+			}
+		}
+		if pullRequests := day.CopilotDotcomPullRequests; pullRequests != nil {
+			for _, repository := range pullRequests.Repositories {
+				for _, model := range repository.Models {
+					report.Chat.PullRequestSummaries += model.TotalPRSummariesCreated
+				}
+			}
+		}
+		if includeDaily {
+			report.Daily = append(report.Daily, daily)
+		}
+	}
+
+	report.Completions.finish()
+	report.Languages = sortedCopilotBreakdowns(languages)
+	report.Editors = sortedCopilotBreakdowns(editors)
+	return report
+}
+
+func sortedCopilotBreakdowns(index map[string]*CopilotBreakdown) []CopilotBreakdown {
+	sorted := make([]CopilotBreakdown, 0, len(index))
+	for _, entry := range index {
+		entry.finish()
+		sorted = append(sorted, *entry)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Suggestions != sorted[j].Suggestions {
+			return sorted[i].Suggestions > sorted[j].Suggestions
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+// GetCopilotMetrics creates a tool to report the Copilot usage metrics of an organization or team.
+func GetCopilotMetrics(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	return mcp.Tool{
+			Name:        "get_copilot_metrics",
+			Description: t("TOOL_GET_COPILOT_METRICS_DESCRIPTION", fmt.Sprintf("Report the GitHub Copilot usage of an organization or one of its teams over up to the last %d days: active and engaged users, code completion suggestions and acceptances broken down by language and editor, and IDE, github.com and pull request chat activity. Metrics are only available when the Copilot metrics API access policy is enabled for the organization.", maxCopilotMetricsDays)),
+			Annotations: &mcp.ToolAnnotations{
+				Title:        t("TOOL_GET_COPILOT_METRICS_USER_TITLE", "Get Copilot usage metrics"),
+				ReadOnlyHint: true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"org": {
+						Type:        "string",
+						Description: "Organization name",
+					},
+					"team": {
+						Type:        "string",
+						Description: "Team slug, to report the metrics of the team's members only",
+					},
+					"since": {
+						Type:        "string",
+						Description: "Start of the period, as an ISO 8601 date (YYYY-MM-DD) or timestamp. Defaults to the oldest available day",
+					},
+					"until": {
+						Type:        "string",
+						Description: "End of the period, as an ISO 8601 date (YYYY-MM-DD) or timestamp. Defaults to the latest available day",
+					},
+					"include_daily": {
+						Type:        "boolean",
+						Description: "Also return the totals of every day of the period",
+					},
+				},
+				Required: []string{"org"},
+			},
+		},
+		func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+			org, err := RequiredParam[string](args, "org")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			team, err := OptionalParam[string](args, "team")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			since, err := OptionalParam[string](args, "since")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			until, err := OptionalParam[string](args, "until")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			includeDaily, err := OptionalParam[bool](args, "include_daily")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+
+			// One page holds every day GitHub keeps metrics for
+			opts := &github.CopilotMetricsListOptions{ListOptions: github.ListOptions{PerPage: maxCopilotMetricsDays}}
+			if since != "" {
+				sinceTime, err := parseISOTimestamp(since)
+				if err != nil {
+					return utils.NewToolResultError(fmt.Sprintf("since: %s", err)), nil, nil
+				}
+				opts.Since = &sinceTime
+			}
+			if until != "" {
+				untilTime, err := parseISOTimestamp(until)
+				if err != nil {
+					return utils.NewToolResultError(fmt.Sprintf("until: %s", err)), nil, nil
+				}
+				opts.Until = &untilTime
+			}
+			if opts.Since != nil && opts.Until != nil && opts.Until.Before(*opts.Since) {
+				return utils.NewToolResultError("until must not be before since"), nil, nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+
+			var days []*github.CopilotMetrics
+			var resp *github.Response
+			if team != "" {
+				days, resp, err = client.Copilot.GetOrganizationTeamMetrics(ctx, org, team, opts)
+			} else {
+				days, resp, err = client.Copilot.GetOrganizationMetrics(ctx, org, opts)
+			}
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to get Copilot metrics", resp, err), nil, nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			report := summarizeCopilotMetrics(days, includeDaily)
+			report.Org = org
+			report.Team = team
+			if len(days) > 0 {
+				report.Since = days[0].Date
+				report.Until = days[len(days)-1].Date
+			}
+
+			return MarshalledTextResult(report), nil, nil
+		}
+}
+
+// CopilotSeat is the activity of one Copilot seat.
+type CopilotSeat struct {
+	Assignee                string            `json:"assignee"`
+	AssigneeType            string            `json:"assignee_type"`
+	AssigningTeam           string            `json:"assigning_team,omitempty"`
+	PlanType                string            `json:"plan_type,omitempty"`
+	LastActivityAt          *github.Timestamp `json:"last_activity_at,omitempty"`
+	LastEditor              string            `json:"last_activity_editor,omitempty"`
+	PendingCancellationDate string            `json:"pending_cancellation_date,omitempty"`
+}
+
+// CopilotSeatUsage reports the Copilot seats of an organization.
+type CopilotSeatUsage struct {
+	Org                   string                       `json:"org"`
+	SeatBreakdown         *github.CopilotSeatBreakdown `json:"seat_breakdown"`
+	SeatManagementSetting string                       `json:"seat_management_setting"`
+	CopilotChat           string                       `json:"copilot_chat"`
+	PublicCodeSuggestions string                       `json:"public_code_suggestions"`
+	TotalSeats            int64                        `json:"total_seats"`
+	Seats                 []CopilotSeat                `json:"seats"`
+}
+
+// GetCopilotSeatUsage creates a tool to report the Copilot seats of an organization and their activity.
+func GetCopilotSeatUsage(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	return mcp.Tool{
+			Name:        "get_copilot_seat_usage",
+			Description: t("TOOL_GET_COPILOT_SEAT_USAGE_DESCRIPTION", "Get the GitHub Copilot seats of an organization: how many are assigned, active and inactive in the current billing cycle, the Copilot policies, and a page of the seats with their last activity"),
+			Annotations: &mcp.ToolAnnotations{
+				Title:        t("TOOL_GET_COPILOT_SEAT_USAGE_USER_TITLE", "Get Copilot seat usage"),
+				ReadOnlyHint: true,
+			},
+			InputSchema: WithPagination(&jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"org": {
+						Type:        "string",
+						Description: "Organization name",
+					},
+				},
+				Required: []string{"org"},
+			}),
+		},
+		func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+			org, err := RequiredParam[string](args, "org")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			pagination, err := OptionalPaginationParams(args)
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+
+			billing, resp, err := client.Copilot.GetCopilotBilling(ctx, org)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to get Copilot billing", resp, err), nil, nil
+			}
+			_ = resp.Body.Close()
+
+			seats, resp, err := client.Copilot.ListCopilotSeats(ctx, org, &github.ListOptions{Page: pagination.Page, PerPage: pagination.PerPage})
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to list Copilot seats", resp, err), nil, nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			usage := CopilotSeatUsage{
+				Org:                   org,
+				SeatBreakdown:         billing.SeatBreakdown,
+				SeatManagementSetting: billing.SeatManagementSetting,
+				CopilotChat:           billing.CopilotChat,
+				PublicCodeSuggestions: billing.PublicCodeSuggestions,
+				TotalSeats:            seats.TotalSeats,
+				Seats:                 make([]CopilotSeat, 0, len(seats.Seats)),
+			}
+			for _, details := range seats.Seats {
+				seat := CopilotSeat{
+					PlanType:                details.GetPlanType(),
+					LastActivityAt:          details.LastActivityAt,
+					LastEditor:              details.GetLastActivityEditor(),
+					PendingCancellationDate: details.GetPendingCancellationDate(),
+				}
+				if user, ok := details.GetUser(); ok {
+					seat.Assignee, seat.AssigneeType = user.GetLogin(), "User"
+				} else if team, ok := details.GetTeam(); ok {
+					seat.Assignee, seat.AssigneeType = team.GetSlug(), "Team"
+				} else if organization, ok := details.GetOrganization(); ok {
+					seat.Assignee, seat.AssigneeType = organization.GetLogin(), "Organization"
+				}
+				if details.AssigningTeam != nil {
+					seat.AssigningTeam = details.AssigningTeam.GetSlug()
+				}
+				usage.Seats = append(usage.Seats, seat)
+			}
+
+			return MarshalledTextResult(usage), nil, nil
+		}
+}
+
+// copilotSeatAssigneesProperties are the parameters selecting the users and teams of Copilot seat changes.
+func copilotSeatAssigneesProperties() map[string]*jsonschema.Schema {
+	return map[string]*jsonschema.Schema{
+		"org": {
+			Type:        "string",
+			Description: "Organization name",
+		},
+		"usernames": {
+			Type:        "array",
+			Description: "Logins of organization members",
+			Items: &jsonschema.Schema{
+				Type: "string",
+			},
+		},
+		"teams": {
+			Type:        "array",
+			Description: "Names of teams of the organization, covering all of their members",
+			Items: &jsonschema.Schema{
+				Type: "string",
+			},
+		},
+	}
+}
+
+func requiredCopilotSeatAssignees(args map[string]any) (usernames []string, teams []string, err error) {
+	if usernames, err = OptionalStringArrayParam(args, "usernames"); err != nil {
+		return nil, nil, err
+	}
+	if teams, err = OptionalStringArrayParam(args, "teams"); err != nil {
+		return nil, nil, err
+	}
+	if len(usernames) == 0 && len(teams) == 0 {
+		return nil, nil, fmt.Errorf("at least one of usernames or teams is required")
+	}
+	return usernames, teams, nil
+}
+
+// AddCopilotSeats creates a tool to assign Copilot seats to users and teams of an organization.
+func AddCopilotSeats(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	return mcp.Tool{
+			Name:        "add_copilot_seats",
+			Description: t("TOOL_ADD_COPILOT_SEATS_DESCRIPTION", "Assign GitHub Copilot seats to members and teams of an organization, which the organization is billed for. The organization's seat management setting must allow assigning seats to selected users and teams. Returns the number of new seats; members who already have a seat are not counted"),
+			Annotations: &mcp.ToolAnnotations{
+				Title:        t("TOOL_ADD_COPILOT_SEATS_USER_TITLE", "Add Copilot seats"),
+				ReadOnlyHint: false,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type:       "object",
+				Properties: copilotSeatAssigneesProperties(),
+				Required:   []string{"org"},
+			},
+		},
+		func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+			org, err := RequiredParam[string](args, "org")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			usernames, teams, err := requiredCopilotSeatAssignees(args)
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+
+			seatsCreated := 0
+			if len(usernames) > 0 {
+				assignments, resp, err := client.Copilot.AddCopilotUsers(ctx, org, usernames)
+				if err != nil {
+					return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to add Copilot seats for users", resp, err), nil, nil
+				}
+				_ = resp.Body.Close()
+				seatsCreated += assignments.SeatsCreated
+			}
+			if len(teams) > 0 {
+				assignments, resp, err := client.Copilot.AddCopilotTeams(ctx, org, teams)
+				if err != nil {
+					return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to add Copilot seats for teams", resp, err), nil, nil
+				}
+				_ = resp.Body.Close()
+				seatsCreated += assignments.SeatsCreated
+			}
+
+			return MarshalledTextResult(map[string]any{
+				"org":           org,
+				"seats_created": seatsCreated,
+			}), nil, nil
+		}
+}
+
+// RemoveCopilotSeats creates a tool to cancel the Copilot seats of users and teams of an organization.
+func RemoveCopilotSeats(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	return mcp.Tool{
+			Name:        "remove_copilot_seats",
+			Description: t("TOOL_REMOVE_COPILOT_SEATS_DESCRIPTION", "Cancel the GitHub Copilot seats of members and teams of an organization. Seats stay usable until the end of the billing cycle, when they are removed. A member who has a seat through a team keeps it until they leave the team or the team's seats are cancelled. Returns the number of cancelled seats"),
+			Annotations: &mcp.ToolAnnotations{
+				Title:           t("TOOL_REMOVE_COPILOT_SEATS_USER_TITLE", "Remove Copilot seats"),
+				ReadOnlyHint:    false,
+				DestructiveHint: jsonschema.Ptr(true),
+			},
+			InputSchema: &jsonschema.Schema{
+				Type:       "object",
+				Properties: copilotSeatAssigneesProperties(),
+				Required:   []string{"org"},
+			},
+		},
+		func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+			org, err := RequiredParam[string](args, "org")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			usernames, teams, err := requiredCopilotSeatAssignees(args)
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+
+			seatsCancelled := 0
+			if len(usernames) > 0 {
+				cancellations, resp, err := client.Copilot.RemoveCopilotUsers(ctx, org, usernames)
+				if err != nil {
+					return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to remove Copilot seats of users", resp, err), nil, nil
+				}
+				_ = resp.Body.Close()
+				seatsCancelled += cancellations.SeatsCancelled
+			}
+			if len(teams) > 0 {
+				cancellations, resp, err := client.Copilot.RemoveCopilotTeams(ctx, org, teams)
+				if err != nil {
+					return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to remove Copilot seats of teams", resp, err), nil, nil
+				}
+				_ = resp.Body.Close()
+				seatsCancelled += cancellations.SeatsCancelled
+			}
+
+			return MarshalledTextResult(map[string]any{
+				"org":             org,
+				"seats_cancelled": seatsCancelled,
+			}), nil, nil
+		}
+}
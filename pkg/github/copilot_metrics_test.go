@@ -0,0 +1,284 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v79/github"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testCopilotMetricsDay(date string, active, goSuggestions, goAcceptances, pySuggestions int) *github.CopilotMetrics {
+	return &github.CopilotMetrics{
+		Date:              date,
+		TotalActiveUsers:  github.Ptr(active),
+		TotalEngagedUsers: github.Ptr(active - 1),
+		CopilotIDECodeCompletions: &github.CopilotIDECodeCompletions{
+			Languages: []*github.CopilotIDECodeCompletionsLanguage{
+				{Name: "go", TotalEngagedUsers: active - 1},
+				{Name: "python", TotalEngagedUsers: 1},
+			},
+			Editors: []*github.CopilotIDECodeCompletionsEditor{{
+				Name:              "vscode",
+				TotalEngagedUsers: active - 1,
+				Models: []*github.CopilotIDECodeCompletionsModel{{
+					Name: "default",
+					Languages: []*github.CopilotIDECodeCompletionsModelLanguage{
+						{Name: "go", TotalCodeSuggestions: goSuggestions, TotalCodeAcceptances: goAcceptances, TotalCodeLinesSuggested: goSuggestions * 2, TotalCodeLinesAccepted: goAcceptances * 2},
+						{Name: "python", TotalCodeSuggestions: pySuggestions},
+					},
+				}},
+			}},
+		},
+		CopilotIDEChat: &github.CopilotIDEChat{
+			Editors: []*github.CopilotIDEChatEditor{{
+				Name:   "vscode",
+				Models: []*github.CopilotIDEChatModel{{Name: "default", TotalChats: 3, TotalChatInsertionEvents: 2, TotalChatCopyEvents: 1}},
+			}},
+		},
+		CopilotDotcomChat: &github.CopilotDotcomChat{
+			Models: []*github.CopilotDotcomChatModel{{Name: "default", TotalChats: 1}},
+		},
+	}
+}
+
+func Test_summarizeCopilotMetrics(t *testing.T) {
+	report := summarizeCopilotMetrics([]*github.CopilotMetrics{
+		testCopilotMetricsDay("2026-10-01", 5, 100, 40, 10),
+		testCopilotMetricsDay("2026-10-02", 8, 60, 20, 30),
+	}, true)
+
+	assert.Equal(t, 2, report.Days)
+	assert.Equal(t, 8, report.PeakActiveUsers)
+	assert.Equal(t, 7, report.PeakEngagedUsers)
+	assert.Equal(t, CopilotCompletionTotals{Suggestions: 200, Acceptances: 60, LinesSuggested: 320, LinesAccepted: 120, AcceptanceRate: 0.3}, report.Completions)
+	assert.Equal(t, CopilotChatTotals{IDEChats: 6, IDEChatInsertions: 4, IDEChatCopies: 2, DotcomChats: 2}, report.Chat)
+	require.Len(t, report.Languages, 2)
+	assert.Equal(t, "go", report.Languages[0].Name)
+	assert.Equal(t, 160, report.Languages[0].Suggestions)
+	assert.Equal(t, 7, report.Languages[0].PeakEngagedUsers)
+	assert.Equal(t, 0.375, report.Languages[0].AcceptanceRate)
+	assert.Equal(t, "python", report.Languages[1].Name)
+	assert.Equal(t, []CopilotBreakdown{{Name: "vscode", PeakEngagedUsers: 7, CopilotCompletionTotals: report.Completions}}, report.Editors)
+	assert.Equal(t, []CopilotDailyMetrics{
+		{Date: "2026-10-01", ActiveUsers: 5, EngagedUsers: 4, Suggestions: 110, Acceptances: 40, Chats: 4},
+		{Date: "2026-10-02", ActiveUsers: 8, EngagedUsers: 7, Suggestions: 90, Acceptances: 20, Chats: 4},
+	}, report.Daily)
+
+	assert.Nil(t, summarizeCopilotMetrics([]*github.CopilotMetrics{testCopilotMetricsDay("2026-10-01", 5, 1, 1, 1)}, false).Daily)
+}
+
+func Test_GetCopilotMetrics(t *testing.T) {
+	tool, _ := GetCopilotMetrics(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	schema, ok := tool.InputSchema.(*jsonschema.Schema)
+	require.True(t, ok, "InputSchema should be *jsonschema.Schema")
+	assert.Equal(t, "get_copilot_metrics", tool.Name)
+	assert.True(t, tool.Annotations.ReadOnlyHint)
+	assert.Contains(t, schema.Properties, "team")
+	assert.Contains(t, schema.Properties, "since")
+	assert.ElementsMatch(t, schema.Required, []string{"org"})
+
+	days := []*github.CopilotMetrics{testCopilotMetricsDay("2026-10-01", 5, 100, 40, 10)}
+	tests := []struct {
+		name           string
+		mockedClient   *http.Client
+		requestArgs    map[string]any
+		expectError    bool
+		expectedErrMsg string
+		expectedTeam   string
+	}{
+		{
+			name: "organization metrics for a period",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.GetOrgsCopilotMetricsByOrg,
+					expectQueryParams(t, map[string]string{
+						"since":    "2026-10-01T00:00:00Z",
+						"until":    "2026-10-07T00:00:00Z",
+						"per_page": "100",
+					}).andThen(mockResponse(t, http.StatusOK, days)),
+				),
+			),
+			requestArgs: map[string]any{"org": "corp", "since": "2026-10-01", "until": "2026-10-07"},
+		},
+		{
+			name: "team metrics",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.GetOrgsTeamCopilotMetricsByOrgByTeamSlug,
+					expectPath(t, "/orgs/corp/team/platform/copilot/metrics").andThen(mockResponse(t, http.StatusOK, days)),
+				),
+			),
+			requestArgs:  map[string]any{"org": "corp", "team": "platform"},
+			expectedTeam: "platform",
+		},
+		{
+			name:           "until before since",
+			mockedClient:   mock.NewMockedHTTPClient(),
+			requestArgs:    map[string]any{"org": "corp", "since": "2026-10-07", "until": "2026-10-01"},
+			expectError:    true,
+			expectedErrMsg: "until must not be before since",
+		},
+		{
+			name: "metrics access disabled",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.GetOrgsCopilotMetricsByOrg,
+					mockResponse(t, http.StatusUnprocessableEntity, map[string]string{"message": "Copilot Usage Metrics API setting is disabled at the organization or enterprise level."}),
+				),
+			),
+			requestArgs:    map[string]any{"org": "corp"},
+			expectError:    true,
+			expectedErrMsg: "failed to get Copilot metrics",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, handler := GetCopilotMetrics(stubGetClientFn(github.NewClient(tc.mockedClient)), translations.NullTranslationHelper)
+			request := createMCPRequest(tc.requestArgs)
+			result, _, err := handler(context.Background(), &request, tc.requestArgs)
+			require.NoError(t, err)
+
+			if tc.expectError {
+				assert.Contains(t, getErrorResult(t, result).Text, tc.expectedErrMsg)
+				return
+			}
+
+			require.False(t, result.IsError, getTextResult(t, result).Text)
+			var report CopilotMetricsReport
+			require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &report))
+			assert.Equal(t, "corp", report.Org)
+			assert.Equal(t, tc.expectedTeam, report.Team)
+			assert.Equal(t, "2026-10-01", report.Since)
+			assert.Equal(t, 110, report.Completions.Suggestions)
+			assert.Nil(t, report.Daily)
+		})
+	}
+}
+
+func Test_GetCopilotSeatUsage(t *testing.T) {
+	tool, _ := GetCopilotSeatUsage(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.Equal(t, "get_copilot_seat_usage", tool.Name)
+	assert.True(t, tool.Annotations.ReadOnlyHint)
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatch(
+			mock.GetOrgsCopilotBillingByOrg,
+			&github.CopilotOrganizationDetails{
+				SeatBreakdown:         &github.CopilotSeatBreakdown{Total: 2, ActiveThisCycle: 1, InactiveThisCycle: 1},
+				SeatManagementSetting: "assign_selected",
+				CopilotChat:           "enabled",
+				PublicCodeSuggestions: "block",
+			},
+		),
+		mock.WithRequestMatchHandler(
+			mock.GetOrgsCopilotBillingSeatsByOrg,
+			expectQueryParams(t, map[string]string{"page": "2", "per_page": "1"}).andThen(
+				http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+					_, _ = w.Write([]byte(`{"total_seats": 2, "seats": [{
+						"assignee": {"login": "octocat", "type": "User"},
+						"assigning_team": {"slug": "platform"},
+						"plan_type": "business",
+						"last_activity_at": "2026-10-01T12:00:00Z",
+						"last_activity_editor": "vscode/1.95.0",
+						"created_at": "2026-01-01T00:00:00Z"
+					}]}`))
+				}),
+			),
+		),
+	)
+
+	_, handler := GetCopilotSeatUsage(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+	args := map[string]any{"org": "corp", "page": float64(2), "perPage": float64(1)}
+	request := createMCPRequest(args)
+	result, _, err := handler(context.Background(), &request, args)
+	require.NoError(t, err)
+	require.False(t, result.IsError, getTextResult(t, result).Text)
+
+	var usage CopilotSeatUsage
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &usage))
+	assert.Equal(t, 1, usage.SeatBreakdown.InactiveThisCycle)
+	assert.Equal(t, "assign_selected", usage.SeatManagementSetting)
+	assert.Equal(t, int64(2), usage.TotalSeats)
+	require.Len(t, usage.Seats, 1)
+	assert.Equal(t, "octocat", usage.Seats[0].Assignee)
+	assert.Equal(t, "User", usage.Seats[0].AssigneeType)
+	assert.Equal(t, "platform", usage.Seats[0].AssigningTeam)
+	assert.Equal(t, "vscode/1.95.0", usage.Seats[0].LastEditor)
+}
+
+func Test_AddCopilotSeats(t *testing.T) {
+	tool, _ := AddCopilotSeats(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.False(t, tool.Annotations.ReadOnlyHint)
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.PostOrgsCopilotBillingSelectedUsersByOrg,
+			expectRequestBody(t, map[string]any{"selected_usernames": []any{"octocat", "hubot"}}).andThen(
+				mockResponse(t, http.StatusCreated, &github.SeatAssignments{SeatsCreated: 2}),
+			),
+		),
+		mock.WithRequestMatchHandler(
+			mock.PostOrgsCopilotBillingSelectedTeamsByOrg,
+			expectRequestBody(t, map[string]any{"selected_teams": []any{"platform"}}).andThen(
+				mockResponse(t, http.StatusCreated, &github.SeatAssignments{SeatsCreated: 5}),
+			),
+		),
+	)
+	_, handler := AddCopilotSeats(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+	args := map[string]any{"org": "corp", "usernames": []any{"octocat", "hubot"}, "teams": []any{"platform"}}
+	request := createMCPRequest(args)
+	result, _, err := handler(context.Background(), &request, args)
+	require.NoError(t, err)
+	require.False(t, result.IsError, getTextResult(t, result).Text)
+
+	var response map[string]any
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &response))
+	assert.Equal(t, float64(7), response["seats_created"])
+
+	args = map[string]any{"org": "corp"}
+	request = createMCPRequest(args)
+	result, _, err = handler(context.Background(), &request, args)
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	assert.Contains(t, getErrorResult(t, result).Text, "at least one of usernames or teams is required")
+}
+
+func Test_RemoveCopilotSeats(t *testing.T) {
+	tool, _ := RemoveCopilotSeats(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.True(t, *tool.Annotations.DestructiveHint)
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.DeleteOrgsCopilotBillingSelectedUsersByOrg,
+			expectRequestBody(t, map[string]any{"selected_usernames": []any{"octocat"}}).andThen(
+				mockResponse(t, http.StatusOK, &github.SeatCancellations{SeatsCancelled: 1}),
+			),
+		),
+	)
+	_, handler := RemoveCopilotSeats(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+	args := map[string]any{"org": "corp", "usernames": []any{"octocat"}}
+	request := createMCPRequest(args)
+	result, _, err := handler(context.Background(), &request, args)
+	require.NoError(t, err)
+	require.False(t, result.IsError, getTextResult(t, result).Text)
+
+	var response map[string]any
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &response))
+	assert.Equal(t, float64(1), response["seats_cancelled"])
+}
@@ -180,3 +180,110 @@ func ListDependabotAlerts(getClient GetClientFn, t translations.TranslationHelpe
 
 	return tool, handler
 }
+
+// DismissDependabotAlert creates a tool to dismiss a Dependabot alert, or reopen a dismissed one.
+func DismissDependabotAlert(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "dismiss_dependabot_alert",
+		Description: t("TOOL_DISMISS_DEPENDABOT_ALERT_DESCRIPTION", "Dismiss a Dependabot alert with a reason, or reopen a dismissed alert."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_DISMISS_DEPENDABOT_ALERT_USER_TITLE", "Dismiss dependabot alert"),
+			ReadOnlyHint: false,
+		},
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"owner": {
+					Type:        "string",
+					Description: "The owner of the repository.",
+				},
+				"repo": {
+					Type:        "string",
+					Description: "The name of the repository.",
+				},
+				"alertNumber": {
+					Type:        "number",
+					Description: "The number of the alert.",
+				},
+				"reason": {
+					Type:        "string",
+					Description: "Why the alert is dismissed. Required unless reopen is true.",
+					Enum:        []any{"fix_started", "inaccurate", "no_bandwidth", "not_used", "tolerable_risk"},
+				},
+				"comment": {
+					Type:        "string",
+					Description: "Comment explaining the dismissal.",
+				},
+				"reopen": {
+					Type:        "boolean",
+					Description: "Reopen the alert instead of dismissing it.",
+				},
+			},
+			Required: []string{"owner", "repo", "alertNumber"},
+		},
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		repo, err := RequiredParam[string](args, "repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		alertNumber, err := RequiredInt(args, "alertNumber")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		reason, err := OptionalParam[string](args, "reason")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		comment, err := OptionalParam[string](args, "comment")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		reopen, err := OptionalParam[bool](args, "reopen")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		state := &github.DependabotAlertState{State: "open"}
+		switch {
+		case reopen && (reason != "" || comment != ""):
+			return utils.NewToolResultError("reason and comment cannot be set when reopening an alert"), nil, nil
+		case !reopen && reason == "":
+			return utils.NewToolResultError("missing required parameter: reason"), nil, nil
+		case !reopen:
+			state = &github.DependabotAlertState{State: "dismissed", DismissedReason: github.Ptr(reason)}
+			if comment != "" {
+				state.DismissedComment = github.Ptr(comment)
+			}
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return utils.NewToolResultErrorFromErr("failed to get GitHub client", err), nil, err
+		}
+
+		alert, resp, err := client.Dependabot.UpdateAlert(ctx, owner, repo, alertNumber, state)
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx,
+				fmt.Sprintf("failed to update alert with number '%d'", alertNumber),
+				resp,
+				err,
+			), nil, nil
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		r, err := json.Marshal(alert)
+		if err != nil {
+			return utils.NewToolResultErrorFromErr("failed to marshal alert", err), nil, err
+		}
+
+		return utils.NewToolResultText(string(r)), nil, nil
+	})
+
+	return tool, handler
+}
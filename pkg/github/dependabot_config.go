@@ -0,0 +1,481 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	ghErrors "github.com/github/github-mcp-server/pkg/errors"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/github/github-mcp-server/pkg/utils"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.yaml.in/yaml/v3"
+)
+
+// dependabotConfigPaths are the locations Dependabot reads its configuration from, preferred first
+var dependabotConfigPaths = []string{".github/dependabot.yml", ".github/dependabot.yaml"}
+
+// dependabotManifests maps manifest file names to the Dependabot package ecosystem they belong to
+var dependabotManifests = map[string]string{
+	"go.mod":           "gomod",
+	"package.json":     "npm",
+	"requirements.txt": "pip",
+	"pyproject.toml":   "pip",
+	"Pipfile":          "pip",
+	"setup.py":         "pip",
+	"Gemfile":          "bundler",
+	"Cargo.toml":       "cargo",
+	"pom.xml":          "maven",
+	"build.gradle":     "gradle",
+	"build.gradle.kts": "gradle",
+	"composer.json":    "composer",
+	"Dockerfile":       "docker",
+	"mix.exs":          "mix",
+	"pubspec.yaml":     "pub",
+	"Package.swift":    "swift",
+	"packages.config":  "nuget",
+}
+
+// dependabotManifestSuffixes maps manifest file extensions to their package ecosystem
+var dependabotManifestSuffixes = map[string]string{
+	".csproj": "nuget",
+	".fsproj": "nuget",
+	".vbproj": "nuget",
+	".tf":     "terraform",
+}
+
+// dependabotSkippedDirs are directories whose manifests belong to vendored or test code
+var dependabotSkippedDirs = map[string]bool{
+	"node_modules": true,
+	"vendor":       true,
+	"testdata":     true,
+	"third_party":  true,
+}
+
+// dependabotEcosystems are the package-ecosystem values Dependabot accepts
+var dependabotEcosystems = map[string]bool{
+	"bun": true, "bundler": true, "cargo": true, "composer": true, "devcontainers": true,
+	"docker": true, "docker-compose": true, "dotnet-sdk": true, "elm": true, "github-actions": true,
+	"gitsubmodule": true, "gomod": true, "gradle": true, "helm": true, "maven": true, "mix": true,
+	"npm": true, "nuget": true, "pip": true, "pub": true, "swift": true, "terraform": true, "uv": true,
+}
+
+// dependabotIntervals are the schedule intervals Dependabot accepts
+var dependabotIntervals = map[string]bool{
+	"daily": true, "weekly": true, "monthly": true, "quarterly": true, "semiannually": true, "yearly": true, "cron": true,
+}
+
+// dependabotWeekdays are the days a weekly schedule can run on
+var dependabotWeekdays = map[string]bool{
+	"monday": true, "tuesday": true, "wednesday": true, "thursday": true, "friday": true, "saturday": true, "sunday": true,
+}
+
+// dependabotConfig is the part of a dependabot.yml file that is generated and validated.
+type dependabotConfig struct {
+	Version int                `yaml:"version"`
+	Updates []dependabotUpdate `yaml:"updates"`
+}
+
+type dependabotUpdate struct {
+	PackageEcosystem      string             `yaml:"package-ecosystem"`
+	Directory             string             `yaml:"directory,omitempty"`
+	Directories           []string           `yaml:"directories,omitempty"`
+	TargetBranch          string             `yaml:"target-branch,omitempty"`
+	Schedule              dependabotSchedule `yaml:"schedule"`
+	OpenPullRequestsLimit *int               `yaml:"open-pull-requests-limit,omitempty"`
+}
+
+type dependabotSchedule struct {
+	Interval string `yaml:"interval"`
+	Day      string `yaml:"day,omitempty"`
+	Cronjob  string `yaml:"cronjob,omitempty"`
+}
+
+// DetectedEcosystem lists the directories holding manifests of one package ecosystem.
+type DetectedEcosystem struct {
+	Ecosystem   string   `json:"ecosystem"`
+	Directories []string `json:"directories"`
+}
+
+// GenerateDependabotConfigResult reports the generated Dependabot configuration.
+type GenerateDependabotConfigResult struct {
+	Branch     string              `json:"branch"`
+	Path       string              `json:"path"`
+	Config     string              `json:"config"`
+	Ecosystems []DetectedEcosystem `json:"ecosystems"`
+	// ExistingConfigErrors lists the problems of the configuration already in the repository
+	ExistingConfigErrors []string `json:"existing_config_errors,omitempty"`
+	// Uncovered lists the detected ecosystem directories the existing configuration does not update
+	Uncovered []string `json:"uncovered_by_existing_config,omitempty"`
+	CommitSHA string   `json:"commit_sha,omitempty"`
+	// DryRun is set when the configuration was generated but not pushed
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// detectDependabotEcosystems returns the package ecosystems of the manifests among paths, with
+// the directories holding them, sorted by ecosystem.
+func detectDependabotEcosystems(paths []string) []DetectedEcosystem {
+	directories := make(map[string]map[string]bool)
+	add := func(ecosystem, dir string) {
+		if directories[ecosystem] == nil {
+			directories[ecosystem] = make(map[string]bool)
+		}
+		directories[ecosystem][dir] = true
+	}
+
+	for _, p := range paths {
+		dir, name := path.Split(p)
+		dir = strings.TrimSuffix(dir, "/")
+		if dir == ".github/workflows" && (strings.HasSuffix(name, ".yml") || strings.HasSuffix(name, ".yaml")) {
+			add("github-actions", "/")
+			continue
+		}
+		skipped := false
+		for _, segment := range strings.Split(dir, "/") {
+			if dependabotSkippedDirs[segment] {
+				skipped = true
+				break
+			}
+		}
+		if skipped {
+			continue
+		}
+
+		ecosystem, ok := dependabotManifests[name]
+		if !ok {
+			ecosystem, ok = dependabotManifestSuffixes[path.Ext(name)]
+		}
+		if ok {
+			add(ecosystem, "/"+dir)
+		}
+	}
+
+	detected := make([]DetectedEcosystem, 0, len(directories))
+	for ecosystem, dirs := range directories {
+		entry := DetectedEcosystem{Ecosystem: ecosystem}
+		for dir := range dirs {
+			entry.Directories = append(entry.Directories, dir)
+		}
+		sort.Strings(entry.Directories)
+		detected = append(detected, entry)
+	}
+	sort.Slice(detected, func(i, j int) bool { return detected[i].Ecosystem < detected[j].Ecosystem })
+	return detected
+}
+
+// buildDependabotConfig returns a configuration updating every detected ecosystem directory on
+// the given schedule. Weekly updates run on Mondays.
+func buildDependabotConfig(detected []DetectedEcosystem, interval string, openPullRequestsLimit *int) dependabotConfig {
+	config := dependabotConfig{Version: 2}
+	schedule := dependabotSchedule{Interval: interval}
+	if interval == "weekly" {
+		schedule.Day = "monday"
+	}
+	for _, ecosystem := range detected {
+		for _, dir := range ecosystem.Directories {
+			config.Updates = append(config.Updates, dependabotUpdate{
+				PackageEcosystem:      ecosystem.Ecosystem,
+				Directory:             dir,
+				Schedule:              schedule,
+				OpenPullRequestsLimit: openPullRequestsLimit,
+			})
+		}
+	}
+	return config
+}
+
+// validateDependabotConfig parses a dependabot.yml file and returns the problems Dependabot
+// would reject it for. Settings that are not generated by this package are not checked.
+func validateDependabotConfig(content []byte) (dependabotConfig, []string) {
+	var config dependabotConfig
+	if err := yaml.Unmarshal(content, &config); err != nil {
+		return config, []string{fmt.Sprintf("invalid YAML: %s", err)}
+	}
+
+	var problems []string
+	if config.Version != 2 {
+		problems = append(problems, fmt.Sprintf("version must be 2, got %d", config.Version))
+	}
+	if len(config.Updates) == 0 {
+		problems = append(problems, "updates must list at least one package ecosystem")
+	}
+	seen := make(map[string]bool)
+	for i, update := range config.Updates {
+		where := fmt.Sprintf("updates[%d]", i)
+		if !dependabotEcosystems[update.PackageEcosystem] {
+			problems = append(problems, fmt.Sprintf("%s: unknown package-ecosystem %q", where, update.PackageEcosystem))
+		}
+		dirs := update.Directories
+		if update.Directory != "" {
+			dirs = append(dirs, update.Directory)
+		}
+		switch {
+		case len(dirs) == 0:
+			problems = append(problems, fmt.Sprintf("%s: directory or directories is required", where))
+		case update.Directory != "" && len(update.Directories) > 0:
+			problems = append(problems, fmt.Sprintf("%s: directory and directories cannot both be set", where))
+		}
+		for _, dir := range dirs {
+			if !strings.HasPrefix(dir, "/") {
+				problems = append(problems, fmt.Sprintf("%s: directory %q must start with /", where, dir))
+			}
+			key := update.PackageEcosystem + " " + dir + " " + update.TargetBranch
+			if seen[key] {
+				problems = append(problems, fmt.Sprintf("%s: %s updates of %s are configured more than once", where, update.PackageEcosystem, dir))
+			}
+			seen[key] = true
+		}
+
+		schedule := update.Schedule
+		switch {
+		case schedule.Interval == "":
+			problems = append(problems, fmt.Sprintf("%s: schedule.interval is required", where))
+		case !dependabotIntervals[schedule.Interval]:
+			problems = append(problems, fmt.Sprintf("%s: unknown schedule.interval %q", where, schedule.Interval))
+		case schedule.Interval == "cron" && schedule.Cronjob == "":
+			problems = append(problems, fmt.Sprintf("%s: schedule.cronjob is required for the cron interval", where))
+		}
+		if schedule.Day != "" && !dependabotWeekdays[strings.ToLower(schedule.Day)] {
+			problems = append(problems, fmt.Sprintf("%s: unknown schedule.day %q", where, schedule.Day))
+		}
+		if update.OpenPullRequestsLimit != nil && *update.OpenPullRequestsLimit < 0 {
+			problems = append(problems, fmt.Sprintf("%s: open-pull-requests-limit must not be negative", where))
+		}
+	}
+	return config, problems
+}
+
+// uncoveredEcosystems returns the detected "ecosystem directory" pairs config does not update.
+func uncoveredEcosystems(config dependabotConfig, detected []DetectedEcosystem) []string {
+	covered := make(map[string]bool)
+	for _, update := range config.Updates {
+		dirs := update.Directories
+		if update.Directory != "" {
+			dirs = append(dirs, update.Directory)
+		}
+		for _, dir := range dirs {
+			covered[update.PackageEcosystem+" "+path.Clean(dir)] = true
+		}
+	}
+	var uncovered []string
+	for _, ecosystem := range detected {
+		for _, dir := range ecosystem.Directories {
+			if !covered[ecosystem.Ecosystem+" "+dir] {
+				uncovered = append(uncovered, ecosystem.Ecosystem+" "+dir)
+			}
+		}
+	}
+	return uncovered
+}
+
+// GenerateDependabotConfig creates a tool to generate and push a Dependabot configuration.
+func GenerateDependabotConfig(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "generate_dependabot_config",
+		Description: t("TOOL_GENERATE_DEPENDABOT_CONFIG_DESCRIPTION", "Generate a .github/dependabot.yml for a repository from the package manifests found on a branch, covering every detected ecosystem and directory, validate it and commit it to the branch. An existing configuration is validated and compared with the detected ecosystems, and is only replaced when overwrite is set."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_GENERATE_DEPENDABOT_CONFIG_USER_TITLE", "Generate Dependabot configuration"),
+			ReadOnlyHint: false,
+		},
+		InputSchema: withCommitIdentity(&jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"owner": {
+					Type:        "string",
+					Description: "Repository owner",
+				},
+				"repo": {
+					Type:        "string",
+					Description: "Repository name",
+				},
+				"branch": {
+					Type:        "string",
+					Description: "Branch to inspect and commit the configuration to. Defaults to the repository's default branch",
+				},
+				"schedule_interval": {
+					Type:        "string",
+					Description: "How often Dependabot checks for updates. Weekly updates run on Mondays",
+					Enum:        []any{"daily", "weekly", "monthly"},
+					Default:     json.RawMessage(`"weekly"`),
+				},
+				"open_pull_requests_limit": {
+					Type:        "integer",
+					Description: "Maximum number of open version update pull requests per ecosystem directory. Dependabot's default is 5",
+					Minimum:     jsonschema.Ptr(0.0),
+				},
+				"overwrite": {
+					Type:        "boolean",
+					Description: "Replace an existing Dependabot configuration (default: false)",
+				},
+				"dry_run": {
+					Type:        "boolean",
+					Description: "Generate and validate the configuration without committing it",
+				},
+				"message": {
+					Type:        "string",
+					Description: "Commit message (default: \"Add Dependabot configuration\")",
+				},
+			},
+			Required: []string{"owner", "repo"},
+		}),
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		repo, err := RequiredParam[string](args, "repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		branch, err := OptionalParam[string](args, "branch")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		interval, err := OptionalParam[string](args, "schedule_interval")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		if interval == "" {
+			interval = "weekly"
+		}
+		if interval != "daily" && interval != "weekly" && interval != "monthly" {
+			return utils.NewToolResultError("schedule_interval must be daily, weekly or monthly"), nil, nil
+		}
+		var openPullRequestsLimit *int
+		if _, ok := args["open_pull_requests_limit"]; ok {
+			limit, err := RequiredInt(args, "open_pull_requests_limit")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			if limit < 0 {
+				return utils.NewToolResultError("open_pull_requests_limit must not be negative"), nil, nil
+			}
+			openPullRequestsLimit = &limit
+		}
+		overwrite, err := OptionalParam[bool](args, "overwrite")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		dryRun, err := OptionalParam[bool](args, "dry_run")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		message, err := OptionalParam[string](args, "message")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		if message == "" {
+			message = "Add Dependabot configuration"
+		}
+		identity, err := commitIdentityFromArgs(ctx, args)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		if branch == "" {
+			repository, resp, err := client.Repositories.Get(ctx, owner, repo)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to get repository", resp, err), nil, nil
+			}
+			_ = resp.Body.Close()
+			branch = repository.GetDefaultBranch()
+		}
+
+		tree, resp, err := client.Git.GetTree(ctx, owner, repo, branch, true)
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to get tree", resp, err), nil, nil
+		}
+		_ = resp.Body.Close()
+		if tree.GetTruncated() {
+			return utils.NewToolResultError(fmt.Sprintf("tree of %s/%s@%s is too large to list recursively", owner, repo, branch)), nil, nil
+		}
+
+		var paths []string
+		existing := make(map[string]string)
+		for _, entry := range tree.Entries {
+			if entry.GetType() != "blob" {
+				continue
+			}
+			paths = append(paths, entry.GetPath())
+			for _, configPath := range dependabotConfigPaths {
+				if entry.GetPath() == configPath {
+					existing[configPath] = entry.GetSHA()
+				}
+			}
+		}
+
+		detected := detectDependabotEcosystems(paths)
+		if len(detected) == 0 {
+			return utils.NewToolResultError(fmt.Sprintf("no package manifests supported by Dependabot found in %s/%s@%s", owner, repo, branch)), nil, nil
+		}
+
+		var content bytes.Buffer
+		enc := yaml.NewEncoder(&content)
+		enc.SetIndent(2)
+		if err := enc.Encode(buildDependabotConfig(detected, interval, openPullRequestsLimit)); err != nil {
+			return nil, nil, fmt.Errorf("failed to encode Dependabot configuration: %w", err)
+		}
+		if _, problems := validateDependabotConfig(content.Bytes()); len(problems) > 0 {
+			return nil, nil, fmt.Errorf("generated Dependabot configuration is invalid: %s", strings.Join(problems, "; "))
+		}
+
+		result := GenerateDependabotConfigResult{
+			Branch:     branch,
+			Path:       dependabotConfigPaths[0],
+			Config:     content.String(),
+			Ecosystems: detected,
+			DryRun:     dryRun,
+		}
+		for _, configPath := range dependabotConfigPaths {
+			sha, ok := existing[configPath]
+			if !ok {
+				continue
+			}
+			current, resp, err := client.Git.GetBlobRaw(ctx, owner, repo, sha)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx, fmt.Sprintf("failed to read %s", configPath), resp, err), nil, nil
+			}
+			_ = resp.Body.Close()
+			config, problems := validateDependabotConfig(current)
+			result.Path = configPath
+			result.ExistingConfigErrors = problems
+			result.Uncovered = uncoveredEcosystems(config, detected)
+			break
+		}
+
+		if dryRun {
+			return MarshalledTextResult(result), nil, nil
+		}
+		if len(existing) > 0 && !overwrite {
+			return utils.NewToolResultError(fmt.Sprintf(
+				"%s already exists; set overwrite to replace it, or dry_run to compare it with the generated configuration",
+				result.Path,
+			)), nil, nil
+		}
+
+		ctx, _ = withRetryBudget(ctx)
+		commit, err := pushChunk(ctx, client, owner, repo, "refs/heads/"+branch, []FileEntry{{Path: result.Path, Content: result.Config}}, message, identity, chunkRetryConfig, false)
+		if err != nil {
+			return utils.NewToolResultError(fmt.Sprintf("failed to commit %s to branch %s: %s", result.Path, branch, err)), nil, nil
+		}
+		if !commit.NoChanges {
+			result.CommitSHA = commit.SHA
+		}
+
+		return MarshalledTextResult(result), nil, nil
+	})
+
+	return tool, handler
+}
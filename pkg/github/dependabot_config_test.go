@@ -0,0 +1,220 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v79/github"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_detectDependabotEcosystems(t *testing.T) {
+	detected := detectDependabotEcosystems([]string{
+		"go.mod",
+		"web/package.json",
+		"web/node_modules/left-pad/package.json",
+		"vendor/example.com/lib/go.mod",
+		"services/api/Dockerfile",
+		"infra/main.tf",
+		"infra/variables.tf",
+		".github/workflows/ci.yml",
+		".github/workflows/release.yaml",
+		"README.md",
+	})
+	assert.Equal(t, []DetectedEcosystem{
+		{Ecosystem: "docker", Directories: []string{"/services/api"}},
+		{Ecosystem: "github-actions", Directories: []string{"/"}},
+		{Ecosystem: "gomod", Directories: []string{"/"}},
+		{Ecosystem: "npm", Directories: []string{"/web"}},
+		{Ecosystem: "terraform", Directories: []string{"/infra"}},
+	}, detected)
+}
+
+func Test_validateDependabotConfig(t *testing.T) {
+	_, problems := validateDependabotConfig([]byte("version: 2\nupdates:\n  - package-ecosystem: gomod\n    directory: /\n    schedule:\n      interval: weekly\n      day: monday\n"))
+	assert.Empty(t, problems)
+
+	_, problems = validateDependabotConfig([]byte(`version: 1
+updates:
+  - package-ecosystem: golang
+    directory: api
+    schedule:
+      interval: hourly
+  - package-ecosystem: npm
+    directory: /
+    schedule:
+      interval: weekly
+      day: someday
+  - package-ecosystem: npm
+    directory: /
+    schedule:
+      interval: cron
+`))
+	assert.Equal(t, []string{
+		"version must be 2, got 1",
+		`updates[0]: unknown package-ecosystem "golang"`,
+		`updates[0]: directory "api" must start with /`,
+		`updates[0]: unknown schedule.interval "hourly"`,
+		`updates[1]: unknown schedule.day "someday"`,
+		"updates[2]: npm updates of / are configured more than once",
+		"updates[2]: schedule.cronjob is required for the cron interval",
+	}, problems)
+
+	_, problems = validateDependabotConfig([]byte("version: [2"))
+	require.Len(t, problems, 1)
+	assert.True(t, strings.HasPrefix(problems[0], "invalid YAML"))
+}
+
+func Test_GenerateDependabotConfig(t *testing.T) {
+	mockClient := github.NewClient(nil)
+	tool, _ := GenerateDependabotConfig(stubGetClientFn(mockClient), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	schema, ok := tool.InputSchema.(*jsonschema.Schema)
+	require.True(t, ok, "InputSchema should be *jsonschema.Schema")
+
+	assert.Equal(t, "generate_dependabot_config", tool.Name)
+	assert.Contains(t, schema.Properties, "schedule_interval")
+	assert.Contains(t, schema.Properties, "overwrite")
+	assert.ElementsMatch(t, schema.Required, []string{"owner", "repo"})
+
+	expectedConfig := `version: 2
+updates:
+  - package-ecosystem: github-actions
+    directory: /
+    schedule:
+      interval: weekly
+      day: monday
+  - package-ecosystem: gomod
+    directory: /
+    schedule:
+      interval: weekly
+      day: monday
+`
+	branchTree := func(paths ...string) mock.MockBackendOption {
+		var entries []*github.TreeEntry
+		for _, p := range paths {
+			entries = append(entries, &github.TreeEntry{Path: github.Ptr(p), Type: github.Ptr("blob"), Mode: github.Ptr("100644"), SHA: github.Ptr(strings.NewReplacer("/", "-", ".", "-").Replace(p) + "-sha")})
+		}
+		return mock.WithRequestMatchHandler(
+			mock.GetReposGitTreesByOwnerByRepoByTreeSha,
+			expectPath(t, "/repos/owner/repo/git/trees/main").andThen(
+				mockResponse(t, http.StatusOK, &github.Tree{Entries: entries}),
+			),
+		)
+	}
+	existingConfig := mock.WithRequestMatchHandler(
+		mock.GetReposGitBlobsByOwnerByRepoByFileSha,
+		expectPath(t, "/repos/owner/repo/git/blobs/-github-dependabot-yml-sha").andThen(
+			http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte("version: 2\nupdates:\n  - package-ecosystem: gomod\n    directory: /\n    schedule:\n      interval: monthly\n"))
+			}),
+		),
+	)
+
+	tests := []struct {
+		name           string
+		mockedClient   *http.Client
+		args           map[string]interface{}
+		expectError    bool
+		expectedErrMsg string
+		validate       func(t *testing.T, result GenerateDependabotConfigResult)
+	}{
+		{
+			name: "commits the generated configuration",
+			mockedClient: mock.NewMockedHTTPClient(
+				branchTree("go.mod", ".github/workflows/ci.yml"),
+				mockGitDataAPI(t)[0],
+				mockGitDataAPI(t)[1],
+				mock.WithRequestMatchHandler(
+					mock.PostReposGitTreesByOwnerByRepo,
+					http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						var body struct {
+							Tree []map[string]any `json:"tree"`
+						}
+						assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+						if assert.Len(t, body.Tree, 1) {
+							assert.Equal(t, ".github/dependabot.yml", body.Tree[0]["path"])
+							assert.Equal(t, expectedConfig, body.Tree[0]["content"])
+						}
+						mockResponse(t, http.StatusCreated, &github.Tree{SHA: github.Ptr("new-tree-sha")})(w, r)
+					}),
+				),
+				mockGitDataAPI(t)[3],
+				mockGitDataAPI(t)[4],
+			),
+			validate: func(t *testing.T, result GenerateDependabotConfigResult) {
+				assert.Equal(t, "new-commit-sha", result.CommitSHA)
+				assert.Equal(t, expectedConfig, result.Config)
+			},
+		},
+		{
+			name: "dry run compares the existing configuration",
+			mockedClient: mock.NewMockedHTTPClient(
+				branchTree("go.mod", ".github/workflows/ci.yml", ".github/dependabot.yml"),
+				existingConfig,
+			),
+			args: map[string]interface{}{"dry_run": true},
+			validate: func(t *testing.T, result GenerateDependabotConfigResult) {
+				assert.True(t, result.DryRun)
+				assert.Empty(t, result.CommitSHA)
+				assert.Empty(t, result.ExistingConfigErrors)
+				assert.Equal(t, []string{"github-actions /"}, result.Uncovered)
+			},
+		},
+		{
+			name: "existing configuration is not replaced without overwrite",
+			mockedClient: mock.NewMockedHTTPClient(
+				branchTree("go.mod", ".github/dependabot.yml"),
+				existingConfig,
+			),
+			expectError:    true,
+			expectedErrMsg: ".github/dependabot.yml already exists",
+		},
+		{
+			name:           "no manifests",
+			mockedClient:   mock.NewMockedHTTPClient(branchTree("README.md")),
+			expectError:    true,
+			expectedErrMsg: "no package manifests",
+		},
+		{
+			name:           "unsupported interval",
+			mockedClient:   mock.NewMockedHTTPClient(),
+			args:           map[string]interface{}{"schedule_interval": "hourly"},
+			expectError:    true,
+			expectedErrMsg: "schedule_interval must be",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, handler := GenerateDependabotConfig(stubGetClientFn(github.NewClient(tc.mockedClient)), translations.NullTranslationHelper)
+
+			args := map[string]interface{}{"owner": "owner", "repo": "repo", "branch": "main"}
+			for k, v := range tc.args {
+				args[k] = v
+			}
+			request := createMCPRequest(args)
+			result, _, err := handler(context.Background(), &request, args)
+			require.NoError(t, err)
+
+			if tc.expectError {
+				assert.Contains(t, getErrorResult(t, result).Text, tc.expectedErrMsg)
+				return
+			}
+
+			require.False(t, result.IsError, getTextResult(t, result).Text)
+			var generated GenerateDependabotConfigResult
+			require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &generated))
+			tc.validate(t, generated)
+		})
+	}
+}
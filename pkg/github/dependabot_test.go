@@ -267,3 +267,37 @@ func Test_ListDependabotAlerts(t *testing.T) {
 		})
 	}
 }
+
+func Test_DismissDependabotAlert(t *testing.T) {
+	tool, _ := DismissDependabotAlert(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.PatchReposDependabotAlertsByOwnerByRepoByAlertNumber,
+			expectPath(t, "/repos/owner/repo/dependabot/alerts/7").andThen(
+				expectRequestBody(t, map[string]any{"state": "dismissed", "dismissed_reason": "not_used"}).andThen(
+					mockResponse(t, http.StatusOK, &github.DependabotAlert{Number: github.Ptr(7), State: github.Ptr("dismissed")}),
+				),
+			),
+		),
+	)
+	_, handler := DismissDependabotAlert(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+	args := map[string]any{"owner": "owner", "repo": "repo", "alertNumber": float64(7), "reason": "not_used"}
+	request := createMCPRequest(args)
+	result, _, err := handler(context.Background(), &request, args)
+	require.NoError(t, err)
+	require.False(t, result.IsError, getTextResult(t, result).Text)
+
+	var returned github.DependabotAlert
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &returned))
+	assert.Equal(t, "dismissed", returned.GetState())
+
+	args = map[string]any{"owner": "owner", "repo": "repo", "alertNumber": float64(7)}
+	request = createMCPRequest(args)
+	result, _, err = handler(context.Background(), &request, args)
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	assert.Contains(t, getErrorResult(t, result).Text, "missing required parameter: reason")
+}
@@ -0,0 +1,255 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	ghErrors "github.com/github/github-mcp-server/pkg/errors"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/github/github-mcp-server/pkg/utils"
+	"github.com/google/go-github/v79/github"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// MinimalDeployKey is the output type for repository deploy keys.
+type MinimalDeployKey struct {
+	ID        int64  `json:"id"`
+	Title     string `json:"title"`
+	Key       string `json:"key"`
+	ReadOnly  bool   `json:"read_only"`
+	Verified  bool   `json:"verified"`
+	AddedBy   string `json:"added_by,omitempty"`
+	CreatedAt string `json:"created_at,omitempty"`
+	LastUsed  string `json:"last_used,omitempty"`
+}
+
+func convertToMinimalDeployKey(key *github.Key) MinimalDeployKey {
+	m := MinimalDeployKey{
+		ID:       key.GetID(),
+		Title:    key.GetTitle(),
+		Key:      key.GetKey(),
+		ReadOnly: key.GetReadOnly(),
+		Verified: key.GetVerified(),
+		AddedBy:  key.GetAddedBy(),
+	}
+	if key.CreatedAt != nil {
+		m.CreatedAt = key.CreatedAt.Format(time.RFC3339)
+	}
+	if key.LastUsed != nil {
+		m.LastUsed = key.LastUsed.Format(time.RFC3339)
+	}
+	return m
+}
+
+// ListDeployKeys creates a tool to list the deploy keys of a repository.
+func ListDeployKeys(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "list_deploy_keys",
+		Description: t("TOOL_LIST_DEPLOY_KEYS_DESCRIPTION", "List the deploy keys of a GitHub repository, including whether each key has read-only or read-write access. Requires admin access to the repository."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_LIST_DEPLOY_KEYS_USER_TITLE", "List deploy keys"),
+			ReadOnlyHint: true,
+		},
+		InputSchema: WithPagination(&jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"owner": {
+					Type:        "string",
+					Description: "Repository owner",
+				},
+				"repo": {
+					Type:        "string",
+					Description: "Repository name",
+				},
+			},
+			Required: []string{"owner", "repo"},
+		}),
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		repo, err := RequiredParam[string](args, "repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		pagination, err := OptionalPaginationParams(args)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		keys, resp, err := client.Repositories.ListKeys(ctx, owner, repo, &github.ListOptions{
+			Page:    pagination.Page,
+			PerPage: pagination.PerPage,
+		})
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to list deploy keys", resp, err), nil, nil
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		minimalKeys := make([]MinimalDeployKey, 0, len(keys))
+		for _, key := range keys {
+			minimalKeys = append(minimalKeys, convertToMinimalDeployKey(key))
+		}
+
+		return MarshalledTextResult(minimalKeys), nil, nil
+	})
+
+	return tool, handler
+}
+
+// CreateDeployKey creates a tool to add a deploy key to a repository.
+func CreateDeployKey(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "create_deploy_key",
+		Description: t("TOOL_CREATE_DEPLOY_KEY_DESCRIPTION", "Add an SSH deploy key to a GitHub repository so that an external system can clone it, or push to it when read_only is false, without a user token. Requires admin access to the repository."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_CREATE_DEPLOY_KEY_USER_TITLE", "Create deploy key"),
+			ReadOnlyHint: false,
+		},
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"owner": {
+					Type:        "string",
+					Description: "Repository owner",
+				},
+				"repo": {
+					Type:        "string",
+					Description: "Repository name",
+				},
+				"title": {
+					Type:        "string",
+					Description: "Name for the key, e.g. the system that will use it",
+				},
+				"key": {
+					Type:        "string",
+					Description: "The public SSH key, e.g. 'ssh-ed25519 AAAA...'",
+				},
+				"read_only": {
+					Type:        "boolean",
+					Description: "Whether the key can only read the repository. Set to false to allow pushes (default: true)",
+					Default:     json.RawMessage("true"),
+				},
+			},
+			Required: []string{"owner", "repo", "title", "key"},
+		},
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		repo, err := RequiredParam[string](args, "repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		title, err := RequiredParam[string](args, "title")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		key, err := RequiredParam[string](args, "key")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		// Default to read-only so that write access is always an explicit choice
+		readOnly := true
+		if _, ok := args["read_only"]; ok {
+			readOnly, err = OptionalParam[bool](args, "read_only")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		created, resp, err := client.Repositories.CreateKey(ctx, owner, repo, &github.Key{
+			Title:    github.Ptr(title),
+			Key:      github.Ptr(key),
+			ReadOnly: github.Ptr(readOnly),
+		})
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to create deploy key", resp, err), nil, nil
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		return MarshalledTextResult(convertToMinimalDeployKey(created)), nil, nil
+	})
+
+	return tool, handler
+}
+
+// DeleteDeployKey creates a tool to remove a deploy key from a repository.
+func DeleteDeployKey(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "delete_deploy_key",
+		Description: t("TOOL_DELETE_DEPLOY_KEY_DESCRIPTION", "Remove a deploy key from a GitHub repository, revoking the access of any system using it. Deploy keys cannot be changed; to rotate one, create the new key before deleting the old one."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:           t("TOOL_DELETE_DEPLOY_KEY_USER_TITLE", "Delete deploy key"),
+			ReadOnlyHint:    false,
+			DestructiveHint: jsonschema.Ptr(true),
+		},
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"owner": {
+					Type:        "string",
+					Description: "Repository owner",
+				},
+				"repo": {
+					Type:        "string",
+					Description: "Repository name",
+				},
+				"key_id": {
+					Type:        "number",
+					Description: "The ID of the deploy key, as returned by list_deploy_keys",
+				},
+			},
+			Required: []string{"owner", "repo", "key_id"},
+		},
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		repo, err := RequiredParam[string](args, "repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		keyID, err := RequiredBigInt(args, "key_id")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		resp, err := client.Repositories.DeleteKey(ctx, owner, repo, keyID)
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to delete deploy key", resp, err), nil, nil
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		return utils.NewToolResultText(fmt.Sprintf("Deploy key %d deleted from %s/%s", keyID, owner, repo)), nil, nil
+	})
+
+	return tool, handler
+}
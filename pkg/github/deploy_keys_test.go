@@ -0,0 +1,201 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v79/github"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ListDeployKeys(t *testing.T) {
+	mockClient := github.NewClient(nil)
+	tool, _ := ListDeployKeys(stubGetClientFn(mockClient), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	schema, ok := tool.InputSchema.(*jsonschema.Schema)
+	require.True(t, ok, "InputSchema should be *jsonschema.Schema")
+	assert.Equal(t, "list_deploy_keys", tool.Name)
+	assert.ElementsMatch(t, schema.Required, []string{"owner", "repo"})
+
+	client := github.NewClient(mock.NewMockedHTTPClient(
+		mock.WithRequestMatch(
+			mock.GetReposKeysByOwnerByRepo,
+			[]*github.Key{
+				{
+					ID:       github.Ptr(int64(1)),
+					Title:    github.Ptr("ci"),
+					Key:      github.Ptr("ssh-ed25519 AAAA"),
+					ReadOnly: github.Ptr(false),
+					Verified: github.Ptr(true),
+					AddedBy:  github.Ptr("octocat"),
+				},
+			},
+		),
+	))
+	_, handler := ListDeployKeys(stubGetClientFn(client), translations.NullTranslationHelper)
+
+	args := map[string]interface{}{"owner": "owner", "repo": "repo"}
+	request := createMCPRequest(args)
+	result, _, err := handler(context.Background(), &request, args)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+
+	var keys []MinimalDeployKey
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &keys))
+	require.Len(t, keys, 1)
+	assert.Equal(t, "ci", keys[0].Title)
+	assert.False(t, keys[0].ReadOnly)
+	assert.Equal(t, "octocat", keys[0].AddedBy)
+}
+
+func Test_CreateDeployKey(t *testing.T) {
+	mockClient := github.NewClient(nil)
+	tool, _ := CreateDeployKey(stubGetClientFn(mockClient), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	schema, ok := tool.InputSchema.(*jsonschema.Schema)
+	require.True(t, ok, "InputSchema should be *jsonschema.Schema")
+	assert.Equal(t, "create_deploy_key", tool.Name)
+	assert.Contains(t, schema.Properties, "read_only")
+	assert.ElementsMatch(t, schema.Required, []string{"owner", "repo", "title", "key"})
+
+	tests := []struct {
+		name             string
+		args             map[string]interface{}
+		status           int
+		expectedReadOnly bool
+		expectError      bool
+		expectedErrMsg   string
+	}{
+		{
+			name:             "read-only by default",
+			args:             map[string]interface{}{"title": "ci", "key": "ssh-ed25519 AAAA"},
+			status:           http.StatusCreated,
+			expectedReadOnly: true,
+		},
+		{
+			name:             "read-write key",
+			args:             map[string]interface{}{"title": "deployer", "key": "ssh-ed25519 BBBB", "read_only": false},
+			status:           http.StatusCreated,
+			expectedReadOnly: false,
+		},
+		{
+			name:           "key already in use",
+			args:           map[string]interface{}{"title": "ci", "key": "ssh-ed25519 AAAA"},
+			status:         http.StatusUnprocessableEntity,
+			expectError:    true,
+			expectedErrMsg: "failed to create deploy key",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var sent github.Key
+			client := github.NewClient(mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.PostReposKeysByOwnerByRepo,
+					http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						assert.NoError(t, json.NewDecoder(r.Body).Decode(&sent))
+						if tc.status != http.StatusCreated {
+							mockResponse(t, tc.status, map[string]string{"message": "key is already in use"})(w, r)
+							return
+						}
+						sent.ID = github.Ptr(int64(7))
+						mockResponse(t, tc.status, &sent)(w, r)
+					}),
+				),
+			))
+			_, handler := CreateDeployKey(stubGetClientFn(client), translations.NullTranslationHelper)
+
+			args := map[string]interface{}{"owner": "owner", "repo": "repo"}
+			for k, v := range tc.args {
+				args[k] = v
+			}
+			request := createMCPRequest(args)
+			result, _, err := handler(context.Background(), &request, args)
+			require.NoError(t, err)
+
+			if tc.expectError {
+				assert.Contains(t, getErrorResult(t, result).Text, tc.expectedErrMsg)
+				return
+			}
+
+			require.False(t, result.IsError)
+			assert.Equal(t, tc.expectedReadOnly, sent.GetReadOnly())
+
+			var key MinimalDeployKey
+			require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &key))
+			assert.Equal(t, int64(7), key.ID)
+			assert.Equal(t, tc.expectedReadOnly, key.ReadOnly)
+		})
+	}
+}
+
+func Test_DeleteDeployKey(t *testing.T) {
+	mockClient := github.NewClient(nil)
+	tool, _ := DeleteDeployKey(stubGetClientFn(mockClient), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	schema, ok := tool.InputSchema.(*jsonschema.Schema)
+	require.True(t, ok, "InputSchema should be *jsonschema.Schema")
+	assert.Equal(t, "delete_deploy_key", tool.Name)
+	assert.ElementsMatch(t, schema.Required, []string{"owner", "repo", "key_id"})
+
+	tests := []struct {
+		name           string
+		mockedClient   *http.Client
+		expectError    bool
+		expectedErrMsg string
+	}{
+		{
+			name: "key deleted",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.DeleteReposKeysByOwnerByRepoByKeyId,
+					http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+						w.WriteHeader(http.StatusNoContent)
+					}),
+				),
+			),
+		},
+		{
+			name: "key not found",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.DeleteReposKeysByOwnerByRepoByKeyId,
+					mockResponse(t, http.StatusNotFound, map[string]string{"message": "Not Found"}),
+				),
+			),
+			expectError:    true,
+			expectedErrMsg: "failed to delete deploy key",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client := github.NewClient(tc.mockedClient)
+			_, handler := DeleteDeployKey(stubGetClientFn(client), translations.NullTranslationHelper)
+
+			args := map[string]interface{}{"owner": "owner", "repo": "repo", "key_id": float64(7)}
+			request := createMCPRequest(args)
+			result, _, err := handler(context.Background(), &request, args)
+			require.NoError(t, err)
+
+			if tc.expectError {
+				assert.Contains(t, getErrorResult(t, result).Text, tc.expectedErrMsg)
+				return
+			}
+
+			require.False(t, result.IsError)
+			assert.Equal(t, "Deploy key 7 deleted from owner/repo", getTextResult(t, result).Text)
+		})
+	}
+}
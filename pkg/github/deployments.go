@@ -0,0 +1,791 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	ghErrors "github.com/github/github-mcp-server/pkg/errors"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/github/github-mcp-server/pkg/utils"
+	"github.com/google/go-github/v79/github"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// MinimalDeployment is the output type for deployments.
+type MinimalDeployment struct {
+	ID          int64  `json:"id"`
+	SHA         string `json:"sha"`
+	Ref         string `json:"ref"`
+	Task        string `json:"task,omitempty"`
+	Environment string `json:"environment"`
+	Description string `json:"description,omitempty"`
+	Creator     string `json:"creator,omitempty"`
+	CreatedAt   string `json:"created_at,omitempty"`
+}
+
+// MinimalDeploymentStatus is the output type for deployment statuses.
+type MinimalDeploymentStatus struct {
+	ID             int64  `json:"id"`
+	DeploymentID   int64  `json:"deployment_id"`
+	State          string `json:"state"`
+	Description    string `json:"description,omitempty"`
+	Environment    string `json:"environment,omitempty"`
+	EnvironmentURL string `json:"environment_url,omitempty"`
+	LogURL         string `json:"log_url,omitempty"`
+	CreatedAt      string `json:"created_at,omitempty"`
+}
+
+// EnvironmentReviewer is a user or team whose approval a deployment to an environment requires.
+type EnvironmentReviewer struct {
+	Type string `json:"type"`
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// EnvironmentProtection is the output type for an environment and its protection rules.
+type EnvironmentProtection struct {
+	Name                   string                `json:"name"`
+	HTMLURL                string                `json:"html_url,omitempty"`
+	WaitTimer              int                   `json:"wait_timer"`
+	Reviewers              []EnvironmentReviewer `json:"reviewers"`
+	PreventSelfReview      bool                  `json:"prevent_self_review"`
+	CanAdminsBypass        bool                  `json:"can_admins_bypass"`
+	DeploymentBranchPolicy string                `json:"deployment_branch_policy"`
+}
+
+func convertToMinimalDeployment(deployment *github.Deployment) MinimalDeployment {
+	m := MinimalDeployment{
+		ID:          deployment.GetID(),
+		SHA:         deployment.GetSHA(),
+		Ref:         deployment.GetRef(),
+		Task:        deployment.GetTask(),
+		Environment: deployment.GetEnvironment(),
+		Description: deployment.GetDescription(),
+		Creator:     deployment.GetCreator().GetLogin(),
+	}
+	if deployment.CreatedAt != nil {
+		m.CreatedAt = deployment.CreatedAt.Format(time.RFC3339)
+	}
+	return m
+}
+
+func convertToEnvironmentProtection(env *github.Environment) EnvironmentProtection {
+	p := EnvironmentProtection{
+		Name:                   env.GetName(),
+		HTMLURL:                env.GetHTMLURL(),
+		Reviewers:              []EnvironmentReviewer{},
+		CanAdminsBypass:        env.GetCanAdminsBypass(),
+		DeploymentBranchPolicy: "all",
+	}
+	if policy := env.DeploymentBranchPolicy; policy != nil {
+		switch {
+		case policy.GetProtectedBranches():
+			p.DeploymentBranchPolicy = "protected"
+		case policy.GetCustomBranchPolicies():
+			p.DeploymentBranchPolicy = "custom"
+		}
+	}
+	for _, rule := range env.ProtectionRules {
+		switch rule.GetType() {
+		case "wait_timer":
+			p.WaitTimer = rule.GetWaitTimer()
+		case "required_reviewers":
+			p.PreventSelfReview = rule.GetPreventSelfReview()
+			for _, reviewer := range rule.Reviewers {
+				switch r := reviewer.Reviewer.(type) {
+				case *github.User:
+					p.Reviewers = append(p.Reviewers, EnvironmentReviewer{Type: "User", ID: r.GetID(), Name: r.GetLogin()})
+				case *github.Team:
+					p.Reviewers = append(p.Reviewers, EnvironmentReviewer{Type: "Team", ID: r.GetID(), Name: r.GetSlug()})
+				}
+			}
+		}
+	}
+	return p
+}
+
+// ListDeployments creates a tool to list the deployments of a repository.
+func ListDeployments(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "list_deployments",
+		Description: t("TOOL_LIST_DEPLOYMENTS_DESCRIPTION", "List the deployments of a GitHub repository, newest first, optionally for one environment, ref, SHA or task."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_LIST_DEPLOYMENTS_USER_TITLE", "List deployments"),
+			ReadOnlyHint: true,
+		},
+		InputSchema: WithPagination(&jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"owner": {
+					Type:        "string",
+					Description: "Repository owner",
+				},
+				"repo": {
+					Type:        "string",
+					Description: "Repository name",
+				},
+				"environment": {
+					Type:        "string",
+					Description: "Only list deployments to this environment",
+				},
+				"ref": {
+					Type:        "string",
+					Description: "Only list deployments of this branch, tag or SHA",
+				},
+				"sha": {
+					Type:        "string",
+					Description: "Only list deployments of this commit SHA",
+				},
+				"task": {
+					Type:        "string",
+					Description: "Only list deployments for this task, e.g. 'deploy'",
+				},
+			},
+			Required: []string{"owner", "repo"},
+		}),
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		repo, err := RequiredParam[string](args, "repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		environment, err := OptionalParam[string](args, "environment")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		ref, err := OptionalParam[string](args, "ref")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		sha, err := OptionalParam[string](args, "sha")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		task, err := OptionalParam[string](args, "task")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		pagination, err := OptionalPaginationParams(args)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		deployments, resp, err := client.Repositories.ListDeployments(ctx, owner, repo, &github.DeploymentsListOptions{
+			SHA:         sha,
+			Ref:         ref,
+			Task:        task,
+			Environment: environment,
+			ListOptions: github.ListOptions{Page: pagination.Page, PerPage: pagination.PerPage},
+		})
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to list deployments", resp, err), nil, nil
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		result := make([]MinimalDeployment, 0, len(deployments))
+		for _, deployment := range deployments {
+			result = append(result, convertToMinimalDeployment(deployment))
+		}
+
+		return MarshalledTextResult(result), nil, nil
+	})
+
+	return tool, handler
+}
+
+// CreateDeployment creates a tool to create a deployment of a ref to an environment.
+func CreateDeployment(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "create_deployment",
+		Description: t("TOOL_CREATE_DEPLOYMENT_DESCRIPTION", "Create a deployment of a branch, tag or SHA to an environment. The deployment only records the intent to deploy; report progress with create_deployment_status. By default GitHub refuses to deploy a ref whose commit statuses are not all successful."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_CREATE_DEPLOYMENT_USER_TITLE", "Create deployment"),
+			ReadOnlyHint: false,
+		},
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"owner": {
+					Type:        "string",
+					Description: "Repository owner",
+				},
+				"repo": {
+					Type:        "string",
+					Description: "Repository name",
+				},
+				"ref": {
+					Type:        "string",
+					Description: "Branch, tag or SHA to deploy",
+				},
+				"environment": {
+					Type:        "string",
+					Description: "Environment to deploy to. Defaults to 'production'",
+				},
+				"description": {
+					Type:        "string",
+					Description: "Short description of the deployment",
+				},
+				"task": {
+					Type:        "string",
+					Description: "Task to run, e.g. 'deploy' (default) or 'deploy:migrations'",
+				},
+				"payload": {
+					Type:        "object",
+					Description: "Extra information for the deployment system",
+				},
+				"auto_merge": {
+					Type:        "boolean",
+					Description: "Merge the default branch into ref first when ref is behind it. Defaults to true",
+				},
+				"required_contexts": {
+					Type:        "array",
+					Description: "Status check contexts that must pass before deploying. Defaults to all of them; pass an empty array to skip the checks",
+					Items: &jsonschema.Schema{
+						Type: "string",
+					},
+				},
+				"transient_environment": {
+					Type:        "boolean",
+					Description: "Whether the environment is specific to the deployment and goes away, like a review app",
+				},
+				"production_environment": {
+					Type:        "boolean",
+					Description: "Whether the environment is one end users interact with. Defaults to true for 'production'",
+				},
+			},
+			Required: []string{"owner", "repo", "ref"},
+		},
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		repo, err := RequiredParam[string](args, "repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		ref, err := RequiredParam[string](args, "ref")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		request := &github.DeploymentRequest{Ref: github.Ptr(ref)}
+		for param, field := range map[string]**string{
+			"environment": &request.Environment,
+			"description": &request.Description,
+			"task":        &request.Task,
+		} {
+			value, err := OptionalParam[string](args, param)
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			if value != "" {
+				*field = github.Ptr(value)
+			}
+		}
+		for param, field := range map[string]**bool{
+			"auto_merge":             &request.AutoMerge,
+			"transient_environment":  &request.TransientEnvironment,
+			"production_environment": &request.ProductionEnvironment,
+		} {
+			if _, ok := args[param]; !ok {
+				continue
+			}
+			value, err := OptionalParam[bool](args, param)
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			*field = github.Ptr(value)
+		}
+		// An empty list is meaningful, it skips the status checks
+		if _, ok := args["required_contexts"]; ok {
+			contexts, err := OptionalStringArrayParam(args, "required_contexts")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			request.RequiredContexts = &contexts
+		}
+		if payload, ok := args["payload"]; ok {
+			if _, isObject := payload.(map[string]any); !isObject {
+				return utils.NewToolResultError("payload must be an object"), nil, nil
+			}
+			request.Payload = payload
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		deployment, resp, err := client.Repositories.CreateDeployment(ctx, owner, repo, request)
+		if err != nil {
+			// GitHub answers 202 without creating the deployment when it auto-merged the default branch into ref
+			var acceptedErr *github.AcceptedError
+			if errors.As(err, &acceptedErr) {
+				return utils.NewToolResultError(fmt.Sprintf("GitHub merged the default branch into %s instead of creating the deployment; create the deployment again to deploy the merged ref", ref)), nil, nil
+			}
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to create deployment", resp, err), nil, nil
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		return MarshalledTextResult(convertToMinimalDeployment(deployment)), nil, nil
+	})
+
+	return tool, handler
+}
+
+// CreateDeploymentStatus creates a tool to report the state of a deployment.
+func CreateDeploymentStatus(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "create_deployment_status",
+		Description: t("TOOL_CREATE_DEPLOYMENT_STATUS_DESCRIPTION", "Set the status of a deployment, e.g. in_progress when the deploy starts and success or failure when it ends."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_CREATE_DEPLOYMENT_STATUS_USER_TITLE", "Create deployment status"),
+			ReadOnlyHint: false,
+		},
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"owner": {
+					Type:        "string",
+					Description: "Repository owner",
+				},
+				"repo": {
+					Type:        "string",
+					Description: "Repository name",
+				},
+				"deployment_id": {
+					Type:        "number",
+					Description: "ID of the deployment",
+				},
+				"state": {
+					Type:        "string",
+					Description: "State of the deployment",
+					Enum:        []any{"queued", "pending", "in_progress", "success", "failure", "error", "inactive"},
+				},
+				"description": {
+					Type:        "string",
+					Description: "Short description of the status, up to 140 characters",
+				},
+				"log_url": {
+					Type:        "string",
+					Description: "URL of the deployment output",
+				},
+				"environment_url": {
+					Type:        "string",
+					Description: "URL to access the deployed environment",
+				},
+				"environment": {
+					Type:        "string",
+					Description: "Name of the environment, to change the environment of the deployment",
+				},
+				"auto_inactive": {
+					Type:        "boolean",
+					Description: "Mark earlier successful deployments to the same environment inactive. Defaults to true",
+				},
+			},
+			Required: []string{"owner", "repo", "deployment_id", "state"},
+		},
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		repo, err := RequiredParam[string](args, "repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		deploymentID, err := RequiredBigInt(args, "deployment_id")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		state, err := RequiredParam[string](args, "state")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		request := &github.DeploymentStatusRequest{State: github.Ptr(state)}
+		for param, field := range map[string]**string{
+			"description":     &request.Description,
+			"log_url":         &request.LogURL,
+			"environment_url": &request.EnvironmentURL,
+			"environment":     &request.Environment,
+		} {
+			value, err := OptionalParam[string](args, param)
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			if value != "" {
+				*field = github.Ptr(value)
+			}
+		}
+		if _, ok := args["auto_inactive"]; ok {
+			autoInactive, err := OptionalParam[bool](args, "auto_inactive")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			request.AutoInactive = github.Ptr(autoInactive)
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		status, resp, err := client.Repositories.CreateDeploymentStatus(ctx, owner, repo, deploymentID, request)
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to create deployment status", resp, err), nil, nil
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		result := MinimalDeploymentStatus{
+			ID:             status.GetID(),
+			DeploymentID:   deploymentID,
+			State:          status.GetState(),
+			Description:    status.GetDescription(),
+			Environment:    status.GetEnvironment(),
+			EnvironmentURL: status.GetEnvironmentURL(),
+			LogURL:         status.GetLogURL(),
+		}
+		if status.CreatedAt != nil {
+			result.CreatedAt = status.CreatedAt.Format(time.RFC3339)
+		}
+		return MarshalledTextResult(result), nil, nil
+	})
+
+	return tool, handler
+}
+
+// ListEnvironments creates a tool to list the deployment environments of a repository.
+func ListEnvironments(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "list_environments",
+		Description: t("TOOL_LIST_ENVIRONMENTS_DESCRIPTION", "List the deployment environments of a GitHub repository with their protection rules: wait timer, required reviewers and deployment branch policy."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_LIST_ENVIRONMENTS_USER_TITLE", "List environments"),
+			ReadOnlyHint: true,
+		},
+		InputSchema: WithPagination(&jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"owner": {
+					Type:        "string",
+					Description: "Repository owner",
+				},
+				"repo": {
+					Type:        "string",
+					Description: "Repository name",
+				},
+			},
+			Required: []string{"owner", "repo"},
+		}),
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		repo, err := RequiredParam[string](args, "repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		pagination, err := OptionalPaginationParams(args)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		envs, resp, err := client.Repositories.ListEnvironments(ctx, owner, repo, &github.EnvironmentListOptions{
+			ListOptions: github.ListOptions{Page: pagination.Page, PerPage: pagination.PerPage},
+		})
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to list environments", resp, err), nil, nil
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		result := make([]EnvironmentProtection, 0, len(envs.Environments))
+		for _, env := range envs.Environments {
+			result = append(result, convertToEnvironmentProtection(env))
+		}
+
+		return MarshalledTextResult(map[string]any{
+			"total_count":  envs.GetTotalCount(),
+			"environments": result,
+		}), nil, nil
+	})
+
+	return tool, handler
+}
+
+// GetEnvironment creates a tool to get a deployment environment and its protection rules.
+func GetEnvironment(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "get_environment",
+		Description: t("TOOL_GET_ENVIRONMENT_DESCRIPTION", "Get a deployment environment of a GitHub repository with its protection rules: wait timer, required reviewers and deployment branch policy."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_GET_ENVIRONMENT_USER_TITLE", "Get environment"),
+			ReadOnlyHint: true,
+		},
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"owner": {
+					Type:        "string",
+					Description: "Repository owner",
+				},
+				"repo": {
+					Type:        "string",
+					Description: "Repository name",
+				},
+				"environment": {
+					Type:        "string",
+					Description: "Environment name",
+				},
+			},
+			Required: []string{"owner", "repo", "environment"},
+		},
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		repo, err := RequiredParam[string](args, "repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		environment, err := RequiredParam[string](args, "environment")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		env, resp, err := client.Repositories.GetEnvironment(ctx, owner, repo, environment)
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to get environment", resp, err), nil, nil
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		return MarshalledTextResult(convertToEnvironmentProtection(env)), nil, nil
+	})
+
+	return tool, handler
+}
+
+// environmentReviewers resolves user logins and team slugs to the reviewer IDs the API takes.
+// Teams are looked up in the organization that owns the repository.
+func environmentReviewers(ctx context.Context, client *github.Client, owner string, users, teams []string) ([]*github.EnvReviewers, error) {
+	reviewers := make([]*github.EnvReviewers, 0, len(users)+len(teams))
+	for _, login := range users {
+		user, resp, err := client.Users.Get(ctx, login)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get user %s: %w", login, err)
+		}
+		_ = resp.Body.Close()
+		reviewers = append(reviewers, &github.EnvReviewers{Type: github.Ptr("User"), ID: user.ID})
+	}
+	for _, slug := range teams {
+		team, resp, err := client.Teams.GetTeamBySlug(ctx, owner, slug)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get team %s/%s: %w", owner, slug, err)
+		}
+		_ = resp.Body.Close()
+		reviewers = append(reviewers, &github.EnvReviewers{Type: github.Ptr("Team"), ID: team.ID})
+	}
+	return reviewers, nil
+}
+
+// SetEnvironmentProtection creates a tool to create an environment or change its protection rules.
+func SetEnvironmentProtection(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "set_environment_protection",
+		Description: t("TOOL_SET_ENVIRONMENT_PROTECTION_DESCRIPTION", "Create a deployment environment or change its protection rules. Only the given settings change; the others keep their current values. Requires admin access to the repository."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_SET_ENVIRONMENT_PROTECTION_USER_TITLE", "Set environment protection"),
+			ReadOnlyHint: false,
+		},
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"owner": {
+					Type:        "string",
+					Description: "Repository owner",
+				},
+				"repo": {
+					Type:        "string",
+					Description: "Repository name",
+				},
+				"environment": {
+					Type:        "string",
+					Description: "Environment name. The environment is created if it does not exist",
+				},
+				"wait_timer": {
+					Type:        "number",
+					Description: "Minutes to wait before a deployment proceeds, from 0 to 43200 (30 days). 0 removes the timer",
+					Minimum:     jsonschema.Ptr(0.0),
+					Maximum:     jsonschema.Ptr(43200.0),
+				},
+				"reviewer_users": {
+					Type:        "array",
+					Description: "Logins of users who can approve deployments. Together with reviewer_teams, replaces the current reviewers; pass empty arrays to remove them. At most 6 reviewers in total",
+					Items: &jsonschema.Schema{
+						Type: "string",
+					},
+				},
+				"reviewer_teams": {
+					Type:        "array",
+					Description: "Slugs of teams of the repository's organization who can approve deployments",
+					Items: &jsonschema.Schema{
+						Type: "string",
+					},
+				},
+				"prevent_self_review": {
+					Type:        "boolean",
+					Description: "Prevent the user who started a deployment from approving it",
+				},
+				"can_admins_bypass": {
+					Type:        "boolean",
+					Description: "Allow repository administrators to bypass the protection rules",
+				},
+				"deployment_branch_policy": {
+					Type:        "string",
+					Description: "Which branches can deploy: all branches, protected branches only, or branches matching custom name patterns configured on the environment",
+					Enum:        []any{"all", "protected", "custom"},
+				},
+			},
+			Required: []string{"owner", "repo", "environment"},
+		},
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		repo, err := RequiredParam[string](args, "repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		environment, err := RequiredParam[string](args, "environment")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		branchPolicy, err := OptionalParam[string](args, "deployment_branch_policy")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		reviewerUsers, err := OptionalStringArrayParam(args, "reviewer_users")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		reviewerTeams, err := OptionalStringArrayParam(args, "reviewer_teams")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		if len(reviewerUsers)+len(reviewerTeams) > 6 {
+			return utils.NewToolResultError("an environment can have at most 6 reviewers"), nil, nil
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		// The API replaces every setting, so start from the current ones
+		current := EnvironmentProtection{Reviewers: []EnvironmentReviewer{}, CanAdminsBypass: true, DeploymentBranchPolicy: "all"}
+		env, resp, err := client.Repositories.GetEnvironment(ctx, owner, repo, environment)
+		if err != nil {
+			var ghErr *github.ErrorResponse
+			if !errors.As(err, &ghErr) || ghErr.Response.StatusCode != http.StatusNotFound {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to get environment", resp, err), nil, nil
+			}
+		} else {
+			_ = resp.Body.Close()
+			current = convertToEnvironmentProtection(env)
+		}
+
+		update := &github.CreateUpdateEnvironment{
+			WaitTimer:         github.Ptr(current.WaitTimer),
+			CanAdminsBypass:   github.Ptr(current.CanAdminsBypass),
+			PreventSelfReview: github.Ptr(current.PreventSelfReview),
+		}
+		for _, reviewer := range current.Reviewers {
+			update.Reviewers = append(update.Reviewers, &github.EnvReviewers{Type: github.Ptr(reviewer.Type), ID: github.Ptr(reviewer.ID)})
+		}
+		if _, ok := args["wait_timer"]; ok {
+			waitTimer, err := OptionalIntParam(args, "wait_timer")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			update.WaitTimer = github.Ptr(waitTimer)
+		}
+		for param, field := range map[string]**bool{
+			"prevent_self_review": &update.PreventSelfReview,
+			"can_admins_bypass":   &update.CanAdminsBypass,
+		} {
+			if _, ok := args[param]; !ok {
+				continue
+			}
+			value, err := OptionalParam[bool](args, param)
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			*field = github.Ptr(value)
+		}
+		_, usersGiven := args["reviewer_users"]
+		_, teamsGiven := args["reviewer_teams"]
+		if usersGiven || teamsGiven {
+			update.Reviewers, err = environmentReviewers(ctx, client, owner, reviewerUsers, reviewerTeams)
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+		}
+		if branchPolicy == "" {
+			branchPolicy = current.DeploymentBranchPolicy
+		}
+		switch branchPolicy {
+		case "protected":
+			update.DeploymentBranchPolicy = &github.BranchPolicy{ProtectedBranches: github.Ptr(true), CustomBranchPolicies: github.Ptr(false)}
+		case "custom":
+			update.DeploymentBranchPolicy = &github.BranchPolicy{ProtectedBranches: github.Ptr(false), CustomBranchPolicies: github.Ptr(true)}
+		}
+
+		env, resp, err = client.Repositories.CreateUpdateEnvironment(ctx, owner, repo, environment, update)
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to update environment", resp, err), nil, nil
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		return MarshalledTextResult(convertToEnvironmentProtection(env)), nil, nil
+	})
+
+	return tool, handler
+}
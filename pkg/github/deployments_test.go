@@ -0,0 +1,329 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v79/github"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var protectedEnvironment = map[string]any{
+	"name":              "production",
+	"html_url":          "https://github.com/owner/repo/deployments/activity_log?environments_filter=production",
+	"can_admins_bypass": false,
+	"protection_rules": []any{
+		map[string]any{"type": "wait_timer", "wait_timer": 30},
+		map[string]any{
+			"type":                "required_reviewers",
+			"prevent_self_review": true,
+			"reviewers": []any{
+				map[string]any{"type": "User", "reviewer": map[string]any{"id": 1, "login": "octocat"}},
+				map[string]any{"type": "Team", "reviewer": map[string]any{"id": 2, "slug": "ops"}},
+			},
+		},
+	},
+	"deployment_branch_policy": map[string]any{"protected_branches": true, "custom_branch_policies": false},
+}
+
+func Test_ListDeployments(t *testing.T) {
+	tool, _ := ListDeployments(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.True(t, tool.Annotations.ReadOnlyHint)
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.GetReposDeploymentsByOwnerByRepo,
+			expectQueryParams(t, map[string]string{"environment": "production", "ref": "main", "page": "1", "per_page": "30"}).andThen(
+				mockResponse(t, http.StatusOK, []*github.Deployment{{
+					ID:          github.Ptr(int64(7)),
+					SHA:         github.Ptr("abc123"),
+					Ref:         github.Ptr("main"),
+					Task:        github.Ptr("deploy"),
+					Environment: github.Ptr("production"),
+					Creator:     &github.User{Login: github.Ptr("octocat")},
+				}}),
+			),
+		),
+	)
+	_, handler := ListDeployments(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+	args := map[string]any{"owner": "owner", "repo": "repo", "environment": "production", "ref": "main"}
+	request := createMCPRequest(args)
+	result, _, err := handler(context.Background(), &request, args)
+	require.NoError(t, err)
+	require.False(t, result.IsError, getTextResult(t, result).Text)
+
+	var deployments []MinimalDeployment
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &deployments))
+	assert.Equal(t, []MinimalDeployment{{ID: 7, SHA: "abc123", Ref: "main", Task: "deploy", Environment: "production", Creator: "octocat"}}, deployments)
+}
+
+func Test_CreateDeployment(t *testing.T) {
+	tool, _ := CreateDeployment(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	t.Run("creates deployment", func(t *testing.T) {
+		mockedClient := mock.NewMockedHTTPClient(
+			mock.WithRequestMatchHandler(
+				mock.PostReposDeploymentsByOwnerByRepo,
+				expectRequestBody(t, map[string]any{
+					"ref":               "v1.2.0",
+					"environment":       "staging",
+					"auto_merge":        false,
+					"required_contexts": []any{},
+					"payload":           map[string]any{"region": "eu"},
+				}).andThen(
+					mockResponse(t, http.StatusCreated, &github.Deployment{
+						ID:          github.Ptr(int64(8)),
+						SHA:         github.Ptr("def456"),
+						Ref:         github.Ptr("v1.2.0"),
+						Environment: github.Ptr("staging"),
+					}),
+				),
+			),
+		)
+		_, handler := CreateDeployment(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+		args := map[string]any{
+			"owner":             "owner",
+			"repo":              "repo",
+			"ref":               "v1.2.0",
+			"environment":       "staging",
+			"auto_merge":        false,
+			"required_contexts": []any{},
+			"payload":           map[string]any{"region": "eu"},
+		}
+		request := createMCPRequest(args)
+		result, _, err := handler(context.Background(), &request, args)
+		require.NoError(t, err)
+		require.False(t, result.IsError, getTextResult(t, result).Text)
+
+		var deployment MinimalDeployment
+		require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &deployment))
+		assert.Equal(t, MinimalDeployment{ID: 8, SHA: "def456", Ref: "v1.2.0", Environment: "staging"}, deployment)
+	})
+
+	t.Run("auto merged instead of deploying", func(t *testing.T) {
+		mockedClient := mock.NewMockedHTTPClient(
+			mock.WithRequestMatchHandler(
+				mock.PostReposDeploymentsByOwnerByRepo,
+				mockResponse(t, http.StatusAccepted, map[string]any{"message": "Auto-merged main into topic on deployment."}),
+			),
+		)
+		_, handler := CreateDeployment(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+		args := map[string]any{"owner": "owner", "repo": "repo", "ref": "topic"}
+		request := createMCPRequest(args)
+		result, _, err := handler(context.Background(), &request, args)
+		require.NoError(t, err)
+		require.True(t, result.IsError)
+		assert.Contains(t, getErrorResult(t, result).Text, "create the deployment again")
+	})
+}
+
+func Test_CreateDeploymentStatus(t *testing.T) {
+	tool, _ := CreateDeploymentStatus(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.PostReposDeploymentsStatusesByOwnerByRepoByDeploymentId,
+			expectPath(t, "/repos/owner/repo/deployments/8/statuses").andThen(
+				expectRequestBody(t, map[string]any{
+					"state":           "success",
+					"environment_url": "https://staging.example.com",
+					"auto_inactive":   false,
+				}).andThen(
+					mockResponse(t, http.StatusCreated, &github.DeploymentStatus{
+						ID:             github.Ptr(int64(99)),
+						State:          github.Ptr("success"),
+						EnvironmentURL: github.Ptr("https://staging.example.com"),
+					}),
+				),
+			),
+		),
+	)
+	_, handler := CreateDeploymentStatus(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+	args := map[string]any{
+		"owner":           "owner",
+		"repo":            "repo",
+		"deployment_id":   float64(8),
+		"state":           "success",
+		"environment_url": "https://staging.example.com",
+		"auto_inactive":   false,
+	}
+	request := createMCPRequest(args)
+	result, _, err := handler(context.Background(), &request, args)
+	require.NoError(t, err)
+	require.False(t, result.IsError, getTextResult(t, result).Text)
+
+	var status MinimalDeploymentStatus
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &status))
+	assert.Equal(t, MinimalDeploymentStatus{ID: 99, DeploymentID: 8, State: "success", EnvironmentURL: "https://staging.example.com"}, status)
+}
+
+func Test_ListEnvironments(t *testing.T) {
+	tool, _ := ListEnvironments(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.GetReposEnvironmentsByOwnerByRepo,
+			mockResponse(t, http.StatusOK, map[string]any{
+				"total_count":  2,
+				"environments": []any{protectedEnvironment, map[string]any{"name": "staging", "can_admins_bypass": true}},
+			}),
+		),
+	)
+	_, handler := ListEnvironments(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+	args := map[string]any{"owner": "owner", "repo": "repo"}
+	request := createMCPRequest(args)
+	result, _, err := handler(context.Background(), &request, args)
+	require.NoError(t, err)
+	require.False(t, result.IsError, getTextResult(t, result).Text)
+
+	var response struct {
+		TotalCount   int                     `json:"total_count"`
+		Environments []EnvironmentProtection `json:"environments"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &response))
+	assert.Equal(t, 2, response.TotalCount)
+	require.Len(t, response.Environments, 2)
+	assert.Equal(t, EnvironmentProtection{
+		Name:      "production",
+		HTMLURL:   "https://github.com/owner/repo/deployments/activity_log?environments_filter=production",
+		WaitTimer: 30,
+		Reviewers: []EnvironmentReviewer{
+			{Type: "User", ID: 1, Name: "octocat"},
+			{Type: "Team", ID: 2, Name: "ops"},
+		},
+		PreventSelfReview:      true,
+		DeploymentBranchPolicy: "protected",
+	}, response.Environments[0])
+	assert.Equal(t, EnvironmentProtection{Name: "staging", Reviewers: []EnvironmentReviewer{}, CanAdminsBypass: true, DeploymentBranchPolicy: "all"}, response.Environments[1])
+}
+
+func Test_GetEnvironment(t *testing.T) {
+	tool, _ := GetEnvironment(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.GetReposEnvironmentsByOwnerByRepoByEnvironmentName,
+			expectPath(t, "/repos/owner/repo/environments/production").andThen(
+				mockResponse(t, http.StatusOK, protectedEnvironment),
+			),
+		),
+	)
+	_, handler := GetEnvironment(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+	args := map[string]any{"owner": "owner", "repo": "repo", "environment": "production"}
+	request := createMCPRequest(args)
+	result, _, err := handler(context.Background(), &request, args)
+	require.NoError(t, err)
+	require.False(t, result.IsError, getTextResult(t, result).Text)
+
+	var env EnvironmentProtection
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &env))
+	assert.Equal(t, 30, env.WaitTimer)
+	assert.Len(t, env.Reviewers, 2)
+	assert.Equal(t, "protected", env.DeploymentBranchPolicy)
+}
+
+func Test_SetEnvironmentProtection(t *testing.T) {
+	tool, _ := SetEnvironmentProtection(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	t.Run("keeps settings that are not given", func(t *testing.T) {
+		mockedClient := mock.NewMockedHTTPClient(
+			mock.WithRequestMatchHandler(
+				mock.GetReposEnvironmentsByOwnerByRepoByEnvironmentName,
+				mockResponse(t, http.StatusOK, protectedEnvironment),
+			),
+			mock.WithRequestMatchHandler(
+				mock.PutReposEnvironmentsByOwnerByRepoByEnvironmentName,
+				expectRequestBody(t, map[string]any{
+					"wait_timer":          float64(60),
+					"can_admins_bypass":   false,
+					"prevent_self_review": true,
+					"reviewers": []any{
+						map[string]any{"type": "User", "id": float64(1)},
+						map[string]any{"type": "Team", "id": float64(2)},
+					},
+					"deployment_branch_policy": map[string]any{"protected_branches": true, "custom_branch_policies": false},
+				}).andThen(
+					mockResponse(t, http.StatusOK, protectedEnvironment),
+				),
+			),
+		)
+		_, handler := SetEnvironmentProtection(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+		args := map[string]any{"owner": "owner", "repo": "repo", "environment": "production", "wait_timer": float64(60)}
+		request := createMCPRequest(args)
+		result, _, err := handler(context.Background(), &request, args)
+		require.NoError(t, err)
+		require.False(t, result.IsError, getTextResult(t, result).Text)
+	})
+
+	t.Run("creates environment with reviewers", func(t *testing.T) {
+		mockedClient := mock.NewMockedHTTPClient(
+			mock.WithRequestMatchHandler(
+				mock.GetReposEnvironmentsByOwnerByRepoByEnvironmentName,
+				mockResponse(t, http.StatusNotFound, map[string]any{"message": "Not Found"}),
+			),
+			mock.WithRequestMatchHandler(
+				mock.GetUsersByUsername,
+				expectPath(t, "/users/hubot").andThen(
+					mockResponse(t, http.StatusOK, &github.User{ID: github.Ptr(int64(3)), Login: github.Ptr("hubot")}),
+				),
+			),
+			mock.WithRequestMatchHandler(
+				mock.GetOrgsTeamsByOrgByTeamSlug,
+				expectPath(t, "/orgs/owner/teams/release").andThen(
+					mockResponse(t, http.StatusOK, &github.Team{ID: github.Ptr(int64(4)), Slug: github.Ptr("release")}),
+				),
+			),
+			mock.WithRequestMatchHandler(
+				mock.PutReposEnvironmentsByOwnerByRepoByEnvironmentName,
+				expectRequestBody(t, map[string]any{
+					"wait_timer":          float64(0),
+					"can_admins_bypass":   true,
+					"prevent_self_review": false,
+					"reviewers": []any{
+						map[string]any{"type": "User", "id": float64(3)},
+						map[string]any{"type": "Team", "id": float64(4)},
+					},
+					"deployment_branch_policy": nil,
+				}).andThen(
+					mockResponse(t, http.StatusOK, map[string]any{"name": "qa"}),
+				),
+			),
+		)
+		_, handler := SetEnvironmentProtection(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+		args := map[string]any{"owner": "owner", "repo": "repo", "environment": "qa", "reviewer_users": []any{"hubot"}, "reviewer_teams": []any{"release"}}
+		request := createMCPRequest(args)
+		result, _, err := handler(context.Background(), &request, args)
+		require.NoError(t, err)
+		require.False(t, result.IsError, getTextResult(t, result).Text)
+	})
+
+	t.Run("too many reviewers", func(t *testing.T) {
+		_, handler := SetEnvironmentProtection(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+		args := map[string]any{"owner": "owner", "repo": "repo", "environment": "qa", "reviewer_users": []any{"a", "b", "c", "d", "e", "f", "g"}}
+		request := createMCPRequest(args)
+		result, _, err := handler(context.Background(), &request, args)
+		require.NoError(t, err)
+		require.True(t, result.IsError)
+		assert.Contains(t, getErrorResult(t, result).Text, "at most 6 reviewers")
+	})
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 
+	ghErrors "github.com/github/github-mcp-server/pkg/errors"
 	"github.com/github/github-mcp-server/pkg/translations"
 	"github.com/github/github-mcp-server/pkg/utils"
 	"github.com/go-viper/mapstructure/v2"
@@ -439,7 +441,12 @@ func GetDiscussionComments(getGQLClient GetGQLClientFn, t translations.Translati
 					Discussion struct {
 						Comments struct {
 							Nodes []struct {
-								Body githubv4.String
+								ID     githubv4.ID
+								Body   githubv4.String
+								URL    githubv4.String `graphql:"url"`
+								Author struct {
+									Login githubv4.String
+								}
 							}
 							PageInfo struct {
 								HasNextPage     githubv4.Boolean
@@ -469,7 +476,13 @@ func GetDiscussionComments(getGQLClient GetGQLClientFn, t translations.Translati
 
 			var comments []*github.IssueComment
 			for _, c := range q.Repository.Discussion.Comments.Nodes {
-				comments = append(comments, &github.IssueComment{Body: github.Ptr(string(c.Body))})
+				// The node ID is what add_discussion_comment and mark_discussion_comment_as_answer take
+				comments = append(comments, &github.IssueComment{
+					NodeID:  github.Ptr(fmt.Sprint(c.ID)),
+					Body:    github.Ptr(string(c.Body)),
+					HTMLURL: github.Ptr(string(c.URL)),
+					User:    &github.User{Login: github.Ptr(string(c.Author.Login))},
+				})
 			}
 
 			// Create response with pagination info
@@ -589,3 +602,323 @@ func ListDiscussionCategories(getGQLClient GetGQLClientFn, t translations.Transl
 			return utils.NewToolResultText(string(out)), nil, nil
 		}
 }
+
+// CreateDiscussion creates a tool to start a new discussion in a repository.
+func CreateDiscussion(getGQLClient GetGQLClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	return mcp.Tool{
+			Name:        "create_discussion",
+			Description: t("TOOL_CREATE_DISCUSSION_DESCRIPTION", "Create a discussion in a repository's discussion category."),
+			Annotations: &mcp.ToolAnnotations{
+				Title:        t("TOOL_CREATE_DISCUSSION_USER_TITLE", "Create discussion"),
+				ReadOnlyHint: false,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"owner": {
+						Type:        "string",
+						Description: "Repository owner",
+					},
+					"repo": {
+						Type:        "string",
+						Description: "Repository name",
+					},
+					"category": {
+						Type:        "string",
+						Description: "Category ID, name or slug, as returned by list_discussion_categories",
+					},
+					"title": {
+						Type:        "string",
+						Description: "Discussion title",
+					},
+					"body": {
+						Type:        "string",
+						Description: "Discussion body in Markdown",
+					},
+				},
+				Required: []string{"owner", "repo", "category", "title", "body"},
+			},
+		},
+		func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+			owner, err := RequiredParam[string](args, "owner")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			repo, err := RequiredParam[string](args, "repo")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			category, err := RequiredParam[string](args, "category")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			title, err := RequiredParam[string](args, "title")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			body, err := RequiredParam[string](args, "body")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+
+			client, err := getGQLClient(ctx)
+			if err != nil {
+				return utils.NewToolResultError(fmt.Sprintf("failed to get GitHub GQL client: %v", err)), nil, nil
+			}
+
+			var q struct {
+				Repository struct {
+					ID                   githubv4.ID
+					DiscussionCategories struct {
+						Nodes []struct {
+							ID   githubv4.ID
+							Name githubv4.String
+							Slug githubv4.String
+						}
+					} `graphql:"discussionCategories(first: 100)"`
+				} `graphql:"repository(owner: $owner, name: $repo)"`
+			}
+			vars := map[string]interface{}{
+				"owner": githubv4.String(owner),
+				"repo":  githubv4.String(repo),
+			}
+			if err := client.Query(ctx, &q, vars); err != nil {
+				return ghErrors.NewGitHubGraphQLErrorResponse(ctx, "failed to get discussion categories", err), nil, nil
+			}
+
+			var categoryID githubv4.ID
+			names := make([]string, 0, len(q.Repository.DiscussionCategories.Nodes))
+			for _, c := range q.Repository.DiscussionCategories.Nodes {
+				if fmt.Sprint(c.ID) == category || strings.EqualFold(string(c.Name), category) || string(c.Slug) == category {
+					categoryID = c.ID
+					break
+				}
+				names = append(names, string(c.Name))
+			}
+			if categoryID == nil {
+				return utils.NewToolResultError(fmt.Sprintf("repository has no discussion category %q; available categories: %s", category, strings.Join(names, ", "))), nil, nil
+			}
+
+			var mutation struct {
+				CreateDiscussion struct {
+					Discussion struct {
+						Number   githubv4.Int
+						URL      githubv4.String `graphql:"url"`
+						Category struct {
+							Name githubv4.String
+						}
+					}
+				} `graphql:"createDiscussion(input: $input)"`
+			}
+			input := githubv4.CreateDiscussionInput{
+				RepositoryID: q.Repository.ID,
+				CategoryID:   categoryID,
+				Title:        githubv4.String(title),
+				Body:         githubv4.String(body),
+			}
+			if err := client.Mutate(ctx, &mutation, input, nil); err != nil {
+				return ghErrors.NewGitHubGraphQLErrorResponse(ctx, "failed to create discussion", err), nil, nil
+			}
+			d := mutation.CreateDiscussion.Discussion
+
+			out, err := json.Marshal(map[string]interface{}{
+				"number":   int(d.Number),
+				"url":      string(d.URL),
+				"category": string(d.Category.Name),
+			})
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to marshal discussion: %w", err)
+			}
+			return utils.NewToolResultText(string(out)), nil, nil
+		}
+}
+
+// AddDiscussionComment creates a tool to comment on a discussion or reply to one of its comments.
+func AddDiscussionComment(getGQLClient GetGQLClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	return mcp.Tool{
+			Name:        "add_discussion_comment",
+			Description: t("TOOL_ADD_DISCUSSION_COMMENT_DESCRIPTION", "Add a comment to a discussion, or reply to one of its comments."),
+			Annotations: &mcp.ToolAnnotations{
+				Title:        t("TOOL_ADD_DISCUSSION_COMMENT_USER_TITLE", "Add discussion comment"),
+				ReadOnlyHint: false,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"owner": {
+						Type:        "string",
+						Description: "Repository owner",
+					},
+					"repo": {
+						Type:        "string",
+						Description: "Repository name",
+					},
+					"discussionNumber": {
+						Type:        "number",
+						Description: "Discussion Number",
+					},
+					"body": {
+						Type:        "string",
+						Description: "Comment body in Markdown",
+					},
+					"replyToCommentId": {
+						Type:        "string",
+						Description: "Node ID of a top-level comment to reply to, as returned by get_discussion_comments",
+					},
+				},
+				Required: []string{"owner", "repo", "discussionNumber", "body"},
+			},
+		},
+		func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+			owner, err := RequiredParam[string](args, "owner")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			repo, err := RequiredParam[string](args, "repo")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			discussionNumber, err := RequiredInt(args, "discussionNumber")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			body, err := RequiredParam[string](args, "body")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			replyTo, err := OptionalParam[string](args, "replyToCommentId")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+
+			client, err := getGQLClient(ctx)
+			if err != nil {
+				return utils.NewToolResultError(fmt.Sprintf("failed to get GitHub GQL client: %v", err)), nil, nil
+			}
+
+			var q struct {
+				Repository struct {
+					Discussion struct {
+						ID githubv4.ID
+					} `graphql:"discussion(number: $discussionNumber)"`
+				} `graphql:"repository(owner: $owner, name: $repo)"`
+			}
+			vars := map[string]interface{}{
+				"owner":            githubv4.String(owner),
+				"repo":             githubv4.String(repo),
+				"discussionNumber": githubv4.Int(discussionNumber), // #nosec G115 - discussion numbers are always small positive integers
+			}
+			if err := client.Query(ctx, &q, vars); err != nil {
+				return ghErrors.NewGitHubGraphQLErrorResponse(ctx, "failed to get discussion", err), nil, nil
+			}
+
+			var mutation struct {
+				AddDiscussionComment struct {
+					Comment struct {
+						ID  githubv4.ID
+						URL githubv4.String `graphql:"url"`
+					}
+				} `graphql:"addDiscussionComment(input: $input)"`
+			}
+			input := githubv4.AddDiscussionCommentInput{
+				DiscussionID: q.Repository.Discussion.ID,
+				Body:         githubv4.String(body),
+			}
+			if replyTo != "" {
+				input.ReplyToID = githubv4.NewID(replyTo)
+			}
+			if err := client.Mutate(ctx, &mutation, input, nil); err != nil {
+				return ghErrors.NewGitHubGraphQLErrorResponse(ctx, "failed to add discussion comment", err), nil, nil
+			}
+
+			out, err := json.Marshal(map[string]interface{}{
+				"id":  fmt.Sprint(mutation.AddDiscussionComment.Comment.ID),
+				"url": string(mutation.AddDiscussionComment.Comment.URL),
+			})
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to marshal discussion comment: %w", err)
+			}
+			return utils.NewToolResultText(string(out)), nil, nil
+		}
+}
+
+// MarkDiscussionCommentAsAnswer creates a tool to mark or unmark a discussion comment as the answer.
+func MarkDiscussionCommentAsAnswer(getGQLClient GetGQLClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	return mcp.Tool{
+			Name:        "mark_discussion_comment_as_answer",
+			Description: t("TOOL_MARK_DISCUSSION_COMMENT_AS_ANSWER_DESCRIPTION", "Mark a comment as the answer of its discussion, or unmark it. The discussion must be in a category that accepts answers."),
+			Annotations: &mcp.ToolAnnotations{
+				Title:        t("TOOL_MARK_DISCUSSION_COMMENT_AS_ANSWER_USER_TITLE", "Mark discussion comment as answer"),
+				ReadOnlyHint: false,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"commentId": {
+						Type:        "string",
+						Description: "Node ID of the comment, as returned by get_discussion_comments",
+					},
+					"unmark": {
+						Type:        "boolean",
+						Description: "Unmark the comment as the answer instead",
+					},
+				},
+				Required: []string{"commentId"},
+			},
+		},
+		func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+			commentID, err := RequiredParam[string](args, "commentId")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			unmark, err := OptionalParam[bool](args, "unmark")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+
+			client, err := getGQLClient(ctx)
+			if err != nil {
+				return utils.NewToolResultError(fmt.Sprintf("failed to get GitHub GQL client: %v", err)), nil, nil
+			}
+
+			type answeredDiscussion struct {
+				Number     githubv4.Int
+				URL        githubv4.String `graphql:"url"`
+				IsAnswered githubv4.Boolean
+			}
+			var d answeredDiscussion
+			if unmark {
+				var mutation struct {
+					UnmarkDiscussionCommentAsAnswer struct {
+						Discussion answeredDiscussion
+					} `graphql:"unmarkDiscussionCommentAsAnswer(input: $input)"`
+				}
+				input := githubv4.UnmarkDiscussionCommentAsAnswerInput{ID: githubv4.ID(commentID)}
+				if err := client.Mutate(ctx, &mutation, input, nil); err != nil {
+					return ghErrors.NewGitHubGraphQLErrorResponse(ctx, "failed to unmark discussion comment as answer", err), nil, nil
+				}
+				d = mutation.UnmarkDiscussionCommentAsAnswer.Discussion
+			} else {
+				var mutation struct {
+					MarkDiscussionCommentAsAnswer struct {
+						Discussion answeredDiscussion
+					} `graphql:"markDiscussionCommentAsAnswer(input: $input)"`
+				}
+				input := githubv4.MarkDiscussionCommentAsAnswerInput{ID: githubv4.ID(commentID)}
+				if err := client.Mutate(ctx, &mutation, input, nil); err != nil {
+					return ghErrors.NewGitHubGraphQLErrorResponse(ctx, "failed to mark discussion comment as answer", err), nil, nil
+				}
+				d = mutation.MarkDiscussionCommentAsAnswer.Discussion
+			}
+
+			out, err := json.Marshal(map[string]interface{}{
+				"number":     int(d.Number),
+				"url":        string(d.URL),
+				"isAnswered": bool(d.IsAnswered),
+			})
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to marshal discussion: %w", err)
+			}
+			return utils.NewToolResultText(string(out)), nil, nil
+		}
+}
@@ -602,7 +602,7 @@ func Test_GetDiscussionComments(t *testing.T) {
 	assert.ElementsMatch(t, schema.Required, []string{"owner", "repo", "discussionNumber"})
 
 	// Use exact string query that matches implementation output
-	qGetComments := "query($after:String$discussionNumber:Int!$first:Int!$owner:String!$repo:String!){repository(owner: $owner, name: $repo){discussion(number: $discussionNumber){comments(first: $first, after: $after){nodes{body},pageInfo{hasNextPage,hasPreviousPage,startCursor,endCursor},totalCount}}}}"
+	qGetComments := "query($after:String$discussionNumber:Int!$first:Int!$owner:String!$repo:String!){repository(owner: $owner, name: $repo){discussion(number: $discussionNumber){comments(first: $first, after: $after){nodes{id,body,url,author{login}},pageInfo{hasNextPage,hasPreviousPage,startCursor,endCursor},totalCount}}}}"
 
 	// Variables matching what GraphQL receives after JSON marshaling/unmarshaling
 	vars := map[string]interface{}{
@@ -618,8 +618,8 @@ func Test_GetDiscussionComments(t *testing.T) {
 			"discussion": map[string]any{
 				"comments": map[string]any{
 					"nodes": []map[string]any{
-						{"body": "This is the first comment"},
-						{"body": "This is the second comment"},
+						{"id": "DC_1", "body": "This is the first comment", "url": "https://github.com/owner/repo/discussions/1#discussioncomment-1", "author": map[string]any{"login": "user1"}},
+						{"id": "DC_2", "body": "This is the second comment", "url": "https://github.com/owner/repo/discussions/1#discussioncomment-2", "author": map[string]any{"login": "user2"}},
 					},
 					"pageInfo": map[string]any{
 						"hasNextPage":     false,
@@ -668,6 +668,8 @@ func Test_GetDiscussionComments(t *testing.T) {
 	for i, comment := range response.Comments {
 		assert.Equal(t, expectedBodies[i], *comment.Body)
 	}
+	assert.Equal(t, "DC_1", response.Comments[0].GetNodeID())
+	assert.Equal(t, "user2", response.Comments[1].GetUser().GetLogin())
 }
 
 func Test_ListDiscussionCategories(t *testing.T) {
@@ -813,3 +815,176 @@ func Test_ListDiscussionCategories(t *testing.T) {
 		})
 	}
 }
+
+func Test_CreateDiscussion(t *testing.T) {
+	toolDef, _ := CreateDiscussion(nil, translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(toolDef.Name, toolDef))
+
+	qCategories := "query($owner:String!$repo:String!){repository(owner: $owner, name: $repo){id,discussionCategories(first: 100){nodes{id,name,slug}}}}"
+	vars := map[string]interface{}{"owner": "owner", "repo": "repo"}
+	categories := githubv4mock.DataResponse(map[string]any{
+		"repository": map[string]any{
+			"id": "R_1",
+			"discussionCategories": map[string]any{"nodes": []map[string]any{
+				{"id": "DIC_general", "name": "General", "slug": "general"},
+				{"id": "DIC_qa", "name": "Q&A", "slug": "q-a"},
+			}},
+		},
+	})
+
+	t.Run("creates in the category with the given name", func(t *testing.T) {
+		var mutation struct {
+			CreateDiscussion struct {
+				Discussion struct {
+					Number   githubv4.Int
+					URL      githubv4.String `graphql:"url"`
+					Category struct {
+						Name githubv4.String
+					}
+				}
+			} `graphql:"createDiscussion(input: $input)"`
+		}
+		httpClient := githubv4mock.NewMockedHTTPClient(
+			githubv4mock.NewQueryMatcher(qCategories, vars, categories),
+			githubv4mock.NewMutationMatcher(mutation, githubv4.CreateDiscussionInput{
+				RepositoryID: "R_1",
+				CategoryID:   "DIC_qa",
+				Title:        "How do I configure toolsets?",
+				Body:         "Details",
+			}, nil, githubv4mock.DataResponse(map[string]any{
+				"createDiscussion": map[string]any{"discussion": map[string]any{
+					"number":   7,
+					"url":      "https://github.com/owner/repo/discussions/7",
+					"category": map[string]any{"name": "Q&A"},
+				}},
+			})),
+		)
+		_, handler := CreateDiscussion(stubGetGQLClientFn(githubv4.NewClient(httpClient)), translations.NullTranslationHelper)
+
+		reqParams := map[string]interface{}{"owner": "owner", "repo": "repo", "category": "q&a", "title": "How do I configure toolsets?", "body": "Details"}
+		req := createMCPRequest(reqParams)
+		res, _, err := handler(context.Background(), &req, reqParams)
+		require.NoError(t, err)
+		require.False(t, res.IsError, getTextResult(t, res).Text)
+
+		var out map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(getTextResult(t, res).Text), &out))
+		assert.Equal(t, map[string]interface{}{"number": float64(7), "url": "https://github.com/owner/repo/discussions/7", "category": "Q&A"}, out)
+	})
+
+	t.Run("unknown category", func(t *testing.T) {
+		httpClient := githubv4mock.NewMockedHTTPClient(githubv4mock.NewQueryMatcher(qCategories, vars, categories))
+		_, handler := CreateDiscussion(stubGetGQLClientFn(githubv4.NewClient(httpClient)), translations.NullTranslationHelper)
+
+		reqParams := map[string]interface{}{"owner": "owner", "repo": "repo", "category": "Ideas", "title": "t", "body": "b"}
+		req := createMCPRequest(reqParams)
+		res, _, err := handler(context.Background(), &req, reqParams)
+		require.NoError(t, err)
+		require.True(t, res.IsError)
+		assert.Contains(t, getErrorResult(t, res).Text, `repository has no discussion category "Ideas"; available categories: General, Q&A`)
+	})
+}
+
+func Test_AddDiscussionComment(t *testing.T) {
+	toolDef, _ := AddDiscussionComment(nil, translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(toolDef.Name, toolDef))
+
+	var mutation struct {
+		AddDiscussionComment struct {
+			Comment struct {
+				ID  githubv4.ID
+				URL githubv4.String `graphql:"url"`
+			}
+		} `graphql:"addDiscussionComment(input: $input)"`
+	}
+	httpClient := githubv4mock.NewMockedHTTPClient(
+		githubv4mock.NewQueryMatcher(
+			"query($discussionNumber:Int!$owner:String!$repo:String!){repository(owner: $owner, name: $repo){discussion(number: $discussionNumber){id}}}",
+			map[string]interface{}{"owner": "owner", "repo": "repo", "discussionNumber": float64(7)},
+			githubv4mock.DataResponse(map[string]any{"repository": map[string]any{"discussion": map[string]any{"id": "D_7"}}}),
+		),
+		githubv4mock.NewMutationMatcher(mutation, githubv4.AddDiscussionCommentInput{
+			DiscussionID: "D_7",
+			Body:         "Try the --toolsets flag",
+			ReplyToID:    githubv4.NewID("DC_1"),
+		}, nil, githubv4mock.DataResponse(map[string]any{
+			"addDiscussionComment": map[string]any{"comment": map[string]any{
+				"id":  "DC_2",
+				"url": "https://github.com/owner/repo/discussions/7#discussioncomment-2",
+			}},
+		})),
+	)
+	_, handler := AddDiscussionComment(stubGetGQLClientFn(githubv4.NewClient(httpClient)), translations.NullTranslationHelper)
+
+	reqParams := map[string]interface{}{"owner": "owner", "repo": "repo", "discussionNumber": float64(7), "body": "Try the --toolsets flag", "replyToCommentId": "DC_1"}
+	req := createMCPRequest(reqParams)
+	res, _, err := handler(context.Background(), &req, reqParams)
+	require.NoError(t, err)
+	require.False(t, res.IsError, getTextResult(t, res).Text)
+
+	var out map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, res).Text), &out))
+	assert.Equal(t, "DC_2", out["id"])
+}
+
+func Test_MarkDiscussionCommentAsAnswer(t *testing.T) {
+	toolDef, _ := MarkDiscussionCommentAsAnswer(nil, translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(toolDef.Name, toolDef))
+
+	type answeredDiscussion struct {
+		Number     githubv4.Int
+		URL        githubv4.String `graphql:"url"`
+		IsAnswered githubv4.Boolean
+	}
+	discussion := func(answered bool) map[string]any {
+		return map[string]any{"discussion": map[string]any{"number": 7, "url": "https://github.com/owner/repo/discussions/7", "isAnswered": answered}}
+	}
+
+	tests := []struct {
+		name     string
+		unmark   bool
+		matcher  githubv4mock.Matcher
+		answered bool
+	}{
+		{
+			name: "mark",
+			matcher: githubv4mock.NewMutationMatcher(struct {
+				MarkDiscussionCommentAsAnswer struct {
+					Discussion answeredDiscussion
+				} `graphql:"markDiscussionCommentAsAnswer(input: $input)"`
+			}{}, githubv4.MarkDiscussionCommentAsAnswerInput{ID: "DC_2"}, nil, githubv4mock.DataResponse(map[string]any{
+				"markDiscussionCommentAsAnswer": discussion(true),
+			})),
+			answered: true,
+		},
+		{
+			name:   "unmark",
+			unmark: true,
+			matcher: githubv4mock.NewMutationMatcher(struct {
+				UnmarkDiscussionCommentAsAnswer struct {
+					Discussion answeredDiscussion
+				} `graphql:"unmarkDiscussionCommentAsAnswer(input: $input)"`
+			}{}, githubv4.UnmarkDiscussionCommentAsAnswerInput{ID: "DC_2"}, nil, githubv4mock.DataResponse(map[string]any{
+				"unmarkDiscussionCommentAsAnswer": discussion(false),
+			})),
+			answered: false,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			httpClient := githubv4mock.NewMockedHTTPClient(tc.matcher)
+			_, handler := MarkDiscussionCommentAsAnswer(stubGetGQLClientFn(githubv4.NewClient(httpClient)), translations.NullTranslationHelper)
+
+			reqParams := map[string]interface{}{"commentId": "DC_2", "unmark": tc.unmark}
+			req := createMCPRequest(reqParams)
+			res, _, err := handler(context.Background(), &req, reqParams)
+			require.NoError(t, err)
+			require.False(t, res.IsError, getTextResult(t, res).Text)
+
+			var out map[string]interface{}
+			require.NoError(t, json.Unmarshal([]byte(getTextResult(t, res).Text), &out))
+			assert.Equal(t, tc.answered, out["isAnswered"])
+			assert.Equal(t, float64(7), out["number"])
+		})
+	}
+}
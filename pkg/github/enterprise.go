@@ -0,0 +1,114 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v79/github"
+)
+
+// enterpriseToolVersions are the first GitHub Enterprise Server versions with the APIs that
+// tools rely on. Tools not listed work with every supported version.
+var enterpriseToolVersions = map[string]string{
+	"list_repository_rulesets": "3.11",
+	"get_repository_ruleset":   "3.11",
+	"set_repository_ruleset":   "3.11",
+	"list_issue_types":         "3.17",
+	"sub_issue_write":          "3.17",
+	// Projects were only reachable through GraphQL before
+	"list_projects":       "3.19",
+	"get_project":         "3.19",
+	"list_project_fields": "3.19",
+	"get_project_field":   "3.19",
+	"list_project_items":  "3.19",
+	"get_project_item":    "3.19",
+	"add_project_item":    "3.19",
+	"update_project_item": "3.19",
+	"delete_project_item": "3.19",
+}
+
+// cloudOnlyTools rely on APIs that GitHub Enterprise Server does not have at all.
+var cloudOnlyTools = []string{
+	"assign_copilot_to_issue",
+	"request_copilot_review",
+	"get_copilot_metrics",
+	"get_copilot_seat_usage",
+	"add_copilot_seats",
+	"remove_copilot_seats",
+}
+
+// EnterpriseVersion returns the version of GitHub Enterprise Server that client targets, from
+// its meta endpoint, or empty if the host is not GitHub Enterprise Server.
+func EnterpriseVersion(ctx context.Context, client *github.Client) (string, error) {
+	req, err := client.NewRequest(http.MethodGet, "meta", nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create meta request: %w", err)
+	}
+	var meta struct {
+		InstalledVersion string `json:"installed_version"`
+	}
+	resp, err := client.Do(ctx, req, &meta)
+	if resp != nil {
+		_ = resp.Body.Close()
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get GitHub meta: %w", err)
+	}
+	if meta.InstalledVersion != "" {
+		return meta.InstalledVersion, nil
+	}
+	// GitHub Enterprise Server also sends its version with every response
+	return resp.Header.Get("X-GitHub-Enterprise-Version"), nil
+}
+
+// EnterpriseUnavailableTools returns, sorted, the tools relying on APIs that version of GitHub
+// Enterprise Server lacks. An unknown version hides only the tools no version supports.
+func EnterpriseUnavailableTools(version string) []string {
+	tools := slices.Clone(cloudOnlyTools)
+	if _, ok := parseVersion(version); ok {
+		for tool, since := range enterpriseToolVersions {
+			if compareVersions(version, since) < 0 {
+				tools = append(tools, tool)
+			}
+		}
+	}
+	slices.Sort(tools)
+	return tools
+}
+
+// parseVersion returns the numeric parts of a dotted version, such as 3.14.2.
+func parseVersion(version string) ([]int, bool) {
+	if version == "" {
+		return nil, false
+	}
+	var parts []int
+	for _, field := range strings.Split(version, ".") {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 0 {
+			return nil, false
+		}
+		parts = append(parts, n)
+	}
+	return parts, true
+}
+
+// compareVersions compares two dotted versions part by part, missing parts counting as zero.
+// Versions that do not parse compare as equal.
+func compareVersions(a, b string) int {
+	pa, okA := parseVersion(a)
+	pb, okB := parseVersion(b)
+	if !okA || !okB {
+		return 0
+	}
+	for len(pa) < len(pb) {
+		pa = append(pa, 0)
+	}
+	for len(pb) < len(pa) {
+		pb = append(pb, 0)
+	}
+	return slices.Compare(pa, pb)
+}
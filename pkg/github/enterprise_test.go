@@ -0,0 +1,93 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v79/github"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_EnterpriseVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		handler  http.HandlerFunc
+		expected string
+		err      string
+	}{
+		{
+			name: "version in the meta",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("X-GitHub-Enterprise-Version", "3.14.1")
+				_, _ = w.Write([]byte(`{"installed_version": "3.14.2"}`))
+			},
+			expected: "3.14.2",
+		},
+		{
+			name: "version in the headers",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("X-GitHub-Enterprise-Version", "3.12.0")
+				_, _ = w.Write([]byte(`{}`))
+			},
+			expected: "3.12.0",
+		},
+		{
+			name: "not GitHub Enterprise Server",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(`{"verifiable_password_authentication": false}`))
+			},
+		},
+		{
+			name: "private mode",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusUnauthorized)
+				_, _ = w.Write([]byte(`{"message": "Must authenticate to access this API."}`))
+			},
+			err: "failed to get GitHub meta",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client := github.NewClient(mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(mock.EndpointPattern{Pattern: "/meta", Method: "GET"}, tc.handler),
+			))
+			version, err := EnterpriseVersion(context.Background(), client)
+			if tc.err != "" {
+				assert.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, version)
+		})
+	}
+}
+
+func Test_EnterpriseUnavailableTools(t *testing.T) {
+	// Every listed tool exists
+	tsg := DefaultToolsetGroup(false, stubGetClientFn(nil), stubGetGQLClientFn(nil), stubGetRawClientFn(nil), translations.NullTranslationHelper, 0, FeatureFlags{}, nil)
+	for _, tool := range EnterpriseUnavailableTools("1.0") {
+		_, _, err := tsg.FindToolByName(tool)
+		assert.NoError(t, err, tool)
+	}
+
+	recent := EnterpriseUnavailableTools("3.19.0")
+	assert.Equal(t, []string{"add_copilot_seats", "assign_copilot_to_issue", "get_copilot_metrics", "get_copilot_seat_usage", "remove_copilot_seats", "request_copilot_review"}, recent)
+	assert.Equal(t, recent, EnterpriseUnavailableTools(""), "an unknown version hides cloud-only tools")
+
+	older := EnterpriseUnavailableTools("3.16.5")
+	assert.Contains(t, older, "list_issue_types")
+	assert.Contains(t, older, "list_projects")
+	assert.NotContains(t, older, "list_repository_rulesets")
+	assert.Contains(t, EnterpriseUnavailableTools("3.10"), "list_repository_rulesets")
+}
+
+func Test_compareVersions(t *testing.T) {
+	assert.Equal(t, 0, compareVersions("3.14", "3.14.0"))
+	assert.Equal(t, -1, compareVersions("3.9.5", "3.10"))
+	assert.Equal(t, 1, compareVersions("3.17.1", "3.17"))
+	assert.Equal(t, 0, compareVersions("3.x", "3.17"))
+}
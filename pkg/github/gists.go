@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/http"
 
+	ghErrors "github.com/github/github-mcp-server/pkg/errors"
 	"github.com/github/github-mcp-server/pkg/translations"
 	"github.com/github/github-mcp-server/pkg/utils"
 	"github.com/google/go-github/v79/github"
@@ -181,13 +182,30 @@ func CreateGist(getClient GetClientFn, t translations.TranslationHelperFunc) (mc
 					Type:        "string",
 					Description: "Content for simple single-file gist creation",
 				},
+				"files": {
+					Type:        "array",
+					Description: "Files of a multi-file gist, instead of filename and content",
+					Items: &jsonschema.Schema{
+						Type: "object",
+						Properties: map[string]*jsonschema.Schema{
+							"filename": {
+								Type:        "string",
+								Description: "Filename",
+							},
+							"content": {
+								Type:        "string",
+								Description: "File content",
+							},
+						},
+						Required: []string{"filename", "content"},
+					},
+				},
 				"public": {
 					Type:        "boolean",
 					Description: "Whether the gist is public",
 					Default:     json.RawMessage(`false`),
 				},
 			},
-			Required: []string{"filename", "content"},
 		},
 	}
 
@@ -197,12 +215,7 @@ func CreateGist(getClient GetClientFn, t translations.TranslationHelperFunc) (mc
 			return utils.NewToolResultError(err.Error()), nil, nil
 		}
 
-		filename, err := RequiredParam[string](args, "filename")
-		if err != nil {
-			return utils.NewToolResultError(err.Error()), nil, nil
-		}
-
-		content, err := RequiredParam[string](args, "content")
+		files, err := gistFilesFromArgs(args)
 		if err != nil {
 			return utils.NewToolResultError(err.Error()), nil, nil
 		}
@@ -212,12 +225,6 @@ func CreateGist(getClient GetClientFn, t translations.TranslationHelperFunc) (mc
 			return utils.NewToolResultError(err.Error()), nil, nil
 		}
 
-		files := make(map[github.GistFilename]github.GistFile)
-		files[github.GistFilename(filename)] = github.GistFile{
-			Filename: github.Ptr(filename),
-			Content:  github.Ptr(content),
-		}
-
 		gist := &github.Gist{
 			Files:       files,
 			Public:      github.Ptr(public),
@@ -263,7 +270,7 @@ func CreateGist(getClient GetClientFn, t translations.TranslationHelperFunc) (mc
 func UpdateGist(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
 	tool := mcp.Tool{
 		Name:        "update_gist",
-		Description: t("TOOL_UPDATE_GIST_DESCRIPTION", "Update an existing gist"),
+		Description: t("TOOL_UPDATE_GIST_DESCRIPTION", "Update an existing gist. Files not mentioned are left unchanged; use files to change, rename or delete several files at once"),
 		Annotations: &mcp.ToolAnnotations{
 			Title:        t("TOOL_UPDATE_GIST", "Update Gist"),
 			ReadOnlyHint: false,
@@ -287,8 +294,34 @@ func UpdateGist(getClient GetClientFn, t translations.TranslationHelperFunc) (mc
 					Type:        "string",
 					Description: "Content for the file",
 				},
+				"files": {
+					Type:        "array",
+					Description: "Changes to several files, instead of filename and content",
+					Items: &jsonschema.Schema{
+						Type: "object",
+						Properties: map[string]*jsonschema.Schema{
+							"filename": {
+								Type:        "string",
+								Description: "Name of the file to change, or to create",
+							},
+							"content": {
+								Type:        "string",
+								Description: "New content of the file",
+							},
+							"new_filename": {
+								Type:        "string",
+								Description: "New name of the file",
+							},
+							"delete": {
+								Type:        "boolean",
+								Description: "Delete the file from the gist",
+							},
+						},
+						Required: []string{"filename"},
+					},
+				},
 			},
-			Required: []string{"gist_id", "filename", "content"},
+			Required: []string{"gist_id"},
 		},
 	}
 
@@ -298,30 +331,24 @@ func UpdateGist(getClient GetClientFn, t translations.TranslationHelperFunc) (mc
 			return utils.NewToolResultError(err.Error()), nil, nil
 		}
 
-		description, err := OptionalParam[string](args, "description")
-		if err != nil {
-			return utils.NewToolResultError(err.Error()), nil, nil
-		}
-
-		filename, err := RequiredParam[string](args, "filename")
-		if err != nil {
-			return utils.NewToolResultError(err.Error()), nil, nil
+		// The edit is sent as a map, as deleting a file takes a null that github.Gist cannot express
+		edit := map[string]any{}
+		if _, ok := args["description"]; ok {
+			description, err := OptionalParam[string](args, "description")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			edit["description"] = description
 		}
-
-		content, err := RequiredParam[string](args, "content")
+		files, err := gistFileEditsFromArgs(args)
 		if err != nil {
 			return utils.NewToolResultError(err.Error()), nil, nil
 		}
-
-		files := make(map[github.GistFilename]github.GistFile)
-		files[github.GistFilename(filename)] = github.GistFile{
-			Filename: github.Ptr(filename),
-			Content:  github.Ptr(content),
+		if len(files) > 0 {
+			edit["files"] = files
 		}
-
-		gist := &github.Gist{
-			Files:       files,
-			Description: github.Ptr(description),
+		if len(edit) == 0 {
+			return utils.NewToolResultError("nothing to update: provide description, filename and content, or files"), nil, nil
 		}
 
 		client, err := getClient(ctx)
@@ -329,7 +356,12 @@ func UpdateGist(getClient GetClientFn, t translations.TranslationHelperFunc) (mc
 			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
 		}
 
-		updatedGist, resp, err := client.Gists.Edit(ctx, gistID, gist)
+		req, err := client.NewRequest(http.MethodPatch, "gists/"+gistID, edit)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		updatedGist := new(github.Gist)
+		resp, err := client.Do(ctx, req, updatedGist)
 		if err != nil {
 			return nil, nil, fmt.Errorf("failed to update gist: %w", err)
 		}
@@ -358,3 +390,173 @@ func UpdateGist(getClient GetClientFn, t translations.TranslationHelperFunc) (mc
 
 	return tool, handler
 }
+
+// ForkGist creates a tool to fork a gist into the authenticated user's account
+func ForkGist(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "fork_gist",
+		Description: t("TOOL_FORK_GIST_DESCRIPTION", "Fork a gist into the authenticated user's account, to change a copy of someone else's gist"),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_FORK_GIST", "Fork Gist"),
+			ReadOnlyHint: false,
+		},
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"gist_id": {
+					Type:        "string",
+					Description: "ID of the gist to fork",
+				},
+			},
+			Required: []string{"gist_id"},
+		},
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		gistID, err := RequiredParam[string](args, "gist_id")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		fork, resp, err := client.Gists.Fork(ctx, gistID)
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to fork gist", resp, err), nil, nil
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		return MarshalledTextResult(MinimalResponse{
+			ID:  fork.GetID(),
+			URL: fork.GetHTMLURL(),
+		}), nil, nil
+	})
+
+	return tool, handler
+}
+
+// gistFilesFromArgs returns the files of a new gist, given either as filename and content or as files.
+func gistFilesFromArgs(args map[string]any) (map[github.GistFilename]github.GistFile, error) {
+	files := make(map[github.GistFilename]github.GistFile)
+	rawFiles, ok := args["files"]
+	if !ok {
+		filename, err := RequiredParam[string](args, "filename")
+		if err != nil {
+			return nil, err
+		}
+		content, err := RequiredParam[string](args, "content")
+		if err != nil {
+			return nil, err
+		}
+		files[github.GistFilename(filename)] = github.GistFile{Filename: github.Ptr(filename), Content: github.Ptr(content)}
+		return files, nil
+	}
+
+	if _, ok := args["filename"]; ok {
+		return nil, fmt.Errorf("provide either filename and content or files, not both")
+	}
+	list, ok := rawFiles.([]any)
+	if !ok || len(list) == 0 {
+		return nil, fmt.Errorf("files must be a non-empty array of objects with filename and content")
+	}
+	for i, raw := range list {
+		file, ok := raw.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("files[%d] must be an object with filename and content", i)
+		}
+		filename, err := RequiredParam[string](file, "filename")
+		if err != nil {
+			return nil, fmt.Errorf("files[%d]: %w", i, err)
+		}
+		content, err := RequiredParam[string](file, "content")
+		if err != nil {
+			return nil, fmt.Errorf("files[%d]: %w", i, err)
+		}
+		if _, duplicate := files[github.GistFilename(filename)]; duplicate {
+			return nil, fmt.Errorf("files[%d]: %s is listed more than once", i, filename)
+		}
+		files[github.GistFilename(filename)] = github.GistFile{Filename: github.Ptr(filename), Content: github.Ptr(content)}
+	}
+	return files, nil
+}
+
+// gistFileEditsFromArgs returns the files part of a gist edit: a file object to change a file,
+// or nil to delete it.
+func gistFileEditsFromArgs(args map[string]any) (map[string]any, error) {
+	edits := map[string]any{}
+	rawFiles, ok := args["files"]
+	if !ok {
+		_, hasFilename := args["filename"]
+		_, hasContent := args["content"]
+		if !hasFilename && !hasContent {
+			return edits, nil
+		}
+		filename, err := RequiredParam[string](args, "filename")
+		if err != nil {
+			return nil, err
+		}
+		content, err := RequiredParam[string](args, "content")
+		if err != nil {
+			return nil, err
+		}
+		edits[filename] = map[string]any{"content": content}
+		return edits, nil
+	}
+
+	if _, ok := args["filename"]; ok {
+		return nil, fmt.Errorf("provide either filename and content or files, not both")
+	}
+	list, ok := rawFiles.([]any)
+	if !ok {
+		return nil, fmt.Errorf("files must be an array of objects")
+	}
+	for i, raw := range list {
+		file, ok := raw.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("files[%d] must be an object with filename", i)
+		}
+		filename, err := RequiredParam[string](file, "filename")
+		if err != nil {
+			return nil, fmt.Errorf("files[%d]: %w", i, err)
+		}
+		if _, duplicate := edits[filename]; duplicate {
+			return nil, fmt.Errorf("files[%d]: %s is listed more than once", i, filename)
+		}
+		remove, err := OptionalParam[bool](file, "delete")
+		if err != nil {
+			return nil, fmt.Errorf("files[%d]: %w", i, err)
+		}
+		_, hasContent := file["content"]
+		newFilename, err := OptionalParam[string](file, "new_filename")
+		if err != nil {
+			return nil, fmt.Errorf("files[%d]: %w", i, err)
+		}
+
+		if remove {
+			if hasContent || newFilename != "" {
+				return nil, fmt.Errorf("files[%d]: a deleted file cannot have content or new_filename", i)
+			}
+			edits[filename] = nil
+			continue
+		}
+		edit := map[string]any{}
+		if hasContent {
+			content, err := OptionalParam[string](file, "content")
+			if err != nil {
+				return nil, fmt.Errorf("files[%d]: %w", i, err)
+			}
+			edit["content"] = content
+		}
+		if newFilename != "" {
+			edit["filename"] = newFilename
+		}
+		if len(edit) == 0 {
+			return nil, fmt.Errorf("files[%d]: provide content, new_filename or delete for %s", i, filename)
+		}
+		edits[filename] = edit
+	}
+	return edits, nil
+}
@@ -331,11 +331,11 @@ func Test_CreateGist(t *testing.T) {
 	assert.Contains(t, schema.Properties, "description")
 	assert.Contains(t, schema.Properties, "filename")
 	assert.Contains(t, schema.Properties, "content")
+	assert.Contains(t, schema.Properties, "files")
 	assert.Contains(t, schema.Properties, "public")
 
-	// Verify required parameters
-	assert.Contains(t, schema.Required, "filename")
-	assert.Contains(t, schema.Required, "content")
+	// Files are given either as filename and content or as files
+	assert.Empty(t, schema.Required)
 
 	// Setup mock data for test cases
 	createdGist := &github.Gist{
@@ -477,11 +477,10 @@ func Test_UpdateGist(t *testing.T) {
 	assert.Contains(t, schema.Properties, "description")
 	assert.Contains(t, schema.Properties, "filename")
 	assert.Contains(t, schema.Properties, "content")
+	assert.Contains(t, schema.Properties, "files")
 
 	// Verify required parameters
-	assert.Contains(t, schema.Required, "gist_id")
-	assert.Contains(t, schema.Required, "filename")
-	assert.Contains(t, schema.Required, "content")
+	assert.Equal(t, []string{"gist_id"}, schema.Required)
 
 	// Setup mock data for test cases
 	updatedGist := &github.Gist{
@@ -619,3 +618,133 @@ func Test_UpdateGist(t *testing.T) {
 		})
 	}
 }
+
+func Test_CreateGist_MultipleFiles(t *testing.T) {
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.PostGists,
+			expectRequestBody(t, map[string]any{
+				"description": "Build logs",
+				"public":      false,
+				"files": map[string]any{
+					"build.log": map[string]any{"filename": "build.log", "content": "ok"},
+					"test.log":  map[string]any{"filename": "test.log", "content": "FAIL"},
+				},
+			}).andThen(
+				mockResponse(t, http.StatusCreated, &github.Gist{ID: github.Ptr("gist-id"), HTMLURL: github.Ptr("https://gist.github.com/gist-id")}),
+			),
+		),
+	)
+	_, handler := CreateGist(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+	args := map[string]any{
+		"description": "Build logs",
+		"files": []any{
+			map[string]any{"filename": "build.log", "content": "ok"},
+			map[string]any{"filename": "test.log", "content": "FAIL"},
+		},
+	}
+	request := createMCPRequest(args)
+	result, _, err := handler(context.Background(), &request, args)
+	require.NoError(t, err)
+	require.False(t, result.IsError, getTextResult(t, result).Text)
+
+	args = map[string]any{"files": []any{
+		map[string]any{"filename": "a.txt", "content": "1"},
+		map[string]any{"filename": "a.txt", "content": "2"},
+	}}
+	request = createMCPRequest(args)
+	result, _, err = handler(context.Background(), &request, args)
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	assert.Contains(t, getErrorResult(t, result).Text, "files[1]: a.txt is listed more than once")
+}
+
+func Test_UpdateGist_Files(t *testing.T) {
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.PatchGistsByGistId,
+			expectRequestBody(t, map[string]any{
+				"files": map[string]any{
+					"notes.md":   map[string]any{"content": "# Notes"},
+					"draft.txt":  map[string]any{"filename": "final.txt"},
+					"scratch.sh": nil,
+				},
+			}).andThen(
+				mockResponse(t, http.StatusOK, &github.Gist{ID: github.Ptr("gist-id"), HTMLURL: github.Ptr("https://gist.github.com/gist-id")}),
+			),
+		),
+	)
+	_, handler := UpdateGist(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+	args := map[string]any{
+		"gist_id": "gist-id",
+		"files": []any{
+			map[string]any{"filename": "notes.md", "content": "# Notes"},
+			map[string]any{"filename": "draft.txt", "new_filename": "final.txt"},
+			map[string]any{"filename": "scratch.sh", "delete": true},
+		},
+	}
+	request := createMCPRequest(args)
+	result, _, err := handler(context.Background(), &request, args)
+	require.NoError(t, err)
+	require.False(t, result.IsError, getTextResult(t, result).Text)
+
+	for _, tc := range []struct {
+		args    map[string]any
+		message string
+	}{
+		{map[string]any{"gist_id": "gist-id"}, "nothing to update"},
+		{map[string]any{"gist_id": "gist-id", "files": []any{map[string]any{"filename": "a", "delete": true, "content": "x"}}}, "a deleted file cannot have content or new_filename"},
+		{map[string]any{"gist_id": "gist-id", "files": []any{map[string]any{"filename": "a"}}}, "provide content, new_filename or delete for a"},
+	} {
+		request := createMCPRequest(tc.args)
+		result, _, err := handler(context.Background(), &request, tc.args)
+		require.NoError(t, err)
+		require.True(t, result.IsError)
+		assert.Contains(t, getErrorResult(t, result).Text, tc.message)
+	}
+}
+
+func Test_UpdateGist_DescriptionOnly(t *testing.T) {
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.PatchGistsByGistId,
+			expectRequestBody(t, map[string]any{"description": "Renamed"}).andThen(
+				mockResponse(t, http.StatusOK, &github.Gist{ID: github.Ptr("gist-id")}),
+			),
+		),
+	)
+	_, handler := UpdateGist(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+	args := map[string]any{"gist_id": "gist-id", "description": "Renamed"}
+	request := createMCPRequest(args)
+	result, _, err := handler(context.Background(), &request, args)
+	require.NoError(t, err)
+	require.False(t, result.IsError, getTextResult(t, result).Text)
+}
+
+func Test_ForkGist(t *testing.T) {
+	tool, _ := ForkGist(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.PostGistsForksByGistId,
+			expectPath(t, "/gists/gist-id/forks").andThen(
+				mockResponse(t, http.StatusCreated, &github.Gist{ID: github.Ptr("fork-id"), HTMLURL: github.Ptr("https://gist.github.com/fork-id")}),
+			),
+		),
+	)
+	_, handler := ForkGist(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+	args := map[string]any{"gist_id": "gist-id"}
+	request := createMCPRequest(args)
+	result, _, err := handler(context.Background(), &request, args)
+	require.NoError(t, err)
+	require.False(t, result.IsError, getTextResult(t, result).Text)
+
+	var fork MinimalResponse
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &fork))
+	assert.Equal(t, MinimalResponse{ID: "fork-id", URL: "https://gist.github.com/fork-id"}, fork)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"regexp"
 	"strings"
 
 	ghErrors "github.com/github/github-mcp-server/pkg/errors"
@@ -36,6 +37,91 @@ type TreeResponse struct {
 	Count     int                 `json:"count"`
 }
 
+// treeFilter selects the entries of a recursive tree by directory, depth and glob.
+type treeFilter struct {
+	pathPrefix string
+	maxDepth   int
+	include    []*regexp.Regexp
+	exclude    []*regexp.Regexp
+}
+
+func parseTreeFilter(args map[string]any) (treeFilter, error) {
+	var filter treeFilter
+	pathPrefix, err := OptionalParam[string](args, "path_prefix")
+	if err != nil {
+		return filter, err
+	}
+	filter.pathPrefix = strings.Trim(pathPrefix, "/")
+	if filter.maxDepth, err = OptionalIntParam(args, "max_depth"); err != nil {
+		return filter, err
+	}
+	if filter.maxDepth < 0 {
+		return filter, fmt.Errorf("max_depth must be at least 1")
+	}
+	for param, patterns := range map[string]*[]*regexp.Regexp{"include": &filter.include, "exclude": &filter.exclude} {
+		globs, err := OptionalStringArrayParam(args, param)
+		if err != nil {
+			return filter, err
+		}
+		for _, glob := range globs {
+			pattern, err := compileGlob(strings.TrimSuffix(glob, "/"))
+			if err != nil {
+				return filter, fmt.Errorf("invalid %s glob %q: %w", param, glob, err)
+			}
+			*patterns = append(*patterns, pattern)
+		}
+	}
+	return filter, nil
+}
+
+func (f treeFilter) active() bool {
+	return f.pathPrefix != "" || f.maxDepth > 0 || len(f.include) > 0 || len(f.exclude) > 0
+}
+
+func (f treeFilter) apply(entries []*github.TreeEntry) []*github.TreeEntry {
+	var filtered []*github.TreeEntry
+	for _, entry := range entries {
+		path := entry.GetPath()
+		relative := path
+		if f.pathPrefix != "" {
+			if !strings.HasPrefix(path, f.pathPrefix+"/") {
+				continue
+			}
+			relative = path[len(f.pathPrefix)+1:]
+		}
+		if f.maxDepth > 0 && strings.Count(relative, "/") >= f.maxDepth {
+			continue
+		}
+		if len(f.include) > 0 && !matchesAny(f.include, path) {
+			continue
+		}
+		if f.excludes(path) {
+			continue
+		}
+		filtered = append(filtered, entry)
+	}
+	return filtered
+}
+
+// excludes reports whether path or one of its parent directories matches an exclude glob.
+func (f treeFilter) excludes(path string) bool {
+	for i := 0; i < len(path); i++ {
+		if path[i] == '/' && matchesAny(f.exclude, path[:i]) {
+			return true
+		}
+	}
+	return matchesAny(f.exclude, path)
+}
+
+func matchesAny(patterns []*regexp.Regexp, path string) bool {
+	for _, pattern := range patterns {
+		if pattern.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
+
 // GetRepositoryTree creates a tool to get the tree structure of a GitHub repository.
 func GetRepositoryTree(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
 	tool := mcp.Tool{
@@ -67,7 +153,30 @@ func GetRepositoryTree(getClient GetClientFn, t translations.TranslationHelperFu
 				},
 				"path_filter": {
 					Type:        "string",
-					Description: "Optional path prefix to filter the tree results (e.g., 'src/' to only show files in the src directory)",
+					Description: "Optional path prefix to filter the tree results (e.g., 'src/' to only show files in the src directory). Prefer path_prefix",
+				},
+				"path_prefix": {
+					Type:        "string",
+					Description: "Only return the entries inside this directory (e.g., 'src/components'). Implies recursive",
+				},
+				"max_depth": {
+					Type:        "number",
+					Description: "Only return entries at most this many levels below path_prefix, or below the root without one; 1 returns the direct children. Implies recursive",
+					Minimum:     jsonschema.Ptr(1.0),
+				},
+				"include": {
+					Type:        "array",
+					Description: "Only return the entries whose path matches one of these globs (e.g., ['*.go', 'docs/**/*.md']). Globs without a slash match at any depth, '*' does not cross directories and '**' does. Implies recursive",
+					Items: &jsonschema.Schema{
+						Type: "string",
+					},
+				},
+				"exclude": {
+					Type:        "array",
+					Description: "Leave out the entries whose path matches one of these globs, and everything inside excluded directories (e.g., ['vendor', '*_test.go']). Implies recursive",
+					Items: &jsonschema.Schema{
+						Type: "string",
+					},
 				},
 			},
 			Required: []string{"owner", "repo"},
@@ -96,6 +205,13 @@ func GetRepositoryTree(getClient GetClientFn, t translations.TranslationHelperFu
 			if err != nil {
 				return utils.NewToolResultError(err.Error()), nil, nil
 			}
+			filter, err := parseTreeFilter(args)
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			if filter.active() {
+				recursive = true
+			}
 
 			client, err := getClient(ctx)
 			if err != nil {
@@ -137,6 +253,9 @@ func GetRepositoryTree(getClient GetClientFn, t translations.TranslationHelperFu
 			} else {
 				filteredEntries = tree.Entries
 			}
+			if filter.active() {
+				filteredEntries = filter.apply(filteredEntries)
+			}
 
 			treeEntries := make([]TreeEntryResponse, len(filteredEntries))
 			for i, entry := range filteredEntries {
@@ -0,0 +1,249 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	ghErrors "github.com/github/github-mcp-server/pkg/errors"
+	"github.com/github/github-mcp-server/pkg/signing"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/github/github-mcp-server/pkg/utils"
+	"github.com/google/go-github/v79/github"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// CreatedTag is the output type for a tag created by create_tag.
+type CreatedTag struct {
+	Tag        string `json:"tag"`
+	Ref        string `json:"ref"`
+	SHA        string `json:"sha"`
+	ObjectSHA  string `json:"object_sha"`
+	ObjectType string `json:"object_type"`
+	Signed     bool   `json:"signed"`
+}
+
+// signTag appends a signature by signer to the message of tag. The REST API has no signature
+// field for tags, but a signed tag is an annotated tag whose message ends with the signature of
+// the rest of the tag object, so signing the object GitHub will build gives a verified tag.
+func signTag(signer *signing.Signer, tag *github.CreateTag) error {
+	tag.Tagger = signer.Identity(time.Now())
+	if !strings.HasSuffix(tag.Message, "\n") {
+		tag.Message += "\n"
+	}
+
+	date := tag.Tagger.GetDate()
+	payload := fmt.Sprintf("object %s\ntype %s\ntag %s\ntagger %s <%s> %d %s\n\n%s",
+		tag.Object, tag.Type, tag.Tag,
+		tag.Tagger.GetName(), tag.Tagger.GetEmail(), date.Unix(), date.Format("-0700"),
+		tag.Message)
+
+	var signature bytes.Buffer
+	if err := signer.Sign(&signature, strings.NewReader(payload)); err != nil {
+		return err
+	}
+	tag.Message += signature.String()
+	return nil
+}
+
+// CreateTag creates a tool to create an annotated tag with the Git Data API.
+func CreateTag(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "create_tag",
+		Description: t("TOOL_CREATE_TAG_DESCRIPTION", "Create an annotated git tag in a GitHub repository, without a release. When the server signs commits, the tag is signed too and the signing identity is the tagger. Use create_release to tag a release."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_CREATE_TAG_USER_TITLE", "Create tag"),
+			ReadOnlyHint: false,
+		},
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"owner": {
+					Type:        "string",
+					Description: "Repository owner",
+				},
+				"repo": {
+					Type:        "string",
+					Description: "Repository name",
+				},
+				"tag": {
+					Type:        "string",
+					Description: "Tag name, e.g. 'v1.2.0'",
+				},
+				"sha": {
+					Type:        "string",
+					Description: "SHA of the object to tag, usually a commit",
+				},
+				"message": {
+					Type:        "string",
+					Description: "Tag message",
+				},
+				"object_type": {
+					Type:        "string",
+					Description: "Type of the tagged object",
+					Enum:        []any{"commit", "tree", "blob"},
+				},
+				"tagger_name": {
+					Type:        "string",
+					Description: "Name of the tagger. Requires tagger_email. Defaults to the authenticated user. Cannot be set when the server signs commits",
+				},
+				"tagger_email": {
+					Type:        "string",
+					Description: "Email of the tagger. Requires tagger_name",
+				},
+			},
+			Required: []string{"owner", "repo", "tag", "sha", "message"},
+		},
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		repo, err := RequiredParam[string](args, "repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		tagName, err := RequiredParam[string](args, "tag")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		sha, err := RequiredParam[string](args, "sha")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		message, err := RequiredParam[string](args, "message")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		objectType, err := OptionalParam[string](args, "object_type")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		if objectType == "" {
+			objectType = "commit"
+		}
+		taggerName, err := OptionalParam[string](args, "tagger_name")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		taggerEmail, err := OptionalParam[string](args, "tagger_email")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		if (taggerName == "") != (taggerEmail == "") {
+			return utils.NewToolResultError("tagger_name and tagger_email must be provided together"), nil, nil
+		}
+
+		tag := github.CreateTag{Tag: tagName, Message: message, Object: sha, Type: objectType}
+		signer, signed := signing.SignerFromContext(ctx)
+		switch {
+		case signed && taggerName != "":
+			return utils.NewToolResultError("tagger cannot be set because this server signs tags; the signing identity is always the tagger"), nil, nil
+		case signed:
+			if err := signTag(signer, &tag); err != nil {
+				return nil, nil, fmt.Errorf("failed to sign tag: %w", err)
+			}
+		case taggerName != "":
+			tag.Tagger = &github.CommitAuthor{
+				Name:  github.Ptr(taggerName),
+				Email: github.Ptr(taggerEmail),
+				Date:  &github.Timestamp{Time: time.Now().UTC().Truncate(time.Second)},
+			}
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		tagObj, resp, err := client.Git.CreateTag(ctx, owner, repo, tag)
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to create tag object", resp, err), nil, nil
+		}
+		_ = resp.Body.Close()
+
+		// The tag object is not visible as a tag until a ref points to it
+		ref, resp, err := client.Git.CreateRef(ctx, owner, repo, github.CreateRef{Ref: "refs/tags/" + tagName, SHA: tagObj.GetSHA()})
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to create tag reference", resp, err), nil, nil
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		return MarshalledTextResult(CreatedTag{
+			Tag:        tagName,
+			Ref:        ref.GetRef(),
+			SHA:        tagObj.GetSHA(),
+			ObjectSHA:  sha,
+			ObjectType: objectType,
+			Signed:     signed,
+		}), nil, nil
+	})
+
+	return tool, handler
+}
+
+// DeleteTag creates a tool to delete a tag from a repository.
+func DeleteTag(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "delete_tag",
+		Description: t("TOOL_DELETE_TAG_DESCRIPTION", "Delete a tag from a GitHub repository. A release of the tag becomes a draft."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:           t("TOOL_DELETE_TAG_USER_TITLE", "Delete tag"),
+			ReadOnlyHint:    false,
+			DestructiveHint: jsonschema.Ptr(true),
+		},
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"owner": {
+					Type:        "string",
+					Description: "Repository owner",
+				},
+				"repo": {
+					Type:        "string",
+					Description: "Repository name",
+				},
+				"tag": {
+					Type:        "string",
+					Description: "Tag name",
+				},
+			},
+			Required: []string{"owner", "repo", "tag"},
+		},
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		repo, err := RequiredParam[string](args, "repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		tag, err := RequiredParam[string](args, "tag")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		resp, err := client.Git.DeleteRef(ctx, owner, repo, "tags/"+tag)
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to delete tag", resp, err), nil, nil
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		return utils.NewToolResultText(fmt.Sprintf("Deleted tag %s from %s/%s", tag, owner, repo)), nil, nil
+	})
+
+	return tool, handler
+}
@@ -0,0 +1,148 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/signing"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v79/github"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CreateTag(t *testing.T) {
+	tool, _ := CreateTag(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	newHandler := func(t *testing.T, tagRequest *map[string]any) mcp.ToolHandlerFor[map[string]any, any] {
+		mockedClient := mock.NewMockedHTTPClient(
+			mock.WithRequestMatchHandler(
+				mock.PostReposGitTagsByOwnerByRepo,
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					require.NoError(t, json.NewDecoder(r.Body).Decode(tagRequest))
+					mockResponse(t, http.StatusCreated, &github.Tag{SHA: github.Ptr("tag-sha")})(w, r)
+				}),
+			),
+			mock.WithRequestMatchHandler(
+				mock.PostReposGitRefsByOwnerByRepo,
+				expectRequestBody(t, map[string]any{"ref": "refs/tags/v1.0.0", "sha": "tag-sha"}).andThen(
+					mockResponse(t, http.StatusCreated, &github.Reference{Ref: github.Ptr("refs/tags/v1.0.0")}),
+				),
+			),
+		)
+		_, handler := CreateTag(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+		return handler
+	}
+
+	t.Run("annotated tag", func(t *testing.T) {
+		var tagRequest map[string]any
+		handler := newHandler(t, &tagRequest)
+
+		args := map[string]any{
+			"owner":        "owner",
+			"repo":         "repo",
+			"tag":          "v1.0.0",
+			"sha":          "commit-sha",
+			"message":      "Release 1.0.0",
+			"tagger_name":  "Release Bot",
+			"tagger_email": "bot@example.com",
+		}
+		request := createMCPRequest(args)
+		result, _, err := handler(context.Background(), &request, args)
+		require.NoError(t, err)
+		require.False(t, result.IsError, getTextResult(t, result).Text)
+
+		assert.Equal(t, "Release 1.0.0", tagRequest["message"])
+		assert.Equal(t, "commit-sha", tagRequest["object"])
+		assert.Equal(t, "commit", tagRequest["type"])
+		assert.Equal(t, "bot@example.com", tagRequest["tagger"].(map[string]any)["email"])
+
+		var created CreatedTag
+		require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &created))
+		assert.Equal(t, CreatedTag{Tag: "v1.0.0", Ref: "refs/tags/v1.0.0", SHA: "tag-sha", ObjectSHA: "commit-sha", ObjectType: "commit"}, created)
+	})
+
+	t.Run("signed tag", func(t *testing.T) {
+		dir := t.TempDir()
+		program := filepath.Join(dir, "sign")
+		payloadFile := filepath.Join(dir, "payload")
+		require.NoError(t, os.WriteFile(program, []byte("#!/bin/sh\ncat > "+payloadFile+"\necho SIGNATURE\n"), 0o700))
+		signer, err := signing.New(signing.Config{Format: signing.FormatSSH, Key: "key", Program: program, Name: "Octo Cat", Email: "octocat@example.com"})
+		require.NoError(t, err)
+		ctx := signing.ContextWithSigner(context.Background(), signer)
+
+		var tagRequest map[string]any
+		handler := newHandler(t, &tagRequest)
+
+		args := map[string]any{"owner": "owner", "repo": "repo", "tag": "v1.0.0", "sha": "commit-sha", "message": "Release 1.0.0"}
+		request := createMCPRequest(args)
+		result, _, err := handler(ctx, &request, args)
+		require.NoError(t, err)
+		require.False(t, result.IsError, getTextResult(t, result).Text)
+
+		// The signature covers the tag object GitHub builds, and the message carries it
+		tagger := tagRequest["tagger"].(map[string]any)
+		assert.Equal(t, "octocat@example.com", tagger["email"])
+		date, err := time.Parse(time.RFC3339, tagger["date"].(string))
+		require.NoError(t, err)
+		payload, err := os.ReadFile(payloadFile)
+		require.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("object commit-sha\ntype commit\ntag v1.0.0\ntagger Octo Cat <octocat@example.com> %d +0000\n\nRelease 1.0.0\n", date.Unix()), string(payload))
+		assert.Equal(t, "Release 1.0.0\nSIGNATURE\n", tagRequest["message"])
+
+		var created CreatedTag
+		require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &created))
+		assert.True(t, created.Signed)
+
+		args["tagger_name"] = "Someone"
+		args["tagger_email"] = "someone@example.com"
+		request = createMCPRequest(args)
+		result, _, err = handler(ctx, &request, args)
+		require.NoError(t, err)
+		require.True(t, result.IsError)
+		assert.Contains(t, getErrorResult(t, result).Text, "the signing identity is always the tagger")
+	})
+
+	t.Run("tagger name without email", func(t *testing.T) {
+		_, handler := CreateTag(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+		args := map[string]any{"owner": "owner", "repo": "repo", "tag": "v1.0.0", "sha": "commit-sha", "message": "m", "tagger_name": "Bot"}
+		request := createMCPRequest(args)
+		result, _, err := handler(context.Background(), &request, args)
+		require.NoError(t, err)
+		require.True(t, result.IsError)
+		assert.Contains(t, getErrorResult(t, result).Text, "tagger_name and tagger_email must be provided together")
+	})
+}
+
+func Test_DeleteTag(t *testing.T) {
+	tool, _ := DeleteTag(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.True(t, *tool.Annotations.DestructiveHint)
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.DeleteReposGitRefsByOwnerByRepoByRef,
+			expectPath(t, "/repos/owner/repo/git/refs/tags/v1.0.0").andThen(
+				http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusNoContent) }),
+			),
+		),
+	)
+	_, handler := DeleteTag(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+	args := map[string]any{"owner": "owner", "repo": "repo", "tag": "v1.0.0"}
+	request := createMCPRequest(args)
+	result, _, err := handler(context.Background(), &request, args)
+	require.NoError(t, err)
+	require.False(t, result.IsError, getTextResult(t, result).Text)
+	assert.Equal(t, "Deleted tag v1.0.0 from owner/repo", getTextResult(t, result).Text)
+}
@@ -33,6 +33,10 @@ func Test_GetRepositoryTree(t *testing.T) {
 	assert.Contains(t, inputSchema.Properties, "tree_sha")
 	assert.Contains(t, inputSchema.Properties, "recursive")
 	assert.Contains(t, inputSchema.Properties, "path_filter")
+	assert.Contains(t, inputSchema.Properties, "path_prefix")
+	assert.Contains(t, inputSchema.Properties, "max_depth")
+	assert.Contains(t, inputSchema.Properties, "include")
+	assert.Contains(t, inputSchema.Properties, "exclude")
 	assert.ElementsMatch(t, inputSchema.Required, []string{"owner", "repo"})
 
 	// Setup mock data
@@ -194,3 +198,103 @@ func Test_GetRepositoryTree(t *testing.T) {
 		})
 	}
 }
+
+func Test_GetRepositoryTreeFilters(t *testing.T) {
+	paths := []string{
+		"README.md",
+		"src",
+		"src/main.go",
+		"src/main_test.go",
+		"src/internal",
+		"src/internal/util.go",
+		"src/vendor",
+		"src/vendor/lib.go",
+		"srcgen/gen.go",
+		"docs/guide.md",
+	}
+	entries := make([]*github.TreeEntry, 0, len(paths))
+	for _, path := range paths {
+		entryType := "blob"
+		if !strings.Contains(path, ".") {
+			entryType = "tree"
+		}
+		entries = append(entries, &github.TreeEntry{Path: github.Ptr(path), Type: github.Ptr(entryType), SHA: github.Ptr("sha")})
+	}
+
+	tests := []struct {
+		name          string
+		args          map[string]any
+		expectedPaths []string
+		expectError   string
+	}{
+		{
+			name:          "path prefix",
+			args:          map[string]any{"path_prefix": "src/"},
+			expectedPaths: []string{"src/main.go", "src/main_test.go", "src/internal", "src/internal/util.go", "src/vendor", "src/vendor/lib.go"},
+		},
+		{
+			name:          "max depth below path prefix",
+			args:          map[string]any{"path_prefix": "src", "max_depth": float64(1)},
+			expectedPaths: []string{"src/main.go", "src/main_test.go", "src/internal", "src/vendor"},
+		},
+		{
+			name:          "max depth from the root",
+			args:          map[string]any{"max_depth": float64(1)},
+			expectedPaths: []string{"README.md", "src"},
+		},
+		{
+			name:          "include and exclude globs",
+			args:          map[string]any{"include": []any{"*.go"}, "exclude": []any{"vendor/", "*_test.go", "srcgen"}},
+			expectedPaths: []string{"src/main.go", "src/internal/util.go"},
+		},
+		{
+			name:          "rooted glob",
+			args:          map[string]any{"include": []any{"docs/**/*.md"}},
+			expectedPaths: []string{"docs/guide.md"},
+		},
+		{
+			name:        "invalid max depth",
+			args:        map[string]any{"max_depth": float64(-1)},
+			expectError: "max_depth must be at least 1",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockedClient := mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.GetReposGitTreesByOwnerByRepoByTreeSha,
+					expectQueryParams(t, map[string]string{"recursive": "1"}).andThen(
+						mockResponse(t, http.StatusOK, &github.Tree{SHA: github.Ptr("abc123"), Truncated: github.Ptr(false), Entries: entries}),
+					),
+				),
+			)
+			_, handler := GetRepositoryTree(stubGetClientFromHTTPFn(mockedClient), translations.NullTranslationHelper)
+
+			args := map[string]any{"owner": "owner", "repo": "repo", "tree_sha": "main"}
+			for k, v := range tc.args {
+				args[k] = v
+			}
+			request := createMCPRequest(args)
+			result, _, err := handler(context.Background(), &request, args)
+			require.NoError(t, err)
+
+			if tc.expectError != "" {
+				require.True(t, result.IsError)
+				assert.Contains(t, getErrorResult(t, result).Text, tc.expectError)
+				return
+			}
+			require.False(t, result.IsError, getTextResult(t, result).Text)
+
+			var response TreeResponse
+			require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &response))
+			assert.True(t, response.Recursive)
+			var returnedPaths []string
+			for _, entry := range response.Tree {
+				returnedPaths = append(returnedPaths, entry.Path)
+			}
+			assert.Equal(t, tc.expectedPaths, returnedPaths)
+			assert.Equal(t, len(tc.expectedPaths), response.Count)
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	ghErrors "github.com/github/github-mcp-server/pkg/errors"
+	"github.com/google/go-github/v79/github"
+	"github.com/google/jsonschema-go/jsonschema"
+)
+
+// ignoreRule is one pattern line of a .gitignore file.
+type ignoreRule struct {
+	pattern *regexp.Regexp
+	// negate is set for patterns starting with '!', which include paths again
+	negate bool
+	// dirOnly is set for patterns ending with '/', which match only directories
+	dirOnly bool
+}
+
+// ignoreRules matches paths against .gitignore patterns, relative to the repository root.
+// Later rules take precedence over earlier ones, as in git.
+type ignoreRules []ignoreRule
+
+// respectGitignoreSchema describes the respect_gitignore parameter of the bulk push tools.
+func respectGitignoreSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:        "boolean",
+		Description: "Leave out files matching the .gitignore at the root of the target ref, such as node_modules/ and build output. Skipped files are listed in ignored_paths (default: false)",
+		Default:     json.RawMessage("false"),
+	}
+}
+
+// ignorePatternsSchema describes the ignore_patterns parameter of the bulk push tools.
+func ignorePatternsSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:        "array",
+		Description: "Patterns in .gitignore syntax of files to leave out, applied after the repository's .gitignore when respect_gitignore is set. Skipped files are listed in ignored_paths",
+		Items:       &jsonschema.Schema{Type: "string"},
+	}
+}
+
+// parseIgnorePatterns parses patterns in .gitignore syntax. Blank lines and comments are skipped.
+func parseIgnorePatterns(lines ...string) (ignoreRules, error) {
+	var rules ignoreRules
+	for _, line := range lines {
+		line = strings.TrimSuffix(line, "\r")
+		// Trailing spaces are ignored unless escaped with a backslash
+		if trimmed := strings.TrimRight(line, " "); strings.HasSuffix(trimmed, `\`) && trimmed != line {
+			line = trimmed[:len(trimmed)-1] + " "
+		} else {
+			line = trimmed
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var rule ignoreRule
+		if strings.HasPrefix(line, "!") {
+			rule.negate = true
+			line = line[1:]
+		} else if strings.HasPrefix(line, `\!`) || strings.HasPrefix(line, `\#`) {
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			rule.dirOnly = true
+			line = strings.TrimRight(line, "/")
+		}
+		if line == "" {
+			continue
+		}
+
+		pattern, err := compileGlob(line)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ignore pattern %q: %w", line, err)
+		}
+		rule.pattern = pattern
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// compileGlob compiles a glob matching whole paths. As in .gitignore, globs with a slash are
+// relative to the root while others match at any depth.
+func compileGlob(glob string) (*regexp.Regexp, error) {
+	prefix := "^(?:.*/)?"
+	if strings.Contains(glob, "/") {
+		prefix = "^"
+		glob = strings.TrimPrefix(glob, "/")
+	}
+	return regexp.Compile(prefix + globToRegexp(glob) + "$")
+}
+
+// globToRegexp translates a .gitignore glob to a regular expression. '*' and '?' do not match
+// slashes, while '**' matches any number of directories.
+func globToRegexp(glob string) string {
+	var b strings.Builder
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case strings.HasPrefix(glob[i:], "**/") && (i == 0 || glob[i-1] == '/'):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**") && i+2 == len(glob) && i > 0 && glob[i-1] == '/':
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i += end + 1
+		case c == '\\' && i+1 < len(glob):
+			i++
+			b.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		default:
+			b.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		}
+	}
+	return b.String()
+}
+
+// ignores reports whether path is ignored. As in git, a file in an ignored directory is ignored
+// even if a later pattern would include it again.
+func (r ignoreRules) ignores(path string) bool {
+	path = strings.Trim(path, "/")
+	for i := 0; i < len(path); i++ {
+		if path[i] == '/' && r.matches(path[:i], true) {
+			return true
+		}
+	}
+	return r.matches(path, false)
+}
+
+// matches returns whether the last rule matching path ignores it.
+func (r ignoreRules) matches(path string, isDir bool) bool {
+	ignored := false
+	for _, rule := range r {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		if rule.pattern.MatchString(path) {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
+
+// ignoreRulesFromArgs returns the ignore rules requested by respect_gitignore and ignore_patterns,
+// reading the .gitignore at ref of the repository if needed. It returns nil when neither is set.
+// A repository without a .gitignore contributes no rules.
+func ignoreRulesFromArgs(ctx context.Context, client *github.Client, owner, repo, ref string, args map[string]any) (ignoreRules, error) {
+	respectGitignore, err := OptionalParam[bool](args, "respect_gitignore")
+	if err != nil {
+		return nil, err
+	}
+	patterns, err := OptionalStringArrayParam(args, "ignore_patterns")
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []string
+	if respectGitignore {
+		file, _, resp, err := client.Repositories.GetContents(ctx, owner, repo, ".gitignore", &github.RepositoryContentGetOptions{Ref: ref})
+		switch {
+		case resp != nil && resp.StatusCode == http.StatusNotFound:
+		case err != nil:
+			_, _ = ghErrors.NewGitHubAPIErrorToCtx(ctx, "failed to get .gitignore", resp, err)
+			return nil, fmt.Errorf("failed to get .gitignore: %w", err)
+		default:
+			content, err := file.GetContent()
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode .gitignore: %w", err)
+			}
+			lines = strings.Split(content, "\n")
+		}
+		if resp != nil {
+			_ = resp.Body.Close()
+		}
+	}
+	lines = append(lines, patterns...)
+	return parseIgnorePatterns(lines...)
+}
+
+// filterIgnoredFiles removes the file objects whose path is ignored, returning the ignored paths.
+// Objects that are not files with a path are kept for validation to report.
+func filterIgnoredFiles(filesObj []interface{}, rules ignoreRules) ([]interface{}, []string) {
+	if len(rules) == 0 {
+		return filesObj, nil
+	}
+	kept := make([]interface{}, 0, len(filesObj))
+	var ignored []string
+	for _, obj := range filesObj {
+		if fileMap, ok := obj.(map[string]interface{}); ok {
+			if path, ok := fileMap["path"].(string); ok && path != "" && rules.ignores(path) {
+				ignored = append(ignored, path)
+				continue
+			}
+		}
+		kept = append(kept, obj)
+	}
+	return kept, ignored
+}
@@ -0,0 +1,155 @@
+package github
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v79/github"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIgnoreRules(t *testing.T) {
+	rules, err := parseIgnorePatterns(
+		"# dependencies",
+		"node_modules/",
+		"",
+		"/build",
+		"*.log",
+		"!keep.log",
+		"docs/**/*.tmp",
+		"secret?.txt",
+		"cache[0-9]/",
+		"vendor/",
+		"!vendor/modules.txt",
+		`\#notes`,
+		"trailing   ",
+	)
+	require.NoError(t, err)
+
+	tests := []struct {
+		path    string
+		ignored bool
+	}{
+		{"node_modules/react/index.js", true},
+		{"web/node_modules/react/index.js", true},
+		{"node_modules", false}, // a file, not a directory
+		{"build/app.js", true},
+		{"build", true},
+		{"src/build/app.js", false},
+		{"debug.log", true},
+		{"logs/server/debug.log", true},
+		{"keep.log", false},
+		{"docs/a.tmp", true},
+		{"docs/guide/v1/a.tmp", true},
+		{"src/docs/a.tmp", false},
+		{"secret1.txt", true},
+		{"secret12.txt", false},
+		{"cache7/data.bin", true},
+		{"cacheX/data.bin", false},
+		{"vendor/modules.txt", true}, // files in an ignored directory cannot be included again
+		{"#notes", true},
+		{"trailing", true},
+		{"src/main.go", false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.path, func(t *testing.T) {
+			assert.Equal(t, tc.ignored, rules.ignores(tc.path))
+		})
+	}
+}
+
+func Test_PushFilesChunked_IgnoredFiles(t *testing.T) {
+	gitignore := "node_modules/\n*.log\n"
+	contents := mock.WithRequestMatchHandler(
+		mock.GetReposContentsByOwnerByRepoByPath,
+		expectPath(t, "/repos/owner/repo/contents/.gitignore").andThen(
+			mockResponse(t, http.StatusOK, &github.RepositoryContent{
+				Type:     github.Ptr("file"),
+				Encoding: github.Ptr("base64"),
+				Content:  github.Ptr(base64.StdEncoding.EncodeToString([]byte(gitignore))),
+			}),
+		),
+	)
+	missing := mock.WithRequestMatchHandler(
+		mock.GetReposContentsByOwnerByRepoByPath,
+		mockResponse(t, http.StatusNotFound, map[string]string{"message": "Not Found"}),
+	)
+	files := []interface{}{
+		map[string]interface{}{"path": "index.js", "content": "console.log(1)"},
+		map[string]interface{}{"path": "node_modules/left-pad/index.js", "content": "module.exports = 1"},
+		map[string]interface{}{"path": "npm-debug.log", "content": "error"},
+		map[string]interface{}{"path": "dist/app.min.js", "content": "x"},
+	}
+
+	tests := []struct {
+		name            string
+		contents        mock.MockBackendOption
+		args            map[string]interface{}
+		expectedIgnored []string
+		expectedErrMsg  string
+	}{
+		{
+			name:            "respects the repository .gitignore",
+			contents:        contents,
+			args:            map[string]interface{}{"respect_gitignore": true},
+			expectedIgnored: []string{"node_modules/left-pad/index.js", "npm-debug.log"},
+		},
+		{
+			name:            "applies inline patterns after .gitignore",
+			contents:        contents,
+			args:            map[string]interface{}{"respect_gitignore": true, "ignore_patterns": []interface{}{"dist/", "!npm-debug.log"}},
+			expectedIgnored: []string{"node_modules/left-pad/index.js", "dist/app.min.js"},
+		},
+		{
+			name:            "repository without .gitignore",
+			contents:        missing,
+			args:            map[string]interface{}{"respect_gitignore": true, "ignore_patterns": []interface{}{"*.js"}},
+			expectedIgnored: []string{"index.js", "node_modules/left-pad/index.js", "dist/app.min.js"},
+		},
+		{
+			name:           "every file ignored",
+			contents:       missing,
+			args:           map[string]interface{}{"ignore_patterns": []interface{}{"*"}},
+			expectedErrMsg: "all 4 files are ignored",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client := github.NewClient(mock.NewMockedHTTPClient(append(mockGitDataAPI(t), tc.contents)...))
+			_, handler := PushFilesChunked(stubGetClientFn(client), translations.NullTranslationHelper)
+
+			args := map[string]interface{}{
+				"owner":   "owner",
+				"repo":    "repo",
+				"branch":  "main",
+				"files":   files,
+				"message": "Add files",
+			}
+			for k, v := range tc.args {
+				args[k] = v
+			}
+			request := createMCPRequest(args)
+			result, _, err := handler(context.Background(), &request, args)
+			require.NoError(t, err)
+
+			if tc.expectedErrMsg != "" {
+				require.True(t, result.IsError)
+				assert.Contains(t, getErrorResult(t, result).Text, tc.expectedErrMsg)
+				return
+			}
+
+			require.False(t, result.IsError, getTextResult(t, result).Text)
+			var response PushFilesChunkedResult
+			require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &response))
+			assert.Equal(t, tc.expectedIgnored, response.IgnoredPaths)
+			assert.Equal(t, len(files)-len(tc.expectedIgnored), response.TotalFiles)
+		})
+	}
+}
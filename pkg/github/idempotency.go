@@ -0,0 +1,169 @@
+package github
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/github/github-mcp-server/pkg/accounts"
+	"github.com/github/github-mcp-server/pkg/utils"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	// idempotencyCacheSize is the number of results remembered for replay
+	idempotencyCacheSize = 1000
+	// idempotencyKeyTTL is how long a result can be replayed
+	idempotencyKeyTTL = 24 * time.Hour
+)
+
+// idempotentResults holds the results of recent bulk writes made with an idempotency_key.
+var idempotentResults = newIdempotencyCache(idempotencyCacheSize, idempotencyKeyTTL)
+
+// idempotencyEntry is the state of one key: running until result is set.
+type idempotencyEntry struct {
+	key         string
+	fingerprint [sha256.Size]byte
+	result      *mcp.CallToolResult
+	expires     time.Time
+}
+
+// idempotencyCache is an LRU of idempotency entries with a TTL.
+type idempotencyCache struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	order   *list.List // front is most recently used
+	entries map[string]*list.Element
+}
+
+func newIdempotencyCache(size int, ttl time.Duration) *idempotencyCache {
+	return &idempotencyCache{
+		size:    size,
+		ttl:     ttl,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// begin claims key for a call with the given fingerprint. It returns the stored result if the
+// key already completed, or an error if the key is in use by a running call or was used with
+// different parameters. Otherwise the caller owns the key until it calls finish.
+func (c *idempotencyCache) begin(key string, fingerprint [sha256.Size]byte, now time.Time) (*mcp.CallToolResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*idempotencyEntry)
+		if entry.result == nil || now.Before(entry.expires) {
+			if entry.fingerprint != fingerprint {
+				return nil, fmt.Errorf("idempotency_key was already used with different parameters; use a new key for a different operation")
+			}
+			if entry.result == nil {
+				return nil, fmt.Errorf("a call with this idempotency_key is still running; retry once it has finished")
+			}
+			c.order.MoveToFront(elem)
+			return entry.result, nil
+		}
+		c.remove(elem)
+	}
+
+	c.entries[key] = c.order.PushFront(&idempotencyEntry{key: key, fingerprint: fingerprint})
+	for c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+	return nil, nil
+}
+
+// finish stores the result of the call that owns key. Error results are dropped so that the
+// call can be retried with the same key.
+func (c *idempotencyCache) finish(key string, result *mcp.CallToolResult, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return
+	}
+	if result == nil || result.IsError {
+		c.remove(elem)
+		return
+	}
+	entry := elem.Value.(*idempotencyEntry)
+	entry.result = result
+	entry.expires = now.Add(c.ttl)
+}
+
+func (c *idempotencyCache) remove(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.entries, elem.Value.(*idempotencyEntry).key)
+}
+
+// withIdempotencyKey adds the idempotency_key parameter to a bulk write tool. A repeated call
+// with the same key for the same repository and branch returns the result of the first call
+// instead of writing again, so that a client can safely retry after a timeout.
+func withIdempotencyKey(tool mcp.Tool, handler mcp.ToolHandlerFor[map[string]any, any]) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	if schema, ok := tool.InputSchema.(*jsonschema.Schema); ok {
+		schema.Properties["idempotency_key"] = &jsonschema.Schema{
+			Type:        "string",
+			Description: "Client-chosen unique key for this operation, e.g. a UUID. Retrying with the same key and parameters returns the original result instead of writing again",
+		}
+	}
+
+	wrapped := func(ctx context.Context, req *mcp.CallToolRequest, args map[string]any) (result *mcp.CallToolResult, out any, err error) {
+		key, err := OptionalParam[string](args, "idempotency_key")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		if key == "" {
+			return handler(ctx, req, args)
+		}
+
+		params := make(map[string]any, len(args))
+		for k, v := range args {
+			if k != "idempotency_key" {
+				params[k] = v
+			}
+		}
+		// Map keys are marshalled in sorted order, so equal parameters give equal fingerprints
+		encoded, err := json.Marshal(params)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to marshal parameters: %w", err)
+		}
+		owner, _ := OptionalParam[string](args, "owner")
+		repo, _ := OptionalParam[string](args, "repo")
+		branch, _ := OptionalParam[string](args, "branch")
+		if branch == "" {
+			branch, _ = OptionalParam[string](args, "ref")
+		}
+		// Keys are per account, so that no account replays the results of another
+		cacheKey := fmt.Sprintf("%s\x00%s\x00%s/%s\x00%s\x00%s", accounts.Key(ctx), tool.Name, owner, repo, branch, key)
+
+		stored, err := idempotentResults.begin(cacheKey, sha256.Sum256(encoded), time.Now())
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		if stored != nil {
+			replay := *stored
+			replay.Meta = mcp.Meta{"idempotent_replay": true}
+			return &replay, nil, nil
+		}
+
+		// A panicking call releases the key, which would otherwise stay running
+		defer func() {
+			if r := recover(); r != nil {
+				idempotentResults.finish(cacheKey, nil, time.Now())
+				result, out, err = nil, nil, fmt.Errorf("%s failed unexpectedly: %v", tool.Name, r)
+			}
+		}()
+		result, out, err = handler(ctx, req, args)
+		idempotentResults.finish(cacheKey, result, time.Now())
+		return result, out, err
+	}
+	return tool, wrapped
+}
@@ -0,0 +1,156 @@
+package github
+
+import (
+	"context"
+	"crypto/sha256"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/github/github-mcp-server/pkg/accounts"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/github/github-mcp-server/pkg/utils"
+	"github.com/google/go-github/v79/github"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_idempotencyCache(t *testing.T) {
+	now := time.Now()
+	cache := newIdempotencyCache(2, time.Hour)
+	fp := sha256.Sum256([]byte("params"))
+	ok := utils.NewToolResultText("ok")
+
+	stored, err := cache.begin("a", fp, now)
+	require.NoError(t, err)
+	assert.Nil(t, stored)
+
+	// A running call blocks duplicates
+	_, err = cache.begin("a", fp, now)
+	assert.ErrorContains(t, err, "still running")
+
+	cache.finish("a", ok, now)
+	stored, err = cache.begin("a", fp, now)
+	require.NoError(t, err)
+	assert.Same(t, ok, stored)
+
+	_, err = cache.begin("a", sha256.Sum256([]byte("other")), now)
+	assert.ErrorContains(t, err, "different parameters")
+
+	// Error results are dropped so that the call can be retried
+	_, err = cache.begin("b", fp, now)
+	require.NoError(t, err)
+	cache.finish("b", utils.NewToolResultError("failed"), now)
+	stored, err = cache.begin("b", fp, now)
+	require.NoError(t, err)
+	assert.Nil(t, stored)
+	cache.finish("b", ok, now)
+
+	// The least recently used key is evicted
+	_, err = cache.begin("a", fp, now)
+	require.NoError(t, err)
+	_, err = cache.begin("c", fp, now)
+	require.NoError(t, err)
+	_, hasA := cache.entries["a"]
+	_, hasB := cache.entries["b"]
+	assert.True(t, hasA)
+	assert.False(t, hasB)
+
+	// Expired results are not replayed
+	stored, err = cache.begin("a", fp, now.Add(2*time.Hour))
+	require.NoError(t, err)
+	assert.Nil(t, stored)
+}
+
+func Test_PushFilesChunked_IdempotencyKey(t *testing.T) {
+	t.Cleanup(func() { idempotentResults = newIdempotencyCache(idempotencyCacheSize, idempotencyKeyTTL) })
+
+	commits := 0
+	options := mockGitDataAPI(t)
+	options[3] = mock.WithRequestMatchHandler(
+		mock.PostReposGitCommitsByOwnerByRepo,
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			commits++
+			mockResponse(t, http.StatusCreated, &github.Commit{SHA: github.Ptr("new-commit-sha")})(w, r)
+		}),
+	)
+	client := github.NewClient(mock.NewMockedHTTPClient(options...))
+	_, handler := PushFilesChunked(stubGetClientFn(client), translations.NullTranslationHelper)
+
+	call := func(message, key string) *mcp.CallToolResult {
+		args := map[string]interface{}{
+			"owner":           "owner",
+			"repo":            "repo",
+			"branch":          "main",
+			"files":           []interface{}{map[string]interface{}{"path": "a.txt", "content": "a"}},
+			"message":         message,
+			"idempotency_key": key,
+		}
+		request := createMCPRequest(args)
+		result, _, err := handler(context.Background(), &request, args)
+		require.NoError(t, err)
+		return result
+	}
+
+	first := call("Add files", "key-1")
+	require.False(t, first.IsError)
+	assert.Equal(t, 1, commits)
+
+	// A retry with the same key returns the first result without pushing again
+	replay := call("Add files", "key-1")
+	require.False(t, replay.IsError)
+	assert.Equal(t, 1, commits)
+	assert.Equal(t, getTextResult(t, first).Text, getTextResult(t, replay).Text)
+	assert.Equal(t, true, replay.Meta["idempotent_replay"])
+
+	errResult := call("Other message", "key-1")
+	require.True(t, errResult.IsError)
+	assert.Contains(t, getErrorResult(t, errResult).Text, "different parameters")
+	assert.Equal(t, 1, commits)
+
+	require.False(t, call("Add files", "key-2").IsError)
+	assert.Equal(t, 2, commits)
+}
+
+func Test_withIdempotencyKey(t *testing.T) {
+	t.Cleanup(func() { idempotentResults = newIdempotencyCache(idempotencyCacheSize, idempotencyKeyTTL) })
+
+	calls := 0
+	panics := true
+	_, handler := withIdempotencyKey(mcp.Tool{Name: "write", InputSchema: &jsonschema.Schema{Type: "object", Properties: map[string]*jsonschema.Schema{}}},
+		func(_ context.Context, _ *mcp.CallToolRequest, _ map[string]any) (*mcp.CallToolResult, any, error) {
+			calls++
+			if panics {
+				panic("boom")
+			}
+			return utils.NewToolResultText("done"), nil, nil
+		})
+	call := func(ctx context.Context) (*mcp.CallToolResult, error) {
+		args := map[string]any{"owner": "owner", "repo": "repo", "idempotency_key": "key"}
+		request := createMCPRequest(args)
+		result, _, err := handler(ctx, &request, args)
+		return result, err
+	}
+
+	// A panicking call releases its key instead of leaving it running
+	_, err := call(context.Background())
+	assert.EqualError(t, err, "write failed unexpectedly: boom")
+	panics = false
+	result, err := call(context.Background())
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	assert.Equal(t, 2, calls)
+
+	// Each account has keys of its own
+	_, err = call(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+	ctx := accounts.ContextWithAccount(context.Background(), "work")
+	result, err = call(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, result.Meta)
+	assert.Equal(t, 3, calls)
+}
@@ -0,0 +1,290 @@
+package github
+
+import (
+	"context"
+	"fmt"
+
+	ghErrors "github.com/github/github-mcp-server/pkg/errors"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/github/github-mcp-server/pkg/utils"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/shurcooL/githubv4"
+)
+
+// ExternalIdentityAttributes are the attributes an identity provider supplied for a user,
+// through SAML single sign-on or SCIM provisioning.
+type ExternalIdentityAttributes struct {
+	NameID       string   `json:"name_id,omitempty"`
+	Username     string   `json:"username,omitempty"`
+	GivenName    string   `json:"given_name,omitempty"`
+	FamilyName   string   `json:"family_name,omitempty"`
+	PrimaryEmail string   `json:"primary_email,omitempty"`
+	Emails       []string `json:"emails,omitempty"`
+}
+
+// ExternalIdentity links a corporate directory identity to a GitHub account.
+// Login is empty when the identity has not been linked to an account yet.
+type ExternalIdentity struct {
+	GUID  string                      `json:"guid"`
+	Login string                      `json:"login,omitempty"`
+	Name  string                      `json:"name,omitempty"`
+	SAML  *ExternalIdentityAttributes `json:"saml,omitempty"`
+	SCIM  *ExternalIdentityAttributes `json:"scim,omitempty"`
+}
+
+type identityEmail struct {
+	Value   githubv4.String
+	Primary githubv4.Boolean
+}
+
+type externalIdentityNode struct {
+	GUID         githubv4.String `graphql:"guid"`
+	SamlIdentity *struct {
+		NameID     githubv4.String `graphql:"nameId"`
+		Username   githubv4.String
+		GivenName  githubv4.String
+		FamilyName githubv4.String
+		Emails     []identityEmail
+	}
+	ScimIdentity *struct {
+		Username   githubv4.String
+		GivenName  githubv4.String
+		FamilyName githubv4.String
+		Emails     []identityEmail
+	}
+	User *struct {
+		Login githubv4.String
+		Name  githubv4.String
+	}
+}
+
+type externalIdentitiesQuery struct {
+	Organization struct {
+		// SamlIdentityProvider is null when the organization has no SAML SSO configured
+		SamlIdentityProvider *struct {
+			SsoURL             githubv4.String `graphql:"ssoUrl"`
+			ExternalIdentities struct {
+				Nodes    []externalIdentityNode
+				PageInfo struct {
+					HasNextPage githubv4.Boolean
+					EndCursor   githubv4.String
+				}
+				TotalCount int
+			} `graphql:"externalIdentities(first: $first, after: $after, login: $login, userName: $userName, membersOnly: $membersOnly)"`
+		}
+	} `graphql:"organization(login: $org)"`
+}
+
+func identityAttributes(nameID, username, givenName, familyName githubv4.String, emails []identityEmail) *ExternalIdentityAttributes {
+	attrs := &ExternalIdentityAttributes{
+		NameID:     string(nameID),
+		Username:   string(username),
+		GivenName:  string(givenName),
+		FamilyName: string(familyName),
+	}
+	for _, email := range emails {
+		attrs.Emails = append(attrs.Emails, string(email.Value))
+		if email.Primary {
+			attrs.PrimaryEmail = string(email.Value)
+		}
+	}
+	return attrs
+}
+
+func convertExternalIdentity(node externalIdentityNode) ExternalIdentity {
+	identity := ExternalIdentity{GUID: string(node.GUID)}
+	if node.User != nil {
+		identity.Login = string(node.User.Login)
+		identity.Name = string(node.User.Name)
+	}
+	if saml := node.SamlIdentity; saml != nil {
+		identity.SAML = identityAttributes(saml.NameID, saml.Username, saml.GivenName, saml.FamilyName, saml.Emails)
+	}
+	if scim := node.ScimIdentity; scim != nil {
+		identity.SCIM = identityAttributes("", scim.Username, scim.GivenName, scim.FamilyName, scim.Emails)
+	}
+	return identity
+}
+
+// queryExternalIdentities runs the external identities query. Optional variables must be
+// pointers so that every lookup sends the same query document. The returned result is nil,
+// with an error result, if the query fails or the organization does not use SAML SSO.
+func queryExternalIdentities(ctx context.Context, client *githubv4.Client, org string, vars map[string]any) (*externalIdentitiesQuery, *mcp.CallToolResult) {
+	vars["org"] = githubv4.String(org)
+	for _, name := range []string{"after", "login", "userName"} {
+		if _, ok := vars[name]; !ok {
+			vars[name] = (*githubv4.String)(nil)
+		}
+	}
+	if _, ok := vars["membersOnly"]; !ok {
+		vars["membersOnly"] = (*githubv4.Boolean)(nil)
+	}
+
+	var q externalIdentitiesQuery
+	if err := client.Query(ctx, &q, vars); err != nil {
+		return nil, ghErrors.NewGitHubGraphQLErrorResponse(ctx, "failed to query external identities", err)
+	}
+	if q.Organization.SamlIdentityProvider == nil {
+		return nil, utils.NewToolResultError(fmt.Sprintf("organization %s does not have SAML single sign-on configured, or you are not an owner of it", org))
+	}
+	return &q, nil
+}
+
+// ListOrgExternalIdentities creates a tool to list the SAML/SCIM identities linked to an organization's members.
+func ListOrgExternalIdentities(getGQLClient GetGQLClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	return mcp.Tool{
+			Name:        "list_org_external_identities",
+			Description: t("TOOL_LIST_ORG_EXTERNAL_IDENTITIES_DESCRIPTION", "List the external identities (SAML single sign-on and SCIM provisioning) of an organization, mapping GitHub accounts to identities in the corporate directory. Requires organization owner access and an organization with SAML SSO enabled."),
+			Annotations: &mcp.ToolAnnotations{
+				Title:        t("TOOL_LIST_ORG_EXTERNAL_IDENTITIES_USER_TITLE", "List organization external identities"),
+				ReadOnlyHint: true,
+			},
+			InputSchema: WithCursorPagination(&jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"org": {
+						Type:        "string",
+						Description: "Organization login",
+					},
+					"members_only": {
+						Type:        "boolean",
+						Description: "Only return identities linked to current organization members",
+					},
+				},
+				Required: []string{"org"},
+			}),
+		},
+		func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+			org, err := RequiredParam[string](args, "org")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			membersOnly, err := OptionalParam[bool](args, "members_only")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			pagination, err := OptionalCursorPaginationParams(args)
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			paginationParams, err := pagination.ToGraphQLParams()
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+
+			client, err := getGQLClient(ctx)
+			if err != nil {
+				return utils.NewToolResultErrorFromErr("failed to get GitHub GQL client", err), nil, nil
+			}
+
+			vars := map[string]any{
+				"first": githubv4.Int(*paginationParams.First),
+			}
+			if paginationParams.After != nil {
+				vars["after"] = githubv4.NewString(githubv4.String(*paginationParams.After))
+			}
+			if membersOnly {
+				vars["membersOnly"] = githubv4.NewBoolean(true)
+			}
+
+			q, errResult := queryExternalIdentities(ctx, client, org, vars)
+			if errResult != nil {
+				return errResult, nil, nil
+			}
+
+			provider := q.Organization.SamlIdentityProvider
+			identities := make([]ExternalIdentity, 0, len(provider.ExternalIdentities.Nodes))
+			for _, node := range provider.ExternalIdentities.Nodes {
+				identities = append(identities, convertExternalIdentity(node))
+			}
+
+			return MarshalledTextResult(map[string]any{
+				"sso_url":    string(provider.SsoURL),
+				"identities": identities,
+				"pageInfo": map[string]any{
+					"hasNextPage": provider.ExternalIdentities.PageInfo.HasNextPage,
+					"endCursor":   string(provider.ExternalIdentities.PageInfo.EndCursor),
+				},
+				"totalCount": provider.ExternalIdentities.TotalCount,
+			}), nil, nil
+		}
+}
+
+// GetOrgExternalIdentity creates a tool to look up the external identity of a single user,
+// either from their GitHub login or from their identity provider username.
+func GetOrgExternalIdentity(getGQLClient GetGQLClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	return mcp.Tool{
+			Name:        "get_org_external_identity",
+			Description: t("TOOL_GET_ORG_EXTERNAL_IDENTITY_DESCRIPTION", "Look up the external identity (SAML single sign-on and SCIM provisioning) of a single user in an organization. Provide either the GitHub login to find their directory identity, or the identity provider username (SAML NameID or SCIM userName) to find their GitHub account."),
+			Annotations: &mcp.ToolAnnotations{
+				Title:        t("TOOL_GET_ORG_EXTERNAL_IDENTITY_USER_TITLE", "Get organization external identity"),
+				ReadOnlyHint: true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"org": {
+						Type:        "string",
+						Description: "Organization login",
+					},
+					"login": {
+						Type:        "string",
+						Description: "GitHub login of the user",
+					},
+					"user_name": {
+						Type:        "string",
+						Description: "Identity provider username: SAML NameID or SCIM userName",
+					},
+				},
+				Required: []string{"org"},
+			},
+		},
+		func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+			org, err := RequiredParam[string](args, "org")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			login, err := OptionalParam[string](args, "login")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			userName, err := OptionalParam[string](args, "user_name")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			if (login == "") == (userName == "") {
+				return utils.NewToolResultError("provide exactly one of login or user_name"), nil, nil
+			}
+
+			client, err := getGQLClient(ctx)
+			if err != nil {
+				return utils.NewToolResultErrorFromErr("failed to get GitHub GQL client", err), nil, nil
+			}
+
+			vars := map[string]any{
+				"first": githubv4.Int(1),
+			}
+			if login != "" {
+				vars["login"] = githubv4.NewString(githubv4.String(login))
+			} else {
+				vars["userName"] = githubv4.NewString(githubv4.String(userName))
+			}
+
+			q, errResult := queryExternalIdentities(ctx, client, org, vars)
+			if errResult != nil {
+				return errResult, nil, nil
+			}
+
+			nodes := q.Organization.SamlIdentityProvider.ExternalIdentities.Nodes
+			if len(nodes) == 0 {
+				lookup := login
+				if lookup == "" {
+					lookup = userName
+				}
+				return utils.NewToolResultError(fmt.Sprintf("no external identity found for %s in organization %s", lookup, org)), nil, nil
+			}
+
+			return MarshalledTextResult(convertExternalIdentity(nodes[0])), nil, nil
+		}
+}
@@ -0,0 +1,236 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/github/github-mcp-server/internal/githubv4mock"
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const externalIdentitiesQueryString = "query($after:String$first:Int!$login:String$membersOnly:Boolean$org:String!$userName:String){organization(login: $org){samlIdentityProvider{ssoUrl,externalIdentities(first: $first, after: $after, login: $login, userName: $userName, membersOnly: $membersOnly){nodes{guid,samlIdentity{nameId,username,givenName,familyName,emails{value,primary}},scimIdentity{username,givenName,familyName,emails{value,primary}},user{login,name}},pageInfo{hasNextPage,endCursor},totalCount}}}}"
+
+func externalIdentitiesResponse(provider any) githubv4mock.GQLResponse {
+	return githubv4mock.DataResponse(map[string]any{
+		"organization": map[string]any{
+			"samlIdentityProvider": provider,
+		},
+	})
+}
+
+var mockExternalIdentityNode = map[string]any{
+	"guid": "guid-1",
+	"samlIdentity": map[string]any{
+		"nameId":     "jdoe@corp.example",
+		"username":   "jdoe",
+		"givenName":  "Jane",
+		"familyName": "Doe",
+		"emails": []any{
+			map[string]any{"value": "jane.doe@corp.example", "primary": true},
+		},
+	},
+	"scimIdentity": nil,
+	"user": map[string]any{
+		"login": "janedoe",
+		"name":  "Jane Doe",
+	},
+}
+
+func Test_ListOrgExternalIdentities(t *testing.T) {
+	toolDef, _ := ListOrgExternalIdentities(nil, translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(toolDef.Name, toolDef))
+
+	schema, ok := toolDef.InputSchema.(*jsonschema.Schema)
+	require.True(t, ok, "InputSchema should be *jsonschema.Schema")
+	assert.Equal(t, "list_org_external_identities", toolDef.Name)
+	assert.Contains(t, schema.Properties, "members_only")
+	assert.Contains(t, schema.Properties, "after")
+	assert.ElementsMatch(t, schema.Required, []string{"org"})
+
+	vars := map[string]any{
+		"org":         "corp",
+		"first":       float64(30),
+		"after":       (*string)(nil),
+		"login":       (*string)(nil),
+		"userName":    (*string)(nil),
+		"membersOnly": true,
+	}
+
+	tests := []struct {
+		name           string
+		response       githubv4mock.GQLResponse
+		expectError    bool
+		expectedErrMsg string
+	}{
+		{
+			name: "lists identities",
+			response: externalIdentitiesResponse(map[string]any{
+				"ssoUrl": "https://idp.example/sso",
+				"externalIdentities": map[string]any{
+					"nodes":      []any{mockExternalIdentityNode},
+					"pageInfo":   map[string]any{"hasNextPage": true, "endCursor": "cursor-1"},
+					"totalCount": 12,
+				},
+			}),
+		},
+		{
+			name:           "organization without SAML",
+			response:       externalIdentitiesResponse(nil),
+			expectError:    true,
+			expectedErrMsg: "does not have SAML single sign-on configured",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			matcher := githubv4mock.NewQueryMatcher(externalIdentitiesQueryString, vars, tc.response)
+			gqlClient := githubv4.NewClient(githubv4mock.NewMockedHTTPClient(matcher))
+			_, handler := ListOrgExternalIdentities(stubGetGQLClientFn(gqlClient), translations.NullTranslationHelper)
+
+			args := map[string]any{"org": "corp", "members_only": true}
+			request := createMCPRequest(args)
+			result, _, err := handler(context.Background(), &request, args)
+			require.NoError(t, err)
+
+			if tc.expectError {
+				assert.Contains(t, getErrorResult(t, result).Text, tc.expectedErrMsg)
+				return
+			}
+
+			require.False(t, result.IsError, getTextResult(t, result).Text)
+			var response struct {
+				SSOURL     string             `json:"sso_url"`
+				Identities []ExternalIdentity `json:"identities"`
+				PageInfo   struct {
+					HasNextPage bool   `json:"hasNextPage"`
+					EndCursor   string `json:"endCursor"`
+				} `json:"pageInfo"`
+				TotalCount int `json:"totalCount"`
+			}
+			require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &response))
+			assert.Equal(t, "https://idp.example/sso", response.SSOURL)
+			assert.Equal(t, 12, response.TotalCount)
+			assert.True(t, response.PageInfo.HasNextPage)
+			assert.Equal(t, "cursor-1", response.PageInfo.EndCursor)
+			require.Len(t, response.Identities, 1)
+			assert.Equal(t, ExternalIdentity{
+				GUID:  "guid-1",
+				Login: "janedoe",
+				Name:  "Jane Doe",
+				SAML: &ExternalIdentityAttributes{
+					NameID:       "jdoe@corp.example",
+					Username:     "jdoe",
+					GivenName:    "Jane",
+					FamilyName:   "Doe",
+					PrimaryEmail: "jane.doe@corp.example",
+					Emails:       []string{"jane.doe@corp.example"},
+				},
+			}, response.Identities[0])
+		})
+	}
+}
+
+func Test_GetOrgExternalIdentity(t *testing.T) {
+	toolDef, _ := GetOrgExternalIdentity(nil, translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(toolDef.Name, toolDef))
+
+	schema, ok := toolDef.InputSchema.(*jsonschema.Schema)
+	require.True(t, ok, "InputSchema should be *jsonschema.Schema")
+	assert.Equal(t, "get_org_external_identity", toolDef.Name)
+	assert.ElementsMatch(t, schema.Required, []string{"org"})
+
+	found := externalIdentitiesResponse(map[string]any{
+		"ssoUrl": "https://idp.example/sso",
+		"externalIdentities": map[string]any{
+			"nodes":      []any{mockExternalIdentityNode},
+			"pageInfo":   map[string]any{"hasNextPage": false, "endCursor": ""},
+			"totalCount": 1,
+		},
+	})
+	notFound := externalIdentitiesResponse(map[string]any{
+		"ssoUrl": "https://idp.example/sso",
+		"externalIdentities": map[string]any{
+			"nodes":      []any{},
+			"pageInfo":   map[string]any{"hasNextPage": false, "endCursor": ""},
+			"totalCount": 0,
+		},
+	})
+
+	tests := []struct {
+		name           string
+		args           map[string]any
+		vars           map[string]any
+		response       githubv4mock.GQLResponse
+		expectError    bool
+		expectedErrMsg string
+	}{
+		{
+			name: "by identity provider username",
+			args: map[string]any{"org": "corp", "user_name": "jdoe@corp.example"},
+			vars: map[string]any{
+				"org":         "corp",
+				"first":       float64(1),
+				"after":       (*string)(nil),
+				"login":       (*string)(nil),
+				"userName":    "jdoe@corp.example",
+				"membersOnly": (*bool)(nil),
+			},
+			response: found,
+		},
+		{
+			name: "no identity for login",
+			args: map[string]any{"org": "corp", "login": "ghost"},
+			vars: map[string]any{
+				"org":         "corp",
+				"first":       float64(1),
+				"after":       (*string)(nil),
+				"login":       "ghost",
+				"userName":    (*string)(nil),
+				"membersOnly": (*bool)(nil),
+			},
+			response:       notFound,
+			expectError:    true,
+			expectedErrMsg: "no external identity found for ghost in organization corp",
+		},
+		{
+			name:           "requires exactly one lookup key",
+			args:           map[string]any{"org": "corp", "login": "janedoe", "user_name": "jdoe"},
+			expectError:    true,
+			expectedErrMsg: "provide exactly one of login or user_name",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var gqlClient *githubv4.Client
+			if tc.vars != nil {
+				matcher := githubv4mock.NewQueryMatcher(externalIdentitiesQueryString, tc.vars, tc.response)
+				gqlClient = githubv4.NewClient(githubv4mock.NewMockedHTTPClient(matcher))
+			}
+			_, handler := GetOrgExternalIdentity(stubGetGQLClientFn(gqlClient), translations.NullTranslationHelper)
+
+			request := createMCPRequest(tc.args)
+			result, _, err := handler(context.Background(), &request, tc.args)
+			require.NoError(t, err)
+
+			if tc.expectError {
+				assert.Contains(t, getErrorResult(t, result).Text, tc.expectedErrMsg)
+				return
+			}
+
+			require.False(t, result.IsError, getTextResult(t, result).Text)
+			var identity ExternalIdentity
+			require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &identity))
+			assert.Equal(t, "janedoe", identity.Login)
+			require.NotNil(t, identity.SAML)
+			assert.Equal(t, "jdoe@corp.example", identity.SAML.NameID)
+			assert.Nil(t, identity.SCIM)
+		})
+	}
+}
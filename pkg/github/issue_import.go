@@ -0,0 +1,605 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	ghErrors "github.com/github/github-mcp-server/pkg/errors"
+	"github.com/github/github-mcp-server/pkg/ratelimit"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/github/github-mcp-server/pkg/utils"
+	"github.com/google/go-github/v79/github"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// maxImportIssues caps the issues imported by one call
+const maxImportIssues = 100
+
+// issueImportLimiters pace the requests of import_issues across calls, per token or
+// installation, so that bulk migrations stay clear of the secondary rate limits on content
+// creation.
+var issueImportLimiters = ratelimit.NewRegistry(nil, ratelimit.DefaultIdleTimeout)
+
+// issueImportRetryConfig is the retry behavior of the requests of import_issues. Only
+// secondary rate limits are retried, since a request that failed otherwise may still have
+// created the issue or comment. Tests shorten the backoff.
+var issueImportRetryConfig = func() ratelimit.RetryConfig {
+	cfg := ratelimit.DefaultRetryConfig()
+	cfg.Retryable = isSecondaryRateLimit
+	return cfg
+}()
+
+// issueImportPolling configures how long import_issues waits for the legacy
+// import API to finish processing an issue. Tests shorten the interval.
+var issueImportPolling = struct {
+	Attempts int
+	Interval time.Duration
+}{Attempts: 10, Interval: time.Second}
+
+// issueImportMediaType is the media type of the legacy issue import API
+const issueImportMediaType = "application/vnd.github.golden-comet-preview+json"
+
+// ImportIssueComment is a comment of an issue to import.
+type ImportIssueComment struct {
+	Body      string     `json:"body"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+}
+
+// ImportIssueSpec is an issue to import.
+type ImportIssueSpec struct {
+	SourceID  string
+	Title     string
+	Body      string
+	Labels    []string
+	Assignees []string
+	Milestone int
+	Comments  []ImportIssueComment
+	Closed    bool
+	CreatedAt *time.Time
+	ClosedAt  *time.Time
+}
+
+// ImportedIssue reports the outcome of importing one issue.
+type ImportedIssue struct {
+	SourceID string `json:"source_id"`
+	// Status is "created" or "imported" on success, "pending" when the import API
+	// had not finished processing the issue in time, and "failed" otherwise
+	Status   string   `json:"status"`
+	Number   int      `json:"number,omitempty"`
+	URL      string   `json:"url,omitempty"`
+	ImportID int      `json:"import_id,omitempty"`
+	Error    string   `json:"error,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// ImportIssuesResult reports the outcome of an import_issues call.
+type ImportIssuesResult struct {
+	Imported int `json:"imported"`
+	Pending  int `json:"pending"`
+	Failed   int `json:"failed"`
+	// Mapping maps the source ID of each imported issue to its issue number
+	Mapping map[string]int  `json:"mapping"`
+	Items   []ImportedIssue `json:"items"`
+}
+
+// issueImportStatus is the status of an issue submitted to the legacy import API.
+// It carries issue_url, which go-github's IssueImportResponse omits.
+type issueImportStatus struct {
+	ID       int                        `json:"id"`
+	Status   string                     `json:"status"`
+	IssueURL string                     `json:"issue_url"`
+	Errors   []*github.IssueImportError `json:"errors"`
+}
+
+// ImportIssues creates a tool to create issues in bulk, e.g. when migrating from another tracker.
+func ImportIssues(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	timestamp := &jsonschema.Schema{
+		Type:        "string",
+		Description: "ISO 8601 timestamp (YYYY-MM-DDThh:mm:ssZ or YYYY-MM-DD). Only kept with use_import_api",
+	}
+
+	tool := mcp.Tool{
+		Name: "import_issues",
+		Description: t("TOOL_IMPORT_ISSUES_DESCRIPTION", fmt.Sprintf("Create up to %d issues with their labels, assignees, comments and state, e.g. when migrating from another tracker. Requests are paced to stay within rate limits and retried after secondary rate limits. "+
+			"With use_import_api the issues go through GitHub's issue import API, which keeps their original timestamps and creates each issue with its comments at once. "+
+			"Returns the issue number created for each source ID", maxImportIssues)),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_IMPORT_ISSUES_USER_TITLE", "Import issues"),
+			ReadOnlyHint: false,
+		},
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"owner": {
+					Type:        "string",
+					Description: "Repository owner",
+				},
+				"repo": {
+					Type:        "string",
+					Description: "Repository name",
+				},
+				"issues": {
+					Type:        "array",
+					Description: "Issues to create, in order",
+					Items: &jsonschema.Schema{
+						Type: "object",
+						Properties: map[string]*jsonschema.Schema{
+							"source_id": {
+								Type:        "string",
+								Description: "Unique ID of the issue in the source tracker, used to report the created issue number",
+							},
+							"title": {
+								Type:        "string",
+								Description: "Issue title",
+							},
+							"body": {
+								Type:        "string",
+								Description: "Issue body",
+							},
+							"labels": {
+								Type:        "array",
+								Description: "Labels to apply",
+								Items:       &jsonschema.Schema{Type: "string"},
+							},
+							"assignees": {
+								Type:        "array",
+								Description: "Usernames to assign. The import API only supports one assignee",
+								Items:       &jsonschema.Schema{Type: "string"},
+							},
+							"milestone": {
+								Type:        "number",
+								Description: "Milestone number",
+							},
+							"comments": {
+								Type:        "array",
+								Description: "Comments to add, in order",
+								Items: &jsonschema.Schema{
+									Type: "object",
+									Properties: map[string]*jsonschema.Schema{
+										"body": {
+											Type:        "string",
+											Description: "Comment body",
+										},
+										"created_at": timestamp,
+									},
+									Required: []string{"body"},
+								},
+							},
+							"closed": {
+								Type:        "boolean",
+								Description: "Close the issue after creating it",
+							},
+							"created_at": timestamp,
+							"closed_at":  timestamp,
+						},
+						Required: []string{"source_id", "title"},
+					},
+				},
+				"use_import_api": {
+					Type:        "boolean",
+					Description: "Create the issues through the issue import API to keep created_at and closed_at timestamps (default: false)",
+				},
+				"continue_on_error": {
+					Type:        "boolean",
+					Description: "Keep importing the remaining issues after one fails (default: true)",
+				},
+			},
+			Required: []string{"owner", "repo", "issues"},
+		},
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		repo, err := RequiredParam[string](args, "repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		specs, err := parseImportIssueSpecs(args["issues"])
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		useImportAPI, err := OptionalParam[bool](args, "use_import_api")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		continueOnError := true
+		if _, ok := args["continue_on_error"]; ok {
+			continueOnError, err = OptionalParam[bool](args, "continue_on_error")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		result := ImportIssuesResult{
+			Mapping: make(map[string]int, len(specs)),
+			Items:   make([]ImportedIssue, 0, len(specs)),
+		}
+		// Imports are bulk work, which interactive calls get ahead of when quota is tight
+		ctx = ratelimit.WithPriority(ctx, ratelimit.PriorityLow)
+		ctx = ratelimit.WithLimiters(ctx, issueImportLimiters)
+		// All the requests share one retry budget, and the import stops once it is exhausted
+		ctx, _ = withRetryBudget(ctx)
+		for _, spec := range specs {
+			var item ImportedIssue
+			if useImportAPI {
+				item, err = submitIssueImport(ctx, client, owner, repo, spec)
+			} else {
+				item, err = createImportIssue(ctx, client, owner, repo, spec)
+			}
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil, nil, ctx.Err()
+				}
+				item.Status = "failed"
+				item.Error = err.Error()
+			}
+			result.Items = append(result.Items, item)
+			if err != nil && (!continueOnError || errors.Is(err, ratelimit.ErrRetryBudgetExhausted)) {
+				break
+			}
+		}
+
+		if useImportAPI {
+			if err := awaitIssueImports(ctx, client, owner, repo, result.Items); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		for _, item := range result.Items {
+			switch item.Status {
+			case "created", "imported":
+				result.Imported++
+				result.Mapping[item.SourceID] = item.Number
+			case "pending":
+				result.Pending++
+			default:
+				result.Failed++
+			}
+		}
+		return MarshalledTextResult(result), nil, nil
+	})
+
+	return tool, handler
+}
+
+// parseImportIssueSpecs validates the issues parameter of import_issues.
+func parseImportIssueSpecs(raw any) ([]ImportIssueSpec, error) {
+	if raw == nil {
+		return nil, fmt.Errorf("missing required parameter: issues")
+	}
+	items, ok := raw.([]any)
+	if !ok {
+		return nil, fmt.Errorf("issues must be an array of objects")
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("issues must contain at least one issue")
+	}
+	if len(items) > maxImportIssues {
+		return nil, fmt.Errorf("too many issues: %d (maximum %d per call)", len(items), maxImportIssues)
+	}
+
+	specs := make([]ImportIssueSpec, 0, len(items))
+	seen := make(map[string]bool, len(items))
+	for i, raw := range items {
+		obj, ok := raw.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("issues[%d] must be an object", i)
+		}
+		var spec ImportIssueSpec
+		var err error
+		if spec.SourceID, err = RequiredParam[string](obj, "source_id"); err != nil {
+			return nil, fmt.Errorf("issues[%d]: %w", i, err)
+		}
+		if seen[spec.SourceID] {
+			return nil, fmt.Errorf("issues[%d]: source_id %s is used more than once", i, spec.SourceID)
+		}
+		seen[spec.SourceID] = true
+		if spec.Title, err = RequiredParam[string](obj, "title"); err != nil {
+			return nil, fmt.Errorf("issues[%d]: %w", i, err)
+		}
+		if spec.Body, err = OptionalParam[string](obj, "body"); err != nil {
+			return nil, fmt.Errorf("issues[%d]: %w", i, err)
+		}
+		if spec.Labels, err = OptionalStringArrayParam(obj, "labels"); err != nil {
+			return nil, fmt.Errorf("issues[%d]: %w", i, err)
+		}
+		if spec.Assignees, err = OptionalStringArrayParam(obj, "assignees"); err != nil {
+			return nil, fmt.Errorf("issues[%d]: %w", i, err)
+		}
+		if spec.Milestone, err = OptionalIntParam(obj, "milestone"); err != nil {
+			return nil, fmt.Errorf("issues[%d]: %w", i, err)
+		}
+		if spec.Closed, err = OptionalParam[bool](obj, "closed"); err != nil {
+			return nil, fmt.Errorf("issues[%d]: %w", i, err)
+		}
+		if spec.CreatedAt, err = optionalImportTimestamp(obj, "created_at"); err != nil {
+			return nil, fmt.Errorf("issues[%d]: %w", i, err)
+		}
+		if spec.ClosedAt, err = optionalImportTimestamp(obj, "closed_at"); err != nil {
+			return nil, fmt.Errorf("issues[%d]: %w", i, err)
+		}
+		if spec.ClosedAt != nil && !spec.Closed {
+			return nil, fmt.Errorf("issues[%d]: closed_at requires closed to be true", i)
+		}
+
+		if rawComments, ok := obj["comments"]; ok && rawComments != nil {
+			comments, ok := rawComments.([]any)
+			if !ok {
+				return nil, fmt.Errorf("issues[%d]: comments must be an array of objects", i)
+			}
+			for j, rawComment := range comments {
+				commentObj, ok := rawComment.(map[string]any)
+				if !ok {
+					return nil, fmt.Errorf("issues[%d].comments[%d] must be an object", i, j)
+				}
+				var comment ImportIssueComment
+				if comment.Body, err = RequiredParam[string](commentObj, "body"); err != nil {
+					return nil, fmt.Errorf("issues[%d].comments[%d]: %w", i, j, err)
+				}
+				if comment.CreatedAt, err = optionalImportTimestamp(commentObj, "created_at"); err != nil {
+					return nil, fmt.Errorf("issues[%d].comments[%d]: %w", i, j, err)
+				}
+				spec.Comments = append(spec.Comments, comment)
+			}
+		}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+// optionalImportTimestamp parses the optional ISO 8601 timestamp named key of obj.
+func optionalImportTimestamp(obj map[string]any, key string) (*time.Time, error) {
+	value, err := OptionalParam[string](obj, key)
+	if err != nil || value == "" {
+		return nil, err
+	}
+	ts, err := parseISOTimestamp(value)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", key, err)
+	}
+	return &ts, nil
+}
+
+// createImportIssue creates spec through the issues API, then adds its comments and closes it.
+// Timestamps cannot be set this way and are reported as warnings.
+func createImportIssue(ctx context.Context, client *github.Client, owner, repo string, spec ImportIssueSpec) (ImportedIssue, error) {
+	item := ImportedIssue{SourceID: spec.SourceID}
+	if spec.CreatedAt != nil || spec.ClosedAt != nil {
+		item.Warnings = append(item.Warnings, "timestamps are only kept with use_import_api")
+	}
+
+	request := &github.IssueRequest{
+		Title: github.Ptr(spec.Title),
+		Body:  github.Ptr(spec.Body),
+	}
+	if len(spec.Labels) > 0 {
+		request.Labels = &spec.Labels
+	}
+	if len(spec.Assignees) > 0 {
+		request.Assignees = &spec.Assignees
+	}
+	if spec.Milestone > 0 {
+		request.Milestone = github.Ptr(spec.Milestone)
+	}
+
+	var issue *github.Issue
+	resp, err := importRequest(ctx, func() (resp *github.Response, err error) {
+		issue, resp, err = client.Issues.Create(ctx, owner, repo, request)
+		return resp, err
+	})
+	if err != nil {
+		return item, importAPIError(ctx, "failed to create issue", resp, err)
+	}
+	_ = resp.Body.Close()
+	item.Number = issue.GetNumber()
+	item.URL = issue.GetHTMLURL()
+
+	for i, comment := range spec.Comments {
+		resp, err := importRequest(ctx, func() (resp *github.Response, err error) {
+			_, resp, err = client.Issues.CreateComment(ctx, owner, repo, item.Number, &github.IssueComment{Body: github.Ptr(comment.Body)})
+			return resp, err
+		})
+		if err != nil {
+			return item, importAPIError(ctx, fmt.Sprintf("issue #%d was created but adding comment %d failed", item.Number, i), resp, err)
+		}
+		_ = resp.Body.Close()
+	}
+
+	if spec.Closed {
+		resp, err := importRequest(ctx, func() (resp *github.Response, err error) {
+			_, resp, err = client.Issues.Edit(ctx, owner, repo, item.Number, &github.IssueRequest{State: github.Ptr("closed")})
+			return resp, err
+		})
+		if err != nil {
+			return item, importAPIError(ctx, fmt.Sprintf("issue #%d was created but closing it failed", item.Number), resp, err)
+		}
+		_ = resp.Body.Close()
+	}
+
+	item.Status = "created"
+	return item, nil
+}
+
+// submitIssueImport submits spec to the legacy issue import API, which processes it asynchronously.
+func submitIssueImport(ctx context.Context, client *github.Client, owner, repo string, spec ImportIssueSpec) (ImportedIssue, error) {
+	item := ImportedIssue{SourceID: spec.SourceID}
+
+	issue := github.IssueImport{
+		Title:  spec.Title,
+		Body:   spec.Body,
+		Closed: github.Ptr(spec.Closed),
+	}
+	if len(spec.Labels) > 0 {
+		issue.Labels = spec.Labels
+	}
+	if len(spec.Assignees) > 0 {
+		issue.Assignee = github.Ptr(spec.Assignees[0])
+		if len(spec.Assignees) > 1 {
+			item.Warnings = append(item.Warnings, fmt.Sprintf("the import API supports one assignee; only %s was assigned", spec.Assignees[0]))
+		}
+	}
+	if spec.Milestone > 0 {
+		issue.Milestone = github.Ptr(spec.Milestone)
+	}
+	if spec.CreatedAt != nil {
+		issue.CreatedAt = &github.Timestamp{Time: *spec.CreatedAt}
+	}
+	if spec.ClosedAt != nil {
+		issue.ClosedAt = &github.Timestamp{Time: *spec.ClosedAt}
+	}
+
+	request := &github.IssueImportRequest{IssueImport: issue}
+	for _, comment := range spec.Comments {
+		c := &github.Comment{Body: comment.Body}
+		if comment.CreatedAt != nil {
+			c.CreatedAt = &github.Timestamp{Time: *comment.CreatedAt}
+		}
+		request.Comments = append(request.Comments, c)
+	}
+
+	// The import API answers 202 Accepted, which go-github reports as an AcceptedError
+	// alongside the decoded response
+	var response *github.IssueImportResponse
+	resp, err := importRequest(ctx, func() (resp *github.Response, err error) {
+		response, resp, err = client.IssueImport.Create(ctx, owner, repo, request)
+		var accepted *github.AcceptedError
+		if errors.As(err, &accepted) {
+			err = nil
+		}
+		return resp, err
+	})
+	if err != nil {
+		return item, importAPIError(ctx, "failed to submit issue import", resp, err)
+	}
+	_ = resp.Body.Close()
+
+	item.ImportID = response.GetID()
+	item.Status = "pending"
+	return item, nil
+}
+
+// awaitIssueImports polls the import API until the pending items are processed or
+// the polling attempts run out, recording the issue numbers of the imported ones.
+func awaitIssueImports(ctx context.Context, client *github.Client, owner, repo string, items []ImportedIssue) error {
+	for attempt := 0; attempt < issueImportPolling.Attempts; attempt++ {
+		pending := 0
+		for i := range items {
+			if items[i].Status != "pending" {
+				continue
+			}
+			status, err := checkIssueImport(ctx, client, owner, repo, items[i].ImportID)
+			if err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				items[i].Status = "failed"
+				items[i].Error = err.Error()
+				continue
+			}
+			switch status.Status {
+			case "imported":
+				items[i].Status = "imported"
+				items[i].URL = status.IssueURL
+				items[i].Number = issueNumberFromURL(status.IssueURL)
+			case "failed":
+				items[i].Status = "failed"
+				items[i].Error = describeIssueImportErrors(status.Errors)
+			default:
+				pending++
+			}
+		}
+		if pending == 0 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(issueImportPolling.Interval):
+		}
+	}
+	return nil
+}
+
+// checkIssueImport gets the status of the issue import id.
+func checkIssueImport(ctx context.Context, client *github.Client, owner, repo string, id int) (*issueImportStatus, error) {
+	req, err := client.NewRequest(http.MethodGet, fmt.Sprintf("repos/%s/%s/import/issues/%d", owner, repo, id), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", issueImportMediaType)
+
+	status := new(issueImportStatus)
+	resp, err := importRequest(ctx, func() (*github.Response, error) {
+		return client.Do(ctx, req, status)
+	})
+	if err != nil {
+		return nil, importAPIError(ctx, "failed to check issue import status", resp, err)
+	}
+	_ = resp.Body.Close()
+	return status, nil
+}
+
+// importRequest makes an API call of import_issues, and repeats it, within the retry budget of
+// ctx, while it hits a secondary rate limit.
+func importRequest(ctx context.Context, call func() (*github.Response, error)) (*github.Response, error) {
+	var resp *github.Response
+	err := ratelimit.RetryWithBackoff(ctx, issueImportRetryConfig, func() error {
+		var err error
+		resp, err = call()
+		return err
+	})
+	return resp, err
+}
+
+// isSecondaryRateLimit reports whether err is a secondary rate limit, which GitHub answers
+// with a 403 or 429 before doing anything.
+func isSecondaryRateLimit(err error) bool {
+	var abuseErr *github.AbuseRateLimitError
+	if errors.As(err, &abuseErr) {
+		return true
+	}
+	var respErr *github.ErrorResponse
+	return errors.As(err, &respErr) && respErr.Response != nil && respErr.Response.StatusCode == http.StatusTooManyRequests
+}
+
+// importAPIError records a failed API call of import_issues in the request context and
+// returns it as an error, so it is reported for the one issue rather than failing the call.
+func importAPIError(ctx context.Context, message string, resp *github.Response, err error) error {
+	_, _ = ghErrors.NewGitHubAPIErrorToCtx(ctx, message, resp, err)
+	return fmt.Errorf("%s: %w", message, err)
+}
+
+// issueNumberFromURL returns the issue number at the end of an issue API or HTML URL.
+func issueNumberFromURL(url string) int {
+	number, err := strconv.Atoi(url[strings.LastIndex(url, "/")+1:])
+	if err != nil {
+		return 0
+	}
+	return number
+}
+
+// describeIssueImportErrors summarizes the errors of a failed issue import.
+func describeIssueImportErrors(errs []*github.IssueImportError) string {
+	if len(errs) == 0 {
+		return "import failed"
+	}
+	parts := make([]string, 0, len(errs))
+	for _, e := range errs {
+		parts = append(parts, fmt.Sprintf("%s %s %s: %s", e.GetLocation(), e.GetField(), e.GetValue(), e.GetCode()))
+	}
+	return "import failed: " + strings.Join(parts, "; ")
+}
@@ -0,0 +1,398 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/ratelimit"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v79/github"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fastIssueImports lifts the polling and retry delays of import_issues for the duration of a test.
+// Mocked clients skip the rate limit transport, so the requests are not paced.
+func fastIssueImports(t *testing.T) {
+	polling, retry := issueImportPolling, issueImportRetryConfig
+	issueImportPolling.Interval = time.Millisecond
+	issueImportRetryConfig.InitialBackoff = time.Millisecond
+	t.Cleanup(func() {
+		issueImportPolling, issueImportRetryConfig = polling, retry
+	})
+}
+
+// sequentialResponses answers successive requests with handlers in turn, repeating the last one.
+func sequentialResponses(handlers ...http.HandlerFunc) http.HandlerFunc {
+	calls := 0
+	return func(w http.ResponseWriter, r *http.Request) {
+		handler := handlers[min(calls, len(handlers)-1)]
+		calls++
+		handler(w, r)
+	}
+}
+
+var (
+	postIssueImport = mock.EndpointPattern{
+		Pattern: "/repos/{owner}/{repo}/import/issues",
+		Method:  "POST",
+	}
+	getIssueImport = mock.EndpointPattern{
+		Pattern: "/repos/{owner}/{repo}/import/issues/{id}",
+		Method:  "GET",
+	}
+)
+
+func Test_ImportIssues(t *testing.T) {
+	mockClient := github.NewClient(nil)
+	tool, _ := ImportIssues(stubGetClientFn(mockClient), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	schema, ok := tool.InputSchema.(*jsonschema.Schema)
+	require.True(t, ok, "InputSchema should be *jsonschema.Schema")
+	assert.Equal(t, "import_issues", tool.Name)
+	assert.False(t, tool.Annotations.ReadOnlyHint)
+	assert.Contains(t, schema.Properties, "use_import_api")
+	assert.Contains(t, schema.Properties, "continue_on_error")
+	assert.ElementsMatch(t, schema.Required, []string{"owner", "repo", "issues"})
+
+	fastIssueImports(t)
+
+	tests := []struct {
+		name           string
+		mockedClient   *http.Client
+		requestArgs    map[string]any
+		expectError    bool
+		expectedErrMsg string
+		expectedResult ImportIssuesResult
+	}{
+		{
+			name: "creates issues with comments and state",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.PostReposIssuesByOwnerByRepo,
+					expectRequestBody(t, map[string]any{
+						"title":     "Crash on start",
+						"body":      "Stack trace attached",
+						"labels":    []any{"bug"},
+						"assignees": []any{"octocat"},
+					}).andThen(mockResponse(t, http.StatusCreated, &github.Issue{Number: github.Ptr(7), HTMLURL: github.Ptr("https://github.com/owner/repo/issues/7")})),
+				),
+				mock.WithRequestMatchHandler(
+					mock.PostReposIssuesCommentsByOwnerByRepoByIssueNumber,
+					expectRequestBody(t, map[string]any{"body": "Fixed in 1.2"}).andThen(
+						mockResponse(t, http.StatusCreated, &github.IssueComment{ID: github.Ptr(int64(1))}),
+					),
+				),
+				mock.WithRequestMatchHandler(
+					mock.PatchReposIssuesByOwnerByRepoByIssueNumber,
+					expectRequestBody(t, map[string]any{"state": "closed"}).andThen(
+						mockResponse(t, http.StatusOK, &github.Issue{Number: github.Ptr(7)}),
+					),
+				),
+			),
+			requestArgs: map[string]any{
+				"owner": "owner",
+				"repo":  "repo",
+				"issues": []any{
+					map[string]any{
+						"source_id":  "JIRA-1",
+						"title":      "Crash on start",
+						"body":       "Stack trace attached",
+						"labels":     []any{"bug"},
+						"assignees":  []any{"octocat"},
+						"comments":   []any{map[string]any{"body": "Fixed in 1.2"}},
+						"closed":     true,
+						"created_at": "2020-01-02",
+					},
+				},
+			},
+			expectedResult: ImportIssuesResult{
+				Imported: 1,
+				Mapping:  map[string]int{"JIRA-1": 7},
+				Items: []ImportedIssue{{
+					SourceID: "JIRA-1",
+					Status:   "created",
+					Number:   7,
+					URL:      "https://github.com/owner/repo/issues/7",
+					Warnings: []string{"timestamps are only kept with use_import_api"},
+				}},
+			},
+		},
+		{
+			name: "reports failed issues and continues",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.PostReposIssuesByOwnerByRepo,
+					sequentialResponses(
+						mockResponse(t, http.StatusUnprocessableEntity, map[string]string{"message": "Validation Failed"}),
+						mockResponse(t, http.StatusCreated, &github.Issue{Number: github.Ptr(8)}),
+					),
+				),
+			),
+			requestArgs: map[string]any{
+				"owner": "owner",
+				"repo":  "repo",
+				"issues": []any{
+					map[string]any{"source_id": "1", "title": "First"},
+					map[string]any{"source_id": "2", "title": "Second"},
+				},
+			},
+			expectedResult: ImportIssuesResult{
+				Imported: 1,
+				Failed:   1,
+				Mapping:  map[string]int{"2": 8},
+				Items: []ImportedIssue{
+					{SourceID: "1", Status: "failed"},
+					{SourceID: "2", Status: "created", Number: 8},
+				},
+			},
+		},
+		{
+			name: "stops at the first failure without continue_on_error",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.PostReposIssuesByOwnerByRepo,
+					mockResponse(t, http.StatusUnprocessableEntity, map[string]string{"message": "Validation Failed"}),
+				),
+			),
+			requestArgs: map[string]any{
+				"owner":             "owner",
+				"repo":              "repo",
+				"continue_on_error": false,
+				"issues": []any{
+					map[string]any{"source_id": "1", "title": "First"},
+					map[string]any{"source_id": "2", "title": "Second"},
+				},
+			},
+			expectedResult: ImportIssuesResult{
+				Failed:  1,
+				Mapping: map[string]int{},
+				Items:   []ImportedIssue{{SourceID: "1", Status: "failed"}},
+			},
+		},
+		{
+			name: "imports issues with timestamps through the import API",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					postIssueImport,
+					expectRequestBody(t, map[string]any{
+						"issue": map[string]any{
+							"title":      "Old bug",
+							"body":       "",
+							"created_at": "2019-05-01T10:00:00Z",
+							"closed_at":  "2019-06-01T00:00:00Z",
+							"assignee":   "octocat",
+							"closed":     true,
+						},
+						"comments": []any{
+							map[string]any{"body": "Confirmed", "created_at": "2019-05-02T00:00:00Z"},
+						},
+					}).andThen(mockResponse(t, http.StatusAccepted, &github.IssueImportResponse{ID: github.Ptr(42), Status: github.Ptr("pending")})),
+				),
+				mock.WithRequestMatchHandler(
+					getIssueImport,
+					expectPath(t, "/repos/owner/repo/import/issues/42").andThen(sequentialResponses(
+						mockResponse(t, http.StatusOK, map[string]any{"id": 42, "status": "pending"}),
+						mockResponse(t, http.StatusOK, map[string]any{"id": 42, "status": "imported", "issue_url": "https://api.github.com/repos/owner/repo/issues/15"}),
+					)),
+				),
+			),
+			requestArgs: map[string]any{
+				"owner":          "owner",
+				"repo":           "repo",
+				"use_import_api": true,
+				"issues": []any{
+					map[string]any{
+						"source_id":  "old-1",
+						"title":      "Old bug",
+						"assignees":  []any{"octocat", "hubot"},
+						"comments":   []any{map[string]any{"body": "Confirmed", "created_at": "2019-05-02"}},
+						"closed":     true,
+						"created_at": "2019-05-01T10:00:00Z",
+						"closed_at":  "2019-06-01",
+					},
+				},
+			},
+			expectedResult: ImportIssuesResult{
+				Imported: 1,
+				Mapping:  map[string]int{"old-1": 15},
+				Items: []ImportedIssue{{
+					SourceID: "old-1",
+					Status:   "imported",
+					Number:   15,
+					URL:      "https://api.github.com/repos/owner/repo/issues/15",
+					ImportID: 42,
+					Warnings: []string{"the import API supports one assignee; only octocat was assigned"},
+				}},
+			},
+		},
+		{
+			name: "reports failed imports",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					postIssueImport,
+					mockResponse(t, http.StatusAccepted, &github.IssueImportResponse{ID: github.Ptr(43), Status: github.Ptr("pending")}),
+				),
+				mock.WithRequestMatchHandler(
+					getIssueImport,
+					mockResponse(t, http.StatusOK, map[string]any{
+						"id":     43,
+						"status": "failed",
+						"errors": []any{map[string]any{"location": "/issue", "field": "assignee", "value": "ghost", "code": "invalid"}},
+					}),
+				),
+			),
+			requestArgs: map[string]any{
+				"owner":          "owner",
+				"repo":           "repo",
+				"use_import_api": true,
+				"issues":         []any{map[string]any{"source_id": "x", "title": "Bad", "assignees": []any{"ghost"}}},
+			},
+			expectedResult: ImportIssuesResult{
+				Failed:  1,
+				Mapping: map[string]int{},
+				Items: []ImportedIssue{{
+					SourceID: "x",
+					Status:   "failed",
+					ImportID: 43,
+					Error:    "import failed: /issue assignee ghost: invalid",
+				}},
+			},
+		},
+		{
+			name:         "duplicate source IDs",
+			mockedClient: mock.NewMockedHTTPClient(),
+			requestArgs: map[string]any{
+				"owner": "owner",
+				"repo":  "repo",
+				"issues": []any{
+					map[string]any{"source_id": "1", "title": "A"},
+					map[string]any{"source_id": "1", "title": "B"},
+				},
+			},
+			expectError:    true,
+			expectedErrMsg: "issues[1]: source_id 1 is used more than once",
+		},
+		{
+			name:         "invalid comment timestamp",
+			mockedClient: mock.NewMockedHTTPClient(),
+			requestArgs: map[string]any{
+				"owner": "owner",
+				"repo":  "repo",
+				"issues": []any{
+					map[string]any{"source_id": "1", "title": "A", "comments": []any{map[string]any{"body": "b", "created_at": "yesterday"}}},
+				},
+			},
+			expectError:    true,
+			expectedErrMsg: "issues[0].comments[0]: created_at: invalid ISO 8601 timestamp",
+		},
+		{
+			name:         "closed_at without closed",
+			mockedClient: mock.NewMockedHTTPClient(),
+			requestArgs: map[string]any{
+				"owner":  "owner",
+				"repo":   "repo",
+				"issues": []any{map[string]any{"source_id": "1", "title": "A", "closed_at": "2020-01-01"}},
+			},
+			expectError:    true,
+			expectedErrMsg: "issues[0]: closed_at requires closed to be true",
+		},
+		{
+			name:         "no issues",
+			mockedClient: mock.NewMockedHTTPClient(),
+			requestArgs: map[string]any{
+				"owner":  "owner",
+				"repo":   "repo",
+				"issues": []any{},
+			},
+			expectError:    true,
+			expectedErrMsg: "issues must contain at least one issue",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client := github.NewClient(tc.mockedClient)
+			_, handler := ImportIssues(stubGetClientFn(client), translations.NullTranslationHelper)
+
+			request := createMCPRequest(tc.requestArgs)
+			result, _, err := handler(context.Background(), &request, tc.requestArgs)
+			require.NoError(t, err)
+
+			if tc.expectError {
+				require.True(t, result.IsError)
+				errorContent := getErrorResult(t, result)
+				assert.Contains(t, errorContent.Text, tc.expectedErrMsg)
+				return
+			}
+
+			require.False(t, result.IsError)
+			textContent := getTextResult(t, result)
+			var got ImportIssuesResult
+			require.NoError(t, json.Unmarshal([]byte(textContent.Text), &got))
+
+			// API error messages embed the mock server URL, so only their presence is compared
+			for i := range got.Items {
+				if got.Items[i].Status == "failed" && tc.expectedResult.Items[i].Error == "" {
+					assert.NotEmpty(t, got.Items[i].Error)
+					got.Items[i].Error = ""
+				}
+			}
+			assert.Equal(t, tc.expectedResult, got)
+		})
+	}
+}
+
+func Test_ImportIssues_RetryBudget(t *testing.T) {
+	fastIssueImports(t)
+	budget := newRetryBudget
+	newRetryBudget = func() *ratelimit.RetryBudget { return ratelimit.NewRetryBudget(5, time.Minute) }
+	t.Cleanup(func() { newRetryBudget = budget })
+
+	requests := 0
+	throttled := mockResponse(t, http.StatusTooManyRequests, map[string]string{"message": "You have exceeded a secondary rate limit"})
+	client := github.NewClient(mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(mock.PostReposIssuesByOwnerByRepo, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			throttled(w, r)
+		})),
+	))
+	_, handler := ImportIssues(stubGetClientFn(client), translations.NullTranslationHelper)
+
+	args := map[string]any{
+		"owner": "owner",
+		"repo":  "repo",
+		"issues": []any{
+			map[string]any{"source_id": "1", "title": "First"},
+			map[string]any{"source_id": "2", "title": "Second"},
+			map[string]any{"source_id": "3", "title": "Third"},
+		},
+	}
+	request := createMCPRequest(args)
+	result, _, err := handler(context.Background(), &request, args)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	var got ImportIssuesResult
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &got))
+
+	// The first issue takes three of the five retries, so the second runs out of budget after
+	// two and the import stops before the third
+	assert.Equal(t, 4+3, requests)
+	require.Len(t, got.Items, 2)
+	assert.Equal(t, 2, got.Failed)
+	assert.NotContains(t, got.Items[0].Error, ratelimit.ErrRetryBudgetExhausted.Error())
+	assert.Contains(t, got.Items[1].Error, ratelimit.ErrRetryBudgetExhausted.Error())
+}
+
+func Test_IssueNumberFromURL(t *testing.T) {
+	assert.Equal(t, 15, issueNumberFromURL("https://api.github.com/repos/owner/repo/issues/15"))
+	assert.Equal(t, 3, issueNumberFromURL("https://github.com/owner/repo/issues/3"))
+	assert.Equal(t, 0, issueNumberFromURL(""))
+}
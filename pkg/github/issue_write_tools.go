@@ -0,0 +1,63 @@
+package github
+
+import (
+	"context"
+	"maps"
+
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// updateIssueOnlyParams are the issue_write parameters that only apply to updates
+var updateIssueOnlyParams = []string{"issue_number", "state", "state_reason", "duplicate_of"}
+
+// issueWriteMethodTool returns issue_write with its method fixed, for clients that look for
+// separate create and update tools. The schema drops the method, and the parameters listed in
+// without, and the handler passes the method on to issue_write.
+func issueWriteMethodTool(issueWrite mcp.Tool, issueWriteHandler mcp.ToolHandlerFor[map[string]any, any], method string, tool mcp.Tool, required []string, without ...string) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	schema := *issueWrite.InputSchema.(*jsonschema.Schema)
+	schema.Properties = maps.Clone(schema.Properties)
+	delete(schema.Properties, "method")
+	for _, name := range without {
+		delete(schema.Properties, name)
+	}
+	schema.Required = required
+	tool.InputSchema = &schema
+
+	handler := func(ctx context.Context, request *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		withMethod := maps.Clone(args)
+		if withMethod == nil {
+			withMethod = map[string]any{}
+		}
+		withMethod["method"] = method
+		return issueWriteHandler(ctx, request, withMethod)
+	}
+	return tool, handler
+}
+
+// CreateIssueTool creates a tool to create an issue, the create method of issue_write on its own.
+func CreateIssueTool(getClient GetClientFn, getGQLClient GetGQLClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	issueWrite, handler := IssueWrite(getClient, getGQLClient, t)
+	return issueWriteMethodTool(issueWrite, handler, "create", mcp.Tool{
+		Name:        "create_issue",
+		Description: t("TOOL_CREATE_ISSUE_DESCRIPTION", "Create a new issue in a GitHub repository."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_CREATE_ISSUE_USER_TITLE", "Create issue"),
+			ReadOnlyHint: false,
+		},
+	}, []string{"owner", "repo", "title"}, updateIssueOnlyParams...)
+}
+
+// UpdateIssueTool creates a tool to update an issue, the update method of issue_write on its own.
+func UpdateIssueTool(getClient GetClientFn, getGQLClient GetGQLClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	issueWrite, handler := IssueWrite(getClient, getGQLClient, t)
+	return issueWriteMethodTool(issueWrite, handler, "update", mcp.Tool{
+		Name:        "update_issue",
+		Description: t("TOOL_UPDATE_ISSUE_DESCRIPTION", "Update an existing issue in a GitHub repository: its title, body, assignees, labels, milestone, type or state. Only the fields given are changed."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_UPDATE_ISSUE_USER_TITLE", "Update issue"),
+			ReadOnlyHint: false,
+		},
+	}, []string{"owner", "repo", "issue_number"})
+}
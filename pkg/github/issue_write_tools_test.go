@@ -0,0 +1,116 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v79/github"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CreateIssueTool(t *testing.T) {
+	tool, _ := CreateIssueTool(stubGetClientFn(github.NewClient(nil)), stubGetGQLClientFn(githubv4.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	schema := tool.InputSchema.(*jsonschema.Schema)
+	assert.Equal(t, "create_issue", tool.Name)
+	assert.ElementsMatch(t, []string{"owner", "repo", "title"}, schema.Required)
+	assert.NotContains(t, schema.Properties, "method")
+	assert.NotContains(t, schema.Properties, "issue_number")
+	assert.NotContains(t, schema.Properties, "state")
+	assert.Contains(t, schema.Properties, "labels")
+
+	// issue_write keeps its own schema
+	issueWrite, _ := IssueWrite(stubGetClientFn(github.NewClient(nil)), stubGetGQLClientFn(githubv4.NewClient(nil)), translations.NullTranslationHelper)
+	assert.Contains(t, issueWrite.InputSchema.(*jsonschema.Schema).Properties, "method")
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.PostReposIssuesByOwnerByRepo,
+			expectRequestBody(t, map[string]any{
+				"title":     "Flaky test",
+				"body":      "It fails on Tuesdays",
+				"labels":    []any{"bug"},
+				"assignees": []any{},
+			}).andThen(
+				mockResponse(t, http.StatusCreated, &github.Issue{
+					Number:  github.Ptr(42),
+					HTMLURL: github.Ptr("https://github.com/owner/repo/issues/42"),
+				}),
+			),
+		),
+	)
+	_, handler := CreateIssueTool(stubGetClientFn(github.NewClient(mockedClient)), stubGetGQLClientFn(githubv4.NewClient(nil)), translations.NullTranslationHelper)
+
+	args := map[string]any{
+		"owner":  "owner",
+		"repo":   "repo",
+		"title":  "Flaky test",
+		"body":   "It fails on Tuesdays",
+		"labels": []any{"bug"},
+	}
+	request := createMCPRequest(args)
+	result, _, err := handler(context.Background(), &request, args)
+	require.NoError(t, err)
+	require.False(t, result.IsError, getTextResult(t, result).Text)
+
+	var created MinimalResponse
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &created))
+	assert.Equal(t, "https://github.com/owner/repo/issues/42", created.URL)
+	assert.NotContains(t, args, "method", "the caller's arguments are not changed")
+}
+
+func Test_UpdateIssueTool(t *testing.T) {
+	tool, _ := UpdateIssueTool(stubGetClientFn(github.NewClient(nil)), stubGetGQLClientFn(githubv4.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	schema := tool.InputSchema.(*jsonschema.Schema)
+	assert.Equal(t, "update_issue", tool.Name)
+	assert.ElementsMatch(t, []string{"owner", "repo", "issue_number"}, schema.Required)
+	assert.NotContains(t, schema.Properties, "method")
+	assert.Contains(t, schema.Properties, "state")
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.PatchReposIssuesByOwnerByRepoByIssueNumber,
+			expectPath(t, "/repos/owner/repo/issues/42").andThen(
+				expectRequestBody(t, map[string]any{
+					"title": "Flaky test on Tuesdays",
+				}).andThen(
+					mockResponse(t, http.StatusOK, &github.Issue{
+						Number:  github.Ptr(42),
+						HTMLURL: github.Ptr("https://github.com/owner/repo/issues/42"),
+					}),
+				),
+			),
+		),
+	)
+	_, handler := UpdateIssueTool(stubGetClientFn(github.NewClient(mockedClient)), stubGetGQLClientFn(githubv4.NewClient(nil)), translations.NullTranslationHelper)
+
+	args := map[string]any{
+		"owner":        "owner",
+		"repo":         "repo",
+		"issue_number": float64(42),
+		"title":        "Flaky test on Tuesdays",
+	}
+	request := createMCPRequest(args)
+	result, _, err := handler(context.Background(), &request, args)
+	require.NoError(t, err)
+	require.False(t, result.IsError, getTextResult(t, result).Text)
+
+	// Without an issue number there is nothing to update
+	args = map[string]any{"owner": "owner", "repo": "repo", "title": "x"}
+	request = createMCPRequest(args)
+	result, _, err = handler(context.Background(), &request, args)
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	assert.Contains(t, getErrorResult(t, result).Text, "issue_number")
+}
@@ -0,0 +1,303 @@
+package github
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	ghErrors "github.com/github/github-mcp-server/pkg/errors"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/github/github-mcp-server/pkg/utils"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// jobLogSectionSeparator separates non-adjacent groups of grep matches, as grep -C does
+const jobLogSectionSeparator = "--"
+
+// jobLogFilter selects the lines of a job log to return.
+type jobLogFilter struct {
+	// since drops lines logged before this time, when set
+	since time.Time
+	// grep keeps only matching lines and their context, when set
+	grep         *regexp.Regexp
+	contextLines int
+	// tailLines is the number of filtered lines kept from the end of the log
+	tailLines int
+}
+
+// JobLogResult is the filtered log of a single job.
+type JobLogResult struct {
+	JobID         int64  `json:"job_id"`
+	Content       string `json:"logs_content"`
+	TotalLines    int    `json:"total_lines"`
+	MatchedLines  int    `json:"matched_lines,omitempty"`
+	ReturnedLines int    `json:"returned_lines"`
+	Truncated     bool   `json:"truncated,omitempty"`
+}
+
+// GetJobLog creates a tool to fetch a single job's log, filtered on the server so only the relevant
+// part of a large log reaches the client.
+func GetJobLog(getClient GetClientFn, t translations.TranslationHelperFunc, contentWindowSize int) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	return mcp.Tool{
+			Name:        "get_job_log",
+			Description: t("TOOL_GET_JOB_LOG_DESCRIPTION", "Get the log of a workflow job, filtered on the server. Use grep to return only matching lines with surrounding context, since_step to skip the steps before the one of interest, and tail_lines to keep only the end of the result."),
+			Annotations: &mcp.ToolAnnotations{
+				Title:        t("TOOL_GET_JOB_LOG_USER_TITLE", "Get filtered job log"),
+				ReadOnlyHint: true,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"owner": {
+						Type:        "string",
+						Description: DescriptionRepositoryOwner,
+					},
+					"repo": {
+						Type:        "string",
+						Description: DescriptionRepositoryName,
+					},
+					"job_id": {
+						Type:        "number",
+						Description: "The unique identifier of the workflow job",
+					},
+					"grep": {
+						Type:        "string",
+						Description: "Regular expression (Go RE2 syntax). Only matching lines and their context lines are returned",
+					},
+					"context_lines": {
+						Type:        "number",
+						Description: "Number of lines to include before and after each grep match",
+						Default:     json.RawMessage(`3`),
+						Minimum:     jsonschema.Ptr(0.0),
+					},
+					"since_step": {
+						Type:        "number",
+						Description: "Step number to start from. Lines logged before this step started are skipped",
+						Minimum:     jsonschema.Ptr(1.0),
+					},
+					"tail_lines": {
+						Type:        "number",
+						Description: "Number of lines to return from the end of the filtered log",
+						Default:     json.RawMessage(`500`),
+					},
+					"max_bytes": {
+						Type:        "number",
+						Description: "Maximum size in bytes of the log content returned. Longer results keep their last bytes, starting at a line boundary",
+						Default:     json.RawMessage(`65536`),
+					},
+				},
+				Required: []string{"owner", "repo", "job_id"},
+			},
+		},
+		func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+			owner, err := RequiredParam[string](args, "owner")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			repo, err := RequiredParam[string](args, "repo")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			jobIDInt, err := RequiredInt(args, "job_id")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			jobID := int64(jobIDInt)
+			pattern, err := OptionalParam[string](args, "grep")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			contextLines := 3
+			if _, ok := args["context_lines"]; ok {
+				contextLines, err = OptionalIntParam(args, "context_lines")
+				if err != nil {
+					return utils.NewToolResultError(err.Error()), nil, nil
+				}
+			}
+			sinceStep, err := OptionalIntParam(args, "since_step")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			tailLines, err := OptionalIntParam(args, "tail_lines")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			if tailLines == 0 {
+				tailLines = 500
+			}
+			maxBytes, err := OptionalIntParam(args, "max_bytes")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			if maxBytes == 0 {
+				maxBytes = defaultJobLogMaxBytes
+			}
+			if contextLines < 0 || sinceStep < 0 || tailLines < 0 || maxBytes < 0 {
+				return utils.NewToolResultError("context_lines, since_step, tail_lines and max_bytes must not be negative"), nil, nil
+			}
+
+			filter := jobLogFilter{
+				contextLines: contextLines,
+				tailLines:    min(tailLines, contentWindowSize),
+			}
+			if pattern != "" {
+				filter.grep, err = regexp.Compile(pattern)
+				if err != nil {
+					return utils.NewToolResultError(fmt.Sprintf("invalid grep pattern: %v", err)), nil, nil
+				}
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+
+			if sinceStep > 0 {
+				job, resp, err := client.Actions.GetWorkflowJobByID(ctx, owner, repo, jobID)
+				if err != nil {
+					return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to get workflow job", resp, err), nil, nil
+				}
+				_ = resp.Body.Close()
+
+				found := false
+				for _, step := range job.Steps {
+					if step.GetNumber() == int64(sinceStep) {
+						found = true
+						filter.since = step.GetStartedAt().Time
+						break
+					}
+				}
+				if !found {
+					return utils.NewToolResultError(fmt.Sprintf("job %d has no step %d", jobID, sinceStep)), nil, nil
+				}
+				if filter.since.IsZero() {
+					return utils.NewToolResultError(fmt.Sprintf("step %d of job %d has not started", sinceStep, jobID)), nil, nil
+				}
+			}
+
+			logURL, resp, err := client.Actions.GetWorkflowJobLogs(ctx, owner, repo, jobID, 1)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to get job logs", resp, err), nil, nil
+			}
+			_ = resp.Body.Close()
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, logURL.String(), nil)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to create log request: %w", err)
+			}
+			httpResp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				return utils.NewToolResultErrorFromErr("failed to download logs", err), nil, nil
+			}
+			defer func() { _ = httpResp.Body.Close() }()
+			if httpResp.StatusCode != http.StatusOK {
+				return utils.NewToolResultError(fmt.Sprintf("failed to download logs: HTTP %d", httpResp.StatusCode)), nil, nil
+			}
+
+			result, err := filterJobLog(httpResp.Body, filter)
+			if err != nil {
+				return utils.NewToolResultErrorFromErr("failed to read logs", err), nil, nil
+			}
+			result.JobID = jobID
+			result.Content, result.Truncated = tailBytes(result.Content, maxBytes)
+			if result.Truncated {
+				result.Content = strings.TrimPrefix(result.Content, jobLogSectionSeparator+"\n")
+			}
+
+			return MarshalledTextResult(result), nil, nil
+		}
+}
+
+// filterJobLog reads a job log line by line and keeps the last filter.tailLines lines that pass the filter.
+func filterJobLog(r io.Reader, filter jobLogFilter) (JobLogResult, error) {
+	var (
+		result     JobLogResult
+		kept       []string
+		before     []string
+		afterLeft  int
+		lastKept   = -1
+		inRange    = filter.since.IsZero()
+		lineNumber int
+	)
+	keep := func(line string, n int) {
+		if filter.grep != nil && lastKept >= 0 && n > lastKept+1 {
+			kept = append(kept, jobLogSectionSeparator)
+		}
+		kept = append(kept, line)
+		lastKept = n
+		// Only the end of the log is returned, so drop lines that can no longer be part of it
+		if len(kept) > 2*filter.tailLines+1 {
+			kept = append(kept[:0], kept[len(kept)-filter.tailLines:]...)
+		}
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		lineNumber++
+		result.TotalLines++
+
+		if !inRange {
+			// Lines without a timestamp continue the line before them
+			if ts, ok := jobLogTimestamp(line); ok && !ts.Before(filter.since) {
+				inRange = true
+			} else {
+				continue
+			}
+		}
+
+		switch {
+		case filter.grep == nil:
+			keep(line, lineNumber)
+		case filter.grep.MatchString(line):
+			result.MatchedLines++
+			for i, b := range before {
+				keep(b, lineNumber-len(before)+i)
+			}
+			before = before[:0]
+			keep(line, lineNumber)
+			afterLeft = filter.contextLines
+		case afterLeft > 0:
+			afterLeft--
+			keep(line, lineNumber)
+		case filter.contextLines > 0:
+			if len(before) == filter.contextLines {
+				before = append(before[:0], before[1:]...)
+			}
+			before = append(before, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return JobLogResult{}, err
+	}
+
+	if len(kept) > filter.tailLines {
+		kept = kept[len(kept)-filter.tailLines:]
+	}
+	if len(kept) > 0 && kept[0] == jobLogSectionSeparator {
+		kept = kept[1:]
+	}
+	result.Content = strings.Join(kept, "\n")
+	result.ReturnedLines = len(kept)
+	return result, nil
+}
+
+// jobLogTimestamp parses the timestamp GitHub Actions puts at the start of each log line.
+func jobLogTimestamp(line string) (time.Time, bool) {
+	stamp, _, ok := strings.Cut(line, " ")
+	if !ok {
+		stamp = line
+	}
+	// Lines may start with a byte order mark
+	stamp = strings.TrimPrefix(stamp, "\ufeff")
+	ts, err := time.Parse(time.RFC3339Nano, stamp)
+	return ts, err == nil
+}
@@ -0,0 +1,199 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v79/github"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_filterJobLog(t *testing.T) {
+	log := strings.Join([]string{
+		"2024-01-01T10:00:00.0000000Z ##[group]Run actions/checkout@v4",
+		"2024-01-01T10:00:01.0000000Z checking out",
+		"2024-01-01T10:00:02.0000000Z ##[endgroup]",
+		"2024-01-01T10:01:00.0000000Z ##[group]Run go test ./...",
+		"2024-01-01T10:01:01.0000000Z ok  pkg/a",
+		"2024-01-01T10:01:02.0000000Z --- FAIL: TestB",
+		"    b_test.go:12: want 1, got 2",
+		"2024-01-01T10:01:03.0000000Z FAIL pkg/b",
+		"2024-01-01T10:01:04.0000000Z ok  pkg/c",
+		"2024-01-01T10:01:05.0000000Z ok  pkg/d",
+		"2024-01-01T10:01:06.0000000Z ##[error]Process completed with exit code 1.",
+	}, "\n")
+
+	tests := []struct {
+		name            string
+		filter          jobLogFilter
+		expectedLines   []string
+		expectedMatches int
+	}{
+		{
+			name:   "tail only",
+			filter: jobLogFilter{tailLines: 2},
+			expectedLines: []string{
+				"2024-01-01T10:01:05.0000000Z ok  pkg/d",
+				"2024-01-01T10:01:06.0000000Z ##[error]Process completed with exit code 1.",
+			},
+		},
+		{
+			name:   "grep with context merges adjacent sections",
+			filter: jobLogFilter{grep: regexp.MustCompile(`FAIL|##\[error\]`), contextLines: 1, tailLines: 100},
+			expectedLines: []string{
+				"2024-01-01T10:01:01.0000000Z ok  pkg/a",
+				"2024-01-01T10:01:02.0000000Z --- FAIL: TestB",
+				"    b_test.go:12: want 1, got 2",
+				"2024-01-01T10:01:03.0000000Z FAIL pkg/b",
+				"2024-01-01T10:01:04.0000000Z ok  pkg/c",
+				"2024-01-01T10:01:05.0000000Z ok  pkg/d",
+				"2024-01-01T10:01:06.0000000Z ##[error]Process completed with exit code 1.",
+			},
+			expectedMatches: 3,
+		},
+		{
+			name:            "grep without context",
+			filter:          jobLogFilter{grep: regexp.MustCompile(`^\s+\w+_test\.go`), tailLines: 100},
+			expectedLines:   []string{"    b_test.go:12: want 1, got 2"},
+			expectedMatches: 1,
+		},
+		{
+			name:   "since step start separates sections",
+			filter: jobLogFilter{since: time.Date(2024, 1, 1, 10, 1, 0, 0, time.UTC), grep: regexp.MustCompile(`^\S+ ok`), tailLines: 100},
+			expectedLines: []string{
+				"2024-01-01T10:01:01.0000000Z ok  pkg/a",
+				"--",
+				"2024-01-01T10:01:04.0000000Z ok  pkg/c",
+				"2024-01-01T10:01:05.0000000Z ok  pkg/d",
+			},
+			expectedMatches: 3,
+		},
+		{
+			name:   "tail drops a leading separator",
+			filter: jobLogFilter{grep: regexp.MustCompile(`FAIL|##\[error\]`), tailLines: 2},
+			expectedLines: []string{
+				"2024-01-01T10:01:06.0000000Z ##[error]Process completed with exit code 1.",
+			},
+			expectedMatches: 3,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := filterJobLog(strings.NewReader(log), tc.filter)
+			require.NoError(t, err)
+			assert.Equal(t, strings.Join(tc.expectedLines, "\n"), result.Content)
+			assert.Equal(t, len(tc.expectedLines), result.ReturnedLines)
+			assert.Equal(t, 11, result.TotalLines)
+			assert.Equal(t, tc.expectedMatches, result.MatchedLines)
+		})
+	}
+}
+
+func Test_GetJobLog(t *testing.T) {
+	tool, _ := GetJobLog(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper, 5000)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.True(t, tool.Annotations.ReadOnlyHint)
+
+	logContent := strings.Join([]string{
+		"\ufeff2024-01-01T10:00:00.0000000Z ##[group]Run make lint",
+		"2024-01-01T10:00:01.0000000Z error: lint warning treated as error",
+		"2024-01-01T10:01:00.0000000Z ##[group]Run make test",
+		"2024-01-01T10:01:01.0000000Z error: TestB failed",
+	}, "\n")
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(logContent))
+	}))
+	defer testServer.Close()
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.GetReposActionsJobsByOwnerByRepoByJobId,
+			mockResponse(t, http.StatusOK, &github.WorkflowJob{
+				ID: github.Ptr(int64(123)),
+				Steps: []*github.TaskStep{
+					{Number: github.Ptr(int64(1)), Name: github.Ptr("lint"), StartedAt: &github.Timestamp{Time: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)}},
+					{Number: github.Ptr(int64(2)), Name: github.Ptr("test"), StartedAt: &github.Timestamp{Time: time.Date(2024, 1, 1, 10, 1, 0, 0, time.UTC)}},
+					{Number: github.Ptr(int64(3)), Name: github.Ptr("deploy")},
+				},
+			}),
+		),
+		mock.WithRequestMatchHandler(
+			mock.GetReposActionsJobsLogsByOwnerByRepoByJobId,
+			http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Location", testServer.URL)
+				w.WriteHeader(http.StatusFound)
+			}),
+		),
+	)
+	_, handler := GetJobLog(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper, 5000)
+
+	tests := []struct {
+		name            string
+		args            map[string]any
+		expectedContent string
+		expectedErrMsg  string
+	}{
+		{
+			name:            "grep since step",
+			args:            map[string]any{"grep": "^\\S+ error:", "since_step": float64(2), "context_lines": float64(0)},
+			expectedContent: "2024-01-01T10:01:01.0000000Z error: TestB failed",
+		},
+		{
+			name:            "grep with default context",
+			args:            map[string]any{"grep": "warning"},
+			expectedContent: logContent,
+		},
+		{
+			name:           "invalid pattern",
+			args:           map[string]any{"grep": "("},
+			expectedErrMsg: "invalid grep pattern",
+		},
+		{
+			name:           "unknown step",
+			args:           map[string]any{"since_step": float64(9)},
+			expectedErrMsg: "job 123 has no step 9",
+		},
+		{
+			name:           "step not started",
+			args:           map[string]any{"since_step": float64(3)},
+			expectedErrMsg: "step 3 of job 123 has not started",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			args := map[string]any{"owner": "owner", "repo": "repo", "job_id": float64(123)}
+			for k, v := range tc.args {
+				args[k] = v
+			}
+			request := createMCPRequest(args)
+			result, _, err := handler(context.Background(), &request, args)
+			require.NoError(t, err)
+
+			if tc.expectedErrMsg != "" {
+				require.True(t, result.IsError)
+				assert.Contains(t, getErrorResult(t, result).Text, tc.expectedErrMsg)
+				return
+			}
+
+			require.False(t, result.IsError, getTextResult(t, result).Text)
+			var log JobLogResult
+			require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &log))
+			assert.Equal(t, int64(123), log.JobID)
+			assert.Equal(t, 4, log.TotalLines)
+			assert.Equal(t, tc.expectedContent, log.Content)
+		})
+	}
+}
@@ -0,0 +1,382 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	ghErrors "github.com/github/github-mcp-server/pkg/errors"
+	"github.com/github/github-mcp-server/pkg/ratelimit"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/github/github-mcp-server/pkg/utils"
+	"github.com/google/go-github/v79/github"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+var labelColorPattern = regexp.MustCompile(`^[0-9a-f]{6}$`)
+
+// desiredLabel is a label of the declarative label set of bulk_sync_labels. A nil Description
+// keeps the description of an existing label.
+type desiredLabel struct {
+	Name        string
+	Color       string
+	Description *string
+}
+
+// LabelUpdate is a label changed by bulk_sync_labels.
+type LabelUpdate struct {
+	Before ExportedLabel `json:"before"`
+	After  ExportedLabel `json:"after"`
+}
+
+// LabelSyncFailure is a change bulk_sync_labels could not make.
+type LabelSyncFailure struct {
+	Name   string `json:"name"`
+	Action string `json:"action"`
+	Error  string `json:"error"`
+}
+
+// LabelSyncReport is the output type of bulk_sync_labels, the diff between the labels of the
+// repository and the label set.
+type LabelSyncReport struct {
+	DryRun  bool            `json:"dry_run"`
+	Created []ExportedLabel `json:"created"`
+	Updated []LabelUpdate   `json:"updated"`
+	Deleted []ExportedLabel `json:"deleted"`
+	// Extra lists the labels outside the label set that were kept because delete_extra is not set.
+	Extra     []string           `json:"extra,omitempty"`
+	Unchanged int                `json:"unchanged"`
+	Failed    []LabelSyncFailure `json:"failed,omitempty"`
+	// Plan is the quota forecast of changes that have to wait for the core rate limit to reset
+	Plan *ratelimit.FanOutPlan `json:"plan,omitempty"`
+	// AwaitingApproval is set when the changes were only previewed because Plan needs approve_plan
+	AwaitingApproval bool   `json:"awaiting_approval,omitempty"`
+	Message          string `json:"message,omitempty"`
+
+	// changes are the labels to create, update or delete, in the order they are changed
+	changes []string
+}
+
+// parseDesiredLabels parses and validates the labels parameter of bulk_sync_labels.
+func parseDesiredLabels(args map[string]any) ([]desiredLabel, error) {
+	raw, ok := args["labels"]
+	if !ok {
+		return nil, fmt.Errorf("missing required parameter: labels")
+	}
+	items, ok := raw.([]any)
+	if !ok {
+		return nil, fmt.Errorf("labels must be an array of objects")
+	}
+
+	labels := make([]desiredLabel, 0, len(items))
+	seen := make(map[string]bool, len(items))
+	for i, item := range items {
+		fields, ok := item.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("labels[%d] must be an object", i)
+		}
+		name, err := RequiredParam[string](fields, "name")
+		if err != nil {
+			return nil, fmt.Errorf("labels[%d]: %w", i, err)
+		}
+		color, err := RequiredParam[string](fields, "color")
+		if err != nil {
+			return nil, fmt.Errorf("labels[%d]: %w", i, err)
+		}
+		color = strings.ToLower(strings.TrimPrefix(color, "#"))
+		if !labelColorPattern.MatchString(color) {
+			return nil, fmt.Errorf("labels[%d]: color of %q must be a 6-character hex code, got %q", i, name, color)
+		}
+		// Label names are case-insensitive on GitHub
+		if seen[strings.ToLower(name)] {
+			return nil, fmt.Errorf("labels[%d]: label %q appears more than once", i, name)
+		}
+		seen[strings.ToLower(name)] = true
+
+		label := desiredLabel{Name: name, Color: color}
+		if _, ok := fields["description"]; ok {
+			description, err := OptionalParam[string](fields, "description")
+			if err != nil {
+				return nil, fmt.Errorf("labels[%d]: %w", i, err)
+			}
+			label.Description = &description
+		}
+		labels = append(labels, label)
+	}
+	return labels, nil
+}
+
+// listAllLabels returns every label of a repository.
+func listAllLabels(ctx context.Context, client *github.Client, owner, repo string) ([]*github.Label, *github.Response, error) {
+	var all []*github.Label
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		labels, resp, err := client.Issues.ListLabels(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, resp, err
+		}
+		_ = resp.Body.Close()
+		all = append(all, labels...)
+		if resp.NextPage == 0 {
+			return all, resp, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// BulkSyncLabels creates a tool to reconcile the labels of a repository with a declarative label set.
+func BulkSyncLabels(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "bulk_sync_labels",
+		Description: t("TOOL_BULK_SYNC_LABELS_DESCRIPTION", "Make the labels of a repository match a label set: create missing labels, update the colors and descriptions of existing ones, and optionally delete labels outside the set. Names match case-insensitively. Returns the diff of the changes; use dry_run to preview it. When the changes need more of the rate limit quota than is left before it resets, they are only previewed and a plan of waves is returned for approval."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:           t("TOOL_BULK_SYNC_LABELS_USER_TITLE", "Sync repository labels"),
+			ReadOnlyHint:    false,
+			DestructiveHint: jsonschema.Ptr(true),
+		},
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"owner": {
+					Type:        "string",
+					Description: "Repository owner",
+				},
+				"repo": {
+					Type:        "string",
+					Description: "Repository name",
+				},
+				"labels": {
+					Type:        "array",
+					Description: "The label set",
+					Items: &jsonschema.Schema{
+						Type: "object",
+						Properties: map[string]*jsonschema.Schema{
+							"name": {
+								Type:        "string",
+								Description: "Label name",
+							},
+							"color": {
+								Type:        "string",
+								Description: "Label color as a 6-character hex code, e.g. 'f29513'",
+							},
+							"description": {
+								Type:        "string",
+								Description: "Label description. When omitted, the description of an existing label is kept",
+							},
+						},
+						Required: []string{"name", "color"},
+					},
+				},
+				"delete_extra": {
+					Type:        "boolean",
+					Description: "Delete the labels of the repository that are not in the label set, removing them from issues and pull requests",
+					Default:     json.RawMessage("false"),
+				},
+				"dry_run": {
+					Type:        "boolean",
+					Description: "Report the changes without making them",
+					Default:     json.RawMessage("false"),
+				},
+				"approve_plan": approvePlanSchema("changes"),
+			},
+			Required: []string{"owner", "repo", "labels"},
+		},
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		repo, err := RequiredParam[string](args, "repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		desired, err := parseDesiredLabels(args)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		deleteExtra, err := OptionalBoolParamWithDefault(args, "delete_extra", false)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		dryRun, err := OptionalBoolParamWithDefault(args, "dry_run", false)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		approvePlan, err := OptionalParam[bool](args, "approve_plan")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		existing, resp, err := listAllLabels(ctx, client, owner, repo)
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to list labels", resp, err), nil, nil
+		}
+
+		// Each change is one API call, so the preview tells how much quota the sync needs
+		preview := syncLabels(ctx, client, owner, repo, desired, existing, deleteExtra, nil)
+		if dryRun {
+			return MarshalledTextResult(preview), nil, nil
+		}
+		targets := make([]ratelimit.FanOutTarget, 0, len(preview.changes))
+		for _, name := range preview.changes {
+			targets = append(targets, ratelimit.FanOutTarget{Name: name, Calls: 1})
+		}
+		var plan *ratelimit.FanOutPlan
+		var schedule []ratelimit.Wave
+		if len(targets) > 0 {
+			plan = planFanOut(ctx, client, targets)
+		}
+		if plan != nil && plan.RequiresApproval {
+			if !approvePlan {
+				preview.DryRun = true
+				preview.Plan = plan
+				preview.AwaitingApproval = true
+				preview.Message = planApprovalMessage(plan, "the changes were only previewed")
+				return MarshalledTextResult(preview), nil, nil
+			}
+			for _, wave := range plan.Waves {
+				for range wave.Targets {
+					schedule = append(schedule, wave)
+				}
+			}
+		}
+
+		report := syncLabels(ctx, client, owner, repo, desired, existing, deleteExtra, func(change int) error {
+			if change >= len(schedule) {
+				return nil
+			}
+			if err := ratelimit.WaitForWave(ctx, schedule[change]); err != nil {
+				return fmt.Errorf("stopped waiting for wave %d of the plan: %w", schedule[change].Index, err)
+			}
+			return nil
+		})
+		if schedule != nil {
+			report.Plan = plan
+		}
+		return MarshalledTextResult(report), nil, nil
+	})
+
+	return tool, handler
+}
+
+// syncLabels makes existing, the labels of a repository, match desired and reports the changes.
+// With a nil wait, the changes are only reported. Otherwise wait is called with the ordinal of
+// each change before it is made, and a change it fails is reported as failed.
+func syncLabels(ctx context.Context, client *github.Client, owner, repo string, desired []desiredLabel, existing []*github.Label, deleteExtra bool, wait func(change int) error) LabelSyncReport {
+	byName := make(map[string]*github.Label, len(existing))
+	for _, label := range existing {
+		byName[strings.ToLower(label.GetName())] = label
+	}
+
+	dryRun := wait == nil
+	report := LabelSyncReport{
+		DryRun:  dryRun,
+		Created: []ExportedLabel{},
+		Updated: []LabelUpdate{},
+		Deleted: []ExportedLabel{},
+	}
+	fail := func(name, action string, err error) {
+		report.Failed = append(report.Failed, LabelSyncFailure{Name: name, Action: action, Error: err.Error()})
+	}
+	// change records a change and, unless dryRun is set, waits for its turn to be made
+	change := func(name string) error {
+		report.changes = append(report.changes, name)
+		if dryRun {
+			return nil
+		}
+		return wait(len(report.changes) - 1)
+	}
+
+	for _, label := range desired {
+		current, ok := byName[strings.ToLower(label.Name)]
+		delete(byName, strings.ToLower(label.Name))
+
+		if !ok {
+			created := ExportedLabel{Name: label.Name, Color: label.Color}
+			if label.Description != nil {
+				created.Description = *label.Description
+			}
+			if err := change(label.Name); err != nil {
+				fail(label.Name, "create", err)
+				continue
+			}
+			if !dryRun {
+				_, resp, err := client.Issues.CreateLabel(ctx, owner, repo, &github.Label{
+					Name:        github.Ptr(created.Name),
+					Color:       github.Ptr(created.Color),
+					Description: label.Description,
+				})
+				if err != nil {
+					fail(label.Name, "create", err)
+					continue
+				}
+				_ = resp.Body.Close()
+			}
+			report.Created = append(report.Created, created)
+			continue
+		}
+
+		before := ExportedLabel{Name: current.GetName(), Color: strings.ToLower(current.GetColor()), Description: current.GetDescription()}
+		after := ExportedLabel{Name: label.Name, Color: label.Color, Description: before.Description}
+		if label.Description != nil {
+			after.Description = *label.Description
+		}
+		if before == after {
+			report.Unchanged++
+			continue
+		}
+		if err := change(label.Name); err != nil {
+			fail(label.Name, "update", err)
+			continue
+		}
+		if !dryRun {
+			_, resp, err := client.Issues.EditLabel(ctx, owner, repo, before.Name, &github.Label{
+				Name:        github.Ptr(after.Name),
+				Color:       github.Ptr(after.Color),
+				Description: github.Ptr(after.Description),
+			})
+			if err != nil {
+				fail(label.Name, "update", err)
+				continue
+			}
+			_ = resp.Body.Close()
+		}
+		report.Updated = append(report.Updated, LabelUpdate{Before: before, After: after})
+	}
+
+	// Whatever is left in byName is outside the label set; keep the repository's order
+	for _, label := range existing {
+		if _, ok := byName[strings.ToLower(label.GetName())]; !ok {
+			continue
+		}
+		if !deleteExtra {
+			report.Extra = append(report.Extra, label.GetName())
+			continue
+		}
+		if err := change(label.GetName()); err != nil {
+			fail(label.GetName(), "delete", err)
+			continue
+		}
+		if !dryRun {
+			resp, err := client.Issues.DeleteLabel(ctx, owner, repo, label.GetName())
+			if err != nil {
+				fail(label.GetName(), "delete", err)
+				continue
+			}
+			_ = resp.Body.Close()
+		}
+		report.Deleted = append(report.Deleted, ExportedLabel{Name: label.GetName(), Color: label.GetColor(), Description: label.GetDescription()})
+	}
+
+	return report
+}
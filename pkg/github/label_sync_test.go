@@ -0,0 +1,222 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v79/github"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_BulkSyncLabels(t *testing.T) {
+	tool, _ := BulkSyncLabels(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.True(t, *tool.Annotations.DestructiveHint)
+
+	existingLabels := []*github.Label{
+		{Name: github.Ptr("Bug"), Color: github.Ptr("D73A4A"), Description: github.Ptr("Something isn't working")},
+		{Name: github.Ptr("docs"), Color: github.Ptr("0075ca"), Description: github.Ptr("Documentation")},
+		{Name: github.Ptr("wontfix"), Color: github.Ptr("ffffff")},
+	}
+	labelSet := []any{
+		map[string]any{"name": "bug", "color": "#d73a4a", "description": "Something isn't working"},
+		map[string]any{"name": "docs", "color": "0075CA"},
+		map[string]any{"name": "priority: high", "color": "b60205", "description": "Fix first"},
+	}
+
+	type calls struct {
+		sync.Mutex
+		created, deleted []string
+		edited           map[string]map[string]any
+	}
+	newClient := func(t *testing.T, c *calls, extra ...mock.MockBackendOption) *http.Client {
+		c.edited = map[string]map[string]any{}
+		return mock.NewMockedHTTPClient(append(extra,
+			mock.WithRequestMatchHandler(
+				mock.GetReposLabelsByOwnerByRepo,
+				mockResponse(t, http.StatusOK, existingLabels),
+			),
+			mock.WithRequestMatchHandler(
+				mock.PostReposLabelsByOwnerByRepo,
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					var body map[string]any
+					require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+					c.Lock()
+					c.created = append(c.created, body["name"].(string))
+					c.Unlock()
+					mockResponse(t, http.StatusCreated, &github.Label{Name: github.Ptr(body["name"].(string))})(w, r)
+				}),
+			),
+			mock.WithRequestMatchHandler(
+				mock.PatchReposLabelsByOwnerByRepoByName,
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					var body map[string]any
+					require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+					c.Lock()
+					c.edited[r.URL.Path] = body
+					c.Unlock()
+					mockResponse(t, http.StatusOK, &github.Label{})(w, r)
+				}),
+			),
+			mock.WithRequestMatchHandler(
+				mock.DeleteReposLabelsByOwnerByRepoByName,
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					c.Lock()
+					c.deleted = append(c.deleted, r.URL.Path)
+					c.Unlock()
+					w.WriteHeader(http.StatusNoContent)
+				}),
+			),
+		)...)
+	}
+
+	t.Run("reconciles labels", func(t *testing.T) {
+		var c calls
+		_, handler := BulkSyncLabels(stubGetClientFn(github.NewClient(newClient(t, &c))), translations.NullTranslationHelper)
+
+		args := map[string]any{"owner": "owner", "repo": "repo", "labels": labelSet, "delete_extra": true}
+		request := createMCPRequest(args)
+		result, _, err := handler(context.Background(), &request, args)
+		require.NoError(t, err)
+		require.False(t, result.IsError, getTextResult(t, result).Text)
+
+		var report LabelSyncReport
+		require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &report))
+		assert.Equal(t, LabelSyncReport{
+			Created: []ExportedLabel{{Name: "priority: high", Color: "b60205", Description: "Fix first"}},
+			Updated: []LabelUpdate{{
+				Before: ExportedLabel{Name: "Bug", Color: "d73a4a", Description: "Something isn't working"},
+				After:  ExportedLabel{Name: "bug", Color: "d73a4a", Description: "Something isn't working"},
+			}},
+			Deleted:   []ExportedLabel{{Name: "wontfix", Color: "ffffff"}},
+			Unchanged: 1,
+		}, report)
+
+		assert.Equal(t, []string{"priority: high"}, c.created)
+		assert.Equal(t, map[string]map[string]any{
+			"/repos/owner/repo/labels/Bug": {"name": "bug", "color": "d73a4a", "description": "Something isn't working"},
+		}, c.edited)
+		assert.Equal(t, []string{"/repos/owner/repo/labels/wontfix"}, c.deleted)
+	})
+
+	t.Run("dry run keeps extra labels", func(t *testing.T) {
+		var c calls
+		_, handler := BulkSyncLabels(stubGetClientFn(github.NewClient(newClient(t, &c))), translations.NullTranslationHelper)
+
+		args := map[string]any{"owner": "owner", "repo": "repo", "labels": labelSet, "dry_run": true}
+		request := createMCPRequest(args)
+		result, _, err := handler(context.Background(), &request, args)
+		require.NoError(t, err)
+		require.False(t, result.IsError, getTextResult(t, result).Text)
+
+		var report LabelSyncReport
+		require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &report))
+		assert.True(t, report.DryRun)
+		assert.Len(t, report.Created, 1)
+		assert.Len(t, report.Updated, 1)
+		assert.Empty(t, report.Deleted)
+		assert.Equal(t, []string{"wontfix"}, report.Extra)
+
+		assert.Empty(t, c.created)
+		assert.Empty(t, c.edited)
+		assert.Empty(t, c.deleted)
+	})
+
+	// coreQuota answers the rate limit request with 1 call to spare, after a 10% reserve
+	coreQuota := func(resetIn time.Duration) mock.MockBackendOption {
+		return mock.WithRequestMatch(mock.GetRateLimit, map[string]any{
+			"resources": map[string]any{
+				"core": &github.Rate{Limit: 100, Remaining: 11, Reset: github.Timestamp{Time: time.Now().Add(resetIn)}},
+			},
+		})
+	}
+
+	t.Run("previews the changes when the quota runs out", func(t *testing.T) {
+		var c calls
+		_, handler := BulkSyncLabels(stubGetClientFn(github.NewClient(newClient(t, &c, coreQuota(time.Hour)))), translations.NullTranslationHelper)
+
+		args := map[string]any{"owner": "owner", "repo": "repo", "labels": labelSet, "delete_extra": true}
+		request := createMCPRequest(args)
+		result, _, err := handler(context.Background(), &request, args)
+		require.NoError(t, err)
+		require.False(t, result.IsError, getTextResult(t, result).Text)
+
+		var report LabelSyncReport
+		require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &report))
+		assert.True(t, report.DryRun)
+		assert.True(t, report.AwaitingApproval)
+		assert.Contains(t, report.Message, "Call again with approve_plan")
+		require.NotNil(t, report.Plan)
+		assert.Equal(t, 3, report.Plan.EstimatedCalls)
+		require.Len(t, report.Plan.Waves, 2)
+		assert.Equal(t, []string{"bug"}, report.Plan.Waves[0].Targets)
+		assert.Equal(t, []string{"priority: high", "wontfix"}, report.Plan.Waves[1].Targets)
+		assert.Len(t, report.Created, 1)
+
+		assert.Empty(t, c.created)
+		assert.Empty(t, c.edited)
+		assert.Empty(t, c.deleted)
+	})
+
+	t.Run("makes the changes in waves once approved", func(t *testing.T) {
+		var c calls
+		_, handler := BulkSyncLabels(stubGetClientFn(github.NewClient(newClient(t, &c, coreQuota(-time.Minute)))), translations.NullTranslationHelper)
+
+		args := map[string]any{"owner": "owner", "repo": "repo", "labels": labelSet, "delete_extra": true, "approve_plan": true}
+		request := createMCPRequest(args)
+		result, _, err := handler(context.Background(), &request, args)
+		require.NoError(t, err)
+		require.False(t, result.IsError, getTextResult(t, result).Text)
+
+		var report LabelSyncReport
+		require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &report))
+		assert.False(t, report.DryRun)
+		assert.NotNil(t, report.Plan)
+		assert.Empty(t, report.Failed)
+		assert.Equal(t, []string{"priority: high"}, c.created)
+		assert.Len(t, c.edited, 1)
+		assert.Equal(t, []string{"/repos/owner/repo/labels/wontfix"}, c.deleted)
+	})
+
+	tests := []struct {
+		name        string
+		labels      []any
+		expectError string
+	}{
+		{
+			name:        "invalid color",
+			labels:      []any{map[string]any{"name": "bug", "color": "red"}},
+			expectError: `labels[0]: color of "bug" must be a 6-character hex code`,
+		},
+		{
+			name:        "duplicate name",
+			labels:      []any{map[string]any{"name": "bug", "color": "d73a4a"}, map[string]any{"name": "BUG", "color": "d73a4a"}},
+			expectError: `labels[1]: label "BUG" appears more than once`,
+		},
+		{
+			name:        "missing color",
+			labels:      []any{map[string]any{"name": "bug"}},
+			expectError: "labels[0]: missing required parameter: color",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, handler := BulkSyncLabels(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+			args := map[string]any{"owner": "owner", "repo": "repo", "labels": tc.labels}
+			request := createMCPRequest(args)
+			result, _, err := handler(context.Background(), &request, args)
+			require.NoError(t, err)
+			require.True(t, result.IsError)
+			assert.Contains(t, getErrorResult(t, result).Text, tc.expectError)
+		})
+	}
+}
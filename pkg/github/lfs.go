@@ -0,0 +1,228 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-github/v79/github"
+	"github.com/google/jsonschema-go/jsonschema"
+)
+
+// MaxLFSFileSizeBytes is the largest file GitHub stores in Git LFS (2GB)
+const MaxLFSFileSizeBytes = 2 * 1024 * 1024 * 1024
+
+// MaxLFSDecompressedTotalBytes bounds the content decompressed from the gzip-compressed files of
+// one call that stores files in Git LFS (4GB)
+const MaxLFSDecompressedTotalBytes = 2 * MaxLFSFileSizeBytes
+
+// lfsMediaType is the media type of the requests and responses of the Git LFS batch API
+const lfsMediaType = "application/vnd.git-lfs+json"
+
+// LFSObject reports a file stored in Git LFS by a push and committed as a pointer file
+type LFSObject struct {
+	Path string `json:"path"`
+	// OID is the SHA-256 of the content, which the pointer file refers to
+	OID  string `json:"oid"`
+	Size int64  `json:"size"`
+	// Uploaded is false when the LFS server already had the content
+	Uploaded bool `json:"uploaded"`
+}
+
+// lfsFileObjectSchema describes a file object of the push tools that can store files in Git LFS
+func lfsFileObjectSchema() *jsonschema.Schema {
+	schema := fileObjectSchema()
+	schema.Properties["lfs"] = &jsonschema.Schema{
+		Type:        "boolean",
+		Description: "Upload the content to the Git LFS storage of the repository and commit an LFS pointer file instead. Files stored in LFS are exempt from the maximum file size. The path should be tracked with filter=lfs in .gitattributes so that clones check out the content",
+	}
+	return schema
+}
+
+// lfsThresholdSchema describes the lfs_threshold_bytes parameter of the push tools
+func lfsThresholdSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:        "integer",
+		Description: "Store every file larger than this many bytes in Git LFS, as if lfs were set on it. Set it at or below the maximum file size to push large files instead of failing with FILE_TOO_LARGE (default: 0, files are stored in LFS only when lfs is set)",
+		Minimum:     jsonschema.Ptr(0.0),
+	}
+}
+
+// lfsValidationOptionsFromArgs returns opts with Git LFS enabled and the threshold set by
+// lfs_threshold_bytes.
+func lfsValidationOptionsFromArgs(args map[string]any, opts FileValidationOptions) (FileValidationOptions, error) {
+	threshold, err := OptionalIntParam(args, "lfs_threshold_bytes")
+	if err != nil {
+		return opts, err
+	}
+	if threshold < 0 {
+		return opts, fmt.Errorf("lfs_threshold_bytes must not be negative")
+	}
+	opts.LFS = true
+	opts.LFSThresholdBytes = int64(threshold)
+	return opts, nil
+}
+
+// lfsPointer returns the pointer file committed in place of content of the given SHA-256 and size.
+func lfsPointer(oid string, size int64) string {
+	return fmt.Sprintf("version https://git-lfs.github.com/spec/v1\noid sha256:%s\nsize %d\n", oid, size)
+}
+
+type lfsBatchRequest struct {
+	Operation string           `json:"operation"`
+	Transfers []string         `json:"transfers"`
+	Objects   []lfsBatchObject `json:"objects"`
+	HashAlgo  string           `json:"hash_algo"`
+}
+
+type lfsBatchResponse struct {
+	Objects []lfsBatchObject `json:"objects"`
+	Message string           `json:"message"`
+}
+
+type lfsBatchObject struct {
+	OID     string               `json:"oid"`
+	Size    int64                `json:"size"`
+	Actions map[string]lfsAction `json:"actions,omitempty"`
+	Error   *lfsObjectError      `json:"error,omitempty"`
+}
+
+type lfsAction struct {
+	Href   string            `json:"href"`
+	Header map[string]string `json:"header"`
+}
+
+type lfsObjectError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// uploadLFSFiles uploads the content of the files with LFS set to the Git LFS storage of the
+// repository, through the batch API at its clone URL, and replaces their content with the pointer
+// files to commit. Content the server already has is not uploaded again.
+func uploadLFSFiles(ctx context.Context, client *github.Client, owner, repo string, files []FileEntry) ([]LFSObject, error) {
+	var objects []LFSObject
+	contents := make(map[string]string)
+	var batch []lfsBatchObject
+	for _, file := range files {
+		if !file.LFS {
+			continue
+		}
+		sum := sha256.Sum256([]byte(file.Content))
+		object := LFSObject{Path: file.Path, OID: hex.EncodeToString(sum[:]), Size: int64(len(file.Content))}
+		objects = append(objects, object)
+		if _, seen := contents[object.OID]; !seen {
+			contents[object.OID] = file.Content
+			batch = append(batch, lfsBatchObject{OID: object.OID, Size: object.Size})
+		}
+	}
+	if len(objects) == 0 {
+		return nil, nil
+	}
+
+	repository, resp, err := client.Repositories.Get(ctx, owner, repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repository: %w", err)
+	}
+	_ = resp.Body.Close()
+	batchURL := strings.TrimSuffix(repository.GetCloneURL(), "/") + "/info/lfs/objects/batch"
+
+	// The batch API is served by the GitHub host, so it is called with the authenticated client
+	body, err := json.Marshal(lfsBatchRequest{Operation: "upload", Transfers: []string{"basic"}, Objects: batch, HashAlgo: "sha256"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal LFS batch request: %w", err)
+	}
+	var response lfsBatchResponse
+	if err := doLFSRequest(ctx, client.Client(), http.MethodPost, batchURL, nil, lfsMediaType, bytes.NewReader(body), &response); err != nil {
+		return nil, fmt.Errorf("LFS batch request failed: %w", err)
+	}
+
+	uploaded := make(map[string]bool)
+	for _, object := range response.Objects {
+		if object.Error != nil {
+			return nil, fmt.Errorf("LFS server rejected object %s: %s (%d)", object.OID, object.Error.Message, object.Error.Code)
+		}
+		content, ok := contents[object.OID]
+		if !ok {
+			return nil, fmt.Errorf("LFS server returned unknown object %s", object.OID)
+		}
+		// Objects without an upload action are already stored
+		upload, ok := object.Actions["upload"]
+		if !ok {
+			continue
+		}
+		// Upload and verify URLs may point to storage hosts, so they only get the headers the
+		// server returned for them
+		if err := doLFSRequest(ctx, http.DefaultClient, http.MethodPut, upload.Href, upload.Header, "application/octet-stream", strings.NewReader(content), nil); err != nil {
+			return nil, fmt.Errorf("failed to upload LFS object %s: %w", object.OID, err)
+		}
+		if verify, ok := object.Actions["verify"]; ok {
+			body, err := json.Marshal(lfsBatchObject{OID: object.OID, Size: object.Size})
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal LFS verify request: %w", err)
+			}
+			if err := doLFSRequest(ctx, http.DefaultClient, http.MethodPost, verify.Href, verify.Header, lfsMediaType, bytes.NewReader(body), nil); err != nil {
+				return nil, fmt.Errorf("failed to verify LFS object %s: %w", object.OID, err)
+			}
+		}
+		uploaded[object.OID] = true
+	}
+
+	// objects lists the LFS files in order, so the nth of them is the nth file with LFS set
+	next := 0
+	for i := range files {
+		if !files[i].LFS {
+			continue
+		}
+		object := &objects[next]
+		object.Uploaded = uploaded[object.OID]
+		files[i].Content = lfsPointer(object.OID, object.Size)
+		files[i].Binary = false
+		next++
+	}
+	return objects, nil
+}
+
+// doLFSRequest sends a request of the Git LFS API with the given headers and decodes the JSON
+// response into result, if not nil. Responses other than 200, 201 and 204 are errors.
+func doLFSRequest(ctx context.Context, client *http.Client, method, url string, header map[string]string, contentType string, body io.Reader, result any) error {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", lfsMediaType)
+	req.Header.Set("Content-Type", contentType)
+	for name, value := range header {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusNoContent:
+	default:
+		var failure lfsBatchResponse
+		_ = json.NewDecoder(io.LimitReader(resp.Body, 64*1024)).Decode(&failure)
+		if failure.Message != "" {
+			return fmt.Errorf("HTTP %d: %s", resp.StatusCode, failure.Message)
+		}
+		return fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	if result == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+package github
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v79/github"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func lfsOID(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// lfsStorage is a fake Git LFS server: the batch API, served through the mocked GitHub client,
+// asks for the upload of every object it does not store, and uploads go to storage.
+type lfsStorage struct {
+	server   *httptest.Server
+	objects  map[string]string
+	verified []string
+}
+
+func newLFSStorage(t *testing.T, stored ...string) *lfsStorage {
+	storage := &lfsStorage{objects: make(map[string]string)}
+	for _, content := range stored {
+		storage.objects[lfsOID(content)] = content
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("PUT /objects/{oid}", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "upload-token", r.Header.Get("Authorization"))
+		content, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		storage.objects[r.PathValue("oid")] = string(content)
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("POST /verify", func(w http.ResponseWriter, r *http.Request) {
+		var object lfsBatchObject
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&object))
+		storage.verified = append(storage.verified, object.OID)
+		w.WriteHeader(http.StatusOK)
+	})
+	storage.server = httptest.NewServer(mux)
+	t.Cleanup(storage.server.Close)
+	return storage
+}
+
+// options returns the mock options of the repository and the batch API of the storage.
+func (s *lfsStorage) options(t *testing.T) []mock.MockBackendOption {
+	return []mock.MockBackendOption{
+		mock.WithRequestMatchHandler(
+			mock.GetReposByOwnerByRepo,
+			mockResponse(t, http.StatusOK, &github.Repository{CloneURL: github.Ptr("https://github.com/owner/repo.git")}),
+		),
+		mock.WithRequestMatchHandler(
+			mock.EndpointPattern{Pattern: "/owner/repo.git/info/lfs/objects/batch", Method: "POST"},
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, lfsMediaType, r.Header.Get("Content-Type"))
+				var request lfsBatchRequest
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+				assert.Equal(t, "upload", request.Operation)
+
+				var response lfsBatchResponse
+				for _, object := range request.Objects {
+					if _, ok := s.objects[object.OID]; !ok {
+						object.Actions = map[string]lfsAction{
+							"upload": {Href: s.server.URL + "/objects/" + object.OID, Header: map[string]string{"Authorization": "upload-token"}},
+							"verify": {Href: s.server.URL + "/verify"},
+						}
+					}
+					response.Objects = append(response.Objects, object)
+				}
+				w.Header().Set("Content-Type", lfsMediaType)
+				require.NoError(t, json.NewEncoder(w).Encode(response))
+			}),
+		),
+	}
+}
+
+func Test_ValidateFilesWithOptions_LFS(t *testing.T) {
+	files := []interface{}{
+		map[string]interface{}{"path": "model.bin", "content": strings.Repeat("x", 100), "lfs": true},
+		map[string]interface{}{"path": "data.csv", "content": strings.Repeat("y", 50)},
+		map[string]interface{}{"path": "README.md", "content": "# Models"},
+	}
+
+	result, entries, err := ValidateFilesWithOptions(files, FileValidationOptions{LFS: true, LFSThresholdBytes: 20})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"model.bin", "data.csv"}, result.LFSFiles)
+	assert.Equal(t, "README.md", result.LargestFile)
+	assert.Equal(t, int64(len("# Models")), result.TotalSize)
+	assert.True(t, entries[0].LFS)
+	assert.True(t, entries[1].LFS)
+	assert.False(t, entries[2].LFS)
+
+	// Without LFS enabled, the lfs flag of a file is ignored
+	result, entries, err = ValidateFilesWithOptions(files, FileValidationOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, result.LFSFiles)
+	assert.Equal(t, "model.bin", result.LargestFile)
+	assert.False(t, entries[0].LFS)
+}
+
+func Test_uploadLFSFiles(t *testing.T) {
+	model := strings.Repeat("weights", 10)
+	dataset := "already stored"
+	storage := newLFSStorage(t, dataset)
+	client := github.NewClient(mock.NewMockedHTTPClient(storage.options(t)...))
+
+	files := []FileEntry{
+		{Path: "model.bin", Content: model, Binary: true, LFS: true},
+		{Path: "README.md", Content: "# Models"},
+		{Path: "data/set.csv", Content: dataset, LFS: true},
+		{Path: "copy.bin", Content: model, LFS: true},
+	}
+	objects, err := uploadLFSFiles(context.Background(), client, "owner", "repo", files)
+	require.NoError(t, err)
+
+	assert.Equal(t, []LFSObject{
+		{Path: "model.bin", OID: lfsOID(model), Size: int64(len(model)), Uploaded: true},
+		{Path: "data/set.csv", OID: lfsOID(dataset), Size: int64(len(dataset)), Uploaded: false},
+		{Path: "copy.bin", OID: lfsOID(model), Size: int64(len(model)), Uploaded: true},
+	}, objects)
+	assert.Equal(t, model, storage.objects[lfsOID(model)])
+	assert.Equal(t, []string{lfsOID(model)}, storage.verified)
+
+	assert.Equal(t, "version https://git-lfs.github.com/spec/v1\noid sha256:"+lfsOID(model)+"\nsize 70\n", files[0].Content)
+	assert.False(t, files[0].Binary)
+	assert.Equal(t, "# Models", files[1].Content)
+	assert.Equal(t, lfsPointer(lfsOID(dataset), int64(len(dataset))), files[2].Content)
+
+	t.Run("rejected object", func(t *testing.T) {
+		client := github.NewClient(mock.NewMockedHTTPClient(
+			mock.WithRequestMatchHandler(
+				mock.GetReposByOwnerByRepo,
+				mockResponse(t, http.StatusOK, &github.Repository{CloneURL: github.Ptr("https://github.com/owner/repo.git")}),
+			),
+			mock.WithRequestMatchHandler(
+				mock.EndpointPattern{Pattern: "/owner/repo.git/info/lfs/objects/batch", Method: "POST"},
+				mockResponse(t, http.StatusOK, lfsBatchResponse{Objects: []lfsBatchObject{{OID: lfsOID("big"), Size: 3, Error: &lfsObjectError{Code: 422, Message: "quota exceeded"}}}}),
+			),
+		))
+		_, err := uploadLFSFiles(context.Background(), client, "owner", "repo", []FileEntry{{Path: "big.bin", Content: "big", LFS: true}})
+		assert.ErrorContains(t, err, "quota exceeded")
+	})
+
+	t.Run("LFS disabled on the repository", func(t *testing.T) {
+		client := github.NewClient(mock.NewMockedHTTPClient(
+			mock.WithRequestMatchHandler(
+				mock.GetReposByOwnerByRepo,
+				mockResponse(t, http.StatusOK, &github.Repository{CloneURL: github.Ptr("https://github.com/owner/repo.git")}),
+			),
+			mock.WithRequestMatchHandler(
+				mock.EndpointPattern{Pattern: "/owner/repo.git/info/lfs/objects/batch", Method: "POST"},
+				mockResponse(t, http.StatusForbidden, map[string]string{"message": "Git LFS is disabled for this repository."}),
+			),
+		))
+		_, err := uploadLFSFiles(context.Background(), client, "owner", "repo", []FileEntry{{Path: "big.bin", Content: "big", LFS: true}})
+		assert.ErrorContains(t, err, "HTTP 403: Git LFS is disabled for this repository.")
+	})
+}
+
+func Test_PushFilesChunked_LFS(t *testing.T) {
+	model := strings.Repeat("weights", 10)
+	storage := newLFSStorage(t)
+	options := mockGitDataAPI(t)
+	options[2] = mock.WithRequestMatchHandler(
+		mock.PostReposGitTreesByOwnerByRepo,
+		expectRequestBody(t, map[string]any{
+			"base_tree": "base-tree-sha",
+			"tree": []any{
+				map[string]any{"path": "model.bin", "mode": "100644", "type": "blob", "content": lfsPointer(lfsOID(model), int64(len(model)))},
+				map[string]any{"path": "README.md", "mode": "100644", "type": "blob", "content": "# Models"},
+			},
+		}).andThen(mockResponse(t, http.StatusCreated, &github.Tree{SHA: github.Ptr("new-tree-sha")})),
+	)
+	options = append(options, storage.options(t)...)
+	_, handler := PushFilesChunked(stubGetClientFn(github.NewClient(mock.NewMockedHTTPClient(options...))), translations.NullTranslationHelper)
+
+	args := map[string]any{
+		"owner":  "owner",
+		"repo":   "repo",
+		"branch": "main",
+		"files": []any{
+			map[string]any{"path": "model.bin", "content": model},
+			map[string]any{"path": "README.md", "content": "# Models"},
+		},
+		"message":             "Add model",
+		"lfs_threshold_bytes": float64(20),
+	}
+	request := createMCPRequest(args)
+	result, _, err := handler(context.Background(), &request, args)
+	require.NoError(t, err)
+	require.False(t, result.IsError, getTextResult(t, result).Text)
+
+	var response PushFilesChunkedResult
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &response))
+	assert.True(t, response.FullySuccessful)
+	assert.Equal(t, []LFSObject{{Path: "model.bin", OID: lfsOID(model), Size: int64(len(model)), Uploaded: true}}, response.LFSObjects)
+	assert.Equal(t, model, storage.objects[lfsOID(model)])
+}
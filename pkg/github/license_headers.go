@@ -0,0 +1,495 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	ghErrors "github.com/github/github-mcp-server/pkg/errors"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/github/github-mcp-server/pkg/utils"
+	"github.com/google/go-github/v79/github"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// maxLicenseHeaderFiles bounds the files read from a ref by one check_license_headers call
+const maxLicenseHeaderFiles = 1000
+
+// licenseHeaderScanLines is how many lines past the header length are searched for it, to allow
+// for shebangs, package comments and blank lines above the header
+const licenseHeaderScanLines = 10
+
+// commentStyle is how a header is commented out in files of one extension. Line comments
+// prefix every line; block comments wrap the header in Start and End.
+type commentStyle struct {
+	Line  string
+	Start string
+	End   string
+}
+
+// licenseHeaderStyles are the extensions check_license_headers knows how to comment a header in.
+var licenseHeaderStyles = map[string]commentStyle{
+	".go":    {Line: "//"},
+	".js":    {Line: "//"},
+	".jsx":   {Line: "//"},
+	".ts":    {Line: "//"},
+	".tsx":   {Line: "//"},
+	".java":  {Line: "//"},
+	".kt":    {Line: "//"},
+	".scala": {Line: "//"},
+	".swift": {Line: "//"},
+	".rs":    {Line: "//"},
+	".c":     {Line: "//"},
+	".h":     {Line: "//"},
+	".cc":    {Line: "//"},
+	".cpp":   {Line: "//"},
+	".hpp":   {Line: "//"},
+	".cs":    {Line: "//"},
+	".py":    {Line: "#"},
+	".rb":    {Line: "#"},
+	".sh":    {Line: "#"},
+	".bash":  {Line: "#"},
+	".pl":    {Line: "#"},
+	".r":     {Line: "#"},
+	".yaml":  {Line: "#"},
+	".yml":   {Line: "#"},
+	".toml":  {Line: "#"},
+	".tf":    {Line: "#"},
+	".sql":   {Line: "--"},
+	".lua":   {Line: "--"},
+	".hs":    {Line: "--"},
+	".css":   {Start: "/*", Line: " *", End: " */"},
+	".scss":  {Start: "/*", Line: " *", End: " */"},
+	".html":  {Start: "<!--", End: "-->"},
+}
+
+// commentMarkers are stripped from the start and end of lines before looking for a header,
+// so that a header is found whatever comment style it was written in
+var commentMarkers = regexp.MustCompile(`^\s*(//+|#+|--+|/\*+|\*+/?|<!--)?\s*|\s*(\*+/|-->)?\s*$`)
+
+// licenseHeaderYear matches the {year} placeholder of a header, which accepts any year or range
+const licenseHeaderYear = `\d{4}(\s*-\s*\d{4})?`
+
+// LicenseHeaderViolation is a file without the required header.
+type LicenseHeaderViolation struct {
+	Path string `json:"path"`
+	// Fixable is set when the header can be added automatically
+	Fixable bool   `json:"fixable"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// LicenseHeaderFixedFile is a file with the missing header added, returned when checking planned files.
+type LicenseHeaderFixedFile struct {
+	Path    string `json:"path"`
+	Content string `json:"content"`
+}
+
+// CheckLicenseHeadersResult reports the files missing a license header.
+type CheckLicenseHeadersResult struct {
+	Ref          string                   `json:"ref,omitempty"`
+	CheckedFiles int                      `json:"checked_files"`
+	Violations   []LicenseHeaderViolation `json:"violations"`
+	// FixCommits are the commits that added the missing headers to the branch
+	FixCommits []string `json:"fix_commits,omitempty"`
+	// FixedFiles are the planned files with the missing headers added, to push instead
+	FixedFiles []LicenseHeaderFixedFile `json:"fixed_files,omitempty"`
+}
+
+// licenseHeaderChecker checks and adds a header in the comment style of each file's extension.
+type licenseHeaderChecker struct {
+	header    []string
+	pattern   *regexp.Regexp
+	templates map[string]string
+	now       time.Time
+}
+
+func newLicenseHeaderChecker(header string, templates map[string]string, now time.Time) (*licenseHeaderChecker, error) {
+	var lines []string
+	var patterns []string
+	for _, line := range strings.Split(strings.TrimSpace(header), "\n") {
+		line = strings.TrimSpace(line)
+		lines = append(lines, line)
+		if line == "" {
+			continue
+		}
+		parts := strings.Split(line, "{year}")
+		for i, part := range parts {
+			parts[i] = regexp.QuoteMeta(part)
+		}
+		patterns = append(patterns, strings.Join(parts, licenseHeaderYear))
+	}
+	if len(patterns) == 0 {
+		return nil, fmt.Errorf("header must not be empty")
+	}
+	for ext := range templates {
+		if !strings.HasPrefix(ext, ".") {
+			return nil, fmt.Errorf("templates must be keyed by extensions starting with a dot, e.g. .go, not %s", ext)
+		}
+	}
+	return &licenseHeaderChecker{
+		header:    lines,
+		pattern:   regexp.MustCompile(`(?m)^` + strings.Join(patterns, `\n`) + `$`),
+		templates: templates,
+		now:       now,
+	}, nil
+}
+
+// supports reports whether the header can be rendered for files at path.
+func (c *licenseHeaderChecker) supports(filePath string) bool {
+	ext := strings.ToLower(path.Ext(filePath))
+	if _, ok := c.templates[ext]; ok {
+		return true
+	}
+	_, ok := licenseHeaderStyles[ext]
+	return ok
+}
+
+// hasHeader reports whether the header is among the first lines of content, in any comment style.
+func (c *licenseHeaderChecker) hasHeader(content string) bool {
+	lines := strings.SplitN(content, "\n", len(c.header)+licenseHeaderScanLines+1)
+	if len(lines) > len(c.header)+licenseHeaderScanLines {
+		lines = lines[:len(lines)-1]
+	}
+	var stripped []string
+	for _, line := range lines {
+		line = commentMarkers.ReplaceAllString(strings.TrimRight(line, "\r"), "")
+		if line != "" {
+			stripped = append(stripped, line)
+		}
+	}
+	return c.pattern.MatchString(strings.Join(stripped, "\n"))
+}
+
+// render returns the header for files at path, from the extension's template if there is one.
+func (c *licenseHeaderChecker) render(filePath string) string {
+	year := strconv.Itoa(c.now.Year())
+	ext := strings.ToLower(path.Ext(filePath))
+	if template, ok := c.templates[ext]; ok {
+		return strings.TrimRight(strings.ReplaceAll(template, "{year}", year), "\n") + "\n"
+	}
+
+	style := licenseHeaderStyles[ext]
+	var b strings.Builder
+	if style.Start != "" {
+		b.WriteString(style.Start + "\n")
+	}
+	for _, line := range c.header {
+		line = strings.ReplaceAll(line, "{year}", year)
+		switch {
+		case style.Line == "":
+			b.WriteString(line)
+		case line == "":
+			b.WriteString(style.Line)
+		default:
+			b.WriteString(style.Line + " " + line)
+		}
+		b.WriteString("\n")
+	}
+	if style.End != "" {
+		b.WriteString(style.End + "\n")
+	}
+	return b.String()
+}
+
+// fix returns content with the header added at the top, below a shebang line if there is one.
+func (c *licenseHeaderChecker) fix(filePath, content string) string {
+	header := c.render(filePath) + "\n"
+	if strings.HasPrefix(content, "#!") {
+		if i := strings.Index(content, "\n"); i >= 0 {
+			return content[:i+1] + "\n" + header + content[i+1:]
+		}
+		return content + "\n\n" + header
+	}
+	return header + content
+}
+
+// check returns the violation of a file, if it lacks the header.
+func (c *licenseHeaderChecker) check(filePath string, content []byte) (LicenseHeaderViolation, bool) {
+	violation := LicenseHeaderViolation{Path: filePath}
+	if !utf8.Valid(content) {
+		violation.Reason = "not UTF-8 text"
+		return violation, !c.hasHeader(string(content))
+	}
+	if c.hasHeader(string(content)) {
+		return violation, false
+	}
+	violation.Fixable = true
+	return violation, true
+}
+
+// CheckLicenseHeaders creates a tool to find, and optionally add, missing license headers.
+func CheckLicenseHeaders(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "check_license_headers",
+		Description: t("TOOL_CHECK_LICENSE_HEADERS_DESCRIPTION", "Check that source files carry a required license header, either at a ref of a repository or in files planned for a push. The header is given as plain text and is recognized in any comment style. With fix set, the missing headers are added in the comment style of each file's extension: committed to the branch when checking a ref, or returned as fixed_files when checking planned files."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_CHECK_LICENSE_HEADERS_USER_TITLE", "Check license headers"),
+			ReadOnlyHint: false,
+		},
+		InputSchema: withCommitIdentity(&jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"owner": {
+					Type:        "string",
+					Description: "Repository owner",
+				},
+				"repo": {
+					Type:        "string",
+					Description: "Repository name",
+				},
+				"ref": {
+					Type:        "string",
+					Description: "Branch, tag or commit SHA to check. Defaults to the default branch. Must be a branch when fix is set",
+				},
+				"paths": {
+					Type:        "array",
+					Description: "File or directory paths to check at ref. Omit to check every file",
+					Items: &jsonschema.Schema{
+						Type: "string",
+					},
+				},
+				"files": {
+					Type:        "array",
+					Description: "Planned files to check instead of the files at ref, each object with path (string) and either content (string) or upload_id (string)",
+					Items:       fileObjectSchema(),
+				},
+				"header": {
+					Type:        "string",
+					Description: "Required header as plain text without comment markers, e.g. \"Copyright {year} Example Inc.\\nSPDX-License-Identifier: Apache-2.0\". {year} matches any year, or year range, and is filled with the current year when fixing",
+				},
+				"templates": {
+					Type:        "object",
+					Description: "Header to add to files of an extension, including comment markers, keyed by extension, e.g. {\".go\": \"// Copyright {year} Example Inc.\\n\"}. Extensions with a template are checked in addition to the built-in ones",
+					AdditionalProperties: &jsonschema.Schema{
+						Type: "string",
+					},
+				},
+				"extensions": {
+					Type:        "array",
+					Description: "Extensions to check, e.g. [\".go\", \".py\"]. Defaults to every extension with a built-in comment style or a template",
+					Items: &jsonschema.Schema{
+						Type: "string",
+					},
+				},
+				"fix": {
+					Type:        "boolean",
+					Description: "Add the missing headers (default: false)",
+				},
+				"message": {
+					Type:        "string",
+					Description: "Commit message of the fix",
+					Default:     json.RawMessage(`"Add missing license headers"`),
+				},
+			},
+			Required: []string{"owner", "repo", "header"},
+		}),
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		repo, err := RequiredParam[string](args, "repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		header, err := RequiredParam[string](args, "header")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		ref, err := OptionalParam[string](args, "ref")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		paths, err := OptionalStringArrayParam(args, "paths")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		extensions, err := OptionalStringArrayParam(args, "extensions")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		fix, err := OptionalParam[bool](args, "fix")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		message, err := OptionalParam[string](args, "message")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		if message == "" {
+			message = "Add missing license headers"
+		}
+		identity, err := commitIdentityFromArgs(ctx, args)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		templates := make(map[string]string)
+		if raw, ok := args["templates"]; ok && raw != nil {
+			templatesObj, ok := raw.(map[string]any)
+			if !ok {
+				return utils.NewToolResultError("templates must be an object mapping extensions to headers"), nil, nil
+			}
+			for ext, template := range templatesObj {
+				text, ok := template.(string)
+				if !ok || strings.TrimSpace(text) == "" {
+					return utils.NewToolResultError(fmt.Sprintf("template for %s must be a non-empty string", ext)), nil, nil
+				}
+				templates[strings.ToLower(ext)] = text
+			}
+		}
+		checker, err := newLicenseHeaderChecker(header, templates, time.Now())
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		selected := func(filePath string) bool {
+			if !checker.supports(filePath) {
+				return false
+			}
+			if len(extensions) == 0 {
+				return true
+			}
+			ext := strings.ToLower(path.Ext(filePath))
+			for _, e := range extensions {
+				if strings.ToLower(e) == ext {
+					return true
+				}
+			}
+			return false
+		}
+
+		// Planned files are checked without reading the repository
+		if filesObj, ok := args["files"].([]interface{}); ok {
+			if ref != "" || len(paths) > 0 {
+				return utils.NewToolResultError("files cannot be combined with ref or paths"), nil, nil
+			}
+			// Nothing is pushed, so the files are not scanned for secrets
+			files, _, err := validatedFiles(ctx, CurrentPushLimits(), filesObj, FileValidationOptions{AllowSecrets: true})
+			if err != nil {
+				return toolErrorResult(err), nil, nil
+			}
+			result := CheckLicenseHeadersResult{Violations: []LicenseHeaderViolation{}}
+			for _, file := range files {
+				if !selected(file.Path) {
+					continue
+				}
+				result.CheckedFiles++
+				violation, missing := checker.check(file.Path, []byte(file.Content))
+				if !missing {
+					continue
+				}
+				result.Violations = append(result.Violations, violation)
+				if fix && violation.Fixable {
+					result.FixedFiles = append(result.FixedFiles, LicenseHeaderFixedFile{Path: file.Path, Content: checker.fix(file.Path, file.Content)})
+				}
+			}
+			return MarshalledTextResult(result), nil, nil
+		}
+
+		for i, p := range paths {
+			paths[i] = strings.Trim(p, "/")
+			if paths[i] == "" {
+				return utils.NewToolResultError(fmt.Sprintf("path at index %d must be a non-empty string", i)), nil, nil
+			}
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		if ref == "" {
+			repository, resp, err := client.Repositories.Get(ctx, owner, repo)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to get repository", resp, err), nil, nil
+			}
+			_ = resp.Body.Close()
+			ref = repository.GetDefaultBranch()
+		}
+
+		tree, resp, err := client.Git.GetTree(ctx, owner, repo, ref, true)
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to get tree", resp, err), nil, nil
+		}
+		_ = resp.Body.Close()
+		if tree.GetTruncated() {
+			return utils.NewToolResultError(fmt.Sprintf(
+				"tree of %s/%s@%s is too large to list recursively; check smaller directories with paths",
+				owner, repo, ref,
+			)), nil, nil
+		}
+
+		entries := tree.Entries
+		if len(paths) > 0 {
+			var missing []string
+			entries, missing = selectTreeEntries(tree.Entries, paths)
+			if len(missing) > 0 {
+				return utils.NewToolResultError(fmt.Sprintf(
+					"paths not found in %s/%s@%s: %s",
+					owner, repo, ref, strings.Join(missing, ", "),
+				)), nil, nil
+			}
+		}
+		var checked []*github.TreeEntry
+		for _, entry := range entries {
+			// Symlinks and submodules have no content to add a header to
+			if entry.GetType() == "blob" && entry.GetMode() != "120000" && selected(entry.GetPath()) {
+				checked = append(checked, entry)
+			}
+		}
+		if len(checked) > maxLicenseHeaderFiles {
+			return utils.NewToolResultError(fmt.Sprintf(
+				"%d files to check exceed the maximum of %d per call; check smaller directories with paths",
+				len(checked), maxLicenseHeaderFiles,
+			)), nil, nil
+		}
+		sort.Slice(checked, func(i, j int) bool { return checked[i].GetPath() < checked[j].GetPath() })
+
+		result := CheckLicenseHeadersResult{Ref: ref, CheckedFiles: len(checked), Violations: []LicenseHeaderViolation{}}
+		var fixed []FileEntry
+		for _, entry := range checked {
+			content, resp, err := client.Git.GetBlobRaw(ctx, owner, repo, entry.GetSHA())
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx, fmt.Sprintf("failed to read file %s", entry.GetPath()), resp, err), nil, nil
+			}
+			_ = resp.Body.Close()
+
+			violation, missing := checker.check(entry.GetPath(), content)
+			if !missing {
+				continue
+			}
+			result.Violations = append(result.Violations, violation)
+			if violation.Fixable {
+				fixed = append(fixed, FileEntry{Path: entry.GetPath(), Content: checker.fix(entry.GetPath(), string(content))})
+			}
+		}
+
+		if fix && len(fixed) > 0 {
+			chunks := planChunks(fixed, CurrentPushLimits().DefaultChunkSize)
+			ctx, _ := withRetryBudget(ctx)
+			for i, chunk := range chunks {
+				commit, err := pushChunk(ctx, client, owner, repo, "refs/heads/"+ref, chunk, renderChunkMessage("", message, i+1, len(chunks), chunk), identity, chunkRetryConfig, false)
+				if err != nil {
+					return utils.NewToolResultError(fmt.Sprintf("failed to commit the fix to branch %s after %d of %d commits: %s", ref, i, len(chunks), err)), nil, nil
+				}
+				if !commit.NoChanges {
+					result.FixCommits = append(result.FixCommits, commit.SHA)
+				}
+			}
+		}
+
+		return MarshalledTextResult(result), nil, nil
+	})
+
+	return tool, handler
+}
@@ -0,0 +1,233 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v79/github"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testLicenseHeader = "Copyright {year} Example Inc.\nSPDX-License-Identifier: Apache-2.0"
+
+func Test_licenseHeaderChecker(t *testing.T) {
+	checker, err := newLicenseHeaderChecker(testLicenseHeader, map[string]string{".proto": "// Copyright {year} Example Inc.\n// SPDX-License-Identifier: Apache-2.0"}, time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	assert.True(t, checker.hasHeader("// Copyright 2019 Example Inc.\n// SPDX-License-Identifier: Apache-2.0\n\npackage main\n"))
+	assert.True(t, checker.hasHeader("#!/usr/bin/env python\n# Copyright 2019-2024 Example Inc.\n# SPDX-License-Identifier: Apache-2.0\n"))
+	assert.True(t, checker.hasHeader("/*\n * Copyright 2020 Example Inc.\n * SPDX-License-Identifier: Apache-2.0\n */\nbody {}\n"))
+	assert.False(t, checker.hasHeader("package main\n"))
+	assert.False(t, checker.hasHeader("// Copyright 2020 Other Corp.\n// SPDX-License-Identifier: Apache-2.0\n"))
+	assert.False(t, checker.hasHeader(strings.Repeat("\n", 20)+"// Copyright 2020 Example Inc.\n// SPDX-License-Identifier: Apache-2.0\n"))
+
+	assert.Equal(t, "// Copyright 2026 Example Inc.\n// SPDX-License-Identifier: Apache-2.0\n\npackage main\n", checker.fix("main.go", "package main\n"))
+	assert.Equal(t, "#!/bin/sh\n\n# Copyright 2026 Example Inc.\n# SPDX-License-Identifier: Apache-2.0\n\necho hi\n", checker.fix("run.sh", "#!/bin/sh\necho hi\n"))
+	assert.Equal(t, "/*\n * Copyright 2026 Example Inc.\n * SPDX-License-Identifier: Apache-2.0\n */\n\nbody {}\n", checker.fix("site.css", "body {}\n"))
+	assert.Equal(t, "// Copyright 2026 Example Inc.\n// SPDX-License-Identifier: Apache-2.0\n\nsyntax = \"proto3\";\n", checker.fix("api.proto", "syntax = \"proto3\";\n"))
+
+	assert.True(t, checker.supports("api.proto"))
+	assert.True(t, checker.supports("Main.JAVA"))
+	assert.False(t, checker.supports("README.md"))
+
+	_, err = newLicenseHeaderChecker(" \n ", nil, time.Now())
+	assert.Error(t, err)
+	_, err = newLicenseHeaderChecker(testLicenseHeader, map[string]string{"go": "// header"}, time.Now())
+	assert.Error(t, err)
+}
+
+func Test_CheckLicenseHeaders(t *testing.T) {
+	mockClient := github.NewClient(nil)
+	tool, _ := CheckLicenseHeaders(stubGetClientFn(mockClient), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	schema, ok := tool.InputSchema.(*jsonschema.Schema)
+	require.True(t, ok, "InputSchema should be *jsonschema.Schema")
+
+	assert.Equal(t, "check_license_headers", tool.Name)
+	assert.Contains(t, schema.Properties, "templates")
+	assert.Contains(t, schema.Properties, "fix")
+	assert.ElementsMatch(t, schema.Required, []string{"owner", "repo", "header"})
+
+	blobs := map[string]string{
+		"good-sha": "// Copyright 2024 Example Inc.\n// SPDX-License-Identifier: Apache-2.0\n\npackage main\n",
+		"bad-sha":  "package util\n",
+	}
+	treeAndBlobs := []mock.MockBackendOption{
+		mock.WithRequestMatchHandler(
+			mock.GetReposGitTreesByOwnerByRepoByTreeSha,
+			expectPath(t, "/repos/owner/repo/git/trees/main").andThen(
+				mockResponse(t, http.StatusOK, &github.Tree{Entries: []*github.TreeEntry{
+					{Path: github.Ptr("main.go"), Type: github.Ptr("blob"), Mode: github.Ptr("100644"), SHA: github.Ptr("good-sha")},
+					{Path: github.Ptr("util/util.go"), Type: github.Ptr("blob"), Mode: github.Ptr("100644"), SHA: github.Ptr("bad-sha")},
+					{Path: github.Ptr("README.md"), Type: github.Ptr("blob"), Mode: github.Ptr("100644"), SHA: github.Ptr("readme-sha")},
+					{Path: github.Ptr("util"), Type: github.Ptr("tree"), Mode: github.Ptr("040000"), SHA: github.Ptr("util-sha")},
+				}}),
+			),
+		),
+		mock.WithRequestMatchHandler(
+			mock.GetReposGitBlobsByOwnerByRepoByFileSha,
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				sha := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+				content, ok := blobs[sha]
+				if !assert.True(t, ok, "unexpected blob %s", sha) {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				_, _ = w.Write([]byte(content))
+			}),
+		),
+	}
+
+	tests := []struct {
+		name            string
+		mockedClient    *http.Client
+		args            map[string]interface{}
+		expectError     bool
+		expectedErrMsg  string
+		expectedChecked int
+		expectedPaths   []string
+		checkResult     func(t *testing.T, result CheckLicenseHeadersResult)
+	}{
+		{
+			name:            "reports files missing the header at a ref",
+			mockedClient:    mock.NewMockedHTTPClient(treeAndBlobs...),
+			args:            map[string]interface{}{"ref": "main"},
+			expectedChecked: 2,
+			expectedPaths:   []string{"util/util.go"},
+		},
+		{
+			name: "commits the fix to the branch",
+			mockedClient: mock.NewMockedHTTPClient(append(treeAndBlobs,
+				mock.WithRequestMatchHandler(
+					mock.GetReposGitRefByOwnerByRepoByRef,
+					mockResponse(t, http.StatusOK, &github.Reference{
+						Ref:    github.Ptr("refs/heads/main"),
+						Object: &github.GitObject{SHA: github.Ptr("base-sha")},
+					}),
+				),
+				mock.WithRequestMatchHandler(
+					mock.GetReposGitCommitsByOwnerByRepoByCommitSha,
+					mockResponse(t, http.StatusOK, &github.Commit{SHA: github.Ptr("base-sha"), Tree: &github.Tree{SHA: github.Ptr("base-tree-sha")}}),
+				),
+				mock.WithRequestMatchHandler(
+					mock.PostReposGitTreesByOwnerByRepo,
+					http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						var body struct {
+							Tree []map[string]any `json:"tree"`
+						}
+						assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+						if assert.Len(t, body.Tree, 1) {
+							assert.Equal(t, "util/util.go", body.Tree[0]["path"])
+							assert.True(t, strings.HasSuffix(body.Tree[0]["content"].(string), "// SPDX-License-Identifier: Apache-2.0\n\npackage util\n"))
+						}
+						mockResponse(t, http.StatusCreated, &github.Tree{SHA: github.Ptr("new-tree-sha")})(w, r)
+					}),
+				),
+				mock.WithRequestMatchHandler(
+					mock.PostReposGitCommitsByOwnerByRepo,
+					expectRequestBody(t, map[string]any{
+						"message": "Add missing license headers",
+						"tree":    "new-tree-sha",
+						"parents": []any{"base-sha"},
+					}).andThen(
+						mockResponse(t, http.StatusCreated, &github.Commit{SHA: github.Ptr("fix-sha")}),
+					),
+				),
+				mock.WithRequestMatchHandler(
+					mock.PatchReposGitRefsByOwnerByRepoByRef,
+					mockResponse(t, http.StatusOK, &github.Reference{}),
+				),
+			)...),
+			args:            map[string]interface{}{"ref": "main", "fix": true},
+			expectedChecked: 2,
+			expectedPaths:   []string{"util/util.go"},
+			checkResult: func(t *testing.T, result CheckLicenseHeadersResult) {
+				assert.Equal(t, []string{"fix-sha"}, result.FixCommits)
+			},
+		},
+		{
+			name:         "checks and fixes planned files",
+			mockedClient: mock.NewMockedHTTPClient(),
+			args: map[string]interface{}{
+				"files": []interface{}{
+					map[string]interface{}{"path": "app.py", "content": "print('hi')\n"},
+					map[string]interface{}{"path": "lib.go", "content": "// Copyright 2024 Example Inc.\n// SPDX-License-Identifier: Apache-2.0\npackage lib\n"},
+					map[string]interface{}{"path": "notes.txt", "content": "notes\n"},
+				},
+				"fix": true,
+			},
+			expectedChecked: 2,
+			expectedPaths:   []string{"app.py"},
+			checkResult: func(t *testing.T, result CheckLicenseHeadersResult) {
+				require.Len(t, result.FixedFiles, 1)
+				assert.Equal(t, "app.py", result.FixedFiles[0].Path)
+				assert.True(t, strings.HasPrefix(result.FixedFiles[0].Content, "# Copyright "))
+			},
+		},
+		{
+			name:         "extensions limit the check",
+			mockedClient: mock.NewMockedHTTPClient(),
+			args: map[string]interface{}{
+				"files": []interface{}{
+					map[string]interface{}{"path": "app.py", "content": "print('hi')\n"},
+				},
+				"extensions": []interface{}{".go"},
+			},
+			expectedChecked: 0,
+		},
+		{
+			name:         "files cannot be combined with ref",
+			mockedClient: mock.NewMockedHTTPClient(),
+			args: map[string]interface{}{
+				"ref": "main",
+				"files": []interface{}{
+					map[string]interface{}{"path": "app.py", "content": "print('hi')\n"},
+				},
+			},
+			expectError:    true,
+			expectedErrMsg: "cannot be combined",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, handler := CheckLicenseHeaders(stubGetClientFn(github.NewClient(tc.mockedClient)), translations.NullTranslationHelper)
+
+			args := map[string]interface{}{"owner": "owner", "repo": "repo", "header": testLicenseHeader}
+			for k, v := range tc.args {
+				args[k] = v
+			}
+			request := createMCPRequest(args)
+			result, _, err := handler(context.Background(), &request, args)
+			require.NoError(t, err)
+
+			if tc.expectError {
+				assert.Contains(t, getErrorResult(t, result).Text, tc.expectedErrMsg)
+				return
+			}
+
+			require.False(t, result.IsError, getTextResult(t, result).Text)
+			var checked CheckLicenseHeadersResult
+			require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &checked))
+			assert.Equal(t, tc.expectedChecked, checked.CheckedFiles)
+			var paths []string
+			for _, violation := range checked.Violations {
+				paths = append(paths, violation.Path)
+			}
+			assert.Equal(t, tc.expectedPaths, paths)
+			if tc.checkResult != nil {
+				tc.checkResult(t, checked)
+			}
+		})
+	}
+}
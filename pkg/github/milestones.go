@@ -0,0 +1,279 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	ghErrors "github.com/github/github-mcp-server/pkg/errors"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/github/github-mcp-server/pkg/utils"
+	"github.com/google/go-github/v79/github"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// MinimalMilestone is the output type for milestones.
+type MinimalMilestone struct {
+	Number       int    `json:"number"`
+	Title        string `json:"title"`
+	Description  string `json:"description,omitempty"`
+	State        string `json:"state"`
+	DueOn        string `json:"due_on,omitempty"`
+	OpenIssues   int    `json:"open_issues"`
+	ClosedIssues int    `json:"closed_issues"`
+	HTMLURL      string `json:"html_url,omitempty"`
+}
+
+func convertToMinimalMilestone(milestone *github.Milestone) MinimalMilestone {
+	m := MinimalMilestone{
+		Number:       milestone.GetNumber(),
+		Title:        milestone.GetTitle(),
+		Description:  milestone.GetDescription(),
+		State:        milestone.GetState(),
+		OpenIssues:   milestone.GetOpenIssues(),
+		ClosedIssues: milestone.GetClosedIssues(),
+		HTMLURL:      milestone.GetHTMLURL(),
+	}
+	if milestone.DueOn != nil {
+		m.DueOn = milestone.DueOn.Format(time.RFC3339)
+	}
+	return m
+}
+
+// parseMilestoneDueOn parses a due date given as YYYY-MM-DD or as an RFC 3339 timestamp.
+func parseMilestoneDueOn(dueOn string) (*github.Timestamp, error) {
+	if date, err := time.Parse(time.DateOnly, dueOn); err == nil {
+		return &github.Timestamp{Time: date}, nil
+	}
+	timestamp, err := time.Parse(time.RFC3339, dueOn)
+	if err != nil {
+		return nil, fmt.Errorf("due_on must be a date in YYYY-MM-DD format or an RFC 3339 timestamp, got %q", dueOn)
+	}
+	return &github.Timestamp{Time: timestamp}, nil
+}
+
+// ListMilestones creates a tool to list the milestones of a repository.
+func ListMilestones(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "list_milestones",
+		Description: t("TOOL_LIST_MILESTONES_DESCRIPTION", "List the milestones of a GitHub repository with their progress, soonest due first."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_LIST_MILESTONES_USER_TITLE", "List milestones"),
+			ReadOnlyHint: true,
+		},
+		InputSchema: WithPagination(&jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"owner": {
+					Type:        "string",
+					Description: "Repository owner",
+				},
+				"repo": {
+					Type:        "string",
+					Description: "Repository name",
+				},
+				"state": {
+					Type:        "string",
+					Description: "Filter by state. Defaults to open",
+					Enum:        []any{"open", "closed", "all"},
+				},
+			},
+			Required: []string{"owner", "repo"},
+		}),
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		repo, err := RequiredParam[string](args, "repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		state, err := OptionalParam[string](args, "state")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		pagination, err := OptionalPaginationParams(args)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		milestones, resp, err := client.Issues.ListMilestones(ctx, owner, repo, &github.MilestoneListOptions{
+			State:       state,
+			Sort:        "due_on",
+			Direction:   "asc",
+			ListOptions: github.ListOptions{Page: pagination.Page, PerPage: pagination.PerPage},
+		})
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to list milestones", resp, err), nil, nil
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		result := make([]MinimalMilestone, 0, len(milestones))
+		for _, milestone := range milestones {
+			result = append(result, convertToMinimalMilestone(milestone))
+		}
+
+		return MarshalledTextResult(result), nil, nil
+	})
+
+	return tool, handler
+}
+
+// MilestoneWrite handles create, update, and delete operations for GitHub milestones
+func MilestoneWrite(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "milestone_write",
+		Description: t("TOOL_MILESTONE_WRITE_DESCRIPTION", "Perform write operations on repository milestones. To set the milestone of an issue, use the 'update_issue' tool."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_MILESTONE_WRITE_TITLE", "Write operations on repository milestones."),
+			ReadOnlyHint: false,
+		},
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"method": {
+					Type:        "string",
+					Description: "Operation to perform: 'create', 'update', or 'delete'",
+					Enum:        []any{"create", "update", "delete"},
+				},
+				"owner": {
+					Type:        "string",
+					Description: "Repository owner (username or organization name)",
+				},
+				"repo": {
+					Type:        "string",
+					Description: "Repository name",
+				},
+				"milestone_number": {
+					Type:        "number",
+					Description: "Milestone number - required for 'update' and 'delete'",
+				},
+				"title": {
+					Type:        "string",
+					Description: "Milestone title. Required for 'create', optional for 'update'.",
+				},
+				"description": {
+					Type:        "string",
+					Description: "Milestone description. Optional for 'create' and 'update'.",
+				},
+				"state": {
+					Type:        "string",
+					Description: "Milestone state. Optional for 'create' and 'update'; close a milestone by updating its state.",
+					Enum:        []any{"open", "closed"},
+				},
+				"due_on": {
+					Type:        "string",
+					Description: "Due date in YYYY-MM-DD format or as an RFC 3339 timestamp. Optional for 'create' and 'update'.",
+				},
+			},
+			Required: []string{"method", "owner", "repo"},
+		},
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		method, err := RequiredParam[string](args, "method")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		method = strings.ToLower(method)
+
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		repo, err := RequiredParam[string](args, "repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		number, err := OptionalIntParam(args, "milestone_number")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		milestone := &github.Milestone{}
+		for param, field := range map[string]**string{
+			"title":       &milestone.Title,
+			"description": &milestone.Description,
+			"state":       &milestone.State,
+		} {
+			value, err := OptionalParam[string](args, param)
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			if value != "" {
+				*field = github.Ptr(value)
+			}
+		}
+		dueOn, err := OptionalParam[string](args, "due_on")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		if dueOn != "" {
+			milestone.DueOn, err = parseMilestoneDueOn(dueOn)
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+		}
+
+		if (method == "update" || method == "delete") && number == 0 {
+			return utils.NewToolResultError(fmt.Sprintf("milestone_number is required for %s", method)), nil, nil
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		switch method {
+		case "create":
+			if milestone.Title == nil {
+				return utils.NewToolResultError("title is required for create"), nil, nil
+			}
+
+			created, resp, err := client.Issues.CreateMilestone(ctx, owner, repo, milestone)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to create milestone", resp, err), nil, nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			return MarshalledTextResult(convertToMinimalMilestone(created)), nil, nil
+
+		case "update":
+			if milestone.Title == nil && milestone.Description == nil && milestone.State == nil && milestone.DueOn == nil {
+				return utils.NewToolResultError("at least one of title, description, state, or due_on must be provided for update"), nil, nil
+			}
+
+			updated, resp, err := client.Issues.EditMilestone(ctx, owner, repo, number, milestone)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to update milestone", resp, err), nil, nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			return MarshalledTextResult(convertToMinimalMilestone(updated)), nil, nil
+
+		case "delete":
+			resp, err := client.Issues.DeleteMilestone(ctx, owner, repo, number)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to delete milestone", resp, err), nil, nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			return utils.NewToolResultText(fmt.Sprintf("milestone %d deleted successfully", number)), nil, nil
+
+		default:
+			return utils.NewToolResultError(fmt.Sprintf("unknown method: %s. Supported methods are: create, update, delete", method)), nil, nil
+		}
+	})
+
+	return tool, handler
+}
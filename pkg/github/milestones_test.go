@@ -0,0 +1,151 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v79/github"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ListMilestones(t *testing.T) {
+	tool, _ := ListMilestones(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.True(t, tool.Annotations.ReadOnlyHint)
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.GetReposMilestonesByOwnerByRepo,
+			expectQueryParams(t, map[string]string{"state": "all", "sort": "due_on", "direction": "asc", "page": "1", "per_page": "30"}).andThen(
+				mockResponse(t, http.StatusOK, []*github.Milestone{{
+					Number:       github.Ptr(3),
+					Title:        github.Ptr("v1.0"),
+					State:        github.Ptr("open"),
+					DueOn:        &github.Timestamp{Time: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)},
+					OpenIssues:   github.Ptr(4),
+					ClosedIssues: github.Ptr(6),
+				}}),
+			),
+		),
+	)
+	_, handler := ListMilestones(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+	args := map[string]any{"owner": "owner", "repo": "repo", "state": "all"}
+	request := createMCPRequest(args)
+	result, _, err := handler(context.Background(), &request, args)
+	require.NoError(t, err)
+	require.False(t, result.IsError, getTextResult(t, result).Text)
+
+	var milestones []MinimalMilestone
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &milestones))
+	assert.Equal(t, []MinimalMilestone{{Number: 3, Title: "v1.0", State: "open", DueOn: "2026-11-01T00:00:00Z", OpenIssues: 4, ClosedIssues: 6}}, milestones)
+}
+
+func Test_MilestoneWrite(t *testing.T) {
+	tool, _ := MilestoneWrite(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	tests := []struct {
+		name         string
+		args         map[string]any
+		mockedClient *http.Client
+		expectError  string
+		expectText   string
+		expectNumber int
+	}{
+		{
+			name: "create",
+			args: map[string]any{"method": "create", "title": "v1.0", "due_on": "2026-11-01"},
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.PostReposMilestonesByOwnerByRepo,
+					expectRequestBody(t, map[string]any{"title": "v1.0", "due_on": "2026-11-01T00:00:00Z"}).andThen(
+						mockResponse(t, http.StatusCreated, &github.Milestone{Number: github.Ptr(3), Title: github.Ptr("v1.0")}),
+					),
+				),
+			),
+			expectNumber: 3,
+		},
+		{
+			name: "close",
+			args: map[string]any{"method": "update", "milestone_number": float64(3), "state": "closed"},
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.PatchReposMilestonesByOwnerByRepoByMilestoneNumber,
+					expectPath(t, "/repos/owner/repo/milestones/3").andThen(
+						expectRequestBody(t, map[string]any{"state": "closed"}).andThen(
+							mockResponse(t, http.StatusOK, &github.Milestone{Number: github.Ptr(3), State: github.Ptr("closed")}),
+						),
+					),
+				),
+			),
+			expectNumber: 3,
+		},
+		{
+			name: "delete",
+			args: map[string]any{"method": "delete", "milestone_number": float64(3)},
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.DeleteReposMilestonesByOwnerByRepoByMilestoneNumber,
+					http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusNoContent) }),
+				),
+			),
+			expectText: "milestone 3 deleted successfully",
+		},
+		{
+			name:        "create without title",
+			args:        map[string]any{"method": "create"},
+			expectError: "title is required for create",
+		},
+		{
+			name:        "update without number",
+			args:        map[string]any{"method": "update", "state": "closed"},
+			expectError: "milestone_number is required for update",
+		},
+		{
+			name:        "update without changes",
+			args:        map[string]any{"method": "update", "milestone_number": float64(3)},
+			expectError: "at least one of title, description, state, or due_on must be provided for update",
+		},
+		{
+			name:        "invalid due date",
+			args:        map[string]any{"method": "create", "title": "v1.0", "due_on": "next week"},
+			expectError: "due_on must be a date in YYYY-MM-DD format",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, handler := MilestoneWrite(stubGetClientFn(github.NewClient(tc.mockedClient)), translations.NullTranslationHelper)
+			args := map[string]any{"owner": "owner", "repo": "repo"}
+			for k, v := range tc.args {
+				args[k] = v
+			}
+			request := createMCPRequest(args)
+			result, _, err := handler(context.Background(), &request, args)
+			require.NoError(t, err)
+
+			if tc.expectError != "" {
+				require.True(t, result.IsError)
+				assert.Contains(t, getErrorResult(t, result).Text, tc.expectError)
+				return
+			}
+			require.False(t, result.IsError, getTextResult(t, result).Text)
+
+			if tc.expectText != "" {
+				assert.Equal(t, tc.expectText, getTextResult(t, result).Text)
+				return
+			}
+			var milestone MinimalMilestone
+			require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &milestone))
+			assert.Equal(t, tc.expectNumber, milestone.Number)
+		})
+	}
+}
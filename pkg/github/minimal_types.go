@@ -47,6 +47,24 @@ type MinimalSearchRepositoriesResult struct {
 	Items             []MinimalRepository `json:"items"`
 }
 
+// MinimalSearchCodeResult is the trimmed output type for code search results.
+type MinimalSearchCodeResult struct {
+	TotalCount        int                 `json:"total_count"`
+	IncompleteResults bool                `json:"incomplete_results"`
+	Items             []MinimalCodeResult `json:"items"`
+}
+
+// MinimalCodeResult is a file found by code search, with the matching parts of its content.
+type MinimalCodeResult struct {
+	// Rank is the position of the result in GitHub's best match order, starting at 1
+	Rank       int      `json:"rank"`
+	Repository string   `json:"repository"`
+	Path       string   `json:"path"`
+	SHA        string   `json:"sha,omitempty"`
+	HTMLURL    string   `json:"html_url,omitempty"`
+	Snippets   []string `json:"snippets,omitempty"`
+}
+
 // MinimalCommitAuthor represents commit author information.
 type MinimalCommitAuthor struct {
 	Name  string `json:"name,omitempty"`
@@ -158,6 +176,33 @@ func convertToMinimalProject(fullProject *github.ProjectV2) *MinimalProject {
 	}
 }
 
+// convertToMinimalRepository converts a GitHub API Repository to MinimalRepository
+func convertToMinimalRepository(repo *github.Repository) MinimalRepository {
+	minimalRepo := MinimalRepository{
+		ID:            repo.GetID(),
+		Name:          repo.GetName(),
+		FullName:      repo.GetFullName(),
+		Description:   repo.GetDescription(),
+		HTMLURL:       repo.GetHTMLURL(),
+		Language:      repo.GetLanguage(),
+		Stars:         repo.GetStargazersCount(),
+		Forks:         repo.GetForksCount(),
+		OpenIssues:    repo.GetOpenIssuesCount(),
+		Topics:        repo.Topics,
+		Private:       repo.GetPrivate(),
+		Fork:          repo.GetFork(),
+		Archived:      repo.GetArchived(),
+		DefaultBranch: repo.GetDefaultBranch(),
+	}
+	if repo.UpdatedAt != nil {
+		minimalRepo.UpdatedAt = repo.UpdatedAt.Format("2006-01-02T15:04:05Z")
+	}
+	if repo.CreatedAt != nil {
+		minimalRepo.CreatedAt = repo.CreatedAt.Format("2006-01-02T15:04:05Z")
+	}
+	return minimalRepo
+}
+
 func convertToMinimalUser(user *github.User) *MinimalUser {
 	if user == nil {
 		return nil
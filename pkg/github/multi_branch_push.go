@@ -0,0 +1,331 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/github/github-mcp-server/pkg/ratelimit"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/github/github-mcp-server/pkg/utils"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// MaxBranchesPerPush bounds the branches updated by one push_files_to_branches call
+const MaxBranchesPerPush = 20
+
+// BranchPushResult reports the commit made on one branch by push_files_to_branches.
+type BranchPushResult struct {
+	Branch    string `json:"branch"`
+	CommitSHA string `json:"commit_sha,omitempty"`
+	Files     int    `json:"files"`
+	Success   bool   `json:"success"`
+	Error     string `json:"error,omitempty"`
+	// ErrorCode is the code of a failure the client can act on, such as EMPTY_REPOSITORY
+	ErrorCode string `json:"error_code,omitempty"`
+	// NoChanges is set when the files were already on the branch, so no commit was made
+	NoChanges bool `json:"no_changes,omitempty"`
+	// Retries is the number of API calls repeated after transient failures
+	Retries int `json:"retries"`
+}
+
+// PushFilesToBranchesResult reports the outcome of push_files_to_branches on every branch.
+type PushFilesToBranchesResult struct {
+	Branches           []BranchPushResult `json:"branches"`
+	SuccessfulBranches int                `json:"successful_branches"`
+	FailedBranches     int                `json:"failed_branches"`
+	FullySuccessful    bool               `json:"fully_successful"`
+	// RetryBudget reports the retries consumed across the pushes to all branches
+	RetryBudget *ratelimit.BudgetUsage `json:"retry_budget,omitempty"`
+	// Warnings are the advisory findings of validating the files and overrides
+	Warnings []ValidationWarning `json:"warnings,omitempty"`
+	// Plan is the quota forecast of pushes that have to wait for the core rate limit to reset
+	Plan *ratelimit.FanOutPlan `json:"plan,omitempty"`
+	// AwaitingApproval is set when nothing was pushed because Plan needs approve_plan
+	AwaitingApproval bool   `json:"awaiting_approval,omitempty"`
+	Message          string `json:"message,omitempty"`
+}
+
+// estimatedPushCalls is the number of API calls pushChunk makes for files, without retries:
+// reading the ref and its commit, creating a blob per binary file, then creating the tree and
+// the commit and moving the ref.
+func estimatedPushCalls(files []FileEntry) int {
+	calls := 5
+	for _, file := range files {
+		if file.Binary {
+			calls++
+		}
+	}
+	return calls
+}
+
+// validatedFiles resolves the upload IDs of file objects and validates them like push_files_chunked,
+// returning the files along with the validation warnings.
+func validatedFiles(ctx context.Context, limits PushLimits, filesObj []interface{}, opts FileValidationOptions) ([]FileEntry, []ValidationWarning, error) {
+	resolved, err := resolveUploadedFiles(ctx, filesObj)
+	if err != nil {
+		return nil, nil, err
+	}
+	validationResult, files, err := ValidateFilesWithOptions(resolved, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := ValidateFileSize(limits, validationResult.LargestFile, validationResult.LargestFileSize); err != nil {
+		return nil, nil, err
+	}
+	return files, validationResult.Warnings, nil
+}
+
+// mergeFiles returns files with overrides applied: a file of overrides replaces the file with the
+// same path, and the others are added.
+func mergeFiles(files, overrides []FileEntry) []FileEntry {
+	merged := make([]FileEntry, 0, len(files)+len(overrides))
+	index := make(map[string]int, len(files))
+	for _, file := range files {
+		index[file.Path] = len(merged)
+		merged = append(merged, file)
+	}
+	for _, file := range overrides {
+		if i, ok := index[file.Path]; ok {
+			merged[i] = file
+			continue
+		}
+		index[file.Path] = len(merged)
+		merged = append(merged, file)
+	}
+	return merged
+}
+
+// PushFilesToBranches creates a tool to commit the same files to several branches of a repository.
+func PushFilesToBranches(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "push_files_to_branches",
+		Description: t("TOOL_PUSH_FILES_TO_BRANCHES_DESCRIPTION", "Commit the same set of files to several branches of a repository, with one commit per branch, e.g. to backport a configuration change to release branches. Branches can add or replace files through overrides. A failure on one branch does not stop the others; the result reports each branch. When the pushes need more of the rate limit quota than is left before it resets, nothing is pushed and a plan of waves is returned for approval."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_PUSH_FILES_TO_BRANCHES_USER_TITLE", "Push files to several branches"),
+			ReadOnlyHint: false,
+		},
+		InputSchema: withCommitIdentity(&jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"owner": {
+					Type:        "string",
+					Description: "Repository owner",
+				},
+				"repo": {
+					Type:        "string",
+					Description: "Repository name",
+				},
+				"branches": {
+					Type:        "array",
+					Description: fmt.Sprintf("Branches to push to (max: %d)", MaxBranchesPerPush),
+					Items: &jsonschema.Schema{
+						Type: "string",
+					},
+				},
+				"files": {
+					Type:        "array",
+					Description: "Files to push to every branch, each object with path (string) and either content (string) or upload_id (string)",
+					Items:       fileObjectSchema(),
+				},
+				"overrides": {
+					Type:        "object",
+					Description: "Files for specific branches, keyed by branch name. A file replaces the file with the same path in files, or is added to them",
+					AdditionalProperties: &jsonschema.Schema{
+						Type:  "array",
+						Items: fileObjectSchema(),
+					},
+				},
+				"message": {
+					Type:        "string",
+					Description: "Commit message, used on every branch",
+				},
+				"max_retries":            maxRetriesSchema(),
+				"allow_empty":            allowEmptySchema(),
+				"allow_secrets":          allowSecretsSchema(),
+				"normalize_line_endings": normalizeLineEndingsSchema(),
+				"approve_plan":           approvePlanSchema("branches"),
+			},
+			Required: []string{"owner", "repo", "branches", "files", "message"},
+		}),
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		repo, err := RequiredParam[string](args, "repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		message, err := RequiredParam[string](args, "message")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		branches, err := OptionalStringArrayParam(args, "branches")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		if len(branches) == 0 {
+			return utils.NewToolResultError("branches must name at least one branch"), nil, nil
+		}
+		if len(branches) > MaxBranchesPerPush {
+			return utils.NewToolResultError(fmt.Sprintf("too many branches: %d exceeds maximum of %d per call", len(branches), MaxBranchesPerPush)), nil, nil
+		}
+		seen := make(map[string]bool, len(branches))
+		for _, branch := range branches {
+			if strings.TrimSpace(branch) == "" {
+				return utils.NewToolResultError("branches must not contain empty names"), nil, nil
+			}
+			if seen[branch] {
+				return utils.NewToolResultError(fmt.Sprintf("branch %s is listed more than once", branch)), nil, nil
+			}
+			seen[branch] = true
+		}
+		identity, err := commitIdentityFromArgs(ctx, args)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		retry, err := chunkRetryConfigFromArgs(args)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		allowEmpty, err := OptionalParam[bool](args, "allow_empty")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		validationOpts, err := fileValidationOptionsFromArgs(args)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		approvePlan, err := OptionalParam[bool](args, "approve_plan")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		filesObj, ok := args["files"].([]interface{})
+		if !ok {
+			return utils.NewToolResultError("files parameter must be an array of objects with path and content"), nil, nil
+		}
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		limits := pushLimitsFor(ctx, client)
+		files, warnings, err := validatedFiles(ctx, limits, filesObj, validationOpts)
+		if err != nil {
+			return toolErrorResult(err), nil, nil
+		}
+
+		branchFiles := make(map[string][]FileEntry, len(branches))
+		for _, branch := range branches {
+			branchFiles[branch] = files
+		}
+		if raw, ok := args["overrides"]; ok && raw != nil {
+			overrides, ok := raw.(map[string]interface{})
+			if !ok {
+				return utils.NewToolResultError("overrides must be an object mapping branch names to arrays of files"), nil, nil
+			}
+			for branch, overrideObj := range overrides {
+				if !seen[branch] {
+					return utils.NewToolResultError(fmt.Sprintf("overrides names branch %s, which is not in branches", branch)), nil, nil
+				}
+				overrideFiles, ok := overrideObj.([]interface{})
+				if !ok {
+					return utils.NewToolResultError(fmt.Sprintf("overrides for branch %s must be an array of files", branch)), nil, nil
+				}
+				validated, overrideWarnings, err := validatedFiles(ctx, limits, overrideFiles, validationOpts)
+				if err != nil {
+					return prefixedToolErrorResult(fmt.Sprintf("overrides for branch %s", branch), err), nil, nil
+				}
+				warnings = append(warnings, overrideWarnings...)
+				branchFiles[branch] = mergeFiles(files, validated)
+			}
+		}
+
+		maxFiles := limits.MaxFilesPerPush
+		for _, branch := range branches {
+			if len(branchFiles[branch]) == 0 {
+				return utils.NewToolResultError(fmt.Sprintf("no files to push to branch %s", branch)), nil, nil
+			}
+			if len(branchFiles[branch]) > maxFiles {
+				return utils.NewToolResultError(fmt.Sprintf(
+					"too many files for branch %s: %d exceeds maximum of %d per commit. Use push_files_chunked for larger batches",
+					branch, len(branchFiles[branch]), maxFiles,
+				)), nil, nil
+			}
+			// Overrides were validated on their own, so the merged files may exceed the total
+			var totalSize int64
+			for _, file := range branchFiles[branch] {
+				if !file.LFS {
+					totalSize += int64(len(file.Content))
+				}
+			}
+			if _, err := ValidateTotalSize(limits, totalSize); err != nil {
+				return prefixedToolErrorResult(fmt.Sprintf("files for branch %s", branch), err), nil, nil
+			}
+		}
+
+		result := PushFilesToBranchesResult{Branches: make([]BranchPushResult, 0, len(branches)), Warnings: warnings}
+
+		// Pushes that would run out of quota halfway wait for it to reset in waves, once approved
+		waves := []ratelimit.Wave{{Index: 1, Targets: branches}}
+		targets := make([]ratelimit.FanOutTarget, 0, len(branches))
+		for _, branch := range branches {
+			targets = append(targets, ratelimit.FanOutTarget{Name: branch, Calls: estimatedPushCalls(branchFiles[branch])})
+		}
+		if plan := planFanOut(ctx, client, targets); plan != nil && plan.RequiresApproval {
+			result.Plan = plan
+			if !approvePlan {
+				result.AwaitingApproval = true
+				result.Message = planApprovalMessage(plan, "nothing was pushed") + ". Use a new idempotency_key, if one was given"
+				return MarshalledTextResult(result), nil, nil
+			}
+			waves = plan.Waves
+		}
+
+		// The pushes to all the branches share one retry budget
+		ctx, budget := withRetryBudget(ctx)
+		for _, wave := range waves {
+			waitErr := ratelimit.WaitForWave(ctx, wave)
+			for _, branch := range wave.Targets {
+				branchResult := BranchPushResult{Branch: branch, Files: len(branchFiles[branch])}
+				var commit chunkCommit
+				err := waitErr
+				if err != nil {
+					err = fmt.Errorf("stopped waiting for wave %d of the plan: %w", wave.Index, err)
+				} else {
+					commit, err = pushChunk(ctx, client, owner, repo, "refs/heads/"+branch, branchFiles[branch], message, identity, retry, allowEmpty)
+				}
+				branchResult.Retries = commit.Retries
+				if err != nil {
+					branchResult.Error = err.Error()
+					var validationErr *ValidationError
+					if errors.As(err, &validationErr) {
+						branchResult.ErrorCode = validationErr.Code
+					}
+					result.FailedBranches++
+				} else {
+					branchResult.Success = true
+					branchResult.NoChanges = commit.NoChanges
+					if !commit.NoChanges {
+						branchResult.CommitSHA = commit.SHA
+					}
+					result.SuccessfulBranches++
+				}
+				result.Branches = append(result.Branches, branchResult)
+			}
+		}
+		result.FullySuccessful = result.FailedBranches == 0
+		usage := budget.Usage()
+		result.RetryBudget = &usage
+
+		return MarshalledTextResult(result), nil, nil
+	})
+
+	return withIdempotencyKey(tool, handler)
+}
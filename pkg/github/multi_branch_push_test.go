@@ -0,0 +1,310 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v79/github"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_PushFilesToBranches(t *testing.T) {
+	mockClient := github.NewClient(nil)
+	tool, _ := PushFilesToBranches(stubGetClientFn(mockClient), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	schema, ok := tool.InputSchema.(*jsonschema.Schema)
+	require.True(t, ok, "InputSchema should be *jsonschema.Schema")
+
+	assert.Equal(t, "push_files_to_branches", tool.Name)
+	assert.Contains(t, schema.Properties, "overrides")
+	assert.Contains(t, schema.Properties, "idempotency_key")
+	assert.ElementsMatch(t, schema.Required, []string{"owner", "repo", "branches", "files", "message"})
+
+	files := []interface{}{
+		map[string]interface{}{"path": ".github/dependabot.yml", "content": "version: 2\n"},
+		map[string]interface{}{"path": "SECURITY.md", "content": "Report issues privately\n"},
+	}
+
+	tests := []struct {
+		name             string
+		args             map[string]interface{}
+		limits           *PushLimits
+		expectedErrMsg   string
+		expectedTrees    map[string][]string
+		expectedFailures []string
+	}{
+		{
+			name: "same files on every branch",
+			args: map[string]interface{}{
+				"branches": []interface{}{"release-1.0", "release-2.0"},
+				"files":    files,
+			},
+			expectedTrees: map[string][]string{
+				"release-1.0": {".github/dependabot.yml=version: 2\n", "SECURITY.md=Report issues privately\n"},
+				"release-2.0": {".github/dependabot.yml=version: 2\n", "SECURITY.md=Report issues privately\n"},
+			},
+		},
+		{
+			name: "overrides replace and add files",
+			args: map[string]interface{}{
+				"branches": []interface{}{"release-1.0", "release-2.0"},
+				"files":    files,
+				"overrides": map[string]interface{}{
+					"release-1.0": []interface{}{
+						map[string]interface{}{"path": ".github/dependabot.yml", "content": "version: 1\n"},
+						map[string]interface{}{"path": "LEGACY.md", "content": "Unsupported\n"},
+					},
+				},
+			},
+			expectedTrees: map[string][]string{
+				"release-1.0": {".github/dependabot.yml=version: 1\n", "SECURITY.md=Report issues privately\n", "LEGACY.md=Unsupported\n"},
+				"release-2.0": {".github/dependabot.yml=version: 2\n", "SECURITY.md=Report issues privately\n"},
+			},
+		},
+		{
+			name: "a failed branch does not stop the others",
+			args: map[string]interface{}{
+				"branches": []interface{}{"missing", "release-2.0"},
+				"files":    files,
+			},
+			expectedTrees: map[string][]string{
+				"release-2.0": {".github/dependabot.yml=version: 2\n", "SECURITY.md=Report issues privately\n"},
+			},
+			expectedFailures: []string{"missing"},
+		},
+		{
+			name: "override for an unknown branch",
+			args: map[string]interface{}{
+				"branches":  []interface{}{"release-1.0"},
+				"files":     files,
+				"overrides": map[string]interface{}{"main": files},
+			},
+			expectedErrMsg: "overrides names branch main, which is not in branches",
+		},
+		{
+			name: "duplicate branch",
+			args: map[string]interface{}{
+				"branches": []interface{}{"release-1.0", "release-1.0"},
+				"files":    files,
+			},
+			expectedErrMsg: "branch release-1.0 is listed more than once",
+		},
+		{
+			name: "overrides push a branch past the total size",
+			args: map[string]interface{}{
+				"branches": []interface{}{"release-1.0", "release-2.0"},
+				"files": []interface{}{
+					map[string]interface{}{"path": "data/a.txt", "content": strings.Repeat("a", 600*1024)},
+				},
+				"overrides": map[string]interface{}{
+					"release-1.0": []interface{}{
+						map[string]interface{}{"path": "data/b.txt", "content": strings.Repeat("b", 600*1024)},
+					},
+				},
+			},
+			limits:         &PushLimits{MaxTotalPushSizeBytes: 1024 * 1024},
+			expectedErrMsg: "files for branch release-1.0: total content size",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.limits != nil {
+				SetPushLimits(*tc.limits)
+				t.Cleanup(func() { SetPushLimits(PushLimits{}) })
+			}
+			// Each branch has its own head and tree, so the tree request tells which branch is pushed
+			trees := map[string][]string{}
+			mockedClient := mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.GetReposGitRefByOwnerByRepoByRef,
+					http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						branch := strings.TrimPrefix(r.URL.Path, "/repos/owner/repo/git/ref/heads/")
+						if branch == "missing" {
+							w.WriteHeader(http.StatusNotFound)
+							_, _ = w.Write([]byte(`{"message": "Not Found"}`))
+							return
+						}
+						mockResponse(t, http.StatusOK, &github.Reference{
+							Ref:    github.Ptr("refs/heads/" + branch),
+							Object: &github.GitObject{SHA: github.Ptr(branch)},
+						})(w, r)
+					}),
+				),
+				mock.WithRequestMatchHandler(
+					mock.GetReposGitCommitsByOwnerByRepoByCommitSha,
+					http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						branch := strings.TrimPrefix(r.URL.Path, "/repos/owner/repo/git/commits/")
+						mockResponse(t, http.StatusOK, &github.Commit{
+							SHA:  github.Ptr(branch),
+							Tree: &github.Tree{SHA: github.Ptr(branch)},
+						})(w, r)
+					}),
+				),
+				mock.WithRequestMatchHandler(
+					mock.PostReposGitTreesByOwnerByRepo,
+					http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						var body struct {
+							BaseTree string `json:"base_tree"`
+							Tree     []struct {
+								Path    string `json:"path"`
+								Content string `json:"content"`
+							} `json:"tree"`
+						}
+						require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+						for _, entry := range body.Tree {
+							trees[body.BaseTree] = append(trees[body.BaseTree], entry.Path+"="+entry.Content)
+						}
+						mockResponse(t, http.StatusCreated, &github.Tree{SHA: github.Ptr("tree-" + body.BaseTree)})(w, r)
+					}),
+				),
+				mock.WithRequestMatchHandler(
+					mock.PostReposGitCommitsByOwnerByRepo,
+					mockResponse(t, http.StatusCreated, &github.Commit{SHA: github.Ptr("new-commit-sha")}),
+				),
+				mock.WithRequestMatchHandler(
+					mock.PatchReposGitRefsByOwnerByRepoByRef,
+					mockResponse(t, http.StatusOK, &github.Reference{Ref: github.Ptr("refs/heads/main")}),
+				),
+			)
+			_, handler := PushFilesToBranches(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+			args := map[string]interface{}{"owner": "owner", "repo": "repo", "message": "Update security config"}
+			for k, v := range tc.args {
+				args[k] = v
+			}
+			request := createMCPRequest(args)
+			result, _, err := handler(context.Background(), &request, args)
+			require.NoError(t, err)
+
+			if tc.expectedErrMsg != "" {
+				assert.Contains(t, getErrorResult(t, result).Text, tc.expectedErrMsg)
+				return
+			}
+
+			require.False(t, result.IsError, getTextResult(t, result).Text)
+			var pushResult PushFilesToBranchesResult
+			require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &pushResult))
+			assert.Equal(t, tc.expectedTrees, trees)
+			assert.Equal(t, len(tc.expectedFailures) == 0, pushResult.FullySuccessful)
+			assert.Equal(t, len(tc.expectedFailures), pushResult.FailedBranches)
+			for _, branch := range pushResult.Branches {
+				if branch.Success {
+					assert.Equal(t, "new-commit-sha", branch.CommitSHA)
+					continue
+				}
+				assert.Contains(t, tc.expectedFailures, branch.Branch)
+				assert.Contains(t, branch.Error, "failed to get branch reference")
+			}
+		})
+	}
+}
+
+func Test_PushFilesToBranches_QuotaPlan(t *testing.T) {
+	args := func(extra map[string]interface{}) map[string]interface{} {
+		args := map[string]interface{}{
+			"owner":    "owner",
+			"repo":     "repo",
+			"message":  "Update security config",
+			"branches": []interface{}{"release-1.0", "release-2.0"},
+			"files":    []interface{}{map[string]interface{}{"path": "SECURITY.md", "content": "Report issues privately\n"}},
+		}
+		for k, v := range extra {
+			args[k] = v
+		}
+		return args
+	}
+
+	tests := []struct {
+		name             string
+		remaining        int
+		resetIn          time.Duration
+		timeout          time.Duration
+		args             map[string]interface{}
+		expectPlan       bool
+		expectAwaiting   bool
+		expectSuccessful int
+		expectError      string
+	}{
+		{
+			name:             "pushes within the quota without a plan",
+			remaining:        5000,
+			resetIn:          time.Hour,
+			args:             args(nil),
+			expectSuccessful: 2,
+		},
+		{
+			name:           "returns the plan when the quota runs out",
+			remaining:      12,
+			resetIn:        time.Hour,
+			args:           args(nil),
+			expectPlan:     true,
+			expectAwaiting: true,
+		},
+		{
+			name:             "pushes in waves once approved",
+			remaining:        12,
+			resetIn:          -time.Minute,
+			args:             args(map[string]interface{}{"approve_plan": true}),
+			expectPlan:       true,
+			expectSuccessful: 2,
+		},
+		{
+			name:        "stops waiting for a wave when the call times out",
+			remaining:   12,
+			resetIn:     time.Hour,
+			timeout:     50 * time.Millisecond,
+			args:        args(map[string]interface{}{"approve_plan": true}),
+			expectPlan:  true,
+			expectError: "stopped waiting for wave 1 of the plan",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			options := append(mockGitDataAPI(t),
+				mock.WithRequestMatch(mock.GetRateLimit, map[string]any{
+					"resources": map[string]any{
+						"core": &github.Rate{Limit: 100, Remaining: tc.remaining, Reset: github.Timestamp{Time: time.Now().Add(tc.resetIn)}},
+					},
+				}),
+			)
+			_, handler := PushFilesToBranches(stubGetClientFn(github.NewClient(mock.NewMockedHTTPClient(options...))), translations.NullTranslationHelper)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			if tc.timeout > 0 {
+				ctx, cancel = context.WithTimeout(context.Background(), tc.timeout)
+			}
+			defer cancel()
+			request := createMCPRequest(tc.args)
+			result, _, err := handler(ctx, &request, tc.args)
+			require.NoError(t, err)
+			require.False(t, result.IsError, getTextResult(t, result).Text)
+
+			var pushResult PushFilesToBranchesResult
+			require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &pushResult))
+			assert.Equal(t, tc.expectPlan, pushResult.Plan != nil)
+			assert.Equal(t, tc.expectAwaiting, pushResult.AwaitingApproval)
+			assert.Equal(t, tc.expectSuccessful, pushResult.SuccessfulBranches)
+			if tc.expectAwaiting {
+				assert.Empty(t, pushResult.Branches)
+				assert.Contains(t, pushResult.Message, "Call again with approve_plan")
+				assert.Equal(t, 10, pushResult.Plan.EstimatedCalls)
+			}
+			if tc.expectError != "" {
+				require.Len(t, pushResult.Branches, 2)
+				assert.Contains(t, pushResult.Branches[0].Error, tc.expectError)
+			}
+		})
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"slices"
+	"sort"
 	"strconv"
 	"time"
 
@@ -23,11 +25,96 @@ const (
 	FilterOnlyParticipating = "only_participating"
 )
 
+// maxDigestNotifications caps how many notifications the digest output of
+// list_notifications reads, since it pages through the whole inbox.
+const maxDigestNotifications = 1000
+
+// notificationReasons are the reasons GitHub gives for sending a notification.
+var notificationReasons = []any{
+	"approval_requested", "assign", "author", "ci_activity", "comment", "invitation", "manual",
+	"member_feature_requested", "mention", "review_requested", "security_advisory_credit",
+	"security_alert", "state_change", "subscribed", "team_mention",
+}
+
+// NotificationDigest summarizes notifications per repository instead of listing them.
+type NotificationDigest struct {
+	Total        int                       `json:"total"`
+	Unread       int                       `json:"unread"`
+	Truncated    bool                      `json:"truncated,omitempty"`
+	ByReason     map[string]int            `json:"by_reason"`
+	Repositories []RepositoryNotifications `json:"repositories"`
+}
+
+// RepositoryNotifications counts the notifications of one repository in a NotificationDigest.
+type RepositoryNotifications struct {
+	Repository    string         `json:"repository"`
+	Total         int            `json:"total"`
+	Unread        int            `json:"unread"`
+	ByReason      map[string]int `json:"by_reason"`
+	BySubjectType map[string]int `json:"by_subject_type"`
+	LatestUpdate  time.Time      `json:"latest_update"`
+}
+
+// filterNotificationsByReason keeps the notifications sent for one of reasons. No reasons keeps all.
+func filterNotificationsByReason(notifications []*github.Notification, reasons []string) []*github.Notification {
+	if len(reasons) == 0 {
+		return notifications
+	}
+	filtered := make([]*github.Notification, 0, len(notifications))
+	for _, n := range notifications {
+		if slices.Contains(reasons, n.GetReason()) {
+			filtered = append(filtered, n)
+		}
+	}
+	return filtered
+}
+
+// digestNotifications counts notifications per repository, busiest repository first.
+func digestNotifications(notifications []*github.Notification, truncated bool) NotificationDigest {
+	digest := NotificationDigest{
+		Truncated:    truncated,
+		ByReason:     map[string]int{},
+		Repositories: []RepositoryNotifications{},
+	}
+	index := map[string]int{}
+	for _, n := range notifications {
+		name := n.GetRepository().GetFullName()
+		i, ok := index[name]
+		if !ok {
+			i = len(digest.Repositories)
+			index[name] = i
+			digest.Repositories = append(digest.Repositories, RepositoryNotifications{
+				Repository:    name,
+				ByReason:      map[string]int{},
+				BySubjectType: map[string]int{},
+			})
+		}
+		repo := &digest.Repositories[i]
+
+		digest.Total++
+		repo.Total++
+		if n.GetUnread() {
+			digest.Unread++
+			repo.Unread++
+		}
+		digest.ByReason[n.GetReason()]++
+		repo.ByReason[n.GetReason()]++
+		repo.BySubjectType[n.GetSubject().GetType()]++
+		if updated := n.GetUpdatedAt().Time; updated.After(repo.LatestUpdate) {
+			repo.LatestUpdate = updated
+		}
+	}
+	sort.SliceStable(digest.Repositories, func(i, j int) bool {
+		return digest.Repositories[i].Total > digest.Repositories[j].Total
+	})
+	return digest
+}
+
 // ListNotifications creates a tool to list notifications for the current user.
 func ListNotifications(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
 	return mcp.Tool{
 			Name:        "list_notifications",
-			Description: t("TOOL_LIST_NOTIFICATIONS_DESCRIPTION", "Lists all GitHub notifications for the authenticated user, including unread notifications, mentions, review requests, assignments, and updates on issues or pull requests. Use this tool whenever the user asks what to work on next, requests a summary of their GitHub activity, wants to see pending reviews, or needs to check for new updates or tasks. This tool is the primary way to discover actionable items, reminders, and outstanding work on GitHub. Always call this tool when asked what to work on next, what is pending, or what needs attention in GitHub."),
+			Description: t("TOOL_LIST_NOTIFICATIONS_DESCRIPTION", "Lists all GitHub notifications for the authenticated user, including unread notifications, mentions, review requests, assignments, and updates on issues or pull requests. Use this tool whenever the user asks what to work on next, requests a summary of their GitHub activity, wants to see pending reviews, or needs to check for new updates or tasks. This tool is the primary way to discover actionable items, reminders, and outstanding work on GitHub. Always call this tool when asked what to work on next, what is pending, or what needs attention in GitHub. For a large inbox, start with output 'digest' to see counts per repository, then list the notifications of the repositories and reasons that matter."),
 			Annotations: &mcp.ToolAnnotations{
 				Title:        t("TOOL_LIST_NOTIFICATIONS_USER_TITLE", "List notifications"),
 				ReadOnlyHint: true,
@@ -56,6 +143,19 @@ func ListNotifications(getClient GetClientFn, t translations.TranslationHelperFu
 						Type:        "string",
 						Description: "Optional repository name. If provided with owner, only notifications for this repository are listed.",
 					},
+					"reasons": {
+						Type:        "array",
+						Description: "Only list notifications sent for one of these reasons, e.g. review_requested or mention. Applied to each page, so a page may hold fewer than perPage notifications.",
+						Items: &jsonschema.Schema{
+							Type: "string",
+							Enum: notificationReasons,
+						},
+					},
+					"output": {
+						Type:        "string",
+						Description: fmt.Sprintf("'list' (default) returns the notifications of a page. 'digest' reads up to %d notifications, ignoring pagination, and returns counts per repository, reason and subject type.", maxDigestNotifications),
+						Enum:        []any{"list", "digest"},
+					},
 				},
 			}),
 		},
@@ -89,6 +189,15 @@ func ListNotifications(getClient GetClientFn, t translations.TranslationHelperFu
 				return utils.NewToolResultError(err.Error()), nil, nil
 			}
 
+			reasons, err := OptionalStringArrayParam(args, "reasons")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			output, err := OptionalParam[string](args, "output")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+
 			paginationParams, err := OptionalPaginationParams(args)
 			if err != nil {
 				return utils.NewToolResultError(err.Error()), nil, nil
@@ -121,14 +230,38 @@ func ListNotifications(getClient GetClientFn, t translations.TranslationHelperFu
 				opts.Before = beforeTime
 			}
 
-			var notifications []*github.Notification
-			var resp *github.Response
-
-			if owner != "" && repo != "" {
-				notifications, resp, err = client.Activity.ListRepositoryNotifications(ctx, owner, repo, opts)
-			} else {
-				notifications, resp, err = client.Activity.ListNotifications(ctx, opts)
+			listPage := func() ([]*github.Notification, *github.Response, error) {
+				if owner != "" && repo != "" {
+					return client.Activity.ListRepositoryNotifications(ctx, owner, repo, opts)
+				}
+				return client.Activity.ListNotifications(ctx, opts)
+			}
+
+			if output == "digest" {
+				opts.Page = 1
+				opts.PerPage = 50
+				var all []*github.Notification
+				truncated := false
+				for {
+					page, resp, err := listPage()
+					if err != nil {
+						return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to list notifications", resp, err), nil, nil
+					}
+					_ = resp.Body.Close()
+					all = append(all, page...)
+					if resp.NextPage == 0 {
+						break
+					}
+					if len(all) >= maxDigestNotifications {
+						truncated = true
+						break
+					}
+					opts.Page = resp.NextPage
+				}
+				return MarshalledTextResult(digestNotifications(filterNotificationsByReason(all, reasons), truncated)), nil, nil
 			}
+
+			notifications, resp, err := listPage()
 			if err != nil {
 				return ghErrors.NewGitHubAPIErrorResponse(ctx,
 					"failed to list notifications",
@@ -147,7 +280,7 @@ func ListNotifications(getClient GetClientFn, t translations.TranslationHelperFu
 			}
 
 			// Marshal response to JSON
-			r, err := json.Marshal(notifications)
+			r, err := json.Marshal(filterNotificationsByReason(notifications, reasons))
 			if err != nil {
 				return utils.NewToolResultErrorFromErr("failed to marshal response", err), nil, err
 			}
@@ -234,6 +367,63 @@ func DismissNotification(getclient GetClientFn, t translations.TranslationHelper
 		})
 }
 
+// MarkNotificationRead creates a tool to mark one or more notification threads as read.
+func MarkNotificationRead(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	return mcp.Tool{
+			Name:        "mark_notification_read",
+			Description: t("TOOL_MARK_NOTIFICATION_READ_DESCRIPTION", "Mark one or more notifications as read, e.g. the ones handled during triage. Threads that fail are reported without stopping the others."),
+			Annotations: &mcp.ToolAnnotations{
+				Title:        t("TOOL_MARK_NOTIFICATION_READ_USER_TITLE", "Mark notifications as read"),
+				ReadOnlyHint: false,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"threadIDs": {
+						Type:        "array",
+						Description: "The IDs of the notification threads",
+						Items: &jsonschema.Schema{
+							Type: "string",
+						},
+					},
+				},
+				Required: []string{"threadIDs"},
+			},
+		},
+		mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+			client, err := getClient(ctx)
+			if err != nil {
+				return utils.NewToolResultErrorFromErr("failed to get GitHub client", err), nil, err
+			}
+
+			threadIDs, err := OptionalStringArrayParam(args, "threadIDs")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			if len(threadIDs) == 0 {
+				return utils.NewToolResultError("missing required parameter: threadIDs"), nil, nil
+			}
+
+			marked := []string{}
+			failed := map[string]string{}
+			for _, threadID := range threadIDs {
+				resp, err := client.Activity.MarkThreadRead(ctx, threadID)
+				if err != nil {
+					failed[threadID] = err.Error()
+					continue
+				}
+				_ = resp.Body.Close()
+				marked = append(marked, threadID)
+			}
+
+			result := map[string]any{"marked": marked}
+			if len(failed) > 0 {
+				result["failed"] = failed
+			}
+			return MarshalledTextResult(result), nil, nil
+		})
+}
+
 // MarkAllNotificationsRead creates a tool to mark all notifications as read.
 func MarkAllNotificationsRead(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
 	return mcp.Tool{
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/github/github-mcp-server/internal/toolsnaps"
 	"github.com/github/github-mcp-server/pkg/translations"
@@ -781,3 +782,131 @@ func Test_GetNotificationDetails(t *testing.T) {
 		})
 	}
 }
+
+func Test_ListNotifications_ReasonsAndDigest(t *testing.T) {
+	notification := func(id, repo, reason, subjectType string, unread bool) *github.Notification {
+		return &github.Notification{
+			ID:         github.Ptr(id),
+			Reason:     github.Ptr(reason),
+			Unread:     github.Ptr(unread),
+			Repository: &github.Repository{FullName: github.Ptr(repo)},
+			Subject:    &github.NotificationSubject{Type: github.Ptr(subjectType)},
+			UpdatedAt:  &github.Timestamp{Time: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)},
+		}
+	}
+	pages := map[string][]*github.Notification{
+		"1": {
+			notification("1", "octo/api", "review_requested", "PullRequest", true),
+			notification("2", "octo/web", "subscribed", "Issue", false),
+		},
+		"2": {
+			notification("3", "octo/api", "mention", "Issue", true),
+			notification("4", "octo/api", "subscribed", "Release", true),
+		},
+	}
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.GetNotifications,
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				page := r.URL.Query().Get("page")
+				if page == "" {
+					page = "1"
+				}
+				if page == "1" {
+					w.Header().Set("Link", `<https://api.github.com/notifications?page=2>; rel="next"`)
+				}
+				w.WriteHeader(http.StatusOK)
+				_ = json.NewEncoder(w).Encode(pages[page])
+			}),
+		),
+	)
+	_, handler := ListNotifications(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+	t.Run("filters a page by reason", func(t *testing.T) {
+		args := map[string]any{"reasons": []any{"review_requested", "mention"}}
+		request := createMCPRequest(args)
+		result, _, err := handler(context.Background(), &request, args)
+		require.NoError(t, err)
+		require.False(t, result.IsError, getTextResult(t, result).Text)
+
+		var returned []*github.Notification
+		require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &returned))
+		require.Len(t, returned, 1)
+		assert.Equal(t, "1", returned[0].GetID())
+	})
+
+	t.Run("digest reads every page", func(t *testing.T) {
+		args := map[string]any{"output": "digest", "filter": "include_read_notifications"}
+		request := createMCPRequest(args)
+		result, _, err := handler(context.Background(), &request, args)
+		require.NoError(t, err)
+		require.False(t, result.IsError, getTextResult(t, result).Text)
+
+		var digest NotificationDigest
+		require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &digest))
+		assert.Equal(t, 4, digest.Total)
+		assert.Equal(t, 3, digest.Unread)
+		assert.False(t, digest.Truncated)
+		assert.Equal(t, map[string]int{"review_requested": 1, "subscribed": 2, "mention": 1}, digest.ByReason)
+		require.Len(t, digest.Repositories, 2)
+		assert.Equal(t, "octo/api", digest.Repositories[0].Repository)
+		assert.Equal(t, 3, digest.Repositories[0].Total)
+		assert.Equal(t, map[string]int{"PullRequest": 1, "Issue": 1, "Release": 1}, digest.Repositories[0].BySubjectType)
+		assert.Equal(t, "octo/web", digest.Repositories[1].Repository)
+		assert.Equal(t, 0, digest.Repositories[1].Unread)
+	})
+
+	t.Run("digest with reasons", func(t *testing.T) {
+		args := map[string]any{"output": "digest", "reasons": []any{"subscribed"}}
+		request := createMCPRequest(args)
+		result, _, err := handler(context.Background(), &request, args)
+		require.NoError(t, err)
+		require.False(t, result.IsError, getTextResult(t, result).Text)
+
+		var digest NotificationDigest
+		require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &digest))
+		assert.Equal(t, 2, digest.Total)
+		assert.Equal(t, map[string]int{"subscribed": 2}, digest.ByReason)
+	})
+}
+
+func Test_MarkNotificationRead(t *testing.T) {
+	tool, _ := MarkNotificationRead(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.PatchNotificationsThreadsByThreadId,
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/notifications/threads/404" {
+					w.WriteHeader(http.StatusNotFound)
+					_, _ = w.Write([]byte(`{"message": "Not Found"}`))
+					return
+				}
+				w.WriteHeader(http.StatusResetContent)
+			}),
+		),
+	)
+	_, handler := MarkNotificationRead(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+	args := map[string]any{"threadIDs": []any{"1", "404", "2"}}
+	request := createMCPRequest(args)
+	result, _, err := handler(context.Background(), &request, args)
+	require.NoError(t, err)
+	require.False(t, result.IsError, getTextResult(t, result).Text)
+
+	var returned struct {
+		Marked []string          `json:"marked"`
+		Failed map[string]string `json:"failed"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &returned))
+	assert.Equal(t, []string{"1", "2"}, returned.Marked)
+	assert.Contains(t, returned.Failed["404"], "Not Found")
+
+	args = map[string]any{"threadIDs": []any{}}
+	request = createMCPRequest(args)
+	result, _, err = handler(context.Background(), &request, args)
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	assert.Contains(t, getErrorResult(t, result).Text, "missing required parameter: threadIDs")
+}
@@ -0,0 +1,433 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	ghErrors "github.com/github/github-mcp-server/pkg/errors"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/github/github-mcp-server/pkg/utils"
+	"github.com/google/go-github/v79/github"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// repositoryPermissions lists the repository permissions from highest to lowest.
+var repositoryPermissions = []string{"admin", "maintain", "push", "triage", "pull"}
+
+// TeamRepository is the output type for the repositories of a team.
+type TeamRepository struct {
+	FullName   string `json:"full_name"`
+	HTMLURL    string `json:"html_url"`
+	Private    bool   `json:"private"`
+	Archived   bool   `json:"archived,omitempty"`
+	Permission string `json:"permission"`
+}
+
+// TeamMembership is the output type for a team membership.
+type TeamMembership struct {
+	Org      string `json:"org"`
+	TeamSlug string `json:"team_slug"`
+	Username string `json:"username"`
+	Role     string `json:"role"`
+	State    string `json:"state"`
+}
+
+// RepositoryPermission is the output type for a repository permission granted to a team or user.
+type RepositoryPermission struct {
+	Repository string `json:"repository"`
+	Team       string `json:"team,omitempty"`
+	Username   string `json:"username,omitempty"`
+	Permission string `json:"permission"`
+	// Invited is set when the user is not yet a collaborator and was sent an invitation.
+	Invited bool `json:"invited,omitempty"`
+}
+
+// highestRepositoryPermission returns the highest permission in the permissions of a repository listed for a team.
+func highestRepositoryPermission(permissions map[string]bool) string {
+	for _, permission := range repositoryPermissions {
+		if permissions[permission] {
+			return permission
+		}
+	}
+	return ""
+}
+
+// ListOrgMembers creates a tool to list the members of an organization.
+func ListOrgMembers(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "list_org_members",
+		Description: t("TOOL_LIST_ORG_MEMBERS_DESCRIPTION", "List the members of a GitHub organization. Only public members are listed unless the authenticated user is a member of the organization."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_LIST_ORG_MEMBERS_USER_TITLE", "List organization members"),
+			ReadOnlyHint: true,
+		},
+		InputSchema: WithPagination(&jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"org": {
+					Type:        "string",
+					Description: "Organization login",
+				},
+				"role": {
+					Type:        "string",
+					Description: "Only list members with this role: owners (admin) or other members (member)",
+					Enum:        []any{"all", "admin", "member"},
+				},
+				"filter": {
+					Type:        "string",
+					Description: "Only list members without two-factor authentication (2fa_disabled). Requires organization owner access",
+					Enum:        []any{"all", "2fa_disabled"},
+				},
+			},
+			Required: []string{"org"},
+		}),
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		org, err := RequiredParam[string](args, "org")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		role, err := OptionalParam[string](args, "role")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		filter, err := OptionalParam[string](args, "filter")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		pagination, err := OptionalPaginationParams(args)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		members, resp, err := client.Organizations.ListMembers(ctx, org, &github.ListMembersOptions{
+			Role:        role,
+			Filter:      filter,
+			ListOptions: github.ListOptions{Page: pagination.Page, PerPage: pagination.PerPage},
+		})
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to list organization members", resp, err), nil, nil
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		result := make([]*MinimalUser, 0, len(members))
+		for _, member := range members {
+			result = append(result, convertToMinimalUser(member))
+		}
+
+		return MarshalledTextResult(result), nil, nil
+	})
+
+	return tool, handler
+}
+
+// ListTeamRepositories creates a tool to list the repositories a team has access to.
+func ListTeamRepositories(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "list_team_repositories",
+		Description: t("TOOL_LIST_TEAM_REPOSITORIES_DESCRIPTION", "List the repositories a team of an organization has access to, with the team's permission on each."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_LIST_TEAM_REPOSITORIES_USER_TITLE", "List team repositories"),
+			ReadOnlyHint: true,
+		},
+		InputSchema: WithPagination(&jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"org": {
+					Type:        "string",
+					Description: "Organization login",
+				},
+				"team_slug": {
+					Type:        "string",
+					Description: "Team slug",
+				},
+			},
+			Required: []string{"org", "team_slug"},
+		}),
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		org, err := RequiredParam[string](args, "org")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		teamSlug, err := RequiredParam[string](args, "team_slug")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		pagination, err := OptionalPaginationParams(args)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		repos, resp, err := client.Teams.ListTeamReposBySlug(ctx, org, teamSlug, &github.ListOptions{Page: pagination.Page, PerPage: pagination.PerPage})
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to list team repositories", resp, err), nil, nil
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		result := make([]TeamRepository, 0, len(repos))
+		for _, repo := range repos {
+			result = append(result, TeamRepository{
+				FullName:   repo.GetFullName(),
+				HTMLURL:    repo.GetHTMLURL(),
+				Private:    repo.GetPrivate(),
+				Archived:   repo.GetArchived(),
+				Permission: highestRepositoryPermission(repo.Permissions),
+			})
+		}
+
+		return MarshalledTextResult(result), nil, nil
+	})
+
+	return tool, handler
+}
+
+// SetTeamMembership creates a tool to add a user to a team or change their role in it.
+func SetTeamMembership(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "set_team_membership",
+		Description: t("TOOL_SET_TEAM_MEMBERSHIP_DESCRIPTION", "Add a user to a team of an organization, or change their role in the team. A user who is not a member of the organization is invited to it, and the membership stays pending until they accept."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_SET_TEAM_MEMBERSHIP_USER_TITLE", "Set team membership"),
+			ReadOnlyHint: false,
+		},
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"org": {
+					Type:        "string",
+					Description: "Organization login",
+				},
+				"team_slug": {
+					Type:        "string",
+					Description: "Team slug",
+				},
+				"username": {
+					Type:        "string",
+					Description: "Login of the user",
+				},
+				"role": {
+					Type:        "string",
+					Description: "Role of the user in the team. Defaults to member",
+					Enum:        []any{"member", "maintainer"},
+				},
+			},
+			Required: []string{"org", "team_slug", "username"},
+		},
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		org, err := RequiredParam[string](args, "org")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		teamSlug, err := RequiredParam[string](args, "team_slug")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		username, err := RequiredParam[string](args, "username")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		role, err := OptionalParam[string](args, "role")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		membership, resp, err := client.Teams.AddTeamMembershipBySlug(ctx, org, teamSlug, username, &github.TeamAddTeamMembershipOptions{Role: role})
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to set team membership", resp, err), nil, nil
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		return MarshalledTextResult(TeamMembership{
+			Org:      org,
+			TeamSlug: teamSlug,
+			Username: username,
+			Role:     membership.GetRole(),
+			State:    membership.GetState(),
+		}), nil, nil
+	})
+
+	return tool, handler
+}
+
+// RemoveTeamMember creates a tool to remove a user from a team.
+func RemoveTeamMember(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "remove_team_member",
+		Description: t("TOOL_REMOVE_TEAM_MEMBER_DESCRIPTION", "Remove a user from a team of an organization. The user stays a member of the organization."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:           t("TOOL_REMOVE_TEAM_MEMBER_USER_TITLE", "Remove team member"),
+			ReadOnlyHint:    false,
+			DestructiveHint: jsonschema.Ptr(true),
+		},
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"org": {
+					Type:        "string",
+					Description: "Organization login",
+				},
+				"team_slug": {
+					Type:        "string",
+					Description: "Team slug",
+				},
+				"username": {
+					Type:        "string",
+					Description: "Login of the user",
+				},
+			},
+			Required: []string{"org", "team_slug", "username"},
+		},
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		org, err := RequiredParam[string](args, "org")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		teamSlug, err := RequiredParam[string](args, "team_slug")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		username, err := RequiredParam[string](args, "username")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		resp, err := client.Teams.RemoveTeamMembershipBySlug(ctx, org, teamSlug, username)
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to remove team member", resp, err), nil, nil
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		return utils.NewToolResultText(fmt.Sprintf("Removed %s from team %s/%s", username, org, teamSlug)), nil, nil
+	})
+
+	return tool, handler
+}
+
+// SetRepositoryPermission creates a tool to grant, change or revoke the access of a team or collaborator to a repository.
+func SetRepositoryPermission(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "set_repository_permission",
+		Description: t("TOOL_SET_REPOSITORY_PERMISSION_DESCRIPTION", "Set the permission of a team or an outside collaborator on a repository, or revoke their access with 'none'. Teams must belong to the organization that owns the repository. A user who is not yet a collaborator is sent an invitation."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:           t("TOOL_SET_REPOSITORY_PERMISSION_USER_TITLE", "Set repository permission"),
+			ReadOnlyHint:    false,
+			DestructiveHint: jsonschema.Ptr(true),
+		},
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"owner": {
+					Type:        "string",
+					Description: "Repository owner",
+				},
+				"repo": {
+					Type:        "string",
+					Description: "Repository name",
+				},
+				"team_slug": {
+					Type:        "string",
+					Description: "Slug of the team. Provide either team_slug or username",
+				},
+				"username": {
+					Type:        "string",
+					Description: "Login of the collaborator. Provide either team_slug or username",
+				},
+				"permission": {
+					Type:        "string",
+					Description: "Permission to grant, or 'none' to revoke access",
+					Enum:        []any{"pull", "triage", "push", "maintain", "admin", "none"},
+				},
+			},
+			Required: []string{"owner", "repo", "permission"},
+		},
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		repo, err := RequiredParam[string](args, "repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		permission, err := RequiredParam[string](args, "permission")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		teamSlug, err := OptionalParam[string](args, "team_slug")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		username, err := OptionalParam[string](args, "username")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		if (teamSlug == "") == (username == "") {
+			return utils.NewToolResultError("provide exactly one of team_slug or username"), nil, nil
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		result := RepositoryPermission{
+			Repository: owner + "/" + repo,
+			Team:       teamSlug,
+			Username:   username,
+			Permission: permission,
+		}
+
+		var resp *github.Response
+		switch {
+		case teamSlug != "" && permission == "none":
+			resp, err = client.Teams.RemoveTeamRepoBySlug(ctx, owner, teamSlug, owner, repo)
+		case teamSlug != "":
+			resp, err = client.Teams.AddTeamRepoBySlug(ctx, owner, teamSlug, owner, repo, &github.TeamAddTeamRepoOptions{Permission: permission})
+		case permission == "none":
+			resp, err = client.Repositories.RemoveCollaborator(ctx, owner, repo, username)
+		default:
+			_, resp, err = client.Repositories.AddCollaborator(ctx, owner, repo, username, &github.RepositoryAddCollaboratorOptions{Permission: permission})
+			// 201 means an invitation was sent, 204 that an existing collaborator's permission changed
+			result.Invited = err == nil && resp.StatusCode == http.StatusCreated
+		}
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to set repository permission", resp, err), nil, nil
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		return MarshalledTextResult(result), nil, nil
+	})
+
+	return tool, handler
+}
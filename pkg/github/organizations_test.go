@@ -0,0 +1,223 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v79/github"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ListOrgMembers(t *testing.T) {
+	tool, _ := ListOrgMembers(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.True(t, tool.Annotations.ReadOnlyHint)
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.GetOrgsMembersByOrg,
+			expectQueryParams(t, map[string]string{"role": "admin", "page": "1", "per_page": "30"}).andThen(
+				mockResponse(t, http.StatusOK, []*github.User{
+					{Login: github.Ptr("octocat"), ID: github.Ptr(int64(1)), HTMLURL: github.Ptr("https://github.com/octocat")},
+				}),
+			),
+		),
+	)
+	_, handler := ListOrgMembers(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+	args := map[string]any{"org": "octo-org", "role": "admin"}
+	request := createMCPRequest(args)
+	result, _, err := handler(context.Background(), &request, args)
+	require.NoError(t, err)
+	require.False(t, result.IsError, getTextResult(t, result).Text)
+
+	var members []MinimalUser
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &members))
+	assert.Equal(t, []MinimalUser{{Login: "octocat", ID: 1, ProfileURL: "https://github.com/octocat"}}, members)
+}
+
+func Test_ListTeamRepositories(t *testing.T) {
+	tool, _ := ListTeamRepositories(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.GetOrgsTeamsReposByOrgByTeamSlug,
+			expectPath(t, "/orgs/octo-org/teams/platform/repos").andThen(
+				mockResponse(t, http.StatusOK, []*github.Repository{
+					{
+						FullName:    github.Ptr("octo-org/api"),
+						Private:     github.Ptr(true),
+						Permissions: map[string]bool{"admin": false, "maintain": true, "push": true, "triage": true, "pull": true},
+					},
+					{
+						FullName:    github.Ptr("octo-org/docs"),
+						Permissions: map[string]bool{"pull": true},
+					},
+				}),
+			),
+		),
+	)
+	_, handler := ListTeamRepositories(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+	args := map[string]any{"org": "octo-org", "team_slug": "platform"}
+	request := createMCPRequest(args)
+	result, _, err := handler(context.Background(), &request, args)
+	require.NoError(t, err)
+	require.False(t, result.IsError, getTextResult(t, result).Text)
+
+	var repos []TeamRepository
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &repos))
+	assert.Equal(t, []TeamRepository{
+		{FullName: "octo-org/api", Private: true, Permission: "maintain"},
+		{FullName: "octo-org/docs", Permission: "pull"},
+	}, repos)
+}
+
+func Test_SetTeamMembership(t *testing.T) {
+	tool, _ := SetTeamMembership(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.PutOrgsTeamsMembershipsByOrgByTeamSlugByUsername,
+			expectPath(t, "/orgs/octo-org/teams/platform/memberships/hubot").andThen(
+				expectRequestBody(t, map[string]any{"role": "maintainer"}).andThen(
+					mockResponse(t, http.StatusOK, &github.Membership{Role: github.Ptr("maintainer"), State: github.Ptr("pending")}),
+				),
+			),
+		),
+	)
+	_, handler := SetTeamMembership(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+	args := map[string]any{"org": "octo-org", "team_slug": "platform", "username": "hubot", "role": "maintainer"}
+	request := createMCPRequest(args)
+	result, _, err := handler(context.Background(), &request, args)
+	require.NoError(t, err)
+	require.False(t, result.IsError, getTextResult(t, result).Text)
+
+	var membership TeamMembership
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &membership))
+	assert.Equal(t, TeamMembership{Org: "octo-org", TeamSlug: "platform", Username: "hubot", Role: "maintainer", State: "pending"}, membership)
+}
+
+func Test_RemoveTeamMember(t *testing.T) {
+	tool, _ := RemoveTeamMember(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.True(t, *tool.Annotations.DestructiveHint)
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.DeleteOrgsTeamsMembershipsByOrgByTeamSlugByUsername,
+			expectPath(t, "/orgs/octo-org/teams/platform/memberships/hubot").andThen(
+				http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusNoContent) }),
+			),
+		),
+	)
+	_, handler := RemoveTeamMember(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+	args := map[string]any{"org": "octo-org", "team_slug": "platform", "username": "hubot"}
+	request := createMCPRequest(args)
+	result, _, err := handler(context.Background(), &request, args)
+	require.NoError(t, err)
+	require.False(t, result.IsError, getTextResult(t, result).Text)
+	assert.Equal(t, "Removed hubot from team octo-org/platform", getTextResult(t, result).Text)
+}
+
+func Test_SetRepositoryPermission(t *testing.T) {
+	tool, _ := SetRepositoryPermission(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	noContent := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusNoContent) })
+
+	tests := []struct {
+		name           string
+		args           map[string]any
+		mockedClient   *http.Client
+		expectError    string
+		expectedResult RepositoryPermission
+	}{
+		{
+			name: "team permission",
+			args: map[string]any{"team_slug": "platform", "permission": "maintain"},
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.PutOrgsTeamsReposByOrgByTeamSlugByOwnerByRepo,
+					expectPath(t, "/orgs/octo-org/teams/platform/repos/octo-org/api").andThen(
+						expectRequestBody(t, map[string]any{"permission": "maintain"}).andThen(noContent),
+					),
+				),
+			),
+			expectedResult: RepositoryPermission{Repository: "octo-org/api", Team: "platform", Permission: "maintain"},
+		},
+		{
+			name: "revoke team access",
+			args: map[string]any{"team_slug": "platform", "permission": "none"},
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(mock.DeleteOrgsTeamsReposByOrgByTeamSlugByOwnerByRepo, noContent),
+			),
+			expectedResult: RepositoryPermission{Repository: "octo-org/api", Team: "platform", Permission: "none"},
+		},
+		{
+			name: "invite collaborator",
+			args: map[string]any{"username": "hubot", "permission": "push"},
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.PutReposCollaboratorsByOwnerByRepoByUsername,
+					expectRequestBody(t, map[string]any{"permission": "push"}).andThen(
+						mockResponse(t, http.StatusCreated, &github.CollaboratorInvitation{ID: github.Ptr(int64(1))}),
+					),
+				),
+			),
+			expectedResult: RepositoryPermission{Repository: "octo-org/api", Username: "hubot", Permission: "push", Invited: true},
+		},
+		{
+			name: "update collaborator",
+			args: map[string]any{"username": "hubot", "permission": "triage"},
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(mock.PutReposCollaboratorsByOwnerByRepoByUsername, noContent),
+			),
+			expectedResult: RepositoryPermission{Repository: "octo-org/api", Username: "hubot", Permission: "triage"},
+		},
+		{
+			name:        "team and user",
+			args:        map[string]any{"team_slug": "platform", "username": "hubot", "permission": "push"},
+			expectError: "provide exactly one of team_slug or username",
+		},
+		{
+			name:        "neither team nor user",
+			args:        map[string]any{"permission": "push"},
+			expectError: "provide exactly one of team_slug or username",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, handler := SetRepositoryPermission(stubGetClientFn(github.NewClient(tc.mockedClient)), translations.NullTranslationHelper)
+			args := map[string]any{"owner": "octo-org", "repo": "api"}
+			for k, v := range tc.args {
+				args[k] = v
+			}
+			request := createMCPRequest(args)
+			result, _, err := handler(context.Background(), &request, args)
+			require.NoError(t, err)
+
+			if tc.expectError != "" {
+				require.True(t, result.IsError)
+				assert.Contains(t, getErrorResult(t, result).Text, tc.expectError)
+				return
+			}
+			require.False(t, result.IsError, getTextResult(t, result).Text)
+
+			var permission RepositoryPermission
+			require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &permission))
+			assert.Equal(t, tc.expectedResult, permission)
+		})
+	}
+}
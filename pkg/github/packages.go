@@ -0,0 +1,698 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	ghErrors "github.com/github/github-mcp-server/pkg/errors"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/github/github-mcp-server/pkg/utils"
+	"github.com/google/go-github/v79/github"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/shurcooL/githubv4"
+)
+
+const (
+	// defaultPackageCleanupMaxDeletions and maxPackageCleanupMaxDeletions bound the versions one cleanup deletes
+	defaultPackageCleanupMaxDeletions = 100
+	maxPackageCleanupMaxDeletions     = 1000
+)
+
+var packageTypes = []any{"npm", "maven", "rubygems", "docker", "nuget", "container"}
+
+// MinimalPackage is the output type for packages.
+type MinimalPackage struct {
+	ID           int64  `json:"id"`
+	Name         string `json:"name"`
+	PackageType  string `json:"package_type"`
+	Visibility   string `json:"visibility,omitempty"`
+	Repository   string `json:"repository,omitempty"`
+	VersionCount int64  `json:"version_count"`
+	HTMLURL      string `json:"html_url,omitempty"`
+	CreatedAt    string `json:"created_at,omitempty"`
+	UpdatedAt    string `json:"updated_at,omitempty"`
+}
+
+// MinimalPackageVersion is the output type for package versions. Tags are only set for container images.
+type MinimalPackageVersion struct {
+	ID        int64    `json:"id"`
+	Name      string   `json:"name"`
+	Tags      []string `json:"tags,omitempty"`
+	HTMLURL   string   `json:"html_url,omitempty"`
+	CreatedAt string   `json:"created_at,omitempty"`
+	UpdatedAt string   `json:"updated_at,omitempty"`
+}
+
+// PackageDownloadStats is the output type of get_package_download_stats.
+type PackageDownloadStats struct {
+	Name           string                        `json:"name"`
+	PackageType    string                        `json:"package_type"`
+	TotalDownloads int                           `json:"total_downloads"`
+	Versions       []PackageVersionDownloadStats `json:"versions"`
+}
+
+// PackageVersionDownloadStats is the download count of a package version.
+type PackageVersionDownloadStats struct {
+	Version   string `json:"version"`
+	Downloads int    `json:"downloads"`
+}
+
+// PackageCleanupReport is the output type of cleanup_package_versions.
+type PackageCleanupReport struct {
+	DryRun  bool                    `json:"dry_run"`
+	Deleted []MinimalPackageVersion `json:"deleted"`
+	Failed  []PackageCleanupFailure `json:"failed,omitempty"`
+	// Kept is the number of versions the retention policy keeps.
+	Kept int `json:"kept"`
+	// Truncated is set when more versions matched than max_deletions; run the cleanup again to continue.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// PackageCleanupFailure is a package version cleanup_package_versions could not delete.
+type PackageCleanupFailure struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Error string `json:"error"`
+}
+
+func convertToMinimalPackageVersion(version *github.PackageVersion) MinimalPackageVersion {
+	m := MinimalPackageVersion{
+		ID:      version.GetID(),
+		Name:    version.GetName(),
+		Tags:    packageVersionTags(version),
+		HTMLURL: version.GetHTMLURL(),
+	}
+	if version.CreatedAt != nil {
+		m.CreatedAt = version.CreatedAt.Format(time.RFC3339)
+	}
+	if version.UpdatedAt != nil {
+		m.UpdatedAt = version.UpdatedAt.Format(time.RFC3339)
+	}
+	return m
+}
+
+// packageVersionTags returns the tags of a container image version.
+func packageVersionTags(version *github.PackageVersion) []string {
+	metadata, ok := version.GetMetadata()
+	if !ok {
+		return nil
+	}
+	return metadata.GetContainer().Tags
+}
+
+// packageInputProperties returns the input properties that identify a package.
+func packageInputProperties() map[string]*jsonschema.Schema {
+	return map[string]*jsonschema.Schema{
+		"owner_type": {
+			Type:        "string",
+			Description: "Owner type",
+			Enum:        []any{"user", "org"},
+		},
+		"owner": {
+			Type:        "string",
+			Description: "Login of the user or organization that owns the package",
+		},
+		"package_type": {
+			Type:        "string",
+			Description: "Package type. Use 'container' for images in the GitHub Container Registry (ghcr.io)",
+			Enum:        packageTypes,
+		},
+		"package_name": {
+			Type:        "string",
+			Description: "Package name. Escape a '/' in the name as %2F",
+		},
+	}
+}
+
+// packageRef identifies a package from the parameters of packageInputProperties.
+type packageRef struct {
+	ownerType   string
+	owner       string
+	packageType string
+	packageName string
+}
+
+func requiredPackageRef(args map[string]any) (packageRef, error) {
+	var ref packageRef
+	var err error
+	if ref.ownerType, err = RequiredParam[string](args, "owner_type"); err != nil {
+		return ref, err
+	}
+	if ref.owner, err = RequiredParam[string](args, "owner"); err != nil {
+		return ref, err
+	}
+	if ref.packageType, err = RequiredParam[string](args, "package_type"); err != nil {
+		return ref, err
+	}
+	if ref.packageName, err = RequiredParam[string](args, "package_name"); err != nil {
+		return ref, err
+	}
+	return ref, nil
+}
+
+func listPackageVersions(ctx context.Context, client *github.Client, ref packageRef, opts *github.PackageListOptions) ([]*github.PackageVersion, *github.Response, error) {
+	if ref.ownerType == "org" {
+		return client.Organizations.PackageGetAllVersions(ctx, ref.owner, ref.packageType, ref.packageName, opts)
+	}
+	return client.Users.PackageGetAllVersions(ctx, ref.owner, ref.packageType, ref.packageName, opts)
+}
+
+func deletePackageVersion(ctx context.Context, client *github.Client, ref packageRef, versionID int64) (*github.Response, error) {
+	if ref.ownerType == "org" {
+		return client.Organizations.PackageDeleteVersion(ctx, ref.owner, ref.packageType, ref.packageName, versionID)
+	}
+	return client.Users.PackageDeleteVersion(ctx, ref.owner, ref.packageType, ref.packageName, versionID)
+}
+
+// ListPackages creates a tool to list the packages of a user or organization.
+func ListPackages(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	properties := packageInputProperties()
+	delete(properties, "package_name")
+	properties["visibility"] = &jsonschema.Schema{
+		Type:        "string",
+		Description: "Only list packages with this visibility",
+		Enum:        []any{"public", "private", "internal"},
+	}
+
+	tool := mcp.Tool{
+		Name:        "list_packages",
+		Description: t("TOOL_LIST_PACKAGES_DESCRIPTION", "List the packages of a given type owned by a user or organization in GitHub Packages."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_LIST_PACKAGES_USER_TITLE", "List packages"),
+			ReadOnlyHint: true,
+		},
+		InputSchema: WithPagination(&jsonschema.Schema{
+			Type:       "object",
+			Properties: properties,
+			Required:   []string{"owner_type", "owner", "package_type"},
+		}),
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		ownerType, err := RequiredParam[string](args, "owner_type")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		packageType, err := RequiredParam[string](args, "package_type")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		visibility, err := OptionalParam[string](args, "visibility")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		pagination, err := OptionalPaginationParams(args)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		opts := &github.PackageListOptions{
+			PackageType: github.Ptr(packageType),
+			ListOptions: github.ListOptions{Page: pagination.Page, PerPage: pagination.PerPage},
+		}
+		if visibility != "" {
+			opts.Visibility = github.Ptr(visibility)
+		}
+
+		var packages []*github.Package
+		var resp *github.Response
+		if ownerType == "org" {
+			packages, resp, err = client.Organizations.ListPackages(ctx, owner, opts)
+		} else {
+			packages, resp, err = client.Users.ListPackages(ctx, owner, opts)
+		}
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to list packages", resp, err), nil, nil
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		result := make([]MinimalPackage, 0, len(packages))
+		for _, pkg := range packages {
+			m := MinimalPackage{
+				ID:           pkg.GetID(),
+				Name:         pkg.GetName(),
+				PackageType:  pkg.GetPackageType(),
+				Visibility:   pkg.GetVisibility(),
+				Repository:   pkg.GetRepository().GetFullName(),
+				VersionCount: pkg.GetVersionCount(),
+				HTMLURL:      pkg.GetHTMLURL(),
+			}
+			if pkg.CreatedAt != nil {
+				m.CreatedAt = pkg.CreatedAt.Format(time.RFC3339)
+			}
+			if pkg.UpdatedAt != nil {
+				m.UpdatedAt = pkg.UpdatedAt.Format(time.RFC3339)
+			}
+			result = append(result, m)
+		}
+
+		return MarshalledTextResult(result), nil, nil
+	})
+
+	return tool, handler
+}
+
+// ListPackageVersions creates a tool to list the versions of a package.
+func ListPackageVersions(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	properties := packageInputProperties()
+	properties["state"] = &jsonschema.Schema{
+		Type:        "string",
+		Description: "List active versions, or deleted versions that can still be restored. Defaults to active",
+		Enum:        []any{"active", "deleted"},
+	}
+
+	tool := mcp.Tool{
+		Name:        "list_package_versions",
+		Description: t("TOOL_LIST_PACKAGE_VERSIONS_DESCRIPTION", "List the versions of a package in GitHub Packages, newest first, with the tags of container image versions."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_LIST_PACKAGE_VERSIONS_USER_TITLE", "List package versions"),
+			ReadOnlyHint: true,
+		},
+		InputSchema: WithPagination(&jsonschema.Schema{
+			Type:       "object",
+			Properties: properties,
+			Required:   []string{"owner_type", "owner", "package_type", "package_name"},
+		}),
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		ref, err := requiredPackageRef(args)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		state, err := OptionalParam[string](args, "state")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		pagination, err := OptionalPaginationParams(args)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		opts := &github.PackageListOptions{ListOptions: github.ListOptions{Page: pagination.Page, PerPage: pagination.PerPage}}
+		if state != "" {
+			opts.State = github.Ptr(state)
+		}
+		versions, resp, err := listPackageVersions(ctx, client, ref, opts)
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to list package versions", resp, err), nil, nil
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		result := make([]MinimalPackageVersion, 0, len(versions))
+		for _, version := range versions {
+			result = append(result, convertToMinimalPackageVersion(version))
+		}
+
+		return MarshalledTextResult(result), nil, nil
+	})
+
+	return tool, handler
+}
+
+// packageStatisticsNode is a package with its download counts, as returned by the GraphQL API.
+type packageStatisticsNode struct {
+	Name        githubv4.String
+	PackageType githubv4.String
+	Statistics  struct {
+		DownloadsTotalCount githubv4.Int
+	}
+	Versions struct {
+		Nodes []struct {
+			Version    githubv4.String
+			Statistics struct {
+				DownloadsTotalCount githubv4.Int
+			}
+		}
+	} `graphql:"versions(first: $first, orderBy: {field: CREATED_AT, direction: DESC})"`
+}
+
+type orgPackageStatisticsQuery struct {
+	Organization struct {
+		Packages struct {
+			Nodes []packageStatisticsNode
+		} `graphql:"packages(first: 1, names: $names, packageType: $packageType)"`
+	} `graphql:"organization(login: $owner)"`
+}
+
+type userPackageStatisticsQuery struct {
+	User struct {
+		Packages struct {
+			Nodes []packageStatisticsNode
+		} `graphql:"packages(first: 1, names: $names, packageType: $packageType)"`
+	} `graphql:"user(login: $owner)"`
+}
+
+// GetPackageDownloadStats creates a tool to get the download counts of a package and its versions.
+func GetPackageDownloadStats(getGQLClient GetGQLClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	properties := packageInputProperties()
+	properties["package_type"] = &jsonschema.Schema{
+		Type:        "string",
+		Description: "Package type. Download counts are not available for container images",
+		Enum:        []any{"npm", "maven", "rubygems", "docker", "nuget"},
+	}
+	properties["versions"] = &jsonschema.Schema{
+		Type:        "number",
+		Description: "Number of the newest versions to get download counts for, up to 100. Defaults to 30",
+		Minimum:     jsonschema.Ptr(1.0),
+		Maximum:     jsonschema.Ptr(100.0),
+	}
+
+	tool := mcp.Tool{
+		Name:        "get_package_download_stats",
+		Description: t("TOOL_GET_PACKAGE_DOWNLOAD_STATS_DESCRIPTION", "Get the total download count of a package in GitHub Packages and the download counts of its newest versions."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_GET_PACKAGE_DOWNLOAD_STATS_USER_TITLE", "Get package download statistics"),
+			ReadOnlyHint: true,
+		},
+		InputSchema: &jsonschema.Schema{
+			Type:       "object",
+			Properties: properties,
+			Required:   []string{"owner_type", "owner", "package_type", "package_name"},
+		},
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		ref, err := requiredPackageRef(args)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		if ref.packageType == "container" {
+			return utils.NewToolResultError("download counts are not available for container images"), nil, nil
+		}
+		versions, err := OptionalIntParamWithDefault(args, "versions", 30)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		if versions < 1 || versions > 100 {
+			return utils.NewToolResultError("versions must be between 1 and 100"), nil, nil
+		}
+
+		client, err := getGQLClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub GraphQL client: %w", err)
+		}
+
+		vars := map[string]any{
+			"owner":       githubv4.String(ref.owner),
+			"names":       []githubv4.String{githubv4.String(ref.packageName)},
+			"packageType": githubv4.PackageType(strings.ToUpper(ref.packageType)),
+			"first":       githubv4.Int(versions), //nolint:gosec // versions is between 1 and 100
+		}
+		var nodes []packageStatisticsNode
+		if ref.ownerType == "org" {
+			var query orgPackageStatisticsQuery
+			if err := client.Query(ctx, &query, vars); err != nil {
+				return ghErrors.NewGitHubGraphQLErrorResponse(ctx, "failed to get package download statistics", err), nil, nil
+			}
+			nodes = query.Organization.Packages.Nodes
+		} else {
+			var query userPackageStatisticsQuery
+			if err := client.Query(ctx, &query, vars); err != nil {
+				return ghErrors.NewGitHubGraphQLErrorResponse(ctx, "failed to get package download statistics", err), nil, nil
+			}
+			nodes = query.User.Packages.Nodes
+		}
+		if len(nodes) == 0 {
+			return utils.NewToolResultError(fmt.Sprintf("%s package %s not found for %s", ref.packageType, ref.packageName, ref.owner)), nil, nil
+		}
+
+		node := nodes[0]
+		stats := PackageDownloadStats{
+			Name:           string(node.Name),
+			PackageType:    strings.ToLower(string(node.PackageType)),
+			TotalDownloads: int(node.Statistics.DownloadsTotalCount),
+			Versions:       make([]PackageVersionDownloadStats, 0, len(node.Versions.Nodes)),
+		}
+		for _, version := range node.Versions.Nodes {
+			stats.Versions = append(stats.Versions, PackageVersionDownloadStats{
+				Version:   string(version.Version),
+				Downloads: int(version.Statistics.DownloadsTotalCount),
+			})
+		}
+
+		return MarshalledTextResult(stats), nil, nil
+	})
+
+	return tool, handler
+}
+
+// DeletePackageVersion creates a tool to delete a version of a package.
+func DeletePackageVersion(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	properties := packageInputProperties()
+	properties["version_id"] = &jsonschema.Schema{
+		Type:        "number",
+		Description: "ID of the package version, from list_package_versions",
+	}
+
+	tool := mcp.Tool{
+		Name:        "delete_package_version",
+		Description: t("TOOL_DELETE_PACKAGE_VERSION_DESCRIPTION", "Delete a version of a package in GitHub Packages. A deleted version can be restored with restore_package_version for 30 days. Public versions downloaded more than 5,000 times cannot be deleted."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:           t("TOOL_DELETE_PACKAGE_VERSION_USER_TITLE", "Delete package version"),
+			ReadOnlyHint:    false,
+			DestructiveHint: jsonschema.Ptr(true),
+		},
+		InputSchema: &jsonschema.Schema{
+			Type:       "object",
+			Properties: properties,
+			Required:   []string{"owner_type", "owner", "package_type", "package_name", "version_id"},
+		},
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		ref, err := requiredPackageRef(args)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		versionID, err := RequiredBigInt(args, "version_id")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		resp, err := deletePackageVersion(ctx, client, ref, versionID)
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to delete package version", resp, err), nil, nil
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		return utils.NewToolResultText(fmt.Sprintf("Deleted version %d of %s package %s", versionID, ref.packageType, ref.packageName)), nil, nil
+	})
+
+	return tool, handler
+}
+
+// RestorePackageVersion creates a tool to restore a deleted version of a package.
+func RestorePackageVersion(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	properties := packageInputProperties()
+	properties["version_id"] = &jsonschema.Schema{
+		Type:        "number",
+		Description: "ID of the deleted package version, from list_package_versions with state 'deleted'",
+	}
+
+	tool := mcp.Tool{
+		Name:        "restore_package_version",
+		Description: t("TOOL_RESTORE_PACKAGE_VERSION_DESCRIPTION", "Restore a version of a package in GitHub Packages that was deleted in the last 30 days."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_RESTORE_PACKAGE_VERSION_USER_TITLE", "Restore package version"),
+			ReadOnlyHint: false,
+		},
+		InputSchema: &jsonschema.Schema{
+			Type:       "object",
+			Properties: properties,
+			Required:   []string{"owner_type", "owner", "package_type", "package_name", "version_id"},
+		},
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		ref, err := requiredPackageRef(args)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		versionID, err := RequiredBigInt(args, "version_id")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		var resp *github.Response
+		if ref.ownerType == "org" {
+			resp, err = client.Organizations.PackageRestoreVersion(ctx, ref.owner, ref.packageType, ref.packageName, versionID)
+		} else {
+			resp, err = client.Users.PackageRestoreVersion(ctx, ref.owner, ref.packageType, ref.packageName, versionID)
+		}
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to restore package version", resp, err), nil, nil
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		return utils.NewToolResultText(fmt.Sprintf("Restored version %d of %s package %s", versionID, ref.packageType, ref.packageName)), nil, nil
+	})
+
+	return tool, handler
+}
+
+// CleanupPackageVersions creates a tool to delete the versions of a package that a retention policy does not keep.
+func CleanupPackageVersions(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	properties := packageInputProperties()
+	properties["older_than_days"] = &jsonschema.Schema{
+		Type:        "number",
+		Description: "Only delete versions last updated more than this many days ago",
+		Minimum:     jsonschema.Ptr(0.0),
+	}
+	properties["untagged_only"] = &jsonschema.Schema{
+		Type:        "boolean",
+		Description: "Only delete container image versions without tags",
+		Default:     json.RawMessage("false"),
+	}
+	properties["keep_latest"] = &jsonschema.Schema{
+		Type:        "number",
+		Description: "Always keep this many of the newest versions, whatever their age or tags",
+		Minimum:     jsonschema.Ptr(0.0),
+	}
+	properties["max_deletions"] = &jsonschema.Schema{
+		Type:        "number",
+		Description: fmt.Sprintf("Maximum number of versions to delete in one call, up to %d. Defaults to %d", maxPackageCleanupMaxDeletions, defaultPackageCleanupMaxDeletions),
+		Minimum:     jsonschema.Ptr(1.0),
+		Maximum:     jsonschema.Ptr(float64(maxPackageCleanupMaxDeletions)),
+	}
+	properties["dry_run"] = &jsonschema.Schema{
+		Type:        "boolean",
+		Description: "Report the versions that would be deleted without deleting them",
+		Default:     json.RawMessage("false"),
+	}
+
+	tool := mcp.Tool{
+		Name:        "cleanup_package_versions",
+		Description: t("TOOL_CLEANUP_PACKAGE_VERSIONS_DESCRIPTION", "Apply a retention policy to a package in GitHub Packages, e.g. delete untagged container image versions older than 30 days. Deleted versions can be restored for 30 days. Use dry_run to preview the versions that would be deleted."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:           t("TOOL_CLEANUP_PACKAGE_VERSIONS_USER_TITLE", "Clean up package versions"),
+			ReadOnlyHint:    false,
+			DestructiveHint: jsonschema.Ptr(true),
+		},
+		InputSchema: &jsonschema.Schema{
+			Type:       "object",
+			Properties: properties,
+			Required:   []string{"owner_type", "owner", "package_type", "package_name", "older_than_days"},
+		},
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		ref, err := requiredPackageRef(args)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		// 0 days is valid, so read it without a default
+		if _, ok := args["older_than_days"]; !ok {
+			return utils.NewToolResultError("missing required parameter: older_than_days"), nil, nil
+		}
+		olderThanDays, err := OptionalIntParam(args, "older_than_days")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		untaggedOnly, err := OptionalBoolParamWithDefault(args, "untagged_only", false)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		keepLatest, err := OptionalIntParam(args, "keep_latest")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		maxDeletions, err := OptionalIntParamWithDefault(args, "max_deletions", defaultPackageCleanupMaxDeletions)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		dryRun, err := OptionalBoolParamWithDefault(args, "dry_run", false)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		if olderThanDays < 0 || keepLatest < 0 {
+			return utils.NewToolResultError("older_than_days and keep_latest cannot be negative"), nil, nil
+		}
+		if maxDeletions < 1 || maxDeletions > maxPackageCleanupMaxDeletions {
+			return utils.NewToolResultError(fmt.Sprintf("max_deletions must be between 1 and %d", maxPackageCleanupMaxDeletions)), nil, nil
+		}
+		if untaggedOnly && ref.packageType != "container" {
+			return utils.NewToolResultError("untagged_only only applies to container packages"), nil, nil
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		// Versions are listed newest first, so keep_latest keeps the head of the list
+		var versions []*github.PackageVersion
+		opts := &github.PackageListOptions{State: github.Ptr("active"), ListOptions: github.ListOptions{PerPage: 100}}
+		for {
+			page, resp, err := listPackageVersions(ctx, client, ref, opts)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to list package versions", resp, err), nil, nil
+			}
+			_ = resp.Body.Close()
+			versions = append(versions, page...)
+			if resp.NextPage == 0 {
+				break
+			}
+			opts.Page = resp.NextPage
+		}
+
+		cutoff := time.Now().AddDate(0, 0, -olderThanDays)
+		report := PackageCleanupReport{DryRun: dryRun, Deleted: []MinimalPackageVersion{}}
+		for i, version := range versions {
+			updated := version.GetUpdatedAt().Time
+			if updated.IsZero() {
+				updated = version.GetCreatedAt().Time
+			}
+			if i < keepLatest || !updated.Before(cutoff) || (untaggedOnly && len(packageVersionTags(version)) > 0) {
+				report.Kept++
+				continue
+			}
+			if len(report.Deleted)+len(report.Failed) == maxDeletions {
+				report.Truncated = true
+				report.Kept++
+				continue
+			}
+			if !dryRun {
+				resp, err := deletePackageVersion(ctx, client, ref, version.GetID())
+				if err != nil {
+					report.Failed = append(report.Failed, PackageCleanupFailure{ID: version.GetID(), Name: version.GetName(), Error: err.Error()})
+					continue
+				}
+				_ = resp.Body.Close()
+			}
+			report.Deleted = append(report.Deleted, convertToMinimalPackageVersion(version))
+		}
+
+		return MarshalledTextResult(report), nil, nil
+	})
+
+	return tool, handler
+}
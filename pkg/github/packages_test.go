@@ -0,0 +1,320 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/github/github-mcp-server/internal/githubv4mock"
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v79/github"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func containerVersion(id int64, age time.Duration, tags ...string) *github.PackageVersion {
+	metadata, _ := json.Marshal(map[string]any{
+		"package_type": "container",
+		"container":    map[string]any{"tags": tags},
+	})
+	return &github.PackageVersion{
+		ID:        github.Ptr(id),
+		Name:      github.Ptr(fmt.Sprintf("sha256:%d", id)),
+		UpdatedAt: &github.Timestamp{Time: time.Now().Add(-age)},
+		Metadata:  metadata,
+	}
+}
+
+func Test_ListPackages(t *testing.T) {
+	tool, _ := ListPackages(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.True(t, tool.Annotations.ReadOnlyHint)
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.GetOrgsPackagesByOrg,
+			expectPath(t, "/orgs/octo-org/packages").andThen(
+				expectQueryParams(t, map[string]string{"package_type": "container", "visibility": "private", "page": "1", "per_page": "30"}).andThen(
+					mockResponse(t, http.StatusOK, []*github.Package{{
+						ID:           github.Ptr(int64(1)),
+						Name:         github.Ptr("app"),
+						PackageType:  github.Ptr("container"),
+						Visibility:   github.Ptr("private"),
+						VersionCount: github.Ptr(int64(12)),
+						Repository:   &github.Repository{FullName: github.Ptr("octo-org/app")},
+					}}),
+				),
+			),
+		),
+	)
+	_, handler := ListPackages(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+	args := map[string]any{"owner_type": "org", "owner": "octo-org", "package_type": "container", "visibility": "private"}
+	request := createMCPRequest(args)
+	result, _, err := handler(context.Background(), &request, args)
+	require.NoError(t, err)
+	require.False(t, result.IsError, getTextResult(t, result).Text)
+
+	var packages []MinimalPackage
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &packages))
+	assert.Equal(t, []MinimalPackage{{ID: 1, Name: "app", PackageType: "container", Visibility: "private", Repository: "octo-org/app", VersionCount: 12}}, packages)
+}
+
+func Test_ListPackageVersions(t *testing.T) {
+	tool, _ := ListPackageVersions(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.True(t, tool.Annotations.ReadOnlyHint)
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.GetUsersPackagesVersionsByUsernameByPackageTypeByPackageName,
+			expectPath(t, "/users/octocat/packages/container/app/versions").andThen(
+				expectQueryParams(t, map[string]string{"state": "deleted", "page": "1", "per_page": "30"}).andThen(
+					mockResponse(t, http.StatusOK, []*github.PackageVersion{containerVersion(1, time.Hour, "latest", "v1")}),
+				),
+			),
+		),
+	)
+	_, handler := ListPackageVersions(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+	args := map[string]any{"owner_type": "user", "owner": "octocat", "package_type": "container", "package_name": "app", "state": "deleted"}
+	request := createMCPRequest(args)
+	result, _, err := handler(context.Background(), &request, args)
+	require.NoError(t, err)
+	require.False(t, result.IsError, getTextResult(t, result).Text)
+
+	var versions []MinimalPackageVersion
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &versions))
+	require.Len(t, versions, 1)
+	assert.Equal(t, int64(1), versions[0].ID)
+	assert.Equal(t, []string{"latest", "v1"}, versions[0].Tags)
+}
+
+func Test_GetPackageDownloadStats(t *testing.T) {
+	tool, _ := GetPackageDownloadStats(stubGetGQLClientFn(githubv4.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.True(t, tool.Annotations.ReadOnlyHint)
+
+	t.Run("org package", func(t *testing.T) {
+		matcher := githubv4mock.NewQueryMatcher(
+			"query($first:Int!$names:[String!]!$owner:String!$packageType:PackageType!){organization(login: $owner){packages(first: 1, names: $names, packageType: $packageType){nodes{name,packageType,statistics{downloadsTotalCount},versions(first: $first, orderBy: {field: CREATED_AT, direction: DESC}){nodes{version,statistics{downloadsTotalCount}}}}}}}",
+			map[string]any{
+				"owner":       "octo-org",
+				"names":       []any{"lib"},
+				"packageType": "NPM",
+				"first":       2,
+			},
+			githubv4mock.DataResponse(map[string]any{
+				"organization": map[string]any{
+					"packages": map[string]any{
+						"nodes": []any{map[string]any{
+							"name":        "lib",
+							"packageType": "NPM",
+							"statistics":  map[string]any{"downloadsTotalCount": 42},
+							"versions": map[string]any{
+								"nodes": []any{
+									map[string]any{"version": "1.1.0", "statistics": map[string]any{"downloadsTotalCount": 10}},
+									map[string]any{"version": "1.0.0", "statistics": map[string]any{"downloadsTotalCount": 32}},
+								},
+							},
+						}},
+					},
+				},
+			}),
+		)
+		_, handler := GetPackageDownloadStats(stubGetGQLClientFn(githubv4.NewClient(githubv4mock.NewMockedHTTPClient(matcher))), translations.NullTranslationHelper)
+
+		args := map[string]any{"owner_type": "org", "owner": "octo-org", "package_type": "npm", "package_name": "lib", "versions": float64(2)}
+		request := createMCPRequest(args)
+		result, _, err := handler(context.Background(), &request, args)
+		require.NoError(t, err)
+		require.False(t, result.IsError, getTextResult(t, result).Text)
+
+		var stats PackageDownloadStats
+		require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &stats))
+		assert.Equal(t, PackageDownloadStats{
+			Name:           "lib",
+			PackageType:    "npm",
+			TotalDownloads: 42,
+			Versions:       []PackageVersionDownloadStats{{Version: "1.1.0", Downloads: 10}, {Version: "1.0.0", Downloads: 32}},
+		}, stats)
+	})
+
+	t.Run("container package", func(t *testing.T) {
+		_, handler := GetPackageDownloadStats(stubGetGQLClientFn(githubv4.NewClient(nil)), translations.NullTranslationHelper)
+
+		args := map[string]any{"owner_type": "org", "owner": "octo-org", "package_type": "container", "package_name": "app"}
+		request := createMCPRequest(args)
+		result, _, err := handler(context.Background(), &request, args)
+		require.NoError(t, err)
+		require.True(t, result.IsError)
+		assert.Contains(t, getErrorResult(t, result).Text, "not available for container images")
+	})
+}
+
+func Test_DeletePackageVersion(t *testing.T) {
+	tool, _ := DeletePackageVersion(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.True(t, *tool.Annotations.DestructiveHint)
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.DeleteOrgsPackagesVersionsByOrgByPackageTypeByPackageNameByPackageVersionId,
+			expectPath(t, "/orgs/octo-org/packages/container/app/versions/7").andThen(
+				http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusNoContent) }),
+			),
+		),
+	)
+	_, handler := DeletePackageVersion(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+	args := map[string]any{"owner_type": "org", "owner": "octo-org", "package_type": "container", "package_name": "app", "version_id": float64(7)}
+	request := createMCPRequest(args)
+	result, _, err := handler(context.Background(), &request, args)
+	require.NoError(t, err)
+	require.False(t, result.IsError, getTextResult(t, result).Text)
+	assert.Equal(t, "Deleted version 7 of container package app", getTextResult(t, result).Text)
+}
+
+func Test_RestorePackageVersion(t *testing.T) {
+	tool, _ := RestorePackageVersion(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.PostUsersPackagesVersionsRestoreByUsernameByPackageTypeByPackageNameByPackageVersionId,
+			expectPath(t, "/users/octocat/packages/npm/lib/versions/7/restore").andThen(
+				http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusNoContent) }),
+			),
+		),
+	)
+	_, handler := RestorePackageVersion(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+	args := map[string]any{"owner_type": "user", "owner": "octocat", "package_type": "npm", "package_name": "lib", "version_id": float64(7)}
+	request := createMCPRequest(args)
+	result, _, err := handler(context.Background(), &request, args)
+	require.NoError(t, err)
+	require.False(t, result.IsError, getTextResult(t, result).Text)
+	assert.Equal(t, "Restored version 7 of npm package lib", getTextResult(t, result).Text)
+}
+
+func Test_CleanupPackageVersions(t *testing.T) {
+	tool, _ := CleanupPackageVersions(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.True(t, *tool.Annotations.DestructiveHint)
+
+	day := 24 * time.Hour
+	versions := []*github.PackageVersion{
+		containerVersion(1, 40*day),
+		containerVersion(2, 40*day, "v2"),
+		containerVersion(3, 5*day),
+		containerVersion(4, 50*day),
+		containerVersion(5, 60*day),
+	}
+
+	tests := []struct {
+		name          string
+		args          map[string]any
+		expectDeleted []int64
+		expectKept    int
+		expectTrunc   bool
+		expectError   string
+	}{
+		{
+			name:          "untagged versions older than 30 days",
+			args:          map[string]any{"older_than_days": float64(30), "untagged_only": true},
+			expectDeleted: []int64{1, 4, 5},
+			expectKept:    2,
+		},
+		{
+			name:          "keep latest",
+			args:          map[string]any{"older_than_days": float64(30), "keep_latest": float64(2)},
+			expectDeleted: []int64{4, 5},
+			expectKept:    3,
+		},
+		{
+			name:          "max deletions",
+			args:          map[string]any{"older_than_days": float64(30), "untagged_only": true, "max_deletions": float64(2)},
+			expectDeleted: []int64{1, 4},
+			expectKept:    3,
+			expectTrunc:   true,
+		},
+		{
+			name:          "dry run",
+			args:          map[string]any{"older_than_days": float64(0), "dry_run": true},
+			expectDeleted: []int64{1, 2, 3, 4, 5},
+		},
+		{
+			name:        "missing age",
+			args:        map[string]any{},
+			expectError: "missing required parameter: older_than_days",
+		},
+		{
+			name:        "untagged only for npm",
+			args:        map[string]any{"older_than_days": float64(30), "untagged_only": true, "package_type": "npm"},
+			expectError: "untagged_only only applies to container packages",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var deleted []string
+			mockedClient := mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.GetOrgsPackagesVersionsByOrgByPackageTypeByPackageName,
+					expectQueryParams(t, map[string]string{"state": "active", "per_page": "100"}).andThen(
+						mockResponse(t, http.StatusOK, versions),
+					),
+				),
+				mock.WithRequestMatchHandler(
+					mock.DeleteOrgsPackagesVersionsByOrgByPackageTypeByPackageNameByPackageVersionId,
+					http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						mu.Lock()
+						deleted = append(deleted, r.URL.Path)
+						mu.Unlock()
+						w.WriteHeader(http.StatusNoContent)
+					}),
+				),
+			)
+			_, handler := CleanupPackageVersions(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+			args := map[string]any{"owner_type": "org", "owner": "octo-org", "package_type": "container", "package_name": "app"}
+			for k, v := range tc.args {
+				args[k] = v
+			}
+			request := createMCPRequest(args)
+			result, _, err := handler(context.Background(), &request, args)
+			require.NoError(t, err)
+
+			if tc.expectError != "" {
+				require.True(t, result.IsError)
+				assert.Contains(t, getErrorResult(t, result).Text, tc.expectError)
+				return
+			}
+			require.False(t, result.IsError, getTextResult(t, result).Text)
+
+			var report PackageCleanupReport
+			require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &report))
+			var deletedIDs []int64
+			for _, version := range report.Deleted {
+				deletedIDs = append(deletedIDs, version.ID)
+			}
+			assert.Equal(t, tc.expectDeleted, deletedIDs)
+			assert.Equal(t, tc.expectKept, report.Kept)
+			assert.Equal(t, tc.expectTrunc, report.Truncated)
+
+			if report.DryRun {
+				assert.Empty(t, deleted)
+			} else {
+				assert.Len(t, deleted, len(tc.expectDeleted))
+			}
+		})
+	}
+}
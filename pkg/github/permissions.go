@@ -0,0 +1,404 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/github/github-mcp-server/pkg/accounts"
+	"github.com/github/github-mcp-server/pkg/ghapp"
+	"github.com/github/github-mcp-server/pkg/toolsets"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/github/github-mcp-server/pkg/utils"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// VerifyAccessToolName is the name of the tool reporting the permissions of the token.
+const VerifyAccessToolName = "verify_access"
+
+// tokenGrantsTTL is how long the scopes of a token are trusted before they are read again
+const tokenGrantsTTL = 5 * time.Minute
+
+// Token kinds, as reported by verify_access
+const (
+	// TokenScopes is a classic personal access token or OAuth app token, granted OAuth scopes
+	TokenScopes = "scopes"
+	// TokenInstallation is a GitHub App installation token, granted permissions
+	TokenInstallation = "installation"
+	// TokenUnknown is a token whose grants GitHub does not report, such as a fine-grained
+	// personal access token
+	TokenUnknown = "unknown"
+)
+
+// Permission is a fine-grained permission, such as contents:write.
+type Permission struct {
+	Name  string
+	Level string
+}
+
+func (p Permission) String() string {
+	return p.Name + ":" + p.Level
+}
+
+// permissionLevels orders the levels of permissions, each including the ones below it.
+var permissionLevels = map[string]int{"read": 1, "write": 2, "admin": 3}
+
+func readAccess(name string) Permission  { return Permission{Name: name, Level: "read"} }
+func writeAccess(name string) Permission { return Permission{Name: name, Level: "write"} }
+
+// toolsetPermissions are the fine-grained permissions covering the resources of each toolset.
+// Their read tools need read access and their write tools write access. Toolsets not listed
+// need only the metadata that every token can read.
+var toolsetPermissions = map[string]string{
+	ToolsetMetadataRepos.ID:              "contents",
+	ToolsetMetadataGit.ID:                "contents",
+	ToolsetMetadataBulkOps.ID:            "contents",
+	ToolsetMetadataIssues.ID:             "issues",
+	ToolsetLabels.ID:                     "issues",
+	ToolsetMetadataPullRequests.ID:       "pull_requests",
+	ToolsetMetadataOrgs.ID:               "members",
+	ToolsetMetadataActions.ID:            "actions",
+	ToolsetMetadataCodeSecurity.ID:       "security_events",
+	ToolsetMetadataSecretProtection.ID:   "secret_scanning_alerts",
+	ToolsetMetadataDependabot.ID:         "vulnerability_alerts",
+	ToolsetMetadataNotifications.ID:      "notifications",
+	ToolsetMetadataDiscussions.ID:        "discussions",
+	ToolsetMetadataGists.ID:              "gists",
+	ToolsetMetadataSecurityAdvisories.ID: "repository_advisories",
+	ToolsetMetadataProjects.ID:           "organization_projects",
+	ToolsetMetadataWebhooks.ID:           "repository_hooks",
+	ToolsetMetadataDeployments.ID:        "deployments",
+	ToolsetMetadataPackages.ID:           "packages",
+}
+
+// toolPermissions are the permissions of the tools that need other ones than their toolset's.
+// An empty list means the tool needs only metadata.
+var toolPermissions = map[string][]Permission{
+	"search_repositories":             {},
+	"list_forks":                      {},
+	"list_repository_rulesets":        {},
+	"get_repository_ruleset":          {},
+	"set_repository_ruleset":          {writeAccess("administration")},
+	"create_repository":               {writeAccess("administration")},
+	"fork_repository":                 {writeAccess("administration"), readAccess("contents")},
+	"update_repository":               {writeAccess("administration")},
+	"transfer_repository":             {writeAccess("administration")},
+	"initialize_repository":           {writeAccess("administration"), writeAccess("contents")},
+	"get_branch_protection":           {readAccess("administration")},
+	"update_branch_protection":        {writeAccess("administration")},
+	"list_deploy_keys":                {readAccess("administration")},
+	"create_deploy_key":               {writeAccess("administration")},
+	"delete_deploy_key":               {writeAccess("administration")},
+	"list_invitations":                {readAccess("administration")},
+	"add_collaborator":                {writeAccess("administration")},
+	"remove_collaborator":             {writeAccess("administration")},
+	"cancel_invitation":               {writeAccess("administration")},
+	"get_push_limits":                 {},
+	"search_issues":                   {},
+	"search_pull_requests":            {},
+	"merge_pull_request":              {writeAccess("contents")},
+	"backport_pull_request":           {writeAccess("contents"), writeAccess("pull_requests")},
+	"create_check_run":                {writeAccess("checks")},
+	"update_check_run":                {writeAccess("checks")},
+	"create_commit_status":            {writeAccess("statuses")},
+	"list_actions_variables":          {readAccess("actions_variables")},
+	"set_actions_variable":            {writeAccess("actions_variables")},
+	"delete_actions_variable":         {writeAccess("actions_variables")},
+	"set_actions_secret":              {writeAccess("secrets")},
+	"generate_dependabot_config":      {readAccess("contents"), writeAccess("contents")},
+	"list_environments":               {readAccess("actions")},
+	"get_environment":                 {readAccess("actions")},
+	"set_environment_protection":      {writeAccess("administration")},
+	"search_orgs":                     {},
+	"discover_repositories":           {},
+	"set_repository_permission":       {writeAccess("administration")},
+	"get_copilot_metrics":             {readAccess("organization_copilot_seat_management")},
+	"get_copilot_seat_usage":          {readAccess("organization_copilot_seat_management")},
+	"add_copilot_seats":               {writeAccess("organization_copilot_seat_management")},
+	"remove_copilot_seats":            {writeAccess("organization_copilot_seat_management")},
+	"get_teams":                       {readAccess("members")},
+	"get_team_members":                {readAccess("members")},
+	"get_global_security_advisory":    {},
+	"list_global_security_advisories": {},
+}
+
+// classicScopes are the OAuth scopes of classic tokens granting each permission, any of them
+// sufficing. Permissions not listed need no scope on public repositories, so they are not
+// checked: GitHub reports a missing scope for private ones.
+var classicScopes = map[Permission][]string{
+	writeAccess("contents"):                             {"repo", "public_repo"},
+	writeAccess("issues"):                               {"repo", "public_repo"},
+	writeAccess("pull_requests"):                        {"repo", "public_repo"},
+	writeAccess("actions"):                              {"repo", "public_repo"},
+	writeAccess("checks"):                               {"repo", "public_repo"},
+	writeAccess("statuses"):                             {"repo", "repo:status", "public_repo"},
+	writeAccess("deployments"):                          {"repo", "repo_deployment", "public_repo"},
+	writeAccess("administration"):                       {"repo", "public_repo"},
+	writeAccess("actions_variables"):                    {"repo", "public_repo"},
+	writeAccess("secrets"):                              {"repo", "public_repo"},
+	writeAccess("discussions"):                          {"repo", "public_repo", "write:discussion"},
+	readAccess("security_events"):                       {"repo", "public_repo", "security_events"},
+	writeAccess("security_events"):                      {"repo", "public_repo", "security_events"},
+	readAccess("secret_scanning_alerts"):                {"repo", "public_repo", "security_events"},
+	writeAccess("secret_scanning_alerts"):               {"repo", "public_repo", "security_events"},
+	readAccess("vulnerability_alerts"):                  {"repo", "public_repo", "security_events"},
+	writeAccess("vulnerability_alerts"):                 {"repo", "public_repo", "security_events"},
+	readAccess("notifications"):                         {"notifications", "repo"},
+	writeAccess("notifications"):                        {"notifications", "repo"},
+	writeAccess("gists"):                                {"gist"},
+	readAccess("members"):                               {"read:org", "write:org", "admin:org"},
+	writeAccess("members"):                              {"write:org", "admin:org"},
+	readAccess("organization_projects"):                 {"read:project", "project"},
+	writeAccess("organization_projects"):                {"project"},
+	readAccess("packages"):                              {"read:packages", "write:packages", "delete:packages"},
+	writeAccess("packages"):                             {"write:packages", "delete:packages"},
+	readAccess("repository_hooks"):                      {"read:repo_hook", "write:repo_hook", "admin:repo_hook", "repo"},
+	writeAccess("repository_hooks"):                     {"write:repo_hook", "admin:repo_hook", "repo"},
+	readAccess("organization_copilot_seat_management"):  {"manage_billing:copilot", "read:org", "admin:org"},
+	writeAccess("organization_copilot_seat_management"): {"manage_billing:copilot", "admin:org"},
+}
+
+// ToolPermissions returns the fine-grained permissions that tool, of toolset, needs.
+func ToolPermissions(toolset string, tool mcp.Tool) []Permission {
+	if permissions, ok := toolPermissions[tool.Name]; ok {
+		return permissions
+	}
+	name, ok := toolsetPermissions[toolset]
+	if !ok {
+		return nil
+	}
+	if tool.Annotations != nil && tool.Annotations.ReadOnlyHint {
+		return []Permission{readAccess(name)}
+	}
+	return []Permission{writeAccess(name)}
+}
+
+// TokenGrants are what the token of the server was granted.
+type TokenGrants struct {
+	// Kind is scopes, installation or unknown
+	Kind string `json:"kind"`
+	// Scopes are the OAuth scopes of a classic token
+	Scopes []string `json:"scopes,omitempty"`
+	// Installation is the account of the GitHub App installation the token is for
+	Installation string `json:"installation,omitempty"`
+	// Permissions are the permissions of a GitHub App installation token
+	Permissions map[string]string `json:"permissions,omitempty"`
+}
+
+// Missing returns the permissions of required that g does not grant. Tokens of unknown kind
+// are assumed to have every permission.
+func (g TokenGrants) Missing(required []Permission) []Permission {
+	var missing []Permission
+	for _, p := range required {
+		switch g.Kind {
+		case TokenScopes:
+			scopes, ok := classicScopes[p]
+			if ok && !slices.ContainsFunc(scopes, func(scope string) bool { return slices.Contains(g.Scopes, scope) }) {
+				missing = append(missing, p)
+			}
+		case TokenInstallation:
+			if permissionLevels[g.Permissions[p.Name]] < permissionLevels[p.Level] {
+				missing = append(missing, p)
+			}
+		}
+	}
+	return missing
+}
+
+// LacksPermissionsError returns the error of a call to tool with a token that lacks the
+// missing permissions.
+func LacksPermissionsError(tool string, missing []Permission) string {
+	return fmt.Sprintf("token lacks %s, which %s requires. Call %s to see the permissions of the token", strings.Join(permissionNames(missing), ", "), tool, VerifyAccessToolName)
+}
+
+type cachedGrants struct {
+	grants    TokenGrants
+	expiresAt time.Time
+}
+
+// PermissionChecker reads what the tokens of the server were granted, caching the scopes of
+// each account's token.
+type PermissionChecker struct {
+	getClient GetClientFn
+
+	mu     sync.Mutex
+	grants map[string]cachedGrants
+}
+
+// NewPermissionChecker returns a checker reading grants with the clients of getClient.
+func NewPermissionChecker(getClient GetClientFn) *PermissionChecker {
+	return &PermissionChecker{getClient: getClient, grants: make(map[string]cachedGrants)}
+}
+
+// Grants returns what the token used for the requests of ctx to owner was granted. The
+// GitHub App installation on owner is used when the server authenticates as a GitHub App.
+func (c *PermissionChecker) Grants(ctx context.Context, owner string) (TokenGrants, error) {
+	account := accounts.AccountFromContext(ctx)
+	if app, ok := ghapp.FromContext(ctx); ok && account == accounts.Default {
+		installation, err := app.Installation(ctx, owner)
+		if err != nil {
+			return TokenGrants{}, err
+		}
+		return TokenGrants{Kind: TokenInstallation, Installation: installation.Account, Permissions: installation.Permissions}, nil
+	}
+
+	c.mu.Lock()
+	cached, ok := c.grants[account]
+	c.mu.Unlock()
+	if ok && time.Now().Before(cached.expiresAt) {
+		return cached.grants, nil
+	}
+
+	client, err := c.getClient(ctx)
+	if err != nil {
+		return TokenGrants{}, fmt.Errorf("failed to get GitHub client: %w", err)
+	}
+	_, resp, err := client.Users.Get(ctx, "")
+	if resp != nil {
+		_ = resp.Body.Close()
+	}
+	if err != nil {
+		return TokenGrants{}, fmt.Errorf("failed to get the authenticated user: %w", err)
+	}
+	// Only classic tokens and OAuth app tokens list their scopes
+	grants := TokenGrants{Kind: TokenUnknown}
+	if header := resp.Header.Get("X-OAuth-Scopes"); header != "" {
+		grants.Kind = TokenScopes
+		grants.Scopes = []string{}
+		for _, scope := range strings.Split(header, ",") {
+			if scope = strings.TrimSpace(scope); scope != "" {
+				grants.Scopes = append(grants.Scopes, scope)
+			}
+		}
+	}
+
+	c.mu.Lock()
+	c.grants[account] = cachedGrants{grants: grants, expiresAt: time.Now().Add(tokenGrantsTTL)}
+	c.mu.Unlock()
+	return grants, nil
+}
+
+type permissionCheckerKey struct{}
+
+// ContextWithPermissionChecker returns a context carrying checker, for the verify_access tool.
+func ContextWithPermissionChecker(ctx context.Context, checker *PermissionChecker) context.Context {
+	return context.WithValue(ctx, permissionCheckerKey{}, checker)
+}
+
+// PermissionCheckerFromContext returns the PermissionChecker carried by ctx, if any.
+func PermissionCheckerFromContext(ctx context.Context) (*PermissionChecker, bool) {
+	checker, ok := ctx.Value(permissionCheckerKey{}).(*PermissionChecker)
+	return checker, ok
+}
+
+// ToolAccess reports the permissions a tool needs that the token lacks.
+type ToolAccess struct {
+	Tool     string   `json:"tool"`
+	Required []string `json:"required"`
+	Missing  []string `json:"missing"`
+}
+
+// AccessReport is the result of verify_access.
+type AccessReport struct {
+	Token TokenGrants `json:"token"`
+	// Checked is the number of tools checked
+	Checked int `json:"checked"`
+	// Denied are the checked tools whose calls would fail for lack of permissions
+	Denied []ToolAccess `json:"denied"`
+	Note   string       `json:"note,omitempty"`
+}
+
+// VerifyAccess creates a tool to check that the token has the permissions tools need, before
+// calling them.
+func VerifyAccess(getClient GetClientFn, toolsetGroup *toolsets.ToolsetGroup, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        VerifyAccessToolName,
+		Description: t("TOOL_VERIFY_ACCESS_DESCRIPTION", "Check that the GitHub token of this server has the permissions tools need before calling them. Reports the OAuth scopes of a classic token or the permissions of a GitHub App installation, and the tools whose calls would fail for lack of a permission, such as contents:write. GitHub does not report the permissions of fine-grained personal access tokens, so no tool is denied for them."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_VERIFY_ACCESS_USER_TITLE", "Verify token access"),
+			ReadOnlyHint: true,
+		},
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"tools": {
+					Type:        "array",
+					Description: "Names of the tools to check. Checks the tools of the enabled toolsets when omitted.",
+					Items:       &jsonschema.Schema{Type: "string"},
+				},
+				"owner": {
+					Type:        "string",
+					Description: "Repository owner, organization or user the tools would act on. Decides the GitHub App installation whose permissions are checked.",
+				},
+			},
+		},
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		names, err := OptionalStringArrayParam(args, "tools")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		owner, err := OptionalParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		type checkedTool struct {
+			name     string
+			required []Permission
+		}
+		var checked []checkedTool
+		if len(names) == 0 {
+			for toolsetName, toolset := range toolsetGroup.Toolsets {
+				for _, serverTool := range toolset.GetActiveTools() {
+					checked = append(checked, checkedTool{serverTool.Tool.Name, ToolPermissions(toolsetName, serverTool.Tool)})
+				}
+			}
+		}
+		for _, name := range names {
+			serverTool, toolsetName, err := toolsetGroup.FindToolByName(name)
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			checked = append(checked, checkedTool{name, ToolPermissions(toolsetName, serverTool.Tool)})
+		}
+
+		checker, ok := PermissionCheckerFromContext(ctx)
+		if !ok {
+			checker = NewPermissionChecker(getClient)
+		}
+		grants, err := checker.Grants(ctx, owner)
+		if err != nil {
+			return utils.NewToolResultErrorFromErr("failed to get the permissions of the token", err), nil, nil
+		}
+
+		report := AccessReport{Token: grants, Checked: len(checked), Denied: []ToolAccess{}}
+		for _, c := range checked {
+			missing := grants.Missing(c.required)
+			if len(missing) == 0 {
+				continue
+			}
+			report.Denied = append(report.Denied, ToolAccess{Tool: c.name, Required: permissionNames(c.required), Missing: permissionNames(missing)})
+		}
+		slices.SortFunc(report.Denied, func(a, b ToolAccess) int { return strings.Compare(a.Tool, b.Tool) })
+		if grants.Kind == TokenUnknown {
+			report.Note = "GitHub does not report the permissions of this token, as for fine-grained personal access tokens: calls fail with 403 or 404 when it lacks one"
+		}
+		return MarshalledTextResult(report), nil, nil
+	})
+
+	return tool, handler
+}
+
+func permissionNames(permissions []Permission) []string {
+	names := make([]string, 0, len(permissions))
+	for _, p := range permissions {
+		names = append(names, p.String())
+	}
+	return names
+}
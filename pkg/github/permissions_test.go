@@ -0,0 +1,107 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v79/github"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ToolPermissions(t *testing.T) {
+	tsg := DefaultToolsetGroup(false, nil, nil, nil, translations.NullTranslationHelper, 5000, FeatureFlags{}, nil)
+	for toolset := range toolsetPermissions {
+		assert.Contains(t, tsg.Toolsets, toolset)
+	}
+	for name := range toolPermissions {
+		_, _, err := tsg.FindToolByName(name)
+		assert.NoError(t, err)
+	}
+
+	readOnly := mcp.Tool{Name: "get_file_contents", Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true}}
+	assert.Equal(t, []Permission{{Name: "contents", Level: "read"}}, ToolPermissions(ToolsetMetadataRepos.ID, readOnly))
+	assert.Equal(t, []Permission{{Name: "contents", Level: "write"}}, ToolPermissions(ToolsetMetadataRepos.ID, mcp.Tool{Name: "push_files"}))
+	assert.Equal(t, []Permission{{Name: "administration", Level: "write"}}, ToolPermissions(ToolsetMetadataRepos.ID, mcp.Tool{Name: "update_branch_protection"}))
+	assert.Empty(t, ToolPermissions(ToolsetMetadataRepos.ID, mcp.Tool{Name: "search_repositories"}))
+	assert.Empty(t, ToolPermissions(ToolsetMetadataContext.ID, mcp.Tool{Name: "get_me"}))
+}
+
+func Test_TokenGrants_Missing(t *testing.T) {
+	required := []Permission{writeAccess("contents"), readAccess("notifications"), readAccess("issues")}
+
+	assert.Empty(t, TokenGrants{Kind: TokenScopes, Scopes: []string{"repo"}}.Missing(required))
+	assert.Equal(t, []Permission{readAccess("notifications")}, TokenGrants{Kind: TokenScopes, Scopes: []string{"public_repo"}}.Missing(required))
+	assert.Equal(t, []Permission{writeAccess("contents"), readAccess("notifications")}, TokenGrants{Kind: TokenScopes, Scopes: []string{}}.Missing(required))
+
+	installation := TokenGrants{Kind: TokenInstallation, Permissions: map[string]string{"contents": "read", "issues": "write"}}
+	assert.Equal(t, []Permission{writeAccess("contents"), readAccess("notifications")}, installation.Missing(required))
+	installation.Permissions["contents"] = "admin"
+	assert.Equal(t, []Permission{readAccess("notifications")}, installation.Missing(required))
+
+	assert.Empty(t, TokenGrants{Kind: TokenUnknown}.Missing(required))
+}
+
+func Test_VerifyAccess(t *testing.T) {
+	scopes := "public_repo, read:org"
+	requests := 0
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(mock.GetUser, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			requests++
+			if scopes != "" {
+				w.Header().Set("X-OAuth-Scopes", scopes)
+			}
+			_, _ = w.Write([]byte(`{"login": "octocat"}`))
+		})),
+	)
+	getClient := stubGetClientFn(github.NewClient(mockedClient))
+	tsg := DefaultToolsetGroup(false, getClient, nil, nil, translations.NullTranslationHelper, 5000, FeatureFlags{}, nil)
+	require.NoError(t, tsg.EnableToolsets([]string{ToolsetMetadataNotifications.ID}, nil))
+
+	tool, handler := VerifyAccess(getClient, tsg, translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.True(t, tool.Annotations.ReadOnlyHint)
+
+	ctx := ContextWithPermissionChecker(context.Background(), NewPermissionChecker(getClient))
+	call := func(args map[string]any) AccessReport {
+		t.Helper()
+		request := createMCPRequest(args)
+		result, _, err := handler(ctx, &request, args)
+		require.NoError(t, err)
+		require.False(t, result.IsError, getTextResult(t, result).Text)
+		var report AccessReport
+		require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &report))
+		return report
+	}
+
+	// The tools of the enabled toolsets are checked by default
+	report := call(map[string]any{})
+	assert.Equal(t, TokenGrants{Kind: TokenScopes, Scopes: []string{"public_repo", "read:org"}}, report.Token)
+	assert.Equal(t, 7, report.Checked)
+	require.Len(t, report.Denied, 7)
+	assert.Equal(t, ToolAccess{Tool: "dismiss_notification", Required: []string{"notifications:write"}, Missing: []string{"notifications:write"}}, report.Denied[0])
+
+	report = call(map[string]any{"tools": []any{"create_or_update_file", "update_gist", "get_teams"}})
+	assert.Equal(t, 3, report.Checked)
+	assert.Equal(t, []ToolAccess{{Tool: "update_gist", Required: []string{"gists:write"}, Missing: []string{"gists:write"}}}, report.Denied)
+	assert.Equal(t, 1, requests, "the scopes of the token are cached")
+
+	request := createMCPRequest(map[string]any{"tools": []any{"no_such_tool"}})
+	result, _, err := handler(ctx, &request, map[string]any{"tools": []any{"no_such_tool"}})
+	require.NoError(t, err)
+	assert.Contains(t, getErrorResult(t, result).Text, "no_such_tool")
+
+	// Tokens that do not list their scopes, like fine-grained ones, are assumed to have every permission
+	scopes = ""
+	ctx = context.Background()
+	report = call(map[string]any{})
+	assert.Equal(t, TokenUnknown, report.Token.Kind)
+	assert.Empty(t, report.Denied)
+	assert.NotEmpty(t, report.Note)
+}
@@ -0,0 +1,424 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	ghErrors "github.com/github/github-mcp-server/pkg/errors"
+	"github.com/github/github-mcp-server/pkg/ratelimit"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/github/github-mcp-server/pkg/utils"
+	"github.com/google/go-github/v79/github"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/shurcooL/githubv4"
+)
+
+// projectsGraphQLLimiters pace the GraphQL calls of the project field tools, per token or
+// installation. The REST API cannot resolve fields, options and iterations by name, so these
+// tools spend GraphQL points.
+var projectsGraphQLLimiters = ratelimit.NewRegistry(nil, ratelimit.DefaultIdleTimeout)
+
+// ProjectItemFieldUpdate is the output type of set_project_item_field and move_project_item.
+type ProjectItemFieldUpdate struct {
+	ItemID   int64  `json:"item_id"`
+	Field    string `json:"field"`
+	DataType string `json:"data_type"`
+	Value    any    `json:"value,omitempty"`
+	Cleared  bool   `json:"cleared,omitempty"`
+}
+
+type projectV2Option struct {
+	ID   githubv4.String
+	Name githubv4.String
+}
+
+type projectV2Iteration struct {
+	ID    githubv4.String
+	Title githubv4.String
+}
+
+type projectV2FieldNode struct {
+	Common struct {
+		ID       githubv4.ID
+		Name     githubv4.String
+		DataType githubv4.String
+	} `graphql:"... on ProjectV2FieldCommon"`
+	SingleSelect struct {
+		Options []projectV2Option
+	} `graphql:"... on ProjectV2SingleSelectField"`
+	Iteration struct {
+		Configuration struct {
+			Iterations []projectV2Iteration
+		}
+	} `graphql:"... on ProjectV2IterationField"`
+}
+
+type projectV2WithFields struct {
+	ID     githubv4.ID
+	Fields struct {
+		Nodes []projectV2FieldNode
+	} `graphql:"fields(first: 100)"`
+}
+
+type orgProjectFieldsQuery struct {
+	Organization struct {
+		ProjectV2 projectV2WithFields `graphql:"projectV2(number: $number)"`
+	} `graphql:"organization(login: $owner)"`
+}
+
+type userProjectFieldsQuery struct {
+	User struct {
+		ProjectV2 projectV2WithFields `graphql:"projectV2(number: $number)"`
+	} `graphql:"user(login: $owner)"`
+}
+
+type updateProjectV2ItemFieldValueMutation struct {
+	UpdateProjectV2ItemFieldValue struct {
+		ProjectV2Item struct {
+			ID githubv4.ID
+		}
+	} `graphql:"updateProjectV2ItemFieldValue(input: $input)"`
+}
+
+type clearProjectV2ItemFieldValueMutation struct {
+	ClearProjectV2ItemFieldValue struct {
+		ProjectV2Item struct {
+			ID githubv4.ID
+		}
+	} `graphql:"clearProjectV2ItemFieldValue(input: $input)"`
+}
+
+// queryProjectFields fetches the node ID and the fields of a project.
+func queryProjectFields(ctx context.Context, client *githubv4.Client, ownerType, owner string, projectNumber int) (*projectV2WithFields, error) {
+	vars := map[string]any{
+		"owner":  githubv4.String(owner),
+		"number": githubv4.Int(int32(projectNumber)), // #nosec G115 - project numbers are small
+	}
+	if ownerType == "org" {
+		var query orgProjectFieldsQuery
+		if err := client.Query(ctx, &query, vars); err != nil {
+			return nil, err
+		}
+		return &query.Organization.ProjectV2, nil
+	}
+	var query userProjectFieldsQuery
+	if err := client.Query(ctx, &query, vars); err != nil {
+		return nil, err
+	}
+	return &query.User.ProjectV2, nil
+}
+
+// findProjectField returns the field of project called name, ignoring case.
+func findProjectField(project *projectV2WithFields, name string) (*projectV2FieldNode, error) {
+	names := make([]string, 0, len(project.Fields.Nodes))
+	for i := range project.Fields.Nodes {
+		field := &project.Fields.Nodes[i]
+		if strings.EqualFold(string(field.Common.Name), name) {
+			return field, nil
+		}
+		names = append(names, string(field.Common.Name))
+	}
+	return nil, fmt.Errorf("project has no field %q; available fields: %s", name, strings.Join(names, ", "))
+}
+
+// projectFieldValue converts value to the field value of field, resolving single select options
+// and iterations by name. It also returns the value to report back.
+func projectFieldValue(field *projectV2FieldNode, value any) (githubv4.ProjectV2FieldValue, any, error) {
+	name := string(field.Common.Name)
+	switch field.Common.DataType {
+	case "TEXT":
+		text, ok := value.(string)
+		if !ok {
+			return githubv4.ProjectV2FieldValue{}, nil, fmt.Errorf("field %q takes a text value", name)
+		}
+		return githubv4.ProjectV2FieldValue{Text: githubv4.NewString(githubv4.String(text))}, text, nil
+	case "NUMBER":
+		var number float64
+		switch v := value.(type) {
+		case float64:
+			number = v
+		case string:
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return githubv4.ProjectV2FieldValue{}, nil, fmt.Errorf("field %q takes a number, got %q", name, v)
+			}
+			number = parsed
+		default:
+			return githubv4.ProjectV2FieldValue{}, nil, fmt.Errorf("field %q takes a number", name)
+		}
+		return githubv4.ProjectV2FieldValue{Number: githubv4.NewFloat(githubv4.Float(number))}, number, nil
+	case "DATE":
+		text, _ := value.(string)
+		date, err := time.Parse("2006-01-02", text)
+		if err != nil {
+			return githubv4.ProjectV2FieldValue{}, nil, fmt.Errorf("field %q takes a date in YYYY-MM-DD format", name)
+		}
+		return githubv4.ProjectV2FieldValue{Date: githubv4.NewDate(githubv4.Date{Time: date})}, text, nil
+	case "SINGLE_SELECT":
+		text, _ := value.(string)
+		options := make([]string, 0, len(field.SingleSelect.Options))
+		for _, option := range field.SingleSelect.Options {
+			if strings.EqualFold(string(option.Name), text) || string(option.ID) == text {
+				return githubv4.ProjectV2FieldValue{SingleSelectOptionID: githubv4.NewString(option.ID)}, string(option.Name), nil
+			}
+			options = append(options, string(option.Name))
+		}
+		return githubv4.ProjectV2FieldValue{}, nil, fmt.Errorf("field %q has no option %q; available options: %s", name, text, strings.Join(options, ", "))
+	case "ITERATION":
+		text, _ := value.(string)
+		iterations := make([]string, 0, len(field.Iteration.Configuration.Iterations))
+		for _, iteration := range field.Iteration.Configuration.Iterations {
+			if strings.EqualFold(string(iteration.Title), text) || string(iteration.ID) == text {
+				return githubv4.ProjectV2FieldValue{IterationID: githubv4.NewString(iteration.ID)}, string(iteration.Title), nil
+			}
+			iterations = append(iterations, string(iteration.Title))
+		}
+		return githubv4.ProjectV2FieldValue{}, nil, fmt.Errorf("field %q has no active or upcoming iteration %q; available iterations: %s", name, text, strings.Join(iterations, ", "))
+	default:
+		return githubv4.ProjectV2FieldValue{}, nil, fmt.Errorf("field %q of type %s cannot be set on a project item; change it on the issue or pull request instead", name, field.Common.DataType)
+	}
+}
+
+// setProjectItemFieldValue sets or clears the field called fieldName of a project item. The
+// item is given by its numeric ID, as returned by the REST project tools, and its node ID is
+// looked up for the mutation.
+func setProjectItemFieldValue(ctx context.Context, getClient GetClientFn, getGQLClient GetGQLClientFn, ownerType, owner string, projectNumber int, itemID int64, fieldName string, value any, clearValue bool, requireDataType string) (*mcp.CallToolResult, error) {
+	client, err := getClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+	}
+	gqlClient, err := getGQLClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get GitHub GraphQL client: %w", err)
+	}
+	gqlCtx := ratelimit.WithLimiters(ctx, projectsGraphQLLimiters)
+
+	var item *github.ProjectV2Item
+	var resp *github.Response
+	if ownerType == "org" {
+		item, resp, err = client.Projects.GetOrganizationProjectItem(ctx, owner, projectNumber, itemID, nil)
+	} else {
+		item, resp, err = client.Projects.GetUserProjectItem(ctx, owner, projectNumber, itemID, nil)
+	}
+	if err != nil {
+		return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to get project item", resp, err), nil
+	}
+	_ = resp.Body.Close()
+
+	project, err := queryProjectFields(gqlCtx, gqlClient, ownerType, owner, projectNumber)
+	if err != nil {
+		return ghErrors.NewGitHubGraphQLErrorResponse(ctx, "failed to get project fields", err), nil
+	}
+	field, err := findProjectField(project, fieldName)
+	if err != nil {
+		return utils.NewToolResultError(err.Error()), nil
+	}
+	if requireDataType != "" && string(field.Common.DataType) != requireDataType {
+		return utils.NewToolResultError(fmt.Sprintf("field %q is a %s field, not %s", fieldName, field.Common.DataType, requireDataType)), nil
+	}
+
+	update := ProjectItemFieldUpdate{
+		ItemID:   itemID,
+		Field:    string(field.Common.Name),
+		DataType: string(field.Common.DataType),
+	}
+
+	if clearValue {
+		var mutation clearProjectV2ItemFieldValueMutation
+		input := githubv4.ClearProjectV2ItemFieldValueInput{
+			ProjectID: project.ID,
+			ItemID:    githubv4.ID(item.GetNodeID()),
+			FieldID:   field.Common.ID,
+		}
+		if err := gqlClient.Mutate(gqlCtx, &mutation, input, nil); err != nil {
+			return ghErrors.NewGitHubGraphQLErrorResponse(ctx, "failed to clear project item field", err), nil
+		}
+		update.Cleared = true
+		return MarshalledTextResult(update), nil
+	}
+
+	fieldValue, display, err := projectFieldValue(field, value)
+	if err != nil {
+		return utils.NewToolResultError(err.Error()), nil
+	}
+	var mutation updateProjectV2ItemFieldValueMutation
+	input := githubv4.UpdateProjectV2ItemFieldValueInput{
+		ProjectID: project.ID,
+		ItemID:    githubv4.ID(item.GetNodeID()),
+		FieldID:   field.Common.ID,
+		Value:     fieldValue,
+	}
+	if err := gqlClient.Mutate(gqlCtx, &mutation, input, nil); err != nil {
+		return ghErrors.NewGitHubGraphQLErrorResponse(ctx, "failed to update project item field", err), nil
+	}
+	update.Value = display
+	return MarshalledTextResult(update), nil
+}
+
+// SetProjectItemField creates a tool to set a field of a Project item by field name.
+func SetProjectItemField(getClient GetClientFn, getGQLClient GetGQLClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "set_project_item_field",
+		Description: t("TOOL_SET_PROJECT_ITEM_FIELD_DESCRIPTION", "Set or clear a custom field of a Project item, by field name. Text, number, date (YYYY-MM-DD), single select (option name) and iteration (iteration title) fields are supported. Use move_project_item to change the status."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_SET_PROJECT_ITEM_FIELD_USER_TITLE", "Set project item field"),
+			ReadOnlyHint: false,
+		},
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"owner_type": {
+					Type:        "string",
+					Description: "Owner type",
+					Enum:        []any{"user", "org"},
+				},
+				"owner": {
+					Type:        "string",
+					Description: "If owner_type == user it is the handle for the GitHub user account. If owner_type == org it is the name of the organization. The name is not case sensitive.",
+				},
+				"project_number": {
+					Type:        "number",
+					Description: "The project's number.",
+				},
+				"item_id": {
+					Type:        "number",
+					Description: "The numeric ID of the project item, as returned by list_project_items or add_project_item.",
+				},
+				"field": {
+					Type:        "string",
+					Description: "Name of the field, not case sensitive.",
+				},
+				"value": {
+					Types:       []string{"string", "number"},
+					Description: "New value of the field. Required unless clear is true.",
+				},
+				"clear": {
+					Type:        "boolean",
+					Description: "Clear the value of the field instead of setting it.",
+				},
+			},
+			Required: []string{"owner_type", "owner", "project_number", "item_id", "field"},
+		},
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		ownerType, err := RequiredParam[string](args, "owner_type")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		projectNumber, err := RequiredInt(args, "project_number")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		itemID, err := RequiredBigInt(args, "item_id")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		fieldName, err := RequiredParam[string](args, "field")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		clearValue, err := OptionalParam[bool](args, "clear")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		value := args["value"]
+		switch {
+		case clearValue && value != nil:
+			return utils.NewToolResultError("value cannot be set together with clear"), nil, nil
+		case !clearValue && value == nil:
+			return utils.NewToolResultError("missing required parameter: value"), nil, nil
+		}
+
+		result, err := setProjectItemFieldValue(ctx, getClient, getGQLClient, ownerType, owner, projectNumber, itemID, fieldName, value, clearValue, "")
+		return result, nil, err
+	})
+
+	return tool, handler
+}
+
+// MoveProjectItem creates a tool to move a Project item to another status column.
+func MoveProjectItem(getClient GetClientFn, getGQLClient GetGQLClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "move_project_item",
+		Description: t("TOOL_MOVE_PROJECT_ITEM_DESCRIPTION", "Move a Project item to another status column, such as 'In Progress' or 'Done', by setting its status field to the option with that name."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_MOVE_PROJECT_ITEM_USER_TITLE", "Move project item"),
+			ReadOnlyHint: false,
+		},
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"owner_type": {
+					Type:        "string",
+					Description: "Owner type",
+					Enum:        []any{"user", "org"},
+				},
+				"owner": {
+					Type:        "string",
+					Description: "If owner_type == user it is the handle for the GitHub user account. If owner_type == org it is the name of the organization. The name is not case sensitive.",
+				},
+				"project_number": {
+					Type:        "number",
+					Description: "The project's number.",
+				},
+				"item_id": {
+					Type:        "number",
+					Description: "The numeric ID of the project item, as returned by list_project_items or add_project_item.",
+				},
+				"status": {
+					Type:        "string",
+					Description: "Name of the status column to move the item to, not case sensitive.",
+				},
+				"status_field": {
+					Type:        "string",
+					Description: "Name of the single select field holding the columns. Defaults to 'Status'.",
+				},
+			},
+			Required: []string{"owner_type", "owner", "project_number", "item_id", "status"},
+		},
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		ownerType, err := RequiredParam[string](args, "owner_type")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		projectNumber, err := RequiredInt(args, "project_number")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		itemID, err := RequiredBigInt(args, "item_id")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		status, err := RequiredParam[string](args, "status")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		statusField, err := OptionalParam[string](args, "status_field")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		if statusField == "" {
+			statusField = "Status"
+		}
+
+		result, err := setProjectItemFieldValue(ctx, getClient, getGQLClient, ownerType, owner, projectNumber, itemID, statusField, status, false, "SINGLE_SELECT")
+		return result, nil, err
+	})
+
+	return tool, handler
+}
@@ -0,0 +1,242 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/github/github-mcp-server/internal/githubv4mock"
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v79/github"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var projectFieldsResponse = githubv4mock.DataResponse(map[string]any{
+	"organization": map[string]any{
+		"projectV2": map[string]any{
+			"id": "PVT_1",
+			"fields": map[string]any{
+				"nodes": []any{
+					map[string]any{"id": "PVTF_title", "name": "Title", "dataType": "TITLE"},
+					map[string]any{
+						"id": "PVTSSF_status", "name": "Status", "dataType": "SINGLE_SELECT",
+						"options": []any{
+							map[string]any{"id": "opt_todo", "name": "Todo"},
+							map[string]any{"id": "opt_progress", "name": "In Progress"},
+						},
+					},
+					map[string]any{"id": "PVTF_estimate", "name": "Estimate", "dataType": "NUMBER"},
+					map[string]any{"id": "PVTF_due", "name": "Due", "dataType": "DATE"},
+					map[string]any{
+						"id": "PVTIF_sprint", "name": "Sprint", "dataType": "ITERATION",
+						"configuration": map[string]any{
+							"iterations": []any{map[string]any{"id": "it_1", "title": "Sprint 1"}},
+						},
+					},
+				},
+			},
+		},
+	},
+})
+
+func projectItemRESTClient(t *testing.T) *http.Client {
+	return mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.EndpointPattern{Pattern: "/orgs/{org}/projectsV2/{project}/items/{item_id}", Method: http.MethodGet},
+			expectPath(t, "/orgs/octo-org/projectsV2/1/items/42").andThen(
+				mockResponse(t, http.StatusOK, &github.ProjectV2Item{ID: github.Ptr(int64(42)), NodeID: github.Ptr("PVTI_42")}),
+			),
+		),
+	)
+}
+
+func projectFieldsQueryMatcher() githubv4mock.Matcher {
+	return githubv4mock.NewQueryMatcher(
+		orgProjectFieldsQuery{},
+		map[string]any{"owner": githubv4.String("octo-org"), "number": githubv4.Int(1)},
+		projectFieldsResponse,
+	)
+}
+
+func Test_SetProjectItemField(t *testing.T) {
+	tool, _ := SetProjectItemField(stubGetClientFn(github.NewClient(nil)), stubGetGQLClientFn(githubv4.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	updated := githubv4mock.DataResponse(map[string]any{
+		"updateProjectV2ItemFieldValue": map[string]any{"projectV2Item": map[string]any{"id": "PVTI_42"}},
+	})
+
+	tests := []struct {
+		name          string
+		args          map[string]any
+		mutation      *githubv4mock.Matcher
+		expectError   string
+		expectedValue any
+		expectCleared bool
+	}{
+		{
+			name: "number",
+			args: map[string]any{"field": "estimate", "value": float64(3)},
+			mutation: func() *githubv4mock.Matcher {
+				m := githubv4mock.NewMutationMatcher(updateProjectV2ItemFieldValueMutation{}, githubv4.UpdateProjectV2ItemFieldValueInput{
+					ProjectID: "PVT_1", ItemID: "PVTI_42", FieldID: "PVTF_estimate",
+					Value: githubv4.ProjectV2FieldValue{Number: githubv4.NewFloat(3)},
+				}, nil, updated)
+				return &m
+			}(),
+			expectedValue: float64(3),
+		},
+		{
+			name: "date",
+			args: map[string]any{"field": "Due", "value": "2026-03-01"},
+			mutation: func() *githubv4mock.Matcher {
+				m := githubv4mock.NewMutationMatcher(updateProjectV2ItemFieldValueMutation{}, githubv4.UpdateProjectV2ItemFieldValueInput{
+					ProjectID: "PVT_1", ItemID: "PVTI_42", FieldID: "PVTF_due",
+					Value: githubv4.ProjectV2FieldValue{Date: githubv4.NewDate(githubv4.Date{Time: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)})},
+				}, nil, updated)
+				return &m
+			}(),
+			expectedValue: "2026-03-01",
+		},
+		{
+			name: "iteration by title",
+			args: map[string]any{"field": "Sprint", "value": "sprint 1"},
+			mutation: func() *githubv4mock.Matcher {
+				m := githubv4mock.NewMutationMatcher(updateProjectV2ItemFieldValueMutation{}, githubv4.UpdateProjectV2ItemFieldValueInput{
+					ProjectID: "PVT_1", ItemID: "PVTI_42", FieldID: "PVTIF_sprint",
+					Value: githubv4.ProjectV2FieldValue{IterationID: githubv4.NewString("it_1")},
+				}, nil, updated)
+				return &m
+			}(),
+			expectedValue: "Sprint 1",
+		},
+		{
+			name: "clear",
+			args: map[string]any{"field": "Estimate", "clear": true},
+			mutation: func() *githubv4mock.Matcher {
+				m := githubv4mock.NewMutationMatcher(clearProjectV2ItemFieldValueMutation{}, githubv4.ClearProjectV2ItemFieldValueInput{
+					ProjectID: "PVT_1", ItemID: "PVTI_42", FieldID: "PVTF_estimate",
+				}, nil, githubv4mock.DataResponse(map[string]any{
+					"clearProjectV2ItemFieldValue": map[string]any{"projectV2Item": map[string]any{"id": "PVTI_42"}},
+				}))
+				return &m
+			}(),
+			expectCleared: true,
+		},
+		{
+			name:        "unknown field",
+			args:        map[string]any{"field": "Priority", "value": "High"},
+			expectError: `project has no field "Priority"; available fields: Title, Status, Estimate, Due, Sprint`,
+		},
+		{
+			name:        "field not settable",
+			args:        map[string]any{"field": "Title", "value": "New title"},
+			expectError: `field "Title" of type TITLE cannot be set on a project item`,
+		},
+		{
+			name:        "invalid date",
+			args:        map[string]any{"field": "Due", "value": "March 1st"},
+			expectError: "takes a date in YYYY-MM-DD format",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			matchers := []githubv4mock.Matcher{projectFieldsQueryMatcher()}
+			if tc.mutation != nil {
+				matchers = append(matchers, *tc.mutation)
+			}
+			gqlClient := githubv4.NewClient(githubv4mock.NewMockedHTTPClient(matchers...))
+			_, handler := SetProjectItemField(stubGetClientFn(github.NewClient(projectItemRESTClient(t))), stubGetGQLClientFn(gqlClient), translations.NullTranslationHelper)
+
+			args := map[string]any{"owner_type": "org", "owner": "octo-org", "project_number": float64(1), "item_id": float64(42)}
+			for k, v := range tc.args {
+				args[k] = v
+			}
+			request := createMCPRequest(args)
+			result, _, err := handler(context.Background(), &request, args)
+			require.NoError(t, err)
+
+			if tc.expectError != "" {
+				require.True(t, result.IsError)
+				assert.Contains(t, getErrorResult(t, result).Text, tc.expectError)
+				return
+			}
+			require.False(t, result.IsError, getTextResult(t, result).Text)
+
+			var update ProjectItemFieldUpdate
+			require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &update))
+			assert.Equal(t, int64(42), update.ItemID)
+			assert.Equal(t, tc.expectedValue, update.Value)
+			assert.Equal(t, tc.expectCleared, update.Cleared)
+		})
+	}
+
+	t.Run("value with clear", func(t *testing.T) {
+		_, handler := SetProjectItemField(stubGetClientFn(github.NewClient(nil)), stubGetGQLClientFn(githubv4.NewClient(nil)), translations.NullTranslationHelper)
+		args := map[string]any{"owner_type": "org", "owner": "octo-org", "project_number": float64(1), "item_id": float64(42), "field": "Estimate", "value": float64(1), "clear": true}
+		request := createMCPRequest(args)
+		result, _, err := handler(context.Background(), &request, args)
+		require.NoError(t, err)
+		require.True(t, result.IsError)
+		assert.Contains(t, getErrorResult(t, result).Text, "value cannot be set together with clear")
+	})
+}
+
+func Test_MoveProjectItem(t *testing.T) {
+	tool, _ := MoveProjectItem(stubGetClientFn(github.NewClient(nil)), stubGetGQLClientFn(githubv4.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	t.Run("moves to the named column", func(t *testing.T) {
+		gqlClient := githubv4.NewClient(githubv4mock.NewMockedHTTPClient(
+			projectFieldsQueryMatcher(),
+			githubv4mock.NewMutationMatcher(updateProjectV2ItemFieldValueMutation{}, githubv4.UpdateProjectV2ItemFieldValueInput{
+				ProjectID: "PVT_1", ItemID: "PVTI_42", FieldID: "PVTSSF_status",
+				Value: githubv4.ProjectV2FieldValue{SingleSelectOptionID: githubv4.NewString("opt_progress")},
+			}, nil, githubv4mock.DataResponse(map[string]any{
+				"updateProjectV2ItemFieldValue": map[string]any{"projectV2Item": map[string]any{"id": "PVTI_42"}},
+			})),
+		))
+		_, handler := MoveProjectItem(stubGetClientFn(github.NewClient(projectItemRESTClient(t))), stubGetGQLClientFn(gqlClient), translations.NullTranslationHelper)
+
+		args := map[string]any{"owner_type": "org", "owner": "octo-org", "project_number": float64(1), "item_id": float64(42), "status": "in progress"}
+		request := createMCPRequest(args)
+		result, _, err := handler(context.Background(), &request, args)
+		require.NoError(t, err)
+		require.False(t, result.IsError, getTextResult(t, result).Text)
+
+		var update ProjectItemFieldUpdate
+		require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &update))
+		assert.Equal(t, ProjectItemFieldUpdate{ItemID: 42, Field: "Status", DataType: "SINGLE_SELECT", Value: "In Progress"}, update)
+	})
+
+	t.Run("unknown column", func(t *testing.T) {
+		gqlClient := githubv4.NewClient(githubv4mock.NewMockedHTTPClient(projectFieldsQueryMatcher()))
+		_, handler := MoveProjectItem(stubGetClientFn(github.NewClient(projectItemRESTClient(t))), stubGetGQLClientFn(gqlClient), translations.NullTranslationHelper)
+
+		args := map[string]any{"owner_type": "org", "owner": "octo-org", "project_number": float64(1), "item_id": float64(42), "status": "Done"}
+		request := createMCPRequest(args)
+		result, _, err := handler(context.Background(), &request, args)
+		require.NoError(t, err)
+		require.True(t, result.IsError)
+		assert.Contains(t, getErrorResult(t, result).Text, `field "Status" has no option "Done"; available options: Todo, In Progress`)
+	})
+
+	t.Run("status field must be single select", func(t *testing.T) {
+		gqlClient := githubv4.NewClient(githubv4mock.NewMockedHTTPClient(projectFieldsQueryMatcher()))
+		_, handler := MoveProjectItem(stubGetClientFn(github.NewClient(projectItemRESTClient(t))), stubGetGQLClientFn(gqlClient), translations.NullTranslationHelper)
+
+		args := map[string]any{"owner_type": "org", "owner": "octo-org", "project_number": float64(1), "item_id": float64(42), "status": "Todo", "status_field": "Estimate"}
+		request := createMCPRequest(args)
+		result, _, err := handler(context.Background(), &request, args)
+		require.NoError(t, err)
+		require.True(t, result.IsError)
+		assert.Contains(t, getErrorResult(t, result).Text, `field "Estimate" is a NUMBER field, not SINGLE_SELECT`)
+	})
+}
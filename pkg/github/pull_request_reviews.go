@@ -0,0 +1,559 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	ghErrors "github.com/github/github-mcp-server/pkg/errors"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/github/github-mcp-server/pkg/utils"
+	"github.com/google/go-github/v79/github"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/shurcooL/githubv4"
+)
+
+// reviewCommentSchema describes one inline comment of create_pull_request_review.
+func reviewCommentSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"path": {
+				Type:        "string",
+				Description: "Path of the file to comment on, relative to the repository root",
+			},
+			"body": {
+				Type:        "string",
+				Description: "Text of the comment. Optional when suggestion is given",
+			},
+			"line": {
+				Type:        "number",
+				Description: "Line of the diff the comment applies to, or the last line of a multi-line comment",
+			},
+			"side": {
+				Type:        "string",
+				Description: "Side of the diff the line is on: LEFT for deleted lines, RIGHT for added or unchanged lines (default: RIGHT)",
+				Enum:        []any{"LEFT", "RIGHT"},
+			},
+			"startLine": {
+				Type:        "number",
+				Description: "First line of a multi-line comment, before line",
+			},
+			"startSide": {
+				Type:        "string",
+				Description: "Side of the diff startLine is on (default: side)",
+				Enum:        []any{"LEFT", "RIGHT"},
+			},
+			"suggestion": {
+				Type:        "string",
+				Description: "Replacement for the commented lines, added to the comment as a suggested change the author can apply. Only for lines on the RIGHT side; an empty string suggests deleting them",
+			},
+		},
+		Required: []string{"path", "line"},
+	}
+}
+
+// suggestionBlock returns a suggested change block for replacement, fenced with more backticks
+// than replacement contains in a row so that code blocks inside it are kept.
+func suggestionBlock(replacement string) string {
+	longest, run := 0, 0
+	for _, r := range replacement {
+		if r != '`' {
+			run = 0
+			continue
+		}
+		run++
+		longest = max(longest, run)
+	}
+	fence := strings.Repeat("`", max(3, longest+1))
+	if replacement != "" && !strings.HasSuffix(replacement, "\n") {
+		replacement += "\n"
+	}
+	return fence + "suggestion\n" + replacement + fence
+}
+
+// draftReviewComments converts the comments parameter of create_pull_request_review.
+func draftReviewComments(args map[string]any) ([]*github.DraftReviewComment, error) {
+	commentsObj, ok := args["comments"]
+	if !ok {
+		return nil, nil
+	}
+	items, ok := commentsObj.([]any)
+	if !ok {
+		return nil, fmt.Errorf("comments must be an array of objects")
+	}
+
+	comments := make([]*github.DraftReviewComment, 0, len(items))
+	for i, item := range items {
+		comment, ok := item.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("comment at index %d must be an object", i)
+		}
+		path, err := RequiredParam[string](comment, "path")
+		if err != nil {
+			return nil, fmt.Errorf("comment at index %d: %w", i, err)
+		}
+		line, err := RequiredInt(comment, "line")
+		if err != nil {
+			return nil, fmt.Errorf("comment at index %d: %w", i, err)
+		}
+		body, err := OptionalParam[string](comment, "body")
+		if err != nil {
+			return nil, fmt.Errorf("comment at index %d: %w", i, err)
+		}
+		side, err := OptionalParam[string](comment, "side")
+		if err != nil {
+			return nil, fmt.Errorf("comment at index %d: %w", i, err)
+		}
+		startLine, err := OptionalIntParam(comment, "startLine")
+		if err != nil {
+			return nil, fmt.Errorf("comment at index %d: %w", i, err)
+		}
+		startSide, err := OptionalParam[string](comment, "startSide")
+		if err != nil {
+			return nil, fmt.Errorf("comment at index %d: %w", i, err)
+		}
+		suggestion, hasSuggestion := comment["suggestion"].(string)
+
+		if side == "" {
+			side = "RIGHT"
+		}
+		switch {
+		case line < 1:
+			return nil, fmt.Errorf("comment at index %d: line must be at least 1", i)
+		case startLine != 0 && (startLine < 1 || startLine >= line):
+			return nil, fmt.Errorf("comment at index %d: startLine must be at least 1 and before line %d", i, line)
+		case startSide != "" && startLine == 0:
+			return nil, fmt.Errorf("comment at index %d: startSide requires startLine", i)
+		case hasSuggestion && (side != "RIGHT" || (startSide != "" && startSide != "RIGHT")):
+			return nil, fmt.Errorf("comment at index %d: suggestions can only replace lines on the RIGHT side", i)
+		case body == "" && !hasSuggestion:
+			return nil, fmt.Errorf("comment at index %d: body or suggestion is required", i)
+		}
+
+		if hasSuggestion {
+			if body != "" {
+				body += "\n\n"
+			}
+			body += suggestionBlock(suggestion)
+		}
+		draft := &github.DraftReviewComment{
+			Path: github.Ptr(path),
+			Body: github.Ptr(body),
+			Line: github.Ptr(line),
+			Side: github.Ptr(side),
+		}
+		if startLine != 0 {
+			draft.StartLine = github.Ptr(startLine)
+			draft.StartSide = github.Ptr(side)
+			if startSide != "" {
+				draft.StartSide = github.Ptr(startSide)
+			}
+		}
+		comments = append(comments, draft)
+	}
+	return comments, nil
+}
+
+// CreatePullRequestReviewResult is the review created by create_pull_request_review
+type CreatePullRequestReviewResult struct {
+	ID       int64  `json:"id"`
+	State    string `json:"state"`
+	URL      string `json:"url"`
+	Comments int    `json:"comments"`
+}
+
+// CreatePullRequestReviewWithComments creates a tool to create a pull request review with any number of
+// inline comments in one call, either submitted or left pending.
+func CreatePullRequestReviewWithComments(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	return mcp.Tool{
+			Name:        "create_pull_request_review",
+			Description: t("TOOL_CREATE_PULL_REQUEST_REVIEW_DESCRIPTION", "Create a review on a pull request with any number of inline comments in one call, instead of creating a pending review, adding comments one by one and submitting it. Comments can span several lines and carry suggested changes. Without event the review is left pending for the authenticated user, to be submitted with pull_request_review_write."),
+			Annotations: &mcp.ToolAnnotations{
+				Title:        t("TOOL_CREATE_PULL_REQUEST_REVIEW_USER_TITLE", "Create pull request review with comments"),
+				ReadOnlyHint: false,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"owner": {
+						Type:        "string",
+						Description: "Repository owner",
+					},
+					"repo": {
+						Type:        "string",
+						Description: "Repository name",
+					},
+					"pullNumber": {
+						Type:        "number",
+						Description: "Pull request number",
+					},
+					"body": {
+						Type:        "string",
+						Description: "Summary comment of the review",
+					},
+					"event": {
+						Type:        "string",
+						Description: "Submit the review with this verdict. Leave unset to keep the review pending",
+						Enum:        []any{"APPROVE", "REQUEST_CHANGES", "COMMENT"},
+					},
+					"commitID": {
+						Type:        "string",
+						Description: "SHA of the commit to review. Defaults to the latest commit of the pull request",
+					},
+					"comments": {
+						Type:        "array",
+						Description: "Inline comments of the review",
+						Items:       reviewCommentSchema(),
+					},
+				},
+				Required: []string{"owner", "repo", "pullNumber"},
+			},
+		},
+		func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+			owner, err := RequiredParam[string](args, "owner")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			repo, err := RequiredParam[string](args, "repo")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			pullNumber, err := RequiredInt(args, "pullNumber")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			body, err := OptionalParam[string](args, "body")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			event, err := OptionalParam[string](args, "event")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			commitID, err := OptionalParam[string](args, "commitID")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			comments, err := draftReviewComments(args)
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			if event != "" && event != "APPROVE" && body == "" && len(comments) == 0 {
+				return utils.NewToolResultError(fmt.Sprintf("a %s review needs a body or comments", event)), nil, nil
+			}
+
+			review := &github.PullRequestReviewRequest{Comments: comments}
+			if body != "" {
+				review.Body = github.Ptr(body)
+			}
+			if event != "" {
+				review.Event = github.Ptr(event)
+			}
+			if commitID != "" {
+				review.CommitID = github.Ptr(commitID)
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return utils.NewToolResultErrorFromErr("failed to get GitHub client", err), nil, nil
+			}
+			created, resp, err := client.PullRequests.CreateReview(ctx, owner, repo, pullNumber, review)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to create pull request review", resp, err), nil, nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			return MarshalledTextResult(CreatePullRequestReviewResult{
+				ID:       created.GetID(),
+				State:    created.GetState(),
+				URL:      created.GetHTMLURL(),
+				Comments: len(comments),
+			}), nil, nil
+		}
+}
+
+// RequestPullRequestReviewers creates a tool to request reviews from users and teams.
+func RequestPullRequestReviewers(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	return mcp.Tool{
+			Name:        "request_pull_request_reviewers",
+			Description: t("TOOL_REQUEST_PULL_REQUEST_REVIEWERS_DESCRIPTION", "Request reviews on a pull request from users and teams. Reviewers already requested stay requested."),
+			Annotations: &mcp.ToolAnnotations{
+				Title:        t("TOOL_REQUEST_PULL_REQUEST_REVIEWERS_USER_TITLE", "Request pull request reviewers"),
+				ReadOnlyHint: false,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"owner": {
+						Type:        "string",
+						Description: "Repository owner",
+					},
+					"repo": {
+						Type:        "string",
+						Description: "Repository name",
+					},
+					"pullNumber": {
+						Type:        "number",
+						Description: "Pull request number",
+					},
+					"reviewers": {
+						Type:        "array",
+						Description: "Usernames to request reviews from",
+						Items: &jsonschema.Schema{
+							Type: "string",
+						},
+					},
+					"teamReviewers": {
+						Type:        "array",
+						Description: "Slugs of teams of the repository owner to request reviews from",
+						Items: &jsonschema.Schema{
+							Type: "string",
+						},
+					},
+				},
+				Required: []string{"owner", "repo", "pullNumber"},
+			},
+		},
+		func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+			owner, err := RequiredParam[string](args, "owner")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			repo, err := RequiredParam[string](args, "repo")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			pullNumber, err := RequiredInt(args, "pullNumber")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			reviewers, err := OptionalStringArrayParam(args, "reviewers")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			teamReviewers, err := OptionalStringArrayParam(args, "teamReviewers")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			if len(reviewers) == 0 && len(teamReviewers) == 0 {
+				return utils.NewToolResultError("at least one of reviewers or teamReviewers is required"), nil, nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return utils.NewToolResultErrorFromErr("failed to get GitHub client", err), nil, nil
+			}
+			pr, resp, err := client.PullRequests.RequestReviewers(ctx, owner, repo, pullNumber, github.ReviewersRequest{
+				Reviewers:     reviewers,
+				TeamReviewers: teamReviewers,
+			})
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to request reviewers", resp, err), nil, nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			requested := map[string][]string{
+				"reviewers":      {},
+				"team_reviewers": {},
+			}
+			for _, user := range pr.RequestedReviewers {
+				requested["reviewers"] = append(requested["reviewers"], user.GetLogin())
+			}
+			for _, team := range pr.RequestedTeams {
+				requested["team_reviewers"] = append(requested["team_reviewers"], team.GetSlug())
+			}
+			return MarshalledTextResult(requested), nil, nil
+		}
+}
+
+// reviewThreadCommentsPerThread is the number of comments returned for each review thread
+const reviewThreadCommentsPerThread = 20
+
+// reviewThreadsQuery lists the review threads of a pull request
+type reviewThreadsQuery struct {
+	Repository struct {
+		PullRequest struct {
+			ReviewThreads struct {
+				Nodes []struct {
+					ID            githubv4.ID
+					IsResolved    githubv4.Boolean
+					IsOutdated    githubv4.Boolean
+					Path          githubv4.String
+					Line          *githubv4.Int
+					StartLine     *githubv4.Int
+					DiffSide      githubv4.String
+					StartDiffSide *githubv4.String
+					ResolvedBy    *struct {
+						Login githubv4.String
+					}
+					Comments struct {
+						Nodes []struct {
+							ID     githubv4.ID
+							Body   githubv4.String
+							URL    githubv4.String `graphql:"url"`
+							Author struct {
+								Login githubv4.String
+							}
+							CreatedAt githubv4.DateTime
+						}
+						TotalCount int
+					} `graphql:"comments(first: $commentsFirst)"`
+				}
+				PageInfo   PageInfoFragment
+				TotalCount int
+			} `graphql:"reviewThreads(first: $first, after: $after)"`
+		} `graphql:"pullRequest(number: $pullNumber)"`
+	} `graphql:"repository(owner: $owner, name: $repo)"`
+}
+
+// ReviewThreadComment is a comment of a review thread
+type ReviewThreadComment struct {
+	ID        string `json:"id"`
+	Author    string `json:"author"`
+	Body      string `json:"body"`
+	URL       string `json:"url"`
+	CreatedAt string `json:"created_at"`
+}
+
+// ReviewThread is a conversation on lines of a pull request diff
+type ReviewThread struct {
+	ID         string                `json:"id"`
+	Path       string                `json:"path"`
+	Line       int                   `json:"line,omitempty"`
+	StartLine  int                   `json:"start_line,omitempty"`
+	Side       string                `json:"side"`
+	StartSide  string                `json:"start_side,omitempty"`
+	IsResolved bool                  `json:"is_resolved"`
+	IsOutdated bool                  `json:"is_outdated"`
+	ResolvedBy string                `json:"resolved_by,omitempty"`
+	Comments   []ReviewThreadComment `json:"comments"`
+	// TotalComments is the number of comments of the thread, of which the first
+	// reviewThreadCommentsPerThread are returned
+	TotalComments int `json:"total_comments"`
+}
+
+// ListPullRequestReviewThreads creates a tool to list the review threads of a pull request.
+func ListPullRequestReviewThreads(getGQLClient GetGQLClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	schema := &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"owner": {
+				Type:        "string",
+				Description: "Repository owner",
+			},
+			"repo": {
+				Type:        "string",
+				Description: "Repository name",
+			},
+			"pullNumber": {
+				Type:        "number",
+				Description: "Pull request number",
+			},
+		},
+		Required: []string{"owner", "repo", "pullNumber"},
+	}
+	WithCursorPagination(schema)
+
+	return mcp.Tool{
+			Name:        "list_pull_request_review_threads",
+			Description: t("TOOL_LIST_PULL_REQUEST_REVIEW_THREADS_DESCRIPTION", fmt.Sprintf("List the review threads of a pull request: the conversations on lines of its diff, with their lines, whether they are resolved or outdated, and their first %d comments.", reviewThreadCommentsPerThread)),
+			Annotations: &mcp.ToolAnnotations{
+				Title:        t("TOOL_LIST_PULL_REQUEST_REVIEW_THREADS_USER_TITLE", "List pull request review threads"),
+				ReadOnlyHint: true,
+			},
+			InputSchema: schema,
+		},
+		func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+			owner, err := RequiredParam[string](args, "owner")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			repo, err := RequiredParam[string](args, "repo")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			pullNumber, err := RequiredInt(args, "pullNumber")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			pagination, err := OptionalCursorPaginationParams(args)
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			paginationParams, err := pagination.ToGraphQLParams()
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+
+			client, err := getGQLClient(ctx)
+			if err != nil {
+				return utils.NewToolResultErrorFromErr("failed to get GitHub GQL client", err), nil, nil
+			}
+
+			var q reviewThreadsQuery
+			vars := map[string]any{
+				"owner":         githubv4.String(owner),
+				"repo":          githubv4.String(repo),
+				"pullNumber":    githubv4.Int(pullNumber), // #nosec G115 - pull request numbers are always small positive integers
+				"first":         githubv4.Int(*paginationParams.First),
+				"commentsFirst": githubv4.Int(reviewThreadCommentsPerThread),
+				"after":         (*githubv4.String)(nil),
+			}
+			if paginationParams.After != nil {
+				vars["after"] = githubv4.String(*paginationParams.After)
+			}
+			if err := client.Query(ctx, &q, vars); err != nil {
+				return ghErrors.NewGitHubGraphQLErrorResponse(ctx, "failed to list review threads", err), nil, nil
+			}
+
+			threads := q.Repository.PullRequest.ReviewThreads
+			result := make([]ReviewThread, 0, len(threads.Nodes))
+			for _, node := range threads.Nodes {
+				thread := ReviewThread{
+					ID:            fmt.Sprint(node.ID),
+					Path:          string(node.Path),
+					Side:          string(node.DiffSide),
+					IsResolved:    bool(node.IsResolved),
+					IsOutdated:    bool(node.IsOutdated),
+					Comments:      make([]ReviewThreadComment, 0, len(node.Comments.Nodes)),
+					TotalComments: node.Comments.TotalCount,
+				}
+				if node.Line != nil {
+					thread.Line = int(*node.Line)
+				}
+				if node.StartLine != nil {
+					thread.StartLine = int(*node.StartLine)
+				}
+				if node.StartDiffSide != nil {
+					thread.StartSide = string(*node.StartDiffSide)
+				}
+				if node.ResolvedBy != nil {
+					thread.ResolvedBy = string(node.ResolvedBy.Login)
+				}
+				for _, comment := range node.Comments.Nodes {
+					thread.Comments = append(thread.Comments, ReviewThreadComment{
+						ID:        fmt.Sprint(comment.ID),
+						Author:    string(comment.Author.Login),
+						Body:      string(comment.Body),
+						URL:       string(comment.URL),
+						CreatedAt: comment.CreatedAt.Format(time.RFC3339),
+					})
+				}
+				result = append(result, thread)
+			}
+
+			return MarshalledTextResult(map[string]any{
+				"threads": result,
+				"pageInfo": map[string]any{
+					"hasNextPage":     threads.PageInfo.HasNextPage,
+					"hasPreviousPage": threads.PageInfo.HasPreviousPage,
+					"startCursor":     string(threads.PageInfo.StartCursor),
+					"endCursor":       string(threads.PageInfo.EndCursor),
+				},
+				"totalCount": threads.TotalCount,
+			}), nil, nil
+		}
+}
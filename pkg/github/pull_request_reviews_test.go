@@ -0,0 +1,295 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/github/github-mcp-server/internal/githubv4mock"
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v79/github"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_suggestionBlock(t *testing.T) {
+	assert.Equal(t, "```suggestion\nreturn nil\n```", suggestionBlock("return nil"))
+	assert.Equal(t, "```suggestion\n```", suggestionBlock(""), "an empty suggestion deletes the lines")
+	assert.Equal(t, "````suggestion\n```go\nx := 1\n```\n````", suggestionBlock("```go\nx := 1\n```\n"))
+}
+
+func Test_CreatePullRequestReviewWithComments(t *testing.T) {
+	tool, _ := CreatePullRequestReviewWithComments(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.Equal(t, "create_pull_request_review", tool.Name)
+
+	tests := []struct {
+		name           string
+		args           map[string]any
+		expectedBody   map[string]any
+		expectedErrMsg string
+	}{
+		{
+			name: "submits a review with multi-line comments and suggestions",
+			args: map[string]any{
+				"body":  "A few nits",
+				"event": "REQUEST_CHANGES",
+				"comments": []any{
+					map[string]any{"path": "main.go", "line": float64(12), "body": "Typo"},
+					map[string]any{"path": "main.go", "line": float64(20), "startLine": float64(18), "body": "Simplify", "suggestion": "return err"},
+					map[string]any{"path": "old.go", "line": float64(3), "side": "LEFT", "body": "Why remove this?"},
+				},
+			},
+			expectedBody: map[string]any{
+				"body":  "A few nits",
+				"event": "REQUEST_CHANGES",
+				"comments": []any{
+					map[string]any{"path": "main.go", "line": float64(12), "side": "RIGHT", "body": "Typo"},
+					map[string]any{"path": "main.go", "line": float64(20), "side": "RIGHT", "start_line": float64(18), "start_side": "RIGHT", "body": "Simplify\n\n```suggestion\nreturn err\n```"},
+					map[string]any{"path": "old.go", "line": float64(3), "side": "LEFT", "body": "Why remove this?"},
+				},
+			},
+		},
+		{
+			name: "leaves the review pending without event",
+			args: map[string]any{
+				"comments": []any{
+					map[string]any{"path": "main.go", "line": float64(1), "suggestion": "package main"},
+				},
+			},
+			expectedBody: map[string]any{
+				"comments": []any{
+					map[string]any{"path": "main.go", "line": float64(1), "side": "RIGHT", "body": "```suggestion\npackage main\n```"},
+				},
+			},
+		},
+		{
+			name: "comment without body or suggestion",
+			args: map[string]any{
+				"comments": []any{map[string]any{"path": "main.go", "line": float64(1)}},
+			},
+			expectedErrMsg: "comment at index 0: body or suggestion is required",
+		},
+		{
+			name: "start line after line",
+			args: map[string]any{
+				"comments": []any{map[string]any{"path": "main.go", "line": float64(5), "startLine": float64(5), "body": "x"}},
+			},
+			expectedErrMsg: "startLine must be at least 1 and before line 5",
+		},
+		{
+			name: "suggestion on deleted lines",
+			args: map[string]any{
+				"comments": []any{map[string]any{"path": "main.go", "line": float64(5), "side": "LEFT", "suggestion": "x"}},
+			},
+			expectedErrMsg: "suggestions can only replace lines on the RIGHT side",
+		},
+		{
+			name:           "comment review without content",
+			args:           map[string]any{"event": "COMMENT"},
+			expectedErrMsg: "a COMMENT review needs a body or comments",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockedClient := mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.PostReposPullsReviewsByOwnerByRepoByPullNumber,
+					expectPath(t, "/repos/owner/repo/pulls/7/reviews").andThen(
+						expectRequestBody(t, tc.expectedBody).andThen(
+							mockResponse(t, http.StatusOK, &github.PullRequestReview{
+								ID:      github.Ptr(int64(99)),
+								State:   github.Ptr("CHANGES_REQUESTED"),
+								HTMLURL: github.Ptr("https://github.com/owner/repo/pull/7#pullrequestreview-99"),
+							}),
+						),
+					),
+				),
+			)
+			_, handler := CreatePullRequestReviewWithComments(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+			args := map[string]any{"owner": "owner", "repo": "repo", "pullNumber": float64(7)}
+			for k, v := range tc.args {
+				args[k] = v
+			}
+			request := createMCPRequest(args)
+			result, _, err := handler(context.Background(), &request, args)
+			require.NoError(t, err)
+
+			if tc.expectedErrMsg != "" {
+				require.True(t, result.IsError)
+				assert.Contains(t, getErrorResult(t, result).Text, tc.expectedErrMsg)
+				return
+			}
+
+			require.False(t, result.IsError, getTextResult(t, result).Text)
+			var review CreatePullRequestReviewResult
+			require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &review))
+			assert.Equal(t, int64(99), review.ID)
+			assert.Equal(t, len(tc.expectedBody["comments"].([]any)), review.Comments)
+		})
+	}
+}
+
+func Test_RequestPullRequestReviewers(t *testing.T) {
+	tool, _ := RequestPullRequestReviewers(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.PostReposPullsRequestedReviewersByOwnerByRepoByPullNumber,
+			expectRequestBody(t, map[string]any{
+				"reviewers":      []any{"octocat"},
+				"team_reviewers": []any{"platform"},
+			}).andThen(
+				mockResponse(t, http.StatusCreated, &github.PullRequest{
+					RequestedReviewers: []*github.User{{Login: github.Ptr("octocat")}, {Login: github.Ptr("hubot")}},
+					RequestedTeams:     []*github.Team{{Slug: github.Ptr("platform")}},
+				}),
+			),
+		),
+	)
+	_, handler := RequestPullRequestReviewers(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+	args := map[string]any{
+		"owner":         "owner",
+		"repo":          "repo",
+		"pullNumber":    float64(7),
+		"reviewers":     []any{"octocat"},
+		"teamReviewers": []any{"platform"},
+	}
+	request := createMCPRequest(args)
+	result, _, err := handler(context.Background(), &request, args)
+	require.NoError(t, err)
+	require.False(t, result.IsError, getTextResult(t, result).Text)
+
+	var requested map[string][]string
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &requested))
+	assert.Equal(t, []string{"octocat", "hubot"}, requested["reviewers"])
+	assert.Equal(t, []string{"platform"}, requested["team_reviewers"])
+
+	args = map[string]any{"owner": "owner", "repo": "repo", "pullNumber": float64(7)}
+	request = createMCPRequest(args)
+	result, _, err = handler(context.Background(), &request, args)
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	assert.Contains(t, getErrorResult(t, result).Text, "at least one of reviewers or teamReviewers is required")
+}
+
+func Test_ListPullRequestReviewThreads(t *testing.T) {
+	tool, _ := ListPullRequestReviewThreads(stubGetGQLClientFn(githubv4.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.True(t, tool.Annotations.ReadOnlyHint)
+
+	vars := map[string]any{
+		"owner":         githubv4.String("owner"),
+		"repo":          githubv4.String("repo"),
+		"pullNumber":    githubv4.Int(7),
+		"first":         githubv4.Int(2),
+		"commentsFirst": githubv4.Int(reviewThreadCommentsPerThread),
+		"after":         githubv4.String("cursor1"),
+	}
+	response := githubv4mock.DataResponse(map[string]any{
+		"repository": map[string]any{
+			"pullRequest": map[string]any{
+				"reviewThreads": map[string]any{
+					"nodes": []any{
+						map[string]any{
+							"id":            "PRRT_1",
+							"isResolved":    true,
+							"isOutdated":    false,
+							"path":          "main.go",
+							"line":          20,
+							"startLine":     18,
+							"diffSide":      "RIGHT",
+							"startDiffSide": "RIGHT",
+							"resolvedBy":    map[string]any{"login": "octocat"},
+							"comments": map[string]any{
+								"nodes": []any{
+									map[string]any{
+										"id":        "PRRC_1",
+										"body":      "Simplify",
+										"url":       "https://github.com/owner/repo/pull/7#discussion_r1",
+										"author":    map[string]any{"login": "hubot"},
+										"createdAt": "2024-01-01T12:00:00Z",
+									},
+								},
+								"totalCount": 3,
+							},
+						},
+						map[string]any{
+							"id":            "PRRT_2",
+							"isResolved":    false,
+							"isOutdated":    true,
+							"path":          "README.md",
+							"line":          nil,
+							"startLine":     nil,
+							"diffSide":      "RIGHT",
+							"startDiffSide": nil,
+							"resolvedBy":    nil,
+							"comments": map[string]any{
+								"nodes":      []any{},
+								"totalCount": 0,
+							},
+						},
+					},
+					"pageInfo": map[string]any{
+						"hasNextPage":     true,
+						"hasPreviousPage": true,
+						"startCursor":     "cursor2",
+						"endCursor":       "cursor3",
+					},
+					"totalCount": 5,
+				},
+			},
+		},
+	})
+	gqlClient := githubv4.NewClient(githubv4mock.NewMockedHTTPClient(githubv4mock.NewQueryMatcher(reviewThreadsQuery{}, vars, response)))
+	_, handler := ListPullRequestReviewThreads(stubGetGQLClientFn(gqlClient), translations.NullTranslationHelper)
+
+	args := map[string]any{"owner": "owner", "repo": "repo", "pullNumber": float64(7), "perPage": float64(2), "after": "cursor1"}
+	request := createMCPRequest(args)
+	result, _, err := handler(context.Background(), &request, args)
+	require.NoError(t, err)
+	require.False(t, result.IsError, getTextResult(t, result).Text)
+
+	var listed struct {
+		Threads  []ReviewThread `json:"threads"`
+		PageInfo struct {
+			HasNextPage bool   `json:"hasNextPage"`
+			EndCursor   string `json:"endCursor"`
+		} `json:"pageInfo"`
+		TotalCount int `json:"totalCount"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &listed))
+	require.Len(t, listed.Threads, 2)
+	assert.Equal(t, ReviewThread{
+		ID:         "PRRT_1",
+		Path:       "main.go",
+		Line:       20,
+		StartLine:  18,
+		Side:       "RIGHT",
+		StartSide:  "RIGHT",
+		IsResolved: true,
+		ResolvedBy: "octocat",
+		Comments: []ReviewThreadComment{{
+			ID:        "PRRC_1",
+			Author:    "hubot",
+			Body:      "Simplify",
+			URL:       "https://github.com/owner/repo/pull/7#discussion_r1",
+			CreatedAt: "2024-01-01T12:00:00Z",
+		}},
+		TotalComments: 3,
+	}, listed.Threads[0])
+	assert.True(t, listed.Threads[1].IsOutdated)
+	assert.Zero(t, listed.Threads[1].Line)
+	assert.Empty(t, listed.Threads[1].Comments)
+	assert.True(t, listed.PageInfo.HasNextPage)
+	assert.Equal(t, "cursor3", listed.PageInfo.EndCursor)
+	assert.Equal(t, 5, listed.TotalCount)
+}
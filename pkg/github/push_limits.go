@@ -0,0 +1,317 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/google/go-github/v79/github"
+)
+
+// Bounds that configured push limits are clamped to
+const (
+	// maxFilesPerPushCeiling bounds the files in one commit, keeping tree requests reasonable
+	maxFilesPerPushCeiling = 1000
+	// minFileSizeBytes is the smallest configurable per-file limit (1KB)
+	minFileSizeBytes = 1024
+	// maxFileSizeCeiling is GitHub's hard limit on the size of a single file (100MB)
+	maxFileSizeCeiling = 100 * 1024 * 1024
+	// minTotalPushSizeBytes is the smallest configurable total push size (1MB)
+	minTotalPushSizeBytes = 1024 * 1024
+	// maxTotalPushSizeCeiling is GitHub's limit on the size of a single push (2GB)
+	maxTotalPushSizeCeiling = 2 * 1024 * 1024 * 1024
+	// maxChunkSizeCeiling bounds the files in one chunk of a chunked push
+	maxChunkSizeCeiling = 1000
+)
+
+// Sources reported for push limits that are not configured
+const (
+	PushLimitSourceDefault = "default"
+)
+
+// Keys of PushLimits.Sources, matching the fields reported by get_push_limits
+const (
+	PushLimitMaxFilesPerPush       = "max_files_per_push"
+	PushLimitMaxFileSizeBytes      = "max_file_size_bytes"
+	PushLimitMaxTotalPushSizeBytes = "max_total_push_size_bytes"
+	PushLimitDefaultChunkSize      = "default_chunk_size"
+	PushLimitMaxChunkSize          = "max_chunk_size"
+)
+
+// PushLimits bounds the pushes made by the file tools. Zero fields take the built-in defaults.
+type PushLimits struct {
+	MaxFilesPerPush       int
+	MaxFileSizeBytes      int64
+	MaxTotalPushSizeBytes int64
+	DefaultChunkSize      int
+	MaxChunkSize          int
+
+	// Sources names where each limit was set, such as env or file, keyed by the PushLimit
+	// constants. Limits without a source are reported as default.
+	Sources map[string]string
+
+	// Host is the GitHub host the limits apply to, when resolved by a LimitsProvider
+	Host string
+	// HostType is one of the HostType constants, or empty when it could not be detected
+	HostType string
+	// EnterpriseVersion is the version of a GitHub Enterprise Server host
+	EnterpriseVersion string
+}
+
+// DefaultPushLimits returns the built-in limits.
+func DefaultPushLimits() PushLimits {
+	return PushLimits{
+		MaxFilesPerPush:       MaxFilesPerPush,
+		MaxFileSizeBytes:      MaxFileSizeBytes,
+		MaxTotalPushSizeBytes: MaxTotalPushSizeBytes,
+		DefaultChunkSize:      DefaultChunkSize,
+		MaxChunkSize:          MaxChunkSize,
+		Sources:               map[string]string{},
+	}
+}
+
+// Source reports where the limit with the given key was set.
+func (l PushLimits) Source(key string) string {
+	if source, ok := l.Sources[key]; ok && source != "" {
+		return source
+	}
+	return PushLimitSourceDefault
+}
+
+// Normalize fills unset limits with the defaults and clamps the others to the range GitHub
+// accepts. It returns the keys of the limits that were clamped, whose source is marked as such.
+func (l PushLimits) Normalize() (PushLimits, []string) {
+	defaults := DefaultPushLimits()
+	sources := make(map[string]string, len(l.Sources))
+	for key, source := range l.Sources {
+		sources[key] = source
+	}
+
+	var clamped []string
+	clampInt := func(key string, value *int64, fallback, lower, upper int64) {
+		if *value == 0 {
+			*value = fallback
+			sources[key] = PushLimitSourceDefault
+			return
+		}
+		if *value >= lower && *value <= upper {
+			return
+		}
+		*value = max(lower, min(*value, upper))
+		clamped = append(clamped, key)
+		sources[key] = fmt.Sprintf("%s (clamped)", l.Source(key))
+	}
+
+	maxFiles := int64(l.MaxFilesPerPush)
+	clampInt(PushLimitMaxFilesPerPush, &maxFiles, int64(defaults.MaxFilesPerPush), 1, maxFilesPerPushCeiling)
+	totalSize := l.MaxTotalPushSizeBytes
+	clampInt(PushLimitMaxTotalPushSizeBytes, &totalSize, defaults.MaxTotalPushSizeBytes, minTotalPushSizeBytes, maxTotalPushSizeCeiling)
+	// A single file can never be larger than a whole push
+	fileSize := l.MaxFileSizeBytes
+	clampInt(PushLimitMaxFileSizeBytes, &fileSize, min(defaults.MaxFileSizeBytes, totalSize), minFileSizeBytes, min(maxFileSizeCeiling, totalSize))
+	maxChunk := int64(l.MaxChunkSize)
+	clampInt(PushLimitMaxChunkSize, &maxChunk, int64(defaults.MaxChunkSize), 1, maxChunkSizeCeiling)
+	defaultChunk := int64(l.DefaultChunkSize)
+	clampInt(PushLimitDefaultChunkSize, &defaultChunk, min(int64(defaults.DefaultChunkSize), maxChunk), 1, maxChunk)
+
+	return PushLimits{
+		MaxFilesPerPush:       int(maxFiles),
+		MaxFileSizeBytes:      fileSize,
+		MaxTotalPushSizeBytes: totalSize,
+		DefaultChunkSize:      int(defaultChunk),
+		MaxChunkSize:          int(maxChunk),
+		Sources:               sources,
+		Host:                  l.Host,
+		HostType:              l.HostType,
+		EnterpriseVersion:     l.EnterpriseVersion,
+	}, clamped
+}
+
+// maxChunkBytes returns the largest chunk in bytes, leaving a safety margin below the push limit.
+func (l PushLimits) maxChunkBytes() int64 {
+	return int64(float64(l.MaxTotalPushSizeBytes) * ChunkSafetyMarginPercent)
+}
+
+// pushLimits holds the limits in effect, set at startup by SetPushLimits.
+var pushLimits atomic.Pointer[PushLimits]
+
+// SetPushLimits normalizes l and makes it the limits in effect. It returns the keys of the limits
+// that were clamped. Tool schemas describe the limits in effect when they are created, so the
+// limits should be set before the tools are.
+func SetPushLimits(l PushLimits) []string {
+	normalized, clamped := l.Normalize()
+	pushLimits.Store(&normalized)
+	return clamped
+}
+
+// CurrentPushLimits returns the limits in effect.
+func CurrentPushLimits() PushLimits {
+	if l := pushLimits.Load(); l != nil {
+		return *l
+	}
+	return DefaultPushLimits()
+}
+
+// Kinds of GitHub hosts reported in PushLimits.HostType
+const (
+	HostTypeDotcom = "github.com"
+	HostTypeGHEC   = "ghe.com"
+	HostTypeGHES   = "ghes"
+)
+
+// LimitsProvider resolves the push limits of the GitHub host a client targets.
+type LimitsProvider interface {
+	PushLimits(ctx context.Context, client *github.Client) (PushLimits, error)
+}
+
+// limitsProvider holds the provider in effect, set at startup by SetLimitsProvider.
+var limitsProvider atomic.Pointer[LimitsProvider]
+
+// SetLimitsProvider makes p resolve the push limits checked by the file tools. A nil p
+// uses the limits in effect for every host.
+func SetLimitsProvider(p LimitsProvider) {
+	if p == nil {
+		limitsProvider.Store(nil)
+		return
+	}
+	limitsProvider.Store(&p)
+}
+
+// pushLimitsFor returns the push limits of the host client targets. It falls back to the
+// limits in effect when no provider is set or the provider fails.
+func pushLimitsFor(ctx context.Context, client *github.Client) PushLimits {
+	p := limitsProvider.Load()
+	if p == nil {
+		return CurrentPushLimits()
+	}
+	limits, err := (*p).PushLimits(ctx, client)
+	if err != nil {
+		return CurrentPushLimits()
+	}
+	return limits
+}
+
+// HostLimitsProvider is a LimitsProvider with per-host configuration. The non-zero limits of a
+// configured host replace the limits in effect, and other hosts get the limits in effect.
+// The first time it sees a host other than github.com or ghe.com, it queries the host to
+// detect GitHub Enterprise Server and its version.
+type HostLimitsProvider struct {
+	hosts map[string]PushLimits
+
+	mu       sync.Mutex
+	detected map[string]hostInfo
+}
+
+// hostInfo is what HostLimitsProvider detected about a host.
+type hostInfo struct {
+	hostType          string
+	enterpriseVersion string
+}
+
+// NewHostLimitsProvider returns a provider applying hosts, keyed by hostname such as
+// github.example.com.
+func NewHostLimitsProvider(hosts map[string]PushLimits) *HostLimitsProvider {
+	normalized := make(map[string]PushLimits, len(hosts))
+	for host, limits := range hosts {
+		normalized[strings.ToLower(strings.TrimSpace(host))] = limits
+	}
+	return &HostLimitsProvider{
+		hosts:    normalized,
+		detected: make(map[string]hostInfo),
+	}
+}
+
+// PushLimits returns the limits of the host client targets.
+func (p *HostLimitsProvider) PushLimits(ctx context.Context, client *github.Client) (PushLimits, error) {
+	host := clientHost(client)
+	limits := CurrentPushLimits()
+	if override, ok := p.hosts[host]; ok {
+		limits = mergePushLimits(limits, override, fmt.Sprintf("host %s", host))
+	}
+	info := p.detect(ctx, client, host)
+	limits.Host = host
+	limits.HostType = info.hostType
+	limits.EnterpriseVersion = info.enterpriseVersion
+	normalized, _ := limits.Normalize()
+	return normalized, nil
+}
+
+// detect returns what is known about host, querying it once when it is not a GitHub cloud host.
+// Failed queries are retried on the next call.
+func (p *HostLimitsProvider) detect(ctx context.Context, client *github.Client, host string) hostInfo {
+	switch {
+	case host == "github.com":
+		return hostInfo{hostType: HostTypeDotcom}
+	case strings.HasSuffix(host, ".ghe.com"):
+		return hostInfo{hostType: HostTypeGHEC}
+	}
+
+	p.mu.Lock()
+	info, ok := p.detected[host]
+	p.mu.Unlock()
+	if ok {
+		return info
+	}
+
+	version, err := EnterpriseVersion(ctx, client)
+	if err != nil {
+		return hostInfo{}
+	}
+	if version != "" {
+		info = hostInfo{hostType: HostTypeGHES, enterpriseVersion: version}
+	}
+
+	p.mu.Lock()
+	p.detected[host] = info
+	p.mu.Unlock()
+	return info
+}
+
+// clientHost returns the GitHub host client targets, without the api. prefix of cloud API hosts.
+func clientHost(client *github.Client) string {
+	host := strings.ToLower(client.BaseURL.Hostname())
+	if host == "api.github.com" || strings.HasSuffix(host, ".ghe.com") {
+		host = strings.TrimPrefix(host, "api.")
+	}
+	return host
+}
+
+// mergePushLimits returns base with the non-zero limits of override, whose sources are
+// those of override or source when it names none.
+func mergePushLimits(base, override PushLimits, source string) PushLimits {
+	merged := base
+	merged.Sources = make(map[string]string, len(base.Sources))
+	for key, s := range base.Sources {
+		merged.Sources[key] = s
+	}
+	setSource := func(key string) {
+		if s, ok := override.Sources[key]; ok && s != "" {
+			merged.Sources[key] = s
+			return
+		}
+		merged.Sources[key] = source
+	}
+	if override.MaxFilesPerPush != 0 {
+		merged.MaxFilesPerPush = override.MaxFilesPerPush
+		setSource(PushLimitMaxFilesPerPush)
+	}
+	if override.MaxFileSizeBytes != 0 {
+		merged.MaxFileSizeBytes = override.MaxFileSizeBytes
+		setSource(PushLimitMaxFileSizeBytes)
+	}
+	if override.MaxTotalPushSizeBytes != 0 {
+		merged.MaxTotalPushSizeBytes = override.MaxTotalPushSizeBytes
+		setSource(PushLimitMaxTotalPushSizeBytes)
+	}
+	if override.DefaultChunkSize != 0 {
+		merged.DefaultChunkSize = override.DefaultChunkSize
+		setSource(PushLimitDefaultChunkSize)
+	}
+	if override.MaxChunkSize != 0 {
+		merged.MaxChunkSize = override.MaxChunkSize
+		setSource(PushLimitMaxChunkSize)
+	}
+	return merged
+}
@@ -0,0 +1,213 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v79/github"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_PushLimitsNormalize(t *testing.T) {
+	tests := []struct {
+		name            string
+		limits          PushLimits
+		expected        PushLimits
+		expectedClamped []string
+		expectedSources map[string]string
+	}{
+		{
+			name:     "unset limits use the defaults",
+			limits:   PushLimits{},
+			expected: DefaultPushLimits(),
+			expectedSources: map[string]string{
+				PushLimitMaxFilesPerPush: PushLimitSourceDefault,
+				PushLimitMaxChunkSize:    PushLimitSourceDefault,
+			},
+		},
+		{
+			name: "limits in range are kept",
+			limits: PushLimits{
+				MaxFilesPerPush:  500,
+				MaxFileSizeBytes: 50 * 1024 * 1024,
+				Sources:          map[string]string{PushLimitMaxFilesPerPush: "env", PushLimitMaxFileSizeBytes: "file"},
+			},
+			expected: PushLimits{
+				MaxFilesPerPush:       500,
+				MaxFileSizeBytes:      50 * 1024 * 1024,
+				MaxTotalPushSizeBytes: MaxTotalPushSizeBytes,
+				DefaultChunkSize:      DefaultChunkSize,
+				MaxChunkSize:          MaxChunkSize,
+			},
+			expectedSources: map[string]string{
+				PushLimitMaxFilesPerPush:  "env",
+				PushLimitMaxFileSizeBytes: "file",
+				PushLimitMaxChunkSize:     PushLimitSourceDefault,
+			},
+		},
+		{
+			name: "limits out of range are clamped",
+			limits: PushLimits{
+				MaxFilesPerPush:       5000,
+				MaxTotalPushSizeBytes: 10 * 1024 * 1024,
+				MaxFileSizeBytes:      20 * 1024 * 1024,
+				MaxChunkSize:          20,
+				DefaultChunkSize:      30,
+				Sources: map[string]string{
+					PushLimitMaxFilesPerPush:  "env",
+					PushLimitMaxFileSizeBytes: "file",
+					PushLimitDefaultChunkSize: "file",
+				},
+			},
+			expected: PushLimits{
+				MaxFilesPerPush:       maxFilesPerPushCeiling,
+				MaxFileSizeBytes:      10 * 1024 * 1024,
+				MaxTotalPushSizeBytes: 10 * 1024 * 1024,
+				DefaultChunkSize:      20,
+				MaxChunkSize:          20,
+			},
+			expectedClamped: []string{PushLimitMaxFilesPerPush, PushLimitMaxFileSizeBytes, PushLimitDefaultChunkSize},
+			expectedSources: map[string]string{
+				PushLimitMaxFilesPerPush:  "env (clamped)",
+				PushLimitMaxFileSizeBytes: "file (clamped)",
+				PushLimitDefaultChunkSize: "file (clamped)",
+			},
+		},
+		{
+			name:   "defaults follow smaller configured limits",
+			limits: PushLimits{MaxTotalPushSizeBytes: 10 * 1024 * 1024, MaxChunkSize: 10},
+			expected: PushLimits{
+				MaxFilesPerPush:       MaxFilesPerPush,
+				MaxFileSizeBytes:      10 * 1024 * 1024,
+				MaxTotalPushSizeBytes: 10 * 1024 * 1024,
+				DefaultChunkSize:      10,
+				MaxChunkSize:          10,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			normalized, clamped := tc.limits.Normalize()
+			assert.Equal(t, tc.expectedClamped, clamped)
+			assert.Equal(t, tc.expected.MaxFilesPerPush, normalized.MaxFilesPerPush)
+			assert.Equal(t, tc.expected.MaxFileSizeBytes, normalized.MaxFileSizeBytes)
+			assert.Equal(t, tc.expected.MaxTotalPushSizeBytes, normalized.MaxTotalPushSizeBytes)
+			assert.Equal(t, tc.expected.DefaultChunkSize, normalized.DefaultChunkSize)
+			assert.Equal(t, tc.expected.MaxChunkSize, normalized.MaxChunkSize)
+			for key, source := range tc.expectedSources {
+				assert.Equal(t, source, normalized.Source(key), key)
+			}
+		})
+	}
+}
+
+func Test_GetPushLimits(t *testing.T) {
+	tool, _ := GetPushLimits(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	t.Cleanup(func() { SetPushLimits(PushLimits{}) })
+	SetPushLimits(PushLimits{
+		MaxFilesPerPush: 250,
+		Sources:         map[string]string{PushLimitMaxFilesPerPush: "env"},
+	})
+
+	_, handler := GetPushLimits(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	request := createMCPRequest(map[string]interface{}{})
+	result, _, err := handler(context.Background(), &request, map[string]interface{}{})
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+
+	var limits struct {
+		MaxFilesPerPush  int               `json:"max_files_per_push"`
+		MaxFileSizeBytes int64             `json:"max_file_size_bytes"`
+		Sources          map[string]string `json:"sources"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &limits))
+	assert.Equal(t, 250, limits.MaxFilesPerPush)
+	assert.Equal(t, int64(MaxFileSizeBytes), limits.MaxFileSizeBytes)
+	assert.Equal(t, "env", limits.Sources[PushLimitMaxFilesPerPush])
+	assert.Equal(t, PushLimitSourceDefault, limits.Sources[PushLimitMaxFileSizeBytes])
+
+	// Validation uses the limits in effect
+	errResult, _ := ValidateFileCount(251, CurrentPushLimits().MaxFilesPerPush)
+	require.NotNil(t, errResult)
+	assert.Contains(t, getErrorResult(t, errResult).Text, "maximum of 250")
+}
+
+func Test_HostLimitsProvider(t *testing.T) {
+	t.Cleanup(func() { SetPushLimits(PushLimits{}) })
+	SetPushLimits(PushLimits{
+		MaxFilesPerPush: 250,
+		Sources:         map[string]string{PushLimitMaxFilesPerPush: "env"},
+	})
+
+	provider := NewHostLimitsProvider(map[string]PushLimits{
+		"GHES.example.com": {
+			MaxFileSizeBytes:      10 * 1024 * 1024,
+			MaxTotalPushSizeBytes: 50 * 1024 * 1024,
+			Sources:               map[string]string{PushLimitMaxFileSizeBytes: "file"},
+		},
+	})
+
+	t.Run("github.com uses the limits in effect", func(t *testing.T) {
+		limits, err := provider.PushLimits(context.Background(), github.NewClient(nil))
+		require.NoError(t, err)
+		assert.Equal(t, "github.com", limits.Host)
+		assert.Equal(t, HostTypeDotcom, limits.HostType)
+		assert.Equal(t, 250, limits.MaxFilesPerPush)
+		assert.Equal(t, int64(MaxFileSizeBytes), limits.MaxFileSizeBytes)
+	})
+
+	t.Run("configured host overrides the limits in effect", func(t *testing.T) {
+		meta := mock.NewMockedHTTPClient(
+			mock.WithRequestMatchHandler(
+				mock.EndpointPattern{Pattern: "/api/v3/meta", Method: "GET"},
+				http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+					w.Header().Set("X-GitHub-Enterprise-Version", "3.14.2")
+					_, _ = w.Write([]byte(`{}`))
+				}),
+			),
+		)
+		client, err := github.NewClient(meta).WithEnterpriseURLs("https://ghes.example.com/api/v3/", "https://ghes.example.com/api/uploads/")
+		require.NoError(t, err)
+
+		limits, err := provider.PushLimits(context.Background(), client)
+		require.NoError(t, err)
+		assert.Equal(t, "ghes.example.com", limits.Host)
+		assert.Equal(t, HostTypeGHES, limits.HostType)
+		assert.Equal(t, "3.14.2", limits.EnterpriseVersion)
+		assert.Equal(t, 250, limits.MaxFilesPerPush)
+		assert.Equal(t, int64(10*1024*1024), limits.MaxFileSizeBytes)
+		assert.Equal(t, int64(50*1024*1024), limits.MaxTotalPushSizeBytes)
+		assert.Equal(t, "file", limits.Source(PushLimitMaxFileSizeBytes))
+		assert.Equal(t, "host ghes.example.com", limits.Source(PushLimitMaxTotalPushSizeBytes))
+		assert.Equal(t, "env", limits.Source(PushLimitMaxFilesPerPush))
+
+		// Validation is checked against the limits of the host
+		result, err := ValidateFileSize(limits, "big.bin", 11*1024*1024)
+		require.Error(t, err)
+		require.NotNil(t, result)
+		var validationErr *ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, "ghes.example.com", validationErr.Details["host"])
+		_, err = ValidateFileSize(DefaultPushLimits(), "big.bin", 11*1024*1024)
+		assert.NoError(t, err)
+	})
+
+	t.Run("ghe.com hosts are not queried", func(t *testing.T) {
+		client, err := github.NewClient(mock.NewMockedHTTPClient()).WithEnterpriseURLs("https://api.tenant.ghe.com/", "https://uploads.tenant.ghe.com/")
+		require.NoError(t, err)
+
+		limits, err := provider.PushLimits(context.Background(), client)
+		require.NoError(t, err)
+		assert.Equal(t, "tenant.ghe.com", limits.Host)
+		assert.Equal(t, HostTypeGHEC, limits.HostType)
+	})
+}
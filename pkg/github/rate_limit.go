@@ -0,0 +1,285 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	ghErrors "github.com/github/github-mcp-server/pkg/errors"
+	"github.com/github/github-mcp-server/pkg/ratelimit"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v79/github"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// RateLimitResource is the GitHub quota of one rate limit resource, such as core or search
+type RateLimitResource struct {
+	Limit     int       `json:"limit"`
+	Used      int       `json:"used"`
+	Remaining int       `json:"remaining"`
+	ResetAt   time.Time `json:"reset_at"`
+	// ResetsInSeconds is the time left until ResetAt, when the quota is restored
+	ResetsInSeconds int64 `json:"resets_in_seconds"`
+}
+
+// PriorityWaits are the waits of the requests of one priority on rate limiters
+type PriorityWaits struct {
+	Waits  int64 `json:"waits"`
+	WaitMs int64 `json:"wait_ms"`
+}
+
+// priorityWaits breaks down stats by priority, keyed by the name of the priority.
+func priorityWaits(stats ratelimit.Stats) map[string]PriorityWaits {
+	waits := make(map[string]PriorityWaits, 3)
+	for _, priority := range []ratelimit.Priority{ratelimit.PriorityHigh, ratelimit.PriorityNormal, ratelimit.PriorityLow} {
+		byPriority := stats.Priority(priority)
+		waits[priority.String()] = PriorityWaits{Waits: byPriority.Waits, WaitMs: byPriority.WaitMs}
+	}
+	return waits
+}
+
+// ClientRateLimiterStatus sums up the rate limiters a tool paces its own requests with, one per
+// token or installation
+type ClientRateLimiterStatus struct {
+	Name       string `json:"name"`
+	Identities int    `json:"identities"`
+	// Waits count the requests that went through the limiters, whether or not they had to wait
+	CoreWaits    int64 `json:"core_waits"`
+	SearchWaits  int64 `json:"search_waits"`
+	GraphQLWaits int64 `json:"graphql_waits"`
+	TotalWaitMs  int64 `json:"total_wait_ms"`
+	// ByPriority breaks the waits down by priority: high, normal and low
+	ByPriority map[string]PriorityWaits `json:"by_priority"`
+}
+
+// RequestLimitersStatus sums up the rate limiters that pace every request, one per token or
+// installation
+type RequestLimitersStatus struct {
+	Identities   int   `json:"identities"`
+	CoreWaits    int64 `json:"core_waits"`
+	SearchWaits  int64 `json:"search_waits"`
+	GraphQLWaits int64 `json:"graphql_waits"`
+	TotalWaitMs  int64 `json:"total_wait_ms"`
+	// ByPriority breaks the waits down by priority: high, normal and low
+	ByPriority map[string]PriorityWaits `json:"by_priority"`
+}
+
+// RateLimitStatus is the output of get_rate_limit_status
+type RateLimitStatus struct {
+	Resources       map[string]RateLimitResource `json:"resources"`
+	RequestLimiters RequestLimitersStatus        `json:"request_limiters"`
+	ClientLimiters  []ClientRateLimiterStatus    `json:"client_limiters"`
+}
+
+// requestLimiters pace every request of the GitHub clients of the server, through
+// ratelimit.Transport, with a limiter per token or installation.
+var requestLimiters = ratelimit.NewRegistry(newRequestLimiter, ratelimit.DefaultIdleTimeout)
+
+// newRequestLimiter creates the request limiter of a token. Its core and GraphQL bursts let short
+// bursts of calls, like the steps of a commit, through at once, as GitHub only enforces the quota
+// per hour.
+func newRequestLimiter() *ratelimit.RateLimiter {
+	limiter := ratelimit.NewDefault()
+	limiter.SetBurst(100, 5, 100)
+	return limiter
+}
+
+// RequestLimiters returns the registry of the rate limiters that pace every request of the
+// GitHub clients.
+func RequestLimiters() *ratelimit.Registry {
+	return requestLimiters
+}
+
+// namedRateLimiters are the client-side rate limiters of a tool, reported by
+// get_rate_limit_status. The transport of the clients paces the requests whose context
+// carries them, see ratelimit.WithLimiters, with the limiter of their token or installation.
+type namedRateLimiters struct {
+	name     string
+	registry *ratelimit.Registry
+}
+
+// clientRateLimiters returns the rate limiters of the tools. They are looked up on every call
+// so that replaced limiters are reported.
+func clientRateLimiters() []namedRateLimiters {
+	return []namedRateLimiters{
+		{"chunked_push", chunkPushLimiters},
+		{"issue_import", issueImportLimiters},
+		{"project_fields", projectsGraphQLLimiters},
+	}
+}
+
+// RateLimiterStats returns the stats of the rate limiters of the server, keyed by name and
+// summed over every token: the request limiters as "requests", and the limiters of the tools.
+func RateLimiterStats() map[string]ratelimit.Stats {
+	named := clientRateLimiters()
+	stats := make(map[string]ratelimit.Stats, len(named)+1)
+	stats["requests"] = requestLimiters.Stats().Stats
+	for _, limiters := range named {
+		stats[limiters.name] = limiters.registry.Stats().Stats
+	}
+	return stats
+}
+
+// requestLimiterStatePrefix prefixes the identities of the request limiters among the states
+// of RateLimiterStates, to keep them apart from the limiters of the tools
+const requestLimiterStatePrefix = "requests/"
+
+// RateLimiterStates returns the state of the rate limiters of the server, to be saved across
+// restarts: the request limiters keyed by "requests/" and their identity, and the limiters of
+// the tools keyed by name, "/" and their identity.
+func RateLimiterStates() map[string]ratelimit.State {
+	states := make(map[string]ratelimit.State)
+	for identity, state := range requestLimiters.States() {
+		states[requestLimiterStatePrefix+identity] = state
+	}
+	for _, named := range clientRateLimiters() {
+		for identity, state := range named.registry.States() {
+			states[named.name+"/"+identity] = state
+		}
+	}
+	return states
+}
+
+// RestoreRateLimiterStates restores the rate limiters of the server from states saved by
+// RateLimiterStates. States of limiters the server no longer has are ignored.
+func RestoreRateLimiterStates(states map[string]ratelimit.State) {
+	named := clientRateLimiters()
+	restored := make(map[*ratelimit.Registry]map[string]ratelimit.State, len(named)+1)
+	restored[requestLimiters] = make(map[string]ratelimit.State)
+	for _, limiters := range named {
+		restored[limiters.registry] = make(map[string]ratelimit.State)
+	}
+	for key, state := range states {
+		if identity, ok := strings.CutPrefix(key, requestLimiterStatePrefix); ok {
+			restored[requestLimiters][identity] = state
+			continue
+		}
+		for _, limiters := range named {
+			if identity, ok := strings.CutPrefix(key, limiters.name+"/"); ok {
+				restored[limiters.registry][identity] = state
+				break
+			}
+		}
+	}
+	for registry, states := range restored {
+		registry.Restore(states)
+	}
+}
+
+// planFanOut forecasts the core quota needed by targets. It returns nil when the quota cannot be
+// read, in which case the fan-out goes ahead unplanned.
+func planFanOut(ctx context.Context, client *github.Client, targets []ratelimit.FanOutTarget) *ratelimit.FanOutPlan {
+	limits, resp, err := client.RateLimit.Get(ctx)
+	if err != nil || limits.GetCore() == nil {
+		return nil
+	}
+	_ = resp.Body.Close()
+
+	core := limits.GetCore()
+	plan := ratelimit.PlanFanOut(targets, ratelimit.Quota{Limit: core.Limit, Remaining: core.Remaining, Reset: core.Reset.Time}, time.Now())
+	return &plan
+}
+
+// approvePlanSchema is the schema of the approve_plan parameter of tools that fan out over targets,
+// such as branches.
+func approvePlanSchema(targets string) *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:        "boolean",
+		Description: fmt.Sprintf("Go ahead even when the rate limit quota runs out before all %s are done, waiting for it to reset between waves of %s. Without it, such a call makes no changes and returns the plan of waves instead", targets, targets),
+		Default:     json.RawMessage("false"),
+	}
+}
+
+// planApprovalMessage explains why a fan-out planned by plan waits for approve_plan.
+func planApprovalMessage(plan *ratelimit.FanOutPlan, outcome string) string {
+	return fmt.Sprintf(
+		"this needs about %d API calls, more than the quota allows before it resets at %s, so %s. Call again with approve_plan to go ahead in %d waves, the last starting at %s",
+		plan.EstimatedCalls, plan.ResetAt.Format(time.RFC3339), outcome, len(plan.Waves), plan.EstimatedFinish.Format(time.RFC3339),
+	)
+}
+
+// GetRateLimitStatus creates a tool to report the GitHub rate limits of the token together with
+// the client-side rate limiters of this server.
+func GetRateLimitStatus(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "get_rate_limit_status",
+		Description: t("TOOL_GET_RATE_LIMIT_STATUS_DESCRIPTION", "Get the live GitHub API quota of the token, per resource such as core, search and graphql, with when each resets, together with the client-side rate limiters this server paces all requests with, and in particular chunked pushes, issue imports and project field updates, each per token. Waits are broken down by priority: read-only tool calls wait at high priority, bulk operations such as chunked pushes and imports at low priority. Use it to decide whether to defer expensive operations. Checking the quota does not count against it."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_GET_RATE_LIMIT_STATUS_USER_TITLE", "Get rate limit status"),
+			ReadOnlyHint: true,
+		},
+		InputSchema: &jsonschema.Schema{
+			Type:       "object",
+			Properties: map[string]*jsonschema.Schema{},
+		},
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, _ map[string]any) (*mcp.CallToolResult, any, error) {
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		limits, resp, err := client.RateLimit.Get(ctx)
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to get rate limits", resp, err), nil, nil
+		}
+		_ = resp.Body.Close()
+
+		status := RateLimitStatus{Resources: make(map[string]RateLimitResource)}
+		for name, rate := range map[string]*github.Rate{
+			"core":                        limits.Core,
+			"search":                      limits.Search,
+			"graphql":                     limits.GraphQL,
+			"code_search":                 limits.CodeSearch,
+			"integration_manifest":        limits.IntegrationManifest,
+			"source_import":               limits.SourceImport,
+			"code_scanning_upload":        limits.CodeScanningUpload,
+			"actions_runner_registration": limits.ActionsRunnerRegistration,
+			"scim":                        limits.SCIM,
+			"dependency_snapshots":        limits.DependencySnapshots,
+			"audit_log":                   limits.AuditLog,
+		} {
+			// Resources the token has no access to are left out of the response
+			if rate == nil {
+				continue
+			}
+			status.Resources[name] = RateLimitResource{
+				Limit:           rate.Limit,
+				Used:            rate.Used,
+				Remaining:       rate.Remaining,
+				ResetAt:         rate.Reset.Time,
+				ResetsInSeconds: max(int64(time.Until(rate.Reset.Time).Seconds()), 0),
+			}
+		}
+
+		requests := requestLimiters.Stats()
+		status.RequestLimiters = RequestLimitersStatus{
+			Identities:   requests.Limiters,
+			CoreWaits:    requests.CoreWaits,
+			SearchWaits:  requests.SearchWaits,
+			GraphQLWaits: requests.GraphQLWaits,
+			TotalWaitMs:  requests.TotalWaitMs,
+			ByPriority:   priorityWaits(requests.Stats),
+		}
+		for _, named := range clientRateLimiters() {
+			stats := named.registry.Stats()
+			status.ClientLimiters = append(status.ClientLimiters, ClientRateLimiterStatus{
+				Name:         named.name,
+				Identities:   stats.Limiters,
+				CoreWaits:    stats.CoreWaits,
+				SearchWaits:  stats.SearchWaits,
+				GraphQLWaits: stats.GraphQLWaits,
+				TotalWaitMs:  stats.TotalWaitMs,
+				ByPriority:   priorityWaits(stats.Stats),
+			})
+		}
+
+		return MarshalledTextResult(status), nil, nil
+	})
+
+	return tool, handler
+}
@@ -0,0 +1,89 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/ratelimit"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v79/github"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_GetRateLimitStatus(t *testing.T) {
+	tool, _ := GetRateLimitStatus(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.True(t, tool.Annotations.ReadOnlyHint)
+
+	reset := time.Now().Add(30 * time.Minute).Truncate(time.Second).UTC()
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.GetRateLimit,
+			mockResponse(t, http.StatusOK, map[string]any{
+				"resources": map[string]any{
+					"core":   map[string]any{"limit": 5000, "used": 4900, "remaining": 100, "reset": reset.Unix()},
+					"search": map[string]any{"limit": 30, "used": 0, "remaining": 30, "reset": reset.Unix()},
+				},
+			}),
+		),
+	)
+	_, handler := GetRateLimitStatus(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+	request := createMCPRequest(map[string]any{})
+	result, _, err := handler(context.Background(), &request, map[string]any{})
+	require.NoError(t, err)
+	require.False(t, result.IsError, getTextResult(t, result).Text)
+
+	var status RateLimitStatus
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &status))
+	require.Len(t, status.Resources, 2)
+	core := status.Resources["core"]
+	assert.Equal(t, 5000, core.Limit)
+	assert.Equal(t, 4900, core.Used)
+	assert.Equal(t, 100, core.Remaining)
+	assert.True(t, reset.Equal(core.ResetAt))
+	assert.InDelta(t, 30*60, core.ResetsInSeconds, 5)
+	assert.Equal(t, 30, status.Resources["search"].Remaining)
+	assert.Contains(t, getTextResult(t, result).Text, `"request_limiters"`)
+
+	names := make([]string, 0, len(status.ClientLimiters))
+	for _, limiter := range status.ClientLimiters {
+		names = append(names, limiter.Name)
+	}
+	assert.Equal(t, []string{"chunked_push", "issue_import", "project_fields"}, names)
+	assert.Len(t, status.RequestLimiters.ByPriority, 3)
+	assert.Contains(t, status.ClientLimiters[0].ByPriority, "high")
+}
+
+func Test_RateLimiterStates(t *testing.T) {
+	defaultRequests, defaultImports := requestLimiters, issueImportLimiters
+	t.Cleanup(func() { requestLimiters, issueImportLimiters = defaultRequests, defaultImports })
+	requestLimiters = ratelimit.NewRegistry(newRequestLimiter, time.Hour)
+	issueImportLimiters = ratelimit.NewRegistry(nil, time.Hour)
+
+	for range 3 {
+		require.NoError(t, issueImportLimiters.Get("token:abc").WaitCore(context.Background()))
+		require.NoError(t, requestLimiters.Get("token:abc").WaitCore(context.Background()))
+	}
+
+	states := RateLimiterStates()
+	assert.Contains(t, states, "requests/token:abc")
+	assert.Contains(t, states, "issue_import/token:abc")
+	states["removed_limiter"] = ratelimit.State{}
+
+	// A restarted server starts from the saved states
+	requestLimiters = ratelimit.NewRegistry(newRequestLimiter, time.Hour)
+	issueImportLimiters = ratelimit.NewRegistry(nil, time.Hour)
+	RestoreRateLimiterStates(states)
+
+	assert.Equal(t, 1, issueImportLimiters.Stats().Limiters)
+	assert.InDelta(t, states["issue_import/token:abc"].Core.Tokens, issueImportLimiters.Get("token:abc").GetTokens().Core, 0.1)
+	assert.Equal(t, 1, requestLimiters.Stats().Limiters)
+	assert.InDelta(t, states["requests/token:abc"].Core.Tokens, requestLimiters.Get("token:abc").GetTokens().Core, 0.1)
+}
@@ -0,0 +1,622 @@
+package github
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	ghErrors "github.com/github/github-mcp-server/pkg/errors"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/github/github-mcp-server/pkg/uploads"
+	"github.com/github/github-mcp-server/pkg/utils"
+	"github.com/google/go-github/v79/github"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	// maxReleaseAssetSize is the largest file GitHub accepts as a release asset
+	maxReleaseAssetSize = 2 * 1024 * 1024 * 1024
+	// assetUploadSessionTTL is how long a chunked asset upload is kept after its last chunk
+	assetUploadSessionTTL = time.Hour
+	// maxAssetUploadSessions is the number of chunked asset uploads in progress at once
+	maxAssetUploadSessions = 100
+)
+
+// MinimalReleaseAsset is the trimmed output type for release assets.
+type MinimalReleaseAsset struct {
+	ID                 int64  `json:"id"`
+	Name               string `json:"name"`
+	Label              string `json:"label,omitempty"`
+	ContentType        string `json:"content_type"`
+	Size               int    `json:"size"`
+	State              string `json:"state"`
+	BrowserDownloadURL string `json:"browser_download_url"`
+}
+
+func convertToMinimalReleaseAsset(asset *github.ReleaseAsset) MinimalReleaseAsset {
+	return MinimalReleaseAsset{
+		ID:                 asset.GetID(),
+		Name:               asset.GetName(),
+		Label:              asset.GetLabel(),
+		ContentType:        asset.GetContentType(),
+		Size:               asset.GetSize(),
+		State:              asset.GetState(),
+		BrowserDownloadURL: asset.GetBrowserDownloadURL(),
+	}
+}
+
+// releaseFieldsSchema describes the release fields shared by create_release and update_release.
+func releaseFieldsSchema() map[string]*jsonschema.Schema {
+	return map[string]*jsonschema.Schema{
+		"owner": {
+			Type:        "string",
+			Description: "Repository owner",
+		},
+		"repo": {
+			Type:        "string",
+			Description: "Repository name",
+		},
+		"tag_name": {
+			Type:        "string",
+			Description: "Tag of the release, e.g. 'v1.2.0'. The tag is created from target_commitish if it does not exist",
+		},
+		"target_commitish": {
+			Type:        "string",
+			Description: "Branch or commit SHA the tag is created from when it does not exist. Defaults to the default branch",
+		},
+		"name": {
+			Type:        "string",
+			Description: "Release title",
+		},
+		"body": {
+			Type:        "string",
+			Description: "Release notes in Markdown",
+		},
+		"draft": {
+			Type:        "boolean",
+			Description: "Whether the release is an unpublished draft",
+		},
+		"prerelease": {
+			Type:        "boolean",
+			Description: "Whether the release is marked as a prerelease",
+		},
+		"make_latest": {
+			Type:        "string",
+			Description: "Whether the release becomes the latest release of the repository. 'legacy' picks the latest by creation date and semantic version",
+			Enum:        []any{"true", "false", "legacy"},
+		},
+	}
+}
+
+// releaseFromArgs returns the release fields set in args, and how many there are.
+func releaseFromArgs(args map[string]any) (*github.RepositoryRelease, int, error) {
+	release := &github.RepositoryRelease{}
+	fields := 0
+	for name, field := range map[string]**string{
+		"tag_name":         &release.TagName,
+		"target_commitish": &release.TargetCommitish,
+		"name":             &release.Name,
+		"body":             &release.Body,
+		"make_latest":      &release.MakeLatest,
+	} {
+		if _, ok := args[name]; !ok {
+			continue
+		}
+		value, err := OptionalParam[string](args, name)
+		if err != nil {
+			return nil, 0, err
+		}
+		*field = github.Ptr(value)
+		fields++
+	}
+	for name, field := range map[string]**bool{
+		"draft":      &release.Draft,
+		"prerelease": &release.Prerelease,
+	} {
+		if _, ok := args[name]; !ok {
+			continue
+		}
+		value, err := OptionalParam[bool](args, name)
+		if err != nil {
+			return nil, 0, err
+		}
+		*field = github.Ptr(value)
+		fields++
+	}
+	return release, fields, nil
+}
+
+func convertToMinimalRelease(release *github.RepositoryRelease) MinimalRelease {
+	m := MinimalRelease{
+		ID:         release.GetID(),
+		TagName:    release.GetTagName(),
+		Name:       release.GetName(),
+		Body:       release.GetBody(),
+		HTMLURL:    release.GetHTMLURL(),
+		Prerelease: release.GetPrerelease(),
+		Draft:      release.GetDraft(),
+		Author:     convertToMinimalUser(release.GetAuthor()),
+	}
+	if release.PublishedAt != nil {
+		m.PublishedAt = release.PublishedAt.Format(time.RFC3339)
+	}
+	return m
+}
+
+// CreateRelease creates a tool to create a release in a GitHub repository.
+func CreateRelease(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	properties := releaseFieldsSchema()
+	properties["generate_release_notes"] = &jsonschema.Schema{
+		Type:        "boolean",
+		Description: "Whether to generate the name and notes of the release from the changes since the previous release. A given name or body takes precedence",
+	}
+
+	tool := mcp.Tool{
+		Name:        "create_release",
+		Description: t("TOOL_CREATE_RELEASE_DESCRIPTION", "Create a release in a GitHub repository, creating its tag if needed. Use upload_release_asset to attach files to it."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_CREATE_RELEASE_USER_TITLE", "Create release"),
+			ReadOnlyHint: false,
+		},
+		InputSchema: &jsonschema.Schema{
+			Type:       "object",
+			Properties: properties,
+			Required:   []string{"owner", "repo", "tag_name"},
+		},
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		repo, err := RequiredParam[string](args, "repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		if _, err := RequiredParam[string](args, "tag_name"); err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		release, _, err := releaseFromArgs(args)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		generateNotes, err := OptionalParam[bool](args, "generate_release_notes")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		if generateNotes {
+			release.GenerateReleaseNotes = github.Ptr(true)
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		created, resp, err := client.Repositories.CreateRelease(ctx, owner, repo, release)
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to create release", resp, err), nil, nil
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		return MarshalledTextResult(convertToMinimalRelease(created)), nil, nil
+	})
+
+	return tool, handler
+}
+
+// UpdateRelease creates a tool to edit an existing release.
+func UpdateRelease(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	properties := releaseFieldsSchema()
+	properties["release_id"] = &jsonschema.Schema{
+		Type:        "number",
+		Description: "ID of the release, as returned by list_releases or create_release",
+	}
+
+	tool := mcp.Tool{
+		Name:        "update_release",
+		Description: t("TOOL_UPDATE_RELEASE_DESCRIPTION", "Update a release in a GitHub repository, e.g. to edit its notes or publish a draft. Only the fields given are changed."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_UPDATE_RELEASE_USER_TITLE", "Update release"),
+			ReadOnlyHint: false,
+		},
+		InputSchema: &jsonschema.Schema{
+			Type:       "object",
+			Properties: properties,
+			Required:   []string{"owner", "repo", "release_id"},
+		},
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		repo, err := RequiredParam[string](args, "repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		releaseID, err := RequiredInt(args, "release_id")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		release, fields, err := releaseFromArgs(args)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		if fields == 0 {
+			return utils.NewToolResultError("nothing to update: set at least one release field"), nil, nil
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		updated, resp, err := client.Repositories.EditRelease(ctx, owner, repo, int64(releaseID), release)
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to update release", resp, err), nil, nil
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		return MarshalledTextResult(convertToMinimalRelease(updated)), nil, nil
+	})
+
+	return tool, handler
+}
+
+// assetUploadSession is a release asset staged in a temporary file, chunk by chunk, until it is
+// uploaded.
+type assetUploadSession struct {
+	id        string
+	target    string // owner/repo, release and asset name the chunks belong to
+	file      *os.File
+	size      int64
+	expires   time.Time
+	uploading bool
+}
+
+// assetUploadSessions holds the chunked release asset uploads in progress.
+type assetUploadSessions struct {
+	mu       sync.Mutex
+	ttl      time.Duration
+	max      int
+	sessions map[string]*assetUploadSession
+}
+
+// releaseAssetUploads holds the chunked uploads of upload_release_asset.
+var releaseAssetUploads = newAssetUploadSessions(assetUploadSessionTTL, maxAssetUploadSessions)
+
+func newAssetUploadSessions(ttl time.Duration, max int) *assetUploadSessions {
+	return &assetUploadSessions{ttl: ttl, max: max, sessions: make(map[string]*assetUploadSession)}
+}
+
+// start stages a new asset for target.
+func (s *assetUploadSessions) start(target string, now time.Time) (*assetUploadSession, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.prune(now)
+	if len(s.sessions) >= s.max {
+		return nil, fmt.Errorf("too many release asset uploads in progress; finish or abandon one first")
+	}
+	file, err := os.CreateTemp("", "release-asset-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to stage release asset: %w", err)
+	}
+	var id [16]byte
+	_, _ = rand.Read(id[:])
+	session := &assetUploadSession{
+		id:        hex.EncodeToString(id[:]),
+		target:    target,
+		file:      file,
+		expires:   now.Add(s.ttl),
+		uploading: true,
+	}
+	s.sessions[session.id] = session
+	return session, nil
+}
+
+// acquire returns the session with the given ID for target, which the caller holds until it
+// calls release or remove.
+func (s *assetUploadSessions) acquire(id, target string, now time.Time) (*assetUploadSession, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.prune(now)
+	session, ok := s.sessions[id]
+	switch {
+	case !ok:
+		return nil, fmt.Errorf("upload session %s not found or expired; start again without session_id", id)
+	case session.target != target:
+		return nil, fmt.Errorf("upload session %s belongs to a different repository, release or asset name", id)
+	case session.uploading:
+		return nil, fmt.Errorf("upload session %s is busy with another chunk; retry once it has finished", id)
+	}
+	session.uploading = true
+	return session, nil
+}
+
+// release hands the session back, keeping it for the next chunk.
+func (s *assetUploadSessions) release(session *assetUploadSession, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	session.uploading = false
+	session.expires = now.Add(s.ttl)
+}
+
+// remove deletes the session and its staged file.
+func (s *assetUploadSessions) remove(session *assetUploadSession) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, session.id)
+	session.discard()
+}
+
+func (s *assetUploadSessions) prune(now time.Time) {
+	for id, session := range s.sessions {
+		if !session.uploading && now.After(session.expires) {
+			delete(s.sessions, id)
+			session.discard()
+		}
+	}
+}
+
+func (session *assetUploadSession) discard() {
+	_ = session.file.Close()
+	_ = os.Remove(session.file.Name())
+}
+
+// write adds chunk, which starts at offset, to the staged asset. A chunk that was already
+// received is accepted again, so that a client can resend a chunk it got no answer for.
+func (session *assetUploadSession) write(chunk []byte, offset int64) error {
+	if offset > session.size {
+		return fmt.Errorf("chunk starts at offset %d, but only %d bytes were received; resend from offset %d", offset, session.size, session.size)
+	}
+	end := offset + int64(len(chunk))
+	if end <= session.size {
+		return nil
+	}
+	if end > maxReleaseAssetSize {
+		return fmt.Errorf("release assets must be smaller than %s", FormatFileSize(maxReleaseAssetSize))
+	}
+	if _, err := session.file.WriteAt(chunk[session.size-offset:], session.size); err != nil {
+		return fmt.Errorf("failed to stage release asset: %w", err)
+	}
+	session.size = end
+	return nil
+}
+
+// AssetUploadProgress reports a chunked upload that is not finished yet.
+type AssetUploadProgress struct {
+	SessionID     string `json:"session_id"`
+	ReceivedBytes int64  `json:"received_bytes"`
+	Message       string `json:"message"`
+}
+
+// UploadReleaseAsset creates a tool to attach a file to a release. Large files are sent as a
+// series of base64 chunks that are staged on the server and uploaded once the last one arrives.
+func UploadReleaseAsset(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name: "upload_release_asset",
+		Description: t("TOOL_UPLOAD_RELEASE_ASSET_DESCRIPTION", `Upload a file as an asset of a release.
+Small files can be sent in one call with final=true. Large files are sent in chunks: the first call, without session_id, returns a session_id; send the following chunks with that session_id and the offset they start at, and set final=true on the last one to upload the asset.
+If a call fails, the response reports the bytes received so far: resend from that offset, or call again with final=true and no content to retry the upload itself. Sessions expire an hour after their last chunk.`),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_UPLOAD_RELEASE_ASSET_USER_TITLE", "Upload release asset"),
+			ReadOnlyHint: false,
+		},
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"owner": {
+					Type:        "string",
+					Description: "Repository owner",
+				},
+				"repo": {
+					Type:        "string",
+					Description: "Repository name",
+				},
+				"release_id": {
+					Type:        "number",
+					Description: "ID of the release, as returned by list_releases or create_release",
+				},
+				"name": {
+					Type:        "string",
+					Description: "File name of the asset, e.g. 'tool_linux_amd64.tar.gz'",
+				},
+				"label": {
+					Type:        "string",
+					Description: "Display name of the asset, shown instead of the file name",
+				},
+				"content_type": {
+					Type:        "string",
+					Description: "Media type of the asset. Defaults to the type of the file name's extension, or application/octet-stream",
+				},
+				"content": {
+					Type:        "string",
+					Description: "This chunk of the file, base64-encoded on its own",
+				},
+				"upload_id": uploadIDSchema(),
+				"session_id": {
+					Type:        "string",
+					Description: "Session returned by the first chunk of a chunked upload",
+				},
+				"offset": {
+					Type:        "number",
+					Description: "Byte offset of this chunk in the file",
+					Default:     json.RawMessage(`0`),
+					Minimum:     jsonschema.Ptr(0.0),
+				},
+				"final": {
+					Type:        "boolean",
+					Description: "Whether this is the last chunk, after which the asset is uploaded",
+					Default:     json.RawMessage(`true`),
+				},
+			},
+			Required: []string{"owner", "repo", "release_id", "name"},
+		},
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		repo, err := RequiredParam[string](args, "repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		releaseID, err := RequiredInt(args, "release_id")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		name, err := RequiredParam[string](args, "name")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		label, err := OptionalParam[string](args, "label")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		contentType, err := OptionalParam[string](args, "content_type")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		content, err := OptionalParam[string](args, "content")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		uploadID, err := OptionalParam[string](args, "upload_id")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		sessionID, err := OptionalParam[string](args, "session_id")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		offset, err := OptionalIntParam(args, "offset")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		final, err := OptionalBoolParamWithDefault(args, "final", true)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		if offset < 0 {
+			return utils.NewToolResultError("offset must not be negative"), nil, nil
+		}
+		if contentType == "" {
+			contentType = mime.TypeByExtension(filepath.Ext(name))
+		}
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		opts := &github.UploadOptions{Name: name, Label: label, MediaType: contentType}
+
+		if uploadID != "" {
+			if content != "" || sessionID != "" {
+				return utils.NewToolResultError("upload_id cannot be combined with content or session_id"), nil, nil
+			}
+			store, ok := uploads.StoreFromContext(ctx)
+			if !ok {
+				return utils.NewToolResultError("this server does not accept uploads; send the file as base64 content instead"), nil, nil
+			}
+			file, err := store.Open(uploadID)
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			defer func() { _ = file.Close() }()
+			return uploadReleaseAssetFile(ctx, getClient, owner, repo, int64(releaseID), opts, file, "")
+		}
+
+		chunk, err := decodeBase64Content(content)
+		if err != nil {
+			return utils.NewToolResultError(fmt.Sprintf("content is not valid base64: %v", err)), nil, nil
+		}
+
+		target := fmt.Sprintf("%s/%s\x00%d\x00%s", owner, repo, releaseID, name)
+		var session *assetUploadSession
+		if sessionID == "" {
+			if offset != 0 {
+				return utils.NewToolResultError("the first chunk starts at offset 0; send later chunks with the session_id it returned"), nil, nil
+			}
+			session, err = releaseAssetUploads.start(target, time.Now())
+		} else {
+			session, err = releaseAssetUploads.acquire(sessionID, target, time.Now())
+		}
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		if err := session.write([]byte(chunk), int64(offset)); err != nil {
+			releaseAssetUploads.release(session, time.Now())
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		if !final {
+			releaseAssetUploads.release(session, time.Now())
+			return MarshalledTextResult(AssetUploadProgress{
+				SessionID:     session.id,
+				ReceivedBytes: session.size,
+				Message:       fmt.Sprintf("Chunk received; send the chunk at offset %d next", session.size),
+			}), nil, nil
+		}
+		if session.size == 0 {
+			releaseAssetUploads.remove(session)
+			return utils.NewToolResultError("the asset is empty; send its content"), nil, nil
+		}
+
+		// The upload closes the file it sends, so each attempt opens the staged file anew
+		staged, err := os.Open(session.file.Name())
+		if err != nil {
+			releaseAssetUploads.remove(session)
+			return nil, nil, fmt.Errorf("failed to read staged release asset: %w", err)
+		}
+		defer func() { _ = staged.Close() }()
+		result, out, err := uploadReleaseAssetFile(ctx, getClient, owner, repo, int64(releaseID), opts, staged, session.id)
+		if err != nil || (result != nil && result.IsError) {
+			// Keep the staged file so that the upload can be retried without sending it again
+			releaseAssetUploads.release(session, time.Now())
+			return result, out, err
+		}
+		releaseAssetUploads.remove(session)
+		return result, out, err
+	})
+
+	return tool, handler
+}
+
+// uploadReleaseAssetFile streams file to the uploads endpoint of the release.
+func uploadReleaseAssetFile(ctx context.Context, getClient GetClientFn, owner, repo string, releaseID int64, opts *github.UploadOptions, file *os.File, sessionID string) (*mcp.CallToolResult, any, error) {
+	client, err := getClient(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+	}
+
+	asset, resp, err := client.Repositories.UploadReleaseAsset(ctx, owner, repo, releaseID, opts, file)
+	if err != nil {
+		message := "failed to upload release asset"
+		if sessionID != "" {
+			message = fmt.Sprintf("failed to upload release asset; call again with session_id %s and final=true to retry", sessionID)
+		}
+		var ghErr *github.ErrorResponse
+		if errors.As(err, &ghErr) && ghErr.Response != nil && ghErr.Response.StatusCode == http.StatusUnprocessableEntity {
+			message += ". An asset with this name may already exist on the release"
+		}
+		return ghErrors.NewGitHubAPIErrorResponse(ctx, message, resp, err), nil, nil
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	return MarshalledTextResult(convertToMinimalReleaseAsset(asset)), nil, nil
+}
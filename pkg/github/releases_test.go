@@ -0,0 +1,271 @@
+package github
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/github/github-mcp-server/pkg/uploads"
+	"github.com/google/go-github/v79/github"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CreateRelease(t *testing.T) {
+	tool, _ := CreateRelease(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.PostReposReleasesByOwnerByRepo,
+			expectRequestBody(t, map[string]any{
+				"tag_name":               "v1.2.0",
+				"target_commitish":       "main",
+				"draft":                  true,
+				"generate_release_notes": true,
+			}).andThen(
+				mockResponse(t, http.StatusCreated, &github.RepositoryRelease{
+					ID:      github.Ptr(int64(7)),
+					TagName: github.Ptr("v1.2.0"),
+					Name:    github.Ptr("v1.2.0"),
+					Draft:   github.Ptr(true),
+					HTMLURL: github.Ptr("https://github.com/owner/repo/releases/tag/v1.2.0"),
+				}),
+			),
+		),
+	)
+	_, handler := CreateRelease(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+	args := map[string]any{
+		"owner":                  "owner",
+		"repo":                   "repo",
+		"tag_name":               "v1.2.0",
+		"target_commitish":       "main",
+		"draft":                  true,
+		"generate_release_notes": true,
+	}
+	request := createMCPRequest(args)
+	result, _, err := handler(context.Background(), &request, args)
+	require.NoError(t, err)
+	require.False(t, result.IsError, getTextResult(t, result).Text)
+
+	var release MinimalRelease
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &release))
+	assert.Equal(t, int64(7), release.ID)
+	assert.True(t, release.Draft)
+
+	args = map[string]any{"owner": "owner", "repo": "repo"}
+	request = createMCPRequest(args)
+	result, _, err = handler(context.Background(), &request, args)
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	assert.Contains(t, getErrorResult(t, result).Text, "tag_name")
+}
+
+func Test_UpdateRelease(t *testing.T) {
+	tool, _ := UpdateRelease(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.PatchReposReleasesByOwnerByRepoByReleaseId,
+			expectPath(t, "/repos/owner/repo/releases/7").andThen(
+				expectRequestBody(t, map[string]any{
+					"draft":       false,
+					"make_latest": "true",
+				}).andThen(
+					mockResponse(t, http.StatusOK, &github.RepositoryRelease{
+						ID:          github.Ptr(int64(7)),
+						TagName:     github.Ptr("v1.2.0"),
+						PublishedAt: &github.Timestamp{Time: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+					}),
+				),
+			),
+		),
+	)
+	_, handler := UpdateRelease(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+	args := map[string]any{"owner": "owner", "repo": "repo", "release_id": float64(7), "draft": false, "make_latest": "true"}
+	request := createMCPRequest(args)
+	result, _, err := handler(context.Background(), &request, args)
+	require.NoError(t, err)
+	require.False(t, result.IsError, getTextResult(t, result).Text)
+
+	var release MinimalRelease
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &release))
+	assert.Equal(t, "2024-01-02T03:04:05Z", release.PublishedAt)
+
+	args = map[string]any{"owner": "owner", "repo": "repo", "release_id": float64(7)}
+	request = createMCPRequest(args)
+	result, _, err = handler(context.Background(), &request, args)
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	assert.Contains(t, getErrorResult(t, result).Text, "nothing to update")
+}
+
+// assetUploadServer records the assets uploaded to it, failing the first failures uploads.
+type assetUploadServer struct {
+	t        *testing.T
+	failures int
+	uploads  []string
+	queries  []string
+	types    []string
+}
+
+func (s *assetUploadServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	require.NoError(s.t, err)
+	if s.failures > 0 {
+		s.failures--
+		w.WriteHeader(http.StatusBadGateway)
+		_, _ = w.Write([]byte(`{"message": "Bad Gateway"}`))
+		return
+	}
+	s.uploads = append(s.uploads, string(body))
+	s.queries = append(s.queries, r.URL.RawQuery)
+	s.types = append(s.types, r.Header.Get("Content-Type"))
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(&github.ReleaseAsset{
+		ID:                 github.Ptr(int64(99)),
+		Name:               github.Ptr(r.URL.Query().Get("name")),
+		Size:               github.Ptr(len(body)),
+		State:              github.Ptr("uploaded"),
+		BrowserDownloadURL: github.Ptr("https://github.com/owner/repo/releases/download/v1/" + r.URL.Query().Get("name")),
+	})
+}
+
+func Test_UploadReleaseAsset(t *testing.T) {
+	tool, _ := UploadReleaseAsset(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	b64 := func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) }
+	newHandler := func(t *testing.T, server *assetUploadServer) func(args map[string]any) (string, bool) {
+		mockedClient := mock.NewMockedHTTPClient(
+			mock.WithRequestMatchHandler(mock.PostReposReleasesAssetsByOwnerByRepoByReleaseId, server),
+		)
+		_, handler := UploadReleaseAsset(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+		return func(args map[string]any) (string, bool) {
+			full := map[string]any{"owner": "owner", "repo": "repo", "release_id": float64(7), "name": "tool.tar.gz"}
+			for k, v := range args {
+				full[k] = v
+			}
+			request := createMCPRequest(full)
+			result, _, err := handler(context.Background(), &request, full)
+			require.NoError(t, err)
+			if result.IsError {
+				return getErrorResult(t, result).Text, true
+			}
+			return getTextResult(t, result).Text, false
+		}
+	}
+
+	t.Run("single call", func(t *testing.T) {
+		server := &assetUploadServer{t: t}
+		call := newHandler(t, server)
+
+		text, isErr := call(map[string]any{"content": b64("binary"), "label": "Linux"})
+		require.False(t, isErr, text)
+		var asset MinimalReleaseAsset
+		require.NoError(t, json.Unmarshal([]byte(text), &asset))
+		assert.Equal(t, int64(99), asset.ID)
+		assert.Equal(t, []string{"binary"}, server.uploads)
+		assert.Equal(t, "label=Linux&name=tool.tar.gz", server.queries[0])
+		assert.Equal(t, "application/gzip", server.types[0])
+	})
+
+	t.Run("chunks with a resent chunk and a retried upload", func(t *testing.T) {
+		server := &assetUploadServer{t: t, failures: 1}
+		call := newHandler(t, server)
+
+		text, isErr := call(map[string]any{"content": b64("hello "), "final": false, "content_type": "application/x-custom"})
+		require.False(t, isErr, text)
+		var progress AssetUploadProgress
+		require.NoError(t, json.Unmarshal([]byte(text), &progress))
+		assert.Equal(t, int64(6), progress.ReceivedBytes)
+		session := progress.SessionID
+
+		// Resending the first chunk is harmless
+		text, isErr = call(map[string]any{"session_id": session, "content": b64("hello "), "final": false})
+		require.False(t, isErr, text)
+
+		text, isErr = call(map[string]any{"session_id": session, "offset": float64(20), "content": b64("x"), "final": false})
+		require.True(t, isErr)
+		assert.Contains(t, text, "resend from offset 6")
+
+		text, isErr = call(map[string]any{"session_id": session, "offset": float64(6), "content": b64("world"), "content_type": "application/x-custom"})
+		require.True(t, isErr)
+		assert.Contains(t, text, "call again with session_id "+session+" and final=true to retry")
+
+		text, isErr = call(map[string]any{"session_id": session, "offset": float64(11), "content_type": "application/x-custom"})
+		require.False(t, isErr, text)
+		assert.Equal(t, []string{"hello world"}, server.uploads)
+		assert.Equal(t, "application/x-custom", server.types[0])
+
+		// The session is gone once the asset is uploaded
+		text, isErr = call(map[string]any{"session_id": session, "content": b64("again")})
+		require.True(t, isErr)
+		assert.Contains(t, text, "not found or expired")
+	})
+
+	t.Run("sessions belong to one asset", func(t *testing.T) {
+		call := newHandler(t, &assetUploadServer{t: t})
+
+		text, isErr := call(map[string]any{"content": b64("abc"), "final": false})
+		require.False(t, isErr, text)
+		var progress AssetUploadProgress
+		require.NoError(t, json.Unmarshal([]byte(text), &progress))
+
+		text, isErr = call(map[string]any{"session_id": progress.SessionID, "name": "other.zip", "offset": float64(3), "content": b64("d")})
+		require.True(t, isErr)
+		assert.Contains(t, text, "different repository, release or asset name")
+	})
+
+	t.Run("from the upload store", func(t *testing.T) {
+		server := &assetUploadServer{t: t}
+		mockedClient := mock.NewMockedHTTPClient(
+			mock.WithRequestMatchHandler(mock.PostReposReleasesAssetsByOwnerByRepoByReleaseId, server),
+		)
+		_, handler := UploadReleaseAsset(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+		store, err := uploads.NewStore(t.TempDir())
+		require.NoError(t, err)
+		upload, err := store.Put(strings.NewReader("uploaded binary"), "")
+		require.NoError(t, err)
+
+		args := map[string]any{"owner": "owner", "repo": "repo", "release_id": float64(7), "name": "tool.bin", "upload_id": upload.ID}
+		request := createMCPRequest(args)
+		result, _, err := handler(uploads.ContextWithStore(context.Background(), store), &request, args)
+		require.NoError(t, err)
+		require.False(t, result.IsError, getTextResult(t, result).Text)
+		assert.Equal(t, []string{"uploaded binary"}, server.uploads)
+		assert.Equal(t, "application/octet-stream", server.types[0])
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		call := newHandler(t, &assetUploadServer{t: t})
+
+		text, isErr := call(map[string]any{"content": "not base64!"})
+		require.True(t, isErr)
+		assert.Contains(t, text, "content is not valid base64")
+
+		text, isErr = call(map[string]any{"content": b64("x"), "offset": float64(5)})
+		require.True(t, isErr)
+		assert.Contains(t, text, "the first chunk starts at offset 0")
+
+		text, isErr = call(map[string]any{})
+		require.True(t, isErr)
+		assert.Contains(t, text, "the asset is empty")
+
+		text, isErr = call(map[string]any{"upload_id": "sha256:abc"})
+		require.True(t, isErr)
+		assert.Contains(t, text, "this server does not accept uploads")
+	})
+}
@@ -0,0 +1,316 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	ghErrors "github.com/github/github-mcp-server/pkg/errors"
+	"github.com/github/github-mcp-server/pkg/sanitize"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/github/github-mcp-server/pkg/utils"
+	"github.com/google/go-github/v79/github"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// renameTreeChunkSize is how many files rename_directory moves per tree it creates. Each moved
+// file takes two tree entries, one adding the new path and one removing the old one.
+var renameTreeChunkSize = 500
+
+// maxReportedRenameConflicts caps the conflicting paths listed when a rename is refused
+const maxReportedRenameConflicts = 10
+
+// RenameDirectoryResult reports a directory moved by rename_directory
+type RenameDirectoryResult struct {
+	CommitSHA  string `json:"commit_sha,omitempty"`
+	Ref        string `json:"ref"`
+	From       string `json:"from"`
+	To         string `json:"to"`
+	FilesMoved int    `json:"files_moved"`
+	// TreesCreated is the number of trees the entries were written in
+	TreesCreated int `json:"trees_created"`
+	// NoChanges is set when nothing was committed, as when the move left the tree unchanged
+	NoChanges bool `json:"no_changes,omitempty"`
+}
+
+// RenameDirectory creates a tool to move every file under a directory to another directory in
+// a single commit, reusing the existing blobs.
+func RenameDirectory(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "rename_directory",
+		Description: t("TOOL_RENAME_DIRECTORY_DESCRIPTION", "Rename or move a directory of a branch in a single commit. Every file under the directory is moved to the new directory with its mode preserved, reusing the existing blobs so no content is uploaded. Directories with thousands of files are supported. Fails if a moved file would replace an existing file."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_RENAME_DIRECTORY_USER_TITLE", "Rename directory"),
+			ReadOnlyHint: false,
+		},
+		InputSchema: withCommitIdentity(&jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"owner": {
+					Type:        "string",
+					Description: "Repository owner",
+				},
+				"repo": {
+					Type:        "string",
+					Description: "Repository name",
+				},
+				"branch": {
+					Type:        "string",
+					Description: "Branch to rename the directory on",
+				},
+				"from": {
+					Type:        "string",
+					Description: "Directory to move, such as 'src/old'",
+				},
+				"to": {
+					Type:        "string",
+					Description: "New path of the directory, such as 'src/new'. Must not be inside from, or from inside it",
+				},
+				"message": {
+					Type:        "string",
+					Description: "Commit message",
+				},
+				"sanitize_paths": {
+					Type:        "string",
+					Description: "Path sanitization policy applied to to. 'none' keeps it as given, 'normalize' folds unicode confusables, replaces whitespace with dashes and strips trailing dots, 'strict' additionally replaces any character outside [A-Za-z0-9._-] (including emoji) with '_'. The sanitized path is reported in the result",
+					Enum:        []any{"none", "normalize", "strict"},
+					Default:     json.RawMessage(`"none"`),
+				},
+				"allow_empty": allowEmptySchema(),
+			},
+			Required: []string{"owner", "repo", "branch", "from", "to", "message"},
+		}),
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		repo, err := RequiredParam[string](args, "repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		branch, err := RequiredParam[string](args, "branch")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		from, err := RequiredParam[string](args, "from")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		to, err := RequiredParam[string](args, "to")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		message, err := RequiredParam[string](args, "message")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		sanitizePolicy, err := OptionalParam[string](args, "sanitize_paths")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		pathPolicy, err := sanitize.ParsePathPolicy(sanitizePolicy)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		allowEmpty, err := OptionalParam[bool](args, "allow_empty")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		identity, err := commitIdentityFromArgs(ctx, args)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		from, to, err = cleanDirectoryRename(from, to, pathPolicy)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		// Get the reference for the branch
+		ref, resp, err := client.Git.GetRef(ctx, owner, repo, "refs/heads/"+branch)
+		if isEmptyRepository(resp, err) {
+			return validationErrorResult(emptyRepositoryError(owner, repo, err)), nil, nil
+		}
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to get branch reference", resp, err), nil, nil
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		// Get the commit object
+		baseCommit, resp, err := client.Git.GetCommit(ctx, owner, repo, *ref.Object.SHA)
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to get base commit", resp, err), nil, nil
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		baseTree, resp, err := client.Git.GetTree(ctx, owner, repo, baseCommit.GetTree().GetSHA(), true)
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to get tree", resp, err), nil, nil
+		}
+		_ = resp.Body.Close()
+		if baseTree.GetTruncated() {
+			return utils.NewToolResultError(fmt.Sprintf(
+				"tree of %s/%s@%s is too large to list recursively; rename smaller directories instead",
+				owner, repo, branch,
+			)), nil, nil
+		}
+
+		moved, err := directoryRenameEntries(baseTree.Entries, from, to)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		result := RenameDirectoryResult{
+			Ref:        ref.GetRef(),
+			From:       from,
+			To:         to,
+			FilesMoved: len(moved),
+		}
+
+		// Large directories are written in several trees, each based on the one before, so that
+		// no single request has to carry every entry
+		treeSHA := baseCommit.GetTree().GetSHA()
+		for start := 0; start < len(moved); start += renameTreeChunkSize {
+			end := min(start+renameTreeChunkSize, len(moved))
+			var entries []*github.TreeEntry
+			for _, entry := range moved[start:end] {
+				entries = append(entries,
+					&github.TreeEntry{
+						Path: github.Ptr(to + strings.TrimPrefix(entry.GetPath(), from)),
+						Mode: github.Ptr(entry.GetMode()),
+						Type: github.Ptr(entry.GetType()),
+						SHA:  github.Ptr(entry.GetSHA()),
+					},
+					&github.TreeEntry{
+						Path: github.Ptr(entry.GetPath()),
+						Mode: github.Ptr(entry.GetMode()),
+						Type: github.Ptr(entry.GetType()),
+						SHA:  nil, // nil SHA means delete
+					},
+				)
+			}
+
+			newTree, resp, err := client.Git.CreateTree(ctx, owner, repo, treeSHA, entries)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx, fmt.Sprintf("failed to create tree for files %d to %d", start+1, end), resp, err), nil, nil
+			}
+			_ = resp.Body.Close()
+			treeSHA = newTree.GetSHA()
+			result.TreesCreated++
+		}
+
+		if !allowEmpty && treeSHA == baseCommit.GetTree().GetSHA() {
+			result.NoChanges = true
+			return MarshalledTextResult(result), nil, nil
+		}
+
+		// Create commit
+		commit := github.Commit{
+			Message: github.Ptr(message),
+			Tree:    &github.Tree{SHA: github.Ptr(treeSHA)},
+			Parents: []*github.Commit{{SHA: baseCommit.SHA}},
+		}
+		identity.apply(&commit)
+		newCommit, resp, err := client.Git.CreateCommit(ctx, owner, repo, commit, signedCommitOptions(ctx, &commit))
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to create commit", resp, err), nil, nil
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		// Update reference
+		updatedRef, resp, err := client.Git.UpdateRef(ctx, owner, repo, *ref.Ref, github.UpdateRef{
+			SHA:   *newCommit.SHA,
+			Force: github.Ptr(false),
+		})
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to update reference", resp, err), nil, nil
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		result.CommitSHA = newCommit.GetSHA()
+		result.Ref = updatedRef.GetRef()
+		return MarshalledTextResult(result), nil, nil
+	})
+
+	return withIdempotencyKey(tool, handler)
+}
+
+// cleanDirectoryRename returns from and to without empty segments, to being sanitized with
+// policy as well. It fails unless they are different directories neither of which contains the
+// other, and rejects "." and ".." segments, which would alias another directory.
+func cleanDirectoryRename(from, to string, policy sanitize.PathPolicy) (string, string, error) {
+	for _, dir := range []string{from, to} {
+		if err := sanitize.CheckPath(dir); err != nil {
+			return "", "", err
+		}
+	}
+	clean := func(dir string) string {
+		return strings.Join(strings.FieldsFunc(dir, func(r rune) bool { return r == '/' }), "/")
+	}
+	from, to = clean(from), clean(sanitize.Path(to, policy))
+
+	switch {
+	case from == "" || to == "":
+		return "", "", fmt.Errorf("from and to must be directories, not the repository root")
+	case from == to:
+		return "", "", fmt.Errorf("from and to are the same directory")
+	case strings.HasPrefix(to, from+"/"):
+		return "", "", fmt.Errorf("cannot move %s into its own subdirectory %s", from, to)
+	case strings.HasPrefix(from, to+"/"):
+		return "", "", fmt.Errorf("cannot move %s into its parent directory %s", from, to)
+	}
+	return from, to, nil
+}
+
+// directoryRenameEntries returns the entries of a recursive tree to move from the directory from
+// to the directory to: its files, symlinks and submodules. It fails if from holds none of them,
+// or if a file of the tree would be replaced by a moved entry or sits where to needs a directory.
+func directoryRenameEntries(tree []*github.TreeEntry, from, to string) ([]*github.TreeEntry, error) {
+	existing := make(map[string]bool, len(tree))
+	var moved []*github.TreeEntry
+	for _, entry := range tree {
+		if entry.GetType() == "tree" {
+			continue
+		}
+		existing[entry.GetPath()] = true
+		if strings.HasPrefix(entry.GetPath(), from+"/") {
+			moved = append(moved, entry)
+		}
+	}
+	if len(moved) == 0 {
+		if existing[from] {
+			return nil, fmt.Errorf("%s is a file, not a directory", from)
+		}
+		return nil, fmt.Errorf("directory %s not found", from)
+	}
+
+	for dir := to; dir != "."; dir = path.Dir(dir) {
+		if existing[dir] {
+			return nil, fmt.Errorf("cannot move %s to %s: %s is a file", from, to, dir)
+		}
+	}
+
+	var conflicts []string
+	for _, entry := range moved {
+		target := to + strings.TrimPrefix(entry.GetPath(), from)
+		if existing[target] {
+			conflicts = append(conflicts, target)
+		}
+	}
+	if len(conflicts) > 0 {
+		listed := conflicts[:min(len(conflicts), maxReportedRenameConflicts)]
+		return nil, fmt.Errorf("%d moved file(s) would replace existing files: %s", len(conflicts), strings.Join(listed, ", "))
+	}
+	return moved, nil
+}
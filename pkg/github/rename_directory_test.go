@@ -0,0 +1,213 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v79/github"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createdTree is the body of a create tree request
+type createdTree struct {
+	BaseTree string           `json:"base_tree"`
+	Tree     []map[string]any `json:"tree"`
+}
+
+func Test_RenameDirectory(t *testing.T) {
+	mockClient := github.NewClient(nil)
+	tool, _ := RenameDirectory(stubGetClientFn(mockClient), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	schema, ok := tool.InputSchema.(*jsonschema.Schema)
+	require.True(t, ok, "InputSchema should be *jsonschema.Schema")
+	assert.Equal(t, "rename_directory", tool.Name)
+	assert.False(t, tool.Annotations.ReadOnlyHint)
+	assert.Contains(t, schema.Properties, "from")
+	assert.Contains(t, schema.Properties, "to")
+	assert.ElementsMatch(t, schema.Required, []string{"owner", "repo", "branch", "from", "to", "message"})
+
+	baseTree := &github.Tree{
+		SHA: github.Ptr("base-tree-sha"),
+		Entries: []*github.TreeEntry{
+			{Path: github.Ptr("README.md"), Type: github.Ptr("blob"), Mode: github.Ptr("100644"), SHA: github.Ptr("readme-sha")},
+			{Path: github.Ptr("old"), Type: github.Ptr("tree"), Mode: github.Ptr("040000"), SHA: github.Ptr("old-sha")},
+			{Path: github.Ptr("old/a.txt"), Type: github.Ptr("blob"), Mode: github.Ptr("100644"), SHA: github.Ptr("a-sha")},
+			{Path: github.Ptr("old/bin/run.sh"), Type: github.Ptr("blob"), Mode: github.Ptr("100755"), SHA: github.Ptr("run-sha")},
+			{Path: github.Ptr("old/vendor/lib"), Type: github.Ptr("commit"), Mode: github.Ptr("160000"), SHA: github.Ptr("lib-sha")},
+			{Path: github.Ptr("older/b.txt"), Type: github.Ptr("blob"), Mode: github.Ptr("100644"), SHA: github.Ptr("b-sha")},
+			{Path: github.Ptr("new/a.txt"), Type: github.Ptr("blob"), Mode: github.Ptr("100644"), SHA: github.Ptr("other-sha")},
+		},
+	}
+
+	renameOptions := func(trees http.HandlerFunc) []mock.MockBackendOption {
+		options := mockGitDataAPI(t)
+		options[2] = mock.WithRequestMatchHandler(mock.PostReposGitTreesByOwnerByRepo, trees)
+		return append(options, mock.WithRequestMatchHandler(
+			mock.GetReposGitTreesByOwnerByRepoByTreeSha,
+			expectPath(t, "/repos/owner/repo/git/trees/base-tree-sha").andThen(
+				mockResponse(t, http.StatusOK, baseTree),
+			),
+		))
+	}
+
+	t.Run("moves files in chunked trees", func(t *testing.T) {
+		previous := renameTreeChunkSize
+		renameTreeChunkSize = 2
+		t.Cleanup(func() { renameTreeChunkSize = previous })
+
+		var requests []createdTree
+		recordTree := func(sha string) http.HandlerFunc {
+			return func(w http.ResponseWriter, r *http.Request) {
+				var body createdTree
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+				requests = append(requests, body)
+				mockResponse(t, http.StatusCreated, &github.Tree{SHA: github.Ptr(sha)})(w, r)
+			}
+		}
+		client := github.NewClient(mock.NewMockedHTTPClient(renameOptions(sequentialResponses(recordTree("tree-1"), recordTree("tree-2")))...))
+		_, handler := RenameDirectory(stubGetClientFn(client), translations.NullTranslationHelper)
+
+		args := map[string]any{
+			"owner":   "owner",
+			"repo":    "repo",
+			"branch":  "main",
+			"from":    "/old/",
+			"to":      "moved/here",
+			"message": "Move old",
+		}
+		request := createMCPRequest(args)
+		result, _, err := handler(context.Background(), &request, args)
+		require.NoError(t, err)
+		require.False(t, result.IsError, getTextResult(t, result).Text)
+
+		var got RenameDirectoryResult
+		require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &got))
+		assert.Equal(t, RenameDirectoryResult{
+			CommitSHA:    "new-commit-sha",
+			Ref:          "refs/heads/main",
+			From:         "old",
+			To:           "moved/here",
+			FilesMoved:   3,
+			TreesCreated: 2,
+		}, got)
+
+		require.Len(t, requests, 2)
+		assert.Equal(t, "base-tree-sha", requests[0].BaseTree)
+		assert.Equal(t, []map[string]any{
+			{"path": "moved/here/a.txt", "mode": "100644", "type": "blob", "sha": "a-sha"},
+			{"path": "old/a.txt", "mode": "100644", "type": "blob", "sha": nil},
+			{"path": "moved/here/bin/run.sh", "mode": "100755", "type": "blob", "sha": "run-sha"},
+			{"path": "old/bin/run.sh", "mode": "100755", "type": "blob", "sha": nil},
+		}, requests[0].Tree)
+		assert.Equal(t, "tree-1", requests[1].BaseTree)
+		assert.Equal(t, []map[string]any{
+			{"path": "moved/here/vendor/lib", "mode": "160000", "type": "commit", "sha": "lib-sha"},
+			{"path": "old/vendor/lib", "mode": "160000", "type": "commit", "sha": nil},
+		}, requests[1].Tree)
+	})
+
+	tests := []struct {
+		name           string
+		from           string
+		to             string
+		sanitizePaths  string
+		expectedErrMsg string
+	}{
+		{
+			name:           "existing files are not replaced",
+			from:           "old",
+			to:             "new",
+			expectedErrMsg: "1 moved file(s) would replace existing files: new/a.txt",
+		},
+		{
+			name:           "target is a file",
+			from:           "old",
+			to:             "README.md/old",
+			expectedErrMsg: "README.md is a file",
+		},
+		{
+			name:           "directory not found",
+			from:           "missing",
+			to:             "new",
+			expectedErrMsg: "directory missing not found",
+		},
+		{
+			name:           "source is a file",
+			from:           "README.md",
+			to:             "docs",
+			expectedErrMsg: "README.md is a file, not a directory",
+		},
+		{
+			name:           "target inside source",
+			from:           "old",
+			to:             "old/inner",
+			expectedErrMsg: "cannot move old into its own subdirectory old/inner",
+		},
+		{
+			name:           "same directory",
+			from:           "old",
+			to:             "old/",
+			expectedErrMsg: "from and to are the same directory",
+		},
+		{
+			name:           "source inside target",
+			from:           "old//bin",
+			to:             "old",
+			expectedErrMsg: "cannot move old/bin into its parent directory old",
+		},
+		{
+			name:           "dot segments in source",
+			from:           "new/../old",
+			to:             "moved",
+			expectedErrMsg: `path "new/../old" must not contain ".." segments`,
+		},
+		{
+			name:           "dot segments in target",
+			from:           "old",
+			to:             "./moved",
+			expectedErrMsg: `path "./moved" must not contain "." segments`,
+		},
+		{
+			name:           "target inside source once sanitized",
+			from:           "old",
+			to:             "old /inner ",
+			sanitizePaths:  "normalize",
+			expectedErrMsg: "cannot move old into its own subdirectory old/inner",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client := github.NewClient(mock.NewMockedHTTPClient(renameOptions(func(w http.ResponseWriter, _ *http.Request) {
+				t.Error("no tree should be created")
+				w.WriteHeader(http.StatusInternalServerError)
+			})...))
+			_, handler := RenameDirectory(stubGetClientFn(client), translations.NullTranslationHelper)
+
+			args := map[string]any{
+				"owner":   "owner",
+				"repo":    "repo",
+				"branch":  "main",
+				"from":    tc.from,
+				"to":      tc.to,
+				"message": "Move",
+			}
+			if tc.sanitizePaths != "" {
+				args["sanitize_paths"] = tc.sanitizePaths
+			}
+			request := createMCPRequest(args)
+			result, _, err := handler(context.Background(), &request, args)
+			require.NoError(t, err)
+			require.True(t, result.IsError)
+			assert.Contains(t, getErrorResult(t, result).Text, tc.expectedErrMsg)
+		})
+	}
+}
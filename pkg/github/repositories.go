@@ -20,16 +20,24 @@ import (
 
 // Bulk operation limits
 const (
-	// MaxFilesPerPush is the maximum number of files allowed in a single push_files call
+	// MaxFilesPerPush is the default maximum number of files allowed in a single push_files call
 	MaxFilesPerPush = 100
-	// MaxFileSizeBytes is the maximum size of a single file (25MB)
+	// MaxFileSizeBytes is the default maximum size of a single file (25MB)
 	MaxFileSizeBytes = 25 * 1024 * 1024
-	// MaxTotalPushSizeBytes is the maximum total size of all files in a push (100MB)
+	// MaxTotalPushSizeBytes is the default maximum total size of all files in a push (100MB)
 	MaxTotalPushSizeBytes = 100 * 1024 * 1024
 	// DefaultChunkSize is the default number of files per chunk in chunked operations
 	DefaultChunkSize = 50
-	// MaxChunkSize is the maximum allowed chunk size
+	// MaxChunkSize is the default maximum allowed chunk size
 	MaxChunkSize = 100
+	// MaxChunkRetries is the largest max_retries accepted by the chunked tools
+	MaxChunkRetries = 10
+	// DefaultBulkReadFileSizeBytes is the default per-file content limit of get_files_bulk (100KB)
+	DefaultBulkReadFileSizeBytes = 100 * 1024
+	// DefaultBulkReadTotalSizeBytes is the default total content budget of get_files_bulk (1MB)
+	DefaultBulkReadTotalSizeBytes = 1024 * 1024
+	// MaxBulkReadTotalSizeBytes is the largest total content budget of get_files_bulk (10MB)
+	MaxBulkReadTotalSizeBytes = 10 * 1024 * 1024
 )
 
 func GetCommit(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
@@ -860,6 +868,85 @@ func ForkRepository(getClient GetClientFn, t translations.TranslationHelperFunc)
 	return tool, handler
 }
 
+// ListForks creates a tool to list the forks of a repository.
+func ListForks(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "list_forks",
+		Description: t("TOOL_LIST_FORKS_DESCRIPTION", "List the forks of a GitHub repository"),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_LIST_FORKS_USER_TITLE", "List forks"),
+			ReadOnlyHint: true,
+		},
+		InputSchema: WithPagination(&jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"owner": {
+					Type:        "string",
+					Description: "Repository owner",
+				},
+				"repo": {
+					Type:        "string",
+					Description: "Repository name",
+				},
+				"sort": {
+					Type:        "string",
+					Description: "How to sort the forks (default: newest)",
+					Enum:        []any{"newest", "oldest", "stargazers", "watchers"},
+				},
+			},
+			Required: []string{"owner", "repo"},
+		}),
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		repo, err := RequiredParam[string](args, "repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		sort, err := OptionalParam[string](args, "sort")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		pagination, err := OptionalPaginationParams(args)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		forks, resp, err := client.Repositories.ListForks(ctx, owner, repo, &github.RepositoryListForksOptions{
+			Sort: sort,
+			ListOptions: github.ListOptions{
+				Page:    pagination.Page,
+				PerPage: pagination.PerPage,
+			},
+		})
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx,
+				fmt.Sprintf("failed to list forks of %s/%s", owner, repo),
+				resp,
+				err,
+			), nil, nil
+		}
+		_ = resp.Body.Close()
+
+		minimalForks := make([]MinimalRepository, 0, len(forks))
+		for _, fork := range forks {
+			minimalForks = append(minimalForks, convertToMinimalRepository(fork))
+		}
+		return MarshalledTextResult(minimalForks), nil, nil
+	})
+
+	return tool, handler
+}
+
 // DeleteFile creates a tool to delete a file in a GitHub repository.
 // This tool uses a more roundabout way of deleting a file than just using the client.Repositories.DeleteFile.
 // This is because REST file deletion endpoint (and client.Repositories.DeleteFile) don't add commit signing to the deletion commit,
@@ -1199,6 +1286,7 @@ func PushFiles(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp
 								Type:        "string",
 								Description: "file content",
 							},
+							"content_encoding": fileContentEncodingSchema(),
 						},
 						Required: []string{"path", "content"},
 					},
@@ -1207,6 +1295,8 @@ func PushFiles(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp
 					Type:        "string",
 					Description: "Commit message",
 				},
+				"allow_secrets":          allowSecretsSchema(),
+				"normalize_line_endings": normalizeLineEndingsSchema(),
 			},
 			Required: []string{"owner", "repo", "branch", "files", "message"},
 		},
@@ -1229,6 +1319,10 @@ func PushFiles(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp
 		if err != nil {
 			return utils.NewToolResultError(err.Error()), nil, nil
 		}
+		validationOpts, err := fileValidationOptionsFromArgs(args)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
 
 		// Parse files parameter - this should be an array of objects with path and content
 		filesObj, ok := args["files"].([]interface{})
@@ -1236,32 +1330,29 @@ func PushFiles(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp
 			return utils.NewToolResultError("files parameter must be an array of objects with path and content"), nil, nil
 		}
 
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+		limits := pushLimitsFor(ctx, client)
+
 		// Validate file count limit
-		if result, err := ValidateFileCount(len(filesObj), MaxFilesPerPush); result != nil || err != nil {
+		if result, err := ValidateFileCount(len(filesObj), limits.MaxFilesPerPush); result != nil || err != nil {
 			return result, nil, nil
 		}
 
 		// Validate files using shared validation logic
-		validationResult, files, err := ValidateFiles(filesObj)
+		validationResult, files, err := ValidateFilesWithOptions(filesObj, validationOpts)
 		if err != nil {
-			return utils.NewToolResultError(err.Error()), nil, nil
+			return toolErrorResult(err), nil, nil
 		}
 
-		// Check for oversized files
-		for _, path := range validationResult.OversizedFiles {
-			if result, err := ValidateFileSize(path, validationResult.LargestFileSize); result != nil || err != nil {
-				return result, nil, nil
-			}
-		}
-
-		// Validate total size
-		if result, err := ValidateTotalSize(validationResult.TotalSize); result != nil || err != nil {
+		// Check the largest file and the total size against the limits of the host
+		if result, err := ValidateFileSize(limits, validationResult.LargestFile, validationResult.LargestFileSize); result != nil || err != nil {
 			return result, nil, nil
 		}
-
-		client, err := getClient(ctx)
-		if err != nil {
-			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		if result, err := ValidateTotalSize(limits, validationResult.TotalSize); result != nil || err != nil {
+			return result, nil, nil
 		}
 
 		// Get the reference for the branch
@@ -1344,7 +1435,7 @@ func PushFiles(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp
 			return nil, nil, fmt.Errorf("failed to marshal response: %w", err)
 		}
 
-		return utils.NewToolResultText(string(r)), nil, nil
+		return withValidationWarnings(utils.NewToolResultText(string(r)), validationResult.Warnings), nil, nil
 	})
 
 	return tool, handler
@@ -3470,3 +3470,34 @@ func Test_RepositoriesGetRepositoryTree(t *testing.T) {
 		})
 	}
 }
+
+func Test_ListForks(t *testing.T) {
+	tool, _ := ListForks(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.True(t, tool.Annotations.ReadOnlyHint)
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.GetReposForksByOwnerByRepo,
+			expectQueryParams(t, map[string]string{"sort": "stargazers", "page": "1", "per_page": "30"}).andThen(
+				mockResponse(t, http.StatusOK, []*github.Repository{
+					{ID: github.Ptr(int64(2)), FullName: github.Ptr("fork-owner/repo"), Fork: github.Ptr(true), StargazersCount: github.Ptr(5)},
+				}),
+			),
+		),
+	)
+	_, handler := ListForks(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+	args := map[string]any{"owner": "owner", "repo": "repo", "sort": "stargazers"}
+	request := createMCPRequest(args)
+	result, _, err := handler(context.Background(), &request, args)
+	require.NoError(t, err)
+	require.False(t, result.IsError, getTextResult(t, result).Text)
+
+	var forks []MinimalRepository
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &forks))
+	require.Len(t, forks, 1)
+	assert.Equal(t, "fork-owner/repo", forks[0].FullName)
+	assert.True(t, forks[0].Fork)
+	assert.Equal(t, 5, forks[0].Stars)
+}
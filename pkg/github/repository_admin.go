@@ -0,0 +1,362 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	ghErrors "github.com/github/github-mcp-server/pkg/errors"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/github/github-mcp-server/pkg/utils"
+	"github.com/google/go-github/v79/github"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// RepositorySettings is the output type of update_repository.
+type RepositorySettings struct {
+	FullName            string   `json:"full_name"`
+	HTMLURL             string   `json:"html_url"`
+	Description         string   `json:"description,omitempty"`
+	Homepage            string   `json:"homepage,omitempty"`
+	Visibility          string   `json:"visibility"`
+	DefaultBranch       string   `json:"default_branch"`
+	Archived            bool     `json:"archived"`
+	AllowMergeCommit    bool     `json:"allow_merge_commit"`
+	AllowSquashMerge    bool     `json:"allow_squash_merge"`
+	AllowRebaseMerge    bool     `json:"allow_rebase_merge"`
+	AllowAutoMerge      bool     `json:"allow_auto_merge"`
+	AllowUpdateBranch   bool     `json:"allow_update_branch"`
+	DeleteBranchOnMerge bool     `json:"delete_branch_on_merge"`
+	Topics              []string `json:"topics"`
+}
+
+func convertToRepositorySettings(repo *github.Repository) RepositorySettings {
+	topics := repo.Topics
+	if topics == nil {
+		topics = []string{}
+	}
+	return RepositorySettings{
+		FullName:            repo.GetFullName(),
+		HTMLURL:             repo.GetHTMLURL(),
+		Description:         repo.GetDescription(),
+		Homepage:            repo.GetHomepage(),
+		Visibility:          repo.GetVisibility(),
+		DefaultBranch:       repo.GetDefaultBranch(),
+		Archived:            repo.GetArchived(),
+		AllowMergeCommit:    repo.GetAllowMergeCommit(),
+		AllowSquashMerge:    repo.GetAllowSquashMerge(),
+		AllowRebaseMerge:    repo.GetAllowRebaseMerge(),
+		AllowAutoMerge:      repo.GetAllowAutoMerge(),
+		AllowUpdateBranch:   repo.GetAllowUpdateBranch(),
+		DeleteBranchOnMerge: repo.GetDeleteBranchOnMerge(),
+		Topics:              topics,
+	}
+}
+
+// UpdateRepository creates a tool to change the settings of a repository.
+func UpdateRepository(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "update_repository",
+		Description: t("TOOL_UPDATE_REPOSITORY_DESCRIPTION", "Update the settings of a GitHub repository: description, visibility, default branch, merge options and topics, or archive and unarchive it. Only the settings given are changed. Requires admin access to the repository."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_UPDATE_REPOSITORY_USER_TITLE", "Update repository settings"),
+			ReadOnlyHint: false,
+		},
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"owner": {
+					Type:        "string",
+					Description: "Repository owner",
+				},
+				"repo": {
+					Type:        "string",
+					Description: "Repository name",
+				},
+				"new_name": {
+					Type:        "string",
+					Description: "New name of the repository",
+				},
+				"description": {
+					Type:        "string",
+					Description: "Repository description",
+				},
+				"homepage": {
+					Type:        "string",
+					Description: "URL of the project's homepage",
+				},
+				"visibility": {
+					Type:        "string",
+					Description: "Repository visibility. 'internal' is only available to organizations on GitHub Enterprise",
+					Enum:        []any{"public", "private", "internal"},
+				},
+				"default_branch": {
+					Type:        "string",
+					Description: "Branch to make the default branch. It must already exist",
+				},
+				"archived": {
+					Type:        "boolean",
+					Description: "Set to true to archive the repository, making it read-only, or false to unarchive it",
+				},
+				"allow_merge_commit": {
+					Type:        "boolean",
+					Description: "Whether pull requests can be merged with a merge commit",
+				},
+				"allow_squash_merge": {
+					Type:        "boolean",
+					Description: "Whether pull requests can be squash-merged",
+				},
+				"allow_rebase_merge": {
+					Type:        "boolean",
+					Description: "Whether pull requests can be rebase-merged",
+				},
+				"allow_auto_merge": {
+					Type:        "boolean",
+					Description: "Whether pull requests can be set to merge automatically once requirements are met",
+				},
+				"allow_update_branch": {
+					Type:        "boolean",
+					Description: "Whether pull request branches can be updated with the base branch from the pull request page",
+				},
+				"delete_branch_on_merge": {
+					Type:        "boolean",
+					Description: "Whether head branches are deleted when their pull requests are merged",
+				},
+				"topics": {
+					Type:        "array",
+					Description: "Topics of the repository, replacing the current ones. An empty list removes all topics",
+					Items:       &jsonschema.Schema{Type: "string"},
+				},
+			},
+			Required: []string{"owner", "repo"},
+		},
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		repo, err := RequiredParam[string](args, "repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		settings := &github.Repository{}
+		edits := 0
+		for name, field := range map[string]**string{
+			"new_name":       &settings.Name,
+			"description":    &settings.Description,
+			"homepage":       &settings.Homepage,
+			"visibility":     &settings.Visibility,
+			"default_branch": &settings.DefaultBranch,
+		} {
+			if _, ok := args[name]; !ok {
+				continue
+			}
+			value, err := OptionalParam[string](args, name)
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			*field = github.Ptr(value)
+			edits++
+		}
+		for name, field := range map[string]**bool{
+			"allow_merge_commit":     &settings.AllowMergeCommit,
+			"allow_squash_merge":     &settings.AllowSquashMerge,
+			"allow_rebase_merge":     &settings.AllowRebaseMerge,
+			"allow_auto_merge":       &settings.AllowAutoMerge,
+			"allow_update_branch":    &settings.AllowUpdateBranch,
+			"delete_branch_on_merge": &settings.DeleteBranchOnMerge,
+		} {
+			if _, ok := args[name]; !ok {
+				continue
+			}
+			value, err := OptionalParam[bool](args, name)
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			*field = github.Ptr(value)
+			edits++
+		}
+		_, setArchived := args["archived"]
+		archived, err := OptionalParam[bool](args, "archived")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		_, setTopics := args["topics"]
+		topics, err := OptionalStringArrayParam(args, "topics")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		if edits == 0 && !setArchived && !setTopics {
+			return utils.NewToolResultError("nothing to update: set at least one setting"), nil, nil
+		}
+		if settings.Name != nil && *settings.Name == "" {
+			return utils.NewToolResultError("new_name must not be empty"), nil, nil
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		// An archived repository is read-only, so it is unarchived before the other changes and
+		// archived after them
+		var updated *github.Repository
+		var resp *github.Response
+		if setArchived && !archived {
+			updated, resp, err = client.Repositories.Edit(ctx, owner, repo, &github.Repository{Archived: github.Ptr(false)})
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to unarchive repository", resp, err), nil, nil
+			}
+			_ = resp.Body.Close()
+		}
+		if edits > 0 {
+			updated, resp, err = client.Repositories.Edit(ctx, owner, repo, settings)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to update repository", resp, err), nil, nil
+			}
+			_ = resp.Body.Close()
+			// Later calls use the new name
+			repo = updated.GetName()
+		}
+		if setTopics {
+			replaced, resp, err := client.Repositories.ReplaceAllTopics(ctx, owner, repo, topics)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to replace repository topics", resp, err), nil, nil
+			}
+			_ = resp.Body.Close()
+			if updated != nil {
+				updated.Topics = replaced
+			}
+		}
+		if setArchived && archived {
+			updated, resp, err = client.Repositories.Edit(ctx, owner, repo, &github.Repository{Archived: github.Ptr(true)})
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to archive repository", resp, err), nil, nil
+			}
+			_ = resp.Body.Close()
+		}
+		if updated == nil {
+			updated, resp, err = client.Repositories.Get(ctx, owner, repo)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to get repository", resp, err), nil, nil
+			}
+			_ = resp.Body.Close()
+		}
+
+		return MarshalledTextResult(convertToRepositorySettings(updated)), nil, nil
+	})
+
+	return tool, handler
+}
+
+// TransferRepository creates a tool to transfer a repository to another user or organization.
+func TransferRepository(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "transfer_repository",
+		Description: t("TOOL_TRANSFER_REPOSITORY_DESCRIPTION", "Transfer a repository to another user or organization. Transfers to a user must be accepted by that user; transfers to an organization you can create repositories in take effect right away. Requires admin access to the repository."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:           t("TOOL_TRANSFER_REPOSITORY_USER_TITLE", "Transfer repository"),
+			ReadOnlyHint:    false,
+			DestructiveHint: jsonschema.Ptr(true),
+		},
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"owner": {
+					Type:        "string",
+					Description: "Repository owner",
+				},
+				"repo": {
+					Type:        "string",
+					Description: "Repository name",
+				},
+				"new_owner": {
+					Type:        "string",
+					Description: "User or organization to transfer the repository to",
+				},
+				"new_name": {
+					Type:        "string",
+					Description: "Name of the repository under its new owner (default: the current name)",
+				},
+				"team_ids": {
+					Type:        "array",
+					Description: "IDs of teams of the new organization to give access to the repository",
+					Items:       &jsonschema.Schema{Type: "number"},
+				},
+			},
+			Required: []string{"owner", "repo", "new_owner"},
+		},
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		repo, err := RequiredParam[string](args, "repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		newOwner, err := RequiredParam[string](args, "new_owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		newName, err := OptionalParam[string](args, "new_name")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		var teamIDs []int64
+		if rawIDs, ok := args["team_ids"]; ok {
+			ids, ok := rawIDs.([]any)
+			if !ok {
+				return utils.NewToolResultError("team_ids must be an array of numbers"), nil, nil
+			}
+			for _, id := range ids {
+				number, ok := id.(float64)
+				if !ok {
+					return utils.NewToolResultError("team_ids must be an array of numbers"), nil, nil
+				}
+				teamIDs = append(teamIDs, int64(number))
+			}
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		request := github.TransferRequest{NewOwner: newOwner, TeamID: teamIDs}
+		if newName != "" {
+			request.NewName = github.Ptr(newName)
+		}
+		transferred, resp, err := client.Repositories.Transfer(ctx, owner, repo, request)
+		var accepted *github.AcceptedError
+		switch {
+		case errors.As(err, &accepted):
+			// GitHub moves the repository in the background
+			transferred = &github.Repository{}
+		case err != nil:
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to transfer repository", resp, err), nil, nil
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		name := transferred.GetName()
+		if name == "" {
+			name = repo
+			if newName != "" {
+				name = newName
+			}
+		}
+		return MarshalledTextResult(map[string]any{
+			"message":   fmt.Sprintf("Transfer of %s/%s to %s started. Transfers to a user take effect once the user accepts them", owner, repo, newOwner),
+			"full_name": fmt.Sprintf("%s/%s", newOwner, name),
+			"html_url":  transferred.GetHTMLURL(),
+		}), nil, nil
+	})
+
+	return tool, handler
+}
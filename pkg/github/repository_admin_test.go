@@ -0,0 +1,187 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v79/github"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_UpdateRepository(t *testing.T) {
+	tool, _ := UpdateRepository(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	t.Run("renames, changes settings and topics, then archives", func(t *testing.T) {
+		var edits []map[string]any
+		mockedClient := mock.NewMockedHTTPClient(
+			mock.WithRequestMatchHandler(
+				mock.PatchReposByOwnerByRepo,
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					var body map[string]any
+					require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+					edits = append(edits, body)
+					repo := &github.Repository{
+						Name:             github.Ptr("renamed"),
+						FullName:         github.Ptr("owner/renamed"),
+						Visibility:       github.Ptr("private"),
+						AllowSquashMerge: github.Ptr(true),
+						Topics:           []string{"go", "mcp"},
+					}
+					if _, ok := body["archived"]; ok {
+						repo.Archived = github.Ptr(true)
+					}
+					// The rename applies to the calls after the first edit
+					expectedPath := "/repos/owner/renamed"
+					if len(edits) == 1 {
+						expectedPath = "/repos/owner/repo"
+					}
+					assert.Equal(t, expectedPath, r.URL.Path)
+					w.WriteHeader(http.StatusOK)
+					_ = json.NewEncoder(w).Encode(repo)
+				}),
+			),
+			mock.WithRequestMatchHandler(
+				mock.PutReposTopicsByOwnerByRepo,
+				expectPath(t, "/repos/owner/renamed/topics").andThen(
+					expectRequestBody(t, map[string]any{"names": []any{"go", "mcp"}}).andThen(
+						mockResponse(t, http.StatusOK, map[string]any{"names": []string{"go", "mcp"}}),
+					),
+				),
+			),
+		)
+		_, handler := UpdateRepository(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+		args := map[string]any{
+			"owner":              "owner",
+			"repo":               "repo",
+			"new_name":           "renamed",
+			"visibility":         "private",
+			"allow_squash_merge": true,
+			"topics":             []any{"go", "mcp"},
+			"archived":           true,
+		}
+		request := createMCPRequest(args)
+		result, _, err := handler(context.Background(), &request, args)
+		require.NoError(t, err)
+		require.False(t, result.IsError, getTextResult(t, result).Text)
+
+		require.Len(t, edits, 2)
+		assert.Equal(t, map[string]any{"name": "renamed", "visibility": "private", "allow_squash_merge": true}, edits[0])
+		assert.Equal(t, map[string]any{"archived": true}, edits[1])
+
+		var settings RepositorySettings
+		require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &settings))
+		assert.Equal(t, "owner/renamed", settings.FullName)
+		assert.True(t, settings.Archived)
+		assert.Equal(t, []string{"go", "mcp"}, settings.Topics)
+	})
+
+	t.Run("unarchives before other changes", func(t *testing.T) {
+		var edits []map[string]any
+		mockedClient := mock.NewMockedHTTPClient(
+			mock.WithRequestMatchHandler(
+				mock.PatchReposByOwnerByRepo,
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					var body map[string]any
+					require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+					edits = append(edits, body)
+					w.WriteHeader(http.StatusOK)
+					_ = json.NewEncoder(w).Encode(&github.Repository{Name: github.Ptr("repo"), DefaultBranch: github.Ptr("trunk")})
+				}),
+			),
+		)
+		_, handler := UpdateRepository(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+		args := map[string]any{"owner": "owner", "repo": "repo", "archived": false, "default_branch": "trunk"}
+		request := createMCPRequest(args)
+		result, _, err := handler(context.Background(), &request, args)
+		require.NoError(t, err)
+		require.False(t, result.IsError, getTextResult(t, result).Text)
+		assert.Equal(t, []map[string]any{{"archived": false}, {"default_branch": "trunk"}}, edits)
+	})
+
+	t.Run("topics only", func(t *testing.T) {
+		mockedClient := mock.NewMockedHTTPClient(
+			mock.WithRequestMatchHandler(
+				mock.PutReposTopicsByOwnerByRepo,
+				expectRequestBody(t, map[string]any{"names": []any{}}).andThen(
+					mockResponse(t, http.StatusOK, map[string]any{"names": []string{}}),
+				),
+			),
+			mock.WithRequestMatch(
+				mock.GetReposByOwnerByRepo,
+				&github.Repository{FullName: github.Ptr("owner/repo")},
+			),
+		)
+		_, handler := UpdateRepository(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+		args := map[string]any{"owner": "owner", "repo": "repo", "topics": []any{}}
+		request := createMCPRequest(args)
+		result, _, err := handler(context.Background(), &request, args)
+		require.NoError(t, err)
+		require.False(t, result.IsError, getTextResult(t, result).Text)
+
+		var settings RepositorySettings
+		require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &settings))
+		assert.Equal(t, "owner/repo", settings.FullName)
+		assert.Empty(t, settings.Topics)
+	})
+
+	t.Run("nothing to update", func(t *testing.T) {
+		_, handler := UpdateRepository(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+		args := map[string]any{"owner": "owner", "repo": "repo"}
+		request := createMCPRequest(args)
+		result, _, err := handler(context.Background(), &request, args)
+		require.NoError(t, err)
+		require.True(t, result.IsError)
+		assert.Contains(t, getErrorResult(t, result).Text, "nothing to update")
+	})
+}
+
+func Test_TransferRepository(t *testing.T) {
+	tool, _ := TransferRepository(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.True(t, *tool.Annotations.DestructiveHint)
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.PostReposTransferByOwnerByRepo,
+			expectRequestBody(t, map[string]any{
+				"new_owner": "new-org",
+				"new_name":  "tool",
+				"team_ids":  []any{float64(42)},
+			}).andThen(
+				mockResponse(t, http.StatusAccepted, &github.Repository{
+					Name:    github.Ptr("tool"),
+					HTMLURL: github.Ptr("https://github.com/new-org/tool"),
+				}),
+			),
+		),
+	)
+	_, handler := TransferRepository(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+	args := map[string]any{"owner": "owner", "repo": "repo", "new_owner": "new-org", "new_name": "tool", "team_ids": []any{float64(42)}}
+	request := createMCPRequest(args)
+	result, _, err := handler(context.Background(), &request, args)
+	require.NoError(t, err)
+	require.False(t, result.IsError, getTextResult(t, result).Text)
+
+	var transferred map[string]any
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &transferred))
+	assert.Equal(t, "new-org/tool", transferred["full_name"])
+	assert.Contains(t, transferred["message"], "Transfer of owner/repo to new-org started")
+
+	args = map[string]any{"owner": "owner", "repo": "repo", "new_owner": "new-org", "team_ids": []any{"platform"}}
+	request = createMCPRequest(args)
+	result, _, err = handler(context.Background(), &request, args)
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	assert.Contains(t, getErrorResult(t, result).Text, "team_ids must be an array of numbers")
+}
@@ -0,0 +1,387 @@
+package github
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha1" //nolint:gosec // git blob SHAs are SHA-1
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"strings"
+
+	ghErrors "github.com/github/github-mcp-server/pkg/errors"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/github/github-mcp-server/pkg/utils"
+	"github.com/google/go-github/v79/github"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// MaxRepositoryArchiveSizeBytes is the largest archive get_repository_archive downloads (100MB)
+const MaxRepositoryArchiveSizeBytes = 100 * 1024 * 1024
+
+// errArchiveTooLarge is returned when an archive exceeds MaxRepositoryArchiveSizeBytes
+var errArchiveTooLarge = fmt.Errorf("archive is larger than %d bytes", MaxRepositoryArchiveSizeBytes)
+
+// RepositoryArchiveEntry is a file listed by get_repository_archive
+type RepositoryArchiveEntry struct {
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+	// Symlink is set for symbolic links, whose content is the link target
+	Symlink bool `json:"symlink,omitempty"`
+}
+
+// RepositoryArchiveResult represents the files of a repository archive
+type RepositoryArchiveResult struct {
+	Ref        string `json:"ref"`
+	Format     string `json:"format"`
+	TotalFiles int    `json:"total_files"`
+	TotalSize  int64  `json:"total_size"`
+	// Entries lists the selected files when include_content is not set
+	Entries []RepositoryArchiveEntry `json:"entries,omitempty"`
+	// Files holds the contents of the selected files when include_content is set
+	Files         []BulkFile `json:"files,omitempty"`
+	BytesReturned int        `json:"bytes_returned,omitempty"`
+	// RemainingPaths lists the files left out once the total size budget was spent.
+	// Pass them as paths in another call, or to get_files_bulk, to continue.
+	RemainingPaths []string `json:"remaining_paths,omitempty"`
+}
+
+// GetRepositoryArchive creates a tool to list or extract the files of a repository archive at a ref.
+func GetRepositoryArchive(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "get_repository_archive",
+		Description: t("TOOL_GET_REPOSITORY_ARCHIVE_DESCRIPTION", fmt.Sprintf("Download the tarball or zipball of a repository at a ref, in one request, and list its files or, with include_content, return the contents of the selected files. All files come from the same snapshot, so this is the cheapest way to read many files consistently. Archives larger than %d bytes are rejected; use get_files_bulk for those. Contents are limited by max_file_size and max_total_size like get_files_bulk, and files left out are listed in remaining_paths.", MaxRepositoryArchiveSizeBytes)),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_GET_REPOSITORY_ARCHIVE_USER_TITLE", "Get repository archive"),
+			ReadOnlyHint: true,
+		},
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"owner": {
+					Type:        "string",
+					Description: "Repository owner",
+				},
+				"repo": {
+					Type:        "string",
+					Description: "Repository name",
+				},
+				"ref": {
+					Type:        "string",
+					Description: "Branch, tag or commit SHA to download. Defaults to the default branch",
+				},
+				"format": {
+					Type:        "string",
+					Description: "Archive format to download (default: tarball)",
+					Enum:        []any{string(github.Tarball), string(github.Zipball)},
+				},
+				"paths": {
+					Type:        "array",
+					Description: "File or directory paths to select. Omit to select every file in the repository",
+					Items: &jsonschema.Schema{
+						Type: "string",
+					},
+				},
+				"include_content": {
+					Type:        "boolean",
+					Description: "Return the contents of the selected files instead of listing them",
+				},
+				"max_file_size": {
+					Type:        "integer",
+					Description: fmt.Sprintf("Maximum bytes of content returned per file; larger files are truncated (default: %d)", DefaultBulkReadFileSizeBytes),
+					Minimum:     jsonschema.Ptr(1.0),
+				},
+				"max_total_size": {
+					Type:        "integer",
+					Description: fmt.Sprintf("Maximum bytes of content returned in total (default: %d, max: %d)", DefaultBulkReadTotalSizeBytes, MaxBulkReadTotalSizeBytes),
+					Minimum:     jsonschema.Ptr(1.0),
+					Maximum:     jsonschema.Ptr(float64(MaxBulkReadTotalSizeBytes)),
+				},
+			},
+			Required: []string{"owner", "repo"},
+		},
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		repo, err := RequiredParam[string](args, "repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		ref, err := OptionalParam[string](args, "ref")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		format, err := OptionalParam[string](args, "format")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		paths, err := OptionalStringArrayParam(args, "paths")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		includeContent, err := OptionalParam[bool](args, "include_content")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		maxFileSize, err := OptionalIntParamWithDefault(args, "max_file_size", DefaultBulkReadFileSizeBytes)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		maxTotalSize, err := OptionalIntParamWithDefault(args, "max_total_size", DefaultBulkReadTotalSizeBytes)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		if maxFileSize < 1 || maxTotalSize < 1 {
+			return utils.NewToolResultError("max_file_size and max_total_size must be positive"), nil, nil
+		}
+		maxTotalSize = min(maxTotalSize, MaxBulkReadTotalSizeBytes)
+		maxFileSize = min(maxFileSize, maxTotalSize)
+
+		switch github.ArchiveFormat(format) {
+		case "":
+			format = string(github.Tarball)
+		case github.Tarball, github.Zipball:
+		default:
+			return utils.NewToolResultError(fmt.Sprintf("format must be %s or %s", github.Tarball, github.Zipball)), nil, nil
+		}
+		for i, path := range paths {
+			paths[i] = strings.Trim(path, "/")
+			if paths[i] == "" {
+				return utils.NewToolResultError(fmt.Sprintf("path at index %d must be a non-empty string", i)), nil, nil
+			}
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		if ref == "" {
+			repository, resp, err := client.Repositories.Get(ctx, owner, repo)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to get repository", resp, err), nil, nil
+			}
+			_ = resp.Body.Close()
+			ref = repository.GetDefaultBranch()
+		}
+
+		archiveURL, resp, err := client.Repositories.GetArchiveLink(ctx, owner, repo, github.ArchiveFormat(format), &github.RepositoryContentGetOptions{Ref: ref}, 1)
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to get archive link", resp, err), nil, nil
+		}
+
+		// The archive link is signed, so it is downloaded without the credentials of the client
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, archiveURL.String(), nil)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create archive request: %w", err)
+		}
+		httpResp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return utils.NewToolResultErrorFromErr("failed to download archive", err), nil, nil
+		}
+		defer func() { _ = httpResp.Body.Close() }()
+		if httpResp.StatusCode != http.StatusOK {
+			return utils.NewToolResultError(fmt.Sprintf("failed to download archive: HTTP %d", httpResp.StatusCode)), nil, nil
+		}
+
+		result := RepositoryArchiveResult{Ref: ref, Format: format}
+		matched := make(map[string]bool, len(paths))
+		budgetSpent := false
+		err = readRepositoryArchive(github.ArchiveFormat(format), httpResp.Body, func(path string, size int64, symlink bool, open func(limit int64) ([]byte, error)) error {
+			if len(paths) > 0 {
+				selected := false
+				for _, p := range paths {
+					if path == p || strings.HasPrefix(path, p+"/") {
+						matched[p] = true
+						selected = true
+					}
+				}
+				if !selected {
+					return nil
+				}
+			}
+			result.TotalFiles++
+			result.TotalSize += size
+
+			if !includeContent {
+				result.Entries = append(result.Entries, RepositoryArchiveEntry{Path: path, Size: size, Symlink: symlink})
+				return nil
+			}
+			// Once a file does not fit, the later ones are left out too so that remaining_paths
+			// can be passed back as is
+			if budgetSpent || result.BytesReturned+min(int(size), maxFileSize) > maxTotalSize {
+				budgetSpent = true
+				result.RemainingPaths = append(result.RemainingPaths, path)
+				return nil
+			}
+			// A byte past max_file_size tells a truncated file apart, whatever size the archive claims
+			content, err := open(int64(maxFileSize) + 1)
+			if err != nil {
+				return fmt.Errorf("failed to read %s: %w", path, err)
+			}
+			file := bulkFileContent(content, int(size), maxFileSize)
+			file.Path = path
+			if !file.Truncated {
+				file.SHA = gitBlobSHA(content)
+			}
+			result.Files = append(result.Files, file)
+			result.BytesReturned += min(len(content), maxFileSize)
+			return nil
+		})
+		if errors.Is(err, errArchiveTooLarge) {
+			return utils.NewToolResultError(fmt.Sprintf("%s of %s/%s@%s: %s; select fewer files with get_files_bulk", format, owner, repo, ref, err)), nil, nil
+		}
+		if err != nil {
+			return utils.NewToolResultErrorFromErr("failed to read archive", err), nil, nil
+		}
+
+		var missing []string
+		for _, p := range paths {
+			if !matched[p] {
+				missing = append(missing, p)
+			}
+		}
+		if len(missing) > 0 {
+			return utils.NewToolResultError(fmt.Sprintf(
+				"paths not found in %s/%s@%s: %s",
+				owner, repo, ref, strings.Join(missing, ", "),
+			)), nil, nil
+		}
+
+		return MarshalledTextResult(result), nil, nil
+	})
+
+	return tool, handler
+}
+
+// archiveVisitFunc is called by readRepositoryArchive for each file of an archive, with its path
+// relative to the repository root and the size the archive records. open returns at most limit
+// bytes from the start of the content of the file, decompressing no more.
+type archiveVisitFunc func(path string, size int64, symlink bool, open func(limit int64) ([]byte, error)) error
+
+// readRepositoryArchive calls visit for the files and symbolic links of a tarball or zipball of a
+// repository, in archive order. The top-level directory of the archive, named after the
+// repository and commit, is stripped from the paths. It fails with errArchiveTooLarge once more
+// than MaxRepositoryArchiveSizeBytes are read from r.
+func readRepositoryArchive(format github.ArchiveFormat, r io.Reader, visit archiveVisitFunc) error {
+	r = &limitedArchiveReader{r: r, remaining: MaxRepositoryArchiveSizeBytes}
+
+	if format == github.Zipball {
+		// Zip archives are indexed at their end, so they are read whole before listing them
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return err
+		}
+		for _, file := range archive.File {
+			mode := file.Mode()
+			if !mode.IsRegular() && mode&fs.ModeSymlink == 0 {
+				continue
+			}
+			path, ok := stripArchiveRoot(file.Name)
+			if !ok {
+				continue
+			}
+			open := func(limit int64) ([]byte, error) {
+				content, err := file.Open()
+				if err != nil {
+					return nil, err
+				}
+				defer func() { _ = content.Close() }()
+				return io.ReadAll(io.LimitReader(content, limit))
+			}
+			if err := visit(path, int64(file.UncompressedSize64), mode&fs.ModeSymlink != 0, open); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = gz.Close() }()
+	archive := tar.NewReader(gz)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var open func(limit int64) ([]byte, error)
+		switch header.Typeflag {
+		case tar.TypeReg:
+			open = func(limit int64) ([]byte, error) { return io.ReadAll(io.LimitReader(archive, limit)) }
+		case tar.TypeSymlink:
+			header.Size = int64(len(header.Linkname))
+			open = func(limit int64) ([]byte, error) {
+				return []byte(header.Linkname[:min(int64(len(header.Linkname)), limit)]), nil
+			}
+		default:
+			continue
+		}
+		path, ok := stripArchiveRoot(header.Name)
+		if !ok {
+			continue
+		}
+		if err := visit(path, header.Size, header.Typeflag == tar.TypeSymlink, open); err != nil {
+			return err
+		}
+	}
+}
+
+// stripArchiveRoot returns name without the top-level directory of the archive, and false for
+// entries outside of it.
+func stripArchiveRoot(name string) (string, bool) {
+	_, path, ok := strings.Cut(name, "/")
+	return path, ok && path != ""
+}
+
+// gitBlobSHA returns the SHA git stores content under, which matches the SHAs of tree entries.
+func gitBlobSHA(content []byte) string {
+	hash := sha1.New() //nolint:gosec // git blob SHAs are SHA-1
+	_, _ = fmt.Fprintf(hash, "blob %d\x00", len(content))
+	_, _ = hash.Write(content)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// limitedArchiveReader reads from r and fails with errArchiveTooLarge once more than remaining
+// bytes were read.
+type limitedArchiveReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (l *limitedArchiveReader) Read(p []byte) (int, error) {
+	if l.remaining < 0 {
+		return 0, errArchiveTooLarge
+	}
+	// One byte past the limit is read to tell an archive of exactly the limit from a larger one
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		return n, errArchiveTooLarge
+	}
+	return n, err
+}
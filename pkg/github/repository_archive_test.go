@@ -0,0 +1,211 @@
+package github
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v79/github"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// archiveFiles are the files of the archives served in the tests, in archive order
+var archiveFiles = []struct{ path, content string }{
+	{"README.md", "# repo\n"},
+	{"src/main.go", "package main\n"},
+	{"src/util.go", "package main\n\nfunc util() {}\n"},
+}
+
+func testTarball(t *testing.T) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	archive := tar.NewWriter(gz)
+	require.NoError(t, archive.WriteHeader(&tar.Header{Typeflag: tar.TypeXGlobalHeader, Name: "pax_global_header", PAXRecords: map[string]string{"comment": "abc123"}}))
+	require.NoError(t, archive.WriteHeader(&tar.Header{Typeflag: tar.TypeDir, Name: "owner-repo-abc123/", Mode: 0o755}))
+	for _, file := range archiveFiles {
+		require.NoError(t, archive.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: "owner-repo-abc123/" + file.path, Mode: 0o644, Size: int64(len(file.content))}))
+		_, err := archive.Write([]byte(file.content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, archive.WriteHeader(&tar.Header{Typeflag: tar.TypeSymlink, Name: "owner-repo-abc123/docs", Linkname: "README.md"}))
+	require.NoError(t, archive.Close())
+	require.NoError(t, gz.Close())
+	return buf.Bytes()
+}
+
+func testZipball(t *testing.T) []byte {
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	_, err := archive.Create("owner-repo-abc123/")
+	require.NoError(t, err)
+	for _, file := range archiveFiles {
+		w, err := archive.Create("owner-repo-abc123/" + file.path)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(file.content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, archive.Close())
+	return buf.Bytes()
+}
+
+func Test_GetRepositoryArchive(t *testing.T) {
+	tool, _ := GetRepositoryArchive(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.True(t, tool.Annotations.ReadOnlyHint)
+
+	archives := map[string][]byte{"/tarball": testTarball(t), "/zipball": testZipball(t)}
+	storage := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(archives[r.URL.Path])
+	}))
+	defer storage.Close()
+
+	redirect := func(format string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			assert.True(t, strings.HasSuffix(r.URL.Path, "/main"), r.URL.Path)
+			w.Header().Set("Location", storage.URL+"/"+format)
+			w.WriteHeader(http.StatusFound)
+		}
+	}
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(mock.GetReposByOwnerByRepo, mockResponse(t, http.StatusOK, &github.Repository{DefaultBranch: github.Ptr("main")})),
+		mock.WithRequestMatchHandler(mock.GetReposTarballByOwnerByRepoByRef, redirect("tarball")),
+		mock.WithRequestMatchHandler(mock.GetReposZipballByOwnerByRepoByRef, redirect("zipball")),
+	)
+	_, handler := GetRepositoryArchive(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+	tests := []struct {
+		name        string
+		args        map[string]any
+		expected    RepositoryArchiveResult
+		expectError string
+	}{
+		{
+			name: "list tarball",
+			args: map[string]any{},
+			expected: RepositoryArchiveResult{
+				Ref: "main", Format: "tarball", TotalFiles: 4, TotalSize: 58,
+				Entries: []RepositoryArchiveEntry{
+					{Path: "README.md", Size: 7},
+					{Path: "src/main.go", Size: 13},
+					{Path: "src/util.go", Size: 29},
+					{Path: "docs", Size: 9, Symlink: true},
+				},
+			},
+		},
+		{
+			name: "extract directory from zipball",
+			args: map[string]any{"ref": "main", "format": "zipball", "paths": []any{"src/"}, "include_content": true},
+			expected: RepositoryArchiveResult{
+				Ref: "main", Format: "zipball", TotalFiles: 2, TotalSize: 42, BytesReturned: 42,
+				Files: []BulkFile{
+					{Path: "src/main.go", SHA: gitBlobSHA([]byte("package main\n")), Size: 13, Encoding: ContentEncodingUTF8, Content: "package main\n"},
+					{Path: "src/util.go", SHA: gitBlobSHA([]byte("package main\n\nfunc util() {}\n")), Size: 29, Encoding: ContentEncodingUTF8, Content: "package main\n\nfunc util() {}\n"},
+				},
+			},
+		},
+		{
+			name: "budget",
+			args: map[string]any{"paths": []any{"README.md", "src"}, "include_content": true, "max_total_size": float64(20)},
+			expected: RepositoryArchiveResult{
+				Ref: "main", Format: "tarball", TotalFiles: 3, TotalSize: 49, BytesReturned: 20,
+				Files: []BulkFile{
+					{Path: "README.md", SHA: gitBlobSHA([]byte("# repo\n")), Size: 7, Encoding: ContentEncodingUTF8, Content: "# repo\n"},
+					{Path: "src/main.go", SHA: gitBlobSHA([]byte("package main\n")), Size: 13, Encoding: ContentEncodingUTF8, Content: "package main\n"},
+				},
+				RemainingPaths: []string{"src/util.go"},
+			},
+		},
+		{
+			name: "truncated files are read no further than max_file_size",
+			args: map[string]any{"paths": []any{"src/util.go"}, "include_content": true, "max_file_size": float64(8)},
+			expected: RepositoryArchiveResult{
+				Ref: "main", Format: "tarball", TotalFiles: 1, TotalSize: 29, BytesReturned: 8,
+				Files: []BulkFile{
+					{Path: "src/util.go", Size: 29, Encoding: ContentEncodingUTF8, Content: "package ", Truncated: true},
+				},
+			},
+		},
+		{
+			name: "truncated zipball files",
+			args: map[string]any{"format": "zipball", "paths": []any{"src/util.go"}, "include_content": true, "max_file_size": float64(8)},
+			expected: RepositoryArchiveResult{
+				Ref: "main", Format: "zipball", TotalFiles: 1, TotalSize: 29, BytesReturned: 8,
+				Files: []BulkFile{
+					{Path: "src/util.go", Size: 29, Encoding: ContentEncodingUTF8, Content: "package ", Truncated: true},
+				},
+			},
+		},
+		{
+			name:        "missing path",
+			args:        map[string]any{"paths": []any{"src", "lib"}},
+			expectError: "paths not found in owner/repo@main: lib",
+		},
+		{
+			name:        "invalid format",
+			args:        map[string]any{"format": "rar"},
+			expectError: "format must be tarball or zipball",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.args["owner"], tc.args["repo"] = "owner", "repo"
+			request := createMCPRequest(tc.args)
+			result, _, err := handler(context.Background(), &request, tc.args)
+			require.NoError(t, err)
+
+			if tc.expectError != "" {
+				require.True(t, result.IsError)
+				assert.Contains(t, getErrorResult(t, result).Text, tc.expectError)
+				return
+			}
+			require.False(t, result.IsError, getTextResult(t, result).Text)
+			var response RepositoryArchiveResult
+			require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &response))
+			assert.Equal(t, tc.expected, response)
+		})
+	}
+}
+
+func Test_readRepositoryArchive_BoundedOpen(t *testing.T) {
+	for format, data := range map[github.ArchiveFormat][]byte{github.Tarball: testTarball(t), github.Zipball: testZipball(t)} {
+		t.Run(string(format), func(t *testing.T) {
+			err := readRepositoryArchive(format, bytes.NewReader(data), func(path string, _ int64, _ bool, open func(limit int64) ([]byte, error)) error {
+				content, err := open(4)
+				require.NoError(t, err)
+				assert.LessOrEqual(t, len(content), 4, path)
+				return nil
+			})
+			require.NoError(t, err)
+		})
+	}
+}
+
+func Test_gitBlobSHA(t *testing.T) {
+	// git hash-object of an empty file and of "hello\n"
+	assert.Equal(t, "e69de29bb2d1d6434b8b29ae775ad8c2e48c5391", gitBlobSHA(nil))
+	assert.Equal(t, "ce013625030ba8dba906f756967f9e9ca394464a", gitBlobSHA([]byte("hello\n")))
+}
+
+func Test_limitedArchiveReader(t *testing.T) {
+	r := &limitedArchiveReader{r: strings.NewReader("12345"), remaining: 5}
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, "12345", string(data))
+
+	r = &limitedArchiveReader{r: strings.NewReader("123456"), remaining: 5}
+	_, err = io.ReadAll(r)
+	assert.ErrorIs(t, err, errArchiveTooLarge)
+}
@@ -0,0 +1,395 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	ghErrors "github.com/github/github-mcp-server/pkg/errors"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/github/github-mcp-server/pkg/utils"
+	"github.com/google/go-github/v79/github"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/muesli/cache2go"
+	"github.com/shurcooL/githubv4"
+)
+
+const (
+	// repositoryCatalogTTL is how long a page of discover_repositories is served from the cache
+	repositoryCatalogTTL       = 5 * time.Minute
+	repositoryCatalogCacheName = "repository-catalog"
+	// defaultOwnerTeamProperty is the custom property discover_repositories reads the owner team from
+	defaultOwnerTeamProperty = "owner"
+)
+
+// repositoryCatalog caches pages of discover_repositories, since developer portals tend to
+// ask for the same catalog over and over.
+var repositoryCatalog = cache2go.Cache(repositoryCatalogCacheName)
+
+// CatalogRelease is the latest release of a repository.
+type CatalogRelease struct {
+	TagName     string    `json:"tag_name"`
+	Name        string    `json:"name,omitempty"`
+	PublishedAt time.Time `json:"published_at"`
+}
+
+// CatalogRepository is the catalog view of a repository returned by discover_repositories.
+type CatalogRepository struct {
+	Name        string   `json:"name"`
+	FullName    string   `json:"full_name"`
+	Description string   `json:"description,omitempty"`
+	URL         string   `json:"url"`
+	Language    string   `json:"language,omitempty"`
+	Topics      []string `json:"topics"`
+	Archived    bool     `json:"archived,omitempty"`
+	// OwnerTeam is the value of the owner team custom property
+	OwnerTeam   string            `json:"owner_team,omitempty"`
+	Properties  map[string]string `json:"properties,omitempty"`
+	LastRelease *CatalogRelease   `json:"last_release,omitempty"`
+	// CIStatus is the combined status of the checks on the default branch head, e.g. SUCCESS or FAILURE
+	CIStatus string    `json:"ci_status,omitempty"`
+	PushedAt time.Time `json:"pushed_at"`
+}
+
+// DiscoverRepositoriesResult is a page of the repository catalog.
+type DiscoverRepositoriesResult struct {
+	Query        string              `json:"query"`
+	TotalCount   int                 `json:"total_count"`
+	Repositories []CatalogRepository `json:"repositories"`
+	HasNextPage  bool                `json:"has_next_page"`
+	EndCursor    string              `json:"end_cursor,omitempty"`
+	// Cached is set when the page was served from the cache rather than queried
+	Cached bool `json:"cached,omitempty"`
+	// Warnings lists catalog fields that could not be filled in
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+type catalogRepositoryNode struct {
+	Name            githubv4.String
+	NameWithOwner   githubv4.String
+	Description     githubv4.String
+	URL             githubv4.String `graphql:"url"`
+	IsArchived      githubv4.Boolean
+	PushedAt        githubv4.DateTime
+	PrimaryLanguage *struct {
+		Name githubv4.String
+	}
+	RepositoryTopics struct {
+		Nodes []struct {
+			Topic struct {
+				Name githubv4.String
+			}
+		}
+	} `graphql:"repositoryTopics(first: 20)"`
+	LatestRelease *struct {
+		TagName     githubv4.String
+		Name        githubv4.String
+		PublishedAt githubv4.DateTime
+	}
+	DefaultBranchRef *struct {
+		Target struct {
+			Commit struct {
+				StatusCheckRollup *struct {
+					State githubv4.String
+				}
+			} `graphql:"... on Commit"`
+		}
+	}
+}
+
+// repositoryCatalogQuery fetches every catalog field of a page of repositories in one request.
+type repositoryCatalogQuery struct {
+	Search struct {
+		RepositoryCount githubv4.Int
+		PageInfo        struct {
+			HasNextPage githubv4.Boolean
+			EndCursor   githubv4.String
+		}
+		Nodes []struct {
+			Repository catalogRepositoryNode `graphql:"... on Repository"`
+		}
+	} `graphql:"search(query: $query, type: REPOSITORY, first: $first, after: $after)"`
+}
+
+// catalogSearchQuery builds the repository search query for an organization.
+func catalogSearchQuery(org string, topics []string, properties map[string]string, language string, includeArchived bool) string {
+	terms := []string{"org:" + org}
+	for _, topic := range topics {
+		terms = append(terms, "topic:"+searchValue(topic))
+	}
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		terms = append(terms, "props."+name+":"+searchValue(properties[name]))
+	}
+	if language != "" {
+		terms = append(terms, "language:"+searchValue(language))
+	}
+	if !includeArchived {
+		terms = append(terms, "archived:false")
+	}
+	return strings.Join(terms, " ")
+}
+
+// searchValue quotes a qualifier value that contains spaces.
+func searchValue(value string) string {
+	if strings.ContainsAny(value, " \t") {
+		return `"` + strings.ReplaceAll(value, `"`, "") + `"`
+	}
+	return value
+}
+
+// customPropertyString formats a custom property value, which is a string or a list of strings.
+func customPropertyString(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []string:
+		return strings.Join(v, ",")
+	case []any:
+		parts := make([]string, 0, len(v))
+		for _, part := range v {
+			parts = append(parts, fmt.Sprint(part))
+		}
+		return strings.Join(parts, ",")
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// DiscoverRepositories creates a tool to list an organization's repositories as a catalog.
+func DiscoverRepositories(getClient GetClientFn, getGQLClient GetGQLClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	return mcp.Tool{
+			Name:        "discover_repositories",
+			Description: t("TOOL_DISCOVER_REPOSITORIES_DESCRIPTION", "Discover the repositories of an organization by topics and custom properties, returning a catalog view of each: owner team, language, topics, latest release and CI status of the default branch. Intended for service catalogs and developer portals. Pages are cached for a few minutes; set refresh to bypass the cache."),
+			Annotations: &mcp.ToolAnnotations{
+				Title:        t("TOOL_DISCOVER_REPOSITORIES_USER_TITLE", "Discover repositories"),
+				ReadOnlyHint: true,
+			},
+			InputSchema: WithCursorPagination(&jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"org": {
+						Type:        "string",
+						Description: "Organization login",
+					},
+					"topics": {
+						Type:        "array",
+						Description: "Topics every repository must have",
+						Items: &jsonschema.Schema{
+							Type: "string",
+						},
+					},
+					"properties": {
+						Type:        "object",
+						Description: "Custom property values every repository must have, keyed by property name, e.g. {\"tier\": \"critical\"}",
+						AdditionalProperties: &jsonschema.Schema{
+							Type: "string",
+						},
+					},
+					"language": {
+						Type:        "string",
+						Description: "Primary language of the repositories",
+					},
+					"include_archived": {
+						Type:        "boolean",
+						Description: "Include archived repositories (default: false)",
+					},
+					"owner_team_property": {
+						Type:        "string",
+						Description: fmt.Sprintf("Custom property holding the team that owns a repository (default: %s)", defaultOwnerTeamProperty),
+					},
+					"refresh": {
+						Type:        "boolean",
+						Description: "Query GitHub even if the page is cached",
+					},
+				},
+				Required: []string{"org"},
+			}),
+		},
+		func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+			org, err := RequiredParam[string](args, "org")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			topics, err := OptionalStringArrayParam(args, "topics")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			properties := make(map[string]string)
+			if raw, ok := args["properties"]; ok && raw != nil {
+				propertiesObj, ok := raw.(map[string]any)
+				if !ok {
+					return utils.NewToolResultError("properties must be an object mapping property names to values"), nil, nil
+				}
+				for name, value := range propertiesObj {
+					text, ok := value.(string)
+					if !ok || text == "" || strings.ContainsAny(name, " :") {
+						return utils.NewToolResultError(fmt.Sprintf("property %q must be a name without spaces or colons and a non-empty string value", name)), nil, nil
+					}
+					properties[name] = text
+				}
+			}
+			language, err := OptionalParam[string](args, "language")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			includeArchived, err := OptionalParam[bool](args, "include_archived")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			ownerProperty, err := OptionalParam[string](args, "owner_team_property")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			if ownerProperty == "" {
+				ownerProperty = defaultOwnerTeamProperty
+			}
+			refresh, err := OptionalParam[bool](args, "refresh")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			pagination, err := OptionalCursorPaginationParams(args)
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			paginationParams, err := pagination.ToGraphQLParams()
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+
+			query := catalogSearchQuery(org, topics, properties, language, includeArchived)
+			cacheKey := fmt.Sprintf("%s|%d|%s|%s", query, *paginationParams.First, pagination.After, ownerProperty)
+			if !refresh {
+				if item, err := repositoryCatalog.Value(cacheKey); err == nil {
+					if cached, ok := item.Data().(DiscoverRepositoriesResult); ok {
+						cached.Cached = true
+						return MarshalledTextResult(cached), nil, nil
+					}
+				}
+			}
+
+			gqlClient, err := getGQLClient(ctx)
+			if err != nil {
+				return utils.NewToolResultErrorFromErr("failed to get GitHub GQL client", err), nil, nil
+			}
+			vars := map[string]any{
+				"query": githubv4.String(query),
+				"first": githubv4.Int(*paginationParams.First),
+				"after": (*githubv4.String)(nil),
+			}
+			if paginationParams.After != nil {
+				vars["after"] = githubv4.NewString(githubv4.String(*paginationParams.After))
+			}
+			var q repositoryCatalogQuery
+			if err := gqlClient.Query(ctx, &q, vars); err != nil {
+				return ghErrors.NewGitHubGraphQLErrorResponse(ctx, "failed to search repositories", err), nil, nil
+			}
+
+			result := DiscoverRepositoriesResult{
+				Query:        query,
+				TotalCount:   int(q.Search.RepositoryCount),
+				Repositories: make([]CatalogRepository, 0, len(q.Search.Nodes)),
+				HasNextPage:  bool(q.Search.PageInfo.HasNextPage),
+				EndCursor:    string(q.Search.PageInfo.EndCursor),
+			}
+			for _, node := range q.Search.Nodes {
+				repo := node.Repository
+				entry := CatalogRepository{
+					Name:        string(repo.Name),
+					FullName:    string(repo.NameWithOwner),
+					Description: string(repo.Description),
+					URL:         string(repo.URL),
+					Archived:    bool(repo.IsArchived),
+					PushedAt:    repo.PushedAt.Time,
+					Topics:      make([]string, 0, len(repo.RepositoryTopics.Nodes)),
+				}
+				if repo.PrimaryLanguage != nil {
+					entry.Language = string(repo.PrimaryLanguage.Name)
+				}
+				for _, topic := range repo.RepositoryTopics.Nodes {
+					entry.Topics = append(entry.Topics, string(topic.Topic.Name))
+				}
+				if release := repo.LatestRelease; release != nil {
+					entry.LastRelease = &CatalogRelease{
+						TagName:     string(release.TagName),
+						Name:        string(release.Name),
+						PublishedAt: release.PublishedAt.Time,
+					}
+				}
+				if ref := repo.DefaultBranchRef; ref != nil && ref.Target.Commit.StatusCheckRollup != nil {
+					entry.CIStatus = string(ref.Target.Commit.StatusCheckRollup.State)
+				}
+				result.Repositories = append(result.Repositories, entry)
+			}
+
+			// Custom properties are not in the GraphQL API, so the values of the whole page are
+			// listed with one REST call
+			if len(result.Repositories) > 0 {
+				client, err := getClient(ctx)
+				if err != nil {
+					return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+				}
+				if err := addCatalogProperties(ctx, client, org, result.Repositories, ownerProperty); err != nil {
+					result.Warnings = append(result.Warnings, fmt.Sprintf("custom properties are missing: %s", err))
+				}
+			}
+
+			repositoryCatalog.Add(cacheKey, repositoryCatalogTTL, result)
+			return MarshalledTextResult(result), nil, nil
+		}
+}
+
+// addCatalogProperties fills in the custom properties and owner team of repositories.
+func addCatalogProperties(ctx context.Context, client *github.Client, org string, repositories []CatalogRepository, ownerProperty string) error {
+	byName := make(map[string]*CatalogRepository, len(repositories))
+	terms := make([]string, 0, len(repositories))
+	for i := range repositories {
+		byName[strings.ToLower(repositories[i].FullName)] = &repositories[i]
+		terms = append(terms, "repo:"+repositories[i].FullName)
+	}
+
+	opts := &github.ListCustomPropertyValuesOptions{
+		RepositoryQuery: strings.Join(terms, " "),
+		ListOptions:     github.ListOptions{PerPage: 100},
+	}
+	for {
+		values, resp, err := client.Organizations.ListCustomPropertyValues(ctx, org, opts)
+		if err != nil {
+			_, _ = ghErrors.NewGitHubAPIErrorToCtx(ctx, "failed to list custom property values", resp, err)
+			return err
+		}
+		_ = resp.Body.Close()
+		for _, value := range values {
+			repo, ok := byName[strings.ToLower(value.RepositoryFullName)]
+			if !ok {
+				continue
+			}
+			for _, property := range value.Properties {
+				text := customPropertyString(property.Value)
+				if text == "" {
+					continue
+				}
+				if repo.Properties == nil {
+					repo.Properties = make(map[string]string)
+				}
+				repo.Properties[property.PropertyName] = text
+				if property.PropertyName == ownerProperty {
+					repo.OwnerTeam = text
+				}
+			}
+		}
+		if resp.NextPage == 0 {
+			return nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
@@ -0,0 +1,133 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/github/github-mcp-server/internal/githubv4mock"
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v79/github"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const repositoryCatalogQueryString = "query($after:String$first:Int!$query:String!){search(query: $query, type: REPOSITORY, first: $first, after: $after){repositoryCount,pageInfo{hasNextPage,endCursor},nodes{... on Repository{name,nameWithOwner,description,url,isArchived,pushedAt,primaryLanguage{name},repositoryTopics(first: 20){nodes{topic{name}}},latestRelease{tagName,name,publishedAt},defaultBranchRef{target{... on Commit{statusCheckRollup{state}}}}}}}}"
+
+func Test_DiscoverRepositories(t *testing.T) {
+	t.Cleanup(repositoryCatalog.Flush)
+
+	toolDef, _ := DiscoverRepositories(nil, nil, translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(toolDef.Name, toolDef))
+
+	schema, ok := toolDef.InputSchema.(*jsonschema.Schema)
+	require.True(t, ok, "InputSchema should be *jsonschema.Schema")
+	assert.Equal(t, "discover_repositories", toolDef.Name)
+	assert.Contains(t, schema.Properties, "topics")
+	assert.Contains(t, schema.Properties, "properties")
+	assert.Contains(t, schema.Properties, "after")
+	assert.ElementsMatch(t, schema.Required, []string{"org"})
+
+	vars := map[string]any{
+		"query": "org:corp topic:payments props.tier:critical archived:false",
+		"first": float64(30),
+		"after": (*string)(nil),
+	}
+	response := githubv4mock.DataResponse(map[string]any{
+		"search": map[string]any{
+			"repositoryCount": 1,
+			"pageInfo":        map[string]any{"hasNextPage": false, "endCursor": "cursor-1"},
+			"nodes": []any{
+				map[string]any{
+					"name":            "ledger",
+					"nameWithOwner":   "corp/ledger",
+					"description":     "Payments ledger",
+					"url":             "https://github.com/corp/ledger",
+					"isArchived":      false,
+					"pushedAt":        "2026-09-01T10:00:00Z",
+					"primaryLanguage": map[string]any{"name": "Go"},
+					"repositoryTopics": map[string]any{
+						"nodes": []any{map[string]any{"topic": map[string]any{"name": "payments"}}},
+					},
+					"latestRelease": map[string]any{"tagName": "v1.4.0", "name": "1.4.0", "publishedAt": "2026-08-20T09:00:00Z"},
+					"defaultBranchRef": map[string]any{
+						"target": map[string]any{"statusCheckRollup": map[string]any{"state": "SUCCESS"}},
+					},
+				},
+			},
+		},
+	})
+	gqlClient := githubv4.NewClient(githubv4mock.NewMockedHTTPClient(
+		githubv4mock.NewQueryMatcher(repositoryCatalogQueryString, vars, response),
+	))
+
+	propertyCalls := 0
+	restClient := github.NewClient(mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.GetOrgsPropertiesValuesByOrg,
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				propertyCalls++
+				assert.Equal(t, "repo:corp/ledger", r.URL.Query().Get("repository_query"))
+				mockResponse(t, http.StatusOK, []*github.RepoCustomPropertyValue{{
+					RepositoryFullName: "corp/ledger",
+					Properties: []*github.CustomPropertyValue{
+						{PropertyName: "owner", Value: "payments-team"},
+						{PropertyName: "tier", Value: "critical"},
+						{PropertyName: "regions", Value: []string{"eu", "us"}},
+					},
+				}})(w, r)
+			}),
+		),
+	))
+
+	_, handler := DiscoverRepositories(stubGetClientFn(restClient), stubGetGQLClientFn(gqlClient), translations.NullTranslationHelper)
+	args := map[string]any{
+		"org":        "corp",
+		"topics":     []any{"payments"},
+		"properties": map[string]any{"tier": "critical"},
+	}
+
+	call := func() DiscoverRepositoriesResult {
+		request := createMCPRequest(args)
+		result, _, err := handler(context.Background(), &request, args)
+		require.NoError(t, err)
+		require.False(t, result.IsError, getTextResult(t, result).Text)
+		var catalog DiscoverRepositoriesResult
+		require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &catalog))
+		return catalog
+	}
+
+	catalog := call()
+	assert.False(t, catalog.Cached)
+	assert.Equal(t, 1, catalog.TotalCount)
+	assert.Equal(t, "cursor-1", catalog.EndCursor)
+	require.Len(t, catalog.Repositories, 1)
+	repo := catalog.Repositories[0]
+	assert.Equal(t, "corp/ledger", repo.FullName)
+	assert.Equal(t, "Go", repo.Language)
+	assert.Equal(t, []string{"payments"}, repo.Topics)
+	assert.Equal(t, "payments-team", repo.OwnerTeam)
+	assert.Equal(t, map[string]string{"owner": "payments-team", "tier": "critical", "regions": "eu,us"}, repo.Properties)
+	assert.Equal(t, "SUCCESS", repo.CIStatus)
+	require.NotNil(t, repo.LastRelease)
+	assert.Equal(t, "v1.4.0", repo.LastRelease.TagName)
+
+	// The second call is served from the cache
+	cached := call()
+	assert.True(t, cached.Cached)
+	assert.Equal(t, catalog.Repositories, cached.Repositories)
+	assert.Equal(t, 1, propertyCalls)
+}
+
+func Test_catalogSearchQuery(t *testing.T) {
+	assert.Equal(t, "org:corp archived:false", catalogSearchQuery("corp", nil, nil, "", false))
+	assert.Equal(t,
+		`org:corp topic:api props.env:prod props.team:"core platform" language:go`,
+		catalogSearchQuery("corp", []string{"api"}, map[string]string{"team": "core platform", "env": "prod"}, "go", true),
+	)
+}
@@ -0,0 +1,443 @@
+package github
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	ghErrors "github.com/github/github-mcp-server/pkg/errors"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/github/github-mcp-server/pkg/utils"
+	"github.com/google/go-github/v79/github"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Sections of a repository export, in the order they are exported
+const (
+	ExportSectionLabels     = "labels"
+	ExportSectionMilestones = "milestones"
+	ExportSectionReleases   = "releases"
+	ExportSectionIssues     = "issues"
+)
+
+// exportSections lists the sections of a repository export in order
+var exportSections = []string{ExportSectionLabels, ExportSectionMilestones, ExportSectionReleases, ExportSectionIssues}
+
+const (
+	// repositoryExportVersion is the version of the archive format of export_repository_metadata
+	repositoryExportVersion = 1
+	// exportPageSize is the number of items requested per API page
+	exportPageSize = 100
+	// defaultExportMaxPages and maxExportMaxPages bound the API pages read by one call
+	defaultExportMaxPages = 5
+	maxExportMaxPages     = 20
+)
+
+// ExportedLabel is a label in a repository export.
+type ExportedLabel struct {
+	Name        string `json:"name"`
+	Color       string `json:"color"`
+	Description string `json:"description,omitempty"`
+}
+
+// ExportedMilestone is a milestone in a repository export.
+type ExportedMilestone struct {
+	Number      int        `json:"number"`
+	Title       string     `json:"title"`
+	Description string     `json:"description,omitempty"`
+	State       string     `json:"state"`
+	DueOn       *time.Time `json:"due_on,omitempty"`
+}
+
+// ExportedRelease is a release in a repository export.
+type ExportedRelease struct {
+	TagName         string     `json:"tag_name"`
+	Name            string     `json:"name,omitempty"`
+	Body            string     `json:"body,omitempty"`
+	TargetCommitish string     `json:"target_commitish,omitempty"`
+	Draft           bool       `json:"draft"`
+	Prerelease      bool       `json:"prerelease"`
+	CreatedAt       *time.Time `json:"created_at,omitempty"`
+	PublishedAt     *time.Time `json:"published_at,omitempty"`
+}
+
+// ExportedIssueComment is an issue comment in a repository export.
+type ExportedIssueComment struct {
+	Body      string     `json:"body"`
+	Author    string     `json:"author,omitempty"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+}
+
+// ExportedIssue is an issue in a repository export. Its fields match the issue specs of
+// import_issues, with source_id set to the issue number.
+type ExportedIssue struct {
+	SourceID    string                 `json:"source_id"`
+	Number      int                    `json:"number"`
+	URL         string                 `json:"url"`
+	Author      string                 `json:"author,omitempty"`
+	Title       string                 `json:"title"`
+	Body        string                 `json:"body,omitempty"`
+	Labels      []string               `json:"labels,omitempty"`
+	Assignees   []string               `json:"assignees,omitempty"`
+	Milestone   int                    `json:"milestone,omitempty"`
+	Comments    []ExportedIssueComment `json:"comments,omitempty"`
+	Closed      bool                   `json:"closed"`
+	StateReason string                 `json:"state_reason,omitempty"`
+	CreatedAt   *time.Time             `json:"created_at,omitempty"`
+	ClosedAt    *time.Time             `json:"closed_at,omitempty"`
+}
+
+// RepositoryExport is one page of the archive produced by export_repository_metadata.
+type RepositoryExport struct {
+	Version    int                 `json:"version"`
+	Owner      string              `json:"owner"`
+	Repo       string              `json:"repo"`
+	Labels     []ExportedLabel     `json:"labels,omitempty"`
+	Milestones []ExportedMilestone `json:"milestones,omitempty"`
+	Releases   []ExportedRelease   `json:"releases,omitempty"`
+	Issues     []ExportedIssue     `json:"issues,omitempty"`
+	// Complete is set when every included section has been exported
+	Complete bool `json:"complete"`
+	// NextCursor resumes the export where this page stopped
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// exportCursor is the position of an export: the API page of a section to read next.
+type exportCursor struct {
+	Section string `json:"section"`
+	Page    int    `json:"page"`
+}
+
+// encodeExportCursor returns the opaque form of c given to callers.
+func encodeExportCursor(c exportCursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeExportCursor parses a cursor returned by a previous call.
+func decodeExportCursor(s string) (exportCursor, error) {
+	var c exportCursor
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err == nil {
+		err = json.Unmarshal(data, &c)
+	}
+	if err != nil || !slices.Contains(exportSections, c.Section) || c.Page < 1 {
+		return exportCursor{}, fmt.Errorf("invalid cursor: pass the next_cursor of a previous export_repository_metadata call")
+	}
+	return c, nil
+}
+
+// ExportRepositoryMetadata creates a tool to export the issues, labels, milestones and releases of a repository.
+func ExportRepositoryMetadata(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name: "export_repository_metadata",
+		Description: t("TOOL_EXPORT_REPOSITORY_METADATA_DESCRIPTION", "Export the labels, milestones, releases and issues of a repository as a JSON archive, e.g. to migrate them to another repository. "+
+			"The archive is returned in pages: while complete is false, call again with next_cursor and merge the sections of each page. "+
+			"Exported issues can be passed to import_issues as they are. Pull requests are not included"),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_EXPORT_REPOSITORY_METADATA_USER_TITLE", "Export repository metadata"),
+			ReadOnlyHint: true,
+		},
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"owner": {
+					Type:        "string",
+					Description: "Repository owner",
+				},
+				"repo": {
+					Type:        "string",
+					Description: "Repository name",
+				},
+				"include": {
+					Type:        "array",
+					Description: "Sections to export (default: all)",
+					Items: &jsonschema.Schema{
+						Type: "string",
+						Enum: []any{ExportSectionLabels, ExportSectionMilestones, ExportSectionReleases, ExportSectionIssues},
+					},
+				},
+				"include_comments": {
+					Type:        "boolean",
+					Description: "Export the comments of each issue, at the cost of one more request per commented issue (default: false)",
+				},
+				"state": {
+					Type:        "string",
+					Description: "State of the issues and milestones to export (default: all)",
+					Enum:        []any{"open", "closed", "all"},
+				},
+				"cursor": {
+					Type:        "string",
+					Description: "next_cursor of the previous call, to resume the export",
+				},
+				"max_pages": {
+					Type:        "number",
+					Description: fmt.Sprintf("API pages of %d items to read in this call (default: %d, max %d)", exportPageSize, defaultExportMaxPages, maxExportMaxPages),
+					Minimum:     jsonschema.Ptr(1.0),
+					Maximum:     jsonschema.Ptr(float64(maxExportMaxPages)),
+				},
+			},
+			Required: []string{"owner", "repo"},
+		},
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		repo, err := RequiredParam[string](args, "repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		include, err := OptionalStringArrayParam(args, "include")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		for _, section := range include {
+			if !slices.Contains(exportSections, section) {
+				return utils.NewToolResultError(fmt.Sprintf("unknown section %q: use labels, milestones, releases or issues", section)), nil, nil
+			}
+		}
+		if len(include) == 0 {
+			include = exportSections
+		}
+		includeComments, err := OptionalParam[bool](args, "include_comments")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		state, err := OptionalParam[string](args, "state")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		if state == "" {
+			state = "all"
+		}
+		maxPages, err := OptionalIntParamWithDefault(args, "max_pages", defaultExportMaxPages)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		if maxPages < 1 || maxPages > maxExportMaxPages {
+			return utils.NewToolResultError(fmt.Sprintf("max_pages must be between 1 and %d", maxExportMaxPages)), nil, nil
+		}
+
+		// Sections are exported in a fixed order, so that a cursor names a position in it
+		var sections []string
+		for _, section := range exportSections {
+			if slices.Contains(include, section) {
+				sections = append(sections, section)
+			}
+		}
+		position := exportCursor{Section: sections[0], Page: 1}
+		cursorArg, err := OptionalParam[string](args, "cursor")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		if cursorArg != "" {
+			position, err = decodeExportCursor(cursorArg)
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			if !slices.Contains(sections, position.Section) {
+				return utils.NewToolResultError(fmt.Sprintf("cursor resumes the %s section, which include leaves out", position.Section)), nil, nil
+			}
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		export := &RepositoryExport{Version: repositoryExportVersion, Owner: owner, Repo: repo}
+		section := slices.Index(sections, position.Section)
+		page := position.Page
+		for pages := 0; pages < maxPages; pages++ {
+			var nextPage int
+			var resp *github.Response
+			switch sections[section] {
+			case ExportSectionLabels:
+				nextPage, resp, err = exportLabels(ctx, client, owner, repo, page, export)
+			case ExportSectionMilestones:
+				nextPage, resp, err = exportMilestones(ctx, client, owner, repo, state, page, export)
+			case ExportSectionReleases:
+				nextPage, resp, err = exportReleases(ctx, client, owner, repo, page, export)
+			case ExportSectionIssues:
+				nextPage, resp, err = exportIssues(ctx, client, owner, repo, state, includeComments, page, export)
+			}
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx, fmt.Sprintf("failed to export %s", sections[section]), resp, err), nil, nil
+			}
+
+			if nextPage != 0 {
+				page = nextPage
+				continue
+			}
+			section++
+			page = 1
+			if section == len(sections) {
+				export.Complete = true
+				break
+			}
+		}
+		if !export.Complete {
+			export.NextCursor = encodeExportCursor(exportCursor{Section: sections[section], Page: page})
+		}
+
+		return MarshalledTextResult(export), nil, nil
+	})
+
+	return tool, handler
+}
+
+// exportLabels appends a page of labels to export and returns the next page, 0 after the last.
+func exportLabels(ctx context.Context, client *github.Client, owner, repo string, page int, export *RepositoryExport) (int, *github.Response, error) {
+	labels, resp, err := client.Issues.ListLabels(ctx, owner, repo, &github.ListOptions{Page: page, PerPage: exportPageSize})
+	if err != nil {
+		return 0, resp, err
+	}
+	_ = resp.Body.Close()
+	for _, label := range labels {
+		export.Labels = append(export.Labels, ExportedLabel{
+			Name:        label.GetName(),
+			Color:       label.GetColor(),
+			Description: label.GetDescription(),
+		})
+	}
+	return resp.NextPage, resp, nil
+}
+
+// exportMilestones appends a page of milestones to export and returns the next page, 0 after the last.
+func exportMilestones(ctx context.Context, client *github.Client, owner, repo, state string, page int, export *RepositoryExport) (int, *github.Response, error) {
+	milestones, resp, err := client.Issues.ListMilestones(ctx, owner, repo, &github.MilestoneListOptions{
+		State:       state,
+		Sort:        "due_on",
+		Direction:   "asc",
+		ListOptions: github.ListOptions{Page: page, PerPage: exportPageSize},
+	})
+	if err != nil {
+		return 0, resp, err
+	}
+	_ = resp.Body.Close()
+	for _, milestone := range milestones {
+		export.Milestones = append(export.Milestones, ExportedMilestone{
+			Number:      milestone.GetNumber(),
+			Title:       milestone.GetTitle(),
+			Description: milestone.GetDescription(),
+			State:       milestone.GetState(),
+			DueOn:       exportTime(milestone.DueOn),
+		})
+	}
+	return resp.NextPage, resp, nil
+}
+
+// exportReleases appends a page of releases to export and returns the next page, 0 after the last.
+func exportReleases(ctx context.Context, client *github.Client, owner, repo string, page int, export *RepositoryExport) (int, *github.Response, error) {
+	releases, resp, err := client.Repositories.ListReleases(ctx, owner, repo, &github.ListOptions{Page: page, PerPage: exportPageSize})
+	if err != nil {
+		return 0, resp, err
+	}
+	_ = resp.Body.Close()
+	for _, release := range releases {
+		export.Releases = append(export.Releases, ExportedRelease{
+			TagName:         release.GetTagName(),
+			Name:            release.GetName(),
+			Body:            release.GetBody(),
+			TargetCommitish: release.GetTargetCommitish(),
+			Draft:           release.GetDraft(),
+			Prerelease:      release.GetPrerelease(),
+			CreatedAt:       exportTime(release.CreatedAt),
+			PublishedAt:     exportTime(release.PublishedAt),
+		})
+	}
+	return resp.NextPage, resp, nil
+}
+
+// exportIssues appends a page of issues to export, oldest first, and returns the next page, 0 after the last.
+// Pull requests, which the issues API also lists, are skipped.
+func exportIssues(ctx context.Context, client *github.Client, owner, repo, state string, includeComments bool, page int, export *RepositoryExport) (int, *github.Response, error) {
+	issues, resp, err := client.Issues.ListByRepo(ctx, owner, repo, &github.IssueListByRepoOptions{
+		State:       state,
+		Sort:        "created",
+		Direction:   "asc",
+		ListOptions: github.ListOptions{Page: page, PerPage: exportPageSize},
+	})
+	if err != nil {
+		return 0, resp, err
+	}
+	_ = resp.Body.Close()
+
+	for _, issue := range issues {
+		if issue.IsPullRequest() {
+			continue
+		}
+		exported := ExportedIssue{
+			SourceID:    strconv.Itoa(issue.GetNumber()),
+			Number:      issue.GetNumber(),
+			URL:         issue.GetHTMLURL(),
+			Author:      issue.GetUser().GetLogin(),
+			Title:       issue.GetTitle(),
+			Body:        issue.GetBody(),
+			Milestone:   issue.GetMilestone().GetNumber(),
+			Closed:      issue.GetState() == "closed",
+			StateReason: issue.GetStateReason(),
+			CreatedAt:   exportTime(issue.CreatedAt),
+			ClosedAt:    exportTime(issue.ClosedAt),
+		}
+		for _, label := range issue.Labels {
+			exported.Labels = append(exported.Labels, label.GetName())
+		}
+		for _, assignee := range issue.Assignees {
+			exported.Assignees = append(exported.Assignees, assignee.GetLogin())
+		}
+		if includeComments && issue.GetComments() > 0 {
+			exported.Comments, resp, err = exportIssueComments(ctx, client, owner, repo, issue.GetNumber())
+			if err != nil {
+				return 0, resp, err
+			}
+		}
+		export.Issues = append(export.Issues, exported)
+	}
+	return resp.NextPage, resp, nil
+}
+
+// exportIssueComments returns all comments of an issue, oldest first.
+func exportIssueComments(ctx context.Context, client *github.Client, owner, repo string, number int) ([]ExportedIssueComment, *github.Response, error) {
+	var comments []ExportedIssueComment
+	opts := &github.IssueListCommentsOptions{
+		Sort:        github.Ptr("created"),
+		Direction:   github.Ptr("asc"),
+		ListOptions: github.ListOptions{PerPage: exportPageSize},
+	}
+	for {
+		page, resp, err := client.Issues.ListComments(ctx, owner, repo, number, opts)
+		if err != nil {
+			return nil, resp, err
+		}
+		_ = resp.Body.Close()
+		for _, comment := range page {
+			comments = append(comments, ExportedIssueComment{
+				Body:      comment.GetBody(),
+				Author:    comment.GetUser().GetLogin(),
+				CreatedAt: exportTime(comment.CreatedAt),
+			})
+		}
+		if resp.NextPage == 0 {
+			return comments, resp, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// exportTime returns the time of ts, or nil when it is unset.
+func exportTime(ts *github.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.UTC()
+	return &t
+}
@@ -0,0 +1,262 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v79/github"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// withNextPage adds a Link header pointing at page to the responses of handler.
+func withNextPage(page string, handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", `<https://api.github.com/resource?page=`+page+`>; rel="next"`)
+		handler(w, r)
+	}
+}
+
+func Test_ExportRepositoryMetadata(t *testing.T) {
+	mockClient := github.NewClient(nil)
+	tool, _ := ExportRepositoryMetadata(stubGetClientFn(mockClient), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	schema, ok := tool.InputSchema.(*jsonschema.Schema)
+	require.True(t, ok, "InputSchema should be *jsonschema.Schema")
+	assert.Equal(t, "export_repository_metadata", tool.Name)
+	assert.True(t, tool.Annotations.ReadOnlyHint)
+	assert.Contains(t, schema.Properties, "cursor")
+	assert.Contains(t, schema.Properties, "include")
+	assert.ElementsMatch(t, schema.Required, []string{"owner", "repo"})
+
+	created := github.Timestamp{Time: time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)}
+	closed := github.Timestamp{Time: time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC)}
+	issues := []*github.Issue{
+		{
+			Number:      github.Ptr(1),
+			Title:       github.Ptr("Crash"),
+			Body:        github.Ptr("It crashes"),
+			State:       github.Ptr("closed"),
+			StateReason: github.Ptr("completed"),
+			HTMLURL:     github.Ptr("https://github.com/owner/repo/issues/1"),
+			User:        &github.User{Login: github.Ptr("octocat")},
+			Labels:      []*github.Label{{Name: github.Ptr("bug")}},
+			Assignees:   []*github.User{{Login: github.Ptr("hubot")}},
+			Milestone:   &github.Milestone{Number: github.Ptr(2)},
+			Comments:    github.Ptr(1),
+			CreatedAt:   &created,
+			ClosedAt:    &closed,
+		},
+		{
+			Number:           github.Ptr(2),
+			Title:            github.Ptr("A pull request"),
+			State:            github.Ptr("open"),
+			PullRequestLinks: &github.PullRequestLinks{URL: github.Ptr("https://api.github.com/repos/owner/repo/pulls/2")},
+		},
+	}
+	fullExport := func() []mock.MockBackendOption {
+		return []mock.MockBackendOption{
+			mock.WithRequestMatch(
+				mock.GetReposLabelsByOwnerByRepo,
+				[]*github.Label{{Name: github.Ptr("bug"), Color: github.Ptr("d73a4a"), Description: github.Ptr("Something is broken")}},
+			),
+			mock.WithRequestMatch(
+				mock.GetReposMilestonesByOwnerByRepo,
+				[]*github.Milestone{{Number: github.Ptr(2), Title: github.Ptr("v1"), State: github.Ptr("open")}},
+			),
+			mock.WithRequestMatch(
+				mock.GetReposReleasesByOwnerByRepo,
+				[]*github.RepositoryRelease{{TagName: github.Ptr("v1.0.0"), Name: github.Ptr("First"), PublishedAt: &created}},
+			),
+			mock.WithRequestMatchHandler(
+				mock.GetReposIssuesByOwnerByRepo,
+				expectQueryParams(t, map[string]string{
+					"state":     "all",
+					"sort":      "created",
+					"direction": "asc",
+					"page":      "1",
+					"per_page":  "100",
+				}).andThen(mockResponse(t, http.StatusOK, issues)),
+			),
+			mock.WithRequestMatch(
+				mock.GetReposIssuesCommentsByOwnerByRepoByIssueNumber,
+				[]*github.IssueComment{{Body: github.Ptr("Fixed"), User: &github.User{Login: github.Ptr("hubot")}, CreatedAt: &closed}},
+			),
+		}
+	}
+
+	createdTime, closedTime := created.Time, closed.Time
+	exportedIssue := ExportedIssue{
+		SourceID:    "1",
+		Number:      1,
+		URL:         "https://github.com/owner/repo/issues/1",
+		Author:      "octocat",
+		Title:       "Crash",
+		Body:        "It crashes",
+		Labels:      []string{"bug"},
+		Assignees:   []string{"hubot"},
+		Milestone:   2,
+		Closed:      true,
+		StateReason: "completed",
+		CreatedAt:   &createdTime,
+		ClosedAt:    &closedTime,
+	}
+
+	tests := []struct {
+		name           string
+		mockedClient   *http.Client
+		requestArgs    map[string]any
+		expectError    bool
+		expectedErrMsg string
+		expected       RepositoryExport
+		expectCursor   *exportCursor
+	}{
+		{
+			name:         "exports every section",
+			mockedClient: mock.NewMockedHTTPClient(fullExport()...),
+			requestArgs: map[string]any{
+				"owner":            "owner",
+				"repo":             "repo",
+				"include_comments": true,
+			},
+			expected: RepositoryExport{
+				Version:    repositoryExportVersion,
+				Owner:      "owner",
+				Repo:       "repo",
+				Labels:     []ExportedLabel{{Name: "bug", Color: "d73a4a", Description: "Something is broken"}},
+				Milestones: []ExportedMilestone{{Number: 2, Title: "v1", State: "open"}},
+				Releases:   []ExportedRelease{{TagName: "v1.0.0", Name: "First", PublishedAt: &createdTime}},
+				Issues: []ExportedIssue{func() ExportedIssue {
+					issue := exportedIssue
+					issue.Comments = []ExportedIssueComment{{Body: "Fixed", Author: "hubot", CreatedAt: &closedTime}}
+					return issue
+				}()},
+				Complete: true,
+			},
+		},
+		{
+			name:         "stops after max_pages",
+			mockedClient: mock.NewMockedHTTPClient(fullExport()...),
+			requestArgs: map[string]any{
+				"owner":     "owner",
+				"repo":      "repo",
+				"max_pages": float64(2),
+			},
+			expected: RepositoryExport{
+				Version:    repositoryExportVersion,
+				Owner:      "owner",
+				Repo:       "repo",
+				Labels:     []ExportedLabel{{Name: "bug", Color: "d73a4a", Description: "Something is broken"}},
+				Milestones: []ExportedMilestone{{Number: 2, Title: "v1", State: "open"}},
+			},
+			expectCursor: &exportCursor{Section: ExportSectionReleases, Page: 1},
+		},
+		{
+			name: "resumes from a cursor within a section",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.GetReposIssuesByOwnerByRepo,
+					expectQueryParams(t, map[string]string{
+						"state":     "closed",
+						"sort":      "created",
+						"direction": "asc",
+						"page":      "3",
+						"per_page":  "100",
+					}).andThen(withNextPage("4", mockResponse(t, http.StatusOK, issues))),
+				),
+			),
+			requestArgs: map[string]any{
+				"owner":     "owner",
+				"repo":      "repo",
+				"include":   []any{"issues"},
+				"state":     "closed",
+				"max_pages": float64(1),
+				"cursor":    encodeExportCursor(exportCursor{Section: ExportSectionIssues, Page: 3}),
+			},
+			expected: RepositoryExport{
+				Version: repositoryExportVersion,
+				Owner:   "owner",
+				Repo:    "repo",
+				Issues:  []ExportedIssue{exportedIssue},
+			},
+			expectCursor: &exportCursor{Section: ExportSectionIssues, Page: 4},
+		},
+		{
+			name: "API error",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.GetReposLabelsByOwnerByRepo,
+					mockResponse(t, http.StatusNotFound, map[string]string{"message": "Not Found"}),
+				),
+			),
+			requestArgs:    map[string]any{"owner": "owner", "repo": "repo"},
+			expectError:    true,
+			expectedErrMsg: "failed to export labels",
+		},
+		{
+			name:           "unknown section",
+			mockedClient:   mock.NewMockedHTTPClient(),
+			requestArgs:    map[string]any{"owner": "owner", "repo": "repo", "include": []any{"wikis"}},
+			expectError:    true,
+			expectedErrMsg: `unknown section "wikis"`,
+		},
+		{
+			name:           "invalid cursor",
+			mockedClient:   mock.NewMockedHTTPClient(),
+			requestArgs:    map[string]any{"owner": "owner", "repo": "repo", "cursor": "not-a-cursor"},
+			expectError:    true,
+			expectedErrMsg: "invalid cursor",
+		},
+		{
+			name:         "cursor for an excluded section",
+			mockedClient: mock.NewMockedHTTPClient(),
+			requestArgs: map[string]any{
+				"owner":   "owner",
+				"repo":    "repo",
+				"include": []any{"labels"},
+				"cursor":  encodeExportCursor(exportCursor{Section: ExportSectionIssues, Page: 1}),
+			},
+			expectError:    true,
+			expectedErrMsg: "cursor resumes the issues section, which include leaves out",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client := github.NewClient(tc.mockedClient)
+			_, handler := ExportRepositoryMetadata(stubGetClientFn(client), translations.NullTranslationHelper)
+
+			request := createMCPRequest(tc.requestArgs)
+			result, _, err := handler(context.Background(), &request, tc.requestArgs)
+			require.NoError(t, err)
+
+			if tc.expectError {
+				require.True(t, result.IsError)
+				errorContent := getErrorResult(t, result)
+				assert.Contains(t, errorContent.Text, tc.expectedErrMsg)
+				return
+			}
+
+			require.False(t, result.IsError)
+			textContent := getTextResult(t, result)
+			var got RepositoryExport
+			require.NoError(t, json.Unmarshal([]byte(textContent.Text), &got))
+
+			if tc.expectCursor != nil {
+				cursor, err := decodeExportCursor(got.NextCursor)
+				require.NoError(t, err)
+				assert.Equal(t, *tc.expectCursor, cursor)
+				got.NextCursor = ""
+			}
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
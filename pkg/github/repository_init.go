@@ -0,0 +1,374 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	ghErrors "github.com/github/github-mcp-server/pkg/errors"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/github/github-mcp-server/pkg/utils"
+	"github.com/google/go-github/v79/github"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	// DefaultBranchName is the branch created by initialize_repository when none is configured
+	DefaultBranchName = "main"
+	// maxRequiredApprovingReviews is the largest review count GitHub accepts in branch protection
+	maxRequiredApprovingReviews = 6
+)
+
+// defaultBranchName holds the configured branch name, set at startup by SetDefaultBranch.
+var defaultBranchName atomic.Pointer[string]
+
+// SetDefaultBranch sets the branch created by initialize_repository when the call does not name
+// one. An empty name restores DefaultBranchName. Tool schemas describe the default, so it should
+// be set before the tools are created.
+func SetDefaultBranch(name string) {
+	defaultBranchName.Store(&name)
+}
+
+// CurrentDefaultBranch returns the branch created by initialize_repository by default.
+func CurrentDefaultBranch() string {
+	if name := defaultBranchName.Load(); name != nil && *name != "" {
+		return *name
+	}
+	return DefaultBranchName
+}
+
+// InitializeRepositoryResult reports how an empty repository was initialized.
+type InitializeRepositoryResult struct {
+	Branch    string   `json:"branch"`
+	CommitSHA string   `json:"commit_sha"`
+	Files     []string `json:"files"`
+	// Protected is set when the baseline branch protection was applied
+	Protected bool `json:"protected"`
+	// Warnings lists the steps that failed after the initial commit was created
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// InitializeRepository creates a tool to make the first commit of an empty repository.
+func InitializeRepository(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "initialize_repository",
+		Description: t("TOOL_INITIALIZE_REPOSITORY_DESCRIPTION", "Create the initial commit of an empty repository, with a README, a LICENSE and a .gitignore rendered from GitHub's templates, make its branch the default branch and protect it. The file tools cannot push to a repository without commits, so use this tool first on newly created empty repositories."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_INITIALIZE_REPOSITORY_USER_TITLE", "Initialize empty repository"),
+			ReadOnlyHint: false,
+		},
+		InputSchema: withCommitIdentity(&jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"owner": {
+					Type:        "string",
+					Description: "Repository owner",
+				},
+				"repo": {
+					Type:        "string",
+					Description: "Repository name. The repository must not have any commits",
+				},
+				"branch": {
+					Type:        "string",
+					Description: fmt.Sprintf("Name of the initial branch, which becomes the default branch (default: %s)", CurrentDefaultBranch()),
+				},
+				"readme": {
+					Type:        "boolean",
+					Description: "Add a README.md with the repository name and description",
+					Default:     json.RawMessage("true"),
+				},
+				"readme_content": {
+					Type:        "string",
+					Description: "Markdown content of the README.md, replacing the generated one",
+				},
+				"license": {
+					Type:        "string",
+					Description: "SPDX key of a license template to add as LICENSE, e.g. mit or apache-2.0",
+				},
+				"copyright_holder": {
+					Type:        "string",
+					Description: "Name filled into the license template. Defaults to the repository owner",
+				},
+				"gitignore_template": {
+					Type:        "string",
+					Description: "Name of a .gitignore template, e.g. Go or Node",
+				},
+				"protect_branch": {
+					Type:        "boolean",
+					Description: "Protect the branch, requiring pull request reviews and blocking force pushes and deletion",
+					Default:     json.RawMessage("true"),
+				},
+				"required_approving_review_count": {
+					Type:        "integer",
+					Description: "Approving reviews required by the branch protection",
+					Minimum:     jsonschema.Ptr(0.0),
+					Maximum:     jsonschema.Ptr(float64(maxRequiredApprovingReviews)),
+					Default:     json.RawMessage("1"),
+				},
+				"message": {
+					Type:        "string",
+					Description: "Commit message",
+					Default:     json.RawMessage(`"Initial commit"`),
+				},
+			},
+			Required: []string{"owner", "repo"},
+		}),
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		repo, err := RequiredParam[string](args, "repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		branch, err := OptionalParam[string](args, "branch")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		if branch == "" {
+			branch = CurrentDefaultBranch()
+		}
+		readme := true
+		if _, ok := args["readme"]; ok {
+			readme, err = OptionalParam[bool](args, "readme")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+		}
+		readmeContent, err := OptionalParam[string](args, "readme_content")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		license, err := OptionalParam[string](args, "license")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		copyrightHolder, err := OptionalParam[string](args, "copyright_holder")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		gitignoreTemplate, err := OptionalParam[string](args, "gitignore_template")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		protect := true
+		if _, ok := args["protect_branch"]; ok {
+			protect, err = OptionalParam[bool](args, "protect_branch")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+		}
+		reviewCount, err := OptionalIntParamWithDefault(args, "required_approving_review_count", 1)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		if reviewCount < 0 || reviewCount > maxRequiredApprovingReviews {
+			return utils.NewToolResultError(fmt.Sprintf("required_approving_review_count must be between 0 and %d", maxRequiredApprovingReviews)), nil, nil
+		}
+		message, err := OptionalParam[string](args, "message")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		if message == "" {
+			message = "Initial commit"
+		}
+		identity, err := commitIdentityFromArgs(ctx, args)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		if !readme && readmeContent == "" && license == "" && gitignoreTemplate == "" {
+			return utils.NewToolResultError("nothing to commit: enable readme or set readme_content, license or gitignore_template"), nil, nil
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		repository, resp, err := client.Repositories.Get(ctx, owner, repo)
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx,
+				"failed to get repository",
+				resp,
+				err,
+			), nil, nil
+		}
+		_ = resp.Body.Close()
+
+		// Any branch means the repository already has commits
+		branches, resp, err := client.Repositories.ListBranches(ctx, owner, repo, &github.BranchListOptions{ListOptions: github.ListOptions{PerPage: 1}})
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx,
+				"failed to list branches",
+				resp,
+				err,
+			), nil, nil
+		}
+		_ = resp.Body.Close()
+		if len(branches) > 0 {
+			return utils.NewToolResultError(fmt.Sprintf("repository %s/%s already has commits; initialize_repository only works on empty repositories", owner, repo)), nil, nil
+		}
+
+		// Render the files of the initial commit
+		var files []FileEntry
+		if readmeContent != "" {
+			files = append(files, FileEntry{Path: "README.md", Content: readmeContent})
+		} else if readme {
+			content := "# " + repository.GetName() + "\n"
+			if description := repository.GetDescription(); description != "" {
+				content += "\n" + description + "\n"
+			}
+			files = append(files, FileEntry{Path: "README.md", Content: content})
+		}
+		if license != "" {
+			template, resp, err := client.Licenses.Get(ctx, license)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					fmt.Sprintf("failed to get license template %s", license),
+					resp,
+					err,
+				), nil, nil
+			}
+			_ = resp.Body.Close()
+			if copyrightHolder == "" {
+				copyrightHolder = owner
+			}
+			files = append(files, FileEntry{Path: "LICENSE", Content: renderLicense(template.GetBody(), copyrightHolder, time.Now())})
+		}
+		if gitignoreTemplate != "" {
+			template, resp, err := client.Gitignores.Get(ctx, gitignoreTemplate)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					fmt.Sprintf("failed to get .gitignore template %s", gitignoreTemplate),
+					resp,
+					err,
+				), nil, nil
+			}
+			_ = resp.Body.Close()
+			files = append(files, FileEntry{Path: ".gitignore", Content: template.GetSource()})
+		}
+
+		// The Git Data API rejects requests on a repository without commits, so a first file is
+		// created through the Contents API. It lands on the repository's default branch and is then
+		// replaced by the initial commit made from all files.
+		bootstrapBranch := repository.GetDefaultBranch()
+		if bootstrapBranch == "" {
+			bootstrapBranch = DefaultBranchName
+		}
+		_, resp, err = client.Repositories.CreateFile(ctx, owner, repo, files[0].Path, &github.RepositoryContentFileOptions{
+			Message: github.Ptr(message),
+			Content: []byte(files[0].Content),
+		})
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx,
+				"failed to create the first commit",
+				resp,
+				err,
+			), nil, nil
+		}
+		_ = resp.Body.Close()
+
+		var entries []*github.TreeEntry
+		result := InitializeRepositoryResult{Branch: branch}
+		for _, file := range files {
+			entries = append(entries, &github.TreeEntry{
+				Path:    github.Ptr(file.Path),
+				Mode:    github.Ptr("100644"),
+				Type:    github.Ptr("blob"),
+				Content: github.Ptr(file.Content),
+			})
+			result.Files = append(result.Files, file.Path)
+		}
+		tree, resp, err := client.Git.CreateTree(ctx, owner, repo, "", entries)
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx,
+				"failed to create tree",
+				resp,
+				err,
+			), nil, nil
+		}
+		_ = resp.Body.Close()
+
+		commit := github.Commit{
+			Message: github.Ptr(message),
+			Tree:    tree,
+		}
+		identity.apply(&commit)
+		initialCommit, resp, err := client.Git.CreateCommit(ctx, owner, repo, commit, signedCommitOptions(ctx, &commit))
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx,
+				"failed to create commit",
+				resp,
+				err,
+			), nil, nil
+		}
+		_ = resp.Body.Close()
+		result.CommitSHA = initialCommit.GetSHA()
+
+		_, resp, err = client.Git.UpdateRef(ctx, owner, repo, "refs/heads/"+bootstrapBranch, github.UpdateRef{
+			SHA:   initialCommit.GetSHA(),
+			Force: github.Ptr(true),
+		})
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx,
+				"failed to update reference",
+				resp,
+				err,
+			), nil, nil
+		}
+		_ = resp.Body.Close()
+
+		// Renaming the default branch keeps it the default branch
+		if branch != bootstrapBranch {
+			_, resp, err := client.Repositories.RenameBranch(ctx, owner, repo, bootstrapBranch, branch)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					fmt.Sprintf("failed to rename branch %s to %s", bootstrapBranch, branch),
+					resp,
+					err,
+				), nil, nil
+			}
+			_ = resp.Body.Close()
+		}
+
+		// The repository is usable without protection, for example on plans that do not offer
+		// it for private repositories, so a failure is reported as a warning
+		if protect {
+			_, resp, err := client.Repositories.UpdateBranchProtection(ctx, owner, repo, branch, &github.ProtectionRequest{
+				RequiredPullRequestReviews: &github.PullRequestReviewsEnforcementRequest{
+					RequiredApprovingReviewCount: reviewCount,
+				},
+				AllowForcePushes: github.Ptr(false),
+				AllowDeletions:   github.Ptr(false),
+			})
+			if err != nil {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("failed to protect branch %s: %s", branch, err))
+			} else {
+				_ = resp.Body.Close()
+				result.Protected = true
+			}
+		}
+
+		return MarshalledTextResult(result), nil, nil
+	})
+
+	return tool, handler
+}
+
+// renderLicense fills the placeholders of a license template from GitHub's licenses API.
+func renderLicense(body, holder string, now time.Time) string {
+	return strings.NewReplacer(
+		"[year]", strconv.Itoa(now.Year()),
+		"[yyyy]", strconv.Itoa(now.Year()),
+		"[fullname]", holder,
+		"[name of copyright owner]", holder,
+	).Replace(body)
+}
@@ -0,0 +1,194 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v79/github"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_InitializeRepository(t *testing.T) {
+	mockClient := github.NewClient(nil)
+	tool, _ := InitializeRepository(stubGetClientFn(mockClient), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	schema, ok := tool.InputSchema.(*jsonschema.Schema)
+	require.True(t, ok, "InputSchema should be *jsonschema.Schema")
+
+	assert.Equal(t, "initialize_repository", tool.Name)
+	assert.Contains(t, schema.Properties, "license")
+	assert.Contains(t, schema.Properties, "gitignore_template")
+	assert.Contains(t, schema.Properties, "protect_branch")
+	assert.ElementsMatch(t, schema.Required, []string{"owner", "repo"})
+
+	repository := &github.Repository{
+		Name:          github.Ptr("repo"),
+		Description:   github.Ptr("A new project"),
+		DefaultBranch: github.Ptr("master"),
+	}
+	emptyRepoOptions := func(protection http.HandlerFunc) []mock.MockBackendOption {
+		return []mock.MockBackendOption{
+			mock.WithRequestMatch(mock.GetReposByOwnerByRepo, repository),
+			mock.WithRequestMatch(mock.GetReposBranchesByOwnerByRepo, []*github.Branch{}),
+			mock.WithRequestMatch(mock.GetLicensesByLicense, &github.License{
+				Key:  github.Ptr("mit"),
+				Body: github.Ptr("MIT License\n\nCopyright (c) [year] [fullname]\n"),
+			}),
+			mock.WithRequestMatch(mock.GetGitignoreTemplatesByName, &github.Gitignore{
+				Name:   github.Ptr("Go"),
+				Source: github.Ptr("*.test\n"),
+			}),
+			mock.WithRequestMatchHandler(
+				mock.PutReposContentsByOwnerByRepoByPath,
+				expectPath(t, "/repos/owner/repo/contents/README.md").andThen(
+					mockResponse(t, http.StatusCreated, &github.RepositoryContentResponse{}),
+				),
+			),
+			mock.WithRequestMatchHandler(
+				mock.PostReposGitTreesByOwnerByRepo,
+				expectRequestBody(t, map[string]any{
+					"tree": []any{
+						map[string]any{"path": "README.md", "mode": "100644", "type": "blob", "content": "# repo\n\nA new project\n"},
+						map[string]any{"path": "LICENSE", "mode": "100644", "type": "blob", "content": "MIT License\n\nCopyright (c) " + time.Now().Format("2006") + " octo-org\n"},
+						map[string]any{"path": ".gitignore", "mode": "100644", "type": "blob", "content": "*.test\n"},
+					},
+				}).andThen(
+					mockResponse(t, http.StatusCreated, &github.Tree{SHA: github.Ptr("initial-tree-sha")}),
+				),
+			),
+			mock.WithRequestMatchHandler(
+				mock.PostReposGitCommitsByOwnerByRepo,
+				expectRequestBody(t, map[string]any{
+					"message": "Initial commit",
+					"tree":    "initial-tree-sha",
+				}).andThen(
+					mockResponse(t, http.StatusCreated, &github.Commit{SHA: github.Ptr("initial-commit-sha")}),
+				),
+			),
+			mock.WithRequestMatchHandler(
+				mock.PatchReposGitRefsByOwnerByRepoByRef,
+				expectPath(t, "/repos/owner/repo/git/refs/heads/master").andThen(
+					mockResponse(t, http.StatusOK, &github.Reference{Ref: github.Ptr("refs/heads/master")}),
+				),
+			),
+			mock.WithRequestMatchHandler(
+				mock.PostReposBranchesRenameByOwnerByRepoByBranch,
+				expectRequestBody(t, map[string]any{"new_name": "main"}).andThen(
+					mockResponse(t, http.StatusCreated, &github.Branch{Name: github.Ptr("main")}),
+				),
+			),
+			mock.WithRequestMatchHandler(mock.PutReposBranchesProtectionByOwnerByRepoByBranch, protection),
+		}
+	}
+
+	tests := []struct {
+		name             string
+		mockedClient     *http.Client
+		args             map[string]interface{}
+		expectError      bool
+		expectedErrMsg   string
+		expectedResult   InitializeRepositoryResult
+		expectedWarnings int
+	}{
+		{
+			name: "initializes, renames and protects the branch",
+			mockedClient: mock.NewMockedHTTPClient(emptyRepoOptions(
+				expectPath(t, "/repos/owner/repo/branches/main/protection").andThen(
+					mockResponse(t, http.StatusOK, &github.Protection{}),
+				),
+			)...),
+			args: map[string]interface{}{
+				"license":            "mit",
+				"copyright_holder":   "octo-org",
+				"gitignore_template": "Go",
+			},
+			expectedResult: InitializeRepositoryResult{
+				Branch:    "main",
+				CommitSHA: "initial-commit-sha",
+				Files:     []string{"README.md", "LICENSE", ".gitignore"},
+				Protected: true,
+			},
+		},
+		{
+			name: "protection failure is a warning",
+			mockedClient: mock.NewMockedHTTPClient(emptyRepoOptions(
+				http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+					w.WriteHeader(http.StatusForbidden)
+					_, _ = w.Write([]byte(`{"message": "Upgrade to GitHub Pro or make this repository public to enable this feature."}`))
+				}),
+			)...),
+			args: map[string]interface{}{
+				"license":            "mit",
+				"copyright_holder":   "octo-org",
+				"gitignore_template": "Go",
+			},
+			expectedResult: InitializeRepositoryResult{
+				Branch:    "main",
+				CommitSHA: "initial-commit-sha",
+				Files:     []string{"README.md", "LICENSE", ".gitignore"},
+			},
+			expectedWarnings: 1,
+		},
+		{
+			name: "repository with commits",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatch(mock.GetReposByOwnerByRepo, repository),
+				mock.WithRequestMatch(mock.GetReposBranchesByOwnerByRepo, []*github.Branch{{Name: github.Ptr("master")}}),
+			),
+			expectError:    true,
+			expectedErrMsg: "already has commits",
+		},
+		{
+			name:         "nothing to commit",
+			mockedClient: mock.NewMockedHTTPClient(),
+			args: map[string]interface{}{
+				"readme": false,
+			},
+			expectError:    true,
+			expectedErrMsg: "nothing to commit",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, handler := InitializeRepository(stubGetClientFn(github.NewClient(tc.mockedClient)), translations.NullTranslationHelper)
+
+			args := map[string]interface{}{"owner": "owner", "repo": "repo"}
+			for k, v := range tc.args {
+				args[k] = v
+			}
+			request := createMCPRequest(args)
+			result, _, err := handler(context.Background(), &request, args)
+			require.NoError(t, err)
+
+			if tc.expectError {
+				assert.Contains(t, getErrorResult(t, result).Text, tc.expectedErrMsg)
+				return
+			}
+
+			require.False(t, result.IsError, getTextResult(t, result).Text)
+			var initialized InitializeRepositoryResult
+			require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &initialized))
+			assert.Len(t, initialized.Warnings, tc.expectedWarnings)
+			initialized.Warnings = nil
+			assert.Equal(t, tc.expectedResult, initialized)
+		})
+	}
+}
+
+func Test_CurrentDefaultBranch(t *testing.T) {
+	t.Cleanup(func() { SetDefaultBranch("") })
+
+	assert.Equal(t, DefaultBranchName, CurrentDefaultBranch())
+	SetDefaultBranch("trunk")
+	assert.Equal(t, "trunk", CurrentDefaultBranch())
+}
@@ -0,0 +1,440 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	pathpkg "path"
+	"sort"
+	"strings"
+
+	ghErrors "github.com/github/github-mcp-server/pkg/errors"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/github/github-mcp-server/pkg/utils"
+	"github.com/google/go-github/v79/github"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Revert states reported in RevertCommitResult.State
+const (
+	RevertStateReverted = "reverted"
+	RevertStateConflict = "conflict"
+	RevertStateEmpty    = "empty"
+)
+
+// RevertConflict is a path changed by a reverted commit that was changed again on the target
+type RevertConflict struct {
+	Path   string `json:"path"`
+	Reason string `json:"reason"`
+}
+
+// RevertCommitResult reports the revert of a commit.
+type RevertCommitResult struct {
+	SHA    string `json:"sha"`
+	Branch string `json:"branch"`
+	State  string `json:"state"`
+	// CommitSHA is the revert commit, on Branch or on the branch of PullRequest
+	CommitSHA    string           `json:"commit_sha,omitempty"`
+	FilesChanged int              `json:"files_changed"`
+	Conflicts    []RevertConflict `json:"conflicts,omitempty"`
+	// PullRequest is the revert pull request opened when create_pull_request is set
+	PullRequest *ChunkedPullRequest `json:"pull_request,omitempty"`
+}
+
+// RevertCommit creates a tool to revert a commit on a branch, directly or through a pull request.
+func RevertCommit(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "revert_commit",
+		Description: t("TOOL_REVERT_COMMIT_DESCRIPTION", "Revert a commit on a branch, like git revert, for example to undo a bulk push. The files the commit changed are restored to their state before it, and the result is committed on the branch or, with create_pull_request, on a new branch with a pull request into the branch. The revert conflicts when a file the commit changed was changed again since; conflicting paths are reported and nothing is committed."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_REVERT_COMMIT_USER_TITLE", "Revert commit"),
+			ReadOnlyHint: false,
+		},
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"owner": {
+					Type:        "string",
+					Description: "Repository owner",
+				},
+				"repo": {
+					Type:        "string",
+					Description: "Repository name",
+				},
+				"branch": {
+					Type:        "string",
+					Description: "Branch to revert the commit on",
+				},
+				"sha": {
+					Type:        "string",
+					Description: "SHA of the commit to revert",
+				},
+				"mainline": {
+					Type:        "number",
+					Description: "For a merge commit, the 1-based number of the parent whose side is kept, usually 1 for the branch merged into. Required for merge commits",
+					Minimum:     jsonschema.Ptr(1.0),
+				},
+				"message": {
+					Type:        "string",
+					Description: "Commit message. Defaults to 'Revert \"<subject>\"' and a line naming the reverted commit",
+				},
+				"create_pull_request": {
+					Type:        "boolean",
+					Description: "Commit the revert to a new branch and open a pull request into branch instead of committing to branch",
+				},
+				"head_branch": {
+					Type:        "string",
+					Description: "Name of the new branch when create_pull_request is set. Must not exist yet (default: revert-<short sha>)",
+				},
+			},
+			Required: []string{"owner", "repo", "branch", "sha"},
+		},
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		repo, err := RequiredParam[string](args, "repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		branch, err := RequiredParam[string](args, "branch")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		sha, err := RequiredParam[string](args, "sha")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		mainline, err := OptionalIntParam(args, "mainline")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		message, err := OptionalParam[string](args, "message")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		createPR, err := OptionalParam[bool](args, "create_pull_request")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		headBranch, err := OptionalParam[string](args, "head_branch")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		if headBranch != "" && !createPR {
+			return utils.NewToolResultError("head_branch requires create_pull_request"), nil, nil
+		}
+		if createPR && headBranch == branch {
+			return utils.NewToolResultError("head_branch must differ from branch, which is the base of the pull request"), nil, nil
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		commit, resp, err := client.Git.GetCommit(ctx, owner, repo, sha)
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to get commit", resp, err), nil, nil
+		}
+		_ = resp.Body.Close()
+
+		// The revert restores the files of the commit's parent, or removes every file of a root commit
+		var parentTree string
+		switch {
+		case len(commit.Parents) > 1 && mainline == 0:
+			return utils.NewToolResultError(fmt.Sprintf("commit %s is a merge commit; set mainline to the parent whose side is kept", sha)), nil, nil
+		case mainline > len(commit.Parents):
+			return utils.NewToolResultError(fmt.Sprintf("mainline %d is out of range: commit %s has %d parent(s)", mainline, sha, len(commit.Parents))), nil, nil
+		case len(commit.Parents) > 0:
+			parent, resp, err := client.Git.GetCommit(ctx, owner, repo, commit.Parents[max(mainline, 1)-1].GetSHA())
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to get parent commit", resp, err), nil, nil
+			}
+			_ = resp.Body.Close()
+			parentTree = parent.GetTree().GetSHA()
+		}
+
+		refName := "refs/heads/" + branch
+		ref, resp, err := client.Git.GetRef(ctx, owner, repo, refName)
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to get branch reference", resp, err), nil, nil
+		}
+		_ = resp.Body.Close()
+		head, resp, err := client.Git.GetCommit(ctx, owner, repo, ref.GetObject().GetSHA())
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to get branch head", resp, err), nil, nil
+		}
+		_ = resp.Body.Close()
+
+		trees := make(map[string]map[string]*github.TreeEntry)
+		headFiles, err := treeFiles(ctx, client, owner, repo, head.GetTree().GetSHA(), trees)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		changed, err := treeFiles(ctx, client, owner, repo, commit.GetTree().GetSHA(), trees)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		restored := map[string]*github.TreeEntry{}
+		if parentTree != "" {
+			if restored, err = treeFiles(ctx, client, owner, repo, parentTree, trees); err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+		}
+
+		target := newRevertTree(headFiles)
+		entries, conflicts := target.apply(treeChanges(changed, restored))
+
+		result := RevertCommitResult{SHA: sha, Branch: branch, FilesChanged: len(entries), Conflicts: conflicts}
+		switch {
+		case len(conflicts) > 0:
+			result.State = RevertStateConflict
+			return MarshalledTextResult(result), nil, nil
+		case len(entries) == 0:
+			result.State = RevertStateEmpty
+			return MarshalledTextResult(result), nil, nil
+		}
+
+		tree, resp, err := client.Git.CreateTree(ctx, owner, repo, head.GetTree().GetSHA(), entries)
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to create tree", resp, err), nil, nil
+		}
+		_ = resp.Body.Close()
+
+		subject, _, _ := strings.Cut(commit.GetMessage(), "\n")
+		if message == "" {
+			message = fmt.Sprintf("Revert %q\n\nThis reverts commit %s.", subject, sha)
+		}
+		revert := github.Commit{
+			Message: github.Ptr(message),
+			Tree:    &github.Tree{SHA: tree.SHA},
+			Parents: []*github.Commit{{SHA: head.SHA}},
+		}
+		created, resp, err := client.Git.CreateCommit(ctx, owner, repo, revert, signedCommitOptions(ctx, &revert))
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to create commit", resp, err), nil, nil
+		}
+		_ = resp.Body.Close()
+		result.State = RevertStateReverted
+		result.CommitSHA = created.GetSHA()
+
+		if !createPR {
+			_, resp, err = client.Git.UpdateRef(ctx, owner, repo, refName, github.UpdateRef{
+				SHA:   created.GetSHA(),
+				Force: github.Ptr(false),
+			})
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to update reference", resp, err), nil, nil
+			}
+			_ = resp.Body.Close()
+			return MarshalledTextResult(result), nil, nil
+		}
+
+		if headBranch == "" {
+			headBranch = "revert-" + sha[:min(len(sha), 7)]
+		}
+		_, resp, err = client.Git.CreateRef(ctx, owner, repo, github.CreateRef{
+			Ref: "refs/heads/" + headBranch,
+			SHA: created.GetSHA(),
+		})
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, fmt.Sprintf("failed to create branch %s", headBranch), resp, err), nil, nil
+		}
+		_ = resp.Body.Close()
+
+		result.PullRequest = &ChunkedPullRequest{Base: branch, Head: headBranch, Title: fmt.Sprintf("Revert %q", subject)}
+		pr, resp, err := client.PullRequests.Create(ctx, owner, repo, &github.NewPullRequest{
+			Title: github.Ptr(result.PullRequest.Title),
+			Head:  github.Ptr(headBranch),
+			Base:  github.Ptr(branch),
+			Body:  github.Ptr(fmt.Sprintf("Reverts %s, restoring %d file(s) to their state before it.", sha, len(entries))),
+		})
+		if err != nil {
+			_, _ = ghErrors.NewGitHubAPIErrorToCtx(ctx, "failed to create pull request", resp, err)
+			result.PullRequest.Error = fmt.Sprintf("failed to create pull request: %v. The revert is on branch %s; open the pull request with the create_pull_request tool", err, headBranch)
+			return MarshalledTextResult(result), nil, nil
+		}
+		_ = resp.Body.Close()
+		result.PullRequest.Number = pr.GetNumber()
+		result.PullRequest.URL = pr.GetHTMLURL()
+
+		return MarshalledTextResult(result), nil, nil
+	})
+
+	return tool, handler
+}
+
+// treeFiles returns the entries of the recursive tree sha other than directories, by
+// path. Trees are cached in trees, since the commit reverted is often the branch head.
+func treeFiles(ctx context.Context, client *github.Client, owner, repo, sha string, trees map[string]map[string]*github.TreeEntry) (map[string]*github.TreeEntry, error) {
+	if files, ok := trees[sha]; ok {
+		return files, nil
+	}
+	tree, resp, err := client.Git.GetTree(ctx, owner, repo, sha, true)
+	if err != nil {
+		_, _ = ghErrors.NewGitHubAPIErrorToCtx(ctx, "failed to get tree", resp, err)
+		return nil, fmt.Errorf("failed to get tree %s: %w", sha, err)
+	}
+	_ = resp.Body.Close()
+	if tree.GetTruncated() {
+		return nil, fmt.Errorf("tree %s is too large to list recursively, so it cannot be reverted with the API", sha)
+	}
+
+	files := make(map[string]*github.TreeEntry, len(tree.Entries))
+	for _, entry := range tree.Entries {
+		if entry.GetType() != "tree" {
+			files[entry.GetPath()] = entry
+		}
+	}
+	trees[sha] = files
+	return files, nil
+}
+
+// treeChange is a file a commit added, modified or deleted, with its entries before and
+// after the commit. Before is nil for an added file and after for a deleted one.
+type treeChange struct {
+	path          string
+	before, after *github.TreeEntry
+}
+
+// treeChanges returns the files that differ between the trees before and after a commit.
+// Deletions come first, so that a file can replace a deleted directory, and then the others by path.
+func treeChanges(before, after map[string]*github.TreeEntry) []treeChange {
+	var changes []treeChange
+	for path, entry := range before {
+		if !sameTreeEntry(entry, after[path]) {
+			changes = append(changes, treeChange{path: path, before: entry, after: after[path]})
+		}
+	}
+	for path, entry := range after {
+		if _, ok := before[path]; !ok {
+			changes = append(changes, treeChange{path: path, after: entry})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if deleted := changes[i].after == nil; deleted != (changes[j].after == nil) {
+			return deleted
+		}
+		return changes[i].path < changes[j].path
+	})
+	return changes
+}
+
+// sameTreeEntry reports whether a and b, either of which may be nil, are the same object and mode.
+func sameTreeEntry(a, b *github.TreeEntry) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.GetSHA() == b.GetSHA() && a.GetMode() == b.GetMode()
+}
+
+// revertTree tracks the files of the branch that a commit is reverted on.
+type revertTree struct {
+	files map[string]*github.TreeEntry
+	// dirs counts the files under each directory
+	dirs map[string]int
+}
+
+// newRevertTree returns a tree holding a copy of files.
+func newRevertTree(files map[string]*github.TreeEntry) *revertTree {
+	tree := &revertTree{files: make(map[string]*github.TreeEntry, len(files)), dirs: make(map[string]int)}
+	for path, entry := range files {
+		tree.set(path, entry)
+	}
+	return tree
+}
+
+// set replaces the file at path by entry, or deletes it if entry is nil.
+func (t *revertTree) set(path string, entry *github.TreeEntry) {
+	delta := 0
+	if _, exists := t.files[path]; exists {
+		delta--
+	}
+	if entry != nil {
+		t.files[path] = entry
+		delta++
+	} else {
+		delete(t.files, path)
+	}
+	if delta == 0 {
+		return
+	}
+	for dir := pathpkg.Dir(path); dir != "."; dir = pathpkg.Dir(dir) {
+		t.dirs[dir] += delta
+	}
+}
+
+// conflict returns why change cannot be applied to the tree, or an empty string if it can.
+func (t *revertTree) conflict(change treeChange) string {
+	current := t.files[change.path]
+	if !sameTreeEntry(current, change.before) {
+		switch {
+		case current == nil:
+			return "deleted on the target branch"
+		case change.before == nil:
+			return "added on the target branch"
+		default:
+			return "modified on the target branch"
+		}
+	}
+	if change.after == nil {
+		return ""
+	}
+	if t.dirs[change.path] > 0 {
+		return "is a directory on the target branch"
+	}
+	for dir := pathpkg.Dir(change.path); dir != "."; dir = pathpkg.Dir(dir) {
+		if _, exists := t.files[dir]; exists {
+			return fmt.Sprintf("%s is a file on the target branch", dir)
+		}
+	}
+	return ""
+}
+
+// apply applies changes to the tree and returns the tree entries that make the same changes,
+// leaving out those already made on the tree. If any change conflicts, the tree is left as it was
+// and the conflicts are returned instead.
+func (t *revertTree) apply(changes []treeChange) ([]*github.TreeEntry, []RevertConflict) {
+	var entries []*github.TreeEntry
+	var conflicts []RevertConflict
+	var applied []treeChange
+	for _, change := range changes {
+		if sameTreeEntry(t.files[change.path], change.after) {
+			continue
+		}
+		if reason := t.conflict(change); reason != "" {
+			conflicts = append(conflicts, RevertConflict{Path: change.path, Reason: reason})
+			continue
+		}
+		t.set(change.path, change.after)
+		applied = append(applied, change)
+
+		if change.after == nil {
+			entries = append(entries, &github.TreeEntry{
+				Path: github.Ptr(change.path),
+				Mode: github.Ptr(change.before.GetMode()),
+				Type: github.Ptr(change.before.GetType()),
+				SHA:  nil, // nil SHA means delete
+			})
+		} else {
+			entries = append(entries, &github.TreeEntry{
+				Path: github.Ptr(change.path),
+				Mode: github.Ptr(change.after.GetMode()),
+				Type: github.Ptr(change.after.GetType()),
+				SHA:  github.Ptr(change.after.GetSHA()),
+			})
+		}
+	}
+
+	if len(conflicts) > 0 {
+		for i := len(applied) - 1; i >= 0; i-- {
+			t.set(applied[i].path, applied[i].before)
+		}
+		return nil, conflicts
+	}
+	return entries, nil
+}
@@ -0,0 +1,195 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v79/github"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_RevertCommit(t *testing.T) {
+	tool, _ := RevertCommit(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.False(t, tool.Annotations.ReadOnlyHint)
+
+	// push changed a.txt and added b.txt, after which the branch changed a.txt again on moved
+	trees := map[string]map[string]string{
+		"good-tree":  {"README.md": "r1", "a.txt": "a1"},
+		"push-tree":  {"README.md": "r1", "a.txt": "a2", "b.txt": "b1"},
+		"moved-tree": {"README.md": "r1", "a.txt": "a3", "b.txt": "b1"},
+	}
+	commits := map[string]*github.Commit{
+		"good":  {SHA: github.Ptr("good"), Tree: &github.Tree{SHA: github.Ptr("good-tree")}},
+		"push":  {SHA: github.Ptr("push"), Tree: &github.Tree{SHA: github.Ptr("push-tree")}, Parents: []*github.Commit{{SHA: github.Ptr("good")}}, Message: github.Ptr("Bulk update\n\nPushed with push_files_chunked")},
+		"moved": {SHA: github.Ptr("moved"), Tree: &github.Tree{SHA: github.Ptr("moved-tree")}, Parents: []*github.Commit{{SHA: github.Ptr("push")}}},
+		"merge": {SHA: github.Ptr("merge"), Tree: &github.Tree{SHA: github.Ptr("push-tree")}, Parents: []*github.Commit{{SHA: github.Ptr("good")}, {SHA: github.Ptr("push")}}},
+	}
+	lastPathSegment := func(r *http.Request) string {
+		return r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+	}
+	gitData := func(t *testing.T, headSHA string) []mock.MockBackendOption {
+		return []mock.MockBackendOption{
+			mock.WithRequestMatch(mock.GetReposGitRefByOwnerByRepoByRef, &github.Reference{Ref: github.Ptr("refs/heads/main"), Object: &github.GitObject{SHA: github.Ptr(headSHA)}}),
+			mock.WithRequestMatchHandler(
+				mock.GetReposGitCommitsByOwnerByRepoByCommitSha,
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					mockResponse(t, http.StatusOK, commits[lastPathSegment(r)])(w, r)
+				}),
+			),
+			mock.WithRequestMatchHandler(
+				mock.GetReposGitTreesByOwnerByRepoByTreeSha,
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					sha := lastPathSegment(r)
+					mockResponse(t, http.StatusOK, &github.Tree{SHA: github.Ptr(sha), Entries: blobEntries(trees[sha])})(w, r)
+				}),
+			),
+			mock.WithRequestMatchHandler(
+				mock.PostReposGitTreesByOwnerByRepo,
+				expectRequestBody(t, map[string]any{
+					"base_tree": "push-tree",
+					"tree": []any{
+						map[string]any{"path": "b.txt", "mode": "100644", "type": "blob", "sha": nil},
+						map[string]any{"path": "a.txt", "mode": "100644", "type": "blob", "sha": "a1"},
+					},
+				}).andThen(mockResponse(t, http.StatusCreated, &github.Tree{SHA: github.Ptr("revert-tree")})),
+			),
+			mock.WithRequestMatchHandler(
+				mock.PostReposGitCommitsByOwnerByRepo,
+				expectRequestBody(t, map[string]any{
+					"message": "Revert \"Bulk update\"\n\nThis reverts commit push.",
+					"tree":    "revert-tree",
+					"parents": []any{"push"},
+				}).andThen(mockResponse(t, http.StatusCreated, &github.Commit{SHA: github.Ptr("revert")})),
+			),
+		}
+	}
+
+	run := func(t *testing.T, client *http.Client, args map[string]any) RevertCommitResult {
+		_, handler := RevertCommit(stubGetClientFn(github.NewClient(client)), translations.NullTranslationHelper)
+		args["owner"], args["repo"], args["branch"] = "owner", "repo", "main"
+		request := createMCPRequest(args)
+		result, _, err := handler(context.Background(), &request, args)
+		require.NoError(t, err)
+		require.False(t, result.IsError, getTextResult(t, result).Text)
+		var response RevertCommitResult
+		require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &response))
+		return response
+	}
+
+	t.Run("reverts on the branch", func(t *testing.T) {
+		options := append(gitData(t, "push"), mock.WithRequestMatchHandler(
+			mock.PatchReposGitRefsByOwnerByRepoByRef,
+			expectRequestBody(t, map[string]any{"sha": "revert", "force": false}).andThen(
+				mockResponse(t, http.StatusOK, &github.Reference{Ref: github.Ptr("refs/heads/main")}),
+			),
+		))
+		response := run(t, mock.NewMockedHTTPClient(options...), map[string]any{"sha": "push"})
+		assert.Equal(t, RevertCommitResult{SHA: "push", Branch: "main", State: RevertStateReverted, CommitSHA: "revert", FilesChanged: 2}, response)
+	})
+
+	t.Run("opens a pull request", func(t *testing.T) {
+		options := append(gitData(t, "push"),
+			mock.WithRequestMatchHandler(
+				mock.PostReposGitRefsByOwnerByRepo,
+				expectRequestBody(t, map[string]any{"ref": "refs/heads/revert-push", "sha": "revert"}).andThen(
+					mockResponse(t, http.StatusCreated, &github.Reference{Ref: github.Ptr("refs/heads/revert-push")}),
+				),
+			),
+			mock.WithRequestMatchHandler(
+				mock.PostReposPullsByOwnerByRepo,
+				expectRequestBody(t, map[string]any{
+					"title": "Revert \"Bulk update\"",
+					"head":  "revert-push",
+					"base":  "main",
+					"body":  "Reverts push, restoring 2 file(s) to their state before it.",
+				}).andThen(mockResponse(t, http.StatusCreated, &github.PullRequest{Number: github.Ptr(7), HTMLURL: github.Ptr("https://github.com/owner/repo/pull/7")})),
+			),
+		)
+		response := run(t, mock.NewMockedHTTPClient(options...), map[string]any{"sha": "push", "create_pull_request": true})
+		assert.Equal(t, &ChunkedPullRequest{Base: "main", Head: "revert-push", Title: "Revert \"Bulk update\"", Number: 7, URL: "https://github.com/owner/repo/pull/7"}, response.PullRequest)
+		assert.Equal(t, "revert", response.CommitSHA)
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		response := run(t, mock.NewMockedHTTPClient(gitData(t, "moved")...), map[string]any{"sha": "push"})
+		assert.Equal(t, RevertStateConflict, response.State)
+		assert.Equal(t, []RevertConflict{{Path: "a.txt", Reason: "modified on the target branch"}}, response.Conflicts)
+		assert.Empty(t, response.CommitSHA)
+	})
+
+	t.Run("already reverted", func(t *testing.T) {
+		response := run(t, mock.NewMockedHTTPClient(gitData(t, "good")...), map[string]any{"sha": "push"})
+		assert.Equal(t, RevertStateEmpty, response.State)
+	})
+
+	t.Run("merge commit without mainline", func(t *testing.T) {
+		_, handler := RevertCommit(stubGetClientFn(github.NewClient(mock.NewMockedHTTPClient(gitData(t, "push")...))), translations.NullTranslationHelper)
+		args := map[string]any{"owner": "owner", "repo": "repo", "branch": "main", "sha": "merge"}
+		request := createMCPRequest(args)
+		result, _, err := handler(context.Background(), &request, args)
+		require.NoError(t, err)
+		require.True(t, result.IsError)
+		assert.Contains(t, getErrorResult(t, result).Text, "commit merge is a merge commit; set mainline")
+	})
+}
+
+// blobEntries returns tree entries of regular files, mapping each path to its blob SHA.
+func blobEntries(blobs map[string]string) []*github.TreeEntry {
+	var entries []*github.TreeEntry
+	for path, sha := range blobs {
+		entries = append(entries, &github.TreeEntry{Path: github.Ptr(path), Mode: github.Ptr("100644"), Type: github.Ptr("blob"), SHA: github.Ptr(sha)})
+	}
+	return entries
+}
+
+func Test_revertTree_apply(t *testing.T) {
+	files := func(blobs map[string]string) map[string]*github.TreeEntry {
+		result := make(map[string]*github.TreeEntry)
+		for _, entry := range blobEntries(blobs) {
+			result[entry.GetPath()] = entry
+		}
+		return result
+	}
+
+	target := newRevertTree(files(map[string]string{"README.md": "r1", "lib": "lib1", "src/a.go": "a1"}))
+
+	// A directory replaced by a file applies, since its files are deleted first
+	entries, conflicts := target.apply(treeChanges(
+		files(map[string]string{"src/a.go": "a1"}),
+		files(map[string]string{"src": "src-file"}),
+	))
+	assert.Empty(t, conflicts)
+	require.Len(t, entries, 2)
+	assert.Nil(t, entries[0].SHA)
+	assert.Equal(t, "src/a.go", entries[0].GetPath())
+	assert.Equal(t, "src", entries[1].GetPath())
+
+	// A conflicting change leaves the tree as it was
+	entries, conflicts = target.apply(treeChanges(
+		files(map[string]string{"README.md": "r0", "gone.md": "g1"}),
+		files(map[string]string{"README.md": "r2", "gone.md": "g2", "lib/x.go": "x2"}),
+	))
+	assert.Nil(t, entries)
+	assert.Equal(t, []RevertConflict{
+		{Path: "README.md", Reason: "modified on the target branch"},
+		{Path: "gone.md", Reason: "deleted on the target branch"},
+		{Path: "lib/x.go", Reason: "lib is a file on the target branch"},
+	}, conflicts)
+	assert.Equal(t, "r1", target.files["README.md"].GetSHA())
+
+	// Changes already on the target are left out
+	entries, conflicts = target.apply(treeChanges(
+		files(map[string]string{"README.md": "r0"}),
+		files(map[string]string{"README.md": "r1"}),
+	))
+	assert.Empty(t, conflicts)
+	assert.Empty(t, entries)
+}
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sort"
+	"strings"
 
 	ghErrors "github.com/github/github-mcp-server/pkg/errors"
 	"github.com/github/github-mcp-server/pkg/translations"
@@ -160,6 +162,84 @@ func SearchRepositories(getClient GetClientFn, t translations.TranslationHelperF
 		}
 }
 
+const (
+	defaultSnippetContextLines = 1
+	// maxCodeSearchResults is the number of results GitHub returns for a search at most
+	maxCodeSearchResults = 1000
+)
+
+// codeSnippet returns the lines of fragment around its matches, with the matched text wrapped in
+// « and ». Lines more than contextLines away from a match are left out, gaps are marked with "…".
+func codeSnippet(fragment string, matches []*github.Match, contextLines int) string {
+	type span struct{ start, end int }
+	runes := []rune(fragment)
+	var spans []span
+	for _, match := range matches {
+		if len(match.Indices) != 2 {
+			continue
+		}
+		start, end := match.Indices[0], match.Indices[1]
+		if start < 0 || end > len(runes) || start >= end {
+			continue
+		}
+		spans = append(spans, span{start, end})
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+	// Overlapping matches are highlighted as one
+	merged := spans[:0]
+	for _, s := range spans {
+		if n := len(merged); n > 0 && s.start <= merged[n-1].end {
+			merged[n-1].end = max(merged[n-1].end, s.end)
+			continue
+		}
+		merged = append(merged, s)
+	}
+	spans = merged
+
+	var b strings.Builder
+	matchLines := map[int]bool{}
+	line, next := 0, 0
+	for i, r := range runes {
+		if next < len(spans) && spans[next].start == i {
+			b.WriteRune('«')
+			matchLines[line] = true
+		}
+		if r == '\n' {
+			line++
+		}
+		b.WriteRune(r)
+		if next < len(spans) && spans[next].end == i+1 {
+			b.WriteRune('»')
+			next++
+		}
+	}
+
+	lines := strings.Split(b.String(), "\n")
+	if len(matchLines) == 0 {
+		return strings.Join(lines[:min(len(lines), 2*contextLines+1)], "\n")
+	}
+	var kept []string
+	last := -1
+	for i, text := range lines {
+		near := false
+		for m := i - contextLines; m <= i+contextLines; m++ {
+			if matchLines[m] {
+				near = true
+				break
+			}
+		}
+		if !near {
+			continue
+		}
+		if last >= 0 && i > last+1 {
+			kept = append(kept, "…")
+		}
+		kept = append(kept, text)
+		last = i
+	}
+	return strings.Join(kept, "\n")
+}
+
 // SearchCode creates a tool to search for code across GitHub repositories.
 func SearchCode(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
 	schema := &jsonschema.Schema{
@@ -178,6 +258,22 @@ func SearchCode(getClient GetClientFn, t translations.TranslationHelperFunc) (mc
 				Description: "Sort order for results",
 				Enum:        []any{"asc", "desc"},
 			},
+			"max_results": {
+				Type:        "number",
+				Description: "Maximum number of results to return, reading as many pages of perPage results as needed starting at page. Defaults to perPage",
+				Minimum:     jsonschema.Ptr(1.0),
+				Maximum:     jsonschema.Ptr(float64(maxCodeSearchResults)),
+			},
+			"snippet_context_lines": {
+				Type:        "number",
+				Description: fmt.Sprintf("Lines of context to keep around each match in snippets (default: %d)", defaultSnippetContextLines),
+				Minimum:     jsonschema.Ptr(0.0),
+			},
+			"minimal_output": {
+				Type:        "boolean",
+				Description: "Return the repository, path and snippets of matching files, in best match order, with matched text wrapped in « and » (default: true). When false, returns full GitHub API code search results.",
+				Default:     json.RawMessage(`true`),
+			},
 		},
 		Required: []string{"query"},
 	}
@@ -209,10 +305,32 @@ func SearchCode(getClient GetClientFn, t translations.TranslationHelperFunc) (mc
 			if err != nil {
 				return utils.NewToolResultError(err.Error()), nil, nil
 			}
+			maxResults, err := OptionalIntParamWithDefault(args, "max_results", pagination.PerPage)
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			if maxResults < 1 || maxResults > maxCodeSearchResults {
+				return utils.NewToolResultError(fmt.Sprintf("max_results must be between 1 and %d", maxCodeSearchResults)), nil, nil
+			}
+			// Zero is a valid number of context lines, so the default only applies when it is missing
+			contextLines := defaultSnippetContextLines
+			if _, ok := args["snippet_context_lines"]; ok {
+				if contextLines, err = OptionalIntParam(args, "snippet_context_lines"); err != nil {
+					return utils.NewToolResultError(err.Error()), nil, nil
+				}
+			}
+			if contextLines < 0 {
+				return utils.NewToolResultError("snippet_context_lines must not be negative"), nil, nil
+			}
+			minimalOutput, err := OptionalBoolParamWithDefault(args, "minimal_output", true)
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
 
 			opts := &github.SearchOptions{
-				Sort:  sort,
-				Order: order,
+				Sort:      sort,
+				Order:     order,
+				TextMatch: minimalOutput,
 				ListOptions: github.ListOptions{
 					PerPage: pagination.PerPage,
 					Page:    pagination.Page,
@@ -224,30 +342,60 @@ func SearchCode(getClient GetClientFn, t translations.TranslationHelperFunc) (mc
 				return utils.NewToolResultErrorFromErr("failed to get GitHub client", err), nil, nil
 			}
 
-			result, resp, err := client.Search.Code(ctx, query, opts)
-			if err != nil {
-				return ghErrors.NewGitHubAPIErrorResponse(ctx,
-					fmt.Sprintf("failed to search code with query '%s'", query),
-					resp,
-					err,
-				), nil, nil
-			}
-			defer func() { _ = resp.Body.Close() }()
-
-			if resp.StatusCode != http.StatusOK {
-				body, err := io.ReadAll(resp.Body)
+			var result *github.CodeSearchResult
+			for {
+				page, resp, err := client.Search.Code(ctx, query, opts)
 				if err != nil {
-					return utils.NewToolResultErrorFromErr("failed to read response body", err), nil, nil
+					return ghErrors.NewGitHubAPIErrorResponse(ctx,
+						fmt.Sprintf("failed to search code with query '%s'", query),
+						resp,
+						err,
+					), nil, nil
+				}
+				_ = resp.Body.Close()
+
+				if result == nil {
+					result = page
+				} else {
+					result.CodeResults = append(result.CodeResults, page.CodeResults...)
+					result.IncompleteResults = github.Ptr(result.GetIncompleteResults() || page.GetIncompleteResults())
+				}
+				if len(result.CodeResults) >= maxResults || len(page.CodeResults) < opts.PerPage || resp.NextPage == 0 {
+					break
 				}
-				return utils.NewToolResultError(fmt.Sprintf("failed to search code: %s", string(body))), nil, nil
+				opts.Page = resp.NextPage
+			}
+			if len(result.CodeResults) > maxResults {
+				result.CodeResults = result.CodeResults[:maxResults]
 			}
 
-			r, err := json.Marshal(result)
-			if err != nil {
-				return utils.NewToolResultErrorFromErr("failed to marshal response", err), nil, nil
+			if !minimalOutput {
+				return MarshalledTextResult(result), nil, nil
 			}
 
-			return utils.NewToolResultText(string(r)), nil, nil
+			minimal := MinimalSearchCodeResult{
+				TotalCount:        result.GetTotal(),
+				IncompleteResults: result.GetIncompleteResults(),
+				Items:             make([]MinimalCodeResult, 0, len(result.CodeResults)),
+			}
+			for i, code := range result.CodeResults {
+				item := MinimalCodeResult{
+					Rank:       (pagination.Page-1)*pagination.PerPage + i + 1,
+					Repository: code.GetRepository().GetFullName(),
+					Path:       code.GetPath(),
+					SHA:        code.GetSHA(),
+					HTMLURL:    code.GetHTMLURL(),
+				}
+				for _, match := range code.TextMatches {
+					if match.GetProperty() != "content" || match.GetFragment() == "" {
+						continue
+					}
+					item.Snippets = append(item.Snippets, codeSnippet(match.GetFragment(), match.Matches, contextLines))
+				}
+				minimal.Items = append(minimal.Items, item)
+			}
+
+			return MarshalledTextResult(minimal), nil, nil
 		}
 }
 
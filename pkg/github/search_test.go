@@ -3,7 +3,9 @@ package github
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"net/url"
 	"testing"
 
 	"github.com/github/github-mcp-server/internal/toolsnaps"
@@ -299,11 +301,12 @@ func Test_SearchCode(t *testing.T) {
 				),
 			),
 			requestArgs: map[string]interface{}{
-				"query":   "fmt.Println language:go",
-				"sort":    "indexed",
-				"order":   "desc",
-				"page":    float64(1),
-				"perPage": float64(30),
+				"query":          "fmt.Println language:go",
+				"sort":           "indexed",
+				"order":          "desc",
+				"page":           float64(1),
+				"perPage":        float64(30),
+				"minimal_output": false,
 			},
 			expectError:    false,
 			expectedResult: mockSearchResult,
@@ -323,7 +326,8 @@ func Test_SearchCode(t *testing.T) {
 				),
 			),
 			requestArgs: map[string]interface{}{
-				"query": "fmt.Println language:go",
+				"query":          "fmt.Println language:go",
+				"minimal_output": false,
 			},
 			expectError:    false,
 			expectedResult: mockSearchResult,
@@ -392,6 +396,78 @@ func Test_SearchCode(t *testing.T) {
 	}
 }
 
+func Test_SearchCode_MinimalOutput(t *testing.T) {
+	page := func(start, n int) *github.CodeSearchResult {
+		result := &github.CodeSearchResult{Total: github.Ptr(5), IncompleteResults: github.Ptr(false)}
+		for i := start; i < start+n; i++ {
+			result.CodeResults = append(result.CodeResults, &github.CodeResult{
+				Path:       github.Ptr(fmt.Sprintf("file%d.go", i)),
+				Repository: &github.Repository{FullName: github.Ptr("owner/repo")},
+				TextMatches: []*github.TextMatch{{
+					Property: github.Ptr("content"),
+					Fragment: github.Ptr("package main\n\nfunc main() {\n\tfmt.Println(x)\n}\n"),
+					Matches:  []*github.Match{{Text: github.Ptr("fmt.Println"), Indices: []int{29, 40}}},
+				}},
+			})
+		}
+		return result
+	}
+
+	var queries []url.Values
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.GetSearchCode,
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				queries = append(queries, r.URL.Query())
+				assert.Equal(t, "application/vnd.github.v3.text-match+json", r.Header.Get("Accept"))
+				if r.URL.Query().Get("page") == "1" {
+					w.Header().Set("Link", `<https://api.github.com/search/code?q=x&page=2>; rel="next"`)
+					mockResponse(t, http.StatusOK, page(1, 2))(w, r)
+					return
+				}
+				mockResponse(t, http.StatusOK, page(3, 2))(w, r)
+			}),
+		),
+	)
+	_, handler := SearchCode(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+	args := map[string]any{"query": "fmt.Println", "perPage": float64(2), "max_results": float64(3), "snippet_context_lines": float64(0)}
+	request := createMCPRequest(args)
+	result, _, err := handler(context.Background(), &request, args)
+	require.NoError(t, err)
+	require.False(t, result.IsError, getTextResult(t, result).Text)
+
+	require.Len(t, queries, 2)
+	assert.Equal(t, "2", queries[1].Get("page"))
+
+	var found MinimalSearchCodeResult
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &found))
+	assert.Equal(t, 5, found.TotalCount)
+	require.Len(t, found.Items, 3)
+	assert.Equal(t, MinimalCodeResult{
+		Rank:       3,
+		Repository: "owner/repo",
+		Path:       "file3.go",
+		Snippets:   []string{"\t«fmt.Println»(x)"},
+	}, found.Items[2])
+}
+
+func Test_codeSnippet(t *testing.T) {
+	fragment := "one\ntwo match\nthree\nfour\nfive\nsix match\nseven"
+	matches := []*github.Match{
+		{Indices: []int{8, 13}},
+		{Indices: []int{10, 12}},
+		{Indices: []int{34, 39}},
+		{Indices: []int{38, 100}},
+	}
+
+	assert.Equal(t, "one\ntwo «match»\nthree\n…\nfive\nsix «match»\nseven", codeSnippet(fragment, matches, 1))
+	assert.Equal(t, "two «match»\n…\nsix «match»", codeSnippet(fragment, matches, 0))
+	assert.Equal(t, "one\ntwo match\nthree", codeSnippet(fragment, nil, 1))
+	// Indices count characters, not bytes
+	assert.Equal(t, "héllo «wörld»", codeSnippet("héllo wörld", []*github.Match{{Indices: []int{6, 11}}}, 0))
+}
+
 func Test_SearchUsers(t *testing.T) {
 	// Verify tool definition once
 	mockClient := github.NewClient(nil)
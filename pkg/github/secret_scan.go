@@ -0,0 +1,128 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+)
+
+// maxReportedSecrets caps the findings listed in a SECRETS_DETECTED error
+const maxReportedSecrets = 50
+
+// secretRule matches one kind of credential in file content.
+type secretRule struct {
+	Name    string
+	Pattern *regexp.Regexp
+	// Group is the submatch holding the secret, checked against MinEntropy
+	Group int
+	// MinEntropy is the Shannon entropy in bits per character the secret must reach, so that
+	// placeholders like "changeme" are not reported. Zero accepts any match.
+	MinEntropy float64
+}
+
+// secretRules are the credentials the pre-push scan looks for
+var secretRules = []secretRule{
+	{Name: "aws_access_key_id", Pattern: regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{
+		Name:       "aws_secret_access_key",
+		Pattern:    regexp.MustCompile(`(?i)aws_?secret_?access_?key["']?\s*[:=]\s*["']?([A-Za-z0-9/+=]{40})\b`),
+		Group:      1,
+		MinEntropy: 4.0,
+	},
+	{Name: "github_token", Pattern: regexp.MustCompile(`\b(?:ghp|gho|ghu|ghs|ghr)_[A-Za-z0-9]{36}\b`)},
+	{Name: "github_fine_grained_token", Pattern: regexp.MustCompile(`\bgithub_pat_[A-Za-z0-9_]{82}\b`)},
+	{Name: "private_key", Pattern: regexp.MustCompile(`-----BEGIN (?:RSA |DSA |EC |OPENSSH |PGP |ENCRYPTED )?PRIVATE KEY(?: BLOCK)?-----`)},
+	{
+		Name:       "generic_secret",
+		Pattern:    regexp.MustCompile(`(?i)(?:secret|token|passwd|password|api_?key)["']?\s*[:=]\s*["']([A-Za-z0-9+/_\-]{20,})["']`),
+		Group:      1,
+		MinEntropy: 4.0,
+	},
+}
+
+// SecretFinding locates a credential found by the pre-push scan. The credential itself is not reported.
+type SecretFinding struct {
+	Path string `json:"path"`
+	Line int    `json:"line"`
+	Rule string `json:"rule"`
+}
+
+// allowSecretsSchema is the parameter of the push tools that skips the secret scan
+func allowSecretsSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:        "boolean",
+		Description: "Push even if the files appear to contain credentials such as AWS keys, GitHub tokens or private keys. Only set this for reported false positives (default: false)",
+		Default:     json.RawMessage("false"),
+	}
+}
+
+// ScanSecrets returns the credentials found in the text files, one finding per rule and line.
+// Binary files are not scanned.
+func ScanSecrets(files []FileEntry) []SecretFinding {
+	var findings []SecretFinding
+	for _, file := range files {
+		if file.Binary {
+			continue
+		}
+		for i, line := range strings.Split(file.Content, "\n") {
+			for _, rule := range secretRules {
+				if rule.matches(line) {
+					findings = append(findings, SecretFinding{Path: file.Path, Line: i + 1, Rule: rule.Name})
+				}
+			}
+		}
+	}
+	return findings
+}
+
+// matches reports whether line holds a credential matching the rule.
+func (r secretRule) matches(line string) bool {
+	for _, match := range r.Pattern.FindAllStringSubmatch(line, -1) {
+		if r.MinEntropy == 0 || shannonEntropy(match[r.Group]) >= r.MinEntropy {
+			return true
+		}
+	}
+	return false
+}
+
+// shannonEntropy returns the Shannon entropy of s in bits per character.
+func shannonEntropy(s string) float64 {
+	if s == "" {
+		return 0
+	}
+	counts := make(map[rune]int)
+	total := 0
+	for _, r := range s {
+		counts[r]++
+		total++
+	}
+	var entropy float64
+	for _, count := range counts {
+		p := float64(count) / float64(total)
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+// ValidateNoSecrets returns a SECRETS_DETECTED error listing the credentials found in files, if any.
+func ValidateNoSecrets(files []FileEntry) error {
+	findings := ScanSecrets(files)
+	if len(findings) == 0 {
+		return nil
+	}
+	first := findings[0]
+	details := map[string]interface{}{
+		"finding_count": len(findings),
+		"findings":      findings[:min(len(findings), maxReportedSecrets)],
+	}
+	return &ValidationError{
+		Code:       "SECRETS_DETECTED",
+		Message:    fmt.Sprintf("found %d possible credential(s), the first a %s in '%s' at line %d", len(findings), first.Rule, first.Path, first.Line),
+		Suggestion: "Remove the credentials and load them from the environment or a secret store instead. If they are false positives, push again with allow_secrets set",
+		Details:    details,
+	}
+}
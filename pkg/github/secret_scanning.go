@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sort"
 
 	ghErrors "github.com/github/github-mcp-server/pkg/errors"
 	"github.com/github/github-mcp-server/pkg/translations"
@@ -15,6 +16,60 @@ import (
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
+// maxSecretScanningSummaryAlerts caps how many alerts the summary of list_secret_scanning_alerts reads.
+const maxSecretScanningSummaryAlerts = 1000
+
+// SecretScanningSummary aggregates secret scanning alerts across the repositories of an owner.
+type SecretScanningSummary struct {
+	Total        int                             `json:"total"`
+	Truncated    bool                            `json:"truncated,omitempty"`
+	ByState      map[string]int                  `json:"by_state"`
+	BySecretType map[string]int                  `json:"by_secret_type"`
+	Repositories []SecretScanningRepositoryCount `json:"repositories"`
+}
+
+// SecretScanningRepositoryCount counts the secret scanning alerts of one repository.
+type SecretScanningRepositoryCount struct {
+	Repository   string         `json:"repository"`
+	Total        int            `json:"total"`
+	Open         int            `json:"open"`
+	BySecretType map[string]int `json:"by_secret_type"`
+}
+
+// summarizeSecretScanningAlerts counts alerts per repository, the one with most open alerts first.
+func summarizeSecretScanningAlerts(alerts []*github.SecretScanningAlert, truncated bool) SecretScanningSummary {
+	summary := SecretScanningSummary{
+		Truncated:    truncated,
+		ByState:      map[string]int{},
+		BySecretType: map[string]int{},
+		Repositories: []SecretScanningRepositoryCount{},
+	}
+	index := map[string]int{}
+	for _, alert := range alerts {
+		name := alert.GetRepository().GetFullName()
+		i, ok := index[name]
+		if !ok {
+			i = len(summary.Repositories)
+			index[name] = i
+			summary.Repositories = append(summary.Repositories, SecretScanningRepositoryCount{Repository: name, BySecretType: map[string]int{}})
+		}
+		repo := &summary.Repositories[i]
+
+		summary.Total++
+		summary.ByState[alert.GetState()]++
+		summary.BySecretType[alert.GetSecretType()]++
+		repo.Total++
+		repo.BySecretType[alert.GetSecretType()]++
+		if alert.GetState() == "open" {
+			repo.Open++
+		}
+	}
+	sort.SliceStable(summary.Repositories, func(i, j int) bool {
+		return summary.Repositories[i].Open > summary.Repositories[j].Open
+	})
+	return summary
+}
+
 func GetSecretScanningAlert(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
 	return mcp.Tool{
 			Name:        "get_secret_scanning_alert",
@@ -91,7 +146,7 @@ func GetSecretScanningAlert(getClient GetClientFn, t translations.TranslationHel
 func ListSecretScanningAlerts(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
 	return mcp.Tool{
 			Name:        "list_secret_scanning_alerts",
-			Description: t("TOOL_LIST_SECRET_SCANNING_ALERTS_DESCRIPTION", "List secret scanning alerts in a GitHub repository."),
+			Description: t("TOOL_LIST_SECRET_SCANNING_ALERTS_DESCRIPTION", "List secret scanning alerts in a GitHub repository, or across the repositories of an organization."),
 			Annotations: &mcp.ToolAnnotations{
 				Title:        t("TOOL_LIST_SECRET_SCANNING_ALERTS_USER_TITLE", "List secret scanning alerts"),
 				ReadOnlyHint: true,
@@ -105,7 +160,7 @@ func ListSecretScanningAlerts(getClient GetClientFn, t translations.TranslationH
 					},
 					"repo": {
 						Type:        "string",
-						Description: "The name of the repository.",
+						Description: "The name of the repository. If omitted, the alerts of all repositories of the organization owner are listed.",
 					},
 					"state": {
 						Type:        "string",
@@ -121,8 +176,12 @@ func ListSecretScanningAlerts(getClient GetClientFn, t translations.TranslationH
 						Description: "Filter by resolution",
 						Enum:        []any{"false_positive", "wont_fix", "revoked", "pattern_edited", "pattern_deleted", "used_in_tests"},
 					},
+					"summary": {
+						Type:        "boolean",
+						Description: fmt.Sprintf("Instead of the alerts, return counts per repository, state and secret type. Reads up to %d alerts. Useful to find the repositories to remediate first across an organization.", maxSecretScanningSummaryAlerts),
+					},
 				},
-				Required: []string{"owner", "repo"},
+				Required: []string{"owner"},
 			},
 		},
 		func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
@@ -130,7 +189,7 @@ func ListSecretScanningAlerts(getClient GetClientFn, t translations.TranslationH
 			if err != nil {
 				return utils.NewToolResultError(err.Error()), nil, nil
 			}
-			repo, err := RequiredParam[string](args, "repo")
+			repo, err := OptionalParam[string](args, "repo")
 			if err != nil {
 				return utils.NewToolResultError(err.Error()), nil, nil
 			}
@@ -146,15 +205,58 @@ func ListSecretScanningAlerts(getClient GetClientFn, t translations.TranslationH
 			if err != nil {
 				return utils.NewToolResultError(err.Error()), nil, nil
 			}
+			summary, err := OptionalParam[bool](args, "summary")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
 
 			client, err := getClient(ctx)
 			if err != nil {
 				return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
 			}
-			alerts, resp, err := client.SecretScanning.ListAlertsForRepo(ctx, owner, repo, &github.SecretScanningAlertListOptions{State: state, SecretType: secretType, Resolution: resolution})
+
+			opts := &github.SecretScanningAlertListOptions{State: state, SecretType: secretType, Resolution: resolution}
+			listPage := func() ([]*github.SecretScanningAlert, *github.Response, error) {
+				if repo == "" {
+					return client.SecretScanning.ListAlertsForOrg(ctx, owner, opts)
+				}
+				return client.SecretScanning.ListAlertsForRepo(ctx, owner, repo, opts)
+			}
+			failedMessage := fmt.Sprintf("failed to list alerts for repository '%s/%s'", owner, repo)
+			if repo == "" {
+				failedMessage = fmt.Sprintf("failed to list alerts for organization '%s'", owner)
+			}
+
+			if summary {
+				opts.ListOptions.PerPage = 100
+				var all []*github.SecretScanningAlert
+				for {
+					page, resp, err := listPage()
+					if err != nil {
+						return ghErrors.NewGitHubAPIErrorResponse(ctx, failedMessage, resp, err), nil, nil
+					}
+					_ = resp.Body.Close()
+					all = append(all, page...)
+
+					// The endpoints page with cursors or page numbers depending on the parameters
+					switch {
+					case resp.After != "":
+						opts.After = resp.After
+					case resp.NextPage != 0:
+						opts.ListOptions.Page = resp.NextPage
+					default:
+						return MarshalledTextResult(summarizeSecretScanningAlerts(all, false)), nil, nil
+					}
+					if len(all) >= maxSecretScanningSummaryAlerts {
+						return MarshalledTextResult(summarizeSecretScanningAlerts(all, true)), nil, nil
+					}
+				}
+			}
+
+			alerts, resp, err := listPage()
 			if err != nil {
 				return ghErrors.NewGitHubAPIErrorResponse(ctx,
-					fmt.Sprintf("failed to list alerts for repository '%s/%s'", owner, repo),
+					failedMessage,
 					resp,
 					err,
 				), nil, nil
@@ -177,3 +279,107 @@ func ListSecretScanningAlerts(getClient GetClientFn, t translations.TranslationH
 			return utils.NewToolResultText(string(r)), nil, nil
 		}
 }
+
+// ResolveSecretScanningAlert creates a tool to resolve a secret scanning alert, or reopen a resolved one.
+func ResolveSecretScanningAlert(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	return mcp.Tool{
+			Name:        "resolve_secret_scanning_alert",
+			Description: t("TOOL_RESOLVE_SECRET_SCANNING_ALERT_DESCRIPTION", "Resolve a secret scanning alert with a resolution reason, or reopen a resolved alert. Resolve as revoked only once the secret has been rotated."),
+			Annotations: &mcp.ToolAnnotations{
+				Title:        t("TOOL_RESOLVE_SECRET_SCANNING_ALERT_USER_TITLE", "Resolve secret scanning alert"),
+				ReadOnlyHint: false,
+			},
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"owner": {
+						Type:        "string",
+						Description: "The owner of the repository.",
+					},
+					"repo": {
+						Type:        "string",
+						Description: "The name of the repository.",
+					},
+					"alertNumber": {
+						Type:        "number",
+						Description: "The number of the alert.",
+					},
+					"resolution": {
+						Type:        "string",
+						Description: "Why the alert is resolved. Required unless reopen is true.",
+						Enum:        []any{"false_positive", "wont_fix", "revoked", "used_in_tests"},
+					},
+					"comment": {
+						Type:        "string",
+						Description: "Comment explaining the resolution.",
+					},
+					"reopen": {
+						Type:        "boolean",
+						Description: "Reopen the alert instead of resolving it.",
+					},
+				},
+				Required: []string{"owner", "repo", "alertNumber"},
+			},
+		},
+		func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+			owner, err := RequiredParam[string](args, "owner")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			repo, err := RequiredParam[string](args, "repo")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			alertNumber, err := RequiredInt(args, "alertNumber")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			resolution, err := OptionalParam[string](args, "resolution")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			comment, err := OptionalParam[string](args, "comment")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			reopen, err := OptionalParam[bool](args, "reopen")
+			if err != nil {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+
+			update := &github.SecretScanningAlertUpdateOptions{State: "open"}
+			switch {
+			case reopen && (resolution != "" || comment != ""):
+				return utils.NewToolResultError("resolution and comment cannot be set when reopening an alert"), nil, nil
+			case !reopen && resolution == "":
+				return utils.NewToolResultError("missing required parameter: resolution"), nil, nil
+			case !reopen:
+				update = &github.SecretScanningAlertUpdateOptions{State: "resolved", Resolution: github.Ptr(resolution)}
+				if comment != "" {
+					update.ResolutionComment = github.Ptr(comment)
+				}
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+
+			alert, resp, err := client.SecretScanning.UpdateAlert(ctx, owner, repo, int64(alertNumber), update)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					fmt.Sprintf("failed to update alert with number '%d'", alertNumber),
+					resp,
+					err,
+				), nil, nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			r, err := json.Marshal(alert)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to marshal alert: %w", err)
+			}
+
+			return utils.NewToolResultText(string(r)), nil, nil
+		}
+}
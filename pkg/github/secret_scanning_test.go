@@ -141,7 +141,8 @@ func Test_ListSecretScanningAlerts(t *testing.T) {
 	assert.Contains(t, schema.Properties, "state")
 	assert.Contains(t, schema.Properties, "secret_type")
 	assert.Contains(t, schema.Properties, "resolution")
-	assert.ElementsMatch(t, schema.Required, []string{"owner", "repo"})
+	assert.Contains(t, schema.Properties, "summary")
+	assert.ElementsMatch(t, schema.Required, []string{"owner"})
 
 	// Setup mock alerts for success case
 	resolvedAlert := github.SecretScanningAlert{
@@ -261,3 +262,124 @@ func Test_ListSecretScanningAlerts(t *testing.T) {
 		})
 	}
 }
+
+func Test_ListSecretScanningAlerts_Organization(t *testing.T) {
+	alert := func(number int, repo, secretType, state string) *github.SecretScanningAlert {
+		return &github.SecretScanningAlert{
+			Number:     github.Ptr(number),
+			State:      github.Ptr(state),
+			SecretType: github.Ptr(secretType),
+			Repository: &github.Repository{FullName: github.Ptr(repo)},
+		}
+	}
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.GetOrgsSecretScanningAlertsByOrg,
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/orgs/octo-org/secret-scanning/alerts", r.URL.Path)
+				page := []*github.SecretScanningAlert{
+					alert(1, "octo-org/api", "github_personal_access_token", "open"),
+					alert(2, "octo-org/web", "aws_access_key_id", "resolved"),
+				}
+				if r.URL.Query().Get("after") == "" {
+					w.Header().Set("Link", `<https://api.github.com/orgs/octo-org/secret-scanning/alerts?after=cursor1>; rel="next"`)
+				} else {
+					assert.Equal(t, "cursor1", r.URL.Query().Get("after"))
+					page = []*github.SecretScanningAlert{alert(3, "octo-org/api", "github_personal_access_token", "open")}
+				}
+				w.WriteHeader(http.StatusOK)
+				_ = json.NewEncoder(w).Encode(page)
+			}),
+		),
+	)
+	_, handler := ListSecretScanningAlerts(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+	t.Run("lists a page of alerts", func(t *testing.T) {
+		args := map[string]any{"owner": "octo-org"}
+		request := createMCPRequest(args)
+		result, _, err := handler(context.Background(), &request, args)
+		require.NoError(t, err)
+		require.False(t, result.IsError, getTextResult(t, result).Text)
+
+		var returned []*github.SecretScanningAlert
+		require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &returned))
+		assert.Len(t, returned, 2)
+	})
+
+	t.Run("summary reads every page", func(t *testing.T) {
+		args := map[string]any{"owner": "octo-org", "summary": true}
+		request := createMCPRequest(args)
+		result, _, err := handler(context.Background(), &request, args)
+		require.NoError(t, err)
+		require.False(t, result.IsError, getTextResult(t, result).Text)
+
+		var summary SecretScanningSummary
+		require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &summary))
+		assert.Equal(t, SecretScanningSummary{
+			Total:        3,
+			ByState:      map[string]int{"open": 2, "resolved": 1},
+			BySecretType: map[string]int{"github_personal_access_token": 2, "aws_access_key_id": 1},
+			Repositories: []SecretScanningRepositoryCount{
+				{Repository: "octo-org/api", Total: 2, Open: 2, BySecretType: map[string]int{"github_personal_access_token": 2}},
+				{Repository: "octo-org/web", Total: 1, Open: 0, BySecretType: map[string]int{"aws_access_key_id": 1}},
+			},
+		}, summary)
+	})
+}
+
+func Test_ResolveSecretScanningAlert(t *testing.T) {
+	tool, _ := ResolveSecretScanningAlert(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	tests := []struct {
+		name           string
+		requestArgs    map[string]any
+		expectedBody   map[string]any
+		expectedErrMsg string
+	}{
+		{
+			name:         "resolve as revoked",
+			requestArgs:  map[string]any{"resolution": "revoked", "comment": "Rotated the token"},
+			expectedBody: map[string]any{"state": "resolved", "resolution": "revoked", "resolution_comment": "Rotated the token"},
+		},
+		{
+			name:         "reopen",
+			requestArgs:  map[string]any{"reopen": true},
+			expectedBody: map[string]any{"state": "open"},
+		},
+		{
+			name:           "resolve without resolution",
+			requestArgs:    map[string]any{},
+			expectedErrMsg: "missing required parameter: resolution",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockedClient := mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.PatchReposSecretScanningAlertsByOwnerByRepoByAlertNumber,
+					expectRequestBody(t, tc.expectedBody).andThen(
+						mockResponse(t, http.StatusOK, &github.SecretScanningAlert{Number: github.Ptr(5), State: github.Ptr("resolved")}),
+					),
+				),
+			)
+			_, handler := ResolveSecretScanningAlert(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+			args := map[string]any{"owner": "owner", "repo": "repo", "alertNumber": float64(5)}
+			for k, v := range tc.requestArgs {
+				args[k] = v
+			}
+			request := createMCPRequest(args)
+			result, _, err := handler(context.Background(), &request, args)
+			require.NoError(t, err)
+
+			if tc.expectedErrMsg != "" {
+				require.True(t, result.IsError)
+				assert.Contains(t, getErrorResult(t, result).Text, tc.expectedErrMsg)
+				return
+			}
+			require.False(t, result.IsError, getTextResult(t, result).Text)
+		})
+	}
+}
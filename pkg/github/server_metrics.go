@@ -0,0 +1,38 @@
+package github
+
+import (
+	"context"
+
+	"github.com/github/github-mcp-server/pkg/metrics"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/github/github-mcp-server/pkg/utils"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// GetServerMetrics creates a tool to report the activity of this server: tool calls, GitHub API
+// requests and rate limiter waits.
+func GetServerMetrics(t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "get_server_metrics",
+		Description: t("TOOL_GET_SERVER_METRICS_DESCRIPTION", "Get the metrics of this MCP server since it started: calls, errors by error code and latencies of each tool, GitHub API requests by API and response status, and the waits on its client-side rate limiters by priority. The same metrics can be scraped by Prometheus when the server is started with a metrics address."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_GET_SERVER_METRICS_USER_TITLE", "Get server metrics"),
+			ReadOnlyHint: true,
+		},
+		InputSchema: &jsonschema.Schema{
+			Type:       "object",
+			Properties: map[string]*jsonschema.Schema{},
+		},
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, _ map[string]any) (*mcp.CallToolResult, any, error) {
+		m, ok := metrics.FromContext(ctx)
+		if !ok {
+			return utils.NewToolResultError("this server does not record metrics"), nil, nil
+		}
+		return MarshalledTextResult(m.Snapshot()), nil, nil
+	})
+
+	return tool, handler
+}
@@ -0,0 +1,40 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/metrics"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_GetServerMetrics(t *testing.T) {
+	tool, handler := GetServerMetrics(translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.True(t, tool.Annotations.ReadOnlyHint)
+
+	request := createMCPRequest(map[string]any{})
+	result, _, err := handler(context.Background(), &request, map[string]any{})
+	require.NoError(t, err)
+	assert.Equal(t, "this server does not record metrics", getErrorResult(t, result).Text)
+
+	m := metrics.New()
+	m.RateLimiters = RateLimiterStats
+	m.RecordToolCall("get_me", 50*time.Millisecond, "")
+	result, _, err = handler(metrics.ContextWithMetrics(context.Background(), m), &request, map[string]any{})
+	require.NoError(t, err)
+	require.False(t, result.IsError, getTextResult(t, result).Text)
+
+	var snapshot metrics.Snapshot
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &snapshot))
+	require.Len(t, snapshot.Tools, 1)
+	assert.Equal(t, "get_me", snapshot.Tools[0].Name)
+	assert.Equal(t, int64(1), snapshot.Tools[0].Calls)
+	// Every rate limiter of the server is reported at each priority
+	assert.Len(t, snapshot.RateLimitWaits, 3*len(RateLimiterStats()))
+}
@@ -0,0 +1,478 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	ghErrors "github.com/github/github-mcp-server/pkg/errors"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/github/github-mcp-server/pkg/utils"
+	"github.com/google/go-github/v79/github"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// submoduleMode is the tree entry mode of a gitlink, the commit a submodule is checked out at
+const submoduleMode = "160000"
+
+var (
+	submoduleSHAPattern     = regexp.MustCompile(`^(?:[0-9a-f]{40}|[0-9a-f]{64})$`)
+	gitmodulesSectionHeader = regexp.MustCompile(`^\s*\[submodule\s+"(.*)"\]\s*$`)
+	gitmodulesKeyValue      = regexp.MustCompile(`^\s*([A-Za-z][A-Za-z0-9-]*)\s*=\s*(.*?)\s*$`)
+)
+
+// SubmoduleResult reports a submodule written by create_or_update_submodule or update_submodule_pointer
+type SubmoduleResult struct {
+	CommitSHA string `json:"commit_sha,omitempty"`
+	Ref       string `json:"ref"`
+	Path      string `json:"path"`
+	SHA       string `json:"sha"`
+	// PreviousSHA is the commit the submodule pointed to before, empty for a new submodule
+	PreviousSHA string `json:"previous_sha,omitempty"`
+	// GitmodulesUpdated is set when .gitmodules was changed by the commit
+	GitmodulesUpdated bool `json:"gitmodules_updated,omitempty"`
+	// NoChanges is set when nothing was committed, as when the submodule was already at sha
+	NoChanges bool `json:"no_changes,omitempty"`
+}
+
+// submoduleSchema returns the input schema shared by the submodule tools, with properties added
+// to the owner, repo, branch, path, sha and message parameters.
+func submoduleSchema(properties map[string]*jsonschema.Schema, required ...string) *jsonschema.Schema {
+	schema := &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"owner": {
+				Type:        "string",
+				Description: "Repository owner",
+			},
+			"repo": {
+				Type:        "string",
+				Description: "Repository name",
+			},
+			"branch": {
+				Type:        "string",
+				Description: "Branch to commit to",
+			},
+			"path": {
+				Type:        "string",
+				Description: "Path of the submodule in the repository, such as 'vendor/lib'",
+			},
+			"sha": {
+				Type:        "string",
+				Description: "Full commit SHA of the submodule repository to point the submodule at",
+			},
+			"message": {
+				Type:        "string",
+				Description: "Commit message",
+			},
+			"allow_empty": allowEmptySchema(),
+		},
+		Required: append([]string{"owner", "repo", "branch", "path", "sha", "message"}, required...),
+	}
+	for name, property := range properties {
+		schema.Properties[name] = property
+	}
+	return withCommitIdentity(schema)
+}
+
+// submoduleParams holds the parameters shared by the submodule tools.
+type submoduleParams struct {
+	owner, repo, branch, path, sha, message string
+	allowEmpty                              bool
+	identity                                commitIdentity
+}
+
+func submoduleParamsFromArgs(ctx context.Context, args map[string]any) (submoduleParams, error) {
+	var p submoduleParams
+	var err error
+	if p.owner, err = RequiredParam[string](args, "owner"); err != nil {
+		return p, err
+	}
+	if p.repo, err = RequiredParam[string](args, "repo"); err != nil {
+		return p, err
+	}
+	if p.branch, err = RequiredParam[string](args, "branch"); err != nil {
+		return p, err
+	}
+	if p.path, err = RequiredParam[string](args, "path"); err != nil {
+		return p, err
+	}
+	if p.sha, err = RequiredParam[string](args, "sha"); err != nil {
+		return p, err
+	}
+	if p.message, err = RequiredParam[string](args, "message"); err != nil {
+		return p, err
+	}
+	if p.allowEmpty, err = OptionalParam[bool](args, "allow_empty"); err != nil {
+		return p, err
+	}
+	if p.identity, err = commitIdentityFromArgs(ctx, args); err != nil {
+		return p, err
+	}
+
+	p.path = strings.Trim(p.path, "/")
+	if p.path == "" || p.path == ".gitmodules" {
+		return p, fmt.Errorf("path must be a directory of the repository")
+	}
+	p.sha = strings.ToLower(p.sha)
+	if !submoduleSHAPattern.MatchString(p.sha) {
+		return p, fmt.Errorf("sha must be a full commit SHA, got %q", p.sha)
+	}
+	return p, nil
+}
+
+// getSubmoduleSHA returns the commit the submodule at path points to at ref, or an empty string if
+// nothing is at path. It fails if path holds a file or directory.
+func getSubmoduleSHA(ctx context.Context, client *github.Client, owner, repo, path, ref string) (string, *github.Response, error) {
+	file, dir, resp, err := client.Repositories.GetContents(ctx, owner, repo, path, &github.RepositoryContentGetOptions{Ref: ref})
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return "", resp, nil
+		}
+		return "", resp, err
+	}
+	_ = resp.Body.Close()
+	if dir != nil || file.GetType() != "submodule" {
+		return "", resp, fmt.Errorf("%s is not a submodule", path)
+	}
+	return file.GetSHA(), resp, nil
+}
+
+// commitSubmoduleEntries commits entries on top of the head of the branch of p and moves the
+// branch to the new commit. result is filled in with the commit and ref, or marked NoChanges if
+// the tree is unchanged and empty commits are not allowed.
+func commitSubmoduleEntries(ctx context.Context, client *github.Client, p submoduleParams, ref *github.Reference, baseCommit *github.Commit, entries []*github.TreeEntry, result *SubmoduleResult) *mcp.CallToolResult {
+	newTree, resp, err := client.Git.CreateTree(ctx, p.owner, p.repo, baseCommit.GetTree().GetSHA(), entries)
+	if err != nil {
+		return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to create tree", resp, err)
+	}
+	_ = resp.Body.Close()
+
+	if !p.allowEmpty && newTree.GetSHA() == baseCommit.GetTree().GetSHA() {
+		result.NoChanges = true
+		return nil
+	}
+
+	commit := github.Commit{
+		Message: github.Ptr(p.message),
+		Tree:    &github.Tree{SHA: newTree.SHA},
+		Parents: []*github.Commit{{SHA: baseCommit.SHA}},
+	}
+	p.identity.apply(&commit)
+	newCommit, resp, err := client.Git.CreateCommit(ctx, p.owner, p.repo, commit, signedCommitOptions(ctx, &commit))
+	if err != nil {
+		return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to create commit", resp, err)
+	}
+	_ = resp.Body.Close()
+
+	updatedRef, resp, err := client.Git.UpdateRef(ctx, p.owner, p.repo, ref.GetRef(), github.UpdateRef{
+		SHA:   newCommit.GetSHA(),
+		Force: github.Ptr(false),
+	})
+	if err != nil {
+		return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to update reference", resp, err)
+	}
+	_ = resp.Body.Close()
+
+	result.CommitSHA = newCommit.GetSHA()
+	result.Ref = updatedRef.GetRef()
+	return nil
+}
+
+// getSubmoduleBase returns the reference and head commit of the branch of p.
+func getSubmoduleBase(ctx context.Context, client *github.Client, p submoduleParams) (*github.Reference, *github.Commit, *mcp.CallToolResult) {
+	ref, resp, err := client.Git.GetRef(ctx, p.owner, p.repo, "refs/heads/"+p.branch)
+	if isEmptyRepository(resp, err) {
+		return nil, nil, validationErrorResult(emptyRepositoryError(p.owner, p.repo, err))
+	}
+	if err != nil {
+		return nil, nil, ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to get branch reference", resp, err)
+	}
+	_ = resp.Body.Close()
+
+	baseCommit, resp, err := client.Git.GetCommit(ctx, p.owner, p.repo, ref.GetObject().GetSHA())
+	if err != nil {
+		return nil, nil, ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to get base commit", resp, err)
+	}
+	_ = resp.Body.Close()
+	return ref, baseCommit, nil
+}
+
+// CreateOrUpdateSubmodule creates a tool to add a submodule, or change the URL, branch or commit
+// of an existing one, in a single commit.
+func CreateOrUpdateSubmodule(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "create_or_update_submodule",
+		Description: t("TOOL_CREATE_OR_UPDATE_SUBMODULE_DESCRIPTION", "Add a Git submodule to a branch, or update the URL, tracked branch or commit of an existing one, in a single commit. Writes the gitlink entry at path and the submodule's section of .gitmodules."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_CREATE_OR_UPDATE_SUBMODULE_USER_TITLE", "Create or update submodule"),
+			ReadOnlyHint: false,
+		},
+		InputSchema: submoduleSchema(map[string]*jsonschema.Schema{
+			"url": {
+				Type:        "string",
+				Description: "URL of the submodule repository, such as 'https://github.com/owner/lib.git' or a relative URL like '../lib.git'",
+			},
+			"name": {
+				Type:        "string",
+				Description: "Name of the submodule in .gitmodules. Defaults to path for a new submodule",
+			},
+			"submodule_branch": {
+				Type:        "string",
+				Description: "Branch of the submodule repository that 'git submodule update --remote' follows",
+			},
+		}, "url"),
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		p, err := submoduleParamsFromArgs(ctx, args)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		url, err := RequiredParam[string](args, "url")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		name, err := OptionalParam[string](args, "name")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		submoduleBranch, err := OptionalParam[string](args, "submodule_branch")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		ref, baseCommit, errResult := getSubmoduleBase(ctx, client, p)
+		if errResult != nil {
+			return errResult, nil, nil
+		}
+
+		previousSHA, resp, err := getSubmoduleSHA(ctx, client, p.owner, p.repo, p.path, baseCommit.GetSHA())
+		if err != nil {
+			if resp != nil && resp.StatusCode == http.StatusOK {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, fmt.Sprintf("failed to get %s", p.path), resp, err), nil, nil
+		}
+
+		var gitmodules string
+		file, _, resp, err := client.Repositories.GetContents(ctx, p.owner, p.repo, ".gitmodules", &github.RepositoryContentGetOptions{Ref: baseCommit.GetSHA()})
+		switch {
+		case err == nil:
+			_ = resp.Body.Close()
+			if gitmodules, err = file.GetContent(); err != nil {
+				return nil, nil, fmt.Errorf("failed to decode .gitmodules: %w", err)
+			}
+		case resp == nil || resp.StatusCode != http.StatusNotFound:
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to get .gitmodules", resp, err), nil, nil
+		}
+
+		updated, err := setGitmodulesEntry(gitmodules, name, p.path, url, submoduleBranch)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		entries := []*github.TreeEntry{{
+			Path: github.Ptr(p.path),
+			Mode: github.Ptr(submoduleMode),
+			Type: github.Ptr("commit"),
+			SHA:  github.Ptr(p.sha),
+		}}
+		if updated != gitmodules {
+			entries = append(entries, &github.TreeEntry{
+				Path:    github.Ptr(".gitmodules"),
+				Mode:    github.Ptr("100644"),
+				Type:    github.Ptr("blob"),
+				Content: github.Ptr(updated),
+			})
+		}
+
+		result := SubmoduleResult{
+			Ref:               ref.GetRef(),
+			Path:              p.path,
+			SHA:               p.sha,
+			PreviousSHA:       previousSHA,
+			GitmodulesUpdated: updated != gitmodules,
+		}
+		if errResult := commitSubmoduleEntries(ctx, client, p, ref, baseCommit, entries, &result); errResult != nil {
+			return errResult, nil, nil
+		}
+		if result.NoChanges {
+			result.GitmodulesUpdated = false
+		}
+		return MarshalledTextResult(result), nil, nil
+	})
+
+	return withIdempotencyKey(tool, handler)
+}
+
+// UpdateSubmodulePointer creates a tool to move an existing submodule to another commit.
+func UpdateSubmodulePointer(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "update_submodule_pointer",
+		Description: t("TOOL_UPDATE_SUBMODULE_POINTER_DESCRIPTION", "Bump an existing Git submodule of a branch to another commit of the submodule repository in a single commit, leaving .gitmodules unchanged. Fails if path is not a submodule."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_UPDATE_SUBMODULE_POINTER_USER_TITLE", "Update submodule pointer"),
+			ReadOnlyHint: false,
+		},
+		InputSchema: submoduleSchema(nil),
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		p, err := submoduleParamsFromArgs(ctx, args)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		ref, baseCommit, errResult := getSubmoduleBase(ctx, client, p)
+		if errResult != nil {
+			return errResult, nil, nil
+		}
+
+		previousSHA, resp, err := getSubmoduleSHA(ctx, client, p.owner, p.repo, p.path, baseCommit.GetSHA())
+		if err != nil {
+			if resp != nil && resp.StatusCode == http.StatusOK {
+				return utils.NewToolResultError(err.Error()), nil, nil
+			}
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, fmt.Sprintf("failed to get %s", p.path), resp, err), nil, nil
+		}
+		if previousSHA == "" {
+			return utils.NewToolResultError(fmt.Sprintf("no submodule at %s on %s; use create_or_update_submodule to add it", p.path, p.branch)), nil, nil
+		}
+
+		entries := []*github.TreeEntry{{
+			Path: github.Ptr(p.path),
+			Mode: github.Ptr(submoduleMode),
+			Type: github.Ptr("commit"),
+			SHA:  github.Ptr(p.sha),
+		}}
+		result := SubmoduleResult{
+			Ref:         ref.GetRef(),
+			Path:        p.path,
+			SHA:         p.sha,
+			PreviousSHA: previousSHA,
+		}
+		if errResult := commitSubmoduleEntries(ctx, client, p, ref, baseCommit, entries, &result); errResult != nil {
+			return errResult, nil, nil
+		}
+		return MarshalledTextResult(result), nil, nil
+	})
+
+	return withIdempotencyKey(tool, handler)
+}
+
+// gitmodulesSection is a [submodule "name"] section of .gitmodules, as the indexes of its header
+// and last line.
+type gitmodulesSection struct {
+	name       string
+	start, end int
+}
+
+// parseGitmodulesSections returns the submodule sections of the lines of .gitmodules.
+func parseGitmodulesSections(lines []string) []gitmodulesSection {
+	var sections []gitmodulesSection
+	inSection := false
+	for i, line := range lines {
+		match := gitmodulesSectionHeader.FindStringSubmatch(line)
+		if match == nil && !strings.HasPrefix(strings.TrimSpace(line), "[") {
+			if inSection {
+				sections[len(sections)-1].end = i
+			}
+			continue
+		}
+		inSection = match != nil
+		if inSection {
+			sections = append(sections, gitmodulesSection{name: match[1], start: i, end: i})
+		}
+	}
+	// Blank lines separating a section from the next one are not part of it
+	for i := range sections {
+		for sections[i].end > sections[i].start && strings.TrimSpace(lines[sections[i].end]) == "" {
+			sections[i].end--
+		}
+	}
+	return sections
+}
+
+// gitmodulesValue returns the value of key in section, and whether it is set.
+func gitmodulesValue(lines []string, section gitmodulesSection, key string) (string, bool) {
+	for _, line := range lines[section.start+1 : section.end+1] {
+		if match := gitmodulesKeyValue.FindStringSubmatch(line); match != nil && match[1] == key {
+			return strings.Trim(match[2], `"`), true
+		}
+	}
+	return "", false
+}
+
+// setGitmodulesEntry returns the .gitmodules content with the url, and branch if not empty, of the
+// submodule at path set. The section of an existing submodule at path is edited in place and keeps
+// its name; otherwise a section named name, or path if name is empty, is appended.
+func setGitmodulesEntry(content, name, path, url, branch string) (string, error) {
+	lines := strings.Split(content, "\n")
+	sections := parseGitmodulesSections(lines)
+
+	var section *gitmodulesSection
+	for i := range sections {
+		if value, _ := gitmodulesValue(lines, sections[i], "path"); value == path {
+			section = &sections[i]
+			break
+		}
+	}
+
+	if section == nil {
+		if name == "" {
+			name = path
+		}
+		for _, other := range sections {
+			if other.name == name {
+				return "", fmt.Errorf("submodule name %s is already used in .gitmodules for another path", name)
+			}
+		}
+		added := fmt.Sprintf("[submodule %q]\n\tpath = %s\n\turl = %s\n", name, path, url)
+		if branch != "" {
+			added += fmt.Sprintf("\tbranch = %s\n", branch)
+		}
+		if content != "" && !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		return content + added, nil
+	}
+
+	values := map[string]string{"url": url}
+	if branch != "" {
+		values["branch"] = branch
+	}
+	for i := section.start + 1; i <= section.end; i++ {
+		match := gitmodulesKeyValue.FindStringSubmatch(lines[i])
+		if match == nil {
+			continue
+		}
+		if value, ok := values[match[1]]; ok {
+			if strings.Trim(match[2], `"`) != value {
+				lines[i] = fmt.Sprintf("\t%s = %s", match[1], value)
+			}
+			delete(values, match[1])
+		}
+	}
+	var added []string
+	for _, key := range []string{"url", "branch"} {
+		if value, ok := values[key]; ok {
+			added = append(added, fmt.Sprintf("\t%s = %s", key, value))
+		}
+	}
+	lines = append(lines[:section.end+1], append(added, lines[section.end+1:]...)...)
+	return strings.Join(lines, "\n"), nil
+}
@@ -0,0 +1,322 @@
+package github
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v79/github"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testSubmoduleSHA    = "0123456789abcdef0123456789abcdef01234567"
+	testOldSubmoduleSHA = "89abcdef0123456789abcdef0123456789abcdef"
+)
+
+func Test_setGitmodulesEntry(t *testing.T) {
+	existing := "[submodule \"lib\"]\n\tpath = vendor/lib\n\turl = https://github.com/owner/lib.git\n\n[submodule \"docs\"]\n\tpath = docs\n\turl = ../docs.git\n"
+
+	tests := []struct {
+		name        string
+		content     string
+		subName     string
+		path        string
+		url         string
+		branch      string
+		expected    string
+		expectError string
+	}{
+		{
+			name:     "new file",
+			path:     "vendor/lib",
+			url:      "https://github.com/owner/lib.git",
+			expected: "[submodule \"vendor/lib\"]\n\tpath = vendor/lib\n\turl = https://github.com/owner/lib.git\n",
+		},
+		{
+			name:     "append section",
+			content:  existing,
+			subName:  "tools",
+			path:     "tools",
+			url:      "../tools.git",
+			branch:   "main",
+			expected: existing + "[submodule \"tools\"]\n\tpath = tools\n\turl = ../tools.git\n\tbranch = main\n",
+		},
+		{
+			name:     "update url and add branch in place",
+			content:  existing,
+			path:     "vendor/lib",
+			url:      "https://github.com/fork/lib.git",
+			branch:   "stable",
+			expected: "[submodule \"lib\"]\n\tpath = vendor/lib\n\turl = https://github.com/fork/lib.git\n\tbranch = stable\n\n[submodule \"docs\"]\n\tpath = docs\n\turl = ../docs.git\n",
+		},
+		{
+			name:     "unchanged",
+			content:  existing,
+			path:     "docs",
+			url:      "../docs.git",
+			expected: existing,
+		},
+		{
+			name:        "name used for another path",
+			content:     existing,
+			subName:     "docs",
+			path:        "site",
+			url:         "../site.git",
+			expectError: "submodule name docs is already used",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			updated, err := setGitmodulesEntry(tc.content, tc.subName, tc.path, tc.url, tc.branch)
+			if tc.expectError != "" {
+				assert.ErrorContains(t, err, tc.expectError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, updated)
+		})
+	}
+}
+
+// mockSubmoduleContents returns a mock option serving the contents API with a submodule at
+// vendor/lib pointing to sha, if not empty, and gitmodules as .gitmodules, if not empty.
+func mockSubmoduleContents(t *testing.T, sha, gitmodules string) mock.MockBackendOption {
+	return mock.WithRequestMatchHandler(
+		mock.GetReposContentsByOwnerByRepoByPath,
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "base-sha", r.URL.Query().Get("ref"))
+			switch {
+			case strings.HasSuffix(r.URL.Path, "/vendor/lib") && sha != "":
+				mockResponse(t, http.StatusOK, &github.RepositoryContent{Type: github.Ptr("submodule"), Path: github.Ptr("vendor/lib"), SHA: github.Ptr(sha)})(w, r)
+			case strings.HasSuffix(r.URL.Path, "/.gitmodules") && gitmodules != "":
+				mockResponse(t, http.StatusOK, &github.RepositoryContent{
+					Type:     github.Ptr("file"),
+					Encoding: github.Ptr("base64"),
+					Content:  github.Ptr(base64.StdEncoding.EncodeToString([]byte(gitmodules))),
+				})(w, r)
+			default:
+				mockResponse(t, http.StatusNotFound, map[string]string{"message": "Not Found"})(w, r)
+			}
+		}),
+	)
+}
+
+func Test_CreateOrUpdateSubmodule(t *testing.T) {
+	tool, _ := CreateOrUpdateSubmodule(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.False(t, tool.Annotations.ReadOnlyHint)
+
+	t.Run("adds a submodule and .gitmodules", func(t *testing.T) {
+		var tree createdTree
+		options := mockGitDataAPI(t)
+		options[2] = mock.WithRequestMatchHandler(
+			mock.PostReposGitTreesByOwnerByRepo,
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&tree))
+				mockResponse(t, http.StatusCreated, &github.Tree{SHA: github.Ptr("new-tree-sha")})(w, r)
+			}),
+		)
+		options = append(options, mockSubmoduleContents(t, "", ""))
+		_, handler := CreateOrUpdateSubmodule(stubGetClientFn(github.NewClient(mock.NewMockedHTTPClient(options...))), translations.NullTranslationHelper)
+
+		args := map[string]any{
+			"owner":   "owner",
+			"repo":    "repo",
+			"branch":  "main",
+			"path":    "vendor/lib/",
+			"url":     "https://github.com/owner/lib.git",
+			"sha":     testSubmoduleSHA,
+			"message": "Add lib submodule",
+		}
+		request := createMCPRequest(args)
+		result, _, err := handler(context.Background(), &request, args)
+		require.NoError(t, err)
+		require.False(t, result.IsError, getTextResult(t, result).Text)
+
+		var response SubmoduleResult
+		require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &response))
+		assert.Equal(t, SubmoduleResult{
+			CommitSHA:         "new-commit-sha",
+			Ref:               "refs/heads/main",
+			Path:              "vendor/lib",
+			SHA:               testSubmoduleSHA,
+			GitmodulesUpdated: true,
+		}, response)
+
+		assert.Equal(t, "base-tree-sha", tree.BaseTree)
+		assert.Equal(t, []map[string]any{
+			{"path": "vendor/lib", "mode": "160000", "type": "commit", "sha": testSubmoduleSHA},
+			{"path": ".gitmodules", "mode": "100644", "type": "blob", "content": "[submodule \"vendor/lib\"]\n\tpath = vendor/lib\n\turl = https://github.com/owner/lib.git\n"},
+		}, tree.Tree)
+	})
+
+	t.Run("updates an existing submodule without touching .gitmodules", func(t *testing.T) {
+		var tree createdTree
+		options := mockGitDataAPI(t)
+		options[2] = mock.WithRequestMatchHandler(
+			mock.PostReposGitTreesByOwnerByRepo,
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&tree))
+				mockResponse(t, http.StatusCreated, &github.Tree{SHA: github.Ptr("new-tree-sha")})(w, r)
+			}),
+		)
+		options = append(options, mockSubmoduleContents(t, testOldSubmoduleSHA, "[submodule \"lib\"]\n\tpath = vendor/lib\n\turl = https://github.com/owner/lib.git\n"))
+		_, handler := CreateOrUpdateSubmodule(stubGetClientFn(github.NewClient(mock.NewMockedHTTPClient(options...))), translations.NullTranslationHelper)
+
+		args := map[string]any{
+			"owner":   "owner",
+			"repo":    "repo",
+			"branch":  "main",
+			"path":    "vendor/lib",
+			"url":     "https://github.com/owner/lib.git",
+			"sha":     testSubmoduleSHA,
+			"message": "Bump lib",
+		}
+		request := createMCPRequest(args)
+		result, _, err := handler(context.Background(), &request, args)
+		require.NoError(t, err)
+		require.False(t, result.IsError, getTextResult(t, result).Text)
+
+		var response SubmoduleResult
+		require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &response))
+		assert.Equal(t, testOldSubmoduleSHA, response.PreviousSHA)
+		assert.False(t, response.GitmodulesUpdated)
+		require.Len(t, tree.Tree, 1)
+		assert.Equal(t, "vendor/lib", tree.Tree[0]["path"])
+	})
+
+	for _, tc := range []struct {
+		name        string
+		args        map[string]any
+		expectError string
+	}{
+		{
+			name:        "short sha",
+			args:        map[string]any{"sha": "0123456"},
+			expectError: "sha must be a full commit SHA",
+		},
+		{
+			name:        "repository root",
+			args:        map[string]any{"path": "/"},
+			expectError: "path must be a directory of the repository",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, handler := CreateOrUpdateSubmodule(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+			args := map[string]any{
+				"owner":   "owner",
+				"repo":    "repo",
+				"branch":  "main",
+				"path":    "vendor/lib",
+				"url":     "https://github.com/owner/lib.git",
+				"sha":     testSubmoduleSHA,
+				"message": "Add lib submodule",
+			}
+			for k, v := range tc.args {
+				args[k] = v
+			}
+			request := createMCPRequest(args)
+			result, _, err := handler(context.Background(), &request, args)
+			require.NoError(t, err)
+			require.True(t, result.IsError)
+			assert.Contains(t, getErrorResult(t, result).Text, tc.expectError)
+		})
+	}
+}
+
+func Test_UpdateSubmodulePointer(t *testing.T) {
+	tool, _ := UpdateSubmodulePointer(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.False(t, tool.Annotations.ReadOnlyHint)
+
+	args := map[string]any{
+		"owner":   "owner",
+		"repo":    "repo",
+		"branch":  "main",
+		"path":    "vendor/lib",
+		"sha":     testSubmoduleSHA,
+		"message": "Bump lib",
+	}
+
+	t.Run("bumps the gitlink", func(t *testing.T) {
+		options := mockGitDataAPI(t)
+		options[2] = mock.WithRequestMatchHandler(
+			mock.PostReposGitTreesByOwnerByRepo,
+			expectRequestBody(t, map[string]any{
+				"base_tree": "base-tree-sha",
+				"tree":      []any{map[string]any{"path": "vendor/lib", "mode": "160000", "type": "commit", "sha": testSubmoduleSHA}},
+			}).andThen(mockResponse(t, http.StatusCreated, &github.Tree{SHA: github.Ptr("new-tree-sha")})),
+		)
+		options = append(options, mockSubmoduleContents(t, testOldSubmoduleSHA, ""))
+		_, handler := UpdateSubmodulePointer(stubGetClientFn(github.NewClient(mock.NewMockedHTTPClient(options...))), translations.NullTranslationHelper)
+
+		request := createMCPRequest(args)
+		result, _, err := handler(context.Background(), &request, args)
+		require.NoError(t, err)
+		require.False(t, result.IsError, getTextResult(t, result).Text)
+
+		var response SubmoduleResult
+		require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &response))
+		assert.Equal(t, SubmoduleResult{
+			CommitSHA:   "new-commit-sha",
+			Ref:         "refs/heads/main",
+			Path:        "vendor/lib",
+			SHA:         testSubmoduleSHA,
+			PreviousSHA: testOldSubmoduleSHA,
+		}, response)
+	})
+
+	t.Run("already at sha", func(t *testing.T) {
+		options := mockGitDataAPI(t)
+		options[2] = mock.WithRequestMatchHandler(
+			mock.PostReposGitTreesByOwnerByRepo,
+			mockResponse(t, http.StatusCreated, &github.Tree{SHA: github.Ptr("base-tree-sha")}),
+		)
+		options = append(options, mockSubmoduleContents(t, testSubmoduleSHA, ""))
+		_, handler := UpdateSubmodulePointer(stubGetClientFn(github.NewClient(mock.NewMockedHTTPClient(options...))), translations.NullTranslationHelper)
+
+		request := createMCPRequest(args)
+		result, _, err := handler(context.Background(), &request, args)
+		require.NoError(t, err)
+		require.False(t, result.IsError, getTextResult(t, result).Text)
+
+		var response SubmoduleResult
+		require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &response))
+		assert.True(t, response.NoChanges)
+		assert.Empty(t, response.CommitSHA)
+	})
+
+	t.Run("no submodule at path", func(t *testing.T) {
+		options := append(mockGitDataAPI(t), mockSubmoduleContents(t, "", ""))
+		_, handler := UpdateSubmodulePointer(stubGetClientFn(github.NewClient(mock.NewMockedHTTPClient(options...))), translations.NullTranslationHelper)
+
+		request := createMCPRequest(args)
+		result, _, err := handler(context.Background(), &request, args)
+		require.NoError(t, err)
+		require.True(t, result.IsError)
+		assert.Contains(t, getErrorResult(t, result).Text, "no submodule at vendor/lib on main")
+	})
+
+	t.Run("path is a file", func(t *testing.T) {
+		options := append(mockGitDataAPI(t), mock.WithRequestMatchHandler(
+			mock.GetReposContentsByOwnerByRepoByPath,
+			mockResponse(t, http.StatusOK, &github.RepositoryContent{Type: github.Ptr("file"), Path: github.Ptr("vendor/lib")}),
+		))
+		_, handler := UpdateSubmodulePointer(stubGetClientFn(github.NewClient(mock.NewMockedHTTPClient(options...))), translations.NullTranslationHelper)
+
+		request := createMCPRequest(args)
+		result, _, err := handler(context.Background(), &request, args)
+		require.NoError(t, err)
+		require.True(t, result.IsError)
+		assert.Contains(t, getErrorResult(t, result).Text, "vendor/lib is not a submodule")
+	})
+}
@@ -102,7 +102,7 @@ var (
 	}
 	ToolsetMetadataStargazers = ToolsetMetadata{
 		ID:          "stargazers",
-		Description: "GitHub Stargazers related tools",
+		Description: "GitHub starring and watching related tools",
 	}
 	ToolsetMetadataDynamic = ToolsetMetadata{
 		ID:          "dynamic",
@@ -112,10 +112,22 @@ var (
 		ID:          "labels",
 		Description: "GitHub Labels related tools",
 	}
+	ToolsetMetadataWebhooks = ToolsetMetadata{
+		ID:          "webhooks",
+		Description: "Repository webhook related tools, including delivery failure analysis",
+	}
 	ToolsetMetadataBulkOps = ToolsetMetadata{
 		ID:          "bulk_operations",
 		Description: "Tools for large-scale repository operations including bulk file uploads, chunked pushes, and batch deletions",
 	}
+	ToolsetMetadataDeployments = ToolsetMetadata{
+		ID:          "deployments",
+		Description: "GitHub Deployments and deployment environments related tools",
+	}
+	ToolsetMetadataPackages = ToolsetMetadata{
+		ID:          "packages",
+		Description: "GitHub Packages related tools, including the GitHub Container Registry",
+	}
 )
 
 func AvailableTools() []ToolsetMetadata {
@@ -140,6 +152,9 @@ func AvailableTools() []ToolsetMetadata {
 		ToolsetMetadataDynamic,
 		ToolsetLabels,
 		ToolsetMetadataBulkOps,
+		ToolsetMetadataWebhooks,
+		ToolsetMetadataDeployments,
+		ToolsetMetadataPackages,
 	}
 }
 
@@ -178,19 +193,44 @@ func DefaultToolsetGroup(readOnly bool, getClient GetClientFn, getGQLClient GetG
 			toolsets.NewServerTool(SearchCode(getClient, t)),
 			toolsets.NewServerTool(GetCommit(getClient, t)),
 			toolsets.NewServerTool(ListBranches(getClient, t)),
+			toolsets.NewServerTool(GetBranchProtection(getClient, t)),
+			toolsets.NewServerTool(ListRepositoryRulesets(getClient, t)),
+			toolsets.NewServerTool(GetRepositoryRuleset(getClient, t)),
 			toolsets.NewServerTool(ListTags(getClient, t)),
 			toolsets.NewServerTool(GetTag(getClient, t)),
 			toolsets.NewServerTool(ListReleases(getClient, t)),
 			toolsets.NewServerTool(GetLatestRelease(getClient, t)),
 			toolsets.NewServerTool(GetReleaseByTag(getClient, t)),
+			toolsets.NewServerTool(CompareAcrossForks(getClient, t)),
+			toolsets.NewServerTool(CompareRefs(getClient, t)),
+			toolsets.NewServerTool(ListDeployKeys(getClient, t)),
+			toolsets.NewServerTool(ExportRepositoryMetadata(getClient, t)),
+			toolsets.NewServerTool(ListForks(getClient, t)),
+			toolsets.NewServerTool(GetRepositoryArchive(getClient, t)),
+			toolsets.NewServerTool(ListInvitations(getClient, t)),
 		).
 		AddWriteTools(
 			toolsets.NewServerTool(CreateOrUpdateFile(getClient, t)),
 			toolsets.NewServerTool(CreateRepository(getClient, t)),
+			toolsets.NewServerTool(InitializeRepository(getClient, t)),
 			toolsets.NewServerTool(ForkRepository(getClient, t)),
+			toolsets.NewServerTool(UpdateRepository(getClient, t)),
+			toolsets.NewServerTool(TransferRepository(getClient, t)),
 			toolsets.NewServerTool(CreateBranch(getClient, t)),
+			toolsets.NewServerTool(UpdateBranchProtection(getClient, t)),
+			toolsets.NewServerTool(SetRepositoryRuleset(getClient, t)),
+			toolsets.NewServerTool(CreateTag(getClient, t)),
+			toolsets.NewServerTool(DeleteTag(getClient, t)),
+			toolsets.NewServerTool(CreateRelease(getClient, t)),
+			toolsets.NewServerTool(UpdateRelease(getClient, t)),
+			toolsets.NewServerTool(UploadReleaseAsset(getClient, t)),
 			toolsets.NewServerTool(PushFiles(getClient, t)),
 			toolsets.NewServerTool(DeleteFile(getClient, t)),
+			toolsets.NewServerTool(CreateDeployKey(getClient, t)),
+			toolsets.NewServerTool(DeleteDeployKey(getClient, t)),
+			toolsets.NewServerTool(AddCollaborator(getClient, t)),
+			toolsets.NewServerTool(RemoveCollaborator(getClient, t)),
+			toolsets.NewServerTool(CancelInvitation(getClient, t)),
 		).
 		AddResourceTemplates(
 			toolsets.NewServerResourceTemplate(GetRepositoryResourceContent(getClient, getRawClient, t)),
@@ -202,6 +242,12 @@ func DefaultToolsetGroup(readOnly bool, getClient GetClientFn, getGQLClient GetG
 	git := toolsets.NewToolset(ToolsetMetadataGit.ID, ToolsetMetadataGit.Description).
 		AddReadTools(
 			toolsets.NewServerTool(GetRepositoryTree(getClient, t)),
+		).
+		AddWriteTools(
+			toolsets.NewServerTool(CreateOrUpdateSubmodule(getClient, t)),
+			toolsets.NewServerTool(UpdateSubmodulePointer(getClient, t)),
+			toolsets.NewServerTool(CherryPickCommits(getClient, t)),
+			toolsets.NewServerTool(RevertCommit(getClient, t)),
 		)
 	issues := toolsets.NewToolset(ToolsetMetadataIssues.ID, ToolsetMetadataIssues.Description).
 		AddReadTools(
@@ -210,12 +256,17 @@ func DefaultToolsetGroup(readOnly bool, getClient GetClientFn, getGQLClient GetG
 			toolsets.NewServerTool(ListIssues(getGQLClient, t)),
 			toolsets.NewServerTool(ListIssueTypes(getClient, t)),
 			toolsets.NewServerTool(GetLabel(getGQLClient, t)),
+			toolsets.NewServerTool(ListMilestones(getClient, t)),
 		).
 		AddWriteTools(
 			toolsets.NewServerTool(IssueWrite(getClient, getGQLClient, t)),
+			toolsets.NewServerTool(CreateIssueTool(getClient, getGQLClient, t)),
+			toolsets.NewServerTool(UpdateIssueTool(getClient, getGQLClient, t)),
 			toolsets.NewServerTool(AddIssueComment(getClient, t)),
 			toolsets.NewServerTool(AssignCopilotToIssue(getGQLClient, t)),
 			toolsets.NewServerTool(SubIssueWrite(getClient, t)),
+			toolsets.NewServerTool(ImportIssues(getClient, t)),
+			toolsets.NewServerTool(MilestoneWrite(getClient, t)),
 		).AddPrompts(
 		toolsets.NewServerPrompt(AssignCodingAgentPrompt(t)),
 		toolsets.NewServerPrompt(IssueToFixWorkflowPrompt(t)),
@@ -227,10 +278,26 @@ func DefaultToolsetGroup(readOnly bool, getClient GetClientFn, getGQLClient GetG
 	orgs := toolsets.NewToolset(ToolsetMetadataOrgs.ID, ToolsetMetadataOrgs.Description).
 		AddReadTools(
 			toolsets.NewServerTool(SearchOrgs(getClient, t)),
+			toolsets.NewServerTool(ListOrgExternalIdentities(getGQLClient, t)),
+			toolsets.NewServerTool(GetOrgExternalIdentity(getGQLClient, t)),
+			toolsets.NewServerTool(DiscoverRepositories(getClient, getGQLClient, t)),
+			toolsets.NewServerTool(GetCopilotMetrics(getClient, t)),
+			toolsets.NewServerTool(GetCopilotSeatUsage(getClient, t)),
+			toolsets.NewServerTool(ListOrgMembers(getClient, t)),
+			toolsets.NewServerTool(ListTeamRepositories(getClient, t)),
+		).
+		AddWriteTools(
+			toolsets.NewServerTool(SetTeamMembership(getClient, t)),
+			toolsets.NewServerTool(RemoveTeamMember(getClient, t)),
+			toolsets.NewServerTool(SetRepositoryPermission(getClient, t)),
+			toolsets.NewServerTool(AddCopilotSeats(getClient, t)),
+			toolsets.NewServerTool(RemoveCopilotSeats(getClient, t)),
 		)
 	pullRequests := toolsets.NewToolset(ToolsetMetadataPullRequests.ID, ToolsetMetadataPullRequests.Description).
 		AddReadTools(
 			toolsets.NewServerTool(PullRequestRead(getClient, cache, t, flags)),
+			toolsets.NewServerTool(GetPullRequestDiffTool(getClient, t)),
+			toolsets.NewServerTool(ListPullRequestReviewThreads(getGQLClient, t)),
 			toolsets.NewServerTool(ListPullRequests(getClient, t)),
 			toolsets.NewServerTool(SearchPullRequests(getClient, t)),
 		).
@@ -240,24 +307,37 @@ func DefaultToolsetGroup(readOnly bool, getClient GetClientFn, getGQLClient GetG
 			toolsets.NewServerTool(CreatePullRequest(getClient, t)),
 			toolsets.NewServerTool(UpdatePullRequest(getClient, getGQLClient, t)),
 			toolsets.NewServerTool(RequestCopilotReview(getClient, t)),
+			toolsets.NewServerTool(BackportPullRequest(getClient, t)),
 			// Reviews
 			toolsets.NewServerTool(PullRequestReviewWrite(getGQLClient, t)),
 			toolsets.NewServerTool(AddCommentToPendingReview(getGQLClient, t)),
+			toolsets.NewServerTool(CreatePullRequestReviewWithComments(getClient, t)),
+			toolsets.NewServerTool(RequestPullRequestReviewers(getClient, t)),
 		)
 	codeSecurity := toolsets.NewToolset(ToolsetMetadataCodeSecurity.ID, ToolsetMetadataCodeSecurity.Description).
 		AddReadTools(
 			toolsets.NewServerTool(GetCodeScanningAlert(getClient, t)),
 			toolsets.NewServerTool(ListCodeScanningAlerts(getClient, t)),
+		).
+		AddWriteTools(
+			toolsets.NewServerTool(DismissCodeScanningAlert(getClient, t)),
 		)
 	secretProtection := toolsets.NewToolset(ToolsetMetadataSecretProtection.ID, ToolsetMetadataSecretProtection.Description).
 		AddReadTools(
 			toolsets.NewServerTool(GetSecretScanningAlert(getClient, t)),
 			toolsets.NewServerTool(ListSecretScanningAlerts(getClient, t)),
+		).
+		AddWriteTools(
+			toolsets.NewServerTool(ResolveSecretScanningAlert(getClient, t)),
 		)
 	dependabot := toolsets.NewToolset(ToolsetMetadataDependabot.ID, ToolsetMetadataDependabot.Description).
 		AddReadTools(
 			toolsets.NewServerTool(GetDependabotAlert(getClient, t)),
 			toolsets.NewServerTool(ListDependabotAlerts(getClient, t)),
+		).
+		AddWriteTools(
+			toolsets.NewServerTool(GenerateDependabotConfig(getClient, t)),
+			toolsets.NewServerTool(DismissDependabotAlert(getClient, t)),
 		)
 
 	notifications := toolsets.NewToolset(ToolsetMetadataNotifications.ID, ToolsetMetadataNotifications.Description).
@@ -267,6 +347,7 @@ func DefaultToolsetGroup(readOnly bool, getClient GetClientFn, getGQLClient GetG
 		).
 		AddWriteTools(
 			toolsets.NewServerTool(DismissNotification(getClient, t)),
+			toolsets.NewServerTool(MarkNotificationRead(getClient, t)),
 			toolsets.NewServerTool(MarkAllNotificationsRead(getClient, t)),
 			toolsets.NewServerTool(ManageNotificationSubscription(getClient, t)),
 			toolsets.NewServerTool(ManageRepositoryNotificationSubscription(getClient, t)),
@@ -278,6 +359,11 @@ func DefaultToolsetGroup(readOnly bool, getClient GetClientFn, getGQLClient GetG
 			toolsets.NewServerTool(GetDiscussion(getGQLClient, t)),
 			toolsets.NewServerTool(GetDiscussionComments(getGQLClient, t)),
 			toolsets.NewServerTool(ListDiscussionCategories(getGQLClient, t)),
+		).
+		AddWriteTools(
+			toolsets.NewServerTool(CreateDiscussion(getGQLClient, t)),
+			toolsets.NewServerTool(AddDiscussionComment(getGQLClient, t)),
+			toolsets.NewServerTool(MarkDiscussionCommentAsAnswer(getGQLClient, t)),
 		)
 
 	actions := toolsets.NewToolset(ToolsetMetadataActions.ID, ToolsetMetadataActions.Description).
@@ -288,9 +374,12 @@ func DefaultToolsetGroup(readOnly bool, getClient GetClientFn, getGQLClient GetG
 			toolsets.NewServerTool(GetWorkflowRunLogs(getClient, t)),
 			toolsets.NewServerTool(ListWorkflowJobs(getClient, t)),
 			toolsets.NewServerTool(GetJobLogs(getClient, t, contentWindowSize)),
+			toolsets.NewServerTool(GetJobLog(getClient, t, contentWindowSize)),
 			toolsets.NewServerTool(ListWorkflowRunArtifacts(getClient, t)),
 			toolsets.NewServerTool(DownloadWorkflowRunArtifact(getClient, t)),
 			toolsets.NewServerTool(GetWorkflowRunUsage(getClient, t)),
+			toolsets.NewServerTool(GetActionsOIDCCustomClaims(getClient, t)),
+			toolsets.NewServerTool(ListActionsVariables(getClient, t)),
 		).
 		AddWriteTools(
 			toolsets.NewServerTool(RunWorkflow(getClient, t)),
@@ -298,6 +387,13 @@ func DefaultToolsetGroup(readOnly bool, getClient GetClientFn, getGQLClient GetG
 			toolsets.NewServerTool(RerunFailedJobs(getClient, t)),
 			toolsets.NewServerTool(CancelWorkflowRun(getClient, t)),
 			toolsets.NewServerTool(DeleteWorkflowRunLogs(getClient, t)),
+			toolsets.NewServerTool(SetActionsOIDCCustomClaims(getClient, t)),
+			toolsets.NewServerTool(SetActionsVariable(getClient, t)),
+			toolsets.NewServerTool(DeleteActionsVariable(getClient, t)),
+			toolsets.NewServerTool(SetActionsSecret(getClient, t)),
+			toolsets.NewServerTool(CreateCheckRun(getClient, t)),
+			toolsets.NewServerTool(UpdateCheckRun(getClient, t)),
+			toolsets.NewServerTool(CreateCommitStatus(getClient, t)),
 		)
 
 	securityAdvisories := toolsets.NewToolset(ToolsetMetadataSecurityAdvisories.ID, ToolsetMetadataSecurityAdvisories.Description).
@@ -316,6 +412,11 @@ func DefaultToolsetGroup(readOnly bool, getClient GetClientFn, getGQLClient GetG
 			toolsets.NewServerTool(GetMe(getClient, t)),
 			toolsets.NewServerTool(GetTeams(getClient, getGQLClient, t)),
 			toolsets.NewServerTool(GetTeamMembers(getGQLClient, t)),
+			toolsets.NewServerTool(GetRateLimitStatus(getClient, t)),
+			toolsets.NewServerTool(GetServerMetrics(t)),
+			toolsets.NewServerTool(GetAppInstallations(t)),
+			toolsets.NewServerTool(SelectAccount(t)),
+			toolsets.NewServerTool(VerifyAccess(getClient, tsg, t)),
 		)
 
 	gists := toolsets.NewToolset(ToolsetMetadataGists.ID, ToolsetMetadataGists.Description).
@@ -326,6 +427,7 @@ func DefaultToolsetGroup(readOnly bool, getClient GetClientFn, getGQLClient GetG
 		AddWriteTools(
 			toolsets.NewServerTool(CreateGist(getClient, t)),
 			toolsets.NewServerTool(UpdateGist(getClient, t)),
+			toolsets.NewServerTool(ForkGist(getClient, t)),
 		)
 
 	projects := toolsets.NewToolset(ToolsetMetadataProjects.ID, ToolsetMetadataProjects.Description).
@@ -341,14 +443,20 @@ func DefaultToolsetGroup(readOnly bool, getClient GetClientFn, getGQLClient GetG
 			toolsets.NewServerTool(AddProjectItem(getClient, t)),
 			toolsets.NewServerTool(DeleteProjectItem(getClient, t)),
 			toolsets.NewServerTool(UpdateProjectItem(getClient, t)),
+			toolsets.NewServerTool(SetProjectItemField(getClient, getGQLClient, t)),
+			toolsets.NewServerTool(MoveProjectItem(getClient, getGQLClient, t)),
 		)
 	stargazers := toolsets.NewToolset(ToolsetMetadataStargazers.ID, ToolsetMetadataStargazers.Description).
 		AddReadTools(
 			toolsets.NewServerTool(ListStarredRepositories(getClient, t)),
+			toolsets.NewServerTool(ListWatchedRepositories(getClient, t)),
+			toolsets.NewServerTool(GetRepositoryWatchStatus(getClient, t)),
 		).
 		AddWriteTools(
 			toolsets.NewServerTool(StarRepository(getClient, t)),
 			toolsets.NewServerTool(UnstarRepository(getClient, t)),
+			toolsets.NewServerTool(WatchRepository(getClient, t)),
+			toolsets.NewServerTool(UnwatchRepository(getClient, t)),
 		)
 	labels := toolsets.NewToolset(ToolsetLabels.ID, ToolsetLabels.Description).
 		AddReadTools(
@@ -360,15 +468,59 @@ func DefaultToolsetGroup(readOnly bool, getClient GetClientFn, getGQLClient GetG
 		AddWriteTools(
 			// create or update
 			toolsets.NewServerTool(LabelWrite(getGQLClient, t)),
+			// reconcile with a label set
+			toolsets.NewServerTool(BulkSyncLabels(getClient, t)),
 		)
 
 	bulkOps := toolsets.NewToolset(ToolsetMetadataBulkOps.ID, ToolsetMetadataBulkOps.Description).
 		AddReadTools(
-			toolsets.NewServerTool(GetPushLimits(t)),
+			toolsets.NewServerTool(GetPushLimits(getClient, t)),
+			toolsets.NewServerTool(GetFilesBulk(getClient, t)),
 		).
 		AddWriteTools(
 			toolsets.NewServerTool(PushFilesChunked(getClient, t)),
+			toolsets.NewServerTool(ResumePushChunked(getClient, t)),
+			toolsets.NewServerTool(PushFilesToBranches(getClient, t)),
 			toolsets.NewServerTool(BulkDeleteFiles(getClient, t)),
+			toolsets.NewServerTool(BulkCopyFiles(getClient, t)),
+			toolsets.NewServerTool(RenameDirectory(getClient, t)),
+			toolsets.NewServerTool(SnapshotBranch(getClient, t)),
+			toolsets.NewServerTool(RestoreBranchSnapshot(getClient, getGQLClient, t)),
+			toolsets.NewServerTool(CheckLicenseHeaders(getClient, t)),
+			toolsets.NewServerTool(BulkApplyTemplate(getClient, t)),
+		)
+
+	webhooks := toolsets.NewToolset(ToolsetMetadataWebhooks.ID, ToolsetMetadataWebhooks.Description).
+		AddReadTools(
+			toolsets.NewServerTool(ListRepositoryWebhooks(getClient, t)),
+			toolsets.NewServerTool(AnalyzeWebhookFailures(getClient, t)),
+		).
+		AddWriteTools(
+			toolsets.NewServerTool(RedeliverWebhookDelivery(getClient, t)),
+		)
+
+	deployments := toolsets.NewToolset(ToolsetMetadataDeployments.ID, ToolsetMetadataDeployments.Description).
+		AddReadTools(
+			toolsets.NewServerTool(ListDeployments(getClient, t)),
+			toolsets.NewServerTool(ListEnvironments(getClient, t)),
+			toolsets.NewServerTool(GetEnvironment(getClient, t)),
+		).
+		AddWriteTools(
+			toolsets.NewServerTool(CreateDeployment(getClient, t)),
+			toolsets.NewServerTool(CreateDeploymentStatus(getClient, t)),
+			toolsets.NewServerTool(SetEnvironmentProtection(getClient, t)),
+		)
+
+	packages := toolsets.NewToolset(ToolsetMetadataPackages.ID, ToolsetMetadataPackages.Description).
+		AddReadTools(
+			toolsets.NewServerTool(ListPackages(getClient, t)),
+			toolsets.NewServerTool(ListPackageVersions(getClient, t)),
+			toolsets.NewServerTool(GetPackageDownloadStats(getGQLClient, t)),
+		).
+		AddWriteTools(
+			toolsets.NewServerTool(DeletePackageVersion(getClient, t)),
+			toolsets.NewServerTool(RestorePackageVersion(getClient, t)),
+			toolsets.NewServerTool(CleanupPackageVersions(getClient, t)),
 		)
 
 	// Add toolsets to the group
@@ -392,6 +544,9 @@ func DefaultToolsetGroup(readOnly bool, getClient GetClientFn, getGQLClient GetG
 	tsg.AddToolset(stargazers)
 	tsg.AddToolset(labels)
 	tsg.AddToolset(bulkOps)
+	tsg.AddToolset(webhooks)
+	tsg.AddToolset(deployments)
+	tsg.AddToolset(packages)
 
 	return tsg
 }
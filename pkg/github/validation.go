@@ -1,19 +1,137 @@
 package github
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"unicode/utf8"
 
+	"github.com/github/github-mcp-server/pkg/sanitize"
+	"github.com/github/github-mcp-server/pkg/uploads"
 	"github.com/github/github-mcp-server/pkg/utils"
+	"github.com/google/go-github/v79/github"
+	"github.com/google/jsonschema-go/jsonschema"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
+// Content encodings accepted in the content_encoding field of a file object
+const (
+	// ContentEncodingUTF8 is plain text content, the default
+	ContentEncodingUTF8 = "utf-8"
+	// ContentEncodingBase64 is content encoded as standard base64, used for binary files
+	ContentEncodingBase64 = "base64"
+	// ContentEncodingGzipBase64 is gzip-compressed content encoded as standard base64
+	ContentEncodingGzipBase64 = "gzip+base64"
+)
+
+// fileContentEncodingSchema describes the optional content_encoding field of a file object
+func fileContentEncodingSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:        "string",
+		Description: "Encoding of content. Use 'base64' for binary files, and 'gzip+base64' to send large files gzip-compressed and base64-encoded; size limits apply to the decoded content",
+		Enum:        []any{ContentEncodingUTF8, ContentEncodingBase64, ContentEncodingGzipBase64},
+		Default:     json.RawMessage(`"utf-8"`),
+	}
+}
+
+// uploadIDSchema describes the optional upload_id field of a file object
+func uploadIDSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:        "string",
+		Description: "ID returned by the server's /uploads endpoint, sent instead of content to keep requests small. Only available on servers served over HTTP with uploads enabled",
+	}
+}
+
+// Line ending conversions of the normalize_line_endings parameter
+const (
+	LineEndingsPreserve = "preserve"
+	LineEndingsLF       = "lf"
+	LineEndingsCRLF     = "crlf"
+)
+
+// normalizeLineEndingsSchema describes the normalize_line_endings parameter of the push tools
+func normalizeLineEndingsSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:        "string",
+		Description: "Convert the line endings of text files before pushing: 'lf' to LF, 'crlf' to CRLF, 'preserve' to keep them as sent. Converted files are reported as LINE_ENDINGS_NORMALIZED warnings. Binary files are never converted (default: preserve)",
+		Enum:        []any{LineEndingsPreserve, LineEndingsLF, LineEndingsCRLF},
+		Default:     json.RawMessage(`"` + LineEndingsPreserve + `"`),
+	}
+}
+
+// fileValidationOptionsFromArgs returns the validation options set by the parameters of a push tool.
+func fileValidationOptionsFromArgs(args map[string]any) (FileValidationOptions, error) {
+	lineEndings, err := OptionalParam[string](args, "normalize_line_endings")
+	if err != nil {
+		return FileValidationOptions{}, err
+	}
+	switch lineEndings {
+	case "", LineEndingsPreserve, LineEndingsLF, LineEndingsCRLF:
+	default:
+		return FileValidationOptions{}, fmt.Errorf("normalize_line_endings must be %s, %s or %s", LineEndingsPreserve, LineEndingsLF, LineEndingsCRLF)
+	}
+	allowSecrets, err := OptionalParam[bool](args, "allow_secrets")
+	if err != nil {
+		return FileValidationOptions{}, err
+	}
+	return FileValidationOptions{LineEndings: lineEndings, AllowSecrets: allowSecrets}, nil
+}
+
 // Chunk safety margin - leave 20% below the 100MB limit for API overhead
 const ChunkSafetyMarginPercent = 0.80
 
+// fileObjectSchema describes a file object of the push tools, with either content or upload_id
+func fileObjectSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"path": {
+				Type:        "string",
+				Description: "path to the file",
+			},
+			"content": {
+				Type:        "string",
+				Description: "file content",
+			},
+			"content_encoding": fileContentEncodingSchema(),
+			"upload_id":        uploadIDSchema(),
+		},
+		Required: []string{"path"},
+	}
+}
+
 // FileEntry represents a file to be pushed with its path and content
 type FileEntry struct {
 	Path    string
 	Content string
+	// Binary is set for base64-encoded content that is not text, which is pushed as a blob
+	Binary bool
+	// LFS is set for files stored in Git LFS. Once uploadLFSFiles has uploaded them, Content is
+	// the pointer file committed in their place.
+	LFS bool
+}
+
+// FileValidationOptions changes how ValidateFilesWithOptions prepares the files it validates
+type FileValidationOptions struct {
+	// LineEndings converts the line endings of text files to LineEndingsLF or LineEndingsCRLF.
+	// Empty or LineEndingsPreserve leaves them as they are.
+	LineEndings string
+	// LFS lets files be stored in Git LFS, either by setting lfs on the file or by being larger
+	// than LFSThresholdBytes when it is positive. Such files are left out of the size checks.
+	LFS               bool
+	LFSThresholdBytes int64
+	// AllowSecrets skips the scan for credentials, which otherwise fails validation with
+	// SECRETS_DETECTED.
+	AllowSecrets bool
 }
 
 // FileValidationResult contains detailed validation results
@@ -23,7 +141,34 @@ type FileValidationResult struct {
 	LargestFile     string
 	LargestFileSize int64
 	Duplicates      map[string][]int // path -> indices where duplicates found
-	OversizedFiles  []string         // files exceeding MaxFileSizeBytes
+	OversizedFiles  []string         // files exceeding the maximum file size
+	NormalizedFiles []string         // files whose line endings were converted
+	LFSFiles        []string         // files to store in Git LFS
+	// Warnings are advisory findings that do not stop the push
+	Warnings []ValidationWarning
+}
+
+// ValidationWarning is a non-fatal finding about a file, reported alongside the result of a push
+type ValidationWarning struct {
+	Code    string `json:"code"`
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// Thresholds of the validation warnings
+const (
+	// largeFileWarningBytes is the size above which files are reported as large (1MB)
+	largeFileWarningBytes = 1024 * 1024
+	// maxPathDepthWarning is the number of directories above which paths are reported as deep
+	maxPathDepthWarning = 20
+)
+
+// binaryFileExtensions are extensions of files that are rarely text, so text content for them
+// suggests binary data that was decoded by mistake
+var binaryFileExtensions = map[string]bool{
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".ico": true, ".webp": true, ".bmp": true,
+	".pdf": true, ".zip": true, ".gz": true, ".tgz": true, ".jar": true, ".exe": true, ".dll": true,
+	".so": true, ".dylib": true, ".class": true, ".woff": true, ".woff2": true, ".ttf": true, ".otf": true,
 }
 
 // ValidationError provides detailed error information with suggestions
@@ -32,6 +177,8 @@ type ValidationError struct {
 	Message    string
 	Suggestion string
 	Details    map[string]interface{}
+	// Cause is the underlying error, such as the API error a validation failure was derived from
+	Cause error
 }
 
 func (e *ValidationError) Error() string {
@@ -41,8 +188,126 @@ func (e *ValidationError) Error() string {
 	return e.Message
 }
 
+// Unwrap returns the underlying error, so that errors.As can reach an API error behind a
+// validation failure.
+func (e *ValidationError) Unwrap() error {
+	return e.Cause
+}
+
+// Is reports whether target is the sentinel error of the error's code, so that callers can
+// test for a kind of failure with errors.Is(err, ErrFileTooLarge).
+func (e *ValidationError) Is(target error) bool {
+	sentinel, ok := validationSentinels[e.Code]
+	return ok && sentinel == target
+}
+
+// Sentinel errors matching ValidationError codes with errors.Is
+var (
+	ErrInvalidFileFormat          = errors.New("invalid file format")
+	ErrMissingFilePath            = errors.New("missing file path")
+	ErrMissingFileContent         = errors.New("missing file content")
+	ErrDuplicatePath              = errors.New("duplicate file path")
+	ErrConflictingFileContent     = errors.New("conflicting file content")
+	ErrUnsupportedContentEncoding = errors.New("unsupported content encoding")
+	ErrInvalidEncodedContent      = errors.New("invalid encoded content")
+	ErrInvalidContentEncoding     = errors.New("content is not valid text")
+	ErrUploadsNotEnabled          = errors.New("uploads not enabled")
+	ErrUploadNotFound             = errors.New("upload not found")
+	ErrSanitizedPathCollision     = errors.New("sanitized path collision")
+	ErrTooManyFiles               = errors.New("too many files")
+	ErrFileTooLarge               = errors.New("file too large")
+	ErrTotalSizeTooLarge          = errors.New("total size too large")
+	ErrChunkTooLarge              = errors.New("chunk too large")
+	ErrEmptyRepository            = errors.New("empty repository")
+	ErrMissingPaths               = errors.New("missing paths")
+	ErrSecretsDetected            = errors.New("secrets detected")
+	ErrAdminRequired              = errors.New("admin access required")
+)
+
+// validationSentinels maps ValidationError codes to their sentinel errors
+var validationSentinels = map[string]error{
+	"INVALID_FILE_FORMAT":          ErrInvalidFileFormat,
+	"MISSING_FILE_PATH":            ErrMissingFilePath,
+	"MISSING_FILE_CONTENT":         ErrMissingFileContent,
+	"DUPLICATE_FILE_PATHS":         ErrDuplicatePath,
+	"CONFLICTING_FILE_CONTENT":     ErrConflictingFileContent,
+	"UNSUPPORTED_CONTENT_ENCODING": ErrUnsupportedContentEncoding,
+	"INVALID_BASE64_CONTENT":       ErrInvalidEncodedContent,
+	"INVALID_COMPRESSED_CONTENT":   ErrInvalidEncodedContent,
+	"INVALID_CONTENT_ENCODING":     ErrInvalidContentEncoding,
+	"UPLOADS_NOT_ENABLED":          ErrUploadsNotEnabled,
+	"UPLOAD_NOT_FOUND":             ErrUploadNotFound,
+	"SANITIZED_PATH_COLLISION":     ErrSanitizedPathCollision,
+	"TOO_MANY_FILES":               ErrTooManyFiles,
+	"FILE_TOO_LARGE":               ErrFileTooLarge,
+	"TOTAL_SIZE_TOO_LARGE":         ErrTotalSizeTooLarge,
+	"CHUNK_TOO_LARGE":              ErrChunkTooLarge,
+	"EMPTY_REPOSITORY":             ErrEmptyRepository,
+	"MISSING_PATHS":                ErrMissingPaths,
+	"SECRETS_DETECTED":             ErrSecretsDetected,
+	"ADMIN_REQUIRED":               ErrAdminRequired,
+}
+
+// validationErrorResult returns err as a tool error whose text is a JSON object with its code,
+// message, suggestion and details, so that clients can react to the code.
+func validationErrorResult(err *ValidationError) *mcp.CallToolResult {
+	return utils.NewToolResultValidationError(err.Code, err.Message, err.Suggestion, err.Details)
+}
+
+// toolErrorResult returns err as a tool error, structured like validationErrorResult when it
+// wraps a ValidationError and as plain text otherwise.
+func toolErrorResult(err error) *mcp.CallToolResult {
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		return validationErrorResult(validationErr)
+	}
+	return utils.NewToolResultError(err.Error())
+}
+
+// prefixedToolErrorResult is toolErrorResult with the message of err prefixed by context.
+func prefixedToolErrorResult(context string, err error) *mcp.CallToolResult {
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		prefixed := *validationErr
+		prefixed.Message = context + ": " + validationErr.Message
+		return validationErrorResult(&prefixed)
+	}
+	return utils.NewToolResultError(context + ": " + err.Error())
+}
+
+// isEmptyRepository reports whether a failed Git Data API call was rejected because the
+// repository has no commits yet.
+func isEmptyRepository(resp *github.Response, err error) bool {
+	if resp == nil || resp.StatusCode != http.StatusConflict {
+		return false
+	}
+	var errResp *github.ErrorResponse
+	return errors.As(err, &errResp) && strings.Contains(strings.ToLower(errResp.Message), "repository is empty")
+}
+
+// emptyRepositoryError reports a write to a repository without commits, which has no
+// branch to build on until it is initialized. cause is the API error that revealed it.
+func emptyRepositoryError(owner, repo string, cause error) *ValidationError {
+	return &ValidationError{
+		Cause:      cause,
+		Code:       "EMPTY_REPOSITORY",
+		Message:    fmt.Sprintf("repository %s/%s is empty and has no branch to commit to", owner, repo),
+		Suggestion: "Call initialize_repository to create the initial commit and default branch, then retry",
+		Details: map[string]interface{}{
+			"owner": owner,
+			"repo":  repo,
+		},
+	}
+}
+
 // ValidateFiles performs comprehensive validation on a set of files
 func ValidateFiles(files []interface{}) (*FileValidationResult, []FileEntry, error) {
+	return ValidateFilesWithOptions(files, FileValidationOptions{})
+}
+
+// ValidateFilesWithOptions validates files like ValidateFiles after preparing their content as
+// opts asks. Sizes and warnings are those of the prepared content.
+func ValidateFilesWithOptions(files []interface{}, opts FileValidationOptions) (*FileValidationResult, []FileEntry, error) {
 	result := &FileValidationResult{
 		Duplicates:     make(map[string][]int),
 		OversizedFiles: make([]string, 0),
@@ -50,13 +315,23 @@ func ValidateFiles(files []interface{}) (*FileValidationResult, []FileEntry, err
 
 	seenPaths := make(map[string]int)
 	entries := make([]FileEntry, 0, len(files))
+	maxFileSize := CurrentPushLimits().MaxFileSizeBytes
+	maxDecodedSize := maxFileSize
+	// Compressed content is bounded by the batch as a whole too, so that many small gzip files
+	// cannot each inflate to the per-file limit
+	maxDecompressedTotal := CurrentPushLimits().MaxTotalPushSizeBytes
+	if opts.LFS {
+		maxDecodedSize = MaxLFSFileSizeBytes
+		maxDecompressedTotal = MaxLFSDecompressedTotalBytes
+	}
+	var decompressedTotal int64
 
 	for i, file := range files {
 		fileMap, ok := file.(map[string]interface{})
 		if !ok {
 			return nil, nil, &ValidationError{
-				Code:    "INVALID_FILE_FORMAT",
-				Message: fmt.Sprintf("file at index %d must be an object with path and content", i),
+				Code:       "INVALID_FILE_FORMAT",
+				Message:    fmt.Sprintf("file at index %d must be an object with path and content", i),
 				Suggestion: "Ensure each file has both 'path' (string) and 'content' (string) fields",
 			}
 		}
@@ -64,8 +339,8 @@ func ValidateFiles(files []interface{}) (*FileValidationResult, []FileEntry, err
 		path, ok := fileMap["path"].(string)
 		if !ok || path == "" {
 			return nil, nil, &ValidationError{
-				Code:    "MISSING_FILE_PATH",
-				Message: fmt.Sprintf("file at index %d must have a non-empty path", i),
+				Code:       "MISSING_FILE_PATH",
+				Message:    fmt.Sprintf("file at index %d must have a non-empty path", i),
 				Suggestion: "Add a valid 'path' field to each file object",
 			}
 		}
@@ -73,12 +348,55 @@ func ValidateFiles(files []interface{}) (*FileValidationResult, []FileEntry, err
 		content, ok := fileMap["content"].(string)
 		if !ok {
 			return nil, nil, &ValidationError{
-				Code:    "MISSING_FILE_CONTENT",
-				Message: fmt.Sprintf("file at index %d must have content", i),
+				Code:       "MISSING_FILE_CONTENT",
+				Message:    fmt.Sprintf("file at index %d must have content", i),
 				Suggestion: "Add a 'content' field to the file object (can be empty string)",
 			}
 		}
 
+		encoding, _ := fileMap["content_encoding"].(string)
+		maxSize := maxDecodedSize
+		if encoding == ContentEncodingGzipBase64 {
+			maxSize = min(maxSize, maxDecompressedTotal-decompressedTotal)
+		}
+		content, err := decodeFileContent(path, content, encoding, maxSize)
+		if err != nil {
+			return nil, nil, err
+		}
+		if encoding == ContentEncodingGzipBase64 {
+			decompressedTotal += int64(len(content))
+			if decompressedTotal > maxDecompressedTotal {
+				return nil, nil, &ValidationError{
+					Code:       "DECOMPRESSED_CONTENT_TOO_LARGE",
+					Message:    fmt.Sprintf("the gzip-compressed files up to '%s' decompress to more than the maximum of %d bytes for one call", path, maxDecompressedTotal),
+					Suggestion: "Split the files across several calls",
+					Details: map[string]interface{}{
+						"path":      path,
+						"max_bytes": maxDecompressedTotal,
+					},
+				}
+			}
+		}
+		// Text content must be valid UTF-8, while base64-encoded content may hold any bytes
+		binary := false
+		if offset, problem := findInvalidText(content); offset >= 0 {
+			if encoding == "" || encoding == ContentEncodingUTF8 {
+				return nil, nil, invalidContentEncodingError(i, path, offset, problem)
+			}
+			binary = true
+		}
+		if !binary {
+			var converted int
+			if content, converted = normalizeLineEndings(content, opts.LineEndings); converted > 0 {
+				result.NormalizedFiles = append(result.NormalizedFiles, path)
+				result.Warnings = append(result.Warnings, ValidationWarning{
+					Code:    "LINE_ENDINGS_NORMALIZED",
+					Path:    path,
+					Message: fmt.Sprintf("converted %d line ending(s) to %s", converted, strings.ToUpper(opts.LineEndings)),
+				})
+			}
+		}
+
 		// Check for duplicate paths
 		if firstIndex, exists := seenPaths[path]; exists {
 			if _, tracked := result.Duplicates[path]; !tracked {
@@ -90,23 +408,48 @@ func ValidateFiles(files []interface{}) (*FileValidationResult, []FileEntry, err
 
 		// Calculate sizes
 		fileSize := int64(len(content))
-		result.TotalSize += fileSize
 		result.FileCount++
 
-		// Track largest file
-		if fileSize > result.LargestFileSize {
-			result.LargestFile = path
-			result.LargestFileSize = fileSize
+		// Files stored in Git LFS are committed as small pointers, so only the LFS limit applies
+		lfs, _ := fileMap["lfs"].(bool)
+		lfs = opts.LFS && (lfs || (opts.LFSThresholdBytes > 0 && fileSize > opts.LFSThresholdBytes))
+		if lfs {
+			if fileSize > MaxLFSFileSizeBytes {
+				return nil, nil, &ValidationError{
+					Code:       "LFS_FILE_TOO_LARGE",
+					Message:    fmt.Sprintf("file '%s' size (%d bytes) exceeds the Git LFS maximum of %d bytes", path, fileSize, int64(MaxLFSFileSizeBytes)),
+					Suggestion: fmt.Sprintf("Split '%s' into smaller files", path),
+				}
+			}
+			result.LFSFiles = append(result.LFSFiles, path)
+		} else {
+			result.TotalSize += fileSize
+
+			// Track largest file
+			if fileSize > result.LargestFileSize {
+				result.LargestFile = path
+				result.LargestFileSize = fileSize
+			}
+
+			// Track oversized files
+			if fileSize > maxFileSize {
+				result.OversizedFiles = append(result.OversizedFiles, path)
+			}
 		}
 
-		// Track oversized files
-		if fileSize > MaxFileSizeBytes {
-			result.OversizedFiles = append(result.OversizedFiles, path)
+		for _, warning := range fileWarnings(path, content, binary) {
+			// CRLF line endings were asked for
+			if warning.Code == "CRLF_LINE_ENDINGS" && opts.LineEndings == LineEndingsCRLF {
+				continue
+			}
+			result.Warnings = append(result.Warnings, warning)
 		}
 
 		entries = append(entries, FileEntry{
 			Path:    path,
 			Content: content,
+			Binary:  binary,
+			LFS:     lfs,
 		})
 	}
 
@@ -120,8 +463,8 @@ func ValidateFiles(files []interface{}) (*FileValidationResult, []FileEntry, err
 			break
 		}
 		return result, nil, &ValidationError{
-			Code:    "DUPLICATE_FILE_PATHS",
-			Message: fmt.Sprintf("duplicate file path '%s' found at indices %v - each file path must be unique", firstDup, indices),
+			Code:       "DUPLICATE_FILE_PATHS",
+			Message:    fmt.Sprintf("duplicate file path '%s' found at indices %v - each file path must be unique", firstDup, indices),
 			Suggestion: fmt.Sprintf("Remove duplicate entries for '%s' and ensure each path appears only once", firstDup),
 			Details: map[string]interface{}{
 				"duplicates": result.Duplicates,
@@ -129,70 +472,435 @@ func ValidateFiles(files []interface{}) (*FileValidationResult, []FileEntry, err
 		}
 	}
 
+	if !opts.AllowSecrets {
+		if err := ValidateNoSecrets(entries); err != nil {
+			return result, nil, err
+		}
+	}
+
 	return result, entries, nil
 }
 
+// normalizeLineEndings converts the line endings of content to LineEndingsLF or
+// LineEndingsCRLF, returning the converted content and how many line endings changed.
+// Other values of lineEndings, and content that needs no conversion, are returned as they are.
+func normalizeLineEndings(content, lineEndings string) (string, int) {
+	var newline string
+	crlf := strings.Count(content, "\r\n")
+	converted, size := crlf, len(content)-crlf
+	switch lineEndings {
+	case LineEndingsLF:
+		newline = "\n"
+	case LineEndingsCRLF:
+		newline = "\r\n"
+		converted = strings.Count(content, "\n") - crlf
+		size = len(content) + converted
+	default:
+		return content, 0
+	}
+	if converted == 0 {
+		return content, 0
+	}
+
+	// Copy the content once, into a buffer of the converted size
+	var normalized strings.Builder
+	normalized.Grow(size)
+	for rest := content; rest != ""; {
+		end := strings.IndexByte(rest, '\n')
+		if end < 0 {
+			normalized.WriteString(rest)
+			break
+		}
+		normalized.WriteString(strings.TrimSuffix(rest[:end], "\r"))
+		normalized.WriteString(newline)
+		rest = rest[end+1:]
+	}
+	return normalized.String(), converted
+}
+
+// fileWarnings returns the advisory findings about a file: large content, text that looks like
+// binary data, deeply nested paths and CRLF line endings.
+func fileWarnings(filePath, content string, binary bool) []ValidationWarning {
+	var warnings []ValidationWarning
+	if size := len(content); size > largeFileWarningBytes {
+		warnings = append(warnings, ValidationWarning{
+			Code:    "LARGE_FILE",
+			Path:    filePath,
+			Message: fmt.Sprintf("file is %.2f MB; consider Git LFS for large or frequently changing files", float64(size)/(1024*1024)),
+		})
+	}
+	if depth := strings.Count(filePath, "/"); depth > maxPathDepthWarning {
+		warnings = append(warnings, ValidationWarning{
+			Code:    "DEEP_PATH",
+			Path:    filePath,
+			Message: fmt.Sprintf("path is nested %d directories deep, more than %d", depth, maxPathDepthWarning),
+		})
+	}
+	if binary {
+		return warnings
+	}
+	switch {
+	// Text is valid UTF-8 here, so a plain substring search finds the replacement character
+	case strings.Contains(content, string(utf8.RuneError)):
+		warnings = append(warnings, ValidationWarning{
+			Code:    "SUSPICIOUS_BINARY_CONTENT",
+			Path:    filePath,
+			Message: fmt.Sprintf("text contains U+FFFD replacement characters, a sign of binary data decoded as text; send binary files with content_encoding '%s'", ContentEncodingBase64),
+		})
+	case binaryFileExtensions[strings.ToLower(path.Ext(filePath))]:
+		warnings = append(warnings, ValidationWarning{
+			Code:    "SUSPICIOUS_BINARY_CONTENT",
+			Path:    filePath,
+			Message: fmt.Sprintf("file type is usually binary but the content was sent as text; send binary files with content_encoding '%s'", ContentEncodingBase64),
+		})
+	}
+	if strings.Contains(content, "\r\n") {
+		warnings = append(warnings, ValidationWarning{
+			Code:    "CRLF_LINE_ENDINGS",
+			Path:    filePath,
+			Message: "file uses CRLF line endings, which show as changed lines next to LF files",
+		})
+	}
+	return warnings
+}
+
+// withValidationWarnings adds warnings to result as a second text content, so that the first
+// content, the result of the push, keeps its format.
+func withValidationWarnings(result *mcp.CallToolResult, warnings []ValidationWarning) *mcp.CallToolResult {
+	if len(warnings) == 0 {
+		return result
+	}
+	data, err := json.Marshal(map[string]any{"warnings": warnings})
+	if err != nil {
+		return result
+	}
+	result.Content = append(result.Content, &mcp.TextContent{Text: string(data)})
+	return result
+}
+
+// findInvalidText returns the byte offset of the first invalid UTF-8 sequence or disallowed
+// control character of content, and which of the two it is. The offset is -1 for valid text.
+// Tabs, line and page breaks and the escape character of terminal color codes are allowed.
+func findInvalidText(content string) (int, string) {
+	for offset := 0; offset < len(content); {
+		// Printable ASCII and line feeds make up most text, so skip them without decoding
+		if c := content[offset]; (c >= 0x20 && c < utf8.RuneSelf) || c == '\n' {
+			offset++
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(content[offset:])
+		if r == utf8.RuneError && size == 1 {
+			return offset, "invalid UTF-8 sequence"
+		}
+		if r < 0x20 && r != '\t' && r != '\n' && r != '\r' && r != '\f' && r != '\v' && r != 0x1b {
+			return offset, fmt.Sprintf("control character U+%04X", r)
+		}
+		offset += size
+	}
+	return -1, ""
+}
+
+// invalidContentEncodingError reports text content that GitHub would reject or corrupt.
+func invalidContentEncodingError(index int, path string, offset int, problem string) *ValidationError {
+	return &ValidationError{
+		Code:       "INVALID_CONTENT_ENCODING",
+		Message:    fmt.Sprintf("file '%s' at index %d is not valid text: %s at byte offset %d", path, index, problem, offset),
+		Suggestion: fmt.Sprintf("Send binary files base64-encoded with content_encoding '%s'", ContentEncodingBase64),
+		Details: map[string]interface{}{
+			"path":        path,
+			"index":       index,
+			"byte_offset": offset,
+			"problem":     problem,
+		},
+	}
+}
+
+// resolveUploadedFiles replaces the upload_id of each file object with the content uploaded under
+// that ID, so that the files can be validated like inline content. files is not modified.
+func resolveUploadedFiles(ctx context.Context, files []interface{}) ([]interface{}, error) {
+	resolved := make([]interface{}, len(files))
+	for i, file := range files {
+		resolved[i] = file
+		fileMap, ok := file.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		uploadID, ok := fileMap["upload_id"].(string)
+		if !ok {
+			continue
+		}
+
+		if _, hasContent := fileMap["content"]; hasContent {
+			return nil, &ValidationError{
+				Code:       "CONFLICTING_FILE_CONTENT",
+				Message:    fmt.Sprintf("file at index %d sets both content and upload_id", i),
+				Suggestion: "Send either content or the upload_id of content uploaded to /uploads",
+			}
+		}
+		if _, hasEncoding := fileMap["content_encoding"]; hasEncoding {
+			return nil, &ValidationError{
+				Code:       "CONFLICTING_FILE_CONTENT",
+				Message:    fmt.Sprintf("file at index %d sets content_encoding with upload_id", i),
+				Suggestion: "Upload the plain file content; content_encoding only applies to inline content",
+			}
+		}
+		store, ok := uploads.StoreFromContext(ctx)
+		if !ok {
+			return nil, &ValidationError{
+				Code:       "UPLOADS_NOT_ENABLED",
+				Message:    fmt.Sprintf("file at index %d uses upload_id, but this server does not accept uploads", i),
+				Suggestion: "Send the file content inline, or use a server served over HTTP with the /uploads endpoint",
+			}
+		}
+		content, err := store.Read(uploadID)
+		if err != nil {
+			return nil, &ValidationError{
+				Code:       "UPLOAD_NOT_FOUND",
+				Message:    fmt.Sprintf("file at index %d: %v", i, err),
+				Suggestion: "Upload the content again to /uploads; uploads expire an hour after they were last uploaded",
+				Cause:      err,
+			}
+		}
+
+		withContent := make(map[string]interface{}, len(fileMap))
+		for k, v := range fileMap {
+			if k != "upload_id" {
+				withContent[k] = v
+			}
+		}
+		withContent["content"] = string(content)
+		resolved[i] = withContent
+	}
+	return resolved, nil
+}
+
+// decodeFileContent returns the plain content of a file sent with the given content_encoding.
+// Decompression stops one byte past maxSize, so an oversized file is still reported as oversized
+// without inflating it completely.
+func decodeFileContent(path, content, encoding string, maxSize int64) (string, error) {
+	switch encoding {
+	case "", ContentEncodingUTF8:
+		return content, nil
+	case ContentEncodingBase64:
+		decoded, err := decodeBase64Content(content)
+		if err != nil {
+			return "", &ValidationError{
+				Code:       "INVALID_BASE64_CONTENT",
+				Message:    fmt.Sprintf("file '%s' content is not valid base64: %v", path, err),
+				Suggestion: "Encode the file bytes with standard base64 (RFC 4648, with padding)",
+				Cause:      err,
+			}
+		}
+		return decoded, nil
+	case ContentEncodingGzipBase64:
+	default:
+		return "", &ValidationError{
+			Code:       "UNSUPPORTED_CONTENT_ENCODING",
+			Message:    fmt.Sprintf("file '%s' has unsupported content_encoding '%s'", path, encoding),
+			Suggestion: fmt.Sprintf("Use '%s', '%s' or '%s'", ContentEncodingUTF8, ContentEncodingBase64, ContentEncodingGzipBase64),
+		}
+	}
+
+	compressed, err := base64.StdEncoding.DecodeString(content)
+	if err != nil {
+		return "", &ValidationError{
+			Code:       "INVALID_COMPRESSED_CONTENT",
+			Message:    fmt.Sprintf("file '%s' content is not valid base64: %v", path, err),
+			Suggestion: "Encode the gzip-compressed bytes with standard base64 (RFC 4648, with padding)",
+			Cause:      err,
+		}
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return "", &ValidationError{
+			Code:       "INVALID_COMPRESSED_CONTENT",
+			Message:    fmt.Sprintf("file '%s' content is not valid gzip data: %v", path, err),
+			Suggestion: "Compress the file content with gzip before base64-encoding it",
+			Cause:      err,
+		}
+	}
+	defer func() { _ = reader.Close() }()
+
+	// The gzip trailer holds the uncompressed size modulo 2^32, which sizes the builder up front
+	// instead of growing it while decompressing. It is only a hint, as the data is not trusted,
+	// so it is capped by the size limit and by the largest ratio deflate can compress to.
+	maxSize++
+	var decompressed strings.Builder
+	if len(compressed) >= 4 {
+		size := int64(binary.LittleEndian.Uint32(compressed[len(compressed)-4:]))
+		decompressed.Grow(int(min(size, maxSize, int64(len(compressed))*maxDeflateRatio)))
+	}
+	if _, err := io.Copy(&decompressed, io.LimitReader(reader, maxSize)); err != nil {
+		return "", &ValidationError{
+			Code:       "INVALID_COMPRESSED_CONTENT",
+			Message:    fmt.Sprintf("file '%s' content could not be decompressed: %v", path, err),
+			Suggestion: "Compress the file content with gzip before base64-encoding it",
+			Cause:      err,
+		}
+	}
+
+	return decompressed.String(), nil
+}
+
+// maxDeflateRatio is the largest ratio of uncompressed to deflate-compressed size
+const maxDeflateRatio = 1032
+
+// base64BlockSize is the number of content bytes decodeBase64Content and encodeBlobContent
+// convert at a time. It is a multiple of 3 and 4, so that blocks need no padding.
+const base64BlockSize = 48 * 1024
+
+// decodeBase64Content decodes standard base64 content one block at a time into the string it
+// returns, so that the decoded bytes are allocated once instead of as a byte slice and a string.
+func decodeBase64Content(content string) (string, error) {
+	// Line breaks, and padding that is not at the end, shift the blocks: decode those at once
+	if padding := strings.IndexByte(content, '='); strings.ContainsAny(content, "\r\n") || (padding >= 0 && padding < len(content)-2) {
+		decoded, err := base64.StdEncoding.DecodeString(content)
+		return string(decoded), err
+	}
+
+	var decoded strings.Builder
+	decoded.Grow(base64.StdEncoding.DecodedLen(len(content)))
+	block := make([]byte, min(len(content), base64BlockSize))
+	out := make([]byte, base64.StdEncoding.DecodedLen(len(block)))
+	for offset := 0; offset < len(content); {
+		n := copy(block, content[offset:])
+		m, err := base64.StdEncoding.Decode(out, block[:n])
+		if corrupt, ok := err.(base64.CorruptInputError); ok {
+			return "", corrupt + base64.CorruptInputError(offset)
+		}
+		decoded.Write(out[:m])
+		offset += n
+	}
+	return decoded.String(), nil
+}
+
+// encodeBlobContent returns content encoded as standard base64 for a blob. Unlike converting
+// content to a byte slice to encode it, which copies all of it, it copies one block at a time.
+func encodeBlobContent(content string) string {
+	var encoded strings.Builder
+	encoded.Grow(base64.StdEncoding.EncodedLen(len(content)))
+	block := make([]byte, min(len(content), base64BlockSize))
+	out := make([]byte, base64.StdEncoding.EncodedLen(len(block)))
+	for rest := content; rest != ""; {
+		n := copy(block, rest)
+		base64.StdEncoding.Encode(out, block[:n])
+		encoded.Write(out[:base64.StdEncoding.EncodedLen(n)])
+		rest = rest[n:]
+	}
+	return encoded.String()
+}
+
+// SanitizeFilePaths rewrites file paths in place according to policy and returns a map of
+// original path -> sanitized path for every file that was renamed. It fails if two files
+// would end up with the same path after sanitization.
+func SanitizeFilePaths(files []FileEntry, policy sanitize.PathPolicy) (map[string]string, error) {
+	renames := make(map[string]string)
+	if policy == sanitize.PathPolicyNone {
+		return renames, nil
+	}
+
+	seen := make(map[string]string, len(files))
+	for i := range files {
+		original := files[i].Path
+		sanitized := sanitize.Path(original, policy)
+
+		if other, exists := seen[sanitized]; exists {
+			return nil, &ValidationError{
+				Code:       "SANITIZED_PATH_COLLISION",
+				Message:    fmt.Sprintf("paths '%s' and '%s' both sanitize to '%s'", other, original, sanitized),
+				Suggestion: "Rename one of the files so they remain distinct after sanitization, or use a less aggressive sanitize_paths policy",
+				Details: map[string]interface{}{
+					"sanitized_path": sanitized,
+					"paths":          []string{other, original},
+				},
+			}
+		}
+		seen[sanitized] = original
+
+		if sanitized != original {
+			renames[original] = sanitized
+			files[i].Path = sanitized
+		}
+	}
+
+	return renames, nil
+}
+
 // ValidateFileCount checks if file count is within limits
 func ValidateFileCount(count int, maxFiles int) (*mcp.CallToolResult, error) {
 	if count > maxFiles {
-		return utils.NewToolResultError(fmt.Sprintf(
-			"too many files: %d exceeds maximum of %d per push_files call. Use push_files_chunked for larger batches or make multiple calls",
-			count, maxFiles,
-		)), &ValidationError{
+		err := &ValidationError{
 			Code:       "TOO_MANY_FILES",
-			Message:    fmt.Sprintf("file count %d exceeds maximum %d", count, maxFiles),
-			Suggestion: "Use push_files_chunked tool for batches over 100 files, or split into multiple push_files calls",
+			Message:    fmt.Sprintf("too many files: %d exceeds maximum of %d per push_files call", count, maxFiles),
+			Suggestion: fmt.Sprintf("Use push_files_chunked for batches over %d files, or split into multiple push_files calls", maxFiles),
+			Details: map[string]interface{}{
+				"file_count": count,
+				"max_files":  maxFiles,
+			},
 		}
+		return validationErrorResult(err), err
 	}
 	return nil, nil
 }
 
 // ValidateFileSize checks if individual file size is within limits
-func ValidateFileSize(path string, size int64) (*mcp.CallToolResult, error) {
-	if size > MaxFileSizeBytes {
+func ValidateFileSize(limits PushLimits, path string, size int64) (*mcp.CallToolResult, error) {
+	maxBytes := limits.MaxFileSizeBytes
+	if size > maxBytes {
 		sizeMB := float64(size) / (1024 * 1024)
-		maxMB := float64(MaxFileSizeBytes) / (1024 * 1024)
-		return utils.NewToolResultError(fmt.Sprintf(
-			"file '%s' size (%d bytes, %.2f MB) exceeds maximum of %d bytes (%.0f MB)",
-			path, size, sizeMB, MaxFileSizeBytes, maxMB,
-		)), &ValidationError{
-			Code:    "FILE_TOO_LARGE",
-			Message: fmt.Sprintf("file '%s' is %.2f MB, exceeds limit of %.0f MB", path, sizeMB, maxMB),
+		maxMB := float64(maxBytes) / (1024 * 1024)
+		err := &ValidationError{
+			Code:       "FILE_TOO_LARGE",
+			Message:    fmt.Sprintf("file '%s' size (%d bytes, %.2f MB) exceeds maximum of %d bytes (%.0f MB)", path, size, sizeMB, maxBytes, maxMB),
 			Suggestion: fmt.Sprintf("Split '%s' into smaller files or use Git LFS for large files", path),
 			Details: map[string]interface{}{
 				"file_size_bytes": size,
 				"file_size_mb":    sizeMB,
-				"max_bytes":       MaxFileSizeBytes,
+				"max_bytes":       maxBytes,
 				"max_mb":          maxMB,
 			},
 		}
+		addLimitsHost(err, limits)
+		return validationErrorResult(err), err
 	}
 	return nil, nil
 }
 
 // ValidateTotalSize checks if total size of all files is within limits
-func ValidateTotalSize(totalSize int64) (*mcp.CallToolResult, error) {
-	if totalSize > MaxTotalPushSizeBytes {
+func ValidateTotalSize(limits PushLimits, totalSize int64) (*mcp.CallToolResult, error) {
+	maxBytes := limits.MaxTotalPushSizeBytes
+	if totalSize > maxBytes {
 		sizeMB := float64(totalSize) / (1024 * 1024)
-		maxMB := float64(MaxTotalPushSizeBytes) / (1024 * 1024)
-		return utils.NewToolResultError(fmt.Sprintf(
-			"total content size (%d bytes, %.2f MB) exceeds maximum of %d bytes (%.0f MB)",
-			totalSize, sizeMB, MaxTotalPushSizeBytes, maxMB,
-		)), &ValidationError{
-			Code:    "TOTAL_SIZE_TOO_LARGE",
-			Message: fmt.Sprintf("total size %.2f MB exceeds limit of %.0f MB", sizeMB, maxMB),
+		maxMB := float64(maxBytes) / (1024 * 1024)
+		err := &ValidationError{
+			Code:       "TOTAL_SIZE_TOO_LARGE",
+			Message:    fmt.Sprintf("total content size (%d bytes, %.2f MB) exceeds maximum of %d bytes (%.0f MB)", totalSize, sizeMB, maxBytes, maxMB),
 			Suggestion: "Use push_files_chunked to split into multiple commits, or reduce the number of files per push",
 			Details: map[string]interface{}{
 				"total_size_bytes": totalSize,
 				"total_size_mb":    sizeMB,
-				"max_bytes":        MaxTotalPushSizeBytes,
+				"max_bytes":        maxBytes,
 				"max_mb":           maxMB,
 			},
 		}
+		addLimitsHost(err, limits)
+		return validationErrorResult(err), err
 	}
 	return nil, nil
 }
 
+// addLimitsHost records in the details of err the host whose limits it was checked against.
+func addLimitsHost(err *ValidationError, limits PushLimits) {
+	if limits.Host == "" {
+		return
+	}
+	err.Details["host"] = limits.Host
+	if limits.HostType != "" {
+		err.Details["host_type"] = limits.HostType
+	}
+}
+
 // ValidateChunkSize validates that a chunk doesn't exceed size limits
 func ValidateChunkSize(files []FileEntry) error {
 	var chunkSize int64
@@ -200,17 +908,18 @@ func ValidateChunkSize(files []FileEntry) error {
 		chunkSize += int64(len(file.Content))
 	}
 
-	if chunkSize > MaxTotalPushSizeBytes {
+	maxBytes := CurrentPushLimits().MaxTotalPushSizeBytes
+	if chunkSize > maxBytes {
 		sizeMB := float64(chunkSize) / (1024 * 1024)
-		maxMB := float64(MaxTotalPushSizeBytes) / (1024 * 1024)
+		maxMB := float64(maxBytes) / (1024 * 1024)
 		return &ValidationError{
-			Code:    "CHUNK_TOO_LARGE",
-			Message: fmt.Sprintf("chunk size (%.2f MB) exceeds maximum of %.0f MB - this chunk contains %d files totaling too much data", sizeMB, maxMB, len(files)),
+			Code:       "CHUNK_TOO_LARGE",
+			Message:    fmt.Sprintf("chunk size (%.2f MB) exceeds maximum of %.0f MB - this chunk contains %d files totaling too much data", sizeMB, maxMB, len(files)),
 			Suggestion: "Reduce chunk_size parameter to use smaller chunks",
 			Details: map[string]interface{}{
 				"chunk_size_bytes": chunkSize,
 				"chunk_size_mb":    sizeMB,
-				"max_bytes":        MaxTotalPushSizeBytes,
+				"max_bytes":        maxBytes,
 				"max_mb":           maxMB,
 				"file_count":       len(files),
 			},
@@ -222,7 +931,7 @@ func ValidateChunkSize(files []FileEntry) error {
 
 // GetMaxChunkSize returns the maximum safe chunk size with safety margin
 func GetMaxChunkSize() int64 {
-	return int64(float64(MaxTotalPushSizeBytes) * ChunkSafetyMarginPercent)
+	return CurrentPushLimits().maxChunkBytes()
 }
 
 // FormatFileSize formats bytes as human-readable size
@@ -1,8 +1,18 @@
 package github
 
 import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"strings"
 	"testing"
+
+	"github.com/github/github-mcp-server/pkg/sanitize"
+	"github.com/google/go-github/v79/github"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
 func TestValidateFiles_Success(t *testing.T) {
@@ -40,6 +50,144 @@ func TestValidateFiles_Success(t *testing.T) {
 	}
 }
 
+func TestValidateFiles_Warnings(t *testing.T) {
+	deepPath := strings.Repeat("d/", maxPathDepthWarning+1) + "file.txt"
+	png := base64.StdEncoding.EncodeToString([]byte{0x89, 'P', 'N', 'G', 0x00, 0x01})
+	files := []interface{}{
+		map[string]interface{}{"path": "clean.txt", "content": "line 1\nline 2\n"},
+		map[string]interface{}{"path": "big.txt", "content": strings.Repeat("a", largeFileWarningBytes+1)},
+		map[string]interface{}{"path": deepPath, "content": "x"},
+		map[string]interface{}{"path": "windows.txt", "content": "line 1\r\nline 2\r\n"},
+		map[string]interface{}{"path": "logo.png", "content": "not really an image"},
+		map[string]interface{}{"path": "mangled.bin.txt", "content": "PK\uFFFD\uFFFD"},
+		map[string]interface{}{"path": "real.png", "content": png, "content_encoding": ContentEncodingBase64},
+	}
+
+	result, _, err := ValidateFiles(files)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	got := make(map[string][]string)
+	for _, warning := range result.Warnings {
+		got[warning.Path] = append(got[warning.Path], warning.Code)
+		if warning.Message == "" {
+			t.Errorf("expected a message for warning %s of %s", warning.Code, warning.Path)
+		}
+	}
+	expected := map[string][]string{
+		"big.txt":         {"LARGE_FILE"},
+		deepPath:          {"DEEP_PATH"},
+		"windows.txt":     {"CRLF_LINE_ENDINGS"},
+		"logo.png":        {"SUSPICIOUS_BINARY_CONTENT"},
+		"mangled.bin.txt": {"SUSPICIOUS_BINARY_CONTENT"},
+	}
+	if len(got) != len(expected) {
+		t.Errorf("expected warnings for %d files, got %v", len(expected), got)
+	}
+	for path, codes := range expected {
+		if fmt.Sprint(got[path]) != fmt.Sprint(codes) {
+			t.Errorf("expected warnings %v for %s, got %v", codes, path, got[path])
+		}
+	}
+}
+
+func TestValidateFilesWithOptions_LineEndings(t *testing.T) {
+	crlfBinary := base64.StdEncoding.EncodeToString([]byte{0x00, '\r', '\n'})
+	files := []interface{}{
+		map[string]interface{}{"path": "unix.txt", "content": "a\nb\n"},
+		map[string]interface{}{"path": "windows.txt", "content": "a\r\nb\r\n"},
+		map[string]interface{}{"path": "mixed.txt", "content": "a\r\nb\nc"},
+		map[string]interface{}{"path": "data.bin", "content": crlfBinary, "content_encoding": ContentEncodingBase64},
+	}
+
+	tests := []struct {
+		lineEndings        string
+		expectedContent    map[string]string
+		expectedNormalized []string
+	}{
+		{
+			lineEndings:     LineEndingsPreserve,
+			expectedContent: map[string]string{"unix.txt": "a\nb\n", "windows.txt": "a\r\nb\r\n", "mixed.txt": "a\r\nb\nc"},
+		},
+		{
+			lineEndings:        LineEndingsLF,
+			expectedContent:    map[string]string{"unix.txt": "a\nb\n", "windows.txt": "a\nb\n", "mixed.txt": "a\nb\nc"},
+			expectedNormalized: []string{"windows.txt", "mixed.txt"},
+		},
+		{
+			lineEndings:        LineEndingsCRLF,
+			expectedContent:    map[string]string{"unix.txt": "a\r\nb\r\n", "windows.txt": "a\r\nb\r\n", "mixed.txt": "a\r\nb\r\nc"},
+			expectedNormalized: []string{"unix.txt", "mixed.txt"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.lineEndings, func(t *testing.T) {
+			result, entries, err := ValidateFilesWithOptions(files, FileValidationOptions{LineEndings: tc.lineEndings})
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			for _, entry := range entries {
+				if entry.Binary {
+					if entry.Content != "\x00\r\n" {
+						t.Errorf("expected binary content to be left as is, got %q", entry.Content)
+					}
+					continue
+				}
+				if entry.Content != tc.expectedContent[entry.Path] {
+					t.Errorf("expected %q for %s, got %q", tc.expectedContent[entry.Path], entry.Path, entry.Content)
+				}
+			}
+			if fmt.Sprint(result.NormalizedFiles) != fmt.Sprint(tc.expectedNormalized) {
+				t.Errorf("expected normalized files %v, got %v", tc.expectedNormalized, result.NormalizedFiles)
+			}
+
+			var normalized, crlf []string
+			for _, warning := range result.Warnings {
+				switch warning.Code {
+				case "LINE_ENDINGS_NORMALIZED":
+					normalized = append(normalized, warning.Path)
+				case "CRLF_LINE_ENDINGS":
+					crlf = append(crlf, warning.Path)
+				}
+			}
+			if fmt.Sprint(normalized) != fmt.Sprint(tc.expectedNormalized) {
+				t.Errorf("expected LINE_ENDINGS_NORMALIZED warnings for %v, got %v", tc.expectedNormalized, normalized)
+			}
+			if tc.lineEndings != LineEndingsPreserve && len(crlf) > 0 {
+				t.Errorf("expected no CRLF_LINE_ENDINGS warnings once line endings are normalized, got %v", crlf)
+			}
+		})
+	}
+
+	if _, err := fileValidationOptionsFromArgs(map[string]any{"normalize_line_endings": "cr"}); err == nil {
+		t.Error("expected an error for an unknown line ending")
+	}
+}
+
+func TestWithValidationWarnings(t *testing.T) {
+	result := withValidationWarnings(&mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "{}"}}}, nil)
+	if len(result.Content) != 1 {
+		t.Fatalf("expected no content to be added without warnings, got %d items", len(result.Content))
+	}
+
+	warnings := []ValidationWarning{{Code: "CRLF_LINE_ENDINGS", Path: "a.txt", Message: "CRLF"}}
+	result = withValidationWarnings(result, warnings)
+	if len(result.Content) != 2 {
+		t.Fatalf("expected the warnings to be added as a second content, got %d items", len(result.Content))
+	}
+	var decoded struct {
+		Warnings []ValidationWarning `json:"warnings"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[1].(*mcp.TextContent).Text), &decoded); err != nil {
+		t.Fatalf("expected JSON warnings, got %v", err)
+	}
+	if len(decoded.Warnings) != 1 || decoded.Warnings[0] != warnings[0] {
+		t.Errorf("expected %v, got %v", warnings, decoded.Warnings)
+	}
+}
+
 func TestValidateFiles_DuplicatePaths(t *testing.T) {
 	files := []interface{}{
 		map[string]interface{}{
@@ -257,7 +405,7 @@ func TestValidateFileSize(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := ValidateFileSize(tt.path, tt.size)
+			result, err := ValidateFileSize(DefaultPushLimits(), tt.path, tt.size)
 			if tt.expectErr {
 				if result == nil && err == nil {
 					t.Error("expected error, got nil")
@@ -293,7 +441,7 @@ func TestValidateTotalSize(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := ValidateTotalSize(tt.totalSize)
+			result, err := ValidateTotalSize(DefaultPushLimits(), tt.totalSize)
 			if tt.expectErr {
 				if result == nil && err == nil {
 					t.Error("expected error, got nil")
@@ -435,6 +583,63 @@ func TestValidationError_Error(t *testing.T) {
 	}
 }
 
+func TestSanitizeFilePaths(t *testing.T) {
+	files := []FileEntry{
+		{Path: "docs/release notes.md", Content: "a"},
+		{Path: "src/main.go", Content: "b"},
+	}
+
+	renames, err := SanitizeFilePaths(files, sanitize.PathPolicyNormalize)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(renames) != 1 {
+		t.Fatalf("expected 1 rename, got %d", len(renames))
+	}
+	if renames["docs/release notes.md"] != "docs/release-notes.md" {
+		t.Errorf("unexpected rename: %v", renames)
+	}
+	if files[0].Path != "docs/release-notes.md" {
+		t.Errorf("expected file path to be rewritten in place, got %s", files[0].Path)
+	}
+	if files[1].Path != "src/main.go" {
+		t.Errorf("expected untouched path, got %s", files[1].Path)
+	}
+}
+
+func TestSanitizeFilePaths_Collision(t *testing.T) {
+	files := []FileEntry{
+		{Path: "notes.md.", Content: "a"},
+		{Path: "notes.md", Content: "b"},
+	}
+
+	_, err := SanitizeFilePaths(files, sanitize.PathPolicyNormalize)
+	if err == nil {
+		t.Fatal("expected collision error, got nil")
+	}
+
+	validationErr, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("expected ValidationError, got %T", err)
+	}
+	if validationErr.Code != "SANITIZED_PATH_COLLISION" {
+		t.Errorf("expected code SANITIZED_PATH_COLLISION, got %s", validationErr.Code)
+	}
+}
+
+func TestSanitizeFilePaths_None(t *testing.T) {
+	files := []FileEntry{{Path: "my file.txt", Content: "a"}}
+
+	renames, err := SanitizeFilePaths(files, sanitize.PathPolicyNone)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(renames) != 0 || files[0].Path != "my file.txt" {
+		t.Errorf("expected paths to be untouched with policy none")
+	}
+}
+
 func BenchmarkValidateFiles(b *testing.B) {
 	// Create a realistic set of files
 	files := make([]interface{}, 100)
@@ -450,3 +655,365 @@ func BenchmarkValidateFiles(b *testing.B) {
 		_, _, _ = ValidateFiles(files)
 	}
 }
+
+// largePayloadFiles is the number of files of the large payload benchmarks, which push 100MB
+const largePayloadFiles = 100
+
+// largePayload returns largePayloadFiles file objects of 1MB of content each, sent with encoding
+func largePayload(b *testing.B, line, encoding string) []interface{} {
+	b.Helper()
+	content := strings.Repeat(line, 1024*1024/len(line))
+	switch encoding {
+	case ContentEncodingBase64:
+		content = base64.StdEncoding.EncodeToString([]byte(content))
+	case ContentEncodingGzipBase64:
+		content = gzipBase64(b, content)
+	}
+
+	files := make([]interface{}, largePayloadFiles)
+	for i := range files {
+		files[i] = map[string]interface{}{
+			"path":             fmt.Sprintf("dir%d/file%d.txt", i%10, i),
+			"content":          content,
+			"content_encoding": encoding,
+		}
+	}
+	return files
+}
+
+func BenchmarkValidateFiles_LargePayload(b *testing.B) {
+	benchmarks := []struct {
+		name     string
+		line     string
+		encoding string
+		opts     FileValidationOptions
+	}{
+		{name: "text", line: "hello world, this is a line of text\n", encoding: ContentEncodingUTF8},
+		{name: "base64", line: "hello world, this is a line of text\n", encoding: ContentEncodingBase64},
+		{name: "base64 binary", line: "\x00\x01\x02\xff\xfe\xfd\x89PNG", encoding: ContentEncodingBase64},
+		{name: "gzip+base64", line: "hello world, this is a line of text\n", encoding: ContentEncodingGzipBase64},
+		{name: "crlf to lf", line: "hello world, this is a line of text\r\n", encoding: ContentEncodingUTF8, opts: FileValidationOptions{LineEndings: LineEndingsLF}},
+		{name: "lf to crlf", line: "hello world, this is a line of text\n", encoding: ContentEncodingUTF8, opts: FileValidationOptions{LineEndings: LineEndingsCRLF}},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			files := largePayload(b, bm.line, bm.encoding)
+			b.SetBytes(int64(largePayloadFiles * 1024 * 1024))
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, _, err := ValidateFilesWithOptions(files, bm.opts); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeBase64Content(t *testing.T) {
+	block := strings.Repeat("QUJD", base64BlockSize/4)
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "empty", content: ""},
+		{name: "padded", content: "QQ=="},
+		{name: "several blocks", content: block + block + "QUI="},
+		{name: "line breaks", content: "QUJD\r\nQUJD\n"},
+		{name: "invalid character in a later block", content: block + "QU!D"},
+		{name: "padding between blocks", content: block[:len(block)-4] + "QQ==" + block},
+		{name: "truncated", content: block + "QUJ"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			expected, expectedErr := base64.StdEncoding.DecodeString(tc.content)
+			decoded, err := decodeBase64Content(tc.content)
+			if fmt.Sprint(err) != fmt.Sprint(expectedErr) {
+				t.Fatalf("expected error %v, got %v", expectedErr, err)
+			}
+			if err == nil && decoded != string(expected) {
+				t.Errorf("expected %d decoded bytes matching DecodeString, got %d", len(expected), len(decoded))
+			}
+		})
+	}
+}
+
+func TestEncodeBlobContent(t *testing.T) {
+	for _, size := range []int{0, 1, 2, 3, base64BlockSize - 1, base64BlockSize, base64BlockSize + 1, 3*base64BlockSize + 2} {
+		content := strings.Repeat("\x00\xffab\n", size/5+1)[:size]
+		if got, expected := encodeBlobContent(content), base64.StdEncoding.EncodeToString([]byte(content)); got != expected {
+			t.Errorf("size %d: expected %d encoded bytes matching EncodeToString, got %d", size, len(expected), len(got))
+		}
+	}
+}
+
+func BenchmarkEncodeBlobContent(b *testing.B) {
+	content := strings.Repeat("\x00\x01\x02\xff\xfe\xfd\x89PNG", 100*1024*1024/10)
+	b.SetBytes(int64(len(content)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = encodeBlobContent(content)
+	}
+}
+
+func gzipBase64(t testing.TB, content string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write([]byte(content)); err != nil {
+		t.Fatalf("failed to compress content: %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("failed to compress content: %v", err)
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+func TestValidateFiles_GzipBase64(t *testing.T) {
+	content := strings.Repeat("hello world\n", 1000)
+	files := []interface{}{
+		map[string]interface{}{
+			"path":             "big.txt",
+			"content":          gzipBase64(t, content),
+			"content_encoding": "gzip+base64",
+		},
+		map[string]interface{}{
+			"path":             "plain.txt",
+			"content":          "plain",
+			"content_encoding": "utf-8",
+		},
+	}
+
+	result, entries, err := ValidateFiles(files)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if entries[0].Content != content {
+		t.Errorf("expected decompressed content, got %d bytes", len(entries[0].Content))
+	}
+	if entries[1].Content != "plain" {
+		t.Errorf("expected plain content to be unchanged, got %q", entries[1].Content)
+	}
+
+	// Sizes are measured after decompression
+	expectedSize := int64(len(content) + len("plain"))
+	if result.TotalSize != expectedSize {
+		t.Errorf("expected total size %d, got %d", expectedSize, result.TotalSize)
+	}
+	if result.LargestFile != "big.txt" {
+		t.Errorf("expected largest file big.txt, got %s", result.LargestFile)
+	}
+}
+
+func TestValidateFiles_GzipBase64Oversized(t *testing.T) {
+	files := []interface{}{
+		map[string]interface{}{
+			"path":             "huge.bin",
+			"content":          gzipBase64(t, strings.Repeat("a", MaxFileSizeBytes+10)),
+			"content_encoding": "gzip+base64",
+		},
+	}
+
+	result, _, err := ValidateFiles(files)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(result.OversizedFiles) != 1 || result.OversizedFiles[0] != "huge.bin" {
+		t.Errorf("expected huge.bin to be reported as oversized, got %v", result.OversizedFiles)
+	}
+}
+
+func TestValidateFiles_GzipBase64DecompressedTotal(t *testing.T) {
+	t.Cleanup(func() { SetPushLimits(PushLimits{}) })
+	SetPushLimits(PushLimits{MaxTotalPushSizeBytes: 1024 * 1024, MaxFileSizeBytes: 512 * 1024})
+
+	// Each file is within the per-file limit, while together they inflate past the total
+	content := gzipBase64(t, strings.Repeat("a", 400*1024))
+	files := []interface{}{
+		map[string]interface{}{"path": "a.txt", "content": content, "content_encoding": "gzip+base64"},
+		map[string]interface{}{"path": "b.txt", "content": content, "content_encoding": "gzip+base64"},
+		map[string]interface{}{"path": "c.txt", "content": content, "content_encoding": "gzip+base64"},
+	}
+
+	_, _, err := ValidateFiles(files)
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Code != "DECOMPRESSED_CONTENT_TOO_LARGE" {
+		t.Fatalf("expected DECOMPRESSED_CONTENT_TOO_LARGE, got %v", err)
+	}
+	if validationErr.Details["path"] != "c.txt" {
+		t.Errorf("expected the third file to exceed the total, got %v", validationErr.Details["path"])
+	}
+
+	if _, _, err := ValidateFiles(files[:2]); err != nil {
+		t.Errorf("expected no error within the total, got %v", err)
+	}
+}
+
+func TestValidateFiles_InvalidContentEncoding(t *testing.T) {
+	tests := []struct {
+		name         string
+		content      string
+		encoding     string
+		expectedCode string
+	}{
+		{name: "unsupported encoding", content: "x", encoding: "zstd", expectedCode: "UNSUPPORTED_CONTENT_ENCODING"},
+		{name: "invalid base64", content: "not base64!", encoding: "gzip+base64", expectedCode: "INVALID_COMPRESSED_CONTENT"},
+		{name: "not gzip", content: base64.StdEncoding.EncodeToString([]byte("plain")), encoding: "gzip+base64", expectedCode: "INVALID_COMPRESSED_CONTENT"},
+		{name: "invalid plain base64", content: "not base64!", encoding: "base64", expectedCode: "INVALID_BASE64_CONTENT"},
+		{name: "invalid UTF-8", content: "ok\xff", encoding: "utf-8", expectedCode: "INVALID_CONTENT_ENCODING"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			files := []interface{}{
+				map[string]interface{}{
+					"path":             "file.txt",
+					"content":          tc.content,
+					"content_encoding": tc.encoding,
+				},
+			}
+
+			_, _, err := ValidateFiles(files)
+			validationErr, ok := err.(*ValidationError)
+			if !ok {
+				t.Fatalf("expected ValidationError, got %T (%v)", err, err)
+			}
+			if validationErr.Code != tc.expectedCode {
+				t.Errorf("expected code %s, got %s", tc.expectedCode, validationErr.Code)
+			}
+		})
+	}
+}
+
+func TestValidateFiles_TextContent(t *testing.T) {
+	tests := []struct {
+		name           string
+		file           map[string]interface{}
+		expectedOffset int
+		expectedBinary bool
+	}{
+		{name: "plain text", file: map[string]interface{}{"content": "line\tone\r\n\x1b[1mbold\x1b[0m\n"}, expectedOffset: -1},
+		{name: "invalid UTF-8", file: map[string]interface{}{"content": "héllo\xc3("}, expectedOffset: 6},
+		{name: "NUL byte", file: map[string]interface{}{"content": "abc\x00def"}, expectedOffset: 3},
+		{name: "upload content without encoding", file: map[string]interface{}{"content": "\x07bell"}, expectedOffset: 0},
+		{name: "base64 binary", file: map[string]interface{}{"content": base64.StdEncoding.EncodeToString([]byte{0x89, 'P', 'N', 'G', 0x00}), "content_encoding": "base64"}, expectedOffset: -1, expectedBinary: true},
+		{name: "base64 text", file: map[string]interface{}{"content": base64.StdEncoding.EncodeToString([]byte("text\n")), "content_encoding": "base64"}, expectedOffset: -1},
+		{name: "gzip binary", file: map[string]interface{}{"content": gzipBase64(t, "a\x00b"), "content_encoding": "gzip+base64"}, expectedOffset: -1, expectedBinary: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.file["path"] = "assets/file"
+			_, entries, err := ValidateFiles([]interface{}{tc.file})
+
+			if tc.expectedOffset >= 0 {
+				validationErr, ok := err.(*ValidationError)
+				if !ok {
+					t.Fatalf("expected ValidationError, got %T (%v)", err, err)
+				}
+				if validationErr.Code != "INVALID_CONTENT_ENCODING" {
+					t.Errorf("expected code INVALID_CONTENT_ENCODING, got %s", validationErr.Code)
+				}
+				if validationErr.Details["byte_offset"] != tc.expectedOffset {
+					t.Errorf("expected byte offset %d, got %v", tc.expectedOffset, validationErr.Details["byte_offset"])
+				}
+				if validationErr.Details["path"] != "assets/file" {
+					t.Errorf("expected path assets/file, got %v", validationErr.Details["path"])
+				}
+				if !strings.Contains(validationErr.Suggestion, "base64") {
+					t.Errorf("expected suggestion to mention base64, got %q", validationErr.Suggestion)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if entries[0].Binary != tc.expectedBinary {
+				t.Errorf("expected binary %v, got %v", tc.expectedBinary, entries[0].Binary)
+			}
+		})
+	}
+}
+
+func TestValidationErrorResult(t *testing.T) {
+	err := &ValidationError{
+		Code:       "DUPLICATE_FILE_PATHS",
+		Message:    `duplicate file path "a<b>.txt"`,
+		Suggestion: "Remove duplicate entries",
+		Details:    map[string]interface{}{"indices": []int{0, 2}},
+	}
+
+	for name, result := range map[string]*mcp.CallToolResult{
+		"validation error": validationErrorResult(err),
+		"wrapped error":    toolErrorResult(fmt.Errorf("validating: %w", err)),
+	} {
+		t.Run(name, func(t *testing.T) {
+			if !result.IsError {
+				t.Fatal("expected an error result")
+			}
+			text := result.Content[0].(*mcp.TextContent).Text
+			var decoded map[string]interface{}
+			if err := json.Unmarshal([]byte(text), &decoded); err != nil {
+				t.Fatalf("expected JSON text, got %q: %v", text, err)
+			}
+			if decoded["code"] != "DUPLICATE_FILE_PATHS" || decoded["message"] != err.Message || decoded["suggestion"] != err.Suggestion {
+				t.Errorf("unexpected error object %v", decoded)
+			}
+			if !strings.Contains(text, "a<b>.txt") {
+				t.Errorf("expected unescaped message in %q", text)
+			}
+			structured, ok := result.StructuredContent.(map[string]any)
+			if !ok || structured["code"] != "DUPLICATE_FILE_PATHS" {
+				t.Errorf("unexpected structured content %v", result.StructuredContent)
+			}
+		})
+	}
+
+	prefixed := prefixedToolErrorResult("overrides for branch main", err)
+	if structured := prefixed.StructuredContent.(map[string]any); structured["message"] != `overrides for branch main: duplicate file path "a<b>.txt"` {
+		t.Errorf("unexpected prefixed message %v", structured["message"])
+	}
+
+	plain := toolErrorResult(errors.New("files parameter must be an array"))
+	if text := plain.Content[0].(*mcp.TextContent).Text; text != "files parameter must be an array" || plain.StructuredContent != nil {
+		t.Errorf("expected plain text error, got %q", text)
+	}
+}
+
+func TestValidationError_IsAndUnwrap(t *testing.T) {
+	_, _, err := ValidateFiles([]interface{}{
+		map[string]interface{}{"path": "a.txt", "content": "a"},
+		map[string]interface{}{"path": "a.txt", "content": "b"},
+	})
+	if !errors.Is(err, ErrDuplicatePath) {
+		t.Errorf("expected errors.Is(err, ErrDuplicatePath), got %v", err)
+	}
+	if errors.Is(err, ErrFileTooLarge) {
+		t.Error("expected a duplicate path error not to match ErrFileTooLarge")
+	}
+
+	_, sizeErr := ValidateFileSize(CurrentPushLimits(), "big.bin", CurrentPushLimits().MaxFileSizeBytes+1)
+	if wrapped := fmt.Errorf("pushing: %w", sizeErr); !errors.Is(wrapped, ErrFileTooLarge) {
+		t.Errorf("expected wrapped error to match ErrFileTooLarge, got %v", wrapped)
+	}
+
+	_, err = decodeFileContent("a.bin", "not base64!", ContentEncodingBase64, MaxFileSizeBytes)
+	if !errors.Is(err, ErrInvalidEncodedContent) {
+		t.Errorf("expected errors.Is(err, ErrInvalidEncodedContent), got %v", err)
+	}
+	var corrupt base64.CorruptInputError
+	if !errors.As(err, &corrupt) {
+		t.Errorf("expected the base64 error as the cause, got %v", err)
+	}
+
+	apiErr := &github.ErrorResponse{Message: "Git Repository is empty."}
+	err = emptyRepositoryError("owner", "repo", apiErr)
+	var errResp *github.ErrorResponse
+	if !errors.Is(err, ErrEmptyRepository) || !errors.As(err, &errResp) || errResp != apiErr {
+		t.Errorf("expected an empty repository error wrapping the API error, got %v", err)
+	}
+}
@@ -0,0 +1,302 @@
+package github
+
+import (
+	"context"
+	"fmt"
+
+	ghErrors "github.com/github/github-mcp-server/pkg/errors"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/github/github-mcp-server/pkg/utils"
+	"github.com/google/go-github/v79/github"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Watch levels of a repository, as offered by the Watch menu on GitHub
+const (
+	// WatchLevelAll notifies about all activity of the repository
+	WatchLevelAll = "all"
+	// WatchLevelParticipating only notifies about threads the user participates in or is
+	// mentioned in, which is the level of repositories that are not watched
+	WatchLevelParticipating = "participating"
+	// WatchLevelIgnore never notifies, not even about mentions
+	WatchLevelIgnore = "ignore"
+)
+
+// RepositoryWatchStatus reports whether the authenticated user stars and watches a repository
+type RepositoryWatchStatus struct {
+	Repository string `json:"repository"`
+	Starred    bool   `json:"starred"`
+	WatchLevel string `json:"watch_level"`
+}
+
+// watchLevelOf returns the watch level of a repository subscription, which is nil for
+// repositories that are not watched.
+func watchLevelOf(subscription *github.Subscription) string {
+	switch {
+	case subscription.GetIgnored():
+		return WatchLevelIgnore
+	case subscription.GetSubscribed():
+		return WatchLevelAll
+	default:
+		return WatchLevelParticipating
+	}
+}
+
+// WatchRepository creates a tool to set the watch level of a repository.
+func WatchRepository(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "watch_repository",
+		Description: t("TOOL_WATCH_REPOSITORY_DESCRIPTION", "Watch a GitHub repository at a subscription level: 'all' to be notified of all activity, 'participating' to only be notified of threads you participate in or are mentioned in (the same as unwatching), or 'ignore' to never be notified."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_WATCH_REPOSITORY_USER_TITLE", "Watch repository"),
+			ReadOnlyHint: false,
+		},
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"owner": {
+					Type:        "string",
+					Description: "Repository owner",
+				},
+				"repo": {
+					Type:        "string",
+					Description: "Repository name",
+				},
+				"level": {
+					Type:        "string",
+					Description: "Subscription level (default: all)",
+					Enum:        []any{WatchLevelAll, WatchLevelParticipating, WatchLevelIgnore},
+				},
+			},
+			Required: []string{"owner", "repo"},
+		},
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		repo, err := RequiredParam[string](args, "repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		level, err := OptionalParam[string](args, "level")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		var subscription *github.Subscription
+		switch level {
+		case "", WatchLevelAll:
+			level = WatchLevelAll
+			subscription = &github.Subscription{Subscribed: github.Ptr(true), Ignored: github.Ptr(false)}
+		case WatchLevelIgnore:
+			subscription = &github.Subscription{Subscribed: github.Ptr(false), Ignored: github.Ptr(true)}
+		case WatchLevelParticipating:
+		default:
+			return utils.NewToolResultError(fmt.Sprintf("level must be one of %s, %s or %s", WatchLevelAll, WatchLevelParticipating, WatchLevelIgnore)), nil, nil
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		// Repositories are watched at the participating level when there is no subscription
+		var resp *github.Response
+		if subscription == nil {
+			resp, err = client.Activity.DeleteRepositorySubscription(ctx, owner, repo)
+		} else {
+			_, resp, err = client.Activity.SetRepositorySubscription(ctx, owner, repo, subscription)
+		}
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx,
+				fmt.Sprintf("failed to watch repository %s/%s", owner, repo),
+				resp,
+				err,
+			), nil, nil
+		}
+		_ = resp.Body.Close()
+
+		return utils.NewToolResultText(fmt.Sprintf("Watching repository %s/%s at level %s", owner, repo, level)), nil, nil
+	})
+
+	return tool, handler
+}
+
+// UnwatchRepository creates a tool to stop watching a repository.
+func UnwatchRepository(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "unwatch_repository",
+		Description: t("TOOL_UNWATCH_REPOSITORY_DESCRIPTION", "Stop watching a GitHub repository, or stop ignoring it. Afterwards you are only notified of threads you participate in or are mentioned in."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_UNWATCH_REPOSITORY_USER_TITLE", "Unwatch repository"),
+			ReadOnlyHint: false,
+		},
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"owner": {
+					Type:        "string",
+					Description: "Repository owner",
+				},
+				"repo": {
+					Type:        "string",
+					Description: "Repository name",
+				},
+			},
+			Required: []string{"owner", "repo"},
+		},
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		repo, err := RequiredParam[string](args, "repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		resp, err := client.Activity.DeleteRepositorySubscription(ctx, owner, repo)
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx,
+				fmt.Sprintf("failed to unwatch repository %s/%s", owner, repo),
+				resp,
+				err,
+			), nil, nil
+		}
+		_ = resp.Body.Close()
+
+		return utils.NewToolResultText(fmt.Sprintf("Successfully unwatched repository %s/%s", owner, repo)), nil, nil
+	})
+
+	return tool, handler
+}
+
+// GetRepositoryWatchStatus creates a tool to check whether the authenticated user stars and
+// watches a repository.
+func GetRepositoryWatchStatus(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "get_repository_watch_status",
+		Description: t("TOOL_GET_REPOSITORY_WATCH_STATUS_DESCRIPTION", "Check whether you have starred a GitHub repository and at which level you watch it: all, participating (not watched) or ignore."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_GET_REPOSITORY_WATCH_STATUS_USER_TITLE", "Get repository star and watch status"),
+			ReadOnlyHint: true,
+		},
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"owner": {
+					Type:        "string",
+					Description: "Repository owner",
+				},
+				"repo": {
+					Type:        "string",
+					Description: "Repository name",
+				},
+			},
+			Required: []string{"owner", "repo"},
+		},
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		repo, err := RequiredParam[string](args, "repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		starred, resp, err := client.Activity.IsStarred(ctx, owner, repo)
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to check if repository is starred", resp, err), nil, nil
+		}
+		_ = resp.Body.Close()
+
+		// A repository without subscription is reported as nil, which is the participating level
+		subscription, resp, err := client.Activity.GetRepositorySubscription(ctx, owner, repo)
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to get repository subscription", resp, err), nil, nil
+		}
+		_ = resp.Body.Close()
+
+		return MarshalledTextResult(RepositoryWatchStatus{
+			Repository: fmt.Sprintf("%s/%s", owner, repo),
+			Starred:    starred,
+			WatchLevel: watchLevelOf(subscription),
+		}), nil, nil
+	})
+
+	return tool, handler
+}
+
+// ListWatchedRepositories creates a tool to list the repositories watched by the authenticated
+// user or a specified user.
+func ListWatchedRepositories(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "list_watched_repositories",
+		Description: t("TOOL_LIST_WATCHED_REPOSITORIES_DESCRIPTION", "List the repositories watched at the 'all' or 'ignore' level, which are the repositories with a subscription."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_LIST_WATCHED_REPOSITORIES_USER_TITLE", "List watched repositories"),
+			ReadOnlyHint: true,
+		},
+		InputSchema: WithPagination(&jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"username": {
+					Type:        "string",
+					Description: "Username to list watched repositories for. Defaults to the authenticated user.",
+				},
+			},
+		}),
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		username, err := OptionalParam[string](args, "username")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		pagination, err := OptionalPaginationParams(args)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		repos, resp, err := client.Activity.ListWatched(ctx, username, &github.ListOptions{
+			Page:    pagination.Page,
+			PerPage: pagination.PerPage,
+		})
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to list watched repositories", resp, err), nil, nil
+		}
+		_ = resp.Body.Close()
+
+		minimalRepos := make([]MinimalRepository, 0, len(repos))
+		for _, repo := range repos {
+			minimalRepos = append(minimalRepos, convertToMinimalRepository(repo))
+		}
+		return MarshalledTextResult(minimalRepos), nil, nil
+	})
+
+	return tool, handler
+}
@@ -0,0 +1,215 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v79/github"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_WatchRepository(t *testing.T) {
+	tool, _ := WatchRepository(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.False(t, tool.Annotations.ReadOnlyHint)
+
+	noContent := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusNoContent) })
+
+	tests := []struct {
+		name         string
+		args         map[string]any
+		mockedClient *http.Client
+		expectError  string
+		expectText   string
+	}{
+		{
+			name: "all activity by default",
+			args: map[string]any{"owner": "owner", "repo": "repo"},
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.PutReposSubscriptionByOwnerByRepo,
+					expectRequestBody(t, map[string]any{"subscribed": true, "ignored": false}).andThen(
+						mockResponse(t, http.StatusOK, &github.Subscription{Subscribed: github.Ptr(true)}),
+					),
+				),
+			),
+			expectText: "Watching repository owner/repo at level all",
+		},
+		{
+			name: "ignore",
+			args: map[string]any{"owner": "owner", "repo": "repo", "level": "ignore"},
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.PutReposSubscriptionByOwnerByRepo,
+					expectRequestBody(t, map[string]any{"subscribed": false, "ignored": true}).andThen(
+						mockResponse(t, http.StatusOK, &github.Subscription{Ignored: github.Ptr(true)}),
+					),
+				),
+			),
+			expectText: "Watching repository owner/repo at level ignore",
+		},
+		{
+			name: "participating removes the subscription",
+			args: map[string]any{"owner": "owner", "repo": "repo", "level": "participating"},
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(mock.DeleteReposSubscriptionByOwnerByRepo, noContent),
+			),
+			expectText: "Watching repository owner/repo at level participating",
+		},
+		{
+			name:        "invalid level",
+			args:        map[string]any{"owner": "owner", "repo": "repo", "level": "releases"},
+			expectError: "level must be one of all, participating or ignore",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, handler := WatchRepository(stubGetClientFn(github.NewClient(tc.mockedClient)), translations.NullTranslationHelper)
+			request := createMCPRequest(tc.args)
+			result, _, err := handler(context.Background(), &request, tc.args)
+			require.NoError(t, err)
+
+			if tc.expectError != "" {
+				require.True(t, result.IsError)
+				assert.Contains(t, getErrorResult(t, result).Text, tc.expectError)
+				return
+			}
+			require.False(t, result.IsError, getTextResult(t, result).Text)
+			assert.Equal(t, tc.expectText, getTextResult(t, result).Text)
+		})
+	}
+}
+
+func Test_UnwatchRepository(t *testing.T) {
+	tool, _ := UnwatchRepository(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.DeleteReposSubscriptionByOwnerByRepo,
+			expectPath(t, "/repos/owner/repo/subscription").andThen(
+				http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusNoContent) }),
+			),
+		),
+	)
+	_, handler := UnwatchRepository(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+	args := map[string]any{"owner": "owner", "repo": "repo"}
+	request := createMCPRequest(args)
+	result, _, err := handler(context.Background(), &request, args)
+	require.NoError(t, err)
+	require.False(t, result.IsError, getTextResult(t, result).Text)
+	assert.Equal(t, "Successfully unwatched repository owner/repo", getTextResult(t, result).Text)
+}
+
+func Test_GetRepositoryWatchStatus(t *testing.T) {
+	tool, _ := GetRepositoryWatchStatus(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.True(t, tool.Annotations.ReadOnlyHint)
+
+	notFound := mockResponse(t, http.StatusNotFound, map[string]string{"message": "Not Found"})
+	noContent := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusNoContent) })
+
+	tests := []struct {
+		name         string
+		mockedClient *http.Client
+		expected     RepositoryWatchStatus
+	}{
+		{
+			name: "starred and watched",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(mock.GetUserStarredByOwnerByRepo, noContent),
+				mock.WithRequestMatchHandler(mock.GetReposSubscriptionByOwnerByRepo, mockResponse(t, http.StatusOK, &github.Subscription{Subscribed: github.Ptr(true), Ignored: github.Ptr(false)})),
+			),
+			expected: RepositoryWatchStatus{Repository: "owner/repo", Starred: true, WatchLevel: WatchLevelAll},
+		},
+		{
+			name: "ignored",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(mock.GetUserStarredByOwnerByRepo, notFound),
+				mock.WithRequestMatchHandler(mock.GetReposSubscriptionByOwnerByRepo, mockResponse(t, http.StatusOK, &github.Subscription{Subscribed: github.Ptr(false), Ignored: github.Ptr(true)})),
+			),
+			expected: RepositoryWatchStatus{Repository: "owner/repo", WatchLevel: WatchLevelIgnore},
+		},
+		{
+			name: "not watched",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(mock.GetUserStarredByOwnerByRepo, notFound),
+				mock.WithRequestMatchHandler(mock.GetReposSubscriptionByOwnerByRepo, notFound),
+			),
+			expected: RepositoryWatchStatus{Repository: "owner/repo", WatchLevel: WatchLevelParticipating},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, handler := GetRepositoryWatchStatus(stubGetClientFn(github.NewClient(tc.mockedClient)), translations.NullTranslationHelper)
+			args := map[string]any{"owner": "owner", "repo": "repo"}
+			request := createMCPRequest(args)
+			result, _, err := handler(context.Background(), &request, args)
+			require.NoError(t, err)
+			require.False(t, result.IsError, getTextResult(t, result).Text)
+
+			var response RepositoryWatchStatus
+			require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &response))
+			assert.Equal(t, tc.expected, response)
+		})
+	}
+}
+
+func Test_ListWatchedRepositories(t *testing.T) {
+	tool, _ := ListWatchedRepositories(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.True(t, tool.Annotations.ReadOnlyHint)
+
+	repos := []*github.Repository{{ID: github.Ptr(int64(1)), Name: github.Ptr("repo"), FullName: github.Ptr("owner/repo"), StargazersCount: github.Ptr(3)}}
+	tests := []struct {
+		name         string
+		args         map[string]any
+		mockedClient *http.Client
+	}{
+		{
+			name: "authenticated user",
+			args: map[string]any{},
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.GetUserSubscriptions,
+					expectQueryParams(t, map[string]string{"page": "1", "per_page": "30"}).andThen(mockResponse(t, http.StatusOK, repos)),
+				),
+			),
+		},
+		{
+			name: "other user",
+			args: map[string]any{"username": "octocat", "page": float64(2)},
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.GetUsersSubscriptionsByUsername,
+					expectPath(t, "/users/octocat/subscriptions").andThen(mockResponse(t, http.StatusOK, repos)),
+				),
+			),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, handler := ListWatchedRepositories(stubGetClientFn(github.NewClient(tc.mockedClient)), translations.NullTranslationHelper)
+			request := createMCPRequest(tc.args)
+			result, _, err := handler(context.Background(), &request, tc.args)
+			require.NoError(t, err)
+			require.False(t, result.IsError, getTextResult(t, result).Text)
+
+			var response []MinimalRepository
+			require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &response))
+			require.Len(t, response, 1)
+			assert.Equal(t, "owner/repo", response[0].FullName)
+			assert.Equal(t, 3, response[0].Stars)
+		})
+	}
+}
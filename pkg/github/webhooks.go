@@ -0,0 +1,534 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	ghErrors "github.com/github/github-mcp-server/pkg/errors"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/github/github-mcp-server/pkg/utils"
+	"github.com/google/go-github/v79/github"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	// DefaultWebhookDeliveriesScanned is how many recent deliveries are inspected per hook by default
+	DefaultWebhookDeliveriesScanned = 100
+	// MaxWebhookDeliveriesScanned caps how many deliveries are inspected per hook
+	MaxWebhookDeliveriesScanned = 500
+	// webhookResponseExcerptLength is the maximum length of a response body excerpt in an analysis
+	webhookResponseExcerptLength = 200
+)
+
+// MinimalWebhook is the output type for repository webhooks.
+type MinimalWebhook struct {
+	ID          int64    `json:"id"`
+	Name        string   `json:"name"`
+	URL         string   `json:"url,omitempty"`
+	ContentType string   `json:"content_type,omitempty"`
+	Events      []string `json:"events"`
+	Active      bool     `json:"active"`
+	UpdatedAt   string   `json:"updated_at,omitempty"`
+}
+
+// WebhookFailureSample describes one failed delivery in detail.
+type WebhookFailureSample struct {
+	DeliveryID      int64  `json:"delivery_id"`
+	GUID            string `json:"guid"`
+	Event           string `json:"event"`
+	StatusCode      int    `json:"status_code"`
+	Status          string `json:"status"`
+	DeliveredAt     string `json:"delivered_at,omitempty"`
+	Redelivery      bool   `json:"redelivery"`
+	ResponseExcerpt string `json:"response_excerpt,omitempty"`
+}
+
+// WebhookFailureAnalysis aggregates the recent failed deliveries of a single hook.
+type WebhookFailureAnalysis struct {
+	HookID            int64                  `json:"hook_id"`
+	URL               string                 `json:"url,omitempty"`
+	Active            bool                   `json:"active"`
+	DeliveriesScanned int                    `json:"deliveries_scanned"`
+	FailedDeliveries  int                    `json:"failed_deliveries"`
+	FailureRate       float64                `json:"failure_rate"`
+	StatusCodes       map[string]int         `json:"status_codes,omitempty"`
+	Events            map[string]int         `json:"events,omitempty"`
+	LastFailureAt     string                 `json:"last_failure_at,omitempty"`
+	LastSuccessAt     string                 `json:"last_success_at,omitempty"`
+	Samples           []WebhookFailureSample `json:"samples,omitempty"`
+	Suggestions       []string               `json:"suggestions,omitempty"`
+}
+
+func convertToMinimalWebhook(hook *github.Hook) MinimalWebhook {
+	m := MinimalWebhook{
+		ID:     hook.GetID(),
+		Name:   hook.GetName(),
+		Events: hook.Events,
+		Active: hook.GetActive(),
+	}
+	if hook.Config != nil {
+		m.URL = hook.Config.GetURL()
+		m.ContentType = hook.Config.GetContentType()
+	}
+	if hook.UpdatedAt != nil {
+		m.UpdatedAt = hook.UpdatedAt.Format(time.RFC3339)
+	}
+	return m
+}
+
+// ListRepositoryWebhooks creates a tool to list the webhooks configured on a repository.
+func ListRepositoryWebhooks(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "list_repository_webhooks",
+		Description: t("TOOL_LIST_REPOSITORY_WEBHOOKS_DESCRIPTION", "List webhooks configured on a GitHub repository. Requires admin access to the repository."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_LIST_REPOSITORY_WEBHOOKS_USER_TITLE", "List repository webhooks"),
+			ReadOnlyHint: true,
+		},
+		InputSchema: WithPagination(&jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"owner": {
+					Type:        "string",
+					Description: "Repository owner",
+				},
+				"repo": {
+					Type:        "string",
+					Description: "Repository name",
+				},
+			},
+			Required: []string{"owner", "repo"},
+		}),
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		repo, err := RequiredParam[string](args, "repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		pagination, err := OptionalPaginationParams(args)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		hooks, resp, err := client.Repositories.ListHooks(ctx, owner, repo, &github.ListOptions{
+			Page:    pagination.Page,
+			PerPage: pagination.PerPage,
+		})
+		if err != nil {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to list repository webhooks", resp, err), nil, nil
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		minimalHooks := make([]MinimalWebhook, 0, len(hooks))
+		for _, hook := range hooks {
+			minimalHooks = append(minimalHooks, convertToMinimalWebhook(hook))
+		}
+
+		return MarshalledTextResult(minimalHooks), nil, nil
+	})
+
+	return tool, handler
+}
+
+// AnalyzeWebhookFailures creates a tool that aggregates recent failed webhook deliveries per hook
+// and suggests likely fixes.
+func AnalyzeWebhookFailures(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "analyze_webhook_failures",
+		Description: t("TOOL_ANALYZE_WEBHOOK_FAILURES_DESCRIPTION", "Analyze recent failed deliveries of a repository's webhooks. For each hook, reports failure counts by status code and event, excerpts of the receiver's responses for a few failed deliveries, and suggested fixes. Failed deliveries can then be retried with redeliver_webhook_delivery."),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_ANALYZE_WEBHOOK_FAILURES_USER_TITLE", "Analyze webhook delivery failures"),
+			ReadOnlyHint: true,
+		},
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"owner": {
+					Type:        "string",
+					Description: "Repository owner",
+				},
+				"repo": {
+					Type:        "string",
+					Description: "Repository name",
+				},
+				"hook_id": {
+					Type:        "number",
+					Description: "Only analyze this webhook. Defaults to all webhooks of the repository",
+				},
+				"max_deliveries": {
+					Type:        "number",
+					Description: fmt.Sprintf("Number of recent deliveries to inspect per webhook (default: %d, max: %d)", DefaultWebhookDeliveriesScanned, MaxWebhookDeliveriesScanned),
+					Minimum:     jsonschema.Ptr(1.0),
+					Maximum:     jsonschema.Ptr(float64(MaxWebhookDeliveriesScanned)),
+				},
+				"samples": {
+					Type:        "number",
+					Description: "Number of failed deliveries per webhook to fetch response excerpts for (default: 3, max: 10)",
+					Minimum:     jsonschema.Ptr(0.0),
+					Maximum:     jsonschema.Ptr(10.0),
+				},
+			},
+			Required: []string{"owner", "repo"},
+		},
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		repo, err := RequiredParam[string](args, "repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		hookID, err := OptionalIntParam(args, "hook_id")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		maxDeliveries, err := OptionalIntParamWithDefault(args, "max_deliveries", DefaultWebhookDeliveriesScanned)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		maxDeliveries = min(max(maxDeliveries, 1), MaxWebhookDeliveriesScanned)
+		samples, err := OptionalIntParamWithDefault(args, "samples", 3)
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		samples = min(max(samples, 0), 10)
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		var hooks []*github.Hook
+		if hookID != 0 {
+			hook, resp, err := client.Repositories.GetHook(ctx, owner, repo, int64(hookID))
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to get repository webhook", resp, err), nil, nil
+			}
+			_ = resp.Body.Close()
+			hooks = append(hooks, hook)
+		} else {
+			opts := &github.ListOptions{PerPage: 100}
+			for {
+				page, resp, err := client.Repositories.ListHooks(ctx, owner, repo, opts)
+				if err != nil {
+					return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to list repository webhooks", resp, err), nil, nil
+				}
+				_ = resp.Body.Close()
+				hooks = append(hooks, page...)
+				if resp.NextPage == 0 {
+					break
+				}
+				opts.Page = resp.NextPage
+			}
+		}
+
+		analyses := make([]WebhookFailureAnalysis, 0, len(hooks))
+		for _, hook := range hooks {
+			deliveries, resp, err := listRecentHookDeliveries(ctx, client, owner, repo, hook.GetID(), maxDeliveries)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx, fmt.Sprintf("failed to list deliveries for webhook %d", hook.GetID()), resp, err), nil, nil
+			}
+
+			analysis := analyzeHookDeliveries(hook, deliveries)
+
+			// Fetch the receiver's response for the most recent failures
+			for _, delivery := range deliveries {
+				if len(analysis.Samples) >= samples {
+					break
+				}
+				if !isFailedDelivery(delivery) {
+					continue
+				}
+				detail, resp, err := client.Repositories.GetHookDelivery(ctx, owner, repo, hook.GetID(), delivery.GetID())
+				if err != nil {
+					return ghErrors.NewGitHubAPIErrorResponse(ctx, fmt.Sprintf("failed to get webhook delivery %d", delivery.GetID()), resp, err), nil, nil
+				}
+				_ = resp.Body.Close()
+				analysis.Samples = append(analysis.Samples, newWebhookFailureSample(detail))
+			}
+
+			analyses = append(analyses, analysis)
+		}
+
+		return MarshalledTextResult(analyses), nil, nil
+	})
+
+	return tool, handler
+}
+
+// RedeliverWebhookDelivery creates a tool to redeliver a webhook delivery.
+func RedeliverWebhookDelivery(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, mcp.ToolHandlerFor[map[string]any, any]) {
+	tool := mcp.Tool{
+		Name:        "redeliver_webhook_delivery",
+		Description: t("TOOL_REDELIVER_WEBHOOK_DELIVERY_DESCRIPTION", "Redeliver a previous delivery of a repository webhook, for example after fixing the receiving endpoint"),
+		Annotations: &mcp.ToolAnnotations{
+			Title:        t("TOOL_REDELIVER_WEBHOOK_DELIVERY_USER_TITLE", "Redeliver webhook delivery"),
+			ReadOnlyHint: false,
+		},
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"owner": {
+					Type:        "string",
+					Description: "Repository owner",
+				},
+				"repo": {
+					Type:        "string",
+					Description: "Repository name",
+				},
+				"hook_id": {
+					Type:        "number",
+					Description: "The webhook ID",
+				},
+				"delivery_id": {
+					Type:        "number",
+					Description: "The ID of the delivery to redeliver",
+				},
+			},
+			Required: []string{"owner", "repo", "hook_id", "delivery_id"},
+		},
+	}
+
+	handler := mcp.ToolHandlerFor[map[string]any, any](func(ctx context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		owner, err := RequiredParam[string](args, "owner")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		repo, err := RequiredParam[string](args, "repo")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		hookID, err := RequiredBigInt(args, "hook_id")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+		deliveryID, err := RequiredBigInt(args, "delivery_id")
+		if err != nil {
+			return utils.NewToolResultError(err.Error()), nil, nil
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+		}
+
+		delivery, resp, err := client.Repositories.RedeliverHookDelivery(ctx, owner, repo, hookID, deliveryID)
+		if err != nil && !isAcceptedError(err) {
+			return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to redeliver webhook delivery", resp, err), nil, nil
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		result := map[string]any{
+			"hook_id":     hookID,
+			"delivery_id": deliveryID,
+			"message":     "Redelivery has been requested",
+		}
+		if delivery != nil && delivery.GetGUID() != "" {
+			result["guid"] = delivery.GetGUID()
+		}
+
+		return MarshalledTextResult(result), nil, nil
+	})
+
+	return tool, handler
+}
+
+// listRecentHookDeliveries pages through the most recent deliveries of a hook, newest first,
+// until limit deliveries have been collected or there are no more.
+func listRecentHookDeliveries(ctx context.Context, client *github.Client, owner, repo string, hookID int64, limit int) ([]*github.HookDelivery, *github.Response, error) {
+	var deliveries []*github.HookDelivery
+	opts := &github.ListCursorOptions{PerPage: min(limit, 100)}
+	for len(deliveries) < limit {
+		page, resp, err := client.Repositories.ListHookDeliveries(ctx, owner, repo, hookID, opts)
+		if err != nil {
+			return nil, resp, err
+		}
+		_ = resp.Body.Close()
+		deliveries = append(deliveries, page...)
+		if resp.Cursor == "" || len(page) == 0 {
+			break
+		}
+		opts.Cursor = resp.Cursor
+	}
+	if len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+	return deliveries, nil, nil
+}
+
+// isFailedDelivery reports whether the receiver did not acknowledge a delivery with a 2xx response.
+// A status code of 0 means no HTTP response was received at all.
+func isFailedDelivery(d *github.HookDelivery) bool {
+	code := d.GetStatusCode()
+	return code < 200 || code >= 300
+}
+
+func analyzeHookDeliveries(hook *github.Hook, deliveries []*github.HookDelivery) WebhookFailureAnalysis {
+	analysis := WebhookFailureAnalysis{
+		HookID:            hook.GetID(),
+		Active:            hook.GetActive(),
+		DeliveriesScanned: len(deliveries),
+		StatusCodes:       make(map[string]int),
+		Events:            make(map[string]int),
+	}
+	if hook.Config != nil {
+		analysis.URL = hook.Config.GetURL()
+	}
+
+	noResponseStatuses := make(map[string]bool)
+	for _, d := range deliveries {
+		deliveredAt := ""
+		if d.DeliveredAt != nil {
+			deliveredAt = d.DeliveredAt.Format(time.RFC3339)
+		}
+
+		if !isFailedDelivery(d) {
+			if analysis.LastSuccessAt == "" {
+				analysis.LastSuccessAt = deliveredAt
+			}
+			continue
+		}
+
+		analysis.FailedDeliveries++
+		if analysis.LastFailureAt == "" {
+			analysis.LastFailureAt = deliveredAt
+		}
+
+		code := "no_response"
+		if d.GetStatusCode() != 0 {
+			code = strconv.Itoa(d.GetStatusCode())
+		} else if d.GetStatus() != "" {
+			noResponseStatuses[d.GetStatus()] = true
+		}
+		analysis.StatusCodes[code]++
+
+		event := d.GetEvent()
+		if d.GetAction() != "" {
+			event += "." + d.GetAction()
+		}
+		analysis.Events[event]++
+	}
+
+	if len(deliveries) > 0 {
+		analysis.FailureRate = float64(analysis.FailedDeliveries) / float64(len(deliveries))
+	}
+	analysis.Suggestions = webhookFailureSuggestions(hook, analysis.StatusCodes, noResponseStatuses)
+
+	return analysis
+}
+
+func webhookFailureSuggestions(hook *github.Hook, statusCodes map[string]int, noResponseStatuses map[string]bool) []string {
+	var suggestions []string
+	if !hook.GetActive() {
+		suggestions = append(suggestions, "The webhook is inactive, so no new events are delivered. Reactivate it once the endpoint is fixed.")
+	}
+
+	codes := make([]string, 0, len(statusCodes))
+	for code := range statusCodes {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	seen := make(map[string]bool)
+	add := func(s string) {
+		if !seen[s] {
+			seen[s] = true
+			suggestions = append(suggestions, s)
+		}
+	}
+
+	for _, code := range codes {
+		switch {
+		case code == "no_response":
+			statuses := make([]string, 0, len(noResponseStatuses))
+			for s := range noResponseStatuses {
+				statuses = append(statuses, s)
+			}
+			sort.Strings(statuses)
+			detail := ""
+			if len(statuses) > 0 {
+				detail = fmt.Sprintf(" (%s)", strings.Join(statuses, "; "))
+			}
+			add(fmt.Sprintf("Some deliveries got no HTTP response%s. Check that the endpoint is publicly reachable, its DNS and TLS certificate are valid, and that it responds within 10 seconds.", detail))
+		case code == "401" || code == "403":
+			add("The endpoint rejected deliveries as unauthorized. Check that the webhook secret matches the one the receiver uses to verify X-Hub-Signature-256, and that no proxy or firewall blocks GitHub's hook IP ranges.")
+		case code == "404" || code == "410":
+			add("The endpoint URL was not found. Verify the webhook URL, including its path, and that the receiving service is deployed.")
+		case code == "405":
+			add("The endpoint does not accept POST requests. Point the webhook at a handler that accepts POST.")
+		case code == "400" || code == "415" || code == "422":
+			add("The endpoint rejected the payload. Check that the webhook content type (application/json or application/x-www-form-urlencoded) matches what the receiver parses.")
+		case code == "413":
+			add("The payload was too large for the endpoint. Raise the receiver's request size limit; GitHub payloads can be up to 25 MB.")
+		case code == "429":
+			add("The endpoint is rate limiting deliveries. Raise its limits for GitHub's hook IP ranges or queue deliveries instead of rejecting them.")
+		case code == "408" || code == "504":
+			add("The endpoint timed out. Acknowledge deliveries immediately with a 2xx response and process them asynchronously.")
+		case strings.HasPrefix(code, "5"):
+			add("The endpoint returned server errors. Check the receiver's logs for the failed delivery GUIDs.")
+		case strings.HasPrefix(code, "3"):
+			add("The endpoint responded with a redirect, which GitHub does not follow. Update the webhook URL to the final location.")
+		}
+	}
+
+	if len(codes) > 0 {
+		add("After fixing the endpoint, retry failed deliveries with redeliver_webhook_delivery.")
+	}
+
+	return suggestions
+}
+
+func newWebhookFailureSample(d *github.HookDelivery) WebhookFailureSample {
+	sample := WebhookFailureSample{
+		DeliveryID: d.GetID(),
+		GUID:       d.GetGUID(),
+		Event:      d.GetEvent(),
+		StatusCode: d.GetStatusCode(),
+		Status:     d.GetStatus(),
+		Redelivery: d.GetRedelivery(),
+	}
+	if d.GetAction() != "" {
+		sample.Event += "." + d.GetAction()
+	}
+	if d.DeliveredAt != nil {
+		sample.DeliveredAt = d.DeliveredAt.Format(time.RFC3339)
+	}
+	if d.Response != nil && d.Response.RawPayload != nil {
+		sample.ResponseExcerpt = responseExcerpt(*d.Response.RawPayload)
+	}
+	return sample
+}
+
+// responseExcerpt returns the start of a delivery response body. The body is usually
+// delivered as a JSON string, which is unquoted first.
+func responseExcerpt(raw json.RawMessage) string {
+	body := string(raw)
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		body = s
+	}
+	runes := []rune(strings.TrimSpace(body))
+	if len(runes) > webhookResponseExcerptLength {
+		return string(runes[:webhookResponseExcerptLength]) + "..."
+	}
+	return string(runes)
+}
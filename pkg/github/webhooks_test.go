@@ -0,0 +1,190 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v79/github"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ListRepositoryWebhooks(t *testing.T) {
+	mockClient := github.NewClient(nil)
+	tool, _ := ListRepositoryWebhooks(stubGetClientFn(mockClient), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	schema, ok := tool.InputSchema.(*jsonschema.Schema)
+	require.True(t, ok, "InputSchema should be *jsonschema.Schema")
+	assert.Equal(t, "list_repository_webhooks", tool.Name)
+	assert.ElementsMatch(t, schema.Required, []string{"owner", "repo"})
+
+	client := github.NewClient(mock.NewMockedHTTPClient(
+		mock.WithRequestMatch(
+			mock.GetReposHooksByOwnerByRepo,
+			[]*github.Hook{
+				{
+					ID:     github.Ptr(int64(1)),
+					Name:   github.Ptr("web"),
+					Events: []string{"push"},
+					Active: github.Ptr(true),
+					Config: &github.HookConfig{URL: github.Ptr("https://example.com/hook"), ContentType: github.Ptr("json")},
+				},
+			},
+		),
+	))
+	_, handler := ListRepositoryWebhooks(stubGetClientFn(client), translations.NullTranslationHelper)
+
+	args := map[string]interface{}{"owner": "owner", "repo": "repo"}
+	request := createMCPRequest(args)
+	result, _, err := handler(context.Background(), &request, args)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+
+	var hooks []MinimalWebhook
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &hooks))
+	require.Len(t, hooks, 1)
+	assert.Equal(t, "https://example.com/hook", hooks[0].URL)
+	assert.Equal(t, "json", hooks[0].ContentType)
+	assert.True(t, hooks[0].Active)
+}
+
+func Test_AnalyzeWebhookFailures(t *testing.T) {
+	mockClient := github.NewClient(nil)
+	tool, _ := AnalyzeWebhookFailures(stubGetClientFn(mockClient), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	schema, ok := tool.InputSchema.(*jsonschema.Schema)
+	require.True(t, ok, "InputSchema should be *jsonschema.Schema")
+	assert.Equal(t, "analyze_webhook_failures", tool.Name)
+	assert.Contains(t, schema.Properties, "hook_id")
+	assert.Contains(t, schema.Properties, "max_deliveries")
+	assert.ElementsMatch(t, schema.Required, []string{"owner", "repo"})
+
+	hook := &github.Hook{
+		ID:     github.Ptr(int64(42)),
+		Active: github.Ptr(true),
+		Config: &github.HookConfig{URL: github.Ptr("https://example.com/hook")},
+	}
+	deliveries := []*github.HookDelivery{
+		{ID: github.Ptr(int64(3)), Event: github.Ptr("push"), StatusCode: github.Ptr(401), Status: github.Ptr("Invalid HTTP Response: 401")},
+		{ID: github.Ptr(int64(2)), Event: github.Ptr("pull_request"), Action: github.Ptr("opened"), StatusCode: github.Ptr(0), Status: github.Ptr("timed out")},
+		{ID: github.Ptr(int64(1)), Event: github.Ptr("push"), StatusCode: github.Ptr(200), Status: github.Ptr("OK")},
+	}
+	responseBody := json.RawMessage(`"{\"error\":\"bad signature\"}"`)
+
+	client := github.NewClient(mock.NewMockedHTTPClient(
+		mock.WithRequestMatch(
+			mock.GetReposHooksByOwnerByRepoByHookId,
+			hook,
+		),
+		mock.WithRequestMatch(
+			mock.GetReposHooksDeliveriesByOwnerByRepoByHookId,
+			deliveries,
+		),
+		mock.WithRequestMatch(
+			mock.GetReposHooksDeliveriesByOwnerByRepoByHookIdByDeliveryId,
+			&github.HookDelivery{
+				ID:         github.Ptr(int64(3)),
+				GUID:       github.Ptr("guid-3"),
+				Event:      github.Ptr("push"),
+				StatusCode: github.Ptr(401),
+				Status:     github.Ptr("Invalid HTTP Response: 401"),
+				Response:   &github.HookResponse{RawPayload: &responseBody},
+			},
+		),
+	))
+	_, handler := AnalyzeWebhookFailures(stubGetClientFn(client), translations.NullTranslationHelper)
+
+	args := map[string]interface{}{"owner": "owner", "repo": "repo", "hook_id": float64(42), "samples": float64(1)}
+	request := createMCPRequest(args)
+	result, _, err := handler(context.Background(), &request, args)
+	require.NoError(t, err)
+	require.False(t, result.IsError, getTextResult(t, result).Text)
+
+	var analyses []WebhookFailureAnalysis
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &analyses))
+	require.Len(t, analyses, 1)
+
+	analysis := analyses[0]
+	assert.Equal(t, int64(42), analysis.HookID)
+	assert.Equal(t, 3, analysis.DeliveriesScanned)
+	assert.Equal(t, 2, analysis.FailedDeliveries)
+	assert.InDelta(t, 2.0/3.0, analysis.FailureRate, 0.001)
+	assert.Equal(t, map[string]int{"401": 1, "no_response": 1}, analysis.StatusCodes)
+	assert.Equal(t, map[string]int{"push": 1, "pull_request.opened": 1}, analysis.Events)
+
+	require.Len(t, analysis.Samples, 1)
+	assert.Equal(t, "guid-3", analysis.Samples[0].GUID)
+	assert.Equal(t, `{"error":"bad signature"}`, analysis.Samples[0].ResponseExcerpt)
+
+	require.Len(t, analysis.Suggestions, 3)
+	assert.Contains(t, analysis.Suggestions[0], "webhook secret")
+	assert.Contains(t, analysis.Suggestions[1], "timed out")
+	assert.Contains(t, analysis.Suggestions[2], "redeliver_webhook_delivery")
+}
+
+func Test_RedeliverWebhookDelivery(t *testing.T) {
+	mockClient := github.NewClient(nil)
+	tool, _ := RedeliverWebhookDelivery(stubGetClientFn(mockClient), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	schema, ok := tool.InputSchema.(*jsonschema.Schema)
+	require.True(t, ok, "InputSchema should be *jsonschema.Schema")
+	assert.Equal(t, "redeliver_webhook_delivery", tool.Name)
+	assert.ElementsMatch(t, schema.Required, []string{"owner", "repo", "hook_id", "delivery_id"})
+
+	tests := []struct {
+		name           string
+		mockedClient   *http.Client
+		expectError    bool
+		expectedErrMsg string
+	}{
+		{
+			name: "redelivery accepted",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.PostReposHooksDeliveriesAttemptsByOwnerByRepoByHookIdByDeliveryId,
+					mockResponse(t, http.StatusAccepted, map[string]any{}),
+				),
+			),
+		},
+		{
+			name: "delivery not found",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.PostReposHooksDeliveriesAttemptsByOwnerByRepoByHookIdByDeliveryId,
+					mockResponse(t, http.StatusNotFound, map[string]string{"message": "Not Found"}),
+				),
+			),
+			expectError:    true,
+			expectedErrMsg: "failed to redeliver webhook delivery",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client := github.NewClient(tc.mockedClient)
+			_, handler := RedeliverWebhookDelivery(stubGetClientFn(client), translations.NullTranslationHelper)
+
+			args := map[string]interface{}{"owner": "owner", "repo": "repo", "hook_id": float64(42), "delivery_id": float64(3)}
+			request := createMCPRequest(args)
+			result, _, err := handler(context.Background(), &request, args)
+			require.NoError(t, err)
+
+			if tc.expectError {
+				assert.Contains(t, getErrorResult(t, result).Text, tc.expectedErrMsg)
+				return
+			}
+
+			require.False(t, result.IsError)
+			assert.Contains(t, getTextResult(t, result).Text, "Redelivery has been requested")
+		})
+	}
+}
@@ -0,0 +1,242 @@
+// Package httpcache caches the responses of GitHub API GET requests by their ETag or
+// Last-Modified validators. Cached responses are always revalidated with a conditional request,
+// so they are never stale, and GitHub does not count the 304 Not Modified responses it answers
+// them with against the rate limit, which stretches the quota of read-heavy sessions.
+package httpcache
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+const (
+	// DefaultMaxBytes is the default size of the cached response bodies (64MB)
+	DefaultMaxBytes = 64 * 1024 * 1024
+	// maxEntryShare bounds the body of one response to this share of the cache, so that one
+	// large response does not evict everything else
+	maxEntryShare = 8
+
+	// HeaderFromCache is set on responses served from the cache
+	HeaderFromCache = "X-From-Cache"
+)
+
+// Stats reports the activity of a Transport
+type Stats struct {
+	// Hits counts the responses served from the cache after a 304 Not Modified
+	Hits int64
+	// Misses counts the cacheable requests that got a full response from the server
+	Misses int64
+	// Evictions counts the responses dropped to stay within the size of the cache
+	Evictions int64
+	Entries   int
+	Bytes     int64
+}
+
+// Transport caches the responses of GET requests that carry an ETag or Last-Modified header,
+// and revalidates them with If-None-Match or If-Modified-Since. Responses are cached per
+// credentials and media type, and the least recently used ones are evicted once the bodies
+// take more than MaxBytes. Requests that already carry validators or ask for a range are sent
+// as is.
+type Transport struct {
+	Base http.RoundTripper
+	// MaxBytes bounds the size of the cached response bodies
+	MaxBytes int64
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	// lru orders the entries from the most to the least recently used
+	lru   *list.List
+	stats Stats
+}
+
+type entry struct {
+	key    string
+	host   string
+	header http.Header
+	body   []byte
+}
+
+// NewTransport wraps base, or http.DefaultTransport if base is nil, to cache up to maxBytes of
+// response bodies, or DefaultMaxBytes if maxBytes is not positive.
+func NewTransport(base http.RoundTripper, maxBytes int64) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxBytes
+	}
+	return &Transport{
+		Base:     base,
+		MaxBytes: maxBytes,
+		entries:  make(map[string]*list.Element),
+		lru:      list.New(),
+	}
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet || req.Header.Get("If-None-Match") != "" || req.Header.Get("If-Modified-Since") != "" || req.Header.Get("Range") != "" {
+		return t.Base.RoundTrip(req)
+	}
+
+	key := cacheKey(req)
+	cached := t.get(key)
+	sent := req
+	if cached != nil {
+		sent = req.Clone(req.Context())
+		if etag := cached.header.Get("ETag"); etag != "" {
+			sent.Header.Set("If-None-Match", etag)
+		}
+		if lastModified := cached.header.Get("Last-Modified"); lastModified != "" {
+			sent.Header.Set("If-Modified-Since", lastModified)
+		}
+	}
+
+	resp, err := t.Base.RoundTrip(sent)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+		t.count(func(s *Stats) { s.Hits++ })
+		return cached.response(req, resp.Header), nil
+	}
+	if resp.StatusCode != http.StatusOK || !cacheable(resp.Header) {
+		return resp, nil
+	}
+
+	t.count(func(s *Stats) { s.Misses++ })
+	maxEntry := t.MaxBytes / maxEntryShare
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxEntry+1))
+	if err != nil {
+		_ = resp.Body.Close()
+		return nil, err
+	}
+	if int64(len(body)) > maxEntry {
+		// Too large to cache: the rest of the body is streamed to the caller
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+		return resp, nil
+	}
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	t.put(&entry{key: key, host: strings.ToLower(req.URL.Host), header: resp.Header.Clone(), body: body})
+	return resp, nil
+}
+
+// cacheable reports whether a response with header can be revalidated and may be stored.
+func cacheable(header http.Header) bool {
+	if header.Get("ETag") == "" && header.Get("Last-Modified") == "" {
+		return false
+	}
+	return !strings.Contains(strings.ToLower(header.Get("Cache-Control")), "no-store")
+}
+
+// cacheKey identifies the response to req. GitHub varies responses by media type, API version
+// and credentials, which are hashed so that tokens are never kept in the clear.
+func cacheKey(req *http.Request) string {
+	credentials := sha256.Sum256([]byte(req.Header.Get("Authorization")))
+	return strings.Join([]string{
+		req.URL.String(),
+		req.Header.Get("Accept"),
+		req.Header.Get("X-GitHub-Api-Version"),
+		hex.EncodeToString(credentials[:8]),
+	}, "\n")
+}
+
+// response returns the cached response for req, with the headers of the 304 Not Modified
+// response that revalidated it, which carry the current rate limit.
+func (e *entry) response(req *http.Request, fresh http.Header) *http.Response {
+	header := e.header.Clone()
+	for name, values := range fresh {
+		if name == "Content-Length" || name == "Transfer-Encoding" {
+			continue
+		}
+		header[name] = values
+	}
+	header.Set(HeaderFromCache, "1")
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.body)),
+		ContentLength: int64(len(e.body)),
+		Request:       req,
+	}
+}
+
+func (t *Transport) get(key string) *entry {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	element, ok := t.entries[key]
+	if !ok {
+		return nil
+	}
+	t.lru.MoveToFront(element)
+	return element.Value.(*entry)
+}
+
+func (t *Transport) put(e *entry) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if element, ok := t.entries[e.key]; ok {
+		t.remove(element)
+	}
+	t.entries[e.key] = t.lru.PushFront(e)
+	t.stats.Bytes += int64(len(e.body))
+	for t.stats.Bytes > t.MaxBytes {
+		t.remove(t.lru.Back())
+		t.stats.Evictions++
+	}
+}
+
+// remove drops the entry of element. t.mu must be held.
+func (t *Transport) remove(element *list.Element) {
+	e := t.lru.Remove(element).(*entry)
+	delete(t.entries, e.key)
+	t.stats.Bytes -= int64(len(e.body))
+}
+
+func (t *Transport) count(update func(*Stats)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	update(&t.stats)
+}
+
+// InvalidateHost drops the cached responses of host, such as api.github.com or a GitHub
+// Enterprise Server hostname, and returns how many were dropped.
+func (t *Transport) InvalidateHost(host string) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	host = strings.ToLower(host)
+	dropped := 0
+	for element := t.lru.Front(); element != nil; {
+		next := element.Next()
+		if element.Value.(*entry).host == host {
+			t.remove(element)
+			dropped++
+		}
+		element = next
+	}
+	return dropped
+}
+
+// Stats returns the activity of the cache and its current size.
+func (t *Transport) Stats() Stats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	stats := t.stats
+	stats.Entries = len(t.entries)
+	return stats
+}
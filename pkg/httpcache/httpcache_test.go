@@ -0,0 +1,220 @@
+package httpcache
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// etagServer serves body with an ETag derived from *version, answers If-None-Match with 304
+// Not Modified, and counts the requests by status.
+func etagServer(t *testing.T, body string, version *atomic.Int32) (*httptest.Server, *atomic.Int32, *atomic.Int32) {
+	t.Helper()
+	var full, notModified atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		etag := `"v` + string(rune('0'+version.Load())) + `"`
+		w.Header().Set("X-RateLimit-Remaining", string(rune('0'+full.Load()+notModified.Load())))
+		if r.Header.Get("If-None-Match") == etag {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		full.Add(1)
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Link", `<https://api.github.com/next>; rel="next"`)
+		_, _ = io.WriteString(w, body+string(rune('0'+version.Load())))
+	}))
+	t.Cleanup(server.Close)
+	return server, &full, &notModified
+}
+
+func get(t *testing.T, client *http.Client, url, token string) (*http.Response, string) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, string(body)
+}
+
+func TestTransport_Revalidates(t *testing.T) {
+	var version atomic.Int32
+	server, full, notModified := etagServer(t, "body", &version)
+	cache := NewTransport(nil, 0)
+	client := &http.Client{Transport: cache}
+
+	resp, body := get(t, client, server.URL+"/repos/o/r", "a")
+	assert.Equal(t, "body0", body)
+	assert.Empty(t, resp.Header.Get(HeaderFromCache))
+
+	resp, body = get(t, client, server.URL+"/repos/o/r", "a")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "body0", body)
+	assert.Equal(t, "1", resp.Header.Get(HeaderFromCache))
+	// The cached headers are kept, and updated by the headers of the 304
+	assert.Equal(t, `<https://api.github.com/next>; rel="next"`, resp.Header.Get("Link"))
+	assert.Equal(t, "1", resp.Header.Get("X-RateLimit-Remaining"))
+	assert.Equal(t, int32(1), full.Load())
+	assert.Equal(t, int32(1), notModified.Load())
+
+	// A changed resource is served in full and replaces the cached response
+	version.Store(1)
+	resp, body = get(t, client, server.URL+"/repos/o/r", "a")
+	assert.Equal(t, "body1", body)
+	assert.Empty(t, resp.Header.Get(HeaderFromCache))
+	_, body = get(t, client, server.URL+"/repos/o/r", "a")
+	assert.Equal(t, "body1", body)
+
+	assert.Equal(t, Stats{Hits: 2, Misses: 2, Entries: 1, Bytes: 5}, cache.Stats())
+}
+
+func TestTransport_KeyedByCredentials(t *testing.T) {
+	var version atomic.Int32
+	server, full, _ := etagServer(t, "body", &version)
+	client := &http.Client{Transport: NewTransport(nil, 0)}
+
+	get(t, client, server.URL+"/user", "a")
+	resp, _ := get(t, client, server.URL+"/user", "b")
+	assert.Empty(t, resp.Header.Get(HeaderFromCache))
+	assert.Equal(t, int32(2), full.Load())
+}
+
+func TestTransport_PassesThrough(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		header http.Header
+		status int
+		etag   string
+	}{
+		{name: "writes", method: http.MethodPost, status: http.StatusOK, etag: `"a"`},
+		{name: "conditional requests", method: http.MethodGet, header: http.Header{"If-None-Match": {`"a"`}}, status: http.StatusOK, etag: `"a"`},
+		{name: "ranges", method: http.MethodGet, header: http.Header{"Range": {"bytes=0-1"}}, status: http.StatusOK, etag: `"a"`},
+		{name: "responses without validators", method: http.MethodGet, status: http.StatusOK},
+		{name: "errors", method: http.MethodGet, status: http.StatusNotFound, etag: `"a"`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var requests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests.Add(1)
+				assert.Empty(t, r.Header.Get("If-Modified-Since"))
+				if tc.etag != "" {
+					w.Header().Set("ETag", tc.etag)
+				}
+				w.WriteHeader(tc.status)
+			}))
+			defer server.Close()
+			cache := NewTransport(nil, 0)
+			client := &http.Client{Transport: cache}
+
+			for range 2 {
+				req, err := http.NewRequest(tc.method, server.URL, nil)
+				require.NoError(t, err)
+				for name, values := range tc.header {
+					req.Header[name] = values
+				}
+				resp, err := client.Do(req)
+				require.NoError(t, err)
+				_ = resp.Body.Close()
+				assert.Equal(t, tc.status, resp.StatusCode)
+			}
+			assert.Equal(t, int32(2), requests.Load())
+			assert.Zero(t, cache.Stats().Entries)
+		})
+	}
+}
+
+func TestTransport_LastModified(t *testing.T) {
+	const lastModified = "Wed, 21 Oct 2026 07:28:00 GMT"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-Modified-Since") == lastModified {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Last-Modified", lastModified)
+		_, _ = io.WriteString(w, "content")
+	}))
+	defer server.Close()
+	client := &http.Client{Transport: NewTransport(nil, 0)}
+
+	get(t, client, server.URL, "")
+	resp, body := get(t, client, server.URL, "")
+	assert.Equal(t, "1", resp.Header.Get(HeaderFromCache))
+	assert.Equal(t, "content", body)
+}
+
+func TestTransport_SizeLimits(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		etag := `"` + r.URL.Path + `"`
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		size := 100
+		if r.URL.Path == "/large" {
+			size = 201
+		}
+		_, _ = io.WriteString(w, strings.Repeat("x", size))
+	}))
+	defer server.Close()
+	// Entries are bounded to 1600/8 = 200 bytes
+	cache := NewTransport(nil, 1600)
+	client := &http.Client{Transport: cache}
+
+	// Responses too large to cache are still served in full
+	_, body := get(t, client, server.URL+"/large", "")
+	assert.Len(t, body, 201)
+	assert.Zero(t, cache.Stats().Entries)
+
+	for i := range 17 {
+		get(t, client, server.URL+"/"+string(rune('a'+i)), "")
+	}
+	// Getting /b again makes /c the least recently used entry, evicted by the next one
+	get(t, client, server.URL+"/b", "")
+	get(t, client, server.URL+"/z", "")
+	stats := cache.Stats()
+	assert.Equal(t, 16, stats.Entries)
+	assert.Equal(t, int64(1600), stats.Bytes)
+	assert.Equal(t, int64(2), stats.Evictions)
+
+	resp, _ := get(t, client, server.URL+"/b", "")
+	assert.Equal(t, "1", resp.Header.Get(HeaderFromCache))
+	resp, _ = get(t, client, server.URL+"/c", "")
+	assert.Empty(t, resp.Header.Get(HeaderFromCache))
+}
+
+func TestTransport_InvalidateHost(t *testing.T) {
+	var version atomic.Int32
+	first, _, _ := etagServer(t, "first", &version)
+	second, _, _ := etagServer(t, "second", &version)
+	cache := NewTransport(nil, 0)
+	client := &http.Client{Transport: cache}
+
+	get(t, client, first.URL+"/a", "")
+	get(t, client, first.URL+"/b", "")
+	get(t, client, second.URL+"/a", "")
+
+	host := strings.TrimPrefix(first.URL, "http://")
+	assert.Equal(t, 2, cache.InvalidateHost(strings.ToUpper(host)))
+	assert.Equal(t, 1, cache.Stats().Entries)
+	assert.Zero(t, cache.InvalidateHost(host))
+
+	resp, _ := get(t, client, first.URL+"/a", "")
+	assert.Empty(t, resp.Header.Get(HeaderFromCache))
+	resp, _ = get(t, client, second.URL+"/a", "")
+	assert.Equal(t, "1", resp.Header.Get(HeaderFromCache))
+}
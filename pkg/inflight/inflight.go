@@ -0,0 +1,255 @@
+// Package inflight bounds the GitHub API requests in flight and coalesces identical concurrent
+// GET requests, so that bursts of parallel tool calls, such as those of multi-agent clients,
+// do not multiply the load on the API.
+package inflight
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Stats reports the activity of a Limiter or a Coalescer
+type Stats struct {
+	// InFlight is the number of requests holding a slot of a Limiter
+	InFlight int
+	// Waits counts the requests that waited for a slot of a Limiter, and WaitMs the time
+	// they spent waiting
+	Waits  int64
+	WaitMs int64
+	// Coalesced counts the requests a Coalescer answered with the response of an identical
+	// request already in flight
+	Coalesced int64
+}
+
+// Limiter bounds the requests in flight through it. A request holds its slot until its
+// response body is read to the end or closed, and waits for one until its context is done.
+type Limiter struct {
+	Base http.RoundTripper
+
+	slots  chan struct{}
+	waits  atomic.Int64
+	waitNs atomic.Int64
+}
+
+// NewLimiter wraps base, or http.DefaultTransport if base is nil, to allow at most limit
+// requests in flight.
+func NewLimiter(base http.RoundTripper, limit int) *Limiter {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Limiter{Base: base, slots: make(chan struct{}, max(limit, 1))}
+}
+
+func (l *Limiter) RoundTrip(req *http.Request) (*http.Response, error) {
+	select {
+	case l.slots <- struct{}{}:
+	default:
+		start := time.Now()
+		select {
+		case l.slots <- struct{}{}:
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+		l.waits.Add(1)
+		l.waitNs.Add(int64(time.Since(start)))
+	}
+
+	resp, err := l.Base.RoundTrip(req)
+	if err != nil {
+		<-l.slots
+		return nil, err
+	}
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: func() { <-l.slots }}
+	return resp, nil
+}
+
+// Stats returns the requests in flight and the waits for a slot.
+func (l *Limiter) Stats() Stats {
+	return Stats{
+		InFlight: len(l.slots),
+		Waits:    l.waits.Load(),
+		WaitMs:   time.Duration(l.waitNs.Load()).Milliseconds(),
+	}
+}
+
+// releasingBody releases the slot of its request once read to the end or closed.
+type releasingBody struct {
+	io.ReadCloser
+	release func()
+	once    sync.Once
+}
+
+func (b *releasingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err == io.EOF {
+		b.once.Do(b.release)
+	}
+	return n, err
+}
+
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
+
+// MaxSharedBodyBytes bounds the response bodies a Coalescer buffers to share. Larger responses
+// are streamed to the request that was sent, and the identical requests waiting for it are
+// sent on their own.
+const MaxSharedBodyBytes = 1 << 20
+
+// errNotShared marks a call whose response was too large to share
+var errNotShared = errors.New("response not shared")
+
+// Coalescer sends identical concurrent GET requests once: a request with the same URL,
+// credentials and media type as one in flight waits for its response instead of being sent.
+// The first request reads the response body, and every request gets a copy. Range requests,
+// raw media types and bodies larger than MaxSharedBodyBytes are never buffered.
+type Coalescer struct {
+	Base http.RoundTripper
+
+	mu        sync.Mutex
+	calls     map[string]*call
+	coalesced atomic.Int64
+}
+
+// call is a request in flight, shared by the identical requests made meanwhile
+type call struct {
+	done chan struct{}
+	resp *http.Response
+	body []byte
+	err  error
+}
+
+// NewCoalescer wraps base, or http.DefaultTransport if base is nil, to coalesce identical
+// concurrent GET requests.
+func NewCoalescer(base http.RoundTripper) *Coalescer {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Coalescer{Base: base, calls: make(map[string]*call)}
+}
+
+func (c *Coalescer) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !coalescable(req) {
+		return c.Base.RoundTrip(req)
+	}
+
+	key := requestKey(req)
+	c.mu.Lock()
+	if shared, ok := c.calls[key]; ok {
+		c.mu.Unlock()
+		c.coalesced.Add(1)
+		select {
+		case <-shared.done:
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+		// The request that was sent may have been cancelled by its caller, which says nothing
+		// about this one, or got a response too large to share
+		if errors.Is(shared.err, errNotShared) || (shared.err != nil && isContextError(shared.err) && req.Context().Err() == nil) {
+			return c.Base.RoundTrip(req)
+		}
+		return shared.response(req)
+	}
+	shared := &call{done: make(chan struct{})}
+	c.calls[key] = shared
+	c.mu.Unlock()
+
+	resp, err := c.Base.RoundTrip(req)
+	if err == nil {
+		shared.resp = resp
+		shared.body, err = readSharedBody(resp)
+	}
+	shared.err = err
+
+	c.mu.Lock()
+	delete(c.calls, key)
+	c.mu.Unlock()
+	close(shared.done)
+	if errors.Is(err, errNotShared) {
+		return resp, nil
+	}
+	return shared.response(req)
+}
+
+// coalescable reports whether req may share the response of an identical request. Range
+// requests and raw media types, such as blobs and release assets, are read partially or
+// streamed by their callers, so they are never buffered.
+func coalescable(req *http.Request) bool {
+	if req.Method != http.MethodGet || req.Header.Get("Range") != "" {
+		return false
+	}
+	accept := strings.ToLower(req.Header.Get("Accept"))
+	return !strings.Contains(accept, ".raw") && !strings.Contains(accept, "octet-stream")
+}
+
+// readSharedBody reads the body of resp to share it. Bodies that are larger than
+// MaxSharedBodyBytes, or say so through their Content-Length, are left to stream, with
+// errNotShared; the part already read is put back in front of the rest.
+func readSharedBody(resp *http.Response) ([]byte, error) {
+	if resp.ContentLength > MaxSharedBodyBytes {
+		return nil, errNotShared
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, MaxSharedBodyBytes+1))
+	if err != nil {
+		_ = resp.Body.Close()
+		return nil, err
+	}
+	if len(body) > MaxSharedBodyBytes {
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+		return nil, errNotShared
+	}
+	_ = resp.Body.Close()
+	return body, nil
+}
+
+// Stats returns the number of coalesced requests.
+func (c *Coalescer) Stats() Stats {
+	return Stats{Coalesced: c.coalesced.Load()}
+}
+
+// response returns a copy of the shared response for req, with a body of its own.
+func (s *call) response(req *http.Request) (*http.Response, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	resp := *s.resp
+	resp.Header = s.resp.Header.Clone()
+	resp.Body = io.NopCloser(bytes.NewReader(s.body))
+	resp.ContentLength = int64(len(s.body))
+	resp.Request = req
+	return &resp, nil
+}
+
+// requestKey identifies the requests that get the same response: GitHub varies responses by
+// media type, API version and credentials, which are hashed so that tokens are never kept in
+// the clear.
+func requestKey(req *http.Request) string {
+	credentials := sha256.Sum256([]byte(req.Header.Get("Authorization")))
+	return strings.Join([]string{
+		req.URL.String(),
+		req.Header.Get("Accept"),
+		req.Header.Get("X-GitHub-Api-Version"),
+		req.Header.Get("Range"),
+		req.Header.Get("If-None-Match"),
+		req.Header.Get("If-Modified-Since"),
+		hex.EncodeToString(credentials[:8]),
+	}, "\n")
+}
+
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
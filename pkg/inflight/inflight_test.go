@@ -0,0 +1,263 @@
+package inflight
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func do(t *testing.T, client *http.Client, ctx context.Context, method, url, token string) (string, error) {
+	t.Helper()
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	require.NoError(t, err)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(resp.Body)
+	return string(body), err
+}
+
+func TestLimiter(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		<-release
+		_, _ = io.WriteString(w, "ok")
+	}))
+	defer server.Close()
+	limiter := NewLimiter(nil, 2)
+	client := &http.Client{Transport: limiter}
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			body, err := do(t, client, context.Background(), http.MethodPost, server.URL, "")
+			assert.NoError(t, err)
+			assert.Equal(t, "ok", body)
+		}()
+	}
+	require.Eventually(t, func() bool { return limiter.Stats().InFlight == 2 }, time.Second, time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(2), peak.Load())
+	stats := limiter.Stats()
+	assert.Zero(t, stats.InFlight)
+	assert.Equal(t, int64(3), stats.Waits)
+}
+
+func TestLimiter_ContextDone(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+	limiter := NewLimiter(nil, 1)
+	client := &http.Client{Transport: limiter}
+
+	go func() { _, _ = do(t, client, context.Background(), http.MethodGet, server.URL, "") }()
+	require.Eventually(t, func() bool { return limiter.Stats().InFlight == 1 }, time.Second, time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := do(t, client, ctx, http.MethodGet, server.URL, "")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 1, limiter.Stats().InFlight)
+}
+
+func TestLimiter_ReleasesOnError(t *testing.T) {
+	limiter := NewLimiter(nil, 1)
+	client := &http.Client{Transport: limiter}
+
+	for range 3 {
+		_, err := do(t, client, context.Background(), http.MethodGet, "http://127.0.0.1:0", "")
+		assert.Error(t, err)
+	}
+	assert.Zero(t, limiter.Stats().InFlight)
+}
+
+func TestCoalescer(t *testing.T) {
+	var requests atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		<-release
+		w.Header().Set("X-Path", r.URL.Path)
+		_, _ = io.WriteString(w, r.URL.Path+" "+r.Header.Get("Authorization"))
+	}))
+	defer server.Close()
+	coalescer := NewCoalescer(nil)
+	client := &http.Client{Transport: coalescer}
+
+	type call struct {
+		method, path, token string
+		expected            string
+	}
+	calls := []call{
+		{http.MethodGet, "/a", "x", "/a Bearer x"},
+		{http.MethodGet, "/a", "x", "/a Bearer x"},
+		{http.MethodGet, "/a", "x", "/a Bearer x"},
+		{http.MethodGet, "/a", "y", "/a Bearer y"},
+		{http.MethodGet, "/b", "x", "/b Bearer x"},
+		{http.MethodPost, "/a", "x", "/a Bearer x"},
+		{http.MethodPost, "/a", "x", "/a Bearer x"},
+	}
+	var wg sync.WaitGroup
+	for _, c := range calls {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			body, err := do(t, client, context.Background(), c.method, server.URL+c.path, c.token)
+			assert.NoError(t, err)
+			assert.Equal(t, c.expected, body)
+		}()
+	}
+	// Three distinct GETs and two POSTs reach the server, the other GETs wait for theirs
+	require.Eventually(t, func() bool { return requests.Load() == 5 && coalescer.Stats().Coalesced == 2 }, time.Second, time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(5), requests.Load())
+
+	// Requests made once the first is done are sent again
+	body, err := do(t, client, context.Background(), http.MethodGet, server.URL+"/a", "x")
+	require.NoError(t, err)
+	assert.Equal(t, "/a Bearer x", body)
+	assert.Equal(t, int32(6), requests.Load())
+}
+
+func TestCoalescer_SentRequestCancelled(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			<-r.Context().Done()
+			return
+		}
+		_, _ = io.WriteString(w, "ok")
+	}))
+	defer server.Close()
+	coalescer := NewCoalescer(nil)
+	client := &http.Client{Transport: coalescer}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := do(t, client, ctx, http.MethodGet, server.URL, "")
+		first <- err
+	}()
+	require.Eventually(t, func() bool { return requests.Load() == 1 }, time.Second, time.Millisecond)
+
+	second := make(chan string, 1)
+	go func() {
+		body, err := do(t, client, context.Background(), http.MethodGet, server.URL, "")
+		assert.NoError(t, err)
+		second <- body
+	}()
+	require.Eventually(t, func() bool { return coalescer.Stats().Coalesced == 1 }, time.Second, time.Millisecond)
+	cancel()
+
+	assert.ErrorIs(t, <-first, context.Canceled)
+	// The waiting request is sent on its own rather than failing with the cancellation
+	assert.Equal(t, "ok", <-second)
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+func TestCoalescer_NotShared(t *testing.T) {
+	large := strings.Repeat("x", MaxSharedBodyBytes+1)
+	var requests atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		<-release
+		// /large announces its size, while /chunked is only known to be too large once read
+		if r.URL.Path == "/large" {
+			w.Header().Set("Content-Length", strconv.Itoa(len(large)))
+		}
+		_, _ = io.WriteString(w, large)
+	}))
+	defer server.Close()
+	coalescer := NewCoalescer(nil)
+	client := &http.Client{Transport: coalescer}
+
+	var wg sync.WaitGroup
+	for _, path := range []string{"/large", "/large", "/chunked", "/chunked"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			body, err := do(t, client, context.Background(), http.MethodGet, server.URL+path, "")
+			assert.NoError(t, err)
+			assert.Equal(t, len(large), len(body))
+		}()
+	}
+	require.Eventually(t, func() bool { return requests.Load() == 2 && coalescer.Stats().Coalesced == 2 }, time.Second, time.Millisecond)
+	close(release)
+	wg.Wait()
+	// The waiting requests are sent on their own rather than sharing a large body
+	assert.Equal(t, int32(4), requests.Load())
+}
+
+func TestCoalescable(t *testing.T) {
+	request := func(method string, header ...string) *http.Request {
+		req, err := http.NewRequest(method, "https://api.github.com/repos/o/r/git/blobs/sha", nil)
+		require.NoError(t, err)
+		for i := 0; i+1 < len(header); i += 2 {
+			req.Header.Set(header[i], header[i+1])
+		}
+		return req
+	}
+	assert.True(t, coalescable(request(http.MethodGet, "Accept", "application/vnd.github+json")))
+	assert.False(t, coalescable(request(http.MethodPost)))
+	assert.False(t, coalescable(request(http.MethodGet, "Range", "bytes=0-9")))
+	assert.False(t, coalescable(request(http.MethodGet, "Accept", "application/vnd.github.raw+json")))
+	assert.False(t, coalescable(request(http.MethodGet, "Accept", "application/octet-stream")))
+}
+
+func TestRequestKey(t *testing.T) {
+	request := func(url string, header ...string) *http.Request {
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		require.NoError(t, err)
+		for i := 0; i+1 < len(header); i += 2 {
+			req.Header.Set(header[i], header[i+1])
+		}
+		return req
+	}
+	base := requestKey(request("https://api.github.com/user", "Authorization", "Bearer secret"))
+	assert.NotContains(t, base, "secret")
+	assert.Equal(t, base, requestKey(request("https://api.github.com/user", "Authorization", "Bearer secret", "X-Trace-Id", "abc")))
+	for _, other := range []*http.Request{
+		request("https://api.github.com/user?page=2", "Authorization", "Bearer secret"),
+		request("https://api.github.com/user", "Authorization", "Bearer other"),
+		request("https://api.github.com/user", "Authorization", "Bearer secret", "Accept", "application/vnd.github.raw"),
+		request("https://api.github.com/user", "Authorization", "Bearer secret", "Range", "bytes=0-9"),
+	} {
+		assert.NotEqual(t, base, requestKey(other), other.URL.String())
+	}
+	assert.True(t, strings.Contains(base, "https://api.github.com/user"))
+}
@@ -0,0 +1,58 @@
+// Package keyring stores secrets in the keyring of the operating system, which encrypts them at
+// rest: the login keychain on macOS, the Secret Service of the desktop, such as GNOME Keyring or
+// KWallet, on Linux and the BSDs, and the Credential Manager on Windows.
+package keyring
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// ErrNotFound is returned when the keyring holds no secret for a service and account.
+var ErrNotFound = errors.New("secret not found in keyring")
+
+// Keyring stores secrets by service and account.
+type Keyring interface {
+	// Get returns the secret of account for service, or ErrNotFound.
+	Get(service, account string) (string, error)
+	// Set stores the secret of account for service, replacing any previous one.
+	Set(service, account, secret string) error
+	// Delete removes the secret of account for service, if any.
+	Delete(service, account string) error
+}
+
+// System returns the keyring of the operating system.
+func System() Keyring {
+	return system{}
+}
+
+// output is the result of a command that exited
+type output struct {
+	stdout string
+	stderr string
+	code   int
+}
+
+// run runs the keyring tool name with args, writing stdin to it. The secrets are passed on
+// stdin rather than as arguments, which other users can list. Tests replace it.
+var run = func(stdin, name string, args ...string) (output, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		return output{}, fmt.Errorf("failed to run %s: %w", name, err)
+	}
+	return output{stdout: stdout.String(), stderr: strings.TrimSpace(stderr.String()), code: cmd.ProcessState.ExitCode()}, nil
+}
+
+// commandError reports a keyring tool that failed to do action.
+func commandError(action, name string, out output) error {
+	return fmt.Errorf("failed to %s secret with %s: exit status %d: %s", action, name, out.code, out.stderr)
+}
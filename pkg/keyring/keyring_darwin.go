@@ -0,0 +1,58 @@
+//go:build darwin
+
+package keyring
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+const (
+	// security is the command line interface of the macOS keychains
+	security = "/usr/bin/security"
+	// errSecItemNotFound is the exit status of security when there is no such item
+	errSecItemNotFound = 44
+)
+
+// system stores secrets as generic passwords of the login keychain
+type system struct{}
+
+func (system) Get(service, account string) (string, error) {
+	out, err := run("", security, "find-generic-password", "-s", service, "-a", account, "-w")
+	if err != nil {
+		return "", err
+	}
+	if out.code == errSecItemNotFound {
+		return "", ErrNotFound
+	}
+	if out.code != 0 {
+		return "", commandError("read", security, out)
+	}
+	return strings.TrimSuffix(out.stdout, "\n"), nil
+}
+
+func (system) Set(service, account, secret string) error {
+	// The command is read from stdin in interactive mode, so that the secret never appears in
+	// the arguments of a process
+	command := fmt.Sprintf("add-generic-password -U -s %q -a %q -X %s\n", service, account, hex.EncodeToString([]byte(secret)))
+	out, err := run(command, security, "-i")
+	if err != nil {
+		return err
+	}
+	if out.code != 0 {
+		return commandError("store", security, out)
+	}
+	return nil
+}
+
+func (system) Delete(service, account string) error {
+	out, err := run("", security, "delete-generic-password", "-s", service, "-a", account)
+	if err != nil {
+		return err
+	}
+	if out.code != 0 && out.code != errSecItemNotFound {
+		return commandError("delete", security, out)
+	}
+	return nil
+}
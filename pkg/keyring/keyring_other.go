@@ -0,0 +1,17 @@
+//go:build !unix && !windows
+
+package keyring
+
+import (
+	"errors"
+	"runtime"
+)
+
+var errUnsupported = errors.New("no keyring is supported on " + runtime.GOOS)
+
+// system reports that the operating system has no supported keyring
+type system struct{}
+
+func (system) Get(string, string) (string, error) { return "", errUnsupported }
+func (system) Set(string, string, string) error   { return errUnsupported }
+func (system) Delete(string, string) error        { return errUnsupported }
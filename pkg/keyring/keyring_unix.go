@@ -0,0 +1,48 @@
+//go:build unix && !darwin
+
+package keyring
+
+import "strings"
+
+// secretTool is the command line client of the Secret Service, from libsecret
+const secretTool = "secret-tool"
+
+// system stores secrets in the Secret Service through secret-tool
+type system struct{}
+
+func (system) Get(service, account string) (string, error) {
+	out, err := run("", secretTool, "lookup", "service", service, "account", account)
+	if err != nil {
+		return "", err
+	}
+	// secret-tool exits with 1 and prints nothing when there is no such secret
+	if out.code == 1 && out.stdout == "" && out.stderr == "" {
+		return "", ErrNotFound
+	}
+	if out.code != 0 {
+		return "", commandError("read", secretTool, out)
+	}
+	return strings.TrimSuffix(out.stdout, "\n"), nil
+}
+
+func (system) Set(service, account, secret string) error {
+	out, err := run(secret, secretTool, "store", "--label", service+" ("+account+")", "service", service, "account", account)
+	if err != nil {
+		return err
+	}
+	if out.code != 0 {
+		return commandError("store", secretTool, out)
+	}
+	return nil
+}
+
+func (system) Delete(service, account string) error {
+	out, err := run("", secretTool, "clear", "service", service, "account", account)
+	if err != nil {
+		return err
+	}
+	if out.code != 0 && !(out.code == 1 && out.stderr == "") {
+		return commandError("delete", secretTool, out)
+	}
+	return nil
+}
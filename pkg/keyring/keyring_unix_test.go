@@ -0,0 +1,89 @@
+//go:build unix && !darwin
+
+package keyring
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSecretTool replaces run with an in-memory secret-tool, recording the commands run.
+func fakeSecretTool(t *testing.T) *[]string {
+	t.Helper()
+	secrets := make(map[string]string)
+	var commands []string
+	original := run
+	t.Cleanup(func() { run = original })
+	run = func(stdin, name string, args ...string) (output, error) {
+		require.Equal(t, secretTool, name)
+		commands = append(commands, strings.Join(args, " "))
+		key := strings.Join(args[len(args)-4:], " ")
+		switch args[0] {
+		case "lookup":
+			secret, ok := secrets[key]
+			if !ok {
+				return output{code: 1}, nil
+			}
+			return output{stdout: secret}, nil
+		case "store":
+			secrets[key] = stdin
+		case "clear":
+			if _, ok := secrets[key]; !ok {
+				return output{code: 1}, nil
+			}
+			delete(secrets, key)
+		}
+		return output{}, nil
+	}
+	return &commands
+}
+
+func TestSystem(t *testing.T) {
+	commands := fakeSecretTool(t)
+	keyring := System()
+
+	_, err := keyring.Get("github-mcp-server", "github.com")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, keyring.Set("github-mcp-server", "github.com", "gho_secret"))
+	secret, err := keyring.Get("github-mcp-server", "github.com")
+	require.NoError(t, err)
+	assert.Equal(t, "gho_secret", secret)
+
+	require.NoError(t, keyring.Delete("github-mcp-server", "github.com"))
+	_, err = keyring.Get("github-mcp-server", "github.com")
+	assert.ErrorIs(t, err, ErrNotFound)
+	// Deleting a missing secret is not an error
+	require.NoError(t, keyring.Delete("github-mcp-server", "github.com"))
+
+	// The secret is passed on stdin, never as an argument
+	for _, command := range *commands {
+		assert.NotContains(t, command, "gho_secret")
+	}
+	assert.Contains(t, *commands, "store --label github-mcp-server (github.com) service github-mcp-server account github.com")
+}
+
+func TestSystem_Failures(t *testing.T) {
+	original := run
+	t.Cleanup(func() { run = original })
+	run = func(string, string, ...string) (output, error) {
+		return output{code: 1, stderr: "Cannot autolaunch D-Bus without X11 $DISPLAY"}, nil
+	}
+
+	_, err := System().Get("github-mcp-server", "github.com")
+	assert.EqualError(t, err, "failed to read secret with secret-tool: exit status 1: Cannot autolaunch D-Bus without X11 $DISPLAY")
+	assert.ErrorContains(t, System().Set("github-mcp-server", "github.com", "gho_secret"), "failed to store secret with secret-tool")
+	assert.ErrorContains(t, System().Delete("github-mcp-server", "github.com"), "failed to delete secret with secret-tool")
+}
+
+func TestRun(t *testing.T) {
+	out, err := run("from stdin", "sh", "-c", "cat; echo oops >&2; exit 3")
+	require.NoError(t, err)
+	assert.Equal(t, output{stdout: "from stdin", stderr: "oops", code: 3}, out)
+
+	_, err = run("", "github-mcp-server-missing-tool")
+	assert.ErrorContains(t, err, "failed to run github-mcp-server-missing-tool")
+}
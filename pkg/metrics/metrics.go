@@ -0,0 +1,388 @@
+// Package metrics records the activity of the server: tool calls with their latency and error
+// codes, the GitHub API requests they make and the time spent waiting on rate limiters. The
+// metrics are served in the Prometheus text format for operators, and as a snapshot for the
+// get_server_metrics tool.
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/github/github-mcp-server/pkg/httpcache"
+	"github.com/github/github-mcp-server/pkg/ratelimit"
+)
+
+// latencyBuckets are the upper bounds, in seconds, of the buckets of the tool call latency
+// histogram
+var latencyBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// API names of GitHub API requests
+const (
+	APIREST    = "rest"
+	APIGraphQL = "graphql"
+)
+
+// Metrics records the activity of a server. It is safe for concurrent use.
+type Metrics struct {
+	// RateLimiters returns the stats of the rate limiters of the server, keyed by limiter name.
+	// It is called whenever the metrics are reported.
+	RateLimiters func() map[string]ratelimit.Stats
+	// ResponseCache returns the stats of the response cache of the server, if it has one. It
+	// is called whenever the metrics are reported.
+	ResponseCache func() httpcache.Stats
+
+	start time.Time
+
+	mu    sync.Mutex
+	tools map[string]*toolMetrics
+	api   map[apiKey]int64
+}
+
+type toolMetrics struct {
+	calls int64
+	// errors counts the failed calls by error code
+	errors  map[string]int64
+	seconds float64
+	maxSecs float64
+	// buckets counts the calls by latency bucket, with a last bucket for slower calls
+	buckets []int64
+}
+
+type apiKey struct {
+	api string
+	// status is the HTTP status of the response, 0 if the request failed without one
+	status int
+}
+
+// New creates empty metrics.
+func New() *Metrics {
+	return &Metrics{
+		start: time.Now(),
+		tools: make(map[string]*toolMetrics),
+		api:   make(map[apiKey]int64),
+	}
+}
+
+// RecordToolCall records a call of tool that took d. errorCode classifies a failed call, and
+// is empty for successful ones.
+func (m *Metrics) RecordToolCall(tool string, d time.Duration, errorCode string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	t, ok := m.tools[tool]
+	if !ok {
+		t = &toolMetrics{errors: make(map[string]int64), buckets: make([]int64, len(latencyBuckets)+1)}
+		m.tools[tool] = t
+	}
+	seconds := d.Seconds()
+	t.calls++
+	t.seconds += seconds
+	t.maxSecs = max(t.maxSecs, seconds)
+	t.buckets[sort.SearchFloat64s(latencyBuckets, seconds)]++
+	if errorCode != "" {
+		t.errors[errorCode]++
+	}
+}
+
+// RecordAPICall records a GitHub API request to api, APIREST or APIGraphQL, answered with
+// status, or 0 if it failed without a response.
+func (m *Metrics) RecordAPICall(api string, status int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.api[apiKey{api: api, status: status}]++
+}
+
+// ToolSnapshot reports the calls of one tool
+type ToolSnapshot struct {
+	Name   string `json:"name"`
+	Calls  int64  `json:"calls"`
+	Errors int64  `json:"errors"`
+	// ErrorCodes counts the failed calls by error code
+	ErrorCodes   map[string]int64 `json:"error_codes,omitempty"`
+	AvgLatencyMs int64            `json:"avg_latency_ms"`
+	MaxLatencyMs int64            `json:"max_latency_ms"`
+}
+
+// APICallSnapshot counts the GitHub API requests of one API answered with one status
+type APICallSnapshot struct {
+	API string `json:"api"`
+	// Status is 0 for requests that failed without a response
+	Status int   `json:"status"`
+	Count  int64 `json:"count"`
+}
+
+// RateLimitWaitSnapshot reports the waits of the requests of one priority on a rate limiter
+type RateLimitWaitSnapshot struct {
+	Limiter  string `json:"limiter"`
+	Priority string `json:"priority"`
+	Waits    int64  `json:"waits"`
+	WaitMs   int64  `json:"wait_ms"`
+}
+
+// ResponseCacheSnapshot reports the activity of the response cache
+type ResponseCacheSnapshot struct {
+	// Hits counts the responses served from the cache, which did not count against the rate limit
+	Hits      int64 `json:"hits"`
+	Misses    int64 `json:"misses"`
+	Evictions int64 `json:"evictions"`
+	Entries   int   `json:"entries"`
+	Bytes     int64 `json:"bytes"`
+}
+
+// Snapshot is the state of the metrics at one point in time, sorted by name
+type Snapshot struct {
+	UptimeSeconds  int64                   `json:"uptime_seconds"`
+	Tools          []ToolSnapshot          `json:"tools"`
+	APICalls       []APICallSnapshot       `json:"api_calls"`
+	RateLimitWaits []RateLimitWaitSnapshot `json:"rate_limit_waits"`
+	// ResponseCache is nil when the server caches no responses
+	ResponseCache *ResponseCacheSnapshot `json:"response_cache,omitempty"`
+}
+
+// priorities are the rate limiter priorities reported, in order
+var priorities = []ratelimit.Priority{ratelimit.PriorityHigh, ratelimit.PriorityNormal, ratelimit.PriorityLow}
+
+// Snapshot returns the current metrics.
+func (m *Metrics) Snapshot() Snapshot {
+	snapshot := Snapshot{
+		UptimeSeconds:  int64(time.Since(m.start).Seconds()),
+		Tools:          []ToolSnapshot{},
+		APICalls:       []APICallSnapshot{},
+		RateLimitWaits: []RateLimitWaitSnapshot{},
+	}
+
+	m.mu.Lock()
+	for name, t := range m.tools {
+		tool := ToolSnapshot{
+			Name:         name,
+			Calls:        t.calls,
+			AvgLatencyMs: int64(t.seconds / float64(t.calls) * 1000),
+			MaxLatencyMs: int64(t.maxSecs * 1000),
+		}
+		if len(t.errors) > 0 {
+			tool.ErrorCodes = make(map[string]int64, len(t.errors))
+			for code, count := range t.errors {
+				tool.ErrorCodes[code] = count
+				tool.Errors += count
+			}
+		}
+		snapshot.Tools = append(snapshot.Tools, tool)
+	}
+	for key, count := range m.api {
+		snapshot.APICalls = append(snapshot.APICalls, APICallSnapshot{API: key.api, Status: key.status, Count: count})
+	}
+	m.mu.Unlock()
+
+	sort.Slice(snapshot.Tools, func(i, j int) bool { return snapshot.Tools[i].Name < snapshot.Tools[j].Name })
+	sort.Slice(snapshot.APICalls, func(i, j int) bool {
+		a, b := snapshot.APICalls[i], snapshot.APICalls[j]
+		if a.API != b.API {
+			return a.API < b.API
+		}
+		return a.Status < b.Status
+	})
+
+	limiters := m.rateLimiters()
+	for _, name := range sortedKeys(limiters) {
+		stats := limiters[name]
+		for _, priority := range priorities {
+			waits := stats.Priority(priority)
+			snapshot.RateLimitWaits = append(snapshot.RateLimitWaits, RateLimitWaitSnapshot{
+				Limiter:  name,
+				Priority: priority.String(),
+				Waits:    waits.Waits,
+				WaitMs:   waits.WaitMs,
+			})
+		}
+	}
+
+	if m.ResponseCache != nil {
+		stats := m.ResponseCache()
+		snapshot.ResponseCache = &ResponseCacheSnapshot{
+			Hits:      stats.Hits,
+			Misses:    stats.Misses,
+			Evictions: stats.Evictions,
+			Entries:   stats.Entries,
+			Bytes:     stats.Bytes,
+		}
+	}
+	return snapshot
+}
+
+func (m *Metrics) rateLimiters() map[string]ratelimit.Stats {
+	if m.RateLimiters == nil {
+		return nil
+	}
+	return m.RateLimiters()
+}
+
+// WritePrometheus writes the metrics in the Prometheus text exposition format.
+func (m *Metrics) WritePrometheus(w io.Writer) error {
+	var bw bytes.Buffer
+	metric := func(name, kind, help string) {
+		fmt.Fprintf(&bw, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+	}
+	sample := func(name string, value float64, labels ...string) {
+		bw.WriteString(name)
+		if len(labels) > 0 {
+			bw.WriteByte('{')
+			for i := 0; i+1 < len(labels); i += 2 {
+				if i > 0 {
+					bw.WriteByte(',')
+				}
+				fmt.Fprintf(&bw, "%s=\"%s\"", labels[i], escapeLabel(labels[i+1]))
+			}
+			bw.WriteByte('}')
+		}
+		fmt.Fprintf(&bw, " %s\n", strconv.FormatFloat(value, 'g', -1, 64))
+	}
+
+	metric("github_mcp_uptime_seconds", "gauge", "Time since the server started.")
+	sample("github_mcp_uptime_seconds", time.Since(m.start).Seconds())
+
+	m.mu.Lock()
+	tools := sortedKeys(m.tools)
+	metric("github_mcp_tool_calls_total", "counter", "Tool calls, by tool.")
+	for _, name := range tools {
+		sample("github_mcp_tool_calls_total", float64(m.tools[name].calls), "tool", name)
+	}
+	metric("github_mcp_tool_errors_total", "counter", "Failed tool calls, by tool and error code.")
+	for _, name := range tools {
+		errors := m.tools[name].errors
+		for _, code := range sortedKeys(errors) {
+			sample("github_mcp_tool_errors_total", float64(errors[code]), "tool", name, "code", code)
+		}
+	}
+	metric("github_mcp_tool_call_duration_seconds", "histogram", "Latency of tool calls, by tool.")
+	for _, name := range tools {
+		t := m.tools[name]
+		var cumulative int64
+		for i, bound := range latencyBuckets {
+			cumulative += t.buckets[i]
+			sample("github_mcp_tool_call_duration_seconds_bucket", float64(cumulative), "tool", name, "le", strconv.FormatFloat(bound, 'g', -1, 64))
+		}
+		sample("github_mcp_tool_call_duration_seconds_bucket", float64(t.calls), "tool", name, "le", "+Inf")
+		sample("github_mcp_tool_call_duration_seconds_sum", t.seconds, "tool", name)
+		sample("github_mcp_tool_call_duration_seconds_count", float64(t.calls), "tool", name)
+	}
+	metric("github_mcp_github_api_requests_total", "counter", "GitHub API requests, by API and response status (0 when no response was received).")
+	keys := make([]apiKey, 0, len(m.api))
+	for key := range m.api {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].api != keys[j].api {
+			return keys[i].api < keys[j].api
+		}
+		return keys[i].status < keys[j].status
+	})
+	for _, key := range keys {
+		sample("github_mcp_github_api_requests_total", float64(m.api[key]), "api", key.api, "status", strconv.Itoa(key.status))
+	}
+	m.mu.Unlock()
+
+	limiters := m.rateLimiters()
+	names := sortedKeys(limiters)
+	metric("github_mcp_rate_limit_waits_total", "counter", "Requests that waited on a client-side rate limiter, by limiter and priority.")
+	for _, name := range names {
+		stats := limiters[name]
+		for _, priority := range priorities {
+			sample("github_mcp_rate_limit_waits_total", float64(stats.Priority(priority).Waits), "limiter", name, "priority", priority.String())
+		}
+	}
+	metric("github_mcp_rate_limit_wait_seconds_total", "counter", "Time spent waiting on client-side rate limiters, by limiter and priority.")
+	for _, name := range names {
+		stats := limiters[name]
+		for _, priority := range priorities {
+			sample("github_mcp_rate_limit_wait_seconds_total", float64(stats.Priority(priority).WaitMs)/1000, "limiter", name, "priority", priority.String())
+		}
+	}
+
+	if m.ResponseCache != nil {
+		stats := m.ResponseCache()
+		metric("github_mcp_response_cache_hits_total", "counter", "GitHub API responses served from the cache after a 304 Not Modified.")
+		sample("github_mcp_response_cache_hits_total", float64(stats.Hits))
+		metric("github_mcp_response_cache_misses_total", "counter", "Cacheable GitHub API requests answered with a full response.")
+		sample("github_mcp_response_cache_misses_total", float64(stats.Misses))
+		metric("github_mcp_response_cache_evictions_total", "counter", "Responses evicted to keep the cache within its size.")
+		sample("github_mcp_response_cache_evictions_total", float64(stats.Evictions))
+		metric("github_mcp_response_cache_entries", "gauge", "Responses in the cache.")
+		sample("github_mcp_response_cache_entries", float64(stats.Entries))
+		metric("github_mcp_response_cache_bytes", "gauge", "Size of the response bodies in the cache.")
+		sample("github_mcp_response_cache_bytes", float64(stats.Bytes))
+	}
+
+	_, err := w.Write(bw.Bytes())
+	return err
+}
+
+// Handler serves the metrics in the Prometheus text exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_ = m.WritePrometheus(w)
+	})
+}
+
+// escapeLabel escapes a label value of the Prometheus text format.
+func escapeLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Transport records the GitHub API requests of a client in Metrics. Requests to paths ending
+// in /graphql count as GraphQL requests, others as REST requests.
+type Transport struct {
+	Base    http.RoundTripper
+	Metrics *Metrics
+}
+
+// NewTransport wraps base, or http.DefaultTransport if base is nil, to record requests in m.
+func NewTransport(base http.RoundTripper, m *Metrics) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Transport{Base: base, Metrics: m}
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.Base.RoundTrip(req)
+	api := APIREST
+	if strings.HasSuffix(req.URL.Path, "/graphql") {
+		api = APIGraphQL
+	}
+	status := 0
+	if resp != nil {
+		status = resp.StatusCode
+	}
+	t.Metrics.RecordAPICall(api, status)
+	return resp, err
+}
+
+type metricsKey struct{}
+
+// ContextWithMetrics returns a context carrying m, for the get_server_metrics tool.
+func ContextWithMetrics(ctx context.Context, m *Metrics) context.Context {
+	return context.WithValue(ctx, metricsKey{}, m)
+}
+
+// FromContext returns the metrics carried by ctx, if any.
+func FromContext(ctx context.Context) (*Metrics, bool) {
+	m, ok := ctx.Value(metricsKey{}).(*Metrics)
+	return m, ok && m != nil
+}
@@ -0,0 +1,140 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/github/github-mcp-server/pkg/httpcache"
+	"github.com/github/github-mcp-server/pkg/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics_Snapshot(t *testing.T) {
+	m := New()
+	m.RateLimiters = func() map[string]ratelimit.Stats {
+		return map[string]ratelimit.Stats{"requests": {CoreWaits: 3, Normal: ratelimit.PriorityStats{Waits: 2, WaitMs: 40}, Low: ratelimit.PriorityStats{Waits: 1, WaitMs: 900}}}
+	}
+	m.RecordToolCall("get_me", 100*time.Millisecond, "")
+	m.RecordToolCall("get_me", 300*time.Millisecond, "http_404")
+	m.RecordToolCall("create_issue", 2*time.Second, "")
+	m.RecordAPICall(APIREST, http.StatusOK)
+	m.RecordAPICall(APIREST, http.StatusOK)
+	m.RecordAPICall(APIGraphQL, 0)
+
+	snapshot := m.Snapshot()
+	assert.Equal(t, []ToolSnapshot{
+		{Name: "create_issue", Calls: 1, AvgLatencyMs: 2000, MaxLatencyMs: 2000},
+		{Name: "get_me", Calls: 2, Errors: 1, ErrorCodes: map[string]int64{"http_404": 1}, AvgLatencyMs: 200, MaxLatencyMs: 300},
+	}, snapshot.Tools)
+	assert.Equal(t, []APICallSnapshot{
+		{API: APIGraphQL, Status: 0, Count: 1},
+		{API: APIREST, Status: http.StatusOK, Count: 2},
+	}, snapshot.APICalls)
+	assert.Equal(t, []RateLimitWaitSnapshot{
+		{Limiter: "requests", Priority: "high"},
+		{Limiter: "requests", Priority: "normal", Waits: 2, WaitMs: 40},
+		{Limiter: "requests", Priority: "low", Waits: 1, WaitMs: 900},
+	}, snapshot.RateLimitWaits)
+
+	// Empty metrics report empty lists rather than null
+	encoded, err := json.Marshal(New().Snapshot())
+	require.NoError(t, err)
+	assert.Contains(t, string(encoded), `"tools":[]`)
+}
+
+func TestMetrics_WritePrometheus(t *testing.T) {
+	m := New()
+	m.RateLimiters = func() map[string]ratelimit.Stats {
+		return map[string]ratelimit.Stats{"code_search": {High: ratelimit.PriorityStats{Waits: 4, WaitMs: 1500}}}
+	}
+	m.RecordToolCall("get_me", 200*time.Millisecond, "")
+	m.RecordToolCall("get_me", 5*time.Second, `bad "code"`)
+	m.RecordAPICall(APIREST, http.StatusNotFound)
+
+	var out strings.Builder
+	require.NoError(t, m.WritePrometheus(&out))
+	text := out.String()
+
+	for _, line := range []string{
+		"# TYPE github_mcp_tool_calls_total counter\n",
+		"github_mcp_tool_calls_total{tool=\"get_me\"} 2\n",
+		"github_mcp_tool_errors_total{tool=\"get_me\",code=\"bad \\\"code\\\"\"} 1\n",
+		"# TYPE github_mcp_tool_call_duration_seconds histogram\n",
+		"github_mcp_tool_call_duration_seconds_bucket{tool=\"get_me\",le=\"0.1\"} 0\n",
+		"github_mcp_tool_call_duration_seconds_bucket{tool=\"get_me\",le=\"0.25\"} 1\n",
+		"github_mcp_tool_call_duration_seconds_bucket{tool=\"get_me\",le=\"5\"} 2\n",
+		"github_mcp_tool_call_duration_seconds_bucket{tool=\"get_me\",le=\"+Inf\"} 2\n",
+		"github_mcp_tool_call_duration_seconds_sum{tool=\"get_me\"} 5.2\n",
+		"github_mcp_tool_call_duration_seconds_count{tool=\"get_me\"} 2\n",
+		"github_mcp_github_api_requests_total{api=\"rest\",status=\"404\"} 1\n",
+		"github_mcp_rate_limit_waits_total{limiter=\"code_search\",priority=\"high\"} 4\n",
+		"github_mcp_rate_limit_wait_seconds_total{limiter=\"code_search\",priority=\"high\"} 1.5\n",
+	} {
+		assert.Contains(t, text, line)
+	}
+}
+
+func TestMetrics_ResponseCache(t *testing.T) {
+	m := New()
+	assert.Nil(t, m.Snapshot().ResponseCache)
+
+	m.ResponseCache = func() httpcache.Stats {
+		return httpcache.Stats{Hits: 7, Misses: 3, Evictions: 1, Entries: 2, Bytes: 2048}
+	}
+	assert.Equal(t, &ResponseCacheSnapshot{Hits: 7, Misses: 3, Evictions: 1, Entries: 2, Bytes: 2048}, m.Snapshot().ResponseCache)
+
+	var out strings.Builder
+	require.NoError(t, m.WritePrometheus(&out))
+	assert.Contains(t, out.String(), "github_mcp_response_cache_hits_total 7\n")
+	assert.Contains(t, out.String(), "github_mcp_response_cache_bytes 2048\n")
+}
+
+func TestTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	m := New()
+	client := &http.Client{Transport: NewTransport(nil, m)}
+	for _, path := range []string{"/user", "/missing", "/api/graphql"} {
+		resp, err := client.Get(server.URL + path)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+	}
+
+	assert.Equal(t, []APICallSnapshot{
+		{API: APIGraphQL, Status: http.StatusOK, Count: 1},
+		{API: APIREST, Status: http.StatusOK, Count: 1},
+		{API: APIREST, Status: http.StatusNotFound, Count: 1},
+	}, m.Snapshot().APICalls)
+}
+
+func TestHandler(t *testing.T) {
+	m := New()
+	m.RecordToolCall("get_me", time.Millisecond, "")
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Header().Get("Content-Type"), "text/plain")
+	assert.Contains(t, rec.Body.String(), "github_mcp_tool_calls_total{tool=\"get_me\"} 1\n")
+}
+
+func TestFromContext(t *testing.T) {
+	_, ok := FromContext(context.Background())
+	assert.False(t, ok)
+
+	m := New()
+	got, ok := FromContext(ContextWithMetrics(context.Background(), m))
+	assert.True(t, ok)
+	assert.Same(t, m, got)
+}
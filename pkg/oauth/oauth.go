@@ -0,0 +1,287 @@
+// Package oauth signs the server in to GitHub with the OAuth device flow: the user enters a code
+// shown by the server on GitHub, and the token obtained is kept in the keyring of the operating
+// system, so that later runs need no sign in and no token is pasted into configuration files.
+package oauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/github/github-mcp-server/pkg/keyring"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"golang.org/x/oauth2"
+)
+
+// KeyringService is the service tokens are stored under in the keyring, by GitHub hostname.
+const KeyringService = "github-mcp-server"
+
+// DefaultScopes are the scopes requested when none are configured, those the tools need.
+var DefaultScopes = []string{"repo", "read:org", "gist", "notifications", "workflow"}
+
+// Config configures the sign in of the server with an OAuth app.
+type Config struct {
+	// ClientID is the client ID of an OAuth app with the device flow enabled
+	ClientID string
+	// Scopes are the scopes requested, DefaultScopes when empty
+	Scopes []string
+	// Keyring keeps the token across runs. The token is only kept in memory when nil.
+	Keyring keyring.Keyring
+	// Prompt shows the code to the user when the request that needs a token carries no prompt
+	// of its own, see ContextWithPrompt. It writes to stderr when nil.
+	Prompt Prompt
+}
+
+// DeviceCode is the code the user enters at VerificationURI to authorize the server.
+type DeviceCode struct {
+	UserCode        string
+	VerificationURI string
+	ExpiresAt       time.Time
+}
+
+// Prompt asks the user to enter code. The server waits for the authorization meanwhile, and
+// stops waiting once it gets it, cancelling ctx. An error, such as the user declining, cancels
+// the sign in.
+type Prompt func(ctx context.Context, code DeviceCode) error
+
+// WriterPrompt returns a Prompt writing the code to w.
+func WriterPrompt(w io.Writer) Prompt {
+	return func(_ context.Context, code DeviceCode) error {
+		_, err := fmt.Fprintf(w, "To sign in to GitHub, open %s and enter the code %s\n", code.VerificationURI, code.UserCode)
+		return err
+	}
+}
+
+// ErrDeclined is returned when the user declines to sign in.
+var ErrDeclined = errors.New("the user declined to sign in to GitHub")
+
+// ElicitationPrompt returns a Prompt asking the user of session to enter the code through MCP
+// elicitation, or nil if the client does not support elicitation. Clients that fail to show
+// the request fall back to fallback, if not nil.
+func ElicitationPrompt(session *mcp.ServerSession, fallback Prompt) Prompt {
+	params := session.InitializeParams()
+	if params == nil || params.Capabilities == nil || params.Capabilities.Elicitation == nil {
+		return nil
+	}
+	return func(ctx context.Context, code DeviceCode) error {
+		result, err := session.Elicit(ctx, &mcp.ElicitParams{
+			Message: fmt.Sprintf("The GitHub MCP Server needs you to sign in to GitHub. Open %s and enter the code %s, then confirm here. The code expires at %s.",
+				code.VerificationURI, code.UserCode, code.ExpiresAt.Format(time.Kitchen)),
+			RequestedSchema: &jsonschema.Schema{Type: "object", Properties: map[string]*jsonschema.Schema{}},
+		})
+		switch {
+		case ctx.Err() != nil:
+			return nil
+		case err != nil:
+			if fallback == nil {
+				return fmt.Errorf("failed to ask the user to sign in: %w", err)
+			}
+			return fallback(ctx, code)
+		case result.Action != "accept":
+			return ErrDeclined
+		}
+		return nil
+	}
+}
+
+type promptKey struct{}
+
+// ContextWithPrompt returns a context whose requests show the code with prompt when they sign
+// in, such as a prompt of the MCP session making the request.
+func ContextWithPrompt(ctx context.Context, prompt Prompt) context.Context {
+	return context.WithValue(ctx, promptKey{}, prompt)
+}
+
+// PromptFromContext returns the prompt carried by ctx, if any.
+func PromptFromContext(ctx context.Context) (Prompt, bool) {
+	prompt, ok := ctx.Value(promptKey{}).(Prompt)
+	return prompt, ok && prompt != nil
+}
+
+// Authenticator provides the token of the signed in user, signing in when there is none.
+type Authenticator struct {
+	config  oauth2.Config
+	keyring keyring.Keyring
+	account string
+	prompt  Prompt
+	logger  *slog.Logger
+
+	// signIn is held by the request signing in, so that concurrent requests sign in once
+	signIn chan struct{}
+
+	mu    sync.Mutex
+	token string
+}
+
+// NewAuthenticator returns an Authenticator signing in to the GitHub instance at webURL, such
+// as https://github.com/, whose tokens are stored in the keyring under its hostname.
+func NewAuthenticator(cfg Config, webURL *url.URL, logger *slog.Logger) *Authenticator {
+	scopes := cfg.Scopes
+	if len(scopes) == 0 {
+		scopes = DefaultScopes
+	}
+	prompt := cfg.Prompt
+	if prompt == nil {
+		prompt = WriterPrompt(os.Stderr)
+	}
+	if logger == nil {
+		logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+	return &Authenticator{
+		config: oauth2.Config{
+			ClientID: cfg.ClientID,
+			Scopes:   scopes,
+			Endpoint: oauth2.Endpoint{
+				AuthURL:       webURL.JoinPath("login/oauth/authorize").String(),
+				DeviceAuthURL: webURL.JoinPath("login/device/code").String(),
+				TokenURL:      webURL.JoinPath("login/oauth/access_token").String(),
+			},
+		},
+		keyring: cfg.Keyring,
+		account: webURL.Hostname(),
+		prompt:  prompt,
+		logger:  logger,
+		signIn:  make(chan struct{}, 1),
+	}
+}
+
+// Token returns the token of the user: the last one obtained, else the one stored in the
+// keyring, else a new one from the device flow, which prompts the user with the prompt of ctx.
+func (a *Authenticator) Token(ctx context.Context) (string, error) {
+	if token := a.current(); token != "" {
+		return token, nil
+	}
+	select {
+	case a.signIn <- struct{}{}:
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+	defer func() { <-a.signIn }()
+	// Another request may have signed in meanwhile
+	if token := a.current(); token != "" {
+		return token, nil
+	}
+
+	if a.keyring != nil {
+		token, err := a.keyring.Get(KeyringService, a.account)
+		switch {
+		case err == nil && token != "":
+			a.setCurrent(token)
+			return token, nil
+		case err != nil && !errors.Is(err, keyring.ErrNotFound):
+			a.logger.Warn("failed to read GitHub token from keyring", "error", err)
+		}
+	}
+
+	token, err := a.login(ctx)
+	if err != nil {
+		return "", err
+	}
+	a.setCurrent(token)
+	a.logger.Info("signed in to GitHub", "host", a.account)
+	if a.keyring != nil {
+		if err := a.keyring.Set(KeyringService, a.account, token); err != nil {
+			a.logger.Warn("failed to store GitHub token in keyring, the next run will sign in again", "error", err)
+		}
+	}
+	return token, nil
+}
+
+// Forget drops token, rejected by GitHub, from memory and the keyring, so that the next request
+// signs in again. A newer token is kept.
+func (a *Authenticator) Forget(token string) {
+	a.mu.Lock()
+	if a.token != token {
+		a.mu.Unlock()
+		return
+	}
+	a.token = ""
+	a.mu.Unlock()
+
+	a.logger.Warn("GitHub rejected the stored token, the next request will sign in again", "host", a.account)
+	if a.keyring == nil {
+		return
+	}
+	if err := a.keyring.Delete(KeyringService, a.account); err != nil {
+		a.logger.Warn("failed to delete GitHub token from keyring", "error", err)
+	}
+}
+
+func (a *Authenticator) current() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.token
+}
+
+func (a *Authenticator) setCurrent(token string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.token = token
+}
+
+// login runs the device flow, prompting the user while it polls GitHub for the authorization.
+func (a *Authenticator) login(ctx context.Context) (string, error) {
+	auth, err := a.config.DeviceAuth(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to start signing in to GitHub: %w", err)
+	}
+	prompt, ok := PromptFromContext(ctx)
+	if !ok {
+		prompt = a.prompt
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	go func() {
+		if err := prompt(ctx, DeviceCode{UserCode: auth.UserCode, VerificationURI: auth.VerificationURI, ExpiresAt: auth.Expiry}); err != nil {
+			cancel(err)
+		}
+	}()
+
+	token, err := a.config.DeviceAccessToken(ctx, auth)
+	if err != nil {
+		if cause := context.Cause(ctx); cause != nil {
+			err = cause
+		}
+		return "", fmt.Errorf("failed to sign in to GitHub: %w", err)
+	}
+	return token.AccessToken, nil
+}
+
+// Transport authenticates requests with the token of the signed in user, signing in first if
+// needed. A token GitHub rejects is forgotten, so that the next request signs in again.
+type Transport struct {
+	Auth *Authenticator
+	Base http.RoundTripper
+}
+
+// NewTransport wraps base, or http.DefaultTransport if base is nil, to authenticate requests
+// with the tokens of auth.
+func NewTransport(auth *Authenticator, base http.RoundTripper) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Transport{Auth: auth, Base: base}
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.Auth.Token(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := t.Base.RoundTrip(req)
+	if err == nil && resp.StatusCode == http.StatusUnauthorized {
+		t.Auth.Forget(token)
+	}
+	return resp, err
+}
@@ -0,0 +1,280 @@
+package oauth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/github/github-mcp-server/pkg/keyring"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryKeyring is a keyring.Keyring kept in memory
+type memoryKeyring struct {
+	mu      sync.Mutex
+	secrets map[string]string
+}
+
+func newMemoryKeyring() *memoryKeyring {
+	return &memoryKeyring{secrets: make(map[string]string)}
+}
+
+func (m *memoryKeyring) Get(service, account string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	secret, ok := m.secrets[service+"/"+account]
+	if !ok {
+		return "", keyring.ErrNotFound
+	}
+	return secret, nil
+}
+
+func (m *memoryKeyring) Set(service, account, secret string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.secrets[service+"/"+account] = secret
+	return nil
+}
+
+func (m *memoryKeyring) Delete(service, account string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.secrets, service+"/"+account)
+	return nil
+}
+
+// deviceFlowServer serves the device flow of GitHub, authorizing each device code once
+// *pending polls were answered authorization_pending, and the API, which rejects stale tokens.
+type deviceFlowServer struct {
+	*httptest.Server
+	codes   atomic.Int32
+	polls   atomic.Int32
+	pending atomic.Int32
+}
+
+func newDeviceFlowServer(t *testing.T) *deviceFlowServer {
+	t.Helper()
+	s := &deviceFlowServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, r.ParseForm())
+		switch r.URL.Path {
+		case "/login/device/code":
+			assert.Equal(t, "client-id", r.PostForm.Get("client_id"))
+			assert.Equal(t, "repo read:org", r.PostForm.Get("scope"))
+			n := s.codes.Add(1)
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"device_code":      fmt.Sprintf("device-%d", n),
+				"user_code":        fmt.Sprintf("CODE-%d", n),
+				"verification_uri": s.URL + "/login/device",
+				"expires_in":       900,
+				"interval":         1,
+			})
+		case "/login/oauth/access_token":
+			assert.Equal(t, "urn:ietf:params:oauth:grant-type:device_code", r.PostForm.Get("grant_type"))
+			// GitHub reports pending authorizations with a 200
+			if s.polls.Add(1) <= s.pending.Load() {
+				_, _ = w.Write([]byte(`{"error": "authorization_pending"}`))
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"access_token": "gho_" + r.PostForm.Get("device_code"),
+				"token_type":   "bearer",
+				"scope":        "repo,read:org",
+			})
+		case "/user":
+			if r.Header.Get("Authorization") == "Bearer gho_stale" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(`{"authorization": "` + r.Header.Get("Authorization") + `"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func newTestAuthenticator(t *testing.T, server *deviceFlowServer, ring keyring.Keyring, prompt Prompt) *Authenticator {
+	t.Helper()
+	webURL, err := url.Parse(server.URL + "/")
+	require.NoError(t, err)
+	return NewAuthenticator(Config{ClientID: "client-id", Scopes: []string{"repo", "read:org"}, Keyring: ring, Prompt: prompt}, webURL, nil)
+}
+
+func TestAuthenticator_SignsIn(t *testing.T) {
+	server := newDeviceFlowServer(t)
+	server.pending.Store(1)
+	ring := newMemoryKeyring()
+	var prompted []DeviceCode
+	var mu sync.Mutex
+	auth := newTestAuthenticator(t, server, ring, func(_ context.Context, code DeviceCode) error {
+		mu.Lock()
+		defer mu.Unlock()
+		prompted = append(prompted, code)
+		return nil
+	})
+
+	// Concurrent requests sign in once
+	var wg sync.WaitGroup
+	for range 3 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			token, err := auth.Token(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, "gho_device-1", token)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), server.codes.Load())
+	assert.Equal(t, int32(2), server.polls.Load())
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, prompted, 1)
+	assert.Equal(t, "CODE-1", prompted[0].UserCode)
+	assert.Equal(t, server.URL+"/login/device", prompted[0].VerificationURI)
+	assert.False(t, prompted[0].ExpiresAt.IsZero())
+
+	// The token is stored for the next runs, under the hostname
+	stored, err := ring.Get(KeyringService, "127.0.0.1")
+	require.NoError(t, err)
+	assert.Equal(t, "gho_device-1", stored)
+}
+
+func TestAuthenticator_UsesStoredToken(t *testing.T) {
+	server := newDeviceFlowServer(t)
+	ring := newMemoryKeyring()
+	require.NoError(t, ring.Set(KeyringService, "127.0.0.1", "gho_stored"))
+	auth := newTestAuthenticator(t, server, ring, func(context.Context, DeviceCode) error {
+		t.Error("unexpected prompt")
+		return nil
+	})
+
+	token, err := auth.Token(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "gho_stored", token)
+	assert.Zero(t, server.codes.Load())
+}
+
+func TestAuthenticator_PromptOfContext(t *testing.T) {
+	server := newDeviceFlowServer(t)
+	auth := newTestAuthenticator(t, server, nil, func(context.Context, DeviceCode) error {
+		t.Error("the prompt of the context is used instead")
+		return nil
+	})
+
+	// A user declining to sign in fails the request without waiting for the authorization
+	ctx := ContextWithPrompt(context.Background(), func(context.Context, DeviceCode) error {
+		return ErrDeclined
+	})
+	_, err := auth.Token(ctx)
+	assert.ErrorIs(t, err, ErrDeclined)
+	assert.Zero(t, server.polls.Load())
+
+	var buf bytes.Buffer
+	token, err := auth.Token(ContextWithPrompt(context.Background(), WriterPrompt(&buf)))
+	require.NoError(t, err)
+	assert.Equal(t, "gho_device-2", token)
+	assert.Equal(t, "To sign in to GitHub, open "+server.URL+"/login/device and enter the code CODE-2\n", buf.String())
+}
+
+func TestTransport_SignsInAgainWhenTokenRejected(t *testing.T) {
+	server := newDeviceFlowServer(t)
+	ring := newMemoryKeyring()
+	require.NoError(t, ring.Set(KeyringService, "127.0.0.1", "gho_stale"))
+	auth := newTestAuthenticator(t, server, ring, func(context.Context, DeviceCode) error { return nil })
+	client := &http.Client{Transport: NewTransport(auth, nil)}
+
+	resp, err := client.Get(server.URL + "/user")
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	_, err = ring.Get(KeyringService, "127.0.0.1")
+	assert.ErrorIs(t, err, keyring.ErrNotFound)
+
+	resp, err = client.Get(server.URL + "/user")
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	var body map[string]string
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "Bearer gho_device-1", body["authorization"])
+	stored, err := ring.Get(KeyringService, "127.0.0.1")
+	require.NoError(t, err)
+	assert.Equal(t, "gho_device-1", stored)
+}
+
+func TestAuthenticator_DeviceFlowErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error": "device_flow_disabled"}`))
+	}))
+	defer server.Close()
+	webURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	auth := NewAuthenticator(Config{ClientID: "client-id"}, webURL, nil)
+
+	_, err = auth.Token(context.Background())
+	assert.ErrorContains(t, err, "failed to start signing in to GitHub")
+	assert.ErrorContains(t, err, "device_flow_disabled")
+}
+
+func TestElicitationPrompt(t *testing.T) {
+	ctx := context.Background()
+	connect := func(t *testing.T, opts *mcp.ClientOptions) *mcp.ServerSession {
+		t.Helper()
+		server := mcp.NewServer(&mcp.Implementation{Name: "server"}, nil)
+		client := mcp.NewClient(&mcp.Implementation{Name: "client"}, opts)
+		serverTransport, clientTransport := mcp.NewInMemoryTransports()
+		serverSession, err := server.Connect(ctx, serverTransport, nil)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = serverSession.Close() })
+		session, err := client.Connect(ctx, clientTransport, nil)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = session.Close() })
+		return serverSession
+	}
+	code := DeviceCode{UserCode: "ABCD-1234", VerificationURI: "https://github.com/login/device"}
+
+	// Clients without elicitation get no prompt
+	assert.Nil(t, ElicitationPrompt(connect(t, nil), nil))
+
+	action := "accept"
+	var message string
+	session := connect(t, &mcp.ClientOptions{
+		ElicitationHandler: func(_ context.Context, req *mcp.ElicitRequest) (*mcp.ElicitResult, error) {
+			message = req.Params.Message
+			if action == "" {
+				return nil, errors.New("no user interface")
+			}
+			return &mcp.ElicitResult{Action: action}, nil
+		},
+	})
+	prompt := ElicitationPrompt(session, nil)
+	require.NotNil(t, prompt)
+	require.NoError(t, prompt(ctx, code))
+	assert.Contains(t, message, "Open https://github.com/login/device and enter the code ABCD-1234")
+
+	action = "decline"
+	assert.ErrorIs(t, prompt(ctx, code), ErrDeclined)
+
+	// Clients that fail to show the request fall back to the fallback prompt
+	action = ""
+	var buf bytes.Buffer
+	require.NoError(t, ElicitationPrompt(session, WriterPrompt(&buf))(ctx, code))
+	assert.Contains(t, buf.String(), "ABCD-1234")
+	assert.ErrorContains(t, prompt(ctx, code), "failed to ask the user to sign in")
+}
@@ -0,0 +1,94 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrRetryBudgetExhausted is returned by RetryWithBackoff when the retry budget attached to the
+// context does not allow another attempt.
+var ErrRetryBudgetExhausted = errors.New("retry budget exhausted")
+
+// RetryBudget caps the retries spent by all the calls that make up a single multi-step operation,
+// such as the chunks of a chunked push. Without a shared budget each call retries independently
+// and a persistent failure multiplies into very long hangs.
+type RetryBudget struct {
+	// MaxRetries is the total number of retries allowed across the operation (0 disables retries)
+	MaxRetries int
+	// MaxWait is the total time that may be spent waiting between retries
+	MaxWait time.Duration
+
+	mu        sync.Mutex
+	retries   int
+	waited    time.Duration
+	exhausted bool
+}
+
+// BudgetUsage reports how much of a RetryBudget has been consumed
+type BudgetUsage struct {
+	MaxRetries  int   `json:"max_retries"`
+	RetriesUsed int   `json:"retries_used"`
+	MaxWaitMs   int64 `json:"max_wait_ms"`
+	WaitedMs    int64 `json:"waited_ms"`
+	Exhausted   bool  `json:"exhausted"`
+	RetriesLeft int   `json:"retries_left"`
+}
+
+// NewRetryBudget creates a budget allowing maxRetries retries and maxWait of total backoff
+func NewRetryBudget(maxRetries int, maxWait time.Duration) *RetryBudget {
+	return &RetryBudget{
+		MaxRetries: maxRetries,
+		MaxWait:    maxWait,
+	}
+}
+
+// DefaultRetryBudget returns the budget used for multi-step operations when none is configured
+func DefaultRetryBudget() *RetryBudget {
+	return NewRetryBudget(10, 2*time.Minute)
+}
+
+// Reserve consumes one retry and the given backoff from the budget. It returns false, and marks
+// the budget exhausted, if either would exceed the limits.
+func (b *RetryBudget) Reserve(backoff time.Duration) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.retries+1 > b.MaxRetries || b.waited+backoff > b.MaxWait {
+		b.exhausted = true
+		return false
+	}
+	b.retries++
+	b.waited += backoff
+	return true
+}
+
+// Usage returns a snapshot of the budget consumption
+func (b *RetryBudget) Usage() BudgetUsage {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return BudgetUsage{
+		MaxRetries:  b.MaxRetries,
+		RetriesUsed: b.retries,
+		MaxWaitMs:   b.MaxWait.Milliseconds(),
+		WaitedMs:    b.waited.Milliseconds(),
+		Exhausted:   b.exhausted,
+		RetriesLeft: b.MaxRetries - b.retries,
+	}
+}
+
+type retryBudgetKey struct{}
+
+// WithRetryBudget returns a context carrying budget. RetryWithBackoff calls made with the
+// returned context draw their retries from it.
+func WithRetryBudget(ctx context.Context, budget *RetryBudget) context.Context {
+	return context.WithValue(ctx, retryBudgetKey{}, budget)
+}
+
+// RetryBudgetFromContext returns the budget attached to ctx, if any
+func RetryBudgetFromContext(ctx context.Context) (*RetryBudget, bool) {
+	budget, ok := ctx.Value(retryBudgetKey{}).(*RetryBudget)
+	return budget, ok && budget != nil
+}
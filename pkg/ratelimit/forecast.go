@@ -0,0 +1,122 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// quotaWindow is the length of GitHub's core rate limit window
+	quotaWindow = time.Hour
+	// quotaReserveRatio is the share of the limit a fan-out leaves for other clients of the
+	// same token, matching the 90% margin used by New
+	quotaReserveRatio = 0.1
+)
+
+// Quota is a snapshot of a rate limit, such as the core one, as reported by the GitHub API.
+type Quota struct {
+	Limit     int       `json:"limit"`
+	Remaining int       `json:"remaining"`
+	Reset     time.Time `json:"reset"`
+}
+
+// FanOutTarget is one unit of a fan-out, such as a repository, with the number of API calls
+// it is expected to cost.
+type FanOutTarget struct {
+	Name  string
+	Calls int
+}
+
+// Wave is a group of targets that fit in one rate limit window.
+type Wave struct {
+	Index   int      `json:"index"`
+	Targets []string `json:"targets"`
+	Calls   int      `json:"calls"`
+	// NotBefore is when the wave can start without exceeding the quota
+	NotBefore time.Time `json:"not_before"`
+}
+
+// FanOutPlan forecasts the quota needed by a fan-out and schedules its targets in waves.
+type FanOutPlan struct {
+	EstimatedCalls int       `json:"estimated_calls"`
+	Limit          int       `json:"limit"`
+	Remaining      int       `json:"remaining"`
+	ResetAt        time.Time `json:"reset_at"`
+	// Reserve is the part of each window that the plan leaves unused
+	Reserve int `json:"reserve"`
+	// FitsCurrentWindow is true when every target can run before the quota resets
+	FitsCurrentWindow bool   `json:"fits_current_window"`
+	Waves             []Wave `json:"waves"`
+	// EstimatedFinish is when the last wave can start
+	EstimatedFinish time.Time `json:"estimated_finish"`
+	// RequiresApproval is set when the plan has to wait for quota resets, so that an operator
+	// can decide whether a job that spans several hours should run at all
+	RequiresApproval bool `json:"requires_approval"`
+}
+
+// PlanFanOut estimates the calls needed by targets and packs them, in order, into waves that
+// fit the remaining quota and then each following window. A target costing more than a whole
+// window gets a wave of its own. Targets are never split, so one target always runs in one wave.
+func PlanFanOut(targets []FanOutTarget, quota Quota, now time.Time) FanOutPlan {
+	reserve := int(float64(quota.Limit)*quotaReserveRatio + 0.5)
+	resetAt := quota.Reset
+	if resetAt.Before(now) {
+		resetAt = now
+	}
+
+	plan := FanOutPlan{
+		Limit:     quota.Limit,
+		Remaining: quota.Remaining,
+		ResetAt:   resetAt,
+		Reserve:   reserve,
+	}
+
+	wave := Wave{Index: 1, NotBefore: now}
+	capacity := quota.Remaining - reserve
+	// fullWindow is true while wave starts at a reset, with the whole limit available
+	fullWindow := false
+	nextStart := resetAt
+	for _, target := range targets {
+		plan.EstimatedCalls += target.Calls
+		// An empty wave in a full window takes the target even if it is too large,
+		// since waiting longer would not help
+		if wave.Calls+target.Calls > capacity && (!fullWindow || len(wave.Targets) > 0) {
+			if len(wave.Targets) > 0 {
+				plan.Waves = append(plan.Waves, wave)
+			}
+			wave = Wave{Index: len(plan.Waves) + 1, NotBefore: nextStart}
+			capacity = quota.Limit - reserve
+			fullWindow = true
+			nextStart = nextStart.Add(quotaWindow)
+		}
+		wave.Targets = append(wave.Targets, target.Name)
+		wave.Calls += target.Calls
+	}
+	if len(wave.Targets) > 0 {
+		plan.Waves = append(plan.Waves, wave)
+	}
+
+	plan.FitsCurrentWindow = len(plan.Waves) <= 1 && plan.EstimatedCalls <= quota.Remaining-reserve
+	plan.RequiresApproval = !plan.FitsCurrentWindow
+	plan.EstimatedFinish = now
+	if len(plan.Waves) > 0 {
+		plan.EstimatedFinish = plan.Waves[len(plan.Waves)-1].NotBefore
+	}
+	return plan
+}
+
+// WaitForWave blocks until wave can start or ctx is done.
+func WaitForWave(ctx context.Context, wave Wave) error {
+	wait := time.Until(wave.NotBefore)
+	if wait <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
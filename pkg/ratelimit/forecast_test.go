@@ -0,0 +1,72 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanFanOut_FitsCurrentWindow(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	quota := Quota{Limit: 5000, Remaining: 4000, Reset: now.Add(30 * time.Minute)}
+
+	plan := PlanFanOut([]FanOutTarget{{Name: "a", Calls: 1000}, {Name: "b", Calls: 2000}}, quota, now)
+
+	assert.Equal(t, 3000, plan.EstimatedCalls)
+	assert.Equal(t, 500, plan.Reserve)
+	assert.True(t, plan.FitsCurrentWindow)
+	assert.False(t, plan.RequiresApproval)
+	require.Len(t, plan.Waves, 1)
+	assert.Equal(t, []string{"a", "b"}, plan.Waves[0].Targets)
+	assert.Equal(t, now, plan.Waves[0].NotBefore)
+	assert.Equal(t, now, plan.EstimatedFinish)
+}
+
+func TestPlanFanOut_SchedulesWaves(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	reset := now.Add(20 * time.Minute)
+	quota := Quota{Limit: 1000, Remaining: 300, Reset: reset}
+
+	plan := PlanFanOut([]FanOutTarget{
+		{Name: "a", Calls: 150},
+		{Name: "b", Calls: 150},
+		{Name: "c", Calls: 600},
+		{Name: "d", Calls: 600},
+		{Name: "huge", Calls: 2000},
+	}, quota, now)
+
+	assert.Equal(t, 3500, plan.EstimatedCalls)
+	assert.False(t, plan.FitsCurrentWindow)
+	assert.True(t, plan.RequiresApproval)
+
+	// 200 calls are usable now, then 900 per window
+	require.Len(t, plan.Waves, 4)
+	assert.Equal(t, Wave{Index: 1, Targets: []string{"a"}, Calls: 150, NotBefore: now}, plan.Waves[0])
+	assert.Equal(t, Wave{Index: 2, Targets: []string{"b", "c"}, Calls: 750, NotBefore: reset}, plan.Waves[1])
+	assert.Equal(t, Wave{Index: 3, Targets: []string{"d"}, Calls: 600, NotBefore: reset.Add(time.Hour)}, plan.Waves[2])
+	// A target larger than a window still runs, alone, in a fresh window
+	assert.Equal(t, Wave{Index: 4, Targets: []string{"huge"}, Calls: 2000, NotBefore: reset.Add(2 * time.Hour)}, plan.Waves[3])
+	assert.Equal(t, reset.Add(2*time.Hour), plan.EstimatedFinish)
+}
+
+func TestPlanFanOut_QuotaBelowReserve(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	quota := Quota{Limit: 5000, Remaining: 100, Reset: now.Add(10 * time.Minute)}
+
+	plan := PlanFanOut([]FanOutTarget{{Name: "a", Calls: 10}}, quota, now)
+
+	require.Len(t, plan.Waves, 1)
+	assert.Equal(t, now.Add(10*time.Minute), plan.Waves[0].NotBefore)
+	assert.True(t, plan.RequiresApproval)
+}
+
+func TestWaitForWave(t *testing.T) {
+	require.NoError(t, WaitForWave(context.Background(), Wave{NotBefore: time.Now().Add(-time.Minute)}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, WaitForWave(ctx, Wave{NotBefore: time.Now().Add(time.Hour)}), context.Canceled)
+}
@@ -0,0 +1,252 @@
+package ratelimit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxConnectionNodes is the most nodes GitHub returns per page of a connection, assumed for
+// connections whose first or last argument cannot be resolved
+const maxConnectionNodes = 100
+
+// GraphQLRateLimit is the rateLimit object of the GitHub GraphQL API. Queries that select it,
+// for example with a field `RateLimit GraphQLRateLimit` in a githubv4 query, let Transport
+// reconcile the points it reserved with the real cost of the query.
+type GraphQLRateLimit struct {
+	Cost      int       `json:"cost"`
+	Limit     int       `json:"limit"`
+	Remaining int       `json:"remaining"`
+	ResetAt   time.Time `json:"resetAt"`
+}
+
+type graphQLCostKey struct{}
+
+// WithGraphQLCost returns a context whose GraphQL queries reserve points from the rate limiter
+// of Transport instead of their estimated cost, for queries whose cost is known better than
+// EstimateGraphQLCost can tell.
+func WithGraphQLCost(ctx context.Context, points int) context.Context {
+	return context.WithValue(ctx, graphQLCostKey{}, points)
+}
+
+// graphQLCostFromContext returns the points set with WithGraphQLCost, if any
+func graphQLCostFromContext(ctx context.Context) (int, bool) {
+	points, ok := ctx.Value(graphQLCostKey{}).(int)
+	return points, ok && points > 0
+}
+
+// EstimateGraphQLCost estimates the points GitHub charges for query the way GitHub computes
+// them: every connection is assumed to return as many nodes as its first or last argument asks
+// for, the requests needed to fetch each connection for all the nodes of its parents are added
+// up, and the total is divided by 100. Arguments given as variables are resolved from
+// variables; unknown ones count as 100 nodes. A query costs at least one point.
+func EstimateGraphQLCost(query string, variables map[string]any) int {
+	tokens := lexGraphQL(query)
+
+	// multipliers holds, for each open selection set, the number of times it is fetched
+	multipliers := []int{1}
+	requests := 0
+	// nodes is the page size of the field whose selection set may open next
+	nodes := 0
+	for i := 0; i < len(tokens); i++ {
+		switch tokens[i] {
+		case "{":
+			multiplier := multipliers[len(multipliers)-1]
+			if nodes > 0 {
+				requests += multiplier
+				multiplier *= nodes
+			}
+			multipliers = append(multipliers, multiplier)
+			nodes = 0
+		case "}":
+			if len(multipliers) > 1 {
+				multipliers = multipliers[:len(multipliers)-1]
+			}
+			nodes = 0
+		case "(":
+			end := closingParen(tokens, i)
+			// The parentheses of an operation hold its variable definitions
+			if len(multipliers) > 1 {
+				if n := pageSize(tokens[i+1:end], variables); n > 0 {
+					nodes = n
+				}
+			}
+			i = end
+		case "@":
+			// Directives, and their arguments, leave the page size of their field alone
+			i++
+			if i+1 < len(tokens) && tokens[i+1] == "(" {
+				i = closingParen(tokens, i+1)
+			}
+		case ":":
+		default:
+			nodes = 0
+		}
+	}
+
+	return max(int(math.Round(float64(requests)/100)), 1)
+}
+
+// pageSize returns the first or last argument among the arguments of a field, 0 if it has none.
+func pageSize(arguments []string, variables map[string]any) int {
+	depth := 0
+	for i := 0; i+2 < len(arguments); i++ {
+		switch arguments[i] {
+		case "(", "[", "{":
+			depth++
+			continue
+		case ")", "]", "}":
+			depth--
+			continue
+		}
+		if depth > 0 || (arguments[i] != "first" && arguments[i] != "last") || arguments[i+1] != ":" {
+			continue
+		}
+		value := arguments[i+2]
+		if value == "$" && i+3 < len(arguments) {
+			switch n := variables[arguments[i+3]].(type) {
+			case int:
+				return n
+			case int32:
+				return int(n)
+			case int64:
+				return int(n)
+			case float64:
+				return int(n)
+			case json.Number:
+				if v, err := n.Int64(); err == nil {
+					return int(v)
+				}
+			}
+			return maxConnectionNodes
+		}
+		var n int
+		if err := json.Unmarshal([]byte(value), &n); err == nil {
+			return n
+		}
+		return maxConnectionNodes
+	}
+	return 0
+}
+
+// closingParen returns the index of the parenthesis that closes the one at open, or the last
+// index if it is never closed.
+func closingParen(tokens []string, open int) int {
+	depth := 0
+	for i := open; i < len(tokens); i++ {
+		switch tokens[i] {
+		case "(":
+			depth++
+		case ")":
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(tokens) - 1
+}
+
+// lexGraphQL splits a GraphQL document into punctuators, names, numbers and strings, leaving
+// out whitespace, commas and comments.
+func lexGraphQL(query string) []string {
+	var tokens []string
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',':
+			i++
+		case c == '#':
+			for i < len(query) && query[i] != '\n' {
+				i++
+			}
+		case c == '"':
+			start := i
+			if strings.HasPrefix(query[i:], `"""`) {
+				end := strings.Index(query[i+3:], `"""`)
+				if end < 0 {
+					return append(tokens, query[start:])
+				}
+				i += end + 6
+			} else {
+				for i++; i < len(query) && query[i] != '"' && query[i] != '\n'; i++ {
+					if query[i] == '\\' {
+						i++
+					}
+				}
+				i++
+			}
+			tokens = append(tokens, query[start:min(i, len(query))])
+		case strings.HasPrefix(query[i:], "..."):
+			tokens = append(tokens, "...")
+			i += 3
+		case isGraphQLNameChar(c) || c == '-':
+			// Numbers may have a fraction, names may not
+			number := c == '-' || (c >= '0' && c <= '9')
+			start := i
+			for i++; i < len(query) && (isGraphQLNameChar(query[i]) || (number && query[i] == '.')); i++ {
+			}
+			tokens = append(tokens, query[start:i])
+		default:
+			tokens = append(tokens, string(c))
+			i++
+		}
+	}
+	return tokens
+}
+
+func isGraphQLNameChar(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// graphQLRequest is the body of a GraphQL request
+type graphQLRequest struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables"`
+}
+
+// graphQLPoints returns the points req reserves: those set with WithGraphQLCost, or else the
+// estimated cost of its query. Requests whose body cannot be read again, as GetBody is not set,
+// count as one point.
+func graphQLPoints(req *http.Request) (graphQLRequest, int) {
+	var body graphQLRequest
+	if req.GetBody != nil {
+		if reader, err := req.GetBody(); err == nil {
+			_ = json.NewDecoder(reader).Decode(&body)
+			_ = reader.Close()
+		}
+	}
+	if points, ok := graphQLCostFromContext(req.Context()); ok {
+		return body, points
+	}
+	if body.Query == "" {
+		return body, 1
+	}
+	return body, EstimateGraphQLCost(body.Query, body.Variables)
+}
+
+// readGraphQLRateLimit returns the rateLimit object of a GraphQL response, if it selects one,
+// and leaves the body of resp to be read again.
+func readGraphQLRateLimit(resp *http.Response) (GraphQLRateLimit, bool) {
+	data, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+	if err != nil {
+		return GraphQLRateLimit{}, false
+	}
+
+	var body struct {
+		Data struct {
+			RateLimit *GraphQLRateLimit `json:"rateLimit"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(data, &body); err != nil || body.Data.RateLimit == nil {
+		return GraphQLRateLimit{}, false
+	}
+	return *body.Data.RateLimit, true
+}
@@ -0,0 +1,93 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateGraphQLCost(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		variables map[string]any
+		expected  int
+	}{
+		{
+			name:     "no connections",
+			query:    `query { viewer { login } }`,
+			expected: 1,
+		},
+		{
+			// The example of the GitHub documentation: 1 + 100 + 5000 requests
+			name: "nested connections",
+			query: `query {
+				viewer {
+					login
+					repositories(first: 100) {
+						edges { node { id issues(first: 50) {
+							edges { node { id labels(first: 60) { edges { node { id name } } } } }
+						} } }
+					}
+				}
+			}`,
+			expected: 51,
+		},
+		{
+			name:      "variables",
+			query:     `query($owner: String!, $first: Int = 10) { repositoryOwner(login: $owner) { repositories(first: $first) { nodes { issues(last: $first) { totalCount } } } } }`,
+			variables: map[string]any{"owner": "octo", "first": float64(100)},
+			expected:  1,
+		},
+		{
+			name:      "unknown variable counts as a full page",
+			query:     `query { a(first: $missing) { nodes { b(first: 10) { nodes { c(first: 1) { nodes { id } } } } } } }`,
+			variables: map[string]any{},
+			expected:  11,
+		},
+		{
+			name:     "aliases, fragments, directives and strings",
+			query:    `query { one: search(query: "is:open (label:bug)", type: ISSUE, first: 100) { nodes { ... on Issue { comments(first: 20) @include(if: true) { nodes { body } } } } } two: search(query: "}", type: ISSUE, first: 100) { nodes { ... on Issue { labels(last: 30) { nodes { name } } } } } }`,
+			expected: 2,
+		},
+		{
+			name:     "mutation",
+			query:    `mutation($input: AddCommentInput!) { addComment(input: $input) { clientMutationId } }`,
+			expected: 1,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, EstimateGraphQLCost(tc.query, tc.variables))
+		})
+	}
+}
+
+func TestRateLimiter_WaitGraphQLN(t *testing.T) {
+	limiter := NewDefault()
+	require.NoError(t, limiter.WaitGraphQLN(context.Background(), 4))
+	assert.InDelta(t, 6, limiter.GetTokens().GraphQL, 0.5)
+
+	// A cost above the burst waits for the burst only
+	limiter = NewDefault()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, limiter.WaitGraphQLN(ctx, 500))
+	assert.InDelta(t, 0, limiter.GetTokens().GraphQL, 0.5)
+	assert.Equal(t, int64(1), limiter.GetStats().GraphQLWaits)
+}
+
+func TestRateLimiter_ReconcileGraphQL(t *testing.T) {
+	limiter := NewDefault()
+	require.NoError(t, limiter.WaitGraphQLN(context.Background(), 2))
+
+	// The query cost 5 points where 2 were reserved
+	limiter.ReconcileGraphQL(2, GraphQLRateLimit{Cost: 5, Limit: 5000, Remaining: 4000, ResetAt: time.Now().Add(time.Hour)})
+	assert.InDelta(t, 5, limiter.GetTokens().GraphQL, 0.5)
+
+	limiter.ReconcileGraphQL(5, GraphQLRateLimit{Cost: 1, Limit: 5000, Remaining: 0, ResetAt: time.Now().Add(time.Hour)})
+	assert.False(t, limiter.AllowGraphQL())
+}
@@ -0,0 +1,182 @@
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// BucketState is the state of the limiter of one kind of request
+type BucketState struct {
+	// Tokens are the requests the limiter let through without waiting
+	Tokens float64 `json:"tokens"`
+	// Quota is the last quota GitHub reported, if any
+	Quota *Quota `json:"quota,omitempty"`
+}
+
+// State is the state of a RateLimiter worth keeping across restarts: the requests it let
+// through and the quota GitHub last reported, with when it resets. A restarted server that
+// restores it keeps pacing requests where it left off instead of starting with full buckets.
+type State struct {
+	SavedAt time.Time   `json:"saved_at"`
+	Core    BucketState `json:"core"`
+	Search  BucketState `json:"search"`
+	GraphQL BucketState `json:"graphql"`
+}
+
+// State returns the current state of r.
+func (r *RateLimiter) State() State {
+	now := time.Now()
+	state := func(b *bucket) BucketState {
+		r.mu.RLock()
+		defer r.mu.RUnlock()
+		s := BucketState{Tokens: b.limiter.TokensAt(now)}
+		if b.quota.Limit > 0 {
+			quota := b.quota
+			s.Quota = &quota
+		}
+		return s
+	}
+	return State{SavedAt: now, Core: state(r.core), Search: state(r.search), GraphQL: state(r.graphql)}
+}
+
+// Restore brings r to the state s it was saved in. Quotas that have not reset yet are applied
+// as if GitHub had just reported them, and each limiter is drained to the tokens it had, plus
+// those it earned since s was saved.
+func (r *RateLimiter) Restore(s State) {
+	r.restore(r.core, "core", s.Core, s.SavedAt)
+	r.restore(r.search, "search", s.Search, s.SavedAt)
+	r.restore(r.graphql, "graphql", s.GraphQL, s.SavedAt)
+}
+
+func (r *RateLimiter) restore(b *bucket, resource string, s BucketState, savedAt time.Time) {
+	if s.Quota != nil && time.Now().Before(s.Quota.Reset) {
+		r.update(resource, s.Quota.Limit, s.Quota.Remaining, s.Quota.Reset)
+	}
+	now := time.Now()
+	tokens := s.Tokens + now.Sub(savedAt).Seconds()*float64(b.limiter.Limit())
+	// Reservations are capped at the burst, so a debt of tokens is drained in several
+	for drain := int(math.Round(b.limiter.TokensAt(now) - tokens)); drain > 0; {
+		n := min(drain, b.limiter.Burst())
+		b.limiter.ReserveN(now, n)
+		drain -= n
+	}
+}
+
+// States returns the state of the limiter of every identity of r, keyed by identity.
+func (r *Registry) States() map[string]State {
+	r.mu.Lock()
+	limiters := make(map[string]*RateLimiter, len(r.limiters))
+	for key, entry := range r.limiters {
+		limiters[key] = entry.limiter
+	}
+	r.mu.Unlock()
+
+	states := make(map[string]State, len(limiters))
+	for key, limiter := range limiters {
+		states[key] = limiter.State()
+	}
+	return states
+}
+
+// Restore restores the limiters of the identities of states, keyed as by States. States saved
+// longer than IdleTimeout ago are skipped, as r would have dropped their limiters by now.
+func (r *Registry) Restore(states map[string]State) {
+	for key, s := range states {
+		if time.Since(s.SavedAt) >= r.idleTimeout() {
+			continue
+		}
+		r.Get(key).Restore(s)
+	}
+}
+
+// Store persists the states of rate limiters across restarts, keyed by limiter.
+type Store interface {
+	// Load returns the saved states, none if nothing was saved yet.
+	Load() (map[string]State, error)
+	Save(states map[string]State) error
+}
+
+// stateFileVersion is the version of the format of FileStore files
+const stateFileVersion = 1
+
+type stateFile struct {
+	Version  int              `json:"version"`
+	Limiters map[string]State `json:"limiters"`
+}
+
+// FileStore is a Store keeping the states in a JSON file at Path, readable by its owner only.
+// Limiters are keyed by identities hashed by RequestKey, so the file holds no tokens.
+type FileStore struct {
+	Path string
+}
+
+func (f FileStore) Load() (map[string]State, error) {
+	data, err := os.ReadFile(f.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rate limiter state: %w", err)
+	}
+	var file stateFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse rate limiter state %s: %w", f.Path, err)
+	}
+	if file.Version != stateFileVersion {
+		return nil, fmt.Errorf("unsupported rate limiter state version %d in %s", file.Version, f.Path)
+	}
+	return file.Limiters, nil
+}
+
+// Save writes states to a temporary file renamed over Path, so that a crash never leaves a
+// partial file behind.
+func (f FileStore) Save(states map[string]State) error {
+	data, err := json.Marshal(stateFile{Version: stateFileVersion, Limiters: states})
+	if err != nil {
+		return fmt.Errorf("failed to encode rate limiter state: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(f.Path), filepath.Base(f.Path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to save rate limiter state: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to save rate limiter state: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save rate limiter state: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), f.Path); err != nil {
+		return fmt.Errorf("failed to save rate limiter state: %w", err)
+	}
+	return nil
+}
+
+// Persist saves the states returned by states to store every interval until ctx is done, and
+// once more then. Failed saves are reported to onError, if not nil, and retried at the next
+// interval.
+func Persist(ctx context.Context, store Store, interval time.Duration, states func() map[string]State, onError func(error)) {
+	save := func() {
+		if err := store.Save(states()); err != nil && onError != nil {
+			onError(err)
+		}
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			save()
+			return
+		case <-ticker.C:
+			save()
+		}
+	}
+}
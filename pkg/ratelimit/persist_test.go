@@ -0,0 +1,180 @@
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiter_StateRestore(t *testing.T) {
+	limiter := New(GitHubLimits{CoreRequestsPerHour: 3600, SearchRequestsPerMinute: 30, GraphQLPointsPerHour: 3600})
+	limiter.SetBurst(10, 5, 10)
+	for range 8 {
+		require.NoError(t, limiter.WaitCore(context.Background()))
+	}
+	reset := time.Now().Add(time.Hour)
+	limiter.update("search", 30, 0, reset)
+
+	state := limiter.State()
+	assert.InDelta(t, 2, state.Core.Tokens, 0.1)
+	assert.Nil(t, state.Core.Quota)
+	require.NotNil(t, state.Search.Quota)
+	assert.Equal(t, Quota{Limit: 30, Remaining: 0, Reset: reset}, *state.Search.Quota)
+
+	// The state survives a round trip through JSON
+	encoded, err := json.Marshal(state)
+	require.NoError(t, err)
+	var decoded State
+	require.NoError(t, json.Unmarshal(encoded, &decoded))
+
+	restored := New(GitHubLimits{CoreRequestsPerHour: 3600, SearchRequestsPerMinute: 30, GraphQLPointsPerHour: 3600})
+	restored.SetBurst(10, 5, 10)
+	restored.Restore(decoded)
+	tokens := restored.GetTokens()
+	assert.InDelta(t, 2, tokens.Core, 0.1)
+	assert.InDelta(t, 10, tokens.GraphQL, 0.1)
+	// The used up search quota still blocks searches until it resets
+	assert.Zero(t, tokens.Search)
+	assert.False(t, restored.AllowSearch())
+}
+
+func TestRateLimiter_RestoreRefills(t *testing.T) {
+	limiter := New(GitHubLimits{CoreRequestsPerHour: 3600, SearchRequestsPerMinute: 30, GraphQLPointsPerHour: 3600})
+	limiter.SetBurst(10, 5, 10)
+
+	// Tokens earned since the state was saved are added back, and quotas that have reset since
+	// are ignored
+	limiter.Restore(State{
+		SavedAt: time.Now().Add(-10 * time.Second),
+		Core:    BucketState{Tokens: -5, Quota: &Quota{Limit: 5000, Remaining: 0, Reset: time.Now().Add(-time.Second)}},
+		Search:  BucketState{Tokens: 5},
+		GraphQL: BucketState{Tokens: -20},
+	})
+	tokens := limiter.GetTokens()
+	// A debt of 5 tokens, plus 0.9 per second for 10 seconds
+	assert.InDelta(t, 4, tokens.Core, 0.1)
+	assert.InDelta(t, 5, tokens.Search, 0.1)
+	// A debt beyond the burst is restored too
+	assert.InDelta(t, -11, tokens.GraphQL, 0.1)
+	assert.True(t, limiter.AllowCore())
+	assert.False(t, limiter.AllowGraphQL())
+}
+
+func TestRegistry_StatesRestore(t *testing.T) {
+	newLimiter := func() *RateLimiter {
+		limiter := NewDefault()
+		limiter.SetBurst(10, 5, 10)
+		return limiter
+	}
+	registry := NewRegistry(newLimiter, time.Hour)
+	for range 6 {
+		require.NoError(t, registry.Get("token:a").WaitCore(context.Background()))
+	}
+	registry.Get("token:b")
+
+	states := registry.States()
+	require.Len(t, states, 2)
+	assert.InDelta(t, 4, states["token:a"].Core.Tokens, 0.1)
+
+	stale := states["token:b"]
+	stale.SavedAt = time.Now().Add(-2 * time.Hour)
+	states["token:b"] = stale
+
+	restored := NewRegistry(newLimiter, time.Hour)
+	restored.Restore(states)
+	// States older than the idle timeout are skipped
+	assert.Equal(t, 1, restored.Stats().Limiters)
+	assert.InDelta(t, 4, restored.Get("token:a").GetTokens().Core, 0.1)
+}
+
+func TestFileStore(t *testing.T) {
+	store := FileStore{Path: filepath.Join(t.TempDir(), "rate-limits.json")}
+
+	states, err := store.Load()
+	require.NoError(t, err)
+	assert.Empty(t, states)
+
+	saved := map[string]State{
+		"requests/token:abc": {
+			SavedAt: time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC),
+			Core:    BucketState{Tokens: 42, Quota: &Quota{Limit: 5000, Remaining: 1200, Reset: time.Date(2026, 10, 16, 12, 30, 0, 0, time.UTC)}},
+		},
+	}
+	require.NoError(t, store.Save(saved))
+	info, err := os.Stat(store.Path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	states, err = store.Load()
+	require.NoError(t, err)
+	assert.Equal(t, saved, states)
+
+	// Saving again replaces the file without leaving temporary files behind
+	require.NoError(t, store.Save(map[string]State{}))
+	entries, err := os.ReadDir(filepath.Dir(store.Path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	require.NoError(t, os.WriteFile(store.Path, []byte(`{"version":2}`), 0600))
+	_, err = store.Load()
+	assert.ErrorContains(t, err, "unsupported rate limiter state version 2")
+}
+
+// memoryStore is a Store that counts its saves and fails them on demand
+type memoryStore struct {
+	mu     sync.Mutex
+	saves  int
+	failOn int
+	states map[string]State
+}
+
+func (m *memoryStore) Load() (map[string]State, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.states, nil
+}
+
+func (m *memoryStore) Save(states map[string]State) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.saves++
+	if m.saves == m.failOn {
+		return errors.New("disk full")
+	}
+	m.states = states
+	return nil
+}
+
+func TestPersist(t *testing.T) {
+	store := &memoryStore{failOn: 1}
+	ctx, cancel := context.WithCancel(context.Background())
+	var errs []error
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		Persist(ctx, store, 10*time.Millisecond, func() map[string]State {
+			return map[string]State{"code_search": {}}
+		}, func(err error) { errs = append(errs, err) })
+	}()
+
+	require.Eventually(t, func() bool {
+		store.mu.Lock()
+		defer store.mu.Unlock()
+		return store.saves >= 2
+	}, time.Second, time.Millisecond)
+	cancel()
+	<-done
+
+	// The failed save is reported and the next one stores the states, as does the last one
+	assert.Equal(t, []error{errors.New("disk full")}, errs)
+	assert.Contains(t, store.states, "code_search")
+	assert.GreaterOrEqual(t, store.saves, 3)
+}
@@ -0,0 +1,153 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Priority orders the requests waiting on a RateLimiter: while requests of a priority wait,
+// requests of lower priorities do not get through, so that background work queued behind a
+// tight quota never holds up interactive calls.
+type Priority int
+
+// Priorities of requests, set with WithPriority
+const (
+	// PriorityNormal is the priority of requests without one, such as the calls of write tools
+	PriorityNormal Priority = iota
+	// PriorityHigh is for interactive reads, which someone is waiting on
+	PriorityHigh
+	// PriorityLow is for background bulk work, such as chunked pushes and imports
+	PriorityLow
+)
+
+// numPriorities is the number of priorities, ranked from 0 for the most urgent
+const numPriorities = 3
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityHigh:
+		return "high"
+	case PriorityLow:
+		return "low"
+	default:
+		return "normal"
+	}
+}
+
+// rank returns 0 for the most urgent priority, up to numPriorities-1 for the least urgent.
+func (p Priority) rank() int {
+	switch p {
+	case PriorityHigh:
+		return 0
+	case PriorityLow:
+		return 2
+	default:
+		return 1
+	}
+}
+
+type priorityKey struct{}
+
+// WithPriority returns a context whose requests wait on rate limiters at priority p.
+func WithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, p)
+}
+
+// PriorityFromContext returns the priority set with WithPriority, PriorityNormal if none is.
+func PriorityFromContext(ctx context.Context) Priority {
+	p, _ := ctx.Value(priorityKey{}).(Priority)
+	return p
+}
+
+// PriorityStats are the waits of the requests of one priority
+type PriorityStats struct {
+	Waits  int64
+	WaitMs int64
+}
+
+// priorityQueue counts the requests waiting on a bucket by priority.
+type priorityQueue struct {
+	mu      sync.Mutex
+	waiting [numPriorities]int
+	// changed is closed, and replaced, when the waiting requests change
+	changed chan struct{}
+}
+
+func (q *priorityQueue) enter(rank int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.waiting[rank]++
+	q.broadcast()
+}
+
+func (q *priorityQueue) leave(rank int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.waiting[rank]--
+	q.broadcast()
+}
+
+// broadcast wakes up the requests waiting for a change. q.mu must be held.
+func (q *priorityQueue) broadcast() {
+	if q.changed != nil {
+		close(q.changed)
+		q.changed = nil
+	}
+}
+
+// preempted reports whether requests more urgent than rank are waiting, along with a channel
+// closed on the next change of the waiting requests.
+func (q *priorityQueue) preempted(rank int) (bool, <-chan struct{}) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.changed == nil {
+		q.changed = make(chan struct{})
+	}
+	for i := 0; i < rank; i++ {
+		if q.waiting[i] > 0 {
+			return true, q.changed
+		}
+	}
+	return false, q.changed
+}
+
+// waitN waits for n tokens of limiter at priority p. A request that has to wait gives its
+// reservation back as soon as a more urgent request starts waiting, and reserves again once
+// none is left.
+func (q *priorityQueue) waitN(ctx context.Context, limiter *rate.Limiter, n int, p Priority) error {
+	rank := p.rank()
+	q.enter(rank)
+	defer q.leave(rank)
+
+	for {
+		preempted, changed := q.preempted(rank)
+		if preempted {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-changed:
+			}
+			continue
+		}
+
+		reservation := limiter.ReserveN(time.Now(), n)
+		timer := time.NewTimer(reservation.Delay())
+		for !preempted {
+			select {
+			case <-timer.C:
+				return nil
+			case <-ctx.Done():
+				timer.Stop()
+				reservation.Cancel()
+				return ctx.Err()
+			case <-changed:
+			}
+			preempted, changed = q.preempted(rank)
+		}
+		timer.Stop()
+		reservation.Cancel()
+	}
+}
@@ -0,0 +1,77 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriorityFromContext(t *testing.T) {
+	assert.Equal(t, PriorityNormal, PriorityFromContext(context.Background()))
+	assert.Equal(t, PriorityLow, PriorityFromContext(WithPriority(context.Background(), PriorityLow)))
+	assert.Equal(t, "high", PriorityHigh.String())
+	assert.Equal(t, "normal", PriorityNormal.String())
+	assert.Equal(t, "low", PriorityLow.String())
+}
+
+// drainedLimiter returns a limiter whose core bucket is empty and refills 10 tokens a second.
+func drainedLimiter() *RateLimiter {
+	limiter := New(GitHubLimits{CoreRequestsPerHour: 40000, SearchRequestsPerMinute: 30, GraphQLPointsPerHour: 5000})
+	limiter.SetBurst(1, 1, 1)
+	for limiter.AllowCore() {
+	}
+	return limiter
+}
+
+func TestRateLimiter_WaitCore_Priority(t *testing.T) {
+	limiter := drainedLimiter()
+
+	var mu sync.Mutex
+	var order []Priority
+	var wg sync.WaitGroup
+	start := func(p Priority) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, limiter.WaitCore(WithPriority(context.Background(), p)))
+			mu.Lock()
+			order = append(order, p)
+			mu.Unlock()
+		}()
+	}
+
+	// The low priority requests queue up first, and are overtaken by the later high one
+	start(PriorityLow)
+	start(PriorityLow)
+	time.Sleep(20 * time.Millisecond)
+	start(PriorityHigh)
+	wg.Wait()
+
+	assert.Equal(t, []Priority{PriorityHigh, PriorityLow, PriorityLow}, order)
+	stats := limiter.GetStats()
+	assert.Equal(t, int64(3), stats.CoreWaits)
+	assert.Equal(t, int64(1), stats.High.Waits)
+	assert.Equal(t, int64(0), stats.Normal.Waits)
+	assert.Equal(t, int64(2), stats.Low.Waits)
+	assert.Greater(t, stats.Low.WaitMs, stats.High.WaitMs)
+}
+
+func TestRateLimiter_WaitCore_CancelledWhilePreempted(t *testing.T) {
+	limiter := drainedLimiter()
+
+	done := make(chan error)
+	go func() {
+		done <- limiter.WaitCore(WithPriority(context.Background(), PriorityHigh))
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, limiter.WaitCore(WithPriority(ctx, PriorityLow)), context.DeadlineExceeded)
+	require.NoError(t, <-done)
+	assert.Equal(t, PriorityStats{}, limiter.GetStats().Low)
+}
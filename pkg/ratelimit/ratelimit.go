@@ -5,9 +5,15 @@ package ratelimit
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
 	"sync"
 	"time"
 
+	"github.com/google/go-github/v79/github"
 	"golang.org/x/time/rate"
 )
 
@@ -30,38 +36,81 @@ func DefaultLimits() GitHubLimits {
 	}
 }
 
+// safetyMargin is the share of a quota the limiters use, leaving the rest for other clients of
+// the same token
+const safetyMargin = 0.9
+
 // RateLimiter provides rate limiting for GitHub API calls
 type RateLimiter struct {
-	core    *rate.Limiter
-	search  *rate.Limiter
-	graphql *rate.Limiter
+	core    *bucket
+	search  *bucket
+	graphql *bucket
 	mu      sync.RWMutex
 
 	// Stats for monitoring
 	stats Stats
 }
 
+// bucket is the token bucket of one kind of request, retuned by UpdateFromResponse.
+type bucket struct {
+	limiter *rate.Limiter
+	// base is the configured rate, restored when a quota window resets
+	base rate.Limit
+	// window is the length of the GitHub quota window of the resource
+	window time.Duration
+	// blockedUntil holds requests back until the quota resets once it is used up
+	blockedUntil time.Time
+	// quota is the last quota GitHub reported for the resource, kept for State
+	quota Quota
+	// queue lets the most urgent waiting requests through first
+	queue priorityQueue
+}
+
+func newBucket(perWindow int, window time.Duration, burst int) *bucket {
+	base := rate.Limit(float64(perWindow) * safetyMargin / window.Seconds())
+	return &bucket{limiter: rate.NewLimiter(base, burst), base: base, window: window}
+}
+
 // Stats tracks rate limiter statistics
 type Stats struct {
 	CoreWaits    int64
 	SearchWaits  int64
 	GraphQLWaits int64
 	TotalWaitMs  int64
+	// High, Normal and Low break the waits down by Priority
+	High   PriorityStats
+	Normal PriorityStats
+	Low    PriorityStats
+}
+
+// Priority returns the stats of the waits at priority p.
+func (s *Stats) Priority(p Priority) *PriorityStats {
+	switch p {
+	case PriorityHigh:
+		return &s.High
+	case PriorityLow:
+		return &s.Low
+	default:
+		return &s.Normal
+	}
+}
+
+// Tokens is the number of requests each limiter of a RateLimiter lets through without waiting
+type Tokens struct {
+	Core    float64
+	Search  float64
+	GraphQL float64
 }
 
 // New creates a new RateLimiter with the specified limits
 func New(limits GitHubLimits) *RateLimiter {
 	// Convert hourly/minute limits to per-second rates
 	// Use 90% of the limit to provide safety margin
-	coreRate := rate.Limit(float64(limits.CoreRequestsPerHour) * 0.9 / 3600)
-	searchRate := rate.Limit(float64(limits.SearchRequestsPerMinute) * 0.9 / 60)
-	graphqlRate := rate.Limit(float64(limits.GraphQLPointsPerHour) * 0.9 / 3600)
-
 	return &RateLimiter{
 		// Burst allows some requests to go through immediately
-		core:    rate.NewLimiter(coreRate, 10),
-		search:  rate.NewLimiter(searchRate, 5),
-		graphql: rate.NewLimiter(graphqlRate, 10),
+		core:    newBucket(limits.CoreRequestsPerHour, time.Hour, 10),
+		search:  newBucket(limits.SearchRequestsPerMinute, time.Minute, 5),
+		graphql: newBucket(limits.GraphQLPointsPerHour, time.Hour, 10),
 	}
 }
 
@@ -70,58 +119,97 @@ func NewDefault() *RateLimiter {
 	return New(DefaultLimits())
 }
 
-// WaitCore waits for permission to make a core API request
+// WaitCore waits for permission to make a core API request, at the priority of ctx, see
+// WithPriority
 func (r *RateLimiter) WaitCore(ctx context.Context) error {
-	start := time.Now()
-	err := r.core.Wait(ctx)
-	if err == nil {
-		r.mu.Lock()
-		r.stats.CoreWaits++
-		r.stats.TotalWaitMs += time.Since(start).Milliseconds()
-		r.mu.Unlock()
-	}
-	return err
+	return r.wait(ctx, r.core, 1, &r.stats.CoreWaits)
 }
 
 // WaitSearch waits for permission to make a search API request
 func (r *RateLimiter) WaitSearch(ctx context.Context) error {
-	start := time.Now()
-	err := r.search.Wait(ctx)
-	if err == nil {
-		r.mu.Lock()
-		r.stats.SearchWaits++
-		r.stats.TotalWaitMs += time.Since(start).Milliseconds()
-		r.mu.Unlock()
-	}
-	return err
+	return r.wait(ctx, r.search, 1, &r.stats.SearchWaits)
 }
 
 // WaitGraphQL waits for permission to make a GraphQL API request
 func (r *RateLimiter) WaitGraphQL(ctx context.Context) error {
+	return r.wait(ctx, r.graphql, 1, &r.stats.GraphQLWaits)
+}
+
+// WaitGraphQLN waits for permission to make a GraphQL API request costing points, such as the
+// estimate of EstimateGraphQLCost. Costs above the GraphQL burst wait for the burst only, as
+// the bucket never holds more.
+func (r *RateLimiter) WaitGraphQLN(ctx context.Context, points int) error {
+	return r.wait(ctx, r.graphql, points, &r.stats.GraphQLWaits)
+}
+
+// ReconcileGraphQL charges the GraphQL limiter for the part of the cost of a query that
+// exceeded the reserved points, and retunes it to the quota reported by the rateLimit object
+// of the response. Overestimates are not refunded, but the remaining quota they leave is
+// spread over the rest of the window by the retune.
+func (r *RateLimiter) ReconcileGraphQL(reserved int, usage GraphQLRateLimit) {
+	if extra := usage.Cost - reserved; extra > 0 {
+		r.graphql.limiter.ReserveN(time.Now(), min(extra, r.graphql.limiter.Burst()))
+	}
+	r.update("graphql", usage.Limit, usage.Remaining, usage.ResetAt)
+}
+
+// wait waits for n tokens of b at the priority of ctx, first until its quota resets if it is
+// used up, and counts the wait in waits.
+func (r *RateLimiter) wait(ctx context.Context, b *bucket, n int, waits *int64) error {
 	start := time.Now()
-	err := r.graphql.Wait(ctx)
+	priority := PriorityFromContext(ctx)
+	if until := r.blocked(b); !until.IsZero() {
+		timer := time.NewTimer(time.Until(until))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	err := b.queue.waitN(ctx, b.limiter, max(min(n, b.limiter.Burst()), 1), priority)
 	if err == nil {
+		waited := time.Since(start).Milliseconds()
 		r.mu.Lock()
-		r.stats.GraphQLWaits++
-		r.stats.TotalWaitMs += time.Since(start).Milliseconds()
+		*waits++
+		r.stats.TotalWaitMs += waited
+		stats := r.stats.Priority(priority)
+		stats.Waits++
+		stats.WaitMs += waited
 		r.mu.Unlock()
 	}
 	return err
 }
 
+// blocked returns when the quota of b resets if it is used up, or the zero time. A block that
+// has expired is lifted and the configured rate restored, as a new quota window has started.
+func (r *RateLimiter) blocked(b *bucket) time.Time {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if b.blockedUntil.IsZero() {
+		return time.Time{}
+	}
+	if time.Now().Before(b.blockedUntil) {
+		return b.blockedUntil
+	}
+	b.blockedUntil = time.Time{}
+	b.limiter.SetLimit(b.base)
+	return time.Time{}
+}
+
 // AllowCore checks if a core API request can proceed without waiting
 func (r *RateLimiter) AllowCore() bool {
-	return r.core.Allow()
+	return r.blocked(r.core).IsZero() && r.core.limiter.Allow()
 }
 
 // AllowSearch checks if a search API request can proceed without waiting
 func (r *RateLimiter) AllowSearch() bool {
-	return r.search.Allow()
+	return r.blocked(r.search).IsZero() && r.search.limiter.Allow()
 }
 
 // AllowGraphQL checks if a GraphQL API request can proceed without waiting
 func (r *RateLimiter) AllowGraphQL() bool {
-	return r.graphql.Allow()
+	return r.blocked(r.graphql).IsZero() && r.graphql.limiter.Allow()
 }
 
 // GetStats returns the current rate limiter statistics
@@ -131,6 +219,21 @@ func (r *RateLimiter) GetStats() Stats {
 	return r.stats
 }
 
+// GetTokens returns the number of requests that can currently be made without waiting
+func (r *RateLimiter) GetTokens() Tokens {
+	tokens := func(b *bucket) float64 {
+		if !r.blocked(b).IsZero() {
+			return 0
+		}
+		return b.limiter.Tokens()
+	}
+	return Tokens{
+		Core:    tokens(r.core),
+		Search:  tokens(r.search),
+		GraphQL: tokens(r.graphql),
+	}
+}
+
 // ResetStats resets the rate limiter statistics
 func (r *RateLimiter) ResetStats() {
 	r.mu.Lock()
@@ -140,14 +243,77 @@ func (r *RateLimiter) ResetStats() {
 
 // ReserveN reserves n tokens from the core limiter and returns a Reservation
 func (r *RateLimiter) ReserveN(n int) *rate.Reservation {
-	return r.core.ReserveN(time.Now(), n)
+	return r.core.limiter.ReserveN(time.Now(), n)
 }
 
 // SetBurst sets the burst size for all limiters
 func (r *RateLimiter) SetBurst(core, search, graphql int) {
-	r.core.SetBurst(core)
-	r.search.SetBurst(search)
-	r.graphql.SetBurst(graphql)
+	r.core.limiter.SetBurst(core)
+	r.search.limiter.SetBurst(search)
+	r.graphql.limiter.SetBurst(graphql)
+}
+
+// UpdateFromResponse retunes the limiter of the resource a response counted against to the
+// quota GitHub reported with it. The remaining requests are spread over the time left until
+// the quota resets, never faster than the full quota allows; once none remain, requests wait
+// until the reset. Responses without rate limit headers are ignored.
+func (r *RateLimiter) UpdateFromResponse(resp *github.Response) {
+	if resp == nil {
+		return
+	}
+	r.update(resp.Rate.Resource, resp.Rate.Limit, resp.Rate.Remaining, resp.Rate.Reset.Time)
+}
+
+// UpdateFromHeader is UpdateFromResponse for the X-RateLimit headers of a raw HTTP response,
+// such as a GraphQL response.
+func (r *RateLimiter) UpdateFromHeader(header http.Header) {
+	limit, err := strconv.Atoi(header.Get(headerRateLimit))
+	if err != nil {
+		return
+	}
+	remaining, err := strconv.Atoi(header.Get(headerRateRemaining))
+	if err != nil {
+		return
+	}
+	reset, err := strconv.ParseInt(header.Get(headerRateReset), 10, 64)
+	if err != nil {
+		return
+	}
+	r.update(header.Get(headerRateResource), limit, remaining, time.Unix(reset, 0))
+}
+
+func (r *RateLimiter) update(resource string, limit, remaining int, reset time.Time) {
+	if limit <= 0 {
+		return
+	}
+	var b *bucket
+	switch resource {
+	case "", "core":
+		b = r.core
+	case "search", "code_search":
+		b = r.search
+	case "graphql":
+		b = r.graphql
+	default:
+		// Other resources, such as source imports, are not paced
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	b.quota = Quota{Limit: limit, Remaining: remaining, Reset: reset}
+	untilReset := time.Until(reset)
+	switch {
+	case untilReset <= 0:
+		b.blockedUntil = time.Time{}
+		b.limiter.SetLimit(b.base)
+	case remaining <= 0:
+		b.blockedUntil = reset
+	default:
+		b.blockedUntil = time.Time{}
+		ceiling := rate.Limit(float64(limit) * safetyMargin / b.window.Seconds())
+		b.limiter.SetLimit(min(rate.Limit(float64(remaining)*safetyMargin/untilReset.Seconds()), ceiling))
+	}
 }
 
 // RetryConfig defines retry behavior for rate-limited requests
@@ -156,8 +322,17 @@ type RetryConfig struct {
 	InitialBackoff time.Duration
 	MaxBackoff     time.Duration
 	BackoffFactor  float64
+	// Jitter waits a random duration between zero and the backoff instead of the backoff
+	// itself ("full jitter"), so that calls failing together do not retry together.
+	Jitter bool
+	// Retryable reports whether an error may go away if the call is repeated. Errors it
+	// rejects are returned without retrying. Every error is retried when nil.
+	Retryable RetryableClassifier
 }
 
+// RetryableClassifier reports whether a failed attempt may succeed if repeated
+type RetryableClassifier func(err error) bool
+
 // DefaultRetryConfig returns a sensible default retry configuration
 func DefaultRetryConfig() RetryConfig {
 	return RetryConfig{
@@ -165,10 +340,116 @@ func DefaultRetryConfig() RetryConfig {
 		InitialBackoff: 1 * time.Second,
 		MaxBackoff:     30 * time.Second,
 		BackoffFactor:  2.0,
+		Jitter:         true,
+		Retryable:      IsRetryable,
+	}
+}
+
+// IsRetryable is the default RetryableClassifier. It retries server errors, 429 responses,
+// secondary rate limits, 202 Accepted responses of data GitHub is still computing and errors
+// without a response, such as network errors. Other client errors, such as 422 validation
+// errors, and primary rate limits, which last until the quota resets, are not retried, and
+// neither is a cancelled context.
+func IsRetryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
 	}
+	var abuseErr *github.AbuseRateLimitError
+	var acceptedErr *github.AcceptedError
+	if errors.As(err, &abuseErr) || errors.As(err, &acceptedErr) {
+		return true
+	}
+	var rateErr *github.RateLimitError
+	if errors.As(err, &rateErr) {
+		return false
+	}
+	var respErr *github.ErrorResponse
+	if errors.As(err, &respErr) && respErr.Response != nil {
+		status := respErr.Response.StatusCode
+		return status >= http.StatusInternalServerError || status == http.StatusTooManyRequests
+	}
+	return true
 }
 
-// RetryWithBackoff executes a function with exponential backoff on rate limit errors
+// RetryHinter is implemented by errors that know how long to wait before the next attempt,
+// such as the errors of responses with a Retry-After header. RetryWithBackoff waits at least
+// RetryHint before retrying them.
+type RetryHinter interface {
+	RetryHint() time.Duration
+}
+
+// retryHint returns how long err asks to wait before the next attempt: the hint of a
+// RetryHinter in its chain, the Retry-After of a secondary rate limit or the time until a
+// primary rate limit resets. It returns zero if err gives no hint.
+func retryHint(err error) time.Duration {
+	var hinter RetryHinter
+	if errors.As(err, &hinter) {
+		return hinter.RetryHint()
+	}
+	var abuseErr *github.AbuseRateLimitError
+	if errors.As(err, &abuseErr) && abuseErr.RetryAfter != nil {
+		return *abuseErr.RetryAfter
+	}
+	var rateErr *github.RateLimitError
+	if errors.As(err, &rateErr) {
+		return max(time.Until(rateErr.Rate.Reset.Time), 0)
+	}
+	return 0
+}
+
+// permanentError marks an error that retrying cannot fix
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps err so that RetryWithBackoff returns it immediately instead of retrying.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// retryAfterError carries the delay the server asked for before the next attempt
+type retryAfterError struct {
+	err   error
+	delay time.Duration
+}
+
+func (e *retryAfterError) Error() string { return e.err.Error() }
+
+func (e *retryAfterError) Unwrap() error { return e.err }
+
+func (e *retryAfterError) RetryHint() time.Duration { return e.delay }
+
+// RetryAfter wraps err so that RetryWithBackoff waits at least delay before the next attempt,
+// as asked for by a Retry-After header.
+func RetryAfter(err error, delay time.Duration) error {
+	if err == nil || delay <= 0 {
+		return err
+	}
+	return &retryAfterError{err: err, delay: delay}
+}
+
+// jitter returns a random duration between zero and d. Tests replace it.
+var jitter = func(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	return rand.N(d + 1)
+}
+
+// RetryWithBackoff executes a function with exponential backoff on rate limit errors.
+// If ctx carries a RetryBudget, every retry is drawn from it and the last error is returned
+// wrapped in ErrRetryBudgetExhausted once the budget runs out. Errors wrapped with Permanent,
+// or rejected by cfg.Retryable, are returned, unwrapped, without retrying. Errors that hint at
+// when to retry, such as those wrapped with RetryAfter, wait at least as long as they ask for,
+// even beyond MaxBackoff, unless that is past the deadline of ctx, in which case they are
+// returned at once.
 func RetryWithBackoff(ctx context.Context, cfg RetryConfig, fn func() error) error {
 	backoff := cfg.InitialBackoff
 
@@ -178,6 +459,18 @@ func RetryWithBackoff(ctx context.Context, cfg RetryConfig, fn func() error) err
 		if lastErr == nil {
 			return nil
 		}
+		var permanent *permanentError
+		if errors.As(lastErr, &permanent) {
+			return permanent.err
+		}
+		hint := retryHint(lastErr)
+		var retryAfter *retryAfterError
+		if errors.As(lastErr, &retryAfter) {
+			lastErr = retryAfter.err
+		}
+		if cfg.Retryable != nil && !cfg.Retryable(lastErr) {
+			return lastErr
+		}
 
 		// Check if context is cancelled
 		select {
@@ -186,16 +479,29 @@ func RetryWithBackoff(ctx context.Context, cfg RetryConfig, fn func() error) err
 		default:
 		}
 
-		// Don't wait after the last attempt
+		// Don't wait after the last attempt, or past the deadline
 		if attempt == cfg.MaxRetries {
 			break
 		}
+		if deadline, ok := ctx.Deadline(); ok && hint > 0 && time.Now().Add(hint).After(deadline) {
+			break
+		}
+
+		wait := backoff
+		if cfg.Jitter {
+			wait = jitter(backoff)
+		}
+		wait = max(wait, hint)
+
+		if budget, ok := RetryBudgetFromContext(ctx); ok && !budget.Reserve(wait) {
+			return fmt.Errorf("%w after %d attempts: %w", ErrRetryBudgetExhausted, attempt+1, lastErr)
+		}
 
 		// Wait with exponential backoff
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
-		case <-time.After(backoff):
+		case <-time.After(wait):
 		}
 
 		// Increase backoff for next iteration
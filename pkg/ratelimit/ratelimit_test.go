@@ -3,8 +3,15 @@ package ratelimit
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/http"
 	"testing"
 	"time"
+
+	"github.com/google/go-github/v79/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
 )
 
 func TestNewDefault(t *testing.T) {
@@ -97,6 +104,19 @@ func TestRateLimiter_ResetStats(t *testing.T) {
 	}
 }
 
+func TestRateLimiter_GetTokens(t *testing.T) {
+	limiter := NewDefault()
+	tokens := limiter.GetTokens()
+	if tokens.Core != 10 || tokens.Search != 5 || tokens.GraphQL != 10 {
+		t.Errorf("expected full bursts, got %+v", tokens)
+	}
+
+	_ = limiter.WaitSearch(context.Background())
+	if tokens := limiter.GetTokens(); tokens.Search >= 5 {
+		t.Errorf("expected a search token to be spent, got %v", tokens.Search)
+	}
+}
+
 func TestRetryWithBackoff_Success(t *testing.T) {
 	cfg := RetryConfig{
 		MaxRetries:     3,
@@ -167,6 +187,56 @@ func TestRetryWithBackoff_AllFail(t *testing.T) {
 	}
 }
 
+func TestRetryWithBackoff_Permanent(t *testing.T) {
+	cfg := RetryConfig{
+		MaxRetries:     3,
+		InitialBackoff: 1 * time.Millisecond,
+		MaxBackoff:     10 * time.Millisecond,
+		BackoffFactor:  2.0,
+	}
+
+	testErr := errors.New("not found")
+	attempts := 0
+	err := RetryWithBackoff(context.Background(), cfg, func() error {
+		attempts++
+		return Permanent(testErr)
+	})
+
+	if err != testErr {
+		t.Errorf("expected the unwrapped test error, got: %v", err)
+	}
+	if attempts != 1 {
+		t.Errorf("expected 1 attempt, got %d", attempts)
+	}
+}
+
+func TestRetryWithBackoff_RetryAfter(t *testing.T) {
+	cfg := RetryConfig{
+		MaxRetries:     1,
+		InitialBackoff: 1 * time.Millisecond,
+		MaxBackoff:     10 * time.Millisecond,
+		BackoffFactor:  2.0,
+	}
+
+	testErr := errors.New("secondary rate limit")
+	attempts := 0
+	start := time.Now()
+	err := RetryWithBackoff(context.Background(), cfg, func() error {
+		attempts++
+		return RetryAfter(testErr, 50*time.Millisecond)
+	})
+
+	if err != testErr {
+		t.Errorf("expected the unwrapped test error, got: %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("expected 2 attempts, got %d", attempts)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("expected to wait at least the Retry-After delay, waited %v", elapsed)
+	}
+}
+
 func TestRetryWithBackoff_ContextCancelled(t *testing.T) {
 	cfg := RetryConfig{
 		MaxRetries:     5,
@@ -193,6 +263,141 @@ func TestRetryWithBackoff_ContextCancelled(t *testing.T) {
 	}
 }
 
+// hintError asks to wait delay before retrying, through RetryHinter
+type hintError struct{ delay time.Duration }
+
+func (e hintError) Error() string { return "try again later" }
+
+func (e hintError) RetryHint() time.Duration { return e.delay }
+
+func TestRetryWithBackoff_Jitter(t *testing.T) {
+	var jittered []time.Duration
+	defaultJitter := jitter
+	jitter = func(d time.Duration) time.Duration {
+		jittered = append(jittered, d)
+		return 0
+	}
+	t.Cleanup(func() { jitter = defaultJitter })
+
+	cfg := RetryConfig{
+		MaxRetries:     3,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     300 * time.Millisecond,
+		BackoffFactor:  2.0,
+		Jitter:         true,
+	}
+	start := time.Now()
+	err := RetryWithBackoff(context.Background(), cfg, func() error { return errors.New("error") })
+	require.Error(t, err)
+	// Jitter draws each wait below the backoff, which still grows up to MaxBackoff
+	assert.Equal(t, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond}, jittered)
+	assert.Less(t, time.Since(start), 100*time.Millisecond)
+
+	for range 100 {
+		d := defaultJitter(time.Second)
+		assert.GreaterOrEqual(t, d, time.Duration(0))
+		assert.LessOrEqual(t, d, time.Second)
+	}
+	assert.Zero(t, defaultJitter(0))
+}
+
+func TestRetryWithBackoff_RetryHints(t *testing.T) {
+	retryAfter := 50 * time.Millisecond
+	tests := []struct {
+		name string
+		err  func() error
+	}{
+		{name: "RetryHinter", err: func() error { return fmt.Errorf("wrapped: %w", hintError{delay: retryAfter}) }},
+		{name: "secondary rate limit", err: func() error {
+			return &github.AbuseRateLimitError{Message: "secondary rate limit", RetryAfter: &retryAfter}
+		}},
+		{name: "primary rate limit", err: func() error {
+			return &github.RateLimitError{Rate: github.Rate{Reset: github.Timestamp{Time: time.Now().Add(retryAfter)}}}
+		}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := RetryConfig{MaxRetries: 1, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond, BackoffFactor: 2.0, Jitter: true}
+			attempts := 0
+			start := time.Now()
+			err := RetryWithBackoff(context.Background(), cfg, func() error {
+				attempts++
+				return tc.err()
+			})
+			assert.Error(t, err)
+			assert.Equal(t, 2, attempts)
+			assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+		})
+	}
+}
+
+func TestRetryWithBackoff_HintPastDeadline(t *testing.T) {
+	cfg := RetryConfig{MaxRetries: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond, BackoffFactor: 2.0}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	attempts := 0
+	start := time.Now()
+	err := RetryWithBackoff(ctx, cfg, func() error {
+		attempts++
+		return hintError{delay: time.Minute}
+	})
+	assert.Equal(t, hintError{delay: time.Minute}, err)
+	assert.Equal(t, 1, attempts)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+}
+
+func TestRetryWithBackoff_Retryable(t *testing.T) {
+	validation := &github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusUnprocessableEntity}, Message: "Validation Failed"}
+	cfg := RetryConfig{MaxRetries: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond, BackoffFactor: 2.0, Retryable: IsRetryable}
+
+	attempts := 0
+	err := RetryWithBackoff(context.Background(), cfg, func() error {
+		attempts++
+		if attempts == 1 {
+			return &github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusBadGateway}}
+		}
+		return validation
+	})
+	assert.Equal(t, validation, err)
+	assert.Equal(t, 2, attempts)
+
+	// Errors wrapped with RetryAfter are classified unwrapped
+	attempts = 0
+	err = RetryWithBackoff(context.Background(), cfg, func() error {
+		attempts++
+		return RetryAfter(validation, time.Minute)
+	})
+	assert.Equal(t, validation, err)
+	assert.Equal(t, 1, attempts)
+}
+
+func TestIsRetryable(t *testing.T) {
+	response := func(status int) error {
+		return fmt.Errorf("call failed: %w", &github.ErrorResponse{Response: &http.Response{StatusCode: status}})
+	}
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "network error", err: errors.New("connection reset by peer"), expected: true},
+		{name: "server error", err: response(http.StatusBadGateway), expected: true},
+		{name: "too many requests", err: response(http.StatusTooManyRequests), expected: true},
+		{name: "secondary rate limit", err: &github.AbuseRateLimitError{}, expected: true},
+		{name: "accepted", err: &github.AcceptedError{}, expected: true},
+		{name: "validation error", err: response(http.StatusUnprocessableEntity), expected: false},
+		{name: "not found", err: response(http.StatusNotFound), expected: false},
+		{name: "primary rate limit", err: &github.RateLimitError{}, expected: false},
+		{name: "cancelled", err: fmt.Errorf("request: %w", context.Canceled), expected: false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, IsRetryable(tc.err))
+		})
+	}
+}
+
 func TestDefaultRetryConfig(t *testing.T) {
 	cfg := DefaultRetryConfig()
 
@@ -208,6 +413,9 @@ func TestDefaultRetryConfig(t *testing.T) {
 	if cfg.BackoffFactor != 2.0 {
 		t.Errorf("expected BackoffFactor 2.0, got %f", cfg.BackoffFactor)
 	}
+	if !cfg.Jitter || cfg.Retryable == nil {
+		t.Errorf("expected jitter and a retryable classifier, got %+v", cfg)
+	}
 }
 
 func TestDefaultLimits(t *testing.T) {
@@ -223,3 +431,119 @@ func TestDefaultLimits(t *testing.T) {
 		t.Errorf("expected GraphQLPointsPerHour 5000, got %d", limits.GraphQLPointsPerHour)
 	}
 }
+
+func TestRetryWithBackoff_SharedBudget(t *testing.T) {
+	cfg := RetryConfig{
+		MaxRetries:     3,
+		InitialBackoff: 1 * time.Millisecond,
+		MaxBackoff:     10 * time.Millisecond,
+		BackoffFactor:  2.0,
+	}
+	budget := NewRetryBudget(4, time.Second)
+	ctx := WithRetryBudget(context.Background(), budget)
+
+	attempts := 0
+	failing := func() error {
+		attempts++
+		return errors.New("persistent error")
+	}
+
+	// The first call uses three of the four retries
+	err := RetryWithBackoff(ctx, cfg, failing)
+	if err == nil || errors.Is(err, ErrRetryBudgetExhausted) {
+		t.Fatalf("expected plain error from first call, got: %v", err)
+	}
+	if attempts != 4 {
+		t.Errorf("expected 4 attempts, got %d", attempts)
+	}
+
+	// The second call only gets the single remaining retry
+	attempts = 0
+	err = RetryWithBackoff(ctx, cfg, failing)
+	if !errors.Is(err, ErrRetryBudgetExhausted) {
+		t.Fatalf("expected budget exhausted error, got: %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("expected 2 attempts, got %d", attempts)
+	}
+
+	usage := budget.Usage()
+	if usage.RetriesUsed != 4 || usage.RetriesLeft != 0 || !usage.Exhausted {
+		t.Errorf("unexpected budget usage: %+v", usage)
+	}
+}
+
+func TestRetryBudget_MaxWait(t *testing.T) {
+	budget := NewRetryBudget(10, 50*time.Millisecond)
+	if !budget.Reserve(40 * time.Millisecond) {
+		t.Fatal("expected first reservation to fit")
+	}
+	if budget.Reserve(20 * time.Millisecond) {
+		t.Fatal("expected reservation beyond max wait to be refused")
+	}
+	if usage := budget.Usage(); usage.WaitedMs != 40 || usage.RetriesUsed != 1 {
+		t.Errorf("unexpected budget usage: %+v", usage)
+	}
+}
+
+func TestRateLimiter_UpdateFromResponse(t *testing.T) {
+	limiter := NewDefault()
+
+	// 1800 requests left over the 30 minutes until the reset is one a second, less the margin
+	limiter.UpdateFromResponse(&github.Response{Rate: github.Rate{
+		Limit: 5000, Remaining: 1800, Reset: github.Timestamp{Time: time.Now().Add(30 * time.Minute)}, Resource: "core",
+	}})
+	assert.InDelta(t, 0.9, float64(limiter.core.limiter.Limit()), 0.01)
+
+	// A plentiful quota is never spent faster than the full quota allows
+	limiter.UpdateFromResponse(&github.Response{Rate: github.Rate{
+		Limit: 15000, Remaining: 15000, Reset: github.Timestamp{Time: time.Now().Add(time.Minute)},
+	}})
+	assert.InDelta(t, 15000*0.9/3600, float64(limiter.core.limiter.Limit()), 0.01)
+
+	// Other resources are left alone
+	assert.Equal(t, limiter.search.base, limiter.search.limiter.Limit())
+	assert.Equal(t, limiter.graphql.base, limiter.graphql.limiter.Limit())
+
+	// Responses without rate limit headers are ignored
+	limiter.UpdateFromResponse(&github.Response{})
+	limiter.UpdateFromResponse(nil)
+	assert.InDelta(t, 15000*0.9/3600, float64(limiter.core.limiter.Limit()), 0.01)
+}
+
+func TestRateLimiter_UpdateFromResponse_Exhausted(t *testing.T) {
+	limiter := NewDefault()
+	reset := time.Now().Add(200 * time.Millisecond)
+	limiter.UpdateFromResponse(&github.Response{Rate: github.Rate{
+		Limit: 30, Remaining: 0, Reset: github.Timestamp{Time: reset}, Resource: "search",
+	}})
+
+	assert.False(t, limiter.AllowSearch())
+	assert.Zero(t, limiter.GetTokens().Search)
+	assert.True(t, limiter.AllowCore(), "other limiters are not blocked")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, limiter.WaitSearch(ctx), context.DeadlineExceeded)
+
+	// Once the quota resets, requests go through at the configured rate again
+	require.NoError(t, limiter.WaitSearch(context.Background()))
+	assert.False(t, time.Now().Before(reset))
+	assert.Equal(t, limiter.search.base, limiter.search.limiter.Limit())
+}
+
+func TestRateLimiter_UpdateFromResponse_ResetPassed(t *testing.T) {
+	limiter := NewDefault()
+	limiter.graphql.limiter.SetLimit(rate.Limit(0.01))
+	limiter.UpdateFromResponse(&github.Response{Rate: github.Rate{
+		Limit: 5000, Remaining: 0, Reset: github.Timestamp{Time: time.Now().Add(-time.Second)}, Resource: "graphql",
+	}})
+	assert.True(t, limiter.AllowGraphQL())
+	assert.Equal(t, limiter.graphql.base, limiter.graphql.limiter.Limit())
+}
+
+func TestRateLimiter_UpdateFromHeader_Missing(t *testing.T) {
+	limiter := NewDefault()
+	limiter.UpdateFromHeader(http.Header{"X-Ratelimit-Remaining": []string{"0"}})
+	assert.True(t, limiter.AllowCore())
+}
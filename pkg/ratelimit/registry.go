@@ -0,0 +1,153 @@
+package ratelimit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultIdleTimeout is how long a Registry keeps the limiter of an identity that makes no
+// requests
+const DefaultIdleTimeout = 2 * time.Hour
+
+// Registry holds a RateLimiter per identity, such as a token or GitHub App installation, as each
+// has its own quota. Limiters are created on first use and dropped once idle for IdleTimeout;
+// a dropped limiter is recreated with a full bucket, which is safe once the quota window it
+// tracked has passed.
+type Registry struct {
+	// New creates the limiter of an identity
+	New func() *RateLimiter
+	// IdleTimeout is how long an unused limiter is kept (0 for DefaultIdleTimeout)
+	IdleTimeout time.Duration
+
+	mu        sync.Mutex
+	limiters  map[string]*registryEntry
+	lastSweep time.Time
+	// evicted accumulates the stats of dropped limiters, so that totals do not go backwards
+	evicted Stats
+}
+
+type registryEntry struct {
+	limiter  *RateLimiter
+	lastUsed time.Time
+}
+
+// RegistryStats aggregates the stats of the limiters of a Registry
+type RegistryStats struct {
+	// Limiters is the number of identities with a limiter
+	Limiters int
+	// Stats sums the stats of all limiters, including dropped ones
+	Stats
+}
+
+// NewRegistry creates a registry of limiters made by newLimiter, or NewDefault if nil, that
+// are dropped after idleTimeout without use.
+func NewRegistry(newLimiter func() *RateLimiter, idleTimeout time.Duration) *Registry {
+	if newLimiter == nil {
+		newLimiter = NewDefault
+	}
+	return &Registry{New: newLimiter, IdleTimeout: idleTimeout}
+}
+
+// Get returns the limiter of key, creating it if needed. Idle limiters are dropped along the way.
+func (r *Registry) Get(key string) *RateLimiter {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(r.lastSweep) >= r.idleTimeout()/2 {
+		r.evictIdle(now)
+	}
+	if r.limiters == nil {
+		r.limiters = make(map[string]*registryEntry)
+	}
+	entry, ok := r.limiters[key]
+	if !ok {
+		entry = &registryEntry{limiter: r.New()}
+		r.limiters[key] = entry
+	}
+	entry.lastUsed = now
+	return entry.limiter
+}
+
+// EvictIdle drops the limiters unused for IdleTimeout and returns how many were dropped.
+func (r *Registry) EvictIdle() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.evictIdle(time.Now())
+}
+
+func (r *Registry) evictIdle(now time.Time) int {
+	r.lastSweep = now
+	evicted := 0
+	for key, entry := range r.limiters {
+		if now.Sub(entry.lastUsed) < r.idleTimeout() {
+			continue
+		}
+		r.evicted = addStats(r.evicted, entry.limiter.GetStats())
+		delete(r.limiters, key)
+		evicted++
+	}
+	return evicted
+}
+
+func (r *Registry) idleTimeout() time.Duration {
+	if r.IdleTimeout <= 0 {
+		return DefaultIdleTimeout
+	}
+	return r.IdleTimeout
+}
+
+// Stats returns the number of limiters and the sum of their stats.
+func (r *Registry) Stats() RegistryStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stats := RegistryStats{Limiters: len(r.limiters), Stats: r.evicted}
+	for _, entry := range r.limiters {
+		stats.Stats = addStats(stats.Stats, entry.limiter.GetStats())
+	}
+	return stats
+}
+
+func addStats(a, b Stats) Stats {
+	addPriority := func(a, b PriorityStats) PriorityStats {
+		return PriorityStats{Waits: a.Waits + b.Waits, WaitMs: a.WaitMs + b.WaitMs}
+	}
+	return Stats{
+		CoreWaits:    a.CoreWaits + b.CoreWaits,
+		SearchWaits:  a.SearchWaits + b.SearchWaits,
+		GraphQLWaits: a.GraphQLWaits + b.GraphQLWaits,
+		TotalWaitMs:  a.TotalWaitMs + b.TotalWaitMs,
+		High:         addPriority(a.High, b.High),
+		Normal:       addPriority(a.Normal, b.Normal),
+		Low:          addPriority(a.Low, b.Low),
+	}
+}
+
+type installationIDKey struct{}
+
+// WithInstallationID returns a context whose requests are rate limited as the GitHub App
+// installation id by RequestKey, whatever token they carry. Installation tokens are short-lived
+// and renewed, but share the quota of their installation.
+func WithInstallationID(ctx context.Context, id int64) context.Context {
+	return context.WithValue(ctx, installationIDKey{}, id)
+}
+
+// RequestKey returns the identity whose quota req counts against: the installation set with
+// WithInstallationID, or else a hash of its Authorization header, so that tokens are never
+// kept in the clear. Requests without credentials share the "anonymous" identity.
+func RequestKey(req *http.Request) string {
+	if id, ok := req.Context().Value(installationIDKey{}).(int64); ok {
+		return fmt.Sprintf("installation:%d", id)
+	}
+	authorization := req.Header.Get("Authorization")
+	if authorization == "" {
+		return "anonymous"
+	}
+	sum := sha256.Sum256([]byte(authorization))
+	return "token:" + hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,106 @@
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_Get(t *testing.T) {
+	created := 0
+	registry := NewRegistry(func() *RateLimiter {
+		created++
+		return NewDefault()
+	}, 0)
+
+	first := registry.Get("token:a")
+	assert.Same(t, first, registry.Get("token:a"))
+	assert.NotSame(t, first, registry.Get("token:b"))
+	assert.Equal(t, 2, created)
+	assert.Equal(t, 2, registry.Stats().Limiters)
+}
+
+func TestRegistry_EvictIdle(t *testing.T) {
+	registry := NewRegistry(nil, 20*time.Millisecond)
+
+	idle := registry.Get("token:idle")
+	require.NoError(t, idle.WaitCore(context.Background()))
+	assert.Equal(t, 0, registry.EvictIdle())
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, 1, registry.EvictIdle())
+
+	// The stats of evicted limiters still count towards the totals
+	stats := registry.Stats()
+	assert.Equal(t, 0, stats.Limiters)
+	assert.Equal(t, int64(1), stats.CoreWaits)
+	assert.NotSame(t, idle, registry.Get("token:idle"))
+}
+
+func TestRegistry_GetEvictsIdle(t *testing.T) {
+	registry := NewRegistry(nil, 20*time.Millisecond)
+
+	registry.Get("token:idle")
+	time.Sleep(30 * time.Millisecond)
+	registry.Get("token:active")
+	assert.Equal(t, 1, registry.Stats().Limiters)
+}
+
+func TestRequestKey(t *testing.T) {
+	newRequest := func(ctx context.Context, authorization string) *http.Request {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://api.github.com/user", nil)
+		require.NoError(t, err)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		return req
+	}
+
+	first := RequestKey(newRequest(context.Background(), "Bearer first"))
+	assert.Regexp(t, `^token:[0-9a-f]{16}$`, first)
+	assert.NotContains(t, first, "first")
+	assert.Equal(t, first, RequestKey(newRequest(context.Background(), "Bearer first")))
+	assert.NotEqual(t, first, RequestKey(newRequest(context.Background(), "Bearer second")))
+	assert.Equal(t, "anonymous", RequestKey(newRequest(context.Background(), "")))
+	assert.Equal(t, "installation:42", RequestKey(newRequest(WithInstallationID(context.Background(), 42), "Bearer first")))
+}
+
+func TestRegistryTransport(t *testing.T) {
+	reset := time.Now().Add(time.Hour)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Only the first token runs out of quota
+		remaining := "4000"
+		if r.Header.Get("Authorization") == "Bearer first" {
+			remaining = "0"
+		}
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", remaining)
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+	}))
+	defer server.Close()
+
+	registry := NewRegistry(nil, 0)
+	client := &http.Client{Transport: NewRegistryTransport(nil, registry)}
+	for _, token := range []string{"first", "second"} {
+		req, err := http.NewRequest(http.MethodGet, server.URL+"/user", nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+	}
+
+	assert.Equal(t, 2, registry.Stats().Limiters)
+	assert.Equal(t, int64(2), registry.Stats().CoreWaits)
+	for token, allowed := range map[string]bool{"first": false, "second": true} {
+		req, err := http.NewRequest(http.MethodGet, server.URL+"/user", nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+token)
+		assert.Equal(t, allowed, registry.Get(RequestKey(req)).AllowCore(), token)
+	}
+}
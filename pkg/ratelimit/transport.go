@@ -0,0 +1,128 @@
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// Rate limit headers of GitHub API responses
+const (
+	headerRateLimit     = "X-RateLimit-Limit"
+	headerRateRemaining = "X-RateLimit-Remaining"
+	headerRateReset     = "X-RateLimit-Reset"
+	headerRateResource  = "X-RateLimit-Resource"
+)
+
+// Transport paces the requests of a GitHub client with a RateLimiter and retunes it to the quota
+// reported by the rate limit headers of every response it receives, so that it follows the real
+// remaining quota of the token rather than the default limits. Requests whose context carries
+// registries, see WithLimiters, are paced and retune the limiters of their identity there too. GraphQL queries
+// reserve the points they are estimated to cost, see EstimateGraphQLCost, which are reconciled
+// with their real cost when they select the rateLimit object.
+type Transport struct {
+	Base http.RoundTripper
+	// Limiter is waited on before each request, by the kind of the request. Nil sends requests
+	// right away.
+	Limiter *RateLimiter
+	// Registry, if set, replaces Limiter with the limiter of the identity of each request, so
+	// that every token or installation is paced by its own quota
+	Registry *Registry
+	// Key returns the identity of a request in Registry and in the registries of its context
+	// (default: RequestKey)
+	Key func(*http.Request) string
+}
+
+// NewTransport wraps base, or http.DefaultTransport if base is nil, to pace requests with
+// limiter and update it.
+func NewTransport(base http.RoundTripper, limiter *RateLimiter) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Transport{Base: base, Limiter: limiter}
+}
+
+// NewRegistryTransport wraps base, or http.DefaultTransport if base is nil, to pace requests
+// with the limiter of their identity in registry and update it.
+func NewRegistryTransport(base http.RoundTripper, registry *Registry) *Transport {
+	t := NewTransport(base, nil)
+	t.Registry = registry
+	return t
+}
+
+type limitersKey struct{}
+
+// WithLimiters returns a context whose requests a Transport also paces with the limiter of their
+// identity in each of registries, such as those of a tool that keeps its own pace. Each limiter
+// follows the quota of one token or installation, so that one running out slows no other.
+func WithLimiters(ctx context.Context, registries ...*Registry) context.Context {
+	existing, _ := ctx.Value(limitersKey{}).([]*Registry)
+	return context.WithValue(ctx, limitersKey{}, append(slices.Clip(existing), registries...))
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	limiters := t.limitersFor(req)
+	graphql := strings.HasSuffix(req.URL.Path, "/graphql")
+	var query graphQLRequest
+	points := 1
+	if graphql && len(limiters) > 0 {
+		query, points = graphQLPoints(req)
+	}
+	for _, limiter := range limiters {
+		if err := wait(req, limiter, points); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := t.Base.RoundTrip(req)
+	if resp != nil {
+		for _, limiter := range limiters {
+			limiter.UpdateFromHeader(resp.Header)
+		}
+		// Only responses to queries that select the rate limit are buffered to read it
+		if graphql && len(limiters) > 0 && strings.Contains(query.Query, "rateLimit") {
+			if usage, ok := readGraphQLRateLimit(resp); ok {
+				for _, limiter := range limiters {
+					limiter.ReconcileGraphQL(points, usage)
+				}
+			}
+		}
+	}
+	return resp, err
+}
+
+// limitersFor returns the limiters that pace req: Limiter, or the limiter of the identity of
+// req in Registry, followed by the limiters of that identity in the registries of its context.
+func (t *Transport) limitersFor(req *http.Request) []*RateLimiter {
+	key := RequestKey
+	if t.Key != nil {
+		key = t.Key
+	}
+	registries, _ := req.Context().Value(limitersKey{}).([]*Registry)
+	limiters := make([]*RateLimiter, 0, len(registries)+1)
+	if t.Registry != nil {
+		limiters = append(limiters, t.Registry.Get(key(req)))
+	} else if t.Limiter != nil {
+		limiters = append(limiters, t.Limiter)
+	}
+	for _, registry := range registries {
+		limiters = append(limiters, registry.Get(key(req)))
+	}
+	return limiters
+}
+
+// wait waits on limiter for the kind of req: GraphQL queries, which take their points, searches
+// or core API requests. GitHub Enterprise Server serves the API under /api, so paths are matched
+// by their suffix.
+func wait(req *http.Request, limiter *RateLimiter, points int) error {
+	path := req.URL.Path
+	switch {
+	case strings.HasSuffix(path, "/graphql"):
+		return limiter.WaitGraphQLN(req.Context(), points)
+	case strings.HasPrefix(path, "/search/") || strings.Contains(path, "/api/v3/search/"):
+		return limiter.WaitSearch(req.Context())
+	default:
+		return limiter.WaitCore(req.Context())
+	}
+}
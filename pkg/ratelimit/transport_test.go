@@ -0,0 +1,158 @@
+package ratelimit
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransport(t *testing.T) {
+	reset := time.Now().Add(time.Hour)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		w.Header().Set("X-RateLimit-Resource", "graphql")
+	}))
+	defer server.Close()
+
+	limiter := NewDefault()
+	client := &http.Client{Transport: NewTransport(nil, limiter)}
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	assert.False(t, limiter.AllowGraphQL())
+	assert.True(t, limiter.AllowCore())
+}
+
+func TestTransport_ContextLimiters(t *testing.T) {
+	reset := time.Now().Add(time.Hour)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		w.Header().Set("X-RateLimit-Resource", "graphql")
+	}))
+	defer server.Close()
+
+	requests, tool := NewRegistry(nil, time.Hour), NewRegistry(nil, time.Hour)
+	client := &http.Client{Transport: NewRegistryTransport(nil, requests)}
+	get := func(ctx context.Context, token string) {
+		t.Helper()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/repos/owner/repo", nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+	}
+
+	// Only requests whose context carries the registry use it, with a limiter per token
+	get(context.Background(), "a")
+	assert.Equal(t, 0, tool.Stats().Limiters)
+	ctx := WithLimiters(context.Background(), tool)
+	get(ctx, "a")
+	assert.Equal(t, 1, tool.Stats().Limiters)
+	assert.Equal(t, int64(1), tool.Stats().CoreWaits)
+
+	exhausted := tool.Get(RequestKey(&http.Request{Header: http.Header{"Authorization": []string{"Bearer a"}}}))
+	assert.False(t, exhausted.AllowGraphQL())
+	other := tool.Get(RequestKey(&http.Request{Header: http.Header{"Authorization": []string{"Bearer b"}}}))
+	assert.True(t, other.AllowGraphQL(), "the quota of one token does not retune the limiter of another")
+}
+
+func TestTransport_Pacing(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer server.Close()
+
+	tests := []struct {
+		path   string
+		expect Stats
+	}{
+		{path: "/repos/owner/repo", expect: Stats{CoreWaits: 1}},
+		{path: "/search/code", expect: Stats{SearchWaits: 1}},
+		{path: "/api/v3/search/issues", expect: Stats{SearchWaits: 1}},
+		{path: "/api/graphql", expect: Stats{GraphQLWaits: 1}},
+		{path: "/graphql", expect: Stats{GraphQLWaits: 1}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.path, func(t *testing.T) {
+			limiter := NewDefault()
+			client := &http.Client{Transport: NewTransport(nil, limiter)}
+			resp, err := client.Get(server.URL + tc.path)
+			require.NoError(t, err)
+			_ = resp.Body.Close()
+
+			stats := limiter.GetStats()
+			assert.Equal(t, PriorityStats{Waits: 1, WaitMs: stats.TotalWaitMs}, stats.Normal)
+			stats.TotalWaitMs, stats.Normal = 0, PriorityStats{}
+			assert.Equal(t, tc.expect, stats)
+		})
+	}
+}
+
+func TestTransport_CancelledWhileWaiting(t *testing.T) {
+	limiter := NewDefault()
+	limiter.UpdateFromHeader(http.Header{
+		"X-Ratelimit-Limit":     []string{"5000"},
+		"X-Ratelimit-Remaining": []string{"0"},
+		"X-Ratelimit-Reset":     []string{strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.invalid/repos/owner/repo", nil)
+	require.NoError(t, err)
+	_, err = NewTransport(nil, limiter).RoundTrip(req)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestTransport_GraphQLPoints(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if strings.Contains(string(body), "rateLimit") {
+			_, _ = w.Write([]byte(`{"data":{"rateLimit":{"cost":8,"limit":5000,"remaining":4000,"resetAt":"` + time.Now().Add(time.Hour).Format(time.RFC3339) + `"}}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{}}`))
+	}))
+	defer server.Close()
+
+	// 1 + 100 + 500 requests cost 6 points
+	const query = `{"query":"query { a(first: 100) { nodes { b(first: 5) { nodes { c(first: 1) { nodes { id } } } } } } }"}`
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		body     string
+		expected float64
+	}{
+		{name: "estimated", ctx: context.Background(), body: query, expected: 4},
+		{name: "reserved", ctx: WithGraphQLCost(context.Background(), 3), body: query, expected: 7},
+		{name: "reconciled", ctx: WithGraphQLCost(context.Background(), 3), body: `{"query":"query { rateLimit { cost limit remaining resetAt } }"}`, expected: 2},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			limiter := NewDefault()
+			client := &http.Client{Transport: NewTransport(nil, limiter)}
+			req, err := http.NewRequestWithContext(tc.ctx, http.MethodPost, server.URL+"/graphql", strings.NewReader(tc.body))
+			require.NoError(t, err)
+			resp, err := client.Do(req)
+			require.NoError(t, err)
+			body, err := io.ReadAll(resp.Body)
+			_ = resp.Body.Close()
+			require.NoError(t, err)
+			assert.Contains(t, string(body), `"data"`)
+
+			assert.InDelta(t, tc.expected, limiter.GetTokens().GraphQL, 0.5)
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package sanitize
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// PathPolicy controls how aggressively Path rewrites a repository file path.
+type PathPolicy string
+
+const (
+	// PathPolicyNone leaves paths untouched.
+	PathPolicyNone PathPolicy = "none"
+	// PathPolicyNormalize removes invisible characters, folds common unicode
+	// confusables to ASCII, replaces whitespace with dashes and strips trailing
+	// dots and spaces from each path segment.
+	PathPolicyNormalize PathPolicy = "normalize"
+	// PathPolicyStrict applies PathPolicyNormalize and then replaces every
+	// character outside [A-Za-z0-9._-] (including emoji) with an underscore.
+	PathPolicyStrict PathPolicy = "strict"
+)
+
+// ParsePathPolicy converts a user-supplied policy name to a PathPolicy.
+// An empty string is treated as PathPolicyNone.
+func ParsePathPolicy(s string) (PathPolicy, error) {
+	switch PathPolicy(strings.ToLower(strings.TrimSpace(s))) {
+	case "", PathPolicyNone:
+		return PathPolicyNone, nil
+	case PathPolicyNormalize:
+		return PathPolicyNormalize, nil
+	case PathPolicyStrict:
+		return PathPolicyStrict, nil
+	default:
+		return "", fmt.Errorf("unknown path sanitization policy %q (expected none, normalize or strict)", s)
+	}
+}
+
+// confusables maps characters that commonly appear in model-generated names
+// and look like ASCII to their ASCII equivalent.
+var confusables = map[rune]rune{
+	// Quotes and dashes
+	'‘': '\'', '’': '\'', '‚': '\'', '‛': '\'',
+	'“': '"', '”': '"', '„': '"',
+	'‐': '-', '‑': '-', '‒': '-', '–': '-', '—': '-', '―': '-', '−': '-',
+	// Dots and slashes
+	// Slash lookalikes, including the fullwidth ones, become dashes rather than separators, as
+	// they are folded within a segment
+	'․': '.', '．': '.', '∕': '-', '⁄': '-', '⧸': '-', '／': '-', '＼': '-',
+	// Cyrillic lookalikes
+	'а': 'a', 'е': 'e', 'о': 'o', 'р': 'p', 'с': 'c', 'х': 'x', 'у': 'y', 'і': 'i',
+	'А': 'A', 'В': 'B', 'Е': 'E', 'К': 'K', 'М': 'M', 'Н': 'H', 'О': 'O',
+	'Р': 'P', 'С': 'C', 'Т': 'T', 'Х': 'X',
+	// Greek lookalikes
+	'ο': 'o', 'Ο': 'O', 'Α': 'A', 'Β': 'B', 'Ε': 'E', 'Η': 'H', 'Ι': 'I',
+	'Κ': 'K', 'Μ': 'M', 'Ν': 'N', 'Ρ': 'P', 'Τ': 'T', 'Χ': 'X', 'Ζ': 'Z',
+}
+
+// Path rewrites a slash-separated repository path according to policy. Empty
+// segments are dropped, and a segment that sanitizes to nothing, ".", or ".."
+// becomes "_", so the result never has dot segments.
+func Path(p string, policy PathPolicy) string {
+	if policy == PathPolicyNone || policy == "" {
+		return p
+	}
+
+	segments := strings.Split(p, "/")
+	out := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		if segment == "" {
+			continue
+		}
+		s := sanitizeSegment(segment, policy)
+		if s == "" || isDotSegment(s) {
+			s = "_"
+		}
+		out = append(out, s)
+	}
+	return strings.Join(out, "/")
+}
+
+func sanitizeSegment(segment string, policy PathPolicy) string {
+	segment = strings.Map(foldConfusable, FilterInvisibleCharacters(segment))
+	// Windows and several Git hosts reject leading/trailing spaces and trailing dots in names
+	segment = strings.TrimRight(strings.TrimSpace(segment), ". ")
+
+	var b strings.Builder
+	b.Grow(len(segment))
+	lastWasSpace := false
+	for _, r := range segment {
+		if unicode.IsSpace(r) {
+			if !lastWasSpace {
+				b.WriteRune('-')
+			}
+			lastWasSpace = true
+			continue
+		}
+		lastWasSpace = false
+		switch {
+		case policy == PathPolicyStrict && !isStrictPathRune(r):
+			b.WriteRune('_')
+		case unicode.IsControl(r):
+			continue
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// CheckPath returns an error if the slash-separated path p has a "." or ".."
+// segment, which would point outside of or alias the directory it names.
+func CheckPath(p string) error {
+	for _, segment := range strings.Split(p, "/") {
+		if isDotSegment(segment) {
+			return fmt.Errorf("path %q must not contain %q segments", p, segment)
+		}
+	}
+	return nil
+}
+
+func isDotSegment(segment string) bool {
+	return segment == "." || segment == ".."
+}
+
+func foldConfusable(r rune) rune {
+	if mapped, ok := confusables[r]; ok {
+		return mapped
+	}
+	// Fullwidth ASCII variants (U+FF01-U+FF5E) map directly onto printable ASCII
+	if r >= '！' && r <= '～' {
+		return r - 0xFEE0
+	}
+	return r
+}
+
+func isStrictPathRune(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') ||
+		r == '.' || r == '_' || r == '-'
+}
@@ -0,0 +1,111 @@
+package sanitize
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		policy   PathPolicy
+		expected string
+	}{
+		{
+			name:     "none leaves path untouched",
+			input:    "docs/My File .md.",
+			policy:   PathPolicyNone,
+			expected: "docs/My File .md.",
+		},
+		{
+			name:     "normalize replaces whitespace with dashes",
+			input:    "docs/release notes  v2.md",
+			policy:   PathPolicyNormalize,
+			expected: "docs/release-notes-v2.md",
+		},
+		{
+			name:     "normalize strips trailing dots and surrounding spaces per segment",
+			input:    " src. /main.go.",
+			policy:   PathPolicyNormalize,
+			expected: "src/main.go",
+		},
+		{
+			name:     "normalize folds confusables",
+			input:    "сonfig/ａpp–v1.yaml",
+			policy:   PathPolicyNormalize,
+			expected: "config/app-v1.yaml",
+		},
+		{
+			name:     "normalize removes invisible characters",
+			input:    "read\u200Bme.md",
+			policy:   PathPolicyNormalize,
+			expected: "readme.md",
+		},
+		{
+			name:     "normalize keeps emoji",
+			input:    "assets/\U0001F680 launch.png",
+			policy:   PathPolicyNormalize,
+			expected: "assets/\U0001F680-launch.png",
+		},
+		{
+			name:     "strict replaces emoji and other unicode",
+			input:    "assets/\U0001F680 launch (final).png",
+			policy:   PathPolicyStrict,
+			expected: "assets/_-launch-_final_.png",
+		},
+		{
+			name:     "empty segments are dropped",
+			input:    "/a//b/",
+			policy:   PathPolicyNormalize,
+			expected: "a/b",
+		},
+		{
+			name:     "fullwidth slashes do not split segments",
+			input:    "docs/．．／．．／etc/passwd",
+			policy:   PathPolicyNormalize,
+			expected: "docs/..-..-etc/passwd",
+		},
+		{
+			name:     "fullwidth dot segments become underscores",
+			input:    "docs/．．/．/etc＼passwd",
+			policy:   PathPolicyNormalize,
+			expected: "docs/_/_/etc-passwd",
+		},
+		{
+			name:     "segment sanitized to nothing becomes underscore",
+			input:    "a/.../b",
+			policy:   PathPolicyNormalize,
+			expected: "a/_/b",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, Path(tc.input, tc.policy))
+		})
+	}
+}
+
+func TestCheckPath(t *testing.T) {
+	assert.NoError(t, CheckPath("docs/..-..-etc/passwd"))
+	assert.NoError(t, CheckPath("docs/.github/a..b"))
+	assert.EqualError(t, CheckPath("docs/../../etc/passwd"), `path "docs/../../etc/passwd" must not contain ".." segments`)
+	assert.Error(t, CheckPath("./docs"))
+	assert.Error(t, CheckPath(".."))
+}
+
+func TestParsePathPolicy(t *testing.T) {
+	policy, err := ParsePathPolicy("")
+	require.NoError(t, err)
+	assert.Equal(t, PathPolicyNone, policy)
+
+	policy, err = ParsePathPolicy("Strict")
+	require.NoError(t, err)
+	assert.Equal(t, PathPolicyStrict, policy)
+
+	_, err = ParsePathPolicy("aggressive")
+	assert.Error(t, err)
+}
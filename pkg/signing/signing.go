@@ -0,0 +1,124 @@
+// Package signing signs commits created through the Git data API so that they
+// show as verified on GitHub. Signatures are produced by an external program,
+// gpg or ssh-keygen, in the same way git itself does, so keys never need to be
+// loaded into the server process.
+package signing
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v79/github"
+)
+
+// Format selects the kind of signature to produce.
+type Format string
+
+const (
+	// FormatGPG produces an armored OpenPGP detached signature using gpg.
+	FormatGPG Format = "gpg"
+	// FormatSSH produces an SSH signature in the "git" namespace using ssh-keygen.
+	FormatSSH Format = "ssh"
+)
+
+// Config describes how commits are signed.
+type Config struct {
+	// Format is the signature format. An empty format disables signing.
+	Format Format
+	// Key is the gpg key ID for FormatGPG, or the path to the private key
+	// (or its public key, when the private key is held by ssh-agent) for FormatSSH.
+	Key string
+	// Program overrides the signing program. Defaults to gpg or ssh-keygen.
+	Program string
+	// Name and Email identify the committer of signed commits. GitHub only marks
+	// a commit as verified when the committer email belongs to the key owner.
+	Name  string
+	Email string
+}
+
+// Enabled reports whether the configuration turns signing on.
+func (c Config) Enabled() bool {
+	return c.Format != ""
+}
+
+// Signer signs commit payloads by running an external program. It implements
+// github.MessageSigner.
+type Signer struct {
+	cfg     Config
+	program string
+	args    []string
+}
+
+// New validates cfg and returns a Signer for it.
+func New(cfg Config) (*Signer, error) {
+	if cfg.Key == "" {
+		return nil, fmt.Errorf("commit signing key is required")
+	}
+	if cfg.Name == "" || cfg.Email == "" {
+		return nil, fmt.Errorf("commit signing name and email are required")
+	}
+
+	s := &Signer{cfg: cfg, program: cfg.Program}
+	switch cfg.Format {
+	case FormatGPG:
+		if s.program == "" {
+			s.program = "gpg"
+		}
+		s.args = []string{"--batch", "--no-tty", "--armor", "--detach-sign", "--local-user", cfg.Key}
+	case FormatSSH:
+		if s.program == "" {
+			s.program = "ssh-keygen"
+		}
+		// With no input file ssh-keygen signs stdin and writes the signature to stdout
+		s.args = []string{"-Y", "sign", "-n", "git", "-f", cfg.Key}
+	default:
+		return nil, fmt.Errorf("unknown commit signing format %q (expected gpg or ssh)", cfg.Format)
+	}
+
+	if _, err := exec.LookPath(s.program); err != nil {
+		return nil, fmt.Errorf("commit signing program %q not found: %w", s.program, err)
+	}
+
+	return s, nil
+}
+
+// Sign writes a detached signature of r to w.
+func (s *Signer) Sign(w io.Writer, r io.Reader) error {
+	var stderr bytes.Buffer
+	cmd := exec.Command(s.program, s.args...) //nolint:gosec // program and arguments come from server configuration
+	cmd.Stdin = r
+	cmd.Stdout = w
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to sign commit with %s: %w: %s", s.program, err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// Identity returns the committer identity for a commit signed at t. The time is
+// truncated to whole seconds because that is the precision of the signed payload.
+func (s *Signer) Identity(t time.Time) *github.CommitAuthor {
+	return &github.CommitAuthor{
+		Name:  github.Ptr(s.cfg.Name),
+		Email: github.Ptr(s.cfg.Email),
+		Date:  &github.Timestamp{Time: t.UTC().Truncate(time.Second)},
+	}
+}
+
+type signerKey struct{}
+
+// ContextWithSigner returns a context carrying s, used by tools that create commits.
+func ContextWithSigner(ctx context.Context, s *Signer) context.Context {
+	return context.WithValue(ctx, signerKey{}, s)
+}
+
+// SignerFromContext returns the signer carried by ctx, if any.
+func SignerFromContext(ctx context.Context) (*Signer, bool) {
+	s, ok := ctx.Value(signerKey{}).(*Signer)
+	return s, ok && s != nil
+}
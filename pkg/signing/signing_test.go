@@ -0,0 +1,90 @@
+package signing
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeProgram writes a script that records its arguments and stdin and prints a fixed signature
+func fakeProgram(t *testing.T) (program string, argsFile string, inputFile string) {
+	t.Helper()
+	dir := t.TempDir()
+	argsFile = filepath.Join(dir, "args")
+	inputFile = filepath.Join(dir, "input")
+	program = filepath.Join(dir, "sign")
+	script := "#!/bin/sh\necho \"$@\" > " + argsFile + "\ncat > " + inputFile + "\necho FAKE-SIGNATURE\n"
+	require.NoError(t, os.WriteFile(program, []byte(script), 0o700))
+	return program, argsFile, inputFile
+}
+
+func TestNew_Validation(t *testing.T) {
+	_, err := New(Config{Format: FormatSSH, Name: "a", Email: "a@example.com"})
+	assert.ErrorContains(t, err, "key is required")
+
+	_, err = New(Config{Format: FormatSSH, Key: "key"})
+	assert.ErrorContains(t, err, "name and email are required")
+
+	_, err = New(Config{Format: "x509", Key: "key", Name: "a", Email: "a@example.com"})
+	assert.ErrorContains(t, err, "unknown commit signing format")
+
+	_, err = New(Config{Format: FormatGPG, Key: "key", Program: "/does/not/exist", Name: "a", Email: "a@example.com"})
+	assert.ErrorContains(t, err, "not found")
+}
+
+func TestSigner_Sign(t *testing.T) {
+	tests := []struct {
+		format       Format
+		expectedArgs string
+	}{
+		{format: FormatGPG, expectedArgs: "--batch --no-tty --armor --detach-sign --local-user KEY"},
+		{format: FormatSSH, expectedArgs: "-Y sign -n git -f KEY"},
+	}
+
+	for _, tc := range tests {
+		t.Run(string(tc.format), func(t *testing.T) {
+			program, argsFile, inputFile := fakeProgram(t)
+			signer, err := New(Config{Format: tc.format, Key: "KEY", Program: program, Name: "Octo Cat", Email: "octocat@example.com"})
+			require.NoError(t, err)
+
+			var out bytes.Buffer
+			require.NoError(t, signer.Sign(&out, strings.NewReader("tree abc\n\nmessage")))
+			assert.Equal(t, "FAKE-SIGNATURE\n", out.String())
+
+			args, err := os.ReadFile(argsFile)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedArgs, strings.TrimSpace(string(args)))
+
+			input, err := os.ReadFile(inputFile)
+			require.NoError(t, err)
+			assert.Equal(t, "tree abc\n\nmessage", string(input))
+		})
+	}
+}
+
+func TestSigner_Identity(t *testing.T) {
+	signer := &Signer{cfg: Config{Name: "Octo Cat", Email: "octocat@example.com"}}
+	at := time.Date(2024, 5, 1, 12, 30, 15, 999, time.FixedZone("CEST", 2*3600))
+
+	identity := signer.Identity(at)
+	assert.Equal(t, "Octo Cat", identity.GetName())
+	assert.Equal(t, "octocat@example.com", identity.GetEmail())
+	assert.Equal(t, time.Date(2024, 5, 1, 10, 30, 15, 0, time.UTC), identity.GetDate().Time)
+}
+
+func TestSignerFromContext(t *testing.T) {
+	_, ok := SignerFromContext(context.Background())
+	assert.False(t, ok)
+
+	signer := &Signer{}
+	got, ok := SignerFromContext(ContextWithSigner(context.Background(), signer))
+	assert.True(t, ok)
+	assert.Same(t, signer, got)
+}
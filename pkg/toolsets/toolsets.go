@@ -3,6 +3,7 @@ package toolsets
 import (
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -141,6 +142,13 @@ func (t *Toolset) RegisterResourcesTemplates(s *mcp.Server) {
 	}
 }
 
+func (t *Toolset) GetActivePrompts() []ServerPrompt {
+	if !t.Enabled {
+		return nil
+	}
+	return t.prompts
+}
+
 func (t *Toolset) RegisterPrompts(s *mcp.Server) {
 	if !t.Enabled {
 		return
@@ -182,6 +190,8 @@ type ToolsetGroup struct {
 	Toolsets     map[string]*Toolset
 	everythingOn bool
 	readOnly     bool
+	// removed are the tools taken out of their toolsets, which requests for are ignored
+	removed map[string]bool
 }
 
 func NewToolsetGroup(readOnly bool) *ToolsetGroup {
@@ -189,7 +199,33 @@ func NewToolsetGroup(readOnly bool) *ToolsetGroup {
 		Toolsets:     make(map[string]*Toolset),
 		everythingOn: false,
 		readOnly:     readOnly,
+		removed:      make(map[string]bool),
+	}
+}
+
+// RemoveTools takes the named tools out of their toolsets, such as tools the GitHub host lacks
+// the APIs of. Naming a removed tool in RegisterSpecificTools or Select is not an error, the
+// tool is skipped. It returns the names of the tools removed.
+func (tg *ToolsetGroup) RemoveTools(names ...string) []string {
+	var removed []string
+	remove := func(tools []ServerTool) []ServerTool {
+		return slices.DeleteFunc(tools, func(tool ServerTool) bool {
+			if !slices.Contains(names, tool.Tool.Name) {
+				return false
+			}
+			if !tg.removed[tool.Tool.Name] {
+				tg.removed[tool.Tool.Name] = true
+				removed = append(removed, tool.Tool.Name)
+			}
+			return true
+		})
+	}
+	for _, toolset := range tg.Toolsets {
+		toolset.readTools = remove(toolset.readTools)
+		toolset.writeTools = remove(toolset.writeTools)
 	}
+	slices.Sort(removed)
+	return removed
 }
 
 func (tg *ToolsetGroup) AddToolset(ts *Toolset) {
@@ -320,6 +356,9 @@ func (tg *ToolsetGroup) FindToolByName(toolName string) (*ServerTool, string, er
 func (tg *ToolsetGroup) RegisterSpecificTools(s *mcp.Server, toolNames []string, readOnly bool) error {
 	var skippedTools []string
 	for _, toolName := range toolNames {
+		if tg.removed[toolName] {
+			continue
+		}
 		tool, _, err := tg.FindToolByName(toolName)
 		if err != nil {
 			return fmt.Errorf("tool %s not found: %w", toolName, err)
@@ -342,3 +381,44 @@ func (tg *ToolsetGroup) RegisterSpecificTools(s *mcp.Server, toolNames []string,
 
 	return nil
 }
+
+// Selection is the MCP functionality that a toolset group exposes.
+type Selection struct {
+	Tools             []ServerTool
+	ResourceTemplates []ServerResourceTemplate
+	Prompts           []ServerPrompt
+}
+
+// Select returns what RegisterAll followed by RegisterSpecificTools would register,
+// without touching a server, so that the result can be compared with what is
+// already registered. Tools are unique by name; toolNames that are write tools are
+// skipped in read-only mode.
+func (tg *ToolsetGroup) Select(toolNames []string, readOnly bool) (Selection, error) {
+	var sel Selection
+	seen := make(map[string]bool)
+	for _, toolset := range tg.Toolsets {
+		for _, tool := range toolset.GetActiveTools() {
+			if !seen[tool.Tool.Name] {
+				seen[tool.Tool.Name] = true
+				sel.Tools = append(sel.Tools, tool)
+			}
+		}
+		sel.ResourceTemplates = append(sel.ResourceTemplates, toolset.GetActiveResourceTemplates()...)
+		sel.Prompts = append(sel.Prompts, toolset.GetActivePrompts()...)
+	}
+	for _, toolName := range toolNames {
+		if tg.removed[toolName] {
+			continue
+		}
+		tool, _, err := tg.FindToolByName(toolName)
+		if err != nil {
+			return Selection{}, fmt.Errorf("tool %s not found: %w", toolName, err)
+		}
+		if (!tool.Tool.Annotations.ReadOnlyHint && readOnly) || seen[toolName] {
+			continue
+		}
+		seen[toolName] = true
+		sel.Tools = append(sel.Tools, *tool)
+	}
+	return sel, nil
+}
@@ -3,6 +3,8 @@ package toolsets
 import (
 	"errors"
 	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
 func TestNewToolsetGroupIsEmptyWithoutEverythingOn(t *testing.T) {
@@ -262,3 +264,99 @@ func TestToolsetGroup_GetToolset(t *testing.T) {
 		t.Errorf("expected error to be ToolsetDoesNotExistError, got %v", err)
 	}
 }
+
+func TestSelect(t *testing.T) {
+	readTool := func(name string) ServerTool {
+		return ServerTool{Tool: mcp.Tool{Name: name, Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true}}}
+	}
+	writeTool := func(name string) ServerTool {
+		return ServerTool{Tool: mcp.Tool{Name: name, Annotations: &mcp.ToolAnnotations{}}}
+	}
+
+	newGroup := func(readOnly bool) *ToolsetGroup {
+		tsg := NewToolsetGroup(readOnly)
+		issues := NewToolset("issues", "Issues")
+		issues.AddReadTools(readTool("get_issue")).AddWriteTools(writeTool("create_issue"))
+		issues.AddPrompts(NewServerPrompt(mcp.Prompt{Name: "issue_prompt"}, nil))
+		tsg.AddToolset(issues)
+		repos := NewToolset("repos", "Repositories")
+		repos.AddReadTools(readTool("get_file")).AddWriteTools(writeTool("push_files"))
+		repos.AddResourceTemplates(NewServerResourceTemplate(mcp.ResourceTemplate{URITemplate: "repo://{owner}/{repo}"}, nil))
+		tsg.AddToolset(repos)
+		return tsg
+	}
+	names := func(sel Selection) map[string]bool {
+		m := make(map[string]bool)
+		for _, tool := range sel.Tools {
+			if m[tool.Tool.Name] {
+				t.Errorf("tool %s selected twice", tool.Tool.Name)
+			}
+			m[tool.Tool.Name] = true
+		}
+		return m
+	}
+
+	tsg := newGroup(false)
+	if err := tsg.EnableToolset("issues"); err != nil {
+		t.Fatal(err)
+	}
+	sel, err := tsg.Select([]string{"get_file", "get_issue"}, false)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	got := names(sel)
+	if len(got) != 3 || !got["get_issue"] || !got["create_issue"] || !got["get_file"] {
+		t.Errorf("Expected get_issue, create_issue and get_file, got %v", got)
+	}
+	if len(sel.Prompts) != 1 || len(sel.ResourceTemplates) != 0 {
+		t.Errorf("Expected only the prompts of the enabled toolset, got %d prompts and %d resource templates", len(sel.Prompts), len(sel.ResourceTemplates))
+	}
+
+	tsg = newGroup(true)
+	if err := tsg.EnableToolset("repos"); err != nil {
+		t.Fatal(err)
+	}
+	sel, err = tsg.Select([]string{"create_issue"}, true)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	got = names(sel)
+	if len(got) != 1 || !got["get_file"] {
+		t.Errorf("Expected only get_file in read-only mode, got %v", got)
+	}
+
+	if _, err := tsg.Select([]string{"missing_tool"}, false); err == nil {
+		t.Error("Expected an error for an unknown tool")
+	}
+}
+
+func TestRemoveTools(t *testing.T) {
+	tsg := NewToolsetGroup(false)
+	issues := NewToolset("issues", "Issues")
+	issues.AddReadTools(ServerTool{Tool: mcp.Tool{Name: "get_issue", Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true}}}).
+		AddWriteTools(ServerTool{Tool: mcp.Tool{Name: "create_issue", Annotations: &mcp.ToolAnnotations{}}})
+	tsg.AddToolset(issues)
+	if err := tsg.EnableToolset("issues"); err != nil {
+		t.Fatal(err)
+	}
+
+	removed := tsg.RemoveTools("create_issue", "missing_tool")
+	if len(removed) != 1 || removed[0] != "create_issue" {
+		t.Errorf("Expected create_issue to be removed, got %v", removed)
+	}
+	if _, _, err := tsg.FindToolByName("create_issue"); err == nil {
+		t.Error("Expected removed tool not to be found")
+	}
+
+	// Removed tools named explicitly are skipped, unknown tools are still errors
+	sel, err := tsg.Select([]string{"create_issue"}, false)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(sel.Tools) != 1 || sel.Tools[0].Tool.Name != "get_issue" {
+		t.Errorf("Expected only get_issue, got %v", sel.Tools)
+	}
+	if _, err := tsg.Select([]string{"missing_tool"}, false); err == nil {
+		t.Error("Expected an error for an unknown tool")
+	}
+}
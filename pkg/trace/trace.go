@@ -0,0 +1,130 @@
+// Package trace correlates tool invocations with the GitHub API requests they make.
+// Each invocation gets a trace ID that is sent with every outgoing request, and the
+// request IDs that GitHub returns are recorded so that a failed call can be matched
+// with GitHub's own logs.
+package trace
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// HeaderTraceID carries the trace ID of the invocation on outgoing requests
+	HeaderTraceID = "X-MCP-Trace-Id"
+	// HeaderGitHubRequestID is the response header in which GitHub identifies a request
+	HeaderGitHubRequestID = "X-GitHub-Request-Id"
+
+	// maxRecordedRequests bounds the requests remembered for one invocation
+	maxRecordedRequests = 100
+)
+
+// Request is a GitHub API request made during an invocation.
+type Request struct {
+	Method    string `json:"method"`
+	Path      string `json:"path"`
+	Status    int    `json:"status"`
+	RequestID string `json:"github_request_id,omitempty"`
+}
+
+// Trace holds the trace ID of one invocation and the requests made under it.
+type Trace struct {
+	ID string
+
+	mu       sync.Mutex
+	requests []Request
+}
+
+// New returns a trace with the given ID, or a random one if id is empty.
+func New(id string) *Trace {
+	if id == "" {
+		id = newID()
+	}
+	return &Trace{ID: id}
+}
+
+func newID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+func (t *Trace) record(r Request) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.requests) < maxRecordedRequests {
+		t.requests = append(t.requests, r)
+	}
+}
+
+// Requests returns the requests recorded so far.
+func (t *Trace) Requests() []Request {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]Request(nil), t.requests...)
+}
+
+// FailedRequests returns the recorded requests that GitHub answered with an error status.
+func (t *Trace) FailedRequests() []Request {
+	var failed []Request
+	for _, r := range t.Requests() {
+		if r.Status >= http.StatusBadRequest {
+			failed = append(failed, r)
+		}
+	}
+	return failed
+}
+
+type traceKey struct{}
+
+// ContextWithTrace returns a context carrying t.
+func ContextWithTrace(ctx context.Context, t *Trace) context.Context {
+	return context.WithValue(ctx, traceKey{}, t)
+}
+
+// FromContext returns the trace carried by ctx, if any.
+func FromContext(ctx context.Context) (*Trace, bool) {
+	t, ok := ctx.Value(traceKey{}).(*Trace)
+	return t, ok && t != nil
+}
+
+// Transport sends the trace ID of the request context with each request and records
+// the GitHub request ID of each response in the trace.
+type Transport struct {
+	Base http.RoundTripper
+}
+
+// NewTransport wraps base, or http.DefaultTransport if base is nil.
+func NewTransport(base http.RoundTripper) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Transport{Base: base}
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	tr, ok := FromContext(req.Context())
+	if !ok {
+		return t.Base.RoundTrip(req)
+	}
+
+	req = req.Clone(req.Context())
+	req.Header.Set(HeaderTraceID, tr.ID)
+	resp, err := t.Base.RoundTrip(req)
+	if resp != nil {
+		tr.record(Request{
+			Method:    req.Method,
+			Path:      req.URL.Path,
+			Status:    resp.StatusCode,
+			RequestID: resp.Header.Get(HeaderGitHubRequestID),
+		})
+	}
+	return resp, err
+}
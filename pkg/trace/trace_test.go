@@ -0,0 +1,56 @@
+package trace
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransport(t *testing.T) {
+	var receivedTraceIDs []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedTraceIDs = append(receivedTraceIDs, r.Header.Get(HeaderTraceID))
+		w.Header().Set(HeaderGitHubRequestID, "REQ:"+r.URL.Path)
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewTransport(nil)}
+	get := func(ctx context.Context, path string) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+path, nil)
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+	}
+
+	tr := New("trace-123")
+	ctx := ContextWithTrace(context.Background(), tr)
+	get(ctx, "/repos")
+	get(ctx, "/missing")
+	// Requests outside a tool call are left alone
+	get(context.Background(), "/untraced")
+
+	assert.Equal(t, []string{"trace-123", "trace-123", ""}, receivedTraceIDs)
+	assert.Equal(t, []Request{
+		{Method: http.MethodGet, Path: "/repos", Status: http.StatusOK, RequestID: "REQ:/repos"},
+		{Method: http.MethodGet, Path: "/missing", Status: http.StatusNotFound, RequestID: "REQ:/missing"},
+	}, tr.Requests())
+	assert.Equal(t, []Request{
+		{Method: http.MethodGet, Path: "/missing", Status: http.StatusNotFound, RequestID: "REQ:/missing"},
+	}, tr.FailedRequests())
+}
+
+func TestNew(t *testing.T) {
+	assert.Equal(t, "client-id", New("client-id").ID)
+
+	generated := New("")
+	assert.Len(t, generated.ID, 16)
+	assert.NotEqual(t, generated.ID, New("").ID)
+}
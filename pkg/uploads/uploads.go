@@ -0,0 +1,257 @@
+// Package uploads stores file contents uploaded over HTTP ahead of a tool call, so
+// that large pushes can reference them by ID instead of sending them inline in MCP
+// messages. Uploads are content-addressed: the ID is the SHA-256 of the content, and
+// uploading the same content twice stores it once.
+package uploads
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// IDPrefix starts every upload ID
+	IDPrefix = "sha256:"
+
+	// DefaultMaxSize is the default largest upload (100MB)
+	DefaultMaxSize = 100 * 1024 * 1024
+	// DefaultTTL is how long an upload is kept after it was last uploaded
+	DefaultTTL = time.Hour
+)
+
+var (
+	// ErrNotFound is returned for unknown or expired upload IDs
+	ErrNotFound = errors.New("upload not found or expired")
+	// ErrTooLarge is returned when an upload exceeds the maximum size of the store
+	ErrTooLarge = errors.New("upload exceeds the maximum size")
+	// ErrDigestMismatch is returned when the content does not match the digest announced by the client
+	ErrDigestMismatch = errors.New("upload content does not match the expected sha256")
+)
+
+// Upload describes stored content.
+type Upload struct {
+	ID   string `json:"upload_id"`
+	Size int64  `json:"size"`
+	// Deduplicated is set when the content was already stored
+	Deduplicated bool `json:"deduplicated"`
+}
+
+// Store keeps uploads as files in a directory, named by their SHA-256.
+type Store struct {
+	dir     string
+	maxSize int64
+	ttl     time.Duration
+
+	mu sync.Mutex
+}
+
+// Option configures a Store.
+type Option func(*Store)
+
+// WithMaxSize sets the largest upload accepted by the store.
+func WithMaxSize(size int64) Option {
+	return func(s *Store) { s.maxSize = size }
+}
+
+// WithTTL sets how long uploads are kept.
+func WithTTL(ttl time.Duration) Option {
+	return func(s *Store) { s.ttl = ttl }
+}
+
+// NewStore returns a store keeping uploads in dir, which is created if needed.
+func NewStore(dir string, opts ...Option) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create upload directory: %w", err)
+	}
+	s := &Store{dir: dir, maxSize: DefaultMaxSize, ttl: DefaultTTL}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s, nil
+}
+
+// Put streams r into the store. If expectedSHA256 is not empty, the content must have that
+// hex-encoded digest.
+func (s *Store) Put(r io.Reader, expectedSHA256 string) (Upload, error) {
+	s.Prune(time.Now())
+
+	tmp, err := os.CreateTemp(s.dir, "upload-*.tmp")
+	if err != nil {
+		return Upload{}, fmt.Errorf("failed to create upload file: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hash), io.LimitReader(r, s.maxSize+1))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return Upload{}, fmt.Errorf("failed to store upload: %w", err)
+	}
+	if size > s.maxSize {
+		return Upload{}, ErrTooLarge
+	}
+
+	digest := hex.EncodeToString(hash.Sum(nil))
+	if expectedSHA256 != "" && !strings.EqualFold(expectedSHA256, digest) {
+		return Upload{}, ErrDigestMismatch
+	}
+
+	upload := Upload{ID: IDPrefix + digest, Size: size}
+	path := filepath.Join(s.dir, digest)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := os.Stat(path); err == nil {
+		// Refresh the expiry of the stored copy
+		now := time.Now()
+		_ = os.Chtimes(path, now, now)
+		upload.Deduplicated = true
+		return upload, nil
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return Upload{}, fmt.Errorf("failed to store upload: %w", err)
+	}
+	return upload, nil
+}
+
+// Stat returns the upload with the given ID.
+func (s *Store) Stat(id string) (Upload, error) {
+	path, err := s.path(id)
+	if err != nil {
+		return Upload{}, err
+	}
+	info, err := os.Stat(path)
+	if err != nil || s.expired(info, time.Now()) {
+		return Upload{}, ErrNotFound
+	}
+	return Upload{ID: id, Size: info.Size()}, nil
+}
+
+// Read returns the content of the upload with the given ID.
+func (s *Store) Read(id string) ([]byte, error) {
+	if _, err := s.Stat(id); err != nil {
+		return nil, err
+	}
+	path, _ := s.path(id)
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return content, err
+}
+
+// Open returns the stored file of the upload with the given ID, for streaming it elsewhere.
+// The caller closes the file.
+func (s *Store) Open(id string) (*os.File, error) {
+	if _, err := s.Stat(id); err != nil {
+		return nil, err
+	}
+	path, _ := s.path(id)
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return file, err
+}
+
+// Prune removes the uploads that expired before now.
+func (s *Store) Prune(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || !s.expired(info, now) {
+			continue
+		}
+		_ = os.Remove(filepath.Join(s.dir, entry.Name()))
+	}
+}
+
+func (s *Store) expired(info os.FileInfo, now time.Time) bool {
+	return s.ttl > 0 && now.Sub(info.ModTime()) > s.ttl
+}
+
+// path returns the file of an upload ID, rejecting IDs that are not a SHA-256.
+func (s *Store) path(id string) (string, error) {
+	digest, ok := strings.CutPrefix(id, IDPrefix)
+	if !ok || len(digest) != sha256.Size*2 {
+		return "", fmt.Errorf("invalid upload ID %q: expected %s followed by 64 hex digits", id, IDPrefix)
+	}
+	if _, err := hex.DecodeString(digest); err != nil {
+		return "", fmt.Errorf("invalid upload ID %q: expected %s followed by 64 hex digits", id, IDPrefix)
+	}
+	return filepath.Join(s.dir, strings.ToLower(digest)), nil
+}
+
+// Handler serves the upload endpoints, to be mounted at the root of the HTTP server:
+//
+//	POST /uploads             stores the request body and returns its Upload
+//	HEAD /uploads/{upload_id} reports whether the content is already stored
+//
+// Clients can send the hex SHA-256 of the body in the X-Content-SHA256 header to have it
+// verified, and use HEAD to skip content the server already has.
+func (s *Store) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /uploads", func(w http.ResponseWriter, r *http.Request) {
+		upload, err := s.Put(r.Body, r.Header.Get("X-Content-SHA256"))
+		switch {
+		case errors.Is(err, ErrTooLarge):
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		case errors.Is(err, ErrDigestMismatch):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		status := http.StatusCreated
+		if upload.Deduplicated {
+			status = http.StatusOK
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(upload)
+	})
+	mux.HandleFunc("HEAD /uploads/{id}", func(w http.ResponseWriter, r *http.Request) {
+		upload, err := s.Stat(r.PathValue("id"))
+		if err != nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Length", fmt.Sprintf("%d", upload.Size))
+		w.WriteHeader(http.StatusOK)
+	})
+	return mux
+}
+
+type storeKey struct{}
+
+// ContextWithStore returns a context carrying s, for tools that accept upload IDs.
+func ContextWithStore(ctx context.Context, s *Store) context.Context {
+	return context.WithValue(ctx, storeKey{}, s)
+}
+
+// StoreFromContext returns the store carried by ctx, if any.
+func StoreFromContext(ctx context.Context) (*Store, bool) {
+	s, ok := ctx.Value(storeKey{}).(*Store)
+	return s, ok && s != nil
+}
@@ -0,0 +1,129 @@
+package uploads
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func digest(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestStore_Put(t *testing.T) {
+	store, err := NewStore(t.TempDir(), WithMaxSize(16))
+	require.NoError(t, err)
+
+	upload, err := store.Put(strings.NewReader("hello"), "")
+	require.NoError(t, err)
+	assert.Equal(t, IDPrefix+digest("hello"), upload.ID)
+	assert.Equal(t, int64(5), upload.Size)
+	assert.False(t, upload.Deduplicated)
+
+	// The same content is stored once
+	again, err := store.Put(strings.NewReader("hello"), strings.ToUpper(digest("hello")))
+	require.NoError(t, err)
+	assert.Equal(t, upload.ID, again.ID)
+	assert.True(t, again.Deduplicated)
+
+	content, err := store.Read(upload.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(content))
+
+	_, err = store.Put(strings.NewReader("hello"), digest("other"))
+	assert.ErrorIs(t, err, ErrDigestMismatch)
+	_, err = store.Put(strings.NewReader(strings.Repeat("x", 17)), "")
+	assert.ErrorIs(t, err, ErrTooLarge)
+
+	file, err := store.Open(upload.ID)
+	require.NoError(t, err)
+	opened, err := io.ReadAll(file)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+	assert.Equal(t, "hello", string(opened))
+
+	_, err = store.Read(IDPrefix + digest("missing"))
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = store.Open(IDPrefix + digest("missing"))
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = store.Read("../../etc/passwd")
+	assert.ErrorContains(t, err, "invalid upload ID")
+
+	// Only stored uploads remain, without temporary files
+	entries, err := os.ReadDir(store.dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, digest("hello"), entries[0].Name())
+}
+
+func TestStore_Expiry(t *testing.T) {
+	store, err := NewStore(t.TempDir(), WithTTL(time.Minute))
+	require.NoError(t, err)
+
+	upload, err := store.Put(strings.NewReader("hello"), "")
+	require.NoError(t, err)
+
+	old := time.Now().Add(-2 * time.Minute)
+	require.NoError(t, os.Chtimes(filepath.Join(store.dir, digest("hello")), old, old))
+	_, err = store.Read(upload.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	store.Prune(time.Now())
+	_, err = os.Stat(filepath.Join(store.dir, digest("hello")))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestStore_Handler(t *testing.T) {
+	store, err := NewStore(t.TempDir())
+	require.NoError(t, err)
+	server := httptest.NewServer(store.Handler())
+	defer server.Close()
+
+	post := func(body string) (*http.Response, Upload) {
+		resp, err := http.Post(server.URL+"/uploads", "application/octet-stream", strings.NewReader(body))
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		var upload Upload
+		if resp.StatusCode < http.StatusBadRequest {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&upload))
+		}
+		return resp, upload
+	}
+
+	resp, upload := post("file content")
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, IDPrefix+digest("file content"), upload.ID)
+
+	resp, upload = post("file content")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.True(t, upload.Deduplicated)
+
+	head := func(id string) int {
+		resp, err := http.Head(server.URL + "/uploads/" + id)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		return resp.StatusCode
+	}
+	assert.Equal(t, http.StatusOK, head(upload.ID))
+	assert.Equal(t, http.StatusNotFound, head(IDPrefix+digest("missing")))
+
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/uploads", strings.NewReader("file content"))
+	require.NoError(t, err)
+	req.Header.Set("X-Content-SHA256", digest("other"))
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
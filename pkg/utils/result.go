@@ -1,6 +1,12 @@
 package utils //nolint:revive //TODO: figure out a better name for this package
 
-import "github.com/modelcontextprotocol/go-sdk/mcp"
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
 
 func NewToolResultText(message string) *mcp.CallToolResult {
 	return &mcp.CallToolResult{
@@ -47,3 +53,39 @@ func NewToolResultResource(message string, contents *mcp.ResourceContents) *mcp.
 		IsError: false,
 	}
 }
+
+// NewToolResultValidationError returns a tool error describing a failed validation as a JSON
+// object with code, message, suggestion and details fields, in the text content and as
+// structured content, so that clients can branch on the code.
+func NewToolResultValidationError(code, message, suggestion string, details map[string]any) *mcp.CallToolResult {
+	structured := map[string]any{
+		"code":    code,
+		"message": message,
+	}
+	if suggestion != "" {
+		structured["suggestion"] = suggestion
+	}
+	if len(details) > 0 {
+		structured["details"] = details
+	}
+
+	var text bytes.Buffer
+	enc := json.NewEncoder(&text)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(structured); err != nil {
+		// Details that cannot be encoded are left out rather than losing the error
+		delete(structured, "details")
+		text.Reset()
+		_ = enc.Encode(structured)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: strings.TrimSuffix(text.String(), "\n"),
+			},
+		},
+		StructuredContent: structured,
+		IsError:           true,
+	}
+}